@@ -197,6 +197,16 @@ local_sha=$(get_sha HEAD)
 assert_pass "Tag push allowed" run_hook "refs/tags/v1.0.0" "$local_sha" "refs/tags/v1.0.0" "0000000000000000000000000000000000000000"
 cleanup
 
+# Test 9b: Bead notes push — allowed
+echo "Test 9b: Bead notes push"
+setup_repos
+cd "$TMPDIR/local"
+git notes --ref=beads add -m "Bead: gt-abc" HEAD >/dev/null 2>&1
+local_sha=$(get_sha refs/notes/beads)
+assert_pass "Bead notes push allowed" run_hook "refs/notes/beads" "$local_sha" "refs/notes/beads" "0000000000000000000000000000000000000000"
+assert_block "Other notes push blocked" run_hook "refs/notes/other" "$local_sha" "refs/notes/other" "0000000000000000000000000000000000000000"
+cleanup
+
 # Test 10: Push to default branch with fast-forward integration merge (no merge commit) — BLOCKED
 echo "Test 10: Push to default branch with ff integration merge (no merge commit)"
 setup_repos
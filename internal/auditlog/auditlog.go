@@ -0,0 +1,154 @@
+// Package auditlog records mutating gt commands to an append-only log.
+//
+// Entries are written as JSON lines to ~/gt/mayor/audit.jsonl so shared towns
+// can answer "who changed what, and did it work?". Entries older than the
+// configured retention are pruned when new entries are appended.
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Outcome values for Entry.Outcome.
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Entry is a single audited command invocation.
+type Entry struct {
+	Timestamp  time.Time `json:"ts"`
+	Actor      string    `json:"actor"`          // gt identity, e.g. "gastown/crew/joe" or "overseer"
+	User       string    `json:"user,omitempty"` // OS user that ran the command
+	Command    string    `json:"command"`        // command path without the binary, e.g. "rig add"
+	Args       []string  `json:"args,omitempty"` // full argument list, including flags
+	Cwd        string    `json:"cwd,omitempty"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Append writes an entry to the town's audit log. When retention is positive,
+// entries older than retention are pruned in the same locked operation.
+func Append(townRoot string, entry Entry, retention time.Duration) error {
+	path := constants.MayorAuditLogPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating audit log directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring audit log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	if retention > 0 {
+		if _, err := pruneUnlocked(path, entry.Timestamp.Add(-retention)); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: audit log is shared operational data
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing audit entry: %w", err)
+	}
+	return nil
+}
+
+// Read returns all entries in the town's audit log, oldest first.
+// A missing log returns no entries and no error. Malformed lines are skipped.
+func Read(townRoot string) ([]Entry, error) {
+	data, err := os.ReadFile(constants.MayorAuditLogPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+	return parse(data), nil
+}
+
+// Prune removes entries older than cutoff and returns how many were removed.
+func Prune(townRoot string, cutoff time.Time) (int, error) {
+	path := constants.MayorAuditLogPath(townRoot)
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return 0, fmt.Errorf("acquiring audit log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	return pruneUnlocked(path, cutoff)
+}
+
+// pruneUnlocked rewrites the log without entries older than cutoff.
+// The caller must hold the audit log lock. The file is only rewritten when
+// the oldest entry is past the cutoff, so the common case is a single read.
+func pruneUnlocked(path string, cutoff time.Time) (int, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	entries := parse(data)
+	if len(entries) == 0 || !entries[0].Timestamp.Before(cutoff) {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	removed := 0
+	for _, e := range entries {
+		if e.Timestamp.Before(cutoff) {
+			removed++
+			continue
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return 0, fmt.Errorf("marshaling audit entry: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := util.AtomicWriteFile(path, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("rewriting audit log: %w", err)
+	}
+	return removed, nil
+}
+
+func parse(data []byte) []Entry {
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,87 @@
+package auditlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Timestamp: now, Actor: "overseer", Command: "rig add", Args: []string{"rig", "add", "beads"}, Outcome: OutcomeOK},
+		{Timestamp: now.Add(time.Minute), Actor: "mayor", Command: "shutdown", Outcome: OutcomeError, Error: "boom"},
+	}
+	for _, e := range entries {
+		if err := Append(townRoot, e, 0); err != nil {
+			t.Fatalf("Append() error: %v", err)
+		}
+	}
+
+	got, err := Read(townRoot)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Read() returned %d entries, want 2", len(got))
+	}
+	if got[0].Command != "rig add" || got[1].Error != "boom" {
+		t.Errorf("Read() = %+v", got)
+	}
+}
+
+func TestReadMissingLog(t *testing.T) {
+	got, err := Read(t.TempDir())
+	if err != nil || got != nil {
+		t.Errorf("Read() on missing log = (%v, %v), want (nil, nil)", got, err)
+	}
+}
+
+func TestAppendPrunesExpiredEntries(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+
+	if err := Append(townRoot, Entry{Timestamp: now.Add(-60 * 24 * time.Hour), Command: "rig add"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(townRoot, Entry{Timestamp: now.Add(-10 * 24 * time.Hour), Command: "rig remove"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(townRoot, Entry{Timestamp: now, Command: "shutdown"}, retention); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Command != "rig remove" || got[1].Command != "shutdown" {
+		t.Errorf("entries after prune = %+v, want [rig remove, shutdown]", got)
+	}
+}
+
+func TestReadSkipsMalformedLines(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, "mayor", "audit.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"ts":"2026-03-01T12:00:00Z","actor":"mayor","command":"down","outcome":"ok","duration_ms":5}
+not json
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Command != "down" {
+		t.Errorf("Read() = %+v, want single down entry", got)
+	}
+}
@@ -0,0 +1,153 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Storage backend names as recorded in .beads/metadata.json "backend".
+const (
+	BackendDolt   = "dolt"
+	BackendSQLite = "sqlite"
+)
+
+// MetadataFileName is bd's per-database storage descriptor inside .beads/.
+const MetadataFileName = "metadata.json"
+
+// ErrUnknownBackend is returned by OpenBackend when metadata.json names a
+// backend with no registered implementation.
+var ErrUnknownBackend = errors.New("unknown beads backend")
+
+// BackendMetadata is the storage descriptor bd reads from .beads/metadata.json.
+// Unknown fields are ignored; EnsureMetadata in doltserver preserves them.
+type BackendMetadata struct {
+	Backend        string `json:"backend,omitempty"`
+	Database       string `json:"database,omitempty"` // "dolt", or the sqlite file name
+	DoltMode       string `json:"dolt_mode,omitempty"`
+	DoltDatabase   string `json:"dolt_database,omitempty"`
+	DoltServerHost string `json:"dolt_server_host,omitempty"`
+	DoltServerPort int    `json:"dolt_server_port,omitempty"`
+	DoltServerUser string `json:"dolt_server_user,omitempty"` // scoped SQL user; see gt dolt users
+	JSONLExport    string `json:"jsonl_export,omitempty"`
+
+	// Fallback is set while gt has pointed bd at a local snapshot because
+	// the Dolt server is down (see doltserver.Failover).
+	Fallback *FallbackMarker `json:"gt_fallback,omitempty"`
+}
+
+// FallbackMarker records that a beads directory is running on a local
+// snapshot of its server database until gt dolt reconcile restores it.
+type FallbackMarker struct {
+	// Mode is the town's dolt_fallback policy when the snapshot was taken:
+	// "read-only" or "queue".
+	Mode string `json:"mode"`
+
+	// ForkCommit is the snapshot's Dolt HEAD when it was taken; changes
+	// after it are what reconcile merges back.
+	ForkCommit string `json:"fork_commit,omitempty"`
+
+	Since time.Time `json:"since"`
+}
+
+// ReadBackendMetadata parses beadsDir/metadata.json.
+// Returns os.ErrNotExist (wrapped) when the file is missing.
+func ReadBackendMetadata(beadsDir string) (*BackendMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(beadsDir, MetadataFileName)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var meta BackendMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", MetadataFileName, err)
+	}
+	return &meta, nil
+}
+
+// BackendName returns the backend this metadata selects. Older files omit
+// "backend": a Dolt mode or database means Dolt, anything else is sqlite.
+func (m *BackendMetadata) BackendName() string {
+	switch {
+	case m.Backend != "":
+		return m.Backend
+	case m.DoltMode != "" || m.DoltDatabase != "" || m.Database == BackendDolt:
+		return BackendDolt
+	default:
+		return BackendSQLite
+	}
+}
+
+// Backend is a beads storage backend. Reads and writes go through bd, which
+// owns the schema; implementations add what gt needs to know about where the
+// data lives and whether writes would land somewhere other readers can't see.
+type Backend interface {
+	// Name returns the registered backend name (e.g. "dolt").
+	Name() string
+
+	// Location describes where data is stored: a server address for
+	// networked backends, or a file path.
+	Location() string
+
+	// SplitBrainRisks describes conditions under which bd could read or
+	// write a different copy of the data than other agents (unreachable
+	// server, stray local database, ...). Empty means none detected.
+	SplitBrainRisks(ctx context.Context) []string
+}
+
+// BackendFactory creates a backend for beadsDir from its metadata.
+type BackendFactory func(beadsDir string, meta *BackendMetadata) (Backend, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{}
+)
+
+// RegisterBackend makes a backend available to OpenBackend under name.
+// Registering a name twice replaces the earlier factory.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = factory
+}
+
+// RegisteredBackends returns the names of all registered backends, sorted.
+func RegisteredBackends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenBackend returns the backend described by beadsDir/metadata.json.
+// Without metadata.json the backend is inferred from what is on disk: a
+// dolt/ directory means embedded Dolt, otherwise sqlite.
+func OpenBackend(beadsDir string) (Backend, error) {
+	meta, err := ReadBackendMetadata(beadsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		meta = &BackendMetadata{Backend: BackendSQLite}
+		if info, statErr := os.Stat(filepath.Join(beadsDir, "dolt")); statErr == nil && info.IsDir() {
+			meta.Backend = BackendDolt
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	name := meta.BackendName()
+	backendsMu.RLock()
+	factory, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q in %s (registered: %v)", ErrUnknownBackend, name, filepath.Join(beadsDir, MetadataFileName), RegisteredBackends())
+	}
+	return factory(beadsDir, meta)
+}
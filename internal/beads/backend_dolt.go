@@ -0,0 +1,160 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DoltModeServer is the metadata.json dolt_mode used by Gas Town: bd
+// connects to the town's shared Dolt sql-server instead of an embedded copy.
+const DoltModeServer = "server"
+
+// DoltModeEmbedded is the dolt_mode in which bd opens the database under
+// .beads/dolt/ directly.
+const DoltModeEmbedded = "embedded"
+
+// defaultDoltServerPort matches doltserver.DefaultPort (doltserver imports
+// this package, so the constant cannot be shared).
+const defaultDoltServerPort = 3307
+
+// doltDialTimeout bounds reachability probes of the Dolt server.
+const doltDialTimeout = 2 * time.Second
+
+func init() {
+	RegisterBackend(BackendDolt, newDoltBackend)
+}
+
+// IsDoltServerMode reports whether the metadata selects a Dolt sql-server.
+func (m *BackendMetadata) IsDoltServerMode() bool {
+	return m.BackendName() == BackendDolt && m.DoltMode == DoltModeServer
+}
+
+// DoltServerAddr returns the host:port bd connects to in Dolt server mode,
+// defaulting to the local town server. ok is false outside server mode.
+func (m *BackendMetadata) DoltServerAddr() (addr string, ok bool) {
+	if !m.IsDoltServerMode() {
+		return "", false
+	}
+	host, port := m.DoltServerHost, m.DoltServerPort
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if port == 0 {
+		port = defaultDoltServerPort
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), true
+}
+
+// doltBackend stores beads in Dolt, either on the town's sql-server or in an
+// embedded .beads/dolt/ directory.
+type doltBackend struct {
+	beadsDir string
+	meta     *BackendMetadata
+}
+
+func newDoltBackend(beadsDir string, meta *BackendMetadata) (Backend, error) {
+	return &doltBackend{beadsDir: beadsDir, meta: meta}, nil
+}
+
+func (d *doltBackend) Name() string { return BackendDolt }
+
+func (d *doltBackend) Location() string {
+	if addr, ok := d.meta.DoltServerAddr(); ok {
+		return addr
+	}
+	return filepath.Join(d.beadsDir, "dolt")
+}
+
+func (d *doltBackend) SplitBrainRisks(ctx context.Context) []string {
+	var risks []string
+	if addr, ok := d.meta.DoltServerAddr(); ok {
+		if err := dialDolt(ctx, addr); err != nil {
+			risks = append(risks, fmt.Sprintf("Dolt server unreachable at %s: bd may fail or create an isolated local database", addr))
+		}
+		return risks
+	}
+
+	if f := d.meta.Fallback; f != nil {
+		risks = append(risks, fmt.Sprintf("on a %s fallback snapshot since %s: run gt dolt reconcile once the Dolt server is back",
+			f.Mode, f.Since.Local().Format(time.DateTime)))
+		return risks
+	}
+
+	// Embedded mode while the town server hosts the same database: writes
+	// here are invisible to every agent using the server.
+	db := d.meta.DoltDatabase
+	if townRoot := FindTownRoot(filepath.Dir(d.beadsDir)); townRoot != "" && db != "" {
+		if _, err := os.Stat(filepath.Join(townRoot, ".dolt-data", db)); err == nil {
+			risks = append(risks, fmt.Sprintf("embedded Dolt mode but the town server also has database %q: writes here are invisible to server clients", db))
+		}
+	}
+	return risks
+}
+
+func dialDolt(ctx context.Context, addr string) error {
+	dialer := net.Dialer{Timeout: doltDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Dolt server not reachable at %s: %w", addr, err)
+	}
+	return conn.Close()
+}
+
+// DoltPasswordEnv is the variable bd reads the password for the metadata's
+// dolt_server_user from.
+const DoltPasswordEnv = "BEADS_DOLT_PASSWORD"
+
+// DoltUserSecret names the secret holding a scoped Dolt user's password.
+func DoltUserSecret(user string) string {
+	return "dolt-" + user
+}
+
+// doltPasswords caches scoped Dolt user passwords by town root and user for
+// the life of the process, so each bd call doesn't re-read (and possibly
+// decrypt) the town's secrets. A missing secret is cached as "".
+var (
+	doltPasswords   = make(map[string]string)
+	doltPasswordsMu sync.Mutex
+)
+
+// withDoltPassword adds the password for beadsDir's scoped Dolt user (see
+// gt dolt users) to env from the town's secrets, unless env already has
+// one. Rigs still connecting as root are left alone.
+func withDoltPassword(env []string, townRoot, beadsDir string) []string {
+	if townRoot == "" || os.Getenv(DoltPasswordEnv) != "" {
+		return env
+	}
+	meta, err := ReadBackendMetadata(beadsDir)
+	if err != nil || meta.DoltServerUser == "" {
+		return env
+	}
+	if password := doltUserPassword(townRoot, meta.DoltServerUser); password != "" {
+		return append(env, DoltPasswordEnv+"="+password)
+	}
+	return env
+}
+
+// doltUserPassword resolves user's password from townRoot's secrets once
+// per process.
+func doltUserPassword(townRoot, user string) string {
+	key := townRoot + "\x00" + user
+	doltPasswordsMu.Lock()
+	defer doltPasswordsMu.Unlock()
+	if password, ok := doltPasswords[key]; ok {
+		return password
+	}
+	password, err := config.ResolveSecret(townRoot, DoltUserSecret(user))
+	if err != nil {
+		password = ""
+	}
+	doltPasswords[key] = password
+	return password
+}
@@ -0,0 +1,43 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultSQLiteFile is the database file legacy bd installs create in .beads/.
+const defaultSQLiteFile = "beads.db"
+
+func init() {
+	RegisterBackend(BackendSQLite, newSQLiteBackend)
+}
+
+// sqliteBackend stores beads in a single-file sqlite database (legacy bd).
+type sqliteBackend struct {
+	beadsDir string
+	path     string
+}
+
+func newSQLiteBackend(beadsDir string, meta *BackendMetadata) (Backend, error) {
+	file := meta.Database
+	if file == "" || !strings.HasSuffix(file, ".db") {
+		file = defaultSQLiteFile
+	}
+	return &sqliteBackend{beadsDir: beadsDir, path: filepath.Join(beadsDir, file)}, nil
+}
+
+func (s *sqliteBackend) Name() string { return BackendSQLite }
+
+func (s *sqliteBackend) Location() string { return s.path }
+
+func (s *sqliteBackend) SplitBrainRisks(ctx context.Context) []string {
+	// A Dolt database next to the sqlite file means some bd invocations have
+	// migrated and others have not.
+	if info, err := os.Stat(filepath.Join(s.beadsDir, "dolt")); err == nil && info.IsDir() {
+		return []string{fmt.Sprintf("sqlite backend configured but %s also contains a Dolt database: bd versions may disagree on which is live", s.beadsDir)}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func writeMetadata(t *testing.T, beadsDir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, MetadataFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// closedPort returns a localhost port with nothing listening on it.
+func closedPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+	return port
+}
+
+func TestBackendMetadataBackendName(t *testing.T) {
+	tests := []struct {
+		meta BackendMetadata
+		want string
+	}{
+		{BackendMetadata{Backend: "postgres"}, "postgres"},
+		{BackendMetadata{DoltMode: "server"}, BackendDolt},
+		{BackendMetadata{Database: "dolt"}, BackendDolt},
+		{BackendMetadata{Database: "beads.db"}, BackendSQLite},
+		{BackendMetadata{}, BackendSQLite},
+	}
+	for _, tt := range tests {
+		if got := tt.meta.BackendName(); got != tt.want {
+			t.Errorf("%+v.BackendName() = %q, want %q", tt.meta, got, tt.want)
+		}
+	}
+}
+
+func TestOpenBackend(t *testing.T) {
+	dir := t.TempDir()
+
+	server := filepath.Join(dir, "server", ".beads")
+	writeMetadata(t, server, `{"backend":"dolt","dolt_mode":"server","dolt_database":"gastown","dolt_server_host":"10.0.0.5"}`)
+	b, err := OpenBackend(server)
+	if err != nil {
+		t.Fatalf("OpenBackend(server): %v", err)
+	}
+	if b.Name() != BackendDolt || b.Location() != "10.0.0.5:3307" {
+		t.Errorf("server backend = %s at %s", b.Name(), b.Location())
+	}
+
+	legacy := filepath.Join(dir, "legacy", ".beads")
+	writeMetadata(t, legacy, `{"database":"beads.db","jsonl_export":"issues.jsonl"}`)
+	b, err = OpenBackend(legacy)
+	if err != nil {
+		t.Fatalf("OpenBackend(legacy): %v", err)
+	}
+	if b.Name() != BackendSQLite || b.Location() != filepath.Join(legacy, "beads.db") {
+		t.Errorf("legacy backend = %s at %s", b.Name(), b.Location())
+	}
+
+	// No metadata.json: inferred from the dolt/ directory.
+	embedded := filepath.Join(dir, "embedded", ".beads")
+	if err := os.MkdirAll(filepath.Join(embedded, "dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	b, err = OpenBackend(embedded)
+	if err != nil {
+		t.Fatalf("OpenBackend(embedded): %v", err)
+	}
+	if b.Name() != BackendDolt {
+		t.Errorf("embedded backend = %s, want dolt", b.Name())
+	}
+
+	unknown := filepath.Join(dir, "unknown", ".beads")
+	writeMetadata(t, unknown, `{"backend":"postgres"}`)
+	if _, err := OpenBackend(unknown); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("OpenBackend(postgres) err = %v, want ErrUnknownBackend", err)
+	}
+}
+
+func TestRegisterBackend(t *testing.T) {
+	var gotDir string
+	RegisterBackend("test-backend", func(beadsDir string, meta *BackendMetadata) (Backend, error) {
+		gotDir = beadsDir
+		return &sqliteBackend{beadsDir: beadsDir, path: "custom"}, nil
+	})
+	defer func() {
+		backendsMu.Lock()
+		delete(backends, "test-backend")
+		backendsMu.Unlock()
+	}()
+
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	writeMetadata(t, beadsDir, `{"backend":"test-backend"}`)
+	b, err := OpenBackend(beadsDir)
+	if err != nil {
+		t.Fatalf("OpenBackend: %v", err)
+	}
+	if gotDir != beadsDir || b.Location() != "custom" {
+		t.Errorf("factory not used: dir=%q location=%q", gotDir, b.Location())
+	}
+}
+
+func TestDoltSplitBrainRisks(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	port := closedPort(t)
+	writeMetadata(t, beadsDir, `{"backend":"dolt","dolt_mode":"server","dolt_server_port":`+strconv.Itoa(port)+`}`)
+
+	b, err := OpenBackend(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if risks := b.SplitBrainRisks(context.Background()); len(risks) != 1 {
+		t.Errorf("unreachable server: risks = %v, want 1", risks)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	listening := ln.Addr().(*net.TCPAddr).Port
+	writeMetadata(t, beadsDir, `{"backend":"dolt","dolt_mode":"server","dolt_server_port":`+strconv.Itoa(listening)+`}`)
+	b, _ = OpenBackend(beadsDir)
+	if risks := b.SplitBrainRisks(context.Background()); len(risks) != 0 {
+		t.Errorf("reachable server: risks = %v, want none", risks)
+	}
+}
+
+func TestSQLiteSplitBrainRisks(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	writeMetadata(t, beadsDir, `{"backend":"sqlite","database":"beads.db"}`)
+	b, _ := OpenBackend(beadsDir)
+	if risks := b.SplitBrainRisks(context.Background()); len(risks) != 0 {
+		t.Errorf("clean sqlite: risks = %v", risks)
+	}
+	if err := os.MkdirAll(filepath.Join(beadsDir, "dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if risks := b.SplitBrainRisks(context.Background()); len(risks) != 1 {
+		t.Errorf("sqlite with dolt dir: risks = %v, want 1", risks)
+	}
+}
+
+func TestDoltServerAddr(t *testing.T) {
+	tests := []struct {
+		name   string
+		meta   BackendMetadata
+		want   string
+		wantOK bool
+	}{
+		{"defaults to the local server", BackendMetadata{Backend: BackendDolt, DoltMode: DoltModeServer}, "127.0.0.1:3307", true},
+		{"explicit host and port", BackendMetadata{Backend: BackendDolt, DoltMode: DoltModeServer, DoltServerHost: "10.0.0.5", DoltServerPort: 3308}, "10.0.0.5:3308", true},
+		{"IPv6 host is bracketed", BackendMetadata{Backend: BackendDolt, DoltMode: DoltModeServer, DoltServerHost: "::1"}, "[::1]:3307", true},
+		{"not server mode", BackendMetadata{Backend: BackendDolt, DoltMode: "local"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.meta.DoltServerAddr()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DoltServerAddr() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package beads
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,9 @@ import (
 	"sync"
 
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/util"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Common errors
@@ -141,8 +145,9 @@ type CreateOptions struct {
 	Priority    int    // 0-4
 	Description string
 	Parent      string
-	Actor       string // Who is creating this issue (populates created_by)
-	Ephemeral   bool   // Create as ephemeral (wisp) - not exported to JSONL
+	Actor       string   // Who is creating this issue (populates created_by)
+	Ephemeral   bool     // Create as ephemeral (wisp) - not exported to JSONL
+	Labels      []string // Labels to apply, checked against the rig's label taxonomy
 }
 
 // UpdateOptions specifies options for updating an issue.
@@ -246,6 +251,21 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 		beadsDir = ResolveBeadsDir(b.workDir)
 	}
 
+	// Outside tests, don't let bd silently split from the town's data when
+	// the Dolt server is down (see checkFallback).
+	if !b.isolated {
+		if err := checkFallback(beadsDir, args); err != nil {
+			// Under the journal policy, mutations are queued for
+			// gt dolt journal apply instead of failing.
+			if errors.Is(err, ErrDoltServerDown) {
+				if queued, qerr := b.queueMutation(beadsDir, args); queued {
+					return nil, qerr
+				}
+			}
+			return nil, err
+		}
+	}
+
 	// In isolated mode, use --db flag to force specific database path
 	// This bypasses bd's routing logic that can redirect to .beads-planning
 	// Skip --db for init command since it creates the database
@@ -264,7 +284,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	if b.isolated {
 		env = filterBeadsEnv(os.Environ())
 	} else {
-		env = os.Environ()
+		env = withDoltPassword(os.Environ(), b.getTownRoot(), beadsDir)
 	}
 	cmd.Env = append(env, "BEADS_DIR="+beadsDir)
 
@@ -272,7 +292,9 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	_, span := telemetry.Start(context.Background(), "bd "+bdCommandName(args), attribute.String("beads.dir", beadsDir))
+	err := util.Run(cmd, util.BeadsTimeout)
+	telemetry.End(span, err)
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
@@ -287,6 +309,16 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	return stdout.Bytes(), nil
 }
 
+// bdCommandName returns the bd subcommand in args, for span names.
+func bdCommandName(args []string) string {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return "(none)"
+}
+
 // runWithRouting executes a bd command without setting BEADS_DIR, allowing bd's
 // native prefix-based routing via routes.jsonl to resolve cross-prefix beads.
 // This is needed for slot operations that reference beads with different prefixes
@@ -316,7 +348,9 @@ func (b *Beads) runWithRouting(args ...string) ([]byte, error) { //nolint:unpara
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	_, span := telemetry.Start(context.Background(), "bd "+bdCommandName(args), attribute.Bool("beads.routed", true))
+	err := util.Run(cmd, util.BeadsTimeout)
+	telemetry.End(span, err)
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
@@ -347,6 +381,11 @@ func (b *Beads) wrapError(err error, stderr string, args []string) error {
 		return ErrNotInstalled
 	}
 
+	// A timeout names the hung command itself; its partial stderr is noise.
+	if util.IsTimeout(err) {
+		return err
+	}
+
 	// ErrNotFound is widely used for issue lookups - acceptable exception
 	// Match various "not found" error patterns from bd
 	if strings.Contains(stderr, "not found") || strings.Contains(stderr, "Issue not found") ||
@@ -614,10 +653,11 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+	labelArgs, err := b.createLabelArgs(opts)
+	if err != nil {
+		return nil, err
 	}
+	args = append(args, labelArgs...)
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
 	}
@@ -653,6 +693,24 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	return &issue, nil
 }
 
+// createLabelArgs returns the --labels flag for a create: the gt:<type>
+// label for opts.Type (Type is deprecated) plus opts.Labels, which are
+// checked against the rig's label taxonomy first.
+func (b *Beads) createLabelArgs(opts CreateOptions) ([]string, error) {
+	var labels []string
+	if opts.Type != "" {
+		labels = append(labels, "gt:"+opts.Type)
+	}
+	if err := b.checkLabels(opts.Labels); err != nil {
+		return nil, err
+	}
+	labels = append(labels, opts.Labels...)
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return []string{"--labels=" + strings.Join(labels, ",")}, nil
+}
+
 // CreateWithID creates an issue with a specific ID.
 // This is useful for agent beads, role beads, and other beads that need
 // deterministic IDs rather than auto-generated ones.
@@ -670,10 +728,11 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+	labelArgs, err := b.createLabelArgs(opts)
+	if err != nil {
+		return nil, err
 	}
+	args = append(args, labelArgs...)
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
 	}
@@ -727,10 +786,16 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 	}
 	// Label operations: set-labels replaces all, otherwise use add/remove
 	if len(opts.SetLabels) > 0 {
+		if err := b.checkLabels(opts.SetLabels); err != nil {
+			return err
+		}
 		for _, label := range opts.SetLabels {
 			args = append(args, "--set-labels="+label)
 		}
 	} else {
+		if err := b.checkLabels(opts.AddLabels); err != nil {
+			return err
+		}
 		for _, label := range opts.AddLabels {
 			args = append(args, "--add-label="+label)
 		}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // typesSentinel is a marker file indicating custom types have been configured.
@@ -27,13 +28,13 @@ var (
 )
 
 // FindTownRoot walks up from startDir to find the Gas Town root directory.
-// The town root is identified by the presence of mayor/town.json.
+// The town root is identified by the presence of mayor/town.json or one of
+// its YAML/TOML variants.
 // Returns empty string if not found (reached filesystem root).
 func FindTownRoot(startDir string) string {
 	dir := startDir
 	for {
-		townFile := filepath.Join(dir, "mayor", "town.json")
-		if _, err := os.Stat(townFile); err == nil {
+		if config.ConfigExists(filepath.Join(dir, "mayor", "town.json")) {
 			return dir
 		}
 		parent := filepath.Dir(dir)
@@ -122,7 +123,7 @@ func EnsureCustomTypes(beadsDir string) error {
 	cmd.Dir = beadsDir
 	// Set BEADS_DIR explicitly to ensure bd operates on the correct database
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := util.CombinedOutput(cmd, util.BeadsTimeout); err != nil {
 		return fmt.Errorf("configure custom types in %s: %s: %w",
 			beadsDir, strings.TrimSpace(string(output)), err)
 	}
@@ -141,6 +142,11 @@ func EnsureCustomTypes(beadsDir string) error {
 // Both exist because rig/manager.go cannot import internal/beads (circular dep).
 var prefixRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{0,19}$`)
 
+// ValidPrefix reports whether prefix is a well-formed beads issue prefix.
+func ValidPrefix(prefix string) bool {
+	return prefixRe.MatchString(prefix)
+}
+
 // ensureDatabaseInitialized checks if a beads database exists and initializes it if needed.
 // This handles the case where a rig was added but the database was never created,
 // which causes Dolt panics when trying to create agent beads.
@@ -199,7 +205,7 @@ func ensureDatabaseInitialized(beadsDir string) error {
 	cmd := exec.Command("bd", "init", "--prefix", prefix, "--server")
 	cmd.Dir = parentDir
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := util.CombinedOutput(cmd, util.BeadsTimeout); err != nil {
 		// Handle "already initialized" gracefully, matching install.go behavior.
 		// This can happen due to race conditions or if detection heuristics miss
 		// a valid database state.
@@ -215,7 +221,7 @@ func ensureDatabaseInitialized(beadsDir string) error {
 	pfxCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix)
 	pfxCmd.Dir = parentDir
 	pfxCmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	_, _ = pfxCmd.CombinedOutput() // Best effort — crash prevention guard
+	_, _ = util.CombinedOutput(pfxCmd, util.BeadsTimeout) // Best effort — crash prevention guard
 
 	return nil
 }
@@ -44,6 +44,21 @@ func TestFindTownRoot(t *testing.T) {
 	}
 }
 
+func TestFindTownRoot_YAMLVariant(t *testing.T) {
+	tmpDir := t.TempDir()
+	mayorDir := filepath.Join(tmpDir, "mayor")
+	if err := os.MkdirAll(mayorDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mayorDir, "town.yaml"), []byte("type: town\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FindTownRoot(mayorDir); got != tmpDir {
+		t.Errorf("FindTownRoot(%q) = %q, want %q", mayorDir, got, tmpDir)
+	}
+}
+
 func TestResolveRoutingTarget(t *testing.T) {
 	// Create a temporary town with routes
 	tmpDir := t.TempDir()
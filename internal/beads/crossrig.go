@@ -0,0 +1,89 @@
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnroutedBead is returned when a bead ID's prefix has no entry in the
+// town's routes.jsonl, so the bead cannot be located from another rig.
+var ErrUnroutedBead = errors.New("bead prefix not routed")
+
+// DependencyTypeBlocks is the dependency type created by bd dep add. Only
+// "blocks" edges gate work; parent-child and related edges do not.
+const DependencyTypeBlocks = "blocks"
+
+// ResolveBeadRoute returns the routes.jsonl entry for id's prefix.
+// The id may carry bd's "external:prefix:id" wrapper.
+func ResolveBeadRoute(townRoot, id string) (Route, error) {
+	id = ExtractIssueID(id)
+	prefix := ExtractPrefix(id)
+	if prefix == "" {
+		return Route{}, fmt.Errorf("%q is not a bead ID (expected <prefix>-<id>)", id)
+	}
+	routes, err := LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return Route{}, fmt.Errorf("loading routes: %w", err)
+	}
+	for _, r := range routes {
+		if r.Prefix == prefix {
+			return r, nil
+		}
+	}
+	return Route{}, fmt.Errorf("%s: %w: no route for prefix %q in %s", id, ErrUnroutedBead, prefix, RoutesFileName)
+}
+
+// RouteRigName returns the rig that owns a route, or "town" for town-level
+// routes (path ".").
+func RouteRigName(r Route) string {
+	if r.Path == "." || r.Path == "" {
+		return "town"
+	}
+	return strings.SplitN(r.Path, "/", 2)[0]
+}
+
+// IsCrossRigDependency reports whether a dependency refers to a bead in
+// another rig's database. bd marks these edges with the "external:" wrapper;
+// a differing prefix is treated the same way for older edges.
+func IsCrossRigDependency(issueID string, dep IssueDep) bool {
+	if strings.HasPrefix(dep.ID, "external:") {
+		return true
+	}
+	return ExtractPrefix(dep.ID) != ExtractPrefix(issueID)
+}
+
+// OpenBlockers returns issueID's "blocks" dependencies that are not yet
+// closed. Status reported inline by bd show is trusted for local edges;
+// cross-rig edges (and any edge without a status) are looked up with
+// lookup, since bd cannot see the status of beads stored in another rig's
+// database.
+// A blocker that cannot be looked up is reported as open so dependent work
+// is never dispatched early. Returned IDs are unwrapped.
+func OpenBlockers(issueID string, deps []IssueDep, lookup func(id string) (*Issue, error)) []IssueDep {
+	var open []IssueDep
+	for _, dep := range deps {
+		if dep.DependencyType != DependencyTypeBlocks {
+			continue
+		}
+		crossRig := IsCrossRigDependency(issueID, dep)
+		dep.ID = ExtractIssueID(dep.ID)
+		if crossRig || dep.Status == "" {
+			issue, err := lookup(dep.ID)
+			if err != nil || issue == nil {
+				dep.Status = "unknown"
+				open = append(open, dep)
+				continue
+			}
+			dep.Status = issue.Status
+			if dep.Title == "" {
+				dep.Title = issue.Title
+			}
+		}
+		if dep.Status != "closed" && dep.Status != "tombstone" {
+			open = append(open, dep)
+		}
+	}
+	return open
+}
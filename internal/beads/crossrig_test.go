@@ -0,0 +1,76 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveBeadRoute(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteRoutes(beadsDir, []Route{
+		{Prefix: "hq-", Path: "."},
+		{Prefix: "gt-", Path: "gastown/mayor/rig"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ResolveBeadRoute(townRoot, "external:gt:gt-123")
+	if err != nil {
+		t.Fatalf("ResolveBeadRoute: %v", err)
+	}
+	if RouteRigName(r) != "gastown" {
+		t.Errorf("rig = %q, want gastown", RouteRigName(r))
+	}
+	if r, _ := ResolveBeadRoute(townRoot, "hq-1"); RouteRigName(r) != "town" {
+		t.Errorf("town route rig = %q, want town", RouteRigName(r))
+	}
+	if _, err := ResolveBeadRoute(townRoot, "bd-456"); !errors.Is(err, ErrUnroutedBead) {
+		t.Errorf("unrouted prefix: err = %v, want ErrUnroutedBead", err)
+	}
+	if _, err := ResolveBeadRoute(townRoot, "nohyphen"); err == nil {
+		t.Error("expected error for ID without prefix")
+	}
+}
+
+func TestOpenBlockers(t *testing.T) {
+	remote := map[string]*Issue{
+		"bd-1": {ID: "bd-1", Title: "remote open", Status: "in_progress"},
+		"bd-2": {ID: "bd-2", Title: "remote done", Status: "closed"},
+	}
+	lookup := func(id string) (*Issue, error) {
+		if issue, ok := remote[id]; ok {
+			return issue, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	deps := []IssueDep{
+		{ID: "gt-a", Status: "open", DependencyType: "blocks"},
+		{ID: "gt-b", Status: "closed", DependencyType: "blocks"},
+		{ID: "gt-c", Status: "open", DependencyType: "parent-child"},
+		{ID: "external:bd:bd-1", DependencyType: "blocks"},
+		{ID: "external:bd:bd-2", Status: "open", DependencyType: "blocks"}, // stale inline status
+		{ID: "external:bd:bd-9", DependencyType: "blocks"},
+		{ID: "bd-2", Status: "open", DependencyType: "blocks"}, // older unwrapped cross-rig edge
+	}
+
+	got := OpenBlockers("gt-x", deps, lookup)
+	want := map[string]string{"gt-a": "open", "bd-1": "in_progress", "bd-9": "unknown"}
+	if len(got) != len(want) {
+		t.Fatalf("OpenBlockers = %+v, want %d blockers", got, len(want))
+	}
+	for _, dep := range got {
+		if want[dep.ID] != dep.Status {
+			t.Errorf("blocker %s status = %q, want %q", dep.ID, dep.Status, want[dep.ID])
+		}
+	}
+	if got[1].Title != "remote open" {
+		t.Errorf("cross-rig title = %q, want filled from lookup", got[1].Title)
+	}
+}
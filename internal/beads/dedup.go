@@ -0,0 +1,157 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Dependency types used to record duplicates.
+const (
+	// DependencyTypeRelated links a bead to a probable duplicate without
+	// gating either.
+	DependencyTypeRelated = "related"
+
+	// DependencyTypeDuplicates marks a bead as a duplicate of the one it
+	// depends on.
+	DependencyTypeDuplicates = "duplicates"
+)
+
+// DuplicateLabel marks a bead linked to probable duplicates.
+const DuplicateLabel = "possible-duplicate"
+
+// maxDuplicateLinks caps how many probable duplicates one bead is linked to.
+const maxDuplicateLinks = 3
+
+// DuplicateMatch is an existing bead similar to a new one.
+type DuplicateMatch struct {
+	Issue *Issue
+	Score float64
+}
+
+// DedupResult is what CheckDuplicates found and did.
+type DedupResult struct {
+	// Matches are the beads at or above the link threshold, best first.
+	Matches []DuplicateMatch
+
+	// DuplicateOf is the bead the new one was closed as a duplicate of,
+	// or "" if it was left open.
+	DuplicateOf string
+}
+
+// dedupStopWords are too common in bead titles to suggest a duplicate.
+var dedupStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// dedupTokens returns the set of lowercase words in s, minus stop words.
+func dedupTokens(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len(w) > 1 && !dedupStopWords[w] {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+// dice returns the Dice coefficient of two word sets: 1 when they are the
+// same, 0 when they share nothing.
+func dice(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// Similarity scores how alike two beads are from 0 to 1 by the words in
+// their titles and, when both have one, their descriptions. Titles weigh
+// more: descriptions of automatically filed beads share boilerplate.
+func Similarity(a, b *Issue) float64 {
+	title := dice(dedupTokens(a.Title), dedupTokens(b.Title))
+	if strings.TrimSpace(a.Description) == "" || strings.TrimSpace(b.Description) == "" {
+		return title
+	}
+	desc := dice(dedupTokens(a.Description), dedupTokens(b.Description))
+	return 0.7*title + 0.3*desc
+}
+
+// FindDuplicates returns the candidates at least threshold similar to
+// issue, best first. The issue itself, closed beads, wisps and agent beads
+// are skipped.
+func FindDuplicates(issue *Issue, candidates []*Issue, threshold float64) []DuplicateMatch {
+	var matches []DuplicateMatch
+	for _, c := range candidates {
+		if c.ID == issue.ID || c.Status == "closed" || c.Ephemeral || IsAgentBead(c) {
+			continue
+		}
+		if score := Similarity(issue, c); score >= threshold {
+			matches = append(matches, DuplicateMatch{Issue: c, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// CheckDuplicates compares a newly created issue with the open beads in
+// its database and acts on cfg's thresholds; see LinkDuplicates.
+func (b *Beads) CheckDuplicates(issue *Issue, cfg *config.DedupConfig) (*DedupResult, error) {
+	// bd list without a status leaves out closed beads.
+	candidates, err := b.List(ListOptions{Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing open beads: %w", err)
+	}
+	return b.LinkDuplicates(issue, candidates, cfg)
+}
+
+// LinkDuplicates compares a newly created issue with candidates. If the
+// best match reaches cfg's close threshold, issue gets a duplicates
+// dependency on it and is closed. Otherwise issue gets a related
+// dependency on each of the top matches reaching the link threshold and
+// the possible-duplicate label, for a human or agent to confirm.
+func (b *Beads) LinkDuplicates(issue *Issue, candidates []*Issue, cfg *config.DedupConfig) (*DedupResult, error) {
+	result := &DedupResult{Matches: FindDuplicates(issue, candidates, cfg.EffectiveLinkThreshold())}
+	if len(result.Matches) == 0 {
+		return result, nil
+	}
+
+	best := result.Matches[0]
+	if cfg.CloseThreshold > 0 && best.Score >= cfg.CloseThreshold {
+		if _, err := b.run("dep", "add", issue.ID, best.Issue.ID, "--type="+DependencyTypeDuplicates); err != nil {
+			return result, fmt.Errorf("marking %s a duplicate of %s: %w", issue.ID, best.Issue.ID, err)
+		}
+		reason := fmt.Sprintf("duplicate of %s (similarity %.2f)", best.Issue.ID, best.Score)
+		if err := b.CloseWithReason(reason, issue.ID); err != nil {
+			return result, fmt.Errorf("closing duplicate %s: %w", issue.ID, err)
+		}
+		result.DuplicateOf = best.Issue.ID
+		return result, nil
+	}
+
+	for i, m := range result.Matches {
+		if i == maxDuplicateLinks {
+			break
+		}
+		if _, err := b.run("dep", "add", issue.ID, m.Issue.ID, "--type="+DependencyTypeRelated); err != nil {
+			return result, fmt.Errorf("linking %s to %s: %w", issue.ID, m.Issue.ID, err)
+		}
+	}
+	if err := b.Update(issue.ID, UpdateOptions{AddLabels: []string{DuplicateLabel}}); err != nil {
+		return result, fmt.Errorf("labeling %s: %w", issue.ID, err)
+	}
+	return result, nil
+}
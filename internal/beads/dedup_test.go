@@ -0,0 +1,64 @@
+package beads
+
+import "testing"
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Issue
+		atLeast float64
+		below   float64
+	}{
+		{
+			name:    "same title",
+			a:       Issue{Title: "Fix the login timeout"},
+			b:       Issue{Title: "fix login timeout!"},
+			atLeast: 1, below: 1.01,
+		},
+		{
+			name:    "reworded title",
+			a:       Issue{Title: "CI red on main: build (abc1234)"},
+			b:       Issue{Title: "CI red on main: build (def5678)"},
+			atLeast: 0.75, below: 1,
+		},
+		{
+			name:    "unrelated",
+			a:       Issue{Title: "Add dark mode to dashboard"},
+			b:       Issue{Title: "Refinery drops merge requests"},
+			atLeast: 0, below: 0.01,
+		},
+		{
+			name:    "description counts when both have one",
+			a:       Issue{Title: "Login broken", Description: "token refresh fails after 30 minutes"},
+			b:       Issue{Title: "Login broken", Description: "dashboard colors are wrong"},
+			atLeast: 0.7, below: 0.71,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(&tt.a, &tt.b)
+			if got < tt.atLeast || got >= tt.below {
+				t.Errorf("Similarity = %.3f, want [%.2f, %.2f)", got, tt.atLeast, tt.below)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	issue := &Issue{ID: "gt-new", Title: "Polecat Toast crashed during build"}
+	candidates := []*Issue{
+		{ID: "gt-new", Title: "Polecat Toast crashed during build"},
+		{ID: "gt-closed", Title: "Polecat Toast crashed during build", Status: "closed"},
+		{ID: "gt-wisp", Title: "Polecat Toast crashed during build", Ephemeral: true},
+		{ID: "gt-close", Title: "Polecat Toast crashed during the build step"},
+		{ID: "gt-exact", Title: "polecat toast crashed during build"},
+		{ID: "gt-far", Title: "Update README"},
+	}
+	got := FindDuplicates(issue, candidates, 0.75)
+	if len(got) != 2 {
+		t.Fatalf("FindDuplicates returned %d matches, want 2: %+v", len(got), got)
+	}
+	if got[0].Issue.ID != "gt-exact" || got[1].Issue.ID != "gt-close" {
+		t.Errorf("matches = %s, %s; want gt-exact, gt-close", got[0].Issue.ID, got[1].Issue.ID)
+	}
+}
@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrDoltServerDown is returned instead of running bd when metadata.json
+// points at a Dolt server that isn't reachable: left alone, bd would write
+// to an isolated local database no other agent can see.
+var ErrDoltServerDown = errors.New("Dolt server is down")
+
+// ErrFallbackReadOnly is returned for bd writes while a beads directory is
+// on a read-only fallback snapshot.
+var ErrFallbackReadOnly = errors.New("beads are read-only while the Dolt server is down")
+
+// fallbackReadCommands are the bd subcommands allowed on a read-only
+// fallback snapshot.
+var fallbackReadCommands = map[string]bool{
+	"list": true, "show": true, "ready": true, "blocked": true, "search": true,
+	"stats": true, "count": true, "info": true, "version": true, "where": true,
+	"status": true, "query": true, "export": true,
+}
+
+// serverProbeTTL is how long a reachability probe of the Dolt server is
+// reused, so a burst of bd calls costs one dial.
+const serverProbeTTL = 2 * time.Second
+
+type serverProbe struct {
+	at  time.Time
+	err error
+}
+
+var (
+	serverProbesMu sync.Mutex
+	serverProbes   = map[string]serverProbe{}
+)
+
+// probeDoltServer reports whether the Dolt server at addr accepts
+// connections. A variable so tests can run without a server.
+var probeDoltServer = func(addr string) error {
+	serverProbesMu.Lock()
+	defer serverProbesMu.Unlock()
+	if p, ok := serverProbes[addr]; ok && time.Since(p.at) < serverProbeTTL {
+		return p.err
+	}
+	err := dialDolt(context.Background(), addr)
+	serverProbes[addr] = serverProbe{at: time.Now(), err: err}
+	return err
+}
+
+// checkFallback refuses to run bd args against beadsDir when the result
+// would diverge from the town's data: a server-mode database whose server
+// is down, or a write to a read-only fallback snapshot. Directories without
+// metadata.json are left to bd.
+func checkFallback(beadsDir string, args []string) error {
+	meta, err := ReadBackendMetadata(beadsDir)
+	if err != nil {
+		return nil
+	}
+	command := bdCommandName(args)
+	if f := meta.Fallback; f != nil {
+		if f.Mode == config.DoltFallbackReadOnly && !fallbackReadCommands[command] {
+			return fmt.Errorf("%w: bd %s refused (read-only snapshot since %s; run gt dolt reconcile once the server is back)",
+				ErrFallbackReadOnly, command, f.Since.Local().Format(time.DateTime))
+		}
+		return nil
+	}
+	addr, ok := meta.DoltServerAddr()
+	if !ok || command == "version" {
+		return nil
+	}
+	if err := probeDoltServer(addr); err != nil {
+		return fmt.Errorf("%w: bd %s refused rather than writing to an isolated local database (%v); start it with gt dolt start, or set dolt_fallback in settings/config.json",
+			ErrDoltServerDown, command, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckFallback(t *testing.T) {
+	orig := probeDoltServer
+	t.Cleanup(func() { probeDoltServer = orig })
+	serverErr := errors.New("connection refused")
+	probeDoltServer = func(string) error { return serverErr }
+
+	write := func(t *testing.T, meta string) string {
+		t.Helper()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, MetadataFileName), []byte(meta), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	t.Run("server down", func(t *testing.T) {
+		dir := write(t, `{"backend":"dolt","dolt_mode":"server","dolt_database":"gastown"}`)
+		if err := checkFallback(dir, []string{"list", "--json"}); !errors.Is(err, ErrDoltServerDown) {
+			t.Errorf("list = %v, want ErrDoltServerDown", err)
+		}
+		if err := checkFallback(dir, []string{"version"}); err != nil {
+			t.Errorf("version = %v, want nil", err)
+		}
+	})
+
+	t.Run("server up", func(t *testing.T) {
+		probeDoltServer = func(string) error { return nil }
+		defer func() { probeDoltServer = func(string) error { return serverErr } }()
+		dir := write(t, `{"backend":"dolt","dolt_mode":"server","dolt_database":"gastown"}`)
+		if err := checkFallback(dir, []string{"update", "gt-1"}); err != nil {
+			t.Errorf("update = %v, want nil", err)
+		}
+	})
+
+	t.Run("read-only snapshot", func(t *testing.T) {
+		since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).Format(time.RFC3339)
+		dir := write(t, `{"backend":"dolt","dolt_mode":"embedded","gt_fallback":{"mode":"read-only","since":"`+since+`"}}`)
+		if err := checkFallback(dir, []string{"--allow-stale", "show", "gt-1"}); err != nil {
+			t.Errorf("show = %v, want nil", err)
+		}
+		if err := checkFallback(dir, []string{"update", "gt-1", "--status", "closed"}); !errors.Is(err, ErrFallbackReadOnly) {
+			t.Errorf("update = %v, want ErrFallbackReadOnly", err)
+		}
+	})
+
+	t.Run("queue snapshot", func(t *testing.T) {
+		dir := write(t, `{"backend":"dolt","dolt_mode":"embedded","gt_fallback":{"mode":"queue","fork_commit":"abc"}}`)
+		if err := checkFallback(dir, []string{"create", "--title", "x"}); err != nil {
+			t.Errorf("create = %v, want nil", err)
+		}
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		if err := checkFallback(t.TempDir(), []string{"create"}); err != nil {
+			t.Errorf("create = %v, want nil", err)
+		}
+	})
+}
@@ -27,6 +27,7 @@ type AttachmentFields struct {
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
 	ConvoyOwned      bool   // If true, convoy has gt:owned label (caller-managed lifecycle)
+	Agent            string // Agent alias requested for (or used by) the polecat working this issue
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -86,6 +87,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "convoy_owned", "convoy-owned", "convoyowned":
 			fields.ConvoyOwned = strings.ToLower(value) == "true"
 			hasFields = true
+		case "agent":
+			fields.Agent = value
+			hasFields = true
 		}
 	}
 
@@ -131,6 +135,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.ConvoyOwned {
 		lines = append(lines, "convoy_owned: true")
 	}
+	if fields.Agent != "" {
+		lines = append(lines, "agent: "+fields.Agent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -167,6 +174,7 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"convoy_owned":      true,
 		"convoy-owned":      true,
 		"convoyowned":       true,
+		"agent":             true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -237,6 +245,14 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// HookResults summarizes the refinery's pre/post-merge hook outcomes
+	// (e.g., "pre_merge:lint=pass post_merge:deploy=fail").
+	HookResults string
+
+	// TestRuns summarizes the test commands the refinery ran, in order
+	// (e.g., "[go test ./internal/doltserver/...]=pass [go test ./...]=pass").
+	TestRuns string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -311,6 +327,12 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "hook_results", "hook-results", "hookresults":
+			fields.HookResults = value
+			hasFields = true
+		case "test_runs", "test-runs", "testruns":
+			fields.TestRuns = value
+			hasFields = true
 		}
 	}
 
@@ -375,6 +397,12 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.HookResults != "" {
+		lines = append(lines, "hook_results: "+fields.HookResults)
+	}
+	if fields.TestRuns != "" {
+		lines = append(lines, "test_runs: "+fields.TestRuns)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -421,6 +449,12 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"hook_results":       true,
+		"hook-results":       true,
+		"hookresults":        true,
+		"test_runs":          true,
+		"test-runs":          true,
+		"testruns":           true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -290,3 +290,21 @@ func TestParseAgentFieldsFromDescription(t *testing.T) {
 		t.Errorf("NotificationLevel = %q, want %q", got.NotificationLevel, "verbose")
 	}
 }
+
+func TestAttachmentFieldsAgentRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "agent: claude-opus\n\nNeeds a careful refactor."}
+	fields := ParseAttachmentFields(issue)
+	if fields == nil || fields.Agent != "claude-opus" {
+		t.Fatalf("ParseAttachmentFields() = %+v, want Agent claude-opus", fields)
+	}
+
+	fields.Agent = "codex"
+	desc := SetAttachmentFields(issue, fields)
+	got := ParseAttachmentFields(&Issue{Description: desc})
+	if got == nil || got.Agent != "codex" {
+		t.Errorf("round trip Agent = %+v, want codex", got)
+	}
+	if strings.Count(desc, "agent:") != 1 {
+		t.Errorf("expected a single agent line, got:\n%s", desc)
+	}
+}
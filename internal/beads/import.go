@@ -0,0 +1,273 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Import defaults.
+const (
+	// DefaultImportBatchSize is the number of issues per bd import call.
+	// Each call is one connection and one transaction on the server.
+	DefaultImportBatchSize = 500
+
+	// DefaultImportBatchBytes caps a batch's JSONL size, so a few huge
+	// descriptions don't make one transaction too large.
+	DefaultImportBatchBytes = 4 << 20
+
+	// DefaultImportCapacityPoll is how often ImportIssues rechecks a server
+	// that is near its connection limit.
+	DefaultImportCapacityPoll = 2 * time.Second
+
+	// DefaultImportCapacityWait is how long ImportIssues waits for
+	// connection capacity before giving up.
+	DefaultImportCapacityWait = 5 * time.Minute
+)
+
+// ErrImportNoCapacity is returned when the Dolt server stays near its
+// connection limit for longer than ImportOptions.CapacityWait.
+var ErrImportNoCapacity = errors.New("dolt server has no connection capacity for import")
+
+// ImportOptions controls ImportIssues. Zero values take the defaults above.
+type ImportOptions struct {
+	// BatchSize is the most issues imported per transaction.
+	BatchSize int
+
+	// BatchBytes is the most JSONL bytes imported per transaction; a
+	// single larger issue still goes in a batch of its own.
+	BatchBytes int
+
+	// HasCapacity reports whether the server can take another connection,
+	// e.g. doltserver.HasConnectionCapacity. It is checked before every
+	// batch; while it returns false or an error, the import waits. Nil
+	// skips the check.
+	HasCapacity func() (bool, error)
+
+	// CapacityPoll and CapacityWait are how often to recheck capacity and
+	// how long to wait for it in total before each batch.
+	CapacityPoll time.Duration
+	CapacityWait time.Duration
+
+	// Progress, if set, is called after every batch and while waiting for
+	// capacity.
+	Progress func(ImportProgress)
+}
+
+// ImportProgress reports how far an import has got.
+type ImportProgress struct {
+	Issues    int   // issues imported so far
+	Batches   int   // batches committed so far
+	BytesRead int64 // input consumed so far
+	BatchSize int   // current issues per batch, after any shrinking
+	Waiting   bool  // waiting for connection capacity
+}
+
+// ImportResult summarizes a finished import.
+type ImportResult struct {
+	Issues  int
+	Batches int
+
+	// Splits counts failed batches that were halved and retried.
+	Splits int
+
+	// Waited is the total time spent waiting for connection capacity.
+	Waited time.Duration
+}
+
+// importBatch is a run of JSONL lines imported in one transaction.
+type importBatch struct {
+	lines [][]byte
+	nums  []int // input line number of each line
+}
+
+func (ib importBatch) jsonl() []byte {
+	var buf bytes.Buffer
+	for _, l := range ib.lines {
+		buf.Write(l)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// ImportIssues streams issues from r, JSONL in bd export format, into the
+// database through bd import, BatchSize issues per call instead of a bd
+// create per issue. Before each batch it waits until HasCapacity allows
+// another connection. A failed batch is split in half and retried, so an
+// oversized transaction shrinks until it commits and a bad record is
+// pinned to its line; later batches keep the smaller size. bd import
+// updates issues that already exist, so rerunning an interrupted import
+// is safe.
+func (b *Beads) ImportIssues(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	return importIssues(ctx, r, opts, func(data []byte) error {
+		f, err := os.CreateTemp("", "gt-import-*.jsonl")
+		if err != nil {
+			return fmt.Errorf("creating batch file: %w", err)
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return fmt.Errorf("writing batch file: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing batch file: %w", err)
+		}
+		_, err = b.run("import", "-i", f.Name())
+		return err
+	})
+}
+
+// importIssues is ImportIssues with the bd call replaced by apply, which
+// imports one batch of JSONL.
+func importIssues(ctx context.Context, r io.Reader, opts ImportOptions, apply func([]byte) error) (*ImportResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultImportBatchSize
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = DefaultImportBatchBytes
+	}
+	if opts.CapacityPoll <= 0 {
+		opts.CapacityPoll = DefaultImportCapacityPoll
+	}
+	if opts.CapacityWait <= 0 {
+		opts.CapacityWait = DefaultImportCapacityWait
+	}
+
+	imp := &importer{opts: opts, apply: apply, result: &ImportResult{}}
+	br := bufio.NewReader(r)
+	var batch importBatch
+	var batchBytes int
+	lineNo := 0
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return imp.result, fmt.Errorf("reading import: %w", readErr)
+		}
+		imp.bytesRead += int64(len(line))
+		if len(line) > 0 {
+			lineNo++
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			var rec struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(line, &rec); err != nil {
+				return imp.result, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if rec.ID == "" {
+				return imp.result, fmt.Errorf("line %d: issue has no id", lineNo)
+			}
+			if len(batch.lines) > 0 && (len(batch.lines) >= imp.opts.BatchSize || batchBytes+len(line) > imp.opts.BatchBytes) {
+				if err := imp.flush(ctx, batch); err != nil {
+					return imp.result, err
+				}
+				batch, batchBytes = importBatch{}, 0
+			}
+			batch.lines = append(batch.lines, append([]byte(nil), line...))
+			batch.nums = append(batch.nums, lineNo)
+			batchBytes += len(line)
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+	if len(batch.lines) > 0 {
+		if err := imp.flush(ctx, batch); err != nil {
+			return imp.result, err
+		}
+	}
+	return imp.result, nil
+}
+
+// importer holds an import's running state.
+type importer struct {
+	opts      ImportOptions
+	apply     func([]byte) error
+	result    *ImportResult
+	bytesRead int64
+}
+
+func (imp *importer) progress(waiting bool) {
+	if imp.opts.Progress == nil {
+		return
+	}
+	imp.opts.Progress(ImportProgress{
+		Issues:    imp.result.Issues,
+		Batches:   imp.result.Batches,
+		BytesRead: imp.bytesRead,
+		BatchSize: imp.opts.BatchSize,
+		Waiting:   waiting,
+	})
+}
+
+// flush imports batch, halving it on failure until the halves commit or a
+// single issue fails.
+func (imp *importer) flush(ctx context.Context, batch importBatch) error {
+	if err := imp.waitForCapacity(ctx); err != nil {
+		return err
+	}
+	err := imp.apply(batch.jsonl())
+	if err == nil {
+		imp.result.Issues += len(batch.lines)
+		imp.result.Batches++
+		imp.progress(false)
+		return nil
+	}
+	if len(batch.lines) == 1 {
+		return fmt.Errorf("line %d: %w", batch.nums[0], err)
+	}
+	// Splitting won't help if bd or the server is gone.
+	if errors.Is(err, ErrNotInstalled) || errors.Is(err, ErrDoltServerDown) {
+		return fmt.Errorf("importing lines %d-%d: %w", batch.nums[0], batch.nums[len(batch.nums)-1], err)
+	}
+	imp.result.Splits++
+	half := len(batch.lines) / 2
+	if half < imp.opts.BatchSize {
+		imp.opts.BatchSize = half
+	}
+	first := importBatch{lines: batch.lines[:half], nums: batch.nums[:half]}
+	second := importBatch{lines: batch.lines[half:], nums: batch.nums[half:]}
+	if err := imp.flush(ctx, first); err != nil {
+		return err
+	}
+	return imp.flush(ctx, second)
+}
+
+// waitForCapacity blocks until HasCapacity reports room for another
+// connection. A failing check counts as no capacity, as elsewhere: a
+// server that can't report its load is likely overloaded.
+func (imp *importer) waitForCapacity(ctx context.Context) error {
+	if imp.opts.HasCapacity == nil {
+		return ctx.Err()
+	}
+	start := time.Now()
+	defer func() { imp.result.Waited += time.Since(start) }()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ok, err := imp.opts.HasCapacity()
+		if ok && err == nil {
+			return nil
+		}
+		if time.Since(start) >= imp.opts.CapacityWait {
+			if err != nil {
+				return fmt.Errorf("%w after %v: %v", ErrImportNoCapacity, imp.opts.CapacityWait, err)
+			}
+			return fmt.Errorf("%w after %v", ErrImportNoCapacity, imp.opts.CapacityWait)
+		}
+		imp.progress(true)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(imp.opts.CapacityPoll):
+		}
+	}
+}
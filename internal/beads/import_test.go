@@ -0,0 +1,136 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func importJSONL(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, `{"id":"gt-%d","title":"issue %d"}`+"\n", i, i)
+	}
+	return sb.String()
+}
+
+func TestImportIssues_Batches(t *testing.T) {
+	var sizes []int
+	var progress []ImportProgress
+	res, err := importIssues(context.Background(), strings.NewReader(importJSONL(7)), ImportOptions{
+		BatchSize: 3,
+		Progress:  func(p ImportProgress) { progress = append(progress, p) },
+	}, func(data []byte) error {
+		sizes = append(sizes, bytes.Count(data, []byte("\n")))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("importIssues: %v", err)
+	}
+	if fmt.Sprint(sizes) != "[3 3 1]" {
+		t.Errorf("batch sizes = %v, want [3 3 1]", sizes)
+	}
+	if res.Issues != 7 || res.Batches != 3 {
+		t.Errorf("result = %+v, want 7 issues in 3 batches", res)
+	}
+	if len(progress) != 3 || progress[2].Issues != 7 {
+		t.Errorf("progress = %+v", progress)
+	}
+}
+
+func TestImportIssues_BatchBytes(t *testing.T) {
+	var sizes []int
+	input := importJSONL(4)
+	lineLen := len(strings.SplitN(input, "\n", 2)[0])
+	_, err := importIssues(context.Background(), strings.NewReader(input), ImportOptions{
+		BatchBytes: 2*lineLen + 1,
+	}, func(data []byte) error {
+		sizes = append(sizes, bytes.Count(data, []byte("\n")))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("importIssues: %v", err)
+	}
+	if fmt.Sprint(sizes) != "[2 2]" {
+		t.Errorf("batch sizes = %v, want [2 2]", sizes)
+	}
+}
+
+func TestImportIssues_InvalidLine(t *testing.T) {
+	input := importJSONL(2) + "\n" + `{"title":"no id"}` + "\n"
+	_, err := importIssues(context.Background(), strings.NewReader(input), ImportOptions{}, func([]byte) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Fatalf("err = %v, want line 4 error", err)
+	}
+}
+
+func TestImportIssues_SplitsFailedBatch(t *testing.T) {
+	var sizes []int
+	res, err := importIssues(context.Background(), strings.NewReader(importJSONL(8)), ImportOptions{BatchSize: 8}, func(data []byte) error {
+		n := bytes.Count(data, []byte("\n"))
+		if n > 2 {
+			return errors.New("transaction too large")
+		}
+		sizes = append(sizes, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("importIssues: %v", err)
+	}
+	if res.Issues != 8 || res.Splits != 3 {
+		t.Errorf("result = %+v, want 8 issues after 3 splits", res)
+	}
+	if fmt.Sprint(sizes) != "[2 2 2 2]" {
+		t.Errorf("committed batch sizes = %v, want [2 2 2 2]", sizes)
+	}
+}
+
+func TestImportIssues_PinsBadRecord(t *testing.T) {
+	_, err := importIssues(context.Background(), strings.NewReader(importJSONL(6)), ImportOptions{}, func(data []byte) error {
+		if bytes.Contains(data, []byte(`"gt-5"`)) {
+			return errors.New("constraint violation")
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("err = %v, want line 5 error", err)
+	}
+}
+
+func TestImportIssues_WaitsForCapacity(t *testing.T) {
+	checks := 0
+	waiting := false
+	res, err := importIssues(context.Background(), strings.NewReader(importJSONL(2)), ImportOptions{
+		HasCapacity: func() (bool, error) {
+			checks++
+			return checks > 2, nil
+		},
+		CapacityPoll: time.Millisecond,
+		Progress:     func(p ImportProgress) { waiting = waiting || p.Waiting },
+	}, func([]byte) error { return nil })
+	if err != nil {
+		t.Fatalf("importIssues: %v", err)
+	}
+	if res.Issues != 2 || !waiting {
+		t.Errorf("result = %+v, waiting reported = %v", res, waiting)
+	}
+}
+
+func TestImportIssues_CapacityTimeout(t *testing.T) {
+	applied := false
+	_, err := importIssues(context.Background(), strings.NewReader(importJSONL(1)), ImportOptions{
+		HasCapacity:  func() (bool, error) { return false, errors.New("connection refused") },
+		CapacityPoll: time.Millisecond,
+		CapacityWait: 5 * time.Millisecond,
+	}, func([]byte) error { applied = true; return nil })
+	if !errors.Is(err, ErrImportNoCapacity) {
+		t.Fatalf("err = %v, want ErrImportNoCapacity", err)
+	}
+	if applied {
+		t.Error("batch applied without capacity")
+	}
+}
@@ -0,0 +1,461 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// JournalFile is the town's write-ahead journal of bead mutations queued
+// while the Dolt server was unreachable, relative to the town root.
+const JournalFile = ".runtime/bead-journal.jsonl"
+
+// Journal record kinds. A queued record holds the mutation; applied and
+// conflict records log what happened when it was replayed.
+const (
+	JournalQueued   = "queued"
+	JournalApplied  = "applied"
+	JournalConflict = "conflict"
+)
+
+// JournalPending is the state of a queued mutation not yet replayed.
+const JournalPending = "pending"
+
+// JournalRecord is one line of the journal.
+type JournalRecord struct {
+	Seq  int       `json:"seq"`
+	Kind string    `json:"kind"`
+	At   time.Time `json:"at"`
+
+	// Set on queued records: the bd command and where to run it.
+	BeadsDir string   `json:"beads_dir,omitempty"`
+	WorkDir  string   `json:"work_dir,omitempty"`
+	Actor    string   `json:"actor,omitempty"`
+	Args     []string `json:"args,omitempty"`
+	Issues   []string `json:"issues,omitempty"`
+
+	// Detail explains a conflict.
+	Detail string `json:"detail,omitempty"`
+}
+
+// JournalEntry is a queued mutation with the outcome of its latest replay.
+type JournalEntry struct {
+	Seq       int       `json:"seq"`
+	QueuedAt  time.Time `json:"queued_at"`
+	BeadsDir  string    `json:"beads_dir"`
+	WorkDir   string    `json:"work_dir"`
+	Actor     string    `json:"actor,omitempty"`
+	Args      []string  `json:"args"`
+	Issues    []string  `json:"issues"`
+	State     string    `json:"state"` // pending, applied, or conflict
+	Detail    string    `json:"detail,omitempty"`
+	AppliedAt time.Time `json:"applied_at,omitzero"`
+}
+
+// Command returns the journaled bd command line, for display.
+func (e JournalEntry) Command() string {
+	return "bd " + strings.Join(e.Args, " ")
+}
+
+// journalPath returns the journal file for townRoot.
+func journalPath(townRoot string) string {
+	return filepath.Join(townRoot, JournalFile)
+}
+
+// ReadJournal returns the town's journaled mutations in the order they
+// were queued. A missing journal has none.
+func ReadJournal(townRoot string) ([]JournalEntry, error) {
+	records, err := readJournalRecords(journalPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	return foldJournal(records), nil
+}
+
+// UnappliedJournal returns the journaled mutations still pending or held
+// as conflicts.
+func UnappliedJournal(townRoot string) ([]JournalEntry, error) {
+	entries, err := ReadJournal(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	unapplied := make([]JournalEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.State != JournalApplied {
+			unapplied = append(unapplied, e)
+		}
+	}
+	return unapplied, nil
+}
+
+func readJournalRecords(path string) ([]JournalRecord, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading bead journal: %w", err)
+	}
+	var records []JournalRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r JournalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("bead journal line %d: %w", line, err)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// foldJournal applies outcome records to the queued records they name.
+func foldJournal(records []JournalRecord) []JournalEntry {
+	var entries []JournalEntry
+	bySeq := map[int]int{}
+	for _, r := range records {
+		if r.Kind == JournalQueued {
+			bySeq[r.Seq] = len(entries)
+			entries = append(entries, JournalEntry{
+				Seq:      r.Seq,
+				QueuedAt: r.At,
+				BeadsDir: r.BeadsDir,
+				WorkDir:  r.WorkDir,
+				Actor:    r.Actor,
+				Args:     r.Args,
+				Issues:   r.Issues,
+				State:    JournalPending,
+			})
+			continue
+		}
+		i, ok := bySeq[r.Seq]
+		if !ok {
+			continue
+		}
+		switch r.Kind {
+		case JournalApplied:
+			entries[i].State, entries[i].Detail, entries[i].AppliedAt = JournalApplied, "", r.At
+		case JournalConflict:
+			entries[i].State, entries[i].Detail = JournalConflict, r.Detail
+		}
+	}
+	return entries
+}
+
+// appendJournal appends r to the journal under a cross-process lock,
+// numbering it first if it is a queued record. It returns r's Seq.
+func appendJournal(townRoot string, r JournalRecord) (int, error) {
+	path := journalPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("creating journal dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return 0, fmt.Errorf("acquiring bead journal lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	if r.Kind == JournalQueued {
+		records, err := readJournalRecords(path)
+		if err != nil {
+			return 0, err
+		}
+		r.Seq = 1
+		for _, prev := range records {
+			r.Seq = max(r.Seq, prev.Seq+1)
+		}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling journal record: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return 0, fmt.Errorf("opening bead journal: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return 0, fmt.Errorf("writing bead journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return 0, fmt.Errorf("syncing bead journal: %w", err)
+	}
+	return r.Seq, nil
+}
+
+// journalIssues returns the issues a bd mutation touches, and whether it is
+// one the journal can queue: one whose output gt doesn't read. Creates are
+// left out since callers need the new issue's ID.
+func journalIssues(args []string) ([]string, bool) {
+	var pos []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			pos = append(pos, arg)
+		}
+	}
+	if len(pos) < 2 {
+		return nil, false
+	}
+	switch pos[0] {
+	case "update", "close", "reopen":
+		return pos[1:], true
+	case "dep":
+		if len(pos) >= 4 && (pos[1] == "add" || pos[1] == "remove") {
+			return pos[2:4], true
+		}
+	case "label":
+		if len(pos) >= 4 && (pos[1] == "add" || pos[1] == "remove") {
+			return pos[2:3], true
+		}
+	case "comments":
+		if len(pos) >= 4 && pos[1] == "add" {
+			return pos[2:3], true
+		}
+	}
+	return nil, false
+}
+
+// queueMutation journals bd args when the town's dolt_fallback policy is
+// "journal" and the command can be replayed later. queued is false when
+// the command should fail as usual.
+func (b *Beads) queueMutation(beadsDir string, args []string) (queued bool, err error) {
+	townRoot := b.getTownRoot()
+	if townRoot == "" {
+		return false, nil
+	}
+	issues, ok := journalIssues(args)
+	if !ok {
+		return false, nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.DoltFallback != config.DoltFallbackJournal {
+		return false, nil
+	}
+	_, err = appendJournal(townRoot, JournalRecord{
+		Kind:     JournalQueued,
+		At:       time.Now().UTC(),
+		BeadsDir: beadsDir,
+		WorkDir:  b.workDir,
+		Actor:    b.getActor(),
+		Args:     args,
+		Issues:   issues,
+	})
+	return true, err
+}
+
+// ApplyJournalOptions controls ApplyJournal.
+type ApplyJournalOptions struct {
+	// Force replays entries in conflict instead of holding them.
+	Force bool
+
+	// DryRun checks for conflicts without replaying anything.
+	DryRun bool
+}
+
+// ApplyJournal replays the journal's unapplied mutations in the order they
+// were queued and returns them with their outcome. An entry is held as a
+// conflict when an issue it touches changed on the server after it was
+// queued (other than by an earlier replay) or no longer exists; later
+// entries for the same issue wait behind it so order is kept. A journal
+// whose entries have all been applied is removed.
+func ApplyJournal(townRoot string, opts ApplyJournalOptions) ([]JournalEntry, error) {
+	entries, err := ReadJournal(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last time a replay changed each issue, so gt's own replays
+	// don't read as conflicts for the entries after them.
+	replayed := map[string]time.Time{}
+	for _, e := range entries {
+		if e.State == JournalApplied {
+			for _, id := range e.Issues {
+				replayed[id] = later(replayed[id], e.AppliedAt)
+			}
+		}
+	}
+
+	held := map[string]bool{}
+	var results []JournalEntry
+	for _, e := range entries {
+		if e.State == JournalApplied {
+			continue
+		}
+		if id := firstHeld(e.Issues, held); id != "" {
+			e.Detail = fmt.Sprintf("waiting on an earlier entry for %s", id)
+			results = append(results, hold(e, held))
+			continue
+		}
+
+		if !opts.Force {
+			detail, err := journalConflict(e, replayed)
+			if err != nil {
+				return results, err
+			}
+			if detail != "" {
+				if !opts.DryRun && (e.State != JournalConflict || e.Detail != detail) {
+					if _, err := appendJournal(townRoot, JournalRecord{Seq: e.Seq, Kind: JournalConflict, At: time.Now().UTC(), Detail: detail}); err != nil {
+						return results, err
+					}
+				}
+				e.State, e.Detail = JournalConflict, detail
+				results = append(results, hold(e, held))
+				continue
+			}
+		}
+		if opts.DryRun {
+			results = append(results, e)
+			continue
+		}
+
+		if err := runJournaled(e); err != nil {
+			if errors.Is(err, ErrDoltServerDown) {
+				return results, err
+			}
+			e.State, e.Detail = JournalConflict, err.Error()
+			if _, err := appendJournal(townRoot, JournalRecord{Seq: e.Seq, Kind: JournalConflict, At: time.Now().UTC(), Detail: e.Detail}); err != nil {
+				return results, err
+			}
+			results = append(results, hold(e, held))
+			continue
+		}
+		now := time.Now().UTC()
+		if _, err := appendJournal(townRoot, JournalRecord{Seq: e.Seq, Kind: JournalApplied, At: now}); err != nil {
+			return results, err
+		}
+		e.State, e.Detail, e.AppliedAt = JournalApplied, "", now
+		for _, id := range e.Issues {
+			replayed[id] = now
+		}
+		results = append(results, e)
+	}
+
+	if !opts.DryRun {
+		if err := removeAppliedJournal(townRoot); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// hold marks e's issues as held and returns e.
+func hold(e JournalEntry, held map[string]bool) JournalEntry {
+	for _, id := range e.Issues {
+		held[id] = true
+	}
+	return e
+}
+
+func firstHeld(issues []string, held map[string]bool) string {
+	for _, id := range issues {
+		if held[id] {
+			return id
+		}
+	}
+	return ""
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// journalConflict describes why e can't be replayed safely, or returns ""
+// if it can. replayed holds when earlier replays last changed each issue.
+func journalConflict(e JournalEntry, replayed map[string]time.Time) (string, error) {
+	for _, id := range e.Issues {
+		updated, err := journalIssueUpdatedAt(e, id)
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Sprintf("%s no longer exists", id), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("checking %s: %w", id, err)
+		}
+		if updated.After(later(e.QueuedAt, replayed[id])) {
+			return fmt.Sprintf("%s changed on the server at %s, after this was queued",
+				id, updated.Local().Format(time.DateTime)), nil
+		}
+	}
+	return "", nil
+}
+
+// journalIssueUpdatedAt returns when issue id was last updated, as seen
+// from e's beads directory. A zero time means bd didn't say. A variable so
+// tests can run without bd.
+var journalIssueUpdatedAt = func(e JournalEntry, id string) (time.Time, error) {
+	issue, err := NewWithBeadsDir(e.WorkDir, e.BeadsDir).Show(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	updated, _ := time.Parse(time.RFC3339, issue.UpdatedAt)
+	return updated, nil
+}
+
+// runJournaled replays e with the actor that queued it. A variable so tests
+// can run without bd.
+var runJournaled = func(e JournalEntry) error {
+	if err := checkFallback(e.BeadsDir, e.Args); err != nil {
+		return err
+	}
+	cmd := exec.Command("bd", append([]string{"--allow-stale"}, e.Args...)...) //nolint:gosec // G204: bd is a trusted internal tool
+	cmd.Dir = e.WorkDir
+	env := make([]string, 0, len(os.Environ())+2)
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "BD_ACTOR=") && !strings.HasPrefix(kv, "BEADS_DIR=") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, "BEADS_DIR="+e.BeadsDir)
+	if e.Actor != "" {
+		env = append(env, "BD_ACTOR="+e.Actor)
+	}
+	cmd.Env = env
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := util.Run(cmd, util.BeadsTimeout); err != nil {
+		return (&Beads{}).wrapError(err, stderr.String(), e.Args)
+	}
+	return nil
+}
+
+// removeAppliedJournal removes the journal once every entry in it has been
+// applied, checking again under the lock in case one was just queued.
+func removeAppliedJournal(townRoot string) error {
+	path := journalPath(townRoot)
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring bead journal lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	records, err := readJournalRecords(path)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	for _, e := range foldJournal(records) {
+		if e.State != JournalApplied {
+			return nil
+		}
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing bead journal: %w", err)
+	}
+	return nil
+}
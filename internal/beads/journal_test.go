@@ -0,0 +1,159 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestJournalIssues(t *testing.T) {
+	tests := []struct {
+		args   []string
+		want   []string
+		wantOK bool
+	}{
+		{[]string{"update", "gt-1", "--status=closed"}, []string{"gt-1"}, true},
+		{[]string{"close", "gt-1", "gt-2", "--reason=done"}, []string{"gt-1", "gt-2"}, true},
+		{[]string{"dep", "add", "gt-1", "gt-2"}, []string{"gt-1", "gt-2"}, true},
+		{[]string{"label", "add", "gt-1", "urgent"}, []string{"gt-1"}, true},
+		{[]string{"comments", "add", "gt-1", "looks good"}, []string{"gt-1"}, true},
+		{[]string{"create", "--title=x", "--json"}, nil, false},
+		{[]string{"show", "gt-1", "--json"}, nil, false},
+		{[]string{"dep", "list", "gt-1"}, nil, false},
+		{[]string{"update"}, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := journalIssues(tt.args)
+		if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("journalIssues(%q) = %q, %v; want %q, %v", tt.args, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// journalTown returns a town root whose dolt_fallback is policy.
+func journalTown(t *testing.T, policy string) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.DoltFallback = policy
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestQueueMutation(t *testing.T) {
+	townRoot := journalTown(t, config.DoltFallbackJournal)
+	b := New(townRoot)
+	beadsDir := filepath.Join(townRoot, ".beads")
+
+	if queued, err := b.queueMutation(beadsDir, []string{"update", "hq-1", "--status=closed"}); !queued || err != nil {
+		t.Fatalf("update: queued = %v, %v", queued, err)
+	}
+	if queued, _ := b.queueMutation(beadsDir, []string{"create", "--title=x"}); queued {
+		t.Error("create was queued")
+	}
+	if queued, err := b.queueMutation(beadsDir, []string{"close", "hq-2"}); !queued || err != nil {
+		t.Fatalf("close: queued = %v, %v", queued, err)
+	}
+
+	entries, err := ReadJournal(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Seq != 1 || entries[1].Seq != 2 {
+		t.Fatalf("entries = %+v, want seq 1 and 2", entries)
+	}
+	if e := entries[0]; e.State != JournalPending || e.BeadsDir != beadsDir || e.Command() != "bd update hq-1 --status=closed" {
+		t.Errorf("entry = %+v", e)
+	}
+
+	failTown := journalTown(t, config.DoltFallbackFail)
+	if queued, _ := New(failTown).queueMutation(beadsDir, []string{"update", "hq-1"}); queued {
+		t.Error("queued under the fail policy")
+	}
+}
+
+func TestApplyJournal(t *testing.T) {
+	origRun, origUpdated := runJournaled, journalIssueUpdatedAt
+	t.Cleanup(func() { runJournaled, journalIssueUpdatedAt = origRun, origUpdated })
+
+	townRoot := t.TempDir()
+	queuedAt := time.Now().UTC().Add(-time.Hour)
+	for _, args := range [][]string{
+		{"update", "gt-1", "--status=in_progress"},
+		{"close", "gt-2"},
+		{"update", "gt-2", "--assignee="},
+		{"close", "gt-1"},
+	} {
+		issues, _ := journalIssues(args)
+		if _, err := appendJournal(townRoot, JournalRecord{Kind: JournalQueued, At: queuedAt, Args: args, Issues: issues}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// gt-2 was changed on the server after the entries were queued; gt-1
+	// only by the replays themselves.
+	var mu sync.Mutex
+	lastWrite := map[string]time.Time{"gt-1": queuedAt.Add(-time.Minute)}
+	journalIssueUpdatedAt = func(_ JournalEntry, id string) (time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if id == "gt-2" {
+			return queuedAt.Add(time.Minute), nil
+		}
+		return lastWrite[id], nil
+	}
+	var ran []int
+	runJournaled = func(e JournalEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ran = append(ran, e.Seq)
+		for _, id := range e.Issues {
+			lastWrite[id] = time.Now().UTC()
+		}
+		return nil
+	}
+
+	results, err := ApplyJournal(townRoot, ApplyJournalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := map[int]string{}
+	for _, r := range results {
+		states[r.Seq] = r.State
+	}
+	want := map[int]string{1: JournalApplied, 2: JournalConflict, 3: JournalPending, 4: JournalApplied}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("states = %v, want %v", states, want)
+	}
+	if !reflect.DeepEqual(ran, []int{1, 4}) {
+		t.Errorf("ran = %v, want [1 4]", ran)
+	}
+
+	unapplied, err := UnappliedJournal(townRoot)
+	if err != nil || len(unapplied) != 2 || unapplied[0].State != JournalConflict || unapplied[0].Detail == "" {
+		t.Fatalf("unapplied = %+v, %v; want #2 in conflict and #3 pending", unapplied, err)
+	}
+
+	if _, err := ApplyJournal(townRoot, ApplyJournalOptions{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ran, []int{1, 4, 2, 3}) {
+		t.Errorf("ran = %v, want [1 4 2 3]", ran)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, JournalFile)); !os.IsNotExist(err) {
+		t.Error("journal not removed once fully applied")
+	}
+}
@@ -0,0 +1,61 @@
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// checkLabels checks labels about to be applied against the label taxonomy
+// of the rig this wrapper's beads belong to. In strict mode labels outside
+// it are refused; otherwise they are reported on stderr and applied.
+func (b *Beads) checkLabels(labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	townRoot := b.getTownRoot()
+	rigName := rigOfBeadsDir(townRoot, b.getResolvedBeadsDir())
+	if rigName == "" {
+		return nil
+	}
+	return applyLabelTaxonomy(config.LoadLabelsConfig(filepath.Join(townRoot, rigName)), labels)
+}
+
+// applyLabelTaxonomy returns the taxonomy's errors for labels if it is
+// strict, and otherwise prints them as a warning and returns nil.
+func applyLabelTaxonomy(taxonomy *config.LabelsConfig, labels []string) error {
+	var errs []error
+	for _, label := range labels {
+		if err := taxonomy.CheckLabel(label); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+	if err == nil || taxonomy.IsStrict() {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	return nil
+}
+
+// rigOfBeadsDir returns the rig whose beads live in beadsDir
+// (<town>/<rig>/.beads or <town>/<rig>/mayor/rig/.beads), or "" for the
+// town's own beads or a directory outside the town.
+func rigOfBeadsDir(townRoot, beadsDir string) string {
+	if townRoot == "" || beadsDir == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(townRoot, beadsDir)
+	if err != nil {
+		return ""
+	}
+	first, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	if first == "." || first == ".." || strings.HasPrefix(first, ".") || first == "mayor" || first == "deacon" {
+		return ""
+	}
+	return first
+}
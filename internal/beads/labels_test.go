@@ -0,0 +1,66 @@
+package beads
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestRigOfBeadsDir(t *testing.T) {
+	town := filepath.FromSlash("/town")
+	tests := []struct {
+		beadsDir string
+		want     string
+	}{
+		{"/town/gastown/.beads", "gastown"},
+		{"/town/gastown/mayor/rig/.beads", "gastown"},
+		{"/town/.beads", ""},
+		{"/town/mayor/.beads", ""},
+		{"/elsewhere/.beads", ""},
+	}
+	for _, tt := range tests {
+		if got := rigOfBeadsDir(town, filepath.FromSlash(tt.beadsDir)); got != tt.want {
+			t.Errorf("rigOfBeadsDir(%s) = %q, want %q", tt.beadsDir, got, tt.want)
+		}
+	}
+	if got := rigOfBeadsDir("", "/town/gastown/.beads"); got != "" {
+		t.Errorf("rigOfBeadsDir outside a town = %q", got)
+	}
+}
+
+func TestApplyLabelTaxonomy(t *testing.T) {
+	taxonomy := &config.LabelsConfig{
+		Namespaces: map[string]*config.LabelNamespace{"status": {Values: []string{"docked"}}},
+	}
+	if err := applyLabelTaxonomy(taxonomy, []string{"status:dock"}); err != nil {
+		t.Errorf("warn mode returned %v", err)
+	}
+
+	taxonomy.Enforce = config.LabelsStrict
+	err := applyLabelTaxonomy(taxonomy, []string{"gt:task", "status:dock"})
+	if !errors.Is(err, config.ErrLabelNotAllowed) {
+		t.Errorf("strict mode returned %v, want ErrLabelNotAllowed", err)
+	}
+	if err := applyLabelTaxonomy(taxonomy, []string{"status:docked"}); err != nil {
+		t.Errorf("allowed label returned %v", err)
+	}
+	if err := applyLabelTaxonomy(nil, []string{"anything"}); err != nil {
+		t.Errorf("nil taxonomy returned %v", err)
+	}
+}
+
+func TestCreateLabelArgs(t *testing.T) {
+	b := New(t.TempDir())
+	args, err := b.createLabelArgs(CreateOptions{Type: "task", Labels: []string{"polecat:nux", "state:pending"}})
+	if err != nil {
+		t.Fatalf("createLabelArgs: %v", err)
+	}
+	if len(args) != 1 || args[0] != "--labels=gt:task,polecat:nux,state:pending" {
+		t.Errorf("createLabelArgs = %v", args)
+	}
+	if args, _ := b.createLabelArgs(CreateOptions{}); len(args) != 0 {
+		t.Errorf("createLabelArgs with no labels = %v, want none", args)
+	}
+}
@@ -0,0 +1,368 @@
+package beads
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/errclass"
+)
+
+// DefaultReportCacheTTL is how long report results are reused before the
+// underlying query is run again.
+const DefaultReportCacheTTL = 5 * time.Minute
+
+// reportWorkTypes limits analytics to human-meaningful work items, excluding
+// infrastructure beads (agents, messages, molecules, merge requests, ...).
+var reportWorkTypes = []any{"task", "bug", "feature", "epic", "chore"}
+
+// Query is a minimal SELECT builder for read-only analytics against the
+// beads schema. Conditions use '?' placeholders; Build returns the SQL and
+// its arguments in placeholder order.
+type Query struct {
+	table   string
+	columns []string
+	where   []string
+	args    []any
+	groupBy []string
+	orderBy []string
+	limit   int
+}
+
+// From starts a query against table.
+func From(table string) *Query {
+	return &Query{table: table}
+}
+
+// Select appends result columns (expressions are allowed).
+func (q *Query) Select(columns ...string) *Query {
+	q.columns = append(q.columns, columns...)
+	return q
+}
+
+// Where adds a condition, ANDed with any existing conditions.
+func (q *Query) Where(cond string, args ...any) *Query {
+	q.where = append(q.where, cond)
+	q.args = append(q.args, args...)
+	return q
+}
+
+// WhereIn adds "column IN (?, ...)" for values. An empty list matches nothing.
+func (q *Query) WhereIn(column string, values ...any) *Query {
+	if len(values) == 0 {
+		return q.Where("1 = 0")
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return q.Where(fmt.Sprintf("%s IN (%s)", column, placeholders), values...)
+}
+
+// WorkItems restricts the query to non-ephemeral work items.
+func (q *Query) WorkItems() *Query {
+	return q.Where("COALESCE(ephemeral, 0) = 0").WhereIn("issue_type", reportWorkTypes...)
+}
+
+// GroupBy appends GROUP BY expressions.
+func (q *Query) GroupBy(columns ...string) *Query {
+	q.groupBy = append(q.groupBy, columns...)
+	return q
+}
+
+// OrderBy appends ORDER BY expressions (e.g. "week DESC").
+func (q *Query) OrderBy(columns ...string) *Query {
+	q.orderBy = append(q.orderBy, columns...)
+	return q
+}
+
+// Limit caps the number of rows returned. Zero means no limit.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Build renders the query.
+func (q *Query) Build() (string, []any) {
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	if len(q.columns) == 0 {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strings.Join(q.columns, ", "))
+	}
+	sb.WriteString(" FROM ")
+	sb.WriteString(q.table)
+	if len(q.where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(q.where, " AND "))
+	}
+	if len(q.groupBy) > 0 {
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(strings.Join(q.groupBy, ", "))
+	}
+	if len(q.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		fmt.Fprintf(&sb, " LIMIT %d", q.limit)
+	}
+	return sb.String(), q.args
+}
+
+// Reporter runs analytics queries against a rig's beads database on the
+// Dolt server and caches results on disk.
+type Reporter struct {
+	db       *sql.DB
+	database string
+	cacheDir string
+	cacheTTL time.Duration
+	retry    errclass.Policies
+}
+
+// NewReporter creates a reporter for database using db, which must already
+// be connected to that database. cacheDir holds cached results; an empty
+// cacheDir or non-positive ttl disables caching. Transient query errors are
+// retried with errclass.DefaultPolicies until SetRetryPolicies says otherwise.
+func NewReporter(db *sql.DB, database, cacheDir string, ttl time.Duration) *Reporter {
+	return &Reporter{db: db, database: database, cacheDir: cacheDir, cacheTTL: ttl, retry: errclass.DefaultPolicies()}
+}
+
+// SetRetryPolicies replaces the policies used to retry failed queries.
+func (r *Reporter) SetRetryPolicies(p errclass.Policies) {
+	r.retry = p
+}
+
+// WeeklyThroughput is the number of work items closed in a week.
+type WeeklyThroughput struct {
+	WeekStart string `json:"week_start"` // Monday, YYYY-MM-DD
+	Closed    int    `json:"closed"`
+}
+
+// IssueCycleTime is the time from creation to close for one issue.
+type IssueCycleTime struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Assignee string  `json:"assignee,omitempty"`
+	ClosedAt string  `json:"closed_at"`
+	Hours    float64 `json:"hours"`
+}
+
+// IssueAging is an open or in-progress issue and how long it has been waiting.
+type IssueAging struct {
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	Status    string  `json:"status"`
+	Assignee  string  `json:"assignee,omitempty"`
+	AgeDays   float64 `json:"age_days"`
+	IdleDays  float64 `json:"idle_days"` // since last update
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// AssigneeCompletion summarizes how much of the work assigned to an agent
+// was closed.
+type AssigneeCompletion struct {
+	Assignee string  `json:"assignee"`
+	Assigned int     `json:"assigned"`
+	Closed   int     `json:"closed"`
+	Rate     float64 `json:"rate"` // closed / assigned, 0..1
+}
+
+// Throughput returns closed work items per week for the last weeks weeks.
+func (r *Reporter) Throughput(ctx context.Context, weeks int) ([]WeeklyThroughput, error) {
+	week := "DATE_FORMAT(DATE_SUB(closed_at, INTERVAL WEEKDAY(closed_at) DAY), '%Y-%m-%d')"
+	q := From("issues").
+		Select(week+" AS week_start", "COUNT(*)").
+		Where("status = 'closed'").
+		Where("closed_at IS NOT NULL").
+		Where("closed_at >= DATE_SUB(CURDATE(), INTERVAL ? WEEK)", weeks).
+		WorkItems().
+		GroupBy("week_start").
+		OrderBy("week_start")
+
+	return cachedReport(ctx, r, "throughput", q, func(rows *sql.Rows) (WeeklyThroughput, error) {
+		var t WeeklyThroughput
+		err := rows.Scan(&t.WeekStart, &t.Closed)
+		return t, err
+	})
+}
+
+// CycleTime returns created-to-closed durations for work closed in the last
+// days days, most recent first.
+func (r *Reporter) CycleTime(ctx context.Context, days, limit int) ([]IssueCycleTime, error) {
+	q := From("issues").
+		Select("id", "title", "COALESCE(assignee, '')",
+			"DATE_FORMAT(closed_at, '%Y-%m-%d %H:%i')",
+			"TIMESTAMPDIFF(SECOND, created_at, closed_at) / 3600.0").
+		Where("status = 'closed'").
+		Where("closed_at IS NOT NULL").
+		Where("closed_at >= DATE_SUB(NOW(), INTERVAL ? DAY)", days).
+		WorkItems().
+		OrderBy("closed_at DESC").
+		Limit(limit)
+
+	return cachedReport(ctx, r, "cycle-time", q, func(rows *sql.Rows) (IssueCycleTime, error) {
+		var c IssueCycleTime
+		err := rows.Scan(&c.ID, &c.Title, &c.Assignee, &c.ClosedAt, &c.Hours)
+		return c, err
+	})
+}
+
+// Aging returns open and in-progress work items, oldest first.
+func (r *Reporter) Aging(ctx context.Context, limit int) ([]IssueAging, error) {
+	q := From("issues").
+		Select("id", "title", "status", "COALESCE(assignee, '')",
+			"TIMESTAMPDIFF(SECOND, created_at, NOW()) / 86400.0",
+			"TIMESTAMPDIFF(SECOND, updated_at, NOW()) / 86400.0",
+			"DATE_FORMAT(updated_at, '%Y-%m-%d %H:%i')").
+		WhereIn("status", "open", "in_progress").
+		WorkItems().
+		OrderBy("created_at").
+		Limit(limit)
+
+	return cachedReport(ctx, r, "aging", q, func(rows *sql.Rows) (IssueAging, error) {
+		var a IssueAging
+		err := rows.Scan(&a.ID, &a.Title, &a.Status, &a.Assignee, &a.AgeDays, &a.IdleDays, &a.UpdatedAt)
+		return a, err
+	})
+}
+
+// PolecatCompletion returns per-polecat completion rates for work created in
+// the last days days. Only assignees under a rig's polecats/ are included.
+func (r *Reporter) PolecatCompletion(ctx context.Context, days int) ([]AssigneeCompletion, error) {
+	q := From("issues").
+		Select("assignee", "COUNT(*)", "SUM(CASE WHEN status = 'closed' THEN 1 ELSE 0 END)").
+		Where("assignee LIKE ?", "%/polecats/%").
+		Where("created_at >= DATE_SUB(NOW(), INTERVAL ? DAY)", days).
+		WorkItems().
+		GroupBy("assignee").
+		OrderBy("assignee")
+
+	return cachedReport(ctx, r, "polecat-completion", q, func(rows *sql.Rows) (AssigneeCompletion, error) {
+		var c AssigneeCompletion
+		if err := rows.Scan(&c.Assignee, &c.Assigned, &c.Closed); err != nil {
+			return c, err
+		}
+		if c.Assigned > 0 {
+			c.Rate = float64(c.Closed) / float64(c.Assigned)
+		}
+		return c, nil
+	})
+}
+
+// WorkSummary counts a rig's work items by status.
+type WorkSummary struct {
+	Open       int `json:"open"`
+	InProgress int `json:"in_progress"`
+	Blocked    int `json:"blocked"`
+	Closed     int `json:"closed"`
+
+	// OldestInProgressHours is the age of the oldest in-progress item, or 0.
+	OldestInProgressHours float64 `json:"oldest_in_progress_hours"`
+
+	// ReadyUnassigned counts open, unassigned items with no open blocker.
+	ReadyUnassigned int `json:"ready_unassigned"`
+}
+
+// SummarizeWork counts work items by status in a single query. Unlike the
+// reports it is neither cached nor retried, so callers bound it with ctx.
+func SummarizeWork(ctx context.Context, db *sql.DB) (*WorkSummary, error) {
+	count := func(cond string) string {
+		return "COALESCE(SUM(CASE WHEN " + cond + " THEN 1 ELSE 0 END), 0)"
+	}
+	query, args := From("issues i").
+		Select(count("i.status = 'open'"),
+			count("i.status = 'in_progress'"),
+			count("i.status = 'blocked'"),
+			count("i.status = 'closed'"),
+			"COALESCE(MAX(CASE WHEN i.status = 'in_progress' THEN TIMESTAMPDIFF(SECOND, i.created_at, NOW()) END), 0) / 3600.0",
+			count(`i.status = 'open' AND COALESCE(i.assignee, '') = '' AND NOT EXISTS (
+				SELECT 1 FROM dependencies d JOIN issues b ON b.id = d.depends_on_id
+				WHERE d.issue_id = i.id AND d.type = 'blocks' AND b.status != 'closed')`)).
+		WorkItems().
+		Build()
+
+	var s WorkSummary
+	if err := db.QueryRowContext(ctx, query, args...).Scan(
+		&s.Open, &s.InProgress, &s.Blocked, &s.Closed, &s.OldestInProgressHours, &s.ReadyUnassigned,
+	); err != nil {
+		return nil, fmt.Errorf("summarizing work: %w", err)
+	}
+	return &s, nil
+}
+
+// reportCacheEntry is the on-disk cache format.
+type reportCacheEntry[T any] struct {
+	CreatedAt time.Time `json:"created_at"`
+	Rows      []T       `json:"rows"`
+}
+
+// cachedReport runs q and scans each row with scan, reusing a cached result
+// younger than the reporter's TTL. Cache failures fall through to the query.
+func cachedReport[T any](ctx context.Context, r *Reporter, name string, q *Query, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	query, args := q.Build()
+	cachePath := r.cachePath(name, query, args)
+
+	if cachePath != "" {
+		if data, err := os.ReadFile(cachePath); err == nil { //nolint:gosec // G304: path is constructed internally
+			var entry reportCacheEntry[T]
+			if json.Unmarshal(data, &entry) == nil && time.Since(entry.CreatedAt) < r.cacheTTL {
+				return entry.Rows, nil
+			}
+		}
+	}
+
+	var results []T
+	err := errclass.Retry(ctx, r.retry, func() error {
+		var err error
+		results, err = runReport(ctx, r.db, query, args, scan)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s report: %w", name, err)
+	}
+
+	if cachePath != "" {
+		if data, err := json.Marshal(reportCacheEntry[T]{CreatedAt: time.Now(), Rows: results}); err == nil {
+			if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+				_ = os.WriteFile(cachePath, data, 0644) //nolint:gosec // G306: cache of non-sensitive analytics
+			}
+		}
+	}
+	return results, nil
+}
+
+// runReport runs query once and scans every row.
+func runReport[T any](ctx context.Context, db *sql.DB, query string, args []any, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []T{}
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+		results = append(results, v)
+	}
+	return results, rows.Err()
+}
+
+// cachePath returns the cache file for a report query, or "" when caching
+// is disabled.
+func (r *Reporter) cachePath(name, query string, args []any) string {
+	if r.cacheDir == "" || r.cacheTTL <= 0 {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%v", r.database, query, args)
+	return filepath.Join(r.cacheDir, fmt.Sprintf("%s-%s-%s.json", r.database, name, hex.EncodeToString(h.Sum(nil))[:16]))
+}
@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryBuild(t *testing.T) {
+	query, args := From("issues").
+		Select("assignee", "COUNT(*)").
+		Where("status = ?", "closed").
+		WhereIn("issue_type", "task", "bug").
+		GroupBy("assignee").
+		OrderBy("assignee").
+		Limit(10).
+		Build()
+
+	want := "SELECT assignee, COUNT(*) FROM issues WHERE status = ? AND issue_type IN (?, ?) GROUP BY assignee ORDER BY assignee LIMIT 10"
+	if query != want {
+		t.Errorf("Build() query =\n%s\nwant\n%s", query, want)
+	}
+	if !reflect.DeepEqual(args, []any{"closed", "task", "bug"}) {
+		t.Errorf("Build() args = %v", args)
+	}
+}
+
+func TestQueryBuild_Defaults(t *testing.T) {
+	query, args := From("issues").Build()
+	if query != "SELECT * FROM issues" || len(args) != 0 {
+		t.Errorf("Build() = (%q, %v)", query, args)
+	}
+
+	query, _ = From("issues").WhereIn("status").Build()
+	if !strings.Contains(query, "1 = 0") {
+		t.Errorf("empty WhereIn should match nothing, got %q", query)
+	}
+}
+
+func TestReporterUsesFreshCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	// db is nil: a cache hit must not touch the database.
+	r := NewReporter(nil, "gastown", cacheDir, time.Hour)
+
+	q := From("issues").Select("x").Where("a = ?", 1)
+	query, args := q.Build()
+	path := r.cachePath("test", query, args)
+	data, err := json.Marshal(reportCacheEntry[WeeklyThroughput]{
+		CreatedAt: time.Now(),
+		Rows:      []WeeklyThroughput{{WeekStart: "2026-03-02", Closed: 7}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cachedReport[WeeklyThroughput](context.Background(), r, "test", q, nil)
+	if err != nil {
+		t.Fatalf("cachedReport() error: %v", err)
+	}
+	if len(got) != 1 || got[0].Closed != 7 {
+		t.Errorf("cachedReport() = %+v, want cached row", got)
+	}
+}
+
+func TestReporterCachePathDisabled(t *testing.T) {
+	if p := NewReporter(nil, "gastown", "", time.Hour).cachePath("x", "q", nil); p != "" {
+		t.Errorf("cachePath() with no dir = %q, want empty", p)
+	}
+	if p := NewReporter(nil, "gastown", t.TempDir(), 0).cachePath("x", "q", nil); p != "" {
+		t.Errorf("cachePath() with zero TTL = %q, want empty", p)
+	}
+}
@@ -0,0 +1,61 @@
+package beads
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheduling hint label prefixes. These are optional; issues without them
+// are scheduled on priority and age alone.
+const (
+	SizeLabelPrefix     = "size:"     // size:S, size:M, size:L, size:XL, or size:<points>
+	DeadlineLabelPrefix = "deadline:" // deadline:2026-11-01 or an RFC3339 timestamp
+	WeightLabelPrefix   = "weight:"   // weight:1.5 scales the issue's score
+)
+
+// sizePoints maps t-shirt sizes to points.
+var sizePoints = map[string]int{"XS": 1, "S": 2, "M": 3, "L": 5, "XL": 8}
+
+// MaxSizePoints is the largest size, used to normalize size scores.
+const MaxSizePoints = 8
+
+// SchedulingHints are the optional scheduling fields of an issue.
+type SchedulingHints struct {
+	Size     int       // points; 0 if unset
+	Deadline time.Time // zero if unset
+	Weight   float64   // score multiplier; 1 if unset
+}
+
+// ParseSchedulingHints reads the size, deadline, and weight labels of an
+// issue. Malformed values are ignored.
+func ParseSchedulingHints(issue *Issue) SchedulingHints {
+	h := SchedulingHints{Weight: 1}
+	if issue == nil {
+		return h
+	}
+	for _, label := range issue.Labels {
+		switch {
+		case strings.HasPrefix(label, SizeLabelPrefix):
+			v := strings.TrimSpace(strings.TrimPrefix(label, SizeLabelPrefix))
+			if p, ok := sizePoints[strings.ToUpper(v)]; ok {
+				h.Size = p
+			} else if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				h.Size = n
+			}
+		case strings.HasPrefix(label, DeadlineLabelPrefix):
+			v := strings.TrimSpace(strings.TrimPrefix(label, DeadlineLabelPrefix))
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				h.Deadline = t
+			} else if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+				h.Deadline = t.Add(24*time.Hour - time.Second) // end of day
+			}
+		case strings.HasPrefix(label, WeightLabelPrefix):
+			v := strings.TrimSpace(strings.TrimPrefix(label, WeightLabelPrefix))
+			if w, err := strconv.ParseFloat(v, 64); err == nil && w >= 0 {
+				h.Weight = w
+			}
+		}
+	}
+	return h
+}
@@ -0,0 +1,26 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedulingHints(t *testing.T) {
+	h := ParseSchedulingHints(&Issue{Labels: []string{"size:l", "deadline:2026-11-01", "weight:1.5", "gt:task"}})
+	if h.Size != 5 || h.Weight != 1.5 {
+		t.Errorf("hints = %+v", h)
+	}
+	if want := time.Date(2026, 11, 1, 23, 59, 59, 0, time.Local); !h.Deadline.Equal(want) {
+		t.Errorf("deadline = %v, want end of day %v", h.Deadline, want)
+	}
+
+	h = ParseSchedulingHints(&Issue{Labels: []string{"size:13", "deadline:2026-11-01T09:00:00Z"}})
+	if h.Size != 13 || h.Deadline.Hour() != 9 || h.Weight != 1 {
+		t.Errorf("points/RFC3339 hints = %+v", h)
+	}
+
+	h = ParseSchedulingHints(&Issue{Labels: []string{"size:huge", "deadline:soon", "weight:-1"}})
+	if h.Size != 0 || !h.Deadline.IsZero() || h.Weight != 1 {
+		t.Errorf("malformed hints not ignored: %+v", h)
+	}
+}
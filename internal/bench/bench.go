@@ -0,0 +1,205 @@
+// Package bench measures how long key town operations take and compares
+// the results with a stored baseline, so performance work has a
+// reproducible before and after.
+//
+// gt bench runs the benchmarks from TownBenchmarks against the current
+// town; --save records the results as the baseline later runs compare to.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultThreshold is how much slower than its baseline median a benchmark
+// may get before it counts as a regression.
+const DefaultThreshold = 0.20
+
+// Benchmark is one operation to measure.
+type Benchmark struct {
+	Name string
+
+	// Unit names what Run processes, for throughput ("queries", "rigs").
+	Unit string
+
+	// Run performs the operation once and returns how many units it
+	// processed.
+	Run func(ctx context.Context) (int, error)
+
+	// Teardown, if set, undoes a run's side effects. It runs after every
+	// run and isn't timed.
+	Teardown func()
+}
+
+// Result summarizes a benchmark's runs. Latencies cover successful runs only.
+type Result struct {
+	Name     string `json:"name"`
+	Unit     string `json:"unit"`
+	Runs     int    `json:"runs"`
+	Failures int    `json:"failures,omitempty"`
+
+	Min  time.Duration `json:"min_ns"`
+	Mean time.Duration `json:"mean_ns"`
+	P50  time.Duration `json:"p50_ns"`
+	P90  time.Duration `json:"p90_ns"`
+	P99  time.Duration `json:"p99_ns"`
+	Max  time.Duration `json:"max_ns"`
+
+	// Throughput is units processed per second of successful runs.
+	Throughput float64 `json:"throughput"`
+
+	// Error is the last failure, if any run failed.
+	Error string `json:"error,omitempty"`
+}
+
+// OK reports whether any run succeeded, so the latencies mean something.
+func (r Result) OK() bool {
+	return r.Runs > r.Failures
+}
+
+// Measure runs b n times, stopping early if ctx is cancelled.
+func Measure(ctx context.Context, b Benchmark, n int) Result {
+	res := Result{Name: b.Name, Unit: b.Unit}
+	var samples []time.Duration
+	units := 0
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		start := time.Now()
+		count, err := b.Run(ctx)
+		elapsed := time.Since(start)
+		if b.Teardown != nil {
+			b.Teardown()
+		}
+
+		res.Runs++
+		if err != nil {
+			res.Failures++
+			res.Error = err.Error()
+			continue
+		}
+		samples = append(samples, elapsed)
+		units += count
+	}
+
+	summarize(&res, samples)
+	if total := sum(samples); total > 0 {
+		res.Throughput = float64(units) / total.Seconds()
+	}
+	return res
+}
+
+// summarize fills in the latency statistics of res from samples.
+func summarize(res *Result, samples []time.Duration) {
+	if len(samples) == 0 {
+		return
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	res.Min = sorted[0]
+	res.Max = sorted[len(sorted)-1]
+	res.Mean = sum(sorted) / time.Duration(len(sorted))
+	res.P50 = percentile(sorted, 50)
+	res.P90 = percentile(sorted, 90)
+	res.P99 = percentile(sorted, 99)
+}
+
+// percentile returns the nearest-rank pth percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func sum(samples []time.Duration) time.Duration {
+	var total time.Duration
+	for _, s := range samples {
+		total += s
+	}
+	return total
+}
+
+// Baseline is a saved set of results to compare later runs with.
+type Baseline struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	Version    string    `json:"version,omitempty"`
+	Results    []Result  `json:"results"`
+}
+
+// BaselinePath returns where gt bench keeps the town's baseline.
+func BaselinePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "bench", "baseline.json")
+}
+
+// LoadBaseline reads a baseline. It returns nil and no error when the file
+// doesn't exist.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// SaveBaseline writes b to path, creating its directory.
+func SaveBaseline(path string, b *Baseline) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating baseline directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, b)
+}
+
+// Comparison is a result measured against its baseline.
+type Comparison struct {
+	Name     string        `json:"name"`
+	Baseline time.Duration `json:"baseline_p50_ns"`
+	Current  time.Duration `json:"current_p50_ns"`
+
+	// Change is the relative change in median latency: 0.25 is 25% slower.
+	Change    float64 `json:"change"`
+	Regressed bool    `json:"regressed"`
+}
+
+// Compare compares each result's median latency with the baseline's.
+// A result is a regression when it is more than threshold slower.
+// Results without a usable baseline entry are left out.
+func Compare(base *Baseline, results []Result, threshold float64) []Comparison {
+	if base == nil {
+		return nil
+	}
+	prev := make(map[string]Result, len(base.Results))
+	for _, r := range base.Results {
+		prev[r.Name] = r
+	}
+
+	var out []Comparison
+	for _, r := range results {
+		b, ok := prev[r.Name]
+		if !ok || !b.OK() || !r.OK() || b.P50 <= 0 {
+			continue
+		}
+		change := float64(r.P50)/float64(b.P50) - 1
+		out = append(out, Comparison{
+			Name:      r.Name,
+			Baseline:  b.P50,
+			Current:   r.P50,
+			Change:    change,
+			Regressed: change > threshold,
+		})
+	}
+	return out
+}
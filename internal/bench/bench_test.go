@@ -0,0 +1,124 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	var res Result
+	summarize(&res, samples)
+
+	ms := time.Millisecond
+	if res.Min != ms || res.Max != 100*ms {
+		t.Errorf("min/max = %v/%v", res.Min, res.Max)
+	}
+	if res.P50 != 50*ms || res.P90 != 90*ms || res.P99 != 99*ms {
+		t.Errorf("p50/p90/p99 = %v/%v/%v", res.P50, res.P90, res.P99)
+	}
+	if res.Mean != 50500*time.Microsecond {
+		t.Errorf("mean = %v", res.Mean)
+	}
+
+	var one Result
+	summarize(&one, []time.Duration{7 * ms})
+	if one.P50 != 7*ms || one.P99 != 7*ms {
+		t.Errorf("single sample: %+v", one)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	runs, teardowns := 0, 0
+	b := Benchmark{
+		Name: "fake",
+		Unit: "items",
+		Run: func(context.Context) (int, error) {
+			runs++
+			if runs == 2 {
+				return 0, errors.New("boom")
+			}
+			time.Sleep(time.Millisecond)
+			return 10, nil
+		},
+		Teardown: func() { teardowns++ },
+	}
+
+	res := Measure(context.Background(), b, 4)
+	if res.Runs != 4 || res.Failures != 1 || res.Error != "boom" || !res.OK() {
+		t.Fatalf("result = %+v", res)
+	}
+	if teardowns != 4 {
+		t.Errorf("teardown ran %d times, want 4", teardowns)
+	}
+	if res.P50 < time.Millisecond || res.Throughput <= 0 || res.Throughput > 10000 {
+		t.Errorf("p50 = %v, throughput = %v", res.P50, res.Throughput)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := Measure(ctx, b, 4); got.Runs != 0 || got.OK() {
+		t.Errorf("cancelled measure = %+v", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ms := time.Millisecond
+	base := &Baseline{Results: []Result{
+		{Name: "fast", Runs: 5, P50: 10 * ms},
+		{Name: "slow", Runs: 5, P50: 10 * ms},
+		{Name: "broken", Runs: 5, Failures: 5},
+	}}
+	results := []Result{
+		{Name: "fast", Runs: 5, P50: 8 * ms},
+		{Name: "slow", Runs: 5, P50: 13 * ms},
+		{Name: "broken", Runs: 5, P50: 1 * ms},
+		{Name: "new", Runs: 5, P50: 1 * ms},
+	}
+
+	got := Compare(base, results, 0.2)
+	if len(got) != 2 {
+		t.Fatalf("comparisons = %+v, want fast and slow only", got)
+	}
+	if got[0].Name != "fast" || got[0].Regressed || got[0].Change > -0.19 {
+		t.Errorf("fast = %+v", got[0])
+	}
+	if got[1].Name != "slow" || !got[1].Regressed {
+		t.Errorf("slow = %+v, want a regression", got[1])
+	}
+	if Compare(base, results, 0.5)[1].Regressed {
+		t.Error("30% slowdown regressed with a 50% threshold")
+	}
+	if Compare(nil, results, 0.2) != nil {
+		t.Error("comparisons without a baseline")
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".runtime", "bench", "baseline.json")
+	if b, err := LoadBaseline(path); b != nil || err != nil {
+		t.Fatalf("missing baseline = %v, %v", b, err)
+	}
+
+	want := &Baseline{
+		RecordedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Version:    "0.7.0",
+		Results:    []Result{{Name: "dolt.query", Unit: "queries", Runs: 20, P50: 1500 * time.Microsecond, Throughput: 640}},
+	}
+	if err := SaveBaseline(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.RecordedAt.Equal(want.RecordedAt) || len(got.Results) != 1 || got.Results[0] != want.Results[0] {
+		t.Errorf("loaded %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/pkg/gastown"
+)
+
+// Options selects the benchmarks TownBenchmarks returns.
+type Options struct {
+	// Spawn adds polecat.spawn, which creates and removes a real polecat
+	// worktree in SpawnRig on every run.
+	Spawn    bool
+	SpawnRig string
+}
+
+// TownBenchmarks returns the benchmarks for the town at townRoot:
+//
+//	dolt.query       one count query on the town beads database
+//	rig.status       collecting agent status for every rig
+//	metadata.ensure  ensuring metadata.json for every rig database
+//	polecat.spawn    allocating and creating a polecat (opt-in; no session)
+func TownBenchmarks(townRoot string, opts Options) ([]Benchmark, error) {
+	town, err := gastown.Open(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	rigs, err := town.Rigs()
+	if err != nil {
+		return nil, fmt.Errorf("listing rigs: %w", err)
+	}
+
+	db, err := doltserver.DB(townRoot, "hq")
+	if err != nil {
+		return nil, err
+	}
+
+	benchmarks := []Benchmark{
+		{
+			Name: "dolt.query",
+			Unit: "queries",
+			Run: func(ctx context.Context) (int, error) {
+				var n int
+				err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues WHERE status = 'open'").Scan(&n)
+				return 1, err
+			},
+		},
+		{
+			Name: "rig.status",
+			Unit: "rigs",
+			Run: func(context.Context) (int, error) {
+				for _, r := range rigs {
+					if _, err := town.RigStatus(r.Name); err != nil {
+						return 0, fmt.Errorf("%s: %w", r.Name, err)
+					}
+				}
+				return len(rigs), nil
+			},
+		},
+		{
+			Name: "metadata.ensure",
+			Unit: "rigs",
+			Run: func(context.Context) (int, error) {
+				results, err := doltserver.EnsureAllMetadataParallel(townRoot, doltserver.DefaultMetadataWorkers, nil)
+				if err != nil {
+					return 0, err
+				}
+				for _, r := range results {
+					if r.Status == doltserver.MetadataError {
+						return 0, fmt.Errorf("%s: %s", r.Rig, r.Error)
+					}
+				}
+				return len(results), nil
+			},
+		},
+	}
+
+	if opts.Spawn {
+		b, err := spawnBenchmark(townRoot, opts.SpawnRig)
+		if err != nil {
+			return nil, err
+		}
+		benchmarks = append(benchmarks, b)
+	}
+	return benchmarks, nil
+}
+
+// spawnBenchmark measures what gt sling does to get a polecat ready before
+// starting its session: the Dolt checks, name allocation, and worktree and
+// agent bead creation. Each run's polecat is removed again, untimed.
+func spawnBenchmark(townRoot, rigName string) (Benchmark, error) {
+	if rigName == "" {
+		return Benchmark{}, errors.New("polecat.spawn needs a rig")
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return Benchmark{}, fmt.Errorf("loading rigs config: %w", err)
+	}
+	r, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+	if err != nil {
+		return Benchmark{}, fmt.Errorf("rig '%s' not found", rigName)
+	}
+	mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+
+	var spawned string
+	return Benchmark{
+		Name: "polecat.spawn",
+		Unit: "polecats",
+		Run: func(context.Context) (int, error) {
+			if err := mgr.CheckDoltHealth(); err != nil {
+				return 0, err
+			}
+			if err := mgr.CheckDoltServerCapacity(); err != nil {
+				return 0, err
+			}
+			name, err := mgr.AllocateName()
+			if err != nil {
+				return 0, fmt.Errorf("allocating polecat name: %w", err)
+			}
+			spawned = name
+			if _, err := mgr.AddWithOptions(name, polecat.AddOptions{}); err != nil {
+				return 0, fmt.Errorf("creating polecat: %w", err)
+			}
+			return 1, nil
+		},
+		Teardown: func() {
+			if spawned == "" {
+				return
+			}
+			if err := mgr.Remove(spawned, true); err != nil {
+				mgr.ReleaseName(spawned)
+			}
+			spawned = ""
+		},
+	}, nil
+}
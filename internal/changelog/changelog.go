@@ -0,0 +1,387 @@
+// Package changelog correlates commits on a rig's default branch with the
+// beads they closed, and renders the result as a markdown changelog.
+//
+// Correlation tries, in order: the merge commit recorded on a closed MR
+// bead, "Bead:"/"Closes:"-style commit trailers, the same trailers in a
+// refs/notes/beads note (written by Backfill), a polecat branch name in the
+// commit message, and a parenthesized bead ID in the subject (the form the
+// refinery writes for squash merges).
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Via records how a commit was linked to its beads.
+type Via string
+
+const (
+	ViaMergeRequest Via = "merge-request" // MR bead's merge_commit
+	ViaTrailer      Via = "trailer"       // Closes:/Fixes:/Bead: trailer
+	ViaNote         Via = "note"          // Bead: trailer in a refs/notes/beads note
+	ViaBranch       Via = "branch"        // polecat/<worker>/<bead> in the message
+	ViaSubject      Via = "subject"       // "(<bead>)" in the subject
+)
+
+// Category groups changelog entries.
+type Category string
+
+const (
+	CategoryFeature Category = "feature"
+	CategoryBug     Category = "bug"
+	CategoryChore   Category = "chore"
+	CategoryOther   Category = "other"
+)
+
+// categories lists categories in changelog order with their headings.
+var categories = []struct {
+	cat   Category
+	title string
+}{
+	{CategoryFeature, "Features"},
+	{CategoryBug, "Bug Fixes"},
+	{CategoryChore, "Chores"},
+	{CategoryOther, "Other Changes"},
+}
+
+// trailerKeys are the commit trailers that name closed beads.
+var trailerKeys = map[string]bool{
+	"bead": true, "beads": true, "closes": true, "fixes": true, "resolves": true, "issue": true,
+}
+
+// MergedMR is a closed merge-request bead that recorded its merge commit.
+type MergedMR struct {
+	ID          string
+	Title       string
+	SourceIssue string
+}
+
+// Link ties a commit to the beads it closed. A commit with no beads is
+// still a Link, so every commit appears in the changelog.
+type Link struct {
+	Commit git.LogCommit
+	Beads  []string // closed bead IDs, most authoritative first
+	MR     string   // merge-request bead, when known
+	Via    Via      // empty when no bead was found
+}
+
+// Correlator links commits to beads for one rig.
+type Correlator struct {
+	merged map[string]MergedMR // by merge commit SHA
+	idRe   *regexp.Regexp
+}
+
+// NewCorrelator returns a Correlator recognising bead IDs with the given
+// prefixes (e.g. "gt"). merged maps merge commit SHAs to their MR beads and
+// may be nil.
+func NewCorrelator(prefixes []string, merged map[string]MergedMR) *Correlator {
+	var alts []string
+	for _, p := range prefixes {
+		p = strings.TrimSuffix(p, "-")
+		if p != "" {
+			alts = append(alts, regexp.QuoteMeta(p))
+		}
+	}
+	if len(alts) == 0 {
+		alts = []string{"gt"}
+	}
+	return &Correlator{
+		merged: merged,
+		idRe:   regexp.MustCompile(`^(?:` + strings.Join(alts, "|") + `)-[a-z0-9]+(?:[.-][a-z0-9]+)*$`),
+	}
+}
+
+// MergedMRs loads closed MR beads that recorded a merge commit, keyed by
+// that commit's SHA.
+func MergedMRs(b *beads.Beads) (map[string]MergedMR, error) {
+	issues, err := b.List(beads.ListOptions{
+		Status:   "closed",
+		Label:    "gt:merge-request",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]MergedMR)
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil || fields.MergeCommit == "" {
+			continue
+		}
+		merged[fields.MergeCommit] = MergedMR{ID: issue.ID, Title: issue.Title, SourceIssue: fields.SourceIssue}
+	}
+	return merged, nil
+}
+
+// Correlate links each commit to the beads it closed, preserving order.
+func (c *Correlator) Correlate(commits []git.LogCommit) []Link {
+	links := make([]Link, 0, len(commits))
+	for _, commit := range commits {
+		links = append(links, c.link(commit))
+	}
+	return links
+}
+
+func (c *Correlator) link(commit git.LogCommit) Link {
+	l := Link{Commit: commit}
+	if mr, ok := c.merged[commit.SHA]; ok {
+		l.MR, l.Via = mr.ID, ViaMergeRequest
+		if mr.SourceIssue != "" {
+			l.Beads = []string{mr.SourceIssue}
+			return l
+		}
+	}
+	if ids := c.trailerBeads(commit.Body); len(ids) > 0 {
+		l.Beads, l.Via = ids, ViaTrailer
+		return l
+	}
+	if ids := c.trailerBeads(commit.Notes); len(ids) > 0 {
+		l.Beads, l.Via = ids, ViaNote
+		return l
+	}
+	if id := c.branchBead(commit.Subject + "\n" + commit.Body); id != "" {
+		l.Beads, l.Via = []string{id}, ViaBranch
+		return l
+	}
+	if id := c.subjectBead(commit.Subject); id != "" {
+		l.Beads, l.Via = []string{id}, ViaSubject
+	}
+	return l
+}
+
+// trailerBeads returns bead IDs named by trailers such as "Closes: gt-abc".
+func (c *Correlator) trailerBeads(body string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !trailerKeys[strings.ToLower(strings.TrimSpace(key))] {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			word = strings.TrimRight(word, ".;")
+			if c.idRe.MatchString(word) && !seen[word] {
+				seen[word] = true
+				ids = append(ids, word)
+			}
+		}
+	}
+	return ids
+}
+
+// branchBead returns the bead ID from a polecat/<worker>/<bead>[@ts] branch
+// mentioned in text.
+func (c *Correlator) branchBead(text string) string {
+	for _, word := range strings.Fields(text) {
+		parts := strings.SplitN(strings.Trim(word, "'\"`()"), "/", 3)
+		if len(parts) != 3 || parts[0] != "polecat" {
+			continue
+		}
+		id, _, _ := strings.Cut(parts[2], "@")
+		if c.idRe.MatchString(id) {
+			return id
+		}
+	}
+	return ""
+}
+
+// subjectBead returns the last parenthesized bead ID in subject, as in
+// "feat: add lanes (gt-abc)".
+func (c *Correlator) subjectBead(subject string) string {
+	for rest := subject; ; {
+		open := strings.LastIndex(rest, "(")
+		if open < 0 {
+			return ""
+		}
+		if end := strings.Index(rest[open:], ")"); end > 0 {
+			if id := rest[open+1 : open+end]; c.idRe.MatchString(id) {
+				return id
+			}
+		}
+		rest = rest[:open]
+	}
+}
+
+// Entry is one change in a changelog.
+type Entry struct {
+	Commit string   `json:"commit"`
+	Title  string   `json:"title"`
+	Beads  []string `json:"beads,omitempty"`
+	MR     string   `json:"mr,omitempty"`
+	Via    Via      `json:"via,omitempty"`
+}
+
+// Section is the entries of one category.
+type Section struct {
+	Category Category `json:"category"`
+	Title    string   `json:"title"`
+	Entries  []Entry  `json:"entries"`
+}
+
+// Changelog is the grouped changes between two revisions.
+type Changelog struct {
+	Rig      string    `json:"rig,omitempty"`
+	From     string    `json:"from,omitempty"`
+	To       string    `json:"to"`
+	Sections []Section `json:"sections"`
+
+	// Warnings note beads lookups that failed; the changelog is still
+	// built from what git alone records.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Options selects the commits for Generate.
+type Options struct {
+	Rig      string   // rig name, for the heading
+	Prefixes []string // bead ID prefixes to recognise
+	From     string   // exclusive start revision; empty means from the root
+	To       string   // inclusive end revision
+}
+
+// Generate builds the changelog for the first-parent commits in
+// From..To of g, correlated with the beads in b.
+func Generate(g *git.Git, b *beads.Beads, opts Options) (*Changelog, error) {
+	revRange := opts.To
+	if opts.From != "" {
+		revRange = opts.From + ".." + opts.To
+	}
+	commits, err := g.LogWithNotes(revRange, NotesRef)
+	if err != nil {
+		return nil, fmt.Errorf("listing commits in %s: %w", revRange, err)
+	}
+	cl := &Changelog{Rig: opts.Rig, From: opts.From, To: opts.To}
+
+	merged, err := MergedMRs(b)
+	if err != nil {
+		cl.Warnings = append(cl.Warnings, fmt.Sprintf("listing merged MRs: %v", err))
+	}
+	links := NewCorrelator(opts.Prefixes, merged).Correlate(commits)
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, l := range links {
+		for _, id := range l.Beads {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	issues, err := b.ShowMultiple(ids)
+	if err != nil {
+		cl.Warnings = append(cl.Warnings, fmt.Sprintf("loading linked beads: %v", err))
+	}
+
+	cl.Sections = Build(links, issues)
+	return cl, nil
+}
+
+// Build groups links into sections by the category of their first bead.
+// issues holds the linked beads by ID; a link whose bead is missing falls
+// back to its commit subject and conventional-commit type.
+func Build(links []Link, issues map[string]*beads.Issue) []Section {
+	byCat := make(map[Category][]Entry)
+	for _, l := range links {
+		entry := Entry{Commit: l.Commit.SHA, Title: l.Commit.Subject, Beads: l.Beads, MR: l.MR, Via: l.Via}
+		cat := CategoryOther
+		if len(l.Beads) > 0 {
+			entry.Title = strings.TrimSuffix(entry.Title, " ("+l.Beads[0]+")")
+			if issue := issues[l.Beads[0]]; issue != nil {
+				if issue.Title != "" {
+					entry.Title = issue.Title
+				}
+				cat = IssueCategory(issue)
+			}
+		}
+		if cat == CategoryOther {
+			cat = subjectCategory(l.Commit.Subject)
+		}
+		byCat[cat] = append(byCat[cat], entry)
+	}
+
+	var sections []Section
+	for _, c := range categories {
+		if entries := byCat[c.cat]; len(entries) > 0 {
+			sections = append(sections, Section{Category: c.cat, Title: c.title, Entries: entries})
+		}
+	}
+	return sections
+}
+
+// IssueCategory classifies a bead by its feature/bug/chore label (bare or
+// namespaced, e.g. "gt:bug"), then by its type.
+func IssueCategory(issue *beads.Issue) Category {
+	for _, label := range issue.Labels {
+		if i := strings.LastIndex(label, ":"); i >= 0 {
+			label = label[i+1:]
+		}
+		if cat := categoryOf(label); cat != CategoryOther {
+			return cat
+		}
+	}
+	return categoryOf(issue.Type)
+}
+
+func categoryOf(s string) Category {
+	switch strings.ToLower(s) {
+	case "feature", "feat", "enhancement":
+		return CategoryFeature
+	case "bug", "fix", "bugfix":
+		return CategoryBug
+	case "chore", "refactor", "docs", "test", "build", "ci", "perf", "style":
+		return CategoryChore
+	}
+	return CategoryOther
+}
+
+// subjectCategory classifies a commit by its conventional-commit type
+// ("feat(mq)!: ..." is a feature).
+func subjectCategory(subject string) Category {
+	typ, _, ok := strings.Cut(subject, ":")
+	if !ok || strings.ContainsAny(typ, " \t") {
+		return CategoryOther
+	}
+	if i := strings.Index(typ, "("); i >= 0 {
+		typ = typ[:i]
+	}
+	return categoryOf(strings.TrimSuffix(typ, "!"))
+}
+
+// Markdown renders the changelog.
+func Markdown(cl *Changelog) string {
+	var sb strings.Builder
+	heading := cl.To
+	if cl.From != "" {
+		heading = cl.From + ".." + cl.To
+	}
+	if cl.Rig != "" {
+		heading = cl.Rig + " " + heading
+	}
+	fmt.Fprintf(&sb, "## %s\n", heading)
+	if len(cl.Sections) == 0 {
+		sb.WriteString("\nNo changes.\n")
+	}
+	for _, s := range cl.Sections {
+		fmt.Fprintf(&sb, "\n### %s\n\n", s.Title)
+		for _, e := range s.Entries {
+			if len(e.Beads) > 0 {
+				fmt.Fprintf(&sb, "- %s (%s)\n", e.Title, strings.Join(e.Beads, ", "))
+			} else {
+				fmt.Fprintf(&sb, "- %s (%s)\n", e.Title, shortSHA(e.Commit))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
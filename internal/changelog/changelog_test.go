@@ -0,0 +1,119 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestCorrelate(t *testing.T) {
+	c := NewCorrelator([]string{"gt-"}, map[string]MergedMR{
+		"aaa": {ID: "gt-mr-1", SourceIssue: "gt-abc"},
+		"bbb": {ID: "gt-mr-2"},
+	})
+	tests := []struct {
+		name      string
+		commit    git.LogCommit
+		wantBeads []string
+		wantVia   Via
+		wantMR    string
+	}{
+		{
+			name:      "merge request wins over subject",
+			commit:    git.LogCommit{SHA: "aaa", Subject: "feat: lanes (gt-zzz)"},
+			wantBeads: []string{"gt-abc"}, wantVia: ViaMergeRequest, wantMR: "gt-mr-1",
+		},
+		{
+			name:      "MR without source issue falls through",
+			commit:    git.LogCommit{SHA: "bbb", Subject: "fix: typo (gt-y1)"},
+			wantBeads: []string{"gt-y1"}, wantVia: ViaSubject, wantMR: "gt-mr-2",
+		},
+		{
+			name:      "trailers",
+			commit:    git.LogCommit{SHA: "ccc", Subject: "Tidy", Body: "Details.\n\nCloses: gt-a1, gt-b2.\nfixes: gt-a1 bd-x9\nSigned-off-by: x"},
+			wantBeads: []string{"gt-a1", "gt-b2"}, wantVia: ViaTrailer,
+		},
+		{
+			name:      "squash merge of a polecat branch",
+			commit:    git.LogCommit{SHA: "ddd", Subject: "Squash merge polecat/nux/gt-q7.2@mk12 into main"},
+			wantBeads: []string{"gt-q7.2"}, wantVia: ViaBranch,
+		},
+		{
+			name:      "parenthesized subject ID",
+			commit:    git.LogCommit{SHA: "eee", Subject: "refactor(mq): split engineer (see #12) (gt-wisp-k3)"},
+			wantBeads: []string{"gt-wisp-k3"}, wantVia: ViaSubject,
+		},
+		{
+			name:   "other prefixes are ignored",
+			commit: git.LogCommit{SHA: "fff", Subject: "Bump deps (bd-123)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := c.Correlate([]git.LogCommit{tt.commit})[0]
+			if !reflect.DeepEqual(l.Beads, tt.wantBeads) || l.Via != tt.wantVia || l.MR != tt.wantMR {
+				t.Errorf("link = beads %v via %q mr %q; want %v via %q mr %q",
+					l.Beads, l.Via, l.MR, tt.wantBeads, tt.wantVia, tt.wantMR)
+			}
+		})
+	}
+}
+
+func TestIssueCategory(t *testing.T) {
+	tests := []struct {
+		issue beads.Issue
+		want  Category
+	}{
+		{beads.Issue{Labels: []string{"gt:merge-request", "gt:bug"}, Type: "task"}, CategoryBug},
+		{beads.Issue{Labels: []string{"feature"}}, CategoryFeature},
+		{beads.Issue{Type: "chore"}, CategoryChore},
+		{beads.Issue{Type: "task"}, CategoryOther},
+	}
+	for _, tt := range tests {
+		if got := IssueCategory(&tt.issue); got != tt.want {
+			t.Errorf("IssueCategory(%+v) = %q, want %q", tt.issue, got, tt.want)
+		}
+	}
+}
+
+func TestBuildAndMarkdown(t *testing.T) {
+	links := []Link{
+		{Commit: git.LogCommit{SHA: "1111111111", Subject: "fix: crash (gt-b)"}, Beads: []string{"gt-b"}, Via: ViaSubject},
+		{Commit: git.LogCommit{SHA: "2222222222", Subject: "Add lanes"}, Beads: []string{"gt-a"}, MR: "gt-mr-a", Via: ViaMergeRequest},
+		{Commit: git.LogCommit{SHA: "3333333333", Subject: "docs: readme"}},
+		{Commit: git.LogCommit{SHA: "4444444444", Subject: "Misc (gt-gone)"}, Beads: []string{"gt-gone"}, Via: ViaSubject},
+	}
+	issues := map[string]*beads.Issue{
+		"gt-a": {ID: "gt-a", Title: "Priority lanes", Type: "feature"},
+		"gt-b": {ID: "gt-b", Title: "Engineer crashes on empty queue", Type: "task"},
+	}
+
+	sections := Build(links, issues)
+	var titles []string
+	for _, s := range sections {
+		titles = append(titles, s.Title)
+	}
+	if want := []string{"Features", "Bug Fixes", "Chores", "Other Changes"}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("sections = %v, want %v", titles, want)
+	}
+
+	got := Markdown(&Changelog{Rig: "gastown", From: "v1.2.0", To: "HEAD", Sections: sections})
+	for _, want := range []string{
+		"## gastown v1.2.0..HEAD\n",
+		"### Features\n\n- Priority lanes (gt-a)\n",
+		"### Bug Fixes\n\n- Engineer crashes on empty queue (gt-b)\n",
+		"### Chores\n\n- docs: readme (33333333)\n",
+		"### Other Changes\n\n- Misc (gt-gone)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+
+	if got := Markdown(&Changelog{To: "HEAD"}); got != "## HEAD\n\nNo changes.\n" {
+		t.Errorf("empty markdown = %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// TrailerKey is the commit trailer naming the bead a commit implements.
+const TrailerKey = "Bead"
+
+// NotesRef holds Bead trailers backfilled onto commits that predate
+// trailer injection, so history never has to be rewritten.
+const NotesRef = "refs/notes/beads"
+
+// HasTrailer reports whether message already has a Bead trailer for beadID.
+func HasTrailer(message, beadID string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), TrailerKey) && strings.TrimSpace(value) == beadID {
+			return true
+		}
+	}
+	return false
+}
+
+// WithTrailer returns message with a "Bead: <beadID>" trailer, joining an
+// existing trailer block if the message ends with one. Messages that
+// already name the bead are returned unchanged.
+func WithTrailer(message, beadID string) string {
+	if beadID == "" || HasTrailer(message, beadID) {
+		return message
+	}
+	msg := strings.TrimRight(message, "\n\t ")
+	sep := "\n\n"
+	if paragraphs := strings.Split(msg, "\n\n"); len(paragraphs) > 1 && isTrailerBlock(paragraphs[len(paragraphs)-1]) {
+		sep = "\n"
+	}
+	return msg + sep + TrailerKey + ": " + beadID
+}
+
+// isTrailerBlock reports whether every line of paragraph is "Key: value".
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") || strings.TrimSpace(value) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// BackfillOptions selects the commits for Backfill.
+type BackfillOptions struct {
+	Prefixes []string // bead ID prefixes to recognise
+	From     string   // exclusive start revision; empty means from the root
+	To       string   // inclusive end revision
+	DryRun   bool     // report what would be written without writing notes
+}
+
+// Backfill infers Bead trailers for first-parent commits in From..To that
+// have neither a trailer nor a note, from their MR bead or the branch or
+// bead ID in their message, and records them as notes under NotesRef. It
+// returns the inferred links. b may be nil to infer from git alone.
+func Backfill(g *git.Git, b *beads.Beads, opts BackfillOptions) ([]Link, error) {
+	revRange := opts.To
+	if opts.From != "" {
+		revRange = opts.From + ".." + opts.To
+	}
+	commits, err := g.LogWithNotes(revRange, NotesRef)
+	if err != nil {
+		return nil, fmt.Errorf("listing commits in %s: %w", revRange, err)
+	}
+	var merged map[string]MergedMR
+	if b != nil {
+		if merged, err = MergedMRs(b); err != nil {
+			return nil, fmt.Errorf("listing merged MRs: %w", err)
+		}
+	}
+
+	var inferred []Link
+	for _, l := range NewCorrelator(opts.Prefixes, merged).Correlate(commits) {
+		if len(l.Beads) == 0 || l.Via == ViaTrailer || l.Via == ViaNote {
+			continue
+		}
+		if !opts.DryRun {
+			lines := make([]string, len(l.Beads))
+			for i, id := range l.Beads {
+				lines[i] = TrailerKey + ": " + id
+			}
+			if err := g.AddNote(NotesRef, l.Commit.SHA, strings.Join(lines, "\n")); err != nil {
+				return inferred, fmt.Errorf("noting %s: %w", l.Commit.SHA, err)
+			}
+		}
+		inferred = append(inferred, l)
+	}
+	return inferred, nil
+}
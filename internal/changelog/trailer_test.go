@@ -0,0 +1,93 @@
+package changelog
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestWithTrailer(t *testing.T) {
+	tests := []struct {
+		message, want string
+	}{
+		{"feat: lanes", "feat: lanes\n\nBead: gt-abc"},
+		{"feat: lanes\n\nLonger body.\n", "feat: lanes\n\nLonger body.\n\nBead: gt-abc"},
+		{"feat: lanes\n\nSigned-off-by: A <a@a>", "feat: lanes\n\nSigned-off-by: A <a@a>\nBead: gt-abc"},
+		{"feat: lanes\n\nBead: gt-abc", "feat: lanes\n\nBead: gt-abc"},
+	}
+	for _, tt := range tests {
+		if got := WithTrailer(tt.message, "gt-abc"); got != tt.want {
+			t.Errorf("WithTrailer(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+	if got := WithTrailer("feat: lanes", ""); got != "feat: lanes" {
+		t.Errorf("WithTrailer with no bead = %q", got)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main")
+	for _, msg := range []string{
+		"initial",
+		"Squash merge polecat/nux/gt-a1 into main",
+		"fix: crash\n\nBead: gt-b2",
+		"feat: lanes (gt-c3)",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", ".")
+		run("commit", "-m", msg)
+	}
+	run("config", "user.name", "t")
+	run("config", "user.email", "t@t")
+	g := git.NewGit(dir)
+
+	dry, err := Backfill(g, nil, BackfillOptions{To: "HEAD", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(dry) != 2 || dry[0].Beads[0] != "gt-c3" || dry[1].Beads[0] != "gt-a1" {
+		t.Fatalf("dry run = %+v, want gt-c3 and gt-a1 inferred", dry)
+	}
+	if commits, _ := g.LogWithNotes("HEAD", NotesRef); commits[0].Notes != "" {
+		t.Fatal("dry run wrote a note")
+	}
+
+	if _, err := Backfill(g, nil, BackfillOptions{To: "HEAD"}); err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	commits, err := g.LogWithNotes("HEAD", NotesRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commits[0].Notes != "Bead: gt-c3" || commits[2].Notes != "Bead: gt-a1" || commits[1].Notes != "" {
+		t.Errorf("notes = %q, %q, %q", commits[0].Notes, commits[1].Notes, commits[2].Notes)
+	}
+	links := NewCorrelator(nil, nil).Correlate(commits)
+	if links[2].Via != ViaNote {
+		t.Errorf("backfilled commit linked via %q, want note", links[2].Via)
+	}
+
+	again, err := Backfill(g, nil, BackfillOptions{To: "HEAD"})
+	if err != nil || len(again) != 0 {
+		t.Errorf("second Backfill = %+v, %v; want nothing to do", again, err)
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +26,7 @@ var (
 
 var accountCmd = &cobra.Command{
 	Use:     "account",
+	Aliases: []string{"accounts"},
 	GroupID: GroupConfig,
 	Short:   "Manage Claude Code accounts",
 	RunE:    requireSubcommand,
@@ -35,7 +39,7 @@ Commands:
   gt account list              List registered accounts
   gt account add <handle>      Add a new account
   gt account default <handle>  Set the default account
-  gt account status            Show current account info`,
+  gt account status            Show current account info and usage`,
 }
 
 var accountListCmd = &cobra.Command{
@@ -52,8 +56,9 @@ Examples:
 }
 
 var accountAddCmd = &cobra.Command{
-	Use:   "add <handle>",
-	Short: "Add a new account",
+	Use:         "add <handle>",
+	Annotations: auditAnnotations(""),
+	Short:       "Add a new account",
 	Long: `Add a new Claude Code account.
 
 Creates a config directory at ~/.claude-accounts/<handle> and registers
@@ -69,8 +74,9 @@ Examples:
 }
 
 var accountDefaultCmd = &cobra.Command{
-	Use:   "default <handle>",
-	Short: "Set the default account",
+	Use:         "default <handle>",
+	Annotations: auditAnnotations(""),
+	Short:       "Set the default account",
 	Long: `Set the default Claude Code account.
 
 The default account is used when no --account flag or GT_ACCOUNT env var
@@ -262,22 +268,35 @@ func runAccountDefault(cmd *cobra.Command, args []string) error {
 
 var accountStatusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "Show current account info",
+	Short: "Show current account info and usage",
 	Long: `Show which Claude Code account would be used for new sessions.
 
 Displays the currently resolved account based on:
 1. GT_ACCOUNT environment variable (highest priority)
 2. Default account from config
 
+Also shows recent usage for every registered account: quota status,
+when it was last assigned, which sessions are running on it, and any
+rigs it is dedicated to by the accounts policy.
+
+Assignment policy (mayor/accounts.json "policy" block):
+  mode          "default" (always the default account) or "round-robin"
+                (least-recently-used available account per polecat)
+  rig_accounts  dedicate an account to a rig, e.g. {"gastown": "work"}
+  failover      skip rate-limited accounts when assigning, and let the
+                daemon rotate rate-limited sessions automatically
+
 Examples:
-  gt account status           # Show current account
+  gt account status           # Show current account and usage
+  gt accounts status --json   # Usage as JSON
   GT_ACCOUNT=work gt account status  # Show with env override`,
 	RunE: runAccountStatus,
 }
 
 var accountSwitchCmd = &cobra.Command{
-	Use:   "switch <handle>",
-	Short: "Switch to a different account",
+	Use:         "switch <handle>",
+	Annotations: auditAnnotations(""),
+	Short:       "Switch to a different account",
 	Long: `Switch the active Claude Code account.
 
 This command:
@@ -329,6 +348,21 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("account '%s' not found", handle)
 	}
 
+	usage, err := loadAccountUsage(townRoot, cfg)
+	if err != nil {
+		return err
+	}
+
+	if accountJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Current string                `json:"current"`
+			Policy  *config.AccountPolicy `json:"policy,omitempty"`
+			Usage   []quota.AccountUsage  `json:"usage"`
+		}{Current: handle, Policy: cfg.Policy, Usage: usage})
+	}
+
 	fmt.Printf("%s\n\n", style.Bold.Render("Current Account"))
 	fmt.Printf("Handle:     %s\n", style.Bold.Render(handle))
 	if acct.Email != "" {
@@ -345,9 +379,76 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n%s\n", style.Dim.Render("(default account)"))
 	}
 
+	fmt.Println()
+	printAccountUsageText(usage, cfg.Policy)
 	return nil
 }
 
+// loadAccountUsage gathers per-account usage from quota state and a scan of
+// running sessions. A failed scan (e.g., no tmux server) yields no sessions.
+func loadAccountUsage(townRoot string, cfg *config.AccountsConfig) ([]quota.AccountUsage, error) {
+	mgr := quota.NewManager(townRoot)
+	state, err := mgr.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading quota state: %w", err)
+	}
+	mgr.EnsureAccountsTracked(state, cfg.Accounts)
+
+	var results []quota.ScanResult
+	if scanner, err := quota.NewScanner(tmux.NewTmux(), nil, cfg); err == nil {
+		results, _ = scanner.ScanAll()
+	}
+	return quota.Usage(cfg, state, results), nil
+}
+
+func printAccountUsageText(usage []quota.AccountUsage, policy *config.AccountPolicy) {
+	mode := config.AccountPolicyDefault
+	failover := false
+	if policy != nil {
+		if policy.Mode != "" {
+			mode = policy.Mode
+		}
+		failover = policy.Failover
+	}
+	fmt.Printf("%s  %s\n\n", style.Bold.Render("Usage"),
+		style.Dim.Render(fmt.Sprintf("(policy: %s, failover: %v)", mode, failover)))
+
+	for _, u := range usage {
+		marker := "  "
+		if u.IsDefault {
+			marker = "* "
+		}
+
+		var status string
+		switch config.AccountQuotaStatus(u.Status) {
+		case config.QuotaStatusLimited:
+			status = style.Error.Render(u.Status)
+			if u.ResetsAt != "" {
+				status += style.Dim.Render(" (resets " + u.ResetsAt + ")")
+			}
+		case config.QuotaStatusCooldown:
+			status = style.Warning.Render(u.Status)
+		default:
+			status = style.Success.Render(u.Status)
+		}
+
+		lastUsed := formatTimeAgo(u.LastUsed)
+		if lastUsed == "" {
+			lastUsed = "never"
+		}
+
+		fmt.Printf("%s%-12s %s  %s  %s\n", marker, u.Handle, status,
+			style.Dim.Render("last used "+lastUsed),
+			style.Dim.Render(fmt.Sprintf("%d session(s)", len(u.ActiveSessions))))
+		if len(u.DedicatedRigs) > 0 {
+			fmt.Printf("    %s\n", style.Dim.Render("dedicated to: "+strings.Join(u.DedicatedRigs, ", ")))
+		}
+		for _, sess := range u.ActiveSessions {
+			fmt.Printf("    %s\n", style.Dim.Render(sess))
+		}
+	}
+}
+
 func runAccountSwitch(cmd *cobra.Command, args []string) error {
 	targetHandle := args[0]
 
@@ -506,6 +607,7 @@ func ensureSharedCommandsSymlink(configDir string) error {
 func init() {
 	// Add flags
 	accountListCmd.Flags().BoolVar(&accountJSON, "json", false, "Output as JSON")
+	accountStatusCmd.Flags().BoolVar(&accountJSON, "json", false, "Output as JSON")
 
 	accountAddCmd.Flags().StringVar(&accountEmail, "email", "", "Account email address")
 	accountAddCmd.Flags().StringVar(&accountDescription, "desc", "", "Account description")
@@ -76,7 +76,12 @@ The menu appears as a tmux popup for quick session switching.`,
 var agentsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List agent sessions (no popup)",
-	Long:  `List all agent sessions to stdout without the popup menu.`,
+	Long: `List all agent sessions to stdout without the popup menu.
+
+With --presets, list the built-in agent presets (claude, codex, gemini, ...)
+and whether each binary is installed on this machine, with its resolved path
+and version. Results come from a machine-local cache that agent validation
+also uses; --refresh re-probes every binary.`,
 	RunE:  runAgentsList,
 }
 
@@ -317,6 +322,10 @@ func runAgents(cmd *cobra.Command, args []string) error {
 }
 
 func runAgentsList(cmd *cobra.Command, args []string) error {
+	if agentsListPresets {
+		return runAgentsListPresets()
+	}
+
 	agents, err := getAgentSessions(agentsAllFlag)
 	if err != nil {
 		return fmt.Errorf("listing sessions: %w", err)
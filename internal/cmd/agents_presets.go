@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	agentsListPresets bool
+	agentsListRefresh bool
+	agentsListJSON    bool
+)
+
+func init() {
+	agentsListCmd.Flags().BoolVar(&agentsListPresets, "presets", false, "List agent presets and whether their binaries are installed")
+	agentsListCmd.Flags().BoolVar(&agentsListRefresh, "refresh", false, "With --presets, re-probe every binary instead of using the cache")
+	agentsListCmd.Flags().BoolVar(&agentsListJSON, "json", false, "With --presets, output as JSON")
+}
+
+// AgentPresetStatus describes whether a preset is usable on this machine.
+type AgentPresetStatus struct {
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	Available bool   `json:"available"`
+	Path      string `json:"path,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// agentPresetStatuses resolves every built-in preset through the capability
+// cache, sorted by name.
+func agentPresetStatuses(refresh bool) []AgentPresetStatus {
+	names := config.ListAgentPresets()
+	sort.Strings(names)
+
+	commands := make([]string, 0, len(names))
+	byName := make(map[string]string, len(names))
+	for _, name := range names {
+		rc := config.RuntimeConfigFromPreset(config.AgentPreset(name))
+		if rc == nil || rc.Command == "" {
+			continue
+		}
+		byName[name] = rc.Command
+		commands = append(commands, rc.Command)
+	}
+	caps := config.AgentCapabilities(commands, refresh)
+
+	statuses := make([]AgentPresetStatus, 0, len(byName))
+	for _, name := range names {
+		command, ok := byName[name]
+		if !ok {
+			continue
+		}
+		c := caps[command]
+		statuses = append(statuses, AgentPresetStatus{
+			Name:      name,
+			Command:   command,
+			Available: c.Available(),
+			Path:      c.Path,
+			Version:   c.Version,
+			Error:     c.Error,
+		})
+	}
+	return statuses
+}
+
+func runAgentsListPresets() error {
+	statuses := agentPresetStatuses(agentsListRefresh)
+
+	if agentsListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	available := 0
+	for _, s := range statuses {
+		if !s.Available {
+			fmt.Printf("  %s %-10s %s\n", style.Dim.Render("✗"), s.Name, style.Dim.Render(s.Command+": "+s.Error))
+			continue
+		}
+		available++
+		version := s.Version
+		if version == "" {
+			version = "version unknown"
+		}
+		fmt.Printf("  %s %-10s %s %s\n", style.Success.Render("✓"), s.Name, s.Path, style.Dim.Render("("+version+")"))
+	}
+	fmt.Printf("\n%d of %d presets usable on this machine", available, len(statuses))
+	if !agentsListRefresh {
+		fmt.Printf(" %s", style.Dim.Render("(cached; --refresh to re-probe)"))
+	}
+	fmt.Println()
+	return nil
+}
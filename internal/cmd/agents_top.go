@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"golang.org/x/term"
+)
+
+var agentsTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show CPU and memory per agent session",
+	Long: `Show live CPU and memory use for every agent session in the town.
+
+Each tmux session's pane process and all its descendants are summed, so an
+agent's row includes the tools and test runs it has spawned. PROCS is the
+process count and TOP is the busiest process in the tree.
+
+Filter with --rig and --role (mayor, deacon, witness, refinery, crew,
+polecat), sort with --sort (cpu, mem, name), and refresh continuously with
+--watch. --kill stops one agent's session and processes after confirmation;
+name it by session (gt-gastown-toast) or address (gastown/toast,
+gastown/crew/max, gastown/witness, mayor).
+
+Examples:
+  gt agents top
+  gt agents top --rig gastown --sort mem
+  gt agents top --watch -n 5
+  gt agents top --kill gastown/toast`,
+	Args: cobra.NoArgs,
+	RunE: runAgentsTop,
+}
+
+var (
+	agentsTopSort     string
+	agentsTopRig      string
+	agentsTopRole     string
+	agentsTopJSON     bool
+	agentsTopWatch    bool
+	agentsTopInterval int
+	agentsTopKill     string
+)
+
+func init() {
+	agentsTopCmd.Flags().StringVar(&agentsTopSort, "sort", "cpu", "Sort by cpu, mem, or name")
+	agentsTopCmd.Flags().StringVar(&agentsTopRig, "rig", "", "Only show agents in this rig")
+	agentsTopCmd.Flags().StringVar(&agentsTopRole, "role", "", "Only show this role (mayor, deacon, witness, refinery, crew, polecat)")
+	agentsTopCmd.Flags().BoolVar(&agentsTopJSON, "json", false, "Output as JSON")
+	agentsTopCmd.Flags().BoolVarP(&agentsTopWatch, "watch", "w", false, "Refresh continuously")
+	agentsTopCmd.Flags().IntVarP(&agentsTopInterval, "interval", "n", 2, "Refresh interval in seconds")
+	agentsTopCmd.Flags().StringVar(&agentsTopKill, "kill", "", "Kill this agent's session and processes")
+
+	agentsCmd.AddCommand(agentsTopCmd)
+}
+
+// AgentUsage is the resource use of one agent session's process tree.
+type AgentUsage struct {
+	Agent   string  `json:"agent"`
+	Session string  `json:"session"`
+	Role    string  `json:"role"`
+	Rig     string  `json:"rig,omitempty"`
+	PID     int     `json:"pid"`
+	Procs   int     `json:"procs"`
+	CPU     float64 `json:"cpu_percent"`
+	RSS     int64   `json:"rss_bytes"`
+	Top     string  `json:"top_process,omitempty"`
+}
+
+// psProc is one line of `ps -axo pid=,ppid=,pcpu=,rss=,comm=`.
+type psProc struct {
+	pid, ppid int
+	cpu       float64
+	rss       int64 // bytes
+	comm      string
+}
+
+// parsePSUsage parses `ps -axo pid=,ppid=,pcpu=,rss=,comm=` output, where
+// rss is in kilobytes.
+func parsePSUsage(out string) map[int]psProc {
+	procs := make(map[int]psProc)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		cpu, err3 := strconv.ParseFloat(fields[2], 64)
+		rss, err4 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		comm := strings.Join(fields[4:], " ")
+		if i := strings.LastIndex(comm, "/"); i >= 0 {
+			comm = comm[i+1:]
+		}
+		procs[pid] = psProc{pid: pid, ppid: ppid, cpu: cpu, rss: rss * 1024, comm: comm}
+	}
+	return procs
+}
+
+// sumProcessTree adds root and its descendants into u.
+func sumProcessTree(procs map[int]psProc, root int, u *AgentUsage) {
+	children := make(map[int][]int)
+	for _, p := range procs {
+		children[p.ppid] = append(children[p.ppid], p.pid)
+	}
+	var topCPU float64 = -1
+	seen := map[int]bool{}
+	queue := []int{root}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		p, ok := procs[pid]
+		if !ok {
+			continue
+		}
+		u.Procs++
+		u.CPU += p.cpu
+		u.RSS += p.rss
+		if p.cpu > topCPU {
+			topCPU = p.cpu
+			u.Top = p.comm
+		}
+		queue = append(queue, children[pid]...)
+	}
+}
+
+// agentAddress returns the short address gt agents top shows and --kill
+// accepts, e.g. "gastown/crew/max".
+func agentAddress(a *AgentSession) string {
+	switch a.Type {
+	case AgentMayor:
+		return "mayor"
+	case AgentDeacon:
+		return "deacon"
+	case AgentWitness:
+		return a.Rig + "/witness"
+	case AgentRefinery:
+		return a.Rig + "/refinery"
+	case AgentCrew:
+		return a.Rig + "/crew/" + a.AgentName
+	case AgentPolecat:
+		return a.Rig + "/" + a.AgentName
+	}
+	return a.Name
+}
+
+// agentRoleName is the --role value matching a's type.
+func agentRoleName(t AgentType) string {
+	return [...]string{"mayor", "deacon", "witness", "refinery", "crew", "polecat"}[t]
+}
+
+// collectAgentUsage measures every agent session passing the filters.
+func collectAgentUsage(rigFilter, roleFilter string) ([]AgentUsage, error) {
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	out, err := util.Output(exec.Command("ps", "-axo", "pid=,ppid=,pcpu=,rss=,comm="), util.ProbeTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("reading process table: %w", err)
+	}
+	procs := parsePSUsage(string(out))
+
+	t, err := requireTmux("gt agents top")
+	if err != nil {
+		return nil, err
+	}
+	var usage []AgentUsage
+	for _, a := range agents {
+		if rigFilter != "" && a.Rig != rigFilter {
+			continue
+		}
+		if roleFilter != "" && agentRoleName(a.Type) != roleFilter {
+			continue
+		}
+		u := AgentUsage{Agent: agentAddress(a), Session: a.Name, Role: agentRoleName(a.Type), Rig: a.Rig}
+		if pidStr, err := t.GetPanePID(a.Name); err == nil {
+			if pid, err := strconv.Atoi(pidStr); err == nil {
+				u.PID = pid
+				sumProcessTree(procs, pid, &u)
+			}
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// sortAgentUsage orders usage by key, busiest first for cpu and mem.
+func sortAgentUsage(usage []AgentUsage, key string) {
+	sort.SliceStable(usage, func(i, j int) bool {
+		a, b := usage[i], usage[j]
+		switch key {
+		case "mem":
+			if a.RSS != b.RSS {
+				return a.RSS > b.RSS
+			}
+		case "cpu":
+			if a.CPU != b.CPU {
+				return a.CPU > b.CPU
+			}
+		}
+		return a.Agent < b.Agent
+	})
+}
+
+func runAgentsTop(cmd *cobra.Command, args []string) error {
+	switch agentsTopSort {
+	case "cpu", "mem", "name":
+	default:
+		return fmt.Errorf("invalid --sort %q: must be cpu, mem, or name", agentsTopSort)
+	}
+	if agentsTopRole != "" {
+		valid := false
+		for t := AgentMayor; t <= AgentPolecat; t++ {
+			valid = valid || agentRoleName(t) == agentsTopRole
+		}
+		if !valid {
+			return fmt.Errorf("invalid --role %q: must be mayor, deacon, witness, refinery, crew, or polecat", agentsTopRole)
+		}
+	}
+	if agentsTopKill != "" {
+		return killAgentSession(agentsTopKill)
+	}
+	if agentsTopWatch {
+		if agentsTopJSON {
+			return fmt.Errorf("--json and --watch cannot be used together")
+		}
+		return runAgentsTopWatch()
+	}
+
+	usage, err := collectAgentUsage(agentsTopRig, agentsTopRole)
+	if err != nil {
+		return err
+	}
+	sortAgentUsage(usage, agentsTopSort)
+	if agentsTopJSON {
+		if usage == nil {
+			usage = []AgentUsage{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usage)
+	}
+	fmt.Print(renderAgentUsage(usage))
+	return nil
+}
+
+func runAgentsTopWatch() error {
+	if agentsTopInterval <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", agentsTopInterval)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(time.Duration(agentsTopInterval) * time.Second)
+	defer ticker.Stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	for {
+		var buf bytes.Buffer
+		if isTTY {
+			buf.WriteString("\033[H\033[2J") // ANSI: cursor home + clear screen
+		}
+		header := fmt.Sprintf("[%s] gt agents top (every %ds, Ctrl+C to stop)", time.Now().Format("15:04:05"), agentsTopInterval)
+		if isTTY {
+			header = style.Dim.Render(header)
+		}
+		fmt.Fprintf(&buf, "%s\n\n", header)
+
+		usage, err := collectAgentUsage(agentsTopRig, agentsTopRole)
+		if err != nil {
+			fmt.Fprintf(&buf, "Error: %v\n", err)
+		} else {
+			sortAgentUsage(usage, agentsTopSort)
+			buf.WriteString(renderAgentUsage(usage))
+		}
+		_, _ = os.Stdout.Write(buf.Bytes())
+
+		select {
+		case <-sigChan:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderAgentUsage formats usage as a table with a total line.
+func renderAgentUsage(usage []AgentUsage) string {
+	if len(usage) == 0 {
+		return "No agent sessions running.\n"
+	}
+	tbl := style.NewTable(
+		style.Column{Name: "AGENT", Width: 28},
+		style.Column{Name: "PID", Width: 8, Align: style.AlignRight},
+		style.Column{Name: "PROCS", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "CPU%", Width: 7, Align: style.AlignRight},
+		style.Column{Name: "MEM", Width: 10, Align: style.AlignRight},
+		style.Column{Name: "TOP", Width: 16},
+	)
+	var cpu float64
+	var rss int64
+	for _, u := range usage {
+		pid := "-"
+		if u.PID > 0 {
+			pid = strconv.Itoa(u.PID)
+		}
+		tbl.AddRow(u.Agent, pid, strconv.Itoa(u.Procs), fmt.Sprintf("%.1f", u.CPU), formatBytes(u.RSS), u.Top)
+		cpu += u.CPU
+		rss += u.RSS
+	}
+	return tbl.Render() + fmt.Sprintf("\n  %d agents, %.1f%% CPU, %s\n", len(usage), cpu, formatBytes(rss))
+}
+
+// findAgentSession resolves a session name or agent address to a session.
+func findAgentSession(agents []*AgentSession, target string) *AgentSession {
+	for _, a := range agents {
+		if a.Name == target || agentAddress(a) == target {
+			return a
+		}
+	}
+	// Polecats are also addressed as rig/polecats/name.
+	if rig, name, ok := strings.Cut(target, "/polecats/"); ok {
+		for _, a := range agents {
+			if a.Type == AgentPolecat && a.Rig == rig && a.AgentName == name {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
+// killAgentSession stops target's tmux session and its process tree.
+func killAgentSession(target string) error {
+	t, err := requireTmux("gt agents top --kill")
+	if err != nil {
+		return err
+	}
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	a := findAgentSession(agents, target)
+	if a == nil {
+		return fmt.Errorf("no running agent session %q (see 'gt agents top')", target)
+	}
+
+	ok, err := confirm(confirmPrompt{
+		ID:       PromptAgentsKill,
+		Question: fmt.Sprintf("Kill %s (session %s) and all its processes?", agentAddress(a), a.Name),
+		Safe:     true,
+		Hint:     "pass --yes to kill without asking",
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted.")
+		return nil
+	}
+	if err := t.KillSessionWithProcesses(a.Name); err != nil {
+		return fmt.Errorf("killing %s: %w", a.Name, err)
+	}
+	fmt.Printf("%s Killed %s\n", style.Success.Render("✓"), agentAddress(a))
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/multiplexer"
+)
+
+func TestSumProcessTree(t *testing.T) {
+	procs := parsePSUsage(`
+    1     0   0.0   1000 /sbin/init
+  100     1   0.5   2048 bash
+  101   100  80.0 409600 /usr/local/bin/claude
+  102   101  12.5  10240 go test
+  200     1  99.0   4096 unrelated
+garbage line
+`)
+	if len(procs) != 5 {
+		t.Fatalf("parsed %d processes, want 5", len(procs))
+	}
+
+	var u AgentUsage
+	sumProcessTree(procs, 100, &u)
+	if u.Procs != 3 {
+		t.Errorf("Procs = %d, want 3", u.Procs)
+	}
+	if u.CPU != 93.0 {
+		t.Errorf("CPU = %.1f, want 93.0", u.CPU)
+	}
+	if want := int64(2048+409600+10240) * 1024; u.RSS != want {
+		t.Errorf("RSS = %d, want %d", u.RSS, want)
+	}
+	if u.Top != "claude" {
+		t.Errorf("Top = %q, want claude", u.Top)
+	}
+
+	var gone AgentUsage
+	sumProcessTree(procs, 999, &gone)
+	if gone.Procs != 0 || gone.Top != "" {
+		t.Errorf("missing root = %+v, want empty", gone)
+	}
+}
+
+func TestSortAgentUsage(t *testing.T) {
+	usage := []AgentUsage{
+		{Agent: "b", CPU: 1, RSS: 300},
+		{Agent: "a", CPU: 50, RSS: 100},
+		{Agent: "c", CPU: 1, RSS: 200},
+	}
+	order := func() string {
+		s := ""
+		for _, u := range usage {
+			s += u.Agent
+		}
+		return s
+	}
+
+	sortAgentUsage(usage, "cpu")
+	if got := order(); got != "abc" {
+		t.Errorf("cpu order = %s, want abc", got)
+	}
+	sortAgentUsage(usage, "mem")
+	if got := order(); got != "bca" {
+		t.Errorf("mem order = %s, want bca", got)
+	}
+	sortAgentUsage(usage, "name")
+	if got := order(); got != "abc" {
+		t.Errorf("name order = %s, want abc", got)
+	}
+}
+
+func TestFindAgentSession(t *testing.T) {
+	agents := []*AgentSession{
+		{Name: "hq-mayor", Type: AgentMayor},
+		{Name: "gt-gastown-witness", Type: AgentWitness, Rig: "gastown"},
+		{Name: "gt-gastown-crew-max", Type: AgentCrew, Rig: "gastown", AgentName: "max"},
+		{Name: "gt-gastown-toast", Type: AgentPolecat, Rig: "gastown", AgentName: "toast"},
+	}
+	tests := map[string]string{
+		"mayor":                  "hq-mayor",
+		"gastown/witness":        "gt-gastown-witness",
+		"gastown/crew/max":       "gt-gastown-crew-max",
+		"gastown/toast":          "gt-gastown-toast",
+		"gastown/polecats/toast": "gt-gastown-toast",
+		"gt-gastown-toast":       "gt-gastown-toast",
+		"gastown/refinery":       "",
+	}
+	for target, want := range tests {
+		got := ""
+		if a := findAgentSession(agents, target); a != nil {
+			got = a.Name
+		}
+		if got != want {
+			t.Errorf("findAgentSession(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestRequireTmux_OtherMultiplexer(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","version":1,"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Multiplexer = multiplexer.NameHeadless
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	_, err := requireTmux("gt agents top")
+	if err == nil || !strings.Contains(err.Error(), "gt agents top requires tmux") || !strings.Contains(err.Error(), "headless") {
+		t.Fatalf("requireTmux error = %v, want it to name the command and the headless multiplexer", err)
+	}
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// auditAnnotation marks a mutating command, whose runs are recorded in the
+// audit log (mayor/audit.jsonl). Set it next to the command's definition
+// with auditAnnotations. Read-only commands stay unmarked to keep the log
+// meaningful.
+const auditAnnotation = "gt.audit"
+
+// auditAnnotations returns the Annotations of a mutating command. whenFlag
+// names the flag that makes a run mutating (e.g. "prune" for gt dolt
+// branches --prune); leave it empty if every run mutates.
+func auditAnnotations(whenFlag string) map[string]string {
+	return map[string]string{auditAnnotation: whenFlag}
+}
+
+// isAudited reports whether this run of cmd is recorded in the audit log.
+func isAudited(cmd *cobra.Command) bool {
+	whenFlag, ok := cmd.Annotations[auditAnnotation]
+	if !ok {
+		return false
+	}
+	return whenFlag == "" || cmd.Flags().Changed(whenFlag)
+}
+
+// Audit tail flags
+var (
+	auditTailLimit int
+	auditTailJSON  bool
+)
+
+var auditTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show recent mutating commands from the audit log",
+	Long: `Show the most recent entries from the town audit log.
+
+Every mutating command (rig add/remove, shutdown, dolt migrate, config
+changes, ...) is appended to mayor/audit.jsonl with the actor, arguments,
+timestamp, and outcome.
+
+Retention is configured in settings/config.json:
+  "audit": {"retention_days": 90}   # default 90; negative keeps forever
+  "audit": {"disabled": true}       # turn off audit logging
+
+Examples:
+  gt audit tail              # Last 20 entries
+  gt audit tail -n 100       # Last 100 entries
+  gt audit tail --json       # Machine-readable output`,
+	Args: cobra.NoArgs,
+	RunE: runAuditTail,
+}
+
+func init() {
+	auditTailCmd.Flags().IntVarP(&auditTailLimit, "limit", "n", 20, "Number of entries to show")
+	auditTailCmd.Flags().BoolVar(&auditTailJSON, "json", false, "Output as JSON")
+
+	auditCmd.AddCommand(auditTailCmd)
+}
+
+// recordAudit appends an audit entry for cmd if it is a mutating command.
+// Best-effort: failures are reported on stderr but never change the exit code.
+func recordAudit(cmd *cobra.Command, start time.Time, runErr error) {
+	if cmd == nil {
+		return
+	}
+	if !isAudited(cmd) {
+		return
+	}
+	cmdPath := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+
+	var auditCfg *config.AuditConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		auditCfg = settings.Audit
+	}
+	if auditCfg != nil && auditCfg.Disabled {
+		return
+	}
+
+	entry := auditlog.Entry{
+		Timestamp:  start.UTC(),
+		Actor:      detectSender(),
+		Command:    cmdPath,
+		Args:       os.Args[1:],
+		Outcome:    auditlog.OutcomeOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		entry.Cwd = cwd
+	}
+	if runErr != nil {
+		entry.Outcome = auditlog.OutcomeError
+		entry.Error = runErr.Error()
+	}
+
+	if err := auditlog.Append(townRoot, entry, auditCfg.Retention()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write audit log: %v\n", err)
+	}
+}
+
+func runAuditTail(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	entries, err := auditlog.Read(townRoot)
+	if err != nil {
+		return err
+	}
+	if auditTailLimit > 0 && len(entries) > auditTailLimit {
+		entries = entries[len(entries)-auditTailLimit:]
+	}
+
+	if auditTailJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if entries == nil {
+			entries = []auditlog.Entry{}
+		}
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No audit entries found.")
+		return nil
+	}
+
+	for _, e := range entries {
+		outcome := style.Success.Render("✓")
+		if e.Outcome != auditlog.OutcomeOK {
+			outcome = style.Error.Render("✗")
+		}
+		fmt.Printf("%s %s %s %s\n",
+			style.Dim.Render(e.Timestamp.Local().Format("2006-01-02 15:04:05")),
+			outcome,
+			style.Bold.Render(e.Actor),
+			strings.Join(e.Args, " "))
+		if e.Error != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(e.Error))
+		}
+	}
+	return nil
+}
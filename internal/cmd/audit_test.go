@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/spf13/cobra"
 )
 
 func TestParseDuration(t *testing.T) {
@@ -148,3 +151,54 @@ func TestFormatType(t *testing.T) {
 		}
 	}
 }
+
+func TestMutatingCommandsAudited(t *testing.T) {
+	for _, path := range []string{
+		"rig add", "rig remove", "rig gc", "rig config edit",
+		"town backup", "town restore",
+		"dolt transfer", "dolt users create", "dolt users remove", "dolt upgrade-schema",
+		"config set", "config import",
+		"labels rename", "bead import",
+		"polecat nuke", "polecat rename",
+		"mq pause", "mq resume",
+	} {
+		cmd, _, err := rootCmd.Find(strings.Fields(path))
+		if err != nil {
+			t.Errorf("command %q not found: %v", path, err)
+			continue
+		}
+		if _, ok := cmd.Annotations[auditAnnotation]; !ok {
+			t.Errorf("mutating command %q is not marked for the audit log", path)
+		}
+	}
+}
+
+func TestAuditAnnotationFlagsExist(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if whenFlag := cmd.Annotations[auditAnnotation]; whenFlag != "" && cmd.Flags().Lookup(whenFlag) == nil {
+			t.Errorf("%s is audited when --%s is set, but has no such flag", cmd.CommandPath(), whenFlag)
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestIsAudited_WhenFlag(t *testing.T) {
+	cmd := &cobra.Command{Use: "viewer-dsn", Annotations: auditAnnotations("rotate")}
+	cmd.Flags().Bool("rotate", false, "")
+	if isAudited(cmd) {
+		t.Error("run without --rotate should not be audited")
+	}
+	if err := cmd.Flags().Set("rotate", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !isAudited(cmd) {
+		t.Error("run with --rotate should be audited")
+	}
+	if isAudited(&cobra.Command{Use: "status"}) {
+		t.Error("unmarked command should not be audited")
+	}
+}
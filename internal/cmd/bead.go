@@ -14,7 +14,7 @@ import (
 
 var beadCmd = &cobra.Command{
 	Use:     "bead",
-	Aliases: []string{"bd"},
+	Aliases: []string{"bd", "beads"},
 	GroupID: GroupWork,
 	Short:   "Bead management utilities",
 	Long: `Utilities for managing beads across repositories.
@@ -24,6 +24,7 @@ moving beads between repos and viewing beads by ID with automatic
 prefix-based routing.
 
 Subcommands:
+  import  Bulk-import issues from a JSONL file into a rig
   move    Move a bead from one repository to another
   show    Show details of a bead (routes by prefix)
   read    Alias for show`,
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	beadImportRig        string
+	beadImportBatchSize  int
+	beadImportBatchBytes int
+)
+
+var beadImportCmd = &cobra.Command{
+	Use:         "import <file.jsonl>",
+	Annotations: auditAnnotations(""),
+	Short:       "Bulk-import issues from a JSONL file into a rig",
+	Long: `Import issues from a JSONL file (bd export format) into a rig's beads.
+
+Issues are imported through bd import in batches, one connection and one
+transaction per batch, instead of a bd create per issue. Before each batch
+the import waits until the Dolt server is below 80% of max_connections,
+so a large import backs off rather than starving running agents.
+
+A batch that fails is split in half and retried; later batches keep the
+smaller size. A single issue that still fails stops the import with its
+line number. Issues that already exist are updated, so an interrupted
+import can be rerun.
+
+Examples:
+  gt bead import issues.jsonl --rig gastown
+  gt beads import big.jsonl --rig gastown --batch-size 200`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadImport,
+}
+
+func init() {
+	beadImportCmd.Flags().StringVar(&beadImportRig, "rig", "", "Rig to import into (required)")
+	beadImportCmd.Flags().IntVar(&beadImportBatchSize, "batch-size", beads.DefaultImportBatchSize, "Most issues per transaction")
+	beadImportCmd.Flags().IntVar(&beadImportBatchBytes, "batch-bytes", beads.DefaultImportBatchBytes, "Most JSONL bytes per transaction")
+	_ = beadImportCmd.MarkFlagRequired("rig")
+	beadCmd.AddCommand(beadImportCmd)
+}
+
+func runBeadImport(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(beadImportRig)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	opts := beads.ImportOptions{
+		BatchSize:  beadImportBatchSize,
+		BatchBytes: beadImportBatchBytes,
+		Progress: func(p beads.ImportProgress) {
+			pct := 100.0
+			if info.Size() > 0 {
+				pct = float64(p.BytesRead) * 100 / float64(info.Size())
+			}
+			status := ""
+			if p.Waiting {
+				status = " — waiting for Dolt connection capacity"
+			}
+			fmt.Printf("\r  %d issues, %d batches (%.0f%%)%s\033[K", p.Issues, p.Batches, pct, status)
+		},
+	}
+	beadsDir := beads.ResolveBeadsDir(r.BeadsPath())
+	if meta, err := beads.ReadBackendMetadata(beadsDir); err == nil && meta.IsDoltServerMode() {
+		opts.HasCapacity = func() (bool, error) {
+			ok, _, err := doltserver.HasConnectionCapacity(townRoot)
+			return ok, err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("%s Importing %s into %s (%s)\n", style.Bold.Render("→"), args[0], r.Name, formatBytes(info.Size()))
+	res, err := beads.NewWithBeadsDir(r.BeadsPath(), beadsDir).ImportIssues(ctx, f, opts)
+	fmt.Println()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("interrupted")
+		}
+		return fmt.Errorf("import stopped after %d issues: %w", res.Issues, err)
+	}
+
+	fmt.Printf("%s Imported %d issues in %d batches\n", style.Success.Render("✓"), res.Issues, res.Batches)
+	if res.Splits > 0 {
+		fmt.Printf("  %s %d failed batches were split and retried\n", style.Dim.Render("·"), res.Splits)
+	}
+	if res.Waited >= time.Second {
+		fmt.Printf("  %s waited %s for connection capacity\n", style.Dim.Render("·"), res.Waited.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/bench"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	benchRuns      int
+	benchSpawn     string
+	benchSpawnRuns int
+	benchSave      bool
+	benchThreshold float64
+	benchJSON      bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:     "bench",
+	GroupID: GroupDiag,
+	Short:   "Benchmark key town operations against a saved baseline",
+	Long: `Measure how long key operations take in the current town and compare
+them with a saved baseline, so performance changes can be checked with a
+reproducible harness.
+
+Benchmarks:
+  dolt.query       One count query on the town beads database (latency distribution)
+  rig.status       Collecting agent status for every rig
+  metadata.ensure  Ensuring metadata.json for every rig database (rigs/s)
+  polecat.spawn    Checks, name allocation, and worktree creation for a new
+                   polecat, without starting its session (only with --spawn)
+
+Each benchmark runs --runs times (polecat.spawn runs --spawn-runs times and
+removes each polecat again). The report shows median, p90, p99, and max
+latency, and throughput.
+
+--save stores the results as the town's baseline in
+.runtime/bench/baseline.json. Later runs compare each benchmark's median
+with the baseline and fail if any is more than --threshold slower, so
+gt bench can gate performance-sensitive changes.
+
+Examples:
+  gt bench --save                       # Record a baseline
+  gt bench                              # Compare with it
+  gt bench --runs 100 --threshold 0.1
+  gt bench --spawn gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVar(&benchRuns, "runs", 20, "Runs per benchmark")
+	benchCmd.Flags().StringVar(&benchSpawn, "spawn", "", "Also benchmark polecat spawn in this rig (creates and removes polecats)")
+	benchCmd.Flags().IntVar(&benchSpawnRuns, "spawn-runs", 3, "Runs of the polecat spawn benchmark")
+	benchCmd.Flags().BoolVar(&benchSave, "save", false, "Save the results as the new baseline")
+	benchCmd.Flags().Float64Var(&benchThreshold, "threshold", bench.DefaultThreshold, "Median slowdown that counts as a regression (0.2 = 20%)")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchOutput is the --json shape of gt bench.
+type benchOutput struct {
+	Results     []bench.Result     `json:"results"`
+	Baseline    *time.Time         `json:"baseline_recorded_at,omitempty"`
+	Comparisons []bench.Comparison `json:"comparisons,omitempty"`
+	Regressions int                `json:"regressions"`
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if benchRuns <= 0 || benchSpawnRuns <= 0 {
+		return fmt.Errorf("--runs and --spawn-runs must be positive")
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+
+	benchmarks, err := bench.TownBenchmarks(townRoot, bench.Options{Spawn: benchSpawn != "", SpawnRig: benchSpawn})
+	if err != nil {
+		return err
+	}
+	baselinePath := bench.BaselinePath(townRoot)
+	baseline, err := bench.LoadBaseline(baselinePath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var results []bench.Result
+	for _, b := range benchmarks {
+		runs := benchRuns
+		if b.Name == "polecat.spawn" {
+			runs = benchSpawnRuns
+		}
+		if !benchJSON {
+			fmt.Fprintf(os.Stderr, "%s %s (%d runs)...\n", style.Dim.Render("Running"), b.Name, runs)
+		}
+		results = append(results, bench.Measure(ctx, b, runs))
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("interrupted")
+	}
+
+	comparisons := bench.Compare(baseline, results, benchThreshold)
+	regressions := 0
+	for _, c := range comparisons {
+		if c.Regressed {
+			regressions++
+		}
+	}
+
+	if benchJSON {
+		out := benchOutput{Results: results, Comparisons: comparisons, Regressions: regressions}
+		if baseline != nil {
+			out.Baseline = &baseline.RecordedAt
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		printBench(results, baseline, comparisons)
+	}
+
+	if benchSave {
+		if err := bench.SaveBaseline(baselinePath, &bench.Baseline{RecordedAt: time.Now().UTC(), Version: Version, Results: results}); err != nil {
+			return err
+		}
+		if !benchJSON {
+			fmt.Printf("\n%s Saved baseline to %s\n", style.Success.Render("✓"), baselinePath)
+		}
+		return nil
+	}
+	if regressions > 0 {
+		return fmt.Errorf("%d benchmark(s) regressed more than %.0f%% against the baseline", regressions, benchThreshold*100)
+	}
+	return nil
+}
+
+func printBench(results []bench.Result, baseline *bench.Baseline, comparisons []bench.Comparison) {
+	byName := make(map[string]bench.Comparison, len(comparisons))
+	for _, c := range comparisons {
+		byName[c.Name] = c
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Benchmarks"))
+	fmt.Printf("  %-16s %5s %9s %9s %9s %9s  %-18s %s\n", "", "runs", "p50", "p90", "p99", "max", "throughput", "vs baseline")
+	for _, r := range results {
+		if !r.OK() {
+			fmt.Printf("  %-16s %5d  %s\n", r.Name, r.Runs, style.Error.Render("failed: "+r.Error))
+			continue
+		}
+		vs := style.Dim.Render("-")
+		if c, ok := byName[r.Name]; ok {
+			change := fmt.Sprintf("%+.0f%%", c.Change*100)
+			switch {
+			case c.Regressed:
+				vs = style.Error.Render(change + " regressed")
+			case c.Change < 0:
+				vs = style.Success.Render(change)
+			default:
+				vs = change
+			}
+		}
+		fmt.Printf("  %-16s %5d %9s %9s %9s %9s  %-18s %s\n", r.Name, r.Runs,
+			benchDuration(r.P50), benchDuration(r.P90), benchDuration(r.P99), benchDuration(r.Max),
+			fmt.Sprintf("%.1f %s/s", r.Throughput, r.Unit), vs)
+		if r.Failures > 0 {
+			fmt.Printf("  %-16s %s\n", "", style.Warning.Render(fmt.Sprintf("%d run(s) failed: %s", r.Failures, r.Error)))
+		}
+	}
+
+	if baseline == nil {
+		fmt.Printf("\n  %s\n", style.Dim.Render("No baseline yet (record one with: gt bench --save)"))
+	} else {
+		fmt.Printf("\n  %s\n", style.Dim.Render(fmt.Sprintf("Baseline from %s (gt %s); medians compared",
+			baseline.RecordedAt.Local().Format("2006-01-02 15:04"), baseline.Version)))
+	}
+}
+
+// benchDuration rounds d for display.
+func benchDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Changelog flags
+var (
+	changelogRig  string
+	changelogFrom string
+	changelogTo   string
+	changelogJSON bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:     "changelog",
+	GroupID: GroupWork,
+	Short:   "Generate a changelog from merged commits and their beads",
+	Long: `Generate a markdown changelog for a rig's default branch.
+
+Each commit in the range is linked to the beads it closed, by the first of:
+  - the merge commit recorded on a closed merge-request bead
+  - a commit trailer: Closes:, Fixes:, Resolves:, Bead:, or Issue:
+  - a polecat/<worker>/<bead> branch named in the commit message
+  - a "(<bead>)" at the end of the subject
+
+Changes are grouped by their bead's feature, bug, or chore label (or issue
+type), falling back to the commit's conventional-commit type (feat:, fix:,
+chore:, ...). Commits with no linked bead are listed by subject.
+
+--from defaults to the last release tag before --to, and --to defaults to
+origin/<default-branch>. Only first-parent commits are listed, so each
+merged MR appears once.
+
+Examples:
+  gt changelog --rig gastown
+  gt changelog --rig gastown --from v1.2.0 --to HEAD
+  gt changelog --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runChangelog,
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogRig, "rig", "", "Rig to report on (default: infer from cwd)")
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "Start revision, exclusive (default: last release tag)")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "", "End revision, inclusive (default: origin/<default-branch>)")
+	changelogCmd.Flags().BoolVar(&changelogJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(changelogCmd)
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(changelogRig)
+	if err != nil {
+		return err
+	}
+
+	g := changelogGit(r)
+	fetchBeadNotes(g)
+
+	to := changelogTo
+	if to == "" {
+		if err := g.Fetch("origin"); err != nil {
+			fmt.Fprintf(os.Stderr, "%s fetching origin: %v\n", style.Warning.Render("⚠"), err)
+		}
+		to = "origin/" + r.DefaultBranch()
+	}
+	from, err := changelogStart(r, g, changelogFrom, to)
+	if err != nil {
+		return err
+	}
+
+	cl, err := changelog.Generate(g, beads.New(r.Path), changelog.Options{
+		Rig:      rigName,
+		Prefixes: rigBeadPrefixes(r),
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		return err
+	}
+	for _, w := range cl.Warnings {
+		fmt.Fprintf(os.Stderr, "%s %s\n", style.Warning.Render("⚠"), w)
+	}
+
+	if changelogJSON {
+		out, err := json.MarshalIndent(cl, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling changelog: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Print(changelog.Markdown(cl))
+	return nil
+}
+
+// changelogGit returns the clone the refinery merges in, so origin is the
+// rig's remote.
+func changelogGit(r *rig.Rig) *git.Git {
+	gitDir := filepath.Join(r.Path, "refinery", "rig")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		gitDir = filepath.Join(r.Path, "mayor", "rig")
+	}
+	return git.NewGit(gitDir)
+}
+
+// fetchBeadNotes replaces the local bead notes with origin's. Origin may
+// have none yet, so failures are ignored.
+func fetchBeadNotes(g *git.Git) {
+	_ = g.FetchBranch("origin", "+"+changelog.NotesRef+":"+changelog.NotesRef)
+}
+
+// changelogStart returns from, defaulting to the last release tag before to.
+func changelogStart(r *rig.Rig, g *git.Git, from, to string) (string, error) {
+	if from == "" {
+		eng := refinery.NewEngineer(r)
+		if err := eng.LoadConfig(); err != nil {
+			return "", fmt.Errorf("loading merge queue config: %w", err)
+		}
+		prefix := eng.Config().Release.TagPrefix
+		if prefix == "" {
+			prefix = refinery.DefaultReleaseTagPrefix
+		}
+		tag, err := g.LatestTag(prefix+"*", to)
+		if err != nil {
+			return "", fmt.Errorf("finding last release tag: %w", err)
+		}
+		return tag, nil
+	}
+	return from, nil
+}
+
+// rigBeadPrefixes returns the bead ID prefixes correlated with the rig's
+// commits.
+func rigBeadPrefixes(r *rig.Rig) []string {
+	if r.Config != nil && r.Config.Prefix != "" {
+		return []string{r.Config.Prefix}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Changelog backfill flags
+var (
+	changelogBackfillRig    string
+	changelogBackfillFrom   string
+	changelogBackfillTo     string
+	changelogBackfillDryRun bool
+)
+
+var changelogBackfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Infer Bead trailers for commits made without one",
+	Long: `Infer Bead trailers for historical commits on a rig's default branch.
+
+Commits made before trailer injection have no "Bead: <id>" trailer. This
+infers one from the commit's merge-request bead, the polecat branch named
+in its message, or a "(<bead>)" in its subject, and records it as a git note
+under refs/notes/beads. History is not rewritten. The notes are pushed to
+origin so every clone's changelog sees them.
+
+Commits that already have a trailer or note, or whose bead can't be
+inferred, are skipped. Running it again only notes new commits.
+
+--from and --to work as for 'gt changelog', except --from defaults to the
+root commit so all history is covered.
+
+Examples:
+  gt changelog backfill --rig gastown --dry-run
+  gt changelog backfill --rig gastown
+  gt changelog backfill --rig gastown --from v1.0.0`,
+	Args: cobra.NoArgs,
+	RunE: runChangelogBackfill,
+}
+
+func init() {
+	changelogBackfillCmd.Flags().StringVar(&changelogBackfillRig, "rig", "", "Rig to backfill (default: infer from cwd)")
+	changelogBackfillCmd.Flags().StringVar(&changelogBackfillFrom, "from", "", "Start revision, exclusive (default: root commit)")
+	changelogBackfillCmd.Flags().StringVar(&changelogBackfillTo, "to", "", "End revision, inclusive (default: origin/<default-branch>)")
+	changelogBackfillCmd.Flags().BoolVar(&changelogBackfillDryRun, "dry-run", false, "Show inferred trailers without writing notes")
+
+	changelogCmd.AddCommand(changelogBackfillCmd)
+}
+
+func runChangelogBackfill(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(changelogBackfillRig)
+	if err != nil {
+		return err
+	}
+
+	g := changelogGit(r)
+	fetchBeadNotes(g)
+	to := changelogBackfillTo
+	if to == "" {
+		if err := g.Fetch("origin"); err != nil {
+			fmt.Fprintf(os.Stderr, "%s fetching origin: %v\n", style.Warning.Render("⚠"), err)
+		}
+		to = "origin/" + r.DefaultBranch()
+	}
+
+	links, err := changelog.Backfill(g, beads.New(r.Path), changelog.BackfillOptions{
+		Prefixes: rigBeadPrefixes(r),
+		From:     changelogBackfillFrom,
+		To:       to,
+		DryRun:   changelogBackfillDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("backfilling %s: %w", rigName, err)
+	}
+
+	for _, l := range links {
+		fmt.Printf("  %s %s: %s %s\n", style.Dim.Render(shortSHA(l.Commit.SHA)), l.Commit.Subject,
+			changelog.TrailerKey, strings.Join(l.Beads, ", "))
+	}
+	if len(links) == 0 {
+		fmt.Printf("%s No commits on %s need a Bead trailer\n", style.Dim.Render("ℹ"), rigName)
+		return nil
+	}
+	if changelogBackfillDryRun {
+		fmt.Printf("\n%s Would note %d commit(s)\n", style.Dim.Render("ℹ"), len(links))
+		return nil
+	}
+
+	if err := g.Push("origin", changelog.NotesRef, false); err != nil {
+		return fmt.Errorf("pushing %s: %w", changelog.NotesRef, err)
+	}
+	fmt.Printf("\n%s Noted %d commit(s) and pushed %s\n", style.Success.Render("✓"), len(links), changelog.NotesRef)
+	return nil
+}
@@ -73,10 +73,16 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 
 	// Confirm unless --force
 	if !cleanupForce {
-		fmt.Printf("Kill these %d process(es)? [y/N] ", len(zombies))
-		var response string
-		_, _ = fmt.Scanln(&response)
-		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
+		ok, err := confirm(confirmPrompt{
+			ID:       PromptCleanupKill,
+			Question: fmt.Sprintf("Kill these %d process(es)?", len(zombies)),
+			Safe:     true,
+			Hint:     "pass --force to kill without confirmation",
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println("Aborted")
 			return nil
 		}
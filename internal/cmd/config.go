@@ -3,13 +3,17 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -29,7 +33,10 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config export > profile.json    Export shareable town configuration
+  gt config import profile.json      Import a town configuration profile
+  gt config schema [name]            Print JSON Schemas for config files`,
 }
 
 // Agent subcommands
@@ -64,8 +71,9 @@ Examples:
 }
 
 var configAgentSetCmd = &cobra.Command{
-	Use:   "set <name> <command>",
-	Short: "Set custom agent command",
+	Use:         "set <name> <command>",
+	Annotations: auditAnnotations(""),
+	Short:       "Set custom agent command",
 	Long: `Set a custom agent command in town settings.
 
 This creates or updates a custom agent definition that overrides
@@ -84,8 +92,9 @@ Examples:
 }
 
 var configAgentRemoveCmd = &cobra.Command{
-	Use:   "remove <name>",
-	Short: "Remove custom agent",
+	Use:         "remove <name>",
+	Annotations: auditAnnotations(""),
+	Short:       "Remove custom agent",
 	Long: `Remove a custom agent definition from town settings.
 
 This removes a custom agent from your town settings. Built-in agents
@@ -100,8 +109,9 @@ Examples:
 // Cost-tier subcommand
 
 var configCostTierCmd = &cobra.Command{
-	Use:   "cost-tier [tier]",
-	Short: "Get or set cost optimization tier",
+	Use:         "cost-tier [tier]",
+	Annotations: auditAnnotations(""),
+	Short:       "Get or set cost optimization tier",
 	Long: `Get or set the cost optimization tier for model selection.
 
 With no arguments, shows the current cost tier and role assignments.
@@ -179,8 +189,9 @@ func runConfigCostTier(cmd *cobra.Command, args []string) error {
 // Default-agent subcommand
 
 var configDefaultAgentCmd = &cobra.Command{
-	Use:   "default-agent [name]",
-	Short: "Get or set default agent",
+	Use:         "default-agent [name]",
+	Annotations: auditAnnotations(""),
+	Short:       "Get or set default agent",
 	Long: `Get or set the default agent for the town.
 
 With no arguments, shows the current default agent.
@@ -199,8 +210,9 @@ Examples:
 }
 
 var configAgentEmailDomainCmd = &cobra.Command{
-	Use:   "agent-email-domain [domain]",
-	Short: "Get or set agent email domain",
+	Use:         "agent-email-domain [domain]",
+	Annotations: auditAnnotations(""),
+	Short:       "Get or set agent email domain",
 	Long: `Get or set the domain used for agent git commit emails.
 
 When agents commit code via 'gt commit', their identity is converted
@@ -592,45 +604,151 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// configSetCmd sets a town config value by dot-notation key.
+// configSetCmd sets a config value by dot-notation key.
 var configSetCmd = &cobra.Command{
-	Use:   "set <key> <value>",
-	Short: "Set a configuration value",
-	Long: `Set a town configuration value using dot-notation keys.
+	Use:         "set <key> <value>",
+	Annotations: auditAnnotations(""),
+	Short:       "Set a configuration value",
+	Long: `Set a configuration value using dot-notation keys.
 
-Supported keys:
-  convoy.notify_on_complete   Push notification to Mayor session on convoy
-                              completion (true/false, default: false)
-  cli_theme                   CLI color scheme ("dark", "light", "auto")
-  default_agent               Default agent preset name
+The first part of the key picks the file; the rest is the path of JSON
+keys inside it (map entries are addressed by name):
+
+  town.<path>             settings/config.json (town settings)
+  rig.<rig>.<path>        <rig>/settings/config.json (rig settings)
+  messaging.<path>        config/messaging.json
+  escalation.<path>       settings/escalation.json
+
+Keys without one of these prefixes are town settings, so
+"cli_theme" is the same as "town.cli_theme".
+
+The value is parsed for the field's type: true/false for booleans,
+numbers, durations like 5m, comma-separated lists, and JSON for
+anything else. The whole file is validated before it is written, and
+invalid values are refused.
 
 Examples:
   gt config set convoy.notify_on_complete true
   gt config set cli_theme dark
-  gt config set default_agent claude`,
+  gt config set town.default_agent claude
+  gt config set rig.gastown.merge_queue.on_conflict auto_rebase
+  gt config set escalation.routes.high bead,mail:mayor
+  gt config set messaging.lists.oncall gastown/witness,mayor/`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
 
-// configGetCmd gets a town config value by dot-notation key.
+// configGetCmd gets a config value by dot-notation key.
 var configGetCmd = &cobra.Command{
 	Use:   "get <key>",
 	Short: "Get a configuration value",
-	Long: `Get a town configuration value using dot-notation keys.
+	Long: `Get a configuration value using dot-notation keys.
 
-Supported keys:
-  convoy.notify_on_complete   Push notification to Mayor session on convoy
-                              completion (true/false, default: false)
-  cli_theme                   CLI color scheme
-  default_agent               Default agent preset name
+Keys are the same as for 'gt config set'. Scalars are printed as-is;
+objects and lists are printed as JSON. Unset values print empty, except
+for town settings with a built-in default.
 
 Examples:
   gt config get convoy.notify_on_complete
-  gt config get cli_theme`,
+  gt config get town.default_agent
+  gt config get rig.gastown.merge_queue
+  gt config get escalation.routes`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConfigGet,
 }
 
+// configFile is a config file gt config get/set edits.
+type configFile struct {
+	name string // shown in messages, e.g. "town settings"
+	load func() (interface{}, error)
+	save func(v interface{}) error
+}
+
+// resolveConfigKey splits a gt config get/set key into the file it
+// belongs to and the path inside that file.
+func resolveConfigKey(townRoot, key string) (*configFile, string, error) {
+	prefix, rest, _ := strings.Cut(key, ".")
+	switch prefix {
+	case "rig":
+		rigName, path, ok := strings.Cut(rest, ".")
+		if !ok || rigName == "" || path == "" {
+			return nil, "", fmt.Errorf("rig keys look like rig.<rig>.<path>, got %q", key)
+		}
+		rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+		if err != nil {
+			return nil, "", fmt.Errorf("loading rigs config: %w", err)
+		}
+		if _, ok := rigsConfig.Rigs[rigName]; !ok {
+			return nil, "", fmt.Errorf("rig '%s' not found", rigName)
+		}
+		settingsPath := config.RigSettingsPath(filepath.Join(townRoot, rigName))
+		return &configFile{
+			name: rigName + " rig settings",
+			load: func() (interface{}, error) {
+				settings, err := config.LoadRigSettings(settingsPath)
+				if errors.Is(err, config.ErrNotFound) {
+					return config.NewRigSettings(), nil
+				}
+				return settings, err
+			},
+			save: func(v interface{}) error {
+				return config.SaveRigSettings(settingsPath, v.(*config.RigSettings))
+			},
+		}, path, nil
+
+	case "messaging":
+		configPath := config.MessagingConfigPath(townRoot)
+		return &configFile{
+			name: "messaging config",
+			load: func() (interface{}, error) { return config.LoadOrCreateMessagingConfig(configPath) },
+			save: func(v interface{}) error {
+				return config.SaveMessagingConfig(configPath, v.(*config.MessagingConfig))
+			},
+		}, rest, nil
+
+	case "escalation":
+		configPath := config.EscalationConfigPath(townRoot)
+		return &configFile{
+			name: "escalation config",
+			load: func() (interface{}, error) { return config.LoadOrCreateEscalationConfig(configPath) },
+			save: func(v interface{}) error {
+				return config.SaveEscalationConfig(configPath, v.(*config.EscalationConfig))
+			},
+		}, rest, nil
+
+	case "town":
+		key = rest
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	return &configFile{
+		name: "town settings",
+		load: func() (interface{}, error) { return config.LoadOrCreateTownSettings(settingsPath) },
+		save: func(v interface{}) error {
+			settings := v.(*config.TownSettings)
+			if err := validateCLITheme(settings.CLITheme); err != nil {
+				return err
+			}
+			return config.SaveTownSettings(settingsPath, settings)
+		},
+	}, key, nil
+}
+
+// townSettingDefaults are shown by gt config get for unset town settings.
+var townSettingDefaults = map[string]string{
+	"cli_theme":     "auto",
+	"default_agent": "claude",
+}
+
+// validateCLITheme checks a cli_theme value; empty means the default.
+func validateCLITheme(theme string) error {
+	switch theme {
+	case "", "dark", "light", "auto":
+		return nil
+	}
+	return fmt.Errorf("invalid cli_theme: %q (expected dark, light, or auto)", theme)
+}
+
 func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 	value := args[1]
@@ -640,40 +758,25 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
-	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	file, path, err := resolveConfigKey(townRoot, key)
 	if err != nil {
-		return fmt.Errorf("loading town settings: %w", err)
+		return err
 	}
-
-	switch key {
-	case "convoy.notify_on_complete":
-		b, err := parseBool(value)
-		if err != nil {
-			return fmt.Errorf("invalid value for %s: %w (expected true/false)", key, err)
-		}
-		if townSettings.Convoy == nil {
-			townSettings.Convoy = &config.ConvoyConfig{}
+	for _, deprecated := range config.DeprecatedMergeQueueKeys {
+		if path == "merge_queue."+deprecated {
+			return fmt.Errorf("%s is deprecated and ignored (set the rig's default_branch instead)", key)
 		}
-		townSettings.Convoy.NotifyOnComplete = b
-
-	case "cli_theme":
-		switch value {
-		case "dark", "light", "auto":
-			townSettings.CLITheme = value
-		default:
-			return fmt.Errorf("invalid cli_theme: %q (expected dark, light, or auto)", value)
-		}
-
-	case "default_agent":
-		townSettings.DefaultAgent = value
-
-	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent", key)
 	}
 
-	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
-		return fmt.Errorf("saving town settings: %w", err)
+	v, err := file.load()
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", file.name, err)
+	}
+	if err := config.SetPath(v, path, value); err != nil {
+		return err
+	}
+	if err := file.save(v); err != nil {
+		return fmt.Errorf("saving %s: %w", file.name, err)
 	}
 
 	fmt.Printf("Set %s = %s\n", style.Bold.Render(key), value)
@@ -688,51 +791,57 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
-	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	file, path, err := resolveConfigKey(townRoot, key)
 	if err != nil {
-		return fmt.Errorf("loading town settings: %w", err)
+		return err
 	}
-
-	var value string
-	switch key {
-	case "convoy.notify_on_complete":
-		if townSettings.Convoy != nil && townSettings.Convoy.NotifyOnComplete {
-			value = "true"
-		} else {
-			value = "false"
-		}
-
-	case "cli_theme":
-		value = townSettings.CLITheme
-		if value == "" {
-			value = "auto"
-		}
-
-	case "default_agent":
-		value = townSettings.DefaultAgent
-		if value == "" {
-			value = "claude"
-		}
-
-	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent", key)
+	v, err := file.load()
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", file.name, err)
+	}
+	value, err := config.GetPath(v, path)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(value)
+	out, err := formatConfigValue(value)
+	if err != nil {
+		return err
+	}
+	if out == "" && file.name == "town settings" {
+		out = townSettingDefaults[path]
+	}
+	fmt.Println(out)
 	return nil
 }
 
-// parseBool parses a boolean string (true/false, yes/no, 1/0).
-func parseBool(s string) (bool, error) {
-	switch strings.ToLower(s) {
-	case "true", "yes", "1", "on":
-		return true, nil
-	case "false", "no", "0", "off":
-		return false, nil
-	default:
-		return false, fmt.Errorf("cannot parse %q as boolean", s)
+// formatConfigValue renders a config value for gt config get: scalars
+// as-is, everything else as indented JSON.
+func formatConfigValue(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if s, ok := value.(fmt.Stringer); ok { // time.Duration, time.Time
+		return s.String(), nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		if rv.IsNil() {
+			return "", nil
+		}
+		if rv.Kind() == reflect.Pointer {
+			return formatConfigValue(rv.Elem().Interface())
+		}
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprint(value), nil
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding value: %w", err)
 	}
+	return string(data), nil
 }
 
 func init() {
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configExportOutput string
+	configImportOnly   []string
+	configImportDryRun bool
+)
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export town configuration as a shareable profile",
+	Long: `Export the town's configuration as a single JSON profile.
+
+The profile bundles:
+  settings     settings/config.json (default agent, role_agents, ...)
+  agents       settings/agents.json (custom agent registry)
+  messaging    config/messaging.json (lists, queues, announces)
+  escalation   settings/escalation.json (routes, contacts)
+  daemon       mayor/daemon.json (patrol config)
+
+Secrets are left out: env values whose names look like credentials
+(*_KEY, *_TOKEN, *_SECRET, ...) and the escalation Slack webhook. Values
+that are "secret:NAME" references are kept. The redacted fields are listed
+in the profile's "redacted" array.
+
+Examples:
+  gt config export > town-profile.json
+  gt config export -o town-profile.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigExport,
+}
+
+var configImportCmd = &cobra.Command{
+	Use:         "import <file>",
+	Annotations: auditAnnotations(""),
+	Short:       "Import a town configuration profile",
+	Long: `Import a profile written by 'gt config export', replacing the
+corresponding configuration files. Use "-" to read from stdin.
+
+The whole profile is validated before any file is written. Fields that
+were redacted on export keep this town's current values, so importing a
+shared profile never wipes local credentials.
+
+Examples:
+  gt config import town-profile.json
+  gt config import town-profile.json --only settings,messaging
+  gt config import town-profile.json --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigImport,
+}
+
+func init() {
+	configExportCmd.Flags().StringVarP(&configExportOutput, "output", "o", "", "Write the profile to a file instead of stdout")
+	configImportCmd.Flags().StringSliceVar(&configImportOnly, "only", nil, "Import only these sections (settings, agents, messaging, escalation, daemon)")
+	configImportCmd.Flags().BoolVar(&configImportDryRun, "dry-run", false, "Show which files would be written without writing them")
+
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+
+	profile, err := config.ExportTownProfile(townRoot)
+	if err != nil {
+		return fmt.Errorf("exporting profile: %w", err)
+	}
+
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding profile: %w", err)
+	}
+	data = append(data, '\n')
+
+	if configExportOutput == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(configExportOutput, data, 0644) //nolint:gosec // G306: profiles exclude secrets
+	}
+	if err != nil {
+		return fmt.Errorf("writing profile: %w", err)
+	}
+
+	// Report on stderr so stdout stays a clean profile.
+	if len(profile.Redacted) > 0 {
+		fmt.Fprintf(os.Stderr, "%s Redacted %d secret value(s): %s\n",
+			style.Warning.Render("!"), len(profile.Redacted), strings.Join(profile.Redacted, ", "))
+	}
+	if configExportOutput != "" {
+		fmt.Fprintf(os.Stderr, "%s Exported %s to %s\n",
+			style.Success.Render("✓"), strings.Join(profile.Sections(), ", "), configExportOutput)
+	}
+	return nil
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("reading profile: %w", err)
+	}
+
+	var profile config.TownProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return fmt.Errorf("parsing profile: %w", err)
+	}
+
+	if configImportDryRun {
+		if err := config.ValidateTownProfile(&profile); err != nil {
+			return err
+		}
+		sections := configImportOnly
+		if sections == nil {
+			sections = profile.Sections()
+		}
+		for _, s := range sections {
+			if config.ProfileSectionPath(townRoot, s) == "" {
+				return fmt.Errorf("unknown profile section %q (valid: %v)", s, config.ProfileSections)
+			}
+			fmt.Printf("Would import %s → %s\n", style.Bold.Render(s), relTownPath(townRoot, config.ProfileSectionPath(townRoot, s)))
+		}
+		return nil
+	}
+
+	written, err := config.ImportTownProfile(townRoot, &profile, configImportOnly)
+	for _, s := range written {
+		fmt.Printf("%s Imported %s → %s\n", style.Success.Render("✓"), s, relTownPath(townRoot, config.ProfileSectionPath(townRoot, s)))
+	}
+	if err != nil {
+		return err
+	}
+	if len(written) == 0 {
+		fmt.Printf("%s Profile has nothing to import\n", style.Dim.Render("○"))
+	}
+	return nil
+}
+
+// relTownPath returns path relative to the town root for display.
+func relTownPath(townRoot, path string) string {
+	if rel, err := filepath.Rel(townRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configPromptRig   string
+	configPromptRole  string
+	configPromptIssue string
+)
+
+var configPromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Inspect the initial prompt library",
+	Long: `Inspect the prompt library that runtime configs reference with
+"initial_prompt": "prompt:NAME".
+
+Prompts are NAME.md files under settings/prompts/ in the town and in each
+rig; a rig's file overrides the town's. They are Go templates that can use
+{{.Role}}, {{.Rig}}, {{.Issue}}, {{.TownRoot}} and {{.Env.NAME}}, insert
+another prompt with {{include "name"}}, and start with {{extends "name"}}
+to fill in another prompt's {{block}} sections with {{define}}.`,
+	RunE: requireSubcommand,
+}
+
+var configPromptListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompts in the library",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigPromptList,
+}
+
+var configPromptShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Render a prompt as an agent would receive it",
+	Long: `Render a prompt from the library for a role and rig, resolving includes
+and extends, exactly as it would be passed to the agent.
+
+Examples:
+  gt config prompt show witness --rig gastown --role witness
+  gt config prompt show polecat --rig gastown --role polecat --issue gt-123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigPromptShow,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{configPromptListCmd, configPromptShowCmd} {
+		c.Flags().StringVar(&configPromptRig, "rig", "", "Include this rig's prompts")
+	}
+	configPromptShowCmd.Flags().StringVar(&configPromptRole, "role", "", "Role to render for (mayor, witness, polecat, ...)")
+	configPromptShowCmd.Flags().StringVar(&configPromptIssue, "issue", "", "Issue to render {{.Issue}} as")
+
+	configPromptCmd.AddCommand(configPromptListCmd)
+	configPromptCmd.AddCommand(configPromptShowCmd)
+	configCmd.AddCommand(configPromptCmd)
+}
+
+// promptLibraryRoots returns the town root and, with --rig, the rig path.
+func promptLibraryRoots() (townRoot, rigPath string, err error) {
+	townRoot, err = workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if configPromptRig != "" {
+		rigPath = filepath.Join(townRoot, configPromptRig)
+	}
+	return townRoot, rigPath, nil
+}
+
+func runConfigPromptList(cmd *cobra.Command, args []string) error {
+	townRoot, rigPath, err := promptLibraryRoots()
+	if err != nil {
+		return err
+	}
+	prompts, err := config.ListPrompts(townRoot, rigPath)
+	if err != nil {
+		return err
+	}
+	if len(prompts) == 0 {
+		fmt.Printf("No prompts. Add NAME.md files to %s.\n", filepath.Join(townRoot, "settings", config.PromptsDir))
+		return nil
+	}
+	for _, p := range prompts {
+		rel, _ := filepath.Rel(townRoot, p.Path)
+		line := fmt.Sprintf("  %-24s %s", p.Name, style.Dim.Render(rel))
+		if p.Shadows != "" {
+			line += style.Dim.Render(" (overrides town)")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runConfigPromptShow(cmd *cobra.Command, args []string) error {
+	townRoot, rigPath, err := promptLibraryRoots()
+	if err != nil {
+		return err
+	}
+	ctx := &config.ArgContext{
+		Rig:      configPromptRig,
+		Role:     configPromptRole,
+		Issue:    configPromptIssue,
+		TownRoot: townRoot,
+	}
+	out, err := config.RenderPrompt(townRoot, rigPath, args[0], ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/jsonschema"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var configSchemaDir string
+
+// configSchema is one config file type gt config schema describes.
+type configSchema struct {
+	name  string
+	file  string
+	title string
+	value interface{}
+}
+
+// configSchemas lists the config file types in the order they are listed.
+// mayor/daemon.json is described by the daemon's own struct, which is what
+// the daemon reads.
+var configSchemas = []configSchema{
+	{config.SchemaTown, "mayor/town.json", "Gas Town town config", config.TownConfig{}},
+	{config.SchemaTownSettings, "settings/config.json", "Gas Town town settings", config.TownSettings{}},
+	{config.SchemaRigSettings, "<rig>/settings/config.json", "Gas Town rig settings", config.RigSettings{}},
+	{config.SchemaMessaging, "config/messaging.json", "Gas Town messaging config", config.MessagingConfig{}},
+	{config.SchemaEscalation, "settings/escalation.json", "Gas Town escalation config", config.EscalationConfig{}},
+	{config.SchemaDaemon, "mayor/daemon.json", "Gas Town daemon patrol config", daemon.DaemonPatrolConfig{}},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print JSON Schemas for config files",
+	Long: `Print the JSON Schema for a config file type, generated from the
+structs gt reads the file into.
+
+With no name, lists the available schemas. With --dir, writes every
+schema to <dir>/<name>.schema.json.
+
+Files saved by gt point editors at their schema: JSON files get a
+"$schema" key, YAML files a "# yaml-language-server: $schema=..."
+comment and TOML files a "#:schema ..." comment.
+
+Examples:
+  gt config schema                       # List schemas
+  gt config schema escalation            # Print one schema
+  gt config schema --dir docs/schemas    # Write all schemas`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigSchema,
+}
+
+func init() {
+	configSchemaCmd.Flags().StringVar(&configSchemaDir, "dir", "", "Write every schema to this directory")
+
+	configCmd.AddCommand(configSchemaCmd)
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	if configSchemaDir != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--dir writes every schema; don't pass a name")
+		}
+		return writeConfigSchemas(configSchemaDir)
+	}
+
+	if len(args) == 0 {
+		for _, s := range configSchemas {
+			fmt.Printf("  %-14s %s\n", s.name, style.Dim.Render(s.file))
+		}
+		return nil
+	}
+
+	for _, s := range configSchemas {
+		if s.name == args[0] {
+			data, err := marshalConfigSchema(s)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+	}
+	return fmt.Errorf("unknown schema %q (run 'gt config schema' to list them)", args[0])
+}
+
+// writeConfigSchemas writes every schema to dir as <name>.schema.json.
+func writeConfigSchemas(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	for _, s := range configSchemas {
+		data, err := marshalConfigSchema(s)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, s.name+".schema.json")
+		if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: schemas are public
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+		fmt.Printf("%s Wrote %s\n", style.Success.Render("✓"), path)
+	}
+	return nil
+}
+
+func marshalConfigSchema(s configSchema) ([]byte, error) {
+	schema := jsonschema.Generate(s.value, config.SchemaURL(s.name), s.title)
+	schema["description"] = "Schema for " + s.file + ", generated by gt config schema."
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s schema: %w", s.name, err)
+	}
+	return append(data, '\n'), nil
+}
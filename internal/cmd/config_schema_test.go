@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestConfigSchemasUpToDate checks the published schemas in docs/schemas
+// match the structs. Regenerate with: gt config schema --dir docs/schemas
+func TestConfigSchemasUpToDate(t *testing.T) {
+	for _, s := range configSchemas {
+		want, err := marshalConfigSchema(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		got, err := os.ReadFile(filepath.Join("..", "..", "docs", "schemas", s.name+".schema.json"))
+		if err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("docs/schemas/%s.schema.json is stale; run gt config schema --dir docs/schemas", s.name)
+		}
+	}
+}
@@ -735,37 +735,110 @@ func TestConfigSetGet(t *testing.T) {
 	})
 }
 
-func TestParseBool(t *testing.T) {
+func TestConfigSetGet_OtherFiles(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gastown": {GitURL: "https://example.com/gastown.git"}}}
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		t.Fatalf("save rigs.json: %v", err)
+	}
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(townRoot); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	cmd := &cobra.Command{}
+
+	t.Run("rig settings", func(t *testing.T) {
+		if err := runConfigSet(cmd, []string{"rig.gastown.merge_queue.on_conflict", "auto_rebase"}); err != nil {
+			t.Fatalf("runConfigSet: %v", err)
+		}
+		settingsPath := config.RigSettingsPath(filepath.Join(townRoot, "gastown"))
+		settings, err := config.LoadRigSettings(settingsPath)
+		if err != nil {
+			t.Fatalf("load rig settings: %v", err)
+		}
+		if settings.MergeQueue.OnConflict != config.OnConflictAutoRebase {
+			t.Errorf("on_conflict = %q, want auto_rebase", settings.MergeQueue.OnConflict)
+		}
+
+		// The rig settings validator refuses unknown strategies.
+		err = runConfigSet(cmd, []string{"rig.gastown.merge_queue.on_conflict", "yolo"})
+		if err == nil || !strings.Contains(err.Error(), "on_conflict") {
+			t.Fatalf("expected on_conflict validation error, got %v", err)
+		}
+		settings, _ = config.LoadRigSettings(settingsPath)
+		if settings.MergeQueue.OnConflict != config.OnConflictAutoRebase {
+			t.Errorf("invalid value was written: on_conflict = %q", settings.MergeQueue.OnConflict)
+		}
+	})
+
+	t.Run("unknown rig", func(t *testing.T) {
+		err := runConfigSet(cmd, []string{"rig.nope.merge_queue.enabled", "true"})
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("error = %v, want 'not found'", err)
+		}
+	})
+
+	t.Run("deprecated merge_queue key", func(t *testing.T) {
+		err := runConfigSet(cmd, []string{"rig.gastown.merge_queue.target_branch", "develop"})
+		if err == nil || !strings.Contains(err.Error(), "deprecated") {
+			t.Errorf("error = %v, want 'deprecated'", err)
+		}
+	})
+
+	t.Run("escalation routes", func(t *testing.T) {
+		if err := runConfigSet(cmd, []string{"escalation.routes.high", "bead,mail:mayor"}); err != nil {
+			t.Fatalf("runConfigSet: %v", err)
+		}
+		cfg, err := config.LoadEscalationConfig(config.EscalationConfigPath(townRoot))
+		if err != nil {
+			t.Fatalf("load escalation config: %v", err)
+		}
+		if got := strings.Join(cfg.Routes["high"], ","); got != "bead,mail:mayor" {
+			t.Errorf("routes.high = %q", got)
+		}
+		if err := runConfigGet(cmd, []string{"escalation.routes"}); err != nil {
+			t.Errorf("runConfigGet: %v", err)
+		}
+	})
+
+	t.Run("town prefix", func(t *testing.T) {
+		if err := runConfigSet(cmd, []string{"town.default_agent", "codex"}); err != nil {
+			t.Fatalf("runConfigSet: %v", err)
+		}
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			t.Fatalf("load settings: %v", err)
+		}
+		if settings.DefaultAgent != "codex" {
+			t.Errorf("DefaultAgent = %q, want codex", settings.DefaultAgent)
+		}
+	})
+}
+
+func TestFormatConfigValue(t *testing.T) {
+	var nilMap map[string]string
 	tests := []struct {
-		input string
-		want  bool
-		err   bool
+		value interface{}
+		want  string
 	}{
-		{"true", true, false},
-		{"True", true, false},
-		{"TRUE", true, false},
-		{"yes", true, false},
-		{"1", true, false},
-		{"on", true, false},
-		{"false", false, false},
-		{"False", false, false},
-		{"no", false, false},
-		{"0", false, false},
-		{"off", false, false},
-		{"maybe", false, true},
-		{"", false, true},
+		{nil, ""},
+		{"dark", "dark"},
+		{true, "true"},
+		{3, "3"},
+		{5 * time.Minute, "5m0s"},
+		{nilMap, ""},
+		{[]string{"a", "b"}, "[\n  \"a\",\n  \"b\"\n]"},
 	}
-
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got, err := parseBool(tt.input)
-			if (err != nil) != tt.err {
-				t.Errorf("parseBool(%q) error = %v, wantErr %v", tt.input, err, tt.err)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("parseBool(%q) = %v, want %v", tt.input, got, tt.want)
-			}
-		})
+		got, err := formatConfigValue(tt.value)
+		if err != nil {
+			t.Fatalf("formatConfigValue(%v): %v", tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("formatConfigValue(%v) = %q, want %q", tt.value, got, tt.want)
+		}
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -1321,14 +1322,13 @@ func isReadyIssue(t trackedIssueInfo) bool {
 	}
 
 	// Has assignee - check if session is alive
-	// Use the shared assigneeToSessionName from rig.go
-	sessionName, _ := assigneeToSessionName(t.Assignee)
-	if sessionName == "" {
+	assignee, err := session.ParseAssignee(t.Assignee)
+	if err != nil {
 		return true // Can't determine session = treat as ready
 	}
 
 	// Check if tmux session exists
-	checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
+	checkCmd := exec.Command("tmux", "has-session", "-t", assignee.SessionName())
 	if err := checkCmd.Run(); err != nil {
 		// Session doesn't exist = orphaned molecule or dead worker
 		// This is the key fix: issues with in_progress/hooked status but
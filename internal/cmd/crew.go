@@ -55,7 +55,9 @@ Commands:
   gt crew at <name>        Attach to session
   gt crew remove <name>    Remove workspace
   gt crew refresh <name>   Context cycle with handoff mail
-  gt crew restart <name>   Kill and restart session fresh`,
+  gt crew restart <name>   Kill and restart session fresh
+  gt crew handoff <name>   Hand in-progress work to a polecat
+  gt crew takeover <pc>    Take over a polecat's branch`,
 }
 
 var crewAddCmd = &cobra.Command{
@@ -84,7 +86,9 @@ var crewListCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	Long: `List all crew workspaces in a rig with their status.
 
-Shows git branch, session state, and git status for each workspace.
+Shows each workspace's branch, commits ahead/behind its upstream, dirty
+state, last commit time, and whether its session is running. Use --all to
+see who is active where across the whole town.
 
 Examples:
   gt crew list                    # List in current rig
@@ -131,8 +135,9 @@ Examples:
 }
 
 var crewRemoveCmd = &cobra.Command{
-	Use:   "remove <name...>",
-	Short: "Remove crew workspace(s)",
+	Use:         "remove <name...>",
+	Annotations: auditAnnotations(""),
+	Short:       "Remove crew workspace(s)",
 	Long: `Remove one or more crew workspaces from the rig.
 
 Checks for uncommitted changes and running sessions before removing.
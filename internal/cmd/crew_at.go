@@ -177,6 +177,13 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Secrets reach the agent through a private env file its startup
+	// command loads, never an argv.
+	secrets, err := config.StartupSecrets("crew", townRoot, r.Path, crewAgentOverride)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
+
 	if !hasSession {
 		// Create new session
 		if err := t.NewSession(sessionID, worker.ClonePath); err != nil {
@@ -234,6 +241,10 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && claudeConfigDir != "" {
 			startupCmd = config.PrependEnv(startupCmd, map[string]string{runtimeConfig.Session.ConfigDirEnv: claudeConfigDir})
 		}
+		startupCmd, err = t.WithSecrets(startupCmd, secrets)
+		if err != nil {
+			return err
+		}
 		// Note: Don't call KillPaneProcesses here - this is a NEW session with just
 		// a fresh shell. Killing it would destroy the pane before we can respawn.
 		// KillPaneProcesses is only needed when restarting in an EXISTING session
@@ -278,6 +289,10 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 			if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && claudeConfigDir != "" {
 				startupCmd = config.PrependEnv(startupCmd, map[string]string{runtimeConfig.Session.ConfigDirEnv: claudeConfigDir})
 			}
+			startupCmd, err = t.WithSecrets(startupCmd, secrets)
+			if err != nil {
+				return err
+			}
 			// Kill all processes in the pane before respawning to prevent orphan leaks
 			// RespawnPane's -k flag only sends SIGHUP which Claude/Node may ignore
 			if err := t.KillPaneProcesses(paneID); err != nil {
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	crewHandoffBead   string
+	crewHandoffTitle  string
+	crewHandoffDryRun bool
+)
+
+var crewHandoffCmd = &cobra.Command{
+	Use:   "handoff [name]",
+	Short: "Hand a crew member's in-progress work to a polecat",
+	Long: `Package a crew workspace's current work and sling it to a polecat to finish.
+
+The crew member's branch and any uncommitted changes (committed as WIP) are
+pushed to origin, then the bead is slung to a new polecat that starts from
+that branch. Its merge request targets the rig's default branch as usual.
+
+If the crew member is on the default branch, the work moves to a new
+handoff/<bead> branch first. Without --bead, a task bead is created
+(titled with --title). A bead hooked to the crew member is unhooked so the
+polecat can take it.
+
+The crew workspace is left on the handoff branch; check out another
+branch before continuing unrelated work there.
+
+Examples:
+  gt crew handoff dave --bead gt-abc           # Sling gt-abc with dave's branch
+  gt crew handoff --title "Finish retry logic" # From inside a crew workspace
+  gt crew handoff beads/emma --bead bd-12 --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCrewHandoff,
+}
+
+var crewTakeoverCmd = &cobra.Command{
+	Use:   "takeover <rig>/<polecat> [name]",
+	Short: "Take over a polecat's branch in a crew workspace",
+	Long: `Pull a polecat's branch into a crew workspace for human takeover.
+
+The polecat's session is stopped, its uncommitted changes are committed as
+WIP, and its branch is pushed to origin. The crew workspace (which must have
+no uncommitted changes) then fetches and checks out that branch.
+
+The polecat's hooked bead is moved to the crew member's hook, so gt hook
+and the bead's assignee reflect who owns the work. The polecat itself is
+kept; nuke it once you no longer need its worktree.
+
+Examples:
+  gt crew takeover gastown/Toast dave    # dave takes over Toast's branch
+  gt crew takeover gastown/Toast         # From inside a crew workspace`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runCrewTakeover,
+}
+
+func init() {
+	crewHandoffCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
+	crewHandoffCmd.Flags().StringVar(&crewHandoffBead, "bead", "", "Existing bead to sling (default: create one)")
+	crewHandoffCmd.Flags().StringVar(&crewHandoffTitle, "title", "", "Title for the created bead")
+	crewHandoffCmd.Flags().StringVarP(&crewMessage, "message", "m", "", "Context message for the polecat")
+	crewHandoffCmd.Flags().BoolVarP(&crewHandoffDryRun, "dry-run", "n", false, "Show what would be done")
+
+	crewTakeoverCmd.Flags().BoolVarP(&crewHandoffDryRun, "dry-run", "n", false, "Show what would be done")
+
+	crewCmd.AddCommand(crewHandoffCmd)
+	crewCmd.AddCommand(crewTakeoverCmd)
+}
+
+// resolveCrewWorker finds the named crew worker (rig/name accepted), or the
+// one whose workspace contains the cwd when name is empty.
+func resolveCrewWorker(name string) (*crew.CrewWorker, *rig.Rig, error) {
+	rigName := crewRig
+	if name == "" {
+		detected, err := detectCrewFromCwd()
+		if err != nil {
+			return nil, nil, err
+		}
+		rigName, name = detected.rigName, detected.crewName
+	} else if r, crewName, ok := parseRigSlashName(name); ok {
+		if rigName == "" {
+			rigName = r
+		}
+		name = crewName
+	}
+
+	crewMgr, r, err := getCrewManager(rigName)
+	if err != nil {
+		return nil, nil, err
+	}
+	worker, err := crewMgr.Get(name)
+	if err != nil {
+		if err == crew.ErrCrewNotFound {
+			return nil, nil, fmt.Errorf("crew workspace '%s' not found", name)
+		}
+		return nil, nil, fmt.Errorf("getting crew worker: %w", err)
+	}
+	return worker, r, nil
+}
+
+func runCrewHandoff(cmd *cobra.Command, args []string) error {
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	worker, r, err := resolveCrewWorker(name)
+	if err != nil {
+		return err
+	}
+	crewAddr := fmt.Sprintf("%s/crew/%s", r.Name, worker.Name)
+	g := git.NewGit(worker.ClonePath)
+
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(r.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	dirty, err := g.HasUncommittedChanges()
+	if err != nil {
+		return fmt.Errorf("checking for uncommitted changes: %w", err)
+	}
+
+	// Resolve the bead first: a new handoff branch is named after it.
+	beadID := crewHandoffBead
+	var info *beadInfo
+	if beadID != "" {
+		if info, err = getBeadInfo(beadID); err != nil {
+			return err
+		}
+	} else if crewHandoffTitle == "" {
+		return fmt.Errorf("pass --bead to hand off an existing bead, or --title to create one")
+	}
+
+	if crewHandoffDryRun {
+		if beadID == "" {
+			fmt.Printf("Would create bead: %s\n", crewHandoffTitle)
+			beadID = "<new>"
+		}
+		if branch == defaultBranch || branch == "" || branch == "HEAD" {
+			branch = "handoff/" + beadID
+			fmt.Printf("Would create branch %s in %s\n", branch, worker.ClonePath)
+		}
+		if dirty {
+			fmt.Printf("Would commit uncommitted changes as WIP\n")
+		}
+		fmt.Printf("Would push %s to origin\n", branch)
+		if info != nil && info.Status == beads.StatusHooked && info.Assignee == crewAddr {
+			fmt.Printf("Would run: gt unsling %s %s --force\n", beadID, crewAddr)
+		}
+		fmt.Printf("Would run: gt sling %s %s --base-branch %s\n", beadID, r.Name, branch)
+		return nil
+	}
+
+	if beadID == "" {
+		issue, err := beads.New(r.Path).Create(beads.CreateOptions{
+			Title:       crewHandoffTitle,
+			Type:        "task",
+			Priority:    2,
+			Description: fmt.Sprintf("Handed off from %s. Work so far is on the branch this bead is slung with.", crewAddr),
+			Actor:       crewAddr,
+		})
+		if err != nil {
+			return fmt.Errorf("creating bead: %w", err)
+		}
+		beadID = issue.ID
+		fmt.Printf("%s Created bead %s: %s\n", style.Success.Render("✓"), beadID, crewHandoffTitle)
+	}
+
+	if branch == defaultBranch || branch == "" || branch == "HEAD" {
+		branch = "handoff/" + beadID
+		if err := g.CreateBranch(branch); err != nil {
+			return fmt.Errorf("creating branch %s: %w", branch, err)
+		}
+		if err := g.Checkout(branch); err != nil {
+			return fmt.Errorf("checking out %s: %w", branch, err)
+		}
+	}
+	committed, err := packageWork(g, branch, fmt.Sprintf("WIP: hand off %s to a polecat", beadID))
+	if err != nil {
+		return err
+	}
+	if committed {
+		fmt.Printf("%s Committed uncommitted changes as WIP\n", style.Success.Render("✓"))
+	}
+	fmt.Printf("%s Pushed %s\n", style.Success.Render("✓"), branch)
+
+	// Release the crew member's hook so sling can assign the bead.
+	if info != nil && info.Status == beads.StatusHooked && info.Assignee == crewAddr {
+		if err := runGT("unsling", beadID, crewAddr, "--force"); err != nil {
+			return fmt.Errorf("unhooking %s from %s: %w", beadID, crewAddr, err)
+		}
+	}
+
+	message := crewMessage
+	if message == "" {
+		message = fmt.Sprintf("Handed off by %s: continue from the work already on %s.", crewAddr, branch)
+	}
+	if err := runGT("sling", beadID, r.Name, "--base-branch", branch, "-m", message); err != nil {
+		return fmt.Errorf("slinging %s: %w\n  The work is pushed to %s; retry with: gt sling %s %s --base-branch %s",
+			beadID, err, branch, beadID, r.Name, branch)
+	}
+	fmt.Printf("\nCrew workspace %s is still on %s.\n", worker.Name, branch)
+	return nil
+}
+
+func runCrewTakeover(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	var name string
+	if len(args) > 1 {
+		name = args[1]
+	}
+	if crewRig == "" && name != "" {
+		crewRig = rigName
+	}
+	worker, r, err := resolveCrewWorker(name)
+	if err != nil {
+		return err
+	}
+	if r.Name != rigName {
+		return fmt.Errorf("crew %s is in rig %s, but polecat %s is in %s", worker.Name, r.Name, polecatName, rigName)
+	}
+	crewAddr := fmt.Sprintf("%s/crew/%s", r.Name, worker.Name)
+	polecatAddr := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+
+	polecatMgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	p, err := polecatMgr.Get(polecatName)
+	if err != nil {
+		if errors.Is(err, polecat.ErrPolecatNotFound) {
+			return fmt.Errorf("polecat '%s' not found in %s", polecatName, rigName)
+		}
+		return fmt.Errorf("getting polecat: %w", err)
+	}
+	pGit := git.NewGit(p.ClonePath)
+	branch, err := pGit.CurrentBranch()
+	if err != nil || branch == "" || branch == "HEAD" {
+		return fmt.Errorf("polecat %s has no branch checked out", polecatAddr)
+	}
+
+	crewGit := git.NewGit(worker.ClonePath)
+	if dirty, err := crewGit.HasUncommittedChanges(); err != nil {
+		return fmt.Errorf("checking crew workspace: %w", err)
+	} else if dirty {
+		return fmt.Errorf("crew workspace %s has uncommitted changes; commit or stash them first", worker.Name)
+	}
+
+	if crewHandoffDryRun {
+		fmt.Printf("Would stop %s's session\n", polecatAddr)
+		fmt.Printf("Would commit %s's uncommitted changes as WIP and push %s\n", polecatAddr, branch)
+		fmt.Printf("Would check out %s in %s\n", branch, worker.ClonePath)
+		if p.Issue != "" {
+			fmt.Printf("Would move %s from %s's hook to %s's\n", p.Issue, polecatAddr, crewAddr)
+		}
+		return nil
+	}
+
+	// Stop the agent first so it doesn't keep editing the worktree.
+	sessMgr, _, err := getSessionManager(rigName)
+	if err != nil {
+		return err
+	}
+	if err := sessMgr.Stop(polecatName, false); err != nil && !errors.Is(err, polecat.ErrSessionNotFound) {
+		return fmt.Errorf("stopping %s: %w", polecatAddr, err)
+	} else if err == nil {
+		fmt.Printf("%s Stopped %s\n", style.Success.Render("✓"), polecatAddr)
+	}
+
+	committed, err := packageWork(pGit, branch, fmt.Sprintf("WIP: taken over by %s", crewAddr))
+	if err != nil {
+		return err
+	}
+	if committed {
+		fmt.Printf("%s Committed %s's uncommitted changes as WIP\n", style.Success.Render("✓"), polecatName)
+	}
+	fmt.Printf("%s Pushed %s\n", style.Success.Render("✓"), branch)
+
+	if err := checkoutTakeoverBranch(crewGit, branch); err != nil {
+		return err
+	}
+	fmt.Printf("%s Checked out %s in %s\n", style.Success.Render("✓"), branch, worker.ClonePath)
+
+	if p.Issue != "" {
+		if err := runGT("unsling", p.Issue, polecatAddr, "--force"); err != nil {
+			style.PrintWarning("could not unhook %s from %s: %v", p.Issue, polecatAddr, err)
+		} else if err := runGT("hook", p.Issue, crewAddr); err != nil {
+			style.PrintWarning("could not hook %s to %s: %v\n  Run: gt hook %s %s", p.Issue, crewAddr, err, p.Issue, crewAddr)
+		}
+	}
+
+	fmt.Printf("\nWhen you no longer need the polecat's worktree: gt polecat nuke %s/%s\n", rigName, polecatName)
+	return nil
+}
+
+// packageWork commits any uncommitted changes on the current branch as a WIP
+// commit and pushes the branch to origin. It reports whether a commit was made.
+func packageWork(g *git.Git, branch, message string) (bool, error) {
+	dirty, err := g.HasUncommittedChanges()
+	if err != nil {
+		return false, fmt.Errorf("checking for uncommitted changes: %w", err)
+	}
+	if dirty {
+		if err := g.Add("-A"); err != nil {
+			return false, fmt.Errorf("staging changes: %w", err)
+		}
+		if err := g.Commit(message); err != nil {
+			return false, fmt.Errorf("committing WIP: %w", err)
+		}
+	}
+	if err := g.Push("origin", branch, false); err != nil {
+		return dirty, fmt.Errorf("pushing %s: %w", branch, err)
+	}
+	return dirty, nil
+}
+
+// checkoutTakeoverBranch fetches origin and checks out branch, tracking
+// origin's copy. An existing local branch is fast-forwarded; one with
+// commits origin lacks is left for the user to reconcile.
+func checkoutTakeoverBranch(g *git.Git, branch string) error {
+	if err := g.Fetch("origin"); err != nil {
+		return fmt.Errorf("fetching origin: %w", err)
+	}
+	exists, err := g.BranchExists(branch)
+	if err != nil {
+		return fmt.Errorf("checking for branch %s: %w", branch, err)
+	}
+	if !exists {
+		if err := g.CreateBranchFrom(branch, "origin/"+branch); err != nil {
+			return fmt.Errorf("creating branch %s: %w", branch, err)
+		}
+	}
+	if err := g.Checkout(branch); err != nil {
+		return fmt.Errorf("checking out %s: %w", branch, err)
+	}
+	if exists {
+		if err := g.MergeFFOnly("origin/" + branch); err != nil {
+			return fmt.Errorf("local %s has diverged from origin/%s; merge it by hand: %w", branch, branch, err)
+		}
+	}
+	return nil
+}
+
+// runGT runs a gt subcommand, streaming its output.
+func runGT(args ...string) error {
+	c := exec.Command("gt", args...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
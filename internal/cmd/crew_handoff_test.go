@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// cloneTestRepo returns a bare origin seeded from makeTestGitRepo and a
+// fresh clone of it.
+func cloneTestRepo(t *testing.T) (origin, clone string) {
+	t.Helper()
+	seed := makeTestGitRepo(t)
+	origin = filepath.Join(t.TempDir(), "origin.git")
+	gitOut(t, seed, "clone", "--bare", seed, origin)
+	clone = filepath.Join(t.TempDir(), "clone")
+	gitOut(t, seed, "clone", origin, clone)
+	gitOut(t, clone, "config", "user.email", "test@test.com")
+	gitOut(t, clone, "config", "user.name", "Test")
+	return origin, clone
+}
+
+func TestPackageWork(t *testing.T) {
+	origin, clone := cloneTestRepo(t)
+	gitOut(t, clone, "checkout", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(clone, "wip.txt"), []byte("half done"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := packageWork(git.NewGit(clone), "feature", "WIP: hand off gt-abc to a polecat")
+	if err != nil {
+		t.Fatalf("packageWork: %v", err)
+	}
+	if !committed {
+		t.Error("committed = false with an untracked file")
+	}
+	if msg := gitOut(t, origin, "log", "-1", "--format=%s", "feature"); msg != "WIP: hand off gt-abc to a polecat" {
+		t.Errorf("origin feature head = %q, want the WIP commit", msg)
+	}
+
+	// Clean tree: nothing to commit, push is a no-op.
+	committed, err = packageWork(git.NewGit(clone), "feature", "WIP: again")
+	if err != nil || committed {
+		t.Errorf("clean packageWork = %v, %v; want false, nil", committed, err)
+	}
+}
+
+func TestCheckoutTakeoverBranch(t *testing.T) {
+	origin, polecatClone := cloneTestRepo(t)
+	crewClone := filepath.Join(t.TempDir(), "crew")
+	gitOut(t, polecatClone, "clone", origin, crewClone)
+
+	gitOut(t, polecatClone, "checkout", "-b", "polecat/toast")
+	gitOut(t, polecatClone, "commit", "--allow-empty", "-m", "first")
+	gitOut(t, polecatClone, "push", "origin", "polecat/toast")
+
+	crewGit := git.NewGit(crewClone)
+	if err := checkoutTakeoverBranch(crewGit, "polecat/toast"); err != nil {
+		t.Fatalf("checkoutTakeoverBranch: %v", err)
+	}
+	if got := gitOut(t, crewClone, "rev-parse", "--abbrev-ref", "HEAD"); got != "polecat/toast" {
+		t.Errorf("crew branch = %q", got)
+	}
+
+	// A second takeover fast-forwards the existing local branch.
+	gitOut(t, polecatClone, "commit", "--allow-empty", "-m", "second")
+	gitOut(t, polecatClone, "push", "origin", "polecat/toast")
+	gitOut(t, crewClone, "checkout", "-")
+	if err := checkoutTakeoverBranch(crewGit, "polecat/toast"); err != nil {
+		t.Fatalf("second checkoutTakeoverBranch: %v", err)
+	}
+	if got, want := gitOut(t, crewClone, "rev-parse", "HEAD"), gitOut(t, polecatClone, "rev-parse", "HEAD"); got != want {
+		t.Errorf("crew HEAD = %s, want polecat head %s", got, want)
+	}
+}
@@ -122,7 +122,7 @@ func findRigCrewSessions(rigPrefix string) ([]string, error) { //nolint:unparam
 		return nil, nil
 	}
 
-	prefix := rigPrefix + "-crew-"
+	prefix := session.RigSessionPrefix(rigPrefix) + "crew-"
 	var sessions []string
 
 	for _, s := range allSessions {
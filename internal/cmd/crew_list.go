@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -15,12 +17,35 @@ import (
 
 // CrewListItem represents a crew worker in list output.
 type CrewListItem struct {
-	Name       string `json:"name"`
-	Rig        string `json:"rig"`
-	Branch     string `json:"branch"`
-	Path       string `json:"path"`
-	HasSession bool   `json:"has_session"`
-	GitClean   bool   `json:"git_clean"`
+	Name       string     `json:"name"`
+	Rig        string     `json:"rig"`
+	Branch     string     `json:"branch"`
+	Path       string     `json:"path"`
+	HasSession bool       `json:"has_session"`
+	GitClean   bool       `json:"git_clean"`
+	DirtyFiles int        `json:"dirty_files,omitempty"`
+	Upstream   string     `json:"upstream,omitempty"` // empty when the branch tracks nothing
+	Ahead      int        `json:"ahead"`
+	Behind     int        `json:"behind"`
+	LastCommit *time.Time `json:"last_commit,omitempty"`
+}
+
+// crewGitActivity fills the git-derived fields of item from the worker's clone.
+func crewGitActivity(item *CrewListItem) {
+	g := git.NewGit(item.Path)
+	item.GitClean = true
+	if status, err := g.Status(); err == nil {
+		item.GitClean = status.Clean
+		item.DirtyFiles = len(status.Modified) + len(status.Added) + len(status.Deleted) + len(status.Untracked)
+	}
+	if upstream, err := g.Upstream(); err == nil {
+		if ahead, behind, err := g.AheadBehind(upstream); err == nil {
+			item.Upstream, item.Ahead, item.Behind = upstream, ahead, behind
+		}
+	}
+	if t, err := g.LastCommitTime(); err == nil {
+		item.LastCommit = &t
+	}
 }
 
 func runCrewList(cmd *cobra.Command, args []string) error {
@@ -69,20 +94,15 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 			sessionID := crewSessionName(r.Name, w.Name)
 			hasSession, _ := t.HasSession(sessionID)
 
-			workerGit := git.NewGit(w.ClonePath)
-			gitClean := true
-			if status, err := workerGit.Status(); err == nil {
-				gitClean = status.Clean
-			}
-
-			items = append(items, CrewListItem{
+			item := CrewListItem{
 				Name:       w.Name,
 				Rig:        r.Name,
 				Branch:     w.Branch,
 				Path:       w.ClonePath,
 				HasSession: hasSession,
-				GitClean:   gitClean,
-			})
+			}
+			crewGitActivity(&item)
+			items = append(items, item)
 		}
 	}
 
@@ -105,15 +125,38 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 			status = style.Bold.Render("●")
 		}
 
-		gitStatus := style.Dim.Render("clean")
-		if !item.GitClean {
-			gitStatus = style.Bold.Render("dirty")
-		}
-
 		fmt.Printf("  %s %s/%s\n", status, item.Rig, item.Name)
-		fmt.Printf("    Branch: %s  Git: %s\n", item.Branch, gitStatus)
+		fmt.Printf("    %s\n", formatCrewActivity(item))
 		fmt.Printf("    %s\n", style.Dim.Render(item.Path))
 	}
 
 	return nil
 }
+
+// formatCrewActivity renders a worker's branch, sync and git state on one line.
+func formatCrewActivity(item CrewListItem) string {
+	branch := item.Branch
+	if item.Upstream != "" {
+		var sync []string
+		if item.Ahead > 0 {
+			sync = append(sync, fmt.Sprintf("↑%d", item.Ahead))
+		}
+		if item.Behind > 0 {
+			sync = append(sync, fmt.Sprintf("↓%d", item.Behind))
+		}
+		if len(sync) > 0 {
+			branch += " " + strings.Join(sync, " ")
+		}
+	}
+
+	gitStatus := style.Dim.Render("clean")
+	if !item.GitClean {
+		gitStatus = style.Bold.Render(fmt.Sprintf("dirty (%d files)", item.DirtyFiles))
+	}
+
+	line := fmt.Sprintf("Branch: %s  Git: %s", branch, gitStatus)
+	if item.LastCommit != nil {
+		line += "  Last commit: " + relativeTime(*item.LastCommit)
+	}
+	return line
+}
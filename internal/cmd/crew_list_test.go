@@ -191,3 +191,21 @@ func TestRunCrewList_AllAggregatesJSON(t *testing.T) {
 		t.Fatalf("expected crew from rig-a and rig-b, got: %#v", rigs)
 	}
 }
+
+func TestFormatCrewActivity(t *testing.T) {
+	last := time.Now().Add(-3 * time.Hour)
+	got := formatCrewActivity(CrewListItem{
+		Branch: "main", Upstream: "origin/main", Ahead: 2, Behind: 1,
+		GitClean: false, DirtyFiles: 3, LastCommit: &last,
+	})
+	for _, want := range []string{"Branch: main ↑2 ↓1", "dirty (3 files)", "Last commit: 3 hours ago"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatCrewActivity() = %q, missing %q", got, want)
+		}
+	}
+
+	got = formatCrewActivity(CrewListItem{Branch: "feature", GitClean: true})
+	if strings.Contains(got, "↑") || strings.Contains(got, "Last commit") {
+		t.Errorf("formatCrewActivity() without upstream or commits = %q", got)
+	}
+}
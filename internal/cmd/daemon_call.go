@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/controlapi"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var daemonCallTimeout time.Duration
+
+var daemonCallCmd = &cobra.Command{
+	Use:   "call <method> [params-json]",
+	Short: "Call a method on the daemon's control API",
+	Long: `Call a method on the daemon's control API and print the JSON result.
+
+The daemon serves JSON-RPC 2.0 on a unix socket (daemon/control.sock by
+default), one request per line, so IDE extensions, web UIs, and scripts
+can query and drive the town without running gt for each operation. This
+command is a minimal client for it, handy for trying methods out.
+
+Methods:
+  rig.list                                 All rigs
+  rig.status     {"rig": R}                A rig's agents and whether each runs
+  rig.start      {"rig": R}                Same as gt rig start R
+  rig.stop       {"rig": R}                Same as gt rig stop R
+  polecat.list   {"rig": R}                A rig's polecats and whether each runs
+  polecat.nudge  {"rig": R, "polecat": P, "message": M}
+  queue.status   {"rig": R}                The rig's merge queue
+  dolt.health                              The Dolt server's health
+  escalation.list                          Open escalations
+  rpc.methods                              The methods above
+
+Configure or disable the API under patrols.control_api in
+mayor/daemon.json ("enabled", "socket").
+
+Examples:
+  gt daemon call rig.list
+  gt daemon call rig.status '{"rig": "gastown"}'
+  echo '{"jsonrpc":"2.0","id":1,"method":"dolt.health"}' | nc -U ~/gt/daemon/control.sock`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDaemonCall,
+}
+
+func init() {
+	daemonCallCmd.Flags().DurationVar(&daemonCallTimeout, "timeout", 3*time.Minute, "Give up after this long")
+
+	daemonCmd.AddCommand(daemonCallCmd)
+}
+
+func runDaemonCall(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var params any
+	if len(args) == 2 {
+		if !json.Valid([]byte(args[1])) {
+			return fmt.Errorf("params must be JSON, e.g. '{\"rig\": \"gastown\"}'")
+		}
+		params = json.RawMessage(args[1])
+	}
+
+	client, err := controlapi.Dial(daemon.ControlAPISocket(townRoot, daemon.LoadPatrolConfig(townRoot)))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonCallTimeout)
+	defer cancel()
+	var result json.RawMessage
+	if err := client.Call(ctx, args[0], params, &result); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -220,6 +221,32 @@ Examples:
 	RunE: runDeaconStaleHooks,
 }
 
+var deaconAbandonedBeadsCmd = &cobra.Command{
+	Use:   "abandoned-beads",
+	Short: "Reopen in_progress beads abandoned by dead agents",
+	Long: `Find in_progress beads whose assignee has had no session and no updates
+for the idle threshold (default: 24 hours), and move them back to open.
+
+This is the time-based, town-wide version of 'gt rig reset --stale'. Each
+reopened bead gets a comment recording why, its assignee is cleared, and the
+former assignee is sent a queued nudge it sees when it next starts.
+
+Beads of crew and other persistent agents are skipped unless
+--include-persistent is given, since their work waits for them to restart.
+Use --status to park beads in a custom status (e.g. stalled) instead of
+returning them to the ready queue.
+
+The daemon runs this periodically when the abandoned_beads patrol is enabled
+in mayor/daemon.json.
+
+Examples:
+  gt deacon abandoned-beads                  # Reopen abandoned beads in all rigs
+  gt deacon abandoned-beads --dry-run        # Preview what would be reopened
+  gt deacon abandoned-beads --max-idle=72h   # Use a 3 day threshold
+  gt deacon abandoned-beads --rig gastown --status stalled`,
+	RunE: runDeaconAbandonedBeads,
+}
+
 var deaconPauseCmd = &cobra.Command{
 	Use:   "pause",
 	Short: "Pause the Deacon to prevent patrol actions",
@@ -395,6 +422,13 @@ var (
 	staleHooksMaxAge time.Duration
 	staleHooksDryRun bool
 
+	// Abandoned beads flags
+	abandonedMaxIdle           time.Duration
+	abandonedStatus            string
+	abandonedRig               string
+	abandonedIncludePersistent bool
+	abandonedDryRun            bool
+
 	// Pause flags
 	pauseReason string
 
@@ -424,6 +458,7 @@ func init() {
 	deaconCmd.AddCommand(deaconForceKillCmd)
 	deaconCmd.AddCommand(deaconHealthStateCmd)
 	deaconCmd.AddCommand(deaconStaleHooksCmd)
+	deaconCmd.AddCommand(deaconAbandonedBeadsCmd)
 	deaconCmd.AddCommand(deaconPauseCmd)
 	deaconCmd.AddCommand(deaconResumeCmd)
 	deaconCmd.AddCommand(deaconCleanupOrphansCmd)
@@ -460,6 +495,18 @@ func init() {
 	deaconStaleHooksCmd.Flags().BoolVar(&staleHooksDryRun, "dry-run", false,
 		"Preview what would be unhooked without making changes")
 
+	// Flags for abandoned-beads
+	deaconAbandonedBeadsCmd.Flags().DurationVar(&abandonedMaxIdle, "max-idle", 24*time.Hour,
+		"How long a bead must go without updates while its assignee has no session")
+	deaconAbandonedBeadsCmd.Flags().StringVar(&abandonedStatus, "status", "open",
+		"Status to move abandoned beads to")
+	deaconAbandonedBeadsCmd.Flags().StringVar(&abandonedRig, "rig", "",
+		"Only scan this rig (default: the town and all rigs)")
+	deaconAbandonedBeadsCmd.Flags().BoolVar(&abandonedIncludePersistent, "include-persistent", false,
+		"Also reopen beads of crew and other persistent agents")
+	deaconAbandonedBeadsCmd.Flags().BoolVar(&abandonedDryRun, "dry-run", false,
+		"Preview what would be reopened without making changes")
+
 	// Flags for pause
 	deaconPauseCmd.Flags().StringVar(&pauseReason, "reason", "",
 		"Reason for pausing the Deacon")
@@ -543,6 +590,10 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 		Sender:    "daemon",
 		Topic:     "patrol",
 	}, "I am Deacon. First run `gt deacon heartbeat`. Then check gt hook, if empty create mol-deacon-patrol wisp and execute it.")
+	secrets, err := config.StartupSecrets("deacon", townRoot, "", agentOverride)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
 	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("deacon", "", townRoot, "", initialPrompt, agentOverride)
 	if err != nil {
 		return fmt.Errorf("building startup command: %w", err)
@@ -550,7 +601,12 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	// Secrets reach the agent's environment, never an argv.
 	fmt.Println("Starting Deacon session...")
+	startupCmd, err = t.WithSecrets(startupCmd, secrets)
+	if err != nil {
+		return err
+	}
 	if err := t.NewSessionWithCommand(sessionName, deaconDir, startupCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
@@ -1341,6 +1397,73 @@ func runDeaconStaleHooks(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runDeaconAbandonedBeads reopens in_progress beads abandoned by dead agents.
+func runDeaconAbandonedBeads(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	cfg := &deacon.AbandonedBeadConfig{
+		MaxIdle:           abandonedMaxIdle,
+		Status:            abandonedStatus,
+		IncludePersistent: abandonedIncludePersistent,
+		DryRun:            abandonedDryRun,
+	}
+
+	targets := []string{abandonedRig}
+	if abandonedRig == "" {
+		rigs := discoverRigs(townRoot)
+		sort.Strings(rigs)
+		targets = append(targets, rigs...)
+	}
+
+	total, reopened := 0, 0
+	for _, rigName := range targets {
+		label := rigName
+		if label == "" {
+			label = "town"
+		}
+		result, err := deacon.ScanAbandonedBeads(townRoot, rigName, cfg)
+		if err != nil {
+			fmt.Printf("%s %s: %v\n", style.Dim.Render("✗"), label, err)
+			continue
+		}
+		total += result.AbandonedCount
+		reopened += result.Reopened
+
+		for _, r := range result.Results {
+			status := style.Bold.Render("?")
+			action := "would move to " + cfg.Status
+			if !abandonedDryRun {
+				if r.Reopened {
+					status = style.Bold.Render("✓")
+					action = "moved to " + cfg.Status
+					if r.Nudged {
+						action += ", assignee nudged"
+					}
+				} else {
+					status = style.Dim.Render("✗")
+					action = fmt.Sprintf("error: %s", r.Error)
+				}
+			}
+			fmt.Printf("  %s %s/%s: %s (%s)\n", status, label, r.BeadID, action, r.Reason)
+		}
+	}
+
+	switch {
+	case total == 0:
+		fmt.Printf("%s No abandoned beads (idle threshold %s)\n", style.Dim.Render("○"), abandonedMaxIdle)
+	case abandonedDryRun:
+		fmt.Printf("\n%s Dry run - %d abandoned bead(s), no changes made.\n",
+			style.Dim.Render("ℹ"), total)
+	default:
+		fmt.Printf("\n%s Moved %d of %d abandoned bead(s) to %s\n",
+			style.Bold.Render("✓"), reopened, total, cfg.Status)
+	}
+	return nil
+}
+
 // runDeaconPause pauses the Deacon to prevent patrol actions.
 func runDeaconPause(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
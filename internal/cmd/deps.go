@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Dependency relations accepted by gt deps add/remove.
+const (
+	depsRelBlocks    = "blocks"
+	depsRelDependsOn = "depends-on"
+)
+
+var depsCmd = &cobra.Command{
+	Use:     "deps",
+	GroupID: GroupWork,
+	Short:   "Manage dependencies between beads, including across rigs",
+	Long: `Manage blocking dependencies between beads.
+
+Both beads may live in different rigs: IDs are resolved through the town's
+routes.jsonl, so 'gt-123 blocks bd-456' links a gastown bead to a beads-rig
+bead. Work with open blockers is not slung (use 'gt sling --force' to
+override) and the refinery holds merge requests until their blockers close.
+
+Examples:
+  gt deps add gt-123 blocks bd-456       # bd-456 waits for gt-123
+  gt deps add bd-456 depends-on gt-123   # same edge, other direction
+  gt deps remove gt-123 blocks bd-456
+  gt deps graph bd-456                   # what blocks bd-456, transitively
+  gt deps graph --rig gastown --format dot | dot -Tsvg > deps.svg`,
+	RunE: requireSubcommand,
+}
+
+var depsAddCmd = &cobra.Command{
+	Use:   "add <id> blocks|depends-on <id>",
+	Short: "Add a blocking dependency",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runDepsAdd,
+}
+
+var depsRemoveCmd = &cobra.Command{
+	Use:   "remove <id> blocks|depends-on <id>",
+	Short: "Remove a blocking dependency",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runDepsRemove,
+}
+
+func init() {
+	depsCmd.AddCommand(depsAddCmd)
+	depsCmd.AddCommand(depsRemoveCmd)
+	depsCmd.AddCommand(depsGraphCmd)
+	rootCmd.AddCommand(depsCmd)
+}
+
+// parseDepsEdge turns "<a> <relation> <b>" into (dependent, blocker).
+func parseDepsEdge(args []string) (dependent, blocker string, err error) {
+	a, rel, b := args[0], strings.ToLower(args[1]), args[2]
+	switch rel {
+	case depsRelBlocks:
+		dependent, blocker = b, a
+	case depsRelDependsOn, "depends_on", "needs":
+		dependent, blocker = a, b
+	default:
+		return "", "", fmt.Errorf("unknown relation %q: use %q or %q", args[1], depsRelBlocks, depsRelDependsOn)
+	}
+	if dependent == blocker {
+		return "", "", fmt.Errorf("a bead cannot depend on itself")
+	}
+	return dependent, blocker, nil
+}
+
+// validateDepsEdge checks that both beads are routed and exist.
+func validateDepsEdge(townRoot, dependent, blocker string) error {
+	for _, id := range []string{blocker, dependent} {
+		if _, err := beads.ResolveBeadRoute(townRoot, id); err != nil {
+			return err
+		}
+	}
+	b := beads.New(townRoot)
+	for _, id := range []string{blocker, dependent} {
+		if _, err := b.Show(id); err != nil {
+			return fmt.Errorf("bead %s not found: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func runDepsAdd(cmd *cobra.Command, args []string) error {
+	dependent, blocker, err := parseDepsEdge(args)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := validateDepsEdge(townRoot, dependent, blocker); err != nil {
+		return err
+	}
+
+	// bd stores the edge in the dependent's database, so run from its rig.
+	b := beads.New(beads.ResolveHookDir(townRoot, dependent, townRoot))
+	if err := b.AddDependency(dependent, blocker); err != nil {
+		return fmt.Errorf("adding dependency: %w", err)
+	}
+	fmt.Printf("%s %s blocks %s\n", style.SuccessPrefix, blocker, dependent)
+	return nil
+}
+
+func runDepsRemove(cmd *cobra.Command, args []string) error {
+	dependent, blocker, err := parseDepsEdge(args)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := beads.ResolveBeadRoute(townRoot, dependent); err != nil {
+		return err
+	}
+
+	b := beads.New(beads.ResolveHookDir(townRoot, dependent, townRoot))
+	if err := b.RemoveDependency(dependent, blocker); err != nil {
+		return fmt.Errorf("removing dependency: %w", err)
+	}
+	fmt.Printf("%s %s no longer blocks %s\n", style.SuccessPrefix, blocker, dependent)
+	return nil
+}
@@ -0,0 +1,488 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Output formats for gt deps graph --format.
+const (
+	depsFormatTree    = "tree"
+	depsFormatDot     = "dot"
+	depsFormatMermaid = "mermaid"
+)
+
+// Deps graph flags
+var (
+	depsGraphFormat string
+	depsGraphRig    string
+	depsGraphAll    bool
+	depsGraphDepth  int
+)
+
+var depsGraphCmd = &cobra.Command{
+	Use:   "graph [<id>]",
+	Short: "Render the dependency graph of a bead or a rig",
+	Long: `Render blocking dependencies as an ASCII tree, Graphviz DOT, or Mermaid.
+
+With a bead ID, shows everything that blocks it (transitively, across rigs)
+and the beads it directly blocks. With --rig, shows every blocked bead in
+the rig together with its blockers; closed beads are omitted unless --all.
+
+Nodes are colored by status: closed is green, unknown (unreachable or
+unrouted) is orange, anything else is red. Cross-rig edges are marked with ⇢
+in the tree and drawn dashed in DOT and Mermaid. Dependents in other rigs
+are not listed: bd stores the edge in the dependent's rig, so only blockers
+are visible from this side.
+
+Examples:
+  gt deps graph bd-456
+  gt deps graph bd-456 --format mermaid
+  gt deps graph --rig gastown --format dot | dot -Tsvg > deps.svg`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDepsGraph,
+}
+
+func init() {
+	depsGraphCmd.Flags().StringVar(&depsGraphFormat, "format", depsFormatTree, "Output format: tree, dot, mermaid")
+	depsGraphCmd.Flags().StringVar(&depsGraphRig, "rig", "", "Graph all blocked beads in a rig")
+	depsGraphCmd.Flags().BoolVar(&depsGraphAll, "all", false, "Include closed beads (with --rig)")
+	depsGraphCmd.Flags().IntVar(&depsGraphDepth, "depth", 10, "Maximum blocker depth to follow")
+}
+
+// depsNode is one bead in a dependency graph.
+type depsNode struct {
+	ID       string
+	Title    string
+	Status   string
+	Rig      string
+	Blockers []string // unwrapped IDs of "blocks" dependencies
+	Blocks   []string // unwrapped IDs of same-rig "blocks" dependents
+}
+
+// depsEdge points from a blocker to the bead it blocks.
+type depsEdge struct{ From, To string }
+
+// depsGraph loads beads on demand and caches them by ID.
+type depsGraph struct {
+	townRoot string
+	show     func(id string) (*beads.Issue, error)
+	nodes    map[string]*depsNode
+}
+
+func newDepsGraph(townRoot string, show func(id string) (*beads.Issue, error)) *depsGraph {
+	return &depsGraph{townRoot: townRoot, show: show, nodes: make(map[string]*depsNode)}
+}
+
+// add records a bead that has already been loaded (e.g. by a batch show).
+func (g *depsGraph) add(issue *beads.Issue) *depsNode {
+	n := &depsNode{ID: issue.ID, Title: issue.Title, Status: issue.Status, Rig: g.rigOf(issue.ID)}
+	for _, dep := range issue.Dependencies {
+		if dep.DependencyType == beads.DependencyTypeBlocks {
+			n.Blockers = append(n.Blockers, beads.ExtractIssueID(dep.ID))
+		}
+	}
+	for _, dep := range issue.Dependents {
+		if dep.DependencyType == beads.DependencyTypeBlocks {
+			n.Blocks = append(n.Blocks, beads.ExtractIssueID(dep.ID))
+		}
+	}
+	sort.Strings(n.Blockers)
+	sort.Strings(n.Blocks)
+	g.nodes[n.ID] = n
+	return n
+}
+
+// node returns the bead for id, loading it on first use. Beads that cannot
+// be loaded are returned with status "unknown".
+func (g *depsGraph) node(id string) *depsNode {
+	if n, ok := g.nodes[id]; ok {
+		return n
+	}
+	issue, err := g.show(id)
+	if err != nil || issue == nil {
+		n := &depsNode{ID: id, Status: "unknown", Rig: g.rigOf(id)}
+		g.nodes[id] = n
+		return n
+	}
+	issue.ID = id
+	return g.add(issue)
+}
+
+func (g *depsGraph) rigOf(id string) string {
+	if route, err := beads.ResolveBeadRoute(g.townRoot, id); err == nil {
+		return beads.RouteRigName(route)
+	}
+	return "?"
+}
+
+// collect walks blockers breadth-first from roots up to maxDepth and adds
+// each root's direct dependents. Edges are deduplicated and sorted.
+func (g *depsGraph) collect(roots []string, maxDepth int) []depsEdge {
+	seen := map[depsEdge]bool{}
+	var edges []depsEdge
+	addEdge := func(e depsEdge) {
+		if !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+
+	depth := map[string]int{}
+	queue := append([]string(nil), roots...)
+	for _, id := range roots {
+		depth[id] = 0
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		n := g.node(id)
+		if depth[id] >= maxDepth {
+			continue
+		}
+		for _, blocker := range n.Blockers {
+			addEdge(depsEdge{From: blocker, To: id})
+			if _, ok := depth[blocker]; !ok {
+				depth[blocker] = depth[id] + 1
+				queue = append(queue, blocker)
+			}
+		}
+	}
+	for _, id := range roots {
+		for _, dependent := range g.node(id).Blocks {
+			addEdge(depsEdge{From: id, To: dependent})
+			g.node(dependent)
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].From < edges[j].From
+	})
+	return edges
+}
+
+// rigRoots loads a rig's beads into the graph and returns the blocked beads
+// that do not themselves block another included bead — the ends of each
+// dependency chain. Within a pure cycle every blocked bead is a root.
+func (g *depsGraph) rigRoots(issues []*beads.Issue, includeClosed bool) []string {
+	included := map[string]bool{}
+	for _, issue := range issues {
+		if !includeClosed && (issue.Status == "closed" || issue.Status == "tombstone") {
+			continue
+		}
+		g.add(issue)
+		included[issue.ID] = true
+	}
+
+	blocking := map[string]bool{}
+	var blocked []string
+	for id := range included {
+		n := g.nodes[id]
+		if len(n.Blockers) > 0 {
+			blocked = append(blocked, id)
+		}
+		for _, b := range n.Blockers {
+			if included[b] {
+				blocking[b] = true
+			}
+		}
+	}
+	sort.Strings(blocked)
+
+	var roots []string
+	for _, id := range blocked {
+		if !blocking[id] {
+			roots = append(roots, id)
+		}
+	}
+	if len(roots) == 0 {
+		return blocked
+	}
+	return roots
+}
+
+func runDepsGraph(cmd *cobra.Command, args []string) error {
+	switch depsGraphFormat {
+	case depsFormatTree, depsFormatDot, depsFormatMermaid:
+	default:
+		return fmt.Errorf("invalid --format %q: use tree, dot, or mermaid", depsGraphFormat)
+	}
+	if (len(args) == 1) == (depsGraphRig != "") {
+		return fmt.Errorf("specify either a bead ID or --rig")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	g := newDepsGraph(townRoot, beads.New(townRoot).Show)
+
+	var roots []string
+	if len(args) == 1 {
+		if _, err := beads.ResolveBeadRoute(townRoot, args[0]); err != nil {
+			return err
+		}
+		roots = []string{args[0]}
+	} else {
+		roots, err = loadRigDepsRoots(townRoot, g)
+		if err != nil {
+			return err
+		}
+		if len(roots) == 0 {
+			fmt.Printf("No blocked beads in rig %s\n", depsGraphRig)
+			return nil
+		}
+	}
+
+	switch depsGraphFormat {
+	case depsFormatDot:
+		return writeDepsDot(os.Stdout, g, roots, g.collect(roots, depsGraphDepth))
+	case depsFormatMermaid:
+		return writeDepsMermaid(os.Stdout, g, roots, g.collect(roots, depsGraphDepth))
+	default:
+		for i, id := range roots {
+			if i > 0 {
+				fmt.Println()
+			}
+			writeDepsTree(os.Stdout, g, id, depsGraphDepth)
+		}
+		return nil
+	}
+}
+
+// loadRigDepsRoots lists the rig's beads with one batch show and returns the
+// graph roots.
+func loadRigDepsRoots(townRoot string, g *depsGraph) ([]string, error) {
+	prefix := beads.GetPrefixForRig(townRoot, depsGraphRig) + "-"
+	rigPath := beads.GetRigPathForPrefix(townRoot, prefix)
+	if rigPath == "" || beads.GetRigNameForPrefix(townRoot, prefix) != depsGraphRig {
+		return nil, fmt.Errorf("rig %q has no route in %s", depsGraphRig, beads.RoutesFileName)
+	}
+
+	b := beads.New(rigPath)
+	status := "open"
+	if depsGraphAll {
+		status = "all"
+	}
+	listed, err := b.List(beads.ListOptions{Status: status, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing beads in %s: %w", depsGraphRig, err)
+	}
+	ids := make([]string, 0, len(listed))
+	for _, issue := range listed {
+		ids = append(ids, issue.ID)
+	}
+	// bd list omits dependency details; fetch them in one call.
+	details, err := b.ShowMultiple(ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading dependencies: %w", err)
+	}
+	issues := make([]*beads.Issue, 0, len(details))
+	for _, issue := range details {
+		issues = append(issues, issue)
+	}
+	return g.rigRoots(issues, depsGraphAll), nil
+}
+
+// depsStatusIcon renders a node's status for the tree view.
+func depsStatusIcon(status string) string {
+	switch status {
+	case "closed", "tombstone":
+		return style.SuccessPrefix
+	case "unknown":
+		return style.WarningPrefix
+	default:
+		return style.ErrorPrefix
+	}
+}
+
+// depsStatusText colors a status word to match its icon.
+func depsStatusText(status string) string {
+	switch status {
+	case "closed", "tombstone":
+		return style.Success.Render(status)
+	case "unknown":
+		return style.Warning.Render(status)
+	default:
+		return style.Error.Render(status)
+	}
+}
+
+func depsNodeLabel(n *depsNode, crossRig bool) string {
+	edge := ""
+	if crossRig {
+		edge = "⇢ "
+	}
+	return fmt.Sprintf("%s %s%s %s %s %s", depsStatusIcon(n.Status), edge, style.Bold.Render(n.ID),
+		style.Dim.Render("["+n.Rig+"]"), depsStatusText(n.Status), n.Title)
+}
+
+// writeDepsTree prints the root, its transitive blockers as a tree, and its
+// direct dependents.
+func writeDepsTree(w io.Writer, g *depsGraph, rootID string, maxDepth int) {
+	root := g.node(rootID)
+	fmt.Fprintln(w, depsNodeLabel(root, false))
+
+	var walk func(n *depsNode, indent string, depth int, path map[string]bool)
+	walk = func(n *depsNode, indent string, depth int, path map[string]bool) {
+		for i, id := range n.Blockers {
+			last := i == len(n.Blockers)-1
+			branch, next := "├── ", "│   "
+			if last {
+				branch, next = "└── ", "    "
+			}
+			child := g.node(id)
+			cross := child.Rig != n.Rig
+			if path[id] {
+				fmt.Fprintf(w, "%s%s%s %s\n", indent, branch, id, style.Error.Render("(cycle)"))
+				continue
+			}
+			fmt.Fprintf(w, "%s%s%s\n", indent, branch, depsNodeLabel(child, cross))
+			if depth+1 >= maxDepth {
+				if len(child.Blockers) > 0 {
+					fmt.Fprintf(w, "%s%s%s\n", indent, next, style.Dim.Render("…"))
+				}
+				continue
+			}
+			path[id] = true
+			walk(child, indent+next, depth+1, path)
+			delete(path, id)
+		}
+	}
+
+	if len(root.Blockers) == 0 {
+		fmt.Fprintln(w, style.Dim.Render("  (no blockers)"))
+	} else {
+		fmt.Fprintln(w, style.Dim.Render("Blocked by:"))
+		walk(root, "", 0, map[string]bool{rootID: true})
+	}
+
+	if len(root.Blocks) > 0 {
+		fmt.Fprintln(w, style.Dim.Render("Blocks:"))
+		for _, id := range root.Blocks {
+			child := g.node(id)
+			fmt.Fprintf(w, "  %s\n", depsNodeLabel(child, child.Rig != root.Rig))
+		}
+	}
+}
+
+// depsNodesByRig groups the roots and every bead on an edge by rig, both
+// sorted. Beads loaded but not connected (e.g. unblocked rig beads) are left out.
+func depsNodesByRig(g *depsGraph, roots []string, edges []depsEdge) ([]string, map[string][]*depsNode) {
+	drawn := map[string]bool{}
+	for _, id := range roots {
+		drawn[id] = true
+	}
+	for _, e := range edges {
+		drawn[e.From] = true
+		drawn[e.To] = true
+	}
+	byRig := map[string][]*depsNode{}
+	for id := range drawn {
+		n := g.node(id)
+		byRig[n.Rig] = append(byRig[n.Rig], n)
+	}
+	rigs := make([]string, 0, len(byRig))
+	for rig, nodes := range byRig {
+		rigs = append(rigs, rig)
+		sort.Slice(nodes, func(a, b int) bool { return nodes[a].ID < nodes[b].ID })
+	}
+	sort.Strings(rigs)
+	return rigs, byRig
+}
+
+// depsStatusClass buckets a status for DOT and Mermaid coloring.
+func depsStatusClass(status string) string {
+	switch status {
+	case "closed", "tombstone":
+		return "closed"
+	case "unknown":
+		return "unknown"
+	default:
+		return "open"
+	}
+}
+
+// writeDepsDot emits the graph as Graphviz DOT, clustered by rig.
+func writeDepsDot(w io.Writer, g *depsGraph, roots []string, edges []depsEdge) error {
+	colors := map[string]string{"closed": "darkgreen", "unknown": "orange", "open": "red"}
+	rigs, byRig := depsNodesByRig(g, roots, edges)
+
+	var sb strings.Builder
+	sb.WriteString("digraph deps {\n  rankdir=LR;\n  node [shape=box, style=rounded];\n")
+	for i, rig := range rigs {
+		fmt.Fprintf(&sb, "  subgraph cluster_%d {\n    label=%q;\n", i, rig)
+		for _, n := range byRig[rig] {
+			label := n.ID + "\n" + n.Status
+			if n.Title != "" {
+				label += "\n" + n.Title
+			}
+			fmt.Fprintf(&sb, "    %q [label=%q, color=%s];\n", n.ID, label, colors[depsStatusClass(n.Status)])
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, e := range edges {
+		attrs := ""
+		if g.nodes[e.From].Rig != g.nodes[e.To].Rig {
+			attrs = " [style=dashed]"
+		}
+		fmt.Fprintf(&sb, "  %q -> %q%s;\n", e.From, e.To, attrs)
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeDepsMermaid emits the graph as a Mermaid flowchart with one subgraph
+// per rig. Bead IDs are mapped to n0, n1, ... since Mermaid treats "-" in
+// node IDs as edge syntax.
+func writeDepsMermaid(w io.Writer, g *depsGraph, roots []string, edges []depsEdge) error {
+	rigs, byRig := depsNodesByRig(g, roots, edges)
+	ids := map[string]string{}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for i, rig := range rigs {
+		fmt.Fprintf(&sb, "  subgraph rig%d[\"%s\"]\n", i, mermaidText(rig))
+		for _, n := range byRig[rig] {
+			id := fmt.Sprintf("n%d", len(ids))
+			ids[n.ID] = id
+			label := mermaidText(n.ID) + "<br/>" + mermaidText(n.Status)
+			if n.Title != "" {
+				label += "<br/>" + mermaidText(n.Title)
+			}
+			fmt.Fprintf(&sb, "    %s[\"%s\"]:::%s\n", id, label, depsStatusClass(n.Status))
+		}
+		sb.WriteString("  end\n")
+	}
+	for _, e := range edges {
+		arrow := "-->"
+		if g.nodes[e.From].Rig != g.nodes[e.To].Rig {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+	}
+	sb.WriteString("  classDef closed fill:#d4edda,stroke:#28a745\n")
+	sb.WriteString("  classDef open fill:#f8d7da,stroke:#dc3545\n")
+	sb.WriteString("  classDef unknown fill:#fff3cd,stroke:#fd7e14\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidText escapes characters that end or break a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
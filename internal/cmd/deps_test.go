@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseDepsEdge(t *testing.T) {
+	dependent, blocker, err := parseDepsEdge([]string{"gt-123", "blocks", "bd-456"})
+	if err != nil || dependent != "bd-456" || blocker != "gt-123" {
+		t.Errorf("blocks: got (%q, %q, %v)", dependent, blocker, err)
+	}
+	dependent, blocker, err = parseDepsEdge([]string{"bd-456", "depends-on", "gt-123"})
+	if err != nil || dependent != "bd-456" || blocker != "gt-123" {
+		t.Errorf("depends-on: got (%q, %q, %v)", dependent, blocker, err)
+	}
+	if _, _, err := parseDepsEdge([]string{"gt-1", "relates", "gt-2"}); err == nil {
+		t.Error("expected error for unknown relation")
+	}
+	if _, _, err := parseDepsEdge([]string{"gt-1", "blocks", "gt-1"}); err == nil {
+		t.Error("expected error for self-dependency")
+	}
+}
+
+func TestWriteDepsTree(t *testing.T) {
+	issues := map[string]*beads.Issue{
+		"bd-1": {ID: "bd-1", Title: "root", Status: "open", Dependencies: []beads.IssueDep{
+			{ID: "external:gt:gt-2", DependencyType: "blocks"},
+			{ID: "bd-3", DependencyType: "parent-child"},
+		}},
+		"gt-2": {ID: "gt-2", Title: "upstream", Status: "open", Dependencies: []beads.IssueDep{
+			{ID: "external:bd:bd-1", DependencyType: "blocks"},
+		}},
+	}
+	show := func(id string) (*beads.Issue, error) {
+		if issue, ok := issues[id]; ok {
+			return issue, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	var buf bytes.Buffer
+	writeDepsTree(&buf, newDepsGraph(t.TempDir(), show), "bd-1", 10)
+	out := buf.String()
+	for _, want := range []string{"bd-1", "gt-2", "upstream", "(cycle)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("tree output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "bd-3") {
+		t.Errorf("parent-child edge should not be shown as a blocker:\n%s", out)
+	}
+
+}
+
+func TestWriteDepsDotAndMermaid(t *testing.T) {
+	issues := map[string]*beads.Issue{
+		"gt-1": {ID: "gt-1", Title: `say "hi"`, Status: "open", Dependencies: []beads.IssueDep{
+			{ID: "gt-2", DependencyType: "blocks"},
+		}},
+		"gt-2": {ID: "gt-2", Title: "blocker", Status: "closed"},
+	}
+	show := func(id string) (*beads.Issue, error) {
+		if issue, ok := issues[id]; ok {
+			return issue, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	g := newDepsGraph(t.TempDir(), show)
+	roots := []string{"gt-1"}
+	edges := g.collect(roots, 10)
+	if len(edges) != 1 || edges[0] != (depsEdge{From: "gt-2", To: "gt-1"}) {
+		t.Fatalf("edges = %+v", edges)
+	}
+
+	var buf bytes.Buffer
+	if err := writeDepsDot(&buf, g, roots, edges); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, want := range []string{`"gt-2" -> "gt-1";`, "color=darkgreen", "color=red"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("dot output missing %q:\n%s", want, dot)
+		}
+	}
+
+	buf.Reset()
+	if err := writeDepsMermaid(&buf, g, roots, edges); err != nil {
+		t.Fatal(err)
+	}
+	mermaid := buf.String()
+	for _, want := range []string{"flowchart LR", "n1 --> n0", ":::closed", "#quot;hi#quot;"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid output missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestDepsRigRoots(t *testing.T) {
+	g := newDepsGraph(t.TempDir(), func(string) (*beads.Issue, error) { return nil, errors.New("unused") })
+	blocks := func(id string) []beads.IssueDep {
+		return []beads.IssueDep{{ID: id, DependencyType: "blocks"}}
+	}
+	roots := g.rigRoots([]*beads.Issue{
+		{ID: "gt-a", Status: "open"},
+		{ID: "gt-b", Status: "open", Dependencies: blocks("gt-a")},
+		{ID: "gt-c", Status: "open", Dependencies: blocks("gt-b")},
+		{ID: "gt-d", Status: "closed", Dependencies: blocks("gt-c")},
+		{ID: "gt-e", Status: "open"},
+	}, false)
+	if len(roots) != 1 || roots[0] != "gt-c" {
+		t.Errorf("roots = %v, want [gt-c]", roots)
+	}
+}
@@ -3,10 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -16,6 +19,7 @@ var (
 	doctorRig             string
 	doctorRestartSessions bool
 	doctorSlow            string
+	doctorSweepLocks      bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -52,6 +56,7 @@ Cleanup checks (fixable):
   - session-name-format      Detect sessions with outdated naming format (fixable)
   - wisp-gc                  Detect and clean abandoned wisps (>1h)
   - stale-beads-redirect     Detect stale files in .beads directories with redirects
+  - stale-locks              Detect daemon/Dolt PID and lock files left by crashes
 
 Clone divergence checks:
   - persistent-role-branches Detect crew/witness/refinery not on main
@@ -92,6 +97,7 @@ Dolt checks:
   - dolt-metadata            Check dolt metadata tables exist
   - dolt-server-reachable    Check dolt sql-server is reachable
   - dolt-orphaned-databases  Detect orphaned dolt databases
+  - dolt-schema              Detect schema drift between bd and rig databases
 
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
@@ -101,7 +107,10 @@ Patrol checks:
 
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+Use --sweep-locks to only sweep PID and lock files: every daemon/*.pid,
+daemon/*.lock and Dolt database LOCK file is checked for a live holder,
+stale ones are removed, and each is reported.`,
 	RunE: runDoctor,
 }
 
@@ -111,6 +120,7 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
+	doctorCmd.Flags().BoolVar(&doctorSweepLocks, "sweep-locks", false, "Only remove stale daemon/Dolt PID and lock files, reporting each")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
 	rootCmd.AddCommand(doctorCmd)
@@ -123,6 +133,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if doctorSweepLocks {
+		return runDoctorSweepLocks(townRoot)
+	}
+
 	// Create check context
 	ctx := &doctor.CheckContext{
 		TownRoot:        townRoot,
@@ -166,6 +180,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewWispGCCheck())
 	d.Register(doctor.NewCheckMisclassifiedWisps())
 	d.Register(doctor.NewStaleBeadsRedirectCheck())
+	d.Register(doctor.NewStaleLocksCheck())
 	d.Register(doctor.NewBeadsRedirectTargetCheck())
 	d.Register(doctor.NewBranchCheck())
 	d.Register(doctor.NewCloneDivergenceCheck())
@@ -226,6 +241,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewDoltMetadataCheck())
 	d.Register(doctor.NewDoltServerReachableCheck())
 	d.Register(doctor.NewDoltOrphanedDatabaseCheck())
+	d.Register(doctor.NewDoltSchemaCheck())
 
 	// Worktree gitdir validity (runs across all rigs, or specific rig with --rig)
 	d.Register(doctor.NewWorktreeGitdirCheck())
@@ -264,3 +280,51 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runDoctorSweepLocks removes stale PID and lock files and reports on every
+// artifact found.
+func runDoctorSweepLocks(townRoot string) error {
+	results := lock.Sweep(doctor.TownLockArtifacts(townRoot), true)
+	if len(results) == 0 {
+		fmt.Println("No PID or lock files found.")
+		return nil
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "FILE", Width: 40},
+		style.Column{Name: "KIND", Width: 5},
+		style.Column{Name: "STATUS", Width: 8},
+		style.Column{Name: "DETAIL", Width: 48},
+	)
+	removed, failed := 0, 0
+	for _, r := range results {
+		rel, err := filepath.Rel(townRoot, r.Path)
+		if err != nil {
+			rel = r.Path
+		}
+		status := style.Dim.Render("held")
+		detail := r.Reason
+		switch {
+		case r.Removed:
+			status = style.Success.Render("removed")
+			removed++
+		case r.Err != nil:
+			status = style.Error.Render("failed")
+			detail = r.Err.Error()
+			failed++
+		case r.Stale && r.Keep:
+			status = style.Dim.Render("kept")
+		}
+		tbl.AddRow(rel, r.Kind.String(), status, detail)
+	}
+	fmt.Print(tbl.Render())
+	fmt.Printf("\n  %d checked, %d removed", len(results), removed)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("could not remove %d stale file(s)", failed)
+	}
+	return nil
+}
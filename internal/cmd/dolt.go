@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -10,8 +13,12 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/preflight"
+	"github.com/steveyegge/gastown/internal/progress"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -57,7 +64,13 @@ var doltStartCmd = &cobra.Command{
 	Short: "Start the Dolt server",
 	Long: `Start the Dolt SQL server in the background.
 
-The server will run until stopped with 'gt dolt stop'.`,
+The server will run until stopped with 'gt dolt stop'.
+
+If the port (3307, or GT_DOLT_PORT) is held by a process that isn't dolt,
+start fails and names that process. With --auto-port, the next free port is
+used instead and recorded in daemon/dolt-state.json and each rig's
+metadata.json, so bd and connection strings follow it. The chosen port sticks
+for this town, which lets several towns share a machine.`,
 	RunE: runDoltStart,
 }
 
@@ -71,8 +84,23 @@ var doltStopCmd = &cobra.Command{
 var doltStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show Dolt server status",
-	Long:  `Show the current status of the Dolt SQL server.`,
-	RunE:  runDoltStatus,
+	Long: `Show the current status of the Dolt SQL server.
+
+With --repair, first reconcile daemon/dolt-state.json with the actual server:
+probe the port, verify the PID is a live dolt sql-server, re-list databases,
+and remove stale PID and lock files. Use this when status disagrees with
+reality (externally started servers, crashes).
+
+With --deep, also check every database: time of the last Dolt commit,
+branch count, row counts of the key beads tables, a write probe for
+read-only state, and whether its LOCK file is present. A running server
+holds each database's LOCK; with the server stopped, a LOCK is stale.
+
+Examples:
+  gt dolt status
+  gt dolt status --deep
+  gt dolt status --deep --json`,
+	RunE: runDoltStatus,
 }
 
 var doltLogsCmd = &cobra.Command{
@@ -85,10 +113,22 @@ var doltLogsCmd = &cobra.Command{
 var doltSQLCmd = &cobra.Command{
 	Use:   "sql",
 	Short: "Open Dolt SQL shell",
-	Long: `Open an interactive SQL shell to the Dolt database.
+	Long: `Open an interactive SQL shell to the Dolt database, or run SQL non-interactively.
 
 Works in both embedded mode (no server) and server mode.
-For multi-client access, start the server first with 'gt dolt start'.`,
+For multi-client access, start the server first with 'gt dolt start'.
+
+With --execute or --file, statements run over a pooled connection to the
+running server (falling back to embedded dolt when no server is running)
+and results are printed as a table, CSV, or JSON.
+
+Examples:
+  gt dolt sql                                   # Interactive shell
+  gt dolt sql --db gastown                      # Interactive shell on one rig database
+  gt dolt sql --db gastown -e "SELECT id, title FROM issues LIMIT 5"
+  gt dolt sql --db beads -f report.sql --format csv
+  echo "SHOW TABLES" | gt dolt sql --db hq -f - --format json`,
+	Args: cobra.NoArgs,
 	RunE: runDoltSQL,
 }
 
@@ -101,9 +141,17 @@ Each rig (e.g., gastown, beads) gets its own database that will be
 served by the Dolt server. The rig name becomes the database name
 when connecting via MySQL protocol.
 
+With --seed, the database is also made ready for bd: the beads schema,
+the Gas Town custom issue types, the issue prefix, the rig identity bead,
+and any issues from --seed-file are applied and read back before success
+is reported. If any step fails the new database is dropped. Seeding needs
+the Dolt server running and only applies to new databases.
+
 Example:
   gt dolt init-rig gastown
-  gt dolt init-rig beads`,
+  gt dolt init-rig beads
+  gt dolt init-rig myrig --seed --prefix mr
+  gt dolt init-rig myrig --seed --seed-file issues.jsonl`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDoltInitRig,
 }
@@ -116,8 +164,9 @@ var doltListCmd = &cobra.Command{
 }
 
 var doltMigrateCmd = &cobra.Command{
-	Use:   "migrate",
-	Short: "Migrate existing dolt databases to centralized data directory",
+	Use:         "migrate",
+	Annotations: auditAnnotations(""),
+	Short:       "Migrate existing dolt databases to centralized data directory",
 	Long: `Migrate existing dolt databases from .beads/dolt/ locations to the
 centralized .dolt-data/ directory structure.
 
@@ -126,16 +175,36 @@ This command will:
 2. Move them to .dolt-data/<rigname>/
 3. Remove the old empty directories
 
+Rigs registered in mayor/rigs.json are always checked. The town root's
+other subdirectories are scanned too; rig_discovery in mayor/config.json
+widens or narrows that scan for nested layouts such as clients/acme/rig:
+
+  "rig_discovery": {"scan_depth": 3, "include": ["clients/*/*"], "exclude": ["archive/*"]}
+
+Globs match paths relative to the town root, ignoring case. Database names
+are the rig directory's name and are compared ignoring case, so a rig is
+skipped when .dolt-data already has its database under another case.
+
 Use --dry-run to preview what would be moved (source/target paths and sizes)
 without making any changes.
 
+Before moving anything, a preflight checks that dolt is installed and that
+.dolt-data's filesystem has room for every database that must be copied
+rather than renamed, and aborts with a report if not (--skip-preflight
+bypasses it; --dry-run only reports).
+
+metadata.json is then updated for every rig in parallel, with a line per
+rig (updated, unchanged, or error). Use --json for a machine-readable
+summary of migrations, metadata results, and server startup.
+
 After migration, start the server with 'gt dolt start'.`,
 	RunE: runDoltMigrate,
 }
 
 var doltFixMetadataCmd = &cobra.Command{
-	Use:   "fix-metadata",
-	Short: "Update metadata.json in all rig .beads directories",
+	Use:         "fix-metadata",
+	Annotations: auditAnnotations(""),
+	Short:       "Update metadata.json in all rig .beads directories",
 	Long: `Ensure all rig .beads/metadata.json files have correct Dolt server configuration.
 
 This fixes the split-brain problem where bd falls back to local embedded databases
@@ -149,8 +218,9 @@ Safe to run multiple times (idempotent). Preserves any existing fields in metada
 }
 
 var doltRecoverCmd = &cobra.Command{
-	Use:   "recover",
-	Short: "Detect and recover from Dolt read-only state",
+	Use:         "recover",
+	Annotations: auditAnnotations(""),
+	Short:       "Detect and recover from Dolt read-only state",
 	Long: `Detect if the Dolt server is in read-only mode and attempt recovery.
 
 When the Dolt server enters read-only mode (e.g., from concurrent write
@@ -195,8 +265,9 @@ Examples:
 }
 
 var doltCleanupCmd = &cobra.Command{
-	Use:   "cleanup",
-	Short: "Remove orphaned databases from .dolt-data/",
+	Use:         "cleanup",
+	Annotations: auditAnnotations(""),
+	Short:       "Remove orphaned databases from .dolt-data/",
 	Long: `Detect and remove orphaned databases from the .dolt-data/ directory.
 
 An orphaned database is one that exists in .dolt-data/ but is not referenced
@@ -212,8 +283,9 @@ Examples:
 }
 
 var doltRollbackCmd = &cobra.Command{
-	Use:   "rollback [backup-dir]",
-	Short: "Restore .beads directories from a migration backup",
+	Use:         "rollback [backup-dir]",
+	Annotations: auditAnnotations(""),
+	Short:       "Restore .beads directories from a migration backup",
 	Long: `Roll back a migration by restoring .beads directories from a backup.
 
 If no backup directory is specified, the most recent migration-backup-TIMESTAMP/
@@ -236,6 +308,8 @@ var (
 	doltLogLines     int
 	doltLogFollow    bool
 	doltMigrateDry   bool
+	doltMigrateJSON  bool
+	doltMigrateNoPre bool
 	doltCleanupDry   bool
 	doltRollbackDry  bool
 	doltRollbackList bool
@@ -243,6 +317,15 @@ var (
 	doltSyncForce    bool
 	doltSyncDB       string
 	doltSyncGC       bool
+	doltStatusRepair bool
+	doltStatusDeep   bool
+	doltStatusJSON   bool
+	doltStartAuto    bool
+
+	doltInitRigSeed     bool
+	doltInitRigPrefix   string
+	doltInitRigRepo     string
+	doltInitRigSeedFile string
 )
 
 func init() {
@@ -263,10 +346,23 @@ func init() {
 
 	doltCleanupCmd.Flags().BoolVar(&doltCleanupDry, "dry-run", false, "Preview what would be removed without making changes")
 
+	doltInitRigCmd.Flags().BoolVar(&doltInitRigSeed, "seed", false, "Apply the beads schema and seed data, verified, or roll back")
+	doltInitRigCmd.Flags().StringVar(&doltInitRigPrefix, "prefix", "", "Issue prefix for --seed (default: the rig's configured prefix)")
+	doltInitRigCmd.Flags().StringVar(&doltInitRigRepo, "repo", "", "Git URL recorded on the rig identity bead (with --seed)")
+	doltInitRigCmd.Flags().StringVar(&doltInitRigSeedFile, "seed-file", "", "JSONL issues to import (implies --seed)")
+
+	doltStartCmd.Flags().BoolVar(&doltStartAuto, "auto-port", false, "Use the next free port if the configured one is taken by another process")
+
+	doltStatusCmd.Flags().BoolVar(&doltStatusRepair, "repair", false, "Reconcile the state file with the running server before reporting")
+	doltStatusCmd.Flags().BoolVar(&doltStatusDeep, "deep", false, "Report per-database health (commits, branches, row counts, read-only, locks)")
+	doltStatusCmd.Flags().BoolVar(&doltStatusJSON, "json", false, "Output as JSON")
+
 	doltLogsCmd.Flags().IntVarP(&doltLogLines, "lines", "n", 50, "Number of lines to show")
 	doltLogsCmd.Flags().BoolVarP(&doltLogFollow, "follow", "f", false, "Follow log output")
 
 	doltMigrateCmd.Flags().BoolVar(&doltMigrateDry, "dry-run", false, "Preview what would be migrated without making changes")
+	doltMigrateCmd.Flags().BoolVar(&doltMigrateJSON, "json", false, "Output a JSON summary instead of progress")
+	doltMigrateCmd.Flags().BoolVar(&doltMigrateNoPre, "skip-preflight", false, "Skip the dolt and free-disk checks")
 
 	doltRollbackCmd.Flags().BoolVar(&doltRollbackDry, "dry-run", false, "Show what would be restored without making changes")
 	doltRollbackCmd.Flags().BoolVar(&doltRollbackList, "list", false, "List available backups and exit")
@@ -298,7 +394,7 @@ func runDoltStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no databases found in %s\nInitialize with: gt dolt init-rig <name>", config.DataDir)
 	}
 
-	if err := doltserver.Start(townRoot); err != nil {
+	if err := doltserver.StartWithOptions(townRoot, doltserver.StartOptions{AutoPort: doltStartAuto}); err != nil {
 		return err
 	}
 
@@ -306,7 +402,7 @@ func runDoltStart(cmd *cobra.Command, args []string) error {
 	state, _ := doltserver.LoadState(townRoot)
 
 	fmt.Printf("%s Dolt server started (PID %d, port %d)\n",
-		style.Bold.Render("✓"), state.PID, config.Port)
+		style.Bold.Render("✓"), state.PID, state.Port)
 	fmt.Printf("  Data dir: %s\n", state.DataDir)
 	fmt.Printf("  Databases: %s\n", style.Dim.Render(strings.Join(state.Databases, ", ")))
 	fmt.Printf("  Connection: %s\n", style.Dim.Render(doltserver.GetConnectionString(townRoot)))
@@ -328,6 +424,8 @@ func runDoltStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s All %d databases verified\n", style.Bold.Render("✓"), len(served))
 	}
 
+	warnSchemaDrift(townRoot)
+
 	return nil
 }
 
@@ -358,12 +456,29 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if doltStatusRepair {
+		if doltStatusJSON {
+			return fmt.Errorf("--repair can't be combined with --json")
+		}
+		if err := runDoltStatusRepair(townRoot); err != nil {
+			return err
+		}
+	}
+
 	running, pid, err := doltserver.IsRunning(townRoot)
 	if err != nil {
 		return fmt.Errorf("checking server status: %w", err)
 	}
 
 	config := doltserver.DefaultConfig(townRoot)
+	getMetrics := doltserver.GetHealthMetrics
+	if doltStatusDeep {
+		getMetrics = doltserver.GetDeepHealthMetrics
+	}
+
+	if doltStatusJSON {
+		return printDoltStatusJSON(townRoot, running, pid, getMetrics)
+	}
 
 	if config.IsRemote() {
 		if running {
@@ -379,7 +494,7 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("  Connection: %s\n", doltserver.GetConnectionString(townRoot))
 		if running {
-			metrics := doltserver.GetHealthMetrics(townRoot)
+			metrics := getMetrics(townRoot)
 			fmt.Printf("\n  %s\n", style.Bold.Render("Resource Metrics:"))
 			fmt.Printf("    Query latency: %v\n", metrics.QueryLatency.Round(time.Millisecond))
 			fmt.Printf("    Connections:   %d / %d (%.0f%%)\n",
@@ -389,6 +504,9 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 					style.Bold.Render("!!!"),
 					style.Bold.Render("SERVER IS READ-ONLY — contact the remote server admin"))
 			}
+			if doltStatusDeep {
+				printDatabaseHealth(metrics.Databases)
+			}
 		}
 		return nil
 	}
@@ -415,7 +533,7 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 		}
 
 		// Resource metrics
-		metrics := doltserver.GetHealthMetrics(townRoot)
+		metrics := getMetrics(townRoot)
 		fmt.Printf("\n  %s\n", style.Bold.Render("Resource Metrics:"))
 		fmt.Printf("    Query latency: %v\n", metrics.QueryLatency.Round(time.Millisecond))
 		fmt.Printf("    Connections:   %d / %d (%.0f%%)\n",
@@ -426,6 +544,9 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 				style.Bold.Render("!!!"),
 				style.Bold.Render("SERVER IS READ-ONLY — run 'gt dolt recover' to restart"))
 		}
+		if doltStatusDeep {
+			printDatabaseHealth(metrics.Databases)
+		}
 
 		// Verify all filesystem databases are actually served.
 		_, missing, verifyErr := doltserver.VerifyDatabases(townRoot)
@@ -462,6 +583,11 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 			style.Dim.Render("○"),
 			"not running")
 
+		// A foreign process on the port would make the next start fail.
+		if err := doltserver.CheckPortConflict(townRoot); err != nil {
+			fmt.Printf("\n%s %v\n", style.Bold.Render("!"), err)
+		}
+
 		// List available databases
 		databases, _ := doltserver.ListDatabases(townRoot)
 		if len(databases) == 0 {
@@ -470,14 +596,172 @@ func runDoltStatus(cmd *cobra.Command, args []string) error {
 				config.DataDir)
 			fmt.Printf("  Initialize with: %s\n", style.Dim.Render("gt dolt init-rig <name>"))
 		} else {
+			stale := map[string]bool{}
+			if doltStatusDeep {
+				for _, h := range doltserver.CollectDatabaseHealth(context.Background(), townRoot, nil) {
+					stale[h.Name] = h.LockFile
+				}
+			}
 			fmt.Printf("\nAvailable databases in %s:\n", config.DataDir)
 			for _, db := range databases {
-				fmt.Printf("  - %s\n", db)
+				if stale[db] {
+					fmt.Printf("  - %s %s\n", db, style.Warning.Render("(stale LOCK file)"))
+				} else {
+					fmt.Printf("  - %s\n", db)
+				}
 			}
 			fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt dolt start"))
 		}
 	}
 
+	printFallbackRigs(townRoot, running)
+	if journal, _ := beads.UnappliedJournal(townRoot); len(journal) > 0 {
+		fmt.Printf("\n  %s %d bead mutation(s) journaled while the server was unreachable\n", style.Warning.Render("!"), len(journal))
+		fmt.Printf("  Review with: %s\n", style.Dim.Render("gt dolt journal status"))
+	}
+	return nil
+}
+
+// printFallbackRigs lists rigs on a local fallback snapshot, if any.
+func printFallbackRigs(townRoot string, running bool) {
+	rigs, _ := doltserver.FallbackRigs(townRoot)
+	if len(rigs) == 0 {
+		return
+	}
+	fmt.Printf("\n  %s %d rig(s) on a fallback snapshot (dolt_fallback):\n", style.Warning.Render("!"), len(rigs))
+	for _, r := range rigs {
+		fmt.Printf("    - %s (%s since %s)\n", r.Rig, r.Mode, r.Since.Local().Format("2006-01-02 15:04:05"))
+	}
+	if running {
+		fmt.Printf("  Merge back with: %s\n", style.Dim.Render("gt dolt reconcile"))
+	}
+}
+
+// doltStatusOutput is the --json shape of gt dolt status.
+type doltStatusOutput struct {
+	Running    bool                      `json:"running"`
+	PID        int                       `json:"pid,omitempty"`
+	Remote     bool                      `json:"remote,omitempty"`
+	Connection string                    `json:"connection"`
+	Metrics    *doltserver.HealthMetrics `json:"metrics,omitempty"`
+
+	// Databases is set while the server is down: the databases on disk,
+	// with lock file state when --deep is given.
+	Databases []doltserver.DatabaseHealth `json:"databases,omitempty"`
+
+	// Fallback lists rigs on a local snapshot until gt dolt reconcile.
+	Fallback []doltserver.FallbackRig `json:"fallback,omitempty"`
+
+	// JournalPending counts bead mutations awaiting gt dolt journal apply.
+	JournalPending int `json:"journal_pending,omitempty"`
+}
+
+func printDoltStatusJSON(townRoot string, running bool, pid int, getMetrics func(string) *doltserver.HealthMetrics) error {
+	out := doltStatusOutput{
+		Running:    running,
+		PID:        pid,
+		Remote:     doltserver.DefaultConfig(townRoot).IsRemote(),
+		Connection: doltserver.GetConnectionString(townRoot),
+	}
+	if running {
+		out.Metrics = getMetrics(townRoot)
+	} else if doltStatusDeep {
+		out.Databases = doltserver.CollectDatabaseHealth(context.Background(), townRoot, nil)
+	} else {
+		databases, _ := doltserver.ListDatabases(townRoot)
+		for _, db := range databases {
+			out.Databases = append(out.Databases, doltserver.DatabaseHealth{Name: db})
+		}
+	}
+	out.Fallback, _ = doltserver.FallbackRigs(townRoot)
+	if journal, _ := beads.UnappliedJournal(townRoot); len(journal) > 0 {
+		out.JournalPending = len(journal)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// printDatabaseHealth prints the --deep per-database table.
+func printDatabaseHealth(databases []doltserver.DatabaseHealth) {
+	fmt.Printf("\n  %s\n", style.Bold.Render("Databases:"))
+	if len(databases) == 0 {
+		fmt.Printf("    %s\n", style.Dim.Render("none found"))
+		return
+	}
+	var header strings.Builder
+	fmt.Fprintf(&header, "    %-16s %9s  %-16s %8s", "NAME", "SIZE", "LAST COMMIT", "BRANCHES")
+	for _, table := range doltserver.HealthKeyTables {
+		fmt.Fprintf(&header, " %*s", healthColumnWidth(table), strings.ToUpper(table))
+	}
+	fmt.Printf("%s  %-4s  %s\n", header.String(), "LOCK", "STATUS")
+	for _, d := range databases {
+		size := "-"
+		if d.SizeBytes > 0 {
+			size = formatBytes(d.SizeBytes)
+		}
+		lastCommit, branches := "-", "-"
+		if d.Probed {
+			branches = fmt.Sprintf("%d", d.Branches)
+			if !d.LastCommit.IsZero() {
+				lastCommit = formatAge(d.LastCommit)
+			}
+		}
+		var row strings.Builder
+		fmt.Fprintf(&row, "    %-16s %9s  %-16s %8s", d.Name, size, lastCommit, branches)
+		for _, table := range doltserver.HealthKeyTables {
+			count := "-"
+			if n, ok := d.RowCounts[table]; ok {
+				count = fmt.Sprintf("%d", n)
+			}
+			fmt.Fprintf(&row, " %*s", healthColumnWidth(table), count)
+		}
+		lock := "no"
+		if d.LockFile {
+			lock = "yes"
+		}
+		status := style.Success.Render("ok")
+		switch {
+		case d.ReadOnly:
+			status = style.Error.Render("READ-ONLY")
+		case len(d.Errors) > 0:
+			status = style.Warning.Render("degraded")
+		case !d.Probed:
+			status = style.Dim.Render("not probed")
+		}
+		fmt.Printf("%s  %-4s  %s\n", row.String(), lock, status)
+		for _, e := range d.Errors {
+			fmt.Printf("    %-16s %s\n", "", style.Dim.Render(e))
+		}
+	}
+}
+
+// healthColumnWidth is the width of a row count column in printDatabaseHealth.
+func healthColumnWidth(table string) int {
+	return max(len(table), 7)
+}
+
+// runDoltStatusRepair reconciles dolt-state.json and prints what changed.
+func runDoltStatusRepair(townRoot string) error {
+	report, err := doltserver.ReconcileState(townRoot)
+	if err != nil {
+		return fmt.Errorf("reconciling state: %w", err)
+	}
+	if !report.Changed() {
+		fmt.Printf("%s State file matches the server\n", style.Success.Render("✓"))
+	} else {
+		fmt.Printf("%s Reconciled state file\n", style.Success.Render("✓"))
+		for _, c := range report.Changes {
+			fmt.Printf("    %s\n", c)
+		}
+		for _, f := range report.RemovedFiles {
+			fmt.Printf("    removed %s\n", f)
+		}
+	}
+	for _, w := range report.Warnings {
+		fmt.Printf("  %s %s\n", style.Warning.Render("!"), w)
+	}
+	fmt.Println()
 	return nil
 }
 
@@ -508,71 +792,21 @@ func runDoltLogs(cmd *cobra.Command, args []string) error {
 	return tailCmd.Run()
 }
 
-func runDoltSQL(cmd *cobra.Command, args []string) error {
+func runDoltInitRig(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	config := doltserver.DefaultConfig(townRoot)
-
-	// Check if server is running - if so, connect via Dolt SQL client
-	running, _, _ := doltserver.IsRunning(townRoot)
-	if running {
-		// Connect to running server using dolt sql client
-		// Using --no-tls since server doesn't have TLS configured
-		host := config.Host
-		if host == "" {
-			host = "127.0.0.1"
-		}
-		sqlArgs := []string{
-			"--host", host,
-			"--port", strconv.Itoa(config.Port),
-			"--user", config.User,
-			"--no-tls",
-			"sql",
-		}
-		sqlCmd := exec.Command("dolt", sqlArgs...)
-		if config.Password != "" {
-			sqlCmd.Env = append(os.Environ(), "DOLT_CLI_PASSWORD="+config.Password)
-		}
-		sqlCmd.Stdin = os.Stdin
-		sqlCmd.Stdout = os.Stdout
-		sqlCmd.Stderr = os.Stderr
-		return sqlCmd.Run()
-	}
-
-	// Server not running - list databases and pick first one for embedded mode
-	databases, err := doltserver.ListDatabases(townRoot)
-	if err != nil {
-		return fmt.Errorf("listing databases: %w", err)
-	}
+	rigName := args[0]
 
-	if len(databases) == 0 {
-		return fmt.Errorf("no databases found in %s\nInitialize with: gt dolt init-rig <name>", config.DataDir)
+	if doltInitRigSeed || doltInitRigSeedFile != "" {
+		return runDoltInitRigSeeded(townRoot, rigName)
 	}
-
-	// Use first database for embedded SQL shell
-	dbDir := doltserver.RigDatabaseDir(townRoot, databases[0])
-	fmt.Printf("Using database: %s (start server with 'gt dolt start' for multi-database access)\n\n", databases[0])
-
-	sqlCmd := exec.Command("dolt", "sql")
-	sqlCmd.Dir = dbDir
-	sqlCmd.Stdin = os.Stdin
-	sqlCmd.Stdout = os.Stdout
-	sqlCmd.Stderr = os.Stderr
-
-	return sqlCmd.Run()
-}
-
-func runDoltInitRig(cmd *cobra.Command, args []string) error {
-	townRoot, err := workspace.FindFromCwdOrError()
-	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	if doltInitRigPrefix != "" || doltInitRigRepo != "" {
+		return fmt.Errorf("--prefix and --repo only apply with --seed")
 	}
 
-	rigName := args[0]
-
 	serverWasRunning, created, err := doltserver.InitRig(townRoot, rigName)
 	if err != nil {
 		return err
@@ -600,6 +834,28 @@ func runDoltInitRig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runDoltInitRigSeeded(townRoot, rigName string) error {
+	prefix := doltInitRigPrefix
+	if prefix == "" {
+		prefix = config.GetRigPrefix(townRoot, rigName)
+	}
+
+	result, err := doltserver.InitRigSeeded(townRoot, rigName, doltserver.SeedOptions{
+		Prefix:   prefix,
+		Repo:     doltInitRigRepo,
+		SeedFile: doltInitRigSeedFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Initialized and seeded rig database %q\n", style.Bold.Render("✓"), rigName)
+	fmt.Printf("  Location: %s\n", doltserver.RigDatabaseDir(townRoot, rigName))
+	fmt.Printf("  Beads:    %s (prefix: %s)\n", result.BeadsDir, prefix)
+	fmt.Printf("  Rig bead: %s\n", result.RigBead)
+	return nil
+}
+
 func runDoltInit(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -770,58 +1026,100 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Dolt server is running. Stop it first with: gt dolt stop")
 	}
 
+	// With --json, human-readable progress is discarded and a summary is
+	// printed on return (including when returning an error).
+	out := io.Writer(os.Stdout)
+	summary := doltMigrateSummary{DryRun: doltMigrateDry, Migrations: []doltMigrateEntry{}, Metadata: []doltserver.MetadataResult{}}
+	if doltMigrateJSON {
+		out = io.Discard
+		defer func() {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(summary)
+		}()
+	}
+
 	// Find databases to migrate
 	migrations := doltserver.FindMigratableDatabases(townRoot)
+	for _, m := range migrations {
+		summary.Migrations = append(summary.Migrations, doltMigrateEntry{Rig: m.RigName, Source: m.SourcePath, Target: m.TargetPath})
+	}
 	if len(migrations) == 0 {
-		fmt.Println("No databases found to migrate.")
+		fmt.Fprintln(out, "No databases found to migrate.")
 		return nil
 	}
 
-	fmt.Printf("Found %d database(s) to migrate:\n\n", len(migrations))
+	fmt.Fprintf(out, "Found %d database(s) to migrate:\n\n", len(migrations))
 	for _, m := range migrations {
 		sizeStr := dirSizeHuman(m.SourcePath)
-		fmt.Printf("  %s (%s)\n", m.SourcePath, sizeStr)
-		fmt.Printf("    → %s\n\n", m.TargetPath)
+		fmt.Fprintf(out, "  %s (%s)\n", m.SourcePath, sizeStr)
+		fmt.Fprintf(out, "    → %s\n\n", m.TargetPath)
+	}
+
+	if !doltMigrateNoPre {
+		report := doltMigratePreflight(townRoot, migrations)
+		summary.Preflight = report
+		printPreflight(out, report)
+		fmt.Fprintln(out)
+		if err := report.Err(); err != nil && !doltMigrateDry {
+			return err
+		}
 	}
 
 	if doltMigrateDry {
-		fmt.Println("Dry run: no changes made.")
+		fmt.Fprintln(out, "Dry run: no changes made.")
 		return nil
 	}
 
 	// Perform migrations
-	for _, m := range migrations {
-		fmt.Printf("Migrating %s...\n", m.RigName)
-		if err := doltserver.MigrateRigFromBeads(townRoot, m.RigName, m.SourcePath); err != nil {
+	for i, m := range migrations {
+		fmt.Fprintf(out, "Migrating %s (%d/%d)...\n", m.RigName, i+1, len(migrations))
+		bar := progress.New(out, "Copying", 0, progress.Bytes)
+		err := doltserver.MigrateRigFromBeadsWithProgress(townRoot, m.RigName, m.SourcePath, bar)
+		bar.Finish()
+		if err != nil {
 			return fmt.Errorf("migrating %s: %w", m.RigName, err)
 		}
-		fmt.Printf("  %s Migrated to %s\n", style.Bold.Render("✓"), m.TargetPath)
+		summary.Migrations[i].Migrated = true
+		fmt.Fprintf(out, "  %s Migrated to %s\n", style.Bold.Render("✓"), m.TargetPath)
+
+		// Rigs in nested layouts keep their metadata.json in their own
+		// .beads, which the by-name update below doesn't look for.
+		if m.RigPath != "" && m.RigPath != filepath.Join(townRoot, m.RigName) {
+			r := doltserver.MetadataResult{Rig: m.RigName, Status: doltserver.MetadataUpdated}
+			if err := doltserver.EnsureMetadataIn(townRoot, m.RigName, beads.ResolveBeadsDir(m.RigPath)); err != nil {
+				r.Status, r.Error = doltserver.MetadataError, err.Error()
+				fmt.Fprintf(out, "  %s metadata.json in %s: %v\n", style.Dim.Render("⚠"), m.RigPath, err)
+			}
+			summary.Metadata = append(summary.Metadata, r)
+		}
 	}
 
-	// Update metadata.json for all migrated rigs
-	updated, metaErrs := doltserver.EnsureAllMetadata(townRoot)
-	if len(updated) > 0 {
-		fmt.Printf("\nUpdated metadata.json for: %s\n", strings.Join(updated, ", "))
-	}
-	for _, err := range metaErrs {
-		fmt.Printf("  %s metadata.json update failed: %v\n", style.Dim.Render("⚠"), err)
+	// Update metadata.json for all rigs
+	fmt.Fprintf(out, "\nUpdating metadata.json...\n")
+	results, err := ensureAllMetadataWithProgress(out, townRoot)
+	if err != nil {
+		fmt.Fprintf(out, "  %s metadata.json update failed: %v\n", style.Dim.Render("⚠"), err)
 	}
+	summary.Metadata = append(summary.Metadata, results...)
 
-	fmt.Printf("\n%s Migration complete.\n", style.Bold.Render("✓"))
+	fmt.Fprintf(out, "\n%s Migration complete.\n", style.Bold.Render("✓"))
 
 	// Auto-start the Dolt server to prevent split-brain risk.
 	// If bd commands are run before the server starts, they may silently create
 	// isolated local databases instead of connecting to the centralized server.
-	fmt.Printf("\nStarting Dolt server to prevent split-brain risk...\n")
+	fmt.Fprintf(out, "\nStarting Dolt server to prevent split-brain risk...\n")
 	if err := doltserver.Start(townRoot); err != nil {
-		fmt.Printf("\n%s Could not auto-start Dolt server: %v\n", style.Bold.Render("⚠"), err)
-		fmt.Printf("\n%s WARNING: Do NOT run bd commands until the server is started!\n", style.Bold.Render("⚠"))
-		fmt.Printf("  Running bd before 'gt dolt start' risks split-brain: bd may create an\n")
-		fmt.Printf("  isolated local database instead of connecting to the centralized server.\n")
-		fmt.Printf("\n  Start manually with: %s\n", style.Dim.Render("gt dolt start"))
+		summary.ServerError = err.Error()
+		fmt.Fprintf(out, "\n%s Could not auto-start Dolt server: %v\n", style.Bold.Render("⚠"), err)
+		fmt.Fprintf(out, "\n%s WARNING: Do NOT run bd commands until the server is started!\n", style.Bold.Render("⚠"))
+		fmt.Fprintf(out, "  Running bd before 'gt dolt start' risks split-brain: bd may create an\n")
+		fmt.Fprintf(out, "  isolated local database instead of connecting to the centralized server.\n")
+		fmt.Fprintf(out, "\n  Start manually with: %s\n", style.Dim.Render("gt dolt start"))
 	} else {
+		summary.ServerStarted = true
 		state, _ := doltserver.LoadState(townRoot)
-		fmt.Printf("%s Dolt server started (PID %d)\n", style.Bold.Render("✓"), state.PID)
+		fmt.Fprintf(out, "%s Dolt server started (PID %d)\n", style.Bold.Render("✓"), state.PID)
 
 		// Verify the server is actually serving all databases that exist on disk.
 		// Dolt silently skips databases with stale manifests after migration,
@@ -829,23 +1127,24 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 		// Use retry since the server may still be loading databases after Start().
 		served, missing, verifyErr := doltserver.VerifyDatabasesWithRetry(townRoot, 5)
 		if verifyErr != nil {
-			fmt.Printf("  %s Could not verify databases: %v\n", style.Dim.Render("⚠"), verifyErr)
-			fmt.Printf("  Migration may be incomplete. Verify manually with: %s\n", style.Dim.Render("gt dolt status"))
+			fmt.Fprintf(out, "  %s Could not verify databases: %v\n", style.Dim.Render("⚠"), verifyErr)
+			fmt.Fprintf(out, "  Migration may be incomplete. Verify manually with: %s\n", style.Dim.Render("gt dolt status"))
 			return fmt.Errorf("database verification failed after migration: %w", verifyErr)
 		} else if len(missing) > 0 {
-			fmt.Printf("\n%s Some databases exist on disk but are NOT served by Dolt:\n", style.Bold.Render("⚠"))
+			summary.Unserved = missing
+			fmt.Fprintf(out, "\n%s Some databases exist on disk but are NOT served by Dolt:\n", style.Bold.Render("⚠"))
 			for _, db := range missing {
-				fmt.Printf("  - %s\n", db)
+				fmt.Fprintf(out, "  - %s\n", db)
 			}
-			fmt.Printf("\n  Served databases: %v\n", served)
-			fmt.Printf("\n  This usually means the database has a stale manifest from migration.\n")
-			fmt.Printf("  To fix, try:\n")
-			fmt.Printf("    1. Stop the server:  %s\n", style.Dim.Render("gt dolt stop"))
-			fmt.Printf("    2. Repair the DB:    %s\n", style.Dim.Render("cd ~/gt/.dolt-data/<db> && dolt fsck --repair"))
-			fmt.Printf("    3. Restart:           %s\n", style.Dim.Render("gt dolt start"))
+			fmt.Fprintf(out, "\n  Served databases: %v\n", served)
+			fmt.Fprintf(out, "\n  This usually means the database has a stale manifest from migration.\n")
+			fmt.Fprintf(out, "  To fix, try:\n")
+			fmt.Fprintf(out, "    1. Stop the server:  %s\n", style.Dim.Render("gt dolt stop"))
+			fmt.Fprintf(out, "    2. Repair the DB:    %s\n", style.Dim.Render("cd ~/gt/.dolt-data/<db> && dolt fsck --repair"))
+			fmt.Fprintf(out, "    3. Restart:           %s\n", style.Dim.Render("gt dolt start"))
 			return fmt.Errorf("migration incomplete: %d database(s) exist on disk but are not served: %v", len(missing), missing)
 		} else {
-			fmt.Printf("  %s All %d databases verified as served\n", style.Bold.Render("✓"), len(served))
+			fmt.Fprintf(out, "  %s All %d databases verified as served\n", style.Bold.Render("✓"), len(served))
 		}
 	}
 
@@ -854,17 +1153,47 @@ func runDoltMigrate(cmd *cobra.Command, args []string) error {
 
 // dirSizeHuman returns a human-readable size string for a directory tree.
 func dirSizeHuman(path string) string {
-	var total int64
-	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // skip errors
-		}
-		if !info.IsDir() {
-			total += info.Size()
+	return formatBytes(progress.DirSize(path))
+}
+
+// doltMigrateSummary is the --json output of gt dolt migrate.
+type doltMigrateSummary struct {
+	DryRun        bool                        `json:"dry_run"`
+	Migrations    []doltMigrateEntry          `json:"migrations"`
+	Metadata      []doltserver.MetadataResult `json:"metadata"`
+	ServerStarted bool                        `json:"server_started"`
+	ServerError   string                      `json:"server_error,omitempty"`
+	Unserved      []string                    `json:"unserved_databases,omitempty"`
+	Preflight     *preflight.Report           `json:"preflight,omitempty"`
+}
+
+// doltMigrateEntry is one database found by gt dolt migrate.
+type doltMigrateEntry struct {
+	Rig      string `json:"rig"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Migrated bool   `json:"migrated"`
+}
+
+// ensureAllMetadataWithProgress updates every rig's metadata.json in
+// parallel, printing a line to out as each rig finishes.
+func ensureAllMetadataWithProgress(out io.Writer, townRoot string) ([]doltserver.MetadataResult, error) {
+	done := 0
+	var total int
+	if databases, err := doltserver.ListDatabases(townRoot); err == nil {
+		total = len(databases)
+	}
+	return doltserver.EnsureAllMetadataParallel(townRoot, doltserver.DefaultMetadataWorkers, func(r doltserver.MetadataResult) {
+		done++
+		switch r.Status {
+		case doltserver.MetadataUpdated:
+			fmt.Fprintf(out, "  [%d/%d] %s %s updated\n", done, total, style.Bold.Render("✓"), r.Rig)
+		case doltserver.MetadataUnchanged:
+			fmt.Fprintf(out, "  [%d/%d] %s %s unchanged\n", done, total, style.Dim.Render("-"), r.Rig)
+		default:
+			fmt.Fprintf(out, "  [%d/%d] %s %s: %s\n", done, total, style.Dim.Render("⚠"), r.Rig, r.Error)
 		}
-		return nil
 	})
-	return formatBytes(total)
 }
 
 func runDoltFixMetadata(cmd *cobra.Command, args []string) error {
@@ -873,25 +1202,22 @@ func runDoltFixMetadata(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	updated, errs := doltserver.EnsureAllMetadata(townRoot)
-
-	if len(updated) > 0 {
-		fmt.Printf("%s Updated metadata.json for %d rig(s):\n", style.Bold.Render("✓"), len(updated))
-		for _, name := range updated {
-			fmt.Printf("  - %s\n", name)
-		}
+	results, err := ensureAllMetadataWithProgress(os.Stdout, townRoot)
+	if err != nil {
+		fmt.Printf("  %s %v\n", style.Dim.Render("⚠"), err)
+		return nil
 	}
-
-	if len(errs) > 0 {
-		fmt.Println()
-		for _, err := range errs {
-			fmt.Printf("  %s %v\n", style.Dim.Render("⚠"), err)
-		}
+	if len(results) == 0 {
+		fmt.Println("No rig databases found. Nothing to update.")
+		return nil
 	}
 
-	if len(updated) == 0 && len(errs) == 0 {
-		fmt.Println("No rig databases found. Nothing to update.")
+	counts := make(map[doltserver.MetadataStatus]int)
+	for _, r := range results {
+		counts[r.Status]++
 	}
+	fmt.Printf("\n%s metadata.json: %d updated, %d unchanged, %d failed\n", style.Bold.Render("✓"),
+		counts[doltserver.MetadataUpdated], counts[doltserver.MetadataUnchanged], counts[doltserver.MetadataError])
 
 	return nil
 }
@@ -1012,7 +1338,7 @@ func runDoltRollback(cmd *cobra.Command, args []string) error {
 
 	// Perform the rollback
 	fmt.Println("\nRestoring from backup...")
-	result, err := doltserver.RestoreFromBackup(townRoot, backupPath)
+	result, err := doltserver.RestoreFromBackupWithProgress(townRoot, backupPath, progress.New(os.Stdout, "Restoring", 0, progress.Bytes))
 	if err != nil {
 		return fmt.Errorf("rollback failed: %w", err)
 	}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltBranchesStale string
+	doltBranchesPrune bool
+	doltBranchesForce bool
+	doltBranchesDry   bool
+	doltBranchesJSON  bool
+)
+
+var doltBranchesCmd = &cobra.Command{
+	Use:         "branches <rig> [branch]",
+	Annotations: auditAnnotations("prune"),
+	Short:       "List, inspect, and prune a rig's Dolt branches",
+	Long: `Manage the per-polecat Dolt branches in a rig database.
+
+Each polecat writes to its own branch (polecat-<name>-<timestamp>), which
+gt done merges into main and deletes. Branches from crashed or nuked
+polecats are left behind; this command finds and removes them.
+
+With just a rig, lists every branch besides main with its last activity,
+commits not yet on main, and whether it has uncommitted changes.
+With a branch, shows those unmerged commits, a per-table diff against
+main, and which rows would conflict if it were merged.
+
+--stale limits the list to branches idle longer than the given age.
+--prune deletes the stale branches (default age 7d). Branches with
+unmerged commits or uncommitted changes are skipped unless --force:
+deleting them discards bead writes that never reached main.
+
+Examples:
+  gt dolt branches gastown
+  gt dolt branches gastown polecat-toast-1767225600
+  gt dolt branches gastown --stale 3d
+  gt dolt branches gastown --prune --dry-run
+  gt dolt branches gastown --prune --stale 14d --force`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDoltBranches,
+}
+
+func init() {
+	doltBranchesCmd.Flags().StringVar(&doltBranchesStale, "stale", "", "Only branches idle longer than this (e.g. 7d, 48h); --prune defaults to 7d")
+	doltBranchesCmd.Flags().BoolVar(&doltBranchesPrune, "prune", false, "Delete stale branches")
+	doltBranchesCmd.Flags().BoolVar(&doltBranchesForce, "force", false, "With --prune, also delete branches with unmerged changes")
+	doltBranchesCmd.Flags().BoolVar(&doltBranchesDry, "dry-run", false, "With --prune, show what would be deleted")
+	doltBranchesCmd.Flags().BoolVar(&doltBranchesJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltBranchesCmd)
+}
+
+// defaultBranchStaleAge is the idle age --prune uses without --stale.
+const defaultBranchStaleAge = 7 * 24 * time.Hour
+
+func runDoltBranches(cmd *cobra.Command, args []string) error {
+	rigDB := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing databases: %w", err)
+	}
+	if !slices.Contains(databases, rigDB) {
+		return fmt.Errorf("database %q not found (see 'gt dolt list')", rigDB)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if len(args) == 2 {
+		if doltBranchesPrune || doltBranchesStale != "" {
+			return fmt.Errorf("--prune and --stale apply to the branch list, not a single branch")
+		}
+		return showDoltBranch(ctx, townRoot, rigDB, args[1])
+	}
+
+	var staleAge time.Duration
+	if doltBranchesStale != "" {
+		if staleAge, err = parseDuration(doltBranchesStale); err != nil || staleAge <= 0 {
+			return fmt.Errorf("invalid --stale %q", doltBranchesStale)
+		}
+	} else if doltBranchesPrune {
+		staleAge = defaultBranchStaleAge
+	}
+
+	branches, err := doltserver.ListBranches(ctx, townRoot, rigDB)
+	if err != nil {
+		return err
+	}
+	if staleAge > 0 {
+		branches = doltserver.StaleBranches(branches, time.Now().Add(-staleAge))
+	}
+
+	if doltBranchesPrune {
+		return pruneDoltBranches(ctx, townRoot, rigDB, branches)
+	}
+
+	if doltBranchesJSON {
+		if branches == nil {
+			branches = []doltserver.Branch{}
+		}
+		return printDoltBranchesJSON(branches)
+	}
+
+	if len(branches) == 0 {
+		if staleAge > 0 {
+			fmt.Printf("No branches in %s idle longer than %s.\n", rigDB, doltBranchesStale)
+		} else {
+			fmt.Printf("No branches in %s besides %s.\n", rigDB, doltserver.MainBranch)
+		}
+		return nil
+	}
+	fmt.Printf("%s branches in %s:\n\n", style.Bold.Render(fmt.Sprintf("%d", len(branches))), rigDB)
+	for _, b := range branches {
+		printDoltBranchLine(b)
+	}
+	if staleAge == 0 {
+		fmt.Printf("\nPrune idle branches with: %s\n", style.Dim.Render("gt dolt branches "+rigDB+" --prune"))
+	}
+	return nil
+}
+
+// printDoltBranchLine prints one branch with its idle time and merge state.
+func printDoltBranchLine(b doltserver.Branch) {
+	state := style.Success.Render("merged")
+	if b.HasUnmergedChanges() {
+		state = style.Warning.Render(fmt.Sprintf("%d unmerged", b.Unmerged))
+		if b.Dirty {
+			state += style.Warning.Render(", uncommitted changes")
+		}
+	}
+	fmt.Printf("  %-40s idle %-8s %s\n", b.Name, formatBranchAge(time.Since(b.LastActive())), state)
+}
+
+// formatBranchAge renders d as whole days, hours, or minutes.
+func formatBranchAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// doltBranchDetail is the --json shape of gt dolt branches <rig> <branch>.
+type doltBranchDetail struct {
+	doltserver.Branch
+	Commits []doltserver.BranchCommit  `json:"commits"`
+	Diff    []doltserver.TableDiffStat `json:"diff"`
+	Merge   *doltserver.MergePreview   `json:"merge_preview,omitempty"`
+}
+
+func showDoltBranch(ctx context.Context, townRoot, rigDB, name string) error {
+	branch, err := doltserver.GetBranch(ctx, townRoot, rigDB, name)
+	if err != nil {
+		return err
+	}
+	commits, err := doltserver.BranchCommits(ctx, townRoot, rigDB, name)
+	if err != nil {
+		return err
+	}
+	diff, err := doltserver.BranchDiffStat(ctx, townRoot, rigDB, name)
+	if err != nil {
+		return err
+	}
+	// The preview needs a recent Dolt; without it the rest is still useful.
+	preview, previewErr := doltserver.PreviewMerge(ctx, townRoot, rigDB, name)
+
+	if doltBranchesJSON {
+		detail := doltBranchDetail{Branch: *branch, Commits: commits, Diff: diff, Merge: preview}
+		if detail.Commits == nil {
+			detail.Commits = []doltserver.BranchCommit{}
+		}
+		if detail.Diff == nil {
+			detail.Diff = []doltserver.TableDiffStat{}
+		}
+		return printDoltBranchesJSON(detail)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Branch:"), branch.Name)
+	if branch.Polecat != "" {
+		fmt.Printf("  Polecat:     %s (created %s)\n", branch.Polecat, branch.Created.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("  Head:        %s\n", branch.Hash)
+	fmt.Printf("  Last commit: %s by %s\n", branch.LastCommit.Local().Format("2006-01-02 15:04"), branch.LastCommitter)
+	if branch.Dirty {
+		fmt.Printf("  %s uncommitted working-set changes (lost if deleted)\n", style.Warning.Render("!"))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("Commits not on %s (%d):", doltserver.MainBranch, len(commits))))
+	for _, c := range commits {
+		fmt.Printf("  %s %s %s\n", style.Dim.Render(shortDoltHash(c.Hash)), c.Date.Local().Format("2006-01-02 15:04"), c.Message)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("Changes since diverging from %s:", doltserver.MainBranch)))
+	if len(diff) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+	}
+	for _, s := range diff {
+		fmt.Printf("  %-24s +%d -%d ~%d\n", s.Table, s.Added, s.Deleted, s.Modified)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("Merge into %s:", doltserver.MainBranch)))
+	switch {
+	case previewErr != nil:
+		fmt.Printf("  %s could not preview: %v\n", style.Warning.Render("!"), previewErr)
+	case !preview.HasConflicts():
+		fmt.Printf("  %s no conflicts\n", style.Success.Render("✓"))
+	default:
+		fmt.Printf("  %s conflicts in %s\n", style.Warning.Render("!"), preview.Summary())
+		for _, r := range preview.Rows {
+			fmt.Printf("    %-20s %-24s main %s, branch %s\n", r.Table, r.Key, r.OurChange, r.TheirChange)
+		}
+	}
+	return nil
+}
+
+// shortDoltHash abbreviates a Dolt commit hash for display.
+func shortDoltHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+func pruneDoltBranches(ctx context.Context, townRoot, rigDB string, branches []doltserver.Branch) error {
+	var deleted, skipped []string
+	for _, b := range branches {
+		if b.HasUnmergedChanges() && !doltBranchesForce {
+			skipped = append(skipped, b.Name)
+			if !doltBranchesJSON {
+				fmt.Printf("  %s %s: %d unmerged commit(s)%s\n", style.Warning.Render("skip"), b.Name, b.Unmerged, dirtySuffix(b))
+			}
+			continue
+		}
+		if doltBranchesDry {
+			deleted = append(deleted, b.Name)
+			if !doltBranchesJSON {
+				fmt.Printf("  would delete %s\n", b.Name)
+			}
+			continue
+		}
+		if err := doltserver.DeleteBranch(ctx, townRoot, rigDB, b, doltBranchesForce); err != nil {
+			return err
+		}
+		deleted = append(deleted, b.Name)
+		if !doltBranchesJSON {
+			fmt.Printf("  %s deleted %s\n", style.Success.Render("✓"), b.Name)
+		}
+	}
+
+	if doltBranchesJSON {
+		return printDoltBranchesJSON(map[string]interface{}{"deleted": nonNilStrings(deleted), "skipped": nonNilStrings(skipped), "dry_run": doltBranchesDry})
+	}
+	verb := "Deleted"
+	if doltBranchesDry {
+		verb = "Would delete"
+	}
+	fmt.Printf("\n%s %d branch(es) from %s", verb, len(deleted), rigDB)
+	if len(skipped) > 0 {
+		fmt.Printf(", skipped %d with unmerged changes (use --force to delete anyway)", len(skipped))
+	}
+	fmt.Println()
+	return nil
+}
+
+func dirtySuffix(b doltserver.Branch) string {
+	if b.Dirty {
+		return " and uncommitted changes"
+	}
+	return ""
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func printDoltBranchesJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltJournalStatusJSON bool
+	doltJournalApplyForce bool
+	doltJournalApplyDry   bool
+	doltJournalApplyJSON  bool
+)
+
+var doltJournalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "Bead mutations queued while the Dolt server was unreachable",
+	Long: `Inspect and replay the town's bead journal.
+
+With dolt_fallback set to "journal" in settings/config.json, bead updates
+gt makes while the Dolt server is unreachable (status changes, closes,
+labels, dependencies, comments) are appended to .runtime/bead-journal.jsonl
+instead of failing. Reads and creates still fail. Agents running bd
+themselves are not journaled.
+
+Once the server is back, gt dolt journal apply replays the queued changes
+in order. An entry whose bead changed on the server after it was queued,
+or no longer exists, is held as a conflict along with later entries for
+the same bead; review it and rerun with --force to apply it anyway.`,
+	RunE: requireSubcommand,
+}
+
+var doltJournalStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show queued bead mutations",
+	Long: `Show bead mutations in the journal that haven't been applied yet.
+
+Examples:
+  gt dolt journal status
+  gt dolt journal status --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoltJournalStatus,
+}
+
+var doltJournalApplyCmd = &cobra.Command{
+	Use:         "apply",
+	Annotations: auditAnnotations(""),
+	Short:       "Replay queued bead mutations onto the Dolt server",
+	Long: `Replay bead mutations from the journal in the order they were queued.
+
+Entries that conflict with changes made on the server since they were
+queued are held, with later entries for the same bead; --force applies
+them anyway. The journal is removed once every entry is applied.
+
+Examples:
+  gt dolt journal apply --dry-run
+  gt dolt journal apply
+  gt dolt journal apply --force`,
+	Args: cobra.NoArgs,
+	RunE: runDoltJournalApply,
+}
+
+func init() {
+	doltJournalStatusCmd.Flags().BoolVar(&doltJournalStatusJSON, "json", false, "Output as JSON")
+	doltJournalApplyCmd.Flags().BoolVar(&doltJournalApplyForce, "force", false, "Apply entries held as conflicts")
+	doltJournalApplyCmd.Flags().BoolVar(&doltJournalApplyDry, "dry-run", false, "Check for conflicts without applying anything")
+	doltJournalApplyCmd.Flags().BoolVar(&doltJournalApplyJSON, "json", false, "Output as JSON")
+
+	doltJournalCmd.AddCommand(doltJournalStatusCmd)
+	doltJournalCmd.AddCommand(doltJournalApplyCmd)
+	doltCmd.AddCommand(doltJournalCmd)
+}
+
+func runDoltJournalStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	unapplied, err := beads.UnappliedJournal(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if doltJournalStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(unapplied)
+	}
+	if len(unapplied) == 0 {
+		fmt.Printf("%s Bead journal is empty\n", style.Success.Render("✓"))
+		return nil
+	}
+	fmt.Printf("%s %d queued bead mutation(s):\n\n", style.Bold.Render("Journal:"), len(unapplied))
+	printJournalEntries(unapplied)
+	fmt.Printf("\nApply with: %s\n", style.Dim.Render("gt dolt journal apply"))
+	return nil
+}
+
+func runDoltJournalApply(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results, err := beads.ApplyJournal(townRoot, beads.ApplyJournalOptions{Force: doltJournalApplyForce, DryRun: doltJournalApplyDry})
+	if doltJournalApplyJSON {
+		if results == nil {
+			results = []beads.JournalEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(results); encErr != nil {
+			return encErr
+		}
+	} else if len(results) == 0 && err == nil {
+		fmt.Printf("%s Bead journal is empty\n", style.Success.Render("✓"))
+	} else {
+		printJournalEntries(results)
+	}
+	if err != nil {
+		return err
+	}
+
+	held := 0
+	for _, e := range results {
+		if e.State == beads.JournalConflict || (e.State == beads.JournalPending && e.Detail != "") {
+			held++
+		}
+	}
+	if held > 0 && !doltJournalApplyDry {
+		return fmt.Errorf("%d journal entries held; review them and rerun with --force to apply", held)
+	}
+	return nil
+}
+
+func printJournalEntries(entries []beads.JournalEntry) {
+	for _, e := range entries {
+		var mark string
+		switch {
+		case e.State == beads.JournalApplied:
+			mark = style.Success.Render("✓")
+		case e.State == beads.JournalConflict:
+			mark = style.Error.Render("✗")
+		case e.Detail != "":
+			mark = style.Warning.Render("…")
+		default:
+			mark = style.Dim.Render("○")
+		}
+		fmt.Printf("  %s #%d %s %s\n", mark, e.Seq, e.QueuedAt.Local().Format("2006-01-02 15:04:05"), e.Command())
+		if e.Detail != "" {
+			fmt.Printf("      %s\n", style.Dim.Render(e.Detail))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltReconcileDryRun bool
+	doltReconcileJSON   bool
+)
+
+var doltReconcileCmd = &cobra.Command{
+	Use:         "reconcile",
+	Annotations: auditAnnotations(""),
+	Short:       "Move rigs on a fallback snapshot back to the Dolt server",
+	Long: `Move rigs back to the Dolt server after the daemon failed them over to a
+local snapshot.
+
+When the daemon gives up restarting the server, the town's dolt_fallback
+setting (settings/config.json) decides what bd does:
+
+  fail        bd commands fail until the server is back (default)
+  read-only   each rig reads a local snapshot of its database; writes fail
+  queue       each rig reads and writes a local snapshot
+  journal     gt queues its bead updates (see gt dolt journal)
+
+Once the server is running again, reconcile replays every change made to a
+queue snapshot onto the server and commits it, then puts the rig's
+metadata.json back in server mode and removes the snapshot. Where a row was
+also changed on the server meanwhile, the offline write wins. Read-only
+snapshots are simply dropped.
+
+Examples:
+  gt dolt reconcile --dry-run
+  gt dolt reconcile
+  gt dolt reconcile --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoltReconcile,
+}
+
+func init() {
+	doltReconcileCmd.Flags().BoolVar(&doltReconcileDryRun, "dry-run", false, "Count the changes to replay without applying them")
+	doltReconcileCmd.Flags().BoolVar(&doltReconcileJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltReconcileCmd)
+}
+
+func runDoltReconcile(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+
+	results, err := doltserver.ReconcileFallback(townRoot, doltReconcileDryRun)
+	if err != nil {
+		return err
+	}
+
+	if doltReconcileJSON {
+		if results == nil {
+			results = []doltserver.ReconcileResult{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printReconcileResults(results)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d rig(s) could not be reconciled", failed)
+	}
+	return nil
+}
+
+func printReconcileResults(results []doltserver.ReconcileResult) {
+	if len(results) == 0 {
+		fmt.Printf("%s No rigs on a fallback snapshot\n", style.Success.Render("✓"))
+		return
+	}
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), r.Rig, r.Error)
+		case doltReconcileDryRun:
+			fmt.Printf("  %s: %d change(s) to replay from the %s snapshot taken %s\n",
+				r.Rig, r.Statements, r.Mode, r.Since.Local().Format("2006-01-02 15:04:05"))
+		default:
+			fmt.Printf("%s %s: replayed %d change(s), back on the server\n",
+				style.Success.Render("✓"), r.Rig, r.Statements)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltUpgradeSchemaRig  string
+	doltUpgradeSchemaJSON bool
+)
+
+var doltUpgradeSchemaCmd = &cobra.Command{
+	Use:         "upgrade-schema",
+	Annotations: auditAnnotations(""),
+	Short:       "Check rig databases for schema drift and upgrade them",
+	Long: `Compare each rig database's schema with the installed bd and upgrade
+databases left behind by a bd upgrade.
+
+bd records in every database the version that last migrated its schema.
+A database older than the installed bd is behind: bd may fail on it with
+confusing query errors. A database newer than the installed bd is ahead:
+upgrade bd instead. Upgrades are recorded in daemon/dolt-schema.json, so a
+database later found older than its last upgrade (say, restored from an
+old backup) is reported as behind too.
+
+With no flags, lists every database's state and what to run. With --rig,
+backs the rig's database up to schema-backup-<timestamp>/ in the town
+root, runs bd migrate, and verifies the new version. If the upgrade fails
+the error names the backup, a Dolt backup that 'dolt backup restore'
+turns back into the database.
+
+The same check runs in 'gt doctor' (dolt-schema) and after 'gt dolt start'.
+
+Examples:
+  gt dolt upgrade-schema
+  gt dolt upgrade-schema --rig gastown`,
+	Args: cobra.NoArgs,
+	RunE: runDoltUpgradeSchema,
+}
+
+func init() {
+	doltUpgradeSchemaCmd.Flags().StringVar(&doltUpgradeSchemaRig, "rig", "", "Rig whose database to upgrade (hq for the town)")
+	doltUpgradeSchemaCmd.Flags().BoolVar(&doltUpgradeSchemaJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltUpgradeSchemaCmd)
+}
+
+// installedBdVersion returns the installed bd's version, or "" if it
+// can't be determined.
+func installedBdVersion() string {
+	status, version := deps.CheckBeads()
+	if status == deps.BeadsNotFound || status == deps.BeadsUnknown {
+		return ""
+	}
+	return version
+}
+
+func runDoltUpgradeSchema(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+	bdVersion := installedBdVersion()
+
+	if doltUpgradeSchemaRig != "" {
+		if bdVersion == "" {
+			return fmt.Errorf("could not determine the installed bd version (is bd in PATH?)")
+		}
+		up, err := doltserver.UpgradeSchema(townRoot, doltUpgradeSchemaRig, bdVersion)
+		if err != nil {
+			return err
+		}
+		if doltUpgradeSchemaJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(up)
+		}
+		from := up.From
+		if from == "" {
+			from = "(unrecorded)"
+		}
+		fmt.Printf("%s Upgraded %s schema %s → %s\n", style.Bold.Render("✓"), up.Database, from, up.To)
+		fmt.Printf("  Backup: %s\n", up.Backup)
+		return nil
+	}
+
+	statuses, err := doltserver.CheckSchemas(townRoot, bdVersion)
+	if err != nil {
+		return err
+	}
+	if doltUpgradeSchemaJSON {
+		if statuses == nil {
+			statuses = []doltserver.SchemaStatus{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	if bdVersion == "" {
+		fmt.Printf("%s Installed bd version unknown; comparing with recorded upgrades only\n\n", style.Dim.Render("⚠"))
+	} else {
+		fmt.Printf("Installed bd: %s\n\n", bdVersion)
+	}
+	var behind, ahead int
+	for _, s := range statuses {
+		fmt.Printf("  %s\n", formatSchemaStatus(s))
+		switch s.State {
+		case doltserver.SchemaBehind:
+			behind++
+		case doltserver.SchemaAhead:
+			ahead++
+		}
+	}
+	if behind > 0 {
+		fmt.Printf("\nUpgrade with: %s\n", style.Dim.Render("gt dolt upgrade-schema --rig <rig>"))
+	}
+	if ahead > 0 {
+		fmt.Printf("\nSome databases are newer than the installed bd. Upgrade bd: %s\n", style.Dim.Render("go install "+deps.BeadsInstallPath))
+	}
+	return nil
+}
+
+// formatSchemaStatus renders one database's schema state on a line.
+func formatSchemaStatus(s doltserver.SchemaStatus) string {
+	name := s.Rig
+	if s.Database != s.Rig {
+		name += " (" + s.Database + ")"
+	}
+	switch s.State {
+	case doltserver.SchemaCurrent:
+		return fmt.Sprintf("%s %-24s %s", style.Bold.Render("✓"), name, s.Version)
+	case doltserver.SchemaBehind:
+		return fmt.Sprintf("%s %-24s %s, expected %s", style.Bold.Render("✗"), name, s.Version, s.Expected)
+	case doltserver.SchemaAhead:
+		return fmt.Sprintf("%s %-24s %s, newer than bd %s", style.Bold.Render("⚠"), name, s.Version, s.Expected)
+	default:
+		return fmt.Sprintf("%s %-24s %s", style.Dim.Render("?"), name, s.Error)
+	}
+}
+
+// warnSchemaDrift prints a short notice for databases whose schema doesn't
+// match the installed bd. Best effort: nothing is printed if the check
+// can't run.
+func warnSchemaDrift(townRoot string) {
+	statuses, err := doltserver.CheckSchemas(townRoot, installedBdVersion())
+	if err != nil {
+		return
+	}
+	var drifted []doltserver.SchemaStatus
+	for _, s := range statuses {
+		if s.State == doltserver.SchemaBehind || s.State == doltserver.SchemaAhead {
+			drifted = append(drifted, s)
+		}
+	}
+	if len(drifted) == 0 {
+		return
+	}
+	fmt.Printf("\n%s Schema drift in %d database(s):\n", style.Bold.Render("⚠"), len(drifted))
+	for _, s := range drifted {
+		fmt.Printf("  %s\n", formatSchemaStatus(s))
+	}
+	fmt.Printf("  Review with: %s\n", style.Dim.Render("gt dolt upgrade-schema"))
+}
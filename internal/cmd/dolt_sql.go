@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Output formats for gt dolt sql --format.
+const (
+	doltSQLFormatTable = "table"
+	doltSQLFormatCSV   = "csv"
+	doltSQLFormatJSON  = "json"
+)
+
+// doltSQLTimeout bounds a non-interactive query or script.
+const doltSQLTimeout = 5 * time.Minute
+
+var (
+	doltSQLDB      string
+	doltSQLExecute string
+	doltSQLFile    string
+	doltSQLFormat  string
+)
+
+func init() {
+	doltSQLCmd.Flags().StringVar(&doltSQLDB, "db", "", "Database (rig) to use")
+	doltSQLCmd.Flags().StringVarP(&doltSQLExecute, "execute", "e", "", "Execute a query and exit")
+	doltSQLCmd.Flags().StringVarP(&doltSQLFile, "file", "f", "", "Execute statements from a SQL file and exit ('-' for stdin)")
+	doltSQLCmd.Flags().StringVar(&doltSQLFormat, "format", doltSQLFormatTable, "Output format for --execute/--file: table, csv, json")
+}
+
+func runDoltSQL(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	switch doltSQLFormat {
+	case doltSQLFormatTable, doltSQLFormatCSV, doltSQLFormatJSON:
+	default:
+		return fmt.Errorf("invalid --format %q: use table, csv, or json", doltSQLFormat)
+	}
+	if doltSQLExecute != "" && doltSQLFile != "" {
+		return fmt.Errorf("--execute and --file are mutually exclusive")
+	}
+
+	if doltSQLDB != "" {
+		databases, err := doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+		if !slices.Contains(databases, doltSQLDB) {
+			return fmt.Errorf("database %q not found (available: %s)", doltSQLDB, strings.Join(databases, ", "))
+		}
+	}
+
+	query, err := doltSQLQuery()
+	if err != nil {
+		return err
+	}
+
+	running, _, _ := doltserver.IsRunning(townRoot)
+	if query == "" {
+		return runDoltSQLShell(townRoot, running)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), doltSQLTimeout)
+	defer cancel()
+
+	var results []doltserver.ResultSet
+	if running {
+		db, err := doltserver.DB(townRoot, doltSQLDB)
+		if err != nil {
+			return err
+		}
+		results, err = doltserver.QueryAll(ctx, db, query)
+		if err != nil {
+			return fmt.Errorf("query failed: %w", err)
+		}
+	} else {
+		results, err = doltSQLEmbedded(ctx, townRoot, query)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeSQLResults(os.Stdout, results, doltSQLFormat)
+}
+
+// doltSQLQuery returns the SQL to run from --execute or --file, or "" for an
+// interactive shell.
+func doltSQLQuery() (string, error) {
+	if doltSQLExecute != "" {
+		return doltSQLExecute, nil
+	}
+	if doltSQLFile == "" {
+		return "", nil
+	}
+
+	var data []byte
+	var err error
+	if doltSQLFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(doltSQLFile) //nolint:gosec // G304: user-specified script
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading SQL file: %w", err)
+	}
+	query := strings.TrimSpace(string(data))
+	if query == "" {
+		return "", fmt.Errorf("SQL file %s is empty", doltSQLFile)
+	}
+	return query, nil
+}
+
+// runDoltSQLShell opens the interactive dolt SQL shell.
+func runDoltSQLShell(townRoot string, running bool) error {
+	config := doltserver.DefaultConfig(townRoot)
+
+	if running {
+		// Connect to running server using dolt sql client
+		// Using --no-tls since server doesn't have TLS configured
+		host := config.Host
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		sqlArgs := []string{
+			"--host", host,
+			"--port", strconv.Itoa(config.Port),
+			"--user", config.User,
+			"--no-tls",
+		}
+		if doltSQLDB != "" {
+			sqlArgs = append(sqlArgs, "--use-db", doltSQLDB)
+		}
+		sqlArgs = append(sqlArgs, "sql")
+		sqlCmd := exec.Command("dolt", sqlArgs...)
+		if config.Password != "" {
+			sqlCmd.Env = append(os.Environ(), "DOLT_CLI_PASSWORD="+config.Password)
+		}
+		sqlCmd.Stdin = os.Stdin
+		sqlCmd.Stdout = os.Stdout
+		sqlCmd.Stderr = os.Stderr
+		return sqlCmd.Run()
+	}
+
+	// Server not running - embedded mode works on a single database directory
+	dbName, err := embeddedSQLDatabase(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Using database: %s (start server with 'gt dolt start' for multi-database access)\n\n", dbName)
+
+	sqlCmd := exec.Command("dolt", "sql")
+	sqlCmd.Dir = doltserver.RigDatabaseDir(townRoot, dbName)
+	sqlCmd.Stdin = os.Stdin
+	sqlCmd.Stdout = os.Stdout
+	sqlCmd.Stderr = os.Stderr
+
+	return sqlCmd.Run()
+}
+
+// embeddedSQLDatabase returns --db, or the first database when unset.
+func embeddedSQLDatabase(townRoot string) (string, error) {
+	if doltSQLDB != "" {
+		return doltSQLDB, nil
+	}
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return "", fmt.Errorf("listing databases: %w", err)
+	}
+	if len(databases) == 0 {
+		config := doltserver.DefaultConfig(townRoot)
+		return "", fmt.Errorf("no databases found in %s\nInitialize with: gt dolt init-rig <name>", config.DataDir)
+	}
+	return databases[0], nil
+}
+
+// doltSQLEmbedded runs query with the embedded dolt CLI when no server is
+// running. Output is requested as CSV and parsed so every format renders the
+// same way as server mode. Multi-statement output is returned as one result set.
+func doltSQLEmbedded(ctx context.Context, townRoot, query string) ([]doltserver.ResultSet, error) {
+	dbName, err := embeddedSQLDatabase(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if doltSQLDB == "" {
+		fmt.Fprintf(os.Stderr, "Using database: %s (no server running; pass --db to choose)\n", dbName)
+	}
+
+	sqlCmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "csv", "-q", query)
+	sqlCmd.Dir = doltserver.RigDatabaseDir(townRoot, dbName)
+	var stderr bytes.Buffer
+	sqlCmd.Stderr = &stderr
+	out, err := sqlCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	r := csv.NewReader(bytes.NewReader(out))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing dolt output: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	rs := doltserver.ResultSet{Columns: records[0]}
+	for _, rec := range records[1:] {
+		row := make([]*string, len(rec))
+		for i := range rec {
+			row[i] = &rec[i]
+		}
+		rs.Rows = append(rs.Rows, row)
+	}
+	return []doltserver.ResultSet{rs}, nil
+}
+
+// writeSQLResults renders result sets in the requested format.
+// Multiple result sets are separated by a blank line (table, CSV) or emitted
+// as a JSON array of row arrays.
+func writeSQLResults(w io.Writer, results []doltserver.ResultSet, format string) error {
+	switch format {
+	case doltSQLFormatJSON:
+		out := make([][]map[string]*string, 0, len(results))
+		for _, rs := range results {
+			rows := make([]map[string]*string, 0, len(rs.Rows))
+			for _, row := range rs.Rows {
+				obj := make(map[string]*string, len(rs.Columns))
+				for i, col := range rs.Columns {
+					if i < len(row) {
+						obj[col] = row[i]
+					}
+				}
+				rows = append(rows, obj)
+			}
+			out = append(out, rows)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if len(out) == 1 {
+			return enc.Encode(out[0])
+		}
+		return enc.Encode(out)
+
+	case doltSQLFormatCSV:
+		for i, rs := range results {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			cw := csv.NewWriter(w)
+			if err := cw.Write(rs.Columns); err != nil {
+				return err
+			}
+			for _, row := range rs.Rows {
+				if err := cw.Write(sqlRowStrings(row, "")); err != nil {
+					return err
+				}
+			}
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		for i, rs := range results {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, strings.Join(rs.Columns, "\t"))
+			for _, row := range rs.Rows {
+				fmt.Fprintln(tw, strings.Join(sqlRowStrings(row, "NULL"), "\t"))
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "(%d rows)\n", len(rs.Rows))
+		}
+		return nil
+	}
+}
+
+// sqlRowStrings flattens a row, rendering SQL NULL as null.
+func sqlRowStrings(row []*string, null string) []string {
+	out := make([]string, len(row))
+	for i, v := range row {
+		if v == nil {
+			out[i] = null
+		} else {
+			out[i] = *v
+		}
+	}
+	return out
+}
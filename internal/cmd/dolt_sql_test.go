@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestWriteSQLResults(t *testing.T) {
+	results := []doltserver.ResultSet{{
+		Columns: []string{"id", "title"},
+		Rows: [][]*string{
+			{strPtr("gt-1"), strPtr("Fix, the bug")},
+			{strPtr("gt-2"), nil},
+		},
+	}}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{doltSQLFormatCSV, "id,title\ngt-1,\"Fix, the bug\"\ngt-2,\n"},
+		{doltSQLFormatJSON, `[
+  {
+    "id": "gt-1",
+    "title": "Fix, the bug"
+  },
+  {
+    "id": "gt-2",
+    "title": null
+  }
+]
+`},
+		{doltSQLFormatTable, "id    title\ngt-1  Fix, the bug\ngt-2  NULL\n(2 rows)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeSQLResults(&buf, results, tt.format); err != nil {
+				t.Fatalf("writeSQLResults() error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("writeSQLResults(%s) =\n%s\nwant:\n%s", tt.format, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSQLResults_MultipleSets(t *testing.T) {
+	results := []doltserver.ResultSet{
+		{Columns: []string{"a"}, Rows: [][]*string{{strPtr("1")}}},
+		{Columns: []string{"b"}, Rows: [][]*string{{strPtr("2")}}},
+	}
+	var buf bytes.Buffer
+	if err := writeSQLResults(&buf, results, doltSQLFormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "a\n1\n\nb\n2\n" {
+		t.Errorf("multi-set CSV = %q", got)
+	}
+
+	buf.Reset()
+	if err := writeSQLResults(&buf, results, doltSQLFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "[\n  [") {
+		t.Errorf("multi-set JSON should be an array of arrays, got %q", buf.String())
+	}
+}
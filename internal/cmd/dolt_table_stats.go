@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/rigstats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltTableStatsSince string
+	doltTableStatsRig   string
+	doltTableStatsLimit int
+	doltTableStatsJSON  bool
+)
+
+var doltTableStatsCmd = &cobra.Command{
+	Use:   "table-stats",
+	Short: "Show which databases and tables drive load on the Dolt server",
+	Long: `Show per-table query load recorded by the daemon's table_stats patrol.
+
+The patrol is opt-in; enable it in mayor/daemon.json:
+
+  "patrols": {"table_stats": {"enabled": true}}
+
+It samples the server's processlist every few seconds (sample_interval,
+default 2s), attributes each running query to the tables it names, and
+records the totals into the gt_table_stats table in hq every interval
+(default 15m). Columns:
+
+  busy      Estimated time queries spent on the table (sightings × sample interval)
+  queries   Distinct queries seen running
+  mean      busy / queries, an estimate of query latency
+  max       Longest a query was seen running
+
+Queries that finish between two samples aren't seen, so treat the numbers
+as a profile of where server time goes rather than an exact query count.
+Use it to find hot rigs and tables before deciding on replicas or sharding.
+
+Examples:
+  gt dolt table-stats
+  gt dolt table-stats --since 7d --limit 10
+  gt dolt table-stats --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoltTableStats,
+}
+
+func init() {
+	doltTableStatsCmd.Flags().StringVar(&doltTableStatsSince, "since", "24h", "How far back to report (e.g. 1h, 24h, 7d)")
+	doltTableStatsCmd.Flags().StringVar(&doltTableStatsRig, "rig", "", "Only tables in this rig's database")
+	doltTableStatsCmd.Flags().IntVar(&doltTableStatsLimit, "limit", 20, "Number of tables to show (0 for all)")
+	doltTableStatsCmd.Flags().BoolVar(&doltTableStatsJSON, "json", false, "Output as JSON")
+
+	doltCmd.AddCommand(doltTableStatsCmd)
+}
+
+// doltTableStatsOutput is the --json shape of gt dolt table-stats.
+type doltTableStatsOutput struct {
+	Since  time.Time               `json:"since"`
+	Until  time.Time               `json:"until"`
+	Tables []rigstats.TableSample  `json:"tables"`
+	Rigs   []doltTableStatsRigLoad `json:"rigs"`
+}
+
+// doltTableStatsRigLoad is the load of all of one rig's tables.
+type doltTableStatsRigLoad struct {
+	Rig         string  `json:"rig"`
+	Queries     int     `json:"queries"`
+	BusySeconds float64 `json:"busy_seconds"`
+}
+
+func runDoltTableStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	window, err := parseDuration(doltTableStatsSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q", doltTableStatsSince)
+	}
+	if doltTableStatsLimit < 0 {
+		return fmt.Errorf("--limit can't be negative")
+	}
+
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+	db, err := doltserver.DB(townRoot, rigstats.Database)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now().UTC()
+	since := until.Add(-window)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	samples, err := rigstats.QueryTableStats(ctx, db, doltTableStatsRig, since)
+	if err != nil {
+		return err
+	}
+	tables := rigstats.SummarizeTables(samples)
+	rigs := rigLoad(tables)
+	if doltTableStatsLimit > 0 && len(tables) > doltTableStatsLimit {
+		tables = tables[:doltTableStatsLimit]
+	}
+
+	if doltTableStatsJSON {
+		if tables == nil {
+			tables = []rigstats.TableSample{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doltTableStatsOutput{Since: since, Until: until, Tables: tables, Rigs: rigs})
+	}
+
+	fmt.Printf("%s last %s\n\n", style.Bold.Render("Table load:"), doltTableStatsSince)
+	if len(tables) == 0 {
+		fmt.Printf("  No table samples in the last %s.\n", doltTableStatsSince)
+		fmt.Printf("  %s\n", style.Dim.Render(`Enable the daemon's opt-in table_stats patrol: "table_stats": {"enabled": true} in mayor/daemon.json`))
+		return nil
+	}
+
+	fmt.Printf("  %-14s %-28s %10s %8s %8s %6s\n", "RIG", "TABLE", "BUSY", "QUERIES", "MEAN", "MAX")
+	for _, t := range tables {
+		rig := t.Rig
+		if rig == "" {
+			rig = "-"
+		}
+		fmt.Printf("  %-14s %-28s %10s %8d %8s %6s\n", rig, t.Database+"."+t.Table,
+			tableStatsSeconds(t.BusySeconds), t.Queries, tableStatsSeconds(t.MeanQuerySeconds()),
+			tableStatsSeconds(t.MaxQuerySeconds))
+	}
+
+	if len(rigs) > 1 {
+		fmt.Printf("\n  %-14s %10s %8s\n", "BY RIG", "BUSY", "QUERIES")
+		for _, r := range rigs {
+			fmt.Printf("  %-14s %10s %8d\n", r.Rig, tableStatsSeconds(r.BusySeconds), r.Queries)
+		}
+	}
+	return nil
+}
+
+// rigLoad totals table load per rig, busiest first. Tables outside any rig
+// (town beads in hq) are grouped under "(town)".
+func rigLoad(tables []rigstats.TableSample) []doltTableStatsRigLoad {
+	byRig := map[string]*doltTableStatsRigLoad{}
+	var order []string
+	for _, t := range tables {
+		name := t.Rig
+		if name == "" {
+			name = "(town)"
+		}
+		r := byRig[name]
+		if r == nil {
+			r = &doltTableStatsRigLoad{Rig: name}
+			byRig[name] = r
+			order = append(order, name)
+		}
+		r.Queries += t.Queries
+		r.BusySeconds += t.BusySeconds
+	}
+	out := make([]doltTableStatsRigLoad, 0, len(order))
+	for _, name := range order {
+		out = append(out, *byRig[name])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].BusySeconds > out[j].BusySeconds })
+	return out
+}
+
+// tableStatsSeconds formats an estimated duration in seconds for display.
+func tableStatsSeconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Second / 10).String()
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltTransferDry  bool
+	doltTransferKeep bool
+
+	doltAdoptEntry string
+	doltAdoptRoute string
+)
+
+var doltTransferCmd = &cobra.Command{
+	Use:         "transfer <rig> <dest-town>",
+	Annotations: auditAnnotations(""),
+	Short:       "Move a rig database to another town",
+	Long: `Move a rig's Dolt database from this town to another one.
+
+The destination is a local town path or host:/path for a town on another
+machine (reached with ssh; gt must be installed there). The database is
+copied into the destination's .dolt-data/, and every branch head is
+compared (dolt commit hashes) before anything in this town changes.
+
+After verification:
+  - The destination gets the rig's rigs.json entry, route, and metadata.json
+  - This town drops the rig's rigs.json entry and route
+  - The source database is deleted (unless --keep-source)
+
+The rig's workspace directory in this town is left in place. Stop this
+town's Dolt server first (gt dolt stop) so the database can't change
+mid-copy; restart the destination's server afterwards to serve the rig.
+
+Examples:
+  gt dolt transfer gastown ~/gt-work
+  gt dolt transfer gastown build-box:/home/me/gt --dry-run
+  gt dolt transfer gastown ~/gt-work --keep-source`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDoltTransfer,
+}
+
+// doltAdoptRigCmd registers a transferred database in the destination town.
+// gt dolt transfer runs it over ssh for remote destinations.
+var doltAdoptRigCmd = &cobra.Command{
+	Use:    "adopt-rig <rig>",
+	Short:  "Register a transferred rig database (used by gt dolt transfer)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE:   runDoltAdoptRig,
+}
+
+func init() {
+	doltTransferCmd.Flags().BoolVar(&doltTransferDry, "dry-run", false, "Show what would be transferred without making changes")
+	doltTransferCmd.Flags().BoolVar(&doltTransferKeep, "keep-source", false, "Copy only: leave the database and registrations in this town")
+
+	doltAdoptRigCmd.Flags().StringVar(&doltAdoptEntry, "entry", "", "rigs.json entry as JSON")
+	doltAdoptRigCmd.Flags().StringVar(&doltAdoptRoute, "route", "", "routes.jsonl route as JSON")
+
+	doltCmd.AddCommand(doltTransferCmd)
+	doltCmd.AddCommand(doltAdoptRigCmd)
+}
+
+func runDoltTransfer(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if rigName == "hq" {
+		return fmt.Errorf("hq is the town's own database and can't be transferred")
+	}
+	target, err := doltserver.ParseTransferTarget(args[1])
+	if err != nil {
+		return err
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	entry, registered := rigsConfig.Rigs[rigName]
+	route := rigRoute(townRoot, rigName, entry)
+
+	if doltTransferDry {
+		fmt.Printf("Would transfer %s → %s\n", style.Bold.Render(rigName), target)
+		fmt.Printf("  Copy:   %s → %s\n", doltserver.RigDatabaseDir(townRoot, rigName), filepath.Join(target.DataDir(), rigName))
+		if registered {
+			fmt.Printf("  Register in destination rigs.json\n")
+		}
+		if route != nil {
+			fmt.Printf("  Route:  %s → %s\n", route.Prefix, route.Path)
+		}
+		if !doltTransferKeep {
+			fmt.Printf("  Then remove the database, rigs.json entry, and route from this town\n")
+		}
+		return nil
+	}
+
+	fmt.Printf("Copying %s to %s...\n", rigName, target)
+	result, err := doltserver.TransferDatabase(townRoot, rigName, target)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Verified %d branch head(s)\n", style.Success.Render("✓"), len(result.Branches))
+
+	var entryPtr *config.RigEntry
+	if registered {
+		entryPtr = &entry
+	}
+	if err := adoptInTarget(target, rigName, entryPtr, route); err != nil {
+		return fmt.Errorf("database copied to %s but registering it there failed (this town is unchanged): %w", result.Dest, err)
+	}
+	fmt.Printf("%s Registered %s in %s\n", style.Success.Render("✓"), rigName, target)
+
+	if doltTransferKeep {
+		fmt.Printf("\nSource kept. Start the destination's server to serve it: %s\n", style.Dim.Render("gt dolt start"))
+		return nil
+	}
+
+	if registered {
+		delete(rigsConfig.Rigs, rigName)
+		if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+			return fmt.Errorf("saving rigs config: %w", err)
+		}
+	}
+	if route != nil {
+		if err := beads.RemoveRoute(townRoot, route.Prefix); err != nil {
+			fmt.Printf("  %s Could not remove route %s: %v\n", style.Warning.Render("!"), route.Prefix, err)
+		}
+	}
+	if err := doltserver.RemoveDatabase(townRoot, rigName); err != nil {
+		return fmt.Errorf("removing source database: %w", err)
+	}
+	fmt.Printf("%s Removed %s from this town\n", style.Success.Render("✓"), rigName)
+
+	fmt.Printf("\nStart the destination's server to serve it: %s\n", style.Dim.Render("gt dolt start"))
+	if _, err := os.Stat(filepath.Join(townRoot, rigName)); err == nil {
+		fmt.Printf("The rig workspace %s was left in place.\n", filepath.Join(townRoot, rigName))
+	}
+	return nil
+}
+
+// rigRoute returns the routes.jsonl entry for rigName, or nil if it has none.
+func rigRoute(townRoot, rigName string, entry config.RigEntry) *beads.Route {
+	prefix := ""
+	if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+		prefix = entry.BeadsConfig.Prefix + "-"
+	}
+	routes, _ := beads.LoadRoutes(beads.GetTownBeadsPath(townRoot))
+	for _, r := range routes {
+		first, _, _ := strings.Cut(r.Path, "/")
+		if r.Prefix == prefix || (prefix == "" && first == rigName) {
+			route := r
+			return &route
+		}
+	}
+	return nil
+}
+
+// adoptInTarget registers the rig in the destination town, directly for a
+// local town or by running gt dolt adopt-rig there over ssh.
+func adoptInTarget(target doltserver.TransferTarget, rigName string, entry *config.RigEntry, route *beads.Route) error {
+	if !target.IsRemote() {
+		return adoptRig(target.TownRoot, rigName, entry, route)
+	}
+	argv := []string{"gt", "dolt", "adopt-rig", rigName}
+	if entry != nil {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("encoding rigs.json entry: %w", err)
+		}
+		argv = append(argv, "--entry", string(data))
+	}
+	if route != nil {
+		data, err := json.Marshal(route)
+		if err != nil {
+			return fmt.Errorf("encoding route: %w", err)
+		}
+		argv = append(argv, "--route", string(data))
+	}
+	adopt := target.Command(target.TownRoot, argv...)
+	adopt.Stdout = os.Stdout
+	adopt.Stderr = os.Stderr
+	return adopt.Run()
+}
+
+// adoptRig adds a transferred database's rigs.json entry, route, and
+// metadata.json to townRoot. An existing rigs.json entry is kept as is.
+func adoptRig(townRoot, rigName string, entry *config.RigEntry, route *beads.Route) error {
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	if _, exists := rigsConfig.Rigs[rigName]; !exists && entry != nil {
+		if rigsConfig.Rigs == nil {
+			rigsConfig.Rigs = make(map[string]config.RigEntry)
+		}
+		rigsConfig.Rigs[rigName] = *entry
+		if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+			return fmt.Errorf("saving rigs config: %w", err)
+		}
+	}
+	if route != nil {
+		if err := beads.AppendRoute(townRoot, *route); err != nil {
+			return fmt.Errorf("adding route: %w", err)
+		}
+	}
+	if err := doltserver.EnsureMetadata(townRoot, rigName); err != nil {
+		return fmt.Errorf("writing metadata.json: %w", err)
+	}
+	return nil
+}
+
+func runDoltAdoptRig(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var entry *config.RigEntry
+	if doltAdoptEntry != "" {
+		entry = &config.RigEntry{}
+		if err := json.Unmarshal([]byte(doltAdoptEntry), entry); err != nil {
+			return fmt.Errorf("parsing --entry: %w", err)
+		}
+	}
+	var route *beads.Route
+	if doltAdoptRoute != "" {
+		route = &beads.Route{}
+		if err := json.Unmarshal([]byte(doltAdoptRoute), route); err != nil {
+			return fmt.Errorf("parsing --route: %w", err)
+		}
+	}
+	return adoptRig(townRoot, args[0], entry, route)
+}
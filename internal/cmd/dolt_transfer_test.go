@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeTestRigsConfig(t *testing.T, townRoot string, rigs map[string]config.RigEntry) {
+	t.Helper()
+	cfg := &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: rigs}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRigRoute(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := beads.WriteRoutes(beads.GetTownBeadsPath(townRoot), []beads.Route{
+		{Prefix: "hq-", Path: "."},
+		{Prefix: "gt-", Path: "gastown/mayor/rig"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	withPrefix := config.RigEntry{BeadsConfig: &config.BeadsConfig{Prefix: "gt"}}
+	if r := rigRoute(townRoot, "gastown", withPrefix); r == nil || r.Path != "gastown/mayor/rig" {
+		t.Errorf("by prefix: %+v", r)
+	}
+	if r := rigRoute(townRoot, "gastown", config.RigEntry{}); r == nil || r.Prefix != "gt-" {
+		t.Errorf("by path: %+v", r)
+	}
+	if r := rigRoute(townRoot, "other", config.RigEntry{}); r != nil {
+		t.Errorf("unknown rig: %+v", r)
+	}
+}
+
+func TestAdoptRig(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestRigsConfig(t, townRoot, map[string]config.RigEntry{})
+
+	entry := &config.RigEntry{GitURL: "https://example.com/gastown.git", BeadsConfig: &config.BeadsConfig{Prefix: "gt"}}
+	route := &beads.Route{Prefix: "gt-", Path: "gastown/mayor/rig"}
+	if err := adoptRig(townRoot, "gastown", entry, route); err != nil {
+		t.Fatalf("adoptRig: %v", err)
+	}
+
+	rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rigs.Rigs["gastown"].GitURL; got != entry.GitURL {
+		t.Errorf("rigs.json git_url = %q", got)
+	}
+	routes, err := beads.LoadRoutes(beads.GetTownBeadsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0] != *route {
+		t.Errorf("routes = %v", routes)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "gastown", ".beads", "metadata.json")); err != nil {
+		t.Errorf("metadata.json not written: %v", err)
+	}
+
+	// Adopting again keeps the destination's existing entry.
+	if err := adoptRig(townRoot, "gastown", &config.RigEntry{GitURL: "https://example.com/other.git"}, route); err != nil {
+		t.Fatal(err)
+	}
+	rigs, _ = config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if got := rigs.Rigs["gastown"].GitURL; got != entry.GitURL {
+		t.Errorf("existing entry overwritten: %q", got)
+	}
+}
@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doltUsersAll  bool
+	doltUsersJSON bool
+
+	doltViewerDB     string
+	doltViewerRotate bool
+)
+
+var doltUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage per-rig SQL users on the Dolt server",
+	Long: `Manage per-rig SQL users on the Dolt server.
+
+By default every rig's bd connects to the shared server as root, so any
+agent can read and write every rig's database. On a shared server, give
+each rig its own user, granted privileges on its database only.
+
+'gt dolt users create <rig>' creates the user gt_<rig> with a generated
+password, stores the password in the town's secrets store as
+dolt-gt_<rig> (see gt secret), and points the rig's metadata.json at the
+user. bd run by gt, and by the rig's agents after their next start, then
+connects as that user. Running create again rotates the password.
+
+To run bd by hand in a rig with its own user, export the password first:
+  export BEADS_DOLT_PASSWORD=$(gt secret get dolt-gt_<rig>)
+
+With no subcommand, lists each rig's user and its grants.
+
+Examples:
+  gt dolt users
+  gt dolt users create gastown
+  gt dolt users create --all
+  gt dolt users remove gastown`,
+	Args: cobra.NoArgs,
+	RunE: runDoltUsersList,
+}
+
+var doltUsersCreateCmd = &cobra.Command{
+	Use:         "create [rig...]",
+	Annotations: auditAnnotations(""),
+	Short:       "Create or rotate scoped SQL users for rigs",
+	Long: `Create a SQL user for each rig, granted privileges on its database
+only, store the generated password in the secrets store, and switch the
+rig's metadata.json to the user. An existing user gets a new password.
+
+The hq database is shared by town-level agents and stays on the server's
+default user.`,
+	RunE: runDoltUsersCreate,
+}
+
+var doltUsersRemoveCmd = &cobra.Command{
+	Use:         "remove <rig>...",
+	Annotations: auditAnnotations(""),
+	Short:       "Switch rigs back to the default user and drop their SQL users",
+	Args:        cobra.MinimumNArgs(1),
+	RunE:        runDoltUsersRemove,
+}
+
+var doltViewerDSNCmd = &cobra.Command{
+	Use:         "viewer-dsn",
+	Annotations: auditAnnotations("rotate"),
+	Short:       "Print a read-only connection string for dashboards",
+	Long: `Print the connection string of the built-in read-only "viewer" user.
+
+The viewer has SELECT on every rig database and hq, and nothing else, so
+dashboards and reporting tools can query beads data without any way to
+change it. The first run creates the user with a generated password,
+stored in the secrets store as dolt-viewer; later runs reuse it. Each run
+also grants SELECT on databases added since, so rerun after adding rigs.
+
+The DSN is in go-sql-driver form (user:password@tcp(host:port)/db), which
+most MySQL clients accept or can be adapted from.
+
+Examples:
+  gt dolt viewer-dsn
+  gt dolt viewer-dsn --db gastown
+  gt dolt viewer-dsn --rotate`,
+	Args: cobra.NoArgs,
+	RunE: runDoltViewerDSN,
+}
+
+func init() {
+	doltUsersCmd.Flags().BoolVar(&doltUsersJSON, "json", false, "Output as JSON")
+	doltUsersCreateCmd.Flags().BoolVar(&doltUsersAll, "all", false, "Create users for every rig database")
+	doltViewerDSNCmd.Flags().StringVar(&doltViewerDB, "db", "", "Database to select in the DSN")
+	doltViewerDSNCmd.Flags().BoolVar(&doltViewerRotate, "rotate", false, "Generate a new viewer password")
+
+	doltUsersCmd.AddCommand(doltUsersCreateCmd)
+	doltUsersCmd.AddCommand(doltUsersRemoveCmd)
+	doltCmd.AddCommand(doltUsersCmd)
+	doltCmd.AddCommand(doltViewerDSNCmd)
+}
+
+// doltUserRow is one rig in gt dolt users output.
+type doltUserRow struct {
+	Rig      string   `json:"rig"`
+	Database string   `json:"database"`
+	User     string   `json:"user"` // from metadata.json; empty for the default user
+	Exists   bool     `json:"exists"`
+	Grants   []string `json:"grants,omitempty"`
+}
+
+// doltUsersTown returns the town root after checking the server is up.
+func doltUsersTown() (string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return "", fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+	return townRoot, nil
+}
+
+// doltUsersRigs resolves rig names to their databases, checking each is
+// served. With all set, every served database but hq is returned.
+func doltUsersRigs(townRoot string, rigs []string, all bool) (map[string]string, error) {
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if all {
+		rigs = nil
+		for _, db := range databases {
+			if db != "hq" && !doltserver.IsSystemDatabase(db) {
+				rigs = append(rigs, db)
+			}
+		}
+	}
+	out := make(map[string]string, len(rigs))
+	for _, rig := range rigs {
+		if rig == "hq" {
+			return nil, fmt.Errorf("hq is shared by town-level agents and stays on the default user")
+		}
+		db := doltserver.RigDatabase(townRoot, rig)
+		if !slices.Contains(databases, db) {
+			return nil, fmt.Errorf("database %q for rig %s not found (see 'gt dolt list')", db, rig)
+		}
+		out[rig] = db
+	}
+	return out, nil
+}
+
+func runDoltUsersList(cmd *cobra.Command, args []string) error {
+	townRoot, err := doltUsersTown()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	users, err := doltserver.ListRigUsers(ctx, townRoot)
+	if err != nil {
+		return err
+	}
+	rigs, err := doltUsersRigs(townRoot, nil, true)
+	if err != nil {
+		return err
+	}
+	var rows []doltUserRow
+	for _, rig := range slices.Sorted(maps.Keys(rigs)) {
+		row := doltUserRow{Rig: rig, Database: rigs[rig]}
+		if meta, err := beads.ReadBackendMetadata(doltserver.FindRigBeadsDir(townRoot, rig)); err == nil {
+			row.User = meta.DoltServerUser
+		}
+		for _, u := range users {
+			if u.User == row.User {
+				row.Exists = true
+				row.Grants = u.Grants
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if doltUsersJSON {
+		if rows == nil {
+			rows = []doltUserRow{}
+		}
+		return printDoltUsersJSON(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Println("No rig databases.")
+		return nil
+	}
+	for _, row := range rows {
+		switch {
+		case row.User == "":
+			fmt.Printf("  %-20s %s\n", row.Rig, style.Dim.Render("default user"))
+		case !row.Exists:
+			fmt.Printf("  %-20s %s %s\n", row.Rig, row.User, style.Error.Render("(missing on server; run gt dolt users create "+row.Rig+")"))
+		default:
+			fmt.Printf("  %-20s %s\n", row.Rig, style.Success.Render(row.User))
+			for _, g := range row.Grants {
+				fmt.Printf("  %-20s   %s\n", "", style.Dim.Render(g))
+			}
+		}
+	}
+	return nil
+}
+
+func runDoltUsersCreate(cmd *cobra.Command, args []string) error {
+	if doltUsersAll == (len(args) > 0) {
+		return fmt.Errorf("name rigs or use --all")
+	}
+	townRoot, err := doltUsersTown()
+	if err != nil {
+		return err
+	}
+	rigs, err := doltUsersRigs(townRoot, args, doltUsersAll)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, rig := range slices.Sorted(maps.Keys(rigs)) {
+		user, err := createDoltRigUser(ctx, townRoot, rig, rigs[rig])
+		if err != nil {
+			return fmt.Errorf("%s: %w", rig, err)
+		}
+		fmt.Printf("%s %s: connects as %s (password in secret %s)\n",
+			style.Success.Render("✓"), rig, user, beads.DoltUserSecret(user))
+	}
+	fmt.Printf("\nRestart the rigs' agents to pick up the new credentials.\n")
+	return nil
+}
+
+// createDoltRigUser creates or rotates rig's user, saves its password and
+// switches the rig's metadata.json to it.
+func createDoltRigUser(ctx context.Context, townRoot, rig, rigDB string) (string, error) {
+	user, err := doltserver.RigUserName(rigDB)
+	if err != nil {
+		return "", err
+	}
+	password, err := doltserver.GeneratePassword()
+	if err != nil {
+		return "", err
+	}
+	if err := doltserver.CreateRigUser(ctx, townRoot, rigDB, user, password); err != nil {
+		return "", err
+	}
+	secret := beads.DoltUserSecret(user)
+	if err := config.StoreSecret(townRoot, secret, password); err != nil {
+		// The server already has the new password, so it must not be lost,
+		// nor printed where terminal logs would keep it.
+		path, saveErr := savePendingSecret(townRoot, secret, password)
+		if saveErr != nil {
+			return "", fmt.Errorf("storing password: %w (and saving it to a file: %v)", err, saveErr)
+		}
+		if !errors.Is(err, config.ErrSecretsReadOnly) {
+			return "", fmt.Errorf("storing password: %w; the new password is in %s", err, path)
+		}
+		style.PrintWarning("%v\n  the new password is in %s; save it as %s with your secrets manager, then delete the file, before restarting agents", err, path, secret)
+	}
+	beadsDir, err := doltserver.FindOrCreateRigBeadsDir(townRoot, rig)
+	if err != nil {
+		return "", err
+	}
+	if err := doltserver.SetMetadataUser(beadsDir, user); err != nil {
+		return "", err
+	}
+	return user, nil
+}
+
+// savePendingSecret writes a secret that couldn't be stored to a 0600 file
+// in the town's settings/pending-secrets directory and returns its path.
+func savePendingSecret(townRoot, name, value string) (string, error) {
+	dir := filepath.Join(townRoot, "settings", "pending-secrets")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if err := util.AtomicWriteFile(path, []byte(value+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func printDoltUsersJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runDoltUsersRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := doltUsersTown()
+	if err != nil {
+		return err
+	}
+	rigs, err := doltUsersRigs(townRoot, args, false)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, rig := range slices.Sorted(maps.Keys(rigs)) {
+		user, err := doltserver.RigUserName(rigs[rig])
+		if err != nil {
+			return fmt.Errorf("%s: %w", rig, err)
+		}
+		// Switch bd back before the user disappears under it.
+		if err := doltserver.SetMetadataUser(doltserver.FindRigBeadsDir(townRoot, rig), ""); err != nil {
+			return fmt.Errorf("%s: %w", rig, err)
+		}
+		if err := doltserver.DropRigUser(ctx, townRoot, user); err != nil {
+			return fmt.Errorf("%s: %w", rig, err)
+		}
+		fmt.Printf("%s %s: back on the default user; dropped %s\n", style.Success.Render("✓"), rig, user)
+	}
+	return nil
+}
+
+func runDoltViewerDSN(cmd *cobra.Command, args []string) error {
+	townRoot, err := doltUsersTown()
+	if err != nil {
+		return err
+	}
+	if doltViewerDB != "" {
+		databases, err := doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing databases: %w", err)
+		}
+		if !slices.Contains(databases, doltViewerDB) || doltserver.IsSystemDatabase(doltViewerDB) {
+			return fmt.Errorf("database %q not found (see 'gt dolt list')", doltViewerDB)
+		}
+	}
+
+	password := ""
+	if !doltViewerRotate {
+		password, _ = config.ResolveSecret(townRoot, doltserver.ViewerSecret)
+	}
+	generated := password == ""
+	if generated {
+		if password, err = doltserver.GeneratePassword(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := doltserver.EnsureViewerUser(ctx, townRoot, password); err != nil {
+		return err
+	}
+	if generated {
+		if err := config.StoreSecret(townRoot, doltserver.ViewerSecret, password); err != nil {
+			if !errors.Is(err, config.ErrSecretsReadOnly) {
+				return fmt.Errorf("storing viewer password: %w", err)
+			}
+			// stderr, so the DSN alone can be captured from stdout
+			fmt.Fprintf(os.Stderr, "%s %v; save %s with your secrets manager or the password changes on every run\n",
+				style.Warning.Render("Warning:"), err, doltserver.ViewerSecret)
+		}
+	}
+	fmt.Println(doltserver.ViewerDSN(townRoot, password, doltViewerDB))
+	return nil
+}
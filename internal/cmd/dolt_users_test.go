@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSavePendingSecret(t *testing.T) {
+	townRoot := t.TempDir()
+	path, err := savePendingSecret(townRoot, "dolt-gt_gastown", "s3cret")
+	if err != nil {
+		t.Fatalf("savePendingSecret() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "s3cret\n" {
+		t.Errorf("pending secret file = %q", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("pending secret file mode = %04o, want 0600", perm)
+	}
+}
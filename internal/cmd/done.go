@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
@@ -18,10 +19,12 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/plugin"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -830,9 +833,13 @@ notifyWitness:
 
 	if bdBranch := os.Getenv("BD_BRANCH"); bdBranch != "" {
 		fmt.Printf("Merging Dolt branch %s to main...\n", bdBranch)
-		if err := doltserver.MergePolecatBranch(townRoot, rigName, bdBranch); err != nil {
+		if err := doltserver.MergePolecatBranchWithPolicy(townRoot, rigName, bdBranch, doltConflictPolicy(townRoot, rigName)); err != nil {
 			mergeFailed = true
 			style.PrintWarning("could not merge Dolt branch: %v (data still on branch %s)", err, bdBranch)
+			var conflictErr *doltserver.MergeConflictError
+			if errors.As(err, &conflictErr) {
+				escalateDoltMergeConflict(townRoot, rigName, polecatName, issueID, conflictErr)
+			}
 		} else {
 			fmt.Printf("%s Dolt branch merged to main\n", style.Bold.Render("✓"))
 		}
@@ -856,6 +863,13 @@ afterDoltMerge:
 		nudgeRefinery(rigName, "MERGE_READY received - check inbox for pending work")
 	}
 
+	// Run plugin polecat-done hooks in the worktree (if it still exists)
+	if cwdAvailable {
+		runPluginHooks(townRoot, rigName, plugin.HookPolecatDone, cwd,
+			"GT_RIG="+rigName, "GT_POLECAT="+polecatName, "GT_ISSUE="+issueID,
+			"GT_BRANCH="+branch, "GT_MR_ID="+mrID, "GT_EXIT_TYPE="+exitType)
+	}
+
 	// Notify Witness about completion
 	// Use town-level beads for cross-agent mail
 	townRouter := mail.NewRouter(townRoot)
@@ -982,6 +996,50 @@ afterDoltMerge:
 	return NewSilentExit(0)
 }
 
+// doltConflictPolicy returns the rig's merge_queue.on_dolt_conflict policy,
+// defaulting to auto-resolving in the polecat's favor.
+func doltConflictPolicy(townRoot, rigName string) string {
+	settingsPath := filepath.Join(townRoot, rigName, "settings", "config.json")
+	if settings, err := config.LoadRigSettings(settingsPath); err == nil && settings.MergeQueue != nil && settings.MergeQueue.OnDoltConflict != "" {
+		return settings.MergeQueue.OnDoltConflict
+	}
+	return config.OnDoltConflictTheirs
+}
+
+// escalateDoltMergeConflict routes a polecat Dolt merge that would conflict
+// to a human via gt escalate. The branch is left intact for them to resolve.
+func escalateDoltMergeConflict(townRoot, rigName, polecatName, issueID string, conflictErr *doltserver.MergeConflictError) {
+	preview := conflictErr.Preview
+	var reason strings.Builder
+	fmt.Fprintf(&reason, "gt done for %s/%s did not merge Dolt branch %s into main: it would conflict in %s.\n",
+		rigName, polecatName, preview.Branch, preview.Summary())
+	if len(preview.Rows) > 0 {
+		reason.WriteString("\nConflicting rows (main vs polecat):\n")
+		for _, r := range preview.Rows {
+			fmt.Fprintf(&reason, "  %s %s: %s vs %s\n", r.Table, r.Key, r.OurChange, r.TheirChange)
+		}
+	}
+	fmt.Fprintf(&reason, "\nThe polecat's bead changes (including any MR bead) are only on that branch.\n"+
+		"Inspect with: gt dolt branches %s %s\n"+
+		"Then merge by hand with gt dolt sql (CALL DOLT_MERGE('%s')) and resolve the conflicts.",
+		rigName, preview.Branch, preview.Branch)
+
+	args := []string{"escalate", fmt.Sprintf("Dolt merge conflict on %s", preview.Branch),
+		"--severity", config.SeverityMedium, "--source", "done:" + rigName + "/" + polecatName,
+		"--reason", reason.String()}
+	if issueID != "" {
+		args = append(args, "--related", issueID)
+	}
+	cmd := exec.Command("gt", args...)
+	cmd.Dir = townRoot
+	cmd.Env = telemetry.WithTraceparent(nil)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		style.PrintWarning("could not escalate Dolt merge conflict: %v (%s)", err, strings.TrimSpace(string(out)))
+		return
+	}
+	fmt.Printf("%s Escalated Dolt merge conflict on %s\n", style.Bold.Render("→"), preview.Branch)
+}
+
 // setDoneIntentLabel writes a done-intent:<type>:<unix-ts> label on the agent bead
 // EARLY in gt done, before push/MR. This allows the Witness to detect polecats that
 // crashed mid-gt-done: if the session is dead but done-intent exists, the polecat was
@@ -36,9 +36,10 @@ const (
 )
 
 var downCmd = &cobra.Command{
-	Use:     "down",
-	GroupID: GroupServices,
-	Short:   "Stop all Gas Town services",
+	Use:         "down",
+	Annotations: auditAnnotations(""),
+	GroupID:     GroupServices,
+	Short:       "Stop all Gas Town services",
 	Long: `Stop Gas Town services (reversible pause).
 
 Shutdown levels (progressively more aggressive):
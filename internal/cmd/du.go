@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	duJSON bool
+	duSort string
+)
+
+// duColumns are the --sort keys, in display order after the rig name.
+var duColumns = []string{"clones", "worktrees", "dolt", "logs", "backups", "other", "total"}
+
+var duCmd = &cobra.Command{
+	Use:     "du [rig...]",
+	GroupID: GroupDiag,
+	Short:   "Show disk usage per rig, by category",
+	Long: `Show how much disk each rig uses, broken down by what you can prune:
+
+  clones     .repo.git, mayor/rig, and crew clones
+  worktrees  refinery/rig and polecat worktrees ('gt rig gc' removes dead ones)
+  dolt       the rig's database in .dolt-data
+  logs       headless session logs and agent transcripts ('gt rig gc --retention')
+  backups    migration and schema backups holding the rig's beads
+  other      config, agent state, overlays
+
+With no arguments every rig is listed, plus a (town) row for town-level
+files outside any rig (the hq database, daemon logs, town backups) and a
+total. Sizes are apparent file sizes.
+
+Examples:
+  gt du
+  gt du gastown --json
+  gt du --sort logs`,
+	RunE: runDu,
+}
+
+func init() {
+	duCmd.Flags().BoolVar(&duJSON, "json", false, "Output as JSON")
+	duCmd.Flags().StringVar(&duSort, "sort", "total", "Sort rigs by: name, "+strings.Join(duColumns, ", ")+" (largest first)")
+
+	rootCmd.AddCommand(duCmd)
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	if duSort != "name" && !slices.Contains(duColumns, duSort) {
+		return fmt.Errorf("invalid --sort %q: use name, %s", duSort, strings.Join(duColumns, ", "))
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	var allNames, rigPaths []string
+	for name := range rigsConfig.Rigs {
+		allNames = append(allNames, name)
+		rigPaths = append(rigPaths, filepath.Join(townRoot, name))
+	}
+	sort.Strings(allNames)
+	names := args
+	for _, name := range names {
+		if _, ok := rigsConfig.Rigs[name]; !ok {
+			return fmt.Errorf("rig '%s' not found", name)
+		}
+	}
+	if len(names) == 0 {
+		names = allNames
+	}
+
+	report := rig.MeasureDiskUsage(townRoot, names, rig.DiskUsageOptions{
+		LogPatterns: func(name string) []string {
+			return gcLogPatterns(townRoot, name, filepath.Join(townRoot, name), rigPaths)
+		},
+	})
+	sortDiskUsage(report.Rigs, duSort)
+	if len(args) > 0 {
+		// The town row is only meaningful when every rig was measured.
+		report.Town = rig.DiskUsage{Name: report.Town.Name}
+		report.Total = rig.DiskUsage{Name: report.Total.Name}
+		for _, u := range report.Rigs {
+			report.Total.Add(u)
+		}
+	}
+
+	if duJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	header := fmt.Sprintf("%-20s", "RIG")
+	for _, c := range duColumns {
+		header += fmt.Sprintf(" %10s", strings.ToUpper(c))
+	}
+	fmt.Println(style.Bold.Render(header))
+	rows := report.Rigs
+	if len(args) == 0 {
+		rows = append(rows, report.Town)
+	}
+	for _, u := range rows {
+		fmt.Println(formatDiskUsageRow(u))
+	}
+	fmt.Println(style.Bold.Render(formatDiskUsageRow(report.Total)))
+	return nil
+}
+
+// diskUsageColumn returns the value of a --sort column.
+func diskUsageColumn(u rig.DiskUsage, column string) int64 {
+	switch column {
+	case "clones":
+		return u.Clones
+	case "worktrees":
+		return u.Worktrees
+	case "dolt":
+		return u.Dolt
+	case "logs":
+		return u.Logs
+	case "backups":
+		return u.Backups
+	case "other":
+		return u.Other
+	default:
+		return u.Total
+	}
+}
+
+// sortDiskUsage orders rigs by name, or largest first by column.
+func sortDiskUsage(rows []rig.DiskUsage, column string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if column == "name" {
+			return rows[i].Name < rows[j].Name
+		}
+		a, b := diskUsageColumn(rows[i], column), diskUsageColumn(rows[j], column)
+		if a != b {
+			return a > b
+		}
+		return rows[i].Name < rows[j].Name
+	})
+}
+
+func formatDiskUsageRow(u rig.DiskUsage) string {
+	row := fmt.Sprintf("%-20s", u.Name)
+	for _, c := range duColumns {
+		row += fmt.Sprintf(" %10s", formatBytes(diskUsageColumn(u, c)))
+	}
+	return row
+}
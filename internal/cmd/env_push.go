@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var envCmd = &cobra.Command{
+	Use:     "env",
+	GroupID: GroupAgents,
+	Short:   "Manage the environment of running agent sessions",
+	RunE:    requireSubcommand,
+}
+
+var envPushCmd = &cobra.Command{
+	Use:   "push [agent...]",
+	Short: "Push current env settings into running agent sessions",
+	Long: `Bring running agent sessions up to date with the environment gt would give
+them if they were started now: GT_ROLE, BD_ACTOR, GT_ROOT and the other
+role variables, and CLAUDE_CONFIG_DIR for the selected account.
+
+For each session whose tmux environment differs, the changes are shown and
+confirmed one agent at a time (--yes accepts all). gt then:
+
+  1. Updates the tmux session environment, which new panes, respawns, and
+     gt's own liveness checks read.
+  2. If the pane is at a shell prompt, exports the new values there.
+  3. If an agent is running, it keeps its old process environment until it
+     restarts. --respawn restarts it now through the handoff respawn path,
+     so it comes back primed with the new values.
+
+Agents are named by session or address (gastown/witness, gastown/crew/max,
+gastown/toast, mayor); with none, every agent in the town (or --rig) is
+checked. --account pushes a specific account instead of the default.
+
+Examples:
+  gt env push --dry-run
+  gt env push --rig gastown
+  gt env push gastown/crew/max --account work --respawn`,
+	RunE: runEnvPush,
+}
+
+var (
+	envPushRig     string
+	envPushDryRun  bool
+	envPushRespawn bool
+	envPushAccount string
+)
+
+func init() {
+	envPushCmd.Flags().StringVar(&envPushRig, "rig", "", "Only push to agents in this rig")
+	envPushCmd.Flags().BoolVarP(&envPushDryRun, "dry-run", "n", false, "Show the changes without applying them")
+	envPushCmd.Flags().BoolVar(&envPushRespawn, "respawn", false, "Restart running agents so their processes get the new values")
+	envPushCmd.Flags().StringVar(&envPushAccount, "account", "", "Account whose CLAUDE_CONFIG_DIR to push (default: GT_ACCOUNT or the default account)")
+
+	envCmd.AddCommand(envPushCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+// envChange is one variable that differs between a session and its
+// expected environment.
+type envChange struct {
+	Key, Old, New string
+	Missing       bool // not set in the session at all
+}
+
+// sessionEnvChanges compares a session's tmux environment against the
+// values gt would set. A variable expected to be empty matches an unset one,
+// since AgentEnv uses empty values only to clear inherited settings.
+func sessionEnvChanges(expected, actual map[string]string) []envChange {
+	var changes []envChange
+	for key, want := range expected {
+		have, ok := actual[key]
+		if have == want && (ok || want == "") {
+			continue
+		}
+		changes = append(changes, envChange{Key: key, Old: have, New: want, Missing: !ok})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// exportCommand is the shell line that sets changes in an interactive shell.
+func exportCommand(changes []envChange) string {
+	env := make(map[string]string, len(changes))
+	for _, c := range changes {
+		env[c.Key] = c.New
+	}
+	return strings.TrimSuffix(config.ExportPrefix(env), " && ")
+}
+
+// expectedSessionEnv returns the environment gt would give a's session now.
+func expectedSessionEnv(t *tmux.Tmux, a *AgentSession, townRoot, configDir string) (map[string]string, error) {
+	identity, err := session.ParseSessionName(a.Name)
+	if err != nil {
+		return nil, err
+	}
+	agent, _ := t.GetEnvironment(a.Name, "GT_AGENT")
+	return config.AgentEnv(config.AgentEnvConfig{
+		Role:             string(identity.Role),
+		Rig:              identity.Rig,
+		AgentName:        identity.Name,
+		TownRoot:         townRoot,
+		RuntimeConfigDir: configDir,
+		Agent:            agent,
+	}), nil
+}
+
+func runEnvPush(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	configDir, _, err := config.ResolveAccountConfigDir(constants.MayorAccountsPath(townRoot), envPushAccount)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
+
+	all, err := getAgentSessions(true)
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	var agents []*AgentSession
+	if len(args) > 0 {
+		for _, target := range args {
+			a := findAgentSession(all, target)
+			if a == nil {
+				return fmt.Errorf("no running agent session %q (see 'gt agents list --all')", target)
+			}
+			agents = append(agents, a)
+		}
+	} else {
+		for _, a := range all {
+			if envPushRig == "" || a.Rig == envPushRig {
+				agents = append(agents, a)
+			}
+		}
+	}
+	if len(agents) == 0 {
+		fmt.Println("No agent sessions running.")
+		return nil
+	}
+
+	t, err := requireTmux("gt env push")
+	if err != nil {
+		return err
+	}
+	var pushed, current, failed int
+	for _, a := range agents {
+		expected, err := expectedSessionEnv(t, a, townRoot, configDir)
+		if err != nil {
+			style.PrintWarning("%s: %v", a.Name, err)
+			failed++
+			continue
+		}
+		actual, err := t.GetAllEnvironment(a.Name)
+		if err != nil {
+			style.PrintWarning("%s: reading environment: %v", a.Name, err)
+			failed++
+			continue
+		}
+		changes := sessionEnvChanges(expected, actual)
+		if len(changes) == 0 {
+			current++
+			continue
+		}
+
+		fmt.Printf("%s %s\n", style.Bold.Render(agentAddress(a)), style.Dim.Render("("+a.Name+")"))
+		for _, c := range changes {
+			if c.Missing {
+				fmt.Printf("  + %s=%q\n", c.Key, c.New)
+			} else {
+				fmt.Printf("  ~ %s: %q → %q\n", c.Key, c.Old, c.New)
+			}
+		}
+		if envPushDryRun {
+			fmt.Println()
+			continue
+		}
+
+		applied, err := pushSessionEnv(t, a, changes)
+		switch {
+		case err != nil:
+			var pe *PromptRequiredError
+			if errors.As(err, &pe) {
+				return err
+			}
+			style.PrintWarning("%s: %v", a.Name, err)
+			failed++
+		case applied:
+			pushed++
+		}
+		fmt.Println()
+	}
+
+	switch {
+	case envPushDryRun:
+		fmt.Printf("Dry run: %d agent(s) up to date, no changes made.\n", current)
+	default:
+		fmt.Printf("%s %d pushed, %d up to date", style.Success.Render("✓"), pushed, current)
+		if failed > 0 {
+			fmt.Printf(", %d failed", failed)
+		}
+		fmt.Println()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d agent(s) could not be updated", failed)
+	}
+	return nil
+}
+
+// pushSessionEnv confirms and applies changes to one agent's session,
+// reporting whether they were applied.
+func pushSessionEnv(t *tmux.Tmux, a *AgentSession, changes []envChange) (bool, error) {
+	question := fmt.Sprintf("Push %d change(s) to %s?", len(changes), agentAddress(a))
+	if envPushRespawn {
+		question = fmt.Sprintf("Push %d change(s) to %s and restart it if an agent is running?", len(changes), agentAddress(a))
+	}
+	ok, err := confirm(confirmPrompt{
+		ID:       PromptEnvPush,
+		Question: question,
+		Safe:     true,
+		Hint:     "pass --yes to push without asking",
+	})
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		fmt.Println("  skipped")
+		return false, nil
+	}
+
+	for _, c := range changes {
+		if err := t.SetEnvironment(a.Name, c.Key, c.New); err != nil {
+			return false, fmt.Errorf("setting %s: %w", c.Key, err)
+		}
+	}
+
+	paneCmd, _ := t.GetPaneCommand(a.Name)
+	switch {
+	case isShellCommand(paneCmd):
+		if err := t.SendKeys(a.Name+":0.0", exportCommand(changes)); err != nil {
+			return false, fmt.Errorf("exporting in shell: %w", err)
+		}
+		fmt.Printf("  %s session env updated and exported in the shell\n", style.Success.Render("✓"))
+	case envPushRespawn:
+		restartCmd, err := buildRestartCommand(a.Name)
+		if err != nil {
+			return false, err
+		}
+		pane, err := getSessionPane(a.Name)
+		if err != nil {
+			return false, fmt.Errorf("getting pane: %w", err)
+		}
+		if err := respawnSessionPane(t, a.Name, pane, restartCmd); err != nil {
+			return false, err
+		}
+		fmt.Printf("  %s session env updated, agent respawned\n", style.Success.Render("✓"))
+	default:
+		fmt.Printf("  %s session env updated; the running %s keeps its old values until restarted (--respawn)\n",
+			style.Success.Render("✓"), paneCmd)
+	}
+	return true, nil
+}
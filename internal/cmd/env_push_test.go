@@ -0,0 +1,38 @@
+package cmd
+
+import "testing"
+
+func TestSessionEnvChanges(t *testing.T) {
+	expected := map[string]string{
+		"BD_ACTOR":     "gastown/witness",
+		"GT_ROOT":      "/town",
+		"CLAUDECODE":   "",
+		"NODE_OPTIONS": "",
+		"GT_RIG":       "gastown",
+	}
+	actual := map[string]string{
+		"BD_ACTOR":     "gastown/witness",
+		"GT_ROOT":      "/old-town",
+		"NODE_OPTIONS": "--inspect",
+		"EXTRA":        "kept",
+	}
+
+	changes := sessionEnvChanges(expected, actual)
+	if len(changes) != 3 {
+		t.Fatalf("changes = %+v, want GT_RIG, GT_ROOT, NODE_OPTIONS", changes)
+	}
+	want := []envChange{
+		{Key: "GT_RIG", New: "gastown", Missing: true},
+		{Key: "GT_ROOT", Old: "/old-town", New: "/town"},
+		{Key: "NODE_OPTIONS", Old: "--inspect", New: ""},
+	}
+	for i, c := range changes {
+		if c != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+
+	if got := exportCommand(changes); got != "export GT_RIG=gastown GT_ROOT=/town NODE_OPTIONS=" {
+		t.Errorf("exportCommand = %q", got)
+	}
+}
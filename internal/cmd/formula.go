@@ -18,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -604,6 +605,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 		}
 
 		slingCmd := exec.Command("gt", slingArgs...)
+		slingCmd.Env = telemetry.WithTraceparent(nil)
 		slingCmd.Stdout = os.Stdout
 		slingCmd.Stderr = os.Stderr
 
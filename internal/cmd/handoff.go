@@ -962,6 +962,26 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 		return nil
 	}
 
+	if err := respawnSessionPane(t, targetSession, targetPane, restartCmd); err != nil {
+		return err
+	}
+
+	// If --watch, switch to that session
+	if handoffWatch {
+		fmt.Printf("Switching to %s...\n", targetSession)
+		// Use tmux switch-client to move our view to the target session
+		if err := exec.Command("tmux", "-u", "switch-client", "-t", targetSession).Run(); err != nil {
+			// Non-fatal - they can manually switch
+			fmt.Printf("Note: Could not auto-switch (use: tmux switch-client -t %s)\n", targetSession)
+		}
+	}
+
+	return nil
+}
+
+// respawnSessionPane restarts a session's pane with restartCmd, killing the
+// pane's processes first so none are orphaned.
+func respawnSessionPane(t *tmux.Tmux, targetSession, targetPane, restartCmd string) error {
 	// Set remain-on-exit so the pane survives process death during handoff.
 	// Without this, killing processes causes tmux to destroy the pane before
 	// we can respawn it. This is essential for tmux session reuse.
@@ -982,7 +1002,7 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 		style.PrintWarning("could not clear history: %v", err)
 	}
 
-	// Respawn the remote session's pane, handling deleted working directories
+	// Respawn the pane, handling deleted working directories
 	respawnErr := func() error {
 		paneWorkDir, _ := t.GetPaneWorkDir(targetSession)
 		if paneWorkDir != "" {
@@ -998,17 +1018,6 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 	if respawnErr != nil {
 		return fmt.Errorf("respawning pane: %w", respawnErr)
 	}
-
-	// If --watch, switch to that session
-	if handoffWatch {
-		fmt.Printf("Switching to %s...\n", targetSession)
-		// Use tmux switch-client to move our view to the target session
-		if err := exec.Command("tmux", "-u", "switch-client", "-t", targetSession).Run(); err != nil {
-			// Non-fatal - they can manually switch
-			fmt.Printf("Note: Could not auto-switch (use: tmux switch-client -t %s)\n", targetSession)
-		}
-	}
-
 	return nil
 }
 
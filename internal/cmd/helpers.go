@@ -11,8 +11,11 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // inferRigFromCwd tries to determine the rig from the current directory.
@@ -79,7 +82,18 @@ func isInTmuxSession(targetSession string) bool {
 // Uses syscall.Exec to replace the Go process with tmux for direct terminal
 // control, and passes -u for UTF-8 support regardless of locale settings.
 // See: https://github.com/steveyegge/gastown/issues/1219
+//
+// Towns configured with another multiplexer (or headless mode) attach
+// through that backend instead.
 func attachToTmuxSession(sessionID string) error {
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if m, err := multiplexer.ForTown(townRoot); err == nil {
+			if _, isTmux := m.(*tmux.Tmux); !isTmux {
+				return multiplexer.Attach(m, sessionID)
+			}
+		}
+	}
+
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return fmt.Errorf("tmux not found: %w", err)
@@ -99,6 +113,22 @@ func attachToTmuxSession(sessionID string) error {
 	return syscall.Exec(tmuxPath, args, os.Environ())
 }
 
+// requireTmux returns a tmux client for commands that only work on tmux
+// sessions (pane processes, session environment). It fails, naming what,
+// when the current town runs its agents under another multiplexer.
+func requireTmux(what string) (*tmux.Tmux, error) {
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading town settings: %w", err)
+		}
+		if name := settings.Multiplexer; name != "" && name != multiplexer.NameTmux {
+			return nil, fmt.Errorf("%s requires tmux, but this town runs its agents under %s", what, name)
+		}
+	}
+	return tmux.NewTmux(), nil
+}
+
 // isShellCommand checks if the command is a shell (meaning the runtime has exited).
 func isShellCommand(cmd string) bool {
 	shells := constants.SupportedShells
@@ -18,6 +18,7 @@ Subcommands:
   base       Edit the shared base hook config
   override   Edit overrides for a role or rig
   sync       Regenerate all .claude/settings.json files
+  status     Show hook versions and drift per target
   diff       Show what sync would change
   list       Show all managed settings.json locations
   scan       Scan workspace for existing hooks
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	hooksStatusRig    string
+	hooksStatusTarget string
+	hooksStatusJSON   bool
+)
+
+var hooksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show hook versions and drift per target",
+	Long: `Show the hooks version installed at each target and whether it has drifted.
+
+Each target's hooks section is identified by a short content hash. The
+expected version is computed from base + overrides; the installed version
+from the target's settings.json.
+
+States:
+  in sync    Installed hooks match base + overrides
+  stale      Base or overrides changed since the last sync (gt hooks sync)
+  modified   settings.json was edited since the last sync (sync needs --force)
+  missing    settings.json does not exist
+
+Examples:
+  gt hooks status                  # All targets
+  gt hooks status --rig gastown    # One rig's targets
+  gt hooks status --target crew    # Crew targets in every rig
+  gt hooks status --json`,
+	RunE: runHooksStatus,
+}
+
+func init() {
+	hooksCmd.AddCommand(hooksStatusCmd)
+	hooksStatusCmd.Flags().StringVar(&hooksStatusRig, "rig", "", "Only show targets in this rig")
+	hooksStatusCmd.Flags().StringVar(&hooksStatusTarget, "target", "", "Only show this target (e.g., crew, gastown/witness, mayor)")
+	hooksStatusCmd.Flags().BoolVar(&hooksStatusJSON, "json", false, "Output as JSON")
+}
+
+// hookTargetStatus is the version and drift of one target.
+type hookTargetStatus struct {
+	Target          string   `json:"target"`
+	Path            string   `json:"path"`
+	State           string   `json:"state"`
+	Version         string   `json:"version,omitempty"`
+	ExpectedVersion string   `json:"expected_version"`
+	SyncedVersion   string   `json:"synced_version,omitempty"`
+	SyncedAt        string   `json:"synced_at,omitempty"`
+	DriftedEvents   []string `json:"drifted_events,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+func runHooksStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	targets, err := hooks.DiscoverTargets(townRoot)
+	if err != nil {
+		return fmt.Errorf("discovering targets: %w", err)
+	}
+	targets, err = filterHookTargets(targets, hooksStatusRig, hooksStatusTarget)
+	if err != nil {
+		return err
+	}
+
+	state, err := hooks.LoadSyncState()
+	if err != nil {
+		return fmt.Errorf("loading sync state: %w", err)
+	}
+
+	statuses := make([]hookTargetStatus, 0, len(targets))
+	for _, target := range targets {
+		statuses = append(statuses, buildHookTargetStatus(target, state))
+	}
+
+	if hooksStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	drifted := 0
+	for _, s := range statuses {
+		relPath, err := filepath.Rel(townRoot, s.Path)
+		if err != nil {
+			relPath = s.Path
+		}
+		version := s.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Printf("%s %s  %s\n", renderHookDrift(s.State), style.Bold.Render(s.Target), style.Dim.Render(relPath))
+		fmt.Printf("    installed %s  expected %s", version, s.ExpectedVersion)
+		if s.SyncedVersion != "" {
+			fmt.Printf("  %s", style.Dim.Render(fmt.Sprintf("last sync %s at %s", s.SyncedVersion, s.SyncedAt)))
+		}
+		fmt.Println()
+		if len(s.DriftedEvents) > 0 {
+			fmt.Printf("    drift: %s\n", strings.Join(s.DriftedEvents, ", "))
+		}
+		if s.Error != "" {
+			fmt.Printf("    %s\n", style.Error.Render(s.Error))
+		}
+		if s.State != hooks.DriftInSync {
+			drifted++
+		}
+	}
+
+	fmt.Println()
+	if drifted == 0 {
+		fmt.Printf("%s All %d target(s) in sync\n", style.Success.Render("✓"), len(statuses))
+	} else {
+		fmt.Printf("%s %d of %d target(s) drifted; run 'gt hooks sync' to roll out\n",
+			style.Warning.Render("⚠"), drifted, len(statuses))
+	}
+	return nil
+}
+
+// buildHookTargetStatus computes the version and drift of target.
+func buildHookTargetStatus(target hooks.Target, state map[string]hooks.SyncRecord) hookTargetStatus {
+	s := hookTargetStatus{Target: target.DisplayKey(), Path: target.Path}
+
+	expected, err := hooks.ComputeExpected(target.Key)
+	if err != nil {
+		s.State = "error"
+		s.Error = fmt.Sprintf("computing expected config: %v", err)
+		return s
+	}
+	s.ExpectedVersion = hooks.Version(expected)
+
+	var rec *hooks.SyncRecord
+	if r, ok := state[target.Path]; ok {
+		rec = &r
+		s.SyncedVersion = r.Version
+		s.SyncedAt = r.SyncedAt.Local().Format("2006-01-02 15:04")
+	}
+
+	_, statErr := os.Stat(target.Path)
+	exists := statErr == nil
+	current, err := hooks.LoadSettings(target.Path)
+	if err != nil {
+		s.State = "error"
+		s.Error = fmt.Sprintf("loading settings: %v", err)
+		return s
+	}
+	if exists {
+		s.Version = hooks.Version(&current.Hooks)
+		s.DriftedEvents = hooks.DriftedEvents(&current.Hooks, expected)
+	}
+	s.State = hooks.TargetDrift(exists, &current.Hooks, expected, rec)
+	return s
+}
+
+// filterHookTargets narrows targets to those in rig and matching target.
+// target may be a role ("crew", "polecat"), which selects that role in every
+// rig, or a full key ("gastown/crew", "mayor").
+func filterHookTargets(targets []hooks.Target, rig, target string) ([]hooks.Target, error) {
+	if target != "" {
+		normalized, ok := hooks.NormalizeTarget(target)
+		if !ok {
+			return nil, fmt.Errorf("invalid target %q", target)
+		}
+		target = normalized
+	}
+
+	var filtered []hooks.Target
+	for _, t := range targets {
+		if rig != "" && t.Rig != rig {
+			continue
+		}
+		if target != "" && t.Key != target && !strings.HasSuffix(t.Key, "/"+target) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	if len(filtered) == 0 && (rig != "" || target != "") {
+		return nil, fmt.Errorf("no targets match (rig %q, target %q)", rig, target)
+	}
+	return filtered, nil
+}
+
+func renderHookDrift(state string) string {
+	switch state {
+	case hooks.DriftInSync:
+		return style.Success.Render("✓")
+	case hooks.DriftStale:
+		return style.Warning.Render("⚠ stale")
+	case hooks.DriftModified:
+		return style.Warning.Render("✎ modified")
+	case hooks.DriftMissing:
+		return style.Dim.Render("- missing")
+	default:
+		return style.Error.Render("✖ " + state)
+	}
+}
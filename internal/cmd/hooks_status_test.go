@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+func TestFilterHookTargets(t *testing.T) {
+	targets := []hooks.Target{
+		{Key: "mayor"},
+		{Key: "gastown/crew", Rig: "gastown"},
+		{Key: "gastown/polecats", Rig: "gastown"},
+		{Key: "beads/crew", Rig: "beads"},
+	}
+
+	keys := func(ts []hooks.Target) []string {
+		var out []string
+		for _, t := range ts {
+			out = append(out, t.Key)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name, rig, target string
+		want              []string
+	}{
+		{"no filter", "", "", []string{"mayor", "gastown/crew", "gastown/polecats", "beads/crew"}},
+		{"rig", "gastown", "", []string{"gastown/crew", "gastown/polecats"}},
+		{"role in every rig", "", "crew", []string{"gastown/crew", "beads/crew"}},
+		{"singular alias", "", "polecat", []string{"gastown/polecats"}},
+		{"full key", "", "beads/crew", []string{"beads/crew"}},
+		{"rig and role", "beads", "crew", []string{"beads/crew"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterHookTargets(targets, tt.rig, tt.target)
+			if err != nil {
+				t.Fatalf("filterHookTargets: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", keys(got), tt.want)
+			}
+			for i := range got {
+				if got[i].Key != tt.want[i] {
+					t.Fatalf("got %v, want %v", keys(got), tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := filterHookTargets(targets, "nope", ""); err == nil {
+		t.Error("expected error when nothing matches")
+	}
+	if _, err := filterHookTargets(targets, "", "bogus"); err == nil {
+		t.Error("expected error for invalid target")
+	}
+}
+
+func TestHookTargetStatusDetectsHandEdits(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	base := &hooks.HooksConfig{
+		SessionStart: []hooks.HookEntry{{Hooks: []hooks.Hook{{Type: "command", Command: "gt prime"}}}},
+	}
+	if err := hooks.SaveBase(base); err != nil {
+		t.Fatalf("SaveBase: %v", err)
+	}
+	target := hooks.Target{Path: filepath.Join(tmpDir, "rig", "witness", ".claude", "settings.json"), Key: "rig/witness", Rig: "rig"}
+
+	if _, err := syncTarget(target, false); err != nil {
+		t.Fatalf("syncTarget: %v", err)
+	}
+	state, _ := hooks.LoadSyncState()
+	if got := buildHookTargetStatus(target, state).State; got != hooks.DriftInSync {
+		t.Fatalf("after sync: state = %q, want in sync", got)
+	}
+
+	// Edit the installed hooks by hand.
+	edited := `{"hooks":{"SessionStart":[{"matcher":"","hooks":[{"type":"command","command":"echo mine"}]}]}}`
+	if err := os.WriteFile(target.Path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := buildHookTargetStatus(target, state)
+	if s.State != hooks.DriftModified {
+		t.Errorf("after edit: state = %q, want modified", s.State)
+	}
+	if len(s.DriftedEvents) == 0 || s.DriftedEvents[0] != "SessionStart" {
+		t.Errorf("drifted events = %v, want [SessionStart]", s.DriftedEvents)
+	}
+}
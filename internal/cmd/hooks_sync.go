@@ -11,7 +11,12 @@ import (
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-var hooksSyncDryRun bool
+var (
+	hooksSyncDryRun bool
+	hooksSyncRig    string
+	hooksSyncTarget string
+	hooksSyncForce  bool
+)
 
 var hooksSyncCmd = &cobra.Command{
 	Use:   "sync",
@@ -25,15 +30,24 @@ For each target (mayor, deacon, rig/crew, rig/witness, etc.):
 4. Merge hooks section into existing settings.json (preserving all fields)
 5. Write updated settings.json
 
+Targets whose settings.json was edited since the last sync are skipped
+unless --force is given. Use 'gt hooks status' to see each target's drift.
+
 Examples:
-  gt hooks sync             # Regenerate all settings.json files
-  gt hooks sync --dry-run   # Show what would change without writing`,
+  gt hooks sync                     # Regenerate all settings.json files
+  gt hooks sync --dry-run           # Show what would change without writing
+  gt hooks sync --rig gastown       # Only one rig's targets
+  gt hooks sync --target witness    # Only witnesses, in every rig
+  gt hooks sync --force             # Also overwrite hand-edited targets`,
 	RunE: runHooksSync,
 }
 
 func init() {
 	hooksCmd.AddCommand(hooksSyncCmd)
 	hooksSyncCmd.Flags().BoolVar(&hooksSyncDryRun, "dry-run", false, "Show what would change without writing")
+	hooksSyncCmd.Flags().StringVar(&hooksSyncRig, "rig", "", "Only sync targets in this rig")
+	hooksSyncCmd.Flags().StringVar(&hooksSyncTarget, "target", "", "Only sync this target (e.g., crew, gastown/witness, mayor)")
+	hooksSyncCmd.Flags().BoolVar(&hooksSyncForce, "force", false, "Overwrite targets edited since the last sync")
 }
 
 func runHooksSync(cmd *cobra.Command, args []string) error {
@@ -46,6 +60,15 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("discovering targets: %w", err)
 	}
+	targets, err = filterHookTargets(targets, hooksSyncRig, hooksSyncTarget)
+	if err != nil {
+		return err
+	}
+
+	state, err := hooks.LoadSyncState()
+	if err != nil {
+		return fmt.Errorf("loading sync state: %w", err)
+	}
 
 	if hooksSyncDryRun {
 		fmt.Println("Dry run - showing what would change...")
@@ -57,9 +80,16 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 	updated := 0
 	unchanged := 0
 	created := 0
+	skipped := 0
 	errors := 0
 
 	for _, target := range targets {
+		if !hooksSyncForce && buildHookTargetStatus(target, state).State == hooks.DriftModified {
+			fmt.Printf("  %s %s %s\n", style.Warning.Render("✎"), target.DisplayKey(), style.Dim.Render("(edited since last sync, skipped; use --force)"))
+			skipped++
+			continue
+		}
+
 		result, err := syncTarget(target, hooksSyncDryRun)
 		if err != nil {
 			fmt.Printf("  %s %s: %v\n", style.Error.Render("✖"), target.DisplayKey(), err)
@@ -95,7 +125,7 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 
 	// Summary
 	fmt.Println()
-	total := updated + unchanged + created + errors
+	total := updated + unchanged + created + skipped + errors
 	if hooksSyncDryRun {
 		fmt.Printf("Would sync %d targets (%d to create, %d to update, %d unchanged",
 			total, created, updated, unchanged)
@@ -103,6 +133,9 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Synced %d targets (%d created, %d updated, %d unchanged",
 			total, created, updated, unchanged)
 	}
+	if skipped > 0 {
+		fmt.Printf(", %s", style.Warning.Render(fmt.Sprintf("%d skipped", skipped)))
+	}
 	if errors > 0 {
 		fmt.Printf(", %s", style.Error.Render(fmt.Sprintf("%d errors", errors)))
 	}
@@ -121,6 +154,8 @@ const (
 
 // syncTarget syncs a single target's .claude/settings.json.
 // Uses MarshalSettings/UnmarshalSettings to preserve unknown fields.
+// Unless dryRun is set, the resulting hooks version is recorded so later
+// edits to the file show up as drift.
 func syncTarget(target hooks.Target, dryRun bool) (syncResult, error) {
 	// Compute expected hooks for this target
 	expected, err := hooks.ComputeExpected(target.Key)
@@ -140,6 +175,9 @@ func syncTarget(target hooks.Target, dryRun bool) (syncResult, error) {
 
 	// Compare hooks sections
 	if fileExists && hooks.HooksEqual(expected, &current.Hooks) {
+		if !dryRun {
+			recordHookSync(target, expected)
+		}
 		return syncUnchanged, nil
 	}
 
@@ -175,9 +213,19 @@ func syncTarget(target hooks.Target, dryRun bool) (syncResult, error) {
 	if err := os.WriteFile(target.Path, data, 0644); err != nil {
 		return 0, fmt.Errorf("writing settings: %w", err)
 	}
+	recordHookSync(target, expected)
 
 	if fileExists {
 		return syncUpdated, nil
 	}
 	return syncCreated, nil
 }
+
+// recordHookSync records the hooks version now installed at target.
+// Best-effort: the settings file is already correct, only drift detection
+// is affected.
+func recordHookSync(target hooks.Target, installed *hooks.HooksConfig) {
+	if err := hooks.RecordSync(target.Path, hooks.Version(installed)); err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: could not record hook sync for %s: %v\n", target.DisplayKey(), err)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	incidentsListRig      string
+	incidentsListType     string
+	incidentsListSeverity string
+	incidentsListSince    string
+	incidentsListAll      bool
+	incidentsListJSON     bool
+)
+
+var incidentsCmd = &cobra.Command{
+	Use:     "incidents",
+	GroupID: GroupDiag,
+	Short:   "Structured incident reports from witnesses",
+	RunE:    requireSubcommand,
+	Long: `Inspect incidents reported by rig witnesses.
+
+When a witness detects an anomaly it files an incident bead (gt:incident label)
+with a type, severity, affected agents, and an evidence excerpt. High and
+critical incidents are also filed as escalations related to the incident, so
+they show up in 'gt escalate list' and are re-escalated when stale.
+
+INCIDENT TYPES:
+  agent-dead      Session or agent process gone while work was in flight
+  stuck-polecat   Live session not making progress (e.g. blocked on a prompt)
+  test-break      Merge rejected because tests failed
+  merge-failure   Merge rejected for another reason (build, lint, conflict)`,
+}
+
+var incidentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List incidents",
+	Long: `List open incidents, newest first.
+
+Examples:
+  gt incidents list
+  gt incidents list --rig gastown --severity high
+  gt incidents list --type test-break --since 7d --all
+  gt incidents list --json`,
+	Args: cobra.NoArgs,
+	RunE: runIncidentsList,
+}
+
+func init() {
+	incidentsListCmd.Flags().StringVar(&incidentsListRig, "rig", "", "Only incidents in this rig")
+	incidentsListCmd.Flags().StringVar(&incidentsListType, "type", "", "Only incidents of this type")
+	incidentsListCmd.Flags().StringVar(&incidentsListSeverity, "severity", "", "Only incidents of this severity")
+	incidentsListCmd.Flags().StringVar(&incidentsListSince, "since", "", "Only incidents detected within this duration (e.g. 24h, 7d)")
+	incidentsListCmd.Flags().BoolVar(&incidentsListAll, "all", false, "Include closed incidents")
+	incidentsListCmd.Flags().BoolVar(&incidentsListJSON, "json", false, "Output as JSON")
+
+	incidentsCmd.AddCommand(incidentsListCmd)
+	rootCmd.AddCommand(incidentsCmd)
+}
+
+func runIncidentsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := witness.IncidentFilter{
+		Rig:      incidentsListRig,
+		Type:     witness.IncidentType(incidentsListType),
+		Severity: strings.ToLower(incidentsListSeverity),
+		All:      incidentsListAll,
+	}
+	if filter.Type != "" && !witness.IsValidIncidentType(filter.Type) {
+		return fmt.Errorf("invalid type '%s': must be agent-dead, stuck-polecat, test-break, or merge-failure", incidentsListType)
+	}
+	if filter.Severity != "" && !config.IsValidSeverity(filter.Severity) {
+		return fmt.Errorf("invalid severity '%s': must be critical, high, medium, or low", incidentsListSeverity)
+	}
+	if incidentsListSince != "" {
+		d, err := parseDuration(incidentsListSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+
+	incidents, err := witness.ListIncidents(townRoot, filter)
+	if err != nil {
+		return err
+	}
+
+	if incidentsListJSON {
+		if incidents == nil {
+			incidents = []witness.IncidentRecord{}
+		}
+		out, err := json.MarshalIndent(incidents, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling incidents: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(incidents) == 0 {
+		fmt.Println("No incidents found")
+		return nil
+	}
+
+	fmt.Printf("Incidents (%d):\n\n", len(incidents))
+	for _, inc := range incidents {
+		fmt.Printf("  %s %s [%s] %s\n", severityEmoji(inc.Severity), inc.ID, inc.Status, inc.Summary)
+		fmt.Printf("     %s | %s | %s | %s\n", inc.Type, inc.Severity, inc.Rig,
+			formatRelativeTime(inc.DetectedAt.Format(time.RFC3339)))
+		if len(inc.Agents) > 0 {
+			fmt.Printf("     Agents: %s\n", strings.Join(inc.Agents, ", "))
+		}
+		if inc.RelatedBead != "" {
+			fmt.Printf("     Related: %s\n", inc.RelatedBead)
+		}
+		if line := lastEvidenceLine(inc.Evidence); line != "" {
+			fmt.Printf("     %s\n", style.Dim.Render(line))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// lastEvidenceLine returns the last non-empty evidence line, shortened for
+// the list view.
+func lastEvidenceLine(evidence string) string {
+	lines := strings.Split(strings.TrimSpace(evidence), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if len(line) > 100 {
+		line = line[:97] + "..."
+	}
+	return line
+}
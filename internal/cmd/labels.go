@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	labelsRig     string
+	labelsJSON    bool
+	labelsMigrate bool
+	labelsDryRun  bool
+)
+
+var labelsCmd = &cobra.Command{
+	Use:     "labels",
+	Aliases: []string{"label"},
+	GroupID: GroupWork,
+	Short:   "Manage a rig's label taxonomy",
+	RunE:    requireSubcommand,
+	Long: `Manage a rig's label taxonomy.
+
+The taxonomy lives under "labels" in the rig's settings/config.json: the
+allowed values of each namespace (status:docked is namespace "status",
+value "docked"), allowed labels without a namespace, and color hints.
+When gt adds a label outside it, it warns and suggests the closest
+allowed label, or refuses with "enforce": "strict".
+
+Commands:
+  gt labels list --rig <rig>                 Show the taxonomy and labels in use
+  gt labels rename <old> <new> --rig <rig>   Rename a label`,
+}
+
+var labelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the taxonomy and the labels in use",
+	Long: `Show the rig's label taxonomy and every label on its beads, with how
+many beads carry it. Labels outside the taxonomy are flagged with the
+closest allowed label.
+
+Examples:
+  gt labels list --rig gastown
+  gt labels list --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runLabelsList,
+}
+
+var labelsRenameCmd = &cobra.Command{
+	Use:         "rename <old> <new>",
+	Annotations: auditAnnotations(""),
+	Short:       "Rename a label in the taxonomy, and on beads with --migrate",
+	Long: `Rename a label in the rig's taxonomy. With --migrate, also rewrite every
+bead carrying the old label, open or closed, to carry the new one.
+
+--migrate works for labels the taxonomy doesn't declare, which is how
+typos already on beads are cleaned up.
+
+Examples:
+  gt labels rename status:dock status:docked --rig gastown --migrate
+  gt labels rename flakey flaky --rig gastown --migrate --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runLabelsRename,
+}
+
+func init() {
+	labelsCmd.PersistentFlags().StringVar(&labelsRig, "rig", "", "Rig whose labels to manage (required)")
+	_ = labelsCmd.MarkPersistentFlagRequired("rig")
+	labelsListCmd.Flags().BoolVar(&labelsJSON, "json", false, "Output as JSON")
+	labelsRenameCmd.Flags().BoolVar(&labelsMigrate, "migrate", false, "Also rewrite beads carrying the old label")
+	labelsRenameCmd.Flags().BoolVar(&labelsDryRun, "dry-run", false, "Show what would change without changing it")
+
+	labelsCmd.AddCommand(labelsListCmd)
+	labelsCmd.AddCommand(labelsRenameCmd)
+	rootCmd.AddCommand(labelsCmd)
+}
+
+// labelUsage is one label on a rig's beads.
+type labelUsage struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"` // why the taxonomy rejects it
+}
+
+func runLabelsList(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(labelsRig)
+	if err != nil {
+		return err
+	}
+	taxonomy := config.LoadLabelsConfig(r.Path)
+
+	issues, err := beads.New(r.BeadsPath()).List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing beads: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		for _, label := range issue.Labels {
+			counts[label]++
+		}
+	}
+	usage := make([]labelUsage, 0, len(counts))
+	for label, n := range counts {
+		u := labelUsage{Label: label, Count: n}
+		if err := taxonomy.CheckLabel(label); err != nil {
+			u.Error = strings.TrimPrefix(err.Error(), config.ErrLabelNotAllowed.Error()+": ")
+		}
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Label < usage[j].Label })
+
+	if labelsJSON {
+		out := struct {
+			Rig      string               `json:"rig"`
+			Taxonomy *config.LabelsConfig `json:"taxonomy,omitempty"`
+			InUse    []labelUsage         `json:"in_use"`
+		}{r.Name, taxonomy, usage}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if taxonomy == nil {
+		fmt.Printf("%s\n", style.Dim.Render("(no label taxonomy; any label is allowed)"))
+	} else {
+		enforce := taxonomy.Enforce
+		if enforce == "" {
+			enforce = config.LabelsWarn
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("Taxonomy"), style.Dim.Render("(enforce: "+enforce+")"))
+		for _, label := range taxonomy.AllowedLabels() {
+			fmt.Printf("  %s%s\n", renderLabel(taxonomy, label), labelDescription(taxonomy, label))
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("In use"))
+	if len(usage) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no labels)"))
+	}
+	for _, u := range usage {
+		line := fmt.Sprintf("  %-30s %5d", u.Label, u.Count)
+		if u.Error != "" {
+			line += " " + style.Warning.Render("⚠ "+u.Error)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// renderLabel colors label with its color hint, if it has one.
+func renderLabel(taxonomy *config.LabelsConfig, label string) string {
+	color := taxonomy.LabelColor(label)
+	if color == "" {
+		return label
+	}
+	if code, ok := ansiColorCodes[color]; ok {
+		color = code
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(label)
+}
+
+// ansiColorCodes maps the basic color names a taxonomy may use to ANSI codes.
+var ansiColorCodes = map[string]string{
+	"black": "0", "red": "1", "green": "2", "yellow": "3",
+	"blue": "4", "magenta": "5", "cyan": "6", "white": "7", "gray": "8",
+}
+
+// labelDescription returns the dimmed description of label or its
+// namespace, with a leading separator, or "".
+func labelDescription(taxonomy *config.LabelsConfig, label string) string {
+	desc := ""
+	if info := taxonomy.Labels[label]; info != nil {
+		desc = info.Description
+	} else if ns, _, ok := strings.Cut(label, ":"); ok {
+		if rule := taxonomy.Namespaces[ns]; rule != nil {
+			desc = rule.Description
+		}
+	}
+	if desc == "" {
+		return ""
+	}
+	return style.Dim.Render(" — " + desc)
+}
+
+func runLabelsRename(cmd *cobra.Command, args []string) error {
+	oldLabel, newLabel := args[0], args[1]
+	if newLabel == "" || strings.ContainsAny(newLabel, " ,") {
+		return fmt.Errorf("invalid label %q", newLabel)
+	}
+	if oldLabel == newLabel {
+		return fmt.Errorf("%q is already the label's name", newLabel)
+	}
+
+	_, r, err := getRig(labelsRig)
+	if err != nil {
+		return err
+	}
+	settingsPath := config.RigSettingsPath(r.Path)
+	settings, err := config.LoadRigSettings(settingsPath)
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return err
+	}
+	renamed := settings != nil && settings.Labels.RenameLabel(oldLabel, newLabel)
+	if !renamed && !labelsMigrate {
+		return fmt.Errorf("label %q is not in %s's taxonomy (use --migrate to rewrite beads carrying it)", oldLabel, r.Name)
+	}
+
+	prefix := ""
+	if labelsDryRun {
+		prefix = "[dry-run] "
+	}
+	if renamed {
+		if !labelsDryRun {
+			if err := settings.Labels.Validate(); err != nil {
+				return err
+			}
+			if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+				return fmt.Errorf("saving settings: %w", err)
+			}
+		}
+		fmt.Printf("%s%s Renamed %s to %s in the taxonomy\n", prefix, style.Success.Render("✓"), oldLabel, newLabel)
+	}
+	if !labelsMigrate {
+		return nil
+	}
+
+	bd := beads.New(r.BeadsPath())
+	issues, err := bd.List(beads.ListOptions{Label: oldLabel, Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing beads labeled %s: %w", oldLabel, err)
+	}
+	var failed int
+	for _, issue := range issues {
+		if labelsDryRun {
+			fmt.Printf("%s  %s %s\n", prefix, issue.ID, style.Dim.Render(issue.Title))
+			continue
+		}
+		if err := bd.Update(issue.ID, beads.UpdateOptions{
+			AddLabels:    []string{newLabel},
+			RemoveLabels: []string{oldLabel},
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", style.Warning.Render("⚠"), issue.ID, err)
+			failed++
+		}
+	}
+	fmt.Printf("%s%s Relabeled %d bead(s) from %s to %s\n", prefix, style.Success.Render("✓"), len(issues)-failed, oldLabel, newLabel)
+	if failed > 0 {
+		return fmt.Errorf("%d bead(s) could not be relabeled", failed)
+	}
+	return nil
+}
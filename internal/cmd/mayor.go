@@ -192,11 +192,20 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 				Topic:     "attach",
 			})
 
-			// Build startup command with beacon
+			// Build startup command with beacon. Secrets reach the agent's
+			// environment through a private env file, never an argv.
+			secrets, err := config.StartupSecrets("mayor", townRoot, "", mayorAgentOverride)
+			if err != nil {
+				return fmt.Errorf("resolving secrets: %w", err)
+			}
 			startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("mayor", "", townRoot, "", beacon, mayorAgentOverride)
 			if err != nil {
 				return fmt.Errorf("building startup command: %w", err)
 			}
+			startupCmd, err = t.WithSecrets(startupCmd, secrets)
+			if err != nil {
+				return err
+			}
 
 			// Set remain-on-exit so the pane survives process death during respawn.
 			// Without this, killing processes causes tmux to destroy the pane.
@@ -59,16 +59,19 @@ var (
 
 var mqCmd = &cobra.Command{
 	Use:     "mq",
-	Aliases: []string{"mr"},
+	Aliases: []string{"mr", "queue"},
 	GroupID: GroupWork,
 	Short:   "Merge queue operations",
 	RunE:    requireSubcommand,
 	Long: `Manage merge requests and the merge queue for a rig.
 
-Alias: 'gt mr' is equivalent to 'gt mq' (merge request vs merge queue).
+Alias: 'gt mr' and 'gt queue' are equivalent to 'gt mq'.
 
 The merge queue tracks work branches from polecats waiting to be merged.
-Use these commands to view, submit, retry, and manage merge requests.`,
+Use these commands to view, submit, retry, and manage merge requests.
+
+Urgent work (hotfixes) can jump the queue: 'gt mq promote <branch>' moves
+an MR into the expedite lane, which is merged ahead of the normal lane.`,
 }
 
 var mqSubmitCmd = &cobra.Command{
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -30,6 +31,9 @@ The priority scoring function considers:
 
 Use --strategy=fifo for first-in-first-out ordering instead.
 
+Either way, expedited MRs (see 'gt mq promote') come before the normal lane,
+unless a normal MR has waited longer than merge_queue.normal_lane_max_wait.
+
 Examples:
   gt mq next gastown                    # Show highest-priority MR
   gt mq next gastown --strategy=fifo    # Show oldest MR instead
@@ -55,6 +59,12 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	mqCfg := eng.Config()
+
 	// Create beads wrapper for the rig
 	b := beads.New(r.BeadsPath())
 
@@ -123,6 +133,11 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Lanes take precedence over the strategy's order
+	sort.SliceStable(ready, func(i, j int) bool {
+		return mqLaneRank(mqCfg, ready[i], now) < mqLaneRank(mqCfg, ready[j], now)
+	})
+
 	// Get the top MR
 	next := ready[0]
 	fields := beads.ParseMRFields(next)
@@ -138,6 +153,9 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
+	if pause := eng.Paused(); pause != nil {
+		fmt.Printf("%s Merge queue %s\n\n", style.Warning.Render("⏸"), formatQueuePause(pause))
+	}
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
 	score := calculateMRScore(next, fields, now)
@@ -145,6 +163,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  ID:       %s\n", next.ID)
 	fmt.Printf("  Score:    %.1f\n", score)
 	fmt.Printf("  Priority: P%d\n", next.Priority)
+	fmt.Printf("  Lane:     %s\n", mqCfg.LaneOf(next.Labels))
 
 	if fields != nil {
 		if fields.Branch != "" {
@@ -169,3 +188,9 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// mqLaneRank is the merge queue lane rank of an MR bead (lower goes first).
+func mqLaneRank(cfg *refinery.MergeQueueConfig, issue *beads.Issue, now time.Time) int {
+	createdAt, _ := time.Parse(time.RFC3339, issue.CreatedAt)
+	return cfg.LaneRank(cfg.LaneOf(issue.Labels), createdAt, now)
+}
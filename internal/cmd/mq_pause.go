@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ pause flags
+var mqPauseReason string
+
+var mqPauseCmd = &cobra.Command{
+	Use:         "pause [rig]",
+	Annotations: auditAnnotations(""),
+	Short:       "Pause merge processing for a rig",
+	Long: `Pause merge processing for a rig, e.g. during a release freeze.
+
+The refinery finishes the MR it is working on and holds the rest: while the
+queue is paused 'gt refinery ready' lists nothing and 'gt refinery claim'
+refuses new claims. MRs can still be submitted and wait in the queue.
+
+The pause is stored in the town's wisp layer, so it survives refinery
+restarts. It is shown by 'gt rig status' until 'gt mq resume'.
+
+The rig is inferred from the current directory if not given.
+
+Examples:
+  gt queue pause gastown --reason "v1.4 release freeze"
+  gt queue resume gastown`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQPause,
+}
+
+var mqResumeCmd = &cobra.Command{
+	Use:         "resume [rig]",
+	Annotations: auditAnnotations(""),
+	Short:       "Resume merge processing for a paused rig",
+	Long: `Resume merge processing for a rig paused with 'gt mq pause'.
+
+Held MRs become ready again on the refinery's next patrol.
+
+Examples:
+  gt queue resume gastown`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMQResume,
+}
+
+func init() {
+	mqPauseCmd.Flags().StringVarP(&mqPauseReason, "reason", "r", "", "Why the queue is paused (shown in rig status)")
+
+	mqCmd.AddCommand(mqPauseCmd)
+	mqCmd.AddCommand(mqResumeCmd)
+}
+
+func runMQPause(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	pause, err := refinery.PauseQueue(filepath.Dir(r.Path), rigName, detectSender(), mqPauseReason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Merge queue paused for %s\n", style.Warning.Render("⏸"), rigName)
+	if pause.Reason != "" {
+		fmt.Printf("  Reason: %s\n", pause.Reason)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("The MR in flight finishes; the rest wait for 'gt mq resume "+rigName+"'"))
+	return nil
+}
+
+func runMQResume(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	wasPaused, err := refinery.ResumeQueue(filepath.Dir(r.Path), rigName)
+	if err != nil {
+		return err
+	}
+	if !wasPaused {
+		fmt.Printf("%s Merge queue for %s is not paused\n", style.Dim.Render("ℹ"), rigName)
+		return nil
+	}
+
+	fmt.Printf("%s Merge queue resumed for %s\n", style.Success.Render("▶"), rigName)
+	return nil
+}
+
+// formatQueuePause describes a merge queue pause for status output.
+func formatQueuePause(p *refinery.QueuePause) string {
+	s := "paused " + formatRelativeTime(p.Since.Format(time.RFC3339))
+	if p.By != "" {
+		s += " by " + p.By
+	}
+	if p.Reason != "" {
+		s += ": " + p.Reason
+	}
+	return s
+}
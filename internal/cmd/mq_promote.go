@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ promote/demote flags
+var mqPromoteRig string
+
+var mqPromoteCmd = &cobra.Command{
+	Use:   "promote <branch-or-mr-id>",
+	Short: "Move a merge request into the expedite lane",
+	Long: `Move a merge request into the expedite lane so it is merged ahead of
+the normal queue, e.g. for a hotfix.
+
+This adds the rig's expedite label (merge_queue.expedite_label, default
+gt:expedite) to the MR bead. Labeling the bead directly has the same effect.
+
+The expedite lane has its own concurrency limit
+(merge_queue.expedite_max_concurrent); the normal lane uses max_concurrent.
+A normal MR that has waited longer than merge_queue.normal_lane_max_wait
+(default 2h) goes ahead of expedited MRs, so hotfixes can't starve it.
+
+The rig is inferred from the current directory unless --rig is given.
+
+Examples:
+  gt queue promote polecat/Nux/gt-abc
+  gt mq promote gt-mr-xyz --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMQSetLane(args[0], true)
+	},
+}
+
+var mqDemoteCmd = &cobra.Command{
+	Use:   "demote <branch-or-mr-id>",
+	Short: "Move a merge request back to the normal lane",
+	Long: `Move an expedited merge request back to the normal lane by removing
+the rig's expedite label from the MR bead.
+
+Examples:
+  gt queue demote polecat/Nux/gt-abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMQSetLane(args[0], false)
+	},
+}
+
+func init() {
+	mqPromoteCmd.Flags().StringVar(&mqPromoteRig, "rig", "", "Rig whose queue holds the MR (default: infer from cwd)")
+	mqDemoteCmd.Flags().StringVar(&mqPromoteRig, "rig", "", "Rig whose queue holds the MR (default: infer from cwd)")
+
+	mqCmd.AddCommand(mqPromoteCmd)
+	mqCmd.AddCommand(mqDemoteCmd)
+}
+
+func runMQSetLane(idOrBranch string, expedite bool) error {
+	mgr, r, rigName, err := getRefineryManager(mqPromoteRig)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config for %s: %w", rigName, err)
+	}
+
+	mr, err := mgr.SetExpedite(idOrBranch, eng.Config().ExpediteLabel, expedite)
+	if err != nil {
+		return fmt.Errorf("updating MR lane: %w", err)
+	}
+
+	if expedite {
+		fmt.Printf("%s Expedited: %s (%s)\n", style.Bold.Render("⚡"), mr.Branch, mr.ID)
+		fmt.Printf("  %s\n", style.Dim.Render("Merged ahead of the normal lane once it is ready"))
+	} else {
+		fmt.Printf("%s Moved to normal lane: %s (%s)\n", style.Success.Render("✓"), mr.Branch, mr.ID)
+	}
+	return nil
+}
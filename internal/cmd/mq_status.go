@@ -33,6 +33,8 @@ type MRStatusOutput struct {
 	Rig         string `json:"rig,omitempty"`
 	MergeCommit string `json:"merge_commit,omitempty"`
 	CloseReason string `json:"close_reason,omitempty"`
+	HookResults string `json:"hook_results,omitempty"`
+	TestRuns    string `json:"test_runs,omitempty"`
 
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
@@ -95,6 +97,8 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.HookResults = mrFields.HookResults
+		output.TestRuns = mrFields.TestRuns
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -184,6 +188,12 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		if mrFields.CloseReason != "" {
 			fmt.Printf("   Close Reason: %s\n", mrFields.CloseReason)
 		}
+		if mrFields.HookResults != "" {
+			fmt.Printf("   Hooks:        %s\n", mrFields.HookResults)
+		}
+		if mrFields.TestRuns != "" {
+			fmt.Printf("   Tests:        %s\n", mrFields.TestRuns)
+		}
 	}
 
 	// Dependencies (what this MR is waiting on)
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -285,6 +286,7 @@ Please verify state and execute lifecycle action.
 		"-m", body,
 	)
 	cmd.Dir = townRoot
+	cmd.Env = telemetry.WithTraceparent(nil)
 
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("sending lifecycle request: %w: %s", err, string(out))
@@ -15,6 +15,10 @@ import (
 var (
 	namepoolListFlag  bool
 	namepoolThemeFlag string
+
+	namepoolPrefixFlag   string
+	namepoolSuffixFlag   string
+	namepoolCooldownFlag int
 )
 
 var namepoolCmd = &cobra.Command{
@@ -32,6 +36,7 @@ Examples:
   gt namepool themes       # Show theme names
   gt namepool set minerals # Set theme to 'minerals'
   gt namepool add ember    # Add custom name to pool
+  gt namepool pattern --prefix fe-   # Name polecats fe-<name>
   gt namepool reset        # Reset pool state`,
 	RunE: runNamepool,
 }
@@ -68,6 +73,30 @@ Duplicate names are silently ignored.`,
 	RunE: runNamepoolAdd,
 }
 
+var namepoolPatternCmd = &cobra.Command{
+	Use:   "pattern",
+	Short: "Set the name prefix/suffix and reuse cooldown for this rig",
+	Long: `Set how new polecat names are formed in this rig.
+
+--prefix and --suffix wrap every allocated name, so with --prefix fe- the
+pool hands out fe-furiosa, fe-nux, ... Existing polecats keep their names.
+
+--cooldown holds the N most recently released names back from allocation
+while other names are free, so a name is not reused while logs still refer
+to the previous polecat. The list survives restarts.
+
+Settings are saved to the rig's settings/config.json. Without flags, shows
+the current pattern.
+
+Examples:
+  gt namepool pattern
+  gt namepool pattern --prefix fe-
+  gt namepool pattern --suffix -be --cooldown 10
+  gt namepool pattern --prefix "" --suffix ""   # Clear the pattern`,
+	Args: cobra.NoArgs,
+	RunE: runNamepoolPattern,
+}
+
 var namepoolResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset the pool state (release all names)",
@@ -83,7 +112,11 @@ func init() {
 	namepoolCmd.AddCommand(namepoolThemesCmd)
 	namepoolCmd.AddCommand(namepoolSetCmd)
 	namepoolCmd.AddCommand(namepoolAddCmd)
+	namepoolCmd.AddCommand(namepoolPatternCmd)
 	namepoolCmd.AddCommand(namepoolResetCmd)
+	namepoolPatternCmd.Flags().StringVar(&namepoolPrefixFlag, "prefix", "", "Prefix for new polecat names")
+	namepoolPatternCmd.Flags().StringVar(&namepoolSuffixFlag, "suffix", "", "Suffix for new polecat names")
+	namepoolPatternCmd.Flags().IntVar(&namepoolCooldownFlag, "cooldown", 0, "Number of recently released names to hold back")
 	namepoolCmd.Flags().BoolVarP(&namepoolListFlag, "list", "l", false, "List available themes")
 }
 
@@ -104,15 +137,8 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 	var pool *polecat.NamePool
 
 	settings, err := config.LoadRigSettings(settingsPath)
-	if err == nil && settings.Namepool != nil {
-		// Use configured namepool settings
-		pool = polecat.NewNamePoolWithConfig(
-			rigPath,
-			rigName,
-			settings.Namepool.Style,
-			settings.Namepool.Names,
-			settings.Namepool.MaxBeforeNumbering,
-		)
+	if err == nil {
+		pool = polecat.NewNamePoolFromSettings(rigPath, rigName, settings.Namepool)
 	} else {
 		// Use defaults
 		pool = polecat.NewNamePool(rigPath, rigName)
@@ -137,8 +163,15 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 		fmt.Printf("In use: %s\n", strings.Join(activeNames, ", "))
 	}
 
+	if pool.Prefix != "" || pool.Suffix != "" {
+		fmt.Printf("Pattern: %s<name>%s\n", pool.Prefix, pool.Suffix)
+	}
+	if pool.ReuseCooldown > 0 {
+		fmt.Printf("Reuse cooldown: %d names (%d cooling)\n", pool.ReuseCooldown, len(pool.Recent))
+	}
+
 	// Check if configured (already loaded above)
-	if settings.Namepool != nil {
+	if settings != nil && settings.Namepool != nil {
 		fmt.Printf("(configured in settings/config.json)\n")
 	}
 
@@ -287,6 +320,64 @@ func runNamepoolAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runNamepoolPattern(cmd *cobra.Command, args []string) error {
+	rigName, rigPath := detectCurrentRigWithPath()
+	if rigName == "" {
+		return fmt.Errorf("not in a rig directory")
+	}
+
+	settingsPath := filepath.Join(rigPath, "settings", "config.json")
+	settings, err := config.LoadRigSettings(settingsPath)
+	if err != nil {
+		if os.IsNotExist(err) || strings.Contains(err.Error(), "not found") {
+			settings = config.NewRigSettings()
+		} else {
+			return fmt.Errorf("loading settings: %w", err)
+		}
+	}
+	if settings.Namepool == nil {
+		settings.Namepool = config.DefaultNamepoolConfig()
+	}
+	np := settings.Namepool
+
+	flags := cmd.Flags()
+	if !flags.Changed("prefix") && !flags.Changed("suffix") && !flags.Changed("cooldown") {
+		fmt.Printf("Rig: %s\n", rigName)
+		fmt.Printf("Pattern: %s<name>%s\n", np.Prefix, np.Suffix)
+		fmt.Printf("Reuse cooldown: %d\n", np.ReuseCooldown)
+		return nil
+	}
+
+	prefix, suffix := np.Prefix, np.Suffix
+	if flags.Changed("prefix") {
+		prefix = namepoolPrefixFlag
+	}
+	if flags.Changed("suffix") {
+		suffix = namepoolSuffixFlag
+	}
+	if err := polecat.ValidateNamePattern(prefix, suffix); err != nil {
+		return err
+	}
+	if namepoolCooldownFlag < 0 {
+		return fmt.Errorf("--cooldown must not be negative")
+	}
+	np.Prefix, np.Suffix = prefix, suffix
+	if flags.Changed("cooldown") {
+		np.ReuseCooldown = namepoolCooldownFlag
+	}
+
+	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+
+	fmt.Printf("New polecats in '%s' will be named %s<name>%s", rigName, np.Prefix, np.Suffix)
+	if np.ReuseCooldown > 0 {
+		fmt.Printf(", holding back the last %d released names", np.ReuseCooldown)
+	}
+	fmt.Println()
+	return nil
+}
+
 func runNamepoolReset(cmd *cobra.Command, args []string) error {
 	rigName, rigPath := detectCurrentRigWithPath()
 	if rigName == "" {
@@ -353,11 +444,12 @@ func saveRigNamepoolConfig(rigPath, theme string, customNames []string) error {
 		}
 	}
 
-	// Set namepool
-	settings.Namepool = &config.NamepoolConfig{
-		Style: theme,
-		Names: customNames,
+	// Set namepool, keeping the rest of any existing namepool config
+	if settings.Namepool == nil {
+		settings.Namepool = &config.NamepoolConfig{}
 	}
+	settings.Namepool.Style = theme
+	settings.Namepool.Names = customNames
 
 	// Save (creates directory if needed)
 	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
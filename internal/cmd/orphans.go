@@ -634,10 +634,15 @@ func runOrphansKill(cmd *cobra.Command, args []string) error {
 	if !orphansKillForce {
 		fmt.Printf("%s\n", style.Warning.Render("WARNING: This operation is irreversible!"))
 		total := len(filteredCommits) + len(procOrphans)
-		fmt.Printf("Remove %d orphan(s)? [y/N] ", total)
-		var response string
-		_, _ = fmt.Scanln(&response)
-		if strings.ToLower(strings.TrimSpace(response)) != "y" {
+		ok, err := confirm(confirmPrompt{
+			ID:       PromptOrphansRemove,
+			Question: fmt.Sprintf("Remove %d orphan(s)?", total),
+			Hint:     "irreversible, so --yes does not apply; pass --force to remove without confirmation",
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Printf("%s Canceled\n", style.Dim.Render("ℹ"))
 			return nil
 		}
@@ -899,11 +904,16 @@ func runOrphansKillProcesses(cmd *cobra.Command, args []string) error {
 
 	// Confirm unless --force
 	if !orphansProcsForce {
-		fmt.Printf("Kill these %d process(es)? [y/N] ", len(orphans))
-		var response string
-		_, _ = fmt.Scanln(&response)
-		response = strings.ToLower(strings.TrimSpace(response))
-		if response != "y" && response != "yes" {
+		ok, err := confirm(confirmPrompt{
+			ID:       PromptOrphansKillProcs,
+			Question: fmt.Sprintf("Kill these %d process(es)?", len(orphans)),
+			Safe:     true,
+			Hint:     "pass --force to kill without confirmation",
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println("Aborted")
 			return nil
 		}
@@ -976,11 +986,16 @@ func runOrphansKillProcessesAggressive() error {
 
 	// Confirm unless --force
 	if !orphansProcsForce {
-		fmt.Printf("Kill these %d process(es)? [y/N] ", len(zombies))
-		var response string
-		_, _ = fmt.Scanln(&response)
-		response = strings.ToLower(strings.TrimSpace(response))
-		if response != "y" && response != "yes" {
+		ok, err := confirm(confirmPrompt{
+			ID:       PromptOrphansKillZombie,
+			Question: fmt.Sprintf("Kill these %d process(es)?", len(zombies)),
+			Safe:     true,
+			Hint:     "pass --force to kill without confirmation",
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println("Aborted")
 			return nil
 		}
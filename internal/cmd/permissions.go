@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// commandTownRoot returns the town a command acts on: the town containing
+// the working directory or, outside a town, the GT_TOWN_ROOT or GT_ROOT
+// town that commands fall back to. Returns "" if there is none.
+func commandTownRoot() string {
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		return townRoot
+	}
+	for _, env := range []string{"GT_TOWN_ROOT", "GT_ROOT"} {
+		if dir := os.Getenv(env); dir != "" {
+			if ok, _ := workspace.IsWorkspace(dir); ok {
+				return dir
+			}
+		}
+	}
+	return ""
+}
+
+// Commands that are always allowed regardless of mayor/permissions.json.
+// These are informational and needed to discover what you're allowed to do.
+var permissionExemptCommands = map[string]bool{
+	"help":       true,
+	"version":    true,
+	"completion": true,
+	"whoami":     true,
+}
+
+// checkCommandPermission enforces mayor/permissions.json for cmd.
+// Towns without a permissions file are unrestricted (single-user default).
+func checkCommandPermission(townRoot string, cmd *cobra.Command) error {
+	permCfg, err := config.LoadPermissionsConfig(constants.MayorPermissionsPath(townRoot))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil
+		}
+		// Fail closed: a broken permissions file must not grant everything.
+		return fmt.Errorf("loading %s: %w", constants.MayorPermissionsPath(townRoot), err)
+	}
+	if !permCfg.Enforced() {
+		return nil
+	}
+
+	top := cmd
+	for top.HasParent() && top.Parent() != cmd.Root() {
+		top = top.Parent()
+	}
+	if top == cmd.Root() || permissionExemptCommands[top.Name()] {
+		return nil
+	}
+
+	actor, osUser := permissionIdentities(townRoot)
+	commandPath := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	grants, matched := permCfg.GrantsFor(actor, osUser)
+	if config.Allows(grants, commandPath, top.GroupID) {
+		return nil
+	}
+
+	who := osUser
+	if actor != "" {
+		who = actor
+	}
+	if matched == "" {
+		matched = "default"
+	}
+	return fmt.Errorf("permission denied: %s may not run '%s' (grants from %q in %s)",
+		who, cmd.CommandPath(), matched, constants.MayorPermissionsPath(townRoot))
+}
+
+// permissionIdentities returns the agent identity (empty for humans and
+// unverified sessions) and the OS username, in lookup order.
+//
+// BD_ACTOR, GT_ROLE and USER are not trusted: anyone can set them. The
+// agent identity comes from the name of the tmux session the process runs
+// in, as tmux reports it, and counts only for the OS user owning the town.
+// Agents run as that user, and another user's tmux server can't host them.
+func permissionIdentities(townRoot string) (actor, osUser string) {
+	if u, err := user.Current(); err == nil {
+		osUser = u.Username
+	} else {
+		osUser = "uid:" + strconv.Itoa(os.Getuid())
+	}
+
+	pane := os.Getenv("TMUX_PANE")
+	if pane == "" || !ownedByCurrentUser(townRoot) {
+		return "", osUser
+	}
+	sessionName, err := paneSessionName(pane)
+	if err != nil {
+		return "", osUser
+	}
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return "", osUser
+	}
+	return identity.Address(), osUser
+}
+
+// paneSessionName asks tmux which session pane belongs to. A variable so
+// tests can stand in for tmux.
+var paneSessionName = func(pane string) (string, error) {
+	return tmux.NewTmux().GetPaneSessionName(pane)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePermissions writes mayor/permissions.json into townRoot.
+func writePermissions(t *testing.T, townRoot, perms string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "permissions.json"), []byte(perms), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stubPaneSession makes the process look like it runs in tmux session name.
+func stubPaneSession(t *testing.T, name string) {
+	t.Helper()
+	t.Setenv("TMUX_PANE", "%1")
+	orig := paneSessionName
+	paneSessionName = func(string) (string, error) { return name, nil }
+	t.Cleanup(func() { paneSessionName = orig })
+}
+
+func TestCheckCommandPermission(t *testing.T) {
+	townRoot := t.TempDir()
+
+	// No permissions file: single-user town, everything allowed.
+	setupCostsTestRegistry(t)
+	stubPaneSession(t, "gt-crew-joe")
+	if err := checkCommandPermission(townRoot, rigRemoveCmd); err != nil {
+		t.Fatalf("unrestricted town denied command: %v", err)
+	}
+
+	writePermissions(t, townRoot, `{
+  "version": 1,
+  "identities": {
+    "*/crew/*": ["group:work", "rig status"]
+  }
+}`)
+
+	err := checkCommandPermission(townRoot, rigRemoveCmd)
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("crew running rig remove: err = %v, want permission denied", err)
+	}
+	if err := checkCommandPermission(townRoot, rigStatusCmd); err != nil {
+		t.Errorf("crew running rig status: %v", err)
+	}
+	if err := checkCommandPermission(townRoot, versionCmd); err != nil {
+		t.Errorf("version should always be allowed: %v", err)
+	}
+}
+
+func TestCheckCommandPermission_IgnoresBDActor(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("TMUX_PANE", "")
+	t.Setenv("BD_ACTOR", "mayor")
+	t.Setenv("USER", "mayor")
+	writePermissions(t, townRoot, `{
+  "version": 1,
+  "identities": {"mayor": ["*"]},
+  "default": ["group:diag"]
+}`)
+
+	if u, err := user.Current(); err == nil && u.Username == "mayor" {
+		t.Skip("test user is named mayor")
+	}
+	if err := checkCommandPermission(townRoot, rigRemoveCmd); err == nil {
+		t.Error("BD_ACTOR=mayor was granted mayor's permissions")
+	}
+}
+
+func TestCommandTownRoot_EnvFallback(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GT_ROOT", "")
+	t.Setenv("GT_TOWN_ROOT", townRoot)
+
+	// Outside a town, commands fall back to GT_TOWN_ROOT, so the permission
+	// check must too.
+	if got := commandTownRoot(); got != townRoot {
+		t.Errorf("commandTownRoot() = %q, want %q", got, townRoot)
+	}
+}
@@ -44,10 +44,16 @@ GATE TYPES:
   event       Run on events (e.g., startup)
   manual      Never auto-run, trigger explicitly
 
+Besides a patrol gate and instructions, a plugin.md can contribute commands
+([[commands]], run with gt plugin exec) and hooks ([[hooks]]) that run at
+rig-add, polecat-spawn, polecat-done, pre-merge, and post-merge.
+
 Examples:
   gt plugin list                    # List all discovered plugins
   gt plugin show <name>             # Show plugin details
-  gt plugin list --json             # JSON output`,
+  gt plugin list --json             # JSON output
+  gt plugin disable <name>          # Stop a plugin without removing it
+  gt plugin exec <name> <command>   # Run a contributed command`,
 	RunE: requireSubcommand,
 }
 
@@ -257,10 +263,24 @@ func printPluginSummary(p *plugin.Plugin) {
 		desc = desc[:47] + "..."
 	}
 
-	fmt.Printf("    %s %s\n", style.Bold.Render(p.Name), style.Dim.Render(fmt.Sprintf("[%s]", gateType)))
+	status := ""
+	if !p.Enabled {
+		status = " " + style.Warning.Render("(disabled)")
+	}
+	fmt.Printf("    %s %s%s\n", style.Bold.Render(p.Name), style.Dim.Render(fmt.Sprintf("[%s]", gateType)), status)
 	if desc != "" {
 		fmt.Printf("      %s\n", style.Dim.Render(desc))
 	}
+	if s := p.Summary(); len(s.HookPoints) > 0 || len(s.Commands) > 0 {
+		var parts []string
+		for _, point := range s.HookPoints {
+			parts = append(parts, "hook:"+string(point))
+		}
+		for _, name := range s.Commands {
+			parts = append(parts, "cmd:"+name)
+		}
+		fmt.Printf("      %s\n", style.Dim.Render(strings.Join(parts, " ")))
+	}
 }
 
 func runPluginShow(cmd *cobra.Command, args []string) error {
@@ -305,6 +325,31 @@ func outputPluginShowText(p *plugin.Plugin) error {
 	fmt.Printf("%s %s\n", style.Bold.Render("Location:"), locStr)
 
 	fmt.Printf("%s %d\n", style.Bold.Render("Version:"), p.Version)
+	fmt.Printf("%s %s\n", style.Bold.Render("Status:"), enabledWord(p.Enabled))
+
+	if len(p.Commands) > 0 {
+		fmt.Println()
+		fmt.Printf("%s\n", style.Bold.Render("Commands:"))
+		for _, c := range p.Commands {
+			fmt.Printf("  %s  %s\n", c.Name, style.Dim.Render(c.Description))
+		}
+	}
+	if len(p.Hooks) > 0 {
+		fmt.Println()
+		fmt.Printf("%s\n", style.Bold.Render("Hooks:"))
+		for _, h := range p.Hooks {
+			extra := ""
+			if h.Optional {
+				extra = " (optional)"
+			}
+			fmt.Printf("  %s: %s%s\n", h.On, h.Run, style.Dim.Render(extra))
+		}
+	}
+	if p.Patrol != nil {
+		fmt.Println()
+		fmt.Printf("%s\n", style.Bold.Render("Patrol:"))
+		fmt.Printf("  %s every %s: %s\n", p.PatrolName(), p.Patrol.Interval, p.Patrol.Exec)
+	}
 
 	// Gate
 	fmt.Println()
@@ -382,6 +427,10 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if !p.Enabled && !pluginRunForce {
+		fmt.Printf("%s Plugin %s is disabled (gt plugin enable %s, or --force)\n", style.Warning.Render("⚠"), p.Name, p.Name)
+		return nil
+	}
 
 	// Check gate status for cooldown gates
 	gateOpen := true
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/plugin"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var pluginEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable a disabled plugin",
+	Long: `Enable a plugin previously disabled with 'gt plugin disable'.
+
+Examples:
+  gt plugin enable rebuild-gt`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPluginEnabled(args[0], true)
+	},
+}
+
+var pluginDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Disable a plugin without removing it",
+	Long: `Disable a plugin. A disabled plugin is still listed, but its hooks and
+commands do not run and the Deacon skips it during patrol.
+
+The switch is a .disabled file in the plugin directory.
+
+Examples:
+  gt plugin disable rebuild-gt`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPluginEnabled(args[0], false)
+	},
+}
+
+var pluginExecCmd = &cobra.Command{
+	Use:   "exec <plugin> <command> [args...]",
+	Short: "Run a command contributed by a plugin",
+	Long: `Run a command declared in a plugin's [[commands]] section.
+
+Arguments after the command name are passed to it as "$@". The command
+runs in the current directory with GT_PLUGIN_DIR set to the plugin's
+directory.
+
+Examples:
+  gt plugin exec rebuild-gt status
+  gt plugin exec deploy-tools release --dry-run`,
+	Args:               cobra.MinimumNArgs(2),
+	DisableFlagParsing: true,
+	RunE:               runPluginExec,
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginEnableCmd)
+	pluginCmd.AddCommand(pluginDisableCmd)
+	pluginCmd.AddCommand(pluginExecCmd)
+}
+
+func setPluginEnabled(name string, enabled bool) error {
+	scanner, _, err := getPluginScanner()
+	if err != nil {
+		return err
+	}
+	p, err := scanner.GetPlugin(name)
+	if err != nil {
+		return err
+	}
+	if p.Enabled == enabled {
+		fmt.Printf("%s Plugin %s is already %s\n", style.Dim.Render("○"), name, enabledWord(enabled))
+		return nil
+	}
+	if err := plugin.SetEnabled(p, enabled); err != nil {
+		return err
+	}
+	fmt.Printf("%s Plugin %s %s\n", style.Success.Render("✓"), name, enabledWord(enabled))
+	return nil
+}
+
+func enabledWord(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func runPluginExec(cmd *cobra.Command, args []string) error {
+	scanner, _, err := getPluginScanner()
+	if err != nil {
+		return err
+	}
+	p, err := scanner.GetPlugin(args[0])
+	if err != nil {
+		return err
+	}
+	if !p.Enabled {
+		return fmt.Errorf("plugin %s is disabled (gt plugin enable %s)", p.Name, p.Name)
+	}
+	c, ok := p.FindCommand(args[1])
+	if !ok {
+		return fmt.Errorf("plugin %s has no command %q", p.Name, args[1])
+	}
+
+	shArgs := append([]string{"-c", c.Run, p.Name + ":" + c.Name}, args[2:]...)
+	run := exec.Command("sh", shArgs...) //nolint:gosec // G204: command comes from an operator-installed plugin
+	run.Env = append(os.Environ(), "GT_PLUGIN_DIR="+p.Path)
+	run.Stdin = os.Stdin
+	run.Stdout = os.Stdout
+	run.Stderr = os.Stderr
+	if err := run.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return NewSilentExit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("running %s %s: %w", p.Name, c.Name, err)
+	}
+	return nil
+}
+
+// runPluginHooks runs the enabled plugins' hooks at point for rigName in
+// dir. Hook failures are reported as warnings and never abort the caller.
+func runPluginHooks(townRoot, rigName string, point plugin.HookPoint, dir string, env ...string) {
+	if err := plugin.RunHooks(context.Background(), townRoot, rigName, point, dir, env, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "  %s Plugin hooks: %v\n", style.Warning.Render("!"), err)
+	}
+}
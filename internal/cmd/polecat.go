@@ -97,8 +97,9 @@ Example:
 }
 
 var polecatRemoveCmd = &cobra.Command{
-	Use:   "remove <rig>/<polecat>... | <rig> --all",
-	Short: "Remove polecats from a rig",
+	Use:         "remove <rig>/<polecat>... | <rig> --all",
+	Annotations: auditAnnotations(""),
+	Short:       "Remove polecats from a rig",
 	Long: `Remove one or more polecats from a rig.
 
 Fails if session is running (stop first).
@@ -164,8 +165,9 @@ Examples:
 }
 
 var polecatNukeCmd = &cobra.Command{
-	Use:   "nuke <rig>/<polecat>... | <rig> --all",
-	Short: "Completely destroy a polecat (session, worktree, branch, agent bead)",
+	Use:         "nuke <rig>/<polecat>... | <rig> --all",
+	Annotations: auditAnnotations(""),
+	Short:       "Completely destroy a polecat (session, worktree, branch, agent bead)",
 	Long: `Completely destroy a polecat and all its artifacts.
 
 This is the nuclear option for post-merge cleanup. It:
@@ -192,6 +194,22 @@ Examples:
 	RunE: runPolecatNuke,
 }
 
+var polecatRenameCmd = &cobra.Command{
+	Use:         "rename <rig>/<polecat> <new-name>",
+	Annotations: auditAnnotations(""),
+	Short:       "Rename an idle polecat",
+	Long: `Rename an idle polecat.
+
+Moves the polecat's directory and worktree to the new name, replaces its
+agent bead, and returns the old name to the rig's name pool. The polecat
+must have no running session and no assigned work.
+
+Examples:
+  gt polecat rename greenplace/Toast fe-toast`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPolecatRename,
+}
+
 var polecatGitStateCmd = &cobra.Command{
 	Use:   "git-state <rig>/<polecat>",
 	Short: "Show git state for pre-kill verification",
@@ -330,6 +348,7 @@ func init() {
 	polecatCmd.AddCommand(polecatAddCmd)
 	polecatCmd.AddCommand(polecatRemoveCmd)
 	polecatCmd.AddCommand(polecatStatusCmd)
+	polecatCmd.AddCommand(polecatRenameCmd)
 	polecatCmd.AddCommand(polecatGitStateCmd)
 	polecatCmd.AddCommand(polecatCheckRecoveryCmd)
 	polecatCmd.AddCommand(polecatGCCmd)
@@ -458,16 +477,7 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 			sessionStatus = style.Success.Render("●")
 		}
 
-		// Display actual state, reconciled with tmux session liveness.
-		// Per gt-zecmc design: tmux is ground truth for observable states.
-		// If session is running but beads says done, the polecat is still alive.
-		// If session is dead but beads says working, the polecat is actually done.
-		displayState := p.State
-		if p.SessionRunning && displayState == polecat.StateDone {
-			displayState = polecat.StateWorking
-		} else if !p.SessionRunning && !p.Zombie && displayState.IsActive() {
-			displayState = polecat.StateDone
-		}
+		displayState := reconcilePolecatState(p.State, p.SessionRunning, p.Zombie)
 
 		// State color
 		stateStr := string(displayState)
@@ -496,6 +506,21 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// reconcilePolecatState returns the polecat's actual state, reconciled with
+// tmux session liveness.
+// Per gt-zecmc design: tmux is ground truth for observable states.
+// If session is running but beads says done, the polecat is still alive.
+// If session is dead but beads says working, the polecat is actually done.
+func reconcilePolecatState(state polecat.State, sessionRunning, zombie bool) polecat.State {
+	if sessionRunning && state == polecat.StateDone {
+		return polecat.StateWorking
+	}
+	if !sessionRunning && !zombie && state.IsActive() {
+		return polecat.StateDone
+	}
+	return state
+}
+
 func runPolecatAdd(cmd *cobra.Command, args []string) error {
 	// Emit deprecation warning
 	fmt.Fprintf(os.Stderr, "%s 'gt polecat add' is deprecated. Use 'gt polecat identity add' instead.\n",
@@ -742,6 +767,28 @@ type GitState struct {
 	StashCount       int      `json:"stash_count"`
 }
 
+func runPolecatRename(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	newName := args[1]
+
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Rename(polecatName, newName); err != nil {
+		if errors.Is(err, polecat.ErrPolecatNotFound) {
+			return fmt.Errorf("polecat '%s' not found in rig '%s'", polecatName, rigName)
+		}
+		return fmt.Errorf("renaming %s/%s: %w", rigName, polecatName, err)
+	}
+
+	fmt.Printf("%s Renamed %s/%s to %s/%s\n", style.Success.Render("✓"), rigName, polecatName, rigName, newName)
+	return nil
+}
+
 func runPolecatGitState(cmd *cobra.Command, args []string) error {
 	rigName, polecatName, err := parseAddress(args[0])
 	if err != nil {
@@ -68,7 +68,7 @@ func findRigPolecatSessions(rigName string) ([]string, error) { //nolint:unparam
 		return nil, nil
 	}
 
-	prefix := session.PrefixFor(rigName) + "-"
+	prefix := session.RigSessionPrefix(session.PrefixFor(rigName))
 	var sessions []string
 
 	for _, s := range allSessions {
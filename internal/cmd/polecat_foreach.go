@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Foreach actions
+const (
+	foreachNudge     = "nudge"
+	foreachKill      = "kill"
+	foreachReset     = "reset"
+	foreachGitStatus = "git-status"
+)
+
+var foreachActions = []string{foreachNudge, foreachKill, foreachReset, foreachGitStatus}
+
+var (
+	polecatForeachStates   []string
+	polecatForeachLabels   []string
+	polecatForeachParallel int
+	polecatForeachDryRun   bool
+	polecatForeachJSON     bool
+)
+
+var polecatForeachCmd = &cobra.Command{
+	Use:   "foreach <rig> <action> [message]",
+	Short: "Run an operation across many polecats",
+	Long: `Run an operation on every polecat in a rig that matches the filters.
+
+Actions:
+  nudge <message>  Send a message to each running polecat session
+  kill             Stop each running polecat session (worktree is kept)
+  reset            Release each polecat's issue back to open, unassigned
+  git-status       Collect worktree state (uncommitted, unpushed, stashes)
+
+Filters (combine with AND; repeat or comma-separate values):
+  --state   Match polecat state: working, done, stuck, zombie
+  --label   Match polecats whose issue has ALL of these labels
+
+Polecats are processed in parallel (--parallel, default 4) and the
+results are summarized in a table. Use --dry-run to see which polecats
+match without doing anything.
+
+Examples:
+  gt polecat foreach greenplace git-status
+  gt polecat foreach greenplace nudge "Rebase on main before gt done" --state working
+  gt polecat foreach greenplace kill --state stuck --dry-run
+  gt polecat foreach greenplace reset --label flaky --parallel 8`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runPolecatForeach,
+}
+
+func init() {
+	polecatForeachCmd.Flags().StringSliceVar(&polecatForeachStates, "state", nil, "Only polecats in these states")
+	polecatForeachCmd.Flags().StringSliceVar(&polecatForeachLabels, "label", nil, "Only polecats whose issue has these labels")
+	polecatForeachCmd.Flags().IntVarP(&polecatForeachParallel, "parallel", "p", 4, "Number of polecats to process at once")
+	polecatForeachCmd.Flags().BoolVar(&polecatForeachDryRun, "dry-run", false, "List matching polecats without running the action")
+	polecatForeachCmd.Flags().BoolVar(&polecatForeachJSON, "json", false, "Output results as JSON")
+
+	polecatCmd.AddCommand(polecatForeachCmd)
+}
+
+// foreachPolecat is a polecat considered by gt polecat foreach.
+type foreachPolecat struct {
+	Name           string
+	State          polecat.State
+	Issue          string
+	Labels         []string
+	ClonePath      string
+	SessionRunning bool
+}
+
+// ForeachResult is the outcome of a foreach action on one polecat.
+type ForeachResult struct {
+	Polecat string        `json:"polecat"`
+	State   polecat.State `json:"state"`
+	Issue   string        `json:"issue,omitempty"`
+	Status  string        `json:"status"` // ok, skipped, failed, matched (dry run)
+	Detail  string        `json:"detail,omitempty"`
+	Git     *GitState     `json:"git,omitempty"`
+}
+
+// errForeachSkipped marks a polecat the action doesn't apply to.
+type errForeachSkipped struct{ reason string }
+
+func (e errForeachSkipped) Error() string { return e.reason }
+
+func runPolecatForeach(cmd *cobra.Command, args []string) error {
+	rigName, action := args[0], args[1]
+	if !slices.Contains(foreachActions, action) {
+		return fmt.Errorf("unknown action %q (valid: %s)", action, strings.Join(foreachActions, ", "))
+	}
+	var message string
+	if action == foreachNudge {
+		if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+			return fmt.Errorf("nudge requires a message")
+		}
+		message = args[2]
+	} else if len(args) > 2 {
+		return fmt.Errorf("%s takes no message argument", action)
+	}
+	for _, s := range polecatForeachStates {
+		switch polecat.State(s) {
+		case polecat.StateWorking, polecat.StateDone, polecat.StateStuck, polecat.StateZombie:
+		default:
+			return fmt.Errorf("unknown state %q (valid: working, done, stuck, zombie)", s)
+		}
+	}
+	if polecatForeachParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	t := tmux.NewTmux()
+	sessMgr := polecat.NewSessionManager(t, r)
+	mux := multiplexer.ForTownWith(filepath.Dir(r.Path), t)
+	if r.IsRemote() {
+		mux = r.Tmux()
+	}
+	bd := beads.New(r.Path)
+
+	polecats, err := mgr.List()
+	if err != nil {
+		return fmt.Errorf("listing polecats: %w", err)
+	}
+
+	var targets []foreachPolecat
+	for _, p := range polecats {
+		running, _ := sessMgr.IsRunning(p.Name)
+		fp := foreachPolecat{
+			Name:           p.Name,
+			State:          reconcilePolecatState(p.State, running, false),
+			Issue:          p.Issue,
+			ClonePath:      p.ClonePath,
+			SessionRunning: running,
+		}
+		if len(polecatForeachLabels) > 0 && fp.Issue != "" {
+			if issue, err := bd.Show(fp.Issue); err == nil {
+				fp.Labels = issue.Labels
+			}
+		}
+		if matchesForeachFilter(fp, polecatForeachStates, polecatForeachLabels) {
+			targets = append(targets, fp)
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("No polecats in %s match the filters.\n", rigName)
+		return nil
+	}
+
+	var fn func(foreachPolecat) (string, *GitState, error)
+	switch action {
+	case foreachNudge:
+		sender := nudgeSender()
+		fn = func(p foreachPolecat) (string, *GitState, error) {
+			if !p.SessionRunning {
+				return "", nil, errForeachSkipped{"session not running"}
+			}
+			if err := multiplexer.Nudge(mux, sessMgr.SessionName(p.Name), fmt.Sprintf("[from %s] %s", sender, message)); err != nil {
+				return "", nil, err
+			}
+			_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload(rigName, rigName+"/"+p.Name, message))
+			return "nudged", nil, nil
+		}
+	case foreachKill:
+		fn = func(p foreachPolecat) (string, *GitState, error) {
+			if err := sessMgr.Stop(p.Name, false); err != nil {
+				if errors.Is(err, polecat.ErrSessionNotFound) {
+					return "", nil, errForeachSkipped{"session not running"}
+				}
+				return "", nil, err
+			}
+			return "session stopped", nil, nil
+		}
+	case foreachReset:
+		fn = func(p foreachPolecat) (string, *GitState, error) {
+			if p.Issue == "" {
+				return "", nil, errForeachSkipped{"no issue"}
+			}
+			if err := bd.ReleaseWithReason(p.Issue, "reset by gt polecat foreach"); err != nil {
+				return "", nil, err
+			}
+			return p.Issue + " → open", nil, nil
+		}
+	case foreachGitStatus:
+		fn = func(p foreachPolecat) (string, *GitState, error) {
+			state, err := getGitState(p.ClonePath)
+			if err != nil {
+				return "", nil, err
+			}
+			return formatGitStateSummary(state), state, nil
+		}
+	}
+	if polecatForeachDryRun {
+		fn = func(p foreachPolecat) (string, *GitState, error) {
+			return "would " + action, nil, nil
+		}
+	}
+
+	results := runForeach(targets, polecatForeachParallel, fn)
+	for i := range results {
+		results[i].Polecat = rigName + "/" + results[i].Polecat
+		if polecatForeachDryRun {
+			results[i].Status = "matched"
+		}
+	}
+
+	if polecatForeachJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else if err := printForeachResults(results); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, res := range results {
+		if res.Status == "failed" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s failed for %d of %d polecat(s)", action, failed, len(results))
+	}
+	return nil
+}
+
+// matchesForeachFilter reports whether p is in one of states (if any) and
+// its issue carries every label in labels.
+func matchesForeachFilter(p foreachPolecat, states, labels []string) bool {
+	if len(states) > 0 && !slices.Contains(states, string(p.State)) {
+		return false
+	}
+	for _, l := range labels {
+		if !slices.Contains(p.Labels, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// runForeach applies fn to every target, at most parallel at a time, and
+// returns the results in target order.
+func runForeach(targets []foreachPolecat, parallel int, fn func(foreachPolecat) (string, *GitState, error)) []ForeachResult {
+	results := make([]ForeachResult, len(targets))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i, p := range targets {
+		wg.Add(1)
+		go func(i int, p foreachPolecat) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := ForeachResult{Polecat: p.Name, State: p.State, Issue: p.Issue, Status: "ok"}
+			detail, git, err := fn(p)
+			var skipped errForeachSkipped
+			switch {
+			case errors.As(err, &skipped):
+				res.Status, detail = "skipped", skipped.reason
+			case err != nil:
+				res.Status, detail = "failed", err.Error()
+			}
+			res.Detail, res.Git = detail, git
+			results[i] = res
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// formatGitStateSummary renders a one-line summary of a worktree's state.
+func formatGitStateSummary(s *GitState) string {
+	if s.Clean {
+		return "clean"
+	}
+	var parts []string
+	if n := len(s.UncommittedFiles); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d uncommitted", n))
+	}
+	if s.UnpushedCommits > 0 {
+		parts = append(parts, fmt.Sprintf("%d unpushed", s.UnpushedCommits))
+	}
+	if s.StashCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d stashed", s.StashCount))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printForeachResults(results []ForeachResult) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POLECAT\tSTATE\tISSUE\tRESULT\tDETAIL")
+	counts := make(map[string]int)
+	for _, res := range results {
+		issue := res.Issue
+		if issue == "" {
+			issue = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", res.Polecat, res.State, issue, res.Status, res.Detail)
+		counts[res.Status]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if n := counts["matched"]; n > 0 {
+		fmt.Printf("%s %d polecat(s) matched (dry run)\n", style.Dim.Render("○"), n)
+		return nil
+	}
+	fmt.Printf("%s %d ok, %d skipped, %d failed\n",
+		style.Bold.Render("Summary:"), counts["ok"], counts["skipped"], counts["failed"])
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+func TestMatchesForeachFilter(t *testing.T) {
+	p := foreachPolecat{Name: "nux", State: polecat.StateWorking, Issue: "gt-1", Labels: []string{"flaky", "backend"}}
+
+	tests := []struct {
+		name   string
+		states []string
+		labels []string
+		want   bool
+	}{
+		{"no filters", nil, nil, true},
+		{"state match", []string{"stuck", "working"}, nil, true},
+		{"state mismatch", []string{"done"}, nil, false},
+		{"all labels present", nil, []string{"flaky", "backend"}, true},
+		{"label missing", nil, []string{"flaky", "frontend"}, false},
+		{"state and label", []string{"working"}, []string{"backend"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesForeachFilter(p, tt.states, tt.labels); got != tt.want {
+				t.Errorf("matchesForeachFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunForeach_OrderAndParallelism(t *testing.T) {
+	targets := []foreachPolecat{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+
+	var inFlight, peak int32
+	results := runForeach(targets, 2, func(p foreachPolecat) (string, *GitState, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+
+		switch p.Name {
+		case "b":
+			return "", nil, errForeachSkipped{"session not running"}
+		case "d":
+			return "", nil, errors.New("boom")
+		}
+		return "done " + p.Name, nil, nil
+	})
+
+	if peak > 2 {
+		t.Errorf("peak parallelism = %d, want <= 2", peak)
+	}
+	want := []struct{ name, status, detail string }{
+		{"a", "ok", "done a"},
+		{"b", "skipped", "session not running"},
+		{"c", "ok", "done c"},
+		{"d", "failed", "boom"},
+		{"e", "ok", "done e"},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Polecat != w.name || r.Status != w.status || r.Detail != w.detail {
+			t.Errorf("results[%d] = %+v, want %s %s %q", i, r, w.name, w.status, w.detail)
+		}
+	}
+}
+
+func TestFormatGitStateSummary(t *testing.T) {
+	if got := formatGitStateSummary(&GitState{Clean: true}); got != "clean" {
+		t.Errorf("clean = %q", got)
+	}
+	got := formatGitStateSummary(&GitState{UncommittedFiles: []string{"a", "b"}, UnpushedCommits: 1, StashCount: 3})
+	if got != "2 uncommitted, 1 unpushed, 3 stashed" {
+		t.Errorf("dirty = %q", got)
+	}
+}
@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,11 +15,15 @@ import (
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/plugin"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // SpawnedPolecatInfo contains info about a spawned polecat session.
@@ -59,7 +64,10 @@ type SlingSpawnOptions struct {
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
 // This is used by gt sling when the target is a rig name.
 // The caller (sling) handles hook attachment and nudging.
-func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolecatInfo, error) {
+func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (_ *SpawnedPolecatInfo, err error) {
+	_, span := telemetry.Start(context.Background(), "polecat.spawn", attribute.String("polecat.rig", rigName))
+	defer func() { telemetry.End(span, err) }()
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -191,6 +199,10 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			polecatName, err, rigName, polecatName)
 	}
 
+	// Run plugin polecat-spawn hooks in the new worktree
+	runPluginHooks(townRoot, rigName, plugin.HookPolecatSpawn, polecatObj.ClonePath,
+		"GT_RIG="+rigName, "GT_POLECAT="+polecatName)
+
 	// Branch-per-polecat: generate name but DEFER creation to after sling writes.
 	// DOLT_BRANCH forks from HEAD, but BD_DOLT_AUTO_COMMIT=off means writes
 	// stay in working set. Caller must call CreateDoltBranch() after all writes
@@ -253,9 +265,20 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		return "", fmt.Errorf("rig '%s' not found", s.RigName)
 	}
 
-	// Resolve account
+	// Resolve account. With no explicit --account or GT_ACCOUNT, the accounts
+	// policy (round-robin, dedicated rig account, failover) picks one.
 	accountsPath := constants.MayorAccountsPath(townRoot)
-	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, s.account)
+	account := s.account
+	if account == "" && os.Getenv("GT_ACCOUNT") == "" {
+		if acctCfg, loadErr := config.LoadAccountsConfig(accountsPath); loadErr == nil {
+			assigned, assignErr := quota.NewManager(townRoot).AssignAccount(acctCfg, s.RigName)
+			if assignErr != nil {
+				style.PrintWarning("account policy: %v (using default account)", assignErr)
+			}
+			account = assigned
+		}
+	}
+	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, account)
 	if err != nil {
 		return "", fmt.Errorf("resolving account: %w", err)
 	}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/preflight"
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townbackup"
+)
+
+// rigAddPreflight checks the tools and disk the selected stages of gt rig
+// add need. git-lfs is required with lfs on and checked with lfs auto.
+func rigAddPreflight(townRoot, gitURL, localRepo, lfs string, stages []string) *preflight.Report {
+	report := preflight.New("rig add")
+	if slices.Contains(stages, rig.StageClone) {
+		report.RequireTool(preflight.Git, false, "")
+		switch lfs {
+		case config.LFSOn:
+			report.RequireTool(preflight.GitLFS, false, "")
+		case config.LFSAuto:
+			report.RequireTool(preflight.GitLFS, true, "if the repo uses LFS its files will be pointer files")
+		}
+		report.RequireRemote(gitURL)
+		report.RequireSpace("disk", townRoot, preflight.CloneSize(gitURL, localRepo))
+	}
+	if slices.Contains(stages, rig.StageBeads) {
+		report.RequireTool(preflight.Beads, false, "")
+		report.RequireTool(preflight.Dolt, true, "the rig's server database won't be created")
+	}
+	return report
+}
+
+// doltMigratePreflight checks that dolt is installed and that .dolt-data
+// has room for every database that has to be copied rather than renamed.
+func doltMigratePreflight(townRoot string, migrations []doltserver.Migration) *preflight.Report {
+	report := preflight.New("dolt migrate")
+	report.RequireTool(preflight.Dolt, false, "")
+	dataDir := doltserver.DefaultConfig(townRoot).DataDir
+	var need int64
+	for _, m := range migrations {
+		if !preflight.SameFilesystem(m.SourcePath, dataDir) {
+			need += progress.DirSize(m.SourcePath)
+		}
+	}
+	report.RequireSpace("disk", dataDir, need)
+	return report
+}
+
+// townBackupPreflight checks that dolt is installed when there are
+// databases to back up, and that the staging directory and the archive
+// both fit.
+func townBackupPreflight(townRoot, output string, exclude []string) *preflight.Report {
+	report := preflight.New("town backup")
+	files, databases, err := townbackup.Estimate(townRoot, exclude)
+	if err != nil {
+		files, databases = -1, -1
+	}
+	if databases > 0 {
+		report.RequireTool(preflight.Dolt, false, "")
+	}
+	outDir := filepath.Dir(output)
+	switch {
+	case err != nil:
+		report.RequireSpace("disk", outDir, -1)
+	case preflight.SameFilesystem(os.TempDir(), outDir):
+		report.RequireSpace("disk", outDir, files+2*databases)
+	default:
+		report.RequireSpace("staging disk", os.TempDir(), databases)
+		report.RequireSpace("archive disk", outDir, files+databases)
+	}
+	return report
+}
+
+// printPreflight writes one line per check in report.
+func printPreflight(w io.Writer, report *preflight.Report) {
+	fmt.Fprintf(w, "  Preflight:\n")
+	for _, c := range report.Checks {
+		mark := style.Success.Render("✓")
+		switch c.Status {
+		case preflight.StatusWarn:
+			mark = style.Warning.Render("!")
+		case preflight.StatusFail:
+			mark = style.Error.Render("✗")
+		}
+		fmt.Fprintf(w, "    %s %-6s %s\n", mark, c.Name, c.Detail)
+	}
+}
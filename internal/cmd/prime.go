@@ -23,6 +23,8 @@ var primeDryRun bool
 var primeState bool
 var primeStateJSON bool
 var primeExplain bool
+var primeRole string
+var primeIssue string
 
 // primeHookSource stores the SessionStart source ("startup", "resume", "clear", "compact")
 // when running in hook mode. Used to provide lighter output on compaction/resume.
@@ -67,7 +69,17 @@ HOOK MODE (--hook):
   Claude Code sends JSON on stdin:
     {"session_id": "uuid", "transcript_path": "/path", "source": "startup|resume"}
 
-  Other agents can set GT_SESSION_ID environment variable instead.`,
+  Other agents can set GT_SESSION_ID environment variable instead.
+
+BRIEFING:
+  After the role context, prime adds a briefing built from live state: the
+  assigned bead (--issue, else $GT_ISSUE), the rig's CONTEXT.md, recent
+  commits mentioning the bead (or the hooked bead), and the rig's merge
+  queue policy. Polecat startup beacons pass --issue for their assignment.
+
+  --role previews another role's prime without touching its session:
+    gt prime --role gastown/witness
+    gt prime --role gastown/polecats/toast --issue gt-abc12`,
 	RunE: runPrime,
 }
 
@@ -82,6 +94,10 @@ func init() {
 		"Output state as JSON (requires --state)")
 	primeCmd.Flags().BoolVar(&primeExplain, "explain", false,
 		"Show why each section was included")
+	primeCmd.Flags().StringVar(&primeRole, "role", "",
+		"Prime as this role (e.g. mayor, gastown/witness) instead of the detected one; implies --dry-run")
+	primeCmd.Flags().StringVar(&primeIssue, "issue", "",
+		"Brief on this bead (default: $GT_ISSUE)")
 	rootCmd.AddCommand(primeCmd)
 }
 
@@ -102,6 +118,13 @@ func runPrime(cmd *cobra.Command, args []string) error {
 		return nil // Silent exit - not in workspace and not enabled
 	}
 
+	// --role primes as another agent: role detection reads GT_ROLE, and
+	// nothing is written on that agent's behalf.
+	if primeRole != "" {
+		_ = os.Setenv(EnvGTRole, primeRole)
+		primeDryRun = true
+	}
+
 	if primeHookMode {
 		handlePrimeHookMode(townRoot, cwd)
 	}
@@ -148,6 +171,7 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	if err := outputRoleContext(ctx); err != nil {
 		return err
 	}
+	outputPrimeBriefing(ctx)
 
 	hasSlungWork := checkSlungWork(ctx)
 	explain(hasSlungWork, "Autonomous mode: hooked/in-progress work detected")
@@ -207,6 +231,9 @@ func validatePrimeFlags() error {
 	if primeStateJSON && !primeState {
 		return fmt.Errorf("--json requires --state")
 	}
+	if primeRole != "" && primeHookMode {
+		return fmt.Errorf("--role cannot be combined with --hook")
+	}
 	return nil
 }
 
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// briefingCommitLimit is how many recent commits the briefing lists.
+const briefingCommitLimit = 5
+
+// primeBriefing is the part of gt prime built from live town state rather
+// than role templates: the agent's assignment, its rig's conventions, recent
+// commits relevant to the work, and how the merge queue will land it.
+type primeBriefing struct {
+	// Issue is the bead named by --issue or GT_ISSUE. Hooked work is shown
+	// by the Hooked Work section instead, so it is not repeated here.
+	Issue *beads.Issue
+
+	// IssueID is the bead commits were searched for, if any.
+	IssueID string
+
+	// Conventions is the rig's CONTEXT.md.
+	Conventions string
+
+	// Commits mention IssueID when IssueMatched is set; otherwise they are
+	// the latest commits in the agent's clone.
+	Commits      []git.LogCommit
+	IssueMatched bool
+
+	// MergeQueue is the rig's merge queue policy.
+	MergeQueue *config.MergeQueueConfig
+}
+
+// primeBriefingIssue returns the bead to brief on: --issue, then GT_ISSUE.
+func primeBriefingIssue() string {
+	if primeIssue != "" {
+		return primeIssue
+	}
+	return os.Getenv("GT_ISSUE")
+}
+
+// buildPrimeBriefing gathers the briefing for ctx. issueID is the bead the
+// agent was assigned, or "" to fall back to its hooked work. Sources that
+// are missing or unreadable are left out.
+func buildPrimeBriefing(ctx RoleContext, issueID string) *primeBriefing {
+	b := &primeBriefing{IssueID: issueID}
+
+	if issueID != "" {
+		bd := beads.New(beads.ResolveHookDir(ctx.TownRoot, issueID, ctx.WorkDir))
+		if issue, err := bd.Show(issueID); err == nil {
+			b.Issue = issue
+		} else {
+			explain(true, fmt.Sprintf("Briefing: bead %s not readable: %v", issueID, err))
+		}
+	} else if hooked := findAgentWork(ctx); hooked != nil {
+		b.IssueID = hooked.ID
+	}
+
+	repoDir := ctx.WorkDir
+	if ctx.Rig != "" {
+		rigPath := filepath.Join(ctx.TownRoot, ctx.Rig)
+		if data, err := os.ReadFile(filepath.Join(rigPath, "CONTEXT.md")); err == nil {
+			b.Conventions = strings.TrimSpace(string(data))
+		}
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+			b.MergeQueue = settings.MergeQueue
+		}
+		if !git.NewGit(repoDir).IsRepo() {
+			repoDir = filepath.Join(rigPath, "mayor", "rig")
+		}
+	}
+
+	g := git.NewGit(repoDir)
+	if g.IsRepo() {
+		if b.IssueID != "" {
+			if commits, err := g.RecentCommits(briefingCommitLimit, b.IssueID); err == nil && len(commits) > 0 {
+				b.Commits, b.IssueMatched = commits, true
+			}
+		}
+		if len(b.Commits) == 0 && ctx.Rig != "" {
+			b.Commits, _ = g.RecentCommits(briefingCommitLimit, "")
+		}
+	}
+	return b
+}
+
+// empty reports whether the briefing has nothing to say.
+func (b *primeBriefing) empty() bool {
+	return b.Issue == nil && b.Conventions == "" && len(b.Commits) == 0 && b.MergeQueue == nil
+}
+
+// render writes the briefing as a prime section.
+func (b *primeBriefing) render(w io.Writer) {
+	if b.empty() {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", style.Bold.Render("## 📋 Briefing"))
+
+	if issue := b.Issue; issue != nil {
+		fmt.Fprintf(w, "\n**Assigned:** %s — %s (%s, P%d)\n", issue.ID, issue.Title, issue.Status, issue.Priority)
+		if len(issue.Labels) > 0 {
+			fmt.Fprintf(w, "  Labels: %s\n", strings.Join(issue.Labels, ", "))
+		}
+		if len(issue.BlockedBy) > 0 {
+			fmt.Fprintf(w, "  Blocked by: %s\n", strings.Join(issue.BlockedBy, ", "))
+		}
+		if issue.Description != "" {
+			lines := strings.Split(strings.TrimSpace(issue.Description), "\n")
+			if len(lines) > 10 {
+				lines = append(lines[:10], "...")
+			}
+			for _, line := range lines {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+
+	if b.Conventions != "" {
+		fmt.Fprintf(w, "\n**Rig conventions:**\n%s\n", b.Conventions)
+	}
+
+	if len(b.Commits) > 0 {
+		if b.IssueMatched {
+			fmt.Fprintf(w, "\n**Commits mentioning %s:**\n", b.IssueID)
+		} else {
+			fmt.Fprintf(w, "\n**Recent commits:**\n")
+		}
+		for _, c := range b.Commits {
+			sha := c.SHA
+			if len(sha) > 8 {
+				sha = sha[:8]
+			}
+			fmt.Fprintf(w, "  %s %s\n", sha, c.Subject)
+		}
+	}
+
+	if mq := b.MergeQueue; mq != nil {
+		fmt.Fprintf(w, "\n**Merge queue:**\n")
+		if !mq.Enabled {
+			fmt.Fprintln(w, "  - Disabled: work is not merged by the refinery")
+		} else {
+			if mq.IsRunTestsEnabled() && mq.TestCommand != "" {
+				fmt.Fprintf(w, "  - Tests must pass: `%s`\n", mq.TestCommand)
+			}
+			for _, c := range []struct{ name, cmd string }{
+				{"Lint", mq.LintCommand},
+				{"Build", mq.BuildCommand},
+				{"Typecheck", mq.TypecheckCommand},
+			} {
+				if c.cmd != "" {
+					fmt.Fprintf(w, "  - %s: `%s`\n", c.name, c.cmd)
+				}
+			}
+			for _, h := range mq.PreMerge {
+				kind := "required"
+				if h.Optional {
+					kind = "optional"
+				}
+				fmt.Fprintf(w, "  - Pre-merge check %s (%s): `%s`\n", h.Name, kind, h.Cmd)
+			}
+			if mq.OnConflict != "" {
+				fmt.Fprintf(w, "  - On conflict: %s\n", mq.OnConflict)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// outputPrimeBriefing emits the live-state briefing for ctx.
+func outputPrimeBriefing(ctx RoleContext) {
+	issueID := primeBriefingIssue()
+	explain(issueID != "", "Briefing: assigned bead "+issueID)
+	buildPrimeBriefing(ctx, issueID).render(os.Stdout)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestPrimeBriefingRender(t *testing.T) {
+	runTests := false
+	b := &primeBriefing{
+		Issue: &beads.Issue{
+			ID: "gt-abc12", Title: "Fix the flux capacitor", Status: "hooked", Priority: 1,
+			Labels: []string{"bug"}, Description: "It sparks.",
+		},
+		IssueID:      "gt-abc12",
+		Conventions:  "Use table-driven tests.",
+		Commits:      []git.LogCommit{{SHA: "0123456789abcdef", Subject: "gt-abc12: first try"}},
+		IssueMatched: true,
+		MergeQueue: &config.MergeQueueConfig{
+			Enabled:     true,
+			RunTests:    &runTests,
+			TestCommand: "go test ./...",
+			LintCommand: "golangci-lint run",
+			PreMerge:    []config.MergeHookConfig{{Name: "vet", Cmd: "go vet ./...", Optional: true}},
+			OnConflict:  "assign_back",
+		},
+	}
+
+	var out bytes.Buffer
+	b.render(&out)
+	got := out.String()
+	for _, want := range []string{
+		"Briefing",
+		"gt-abc12 — Fix the flux capacitor (hooked, P1)",
+		"Labels: bug",
+		"It sparks.",
+		"Use table-driven tests.",
+		"Commits mentioning gt-abc12",
+		"01234567 gt-abc12: first try",
+		"Lint: `golangci-lint run`",
+		"Pre-merge check vet (optional): `go vet ./...`",
+		"On conflict: assign_back",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("briefing missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "go test ./...") {
+		t.Errorf("briefing lists tests although run_tests is off:\n%s", got)
+	}
+
+	out.Reset()
+	(&primeBriefing{}).render(&out)
+	if out.Len() != 0 {
+		t.Errorf("empty briefing rendered %q", out.String())
+	}
+}
+
+func TestBuildPrimeBriefing(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	clone := filepath.Join(rigPath, "mayor", "rig")
+	if err := os.MkdirAll(clone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "CONTEXT.md"), []byte("Squash before merging.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewRigSettings()
+	settings.MergeQueue.TestCommand = "make test"
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "Add the thing"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = clone
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// The town root is no clone, so commits come from the rig's mayor clone.
+	b := buildPrimeBriefing(RoleContext{Role: RoleUnknown, Rig: "gastown", TownRoot: town, WorkDir: town}, "")
+	if b.Conventions != "Squash before merging." {
+		t.Errorf("Conventions = %q", b.Conventions)
+	}
+	if b.MergeQueue == nil || b.MergeQueue.TestCommand != "make test" {
+		t.Errorf("MergeQueue = %+v", b.MergeQueue)
+	}
+	if len(b.Commits) != 1 || b.Commits[0].Subject != "Add the thing" || b.IssueMatched {
+		t.Errorf("Commits = %+v, matched %v", b.Commits, b.IssueMatched)
+	}
+}
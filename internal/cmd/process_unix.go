@@ -2,7 +2,10 @@
 
 package cmd
 
-import "syscall"
+import (
+	"os"
+	"syscall"
+)
 
 // isProcessRunning checks if a process with the given PID exists.
 func isProcessRunning(pid int) bool {
@@ -18,3 +21,13 @@ func isProcessRunning(pid int) bool {
 	// EPERM means process exists but we don't have permission to signal it.
 	return err == syscall.EPERM
 }
+
+// ownedByCurrentUser reports whether path belongs to the user running gt.
+func ownedByCurrentUser(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid()
+}
@@ -31,3 +31,10 @@ func isProcessRunning(pid int) bool {
 
 	return exitCode == processStillActive
 }
+
+// ownedByCurrentUser reports whether path belongs to the user running gt.
+// File ownership isn't checked on Windows, so this is always false and
+// permission checks fall back to the OS username.
+func ownedByCurrentUser(path string) bool {
+	return false
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Prompt IDs name each confirmation gt can ask for, so scripts running gt
+// non-interactively can tell which one stopped them. They are part of the
+// CLI's interface: don't rename them.
+const (
+	PromptRigUnsafeProceed  = "rig.unsafe-proceed"
+	PromptShutdown          = "shutdown.confirm"
+	PromptCleanupKill       = "cleanup.kill"
+	PromptOrphansRemove     = "orphans.remove"
+	PromptOrphansKillProcs  = "orphans.procs.kill"
+	PromptOrphansKillZombie = "orphans.zombies.kill"
+	PromptUninstall         = "uninstall.confirm"
+	PromptAgentsKill        = "agents.kill"
+	PromptEnvPush           = "env.push"
+	PromptConfigEditSave    = "config.edit.save"
+	PromptConfigEditRetry   = "config.edit.retry"
+)
+
+// exitPromptRequired is the exit code when a command stopped at a prompt it
+// wasn't allowed to ask.
+const exitPromptRequired = 4
+
+var (
+	assumeYesFlag bool // --yes
+	noInputFlag   bool // --no-input
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&assumeYesFlag, "yes", false,
+		"Accept safe confirmation prompts; fail on the rest instead of asking (implies --no-input)")
+	rootCmd.PersistentFlags().BoolVar(&noInputFlag, "no-input", false,
+		"Never prompt: commands that need confirmation fail with the prompt's ID (also GT_NONINTERACTIVE=1)")
+}
+
+// nonInteractive reports whether gt must not read answers from stdin.
+func nonInteractive() bool {
+	if assumeYesFlag || noInputFlag {
+		return true
+	}
+	switch strings.ToLower(os.Getenv("GT_NONINTERACTIVE")) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// canPrompt reports whether a prompt can be shown and answered.
+func canPrompt() bool {
+	return !nonInteractive() && isStdinTerminal()
+}
+
+// PromptRequiredError is returned when a command needs a confirmation that
+// gt may not ask for: --no-input or GT_NONINTERACTIVE is set, or the prompt
+// isn't one --yes accepts. Execute reports it as a JSON line on stderr and
+// exits with exitPromptRequired.
+type PromptRequiredError struct {
+	ID       string `json:"prompt_id"`
+	Question string `json:"question"`
+	Hint     string `json:"hint,omitempty"` // how to proceed without the prompt
+}
+
+func (e *PromptRequiredError) Error() string {
+	msg := fmt.Sprintf("confirmation required (prompt %s): %s", e.ID, e.Question)
+	if e.Hint != "" {
+		msg += "; " + e.Hint
+	}
+	return msg
+}
+
+// printPromptRequired writes the machine-readable form of e to stderr.
+func printPromptRequired(e *PromptRequiredError) {
+	data, err := json.Marshal(struct {
+		Error string `json:"error"`
+		*PromptRequiredError
+	}{"prompt_required", e})
+	if err == nil {
+		fmt.Fprintln(os.Stderr, string(data))
+	}
+}
+
+// confirmPrompt is a yes/no confirmation defaulting to no.
+type confirmPrompt struct {
+	ID       string
+	Question string
+
+	// Safe prompts are accepted by --yes. Leave it false for prompts
+	// guarding something irreversible; those need the command's own
+	// override flag, named in Hint.
+	Safe bool
+	Hint string
+}
+
+// readYesNo is the interactive prompt, replaceable in tests.
+var readYesNo = promptYesNo
+
+// confirm asks p's question. --yes accepts safe prompts without asking;
+// otherwise, when gt is non-interactive, it returns a *PromptRequiredError
+// instead of reading stdin.
+func confirm(p confirmPrompt) (bool, error) {
+	if p.Safe && assumeYesFlag {
+		return true, nil
+	}
+	if nonInteractive() {
+		return false, &PromptRequiredError{ID: p.ID, Question: p.Question, Hint: p.Hint}
+	}
+	return readYesNo(p.Question), nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func stubPromptMode(t *testing.T, yes, noInput bool) {
+	t.Helper()
+	oldYes, oldNoInput, oldRead := assumeYesFlag, noInputFlag, readYesNo
+	assumeYesFlag, noInputFlag = yes, noInput
+	readYesNo = func(string) bool {
+		t.Fatal("prompt read stdin in non-interactive mode")
+		return false
+	}
+	t.Setenv("GT_NONINTERACTIVE", "")
+	t.Cleanup(func() {
+		assumeYesFlag, noInputFlag, readYesNo = oldYes, oldNoInput, oldRead
+	})
+}
+
+func TestConfirm_YesAcceptsSafePrompts(t *testing.T) {
+	stubPromptMode(t, true, false)
+
+	ok, err := confirm(confirmPrompt{ID: PromptShutdown, Question: "Proceed with shutdown?", Safe: true})
+	if err != nil || !ok {
+		t.Fatalf("confirm = %v, %v; want true, nil", ok, err)
+	}
+}
+
+func TestConfirm_YesRefusesUnsafePrompts(t *testing.T) {
+	stubPromptMode(t, true, false)
+
+	_, err := confirm(confirmPrompt{ID: PromptOrphansRemove, Question: "Remove 3 orphan(s)?", Hint: "pass --force"})
+	var pe *PromptRequiredError
+	if !errors.As(err, &pe) {
+		t.Fatalf("err = %v, want *PromptRequiredError", err)
+	}
+	if pe.ID != PromptOrphansRemove || pe.Hint != "pass --force" {
+		t.Errorf("error = %+v", pe)
+	}
+}
+
+func TestConfirm_NoInputFails(t *testing.T) {
+	for name, setup := range map[string]func(t *testing.T){
+		"flag": func(t *testing.T) { noInputFlag = true },
+		"env":  func(t *testing.T) { t.Setenv("GT_NONINTERACTIVE", "true") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			stubPromptMode(t, false, false)
+			setup(t)
+
+			_, err := confirm(confirmPrompt{ID: PromptShutdown, Question: "Proceed with shutdown?", Safe: true})
+			var pe *PromptRequiredError
+			if !errors.As(fmt.Errorf("wrapped: %w", err), &pe) || pe.ID != PromptShutdown {
+				t.Fatalf("err = %v, want prompt %s required", err, PromptShutdown)
+			}
+		})
+	}
+}
+
+func TestConfirm_InteractiveReadsAnswer(t *testing.T) {
+	stubPromptMode(t, false, false)
+	readYesNo = func(q string) bool { return q == "Continue?" }
+
+	ok, err := confirm(confirmPrompt{ID: PromptUninstall, Question: "Continue?"})
+	if err != nil || !ok {
+		t.Fatalf("confirm = %v, %v; want true, nil", ok, err)
+	}
+}
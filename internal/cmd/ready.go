@@ -149,10 +149,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 		wg.Add(1)
 		go func(r *rig.Rig) {
 			defer wg.Done()
-			// Use rig root path where rig-level beads are stored
-			// BeadsPath returns rig root; redirect system handles mayor/rig routing
-			rigBeads := beads.New(r.BeadsPath())
-			issues, err := rigBeads.Ready()
+			issues, err := readyRigIssues(r)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -160,14 +157,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				src.Error = err.Error()
 			} else {
-				// Filter out formula scaffolds (gt-579)
-				formulaNames := getFormulaNames(r.BeadsPath())
-				filtered := filterFormulaScaffolds(issues, formulaNames)
-				// Defense-in-depth: also filter wisps that shouldn't appear in ready work
-				wispIDs := getWispIDs(r.BeadsPath())
-				filtered = filterWisps(filtered, wispIDs)
-				// Filter identity beads (agents, roles, rigs) - not actionable work
-				src.Issues = filterIdentityBeads(filtered)
+				src.Issues = issues
 			}
 			sources = append(sources, src)
 		}(r)
@@ -253,6 +243,26 @@ func runReady(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// readyRigIssues returns a rig's actionable ready issues, excluding formula
+// scaffolds, wisps, and identity beads.
+func readyRigIssues(r *rig.Rig) ([]*beads.Issue, error) {
+	// Use rig root path where rig-level beads are stored
+	// BeadsPath returns rig root; redirect system handles mayor/rig routing
+	rigBeads := beads.New(r.BeadsPath())
+	issues, err := rigBeads.Ready()
+	if err != nil {
+		return nil, err
+	}
+	// Filter out formula scaffolds (gt-579)
+	formulaNames := getFormulaNames(r.BeadsPath())
+	filtered := filterFormulaScaffolds(issues, formulaNames)
+	// Defense-in-depth: also filter wisps that shouldn't appear in ready work
+	wispIDs := getWispIDs(r.BeadsPath())
+	filtered = filterWisps(filtered, wispIDs)
+	// Filter identity beads (agents, roles, rigs) - not actionable work
+	return filterIdentityBeads(filtered), nil
+}
+
 func printReadyHuman(result ReadyResult) error {
 	if result.Summary.Total == 0 {
 		fmt.Println("No ready work across town.")
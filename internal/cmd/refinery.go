@@ -191,6 +191,13 @@ var refineryReadyCmd = &cobra.Command{
 Shows MRs that are:
 - Not currently claimed by any worker (or claim is stale)
 - Not blocked by an open task (e.g., conflict resolution in progress)
+- In a lane with room under its concurrency limit
+
+Nothing is ready while the queue is paused ('gt mq pause').
+
+MRs are listed in processing order: the expedite lane (see 'gt mq promote')
+first, then the normal lane, except that a normal MR waiting longer than
+merge_queue.normal_lane_max_wait goes ahead of both.
 
 This is the preferred command for finding work to process.
 
@@ -486,6 +493,12 @@ func runRefineryQueue(cmd *cobra.Command, args []string) error {
 			item.MR.Branch,
 			issueInfo,
 			style.Dim.Render(item.Age))
+		if item.MR.HookResults != "" {
+			fmt.Printf("      %s\n", style.Dim.Render("hooks: "+item.MR.HookResults))
+		}
+		if item.MR.TestRuns != "" {
+			fmt.Printf("      %s\n", style.Dim.Render("tests: "+item.MR.TestRuns))
+		}
 	}
 
 	return nil
@@ -699,6 +712,12 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 
 	// Create engineer for the rig (it has beads access for status checking)
 	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if refineryReadyJSON {
+		eng.SetOutput(os.Stderr) // keep stdout valid JSON
+	}
 
 	if refineryReadyAll {
 		return runRefineryReadyAll(eng, rigName)
@@ -719,18 +738,26 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 		type readyOutput struct {
 			Ready     []*refinery.MRInfo    `json:"ready"`
 			Anomalies []*refinery.MRAnomaly `json:"anomalies,omitempty"`
+			Paused    *refinery.QueuePause  `json:"paused,omitempty"`
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(readyOutput{
 			Ready:     ready,
 			Anomalies: anomalies,
+			Paused:    eng.Paused(),
 		})
 	}
 
 	// Human-readable output
 	fmt.Printf("%s Ready MRs for '%s':\n\n", style.Bold.Render("🚀"), rigName)
 
+	if pause := eng.Paused(); pause != nil {
+		fmt.Printf("  %s Merge queue %s\n", style.Warning.Render("⏸"), formatQueuePause(pause))
+		fmt.Printf("  %s\n", style.Dim.Render("Finish the MR in flight; hold the rest until 'gt mq resume "+rigName+"'"))
+		return nil
+	}
+
 	if len(ready) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(none ready)"))
 		return nil
@@ -738,6 +765,9 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 
 	for i, mr := range ready {
 		priority := fmt.Sprintf("P%d", mr.Priority)
+		if mr.Lane == refinery.LaneExpedite {
+			priority += " expedite"
+		}
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
 		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
 	}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Release cut flags
+var (
+	releaseCutRig     string
+	releaseCutVersion string
+	releaseCutBump    string
+	releaseCutBranch  bool
+	releaseCutDryRun  bool
+	releaseCutJSON    bool
+)
+
+var releaseCutCmd = &cobra.Command{
+	Use:   "cut",
+	Short: "Tag a release of a rig's default branch",
+	Long: `Cut a release of a rig's default branch.
+
+Creates an annotated tag at the tip of origin/<default-branch>, named by
+bumping the last release tag's version (v1.2.3 -> v1.2.4 by default). The tag
+message is a changelog of the commits merged since the last release, titled
+from the beads they implemented. The tag is pushed, and the release is
+recorded as a gt:release bead.
+
+The merge queue can also cut releases on its own: set
+merge_queue.release.every to release after that many merges. Set
+merge_queue.release.branch to create a release/<tag> branch each time, and
+merge_queue.release.tag_prefix to change the "v" prefix.
+
+Examples:
+  gt release cut --rig gastown
+  gt release cut --rig gastown --bump minor
+  gt release cut --rig gastown --version 2.0.0 --branch
+  gt release cut --rig gastown --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runReleaseCut,
+}
+
+func init() {
+	releaseCutCmd.Flags().StringVar(&releaseCutRig, "rig", "", "Rig to release (default: infer from cwd)")
+	releaseCutCmd.Flags().StringVar(&releaseCutVersion, "version", "", "Release version (default: bump the last release)")
+	releaseCutCmd.Flags().StringVar(&releaseCutBump, "bump", "patch", "Version part to bump: major, minor, or patch")
+	releaseCutCmd.Flags().BoolVar(&releaseCutBranch, "branch", false, "Also create a release/<tag> branch")
+	releaseCutCmd.Flags().BoolVar(&releaseCutDryRun, "dry-run", false, "Show the release and changelog without tagging")
+	releaseCutCmd.Flags().BoolVar(&releaseCutJSON, "json", false, "Output as JSON")
+	releaseCutCmd.MarkFlagsMutuallyExclusive("version", "bump")
+
+	releaseCmd.AddCommand(releaseCutCmd)
+}
+
+func runReleaseCut(cmd *cobra.Command, args []string) error {
+	_, r, rigName, err := getRefineryManager(releaseCutRig)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if releaseCutJSON {
+		eng.SetOutput(cmd.ErrOrStderr())
+	}
+
+	rel, err := eng.CutRelease(refinery.ReleaseOptions{
+		Version: releaseCutVersion,
+		Bump:    releaseCutBump,
+		Branch:  releaseCutBranch,
+		DryRun:  releaseCutDryRun,
+		Actor:   detectSender(),
+	})
+	if err != nil {
+		return fmt.Errorf("cutting release for %s: %w", rigName, err)
+	}
+
+	if releaseCutJSON {
+		out, err := json.MarshalIndent(rel, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling release: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if releaseCutDryRun {
+		fmt.Printf("%s Would release %s at %s (%s)\n\n", style.Dim.Render("ℹ"), rel.Tag, shortSHA(rel.Commit), rel.Target)
+	} else {
+		fmt.Printf("%s Released %s at %s (%s)\n", style.Success.Render("✓"), rel.Tag, shortSHA(rel.Commit), rel.Target)
+		if rel.Branch != "" {
+			fmt.Printf("  Branch: %s\n", rel.Branch)
+		}
+		if rel.BeadID != "" {
+			fmt.Printf("  Bead:   %s\n", rel.BeadID)
+		}
+		fmt.Println()
+	}
+	fmt.Print(refinery.FormatChangelog(rel))
+	return nil
+}
+
+// shortSHA abbreviates a commit hash for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Report command flags
+var (
+	reportDB      string
+	reportJSON    bool
+	reportNoCache bool
+	reportWeeks   int
+	reportDays    int
+	reportLimit   int
+)
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	GroupID: GroupDiag,
+	Short:   "Analytics reports over beads data",
+	Long: `Run common analytics against a rig's beads database on the Dolt server.
+
+Reports:
+  throughput    Work items closed per week
+  cycle-time    Created-to-closed time for recently closed work
+  aging         Open and in-progress work, oldest first
+  completion    Per-polecat completion rates
+
+Only work items (task, bug, feature, epic, chore) are counted; agent,
+message, and molecule beads are excluded. Results are cached for 5 minutes
+under .runtime/reports/ (use --no-cache to force a fresh query).
+
+Requires a running Dolt server ('gt dolt start').
+
+Examples:
+  gt report throughput --db gastown --weeks 8
+  gt report cycle-time --db gastown --days 14
+  gt report aging --db beads --limit 20
+  gt report completion --db gastown --json`,
+	RunE: requireSubcommand,
+}
+
+var reportThroughputCmd = &cobra.Command{
+	Use:   "throughput",
+	Short: "Work items closed per week",
+	Args:  cobra.NoArgs,
+	RunE:  runReportThroughput,
+}
+
+var reportCycleTimeCmd = &cobra.Command{
+	Use:   "cycle-time",
+	Short: "Created-to-closed time for recently closed work",
+	Args:  cobra.NoArgs,
+	RunE:  runReportCycleTime,
+}
+
+var reportAgingCmd = &cobra.Command{
+	Use:   "aging",
+	Short: "Open and in-progress work, oldest first",
+	Args:  cobra.NoArgs,
+	RunE:  runReportAging,
+}
+
+var reportCompletionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: "Per-polecat completion rates",
+	Args:  cobra.NoArgs,
+	RunE:  runReportCompletion,
+}
+
+func init() {
+	reportCmd.PersistentFlags().StringVar(&reportDB, "db", "", "Rig database to report on (required)")
+	reportCmd.PersistentFlags().BoolVar(&reportJSON, "json", false, "Output as JSON")
+	reportCmd.PersistentFlags().BoolVar(&reportNoCache, "no-cache", false, "Bypass cached results")
+
+	reportThroughputCmd.Flags().IntVar(&reportWeeks, "weeks", 12, "Number of weeks to include")
+	reportCycleTimeCmd.Flags().IntVar(&reportDays, "days", 30, "Include work closed in the last N days")
+	reportCycleTimeCmd.Flags().IntVarP(&reportLimit, "limit", "n", 50, "Maximum issues to show")
+	reportAgingCmd.Flags().IntVarP(&reportLimit, "limit", "n", 50, "Maximum issues to show")
+	reportCompletionCmd.Flags().IntVar(&reportDays, "days", 30, "Include work created in the last N days")
+
+	reportCmd.AddCommand(reportThroughputCmd)
+	reportCmd.AddCommand(reportCycleTimeCmd)
+	reportCmd.AddCommand(reportAgingCmd)
+	reportCmd.AddCommand(reportCompletionCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+// newReporter connects to the Dolt server for --db.
+func newReporter() (*beads.Reporter, error) {
+	if reportDB == "" {
+		return nil, fmt.Errorf("--db is required (see 'gt dolt list' for databases)")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return nil, fmt.Errorf("Dolt server is not running (start it with 'gt dolt start')")
+	}
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if !slices.Contains(databases, reportDB) {
+		return nil, fmt.Errorf("database %q not found (see 'gt dolt list')", reportDB)
+	}
+
+	db, err := doltserver.DB(townRoot, reportDB)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := beads.DefaultReportCacheTTL
+	if reportNoCache {
+		ttl = 0
+	}
+	cacheDir := filepath.Join(constants.TownRuntimePath(townRoot), "reports")
+	r := beads.NewReporter(db, reportDB, cacheDir, ttl)
+	r.SetRetryPolicies(doltserver.RetryPolicies(townRoot))
+	return r, nil
+}
+
+func reportContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 30*time.Second)
+}
+
+func runReportThroughput(cmd *cobra.Command, args []string) error {
+	r, err := newReporter()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := reportContext()
+	defer cancel()
+
+	rows, err := r.Throughput(ctx, reportWeeks)
+	if err != nil {
+		return err
+	}
+	if reportJSON {
+		return printReportJSON(rows)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "WEEK\tCLOSED\t")
+	for _, t := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", t.WeekStart, t.Closed, strings.Repeat("█", min(t.Closed, 60)))
+	}
+	return tw.Flush()
+}
+
+func runReportCycleTime(cmd *cobra.Command, args []string) error {
+	r, err := newReporter()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := reportContext()
+	defer cancel()
+
+	rows, err := r.CycleTime(ctx, reportDays, reportLimit)
+	if err != nil {
+		return err
+	}
+	if reportJSON {
+		return printReportJSON(rows)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCYCLE\tCLOSED\tASSIGNEE\tTITLE")
+	var total float64
+	for _, c := range rows {
+		total += c.Hours
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.ID, formatReportHours(c.Hours), c.ClosedAt, c.Assignee, c.Title)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		fmt.Printf("\nMean cycle time: %s over %d issues\n", formatReportHours(total/float64(len(rows))), len(rows))
+	}
+	return nil
+}
+
+func runReportAging(cmd *cobra.Command, args []string) error {
+	r, err := newReporter()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := reportContext()
+	defer cancel()
+
+	rows, err := r.Aging(ctx, reportLimit)
+	if err != nil {
+		return err
+	}
+	if reportJSON {
+		return printReportJSON(rows)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tAGE\tIDLE\tASSIGNEE\tTITLE")
+	for _, a := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, a.Status,
+			formatReportHours(a.AgeDays*24), formatReportHours(a.IdleDays*24), a.Assignee, a.Title)
+	}
+	return tw.Flush()
+}
+
+func runReportCompletion(cmd *cobra.Command, args []string) error {
+	r, err := newReporter()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := reportContext()
+	defer cancel()
+
+	rows, err := r.PolecatCompletion(ctx, reportDays)
+	if err != nil {
+		return err
+	}
+	if reportJSON {
+		return printReportJSON(rows)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POLECAT\tASSIGNED\tCLOSED\tRATE")
+	for _, c := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.0f%%\n", c.Assignee, c.Assigned, c.Closed, c.Rate*100)
+	}
+	return tw.Flush()
+}
+
+func printReportJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatReportHours renders a duration in hours as "45m", "6.5h", or "3.2d".
+func formatReportHours(hours float64) string {
+	switch {
+	case hours < 1:
+		return fmt.Sprintf("%.0fm", hours*60)
+	case hours < 48:
+		return fmt.Sprintf("%.1fh", hours)
+	default:
+		return fmt.Sprintf("%.1fd", hours/24)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/steveyegge/gastown/internal/deps"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -48,8 +50,9 @@ A rig is a container for managing a project and its agents:
 }
 
 var rigAddCmd = &cobra.Command{
-	Use:   "add <name> <git-url>",
-	Short: "Add a new rig to the workspace",
+	Use:         "add <name> <git-url>",
+	Annotations: auditAnnotations(""),
+	Short:       "Add a new rig to the workspace",
 	Long: `Add a new rig by cloning a repository.
 
 This creates a rig container with:
@@ -67,6 +70,30 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+The work runs in stages, recorded in <rig>/.runtime/provision.json:
+
+  config    config.json and the rig settings directory
+  clone     shared bare repo, mayor clone, refinery worktree, agent dirs
+  beads     beads database, Dolt metadata, route, rig identity bead
+  hooks     polecat settings and commands, agent hooks, plugin rig-add hooks
+  patrols   agent beads, patrol molecules, plugin dirs, daemon patrols
+
+Before anything is created, a preflight checks that git, bd, and dolt are
+installed and new enough, that the remote is reachable, and that there is
+room for the clones (measured for local sources, otherwise a fixed
+headroom), and aborts with a report if not. --skip-preflight bypasses it.
+
+Submodules are initialized recursively in every clone and worktree of the
+rig, and git-lfs objects are fetched when the repo's .gitattributes uses the
+lfs filter. --no-submodules and --lfs on|off change this; both are saved
+under "git" in the rig's settings/config.json. With --lfs on, a missing
+git-lfs fails the preflight; otherwise it is a warning.
+
+Use --skip or --only to choose stages (config always runs), or --no-agents
+to skip hooks and patrols. If a stage fails the rig directory is kept; fix
+the problem and resume with 'gt rig provision <name>'. The rig is registered
+in mayor/rigs.json once no stage is left pending or failed.
+
 Use --adopt to register an existing directory instead of creating new:
   - Reads existing config.json if present
   - Auto-detects git URL from origin remote (git-url argument not required)
@@ -75,6 +102,7 @@ Use --adopt to register an existing directory instead of creating new:
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add my-project git@github.com:user/repo.git --no-agents
   gt rig add existing-rig --adopt`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
@@ -98,8 +126,9 @@ Examples:
 }
 
 var rigRemoveCmd = &cobra.Command{
-	Use:   "remove <name>",
-	Short: "Remove a rig from the registry (does not delete files)",
+	Use:         "remove <name>",
+	Annotations: auditAnnotations(""),
+	Short:       "Remove a rig from the registry (does not delete files)",
 	Long: `Remove a rig from the Gas Town registry.
 
 This only removes the rig entry from mayor/rigs.json and cleans up
@@ -120,18 +149,23 @@ Examples:
 }
 
 var rigResetCmd = &cobra.Command{
-	Use:   "reset",
-	Short: "Reset rig state (handoff content, mail, stale issues)",
+	Use:         "reset",
+	Annotations: auditAnnotations(""),
+	Short:       "Reset rig state (handoff content, mail, stale issues, sessions)",
 	Long: `Reset various rig state.
 
-By default, resets all resettable state. Use flags to reset specific items.
+By default, resets handoff content, mail, and stale issues. Use flags to reset
+specific items. Orphaned tmux sessions are only cleaned with --sessions: these
+are sessions with the rig's prefix but no registered polecat, crew worker,
+witness, or refinery, usually left behind by a crash.
 
 Examples:
   gt rig reset              # Reset all state
   gt rig reset --handoff    # Clear handoff content only
   gt rig reset --mail       # Clear stale mail messages only
   gt rig reset --stale      # Reset orphaned in_progress issues
-  gt rig reset --stale --dry-run  # Preview what would be reset`,
+  gt rig reset --stale --dry-run  # Preview what would be reset
+  gt rig reset --sessions --rig gastown --dry-run  # List orphaned sessions`,
 	RunE: runRigReset,
 }
 
@@ -166,10 +200,15 @@ For each rig, it starts:
 Polecats are NOT started by this command - they are spawned
 on demand when work is assigned.
 
+Rigs inside a scheduled maintenance window (the "schedule" block in
+<rig>/settings/config.json) are skipped. Use --ignore-schedule to start
+them anyway; the daemon will leave them running until the window ends.
+
 Examples:
   gt rig start gastown
   gt rig start gastown beads
-  gt rig start gastown beads myproject`,
+  gt rig start gastown beads myproject
+  gt rig start gastown --ignore-schedule`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runRigStart,
 }
@@ -204,12 +243,23 @@ Before shutdown, checks all polecats for uncommitted work:
 - Stashes
 - Unpushed commits
 
+With --check-clones, or work_check in the rig's settings/config.json, crew
+and refinery clones are checked too.
+
 Use --force to force immediate shutdown (prompts if uncommitted work).
 Use --nuclear to bypass ALL safety checks (will lose work!).
 
+Use --timeout to bound how long shutdown takes without skipping the checks:
+agents are asked to save state and exit, interrupted with Ctrl-C if still
+running after --graceful-timeout (default 2/3 of --timeout), and killed if
+still running after --interrupt-timeout (default the rest). A report at the
+end lists which agents had to be killed.
+
 Examples:
   gt rig shutdown greenplace
   gt rig shutdown greenplace --force
+  gt rig shutdown greenplace --timeout 90s
+  gt rig shutdown greenplace --graceful-timeout 2m --interrupt-timeout 15s
   gt rig shutdown greenplace --nuclear  # DANGER: loses uncommitted work`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRigShutdown,
@@ -224,6 +274,7 @@ If no rig is specified, infers the rig from the current directory.
 
 Displays:
 - Rig information (name, path, beads prefix)
+- Beads summary (status counts, oldest in-progress age, ready unassigned work)
 - Witness status (running/stopped, uptime)
 - Refinery status (running/stopped, uptime, queue size)
 - Polecats (name, state, assigned issue, session status)
@@ -253,6 +304,9 @@ Before shutdown, checks all polecats for uncommitted work:
 - Stashes
 - Unpushed commits
 
+With --check-clones, or work_check in the rig's settings/config.json, crew
+and refinery clones are checked too.
+
 Use --force to force immediate shutdown (prompts if uncommitted work).
 Use --nuclear to bypass ALL safety checks (will lose work!).
 
@@ -278,6 +332,9 @@ Before shutdown, checks all polecats for uncommitted work:
 - Stashes
 - Unpushed commits
 
+With --check-clones, or work_check in the rig's settings/config.json, crew
+and refinery clones are checked too.
+
 Use --force to force immediate shutdown (prompts if uncommitted work).
 Use --nuclear to bypass ALL safety checks (will lose work!).
 
@@ -299,6 +356,12 @@ var (
 	rigAddAdopt        bool
 	rigAddAdoptURL     string
 	rigAddAdoptForce   bool
+	rigAddSkip         []string
+	rigAddOnly         []string
+	rigAddNoAgents     bool
+	rigAddNoPreflight  bool
+	rigAddNoSubmodules bool
+	rigAddLFS          string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -312,6 +375,7 @@ var (
 	rigStopNuclear     bool
 	rigRestartForce    bool
 	rigRestartNuclear  bool
+	rigCheckClones     bool
 	rigListJSON        bool
 	rigRemoveForce     bool
 )
@@ -323,6 +387,9 @@ var (
 		polecatMgr := polecat.NewManager(r, polecatGit, nil) // nil tmux: just listing
 		return polecatMgr.List()
 	}
+	listCrewForWorkCheck = func(r *rig.Rig) ([]*crew.CrewWorker, error) {
+		return crew.NewManager(r, git.NewGit(r.Path)).List()
+	}
 	checkPolecatWorkStatus = func(clonePath string) (*git.UncommittedWorkStatus, error) {
 		pGit := git.NewGit(clonePath)
 		return pGit.CheckUncommittedWork()
@@ -358,6 +425,12 @@ func init() {
 	rigAddCmd.Flags().BoolVar(&rigAddAdopt, "adopt", false, "Adopt an existing directory instead of creating new")
 	rigAddCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL for --adopt (default: auto-detected from origin)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
+	rigAddCmd.Flags().StringSliceVar(&rigAddSkip, "skip", nil, "Provisioning stages to skip (clone, beads, hooks, patrols)")
+	rigAddCmd.Flags().StringSliceVar(&rigAddOnly, "only", nil, "Run only these provisioning stages (config always runs)")
+	rigAddCmd.Flags().BoolVar(&rigAddNoAgents, "no-agents", false, "Skip the hooks and patrols stages")
+	rigAddCmd.Flags().BoolVar(&rigAddNoPreflight, "skip-preflight", false, "Skip the tool, remote, and free-disk checks")
+	rigAddCmd.Flags().BoolVar(&rigAddNoSubmodules, "no-submodules", false, "Don't initialize submodules in the rig's clones and worktrees")
+	rigAddCmd.Flags().StringVar(&rigAddLFS, "lfs", "", "Git LFS handling: auto (default), on, or off")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -376,17 +449,30 @@ func init() {
 
 	rigRestartCmd.Flags().BoolVarP(&rigRestartForce, "force", "f", false, "Force immediate shutdown during restart (prompts if uncommitted work)")
 	rigRestartCmd.Flags().BoolVar(&rigRestartNuclear, "nuclear", false, "DANGER: Bypass ALL safety checks (loses uncommitted work!)")
+
+	for _, c := range []*cobra.Command{rigShutdownCmd, rigRebootCmd, rigStopCmd, rigRestartCmd} {
+		c.Flags().BoolVar(&rigCheckClones, "check-clones", false, "Also check crew and refinery clones for uncommitted work")
+	}
 }
 
 func confirmUnsafeProceed(force bool) bool {
 	// If --force and interactive TTY, prompt.
-	if force && isStdinTerminal() {
+	if force && canPrompt() {
 		fmt.Println()
 		return promptYesNoUnsafeProceed("Proceed anyway?")
 	}
 
-	// Otherwise block with hint.
-	if force {
+	// Otherwise block with hint. Losing work is never auto-accepted, not
+	// even with --yes.
+	if force && nonInteractive() {
+		fmt.Printf("\nConfirmation is disabled (--yes/--no-input/GT_NONINTERACTIVE). Use %s to skip all checks (DANGER: will lose work!)\n",
+			style.Bold.Render("--nuclear"))
+		printPromptRequired(&PromptRequiredError{
+			ID:       PromptRigUnsafeProceed,
+			Question: "Proceed anyway? (uncommitted work found)",
+			Hint:     "pass --nuclear to skip all checks",
+		})
+	} else if force {
 		fmt.Printf("\n%s requires an interactive terminal. Use %s to skip all checks (DANGER: will lose work!)\n",
 			style.Bold.Render("--force"), style.Bold.Render("--nuclear"))
 	} else {
@@ -396,7 +482,9 @@ func confirmUnsafeProceed(force bool) bool {
 	return false
 }
 
-// checkUncommittedWork checks polecats in a rig for uncommitted work.
+// checkUncommittedWork checks polecats in a rig for uncommitted work, plus
+// crew and refinery clones when --check-clones is passed or the rig's
+// work_check setting enables them.
 // operation is the verb shown in the warning (e.g. "stop", "shutdown", "restart").
 // Returns true if the caller should proceed, false if it should abort.
 // When force is true and stdin is a TTY, prompts the user to confirm.
@@ -409,11 +497,36 @@ func checkUncommittedWork(r *rig.Rig, rigName, operation string, force bool) (pr
 			style.Warning.Render("⚠"), err)
 		return confirmUnsafeProceed(force)
 	}
-	if len(polecats) == 0 {
+
+	type clone struct{ name, path string }
+	var clones []clone
+	for _, p := range polecats {
+		clones = append(clones, clone{p.Name, p.ClonePath})
+	}
+
+	checkCrew, checkRefinery := workCheckClones(r)
+	if checkCrew {
+		workers, err := listCrewForWorkCheck(r)
+		if err != nil {
+			fmt.Printf("%s Could not check crew for uncommitted work: %v\n",
+				style.Warning.Render("⚠"), err)
+			return confirmUnsafeProceed(force)
+		}
+		for _, w := range workers {
+			clones = append(clones, clone{"crew/" + w.Name, w.ClonePath})
+		}
+	}
+	if checkRefinery {
+		refineryPath := filepath.Join(r.Path, "refinery", "rig")
+		if _, err := os.Stat(refineryPath); err == nil {
+			clones = append(clones, clone{"refinery", refineryPath})
+		}
+	}
+	if len(clones) == 0 {
 		return true
 	}
 
-	var problemPolecats []struct {
+	var problems []struct {
 		name   string
 		status *git.UncommittedWorkStatus
 	}
@@ -421,37 +534,41 @@ func checkUncommittedWork(r *rig.Rig, rigName, operation string, force bool) (pr
 		name string
 		err  error
 	}
-	for _, p := range polecats {
-		status, err := checkPolecatWorkStatus(p.ClonePath)
+	for _, c := range clones {
+		status, err := checkPolecatWorkStatus(c.path)
 		if err != nil {
 			checkErrors = append(checkErrors, struct {
 				name string
 				err  error
-			}{p.Name, err})
+			}{c.name, err})
 			continue
 		}
 		if status == nil {
 			checkErrors = append(checkErrors, struct {
 				name string
 				err  error
-			}{p.Name, fmt.Errorf("no status returned")})
+			}{c.name, fmt.Errorf("no status returned")})
 			continue
 		}
 		if !status.Clean() {
-			problemPolecats = append(problemPolecats, struct {
+			problems = append(problems, struct {
 				name   string
 				status *git.UncommittedWorkStatus
-			}{p.Name, status})
+			}{c.name, status})
 		}
 	}
-	if len(problemPolecats) == 0 && len(checkErrors) == 0 {
+	if len(problems) == 0 && len(checkErrors) == 0 {
 		return true
 	}
 
-	if len(problemPolecats) > 0 {
-		fmt.Printf("\n%s Cannot %s %s - polecats have uncommitted work:\n",
-			style.Warning.Render("⚠"), operation, rigName)
-		for _, pp := range problemPolecats {
+	if len(problems) > 0 {
+		what := "polecats"
+		if checkCrew || checkRefinery {
+			what = "workspaces"
+		}
+		fmt.Printf("\n%s Cannot %s %s - %s have uncommitted work:\n",
+			style.Warning.Render("⚠"), operation, rigName, what)
+		for _, pp := range problems {
 			fmt.Printf("  %s: %s\n", style.Bold.Render(pp.name), pp.status.String())
 		}
 	}
@@ -465,6 +582,20 @@ func checkUncommittedWork(r *rig.Rig, rigName, operation string, force bool) (pr
 	return confirmUnsafeProceed(force)
 }
 
+// workCheckClones reports whether checkUncommittedWork should also inspect
+// crew and refinery clones: --check-clones enables both, otherwise the rig's
+// work_check setting decides.
+func workCheckClones(r *rig.Rig) (crew, refinery bool) {
+	if rigCheckClones {
+		return true, true
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.WorkCheck == nil {
+		return false, false
+	}
+	return settings.WorkCheck.Crew, settings.WorkCheck.Refinery
+}
+
 func runRigAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -521,6 +652,38 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid push URL %q: expected a remote URL (https://, git@, ssh://, git://)", rigAddPushURL)
 	}
 
+	skip := rigAddSkip
+	if rigAddNoAgents {
+		skip = append(skip, rig.AgentStages...)
+	}
+	stages, err := rig.SelectStages(rigAddOnly, skip)
+	if err != nil {
+		return err
+	}
+	if len(rigAddOnly) > 0 && !slices.Contains(stages, rig.StageConfig) {
+		stages = append([]string{rig.StageConfig}, stages...)
+	}
+
+	var gitCfg *config.GitConfig
+	if rigAddNoSubmodules || rigAddLFS != "" {
+		gitCfg = &config.GitConfig{LFS: rigAddLFS}
+		if rigAddNoSubmodules {
+			off := false
+			gitCfg.Submodules = &off
+		}
+		if err := gitCfg.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if !rigAddNoPreflight {
+		report := rigAddPreflight(townRoot, gitURL, rigAddLocalRepo, gitCfg.LFSMode(), stages)
+		printPreflight(os.Stdout, report)
+		if err := report.Err(); err != nil {
+			return err
+		}
+	}
+
 	startTime := time.Now()
 
 	// Add the rig
@@ -531,8 +694,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		Git:           gitCfg,
+		Stages:        stages,
+		AfterStage:    rigStageFollowUp(townRoot, name),
 	})
 	if err != nil {
+		if _, statErr := os.Stat(filepath.Join(townRoot, name, rig.ProvisionFile)); statErr == nil {
+			return fmt.Errorf("adding rig: %w\n\nFix the problem, then resume with:\n  gt rig provision %s", err, name)
+		}
 		return fmt.Errorf("adding rig: %w", err)
 	}
 
@@ -541,46 +710,6 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("saving rigs config: %w", err)
 	}
 
-	// Add new rig to daemon.json patrol config (witness + refinery rigs arrays)
-	if err := config.AddRigToDaemonPatrols(townRoot, name); err != nil {
-		// Non-fatal: daemon will still work, just won't auto-manage this rig
-		fmt.Printf("  %s Could not update daemon.json patrols: %v\n", style.Warning.Render("!"), err)
-	}
-
-	// Route registration is now handled inside AddRig (before agent bead creation)
-	// to avoid "no route found" warnings (#1424). Determine beadsWorkDir for rig identity bead.
-	var beadsWorkDir string
-	if newRig.Config.Prefix != "" {
-		mayorRigBeads := filepath.Join(townRoot, name, "mayor", "rig", ".beads")
-		if _, err := os.Stat(mayorRigBeads); err == nil {
-			beadsWorkDir = filepath.Join(townRoot, name, "mayor", "rig")
-		} else {
-			beadsWorkDir = filepath.Join(townRoot, name)
-		}
-	}
-
-	// Create rig identity bead
-	if newRig.Config.Prefix != "" && beadsWorkDir != "" {
-		bd := beads.New(beadsWorkDir)
-		fields := &beads.RigFields{
-			Repo:   gitURL,
-			Prefix: newRig.Config.Prefix,
-			State:  beads.RigStateActive,
-		}
-		if _, err := bd.CreateRigBead(name, fields); err != nil {
-			// Non-fatal: rig is functional without the identity bead
-			fmt.Printf("  %s Could not create rig identity bead: %v\n", style.Warning.Render("!"), err)
-		} else {
-			rigBeadID := beads.RigBeadIDWithPrefix(newRig.Config.Prefix, name)
-			fmt.Printf("  Created rig identity bead: %s\n", rigBeadID)
-		}
-	}
-
-	// Sync hooks for the new rig's targets
-	if err := syncRigHooks(townRoot, name); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks for new rig: %v\n", err)
-	}
-
 	elapsed := time.Since(startTime)
 
 	// Read default branch from rig config
@@ -602,6 +731,11 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  ├── witness/\n")
 	fmt.Printf("  └── polecats/         (.claude/ scaffolded for polecat sessions)\n")
 
+	if skipped := skippedStages(filepath.Join(townRoot, name)); len(skipped) > 0 {
+		fmt.Printf("\nSkipped stages: %s\n", strings.Join(skipped, ", "))
+		fmt.Printf("  Run them later with: gt rig provision %s --only %s\n", name, strings.Join(skipped, ","))
+	}
+
 	fmt.Printf("\nNext steps:\n")
 	fmt.Printf("  gt crew add <name> --rig %s   # Create your personal workspace\n", name)
 	fmt.Printf("  cd %s/crew/<name>              # Start working\n", filepath.Join(townRoot, name))
@@ -801,7 +935,10 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
 	// Check for running tmux sessions before removing
-	t := tmux.NewTmux()
+	t, err := multiplexer.ForTown(townRoot)
+	if err != nil {
+		return err
+	}
 	sessions, sessErr := findRigSessions(t, name)
 	if sessErr != nil {
 		if !rigRemoveForce {
@@ -1098,6 +1235,11 @@ func runRigReset(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting current directory: %w", err)
 	}
 
+	// --sessions on its own needs a rig, not a role
+	if rigResetSessions && !rigResetHandoff && !rigResetMail && !rigResetStale {
+		return runResetSessions(cwd, townRoot, rigResetDryRun)
+	}
+
 	// Determine role to reset
 	roleKey := rigResetRole
 	if roleKey == "" {
@@ -1149,6 +1291,13 @@ func runRigReset(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Kill orphaned tmux sessions
+	if rigResetSessions {
+		if err := runResetSessions(cwd, townRoot, rigResetDryRun); err != nil {
+			return fmt.Errorf("resetting sessions: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -1178,14 +1327,13 @@ func runResetStale(bd *beads.Beads, dryRun bool) error {
 			continue // No assignee to check
 		}
 
-		// Parse assignee: rig/name or rig/crew/name
-		sessionName, isPersistent := assigneeToSessionName(issue.Assignee)
-		if sessionName == "" {
+		assignee, err := session.ParseAssignee(issue.Assignee)
+		if err != nil {
 			continue // Couldn't parse assignee
 		}
 
 		// Check if session exists
-		hasSession, err := t.HasSession(sessionName)
+		hasSession, err := t.HasSession(assignee.SessionName())
 		if err != nil {
 			// tmux error, skip this one
 			continue
@@ -1195,8 +1343,8 @@ func runResetStale(bd *beads.Beads, dryRun bool) error {
 			continue // Session exists, not stale
 		}
 
-		// For crew (persistent identities), only reset if explicitly checking sessions
-		if isPersistent {
+		// Persistent identities (crew, witness, ...) resume their work on restart
+		if assignee.IsPersistent() {
 			skippedCount++
 			if dryRun {
 				fmt.Printf("  %s: %s %s\n",
@@ -1247,39 +1395,13 @@ func runResetStale(bd *beads.Beads, dryRun bool) error {
 			fmt.Printf("%s No stale issues to reset\n", style.Success.Render("✓"))
 		}
 		if skippedCount > 0 {
-			fmt.Printf("  Skipped %d persistent (crew) issues\n", skippedCount)
+			fmt.Printf("  Skipped %d issues of persistent agents\n", skippedCount)
 		}
 	}
 
 	return nil
 }
 
-// assigneeToSessionName converts an assignee (rig/name, rig/crew/name, or rig/polecats/name)
-// to tmux session name.
-// Returns the session name and whether this is a persistent identity (crew).
-func assigneeToSessionName(assignee string) (sessionName string, isPersistent bool) {
-	parts := strings.Split(assignee, "/")
-
-	switch len(parts) {
-	case 2:
-		// rig/polecatName -> gt-rig-polecatName
-		return session.PolecatSessionName(session.PrefixFor(parts[0]), parts[1]), false
-	case 3:
-		// rig/crew/name -> gt-rig-crew-name
-		if parts[1] == "crew" {
-			return session.CrewSessionName(session.PrefixFor(parts[0]), parts[2]), true
-		}
-		// rig/polecats/name -> gt-rig-name
-		if parts[1] == "polecats" {
-			return session.PolecatSessionName(session.PrefixFor(parts[0]), parts[2]), false
-		}
-		// Other 3-part formats not recognized
-		return "", false
-	default:
-		return "", false
-	}
-}
-
 // Helper to check if path exists
 func pathExists(path string) bool {
 	_, err := os.Stat(path)
@@ -1400,6 +1522,22 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Check scheduled maintenance window
+		inWindow, windowEnd, err := rigMaintenanceWindow(townRoot, rigName, time.Now())
+		if err != nil {
+			fmt.Printf("%s Rig '%s' has an invalid schedule: %v\n", style.Warning.Render("⚠"), rigName, err)
+		}
+		if inWindow {
+			if !rigStartIgnoreSchedule {
+				fmt.Printf("%s Rig '%s' is in a maintenance window until %s - skipping (use --ignore-schedule to override)\n",
+					style.Warning.Render("⚠"), rigName, windowEnd.Format("2006-01-02 15:04"))
+				continue
+			}
+			if err := overrideRigSchedule(townRoot, rigName, windowEnd); err != nil {
+				fmt.Printf("%s Failed to record schedule override for '%s': %v\n", style.Warning.Render("⚠"), rigName, err)
+			}
+		}
+
 		fmt.Printf("Starting rig %s...\n", style.Bold.Render(rigName))
 
 		var started []string
@@ -1493,6 +1631,11 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("rig '%s' not found", rigName)
 	}
 
+	phases, escalate, err := escalatingShutdownPhases(rigShutdownTimeout, rigShutdownGracefulTimeout, rigShutdownInterruptTimeout)
+	if err != nil {
+		return err
+	}
+
 	// Check all polecats for uncommitted work (unless nuclear)
 	if !rigShutdownNuclear && !checkUncommittedWork(r, rigName, "shutdown", rigShutdownForce) {
 		return fmt.Errorf("refusing to shutdown with uncommitted work")
@@ -1500,11 +1643,17 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Shutting down rig %s...\n", style.Bold.Render(rigName))
 
+	t := tmux.NewTmux()
+	polecatMgr := polecat.NewSessionManager(t, r)
+	refMgr := refinery.NewManager(r)
+	witMgr := witness.NewManager(r)
+	if escalate {
+		return runRigShutdownEscalating(multiplexer.ForTownWith(townRoot, t), rigName, polecatMgr, refMgr, witMgr, phases)
+	}
+
 	var errors []string
 
 	// 1. Stop all polecat sessions
-	t := tmux.NewTmux()
-	polecatMgr := polecat.NewSessionManager(t, r)
 	infos, err := polecatMgr.ListPolecats()
 	if err == nil && len(infos) > 0 {
 		fmt.Printf("  Stopping %d polecat session(s)...\n", len(infos))
@@ -1514,7 +1663,6 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 	}
 
 	// 2. Stop the refinery
-	refMgr := refinery.NewManager(r)
 	if running, _ := refMgr.IsRunning(); running {
 		fmt.Printf("  Stopping refinery...\n")
 		if err := refMgr.Stop(); err != nil {
@@ -1523,7 +1671,6 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 	}
 
 	// 3. Stop the witness
-	witMgr := witness.NewManager(r)
 	if running, _ := witMgr.IsRunning(); running {
 		fmt.Printf("  Stopping witness...\n")
 		if err := witMgr.Stop(); err != nil {
@@ -1611,8 +1758,12 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 	if r.Config != nil && r.Config.Prefix != "" {
 		fmt.Printf("  Beads prefix: %s-\n", r.Config.Prefix)
 	}
+	printRigUpstreamStatus(r.Path)
 	fmt.Println()
 
+	// Beads summary
+	printRigBeadsSummary(townRoot, rigName)
+
 	// Witness status
 	fmt.Printf("%s\n", style.Bold.Render("Witness"))
 	witMgr := witness.NewManager(r)
@@ -1638,6 +1789,9 @@ func runRigStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("  %s stopped\n", style.Dim.Render("○"))
 	}
+	if pause := refinery.QueuePaused(townRoot, rigName); pause != nil {
+		fmt.Printf("  Merge queue: %s\n", style.Warning.Render(formatQueuePause(pause)))
+	}
 	fmt.Println()
 
 	// Polecats
@@ -2043,8 +2197,8 @@ func syncRigHooks(townRoot, rigName string) error {
 // findRigSessions returns all tmux sessions belonging to the given rig.
 // All rig sessions share the "<rigPrefix>-" prefix, so this catches witness,
 // refinery, polecat, and crew sessions in one pass.
-func findRigSessions(t *tmux.Tmux, rigName string) ([]string, error) {
-	prefix := session.PrefixFor(rigName) + "-"
+func findRigSessions(t multiplexer.Multiplexer, rigName string) ([]string, error) {
+	prefix := session.RigSessionPrefix(session.PrefixFor(rigName))
 	all, err := t.ListSessions()
 	if err != nil {
 		return nil, fmt.Errorf("listing tmux sessions: %w", err)
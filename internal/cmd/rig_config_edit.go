@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var rigConfigEditFile string
+
+var rigConfigEditCmd = &cobra.Command{
+	Use:         "edit [rig]",
+	Annotations: auditAnnotations(""),
+	Short:       "Edit a config file in $EDITOR, saving it only if valid",
+	Long: `Open a config file in $EDITOR (default vi) and save it only if gt can
+load it.
+
+The file is edited as a copy. When the editor exits, the copy is checked
+with the same validation gt applies when it reads the file; if it fails,
+the error is shown and you can edit again, or stop with the file
+untouched. Valid changes are shown as a diff and saved after confirming.
+
+--file picks the file, named like its schema (see gt config schema):
+
+  rig-settings   <rig>/settings/config.json (default; needs a rig)
+  town-settings  settings/config.json
+  town           mayor/town.json
+  messaging      config/messaging.json
+  escalation     settings/escalation.json
+  daemon         mayor/daemon.json
+
+Files in YAML or TOML are edited in their own format.
+
+Examples:
+  gt rig config edit gastown
+  gt rig config edit --file messaging
+  EDITOR="code --wait" gt rig config edit --file town-settings`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRigConfigEdit,
+}
+
+func init() {
+	rigConfigEditCmd.Flags().StringVar(&rigConfigEditFile, "file", config.SchemaRigSettings, "Config file to edit, by schema name")
+	rigConfigCmd.AddCommand(rigConfigEditCmd)
+}
+
+// editableConfig is a config file gt rig config edit can open.
+type editableConfig struct {
+	rig  bool // path is under a rig rather than the town
+	path func(root string) string
+
+	// load reads the file at path the way gt does, returning its
+	// validation error.
+	load func(path string) error
+}
+
+// editableConfigs are the files gt rig config edit opens, by schema name.
+var editableConfigs = map[string]editableConfig{
+	config.SchemaRigSettings: {rig: true, path: config.RigSettingsPath, load: func(p string) error {
+		_, err := config.LoadRigSettings(p)
+		return err
+	}},
+	config.SchemaTownSettings: {path: config.TownSettingsPath, load: func(p string) error {
+		_, err := config.LoadTownSettings(p)
+		return err
+	}},
+	config.SchemaTown: {path: constants.MayorTownPath, load: func(p string) error {
+		_, err := config.LoadTownConfig(p)
+		return err
+	}},
+	config.SchemaMessaging: {path: config.MessagingConfigPath, load: func(p string) error {
+		_, err := config.LoadMessagingConfig(p)
+		return err
+	}},
+	config.SchemaEscalation: {path: config.EscalationConfigPath, load: func(p string) error {
+		_, err := config.LoadEscalationConfig(p)
+		return err
+	}},
+	config.SchemaDaemon: {path: config.DaemonPatrolConfigPath, load: func(p string) error {
+		_, err := config.LoadDaemonPatrolConfig(p)
+		return err
+	}},
+}
+
+func runRigConfigEdit(cmd *cobra.Command, args []string) error {
+	target, ok := editableConfigs[rigConfigEditFile]
+	if !ok {
+		names := make([]string, 0, len(editableConfigs))
+		for name := range editableConfigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown config file %q (valid: %s)", rigConfigEditFile, strings.Join(names, ", "))
+	}
+
+	var root string
+	if target.rig {
+		if len(args) == 0 {
+			return fmt.Errorf("%s belongs to a rig: gt rig config edit <rig>", rigConfigEditFile)
+		}
+		_, r, err := getRig(args[0])
+		if err != nil {
+			return err
+		}
+		root = r.Path
+	} else {
+		if len(args) > 0 {
+			return fmt.Errorf("%s is a town file; drop the rig argument", rigConfigEditFile)
+		}
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		root = townRoot
+	}
+
+	path, _ := config.ResolveConfigPath(target.path(root))
+	return editConfigFile(path, target.load)
+}
+
+// editConfigFile edits a copy of path until load accepts it or the user
+// gives up, then shows the diff and saves it. Invalid content is never
+// written to path; if the user gives up, the copy is kept and named so
+// the edits aren't lost.
+func editConfigFile(path string, load func(string) error) error {
+	original, err := os.ReadFile(path) //nolint:gosec // G304: path is one of the town's config files
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	existed := err == nil
+	start := original
+	if !existed {
+		start = []byte("{\n}\n")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gt-config-edit-")
+	if err != nil {
+		return err
+	}
+	keepTmp := false
+	defer func() {
+		if !keepTmp {
+			_ = os.RemoveAll(tmpDir)
+		}
+	}()
+	// Same name, so the loader and the editor see the same format.
+	tmp := filepath.Join(tmpDir, filepath.Base(path))
+	if err := os.WriteFile(tmp, start, 0600); err != nil {
+		return err
+	}
+
+	var edited []byte
+	for {
+		if err := runEditor(tmp); err != nil {
+			return err
+		}
+		edited, err = os.ReadFile(tmp) //nolint:gosec // G304: our own temp file
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, start) {
+			fmt.Printf("%s\n", style.Dim.Render("No changes"))
+			return nil
+		}
+		loadErr := load(tmp)
+		if loadErr == nil {
+			break
+		}
+		fmt.Printf("%s %s is invalid: %v\n", style.Error.Render("✗"), filepath.Base(path), loadErr)
+		again, err := confirm(confirmPrompt{ID: PromptConfigEditRetry, Question: "Edit again?"})
+		if err != nil || !again {
+			keepTmp = true
+			fmt.Printf("%s not changed; your edits are in %s\n", path, tmp)
+			if err != nil {
+				return err
+			}
+			return errors.New("invalid config not saved")
+		}
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(original)),
+		B:        difflib.SplitLines(string(edited)),
+		FromFile: path,
+		ToFile:   path,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	printConfigDiff(diff)
+
+	save, err := confirm(confirmPrompt{ID: PromptConfigEditSave, Question: "Save " + path + "?", Safe: true})
+	if err != nil {
+		return err
+	}
+	if !save {
+		fmt.Printf("%s\n", style.Dim.Render("Not saved"))
+		return nil
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := util.AtomicWriteFile(path, edited, perm); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	fmt.Printf("%s Saved %s\n", style.Success.Render("✓"), path)
+	return nil
+}
+
+// runEditor opens path in $EDITOR, which may carry arguments
+// (e.g. "code --wait").
+func runEditor(path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	editorCmd := exec.Command(editor[0], append(editor[1:], path)...) //nolint:gosec // G204: the user's own editor
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("running editor: %w", err)
+	}
+	return nil
+}
+
+// printConfigDiff prints a unified diff with added and removed lines
+// colored.
+func printConfigDiff(diff string) {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "+++"), strings.HasPrefix(text, "---"):
+			text = style.Bold.Render(text)
+		case strings.HasPrefix(text, "+"):
+			text = style.Success.Render(text)
+		case strings.HasPrefix(text, "-"):
+			text = style.Error.Render(text)
+		case strings.HasPrefix(text, "@@"):
+			text = style.Dim.Render(text)
+		}
+		fmt.Println(text)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// stubEditor points $EDITOR at a script that replaces the edited file
+// with content.
+func stubEditor(t *testing.T, content string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("editor stub is a shell script")
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "content")
+	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncp '"+src+"' \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", script)
+}
+
+func loadRigSettings(path string) error {
+	_, err := config.LoadRigSettings(path)
+	return err
+}
+
+func TestEditConfigFile_SavesValidEdit(t *testing.T) {
+	stubPromptMode(t, false, false)
+	readYesNo = func(q string) bool { return strings.HasPrefix(q, "Save ") }
+
+	path := filepath.Join(t.TempDir(), "settings", "config.json")
+	edited := "{\n  \"type\": \"rig-settings\",\n  \"version\": 1\n}\n"
+	stubEditor(t, edited)
+
+	if err := editConfigFile(path, loadRigSettings); err != nil {
+		t.Fatalf("editConfigFile: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != edited {
+		t.Errorf("saved %q, %v; want %q", got, err, edited)
+	}
+}
+
+func TestEditConfigFile_RefusesInvalidEdit(t *testing.T) {
+	stubPromptMode(t, false, false)
+	var asked []string
+	readYesNo = func(q string) bool {
+		asked = append(asked, q)
+		return false
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := "{\n  \"type\": \"rig-settings\"\n}\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stubEditor(t, "{\n  \"type\": \"rig-settings\",\n")
+	t.Setenv("TMPDIR", t.TempDir()) // the rejected copy is kept
+
+	if err := editConfigFile(path, loadRigSettings); err == nil {
+		t.Fatal("editConfigFile saved invalid JSON")
+	}
+	if len(asked) != 1 || asked[0] != "Edit again?" {
+		t.Errorf("prompts = %v, want one retry prompt", asked)
+	}
+	if got, _ := os.ReadFile(path); string(got) != original {
+		t.Errorf("file changed to %q", got)
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigGCAll       bool
+	rigGCDryRun    bool
+	rigGCJSON      bool
+	rigGCNoRemote  bool
+	rigGCRetention string
+)
+
+var rigGCCmd = &cobra.Command{
+	Use:         "gc [rig...]",
+	Annotations: auditAnnotations(""),
+	Short:       "Clean up merged branches, dead worktrees, and old logs",
+	Long: `Garbage-collect what a long-running rig accumulates.
+
+  - Branches: polecat branches merged to the default branch are deleted
+    locally and on the remote (fork rigs: on the fork). Local polecat
+    branches whose remote branch is gone are deleted too, but only if
+    git branch -d accepts them. Crew and other branches are never touched.
+  - Worktrees: registrations for worktrees whose directory is gone are
+    pruned, and polecat worktree directories whose git metadata is gone
+    (left by crashed polecats) are removed. Polecats with a running
+    session are skipped.
+  - Logs: headless session logs and agent transcripts for the rig older
+    than --retention are deleted. Logs of live sessions are kept.
+
+Reports what was removed and how much disk space was reclaimed.
+
+Examples:
+  gt rig gc gastown --dry-run
+  gt rig gc gastown --retention 7d
+  gt rig gc --all --no-remote`,
+	RunE: runRigGC,
+}
+
+func init() {
+	rigGCCmd.Flags().BoolVar(&rigGCAll, "all", false, "Clean up every rig in the town")
+	rigGCCmd.Flags().BoolVar(&rigGCDryRun, "dry-run", false, "Report what would be removed without removing it")
+	rigGCCmd.Flags().BoolVar(&rigGCJSON, "json", false, "Output as JSON")
+	rigGCCmd.Flags().BoolVar(&rigGCNoRemote, "no-remote", false, "Don't delete merged branches on the remote")
+	rigGCCmd.Flags().StringVar(&rigGCRetention, "retention", "30d", "Keep logs and transcripts newer than this (e.g. 7d, 48h; 0 keeps all)")
+
+	rigCmd.AddCommand(rigGCCmd)
+}
+
+func runRigGC(cmd *cobra.Command, args []string) error {
+	if rigGCAll == (len(args) > 0) {
+		return fmt.Errorf("name one or more rigs, or pass --all")
+	}
+	retention, err := parseDuration(rigGCRetention)
+	if err != nil {
+		return fmt.Errorf("invalid --retention: %w", err)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	names := args
+	if rigGCAll {
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	var rigPaths []string
+	for name := range rigsConfig.Rigs {
+		rigPaths = append(rigPaths, filepath.Join(townRoot, name))
+	}
+
+	liveLogs := make(map[string]bool)
+	if live, err := multiplexer.NewHeadless(townRoot).ListSessions(); err == nil {
+		for _, name := range live {
+			liveLogs[name+".log"] = true
+		}
+	}
+
+	var results []*rig.GCResult
+	var failed int
+	for _, name := range names {
+		if _, ok := rigsConfig.Rigs[name]; !ok {
+			return fmt.Errorf("rig '%s' not found", name)
+		}
+		rigPath := filepath.Join(townRoot, name)
+		opts := rig.GCOptions{
+			Retention:   retention,
+			LogPatterns: gcLogPatterns(townRoot, name, rigPath, rigPaths),
+			KeepLog:     func(path string) bool { return liveLogs[filepath.Base(path)] },
+			NoRemote:    rigGCNoRemote,
+			DryRun:      rigGCDryRun,
+		}
+		if sessMgr, _, err := getSessionManager(name); err == nil {
+			opts.PolecatActive = func(polecat string) bool {
+				running, _ := sessMgr.IsRunning(polecat)
+				return running
+			}
+		}
+
+		result, err := rig.GarbageCollect(rigPath, opts)
+		if err != nil {
+			failed++
+			style.PrintWarning("%s: %v", name, err)
+			continue
+		}
+		results = append(results, result)
+		if !rigGCJSON {
+			printRigGC(result)
+		}
+	}
+
+	if rigGCJSON {
+		if results == nil {
+			results = []*rig.GCResult{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rig(s) could not be cleaned up", failed, len(names))
+	}
+	return nil
+}
+
+// gcLogPatterns returns globs for the rig's headless session logs and agent
+// transcripts. Transcript directories are named after the agent's working
+// directory, so those of rigs whose path extends this one (gastown vs
+// gastown-docs) are excluded by name.
+func gcLogPatterns(townRoot, rigName, rigPath string, rigPaths []string) []string {
+	patterns := []string{
+		filepath.Join(townRoot, "daemon", "headless", session.PolecatSessionName(session.PrefixFor(rigName), "*")+".log"),
+	}
+
+	projectDir, err := getClaudeProjectDir(rigPath)
+	if err != nil {
+		return patterns
+	}
+	var others []string
+	for _, p := range rigPaths {
+		if other, err := getClaudeProjectDir(p); err == nil && other != projectDir && strings.HasPrefix(other, projectDir) {
+			others = append(others, other)
+		}
+	}
+	dirs, _ := filepath.Glob(projectDir + "*")
+	for _, dir := range dirs {
+		if dir != projectDir && !strings.HasPrefix(dir, projectDir+"-") {
+			continue
+		}
+		owned := true
+		for _, other := range others {
+			if dir == other || strings.HasPrefix(dir, other+"-") {
+				owned = false
+				break
+			}
+		}
+		if owned {
+			patterns = append(patterns, filepath.Join(dir, "*.jsonl"))
+		}
+	}
+	return patterns
+}
+
+func printRigGC(r *rig.GCResult) {
+	verb := "Removed"
+	if r.DryRun {
+		verb = "Would remove"
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(r.Rig), style.Dim.Render(fmt.Sprintf("(%s %s)", strings.ToLower(verb), formatBytes(r.ReclaimedBytes))))
+
+	if len(r.Branches) == 0 && len(r.Worktrees) == 0 && len(r.Logs) == 0 {
+		fmt.Printf("  %s nothing to clean up\n", style.Success.Render("✓"))
+	}
+	for _, b := range r.Branches {
+		where := "local"
+		if b.Remote {
+			where = "remote"
+		}
+		fmt.Printf("  %s %s branch %s %s\n", style.Success.Render("✓"), where, b.Name, style.Dim.Render("("+b.Reason+")"))
+	}
+	for _, w := range r.Worktrees {
+		fmt.Printf("  %s worktree %s %s\n", style.Success.Render("✓"), w.Path, style.Dim.Render("("+w.Reason+")"))
+	}
+	if len(r.Logs) > 0 {
+		var size int64
+		for _, l := range r.Logs {
+			size += l.Bytes
+		}
+		fmt.Printf("  %s %d log/transcript file(s) older than %s %s\n",
+			style.Success.Render("✓"), len(r.Logs), rigGCRetention, style.Dim.Render("("+formatBytes(size)+")"))
+	}
+	for _, w := range r.Warnings {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), w)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGCLogPatterns_ExcludesOtherRigs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	townRoot := filepath.Join(t.TempDir(), "town")
+	gastown := filepath.Join(townRoot, "gastown")
+	docs := filepath.Join(townRoot, "gastown-docs")
+
+	var mine []string
+	for _, workDir := range []string{
+		gastown,
+		filepath.Join(gastown, "polecats", "toast", "gastown"),
+		filepath.Join(gastown, "crew", "joe"),
+		filepath.Join(docs, "crew", "max"),
+		filepath.Join(townRoot, "mayor"),
+	} {
+		dir, err := getClaudeProjectDir(workDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(workDir, gastown+string(filepath.Separator)) || workDir == gastown {
+			mine = append(mine, filepath.Join(dir, "*.jsonl"))
+		}
+	}
+
+	patterns := gcLogPatterns(townRoot, "gastown", gastown, []string{gastown, docs})
+	if !strings.HasPrefix(patterns[0], filepath.Join(townRoot, "daemon", "headless")+string(filepath.Separator)) {
+		t.Errorf("first pattern = %s, want headless logs", patterns[0])
+	}
+	transcripts := patterns[1:]
+	sort.Strings(transcripts)
+	sort.Strings(mine)
+	got := strings.Join(transcripts, "\n")
+	want := strings.Join(mine, "\n")
+	if got != want {
+		t.Errorf("transcript patterns:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -881,6 +883,15 @@ func TestRigAddRejectsInvalidNames(t *testing.T) {
 func TestRigAddCreatesAgentBeads(t *testing.T) {
 	bdLogPath := mockBdCommand(t)
 	townRoot := setupTestTown(t)
+
+	// The mock bd stands in for a server-backed bd; give the Dolt server
+	// reachability check something to dial so bd writes aren't refused.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(l.Addr().(*net.TCPAddr).Port))
 	gitURL := createTestGitRepo(t, "agentbeadtest")
 
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
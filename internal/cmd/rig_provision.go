@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/plugin"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigProvisionOnly   []string
+	rigProvisionDryRun bool
+)
+
+var rigProvisionCmd = &cobra.Command{
+	Use:         "provision <name>",
+	Annotations: auditAnnotations(""),
+	Short:       "Resume provisioning a rig that gt rig add left part way",
+	Long: `Run the provisioning stages a rig is still missing.
+
+gt rig add records each stage (config, clone, beads, hooks, patrols) in
+<rig>/.runtime/provision.json. When a stage fails the rig directory is kept
+and the rig is not registered; provision picks up from the first stage that
+is pending or failed. Stages skipped with --skip or --no-agents are left
+alone unless named with --only, which runs exactly the given stages, even
+ones already done. The config stage can't be rerun.
+
+The rig is registered in mayor/rigs.json once no stage is left pending or
+failed.
+
+Examples:
+  gt rig provision myproject                 # Resume after a failed gt rig add
+  gt rig provision myproject --only hooks,patrols
+  gt rig provision myproject --dry-run       # Show the checklist`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigProvision,
+}
+
+func init() {
+	rigProvisionCmd.Flags().StringSliceVar(&rigProvisionOnly, "only", nil, "Run exactly these stages (clone, beads, hooks, patrols)")
+	rigProvisionCmd.Flags().BoolVar(&rigProvisionDryRun, "dry-run", false, "Show the checklist and the stages that would run")
+
+	rigCmd.AddCommand(rigProvisionCmd)
+}
+
+func runRigProvision(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var stages []string
+	if len(rigProvisionOnly) > 0 {
+		if stages, err = rig.SelectStages(rigProvisionOnly, nil); err != nil {
+			return err
+		}
+	}
+
+	rigPath := filepath.Join(townRoot, name)
+	if rigProvisionDryRun {
+		checklist, err := rig.LoadProvisionChecklist(rigPath)
+		if err != nil {
+			return fmt.Errorf("reading provisioning checklist: %w", err)
+		}
+		printProvisionChecklist(checklist)
+		if stages == nil {
+			stages = checklist.Remaining()
+		}
+		if len(stages) == 0 {
+			fmt.Printf("\n%s Nothing to provision\n", style.Success.Render("✓"))
+		} else {
+			fmt.Printf("\nWould run: %s\n", strings.Join(stages, ", "))
+		}
+		return nil
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{
+			Version: 1,
+			Rigs:    make(map[string]config.RigEntry),
+		}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	fmt.Printf("Provisioning rig %s...\n", style.Bold.Render(name))
+	provisioned, err := mgr.Provision(name, stages, rigStageFollowUp(townRoot, name))
+	if err != nil {
+		return fmt.Errorf("provisioning rig: %w", err)
+	}
+	if provisioned == nil {
+		checklist, err := rig.LoadProvisionChecklist(rigPath)
+		if err != nil {
+			return fmt.Errorf("reading provisioning checklist: %w", err)
+		}
+		fmt.Printf("\n%s Stages still to run: %s\n", style.Warning.Render("!"), strings.Join(checklist.Remaining(), ", "))
+		return nil
+	}
+
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	fmt.Printf("\n%s Rig %s is provisioned\n", style.Success.Render("✓"), name)
+	return nil
+}
+
+// rigStageFollowUp returns the gt-level work that follows each provisioning
+// stage of rig name. None of it is fatal, so it never fails a stage.
+func rigStageFollowUp(townRoot, name string) func(stage string) error {
+	rigPath := filepath.Join(townRoot, name)
+	return func(stage string) error {
+		switch stage {
+		case rig.StageBeads:
+			createRigIdentityBead(townRoot, name)
+		case rig.StageHooks:
+			// Sync hooks for the new rig's targets
+			if err := syncRigHooks(townRoot, name); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks for new rig: %v\n", err)
+			}
+			// Run plugin rig-add hooks in the new rig
+			runPluginHooks(townRoot, name, plugin.HookRigAdd, rigPath, "GT_RIG="+name)
+		case rig.StagePatrols:
+			// Add new rig to daemon.json patrol config (witness + refinery rigs arrays)
+			if err := config.AddRigToDaemonPatrols(townRoot, name); err != nil {
+				// Non-fatal: daemon will still work, just won't auto-manage this rig
+				fmt.Printf("  %s Could not update daemon.json patrols: %v\n", style.Warning.Render("!"), err)
+			}
+		}
+		return nil
+	}
+}
+
+// createRigIdentityBead creates the rig's identity bead in its beads database.
+func createRigIdentityBead(townRoot, name string) {
+	rigPath := filepath.Join(townRoot, name)
+	rigCfg, err := rig.LoadRigConfig(rigPath)
+	if err != nil || rigCfg.Beads == nil || rigCfg.Beads.Prefix == "" {
+		return
+	}
+	prefix := rigCfg.Beads.Prefix
+
+	// Tracked beads live in the mayor clone; the rig-level .beads redirects there.
+	beadsWorkDir := rigPath
+	if _, err := os.Stat(filepath.Join(rigPath, "mayor", "rig", ".beads")); err == nil {
+		beadsWorkDir = filepath.Join(rigPath, "mayor", "rig")
+	}
+
+	bd := beads.New(beadsWorkDir)
+	fields := &beads.RigFields{
+		Repo:   rigCfg.GitURL,
+		Prefix: prefix,
+		State:  beads.RigStateActive,
+	}
+	if _, err := bd.CreateRigBead(name, fields); err != nil {
+		// Non-fatal: rig is functional without the identity bead
+		fmt.Printf("  %s Could not create rig identity bead: %v\n", style.Warning.Render("!"), err)
+	} else {
+		fmt.Printf("  Created rig identity bead: %s\n", beads.RigBeadIDWithPrefix(prefix, name))
+	}
+}
+
+// skippedStages returns the stages the rig's checklist records as skipped.
+func skippedStages(rigPath string) []string {
+	checklist, err := rig.LoadProvisionChecklist(rigPath)
+	if err != nil {
+		return nil
+	}
+	var skipped []string
+	for _, s := range checklist.Stages {
+		if s.Status == rig.StageSkipped {
+			skipped = append(skipped, s.Name)
+		}
+	}
+	return skipped
+}
+
+func printProvisionChecklist(checklist *rig.ProvisionChecklist) {
+	for _, s := range checklist.Stages {
+		var mark string
+		switch s.Status {
+		case rig.StageDone:
+			mark = style.Success.Render("✓")
+		case rig.StageFailed:
+			mark = style.Error.Render("✗")
+		case rig.StageSkipped:
+			mark = style.Dim.Render("-")
+		default:
+			mark = style.Dim.Render("○")
+		}
+		fmt.Printf("  %s %-8s %s\n", mark, s.Name, s.Status)
+		if s.Error != "" {
+			fmt.Printf("      %s\n", style.Dim.Render(s.Error))
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -96,6 +97,7 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	addArgs := []string{"rig", "add", rigName, gitURL}
 	addCmd := exec.Command("gt", addArgs...)
 	addCmd.Dir = townRoot
+	addCmd.Env = telemetry.WithTraceparent(nil)
 	addCmd.Stdout = os.Stdout
 	addCmd.Stderr = os.Stderr
 	if err := addCmd.Run(); err != nil {
@@ -119,6 +121,7 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	crewArgs := []string{"crew", "add", user, "--rig", rigName}
 	crewCmd := exec.Command("gt", crewArgs...)
 	crewCmd.Dir = filepath.Join(townRoot, rigName)
+	crewCmd.Env = telemetry.WithTraceparent(nil)
 	crewCmd.Stdout = os.Stdout
 	crewCmd.Stderr = os.Stderr
 	if err := crewCmd.Run(); err != nil {
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigRefreshBranchAll    bool
+	rigRefreshBranchDryRun bool
+	rigRefreshBranchJSON   bool
+)
+
+var rigRefreshBranchCmd = &cobra.Command{
+	Use:   "refresh-branch [rig...]",
+	Short: "Re-detect a rig's default branch from the remote",
+	Long: `Re-detect a rig's default branch from the remote HEAD.
+
+A rig's default_branch is captured when it is added. If upstream later
+renames it (master -> main), the merge queue and new polecats keep
+targeting the old branch. This command asks the remote for its current
+default branch and, if it changed:
+  - Fetches the shared bare repo and moves its origin/HEAD
+  - Updates default_branch in <rig>/config.json, which the merge queue
+    and new polecats follow
+
+Clones (mayor/rig, refinery/rig, crew workspaces) are never switched,
+since they may hold work in progress. Those still on the old branch are
+listed with the commands to update them.
+
+The daemon can do this periodically: enable the branch_refresh patrol in
+mayor/daemon.json.
+
+Examples:
+  gt rig refresh-branch gastown
+  gt rig refresh-branch --all --dry-run
+  gt rig refresh-branch gastown --json`,
+	RunE: runRigRefreshBranch,
+}
+
+func init() {
+	rigRefreshBranchCmd.Flags().BoolVar(&rigRefreshBranchAll, "all", false, "Refresh every rig in the town")
+	rigRefreshBranchCmd.Flags().BoolVar(&rigRefreshBranchDryRun, "dry-run", false, "Report the detected branch without changing anything")
+	rigRefreshBranchCmd.Flags().BoolVar(&rigRefreshBranchJSON, "json", false, "Output as JSON")
+
+	rigCmd.AddCommand(rigRefreshBranchCmd)
+}
+
+func runRigRefreshBranch(cmd *cobra.Command, args []string) error {
+	if rigRefreshBranchAll == (len(args) > 0) {
+		return fmt.Errorf("name one or more rigs, or pass --all")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	names := args
+	if rigRefreshBranchAll {
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var results []*rig.BranchRefresh
+	var failed int
+	for _, name := range names {
+		if _, ok := rigsConfig.Rigs[name]; !ok {
+			return fmt.Errorf("rig '%s' not found", name)
+		}
+		rigPath := filepath.Join(townRoot, name)
+		result, err := rig.RefreshDefaultBranch(rigPath, rigRefreshBranchDryRun)
+		if err != nil {
+			failed++
+			style.PrintWarning("%s: %v", name, err)
+			continue
+		}
+		results = append(results, result)
+		if !rigRefreshBranchJSON {
+			printBranchRefresh(rigPath, result)
+		}
+	}
+
+	if rigRefreshBranchJSON {
+		if results == nil {
+			results = []*rig.BranchRefresh{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rig(s) could not be refreshed", failed, len(names))
+	}
+	return nil
+}
+
+func printBranchRefresh(rigPath string, r *rig.BranchRefresh) {
+	if !r.Changed {
+		fmt.Printf("%s %s: default branch %s (unchanged)\n", style.Success.Render("✓"), style.Bold.Render(r.Rig), r.Current)
+		return
+	}
+	if rigRefreshBranchDryRun {
+		fmt.Printf("%s %s: default branch %s → %s (dry run, not changed)\n",
+			style.Warning.Render("!"), style.Bold.Render(r.Rig), r.Previous, r.Current)
+	} else {
+		fmt.Printf("%s %s: default branch %s → %s\n",
+			style.Success.Render("✓"), style.Bold.Render(r.Rig), r.Previous, r.Current)
+	}
+
+	stale := r.NeedUpdate()
+	if len(stale) == 0 {
+		return
+	}
+	fmt.Printf("  Clones still on %s:\n", r.Previous)
+	for _, c := range stale {
+		dir := filepath.Join(rigPath, c.Path)
+		fmt.Printf("    %-14s %s\n", c.Path,
+			style.Dim.Render(fmt.Sprintf("git -C %s fetch origin && git -C %s remote set-head origin %s && git -C %s checkout %s",
+				dir, dir, r.Current, dir, r.Current)))
+	}
+}
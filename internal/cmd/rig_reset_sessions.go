@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	rigResetSessions bool
+	rigResetRig      string
+)
+
+func init() {
+	rigResetCmd.Flags().BoolVar(&rigResetSessions, "sessions", false, "Kill orphaned tmux sessions (rig prefix, no registered agent)")
+	rigResetCmd.Flags().StringVar(&rigResetRig, "rig", "", "Rig for --sessions (default: auto-detect from cwd)")
+}
+
+// expectedRigSessions returns the tmux session names that belong to a
+// registered agent of r: witness, refinery, and one per polecat and crew
+// worker found on disk.
+func expectedRigSessions(r *rig.Rig) map[string]bool {
+	prefix := session.PrefixFor(r.Name)
+	expected := map[string]bool{
+		session.WitnessSessionName(prefix):  true,
+		session.RefinerySessionName(prefix): true,
+	}
+	for _, name := range r.Polecats {
+		expected[session.PolecatSessionName(prefix, name)] = true
+	}
+	for _, name := range r.Crew {
+		expected[session.CrewSessionName(prefix, name)] = true
+	}
+	return expected
+}
+
+// orphanedRigSessions returns the sessions in running that are not in
+// expected, sorted.
+func orphanedRigSessions(running []string, expected map[string]bool) []string {
+	var orphans []string
+	for _, name := range running {
+		if !expected[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// runResetSessions kills tmux sessions carrying the rig's prefix that no
+// registered polecat, crew worker, witness, or refinery accounts for.
+// These are typically left behind by crashes and block `gt rig remove`.
+func runResetSessions(cwd, townRoot string, dryRun bool) error {
+	rigName := rigResetRig
+	if rigName == "" {
+		roleInfo, err := GetRoleWithContext(cwd, townRoot)
+		if err != nil {
+			return fmt.Errorf("detecting rig: %w", err)
+		}
+		if roleInfo.Rig == "" {
+			return fmt.Errorf("could not detect rig; use --rig to specify")
+		}
+		rigName = roleInfo.Rig
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	t, err := multiplexer.ForTown(townRoot)
+	if err != nil {
+		return err
+	}
+	running, err := findRigSessions(t, rigName)
+	if err != nil {
+		return err
+	}
+
+	orphans := orphanedRigSessions(running, expectedRigSessions(r))
+	if len(orphans) == 0 {
+		fmt.Printf("%s No orphaned sessions for rig %s\n", style.Success.Render("✓"), rigName)
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("Would kill %d orphaned session(s) for rig %s:\n", len(orphans), rigName)
+		for _, s := range orphans {
+			fmt.Printf("  %s\n", s)
+		}
+		return nil
+	}
+
+	fmt.Printf("Killing %d orphaned session(s) for rig %s...\n", len(orphans), rigName)
+	var failed int
+	for _, s := range orphans {
+		if err := t.KillSessionWithProcesses(s); err != nil {
+			fmt.Fprintf(os.Stderr, "  %s Failed to kill session %s: %v\n", style.Warning.Render("!"), s, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  Killed %s\n", s)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to kill %d of %d orphaned session(s)", failed, len(orphans))
+	}
+	fmt.Printf("%s Killed %d orphaned session(s)\n", style.Success.Render("✓"), len(orphans))
+	return nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestOrphanedRigSessions(t *testing.T) {
+	r := &rig.Rig{
+		Name:     "unregistered-test-rig",
+		Polecats: []string{"furiosa"},
+		Crew:     []string{"max"},
+	}
+	running := []string{
+		"gt-witness",
+		"gt-refinery",
+		"gt-furiosa",
+		"gt-crew-max",
+		"gt-nux",
+		"gt-crew-gone",
+	}
+
+	got := orphanedRigSessions(running, expectedRigSessions(r))
+	want := []string{"gt-crew-gone", "gt-nux"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orphanedRigSessions() = %v, want %v", got, want)
+	}
+}
+
+func TestOrphanedRigSessions_NoneRunning(t *testing.T) {
+	r := &rig.Rig{Name: "unregistered-test-rig"}
+	if got := orphanedRigSessions(nil, expectedRigSessions(r)); len(got) != 0 {
+		t.Errorf("orphanedRigSessions(nil) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+var rigStartIgnoreSchedule bool
+
+func init() {
+	rigStartCmd.Flags().BoolVar(&rigStartIgnoreSchedule, "ignore-schedule", false, "Start even if the rig is in a scheduled maintenance window")
+}
+
+// rigMaintenanceWindow reports whether a rig is currently inside a scheduled
+// maintenance window, and when that window ends. Overrides are not considered.
+func rigMaintenanceWindow(townRoot, rigName string, now time.Time) (active bool, until time.Time, err error) {
+	rigPath := filepath.Join(townRoot, rigName)
+	settings, loadErr := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if loadErr != nil || settings.Schedule == nil {
+		return false, time.Time{}, nil
+	}
+
+	active, err = settings.Schedule.InMaintenanceWindow(now)
+	if err != nil || !active {
+		return false, time.Time{}, err
+	}
+	until, err = settings.Schedule.MaintenanceWindowEnd(now)
+	return active, until, err
+}
+
+// overrideRigSchedule records that the current maintenance window should be
+// ignored until it ends, so the daemon does not drain the rig again.
+func overrideRigSchedule(townRoot, rigName string, until time.Time) error {
+	wispCfg := wisp.NewConfig(townRoot, rigName)
+	return wispCfg.Set(config.ScheduleOverrideKey, until.Format(time.RFC3339))
+}
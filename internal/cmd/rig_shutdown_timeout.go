@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	rigShutdownTimeout          time.Duration
+	rigShutdownGracefulTimeout  time.Duration
+	rigShutdownInterruptTimeout time.Duration
+)
+
+func init() {
+	rigShutdownCmd.Flags().DurationVar(&rigShutdownTimeout, "timeout", 0,
+		"Bound shutdown time: ask agents to stop, interrupt them, then kill what's left")
+	rigShutdownCmd.Flags().DurationVar(&rigShutdownGracefulTimeout, "graceful-timeout", 0,
+		"How long agents get to exit after the stop request (default: 2/3 of --timeout)")
+	rigShutdownCmd.Flags().DurationVar(&rigShutdownInterruptTimeout, "interrupt-timeout", 0,
+		"How long agents get to exit after Ctrl-C (default: rest of --timeout)")
+	rigShutdownCmd.MarkFlagsMutuallyExclusive("timeout", "force")
+	rigShutdownCmd.MarkFlagsMutuallyExclusive("graceful-timeout", "force")
+	rigShutdownCmd.MarkFlagsMutuallyExclusive("interrupt-timeout", "force")
+}
+
+// shutdownPhases are the durations of the waits in an escalating shutdown.
+// The final kill phase doesn't wait.
+type shutdownPhases struct {
+	Graceful  time.Duration
+	Interrupt time.Duration
+}
+
+// escalatingShutdownPhases resolves the --timeout flags. ok is false when
+// none were given and shutdown should use the plain stop path.
+func escalatingShutdownPhases(total, graceful, interrupt time.Duration) (phases shutdownPhases, ok bool, err error) {
+	if total < 0 || graceful < 0 || interrupt < 0 {
+		return shutdownPhases{}, false, fmt.Errorf("shutdown timeouts must not be negative")
+	}
+	if total == 0 && graceful == 0 && interrupt == 0 {
+		return shutdownPhases{}, false, nil
+	}
+	if total == 0 {
+		return shutdownPhases{Graceful: graceful, Interrupt: interrupt}, true, nil
+	}
+
+	switch {
+	case graceful == 0 && interrupt == 0:
+		graceful = total * 2 / 3
+		interrupt = total - graceful
+	case graceful == 0:
+		graceful = total - interrupt
+	case interrupt == 0:
+		interrupt = total - graceful
+	}
+	if graceful < 0 || interrupt < 0 || graceful+interrupt > total {
+		return shutdownPhases{}, false, fmt.Errorf("--graceful-timeout (%s) + --interrupt-timeout (%s) exceed --timeout (%s)",
+			graceful, interrupt, total)
+	}
+	return shutdownPhases{Graceful: graceful, Interrupt: interrupt}, true, nil
+}
+
+// shutdownTarget is one agent session stopped by an escalating shutdown.
+type shutdownTarget struct {
+	Name    string       // shown in output, e.g. "polecat Toast"
+	Session string       // tmux session
+	Kill    func() error // the agent manager's forced stop
+}
+
+// How a target's session ended.
+const (
+	shutdownExitedOnRequest   = "exited after stop request"
+	shutdownExitedOnInterrupt = "exited after interrupt"
+	shutdownForceKilled       = "force-killed"
+	shutdownKillFailed        = "kill failed"
+)
+
+// shutdownOutcome records how one target was stopped.
+type shutdownOutcome struct {
+	Target shutdownTarget
+	How    string
+	After  time.Duration // since shutdown began
+	Err    error         // set when How is shutdownKillFailed
+}
+
+// shutdownSessions is the part of the town's multiplexer an escalating
+// shutdown needs.
+type shutdownSessions interface {
+	HasSession(name string) (bool, error)
+	SendKeys(session, keys string) error
+}
+
+// shutdownInterrupter is implemented by multiplexers that can send Ctrl-C
+// (tmux). Elsewhere the interrupt phase is skipped.
+type shutdownInterrupter interface {
+	SendKeysRaw(session, keys string) error
+}
+
+// shutdownPollInterval is how often sessions are checked for exit.
+var shutdownPollInterval = time.Second
+
+// escalatingShutdown stops targets in three phases: a stop request asking
+// agents to save state and exit, Ctrl-C for those still running after
+// phases.Graceful, and a forced kill for those still running after
+// phases.Interrupt. Outcomes are returned in target order.
+func escalatingShutdown(tm shutdownSessions, rigName string, targets []shutdownTarget, phases shutdownPhases) []shutdownOutcome {
+	start := time.Now()
+	outcomes := make([]shutdownOutcome, len(targets))
+	remaining := make(map[int]bool, len(targets))
+	for i, tgt := range targets {
+		outcomes[i].Target = tgt
+		remaining[i] = true
+	}
+
+	// wait polls until every remaining session has exited or d has passed,
+	// recording exits as how.
+	wait := func(d time.Duration, how string) {
+		deadline := time.Now().Add(d)
+		for {
+			for i := range remaining {
+				if alive, err := tm.HasSession(targets[i].Session); err == nil && !alive {
+					outcomes[i].How, outcomes[i].After = how, time.Since(start)
+					delete(remaining, i)
+				}
+			}
+			left := time.Until(deadline)
+			if len(remaining) == 0 || left <= 0 {
+				return
+			}
+			if left > shutdownPollInterval {
+				left = shutdownPollInterval
+			}
+			time.Sleep(left)
+		}
+	}
+
+	fmt.Printf("  Phase 1: Asking %d agent(s) to stop (waiting up to %s)...\n", len(remaining), phases.Graceful)
+	msg := fmt.Sprintf("[SHUTDOWN] Rig %s is shutting down. Save your state and update your handoff bead, then type /exit. "+
+		"You will be interrupted in %s.", rigName, phases.Graceful.Round(time.Second))
+	for i := range targets {
+		_ = tm.SendKeys(targets[i].Session, msg) // best-effort; the later phases don't depend on it
+	}
+	wait(phases.Graceful, shutdownExitedOnRequest)
+
+	if in, ok := tm.(shutdownInterrupter); ok && len(remaining) > 0 {
+		fmt.Printf("  Phase 2: Interrupting %d agent(s) (waiting up to %s)...\n", len(remaining), phases.Interrupt)
+		for i := range remaining {
+			_ = in.SendKeysRaw(targets[i].Session, "C-c")
+		}
+		wait(phases.Interrupt, shutdownExitedOnInterrupt)
+	}
+
+	if len(remaining) > 0 {
+		fmt.Printf("  Phase 3: Killing %d agent(s)...\n", len(remaining))
+		for i := range targets {
+			if !remaining[i] {
+				continue
+			}
+			outcomes[i].How = shutdownForceKilled
+			if err := targets[i].Kill(); err != nil {
+				// The session may have exited between the last poll and now.
+				if alive, herr := tm.HasSession(targets[i].Session); herr != nil || alive {
+					outcomes[i].How, outcomes[i].Err = shutdownKillFailed, err
+				} else {
+					outcomes[i].How = shutdownExitedOnInterrupt
+				}
+			}
+			outcomes[i].After = time.Since(start)
+		}
+	}
+	return outcomes
+}
+
+// printShutdownReport summarizes escalatingShutdown's outcomes, calling
+// out the agents that had to be killed. It returns the failed kills.
+func printShutdownReport(outcomes []shutdownOutcome) []string {
+	var killed, failed []string
+	fmt.Printf("\n  Shutdown report:\n")
+	for _, o := range outcomes {
+		switch o.How {
+		case shutdownForceKilled:
+			killed = append(killed, o.Target.Name)
+			fmt.Printf("    %s %s: %s after %s\n", style.Warning.Render("⚠"), o.Target.Name, o.How, o.After.Round(time.Second))
+		case shutdownKillFailed:
+			failed = append(failed, fmt.Sprintf("%s: %v", o.Target.Name, o.Err))
+			fmt.Printf("    %s %s: %s: %v\n", style.Error.Render("✗"), o.Target.Name, o.How, o.Err)
+		default:
+			fmt.Printf("    %s %s: %s (%s)\n", style.Success.Render("✓"), o.Target.Name, o.How, o.After.Round(time.Second))
+		}
+	}
+	if len(killed) > 0 {
+		fmt.Printf("  Force-killed: %s\n", strings.Join(killed, ", "))
+	}
+	return failed
+}
+
+// runRigShutdownEscalating is rig shutdown with --timeout: every running
+// agent goes through escalatingShutdown.
+func runRigShutdownEscalating(mux multiplexer.Multiplexer, rigName string, polecatMgr *polecat.SessionManager,
+	refMgr *refinery.Manager, witMgr *witness.Manager, phases shutdownPhases) error {
+	var targets []shutdownTarget
+	infos, err := polecatMgr.ListPolecats()
+	if err != nil {
+		return fmt.Errorf("listing polecat sessions: %w", err)
+	}
+	for _, info := range infos {
+		name := info.Polecat
+		targets = append(targets, shutdownTarget{
+			Name:    "polecat " + name,
+			Session: info.SessionID,
+			Kill:    func() error { return polecatMgr.Stop(name, true) },
+		})
+	}
+	if running, _ := mux.HasSession(refMgr.SessionName()); running {
+		targets = append(targets, shutdownTarget{Name: "refinery", Session: refMgr.SessionName(), Kill: refMgr.Stop})
+	}
+	if running, _ := mux.HasSession(witMgr.SessionName()); running {
+		targets = append(targets, shutdownTarget{Name: "witness", Session: witMgr.SessionName(), Kill: witMgr.Stop})
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("%s Rig %s has no running agents\n", style.Success.Render("✓"), rigName)
+		return nil
+	}
+
+	failed := printShutdownReport(escalatingShutdown(mux, rigName, targets, phases))
+	if len(failed) > 0 {
+		fmt.Printf("\n%s Some agents failed to stop:\n", style.Warning.Render("⚠"))
+		for _, e := range failed {
+			fmt.Printf("  - %s\n", e)
+		}
+		return fmt.Errorf("shutdown incomplete")
+	}
+
+	fmt.Printf("%s Rig %s shut down successfully\n", style.Success.Render("✓"), rigName)
+	return nil
+}
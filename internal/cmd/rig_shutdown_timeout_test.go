@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscalatingShutdownPhases(t *testing.T) {
+	tests := []struct {
+		name                       string
+		total, graceful, interrupt time.Duration
+		want                       shutdownPhases
+		wantOK, wantErr            bool
+	}{
+		{name: "unset"},
+		{name: "split", total: 90 * time.Second, want: shutdownPhases{60 * time.Second, 30 * time.Second}, wantOK: true},
+		{name: "graceful given", total: time.Minute, graceful: 50 * time.Second, want: shutdownPhases{50 * time.Second, 10 * time.Second}, wantOK: true},
+		{name: "interrupt given", total: time.Minute, interrupt: 5 * time.Second, want: shutdownPhases{55 * time.Second, 5 * time.Second}, wantOK: true},
+		{name: "phases only", graceful: time.Minute, interrupt: 10 * time.Second, want: shutdownPhases{time.Minute, 10 * time.Second}, wantOK: true},
+		{name: "exceeds total", total: time.Minute, graceful: time.Minute, interrupt: time.Second, wantErr: true},
+		{name: "negative", total: -time.Second, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := escalatingShutdownPhases(tt.total, tt.graceful, tt.interrupt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("got %+v, %v; want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// fakeShutdownSessions simulates agents that exit on the stop request, on
+// Ctrl-C, or not at all.
+type fakeShutdownSessions struct {
+	alive       map[string]bool
+	onRequest   map[string]bool
+	onInterrupt map[string]bool
+}
+
+func (f *fakeShutdownSessions) HasSession(name string) (bool, error) { return f.alive[name], nil }
+
+func (f *fakeShutdownSessions) SendKeys(session, _ string) error {
+	if f.onRequest[session] {
+		f.alive[session] = false
+	}
+	return nil
+}
+
+func (f *fakeShutdownSessions) SendKeysRaw(session, keys string) error {
+	if keys == "C-c" && f.onInterrupt[session] {
+		f.alive[session] = false
+	}
+	return nil
+}
+
+func TestEscalatingShutdown(t *testing.T) {
+	old := shutdownPollInterval
+	shutdownPollInterval = time.Millisecond
+	t.Cleanup(func() { shutdownPollInterval = old })
+
+	tm := &fakeShutdownSessions{
+		alive:       map[string]bool{"polite": true, "stubborn": true, "stuck": true, "broken": true},
+		onRequest:   map[string]bool{"polite": true},
+		onInterrupt: map[string]bool{"stubborn": true},
+	}
+	kill := func(session string) func() error {
+		return func() error { tm.alive[session] = false; return nil }
+	}
+	targets := []shutdownTarget{
+		{Name: "witness", Session: "polite", Kill: kill("polite")},
+		{Name: "refinery", Session: "stubborn", Kill: kill("stubborn")},
+		{Name: "polecat Toast", Session: "stuck", Kill: kill("stuck")},
+		{Name: "polecat Nux", Session: "broken", Kill: func() error { return errors.New("permission denied") }},
+	}
+
+	var outcomes []shutdownOutcome
+	captureStdout(t, func() {
+		outcomes = escalatingShutdown(tm, "testrig", targets, shutdownPhases{Graceful: 5 * time.Millisecond, Interrupt: 5 * time.Millisecond})
+	})
+
+	want := []string{shutdownExitedOnRequest, shutdownExitedOnInterrupt, shutdownForceKilled, shutdownKillFailed}
+	for i, o := range outcomes {
+		if o.How != want[i] {
+			t.Errorf("%s: %q, want %q", o.Target.Name, o.How, want[i])
+		}
+	}
+
+	var failed []string
+	output := captureStdout(t, func() { failed = printShutdownReport(outcomes) })
+	if len(failed) != 1 || failed[0] != "polecat Nux: permission denied" {
+		t.Errorf("failed = %v", failed)
+	}
+	if want := "Force-killed: polecat Toast\n"; !strings.Contains(output, want) {
+		t.Errorf("report missing %q:\n%s", want, output)
+	}
+}
+
+// requestOnlySessions is a multiplexer without Ctrl-C, like headless.
+type requestOnlySessions struct{ alive map[string]bool }
+
+func (f *requestOnlySessions) HasSession(name string) (bool, error) { return f.alive[name], nil }
+func (f *requestOnlySessions) SendKeys(string, string) error        { return nil }
+
+func TestEscalatingShutdown_NoInterrupt(t *testing.T) {
+	old := shutdownPollInterval
+	shutdownPollInterval = time.Millisecond
+	t.Cleanup(func() { shutdownPollInterval = old })
+
+	tm := &requestOnlySessions{alive: map[string]bool{"stuck": true}}
+	targets := []shutdownTarget{{Name: "witness", Session: "stuck", Kill: func() error { tm.alive["stuck"] = false; return nil }}}
+
+	var outcomes []shutdownOutcome
+	output := captureStdout(t, func() {
+		outcomes = escalatingShutdown(tm, "testrig", targets, shutdownPhases{Graceful: 5 * time.Millisecond, Interrupt: time.Hour})
+	})
+	if outcomes[0].How != shutdownForceKilled {
+		t.Errorf("outcome = %q, want %q", outcomes[0].How, shutdownForceKilled)
+	}
+	if strings.Contains(output, "Phase 2") {
+		t.Errorf("interrupt phase ran without Ctrl-C support:\n%s", output)
+	}
+}
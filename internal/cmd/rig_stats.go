@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/rigstats"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigStatsSince   string
+	rigStatsBuckets int
+	rigStatsJSON    bool
+)
+
+var rigStatsCmd = &cobra.Command{
+	Use:   "stats <rig>",
+	Short: "Show historical activity metrics for a rig",
+	Long: `Show a rig's activity over time from samples the daemon records.
+
+The daemon's rig_stats patrol samples every rig hourly (configurable in
+daemon.json under patrols.rig_stats.interval) into the gt_rig_stats table
+in the hq database. Each sample records:
+
+  commits     Merges the refinery landed
+  closed      Work beads closed
+  polecat-h   Polecat hours (working polecats × sample window)
+  queue       Open, unassigned work beads at sample time (mean per bucket)
+  wait-h      Mean age of those queued beads, in hours
+
+The text view draws one sparkline per metric across the --since range.
+--json prints the raw samples plus the bucketed series for dashboards.
+
+Examples:
+  gt rig stats gastown
+  gt rig stats gastown --since 7d
+  gt rig stats gastown --since 90d --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigStats,
+}
+
+func init() {
+	rigStatsCmd.Flags().StringVar(&rigStatsSince, "since", "30d", "How far back to report (e.g. 24h, 7d, 30d)")
+	rigStatsCmd.Flags().IntVar(&rigStatsBuckets, "buckets", 30, "Number of points in each chart")
+	rigStatsCmd.Flags().BoolVar(&rigStatsJSON, "json", false, "Output as JSON")
+
+	rigCmd.AddCommand(rigStatsCmd)
+}
+
+// rigStatsOutput is the --json shape of gt rig stats.
+type rigStatsOutput struct {
+	Rig     string            `json:"rig"`
+	Since   time.Time         `json:"since"`
+	Until   time.Time         `json:"until"`
+	Samples []rigstats.Sample `json:"samples"`
+	Buckets []rigstats.Bucket `json:"buckets"`
+}
+
+func runRigStats(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	window, err := parseDuration(rigStatsSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q", rigStatsSince)
+	}
+	if rigStatsBuckets <= 0 {
+		return fmt.Errorf("--buckets must be positive")
+	}
+
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return fmt.Errorf("Dolt server is not running (start with: gt dolt start)")
+	}
+	db, err := doltserver.DB(townRoot, rigstats.Database)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now().UTC()
+	since := until.Add(-window)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	samples, err := rigstats.Query(ctx, db, rigName, since)
+	if err != nil {
+		return err
+	}
+	buckets := rigstats.Aggregate(samples, since, until, rigStatsBuckets)
+
+	if rigStatsJSON {
+		if samples == nil {
+			samples = []rigstats.Sample{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rigStatsOutput{Rig: rigName, Since: since, Until: until, Samples: samples, Buckets: buckets})
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Rig stats:"), rigName)
+	if len(samples) == 0 {
+		fmt.Printf("  No samples in the last %s.\n", rigStatsSince)
+		fmt.Printf("  %s\n", style.Dim.Render("Samples are recorded by the daemon's rig_stats patrol (gt daemon start)."))
+		return nil
+	}
+	printRigStats(samples, buckets)
+	fmt.Printf("\n  %s\n", style.Dim.Render(fmt.Sprintf("%d sample(s) from %s to %s",
+		len(samples), samples[0].SampledAt.Local().Format("2006-01-02 15:04"),
+		samples[len(samples)-1].SampledAt.Local().Format("2006-01-02 15:04"))))
+	return nil
+}
+
+// printRigStats prints one sparkline row per metric with its total (or mean,
+// for queue metrics) and latest sample.
+func printRigStats(samples []rigstats.Sample, buckets []rigstats.Bucket) {
+	latest := samples[len(samples)-1]
+	var totals rigstats.Bucket
+	for _, s := range samples {
+		totals.CommitsLanded += s.CommitsLanded
+		totals.BeadsClosed += s.BeadsClosed
+		totals.PolecatHours += s.PolecatHours
+		totals.QueueDepth += float64(s.QueueDepth)
+		totals.QueueWaitHours += s.QueueWaitHours
+	}
+	n := float64(len(samples))
+
+	series := func(f func(b rigstats.Bucket) float64) string {
+		values := make([]float64, len(buckets))
+		for i, b := range buckets {
+			values[i] = f(b)
+		}
+		return rigstats.Sparkline(values)
+	}
+
+	fmt.Printf("  %-10s %s  %s\n", "", style.Dim.Render(fmt.Sprintf("%-*s", len(buckets), "chart")), style.Dim.Render("total/mean   latest"))
+	row := func(name, chart, summary, last string) {
+		fmt.Printf("  %-10s %s  %-12s %s\n", name, chart, summary, last)
+	}
+	row("commits", series(func(b rigstats.Bucket) float64 { return float64(b.CommitsLanded) }),
+		fmt.Sprintf("%d", totals.CommitsLanded), fmt.Sprintf("%d", latest.CommitsLanded))
+	row("closed", series(func(b rigstats.Bucket) float64 { return float64(b.BeadsClosed) }),
+		fmt.Sprintf("%d", totals.BeadsClosed), fmt.Sprintf("%d", latest.BeadsClosed))
+	row("polecat-h", series(func(b rigstats.Bucket) float64 { return b.PolecatHours }),
+		fmt.Sprintf("%.1f", totals.PolecatHours), fmt.Sprintf("%.1f", latest.PolecatHours))
+	row("queue", series(func(b rigstats.Bucket) float64 { return b.QueueDepth }),
+		fmt.Sprintf("%.1f avg", totals.QueueDepth/n), fmt.Sprintf("%d", latest.QueueDepth))
+	row("wait-h", series(func(b rigstats.Bucket) float64 { return b.QueueWaitHours }),
+		fmt.Sprintf("%.1f avg", totals.QueueWaitHours/n), fmt.Sprintf("%.1f", latest.QueueWaitHours))
+}
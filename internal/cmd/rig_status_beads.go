@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// rigStatusBeadsTimeout bounds the beads summary query, so gt rig status
+// stays responsive when the Dolt server is slow.
+const rigStatusBeadsTimeout = 3 * time.Second
+
+// printRigBeadsSummary prints the Beads section of gt rig status.
+func printRigBeadsSummary(townRoot, rigName string) {
+	fmt.Printf("%s\n", style.Bold.Render("Beads"))
+	summary, err := fetchRigBeadsSummary(townRoot, rigName)
+	if err != nil {
+		fmt.Printf("  %s\n", style.Dim.Render("unavailable: "+err.Error()))
+	} else {
+		renderRigBeadsSummary(os.Stdout, summary)
+	}
+	fmt.Println()
+}
+
+// fetchRigBeadsSummary queries the rig's database, giving up after
+// rigStatusBeadsTimeout.
+func fetchRigBeadsSummary(townRoot, rigName string) (*beads.WorkSummary, error) {
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		return nil, fmt.Errorf("Dolt server is not running")
+	}
+	db, err := doltserver.DB(townRoot, doltserver.RigDatabase(townRoot, rigName))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rigStatusBeadsTimeout)
+	defer cancel()
+	summary, err := beads.SummarizeWork(ctx, db)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("Dolt server did not answer within %s", rigStatusBeadsTimeout)
+	}
+	return summary, err
+}
+
+// renderRigBeadsSummary writes the counts under the Beads heading.
+func renderRigBeadsSummary(w io.Writer, s *beads.WorkSummary) {
+	fmt.Fprintf(w, "  Open: %d  In progress: %d  Blocked: %d  Closed: %d\n",
+		s.Open, s.InProgress, s.Blocked, s.Closed)
+	if s.InProgress > 0 {
+		age := time.Duration(s.OldestInProgressHours * float64(time.Hour))
+		fmt.Fprintf(w, "  Oldest in progress: %s\n", formatDurationAgo(age))
+	}
+	ready := fmt.Sprintf("%d", s.ReadyUnassigned)
+	if s.ReadyUnassigned > 0 {
+		ready = style.Warning.Render(ready)
+	}
+	fmt.Fprintf(w, "  Ready, unassigned: %s\n", ready)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestRenderRigBeadsSummary(t *testing.T) {
+	var out bytes.Buffer
+	renderRigBeadsSummary(&out, &beads.WorkSummary{
+		Open: 12, InProgress: 3, Blocked: 1, Closed: 140,
+		OldestInProgressHours: 50, ReadyUnassigned: 4,
+	})
+	for _, want := range []string{
+		"Open: 12  In progress: 3  Blocked: 1  Closed: 140",
+		"Oldest in progress: 2 days",
+		"Ready, unassigned: 4",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	renderRigBeadsSummary(&out, &beads.WorkSummary{Closed: 5})
+	if strings.Contains(out.String(), "Oldest in progress") {
+		t.Errorf("oldest age shown with nothing in progress:\n%s", out.String())
+	}
+}
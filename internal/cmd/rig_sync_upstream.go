@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigSyncUpstreamAll      bool
+	rigSyncUpstreamDryRun   bool
+	rigSyncUpstreamJSON     bool
+	rigSyncUpstreamStrategy string
+)
+
+var rigSyncUpstreamCmd = &cobra.Command{
+	Use:   "sync-upstream [rig...]",
+	Short: "Sync a fork rig's default branch from its upstream",
+	Long: `Bring a fork rig's default branch up to date with its upstream.
+
+Fork rigs (added with --push-url) fetch from a read-only upstream and push
+to a fork. The fork's default branch doesn't follow upstream on its own, so
+polecat branches pushed there drift further behind. This command fetches
+both, then:
+  - Fast-forwards the fork if it is only behind upstream
+  - With --strategy rebase, rebases commits only the fork has onto
+    upstream and force-pushes with a lease (never overwriting commits
+    pushed since the fetch)
+  - Otherwise reports the fork as diverged and leaves it alone
+
+A conflicting rebase is aborted and reported as diverged. The result is
+shown in gt rig status.
+
+The daemon can do this periodically: enable the upstream_sync patrol in
+mayor/daemon.json.
+
+Examples:
+  gt rig sync-upstream gastown
+  gt rig sync-upstream --all --dry-run
+  gt rig sync-upstream gastown --strategy rebase`,
+	RunE: runRigSyncUpstream,
+}
+
+func init() {
+	rigSyncUpstreamCmd.Flags().BoolVar(&rigSyncUpstreamAll, "all", false, "Sync every fork rig in the town")
+	rigSyncUpstreamCmd.Flags().BoolVar(&rigSyncUpstreamDryRun, "dry-run", false, "Report divergence without pushing")
+	rigSyncUpstreamCmd.Flags().BoolVar(&rigSyncUpstreamJSON, "json", false, "Output as JSON")
+	rigSyncUpstreamCmd.Flags().StringVar(&rigSyncUpstreamStrategy, "strategy", rig.UpstreamSyncFFOnly, "How to handle a diverged fork: ff-only or rebase")
+
+	rigCmd.AddCommand(rigSyncUpstreamCmd)
+}
+
+func runRigSyncUpstream(cmd *cobra.Command, args []string) error {
+	if rigSyncUpstreamAll == (len(args) > 0) {
+		return fmt.Errorf("name one or more rigs, or pass --all")
+	}
+	if rigSyncUpstreamStrategy != rig.UpstreamSyncFFOnly && rigSyncUpstreamStrategy != rig.UpstreamSyncRebase {
+		return fmt.Errorf("invalid --strategy %q: want %s or %s", rigSyncUpstreamStrategy, rig.UpstreamSyncFFOnly, rig.UpstreamSyncRebase)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	names := args
+	if rigSyncUpstreamAll {
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var results []*rig.UpstreamSync
+	var failed int
+	for _, name := range names {
+		if _, ok := rigsConfig.Rigs[name]; !ok {
+			return fmt.Errorf("rig '%s' not found", name)
+		}
+		result, err := rig.SyncFromUpstream(filepath.Join(townRoot, name), rigSyncUpstreamStrategy, rigSyncUpstreamDryRun)
+		if errors.Is(err, rig.ErrNoPushURL) && rigSyncUpstreamAll {
+			continue
+		}
+		if err != nil {
+			failed++
+			style.PrintWarning("%s: %v", name, err)
+			continue
+		}
+		results = append(results, result)
+		if !rigSyncUpstreamJSON {
+			printUpstreamSync(result)
+		}
+	}
+
+	if rigSyncUpstreamJSON {
+		if results == nil {
+			results = []*rig.UpstreamSync{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else if rigSyncUpstreamAll && len(results) == 0 && failed == 0 {
+		fmt.Println("No fork rigs (rigs with a push_url) in this town.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rig(s) could not be synced", failed, len(names))
+	}
+	return nil
+}
+
+func printUpstreamSync(s *rig.UpstreamSync) {
+	name := style.Bold.Render(s.Rig)
+	switch s.Status {
+	case rig.UpstreamUpToDate:
+		fmt.Printf("%s %s: %s up to date with upstream\n", style.Success.Render("✓"), name, s.Branch)
+	case rig.UpstreamAhead:
+		fmt.Printf("%s %s: %s up to date (%d fork commit(s) not upstream)\n", style.Success.Render("✓"), name, s.Branch, s.Ahead)
+	case rig.UpstreamFastForwarded:
+		fmt.Printf("%s %s: fast-forwarded %s by %d commit(s)\n", style.Success.Render("✓"), name, s.Branch, s.Behind)
+	case rig.UpstreamRebased:
+		fmt.Printf("%s %s: rebased %d fork commit(s) onto %d upstream commit(s) on %s\n",
+			style.Success.Render("✓"), name, s.Ahead, s.Behind, s.Branch)
+	case rig.UpstreamBehind:
+		fmt.Printf("%s %s: %s is %d commit(s) behind upstream (dry run, not pushed)\n",
+			style.Warning.Render("!"), name, s.Branch, s.Behind)
+	case rig.UpstreamDiverged:
+		fmt.Printf("%s %s: %s diverged from upstream (%d ahead, %d behind)\n",
+			style.Warning.Render("!"), name, s.Branch, s.Ahead, s.Behind)
+		if s.Error != "" {
+			fmt.Printf("  %s\n", s.Error)
+		} else if !rigSyncUpstreamDryRun {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("gt rig sync-upstream %s --strategy rebase", s.Rig)))
+		}
+	}
+}
+
+// printRigUpstreamStatus shows a fork rig's last upstream sync in gt rig status.
+func printRigUpstreamStatus(rigPath string) {
+	cfg, err := rig.LoadRigConfig(rigPath)
+	if err != nil || cfg.PushURL == "" {
+		return
+	}
+	s, err := rig.LoadUpstreamSync(rigPath)
+	if err != nil {
+		fmt.Printf("  Upstream: %s\n", style.Warning.Render(err.Error()))
+		return
+	}
+	if s == nil {
+		fmt.Printf("  Upstream: %s\n", style.Dim.Render("never synced (gt rig sync-upstream)"))
+		return
+	}
+	checked := style.Dim.Render("checked " + formatAge(s.CheckedAt))
+	switch s.Status {
+	case rig.UpstreamDiverged:
+		fmt.Printf("  Upstream: %s (%d ahead, %d behind) %s\n",
+			style.Warning.Render("fork "+s.Branch+" diverged"), s.Ahead, s.Behind, checked)
+	default:
+		fmt.Printf("  Upstream: fork %s in sync %s\n", s.Branch, checked)
+	}
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -198,3 +202,108 @@ func TestCheckUncommittedWork_DirtyForceTTYPrompts(t *testing.T) {
 		t.Fatal("expected proceed=true after force+TTY confirmation")
 	}
 }
+
+func TestCheckUncommittedWork_CheckClonesIncludesCrewAndRefinery(t *testing.T) {
+	r := &rig.Rig{Name: "testrig", Path: t.TempDir()}
+	refineryPath := filepath.Join(r.Path, "refinery", "rig")
+	if err := os.MkdirAll(refineryPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var checked []string
+	stubUncommittedWorkCheckDeps(
+		t,
+		func(*rig.Rig) ([]*polecat.Polecat, error) { return nil, nil },
+		func(path string) (*git.UncommittedWorkStatus, error) {
+			checked = append(checked, path)
+			if path == refineryPath {
+				return &git.UncommittedWorkStatus{HasUncommittedChanges: true, ModifiedFiles: []string{"go.mod"}}, nil
+			}
+			return &git.UncommittedWorkStatus{}, nil
+		},
+		func() bool { return false },
+		func(string) bool { return false },
+	)
+	oldCrew := listCrewForWorkCheck
+	listCrewForWorkCheck = func(*rig.Rig) ([]*crew.CrewWorker, error) {
+		return []*crew.CrewWorker{{Name: "dave", ClonePath: "/tmp/dave"}}, nil
+	}
+	t.Cleanup(func() { listCrewForWorkCheck = oldCrew })
+
+	// Without the flag or setting, only polecats (none here) are checked.
+	if !checkUncommittedWork(r, "testrig", "restart", false) {
+		t.Fatal("expected proceed=true when no polecats and clone checks are off")
+	}
+	if len(checked) != 0 {
+		t.Fatalf("checked %v with clone checks off", checked)
+	}
+
+	rigCheckClones = true
+	t.Cleanup(func() { rigCheckClones = false })
+	var proceed bool
+	output := captureStdout(t, func() {
+		proceed = checkUncommittedWork(r, "testrig", "restart", false)
+	})
+	if proceed {
+		t.Fatal("expected proceed=false with a dirty refinery clone")
+	}
+	if len(checked) != 2 {
+		t.Errorf("checked %v, want crew and refinery", checked)
+	}
+	if !strings.Contains(output, "workspaces have uncommitted work") || !strings.Contains(output, "refinery") {
+		t.Errorf("expected refinery in warning, got: %q", output)
+	}
+}
+
+func TestWorkCheckClones_RigSetting(t *testing.T) {
+	r := &rig.Rig{Name: "testrig", Path: t.TempDir()}
+	if c, ref := workCheckClones(r); c || ref {
+		t.Errorf("no settings: crew=%v refinery=%v, want both false", c, ref)
+	}
+
+	settings := config.NewRigSettings()
+	settings.WorkCheck = &config.WorkCheckConfig{Refinery: true}
+	if err := config.SaveRigSettings(config.RigSettingsPath(r.Path), settings); err != nil {
+		t.Fatal(err)
+	}
+	if c, ref := workCheckClones(r); c || !ref {
+		t.Errorf("work_check.refinery: crew=%v refinery=%v, want false, true", c, ref)
+	}
+}
+
+func TestCheckUncommittedWork_ForceNonInteractiveBlocks(t *testing.T) {
+	stubUncommittedWorkCheckDeps(
+		t,
+		func(*rig.Rig) ([]*polecat.Polecat, error) {
+			return []*polecat.Polecat{
+				{Name: "alpha", ClonePath: "/tmp/alpha"},
+			}, nil
+		},
+		func(string) (*git.UncommittedWorkStatus, error) {
+			return &git.UncommittedWorkStatus{
+				HasUncommittedChanges: true,
+				ModifiedFiles:         []string{"README.md"},
+			}, nil
+		},
+		func() bool { return true },
+		func(string) bool {
+			t.Fatalf("prompt should not be called with --yes")
+			return true
+		},
+	)
+	oldYes := assumeYesFlag
+	assumeYesFlag = true
+	t.Cleanup(func() { assumeYesFlag = oldYes })
+
+	var proceed bool
+	output := captureStdout(t, func() {
+		proceed = checkUncommittedWork(testRig(), "testrig", "stop", true)
+	})
+
+	if proceed {
+		t.Fatal("expected --yes not to accept losing uncommitted work")
+	}
+	if !strings.Contains(output, "--nuclear") {
+		t.Fatalf("expected --nuclear hint, got: %q", output)
+	}
+}
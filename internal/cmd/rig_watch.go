@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigWatchEvents    []string
+	rigWatchNoDesktop bool
+	rigWatchWebhook   string
+)
+
+var rigWatchCmd = &cobra.Command{
+	Use:   "watch [rig...]",
+	Short: "Notify on significant rig events",
+	Long: `Watch the town event log and notify when something significant happens.
+
+Event kinds:
+  merged          The refinery merged a branch
+  merge_failed    The refinery rejected a branch
+  polecat_failed  A polecat session died without finishing via gt done
+  witness_died    A witness session died and the daemon restarted it
+  dolt_restarted  The daemon restarted a crashed or unhealthy Dolt server
+
+Each notification is printed, sent as a desktop notification (notify-send
+on Linux, osascript on macOS), and POSTed as JSON to a webhook if one is
+configured. With no rig arguments, all rigs are watched; dolt_restarted
+is town-wide and always reported.
+
+Defaults come from the "notifications" section of settings/config.json:
+
+  "notifications": {
+    "events": ["merged", "polecat_failed", "witness_died", "dolt_restarted"],
+    "desktop": true,
+    "webhook": "https://example.com/hooks/gastown"
+  }
+
+Only events logged after the watch starts are reported.
+
+Examples:
+  gt rig watch
+  gt rig watch gastown beads
+  gt rig watch --events merged,merge_failed --no-desktop
+  gt rig watch --webhook https://example.com/hooks/gastown`,
+	RunE: runRigWatch,
+}
+
+func init() {
+	rigWatchCmd.Flags().StringSliceVar(&rigWatchEvents, "events", nil, "Event kinds to notify on (overrides settings)")
+	rigWatchCmd.Flags().BoolVar(&rigWatchNoDesktop, "no-desktop", false, "Don't send desktop notifications")
+	rigWatchCmd.Flags().StringVar(&rigWatchWebhook, "webhook", "", "POST notifications to this URL (overrides settings)")
+
+	rigCmd.AddCommand(rigWatchCmd)
+}
+
+// rigWatchPollInterval is how often the events file is checked for new lines.
+const rigWatchPollInterval = 500 * time.Millisecond
+
+// watchNotification is a rig event worth telling someone about.
+// It is also the JSON body POSTed to the webhook.
+type watchNotification struct {
+	Event   string    `json:"event"`
+	Rig     string    `json:"rig,omitempty"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"ts"`
+}
+
+// rigWatcher filters events and delivers notifications.
+type rigWatcher struct {
+	enabled map[string]bool
+	rigs    map[string]bool // empty means all rigs
+	desktop bool
+	webhook string
+	out     io.Writer
+	client  *http.Client
+}
+
+func runRigWatch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading settings: %w", err)
+	}
+
+	kinds := settings.Notifications.EnabledEvents()
+	if len(rigWatchEvents) > 0 {
+		kinds = rigWatchEvents
+	}
+	w, err := newRigWatcher(kinds, args)
+	if err != nil {
+		return err
+	}
+	w.desktop = settings.Notifications.DesktopEnabled() && !rigWatchNoDesktop
+	if settings.Notifications != nil {
+		w.webhook = settings.Notifications.Webhook
+	}
+	if rigWatchWebhook != "" {
+		w.webhook = rigWatchWebhook
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	scope := "all rigs"
+	if len(args) > 0 {
+		scope = strings.Join(args, ", ")
+	}
+	fmt.Printf("%s Watching %s for %s (Ctrl-C to stop)\n",
+		style.Bold.Render("👁"), scope, strings.Join(kinds, ", "))
+
+	return w.tail(ctx, filepath.Join(townRoot, events.EventsFile))
+}
+
+// newRigWatcher returns a watcher for the given event kinds and rigs.
+func newRigWatcher(kinds, rigs []string) (*rigWatcher, error) {
+	w := &rigWatcher{
+		enabled: make(map[string]bool),
+		rigs:    make(map[string]bool),
+		out:     os.Stdout,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, k := range kinds {
+		if !slices.Contains(config.WatchEvents, k) {
+			return nil, fmt.Errorf("unknown event kind %q (valid: %s)", k, strings.Join(config.WatchEvents, ", "))
+		}
+		w.enabled[k] = true
+	}
+	for _, r := range rigs {
+		w.rigs[r] = true
+	}
+	return w, nil
+}
+
+// tail follows the events file from its current end until ctx is done.
+// A missing file is waited for, since the first event creates it.
+func (w *rigWatcher) tail(ctx context.Context, path string) error {
+	var file *os.File
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+		}
+	}()
+
+	var partial string
+	ticker := time.NewTicker(rigWatchPollInterval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		if file == nil {
+			f, err := os.Open(path)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("opening events: %w", err)
+			}
+			if f != nil {
+				file = f
+				if first {
+					// Only report events that happen from now on.
+					if _, err := file.Seek(0, io.SeekEnd); err != nil {
+						return fmt.Errorf("seeking events: %w", err)
+					}
+				}
+			}
+		}
+
+		if file != nil {
+			reader := bufio.NewReader(file)
+			for {
+				chunk, err := reader.ReadString('\n')
+				if err != nil {
+					// Keep an incomplete trailing line until the writer finishes it.
+					partial += chunk
+					break
+				}
+				w.handleLine(ctx, partial+chunk)
+				partial = ""
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *rigWatcher) handleLine(ctx context.Context, line string) {
+	var ev events.Event
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &ev); err != nil {
+		return
+	}
+	n := classifyWatchEvent(ev)
+	if n == nil || !w.wants(n) {
+		return
+	}
+	w.deliver(ctx, n)
+}
+
+// wants reports whether n passes the event-kind and rig filters.
+func (w *rigWatcher) wants(n *watchNotification) bool {
+	if !w.enabled[n.Event] {
+		return false
+	}
+	if len(w.rigs) == 0 || n.Rig == "" {
+		return true
+	}
+	return w.rigs[n.Rig]
+}
+
+// classifyWatchEvent maps a raw event to a notification, or nil if the
+// event isn't one gt rig watch reports.
+func classifyWatchEvent(ev events.Event) *watchNotification {
+	n := &watchNotification{Time: time.Now()}
+	if ts, err := time.Parse(time.RFC3339, ev.Timestamp); err == nil {
+		n.Time = ts
+	}
+	str := func(key string) string {
+		s, _ := ev.Payload[key].(string)
+		return s
+	}
+
+	switch ev.Type {
+	case events.TypeMerged, events.TypeMergeFailed:
+		n.Rig, _, _ = strings.Cut(ev.Actor, "/")
+		branch := str("branch")
+		if worker := str("worker"); worker != "" {
+			branch = fmt.Sprintf("%s (%s)", branch, worker)
+		}
+		if ev.Type == events.TypeMerged {
+			n.Event = config.WatchEventMerged
+			n.Title = fmt.Sprintf("%s: merged", n.Rig)
+			n.Message = fmt.Sprintf("Refinery merged %s", branch)
+		} else {
+			n.Event = config.WatchEventMergeFailed
+			n.Title = fmt.Sprintf("%s: merge failed", n.Rig)
+			n.Message = fmt.Sprintf("Refinery rejected %s: %s", branch, str("reason"))
+		}
+
+	case events.TypeSessionDeath:
+		agent := str("agent")
+		parts := strings.Split(agent, "/")
+		switch {
+		case len(parts) == 2 && parts[1] == "witness":
+			n.Event = config.WatchEventWitnessDied
+			n.Rig = parts[0]
+			n.Title = fmt.Sprintf("%s: witness died", n.Rig)
+		case len(parts) == 3 && parts[1] == "polecats":
+			if str("caller") == "gt done" {
+				return nil // finished its work and exited normally
+			}
+			n.Event = config.WatchEventPolecatFailed
+			n.Rig = parts[0]
+			n.Title = fmt.Sprintf("%s: polecat %s failed", n.Rig, parts[2])
+		default:
+			return nil
+		}
+		n.Message = fmt.Sprintf("%s session ended: %s", agent, str("reason"))
+
+	case events.TypeDoltRestarted:
+		n.Event = config.WatchEventDoltRestarted
+		n.Title = "Dolt server restarted"
+		n.Message = fmt.Sprintf("Daemon restarted Dolt: %s", str("reason"))
+
+	default:
+		return nil
+	}
+	return n
+}
+
+// deliver prints n and sends it to the desktop and webhook. Delivery
+// failures are reported but never stop the watch.
+func (w *rigWatcher) deliver(ctx context.Context, n *watchNotification) {
+	fmt.Fprintf(w.out, "[%s] %s %s — %s\n", n.Time.Local().Format("15:04:05"), style.Bold.Render(n.Title), style.Dim.Render("("+n.Event+")"), n.Message)
+
+	if w.desktop {
+		if err := sendDesktopNotification(ctx, n.Title, n.Message); err != nil {
+			fmt.Fprintf(os.Stderr, "%s desktop notification: %v\n", style.Warning.Render("!"), err)
+		}
+	}
+	if w.webhook != "" {
+		if err := w.postWebhook(ctx, n); err != nil {
+			fmt.Fprintf(os.Stderr, "%s webhook: %v\n", style.Warning.Render("!"), err)
+		}
+	}
+}
+
+func (w *rigWatcher) postWebhook(ctx context.Context, n *watchNotification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", w.webhook, resp.Status)
+	}
+	return nil
+}
+
+// sendDesktopNotification shows an OS notification where supported.
+func sendDesktopNotification(ctx context.Context, title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "linux":
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=gastown", title, message)
+	default:
+		return fmt.Errorf("not supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestClassifyWatchEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		ev        events.Event
+		wantEvent string
+		wantRig   string
+	}{
+		{
+			name:      "merged",
+			ev:        events.Event{Type: events.TypeMerged, Actor: "gastown/refinery", Payload: events.MergePayload("gt-mr1", "nux", "polecat/nux", "")},
+			wantEvent: config.WatchEventMerged,
+			wantRig:   "gastown",
+		},
+		{
+			name:      "merge failed",
+			ev:        events.Event{Type: events.TypeMergeFailed, Actor: "beads/refinery", Payload: events.MergePayload("bd-mr2", "ace", "polecat/ace", "tests failed")},
+			wantEvent: config.WatchEventMergeFailed,
+			wantRig:   "beads",
+		},
+		{
+			name:      "witness died",
+			ev:        events.Event{Type: events.TypeSessionDeath, Payload: events.SessionDeathPayload("gt-gastown-witness", "gastown/witness", "session dead", "daemon")},
+			wantEvent: config.WatchEventWitnessDied,
+			wantRig:   "gastown",
+		},
+		{
+			name:      "polecat crashed",
+			ev:        events.Event{Type: events.TypeSessionDeath, Payload: events.SessionDeathPayload("gt-gastown-nux", "gastown/polecats/nux", "crashed with work on hook", "daemon")},
+			wantEvent: config.WatchEventPolecatFailed,
+			wantRig:   "gastown",
+		},
+		{
+			name: "polecat done is not a failure",
+			ev:   events.Event{Type: events.TypeSessionDeath, Payload: events.SessionDeathPayload("gt-gastown-nux", "gastown/polecats/nux", "self-clean: done means gone", "gt done")},
+		},
+		{
+			name: "other session death",
+			ev:   events.Event{Type: events.TypeSessionDeath, Payload: events.SessionDeathPayload("hq-mayor", "mayor", "user request", "gt down")},
+		},
+		{
+			name:      "dolt restarted",
+			ev:        events.Event{Type: events.TypeDoltRestarted, Actor: "daemon", Payload: events.DoltRestartPayload(3307, 1, "server_dead")},
+			wantEvent: config.WatchEventDoltRestarted,
+		},
+		{
+			name: "unrelated",
+			ev:   events.Event{Type: events.TypeSling, Actor: "mayor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Round-trip through JSON so payloads look like they do when read from disk.
+			data, err := json.Marshal(tt.ev)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ev events.Event
+			if err := json.Unmarshal(data, &ev); err != nil {
+				t.Fatal(err)
+			}
+
+			n := classifyWatchEvent(ev)
+			if tt.wantEvent == "" {
+				if n != nil {
+					t.Errorf("expected no notification, got %+v", n)
+				}
+				return
+			}
+			if n == nil {
+				t.Fatal("expected a notification")
+			}
+			if n.Event != tt.wantEvent || n.Rig != tt.wantRig {
+				t.Errorf("got event=%q rig=%q, want %q %q", n.Event, n.Rig, tt.wantEvent, tt.wantRig)
+			}
+			if n.Title == "" || n.Message == "" {
+				t.Errorf("empty title or message: %+v", n)
+			}
+		})
+	}
+}
+
+func TestRigWatcher_Filters(t *testing.T) {
+	if _, err := newRigWatcher([]string{"merged", "bogus"}, nil); err == nil {
+		t.Error("expected error for unknown event kind")
+	}
+
+	w, err := newRigWatcher([]string{config.WatchEventMerged, config.WatchEventDoltRestarted}, []string{"gastown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		n    watchNotification
+		want bool
+	}{
+		{watchNotification{Event: config.WatchEventMerged, Rig: "gastown"}, true},
+		{watchNotification{Event: config.WatchEventMerged, Rig: "beads"}, false},
+		{watchNotification{Event: config.WatchEventMergeFailed, Rig: "gastown"}, false},
+		{watchNotification{Event: config.WatchEventDoltRestarted}, true},
+	}
+	for _, c := range cases {
+		if got := w.wants(&c.n); got != c.want {
+			t.Errorf("wants(%s/%s) = %v, want %v", c.n.Rig, c.n.Event, got, c.want)
+		}
+	}
+}
+
+func TestRigWatcher_TailDeliversNewEvents(t *testing.T) {
+	var posted []watchNotification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n watchNotification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+		posted = append(posted, n)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), events.EventsFile)
+	old, _ := json.Marshal(events.Event{Type: events.TypeMerged, Actor: "gastown/refinery", Payload: events.MergePayload("old", "", "old-branch", "")})
+	if err := os.WriteFile(path, append(old, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newRigWatcher([]string{config.WatchEventMerged}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	w.out = &out
+	w.webhook = srv.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.tail(ctx, path) }()
+
+	// Give tail time to open the file and seek to the end.
+	time.Sleep(100 * time.Millisecond)
+	line, _ := json.Marshal(events.Event{Type: events.TypeMerged, Actor: "gastown/refinery", Payload: events.MergePayload("new", "nux", "polecat/nux", "")})
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write in two pieces to exercise partial-line handling.
+	_, _ = f.Write(line[:10])
+	time.Sleep(rigWatchPollInterval + 100*time.Millisecond)
+	_, _ = f.Write(append(line[10:], '\n'))
+	_ = f.Close()
+	time.Sleep(rigWatchPollInterval + 200*time.Millisecond)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+
+	if strings.Contains(out.String(), "old-branch") {
+		t.Error("event from before the watch started was reported")
+	}
+	if len(posted) != 1 || posted[0].Message != "Refinery merged polecat/nux (nux)" || posted[0].Rig != "gastown" {
+		t.Errorf("posted = %+v", posted)
+	}
+}
@@ -2,17 +2,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/version"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -80,6 +85,9 @@ var branchCheckExemptCommands = map[string]bool{
 
 // persistentPreRun runs before every command.
 func persistentPreRun(cmd *cobra.Command, args []string) error {
+	applyTimeoutFlag(cmd)
+	util.SetCommandContext(cmd.Context())
+
 	// Check if binary was built properly (via make build, not raw go build).
 	// Raw go build produces unsigned binaries that macOS may kill.
 	// Warning only - doesn't block execution.
@@ -98,11 +106,17 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 
 	// Initialize session prefix registry from rigs.json.
 	// Best-effort: if town root not found, the default "gt" prefix is used.
-	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+	if townRoot := commandTownRoot(); townRoot != "" {
 		_ = session.InitRegistry(townRoot)
-		if err := config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot)); err != nil {
-			fmt.Fprintf(os.Stderr, "WARNING: failed to load agent registry %s: %v\n",
-				config.DefaultAgentRegistryPath(townRoot), err)
+		if err := config.LoadTownAgentRegistry(townRoot); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: failed to load agent registry: %v\n", err)
+		}
+
+		// Enforce command permissions on shared towns (mayor/permissions.json)
+		// for the town the command acts on, which the GT_TOWN_ROOT fallback
+		// may name instead of the cwd.
+		if err := checkCommandPermission(townRoot, cmd); err != nil {
+			return err
 		}
 	}
 
@@ -226,11 +240,28 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
+	start := time.Now()
+	townRoot, _ := workspace.FindFromCwd()
+	shutdownTracing := telemetry.Setup(townRoot, "gt", Version)
+	ctx, span := telemetry.StartCommand(context.Background(), "gt")
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	if cmd != nil {
+		span.SetName(cmd.CommandPath())
+	}
+	telemetry.End(span, err)
+	shutdownTracing()
+	recordAudit(cmd, start, err)
+	if err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
 		}
+		// Prompts refused in non-interactive mode get a machine-readable report
+		var pe *PromptRequiredError
+		if errors.As(err, &pe) {
+			printPromptRequired(pe)
+			return exitPromptRequired
+		}
 		// Other errors already printed by cobra
 		return 1
 	}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/search"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	searchRigs  []string
+	searchKinds []string
+	searchLimit int
+	searchJSON  bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <term>",
+	GroupID: GroupWork,
+	Short:   "Search beads, agent transcripts and configs",
+	Long: `Search the town for a term, case-insensitively, in three places:
+
+  bead        titles and descriptions of every rig's beads and hq, queried
+              in the Dolt server (skipped if it isn't running)
+  transcript  agent transcripts (text and tool output, not tool inputs)
+              and headless polecat session logs
+  config      town and rig settings, rig config.json and mayor/*.json;
+              secrets files are never searched
+
+Results are grouped by kind, with the rig and location of each: a bead
+ID, or file:line for transcripts and configs. Each kind returns at most
+--limit results.
+
+Examples:
+  gt search "flaky TLS test"
+  gt search TLS --kind bead --rig gastown
+  gt search merge_queue --kind config
+  gt search "connection refused" --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVar(&searchRigs, "rig", nil, "Only search these rigs (repeatable; hq for town-level)")
+	searchCmd.Flags().StringSliceVar(&searchKinds, "kind", nil, "Only search these kinds: bead, transcript, config (repeatable)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", search.DefaultLimit, "Most results per kind")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	opts := search.Options{Term: args[0], Limit: searchLimit}
+	for _, k := range searchKinds {
+		kind := search.Kind(strings.TrimSpace(k))
+		if !slices.Contains(search.Kinds, kind) {
+			return fmt.Errorf("unknown kind %q (valid: bead, transcript, config)", k)
+		}
+		opts.Kinds = append(opts.Kinds, kind)
+	}
+
+	scopes, err := searchScopes(townRoot, len(opts.Kinds) == 0 || slices.Contains(opts.Kinds, search.KindBead))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results, searchErr := search.Search(ctx, scopes, opts)
+	if searchErr != nil {
+		if len(results) == 0 && ctx.Err() != nil {
+			return searchErr
+		}
+		fmt.Fprintf(os.Stderr, "%s %v\n", style.Warning.Render("⚠"), searchErr)
+	}
+
+	if searchJSON {
+		if results == nil {
+			results = []search.Result{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("(no matches)"))
+		return nil
+	}
+	var kind search.Kind
+	for _, r := range results {
+		if r.Kind != kind {
+			if kind != "" {
+				fmt.Println()
+			}
+			kind = r.Kind
+			fmt.Printf("%s\n", style.Bold.Render(strings.ToUpper(string(kind))+"S"))
+		}
+		rig := r.Rig
+		if rig == "" {
+			rig = "hq"
+		}
+		loc := r.Location
+		if rel, err := filepath.Rel(townRoot, loc); err == nil && !strings.HasPrefix(rel, "..") {
+			loc = rel
+		}
+		switch r.Kind {
+		case search.KindBead:
+			fmt.Printf("  %s %s %s\n", loc, r.Title, style.Dim.Render("["+rig+", "+r.Status+"]"))
+		default:
+			head := loc
+			if r.Title != "" {
+				head += " " + style.Dim.Render(r.Title)
+			}
+			fmt.Printf("  %s %s\n", head, style.Dim.Render("["+rig+"]"))
+		}
+		if r.Snippet != "" {
+			fmt.Printf("      %s\n", r.Snippet)
+		}
+	}
+	return nil
+}
+
+// searchScopes returns the town and each rig to search, narrowed by --rig.
+// Beads are searched only when wanted and the Dolt server is up.
+func searchScopes(townRoot string, wantBeads bool) ([]search.Scope, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	var names, rigPaths []string
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+		rigPaths = append(rigPaths, filepath.Join(townRoot, name))
+	}
+	sort.Strings(names)
+	for _, r := range searchRigs {
+		if _, ok := rigsConfig.Rigs[r]; !ok && r != "hq" {
+			return nil, fmt.Errorf("rig '%s' not found", r)
+		}
+	}
+	include := func(name string) bool { return len(searchRigs) == 0 || slices.Contains(searchRigs, name) }
+
+	databases := map[string]bool{}
+	if wantBeads {
+		if running, _, _ := doltserver.IsRunning(townRoot); running {
+			dbs, _ := doltserver.ListDatabases(townRoot)
+			for _, db := range dbs {
+				databases[db] = true
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "%s Dolt server not running; skipping beads (gt dolt start)\n", style.Warning.Render("⚠"))
+		}
+	}
+	// A rig's beads live in the database its metadata names, which may
+	// differ from the rig name.
+	scopeDB := func(name string) *search.Scope {
+		sc := &search.Scope{}
+		if db := doltserver.RigDatabase(townRoot, name); databases[db] {
+			sc.DB, _ = doltserver.DB(townRoot, db)
+		}
+		return sc
+	}
+
+	var scopes []search.Scope
+	if include("hq") {
+		sc := scopeDB("hq")
+		for _, dir := range []string{townRoot, filepath.Join(townRoot, "mayor"), filepath.Join(townRoot, "deacon")} {
+			if projectDir, err := getClaudeProjectDir(dir); err == nil {
+				sc.TranscriptGlobs = append(sc.TranscriptGlobs, filepath.Join(projectDir, "*.jsonl"))
+			}
+		}
+		sc.ConfigGlobs = []string{
+			filepath.Join(townRoot, "settings", "*.json"),
+			filepath.Join(townRoot, "settings", "*.yaml"),
+			filepath.Join(townRoot, "settings", "*.toml"),
+			filepath.Join(townRoot, "mayor", "*.json"),
+		}
+		scopes = append(scopes, *sc)
+	}
+	for _, name := range names {
+		if !include(name) {
+			continue
+		}
+		rigPath := filepath.Join(townRoot, name)
+		sc := scopeDB(name)
+		sc.Rig = name
+		sc.TranscriptGlobs = gcLogPatterns(townRoot, name, rigPath, rigPaths)
+		sc.ConfigGlobs = []string{
+			filepath.Join(rigPath, "config.json"),
+			filepath.Join(rigPath, "settings", "*.json"),
+		}
+		scopes = append(scopes, *sc)
+	}
+	return scopes, nil
+}
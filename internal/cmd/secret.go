@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var secretCmd = &cobra.Command{
+	Use:     "secret",
+	GroupID: GroupConfig,
+	Short:   "Resolve secrets referenced by agent configuration",
+	Long: `Resolve secrets referenced by agent configuration.
+
+Agent env vars in settings can reference a secret instead of a literal
+value with the form "secret:NAME":
+
+  "env": {"ANTHROPIC_API_KEY": "secret:anthropic"}
+
+When gt starts an agent it resolves the agent's secrets first and passes
+the values through the agent's environment: under tmux, via a private
+(0600) env file the startup command loads and deletes. A secret never
+appears on a command line, in ps output, or in logs. If a secret can't
+be resolved, the agent isn't started.
+
+The provider is configured in settings/config.json under "secrets":
+  env-file   KEY=VALUE file, must be mode 0600 (default: settings/secrets.env)
+  keychain   OS keychain, service "gastown" (macOS security, Linux secret-tool)
+  command    Shell command; the name is passed in GT_SECRET_NAME`,
+	RunE: requireSubcommand,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print the value of a secret",
+	Long: `Print the value of a secret from the configured provider.
+
+For scripts and shells that need a secret gt manages, such as a rig's
+Dolt password (see gt dolt users).
+
+Examples:
+  gt secret get anthropic`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretGet,
+}
+
+func init() {
+	secretCmd.AddCommand(secretGetCmd)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	value, err := config.ResolveSecret(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
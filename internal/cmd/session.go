@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/retry"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -521,13 +523,10 @@ func runSessionRestart(cmd *cobra.Command, args []string) error {
 		// Wait for session to fully terminate before starting a new one.
 		// Without this, Start may fail or create a duplicate if the old
 		// session hasn't been cleaned up by tmux yet.
-		for i := 0; i < 10; i++ {
+		retry.Poll(context.Background(), retry.Constant(10, 200*time.Millisecond), func() bool {
 			still, _ := polecatMgr.IsRunning(polecatName)
-			if !still {
-				break
-			}
-			time.Sleep(200 * time.Millisecond)
-		}
+			return !still
+		})
 	}
 
 	// Start fresh session
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -84,6 +85,14 @@ Formula-on-Bead (--on flag):
   gt sling mol-review --on gt-abc       # Apply formula to existing work
   gt sling shiny --on gt-abc crew       # Apply formula, sling to crew
 
+Issue Templates (--template flag):
+  gt sling --template bugfix gastown --var pkg=doltserver
+
+  Renders <rig>/templates/bugfix.md into a new bead (title, labels, priority,
+  acceptance criteria from its +++ TOML frontmatter; body becomes the
+  description), then slings it. A template's formula, or the rig's
+  workflow.default_formula, is applied as with --on.
+
 Compare:
   gt hook <bead>      # Just attach (no action)
   gt sling <bead>     # Attach + start now (keep context)
@@ -131,6 +140,7 @@ func init() {
 	slingCmd.Flags().StringVarP(&slingMessage, "message", "m", "", "Context message for the work")
 	slingCmd.Flags().BoolVarP(&slingDryRun, "dry-run", "n", false, "Show what would be done")
 	slingCmd.Flags().StringVar(&slingOnTarget, "on", "", "Apply formula to existing bead (implies wisp scaffolding)")
+	slingCmd.Flags().StringVar(&slingTemplate, "template", "", "Create the bead from a rig issue template (<rig>/templates/<name>.md)")
 	slingCmd.Flags().StringArrayVar(&slingVars, "var", nil, "Formula variable (key=value), can be repeated")
 	slingCmd.Flags().StringVarP(&slingArgs, "args", "a", "", "Natural language instructions for the executor (e.g., 'patch release')")
 	slingCmd.Flags().BoolVar(&slingStdin, "stdin", false, "Read --message and/or --args from stdin (avoids shell quoting issues)")
@@ -139,7 +149,7 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingCreate, "create", false, "Create polecat if it doesn't exist")
 	slingCmd.Flags().BoolVar(&slingForce, "force", false, "Force spawn even if polecat has unread mail")
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Claude Code account handle to use")
-	slingCmd.Flags().StringVar(&slingAgent, "agent", "", "Override agent/runtime for this sling (e.g., claude, gemini, codex, or custom alias)")
+	slingCmd.Flags().StringVar(&slingAgent, "agent", "", "Override agent/runtime for this sling (e.g., claude, gemini, codex, or custom alias); beats a bead's agent:<alias> label")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
 	slingCmd.Flags().BoolVar(&slingOwned, "owned", false, "Mark auto-convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
@@ -226,6 +236,28 @@ func runSling(cmd *cobra.Command, args []string) error {
 		args[i] = strings.TrimRight(args[i], "/")
 	}
 
+	// Template mode: gt sling --template <name> <target> creates the bead
+	// first, then continues as a normal (or formula-on-bead) sling.
+	if slingTemplate != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("--template takes exactly one argument: the target rig or polecat")
+		}
+		if slingOnTarget != "" {
+			return fmt.Errorf("--template and --on cannot be combined (set formula in the template instead)")
+		}
+		if err := ValidateTarget(args[0]); err != nil {
+			return err
+		}
+		templateArgs, err := slingFromTemplate(args[0])
+		if err != nil {
+			return err
+		}
+		if templateArgs == nil {
+			return nil // dry run
+		}
+		args = templateArgs
+	}
+
 	// Validate target format early, before any dispatch path (bead, formula, batch)
 	// can trigger resolveTarget side-effects like polecat spawning.
 	if len(args) > 1 {
@@ -307,6 +339,12 @@ func runSling(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("refusing to sling deferred bead %s: %q\nDeferred work should not consume polecat slots. Use --force to override", beadID, info.Title)
 	}
 
+	// Guard against slinging work whose blockers (possibly in other rigs) are
+	// still open. Use --force to override.
+	if blockers := openBeadBlockers(townRoot, beadID, info); len(blockers) > 0 && !slingForce {
+		return fmt.Errorf("refusing to sling blocked bead %s: waiting on %s\nSee 'gt deps graph %s'. Use --force to override", beadID, formatBlockers(blockers), beadID)
+	}
+
 	originalStatus := info.Status
 	originalAssignee := info.Assignee
 	force := slingForce // local copy to avoid mutating package-level flag
@@ -371,12 +409,16 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
 		target = args[1]
 	}
+	agentOverride := slingAgentFor(info)
+	if slingAgent == "" && agentOverride != "" {
+		fmt.Printf("%s Bead %s requests agent %s\n", style.Dim.Render("○"), beadID, agentOverride)
+	}
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:     slingDryRun,
 		Force:      force,
 		Create:     slingCreate,
 		Account:    slingAccount,
-		Agent:      slingAgent,
+		Agent:      agentOverride,
 		NoBoot:     slingNoBoot,
 		HookBead:   beadID,
 		BeadID:     beadID,
@@ -428,10 +470,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 
 		// Extract rig name from assignee (e.g., "gastown/polecats/Toast" -> "gastown")
-		assigneeParts := strings.Split(info.Assignee, "/")
-		if len(assigneeParts) >= 3 && assigneeParts[1] == "polecats" {
-			oldRigName := assigneeParts[0]
-			oldPolecatName := assigneeParts[2]
+		if oldAssignee, err := session.ParseAssignee(info.Assignee); err == nil && oldAssignee.Role() == session.RolePolecat {
+			oldRigName := oldAssignee.Rig()
+			oldPolecatName := oldAssignee.Name()
 
 			// Send LIFECYCLE:Shutdown to witness - will auto-nuke if clean,
 			// otherwise create cleanup wisp for manual intervention
@@ -627,6 +668,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 		AttachedMolecule: attachedMoleculeID,
 		NoMerge:          slingNoMerge,
 	}
+	if newPolecatInfo != nil {
+		fieldUpdates.Agent = spawnedAgentName(townRoot, newPolecatInfo.RigName, agentOverride)
+	}
 	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
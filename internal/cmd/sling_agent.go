@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// beadAgentLabelPrefix marks a bead label that pins the agent for its
+// polecat, e.g. "agent:claude-opus".
+const beadAgentLabelPrefix = "agent:"
+
+// beadAgentOverride returns the agent a bead asks to be worked by via an
+// "agent:<alias>" label. The "agent:" description field is not consulted:
+// sling writes it to record the agent actually spawned, so reading it back
+// would pin every re-sling to the previous run's agent. Empty means the
+// rig's role_agents apply.
+func beadAgentOverride(info *beadInfo) string {
+	if info == nil {
+		return ""
+	}
+	for _, label := range info.Labels {
+		if agent, ok := strings.CutPrefix(label, beadAgentLabelPrefix); ok && strings.TrimSpace(agent) != "" {
+			return strings.TrimSpace(agent)
+		}
+	}
+	return ""
+}
+
+// slingAgentFor returns the agent override for a polecat spawned for info:
+// --agent wins over the bead's own request.
+func slingAgentFor(info *beadInfo) string {
+	if slingAgent != "" {
+		return slingAgent
+	}
+	return beadAgentOverride(info)
+}
+
+// spawnedAgentName resolves the agent a polecat in rigName runs with, for
+// recording on its bead. Resolution errors yield the override unchanged.
+func spawnedAgentName(townRoot, rigName, override string) string {
+	rc, name, err := config.ResolveAgentConfigWithOverride(townRoot, filepath.Join(townRoot, rigName), override)
+	if err != nil {
+		return override
+	}
+	if name == "" && rc != nil {
+		name = rc.ResolvedAgent
+	}
+	return name
+}
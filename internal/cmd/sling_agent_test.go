@@ -0,0 +1,40 @@
+package cmd
+
+import "testing"
+
+func TestBeadAgentOverride(t *testing.T) {
+	tests := []struct {
+		name string
+		info *beadInfo
+		want string
+	}{
+		{"nil", nil, ""},
+		{"none", &beadInfo{Labels: []string{"gt:task"}}, ""},
+		{"label", &beadInfo{Labels: []string{"gt:task", "agent:claude-opus"}}, "claude-opus"},
+		{"recorded field ignored", &beadInfo{Description: "agent: codex\n\nFix the parser."}, ""},
+		{"label with recorded field", &beadInfo{Labels: []string{"agent:gemini"}, Description: "agent: codex"}, "gemini"},
+		{"empty label ignored", &beadInfo{Labels: []string{"agent:"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := beadAgentOverride(tt.info); got != tt.want {
+				t.Errorf("beadAgentOverride() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlingAgentForFlagWins(t *testing.T) {
+	prev := slingAgent
+	t.Cleanup(func() { slingAgent = prev })
+
+	info := &beadInfo{Labels: []string{"agent:claude-opus"}}
+	slingAgent = ""
+	if got := slingAgentFor(info); got != "claude-opus" {
+		t.Errorf("slingAgentFor() = %q, want claude-opus", got)
+	}
+	slingAgent = "codex"
+	if got := slingAgentFor(info); got != "codex" {
+		t.Errorf("slingAgentFor() = %q, want codex", got)
+	}
+}
@@ -109,6 +109,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		// Guard against slinging beads with open (possibly cross-rig) blockers.
+		if blockers := openBeadBlockers(townRoot, beadID, info); len(blockers) > 0 && !slingForce {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "blocked"})
+			fmt.Printf("  %s Skipping blocked bead %s: waiting on %s (use --force to override)\n", style.Dim.Render("✗"), beadID, formatBlockers(blockers))
+			continue
+		}
+
 		// Guard: burn existing molecules before applying new formula.
 		// Runs before polecat spawn to avoid wasted spawn/cleanup on rejected beads.
 		if formulaName != "" {
@@ -133,12 +140,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 
 		// Spawn a fresh polecat
+		agentOverride := slingAgentFor(info)
 		spawnOpts := SlingSpawnOptions{
 			Force:      slingForce,
 			Account:    slingAccount,
 			Create:     slingCreate,
 			HookBead:   beadID, // Set atomically at spawn time
-			Agent:      slingAgent,
+			Agent:      agentOverride,
 			BaseBranch: slingBaseBranch,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
@@ -227,6 +235,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Args:             slingArgs,
 			AttachedMolecule: attachedMoleculeID,
 			NoMerge:          slingNoMerge,
+			Agent:            spawnedAgentName(townRoot, rigName, agentOverride),
 		}
 		// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
 		if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
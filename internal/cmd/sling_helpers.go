@@ -76,6 +76,7 @@ type beadInfo struct {
 	Assignee     string          `json:"assignee"`
 	Description  string          `json:"description"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
+	Labels       []string        `json:"labels,omitempty"`
 }
 
 // isDeferredBead checks whether a bead should be rejected from slinging because
@@ -94,6 +95,24 @@ func isDeferredBead(info *beadInfo) bool {
 	return false
 }
 
+// openBeadBlockers returns the bead's unclosed "blocks" dependencies.
+// Cross-rig blockers are looked up through routes.jsonl.
+func openBeadBlockers(townRoot, beadID string, info *beadInfo) []beads.IssueDep {
+	if len(info.Dependencies) == 0 {
+		return nil
+	}
+	return beads.OpenBlockers(beadID, info.Dependencies, beads.New(townRoot).Show)
+}
+
+// formatBlockers renders blockers as "id (status), ...".
+func formatBlockers(blockers []beads.IssueDep) string {
+	parts := make([]string, len(blockers))
+	for i, dep := range blockers {
+		parts[i] = fmt.Sprintf("%s (%s)", dep.ID, dep.Status)
+	}
+	return strings.Join(parts, ", ")
+}
+
 // collectExistingMolecules returns all molecule wisp IDs attached to a bead.
 // Checks both dependency bonds (ground truth from bd mol bond) and the
 // description's attached_molecule field (metadata pointer). Wisp IDs are
@@ -236,6 +255,7 @@ type beadFieldUpdates struct {
 	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local"
 	ConvoyOwned      bool   // Convoy has gt:owned label (caller-managed lifecycle)
+	Agent            string // Agent the spawned polecat runs with (cost attribution)
 }
 
 // storeFieldsInBead performs a single read-modify-write to update all attachment fields
@@ -302,6 +322,9 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.ConvoyOwned {
 		fields.ConvoyOwned = true
 	}
+	if updates.Agent != "" {
+		fields.Agent = updates.Agent
+	}
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -732,12 +755,12 @@ var isHookedAgentDeadFn = isHookedAgentDead
 // Returns true if the session is confirmed dead. Returns false if alive or if we
 // can't determine liveness (conservative: don't auto-force on uncertainty).
 func isHookedAgentDead(assignee string) bool {
-	sessionName, _ := assigneeToSessionName(assignee)
-	if sessionName == "" {
+	a, err := session.ParseAssignee(assignee)
+	if err != nil {
 		return false // Unknown format, can't determine
 	}
 	t := tmux.NewTmux()
-	alive, err := t.HasSession(sessionName)
+	alive, err := t.HasSession(a.SessionName())
 	if err != nil {
 		return false // tmux not available or error, be conservative
 	}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/issuetemplate"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var slingTemplate string // --template: create the bead from a rig issue template
+
+// slingFromTemplate renders the --template issue template for target's rig,
+// creates the bead, and returns the args to continue slinging with:
+// [bead, target], or [formula, target] with slingOnTarget set when the
+// template or rig names a formula. Returns nil args on --dry-run.
+//
+// --var values used by the template are consumed; the rest are left in
+// slingVars for formula instantiation.
+func slingFromTemplate(target string) ([]string, error) {
+	rigName, _, _ := strings.Cut(target, "/")
+	if _, isRig := IsRigName(rigName); !isRig {
+		return nil, fmt.Errorf("--template needs a rig target (rig or rig/polecat), got %q", target)
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := issuetemplate.Load(r.Path, slingTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	used := tmpl.Variables()
+	var rest []string
+	for _, v := range slingVars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --var %q: expected key=value", v)
+		}
+		if slices.Contains(used, key) {
+			vars[key] = value
+		} else {
+			rest = append(rest, v)
+		}
+	}
+
+	rendered, err := tmpl.Render(vars)
+	if err != nil {
+		return nil, err
+	}
+	formula := rendered.Formula
+	if formula == "" {
+		formula = config.GetDefaultFormula(r.Path)
+	}
+
+	if slingDryRun {
+		fmt.Printf("Would create bead from template %s:\n", tmpl.Name)
+		fmt.Printf("  title:    %s\n", rendered.Title)
+		fmt.Printf("  priority: P%d\n", rendered.Priority)
+		if rendered.Type != "" {
+			fmt.Printf("  type:     %s\n", rendered.Type)
+		}
+		if len(rendered.Labels) > 0 {
+			fmt.Printf("  labels:   %s\n", strings.Join(rendered.Labels, ", "))
+		}
+		if formula != "" {
+			fmt.Printf("  formula:  %s\n", formula)
+		}
+		fmt.Printf("\n%s\n\nWould sling the new bead to %s\n", rendered.Description, target)
+		return nil, nil
+	}
+
+	if err := verifyFormulaIfSet(formula); err != nil {
+		return nil, err
+	}
+
+	bd := beads.New(r.BeadsPath())
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       rendered.Title,
+		Type:        rendered.Type,
+		Priority:    rendered.Priority,
+		Description: rendered.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating bead from template %s: %w", tmpl.Name, err)
+	}
+	if len(rendered.Labels) > 0 {
+		if err := bd.Update(issue.ID, beads.UpdateOptions{AddLabels: rendered.Labels}); err != nil {
+			return nil, fmt.Errorf("labeling %s: %w", issue.ID, err)
+		}
+	}
+	fmt.Printf("%s Created %s from template %s: %s\n", style.Bold.Render("✓"), issue.ID, tmpl.Name, rendered.Title)
+
+	slingVars = rest
+	if formula != "" {
+		slingOnTarget = issue.ID
+		return []string{formula, target}, nil
+	}
+	return []string{issue.ID, target}, nil
+}
+
+// verifyFormulaIfSet checks that a template's formula exists before any
+// bead is created, so a typo doesn't leave an orphaned bead behind.
+func verifyFormulaIfSet(formula string) error {
+	if formula == "" {
+		return nil
+	}
+	if err := verifyFormulaExists(formula); err != nil {
+		return fmt.Errorf("template formula %s: %w", formula, err)
+	}
+	return nil
+}
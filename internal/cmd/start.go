@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
@@ -77,9 +76,10 @@ To stop Gas Town, use 'gt shutdown'.`,
 }
 
 var shutdownCmd = &cobra.Command{
-	Use:     "shutdown",
-	GroupID: GroupServices,
-	Short:   "Shutdown Gas Town with cleanup",
+	Use:         "shutdown",
+	Annotations: auditAnnotations(""),
+	GroupID:     GroupServices,
+	Short:       "Shutdown Gas Town with cleanup",
 	Long: `Shutdown Gas Town by stopping agents and cleaning up polecats.
 
 This is the "done for the day" command - it stops everything AND removes
@@ -247,7 +247,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 		} else {
 			doltOK = true
 			mu.Lock()
-			fmt.Printf("  %s Dolt server started (port %d)\n", style.Bold.Render("✓"), doltserver.DefaultPort)
+			fmt.Printf("  %s Dolt server started (port %d)\n", style.Bold.Render("✓"), doltserver.DefaultConfig(townRoot).Port)
 			mu.Unlock()
 		}
 	}()
@@ -471,8 +471,20 @@ func startOrRestartCrewMember(t *tmux.Tmux, r *rig.Rig, crewName, townRoot strin
 				Sender:    "human",
 				Topic:     "restart",
 			})
-			agentCmd := config.BuildCrewStartupCommand(r.Name, crewName, r.Path, beacon)
-			if err := t.SendKeys(sessionID, agentCmd); err != nil {
+			// Respawn rather than type into the shell, so the secrets
+			// reach the agent's environment without being typed.
+			secrets, err := config.StartupSecrets("crew", townRoot, r.Path, "")
+			var startCmd, paneID string
+			if err == nil {
+				startCmd, err = t.WithSecrets(config.BuildCrewStartupCommand(r.Name, crewName, r.Path, beacon), secrets)
+			}
+			if err == nil {
+				paneID, err = t.GetPaneID(sessionID)
+			}
+			if err == nil {
+				err = t.RespawnPane(paneID, startCmd)
+			}
+			if err != nil {
 				return fmt.Sprintf("  %s %s/%s restart failed: %v\n", style.Dim.Render("○"), r.Name, crewName, err), false
 			}
 			return fmt.Sprintf("  %s %s/%s agent restarted\n", style.Bold.Render("✓"), r.Name, crewName), true
@@ -544,11 +556,15 @@ func runShutdown(cmd *cobra.Command, args []string) error {
 
 	// Confirmation prompt
 	if !shutdownYes && !shutdownForce {
-		fmt.Printf("Proceed with shutdown? [y/N] ")
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "y" && response != "yes" {
+		ok, err := confirm(confirmPrompt{
+			ID:       PromptShutdown,
+			Question: "Proceed with shutdown?",
+			Safe:     true,
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println("Shutdown canceled.")
 			return nil
 		}
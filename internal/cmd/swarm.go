@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/swarm"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -474,6 +475,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 	// Use gt sling to spawn a fresh polecat and assign the task
 	slingCmd := exec.Command("gt", "sling", task.ID, foundRig.Name)
 	slingCmd.Dir = townRoot
+	slingCmd.Env = telemetry.WithTraceparent(nil)
 	slingCmd.Stdout = os.Stdout
 	slingCmd.Stderr = os.Stderr
 
@@ -514,6 +516,7 @@ func spawnSwarmWorkersFromBeads(r *rig.Rig, townRoot string, swarmID string, wor
 		// Use gt sling to assign task to worker (this updates beads)
 		slingCmd := exec.Command("gt", "sling", task.ID, fmt.Sprintf("%s/%s", r.Name, worker))
 		slingCmd.Dir = townRoot
+		slingCmd.Env = telemetry.WithTraceparent(nil)
 		if err := slingCmd.Run(); err != nil {
 			style.PrintWarning("  couldn't sling %s to %s: %v", task.ID, worker, err)
 
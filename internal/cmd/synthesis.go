@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -611,6 +612,7 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 func slingSynthesis(beadID, targetRig string) error {
 	slingArgs := []string{"sling", beadID, targetRig}
 	slingCmd := exec.Command("gt", slingArgs...)
+	slingCmd.Env = telemetry.WithTraceparent(nil)
 	slingCmd.Stdout = os.Stdout
 	slingCmd.Stderr = os.Stderr
 
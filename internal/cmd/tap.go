@@ -16,7 +16,7 @@ flow to guard, audit, inject, or check.
 Subcommands:
   guard   - Block forbidden operations (PreToolUse, exit 2)
   audit   - Log/record tool executions (PostToolUse) [planned]
-  inject  - Modify tool inputs (PreToolUse, updatedInput) [planned]
+  inject  - Modify tool inputs (PreToolUse, updatedInput)
   check   - Validate after execution (PostToolUse) [planned]
 
 Hook configuration in .claude/settings.json:
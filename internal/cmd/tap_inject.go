@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var tapInjectCmd = &cobra.Command{
+	Use:   "inject",
+	Short: "Modify tool inputs (PreToolUse hook)",
+	Long: `Modify tool inputs via Claude Code PreToolUse hooks.
+
+Inject commands read the hook payload on stdin and, when they have
+something to add, print a PreToolUse response whose updatedInput replaces
+the tool's input. They never block: on any problem the tool runs as-is.
+
+Available injections:
+  bead-trailer     - Add a "Bead: <id>" trailer to git commits
+
+Example hook configuration:
+  {
+    "PreToolUse": [{
+      "matcher": "Bash(git commit*)",
+      "hooks": [{"command": "gt tap inject bead-trailer"}]
+    }]
+  }`,
+}
+
+var tapInjectBeadTrailerCmd = &cobra.Command{
+	Use:   "bead-trailer",
+	Short: "Add a Bead trailer to git commits",
+	Long: `Add a "Bead: <id>" trailer to git commit commands run by agents.
+
+The bead is the agent's current work, found from (in order) GT_ISSUE,
+a polecat/<worker>/<bead> branch, or the agent bead's hook_bead. When no
+bead is found, or the command already names it, the commit runs unchanged.
+
+The trailer lets 'gt changelog' tie each commit to the bead it implements.
+Commits made before injection was enabled can be tagged with
+'gt changelog backfill'.
+
+This hook is installed for crew and polecats by default.`,
+	RunE: runTapInjectBeadTrailer,
+}
+
+func init() {
+	tapCmd.AddCommand(tapInjectCmd)
+	tapInjectCmd.AddCommand(tapInjectBeadTrailerCmd)
+}
+
+// preToolUseInput is the part of a PreToolUse hook payload injections read.
+type preToolUseInput struct {
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input"`
+}
+
+func runTapInjectBeadTrailer(cmd *cobra.Command, args []string) error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil
+	}
+	var input preToolUseInput
+	if err := json.Unmarshal(data, &input); err != nil || input.ToolName != "Bash" {
+		return nil
+	}
+	command, _ := input.ToolInput["command"].(string)
+	if !gitCommitPattern.MatchString(command) {
+		return nil
+	}
+
+	beadID := currentWorkBead()
+	updated := injectBeadTrailer(command, beadID)
+	if updated == command {
+		return nil
+	}
+	input.ToolInput["command"] = updated
+
+	out, err := json.Marshal(map[string]interface{}{
+		"hookSpecificOutput": map[string]interface{}{
+			"hookEventName":            "PreToolUse",
+			"permissionDecision":       "allow",
+			"permissionDecisionReason": "gt: tagged commit with Bead: " + beadID,
+			"updatedInput":             input.ToolInput,
+		},
+	})
+	if err != nil {
+		return nil
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// gitCommitPattern matches each "git commit" invocation in a shell command.
+var gitCommitPattern = regexp.MustCompile(`(^|[\s;&|(])(git\s+commit)(\s|$)`)
+
+// injectBeadTrailer adds a --trailer for beadID to every git commit in
+// command, skipping any that appear inside quotes (e.g. in a message).
+// Commands that already carry a Bead trailer are left alone.
+func injectBeadTrailer(command, beadID string) string {
+	if beadID == "" || strings.Contains(command, changelog.TrailerKey+":") {
+		return command
+	}
+	trailer := fmt.Sprintf(" --trailer '%s: %s'", changelog.TrailerKey, beadID)
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range gitCommitPattern.FindAllStringSubmatchIndex(command, -1) {
+		end := m[5] // end of "git commit"
+		if quotedAt(command, m[4]) {
+			continue
+		}
+		sb.WriteString(command[last:end])
+		sb.WriteString(trailer)
+		last = end
+	}
+	sb.WriteString(command[last:])
+	return sb.String()
+}
+
+// quotedAt reports whether byte offset i of a shell command is inside
+// single or double quotes.
+func quotedAt(command string, i int) bool {
+	var quote byte
+	for j := 0; j < i; j++ {
+		switch c := command[j]; {
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == c:
+			quote = 0
+		case c == '\\' && quote != '\'':
+			j++
+		}
+	}
+	return quote != 0
+}
+
+// currentWorkBead returns the bead the calling agent is working on, or ""
+// if it can't tell.
+func currentWorkBead() string {
+	if issue := os.Getenv("GT_ISSUE"); issue != "" {
+		return issue
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if branch, err := git.NewGit(cwd).CurrentBranch(); err == nil && strings.HasPrefix(branch, constants.BranchPolecatPrefix) {
+		if info := parseBranchName(branch); info.Issue != "" {
+			return info.Issue
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return ""
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return ""
+	}
+	agentBeadID := getAgentBeadID(RoleContext{
+		Role:     roleInfo.Role,
+		Rig:      roleInfo.Rig,
+		Polecat:  roleInfo.Polecat,
+		TownRoot: townRoot,
+		WorkDir:  cwd,
+	})
+	return getIssueFromAgentHook(beads.New(beads.ResolveBeadsDir(cwd)), agentBeadID)
+}
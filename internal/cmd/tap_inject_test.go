@@ -0,0 +1,51 @@
+package cmd
+
+import "testing"
+
+func TestInjectBeadTrailer(t *testing.T) {
+	tests := []struct {
+		name, command, want string
+	}{
+		{
+			name:    "plain commit",
+			command: `git commit -m "fix: crash"`,
+			want:    `git commit --trailer 'Bead: gt-abc' -m "fix: crash"`,
+		},
+		{
+			name:    "compound command",
+			command: `git add -A && git commit -m 'wip' && git push`,
+			want:    `git add -A && git commit --trailer 'Bead: gt-abc' -m 'wip' && git push`,
+		},
+		{
+			name:    "git commit inside the message",
+			command: `git commit -m "teach git commit hooks new tricks"`,
+			want:    `git commit --trailer 'Bead: gt-abc' -m "teach git commit hooks new tricks"`,
+		},
+		{
+			name:    "already has a trailer",
+			command: `git commit -m "fix" --trailer "Bead: gt-xyz"`,
+			want:    `git commit -m "fix" --trailer "Bead: gt-xyz"`,
+		},
+		{
+			name:    "not a commit",
+			command: `git commit-tree HEAD^{tree}`,
+			want:    `git commit-tree HEAD^{tree}`,
+		},
+		{
+			name:    "bare commit",
+			command: `git commit`,
+			want:    `git commit --trailer 'Bead: gt-abc'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := injectBeadTrailer(tt.command, "gt-abc"); got != tt.want {
+				t.Errorf("injectBeadTrailer(%q) =\n  %q\nwant\n  %q", tt.command, got, tt.want)
+			}
+		})
+	}
+
+	if got := injectBeadTrailer(`git commit -m x`, ""); got != `git commit -m x` {
+		t.Errorf("no bead: got %q", got)
+	}
+}
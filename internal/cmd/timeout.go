@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var timeoutFlag time.Duration // --timeout
+
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 0,
+		"Limit every git, bd, dolt, and tmux subprocess to this long, replacing the per-tool defaults (0 disables limits)")
+}
+
+// applyTimeoutFlag installs --timeout as the subprocess timeout override.
+// Without the flag each subprocess keeps its own default (see util.GitTimeout
+// and friends). Commands with their own --timeout flag (deacon
+// trigger-pending, rig shutdown, ...) shadow the global one, so it only
+// counts when the root's flag was set. Otherwise any override left by an
+// earlier command run in the same process is cleared.
+func applyTimeoutFlag(cmd *cobra.Command) {
+	if cmd.Root().PersistentFlags().Changed("timeout") {
+		util.SetTimeoutOverride(timeoutFlag)
+	} else {
+		util.ClearTimeoutOverride()
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townbackup"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Town backup flags
+var (
+	townBackupOutput        string
+	townBackupSkipPreflight bool
+	townRestoreSkipClones   bool
+)
+
+var townBackupCmd = &cobra.Command{
+	Use:         "backup",
+	Annotations: auditAnnotations(""),
+	Short:       "Snapshot the town, except repository clones, into one archive",
+	Long: `Write a single archive holding everything needed to rebuild this town
+except its repository clones:
+
+  - town and rig config: mayor/ (town.json, rigs.json), settings/, and
+    each rig's config.json and settings
+  - .beads metadata and routes
+  - every .dolt-data database, captured with a Dolt backup (all branches
+    and history)
+  - agent directories, overlays, and other runtime state
+  - your ~/.gt hooks config (hooks-base.json, hooks-overrides/)
+
+Clones (mayor/rig, refinery/rig, crew workers, .repo.git) and polecat
+worktrees are left out. The archive's manifest.json records each rig's git
+URL, default branch, and crew workers so 'gt town restore' can clone them
+again.
+
+The Dolt server may be running. A remote Dolt server is refused: back up
+its databases on its own machine.
+
+A preflight first checks that dolt is installed and that the temporary
+staging directory and the archive's filesystem have room for the databases
+and files, and aborts with a report if not (--skip-preflight bypasses it).
+
+Examples:
+  gt town backup
+  gt town backup --output /backups/town-$(date +%F).tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: runTownBackup,
+}
+
+var townRestoreCmd = &cobra.Command{
+	Use:         "restore <archive> <town-dir>",
+	Annotations: auditAnnotations(""),
+	Short:       "Rebuild a town from a 'gt town backup' archive",
+	Long: `Rebuild a town in <town-dir>, which must be empty or not exist, from an
+archive written by 'gt town backup'.
+
+Files and Dolt databases are restored first, then each local rig's clones
+are made again from its git URL: .repo.git, mayor/rig, refinery/rig, and
+every crew worker (on its crew/<name> branch if it had one). A rig that
+fails to clone is reported and skipped; rerun 'gt rig add' or 'gt crew add'
+for it once the cause is fixed. Remote rigs are not cloned.
+
+~/.gt hooks files from the archive are written only where you don't
+already have one.
+
+Start the Dolt server afterwards with 'gt dolt start'.
+
+Examples:
+  gt town restore town-backup.tar.gz ~/gt
+  gt town restore town-backup.tar.gz ~/gt --skip-clones`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTownRestore,
+}
+
+func init() {
+	townBackupCmd.Flags().StringVarP(&townBackupOutput, "output", "o", "", "Archive path (default: gt-town-<timestamp>.tar.gz in the current directory)")
+	townBackupCmd.Flags().BoolVar(&townBackupSkipPreflight, "skip-preflight", false, "Skip the dolt and free-disk checks")
+	townRestoreCmd.Flags().BoolVar(&townRestoreSkipClones, "skip-clones", false, "Restore files and databases only; don't clone rigs")
+
+	townCmd.AddCommand(townBackupCmd)
+	townCmd.AddCommand(townRestoreCmd)
+}
+
+func runTownBackup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	output := townBackupOutput
+	if output == "" {
+		output = fmt.Sprintf("gt-town-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	}
+	output, err = filepath.Abs(output)
+	if err != nil {
+		return fmt.Errorf("resolving output path: %w", err)
+	}
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("%s already exists", output)
+	}
+
+	// Write next to the destination and rename, so a failed backup never
+	// leaves a truncated archive behind.
+	tmp := output + ".tmp"
+	if !townBackupSkipPreflight {
+		report := townBackupPreflight(townRoot, output, []string{output, tmp})
+		printPreflight(os.Stdout, report)
+		if err := report.Err(); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	manifest, err := townbackup.Backup(townRoot, f, townbackup.BackupOptions{
+		GTVersion: Version,
+		Exclude:   []string{output, tmp},
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("backing up town: %w", err)
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	fmt.Printf("%s Backed up %s to %s\n", style.Success.Render("✓"), townRoot, output)
+	fmt.Printf("  %d files, %d database(s), %d rig(s)\n", manifest.Files, len(manifest.Databases), len(manifest.Rigs))
+	return nil
+}
+
+func runTownRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := filepath.Abs(args[1])
+	if err != nil {
+		return fmt.Errorf("resolving town path: %w", err)
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	result, err := townbackup.Restore(f, townRoot, townbackup.RestoreOptions{SkipClones: townRestoreSkipClones})
+	if err != nil {
+		return fmt.Errorf("restoring town: %w", err)
+	}
+
+	m := result.Manifest
+	fmt.Printf("%s Restored %s from backup of %s taken %s\n", style.Success.Render("✓"),
+		townRoot, m.TownRoot, m.CreatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  %d files, %d database(s)\n", m.Files, len(m.Databases))
+	for _, rel := range result.HomeSkipped {
+		fmt.Printf("  %s kept existing ~/.gt/%s\n", style.Dim.Render("ℹ"), rel)
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), w)
+	}
+
+	fmt.Println()
+	fmt.Printf("Next: cd %s && gt dolt start\n", townRoot)
+	if len(result.Warnings) > 0 {
+		return fmt.Errorf("%d rig(s) could not be recloned", len(result.Warnings))
+	}
+	return nil
+}
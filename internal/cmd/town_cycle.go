@@ -41,7 +41,7 @@ func init() {
 var townCmd = &cobra.Command{
 	Use:   "town",
 	Short: "Town-level operations",
-	Long:  `Commands for town-level operations including session cycling.`,
+	Long:  `Commands for town-level operations including session cycling and backup.`,
 }
 
 var townNextCmd = &cobra.Command{
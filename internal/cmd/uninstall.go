@@ -4,11 +4,9 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/shell"
@@ -23,9 +21,10 @@ var (
 )
 
 var uninstallCmd = &cobra.Command{
-	Use:     "uninstall",
-	GroupID: GroupConfig,
-	Short:   "Remove Gas Town from the system",
+	Use:         "uninstall",
+	Annotations: auditAnnotations(""),
+	GroupID:     GroupConfig,
+	Short:       "Remove Gas Town from the system",
 	Long: `Completely remove Gas Town from the system.
 
 By default, removes:
@@ -72,13 +71,17 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Println()
-		fmt.Print("Continue? [y/N] ")
-
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-
-		if response != "y" && response != "yes" {
+		// Deleting the workspace can't be undone, so --yes doesn't cover it.
+		ok, err := confirm(confirmPrompt{
+			ID:       PromptUninstall,
+			Question: "Continue?",
+			Safe:     !uninstallWorkspace,
+			Hint:     "pass --force to uninstall without confirmation",
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Println("Aborted.")
 			return nil
 		}
@@ -184,7 +184,7 @@ func runUp(cmd *cobra.Command, args []string) error {
 			doltDetail = err.Error()
 		} else {
 			doltOK = true
-			doltDetail = fmt.Sprintf("started (port %d)", doltserver.DefaultPort)
+			doltDetail = fmt.Sprintf("started (port %d)", doltserver.DefaultConfig(townRoot).Port)
 		}
 	}()
 
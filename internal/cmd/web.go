@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/controlapi"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/web"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	webListen    string
+	webTokenFile string
+)
+
+var webCmd = &cobra.Command{
+	Use:     "web",
+	GroupID: GroupDiag,
+	Short:   "Serve a read-only town dashboard over HTTP",
+	Long: `Serve a minimal web dashboard of the town: a card per rig with its
+agents and merge queue, Dolt health, and open escalations. Handy for
+teammates who want visibility without shelling into the box.
+
+The dashboard reads everything from the daemon's control API, so the
+daemon must be running (gt daemon start). It refreshes every 30 seconds;
+GET /api/town returns the same data as JSON.
+
+The dashboard is read-only unless a token is configured, with the
+GT_WEB_TOKEN environment variable or --token-file. Then rig start/stop and
+polecat nudge buttons appear, and POST /api/action requires the token as
+a bearer token:
+
+  curl -H "Authorization: Bearer $GT_WEB_TOKEN" -d '{"method":"rig.stop","params":{"rig":"gastown"}}' localhost:8080/api/action
+
+By default the server listens on localhost only. Use --listen :8080 to
+serve the team; the token then travels in clear text, so put the
+dashboard behind TLS if actions are enabled.
+
+For the full convoy and mail dashboard, see 'gt dashboard'.
+
+Examples:
+  gt web                                  # Read-only, localhost:8080
+  gt web --listen :8080                   # Read-only, all interfaces
+  GT_WEB_TOKEN=$(openssl rand -hex 16) gt web
+  gt web --token-file ~/.config/gt/web-token`,
+	Args: cobra.NoArgs,
+	RunE: runWeb,
+}
+
+func init() {
+	webCmd.Flags().StringVar(&webListen, "listen", "localhost:8080", "Address to listen on")
+	webCmd.Flags().StringVar(&webTokenFile, "token-file", "", "File holding the token that enables actions (default $GT_WEB_TOKEN)")
+	rootCmd.AddCommand(webCmd)
+}
+
+func runWeb(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	token := os.Getenv("GT_WEB_TOKEN")
+	if webTokenFile != "" {
+		data, err := os.ReadFile(webTokenFile)
+		if err != nil {
+			return fmt.Errorf("reading token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return fmt.Errorf("token file %s is empty", webTokenFile)
+		}
+	}
+
+	socket := daemon.ControlAPISocket(townRoot, daemon.LoadPatrolConfig(townRoot))
+	if _, err := os.Stat(socket); err != nil {
+		return fmt.Errorf("control API socket %s not found (start the daemon with: gt daemon start)", socket)
+	}
+	handler, err := web.NewTownHandler(controlapi.Caller{Path: socket}, token)
+	if err != nil {
+		return fmt.Errorf("creating dashboard handler: %w", err)
+	}
+
+	mode := "read-only"
+	if token != "" {
+		mode = "actions enabled"
+	}
+	addr := webListen
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	fmt.Printf("Serving town dashboard at http://%s (%s) • ctrl+c to stop\n", addr, mode)
+
+	server := &http.Server{
+		Addr:              webListen,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      3 * time.Minute, // actions wait for gt to finish
+		IdleTimeout:       120 * time.Second,
+	}
+	return server.ListenAndServe()
+}
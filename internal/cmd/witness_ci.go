@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// ciPauseReasonPrefix starts the reason of merge queue pauses made by
+// gt witness ci-check, so a green branch only resumes its own pause.
+const ciPauseReasonPrefix = "CI red on "
+
+var (
+	witnessCIAll    bool
+	witnessCIJSON   bool
+	witnessCIDryRun bool
+)
+
+var witnessCICheckCmd = &cobra.Command{
+	Use:   "ci-check [rig]",
+	Short: "Check external CI on the rig's default branch",
+	Long: `Poll external CI for the rig's default branch and react when it changes.
+
+When the branch goes red the witness opens a bug bead labeled ci:red in the
+rig, pauses the merge queue so nothing merges on top of the breakage, and
+files an escalation routed per settings/escalation.json. When the branch
+passes again the bead is closed and the merge queue resumed (unless someone
+else paused it). The red state is kept in the wisp layer, so each breakage
+is reported once.
+
+Configure per rig in settings/config.json:
+
+  "ci": {
+    "provider": "github",          # or "buildkite"
+    "repo": "owner/name",          # github; default: from the rig's git_url
+    "workflow": "ci.yml",          # github; default: all workflows
+    "org": "acme",                 # buildkite
+    "pipeline": "web",             # buildkite (needs BUILDKITE_API_TOKEN)
+    "branch": "main",              # default: the rig's default branch
+    "severity": "high",
+    "pause_merge_queue": true
+  }
+
+GitHub is read through the gh CLI, which must be authenticated. The
+ci-status plugin runs gt witness ci-check --all as a daemon patrol.
+
+Examples:
+  gt witness ci-check gastown
+  gt witness ci-check --all
+  gt witness ci-check gastown --dry-run --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWitnessCICheck,
+}
+
+func init() {
+	witnessCICheckCmd.Flags().BoolVar(&witnessCIAll, "all", false, "Check every rig with CI configured")
+	witnessCICheckCmd.Flags().BoolVar(&witnessCIJSON, "json", false, "Output as JSON")
+	witnessCICheckCmd.Flags().BoolVar(&witnessCIDryRun, "dry-run", false, "Report the CI status without opening beads, pausing or escalating")
+
+	witnessCmd.AddCommand(witnessCICheckCmd)
+}
+
+// CICheckResult is the outcome of checking one rig's CI.
+type CICheckResult struct {
+	Rig        string               `json:"rig"`
+	Branch     string               `json:"branch"`
+	Run        *witness.CIRun       `json:"run,omitempty"`
+	Transition witness.CITransition `json:"transition,omitempty"`
+	Bead       string               `json:"bead,omitempty"`
+	Paused     bool                 `json:"paused,omitempty"`
+	Resumed    bool                 `json:"resumed,omitempty"`
+	Escalated  bool                 `json:"escalated,omitempty"`
+	Errors     []string             `json:"errors,omitempty"`
+}
+
+func runWitnessCICheck(cmd *cobra.Command, args []string) error {
+	if witnessCIAll == (len(args) == 1) {
+		return fmt.Errorf("specify a rig or --all")
+	}
+
+	var townRoot string
+	var rigs []*rig.Rig
+	if witnessCIAll {
+		all, root, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		townRoot, rigs = root, all
+		sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	} else {
+		root, r, err := getRig(args[0])
+		if err != nil {
+			return err
+		}
+		townRoot, rigs = root, []*rig.Rig{r}
+	}
+
+	var results []*CICheckResult
+	for _, r := range rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+		if err != nil && !errors.Is(err, config.ErrNotFound) {
+			if !witnessCIAll {
+				return fmt.Errorf("loading rig settings: %w", err)
+			}
+			results = append(results, &CICheckResult{Rig: r.Name, Errors: []string{err.Error()}})
+			continue
+		}
+		if settings == nil || settings.CI == nil {
+			if !witnessCIAll {
+				return fmt.Errorf("no CI configured for %s: add a \"ci\" section to its settings/config.json", r.Name)
+			}
+			continue
+		}
+		results = append(results, checkRigCI(townRoot, r, settings.CI, witnessCIDryRun))
+	}
+
+	if witnessCIJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	if len(results) == 0 {
+		fmt.Println("No rigs have CI monitoring configured.")
+		return nil
+	}
+	for _, res := range results {
+		printCICheckResult(res)
+	}
+	return nil
+}
+
+// checkRigCI fetches the rig's CI status and, unless dryRun, acts on a
+// change: opening or closing the ci:red bead, pausing or resuming the
+// merge queue, and escalating new breakage.
+func checkRigCI(townRoot string, r *rig.Rig, ci *config.CIConfig, dryRun bool) *CICheckResult {
+	res := &CICheckResult{Rig: r.Name, Branch: ci.Branch}
+	if res.Branch == "" {
+		res.Branch = r.DefaultBranch()
+	}
+	repo := ci.Repo
+	if repo == "" {
+		repo = witness.GitHubRepoFromURL(r.GitURL)
+	}
+
+	run, err := witness.FetchCIStatus(context.Background(), ci, repo, res.Branch)
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+		return res
+	}
+	res.Run = run
+	red := witness.LoadCIRed(townRoot, r.Name)
+	res.Transition = witness.NextCITransition(red, run)
+	if red != nil {
+		res.Bead = red.Bead
+	}
+	if dryRun {
+		return res
+	}
+
+	addErr := func(err error) {
+		if err != nil {
+			res.Errors = append(res.Errors, err.Error())
+		}
+	}
+	by := r.Name + "/witness"
+	switch res.Transition {
+	case witness.CIWentRed:
+		severity := ci.EffectiveSeverity()
+		bead, err := witness.CreateCIRedBead(r.Path, r.Name, res.Branch, severity, run)
+		addErr(err)
+		res.Bead = bead
+		addErr(witness.SaveCIRed(townRoot, r.Name, &witness.CIRedState{
+			Since: time.Now(), Commit: run.Commit, Bead: bead, URL: run.URL,
+		}))
+		if ci.ShouldPauseMergeQueue() && refinery.QueuePaused(townRoot, r.Name) == nil {
+			_, err := refinery.PauseQueue(townRoot, r.Name, by, ciPauseReasonPrefix+res.Branch+": "+run.URL)
+			addErr(err)
+			res.Paused = err == nil
+		}
+		err = escalateCIRed(townRoot, r.Name, res.Branch, severity, bead, run, res.Paused)
+		addErr(err)
+		res.Escalated = err == nil
+
+	case witness.CIWentGreen:
+		if red.Bead != "" {
+			addErr(witness.CloseCIRedBead(r.Path, red.Bead, run))
+		}
+		if p := refinery.QueuePaused(townRoot, r.Name); p != nil && p.By == by && strings.HasPrefix(p.Reason, ciPauseReasonPrefix) {
+			_, err := refinery.ResumeQueue(townRoot, r.Name)
+			addErr(err)
+			res.Resumed = err == nil
+		}
+		addErr(witness.ClearCIRed(townRoot, r.Name))
+	}
+	return res
+}
+
+// escalateCIRed files the breakage through gt escalate, so it follows the
+// town's escalation routes for its severity.
+func escalateCIRed(townRoot, rigName, branch, severity, bead string, run *witness.CIRun, paused bool) error {
+	reason := fmt.Sprintf("CI run %s failed on %s's %s at %s.\n%s\n",
+		run.Name, rigName, branch, run.ShortCommit(), run.URL)
+	if paused {
+		reason += fmt.Sprintf("\nThe merge queue is paused until CI passes again (or: gt mq resume %s).", rigName)
+	}
+	args := []string{"escalate", fmt.Sprintf("CI red on %s/%s", rigName, branch),
+		"--severity", severity, "--source", "ci:" + rigName, "--reason", reason}
+	if bead != "" {
+		args = append(args, "--related", bead)
+	}
+	cmd := exec.Command("gt", args...)
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("escalating: %v (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func printCICheckResult(res *CICheckResult) {
+	if res.Run == nil {
+		fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), res.Rig, strings.Join(res.Errors, "; "))
+		return
+	}
+
+	commit := ""
+	if c := res.Run.ShortCommit(); c != "" {
+		commit = " " + style.Dim.Render(c)
+	}
+	switch res.Transition {
+	case witness.CIStillGreen:
+		icon := style.Success.Render("✓")
+		if res.Run.Status == witness.CIUnknown {
+			icon = style.Dim.Render("○")
+		}
+		fmt.Printf("%s %s/%s: %s%s\n", icon, res.Rig, res.Branch, res.Run.Status, commit)
+	case witness.CIWentRed:
+		fmt.Printf("%s %s/%s: went red%s\n", style.Error.Render("✗"), res.Rig, res.Branch, commit)
+	case witness.CIStillRed:
+		fmt.Printf("%s %s/%s: still red%s\n", style.Error.Render("✗"), res.Rig, res.Branch, commit)
+	case witness.CIWentGreen:
+		fmt.Printf("%s %s/%s: green again%s\n", style.Success.Render("✓"), res.Rig, res.Branch, commit)
+	}
+	if res.Run.URL != "" && res.Transition != witness.CIStillGreen {
+		fmt.Printf("    %s\n", style.Dim.Render(res.Run.URL))
+	}
+
+	var actions []string
+	if res.Bead != "" && (res.Transition == witness.CIWentRed || res.Transition == witness.CIStillRed) {
+		actions = append(actions, "bead "+res.Bead)
+	}
+	if res.Transition == witness.CIWentGreen && res.Bead != "" {
+		actions = append(actions, "closed "+res.Bead)
+	}
+	if res.Paused {
+		actions = append(actions, "merge queue paused")
+	}
+	if res.Resumed {
+		actions = append(actions, "merge queue resumed")
+	}
+	if res.Escalated {
+		actions = append(actions, "escalated")
+	}
+	if len(actions) > 0 {
+		fmt.Printf("    %s\n", strings.Join(actions, ", "))
+	}
+	for _, e := range res.Errors {
+		fmt.Printf("    %s %s\n", style.Warning.Render("⚠"), e)
+	}
+}
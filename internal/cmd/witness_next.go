@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	witnessNextLimit   int
+	witnessNextJSON    bool
+	witnessNextExplain bool
+	witnessNextPolicy  string
+)
+
+var witnessNextCmd = &cobra.Command{
+	Use:   "next <rig>",
+	Short: "Show the next issues to give idle polecats",
+	Long: `Rank the rig's ready, unassigned issues by its scheduling policy.
+
+The Witness uses the top entry when an idle polecat needs work:
+  gt sling <issue> <rig>
+
+Policies (rig settings/config.json "scheduling.policy"):
+  priority  Priority first, oldest first within a priority (default)
+  fifo      Oldest first
+  weighted  Score by priority, age, deadline, and size
+
+Weighted scoring reads optional bead labels:
+  size:S|M|L|XL|<points>   Smaller issues score higher
+  deadline:2026-11-01      Near or past deadlines score higher
+  weight:2                 Multiplies the issue's score
+
+  "scheduling": {
+    "policy": "weighted",
+    "weights": {"priority": 10, "age": 1, "deadline": 8, "size": 1}
+  }
+
+Examples:
+  gt witness next gastown
+  gt witness next gastown --limit 1 --json
+  gt witness next gastown --policy weighted --explain`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessNext,
+}
+
+func init() {
+	witnessNextCmd.Flags().IntVarP(&witnessNextLimit, "limit", "n", 10, "Maximum issues to show (0 = all)")
+	witnessNextCmd.Flags().BoolVar(&witnessNextJSON, "json", false, "Output as JSON")
+	witnessNextCmd.Flags().BoolVar(&witnessNextExplain, "explain", false, "Show how each weighted score was computed")
+	witnessNextCmd.Flags().StringVar(&witnessNextPolicy, "policy", "", "Override the rig's scheduling policy (priority, fifo, weighted)")
+
+	witnessCmd.AddCommand(witnessNextCmd)
+}
+
+func runWitnessNext(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+
+	var scheduling *config.SchedulingConfig
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	switch {
+	case err == nil:
+		scheduling = settings.Scheduling
+	case !errors.Is(err, config.ErrNotFound):
+		return fmt.Errorf("loading rig settings: %w", err)
+	}
+	if witnessNextPolicy != "" {
+		override := config.SchedulingConfig{Policy: witnessNextPolicy}
+		if scheduling != nil {
+			override.Weights = scheduling.Weights
+		}
+		if err := override.Validate(); err != nil {
+			return err
+		}
+		scheduling = &override
+	}
+
+	issues, err := readyRigIssues(r)
+	if err != nil {
+		return fmt.Errorf("listing ready work: %w", err)
+	}
+	ranked := witness.RankReadyWork(schedulableIssues(issues), scheduling, time.Now())
+	if witnessNextLimit > 0 && len(ranked) > witnessNextLimit {
+		ranked = ranked[:witnessNextLimit]
+	}
+
+	if witnessNextJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ranked)
+	}
+
+	if len(ranked) == 0 {
+		fmt.Printf("No ready work in %s.\n", r.Name)
+		return nil
+	}
+
+	policy := scheduling.EffectivePolicy()
+	fmt.Printf("%s Next work for %s (policy: %s)\n\n", style.Bold.Render("📋"), r.Name, policy)
+	for i, s := range ranked {
+		score := ""
+		if policy == config.SchedulingWeighted {
+			score = style.Dim.Render(fmt.Sprintf(" score %.2f", s.Score))
+		}
+		fmt.Printf("  %d. [P%d] %s %s%s\n", i+1, s.Issue.Priority, style.Dim.Render(s.Issue.ID), s.Issue.Title, score)
+		if witnessNextExplain && len(s.Reasons) > 0 {
+			fmt.Printf("     %s\n", style.Dim.Render(strings.Join(s.Reasons, ", ")))
+		}
+	}
+	return nil
+}
+
+// schedulableIssues drops ready issues a polecat shouldn't be handed:
+// already-assigned work and merge requests (owned by the refinery).
+func schedulableIssues(issues []*beads.Issue) []*beads.Issue {
+	var out []*beads.Issue
+	for _, issue := range issues {
+		if issue.Assignee != "" || beads.HasLabel(issue, "gt:merge-request") {
+			continue
+		}
+		out = append(out, issue)
+	}
+	return out
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// AgentCapabilityTTL is how long a cached binary resolution is trusted before
+// LookupAgentBinary searches PATH again.
+const AgentCapabilityTTL = time.Hour
+
+// agentVersionTimeout bounds each `<agent> --version` probe during a refresh.
+const agentVersionTimeout = 5 * time.Second
+
+// AgentCapability records where an agent binary resolved on this machine and
+// which version it reported.
+type AgentCapability struct {
+	Command   string    `json:"command"`
+	Path      string    `json:"path,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	// SearchPath fingerprints the PATH the binary was resolved against;
+	// a different PATH invalidates the entry.
+	SearchPath string `json:"search_path,omitempty"`
+}
+
+// Available reports whether the binary was found.
+func (c *AgentCapability) Available() bool {
+	return c != nil && c.Path != ""
+}
+
+// fresh reports whether c is a usable cache entry for the current PATH.
+func (c *AgentCapability) fresh() bool {
+	return c.Available() && c.SearchPath == searchPathFingerprint() && time.Since(c.CheckedAt) < AgentCapabilityTTL
+}
+
+func searchPathFingerprint() string {
+	sum := sha256.Sum256([]byte(os.Getenv("PATH")))
+	return hex.EncodeToString(sum[:8])
+}
+
+var (
+	capabilityMu     sync.Mutex
+	capabilityCache  map[string]*AgentCapability
+	capabilityLoaded bool
+	// capabilityPath overrides AgentCapabilitiesPath in tests.
+	capabilityPath string
+)
+
+// AgentCapabilitiesPath returns the machine-local capability cache file.
+// Resolved binaries belong to the machine, not the town, so the cache lives
+// in the user cache directory rather than the workspace.
+func AgentCapabilitiesPath() string {
+	if capabilityPath != "" {
+		return capabilityPath
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gastown", "agent-capabilities.json")
+}
+
+// LookupAgentBinary resolves command to an executable path, consulting the
+// capability cache first. Only successful resolutions are cached, and a cached
+// path is re-checked with a stat, so installing or removing an agent is
+// noticed immediately; only the PATH search itself is skipped.
+func LookupAgentBinary(command string) (string, error) {
+	capabilityMu.Lock()
+	defer capabilityMu.Unlock()
+	loadCapabilitiesLocked()
+
+	if c := capabilityCache[command]; c.fresh() {
+		if info, err := os.Stat(c.Path); err == nil && !info.IsDir() {
+			return c.Path, nil
+		}
+	}
+
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", err
+	}
+	prev := capabilityCache[command]
+	c := &AgentCapability{Command: command, Path: path, CheckedAt: time.Now(), SearchPath: searchPathFingerprint()}
+	if prev != nil && prev.Path == path {
+		c.Version = prev.Version
+	}
+	capabilityCache[command] = c
+	saveCapabilitiesLocked()
+	return path, nil
+}
+
+// AgentCapabilities returns the capability of each command, probing any that
+// are uncached, expired, or missing. With refresh set, every command is
+// probed again. Probing resolves the binary and runs `<command> --version`.
+func AgentCapabilities(commands []string, refresh bool) map[string]*AgentCapability {
+	capabilityMu.Lock()
+	defer capabilityMu.Unlock()
+	loadCapabilitiesLocked()
+
+	result := make(map[string]*AgentCapability, len(commands))
+	dirty := false
+	for _, command := range commands {
+		c := capabilityCache[command]
+		if refresh || !c.fresh() {
+			c = probeAgent(command)
+			capabilityCache[command] = c
+			dirty = true
+		}
+		result[command] = c
+	}
+	if dirty {
+		saveCapabilitiesLocked()
+	}
+	return result
+}
+
+// probeAgent resolves command and asks it for its version.
+func probeAgent(command string) *AgentCapability {
+	c := &AgentCapability{Command: command, CheckedAt: time.Now(), SearchPath: searchPathFingerprint()}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		c.Error = "not found in PATH"
+		return c
+	}
+	c.Path = path
+
+	ctx, cancel := context.WithTimeout(context.Background(), agentVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output() //nolint:gosec // G204: path resolved from agent config
+	if err == nil {
+		c.Version = firstLine(string(out))
+	}
+	return c
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+func loadCapabilitiesLocked() {
+	if capabilityLoaded {
+		return
+	}
+	capabilityLoaded = true
+	capabilityCache = make(map[string]*AgentCapability)
+	path := AgentCapabilitiesPath()
+	if path == "" {
+		return
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return
+	}
+	var cached map[string]*AgentCapability
+	if json.Unmarshal(data, &cached) == nil && cached != nil {
+		capabilityCache = cached
+	}
+}
+
+// saveCapabilitiesLocked persists the cache. Failures are ignored: the cache
+// only saves PATH searches.
+func saveCapabilitiesLocked() {
+	path := AgentCapabilitiesPath()
+	if path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(capabilityCache, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = util.AtomicWriteFile(path, append(data, '\n'), 0644)
+}
+
+// resetAgentCapabilities drops the in-memory cache and points it at path.
+// Used by tests.
+func resetAgentCapabilities(path string) {
+	capabilityMu.Lock()
+	defer capabilityMu.Unlock()
+	capabilityPath = path
+	capabilityCache = nil
+	capabilityLoaded = false
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookupAgentBinaryCachesHits(t *testing.T) {
+	prev := AgentCapabilitiesPath()
+	t.Cleanup(func() { resetAgentCapabilities(prev) })
+	dir := t.TempDir()
+	resetAgentCapabilities(filepath.Join(dir, "cache.json"))
+
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	if _, err := LookupAgentBinary("fakeagent"); err == nil {
+		t.Fatal("expected miss before install")
+	}
+
+	agent := filepath.Join(bin, "fakeagent")
+	if err := os.WriteFile(agent, []byte("#!/bin/sh\necho fakeagent 1.2.3\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path, err := LookupAgentBinary("fakeagent")
+	if err != nil || path != agent {
+		t.Fatalf("LookupAgentBinary() = %q, %v; want %q", path, err, agent)
+	}
+
+	// A fresh process reads the persisted entry.
+	resetAgentCapabilities(filepath.Join(dir, "cache.json"))
+	caps := AgentCapabilities([]string{"fakeagent"}, false)
+	if c := caps["fakeagent"]; !c.Available() || c.Path != agent {
+		t.Fatalf("cached capability = %+v", c)
+	}
+
+	// Refresh probes the version.
+	caps = AgentCapabilities([]string{"fakeagent"}, true)
+	if got := caps["fakeagent"].Version; got != "fakeagent 1.2.3" {
+		t.Errorf("Version = %q, want %q", got, "fakeagent 1.2.3")
+	}
+
+	// Removing the binary is noticed despite the cache.
+	if err := os.Remove(agent); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LookupAgentBinary("fakeagent"); err == nil {
+		t.Error("expected miss after removal")
+	}
+}
+
+func TestAgentCapabilityPathChangeInvalidates(t *testing.T) {
+	c := &AgentCapability{Path: "/bin/sh", SearchPath: "stale"}
+	if c.fresh() {
+		t.Error("entry resolved against another PATH should not be fresh")
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AgentPresetsDir returns the town's drop-in preset directory,
+// <town>/settings/agents.d. Each *.json file in it defines one preset.
+func AgentPresetsDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "agents.d")
+}
+
+// LoadTownAgentRegistry loads the town's user-defined presets: first every
+// file in agents.d (in name order), then settings/agents.json, which wins on
+// conflicts. Files already loaded are skipped.
+func LoadTownAgentRegistry(townRoot string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	dirErr := loadAgentPresetsDirLocked(AgentPresetsDir(townRoot))
+	fileErr := loadAgentRegistryFromPathLocked(DefaultAgentRegistryPath(townRoot))
+	return errors.Join(dirErr, fileErr)
+}
+
+// loadAgentPresetsDirLocked merges every *.json preset file in dir into the
+// registry. A missing directory is not an error; a bad file is reported and
+// skipped without affecting the others.
+// Caller must hold registryMu write lock.
+func loadAgentPresetsDirLocked(dir string) error {
+	initRegistryLocked()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		if loadedPaths[path] {
+			continue
+		}
+		if err := loadAgentPresetFileLocked(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		loadedPaths[path] = true
+	}
+	return errors.Join(errs...)
+}
+
+// loadAgentPresetFileLocked reads one preset. The preset's name is its "name"
+// field, or the file name without .json. A file naming an existing preset
+// overlays it: only the fields present in the file change, so a drop-in can
+// adjust e.g. the default args of a built-in without restating it.
+// Caller must hold registryMu write lock.
+func loadAgentPresetFileLocked(path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the town presets dir
+	if err != nil {
+		return err
+	}
+
+	var header struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	name := header.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+
+	preset := &AgentPresetInfo{}
+	if existing := globalRegistry.Agents[name]; existing != nil {
+		preset = clonePresetInfo(existing)
+	}
+	if err := json.Unmarshal(data, preset); err != nil {
+		return err
+	}
+	preset.Name = AgentPreset(name)
+	if preset.Command == "" {
+		return fmt.Errorf("preset %q has no command", name)
+	}
+
+	globalRegistry.Agents[name] = preset
+	return nil
+}
+
+// clonePresetInfo deep-copies the fields json.Unmarshal would otherwise
+// decode into in place, so overlays never mutate the built-in presets.
+func clonePresetInfo(p *AgentPresetInfo) *AgentPresetInfo {
+	c := *p
+	c.Args = append([]string(nil), p.Args...)
+	c.ProcessNames = append([]string(nil), p.ProcessNames...)
+	if p.Env != nil {
+		c.Env = make(map[string]string, len(p.Env))
+		for k, v := range p.Env {
+			c.Env[k] = v
+		}
+	}
+	if p.NonInteractive != nil {
+		ni := *p.NonInteractive
+		c.NonInteractive = &ni
+	}
+	return &c
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writePresetFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAgentPresetsDir(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+
+	townRoot := t.TempDir()
+	dir := AgentPresetsDir(townRoot)
+	writePresetFile(t, dir, "kimi.json", `{
+		"command": "kimi",
+		"args": ["--yolo"],
+		"process_names": ["kimi", "python3"]
+	}`)
+	writePresetFile(t, dir, "codex-fast.json", `{"name": "codex", "args": ["--fast"]}`)
+	writePresetFile(t, dir, "broken.json", `{"args": [`)
+
+	err := LoadTownAgentRegistry(townRoot)
+	if err == nil {
+		t.Error("expected an error for broken.json")
+	}
+
+	kimi := GetAgentPresetByName("kimi")
+	if kimi == nil {
+		t.Fatal("kimi preset not registered")
+	}
+	if kimi.Command != "kimi" || !reflect.DeepEqual(kimi.Args, []string{"--yolo"}) {
+		t.Errorf("kimi = %+v", kimi)
+	}
+	if got := ExpectedPaneCommands(&RuntimeConfig{Command: "kimi"}); !reflect.DeepEqual(got, []string{"kimi", "python3"}) {
+		t.Errorf("ExpectedPaneCommands(kimi) = %v", got)
+	}
+
+	// The overlay changes only the fields it sets.
+	codex := GetAgentPresetByName("codex")
+	if codex.Command != "codex" || !reflect.DeepEqual(codex.Args, []string{"--fast"}) {
+		t.Errorf("codex overlay = %+v", codex)
+	}
+	if codex.ResumeStyle != builtinPresets[AgentCodex].ResumeStyle {
+		t.Errorf("overlay lost ResumeStyle: %q", codex.ResumeStyle)
+	}
+	if reflect.DeepEqual(builtinPresets[AgentCodex].Args, codex.Args) {
+		t.Error("overlay mutated the built-in preset")
+	}
+}
+
+func TestLoadTownAgentRegistryFileWins(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+
+	townRoot := t.TempDir()
+	writePresetFile(t, AgentPresetsDir(townRoot), "mytool.json", `{"command": "from-dir"}`)
+	writePresetFile(t, filepath.Join(townRoot, "settings"), "agents.json",
+		`{"version": 1, "agents": {"mytool": {"command": "from-file"}}}`)
+
+	if err := LoadTownAgentRegistry(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetAgentPresetByName("mytool").Command; got != "from-file" {
+		t.Errorf("Command = %q, want from-file", got)
+	}
+}
+
+func TestLoadTownAgentRegistryRequiresCommand(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+
+	townRoot := t.TempDir()
+	writePresetFile(t, AgentPresetsDir(townRoot), "nocmd.json", `{"args": ["-x"]}`)
+	if err := LoadTownAgentRegistry(townRoot); err == nil {
+		t.Error("expected error for preset without command")
+	}
+	if GetAgentPresetByName("nocmd") != nil {
+		t.Error("invalid preset should not be registered")
+	}
+}
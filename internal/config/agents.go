@@ -639,6 +639,7 @@ func (rc *RuntimeConfig) MergeWithPreset(preset AgentPreset) *RuntimeConfig {
 	result := &RuntimeConfig{
 		Command:       rc.Command,
 		Args:          append([]string(nil), rc.Args...),
+		ArgGroups:     rc.ArgGroups,
 		InitialPrompt: rc.InitialPrompt,
 	}
 
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCI indicates a malformed CI monitoring config.
+var ErrInvalidCI = errors.New("invalid ci config")
+
+// CI providers the witness can poll.
+const (
+	// CIProviderGitHub reads GitHub Actions runs through the gh CLI.
+	CIProviderGitHub = "github"
+
+	// CIProviderBuildkite reads Buildkite builds through its REST API,
+	// authenticated with BUILDKITE_API_TOKEN.
+	CIProviderBuildkite = "buildkite"
+)
+
+// CIConfig configures monitoring of a rig's default branch in external CI
+// (gt witness ci-check). When the branch goes red the witness opens a bead,
+// pauses the merge queue and escalates; when it goes green again the bead
+// is closed and the queue resumed.
+type CIConfig struct {
+	// Provider is "github" or "buildkite".
+	Provider string `json:"provider"`
+
+	// Repo is the GitHub repository as owner/name. Default: parsed from
+	// the rig's git_url.
+	Repo string `json:"repo,omitempty"`
+
+	// Workflow limits GitHub monitoring to one workflow (file name or
+	// name). Default: every workflow that runs on the branch.
+	Workflow string `json:"workflow,omitempty"`
+
+	// Org and Pipeline are the Buildkite organization and pipeline slugs.
+	Org      string `json:"org,omitempty"`
+	Pipeline string `json:"pipeline,omitempty"`
+
+	// Branch is the branch to watch. Default: the rig's default branch.
+	Branch string `json:"branch,omitempty"`
+
+	// Severity of the bead and escalation filed when the branch goes red.
+	// Default: high.
+	Severity string `json:"severity,omitempty"`
+
+	// PauseMergeQueue pauses the rig's merge queue while the branch is red.
+	// Default: true.
+	PauseMergeQueue *bool `json:"pause_merge_queue,omitempty"`
+}
+
+// EffectiveSeverity returns the configured severity, defaulting to high.
+func (c *CIConfig) EffectiveSeverity() string {
+	if c == nil || c.Severity == "" {
+		return SeverityHigh
+	}
+	return c.Severity
+}
+
+// ShouldPauseMergeQueue reports whether a red branch pauses the merge queue.
+func (c *CIConfig) ShouldPauseMergeQueue() bool {
+	return c == nil || c.PauseMergeQueue == nil || *c.PauseMergeQueue
+}
+
+// Validate checks the provider and its required fields.
+func (c *CIConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Provider {
+	case CIProviderGitHub:
+	case CIProviderBuildkite:
+		if c.Org == "" || c.Pipeline == "" {
+			return fmt.Errorf("%w: buildkite needs org and pipeline", ErrInvalidCI)
+		}
+	default:
+		return fmt.Errorf("%w: unknown provider %q (valid: github, buildkite)", ErrInvalidCI, c.Provider)
+	}
+	if c.Severity != "" && !IsValidSeverity(c.Severity) {
+		return fmt.Errorf("%w: invalid severity %q", ErrInvalidCI, c.Severity)
+	}
+	return nil
+}
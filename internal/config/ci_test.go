@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCIConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *CIConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"github", &CIConfig{Provider: CIProviderGitHub}, false},
+		{"buildkite", &CIConfig{Provider: CIProviderBuildkite, Org: "acme", Pipeline: "web"}, false},
+		{"buildkite without pipeline", &CIConfig{Provider: CIProviderBuildkite, Org: "acme"}, true},
+		{"no provider", &CIConfig{}, true},
+		{"unknown provider", &CIConfig{Provider: "jenkins"}, true},
+		{"bad severity", &CIConfig{Provider: CIProviderGitHub, Severity: "urgent"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCI) {
+				t.Errorf("error %v is not ErrInvalidCI", err)
+			}
+		})
+	}
+}
+
+func TestCIConfigDefaults(t *testing.T) {
+	var c *CIConfig
+	if c.EffectiveSeverity() != SeverityHigh || !c.ShouldPauseMergeQueue() {
+		t.Errorf("nil config: severity %q, pause %v", c.EffectiveSeverity(), c.ShouldPauseMergeQueue())
+	}
+	off := false
+	c = &CIConfig{Provider: CIProviderGitHub, Severity: SeverityCritical, PauseMergeQueue: &off}
+	if c.EffectiveSeverity() != SeverityCritical || c.ShouldPauseMergeQueue() {
+		t.Errorf("configured: severity %q, pause %v", c.EffectiveSeverity(), c.ShouldPauseMergeQueue())
+	}
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Container runtimes supported by ContainerConfig.
+const (
+	ContainerRuntimeDocker = "docker"
+	ContainerRuntimePodman = "podman"
+)
+
+// ErrInvalidContainer indicates a malformed container configuration.
+var ErrInvalidContainer = errors.New("invalid container config")
+
+// ContainerConfig runs each polecat inside a container instead of directly on
+// the host. The town root is mounted at its host path, so the polecat's
+// worktree, its git metadata, and the town's beads are visible unchanged and
+// gt/bd inside the container behave as on the host. The image must provide
+// the agent CLI, gt, bd, and git.
+type ContainerConfig struct {
+	// Enabled turns the sandbox on. A config with Enabled=false is ignored.
+	Enabled bool `json:"enabled"`
+
+	// Runtime is the container CLI: "docker" (default) or "podman".
+	Runtime string `json:"runtime,omitempty"`
+
+	// Image is the container image to run (required).
+	Image string `json:"image"`
+
+	// Mounts are extra bind mounts in "host:container[:options]" form,
+	// e.g. "~/.claude:/home/agent/.claude:ro" for agent credentials.
+	// A leading "~" in the host path expands to the user's home directory.
+	Mounts []string `json:"mounts,omitempty"`
+
+	// CPUs caps CPU usage (e.g., "2" or "0.5"). Empty means unlimited.
+	CPUs string `json:"cpus,omitempty"`
+
+	// Memory caps memory (e.g., "4g", "512m"). Empty means unlimited.
+	Memory string `json:"memory,omitempty"`
+
+	// PidsLimit caps the number of processes. Zero means unlimited.
+	PidsLimit int `json:"pids_limit,omitempty"`
+
+	// Network is the container network. Default: "host", so the agent
+	// reaches the town's Dolt server on localhost.
+	Network string `json:"network,omitempty"`
+
+	// ExtraArgs are passed to "<runtime> run" verbatim, before the image.
+	ExtraArgs []string `json:"extra_args,omitempty"`
+}
+
+var (
+	containerCPUsRe   = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	containerMemoryRe = regexp.MustCompile(`^[0-9]+[bkmgBKMG]?$`)
+)
+
+// IsEnabled reports whether polecats should run in a container. Nil-safe.
+func (c *ContainerConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// RuntimeName returns the container CLI, defaulting to docker.
+func (c *ContainerConfig) RuntimeName() string {
+	if c.Runtime == "" {
+		return ContainerRuntimeDocker
+	}
+	return c.Runtime
+}
+
+// Validate checks an enabled config for a known runtime, an image, and
+// well-formed limits and mounts. Disabled or nil configs are always valid.
+func (c *ContainerConfig) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+	switch c.RuntimeName() {
+	case ContainerRuntimeDocker, ContainerRuntimePodman:
+	default:
+		return fmt.Errorf("%w: runtime %q (want %q or %q)", ErrInvalidContainer, c.Runtime, ContainerRuntimeDocker, ContainerRuntimePodman)
+	}
+	if strings.TrimSpace(c.Image) == "" {
+		return fmt.Errorf("%w: image is required", ErrInvalidContainer)
+	}
+	if c.CPUs != "" && !containerCPUsRe.MatchString(c.CPUs) {
+		return fmt.Errorf("%w: cpus %q", ErrInvalidContainer, c.CPUs)
+	}
+	if c.Memory != "" && !containerMemoryRe.MatchString(c.Memory) {
+		return fmt.Errorf("%w: memory %q", ErrInvalidContainer, c.Memory)
+	}
+	if c.PidsLimit < 0 {
+		return fmt.Errorf("%w: pids_limit %d", ErrInvalidContainer, c.PidsLimit)
+	}
+	for _, m := range c.Mounts {
+		if parts := strings.Split(m, ":"); len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("%w: mount %q (want host:container[:options])", ErrInvalidContainer, m)
+		}
+	}
+	return nil
+}
+
+// WrapCommand returns a shell command that runs command inside a new
+// container named name, with workDir as the working directory and townRoot
+// mounted at the same path. The container is removed when it exits.
+// The result is meant to be the initial process of a tmux pane, so the
+// container gets a TTY. passEnv names variables the container inherits
+// from the pane's environment, for values (secrets) that must not appear
+// on the command line.
+func (c *ContainerConfig) WrapCommand(name, workDir, townRoot, command string, passEnv []string) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	args := c.RunArgs(name, workDir, townRoot, passEnv)
+	args = append(args, "sh", "-c", command)
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = ShellQuote(a)
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// RunArgs returns "<runtime> run ..." up to and including the image.
+func (c *ContainerConfig) RunArgs(name, workDir, townRoot string, passEnv []string) []string {
+	network := c.Network
+	if network == "" {
+		network = "host"
+	}
+	args := []string{c.RuntimeName(), "run", "--rm", "-it", "--init",
+		"--name", name,
+		"--network", network,
+		"-v", townRoot + ":" + townRoot,
+		"-w", workDir,
+	}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+		if c.RuntimeName() == ContainerRuntimePodman {
+			args = append(args, "--userns", "keep-id")
+		}
+	}
+	for _, m := range c.Mounts {
+		args = append(args, "-v", expandHome(m))
+	}
+	// "-e NAME" without a value copies NAME from the runtime's environment.
+	for _, env := range passEnv {
+		args = append(args, "-e", env)
+	}
+	if c.CPUs != "" {
+		args = append(args, "--cpus", c.CPUs)
+	}
+	if c.Memory != "" {
+		args = append(args, "--memory", c.Memory)
+	}
+	if c.PidsLimit > 0 {
+		args = append(args, "--pids-limit", fmt.Sprintf("%d", c.PidsLimit))
+	}
+	args = append(args, c.ExtraArgs...)
+	return append(args, c.Image)
+}
+
+// expandHome replaces a leading "~" in a mount's host path with $HOME.
+func expandHome(mount string) string {
+	if !strings.HasPrefix(mount, "~") {
+		return mount
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return mount
+	}
+	return home + strings.TrimPrefix(mount, "~")
+}
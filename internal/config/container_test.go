@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContainerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *ContainerConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"disabled without image", &ContainerConfig{}, false},
+		{"minimal", &ContainerConfig{Enabled: true, Image: "gastown/polecat:latest"}, false},
+		{"podman with limits", &ContainerConfig{Enabled: true, Runtime: "podman", Image: "img", CPUs: "1.5", Memory: "4g", PidsLimit: 512}, false},
+		{"missing image", &ContainerConfig{Enabled: true}, true},
+		{"unknown runtime", &ContainerConfig{Enabled: true, Runtime: "lxc", Image: "img"}, true},
+		{"bad cpus", &ContainerConfig{Enabled: true, Image: "img", CPUs: "two"}, true},
+		{"bad memory", &ContainerConfig{Enabled: true, Image: "img", Memory: "4 GB"}, true},
+		{"bad mount", &ContainerConfig{Enabled: true, Image: "img", Mounts: []string{"/only-host"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidContainer) {
+				t.Errorf("Validate() error = %v, want ErrInvalidContainer", err)
+			}
+		})
+	}
+}
+
+func TestContainerConfigWrapCommand(t *testing.T) {
+	c := &ContainerConfig{
+		Enabled:   true,
+		Image:     "gastown/polecat:latest",
+		Mounts:    []string{"/opt/tools:/opt/tools:ro"},
+		CPUs:      "2",
+		Memory:    "4g",
+		PidsLimit: 256,
+	}
+	got, err := c.WrapCommand("gt-toast", "/town/gastown/polecats/toast", "/town", "export GT_ROLE=polecat && claude 'hi there'", []string{"BEADS_DOLT_PASSWORD"})
+	if err != nil {
+		t.Fatalf("WrapCommand: %v", err)
+	}
+	for _, want := range []string{
+		"docker run --rm -it --init --name gt-toast --network host",
+		"-v /town:/town -w /town/gastown/polecats/toast",
+		"-v /opt/tools:/opt/tools:ro -e BEADS_DOLT_PASSWORD --cpus 2 --memory 4g --pids-limit 256 gastown/polecat:latest sh -c ",
+		`'export GT_ROLE=polecat && claude '\''hi there'\'''`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WrapCommand() = %q\nmissing %q", got, want)
+		}
+	}
+}
+
+func TestLoadRigSettings_InvalidContainer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"type":"rig-settings","version":1,"container":{"enabled":true}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRigSettings(path); !errors.Is(err, ErrInvalidContainer) {
+		t.Errorf("LoadRigSettings() error = %v, want ErrInvalidContainer", err)
+	}
+}
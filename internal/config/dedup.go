@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidDedup indicates a malformed duplicate detection config.
+var ErrInvalidDedup = errors.New("invalid dedup config")
+
+// DefaultDedupLinkThreshold is the similarity at which a new bead is linked
+// to an open one as a probable duplicate.
+const DefaultDedupLinkThreshold = 0.75
+
+// DedupConfig turns on duplicate detection for beads gt files automatically
+// in the rig (the witness's CI and incident beads). A new bead is compared
+// with the rig's open beads by title and description; similarity runs from
+// 0 (nothing shared) to 1 (same words).
+type DedupConfig struct {
+	// LinkThreshold is the similarity at or above which the new bead gets
+	// a related dependency on the match and the possible-duplicate label.
+	// Default: 0.75.
+	LinkThreshold float64 `json:"link_threshold,omitempty"`
+
+	// CloseThreshold is the similarity at or above which the new bead is
+	// closed with a duplicates dependency on the match. Zero (default)
+	// never auto-closes.
+	CloseThreshold float64 `json:"close_threshold,omitempty"`
+}
+
+// EffectiveLinkThreshold returns the link threshold, defaulting to 0.75.
+func (c *DedupConfig) EffectiveLinkThreshold() float64 {
+	if c == nil || c.LinkThreshold == 0 {
+		return DefaultDedupLinkThreshold
+	}
+	return c.LinkThreshold
+}
+
+// Validate checks both thresholds are between 0 and 1 and that auto-close
+// needs at least the similarity linking does.
+func (c *DedupConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.LinkThreshold < 0 || c.LinkThreshold > 1 {
+		return fmt.Errorf("%w: link_threshold %v is outside 0-1", ErrInvalidDedup, c.LinkThreshold)
+	}
+	if c.CloseThreshold < 0 || c.CloseThreshold > 1 {
+		return fmt.Errorf("%w: close_threshold %v is outside 0-1", ErrInvalidDedup, c.CloseThreshold)
+	}
+	if c.CloseThreshold > 0 && c.CloseThreshold < c.EffectiveLinkThreshold() {
+		return fmt.Errorf("%w: close_threshold %v is below link_threshold %v", ErrInvalidDedup, c.CloseThreshold, c.EffectiveLinkThreshold())
+	}
+	return nil
+}
+
+// LoadDedupConfig returns the rig's duplicate detection settings, or nil
+// when it has none and detection is off.
+func LoadDedupConfig(rigPath string) *DedupConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Dedup
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDedupConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *DedupConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"defaults", &DedupConfig{}, false},
+		{"link and close", &DedupConfig{LinkThreshold: 0.6, CloseThreshold: 0.9}, false},
+		{"close only", &DedupConfig{CloseThreshold: 0.95}, false},
+		{"link above one", &DedupConfig{LinkThreshold: 1.5}, true},
+		{"negative close", &DedupConfig{CloseThreshold: -0.1}, true},
+		{"close below default link", &DedupConfig{CloseThreshold: 0.5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidDedup) {
+				t.Errorf("Validate() = %v, want ErrInvalidDedup", err)
+			}
+		})
+	}
+}
+
+func TestDedupConfigEffectiveLinkThreshold(t *testing.T) {
+	var nilCfg *DedupConfig
+	if got := nilCfg.EffectiveLinkThreshold(); got != DefaultDedupLinkThreshold {
+		t.Errorf("nil EffectiveLinkThreshold = %v, want %v", got, DefaultDedupLinkThreshold)
+	}
+	if got := (&DedupConfig{LinkThreshold: 0.5}).EffectiveLinkThreshold(); got != 0.5 {
+		t.Errorf("EffectiveLinkThreshold = %v, want 0.5", got)
+	}
+}
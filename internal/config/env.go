@@ -4,7 +4,6 @@ package config
 import (
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 )
 
@@ -189,23 +188,11 @@ func ShellQuote(s string) string {
 // The keys are sorted for deterministic output.
 // Values containing special characters are properly shell-quoted.
 func ExportPrefix(env map[string]string) string {
-	if len(env) == 0 {
+	exports := envExports(env)
+	if len(exports) == 0 {
 		return ""
 	}
-
-	// Sort keys for deterministic output
-	keys := make([]string, 0, len(env))
-	for k := range env {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	var parts []string
-	for _, k := range keys {
-		parts = append(parts, fmt.Sprintf("%s=%s", k, ShellQuote(env[k])))
-	}
-
-	return "export " + strings.Join(parts, " ") + " && "
+	return "export " + strings.Join(exports, " ") + " && "
 }
 
 // BuildStartupCommandWithEnv builds a startup command with the given environment variables.
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a config file format.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// configVariants lists the files tried for a config path, in precedence
+// order. JSON is the default and wins when several variants exist.
+var configVariants = []struct {
+	ext    string
+	format Format
+}{
+	{".json", FormatJSON},
+	{".yaml", FormatYAML},
+	{".yml", FormatYAML},
+	{".toml", FormatTOML},
+}
+
+// ResolveConfigPath returns the file that holds the config at path, which
+// names its JSON variant (e.g. mayor/town.json). The first existing variant
+// in configVariants order is returned; if none exists, path itself is
+// returned as JSON.
+//
+// With more than one variant present, the others are ignored and a
+// warning is printed to stderr.
+func ResolveConfigPath(path string) (string, Format) {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	var found []string
+	var format Format
+	for _, v := range configVariants {
+		if _, err := os.Stat(base + v.ext); err == nil {
+			if len(found) == 0 {
+				format = v.format
+			}
+			found = append(found, base+v.ext)
+		}
+	}
+	if len(found) == 0 {
+		return path, formatForPath(path)
+	}
+	if len(found) > 1 {
+		fmt.Fprintf(os.Stderr, "warning: %s: using %s, ignoring %s\n",
+			filepath.Base(base), filepath.Base(found[0]), strings.Join(found[1:], ", "))
+	}
+	return found[0], format
+}
+
+// ConfigExists reports whether any variant of the config at path exists.
+func ConfigExists(path string) bool {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, v := range configVariants {
+		if _, err := os.Stat(base + v.ext); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatForPath returns the format implied by path's extension.
+func formatForPath(path string) Format {
+	ext := filepath.Ext(path)
+	for _, v := range configVariants {
+		if v.ext == ext {
+			return v.format
+		}
+	}
+	return FormatJSON
+}
+
+// ReadConfigFile reads the config at path (see ResolveConfigPath) and
+// returns it as JSON, so callers decode every format with encoding/json
+// and the structs' json tags. A missing file returns the *os.PathError
+// from reading path, so os.IsNotExist works on the result.
+func ReadConfigFile(path string) ([]byte, error) {
+	resolved, format := ResolveConfigPath(path)
+	data, err := os.ReadFile(resolved) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJSON {
+		return data, nil
+	}
+	converted, err := toJSON(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", resolved, err)
+	}
+	return converted, nil
+}
+
+// MarshalConfig encodes v in format. Field names come from v's json tags;
+// JSON output is indented like the rest of the config files.
+func MarshalConfig(v interface{}, format Format) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil || format == FormatJSON {
+		return data, err
+	}
+
+	generic, err := decodeGenericJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return nil, fmt.Errorf("encoding yaml: %w", err)
+		}
+	case FormatTOML:
+		table, ok := generic.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("encoding toml: top level must be an object")
+		}
+		if err := toml.NewEncoder(&buf).Encode(dropNulls(table)); err != nil {
+			return nil, fmt.Errorf("encoding toml: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown config format %q", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// toJSON converts a YAML or TOML document to JSON.
+func toJSON(data []byte, format Format) ([]byte, error) {
+	var generic interface{}
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return nil, fmt.Errorf("parsing yaml: %w", err)
+		}
+	case FormatTOML:
+		var table map[string]interface{}
+		if _, err := toml.Decode(string(data), &table); err != nil {
+			return nil, fmt.Errorf("parsing toml: %w", err)
+		}
+		generic = table
+	default:
+		return nil, fmt.Errorf("unknown config format %q", format)
+	}
+	if generic == nil {
+		generic = map[string]interface{}{} // empty YAML document
+	}
+	return json.Marshal(generic)
+}
+
+// decodeGenericJSON decodes JSON into maps and slices, keeping integers as
+// int64 so they aren't written back as floats (3e+11) that encoding/json
+// then refuses to decode into integer fields.
+func decodeGenericJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("decoding config: %w", err)
+	}
+	return convertNumbers(generic), nil
+}
+
+func convertNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = convertNumbers(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = convertNumbers(e)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return int64(f)
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// dropNulls removes null values, which TOML can't represent. A missing key
+// decodes the same as null for every config struct.
+func dropNulls(v map[string]interface{}) map[string]interface{} {
+	for k, e := range v {
+		switch e := e.(type) {
+		case nil:
+			delete(v, k)
+		case map[string]interface{}:
+			dropNulls(e)
+		case []interface{}:
+			for _, item := range e {
+				if m, ok := item.(map[string]interface{}); ok {
+					dropNulls(m)
+				}
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveConfigPath_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "escalation.json")
+
+	if got, format := ResolveConfigPath(jsonPath); got != jsonPath || format != FormatJSON {
+		t.Errorf("no files: got %s (%s), want %s (json)", got, format, jsonPath)
+	}
+	if ConfigExists(jsonPath) {
+		t.Error("ConfigExists with no files = true")
+	}
+
+	tomlPath := filepath.Join(dir, "escalation.toml")
+	writeTestFile(t, tomlPath, `type = "escalation"`)
+	if got, format := ResolveConfigPath(jsonPath); got != tomlPath || format != FormatTOML {
+		t.Errorf("toml only: got %s (%s), want %s (toml)", got, format, tomlPath)
+	}
+	if !ConfigExists(jsonPath) {
+		t.Error("ConfigExists with toml variant = false")
+	}
+
+	ymlPath := filepath.Join(dir, "escalation.yml")
+	writeTestFile(t, ymlPath, `type: escalation`)
+	if got, format := ResolveConfigPath(jsonPath); got != ymlPath || format != FormatYAML {
+		t.Errorf("yml and toml: got %s (%s), want %s (yaml)", got, format, ymlPath)
+	}
+
+	writeTestFile(t, jsonPath, `{"type": "escalation"}`)
+	if got, format := ResolveConfigPath(jsonPath); got != jsonPath || format != FormatJSON {
+		t.Errorf("all variants: got %s (%s), want %s (json)", got, format, jsonPath)
+	}
+}
+
+func TestLoadEscalationConfig_YAML(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "escalation.yaml"), `# Routes for escalations.
+type: escalation
+version: 1
+routes:
+  high: [bead, "mail:mayor"]  # page the mayor
+stale_threshold: 2h
+max_reescalations: 3
+`)
+
+	cfg, err := LoadEscalationConfig(filepath.Join(dir, "escalation.json"))
+	if err != nil {
+		t.Fatalf("LoadEscalationConfig: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Routes["high"], []string{"bead", "mail:mayor"}) {
+		t.Errorf("routes.high = %v", cfg.Routes["high"])
+	}
+	if cfg.StaleThreshold != "2h" || cfg.MaxReescalations == nil || *cfg.MaxReescalations != 3 {
+		t.Errorf("stale_threshold = %q, max_reescalations = %v", cfg.StaleThreshold, cfg.MaxReescalations)
+	}
+}
+
+func TestLoadRigSettings_TOML(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.toml"), `type = "rig-settings"
+version = 1
+
+[merge_queue]
+enabled = true
+on_conflict = "assign_back"  # or auto_rebase
+max_concurrent = 2
+`)
+
+	settings, err := LoadRigSettings(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("LoadRigSettings: %v", err)
+	}
+	if settings.MergeQueue == nil || !settings.MergeQueue.Enabled || settings.MergeQueue.MaxConcurrent != 2 {
+		t.Errorf("merge_queue = %+v", settings.MergeQueue)
+	}
+}
+
+func TestLoadConfig_InvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "messaging.yaml"), "type: [unclosed\n")
+
+	_, err := LoadMessagingConfig(filepath.Join(dir, "messaging.json"))
+	if err == nil || !strings.Contains(err.Error(), "messaging.yaml") {
+		t.Errorf("err = %v, want a parse error naming messaging.yaml", err)
+	}
+}
+
+func TestLoadConfig_MissingAllVariants(t *testing.T) {
+	_, err := LoadTownConfig(filepath.Join(t.TempDir(), "town.json"))
+	if err == nil || !strings.Contains(err.Error(), ErrNotFound.Error()) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSaveConfig_KeepsActiveFormat(t *testing.T) {
+	for _, ext := range []string{".yaml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			dir := t.TempDir()
+			active := filepath.Join(dir, "config"+ext)
+			writeTestFile(t, active, "")
+			jsonPath := filepath.Join(dir, "config.json")
+
+			settings := NewRigSettings()
+			settings.MergeQueue = DefaultMergeQueueConfig()
+			settings.MergeQueue.MaxConcurrent = 3
+			if err := SaveRigSettings(jsonPath, settings); err != nil {
+				t.Fatalf("SaveRigSettings: %v", err)
+			}
+			if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
+				t.Errorf("save created %s; want it written to %s", jsonPath, active)
+			}
+
+			loaded, err := LoadRigSettings(jsonPath)
+			if err != nil {
+				t.Fatalf("LoadRigSettings after save: %v", err)
+			}
+			if !reflect.DeepEqual(loaded, settings) {
+				t.Errorf("round trip mismatch:\n got  %+v\n want %+v", loaded, settings)
+			}
+		})
+	}
+}
+
+func TestMarshalConfig_LargeIntegers(t *testing.T) {
+	// Durations are stored as nanoseconds; they must stay integers.
+	v := map[string]interface{}{"interval": int64(300000000000), "ratio": 0.5, "unset": nil}
+	for _, format := range []Format{FormatYAML, FormatTOML} {
+		data, err := MarshalConfig(v, format)
+		if err != nil {
+			t.Fatalf("%s: MarshalConfig: %v", format, err)
+		}
+		if !strings.Contains(string(data), "300000000000") {
+			t.Errorf("%s: interval not written as an integer:\n%s", format, data)
+		}
+		back, err := toJSON(data, format)
+		if err != nil {
+			t.Fatalf("%s: toJSON: %v", format, err)
+		}
+		if !strings.Contains(string(back), `"interval":300000000000`) || !strings.Contains(string(back), `"ratio":0.5`) {
+			t.Errorf("%s: round trip = %s", format, back)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidGit indicates a malformed rig git config.
+var ErrInvalidGit = errors.New("invalid git config")
+
+// Git LFS modes.
+const (
+	// LFSAuto installs and fetches LFS objects when the repo's
+	// .gitattributes routes files through the lfs filter.
+	LFSAuto = "auto"
+
+	// LFSOn always installs and fetches LFS objects; git-lfs is required.
+	LFSOn = "on"
+
+	// LFSOff never touches LFS; tracked files stay as pointer files.
+	LFSOff = "off"
+)
+
+// GitConfig controls how the rig's clones and worktrees are checked out
+// and who makes and signs their commits. Checkout options are applied to
+// the shared .repo.git when the rig is provisioned, so every polecat,
+// refinery and dog worktree inherits them; identities and signing are set
+// on each clone and worktree.
+type GitConfig struct {
+	// Submodules initializes and updates submodules recursively on every
+	// clone and worktree. Nil defaults to true.
+	Submodules *bool `json:"submodules,omitempty"`
+
+	// LFS is "auto" (default), "on" or "off".
+	LFS string `json:"lfs,omitempty"`
+
+	// Identity is the git author and committer for the rig's agents, so
+	// their commits are attributable. Nil keeps the host's git identity.
+	Identity *GitIdentity `json:"identity,omitempty"`
+
+	// RoleIdentities overrides Identity per role (polecat, crew, refinery,
+	// witness, mayor, dog); fields left empty fall back to Identity.
+	RoleIdentities map[string]*GitIdentity `json:"role_identities,omitempty"`
+
+	// Signing signs agent commits. Nil leaves commits unsigned.
+	Signing *GitSigning `json:"signing,omitempty"`
+}
+
+// Commit signing formats.
+const (
+	SigningSSH     = "ssh"
+	SigningOpenPGP = "openpgp"
+)
+
+// SigningKeyAgent as a signing key selects the first key in the SSH agent,
+// including one forwarded into the session.
+const SigningKeyAgent = "agent"
+
+// GitSigning configures commit signing for the rig's clones and worktrees.
+type GitSigning struct {
+	// Format is "ssh" (default) or "openpgp".
+	Format string `json:"format,omitempty"`
+
+	// Key is the signing key: for ssh a key file path or "agent", for
+	// openpgp a key ID. A role identity's signing_key takes precedence.
+	Key string `json:"key,omitempty"`
+
+	// Roles whose commits are signed. Empty means polecat and refinery,
+	// covering agent work and the refinery's merge commits.
+	Roles []string `json:"roles,omitempty"`
+
+	// AllowedSigners is the ssh allowed signers file used to verify
+	// signatures (gpg.ssh.allowedSignersFile).
+	AllowedSigners string `json:"allowed_signers,omitempty"`
+
+	// Verify makes the refinery reject merge requests whose commits do not
+	// all carry a good signature.
+	Verify bool `json:"verify,omitempty"`
+}
+
+// defaultSigningRoles are the roles signed when GitSigning.Roles is empty.
+var defaultSigningRoles = []string{"polecat", "refinery"}
+
+// SigningFormat returns the configured format, defaulting to ssh.
+func (s *GitSigning) SigningFormat() string {
+	if s == nil || s.Format == "" {
+		return SigningSSH
+	}
+	return s.Format
+}
+
+// SignsRole reports whether commits by role are signed.
+func (s *GitSigning) SignsRole(role string) bool {
+	if s == nil {
+		return false
+	}
+	if len(s.Roles) == 0 {
+		return slices.Contains(defaultSigningRoles, role)
+	}
+	return slices.Contains(s.Roles, role)
+}
+
+// SigningKeyFor returns the key role's commits are signed with, or "" when
+// they are not signed.
+func (c *GitConfig) SigningKeyFor(rig, role, name string) string {
+	if c == nil || !c.Signing.SignsRole(role) {
+		return ""
+	}
+	if id := c.IdentityFor(rig, role, name); id != nil && id.SigningKey != "" {
+		return id.SigningKey
+	}
+	return c.Signing.Key
+}
+
+// GitIdentity is a git author/committer. Name and Email may use {rig},
+// {role} and {name} (the polecat, crew or dog name; the role for singleton
+// agents), e.g. "Gastown Polecat" <polecats@{rig}>.
+type GitIdentity struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+
+	// SigningKey is the key commits are signed with (git user.signingkey).
+	SigningKey string `json:"signing_key,omitempty"`
+}
+
+// identityRoles are the roles that may have their own identity.
+var identityRoles = []string{"polecat", "crew", "refinery", "witness", "mayor", "dog"}
+
+// IdentityFor returns the identity for an agent of role named name in
+// rig, with templates expanded, or nil when none is configured.
+func (c *GitConfig) IdentityFor(rig, role, name string) *GitIdentity {
+	if c == nil {
+		return nil
+	}
+	var id GitIdentity
+	if c.Identity != nil {
+		id = *c.Identity
+	}
+	if r := c.RoleIdentities[role]; r != nil {
+		if r.Name != "" {
+			id.Name = r.Name
+		}
+		if r.Email != "" {
+			id.Email = r.Email
+		}
+		if r.SigningKey != "" {
+			id.SigningKey = r.SigningKey
+		}
+	}
+	if id == (GitIdentity{}) {
+		return nil
+	}
+	if name == "" {
+		name = role
+	}
+	expand := strings.NewReplacer("{rig}", rig, "{role}", role, "{name}", name).Replace
+	id.Name, id.Email = expand(id.Name), expand(id.Email)
+	return &id
+}
+
+// Env returns the git environment that makes id the author and committer,
+// overriding the role name agents otherwise commit as.
+func (id *GitIdentity) Env() map[string]string {
+	env := make(map[string]string)
+	if id == nil {
+		return env
+	}
+	if id.Name != "" {
+		env["GIT_AUTHOR_NAME"] = id.Name
+		env["GIT_COMMITTER_NAME"] = id.Name
+	}
+	if id.Email != "" {
+		env["GIT_AUTHOR_EMAIL"] = id.Email
+		env["GIT_COMMITTER_EMAIL"] = id.Email
+	}
+	return env
+}
+
+// LoadGitIdentity returns the identity for an agent from the rig's
+// settings, or nil when the rig configures none.
+func LoadGitIdentity(rigPath, role, name string) *GitIdentity {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Git.IdentityFor(filepath.Base(rigPath), role, name)
+}
+
+// SubmodulesEnabled reports whether submodules are initialized.
+func (c *GitConfig) SubmodulesEnabled() bool {
+	return c == nil || c.Submodules == nil || *c.Submodules
+}
+
+// LFSMode returns the configured LFS mode, defaulting to auto.
+func (c *GitConfig) LFSMode() string {
+	if c == nil || c.LFS == "" {
+		return LFSAuto
+	}
+	return c.LFS
+}
+
+// Validate checks the LFS mode, signing settings and identity roles.
+func (c *GitConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.LFS {
+	case "", LFSAuto, LFSOn, LFSOff:
+	default:
+		return fmt.Errorf("%w: unknown lfs mode %q (valid: auto, on, off)", ErrInvalidGit, c.LFS)
+	}
+	if err := c.Signing.validate(); err != nil {
+		return err
+	}
+	roles := make([]string, 0, len(c.RoleIdentities))
+	for role := range c.RoleIdentities {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		if !slices.Contains(identityRoles, role) {
+			return fmt.Errorf("%w: unknown role %q in role_identities (valid: %s)", ErrInvalidGit, role, strings.Join(identityRoles, ", "))
+		}
+	}
+	return nil
+}
+
+func (s *GitSigning) validate() error {
+	if s == nil {
+		return nil
+	}
+	switch s.Format {
+	case "", SigningSSH, SigningOpenPGP:
+	default:
+		return fmt.Errorf("%w: unknown signing format %q (valid: ssh, openpgp)", ErrInvalidGit, s.Format)
+	}
+	if s.Key == SigningKeyAgent && s.SigningFormat() != SigningSSH {
+		return fmt.Errorf("%w: signing key %q requires the ssh format", ErrInvalidGit, SigningKeyAgent)
+	}
+	for _, role := range s.Roles {
+		if !slices.Contains(identityRoles, role) {
+			return fmt.Errorf("%w: unknown role %q in signing roles (valid: %s)", ErrInvalidGit, role, strings.Join(identityRoles, ", "))
+		}
+	}
+	return nil
+}
+
+// addGitIdentityEnv sets the rig's git identity for a rig agent's session,
+// replacing the role name AgentEnv puts in GIT_AUTHOR_NAME.
+func addGitIdentityEnv(env map[string]string, rigPath, role string) {
+	if rigPath == "" || role == "" {
+		return
+	}
+	name := env["GT_POLECAT"]
+	if name == "" {
+		name = env["GT_CREW"]
+	}
+	for k, v := range LoadGitIdentity(rigPath, role, name).Env() {
+		env[k] = v
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGitConfig(t *testing.T) {
+	var c *GitConfig
+	if !c.SubmodulesEnabled() || c.LFSMode() != LFSAuto || c.Validate() != nil {
+		t.Errorf("nil config: submodules %v, lfs %q", c.SubmodulesEnabled(), c.LFSMode())
+	}
+	off := false
+	c = &GitConfig{Submodules: &off, LFS: LFSOn}
+	if c.SubmodulesEnabled() || c.LFSMode() != LFSOn || c.Validate() != nil {
+		t.Errorf("configured: submodules %v, lfs %q", c.SubmodulesEnabled(), c.LFSMode())
+	}
+	if err := (&GitConfig{LFS: "yes"}).Validate(); !errors.Is(err, ErrInvalidGit) {
+		t.Errorf("Validate() = %v, want ErrInvalidGit", err)
+	}
+}
+
+func TestGitConfigIdentityFor(t *testing.T) {
+	if id := (&GitConfig{}).IdentityFor("gastown", "polecat", "nux"); id != nil {
+		t.Errorf("no identity configured: got %+v", id)
+	}
+	c := &GitConfig{
+		Identity: &GitIdentity{Name: "Gastown {role}", Email: "{role}s@{rig}", SigningKey: "KEY"},
+		RoleIdentities: map[string]*GitIdentity{
+			"polecat": {Name: "Gastown Polecat {name}"},
+		},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	want := GitIdentity{Name: "Gastown Polecat nux", Email: "polecats@gastown", SigningKey: "KEY"}
+	if got := c.IdentityFor("gastown", "polecat", "nux"); got == nil || *got != want {
+		t.Errorf("polecat identity = %+v, want %+v", got, want)
+	}
+	if got := c.IdentityFor("gastown", "refinery", ""); got == nil || got.Name != "Gastown refinery" {
+		t.Errorf("refinery identity = %+v", got)
+	}
+	if env := c.IdentityFor("gastown", "crew", "max").Env(); env["GIT_COMMITTER_EMAIL"] != "crews@gastown" {
+		t.Errorf("Env() = %v", env)
+	}
+
+	c.RoleIdentities["deacon"] = &GitIdentity{Name: "x"}
+	if err := c.Validate(); !errors.Is(err, ErrInvalidGit) {
+		t.Errorf("Validate() with unknown role = %v, want ErrInvalidGit", err)
+	}
+}
+
+func TestGitConfigSigningKeyFor(t *testing.T) {
+	c := &GitConfig{
+		Signing:        &GitSigning{Key: SigningKeyAgent},
+		RoleIdentities: map[string]*GitIdentity{"refinery": {SigningKey: "~/.ssh/refinery"}},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if got := c.SigningKeyFor("gastown", "polecat", "nux"); got != SigningKeyAgent {
+		t.Errorf("polecat key = %q", got)
+	}
+	if got := c.SigningKeyFor("gastown", "refinery", ""); got != "~/.ssh/refinery" {
+		t.Errorf("refinery key = %q", got)
+	}
+	if got := c.SigningKeyFor("gastown", "crew", "max"); got != "" {
+		t.Errorf("crew is not signed by default, got key %q", got)
+	}
+
+	for _, s := range []*GitSigning{
+		{Format: "x509"},
+		{Format: SigningOpenPGP, Key: SigningKeyAgent},
+		{Roles: []string{"deacon"}},
+	} {
+		if err := (&GitConfig{Signing: s}).Validate(); !errors.Is(err, ErrInvalidGit) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidGit", s, err)
+		}
+	}
+}
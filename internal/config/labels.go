@@ -0,0 +1,267 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/suggest"
+)
+
+// ErrInvalidLabels indicates a malformed label taxonomy.
+var ErrInvalidLabels = errors.New("invalid labels config")
+
+// ErrLabelNotAllowed is returned for a label outside the rig's taxonomy.
+var ErrLabelNotAllowed = errors.New("label not in the rig's taxonomy")
+
+// Label enforcement modes.
+const (
+	// LabelsWarn reports labels outside the taxonomy but applies them.
+	LabelsWarn = "warn"
+
+	// LabelsStrict refuses labels outside the taxonomy.
+	LabelsStrict = "strict"
+)
+
+// reservedLabelNamespaces are namespaces gt itself writes (gt:agent,
+// done-cp:<stage>:..., ...), always allowed.
+var reservedLabelNamespaces = []string{"gt", "done-intent", "done-cp"}
+
+// builtinLabels are other labels gt itself applies, always allowed.
+var builtinLabels = []string{
+	"possible-duplicate", "acked", "resolved", "reescalated", "digest",
+	"status:docked", "ci:red",
+}
+
+// maxLabelTypoDistance is how many edits from a declared namespace an
+// undeclared one may be before it is taken for a typo of it.
+const maxLabelTypoDistance = 2
+
+// labelColorRe matches a color hint: a basic terminal color name or #rrggbb.
+var labelColorRe = regexp.MustCompile(`^(black|red|green|yellow|blue|magenta|cyan|white|gray|#[0-9a-fA-F]{6})$`)
+
+// LabelsConfig is the rig's label registry, so typos (status:dock for
+// status:docked) are caught when gt labels a bead instead of spreading.
+//
+// A label in a declared namespace ("status:docked" in "status") must use
+// one of the namespace's values, if it lists any. A namespace within two
+// edits of a declared one is taken for a typo; other namespaces are left
+// alone, since gt and other tools use many of their own. Labels without a
+// namespace are checked only once Labels declares some. Labels gt itself
+// applies are always allowed.
+type LabelsConfig struct {
+	// Enforce is "warn" (default) or "strict".
+	Enforce string `json:"enforce,omitempty"`
+
+	// Namespaces maps a namespace to its rule.
+	Namespaces map[string]*LabelNamespace `json:"namespaces,omitempty"`
+
+	// Labels are the allowed labels without a namespace.
+	Labels map[string]*LabelInfo `json:"labels,omitempty"`
+}
+
+// LabelNamespace is the rule for labels "<namespace>:<value>".
+type LabelNamespace struct {
+	Description string `json:"description,omitempty"`
+
+	// Values allowed after the colon. Empty allows any value.
+	Values []string `json:"values,omitempty"`
+
+	// Color is a display hint: a basic color name or #rrggbb.
+	Color string `json:"color,omitempty"`
+}
+
+// LabelInfo describes a label without a namespace.
+type LabelInfo struct {
+	Description string `json:"description,omitempty"`
+
+	// Color is a display hint: a basic color name or #rrggbb.
+	Color string `json:"color,omitempty"`
+}
+
+// IsStrict reports whether labels outside the taxonomy are refused.
+func (c *LabelsConfig) IsStrict() bool {
+	return c != nil && c.Enforce == LabelsStrict
+}
+
+// CheckLabel returns nil if label fits the taxonomy, or an error wrapping
+// ErrLabelNotAllowed that suggests the closest label that does.
+func (c *LabelsConfig) CheckLabel(label string) error {
+	if c == nil {
+		return nil
+	}
+	if slices.Contains(builtinLabels, label) {
+		return nil
+	}
+	ns, value, namespaced := strings.Cut(label, ":")
+	if !namespaced {
+		if len(c.Labels) == 0 || hasKey(c.Labels, label) {
+			return nil
+		}
+		return notAllowed(label, "", closest(label, sortedKeys(c.Labels)))
+	}
+	if slices.Contains(reservedLabelNamespaces, ns) {
+		return nil
+	}
+	if rule, ok := c.Namespaces[ns]; ok {
+		if rule == nil || len(rule.Values) == 0 || slices.Contains(rule.Values, value) {
+			return nil
+		}
+		return notAllowed(label, "unknown "+ns+" value", ns+":"+closest(value, rule.Values))
+	}
+	for _, declared := range sortedKeys(c.Namespaces) {
+		if suggest.Distance(ns, declared) <= maxLabelTypoDistance {
+			return notAllowed(label, "unknown namespace", declared+":"+value)
+		}
+	}
+	return nil
+}
+
+// notAllowed builds the ErrLabelNotAllowed error for label.
+func notAllowed(label, why, suggestion string) error {
+	if why == "" {
+		why = "unknown label"
+	}
+	hint := ""
+	if suggestion != "" && !strings.HasSuffix(suggestion, ":") {
+		hint = fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return fmt.Errorf("%w: %s %q%s", ErrLabelNotAllowed, why, label, hint)
+}
+
+// hasKey reports whether m has key, even with a nil value.
+func hasKey[V any](m map[string]V, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// closest returns the candidate most like s, or "" if there are none.
+func closest(s string, candidates []string) string {
+	if best := suggest.FindSimilar(s, candidates, 1); len(best) > 0 {
+		return best[0]
+	}
+	return ""
+}
+
+// AllowedLabels lists the taxonomy's labels, sorted: bare labels, each
+// namespace value as ns:value, and ns:* for namespaces allowing any value.
+func (c *LabelsConfig) AllowedLabels() []string {
+	if c == nil {
+		return nil
+	}
+	labels := sortedKeys(c.Labels)
+	for ns, rule := range c.Namespaces {
+		if rule == nil || len(rule.Values) == 0 {
+			labels = append(labels, ns+":*")
+			continue
+		}
+		for _, v := range rule.Values {
+			labels = append(labels, ns+":"+v)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// LabelColor returns the color hint for label, from its own entry or its
+// namespace's, or "".
+func (c *LabelsConfig) LabelColor(label string) string {
+	if c == nil {
+		return ""
+	}
+	if info := c.Labels[label]; info != nil {
+		return info.Color
+	}
+	if ns, _, ok := strings.Cut(label, ":"); ok {
+		if rule := c.Namespaces[ns]; rule != nil {
+			return rule.Color
+		}
+	}
+	return ""
+}
+
+// RenameLabel renames oldLabel to newLabel in the taxonomy, whether it is
+// a label without a namespace or a namespace value, keeping the order of
+// values and a label's description and color. It reports whether oldLabel
+// was declared.
+func (c *LabelsConfig) RenameLabel(oldLabel, newLabel string) bool {
+	if c == nil {
+		return false
+	}
+	newNS, newValue, newNamespaced := strings.Cut(newLabel, ":")
+	var info *LabelInfo
+	if i, ok := c.Labels[oldLabel]; ok {
+		delete(c.Labels, oldLabel)
+		info = i
+	} else {
+		ns, value, ok := strings.Cut(oldLabel, ":")
+		if !ok || c.Namespaces[ns] == nil {
+			return false
+		}
+		rule := c.Namespaces[ns]
+		i := slices.Index(rule.Values, value)
+		if i < 0 {
+			return false
+		}
+		if newNamespaced && newNS == ns {
+			rule.Values[i] = newValue
+			return true
+		}
+		rule.Values = slices.Delete(rule.Values, i, i+1)
+	}
+
+	if !newNamespaced {
+		if c.Labels == nil {
+			c.Labels = make(map[string]*LabelInfo)
+		}
+		c.Labels[newLabel] = info
+	} else if rule := c.Namespaces[newNS]; rule != nil && len(rule.Values) > 0 && !slices.Contains(rule.Values, newValue) {
+		rule.Values = append(rule.Values, newValue)
+	}
+	return true
+}
+
+// Validate checks the enforcement mode, names and color hints.
+func (c *LabelsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Enforce {
+	case "", LabelsWarn, LabelsStrict:
+	default:
+		return fmt.Errorf("%w: unknown enforce mode %q (valid: warn, strict)", ErrInvalidLabels, c.Enforce)
+	}
+	for _, ns := range sortedKeys(c.Namespaces) {
+		if ns == "" || strings.ContainsAny(ns, ": ") {
+			return fmt.Errorf("%w: invalid namespace %q", ErrInvalidLabels, ns)
+		}
+		if slices.Contains(reservedLabelNamespaces, ns) {
+			return fmt.Errorf("%w: namespace %q is reserved for gt", ErrInvalidLabels, ns)
+		}
+		if rule := c.Namespaces[ns]; rule != nil && rule.Color != "" && !labelColorRe.MatchString(rule.Color) {
+			return fmt.Errorf("%w: namespace %q: invalid color %q", ErrInvalidLabels, ns, rule.Color)
+		}
+	}
+	for _, label := range sortedKeys(c.Labels) {
+		if label == "" || strings.ContainsAny(label, ": ") {
+			return fmt.Errorf("%w: invalid label %q (namespaced labels belong in namespaces)", ErrInvalidLabels, label)
+		}
+		if info := c.Labels[label]; info != nil && info.Color != "" && !labelColorRe.MatchString(info.Color) {
+			return fmt.Errorf("%w: label %q: invalid color %q", ErrInvalidLabels, label, info.Color)
+		}
+	}
+	return nil
+}
+
+// LoadLabelsConfig returns the rig's label taxonomy, or nil when it has
+// none and any label is allowed.
+func LoadLabelsConfig(rigPath string) *LabelsConfig {
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Labels
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testTaxonomy() *LabelsConfig {
+	return &LabelsConfig{
+		Namespaces: map[string]*LabelNamespace{
+			"status":   {Values: []string{"docked", "parked"}, Color: "yellow"},
+			"priority": {Values: []string{"low", "high"}},
+			"area":     {},
+		},
+		Labels: map[string]*LabelInfo{"flaky": {Color: "red"}, "security": nil},
+	}
+}
+
+func TestLabelsConfigCheckLabel(t *testing.T) {
+	c := testTaxonomy()
+	tests := []struct {
+		label      string
+		wantErr    bool
+		suggestion string
+	}{
+		{"status:parked", false, ""},
+		{"area:anything", false, ""},
+		{"flaky", false, ""},
+		{"security", false, ""},
+		{"gt:agent", false, ""},
+		{"done-cp:push:ok:1700000000", false, ""},
+		{"possible-duplicate", false, ""},
+		{"ci:red", false, ""},
+		{"owner:alice", false, ""}, // undeclared namespace, not a typo
+		{"status:dock", true, `"status:docked"`},
+		{"stauts:docked", true, `"status:docked"`},
+		{"flakey", true, `"flaky"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			err := c.CheckLabel(tt.label)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckLabel(%q) = %v, wantErr %v", tt.label, err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrLabelNotAllowed) {
+				t.Errorf("CheckLabel(%q) = %v, want ErrLabelNotAllowed", tt.label, err)
+			}
+			if !strings.Contains(err.Error(), tt.suggestion) {
+				t.Errorf("CheckLabel(%q) = %v, want suggestion %s", tt.label, err, tt.suggestion)
+			}
+		})
+	}
+
+	var nilCfg *LabelsConfig
+	if err := nilCfg.CheckLabel("anything:goes"); err != nil {
+		t.Errorf("nil CheckLabel = %v", err)
+	}
+	if err := (&LabelsConfig{}).CheckLabel("bare"); err != nil {
+		t.Errorf("CheckLabel with no declared labels = %v", err)
+	}
+}
+
+func TestLabelsConfigAllowedLabelsAndColor(t *testing.T) {
+	c := testTaxonomy()
+	want := []string{"area:*", "flaky", "priority:high", "priority:low", "security", "status:docked", "status:parked"}
+	if got := c.AllowedLabels(); !slices.Equal(got, want) {
+		t.Errorf("AllowedLabels = %v, want %v", got, want)
+	}
+	if got := c.LabelColor("status:docked"); got != "yellow" {
+		t.Errorf("LabelColor(status:docked) = %q", got)
+	}
+	if got := c.LabelColor("flaky"); got != "red" {
+		t.Errorf("LabelColor(flaky) = %q", got)
+	}
+}
+
+func TestLabelsConfigRenameLabel(t *testing.T) {
+	c := testTaxonomy()
+	if !c.RenameLabel("status:docked", "status:moored") {
+		t.Fatal("RenameLabel(status:docked) = false")
+	}
+	if got := c.Namespaces["status"].Values; !slices.Equal(got, []string{"moored", "parked"}) {
+		t.Errorf("status values = %v", got)
+	}
+	if !c.RenameLabel("flaky", "intermittent") {
+		t.Fatal("RenameLabel(flaky) = false")
+	}
+	if info := c.Labels["intermittent"]; info == nil || info.Color != "red" || hasKey(c.Labels, "flaky") {
+		t.Errorf("Labels = %v", c.Labels)
+	}
+	if !c.RenameLabel("security", "priority:urgent") {
+		t.Fatal("RenameLabel(security) = false")
+	}
+	if got := c.Namespaces["priority"].Values; !slices.Contains(got, "urgent") {
+		t.Errorf("priority values = %v", got)
+	}
+	if c.RenameLabel("status:dock", "status:docked") {
+		t.Error("RenameLabel of an undeclared label = true")
+	}
+}
+
+func TestLabelsConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *LabelsConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"taxonomy", testTaxonomy(), false},
+		{"hex color", &LabelsConfig{Labels: map[string]*LabelInfo{"x": {Color: "#ff8800"}}}, false},
+		{"unknown mode", &LabelsConfig{Enforce: "block"}, true},
+		{"reserved namespace", &LabelsConfig{Namespaces: map[string]*LabelNamespace{"gt": {}}}, true},
+		{"namespaced bare label", &LabelsConfig{Labels: map[string]*LabelInfo{"a:b": {}}}, true},
+		{"bad color", &LabelsConfig{Namespaces: map[string]*LabelNamespace{"a": {Color: "orange"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidLabels) {
+				t.Errorf("Validate() = %v, want ErrInvalidLabels", err)
+			}
+		})
+	}
+}
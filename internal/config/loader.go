@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // resolveConfigMu serializes agent config resolution across all callers.
@@ -37,7 +38,7 @@ var (
 
 // LoadTownConfig loads and validates a town configuration file.
 func LoadTownConfig(path string) (*TownConfig, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted config location
+	data, err := ReadConfigFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
@@ -67,12 +68,13 @@ func SaveTownConfig(path string, config *TownConfig) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	resolved, format := ResolveConfigPath(path)
+	data, err := marshalWithSchema(config, format, SchemaTown)
 	if err != nil {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := util.AtomicWriteFile(resolved, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -145,6 +147,11 @@ func validateRigsConfig(c *RigsConfig) error {
 	if c.Rigs == nil {
 		c.Rigs = make(map[string]RigEntry)
 	}
+	for name, entry := range c.Rigs {
+		if err := entry.Remote.Validate(); err != nil {
+			return fmt.Errorf("rig %s: %w", name, err)
+		}
+	}
 	return nil
 }
 
@@ -219,6 +226,27 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if err := c.Schedule.Validate(); err != nil {
+		return err
+	}
+	if err := c.Container.Validate(); err != nil {
+		return err
+	}
+	if err := c.Scheduling.Validate(); err != nil {
+		return err
+	}
+	if err := c.CI.Validate(); err != nil {
+		return err
+	}
+	if err := c.Git.Validate(); err != nil {
+		return err
+	}
+	if err := c.Dedup.Validate(); err != nil {
+		return err
+	}
+	if err := c.Labels.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -232,6 +260,10 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: got '%s', want '%s' or '%s'",
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
+	if c.OnDoltConflict != "" && c.OnDoltConflict != OnDoltConflictTheirs && c.OnDoltConflict != OnDoltConflictEscalate {
+		return fmt.Errorf("%w: on_dolt_conflict got '%s', want '%s' or '%s'",
+			ErrInvalidOnConflict, c.OnDoltConflict, OnDoltConflictTheirs, OnDoltConflictEscalate)
+	}
 
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
@@ -251,17 +283,81 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		}
 	}
 
+	// Validate normal_lane_max_wait if specified
+	if c.NormalLaneMaxWait != "" {
+		dur, err := time.ParseDuration(c.NormalLaneMaxWait)
+		if err != nil {
+			return fmt.Errorf("invalid normal_lane_max_wait: %w", err)
+		}
+		if dur < 0 {
+			return fmt.Errorf("normal_lane_max_wait must be non-negative, got %v", dur)
+		}
+	}
+
 	// Validate non-negative values
 	if c.RetryFlakyTests < 0 {
 		return fmt.Errorf("%w: retry_flaky_tests must be non-negative", ErrMissingField)
 	}
+	if c.FlakeQuarantineThreshold < 0 {
+		return fmt.Errorf("%w: flake_quarantine_threshold must be non-negative", ErrMissingField)
+	}
 	if c.MaxConcurrent < 0 {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
+	if c.ExpediteMaxConcurrent != nil && *c.ExpediteMaxConcurrent < 0 {
+		return fmt.Errorf("%w: expedite_max_concurrent must be non-negative", ErrMissingField)
+	}
+	if c.Release != nil && c.Release.Every < 0 {
+		return fmt.Errorf("%w: release.every must be non-negative", ErrMissingField)
+	}
+
+	for i, r := range c.TestRules {
+		if len(r.Paths) == 0 {
+			return fmt.Errorf("%w: test rule %d needs paths", ErrMissingField, i)
+		}
+		if (strings.TrimSpace(r.Cmd) != "") == r.Skip {
+			return fmt.Errorf("test rule %d: set exactly one of cmd or skip", i)
+		}
+	}
+
+	if err := validateMergeHooks("pre_merge", c.PreMerge); err != nil {
+		return err
+	}
+	if err := validateMergeHooks("post_merge", c.PostMerge); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// validateMergeHooks checks that each hook in a pipeline has a unique name,
+// a command, and a valid positive timeout if one is set.
+func validateMergeHooks(phase string, hooks []MergeHookConfig) error {
+	seen := make(map[string]bool, len(hooks))
+	for i, h := range hooks {
+		if strings.TrimSpace(h.Name) == "" {
+			return fmt.Errorf("%w: %s hook %d needs a name", ErrMissingField, phase, i)
+		}
+		if seen[h.Name] {
+			return fmt.Errorf("%s hook %q: duplicate name", phase, h.Name)
+		}
+		seen[h.Name] = true
+		if strings.TrimSpace(h.Cmd) == "" {
+			return fmt.Errorf("%w: %s hook %q needs a cmd", ErrMissingField, phase, h.Name)
+		}
+		if h.Timeout != "" {
+			dur, err := time.ParseDuration(h.Timeout)
+			if err != nil {
+				return fmt.Errorf("invalid timeout for %s hook %q: %w", phase, h.Name, err)
+			}
+			if dur <= 0 {
+				return fmt.Errorf("%s hook %q timeout must be positive, got %v", phase, h.Name, dur)
+			}
+		}
+	}
+	return nil
+}
+
 // NewRigConfig creates a new RigConfig (identity only).
 func NewRigConfig(name, gitURL string) *RigConfig {
 	return &RigConfig{
@@ -284,7 +380,7 @@ func NewRigSettings() *RigSettings {
 
 // LoadRigSettings loads and validates a rig settings file.
 func LoadRigSettings(path string) (*RigSettings, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	data, err := ReadConfigFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
@@ -339,12 +435,13 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
+	resolved, format := ResolveConfigPath(path)
+	data, err := marshalWithSchema(settings, format, SchemaRigSettings)
 	if err != nil {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := util.AtomicWriteFile(resolved, data, 0644); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -403,6 +500,16 @@ func validateMayorConfig(c *MayorConfig) error {
 	if c.Version > CurrentMayorConfigVersion {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentMayorConfigVersion)
 	}
+	if d := c.RigDiscovery; d != nil {
+		if d.ScanDepth < 0 {
+			return fmt.Errorf("rig_discovery.scan_depth must not be negative, got %d", d.ScanDepth)
+		}
+		for _, pattern := range append(append([]string{}, d.Include...), d.Exclude...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid rig_discovery glob %q: %w", pattern, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -421,7 +528,7 @@ func DaemonPatrolConfigPath(townRoot string) string {
 
 // LoadDaemonPatrolConfig loads and validates a daemon patrol config file.
 func LoadDaemonPatrolConfig(path string) (*DaemonPatrolConfig, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	data, err := ReadConfigFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
@@ -451,12 +558,13 @@ func SaveDaemonPatrolConfig(path string, config *DaemonPatrolConfig) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	resolved, format := ResolveConfigPath(path)
+	data, err := marshalWithSchema(config, format, SchemaDaemon)
 	if err != nil {
 		return fmt.Errorf("encoding daemon patrol config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := util.AtomicWriteFile(resolved, data, 0644); err != nil {
 		return fmt.Errorf("writing daemon patrol config: %w", err)
 	}
 
@@ -470,6 +578,19 @@ func validateDaemonPatrolConfig(c *DaemonPatrolConfig) error {
 	if c.Version > CurrentDaemonPatrolConfigVersion {
 		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentDaemonPatrolConfigVersion)
 	}
+	for name, p := range c.Patrols {
+		for _, f := range [][2]string{{"interval", p.Interval}, {"timeout", p.Timeout}} {
+			if f[1] == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(f[1]); err != nil || d <= 0 {
+				return fmt.Errorf("patrol %q: invalid %s %q", name, f[0], f[1])
+			}
+		}
+		if p.Exec != "" && p.Interval == "" {
+			return fmt.Errorf("patrol %q: custom patrols require an interval", name)
+		}
+	}
 	return nil
 }
 
@@ -647,6 +768,19 @@ func validateAccountsConfig(c *AccountsConfig) error {
 			return fmt.Errorf("%w: config_dir for account '%s'", ErrMissingField, handle)
 		}
 	}
+	if c.Policy != nil {
+		switch c.Policy.Mode {
+		case "", AccountPolicyDefault, AccountPolicyRoundRobin:
+		default:
+			return fmt.Errorf("invalid account policy mode '%s': want '%s' or '%s'",
+				c.Policy.Mode, AccountPolicyDefault, AccountPolicyRoundRobin)
+		}
+		for rigName, handle := range c.Policy.RigAccounts {
+			if _, ok := c.Accounts[handle]; !ok {
+				return fmt.Errorf("%w: account '%s' for rig '%s' not found in accounts", ErrMissingField, handle, rigName)
+			}
+		}
+	}
 	return nil
 }
 
@@ -732,7 +866,7 @@ func expandPath(path string) string {
 
 // LoadMessagingConfig loads and validates a messaging configuration file.
 func LoadMessagingConfig(path string) (*MessagingConfig, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	data, err := ReadConfigFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
@@ -762,12 +896,13 @@ func SaveMessagingConfig(path string, config *MessagingConfig) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	resolved, format := ResolveConfigPath(path)
+	data, err := marshalWithSchema(config, format, SchemaMessaging)
 	if err != nil {
 		return fmt.Errorf("encoding messaging config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
+	if err := util.AtomicWriteFile(resolved, data, 0644); err != nil {
 		return fmt.Errorf("writing messaging config: %w", err)
 	}
 
@@ -866,7 +1001,7 @@ func RigSettingsPath(rigPath string) string {
 
 // LoadOrCreateTownSettings loads town settings or creates defaults if missing.
 func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	data, err := ReadConfigFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return NewTownSettings(), nil
@@ -881,25 +1016,56 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	return &settings, nil
 }
 
+// LoadTownSettings loads and validates a town settings file.
+func LoadTownSettings(path string) (*TownSettings, error) {
+	data, err := ReadConfigFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading settings: %w", err)
+	}
+
+	var settings TownSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("parsing settings: %w", err)
+	}
+
+	if err := validateTownSettings(&settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// validateTownSettings validates a TownSettings.
+func validateTownSettings(c *TownSettings) error {
+	if c.Type != "town-settings" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentTownSettingsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentTownSettingsVersion)
+	}
+	return nil
+}
+
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
-	if settings.Type != "town-settings" && settings.Type != "" {
-		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, settings.Type)
-	}
-	if settings.Version > CurrentTownSettingsVersion {
-		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
+	if err := validateTownSettings(settings); err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(settings, "", "  ")
+	resolved, format := ResolveConfigPath(path)
+	data, err := marshalWithSchema(settings, format, SchemaTownSettings)
 	if err != nil {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := util.AtomicWriteFile(resolved, data, 0644); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -950,7 +1116,7 @@ func resolveAgentConfigInternal(townRoot, rigPath string) *RuntimeConfig {
 	}
 
 	// Load custom agent registry if it exists
-	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	_ = LoadTownAgentRegistry(townRoot)
 
 	// Load rig-level custom agent registry if it exists (for per-rig custom agents)
 	_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
@@ -1005,7 +1171,7 @@ func resolveAgentConfigWithOverrideInternal(townRoot, rigPath, agentOverride str
 	}
 
 	// Load custom agent registry if it exists
-	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	_ = LoadTownAgentRegistry(townRoot)
 
 	// Load rig-level custom agent registry if it exists (for per-rig custom agents)
 	_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
@@ -1059,10 +1225,14 @@ func ValidateAgentConfig(agentName string, townSettings *TownSettings, rigSettin
 	}
 
 	// Check if binary exists on system
-	if _, err := exec.LookPath(rc.Command); err != nil {
+	if _, err := LookupAgentBinary(rc.Command); err != nil {
 		return fmt.Errorf("agent %q binary %q not found in PATH", agentName, rc.Command)
 	}
 
+	if err := ValidateRuntimeArgs(rc); err != nil {
+		return fmt.Errorf("agent %q: %w", agentName, err)
+	}
+
 	return nil
 }
 
@@ -1263,7 +1433,7 @@ func resolveRoleAgentConfigCore(role, townRoot, rigPath string) *RuntimeConfig {
 	}
 
 	// Load custom agent registries
-	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	_ = LoadTownAgentRegistry(townRoot)
 	if rigPath != "" {
 		_ = LoadRigAgentRegistry(RigAgentRegistryPath(rigPath))
 	}
@@ -1456,6 +1626,13 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 		copy(result.Args, rc.Args)
 	}
 
+	if rc.ArgGroups != nil {
+		result.ArgGroups = make([]RuntimeArgGroup, len(rc.ArgGroups))
+		for i, g := range rc.ArgGroups {
+			result.ArgGroups[i] = RuntimeArgGroup{IfEnv: g.IfEnv, Args: append([]string(nil), g.Args...)}
+		}
+	}
+
 	// Deep copy Env map
 	if len(rc.Env) > 0 {
 		result.Env = make(map[string]string, len(rc.Env))
@@ -1714,101 +1891,9 @@ func ExtractSimpleRole(gtRole string) string {
 // (ResolveRoleAgentConfig) to select the appropriate agent for the role.
 // This enables per-role model selection via role_agents in settings.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
-	var rc *RuntimeConfig
-	var townRoot string
-
-	// Extract role from envVars for role-based agent resolution.
-	// GT_ROLE may be compound format (e.g., "rig/refinery") so we extract
-	// the simple role name for role_agents lookup.
-	role := ExtractSimpleRole(envVars["GT_ROLE"])
-
-	if rigPath != "" {
-		// Derive town root from rig path
-		townRoot = filepath.Dir(rigPath)
-		if role != "" {
-			// Use role-based agent resolution for per-role model selection
-			rc = ResolveRoleAgentConfig(role, townRoot, rigPath)
-		} else {
-			rc = ResolveAgentConfig(townRoot, rigPath)
-		}
-	} else {
-		// For town-level agents (mayor, deacon), prefer GT_ROOT from envVars
-		// (set by AgentEnv) over cwd detection. This ensures role_agents config
-		// is respected even when the daemon runs outside the town hierarchy.
-		townRoot = envVars["GT_ROOT"]
-		if townRoot == "" {
-			var err error
-			townRoot, err = findTownRootFromCwd()
-			if err != nil {
-				rc = DefaultRuntimeConfig()
-			}
-		}
-		if rc == nil {
-			if role != "" {
-				rc = ResolveRoleAgentConfig(role, townRoot, "")
-			} else {
-				rc = ResolveAgentConfig(townRoot, "")
-			}
-		}
-	}
-
-	// Copy env vars to avoid mutating caller map
-	resolvedEnv := make(map[string]string, len(envVars)+2)
-	for k, v := range envVars {
-		resolvedEnv[k] = v
-	}
-	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
-	if townRoot != "" {
-		resolvedEnv["GT_ROOT"] = townRoot
-	}
-	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
-		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
-	}
-	// Set GT_AGENT from resolved agent name so IsAgentAlive can detect
-	// non-Claude processes (e.g., opencode). Without this, witness patrol
-	// falls back to ["node", "claude"] process detection and auto-nukes
-	// polecats running non-Claude agents. See: gt-agent-role-agents.
-	if rc.ResolvedAgent != "" {
-		resolvedEnv["GT_AGENT"] = rc.ResolvedAgent
-	}
-	// Set GT_PROCESS_NAMES for accurate liveness detection. Custom agents may
-	// shadow built-in preset names (e.g., custom "codex" running "opencode"),
-	// so we resolve process names from both agent name and actual command.
-	processNames := ResolveProcessNames(rc.ResolvedAgent, rc.Command)
-	resolvedEnv["GT_PROCESS_NAMES"] = strings.Join(processNames, ",")
-	// Merge agent-specific env vars (e.g., OPENCODE_PERMISSION for yolo mode)
-	for k, v := range rc.Env {
-		resolvedEnv[k] = v
-	}
-
-	SanitizeAgentEnv(resolvedEnv, envVars)
-
-	// Build environment export prefix
-	var exports []string
-	for k, v := range resolvedEnv {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, ShellQuote(v)))
-	}
-
-	// Sort for deterministic output
-	sort.Strings(exports)
-
-	var cmd string
-	if len(exports) > 0 {
-		// Use 'exec env' instead of 'export ... &&' so the agent process
-		// replaces the shell. This allows WaitForCommand to detect the
-		// running agent via pane_current_command (which shows the direct
-		// process, not child processes).
-		cmd = "exec env " + strings.Join(exports, " ") + " "
-	}
-
-	// Add runtime command
-	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
-	} else {
-		cmd += rc.BuildCommand()
-	}
-
-	return cmd
+	// Without an agent override, resolution cannot fail.
+	rc, resolvedEnv, _ := resolveStartupEnv(envVars, rigPath, "")
+	return startupCommand(rc, resolvedEnv, prompt)
 }
 
 // SanitizeAgentEnv clears environment variables that are known to break agent
@@ -1853,12 +1938,10 @@ func PrependEnv(command string, envVars map[string]string) string {
 		return command
 	}
 
-	var exports []string
-	for k, v := range envVars {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, ShellQuote(v)))
+	exports := envExports(envVars)
+	if len(exports) == 0 {
+		return command
 	}
-
-	sort.Strings(exports)
 	return "export " + strings.Join(exports, " ") + " && " + command
 }
 
@@ -1870,6 +1953,18 @@ func PrependEnv(command string, envVars map[string]string) string {
 //  2. role_agents[GT_ROLE] (if GT_ROLE is in envVars)
 //  3. Default agent resolution (rig's Agent → town's DefaultAgent → "claude")
 func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, prompt, agentOverride string) (string, error) {
+	rc, resolvedEnv, err := resolveStartupEnv(envVars, rigPath, agentOverride)
+	if err != nil {
+		return "", err
+	}
+	return startupCommand(rc, resolvedEnv, prompt), nil
+}
+
+// resolveStartupEnv resolves the runtime config for a startup command and
+// the environment it runs with: envVars plus GT_ROOT, the agent's identity
+// and process names, and the agent's own env settings. See
+// BuildStartupCommandWithAgentOverride for the resolution order.
+func resolveStartupEnv(envVars map[string]string, rigPath, agentOverride string) (*RuntimeConfig, map[string]string, error) {
 	var rc *RuntimeConfig
 	var townRoot string
 
@@ -1882,7 +1977,7 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 			var err error
 			rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
 			if err != nil {
-				return "", err
+				return nil, nil, err
 			}
 		} else if role != "" {
 			// No override, use role-based agent resolution
@@ -1906,7 +2001,7 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 					if preset := GetAgentPresetByName(agentOverride); preset != nil {
 						rc = RuntimeConfigFromPreset(AgentPreset(agentOverride))
 					} else {
-						return "", fmt.Errorf("agent '%s' not found", agentOverride)
+						return nil, nil, fmt.Errorf("agent '%s' not found", agentOverride)
 					}
 				} else {
 					rc = DefaultRuntimeConfig()
@@ -1918,7 +2013,7 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 				var resolveErr error
 				rc, _, resolveErr = ResolveAgentConfigWithOverride(townRoot, "", agentOverride)
 				if resolveErr != nil {
-					return "", resolveErr
+					return nil, nil, resolveErr
 				}
 			} else if role != "" {
 				rc = ResolveRoleAgentConfig(role, townRoot, "")
@@ -1933,6 +2028,8 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	for k, v := range envVars {
 		resolvedEnv[k] = v
 	}
+	addGitIdentityEnv(resolvedEnv, rigPath, role)
+	addDoltPasswordEnv(resolvedEnv, rigPath)
 	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
 	if townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
@@ -1958,16 +2055,17 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	}
 
 	SanitizeAgentEnv(resolvedEnv, envVars)
+	rc.ArgContext = ArgContextFromEnv(resolvedEnv)
 
-	// Build environment export prefix
-	var exports []string
-	for k, v := range resolvedEnv {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, ShellQuote(v)))
-	}
-	sort.Strings(exports)
+	return rc, resolvedEnv, nil
+}
 
+// startupCommand renders rc as an "exec env" command running with env.
+// Secret references are left out: launchers resolve them with
+// StartupSecrets and pass the values through the agent's environment.
+func startupCommand(rc *RuntimeConfig, env map[string]string, prompt string) string {
 	var cmd string
-	if len(exports) > 0 {
+	if exports := envExports(env); len(exports) > 0 {
 		// Use 'exec env' instead of 'export ... &&' so the agent process
 		// replaces the shell. This allows WaitForCommand to detect the
 		// running agent via pane_current_command (which shows the direct
@@ -1980,8 +2078,22 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	} else {
 		cmd += rc.BuildCommand()
 	}
+	return cmd
+}
 
-	return cmd, nil
+// envExports renders env as sorted, shell-quoted NAME=value assignments,
+// skipping secret references ("secret:NAME"), whose values must never
+// appear on a command line.
+func envExports(env map[string]string) []string {
+	exports := make([]string, 0, len(env))
+	for k, v := range env {
+		if _, ok := ParseSecretRef(v); ok {
+			continue
+		}
+		exports = append(exports, fmt.Sprintf("%s=%s", k, ShellQuote(v)))
+	}
+	sort.Strings(exports)
+	return exports
 }
 
 // BuildAgentStartupCommand is a convenience function for starting agent sessions.
@@ -2070,16 +2182,14 @@ func BuildCrewStartupCommandWithAgentOverride(rigName, crewName, rigPath, prompt
 }
 
 // ExpectedPaneCommands returns tmux pane command names that indicate the runtime is running.
-// Claude can report as "node" (older versions) or "claude" (newer versions).
-// Other runtimes typically report their executable name.
+// The names come from the ProcessNames of the registered preset launching rc.Command
+// (Claude reports as "node" or "claude"), so drop-in presets in agents.d supply their own.
+// Unknown runtimes report their executable name.
 func ExpectedPaneCommands(rc *RuntimeConfig) []string {
 	if rc == nil || rc.Command == "" {
 		return nil
 	}
-	if filepath.Base(rc.Command) == "claude" {
-		return []string{"node", "claude"}
-	}
-	return []string{filepath.Base(rc.Command)}
+	return ResolveProcessNames("", rc.Command)
 }
 
 // GetDefaultFormula returns the default formula for a rig from settings/config.json.
@@ -2147,7 +2257,7 @@ func EscalationConfigPath(townRoot string) string {
 
 // LoadEscalationConfig loads and validates an escalation configuration file.
 func LoadEscalationConfig(path string) (*EscalationConfig, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	data, err := ReadConfigFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
@@ -2189,12 +2299,13 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	resolved, format := ResolveConfigPath(path)
+	data, err := marshalWithSchema(config, format, SchemaEscalation)
 	if err != nil {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
+	if err := util.AtomicWriteFile(resolved, data, 0644); err != nil {
 		return fmt.Errorf("writing escalation config: %w", err)
 	}
 
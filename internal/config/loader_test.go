@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -328,6 +329,28 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid on_dolt_conflict",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					OnDoltConflict: "ours",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "on_dolt_conflict escalate",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					OnDoltConflict: OnDoltConflictEscalate,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -479,6 +502,29 @@ func TestLoadMayorConfigNotFound(t *testing.T) {
 	}
 }
 
+func TestValidateMayorConfig_RigDiscovery(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		disc    RigDiscoveryConfig
+		wantErr bool
+	}{
+		{"valid", RigDiscoveryConfig{ScanDepth: 3, Include: []string{"clients/*/*"}, Exclude: []string{"archive/*"}}, false},
+		{"negative depth", RigDiscoveryConfig{ScanDepth: -1}, true},
+		{"bad include", RigDiscoveryConfig{Include: []string{"clients/[a"}}, true},
+		{"bad exclude", RigDiscoveryConfig{Exclude: []string{`archive\`}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMayorConfig()
+			c.RigDiscovery = &tt.disc
+			if err := validateMayorConfig(c); (err != nil) != tt.wantErr {
+				t.Errorf("validateMayorConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAccountsConfigRoundTrip(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
@@ -577,6 +623,40 @@ func TestAccountsConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid round-robin policy with rig account",
+			config: &AccountsConfig{
+				Version: 1,
+				Accounts: map[string]Account{
+					"test": {Email: "test@example.com", ConfigDir: "~/.claude-accounts/test"},
+				},
+				Policy: &AccountPolicy{
+					Mode:        AccountPolicyRoundRobin,
+					RigAccounts: map[string]string{"gastown": "test"},
+					Failover:    true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "policy with unknown mode",
+			config: &AccountsConfig{
+				Version: 1,
+				Policy:  &AccountPolicy{Mode: "random"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "policy rig account refers to nonexistent account",
+			config: &AccountsConfig{
+				Version: 1,
+				Accounts: map[string]Account{
+					"test": {Email: "test@example.com", ConfigDir: "~/.claude-accounts/test"},
+				},
+				Policy: &AccountPolicy{RigAccounts: map[string]string{"gastown": "missing"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2046,6 +2126,31 @@ func TestAddRigToDaemonPatrols(t *testing.T) {
 	})
 }
 
+func TestLoadTownSettings(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	if _, err := LoadTownSettings(filepath.Join(dir, "missing.json")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file: err = %v, want ErrNotFound", err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"type": "town-settings", "default_agent": "gemini"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadTownSettings(path)
+	if err != nil || settings.DefaultAgent != "gemini" {
+		t.Errorf("LoadTownSettings = %+v, %v", settings, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"type": "rig-settings"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTownSettings(path); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("wrong type: err = %v, want ErrInvalidType", err)
+	}
+}
+
 func TestSaveTownSettings(t *testing.T) {
 	t.Parallel()
 	t.Run("saves valid town settings", func(t *testing.T) {
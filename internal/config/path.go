@@ -0,0 +1,258 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownKey is returned for a dot-path that names no config field.
+var ErrUnknownKey = errors.New("unknown config key")
+
+// GetPath returns the value at a dot-separated path of json keys in v, a
+// config struct or pointer to one (e.g. "merge_queue.on_conflict"). Map
+// entries are addressed by key. A path through an unset pointer or missing
+// map entry returns the zero value of the field's type.
+func GetPath(v interface{}, path string) (interface{}, error) {
+	t, err := pathType(reflect.TypeOf(v), path)
+	if err != nil {
+		return nil, err
+	}
+	unset := reflect.Zero(t).Interface()
+	cur := reflect.ValueOf(v)
+	for _, seg := range strings.Split(path, ".") {
+		for cur.Kind() == reflect.Pointer {
+			if cur.IsNil() {
+				return unset, nil
+			}
+			cur = cur.Elem()
+		}
+		if cur.Kind() == reflect.Map {
+			cur = cur.MapIndex(reflect.ValueOf(seg).Convert(cur.Type().Key()))
+			if !cur.IsValid() {
+				return unset, nil
+			}
+			continue
+		}
+		i, _ := jsonField(cur.Type(), seg)
+		for n, x := range i {
+			if n > 0 && cur.Kind() == reflect.Pointer {
+				if cur.IsNil() {
+					return unset, nil
+				}
+				cur = cur.Elem()
+			}
+			cur = cur.Field(x)
+		}
+	}
+	return cur.Interface(), nil
+}
+
+// SetPath sets the value at a dot-separated path in v, a pointer to a
+// config struct, allocating unset pointers and maps along the way. value is
+// parsed for the field's type: true/false (also yes/no, on/off, 1/0) for
+// booleans, numbers, durations like "5m" for time.Duration, comma-separated
+// items for string lists, and JSON for anything else.
+//
+// SetPath only checks that value fits the field; callers validate the
+// whole config by saving it.
+func SetPath(v interface{}, path, value string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("SetPath: need a non-nil pointer, got %T", v)
+	}
+	return setPath(rv.Elem(), strings.Split(path, "."), path, value)
+}
+
+func setPath(cur reflect.Value, segs []string, path, value string) error {
+	if len(segs) == 0 {
+		parsed, err := parseValue(cur.Type(), value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", path, err)
+		}
+		cur.Set(parsed)
+		return nil
+	}
+
+	switch cur.Kind() {
+	case reflect.Pointer:
+		if cur.IsNil() {
+			cur.Set(reflect.New(cur.Type().Elem()))
+		}
+		return setPath(cur.Elem(), segs, path, value)
+	case reflect.Struct:
+		i, ok := jsonField(cur.Type(), segs[0])
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownKey, path)
+		}
+		return setPath(fieldByIndexAlloc(cur, i), segs[1:], path, value)
+	case reflect.Map:
+		if cur.IsNil() {
+			cur.Set(reflect.MakeMap(cur.Type()))
+		}
+		key := reflect.ValueOf(segs[0]).Convert(cur.Type().Key())
+		// Map entries aren't addressable: edit a copy and store it back.
+		elem := reflect.New(cur.Type().Elem()).Elem()
+		if existing := cur.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setPath(elem, segs[1:], path, value); err != nil {
+			return err
+		}
+		cur.SetMapIndex(key, elem)
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownKey, path)
+}
+
+// pathType returns the type of the field at path below t, checking every
+// segment names a field.
+func pathType(t reflect.Type, path string) (reflect.Type, error) {
+	for _, seg := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			i, ok := jsonField(t, seg)
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownKey, path)
+			}
+			t = t.FieldByIndex(i).Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKey, path)
+		}
+	}
+	return t, nil
+}
+
+// jsonField finds the field of struct type t that encoding/json uses for
+// key, including fields promoted from untagged embedded structs.
+func jsonField(t reflect.Type, key string) ([]int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if sub, ok := jsonField(ft, key); ok {
+					return append([]int{i}, sub...), true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return []int{i}, true
+		}
+	}
+	return nil, false
+}
+
+// fieldByIndexAlloc is FieldByIndex, allocating nil embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// parseValue parses s as a value of type t.
+func parseValue(t reflect.Type, s string) (reflect.Value, error) {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot parse %q as a duration (e.g. 30s, 5m)", s)
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := parseValue(t.Elem(), s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(elem)
+		return p, nil
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := parseBoolValue(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot parse %q as an integer", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot parse %q as a non-negative integer", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot parse %q as a number", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(s), "[") {
+			// Comma-separated shorthand; "" clears the list.
+			for _, item := range strings.Split(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					v = reflect.Append(v, reflect.ValueOf(item).Convert(t.Elem()))
+				}
+			}
+			return v, nil
+		}
+		fallthrough
+	default:
+		if err := json.Unmarshal([]byte(s), v.Addr().Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot parse %q as JSON: %w", s, err)
+		}
+	}
+	return v, nil
+}
+
+// parseBoolValue parses a boolean string (true/false, yes/no, on/off, 1/0).
+func parseBoolValue(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1", "on":
+		return true, nil
+	case "false", "no", "0", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("cannot parse %q as boolean", s)
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetPath(t *testing.T) {
+	settings := NewRigSettings()
+	settings.MergeQueue = nil
+
+	tests := []struct {
+		path  string
+		value string
+		get   func() interface{}
+		want  interface{}
+	}{
+		{"merge_queue.on_conflict", "auto_rebase", func() interface{} { return settings.MergeQueue.OnConflict }, "auto_rebase"},
+		{"merge_queue.max_concurrent", "3", func() interface{} { return settings.MergeQueue.MaxConcurrent }, 3},
+		{"merge_queue.enabled", "no", func() interface{} { return settings.MergeQueue.Enabled }, false},
+		{"role_agents.polecat", "codex", func() interface{} { return settings.RoleAgents["polecat"] }, "codex"},
+	}
+	for _, tt := range tests {
+		if err := SetPath(settings, tt.path, tt.value); err != nil {
+			t.Fatalf("SetPath(%s, %q): %v", tt.path, tt.value, err)
+		}
+		if got := tt.get(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.path, got, tt.want)
+		}
+		got, err := GetPath(settings, tt.path)
+		if err != nil {
+			t.Fatalf("GetPath(%s): %v", tt.path, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GetPath(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSetPath_MapOfStructs(t *testing.T) {
+	cfg := NewEscalationConfig()
+	if err := SetPath(cfg, "routes.high", "bead, mail:mayor"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"bead", "mail:mayor"}; !reflect.DeepEqual(cfg.Routes["high"], want) {
+		t.Errorf("routes.high = %v, want %v", cfg.Routes["high"], want)
+	}
+
+	msg := NewMessagingConfig()
+	if err := SetPath(msg, "queues.work.max_claims", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetPath(msg, "queues.work.workers", `["gastown/polecats/*"]`); err != nil {
+		t.Fatal(err)
+	}
+	q := msg.Queues["work"]
+	if q.MaxClaims != 2 || len(q.Workers) != 1 {
+		t.Errorf("queues.work = %+v", q)
+	}
+}
+
+func TestSetPath_Errors(t *testing.T) {
+	tests := []struct {
+		path, value string
+		want        string
+	}{
+		{"merge_queue.nope", "x", "unknown config key"},
+		{"merge_queue.on_conflict.deeper", "x", "unknown config key"},
+		{"merge_queue.max_concurrent", "lots", "invalid value for merge_queue.max_concurrent"},
+		{"merge_queue.run_tests", "maybe", "invalid value"},
+		{"role_agents", "{bad", "cannot parse"},
+	}
+	for _, tt := range tests {
+		err := SetPath(NewRigSettings(), tt.path, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("SetPath(%s, %q) = %v, want error containing %q", tt.path, tt.value, err, tt.want)
+		}
+	}
+	if err := SetPath(NewRigSettings(), "nope", "x"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestGetPath_Unset(t *testing.T) {
+	settings := NewTownSettings()
+	got, err := GetPath(settings, "convoy.notify_on_complete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != false {
+		t.Errorf("unset bool = %v, want false", got)
+	}
+	if _, err := GetPath(settings, "convoy.nope"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestSetPath_PointerField(t *testing.T) {
+	settings := NewRigSettings()
+	if err := SetPath(settings, "merge_queue.run_tests", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if rt := settings.MergeQueue.RunTests; rt == nil || *rt {
+		t.Errorf("run_tests = %v, want pointer to false", rt)
+	}
+}
+
+func TestSetPath_Duration(t *testing.T) {
+	var v struct {
+		Every time.Duration `json:"every"`
+	}
+	if err := SetPath(&v, "every", "5m"); err != nil {
+		t.Fatal(err)
+	}
+	if v.Every != 5*time.Minute {
+		t.Errorf("every = %v", v.Every)
+	}
+}
+
+func TestParseBoolValue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+		err   bool
+	}{
+		{"true", true, false},
+		{"True", true, false},
+		{"TRUE", true, false},
+		{"yes", true, false},
+		{"1", true, false},
+		{"on", true, false},
+		{"false", false, false},
+		{"False", false, false},
+		{"no", false, false},
+		{"0", false, false},
+		{"off", false, false},
+		{"maybe", false, true},
+		{"", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseBoolValue(tt.input)
+			if (err != nil) != tt.err {
+				t.Errorf("parseBoolValue(%q) error = %v, wantErr %v", tt.input, err, tt.err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseBoolValue(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// CurrentPermissionsVersion is the current schema version for PermissionsConfig.
+const CurrentPermissionsVersion = 1
+
+// Permission grant forms accepted in PermissionsConfig.
+const (
+	// PermissionGrantAll allows every command.
+	PermissionGrantAll = "*"
+	// PermissionGroupPrefix prefixes a cobra command group grant, e.g. "group:work".
+	PermissionGroupPrefix = "group:"
+)
+
+// PermissionsConfig restricts which gt commands an identity may run on a
+// shared town. Stored in mayor/permissions.json. A town without this file is
+// unrestricted, which is the intended setup for single-user towns.
+//
+// Grants are one of:
+//   - "*"                 every command
+//   - "group:<id>"        every command in a help group (work, agents, comm,
+//     services, workspace, config, diag)
+//   - "<command path>"    a command and its subcommands, e.g. "rig status"
+//
+// Example:
+//
+//	{
+//	  "version": 1,
+//	  "identities": {
+//	    "steve": ["*"],
+//	    "*/crew/*": ["group:work", "group:comm", "group:diag", "rig status"]
+//	  },
+//	  "default": ["group:diag"]
+//	}
+type PermissionsConfig struct {
+	Version int `json:"version"`
+
+	// Enforce turns enforcement on or off without deleting the file.
+	// Nil means enforced.
+	Enforce *bool `json:"enforce,omitempty"`
+
+	// Identities maps an OS username or agent address (e.g. "gastown/crew/joe",
+	// taken from the agent's tmux session, never from BD_ACTOR) to its grants.
+	// Keys may be path.Match patterns (e.g. "*/crew/*"); exact keys win over
+	// patterns.
+	Identities map[string][]string `json:"identities"`
+
+	// Default lists grants for identities that match no entry in Identities.
+	Default []string `json:"default,omitempty"`
+}
+
+// LoadPermissionsConfig loads and validates a permissions config file.
+func LoadPermissionsConfig(path string) (*PermissionsConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading permissions config: %w", err)
+	}
+
+	var config PermissionsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing permissions config: %w", err)
+	}
+
+	if err := validatePermissionsConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func validatePermissionsConfig(c *PermissionsConfig) error {
+	if c.Version > CurrentPermissionsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentPermissionsVersion)
+	}
+	for key := range c.Identities {
+		if _, err := path.Match(key, ""); err != nil {
+			return fmt.Errorf("permissions: invalid identity pattern %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Enforced reports whether the config should be enforced.
+func (c *PermissionsConfig) Enforced() bool {
+	return c != nil && (c.Enforce == nil || *c.Enforce)
+}
+
+// GrantsFor returns the grants for the first identity that has an entry,
+// trying each identity in order. Falls back to Default when none match.
+// The second return value is the key that matched ("" for Default).
+func (c *PermissionsConfig) GrantsFor(identities ...string) ([]string, string) {
+	for _, id := range identities {
+		if id == "" {
+			continue
+		}
+		if grants, ok := c.Identities[id]; ok {
+			return grants, id
+		}
+	}
+
+	// Patterns are checked in sorted order so matching is deterministic.
+	keys := make([]string, 0, len(c.Identities))
+	for k := range c.Identities {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, id := range identities {
+		if id == "" {
+			continue
+		}
+		for _, k := range keys {
+			if ok, _ := path.Match(k, id); ok {
+				return c.Identities[k], k
+			}
+		}
+	}
+	return c.Default, ""
+}
+
+// Allows reports whether grants permit a command.
+// commandPath is the command path without the binary name (e.g. "rig remove")
+// and group is the help group of its top-level command.
+func Allows(grants []string, commandPath, group string) bool {
+	for _, g := range grants {
+		switch {
+		case g == PermissionGrantAll:
+			return true
+		case strings.HasPrefix(g, PermissionGroupPrefix):
+			if group != "" && strings.TrimPrefix(g, PermissionGroupPrefix) == group {
+				return true
+			}
+		case commandPath == g || strings.HasPrefix(commandPath, g+" "):
+			return true
+		}
+	}
+	return false
+}
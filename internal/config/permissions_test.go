@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPermissionsConfig_GrantsFor(t *testing.T) {
+	t.Parallel()
+	cfg := &PermissionsConfig{
+		Identities: map[string][]string{
+			"steve":            {"*"},
+			"*/crew/*":         {"group:work"},
+			"gastown/crew/joe": {"group:comm"},
+		},
+		Default: []string{"group:diag"},
+	}
+
+	tests := []struct {
+		name        string
+		identities  []string
+		wantMatched string
+	}{
+		{"exact os user", []string{"", "steve"}, "steve"},
+		{"exact actor wins over pattern", []string{"gastown/crew/joe", "steve"}, "gastown/crew/joe"},
+		{"actor pattern", []string{"beads/crew/max", "alice"}, "*/crew/*"},
+		{"falls through to os user", []string{"gastown/witness", "steve"}, "steve"},
+		{"default", []string{"gastown/witness", "alice"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, matched := cfg.GrantsFor(tt.identities...)
+			if matched != tt.wantMatched {
+				t.Errorf("GrantsFor(%v) matched %q, want %q", tt.identities, matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		grants  []string
+		command string
+		group   string
+		want    bool
+	}{
+		{"all", []string{"*"}, "rig remove", "workspace", true},
+		{"group match", []string{"group:work"}, "sling", "work", true},
+		{"group mismatch", []string{"group:work"}, "rig remove", "workspace", false},
+		{"command path", []string{"rig status"}, "rig status", "workspace", true},
+		{"command prefix", []string{"dolt"}, "dolt stop", "services", true},
+		{"sibling not granted", []string{"rig status"}, "rig remove", "workspace", false},
+		{"partial word not granted", []string{"rig"}, "rigs", "", false},
+		{"no grants", nil, "status", "diag", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Allows(tt.grants, tt.command, tt.group); got != tt.want {
+				t.Errorf("Allows(%v, %q, %q) = %v, want %v", tt.grants, tt.command, tt.group, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPermissionsConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "permissions.json")
+
+	if _, err := LoadPermissionsConfig(path); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing file error = %v, want ErrNotFound", err)
+	}
+
+	data := `{"version": 1, "enforce": false, "identities": {"steve": ["*"]}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadPermissionsConfig(path)
+	if err != nil {
+		t.Fatalf("LoadPermissionsConfig() error: %v", err)
+	}
+	if cfg.Enforced() {
+		t.Error("enforce=false config should not be enforced")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 1, "identities": {"[bad": ["*"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPermissionsConfig(path); err == nil {
+		t.Error("invalid identity pattern should fail to load")
+	}
+}
@@ -0,0 +1,384 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// CurrentTownProfileVersion is the current schema version for TownProfile.
+const CurrentTownProfileVersion = 1
+
+// Town profile sections, in export order.
+const (
+	ProfileSectionSettings   = "settings"
+	ProfileSectionAgents     = "agents"
+	ProfileSectionMessaging  = "messaging"
+	ProfileSectionEscalation = "escalation"
+	ProfileSectionDaemon     = "daemon"
+)
+
+// ProfileSections lists the sections a town profile can carry.
+var ProfileSections = []string{
+	ProfileSectionSettings,
+	ProfileSectionAgents,
+	ProfileSectionMessaging,
+	ProfileSectionEscalation,
+	ProfileSectionDaemon,
+}
+
+// TownProfile bundles a town's shareable configuration so a team can keep a
+// canonical setup in version control (gt config export / gt config import).
+// Secret values are never exported; see Redacted.
+type TownProfile struct {
+	Type    string `json:"type"`    // "town-profile"
+	Version int    `json:"version"` // schema version
+
+	Settings   *TownSettings     `json:"settings,omitempty"`   // settings/config.json (includes role_agents)
+	Agents     *AgentRegistry    `json:"agents,omitempty"`     // settings/agents.json
+	Messaging  *MessagingConfig  `json:"messaging,omitempty"`  // config/messaging.json
+	Escalation *EscalationConfig `json:"escalation,omitempty"` // settings/escalation.json
+	Daemon     json.RawMessage   `json:"daemon,omitempty"`     // mayor/daemon.json, verbatim
+
+	// Redacted lists the values left out of the export because they look like
+	// secrets, e.g. "settings.agents.claude.env.ANTHROPIC_API_KEY". Importing
+	// keeps the importing town's own values for these.
+	Redacted []string `json:"redacted,omitempty"`
+}
+
+// secretEnvKeyPattern matches env var names whose literal values are treated
+// as secrets. "secret:NAME" references are exported regardless, since they
+// name a secret rather than contain it.
+var secretEnvKeyPattern = regexp.MustCompile(`(?i)(^|_)(KEY|TOKEN|SECRET|PASSWORD|PASSWD|CREDENTIALS?|AUTH)(_|$)`)
+
+func isSecretEnv(key, value string) bool {
+	if _, ok := ParseSecretRef(value); ok || value == "" {
+		return false
+	}
+	return secretEnvKeyPattern.MatchString(key)
+}
+
+// ProfileSectionPath returns the file a profile section is stored in.
+func ProfileSectionPath(townRoot, section string) string {
+	switch section {
+	case ProfileSectionSettings:
+		return TownSettingsPath(townRoot)
+	case ProfileSectionAgents:
+		return DefaultAgentRegistryPath(townRoot)
+	case ProfileSectionMessaging:
+		return MessagingConfigPath(townRoot)
+	case ProfileSectionEscalation:
+		return EscalationConfigPath(townRoot)
+	case ProfileSectionDaemon:
+		return DaemonPatrolConfigPath(townRoot)
+	}
+	return ""
+}
+
+// Sections returns the sections present in the profile, in export order.
+func (p *TownProfile) Sections() []string {
+	var sections []string
+	for _, s := range ProfileSections {
+		if p.has(s) {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+func (p *TownProfile) has(section string) bool {
+	switch section {
+	case ProfileSectionSettings:
+		return p.Settings != nil
+	case ProfileSectionAgents:
+		return p.Agents != nil
+	case ProfileSectionMessaging:
+		return p.Messaging != nil
+	case ProfileSectionEscalation:
+		return p.Escalation != nil
+	case ProfileSectionDaemon:
+		return len(p.Daemon) > 0
+	}
+	return false
+}
+
+// ExportTownProfile collects the town's configuration files into a profile.
+// Files that don't exist are left out. Secret-looking env values and webhook
+// URLs are redacted.
+func ExportTownProfile(townRoot string) (*TownProfile, error) {
+	p := &TownProfile{Type: "town-profile", Version: CurrentTownProfileVersion}
+
+	if ok, err := readProfileFile(ProfileSectionPath(townRoot, ProfileSectionSettings), &p.Settings); err != nil {
+		return nil, fmt.Errorf("reading town settings: %w", err)
+	} else if ok {
+		for _, name := range sortedKeys(p.Settings.Agents) {
+			if rc := p.Settings.Agents[name]; rc != nil {
+				p.redactEnv("settings.agents."+name, rc.Env)
+			}
+		}
+		if n := p.Settings.Notifications; n != nil && n.Webhook != "" {
+			n.Webhook = ""
+			p.Redacted = append(p.Redacted, "settings.notifications.webhook")
+		}
+	}
+
+	if ok, err := readProfileFile(ProfileSectionPath(townRoot, ProfileSectionAgents), &p.Agents); err != nil {
+		return nil, fmt.Errorf("reading agent registry: %w", err)
+	} else if ok {
+		for _, name := range sortedKeys(p.Agents.Agents) {
+			if info := p.Agents.Agents[name]; info != nil {
+				p.redactEnv("agents."+name, info.Env)
+			}
+		}
+	}
+
+	if ok, err := readProfileFile(ProfileSectionPath(townRoot, ProfileSectionMessaging), &p.Messaging); err != nil {
+		return nil, fmt.Errorf("reading messaging config: %w", err)
+	} else if ok {
+		if err := validateMessagingConfig(p.Messaging); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := readProfileFile(ProfileSectionPath(townRoot, ProfileSectionEscalation), &p.Escalation); err != nil {
+		return nil, fmt.Errorf("reading escalation config: %w", err)
+	} else if ok {
+		if err := validateEscalationConfig(p.Escalation); err != nil {
+			return nil, err
+		}
+		if p.Escalation.Contacts.SlackWebhook != "" {
+			p.Escalation.Contacts.SlackWebhook = ""
+			p.Redacted = append(p.Redacted, "escalation.contacts.slack_webhook")
+		}
+	}
+
+	data, err := os.ReadFile(ProfileSectionPath(townRoot, ProfileSectionDaemon)) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading daemon patrol config: %w", err)
+	}
+	if err == nil {
+		if err := validateDaemonProfile(data); err != nil {
+			return nil, err
+		}
+		p.Daemon = json.RawMessage(data)
+	}
+
+	return p, nil
+}
+
+// readProfileFile decodes the JSON file at path into *dst, allocating it.
+// It reports false without error if the file doesn't exist.
+func readProfileFile[T any](path string, dst **T) (bool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	*dst = v
+	return true, nil
+}
+
+func (p *TownProfile) redactEnv(prefix string, env map[string]string) {
+	for _, key := range sortedKeys(env) {
+		if isSecretEnv(key, env[key]) {
+			delete(env, key)
+			p.Redacted = append(p.Redacted, prefix+".env."+key)
+		}
+	}
+}
+
+// restoreSecretEnv copies the secret-looking values of local into imported
+// where imported lacks them, so importing a redacted profile keeps the
+// town's own credentials.
+func restoreSecretEnv(imported *map[string]string, local map[string]string) {
+	for key, value := range local {
+		if !isSecretEnv(key, value) {
+			continue
+		}
+		if _, ok := (*imported)[key]; ok {
+			continue
+		}
+		if *imported == nil {
+			*imported = make(map[string]string)
+		}
+		(*imported)[key] = value
+	}
+}
+
+func validateDaemonProfile(data []byte) error {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("parsing daemon patrol config: %w", err)
+	}
+	if header.Type != "daemon-patrol-config" && header.Type != "" {
+		return fmt.Errorf("%w: expected type 'daemon-patrol-config', got '%s'", ErrInvalidType, header.Type)
+	}
+	return nil
+}
+
+// ValidateTownProfile checks the profile header and every section it carries.
+func ValidateTownProfile(p *TownProfile) error {
+	if p.Type != "town-profile" {
+		return fmt.Errorf("%w: expected type 'town-profile', got '%s'", ErrInvalidType, p.Type)
+	}
+	if p.Version > CurrentTownProfileVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, p.Version, CurrentTownProfileVersion)
+	}
+	if s := p.Settings; s != nil {
+		if s.Type != "town-settings" && s.Type != "" {
+			return fmt.Errorf("settings: %w: expected type 'town-settings', got '%s'", ErrInvalidType, s.Type)
+		}
+		if s.Version > CurrentTownSettingsVersion {
+			return fmt.Errorf("settings: %w: got %d, max supported %d", ErrInvalidVersion, s.Version, CurrentTownSettingsVersion)
+		}
+		if s.Secrets != nil {
+			if err := s.Secrets.Validate(); err != nil {
+				return fmt.Errorf("settings: %w", err)
+			}
+		}
+	}
+	if p.Agents != nil && p.Agents.Version > CurrentAgentRegistryVersion {
+		return fmt.Errorf("agents: %w: got %d, max supported %d", ErrInvalidVersion, p.Agents.Version, CurrentAgentRegistryVersion)
+	}
+	if p.Messaging != nil {
+		if err := validateMessagingConfig(p.Messaging); err != nil {
+			return fmt.Errorf("messaging: %w", err)
+		}
+	}
+	if p.Escalation != nil {
+		if err := validateEscalationConfig(p.Escalation); err != nil {
+			return fmt.Errorf("escalation: %w", err)
+		}
+	}
+	if len(p.Daemon) > 0 {
+		if err := validateDaemonProfile(p.Daemon); err != nil {
+			return fmt.Errorf("daemon: %w", err)
+		}
+	}
+	return nil
+}
+
+// ImportTownProfile writes the given sections of p into the town, replacing
+// the corresponding files. A nil sections slice imports every section the
+// profile carries. The whole profile is validated before anything is
+// written. Values redacted on export are kept from the town's current files.
+// Returns the sections written.
+func ImportTownProfile(townRoot string, p *TownProfile, sections []string) ([]string, error) {
+	if err := ValidateTownProfile(p); err != nil {
+		return nil, err
+	}
+	if sections == nil {
+		sections = p.Sections()
+	}
+	for _, s := range sections {
+		if ProfileSectionPath(townRoot, s) == "" {
+			return nil, fmt.Errorf("unknown profile section %q (valid: %v)", s, ProfileSections)
+		}
+		if !p.has(s) {
+			return nil, fmt.Errorf("profile has no %s section", s)
+		}
+	}
+
+	var written []string
+	for _, s := range sections {
+		path := ProfileSectionPath(townRoot, s)
+		var err error
+		switch s {
+		case ProfileSectionSettings:
+			err = importSettings(path, p.Settings)
+		case ProfileSectionAgents:
+			err = importAgents(path, p.Agents)
+		case ProfileSectionMessaging:
+			err = SaveMessagingConfig(path, p.Messaging)
+		case ProfileSectionEscalation:
+			err = importEscalation(path, p.Escalation)
+		case ProfileSectionDaemon:
+			err = writeDaemonProfile(path, p.Daemon)
+		}
+		if err != nil {
+			return written, fmt.Errorf("importing %s: %w", s, err)
+		}
+		written = append(written, s)
+	}
+	return written, nil
+}
+
+func importSettings(path string, settings *TownSettings) error {
+	var local *TownSettings
+	if _, err := readProfileFile(path, &local); err != nil {
+		return err
+	}
+	if local != nil {
+		for name, rc := range settings.Agents {
+			if lrc := local.Agents[name]; rc != nil && lrc != nil {
+				restoreSecretEnv(&rc.Env, lrc.Env)
+			}
+		}
+		if n := settings.Notifications; n != nil && n.Webhook == "" && local.Notifications != nil {
+			n.Webhook = local.Notifications.Webhook
+		}
+	}
+	return SaveTownSettings(path, settings)
+}
+
+func importAgents(path string, registry *AgentRegistry) error {
+	var local *AgentRegistry
+	if _, err := readProfileFile(path, &local); err != nil {
+		return err
+	}
+	if local != nil {
+		for name, info := range registry.Agents {
+			if linfo := local.Agents[name]; info != nil && linfo != nil {
+				restoreSecretEnv(&info.Env, linfo.Env)
+			}
+		}
+	}
+	return SaveAgentRegistry(path, registry)
+}
+
+func importEscalation(path string, cfg *EscalationConfig) error {
+	if cfg.Contacts.SlackWebhook == "" {
+		local, err := LoadEscalationConfig(path)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if local != nil {
+			cfg.Contacts.SlackWebhook = local.Contacts.SlackWebhook
+		}
+	}
+	return SaveEscalationConfig(path, cfg)
+}
+
+func writeDaemonProfile(path string, data json.RawMessage) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("encoding daemon patrol config: %w", err)
+	}
+	buf.WriteByte('\n')
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644) //nolint:gosec // G306: config files don't contain secrets
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestJSON(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportTownProfile_RedactsSecrets(t *testing.T) {
+	town := t.TempDir()
+	writeTestJSON(t, TownSettingsPath(town), `{
+		"type": "town-settings", "version": 1,
+		"default_agent": "claude",
+		"role_agents": {"witness": "claude-haiku"},
+		"agents": {"claude-haiku": {"command": "claude", "args": [], "env": {
+			"ANTHROPIC_API_KEY": "sk-live-123",
+			"OPENAI_KEY": "secret:openai",
+			"GIT_AUTHOR_NAME": "gastown"
+		}}},
+		"notifications": {"events": ["merged"], "webhook": "https://example.com/hook"}
+	}`)
+	writeTestJSON(t, EscalationConfigPath(town), `{
+		"type": "escalation", "version": 1,
+		"routes": {"high": ["bead", "mail:mayor"]},
+		"contacts": {"human_email": "ops@example.com", "slack_webhook": "https://hooks.slack.com/services/T/B/x"}
+	}`)
+	writeTestJSON(t, DaemonPatrolConfigPath(town), `{"type": "daemon-patrol-config", "version": 1, "patrols": {"dolt_remotes": {"enabled": true, "interval": 900000000000}}}`)
+
+	p, err := ExportTownProfile(town)
+	if err != nil {
+		t.Fatalf("ExportTownProfile: %v", err)
+	}
+	if got := strings.Join(p.Sections(), ","); got != "settings,escalation,daemon" {
+		t.Errorf("sections = %s", got)
+	}
+
+	env := p.Settings.Agents["claude-haiku"].Env
+	if _, ok := env["ANTHROPIC_API_KEY"]; ok {
+		t.Error("literal API key was exported")
+	}
+	if env["OPENAI_KEY"] != "secret:openai" || env["GIT_AUTHOR_NAME"] != "gastown" {
+		t.Errorf("non-secret env not exported: %v", env)
+	}
+	if p.Settings.RoleAgents["witness"] != "claude-haiku" {
+		t.Errorf("role_agents = %v", p.Settings.RoleAgents)
+	}
+	if p.Escalation.Contacts.SlackWebhook != "" || p.Escalation.Contacts.HumanEmail != "ops@example.com" {
+		t.Errorf("contacts = %+v", p.Escalation.Contacts)
+	}
+	if n := p.Settings.Notifications; n.Webhook != "" || len(n.Events) != 1 {
+		t.Errorf("notifications = %+v", n)
+	}
+	want := "settings.agents.claude-haiku.env.ANTHROPIC_API_KEY,settings.notifications.webhook,escalation.contacts.slack_webhook"
+	if got := strings.Join(p.Redacted, ","); got != want {
+		t.Errorf("redacted = %s, want %s", got, want)
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "sk-live-123") || strings.Contains(string(data), "hooks.slack.com") {
+		t.Errorf("secret leaked into profile: %s", data)
+	}
+}
+
+func TestImportTownProfile_KeepsLocalSecrets(t *testing.T) {
+	src := t.TempDir()
+	writeTestJSON(t, TownSettingsPath(src), `{"type": "town-settings", "version": 1, "default_agent": "codex",
+		"agents": {"fast": {"command": "claude", "args": [], "env": {"ANTHROPIC_API_KEY": "sk-src"}}}}`)
+	writeTestJSON(t, MessagingConfigPath(src), `{"type": "messaging", "version": 1, "lists": {"oncall": ["mayor/"]}}`)
+	writeTestJSON(t, EscalationConfigPath(src), `{"type": "escalation", "version": 1, "routes": {}, "contacts": {"slack_webhook": "https://src"}}`)
+	writeTestJSON(t, DaemonPatrolConfigPath(src), `{"type":"daemon-patrol-config","version":1,"patrols":{"dolt_server":{"enabled":true,"port":3307}}}`)
+
+	profile, err := ExportTownProfile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	writeTestJSON(t, TownSettingsPath(dst), `{"type": "town-settings", "version": 1,
+		"agents": {"fast": {"command": "old", "args": [], "env": {"ANTHROPIC_API_KEY": "sk-dst"}}}}`)
+	writeTestJSON(t, EscalationConfigPath(dst), `{"type": "escalation", "version": 1, "routes": {}, "contacts": {"slack_webhook": "https://dst"}}`)
+
+	written, err := ImportTownProfile(dst, profile, nil)
+	if err != nil {
+		t.Fatalf("ImportTownProfile: %v", err)
+	}
+	if got := strings.Join(written, ","); got != "settings,messaging,escalation,daemon" {
+		t.Errorf("written = %s", got)
+	}
+
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.DefaultAgent != "codex" || settings.Agents["fast"].Command != "claude" {
+		t.Errorf("settings not imported: %+v", settings)
+	}
+	if settings.Agents["fast"].Env["ANTHROPIC_API_KEY"] != "sk-dst" {
+		t.Errorf("local API key not kept: %v", settings.Agents["fast"].Env)
+	}
+
+	esc, err := LoadEscalationConfig(EscalationConfigPath(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esc.Contacts.SlackWebhook != "https://dst" {
+		t.Errorf("local slack webhook not kept: %q", esc.Contacts.SlackWebhook)
+	}
+
+	daemon, err := os.ReadFile(DaemonPatrolConfigPath(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(daemon), `"port": 3307`) {
+		t.Errorf("daemon config not copied verbatim: %s", daemon)
+	}
+}
+
+func TestImportTownProfile_ValidatesBeforeWriting(t *testing.T) {
+	dst := t.TempDir()
+	profile := &TownProfile{
+		Type:      "town-profile",
+		Version:   CurrentTownProfileVersion,
+		Settings:  NewTownSettings(),
+		Messaging: &MessagingConfig{Type: "bogus"},
+	}
+	if _, err := ImportTownProfile(dst, profile, nil); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if _, err := os.Stat(TownSettingsPath(dst)); !os.IsNotExist(err) {
+		t.Error("settings were written despite an invalid profile")
+	}
+
+	if _, err := ImportTownProfile(dst, &TownProfile{Type: "town-profile"}, []string{"bogus"}); err == nil {
+		t.Error("expected error for unknown section")
+	}
+	if _, err := ImportTownProfile(dst, &TownProfile{Type: "town-settings"}, nil); err == nil {
+		t.Error("expected error for wrong profile type")
+	}
+}
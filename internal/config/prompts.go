@@ -0,0 +1,253 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// PromptRefPrefix marks a runtime config initial_prompt that names a file in
+// the prompt library instead of holding the prompt text: "prompt:witness".
+const PromptRefPrefix = "prompt:"
+
+// PromptsDir is the prompt library directory under a town's or rig's
+// settings directory.
+const PromptsDir = "prompts"
+
+// promptExt is the extension of prompt library files.
+const promptExt = ".md"
+
+// maxPromptDepth bounds include and extends nesting, catching cycles.
+const maxPromptDepth = 10
+
+var (
+	promptNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+(/[A-Za-z0-9_.-]+)*$`)
+	extendsPattern    = regexp.MustCompile(`^\s*\{\{-?\s*extends\s+"([^"]+)"\s*-?\}\}[ \t]*\r?\n?`)
+)
+
+// ParsePromptRef reports whether v is a prompt library reference
+// ("prompt:NAME") and returns the referenced name.
+func ParsePromptRef(v string) (string, bool) {
+	name, ok := strings.CutPrefix(v, PromptRefPrefix)
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// ValidatePromptName checks that name is a relative path of letters,
+// digits, '_', '.', and '-' segments, such as "witness" or "roles/crew".
+func ValidatePromptName(name string) error {
+	if !promptNamePattern.MatchString(name) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid prompt name %q: use letters, digits, '_', '.', '-', and '/' between segments", name)
+	}
+	return nil
+}
+
+// PromptDirs returns the prompt library directories searched for a rig, most
+// specific first: <rig>/settings/prompts, then <town>/settings/prompts. With
+// an empty rigPath only the town directory is searched.
+func PromptDirs(townRoot, rigPath string) []string {
+	var dirs []string
+	if rigPath != "" {
+		dirs = append(dirs, filepath.Join(rigPath, constants.DirSettings, PromptsDir))
+	}
+	if townRoot != "" {
+		dirs = append(dirs, filepath.Join(townRoot, constants.DirSettings, PromptsDir))
+	}
+	return dirs
+}
+
+// PromptFile is a prompt found in the library.
+type PromptFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Shadows is the path of a town prompt this rig prompt overrides.
+	Shadows string `json:"shadows,omitempty"`
+}
+
+// ListPrompts returns the prompts visible to a rig, sorted by name. A rig
+// prompt hides the town prompt of the same name.
+func ListPrompts(townRoot, rigPath string) ([]PromptFile, error) {
+	byName := make(map[string]*PromptFile)
+	for _, dir := range PromptDirs(townRoot, rigPath) {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == dir {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != promptExt {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(filepath.ToSlash(rel), promptExt)
+			if p, ok := byName[name]; ok {
+				if p.Shadows == "" {
+					p.Shadows = path
+				}
+				return nil
+			}
+			byName[name] = &PromptFile{Name: name, Path: path}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading prompts in %s: %w", dir, err)
+		}
+	}
+	prompts := make([]PromptFile, 0, len(byName))
+	for _, p := range byName {
+		prompts = append(prompts, *p)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts, nil
+}
+
+// promptLibrary renders prompts from an ordered list of directories.
+type promptLibrary struct {
+	dirs []string
+	data ArgContext
+}
+
+// RenderPrompt renders the named prompt for a rig (empty rigPath for
+// town-level agents). Prompts are Go templates over ArgContext, so they can
+// interpolate {{.Role}}, {{.Rig}}, {{.Issue}}, {{.TownRoot}}, and
+// {{.Env.NAME}}. They can also:
+//
+//   - insert another prompt with {{include "name"}};
+//   - start with {{extends "name"}} to render that prompt instead, with
+//     this file's {{define "block"}} sections replacing its
+//     {{block "block" .}} defaults.
+//
+// A rig prompt that includes or extends its own name gets the town prompt
+// of that name, so rigs can add to a shared prompt rather than copy it.
+func RenderPrompt(townRoot, rigPath, name string, ctx *ArgContext) (string, error) {
+	if ctx == nil {
+		ctx = ArgContextFromEnv(nil)
+	}
+	lib := &promptLibrary{dirs: PromptDirs(townRoot, rigPath), data: ctx.templateData()}
+	return lib.render(name, 0, 0)
+}
+
+// load reads the first name.md found in dirs[from:], returning its text and
+// the index of the directory it came from.
+func (l *promptLibrary) load(name string, from int) (string, int, error) {
+	if err := ValidatePromptName(name); err != nil {
+		return "", 0, err
+	}
+	for i := from; i < len(l.dirs); i++ {
+		data, err := os.ReadFile(filepath.Join(l.dirs[i], filepath.FromSlash(name)+promptExt))
+		if err == nil {
+			return string(data), i, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", 0, fmt.Errorf("reading prompt %q: %w", name, err)
+		}
+	}
+	if from > 0 {
+		return "", 0, fmt.Errorf("prompt %q has no town version to include or extend", name)
+	}
+	return "", 0, fmt.Errorf("prompt %q not found in %s", name, strings.Join(l.dirs, " or "))
+}
+
+// render renders name as found from dirs[from:].
+func (l *promptLibrary) render(name string, from, depth int) (string, error) {
+	if depth > maxPromptDepth {
+		return "", fmt.Errorf("prompt %q: includes nested more than %d deep (cycle?)", name, maxPromptDepth)
+	}
+	text, at, err := l.load(name, from)
+	if err != nil {
+		return "", err
+	}
+	// A self-include resumes the search after the directory name came from,
+	// which may be past from when the rig has no version of its own.
+	nameAt := at
+
+	// Follow the extends chain to the root prompt, most derived first.
+	type layer struct{ name, text string }
+	chain := []layer{{name, text}}
+	for {
+		m := extendsPattern.FindStringSubmatch(chain[len(chain)-1].text)
+		if m == nil {
+			break
+		}
+		chain[len(chain)-1].text = chain[len(chain)-1].text[len(m[0]):]
+		if len(chain) > maxPromptDepth {
+			return "", fmt.Errorf("prompt %q: extends nested more than %d deep (cycle?)", name, maxPromptDepth)
+		}
+		base := m[1]
+		baseFrom := 0
+		if base == chain[len(chain)-1].name {
+			baseFrom = at + 1
+		}
+		baseText, baseAt, err := l.load(base, baseFrom)
+		if err != nil {
+			return "", fmt.Errorf("prompt %q extends: %w", chain[len(chain)-1].name, err)
+		}
+		at = baseAt
+		chain = append(chain, layer{base, baseText})
+	}
+
+	funcs := template.FuncMap{
+		"include": func(inc string) (string, error) {
+			incFrom := 0
+			if inc == name {
+				incFrom = nameAt + 1
+			}
+			return l.render(inc, incFrom, depth+1)
+		},
+		// extends is handled above; this only lets the header parse.
+		"extends": func(string) string { return "" },
+	}
+
+	// Parse the root first so each derived layer's defines replace its blocks.
+	root := chain[len(chain)-1]
+	tmpl, err := template.New(root.name).Funcs(funcs).Option("missingkey=zero").Parse(root.text)
+	if err != nil {
+		return "", fmt.Errorf("parsing prompt %q: %w", root.name, err)
+	}
+	for i := len(chain) - 2; i >= 0; i-- {
+		if _, err := tmpl.New(chain[i].name).Parse(chain[i].text); err != nil {
+			return "", fmt.Errorf("parsing prompt %q: %w", chain[i].name, err)
+		}
+	}
+	var b strings.Builder
+	if err := tmpl.ExecuteTemplate(&b, root.name, l.data); err != nil {
+		return "", fmt.Errorf("rendering prompt %q: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// resolvePromptRef renders p if it is a prompt library reference, locating
+// the library from ctx's town root and rig. A prompt that can't be rendered
+// is reported on stderr and dropped, so the agent still starts.
+func resolvePromptRef(p string, ctx *ArgContext) string {
+	name, ok := ParsePromptRef(p)
+	if !ok {
+		return p
+	}
+	if ctx == nil {
+		ctx = ArgContextFromEnv(nil)
+	}
+	rigPath := ""
+	if ctx.TownRoot != "" && ctx.Rig != "" {
+		rigPath = filepath.Join(ctx.TownRoot, ctx.Rig)
+	}
+	rendered, err := RenderPrompt(ctx.TownRoot, rigPath, name, ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: initial prompt %s: %v; starting without it\n", p, err)
+		return ""
+	}
+	return rendered
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePrompt(t *testing.T, root, name, text string) {
+	t.Helper()
+	path := filepath.Join(root, "settings", PromptsDir, filepath.FromSlash(name)+".md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderPrompt(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+
+	writePrompt(t, town, "shared/rules", "Never push to main.")
+	writePrompt(t, town, "base", `You are the {{.Role}} of {{.Rig}}.
+{{block "focus" .}}Do your job.{{end}}
+{{include "shared/rules"}}`)
+	writePrompt(t, town, "witness", `{{extends "base"}}
+{{define "focus"}}Watch the polecats{{if .Issue}} working on {{.Issue}}{{end}}.{{end}}`)
+	// The rig adds to the town's witness prompt instead of replacing it.
+	writePrompt(t, rig, "witness", `{{include "witness"}}
+Rig note: tests take ten minutes.`)
+
+	ctx := &ArgContext{Role: "witness", Rig: "gastown", Issue: "gt-1", TownRoot: town}
+
+	got, err := RenderPrompt(town, "", "witness", ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "You are the witness of gastown.\nWatch the polecats working on gt-1.\nNever push to main."
+	if got != want {
+		t.Errorf("town witness =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = RenderPrompt(town, rig, "witness", ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := want + "\nRig note: tests take ten minutes."; got != want {
+		t.Errorf("rig witness =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = RenderPrompt(town, rig, "base", &ArgContext{Role: "crew", Rig: "gastown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "Do your job.") {
+		t.Errorf("base without an extender = %q, want its block default", got)
+	}
+}
+
+func TestRenderPromptErrors(t *testing.T) {
+	town := t.TempDir()
+	writePrompt(t, town, "loop", `{{include "loop"}}`)
+	writePrompt(t, town, "a", `{{extends "b"}}`)
+	writePrompt(t, town, "b", `{{extends "a"}}`)
+
+	for name, want := range map[string]string{
+		"missing": "not found",
+		"loop":    "has no town version",
+		"a":       "cycle",
+		"../etc":  "invalid prompt name",
+	} {
+		_, err := RenderPrompt(town, "", name, &ArgContext{})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("RenderPrompt(%q) error = %v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestListPrompts(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	writePrompt(t, town, "witness", "town")
+	writePrompt(t, town, "roles/crew", "town")
+	writePrompt(t, rig, "witness", "rig")
+
+	prompts, err := ListPrompts(town, rig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 2 || prompts[0].Name != "roles/crew" || prompts[1].Name != "witness" {
+		t.Fatalf("ListPrompts = %+v", prompts)
+	}
+	if !strings.HasPrefix(prompts[1].Path, rig) || prompts[1].Shadows == "" {
+		t.Errorf("witness = %+v, want the rig file shadowing the town one", prompts[1])
+	}
+
+	if prompts, err := ListPrompts(t.TempDir(), ""); err != nil || len(prompts) != 0 {
+		t.Errorf("empty library = %v, %v", prompts, err)
+	}
+}
+
+func TestBuildCommandResolvesPromptRef(t *testing.T) {
+	town := t.TempDir()
+	writePrompt(t, town, "mayor", "Hello {{.Role}}")
+
+	rc := &RuntimeConfig{
+		Command:       "claude",
+		Args:          []string{},
+		InitialPrompt: "prompt:mayor",
+		ArgContext:    &ArgContext{Role: "mayor", TownRoot: town},
+	}
+	if got, want := rc.BuildCommandWithPrompt(""), `claude "Hello mayor"`; got != want {
+		t.Errorf("BuildCommandWithPrompt = %q, want %q", got, want)
+	}
+	// An explicit prompt still wins over the library.
+	if got, want := rc.BuildCommandWithPrompt("beacon"), `claude "beacon"`; got != want {
+		t.Errorf("BuildCommandWithPrompt(beacon) = %q, want %q", got, want)
+	}
+
+	rc.InitialPrompt = "prompt:missing"
+	if got := rc.BuildCommandWithPrompt(""); got != "claude" {
+		t.Errorf("missing prompt = %q, want the bare command", got)
+	}
+}
+
+func TestRenderPromptSelfIncludeFromTown(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+	// Only the town has "loop", so its self-include must search past the
+	// town directory, not render the town prompt a second time.
+	writePrompt(t, town, "loop", `{{include "loop"}}`)
+
+	_, err := RenderPrompt(town, rig, "loop", &ArgContext{})
+	if err == nil || !strings.Contains(err.Error(), "has no town version") {
+		t.Fatalf("RenderPrompt error = %v, want no town version", err)
+	}
+	if n := strings.Count(err.Error(), `rendering prompt "loop"`); n != 1 {
+		t.Errorf("town prompt rendered %d times, want once: %v", n, err)
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRemote indicates a malformed remote rig configuration.
+var ErrInvalidRemote = errors.New("invalid remote config")
+
+// RemoteConfig places a rig's agent sessions and git operations on another
+// machine reached over SSH, while the town (mail, routing, the Dolt server)
+// stays local. The remote machine must have the town checked out at the same
+// absolute path, with tmux, git, gt, bd, and the agent CLI installed.
+// Remote agents reach beads through the town's central Dolt server.
+type RemoteConfig struct {
+	// Host is the SSH destination: "user@host" or an ssh_config alias.
+	Host string `json:"host"`
+
+	// Port is the SSH port. Zero uses ssh's default (or ssh_config).
+	Port int `json:"port,omitempty"`
+
+	// IdentityFile is the private key to authenticate with.
+	IdentityFile string `json:"identity_file,omitempty"`
+
+	// DoltHost is the address remote agents use to reach the town's Dolt
+	// server (exported as GT_DOLT_HOST). Required: "127.0.0.1" on the remote
+	// machine is not the town's server.
+	DoltHost string `json:"dolt_host"`
+
+	// DoltPort is the town Dolt server port as seen from the remote machine.
+	// Zero means the town's configured port.
+	DoltPort int `json:"dolt_port,omitempty"`
+}
+
+// Validate checks that the remote has a host and a Dolt address.
+// A nil remote (local rig) is always valid.
+func (r *RemoteConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if strings.TrimSpace(r.Host) == "" {
+		return fmt.Errorf("%w: host is required", ErrInvalidRemote)
+	}
+	if strings.HasPrefix(r.Host, "-") {
+		return fmt.Errorf("%w: host %q", ErrInvalidRemote, r.Host)
+	}
+	if strings.TrimSpace(r.DoltHost) == "" {
+		return fmt.Errorf("%w: dolt_host is required so remote agents can reach beads", ErrInvalidRemote)
+	}
+	if r.Port < 0 || r.Port > 65535 || r.DoltPort < 0 || r.DoltPort > 65535 {
+		return fmt.Errorf("%w: port out of range", ErrInvalidRemote)
+	}
+	return nil
+}
+
+// SSHArgs returns the ssh argv (program first) up to and including the
+// destination; append a single remote command string to use it.
+// BatchMode keeps gt from hanging on a password prompt.
+func (r *RemoteConfig) SSHArgs() []string {
+	args := []string{"ssh", "-o", "BatchMode=yes"}
+	if r.Port > 0 {
+		args = append(args, "-p", strconv.Itoa(r.Port))
+	}
+	if r.IdentityFile != "" {
+		args = append(args, "-i", expandHome(r.IdentityFile))
+	}
+	return append(args, r.Host)
+}
+
+// AgentEnv returns the environment remote agents need to reach the town's
+// beads. defaultDoltPort is used when DoltPort is unset.
+func (r *RemoteConfig) AgentEnv(defaultDoltPort int) map[string]string {
+	port := r.DoltPort
+	if port == 0 {
+		port = defaultDoltPort
+	}
+	return map[string]string{
+		"GT_DOLT_HOST": r.DoltHost,
+		"GT_DOLT_PORT": strconv.Itoa(port),
+	}
+}
+
+// RemoteShellCommand joins argv into one shell-quoted string for ssh to run
+// on the remote machine, optionally inside dir.
+func RemoteShellCommand(dir string, argv ...string) string {
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = quoteRemoteArg(a)
+	}
+	cmd := strings.Join(quoted, " ")
+	if dir != "" {
+		cmd = "cd " + quoteRemoteArg(dir) + " && " + cmd
+	}
+	return cmd
+}
+
+// safeRemoteArgRe matches words the remote shell passes through unchanged.
+var safeRemoteArgRe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// quoteRemoteArg single-quotes a for the remote shell. Unlike ShellQuote it
+// also protects empty strings and leading "~", since ssh re-parses the line.
+func quoteRemoteArg(a string) string {
+	if safeRemoteArgRe.MatchString(a) {
+		return a
+	}
+	return "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+}
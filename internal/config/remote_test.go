@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRemoteConfigValidate(t *testing.T) {
+	var nilRemote *RemoteConfig
+	if err := nilRemote.Validate(); err != nil {
+		t.Errorf("nil remote: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  RemoteConfig
+		wantErr bool
+	}{
+		{"valid", RemoteConfig{Host: "gt@build1", DoltHost: "10.0.0.5"}, false},
+		{"missing host", RemoteConfig{DoltHost: "10.0.0.5"}, true},
+		{"option as host", RemoteConfig{Host: "-oProxyCommand=x", DoltHost: "10.0.0.5"}, true},
+		{"missing dolt host", RemoteConfig{Host: "build1"}, true},
+		{"bad port", RemoteConfig{Host: "build1", DoltHost: "h", Port: 70000}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.remote.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRemote) {
+				t.Errorf("error %v does not wrap ErrInvalidRemote", err)
+			}
+		})
+	}
+}
+
+func TestRemoteConfigSSHArgs(t *testing.T) {
+	r := &RemoteConfig{Host: "gt@build1", Port: 2222, IdentityFile: "/keys/id"}
+	want := []string{"ssh", "-o", "BatchMode=yes", "-p", "2222", "-i", "/keys/id", "gt@build1"}
+	if got := r.SSHArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SSHArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestRemoteConfigAgentEnv(t *testing.T) {
+	r := &RemoteConfig{Host: "build1", DoltHost: "10.0.0.5"}
+	env := r.AgentEnv(3307)
+	if env["GT_DOLT_HOST"] != "10.0.0.5" || env["GT_DOLT_PORT"] != "3307" {
+		t.Errorf("AgentEnv() = %v", env)
+	}
+	r.DoltPort = 4000
+	if got := r.AgentEnv(3307)["GT_DOLT_PORT"]; got != "4000" {
+		t.Errorf("GT_DOLT_PORT = %q, want 4000", got)
+	}
+}
+
+func TestRemoteShellCommand(t *testing.T) {
+	got := RemoteShellCommand("/gt/my rig", "tmux", "send-keys", "-t", "gt-x", "it's", "")
+	want := `cd '/gt/my rig' && tmux send-keys -t gt-x 'it'\''s' ''`
+	if got != want {
+		t.Errorf("RemoteShellCommand() = %q, want %q", got, want)
+	}
+	if got := RemoteShellCommand("", "echo", "~"); got != "echo '~'" {
+		t.Errorf("RemoteShellCommand() = %q, want %q", got, "echo '~'")
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// RuntimeArgGroup is a set of args added to the agent command only when its
+// condition holds, so one agent config can cover several invocations:
+//
+//	{"if_env": "GT_MODEL", "args": ["--model", "{{.Env.GT_MODEL}}"]}
+type RuntimeArgGroup struct {
+	// IfEnv is checked against the agent's environment: "NAME" holds when
+	// NAME is set and non-empty, "NAME=value" when it equals value, and
+	// "!NAME" when it is unset or empty. Empty always holds.
+	IfEnv string `json:"if_env,omitempty"`
+
+	// Args are appended after RuntimeConfig.Args, templated the same way.
+	Args []string `json:"args"`
+}
+
+// ArgContext is what runtime args can reference: {{.Rig}}, {{.Role}},
+// {{.Issue}}, {{.TownRoot}}, and {{.Env.NAME}}.
+type ArgContext struct {
+	Rig      string
+	Role     string // simple role: mayor, witness, polecat, ...
+	Issue    string
+	TownRoot string
+
+	// Env is the environment the agent will start with. Names missing from
+	// it are looked up in gt's own environment.
+	Env map[string]string
+}
+
+// ArgContextFromEnv builds an ArgContext from agent env vars such as those
+// AgentEnv returns (GT_ROLE, GT_RIG, GT_ROOT, GT_ISSUE).
+func ArgContextFromEnv(env map[string]string) *ArgContext {
+	ctx := &ArgContext{Env: env}
+	ctx.Role = ExtractSimpleRole(ctx.lookup("GT_ROLE"))
+	ctx.Rig = ctx.lookup("GT_RIG")
+	ctx.Issue = ctx.lookup("GT_ISSUE")
+	ctx.TownRoot = ctx.lookup("GT_ROOT")
+	return ctx
+}
+
+func (c *ArgContext) lookup(name string) string {
+	if v, ok := c.Env[name]; ok {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+// templateData is the value templates execute against; Env is filled in
+// from both sources so {{.Env.NAME}} sees gt's environment too.
+func (c *ArgContext) templateData() ArgContext {
+	data := *c
+	data.Env = make(map[string]string)
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			data.Env[k] = v
+		}
+	}
+	for k, v := range c.Env {
+		data.Env[k] = v
+	}
+	return data
+}
+
+// holds reports whether an IfEnv condition is met.
+func (c *ArgContext) holds(cond string) bool {
+	cond = strings.TrimSpace(cond)
+	switch {
+	case cond == "":
+		return true
+	case strings.HasPrefix(cond, "!"):
+		return c.lookup(strings.TrimPrefix(cond, "!")) == ""
+	}
+	if name, want, ok := strings.Cut(cond, "="); ok {
+		return c.lookup(name) == want
+	}
+	return c.lookup(cond) != ""
+}
+
+// expandRuntimeArgs evaluates args and the groups whose conditions hold.
+// Templated args are shell-quoted when quote is set, since their values come
+// from the environment; an arg that templates to "" is dropped, so
+// "{{if .Issue}}--resume{{end}}" adds nothing without an issue. An arg whose
+// template doesn't parse or run is kept literally.
+func expandRuntimeArgs(args []string, groups []RuntimeArgGroup, ctx *ArgContext, quote bool) []string {
+	if ctx == nil {
+		ctx = ArgContextFromEnv(nil)
+	}
+	all := append([]string(nil), args...)
+	for _, g := range groups {
+		if ctx.holds(g.IfEnv) {
+			all = append(all, g.Args...)
+		}
+	}
+
+	var data *ArgContext
+	out := make([]string, 0, len(all))
+	for _, arg := range all {
+		if !strings.Contains(arg, "{{") {
+			out = append(out, arg)
+			continue
+		}
+		if data == nil {
+			d := ctx.templateData()
+			data = &d
+		}
+		expanded, err := executeArgTemplate(arg, data)
+		if err != nil {
+			out = append(out, arg)
+			continue
+		}
+		if expanded == "" {
+			continue
+		}
+		if quote {
+			expanded = ShellQuote(expanded)
+		}
+		out = append(out, expanded)
+	}
+	return out
+}
+
+func executeArgTemplate(arg string, data *ArgContext) (string, error) {
+	tmpl, err := template.New("arg").Option("missingkey=zero").Parse(arg)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ValidateRuntimeArgs checks that every templated arg in rc parses and
+// refers only to ArgContext fields, and that every arg group has args.
+func ValidateRuntimeArgs(rc *RuntimeConfig) error {
+	if rc == nil {
+		return nil
+	}
+	check := func(arg string) error {
+		if !strings.Contains(arg, "{{") {
+			return nil
+		}
+		if _, err := executeArgTemplate(arg, &ArgContext{Env: map[string]string{}}); err != nil {
+			return fmt.Errorf("arg %q: %w", arg, err)
+		}
+		return nil
+	}
+	for _, arg := range rc.Args {
+		if err := check(arg); err != nil {
+			return err
+		}
+	}
+	for i, g := range rc.ArgGroups {
+		if len(g.Args) == 0 {
+			return fmt.Errorf("arg_groups[%d] has no args", i)
+		}
+		if name := strings.TrimPrefix(strings.TrimSpace(g.IfEnv), "!"); strings.HasPrefix(name, "=") {
+			return fmt.Errorf("arg_groups[%d]: if_env %q has no variable name", i, g.IfEnv)
+		}
+		for _, arg := range g.Args {
+			if err := check(arg); err != nil {
+				return fmt.Errorf("arg_groups[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
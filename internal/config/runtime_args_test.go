@@ -0,0 +1,91 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandRuntimeArgs(t *testing.T) {
+	t.Setenv("GT_TEST_FROM_GT", "outer")
+	ctx := ArgContextFromEnv(map[string]string{
+		"GT_ROLE":  "gastown/polecats/toast",
+		"GT_RIG":   "gastown",
+		"GT_ROOT":  "/town",
+		"GT_MODEL": "opus",
+	})
+	if ctx.Role != "polecat" || ctx.Rig != "gastown" || ctx.TownRoot != "/town" {
+		t.Fatalf("ArgContextFromEnv = %+v", ctx)
+	}
+
+	args := []string{"--static", "--dir={{.TownRoot}}/{{.Rig}}", "{{if .Issue}}--resume{{end}}", "{{.Env.GT_TEST_FROM_GT}}", "{{.Broken"}
+	groups := []RuntimeArgGroup{
+		{IfEnv: "GT_MODEL", Args: []string{"--model", "{{.Env.GT_MODEL}}"}},
+		{IfEnv: "GT_MODEL=sonnet", Args: []string{"--fast"}},
+		{IfEnv: "!GT_VERBOSE", Args: []string{"--quiet"}},
+		{IfEnv: "GT_UNSET_FOR_TEST", Args: []string{"--never"}},
+	}
+
+	got := expandRuntimeArgs(args, groups, ctx, false)
+	want := []string{"--static", "--dir=/town/gastown", "outer", "{{.Broken", "--model", "opus", "--quiet"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandRuntimeArgs = %q, want %q", got, want)
+	}
+}
+
+func TestBuildCommandTemplatesArgs(t *testing.T) {
+	rc := &RuntimeConfig{
+		Command:    "claude",
+		Args:       []string{"--name", "{{.Role}} of {{.Rig}}"},
+		ArgGroups:  []RuntimeArgGroup{{IfEnv: "GT_MODEL", Args: []string{"--model", "{{.Env.GT_MODEL}}"}}},
+		ArgContext: ArgContextFromEnv(map[string]string{"GT_ROLE": "gastown/witness", "GT_RIG": "gastown", "GT_MODEL": "opus"}),
+	}
+	if got, want := rc.BuildCommandWithPrompt("hi"), `claude --name 'witness of gastown' --model opus "hi"`; got != want {
+		t.Errorf("BuildCommandWithPrompt = %q, want %q", got, want)
+	}
+	if got, want := rc.BuildArgsWithPrompt(""), []string{"claude", "--name", "witness of gastown", "--model", "opus"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildArgsWithPrompt = %q, want %q", got, want)
+	}
+}
+
+func TestBuildStartupCommandTemplatesArgsFromAgentEnv(t *testing.T) {
+	t.Setenv("GT_MODEL", "")
+	townRoot := t.TempDir()
+	rigPath := townRoot + "/gastown"
+	rc := fillRuntimeDefaults(&RuntimeConfig{Command: "claude", Args: []string{"--rig={{.Rig}}"}})
+
+	ts := NewTownSettings()
+	ts.DefaultAgent = "templated"
+	ts.Agents = map[string]*RuntimeConfig{"templated": rc}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), ts); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := BuildStartupCommand(AgentEnvSimple("witness", "gastown", ""), rigPath, "")
+	if !strings.Contains(cmd, "claude --rig=gastown") {
+		t.Errorf("startup command %q doesn't template --rig from the agent env", cmd)
+	}
+}
+
+func TestValidateRuntimeArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		rc      *RuntimeConfig
+		wantErr string
+	}{
+		{"plain", &RuntimeConfig{Args: []string{"--x", "{{.Rig}}"}}, ""},
+		{"unknown field", &RuntimeConfig{Args: []string{"{{.Nope}}"}}, "Nope"},
+		{"unparsable", &RuntimeConfig{Args: []string{"{{.Rig"}}, "arg"},
+		{"empty group", &RuntimeConfig{ArgGroups: []RuntimeArgGroup{{IfEnv: "X"}}}, "no args"},
+		{"nameless condition", &RuntimeConfig{ArgGroups: []RuntimeArgGroup{{IfEnv: "=x", Args: []string{"-v"}}}}, "no variable name"},
+	}
+	for _, tt := range tests {
+		err := ValidateRuntimeArgs(tt.rc)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
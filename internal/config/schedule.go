@@ -0,0 +1,247 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule indicates a malformed maintenance schedule expression.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ScheduleConfig defines scheduled maintenance windows for a rig.
+// While a window is active the daemon drains the rig (stops the witness and
+// refinery and does not auto-restart them). When the window closes the normal
+// heartbeat brings the agents back up.
+type ScheduleConfig struct {
+	// Maintenance lists cron-like expressions with five fields:
+	// minute hour day-of-month month day-of-week.
+	// The rig is in maintenance whenever the current minute matches any expression.
+	// Examples:
+	//   "* 0-6 * * *"      - nightly, midnight to 06:59
+	//   "* * * * 0,6"      - all day Saturday and Sunday
+	//   "* 18-23 * * 1-5"  - weekday evenings
+	Maintenance []string `json:"maintenance,omitempty"`
+
+	// Timezone is the IANA timezone used to evaluate expressions (e.g., "America/New_York").
+	// If empty, the daemon's local time is used.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ScheduleOverrideKey is the wisp config key that suppresses a rig's
+// maintenance schedule, written by 'gt rig start --ignore-schedule'. The
+// value is an RFC3339 timestamp marking the end of the window that was
+// overridden; the daemon ignores the schedule until then.
+const ScheduleOverrideKey = "schedule_override_until"
+
+// maxWindowScan bounds how far ahead MaintenanceWindowEnd searches for the
+// end of a window. Windows longer than this are treated as ending at the bound.
+const maxWindowScan = 8 * 24 * time.Hour
+
+// Validate checks that all maintenance expressions and the timezone parse.
+func (s *ScheduleConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	for _, expr := range s.Maintenance {
+		if _, err := parseCronExpr(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InMaintenanceWindow reports whether now falls inside any maintenance window.
+// Nil-safe: a nil schedule never has an active window.
+func (s *ScheduleConfig) InMaintenanceWindow(now time.Time) (bool, error) {
+	if s == nil || len(s.Maintenance) == 0 {
+		return false, nil
+	}
+	exprs, loc, err := s.compile()
+	if err != nil {
+		return false, err
+	}
+	return matchesAny(exprs, now.In(loc)), nil
+}
+
+// MaintenanceWindowEnd returns the first minute at or after now that is not
+// covered by a maintenance window. If now is outside all windows, now
+// (truncated to the minute) is returned.
+func (s *ScheduleConfig) MaintenanceWindowEnd(now time.Time) (time.Time, error) {
+	t := now.Truncate(time.Minute)
+	if s == nil || len(s.Maintenance) == 0 {
+		return t, nil
+	}
+	exprs, loc, err := s.compile()
+	if err != nil {
+		return t, err
+	}
+	limit := t.Add(maxWindowScan)
+	for t.Before(limit) && matchesAny(exprs, t.In(loc)) {
+		t = t.Add(time.Minute)
+	}
+	return t, nil
+}
+
+func (s *ScheduleConfig) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: timezone %q: %v", ErrInvalidSchedule, s.Timezone, err)
+	}
+	return loc, nil
+}
+
+func (s *ScheduleConfig) compile() ([]*cronExpr, *time.Location, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, nil, err
+	}
+	exprs := make([]*cronExpr, 0, len(s.Maintenance))
+	for _, raw := range s.Maintenance {
+		expr, err := parseCronExpr(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, loc, nil
+}
+
+func matchesAny(exprs []*cronExpr, t time.Time) bool {
+	for _, expr := range exprs {
+		if expr.matches(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// cronExpr is a parsed five-field cron expression.
+// Each field is a bitmask of allowed values.
+type cronExpr struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// cronField describes the valid range of one cron field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day-of-month", 1, 31},
+	{"month", 1, 12},
+	{"day-of-week", 0, 7},
+}
+
+func parseCronExpr(expr string) (*cronExpr, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%w: %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d",
+			ErrInvalidSchedule, expr, len(parts))
+	}
+
+	masks := make([]uint64, len(parts))
+	for i, part := range parts {
+		mask, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSchedule, expr, err)
+		}
+		masks[i] = mask
+	}
+
+	// Day-of-week 7 is an alias for Sunday.
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+
+	return &cronExpr{
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges (a-b),
+// wildcards (*), and steps (*/n, a-b/n) into a bitmask.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			n, err := strconv.Atoi(item[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step in %q", f.name, item)
+			}
+			rangePart, step = item[:idx], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, rangePart)
+			}
+			lo, hi = a, b
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("%s: invalid value %q", f.name, rangePart)
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = f.max // "a/n" means every n starting at a
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s: %q out of range %d-%d", f.name, item, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+// matches reports whether t (already in the schedule's location) matches.
+// Follows standard cron semantics: when both day-of-month and day-of-week
+// are restricted, a day matches if either field matches.
+func (c *cronExpr) matches(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 ||
+		c.hour&(1<<uint(t.Hour())) == 0 ||
+		c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
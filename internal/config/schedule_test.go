@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleConfig_InMaintenanceWindow(t *testing.T) {
+	t.Parallel()
+	// 2026-03-14 is a Saturday.
+	sat0300 := time.Date(2026, 3, 14, 3, 0, 0, 0, time.UTC)
+	mon0300 := time.Date(2026, 3, 16, 3, 0, 0, 0, time.UTC)
+	mon1200 := time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		exprs []string
+		now   time.Time
+		want  bool
+	}{
+		{"nightly inside", []string{"* 0-6 * * *"}, mon0300, true},
+		{"nightly outside", []string{"* 0-6 * * *"}, mon1200, false},
+		{"weekend inside", []string{"* * * * 0,6"}, sat0300, true},
+		{"weekend outside", []string{"* * * * 0,6"}, mon1200, false},
+		{"sunday as 7", []string{"* * * * 7"}, sat0300.Add(24 * time.Hour), true},
+		{"any of several", []string{"* * * * 6", "* 12 * * 1"}, mon1200, true},
+		{"step minutes", []string{"*/15 * * * *"}, mon1200.Add(15 * time.Minute), true},
+		{"step minutes miss", []string{"*/15 * * * *"}, mon1200.Add(7 * time.Minute), false},
+		{"dom or dow", []string{"* * 16 * 6"}, mon1200, true},
+		{"month mismatch", []string{"* * * 4 *"}, mon1200, false},
+		{"empty schedule", nil, mon1200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &ScheduleConfig{Maintenance: tt.exprs, Timezone: "UTC"}
+			got, err := s.InMaintenanceWindow(tt.now)
+			if err != nil {
+				t.Fatalf("InMaintenanceWindow() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("InMaintenanceWindow(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleConfig_NilSafe(t *testing.T) {
+	t.Parallel()
+	var s *ScheduleConfig
+	if got, err := s.InMaintenanceWindow(time.Now()); got || err != nil {
+		t.Errorf("nil schedule: got (%v, %v), want (false, nil)", got, err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("nil schedule Validate() = %v, want nil", err)
+	}
+}
+
+func TestScheduleConfig_MaintenanceWindowEnd(t *testing.T) {
+	t.Parallel()
+	s := &ScheduleConfig{Maintenance: []string{"* 0-6 * * *"}, Timezone: "UTC"}
+	now := time.Date(2026, 3, 16, 3, 30, 45, 0, time.UTC)
+
+	end, err := s.MaintenanceWindowEnd(now)
+	if err != nil {
+		t.Fatalf("MaintenanceWindowEnd() error: %v", err)
+	}
+	want := time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC)
+	if !end.Equal(want) {
+		t.Errorf("MaintenanceWindowEnd() = %v, want %v", end, want)
+	}
+
+	outside := time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)
+	end, err = s.MaintenanceWindowEnd(outside)
+	if err != nil {
+		t.Fatalf("MaintenanceWindowEnd() error: %v", err)
+	}
+	if !end.Equal(outside) {
+		t.Errorf("MaintenanceWindowEnd(outside) = %v, want %v", end, outside)
+	}
+}
+
+func TestScheduleConfig_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cfg     ScheduleConfig
+		wantErr bool
+	}{
+		{"valid", ScheduleConfig{Maintenance: []string{"0-30 22 * * 1-5"}}, false},
+		{"too few fields", ScheduleConfig{Maintenance: []string{"* * *"}}, true},
+		{"hour out of range", ScheduleConfig{Maintenance: []string{"* 24 * * *"}}, true},
+		{"bad step", ScheduleConfig{Maintenance: []string{"*/0 * * * *"}}, true},
+		{"inverted range", ScheduleConfig{Maintenance: []string{"* 6-2 * * *"}}, true},
+		{"non-numeric", ScheduleConfig{Maintenance: []string{"* * * * sat"}}, true},
+		{"bad timezone", ScheduleConfig{Timezone: "Mars/Olympus"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("Validate() error = %v, want ErrInvalidSchedule", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidScheduling indicates a malformed scheduling policy.
+var ErrInvalidScheduling = errors.New("invalid scheduling policy")
+
+// Scheduling policies for picking the next issue for an idle polecat.
+const (
+	// SchedulingPriority orders by priority, oldest first within a priority.
+	// This is the default and matches gt ready's ordering.
+	SchedulingPriority = "priority"
+
+	// SchedulingFIFO orders by creation time, oldest first.
+	SchedulingFIFO = "fifo"
+
+	// SchedulingWeighted scores issues by priority, age, deadline, and size
+	// using SchedulingConfig.Weights.
+	SchedulingWeighted = "weighted"
+)
+
+// SchedulingConfig controls how the witness picks the next ready issue for
+// an idle polecat (gt witness next). Per-issue hints come from bead labels:
+// size:<S|M|L|XL|points>, deadline:<YYYY-MM-DD>, and weight:<multiplier>.
+type SchedulingConfig struct {
+	// Policy is one of "priority" (default), "fifo", or "weighted".
+	Policy string `json:"policy,omitempty"`
+
+	// Weights tunes the weighted policy. Unset weights use the defaults.
+	Weights *SchedulingWeights `json:"weights,omitempty"`
+}
+
+// SchedulingWeights are the per-factor weights of the weighted policy.
+// Each factor is normalized to 0-1 before weighting, except age.
+type SchedulingWeights struct {
+	// Priority rewards higher priority (P0=1, P4=0). Default: 10.
+	Priority *float64 `json:"priority,omitempty"`
+
+	// Age rewards issues that have waited longer (1 per week waited, up to
+	// 4). Default: 1.
+	Age *float64 `json:"age,omitempty"`
+
+	// Deadline rewards issues whose deadline is near or past (1 when due
+	// within a day, falling off over two weeks). Default: 8.
+	Deadline *float64 `json:"deadline,omitempty"`
+
+	// Size rewards smaller issues, so quick wins aren't starved (XS=1, XL=0;
+	// unsized issues 0.5). Default: 1.
+	Size *float64 `json:"size,omitempty"`
+}
+
+// Default weights for the weighted scheduling policy.
+const (
+	DefaultSchedulingPriorityWeight = 10.0
+	DefaultSchedulingAgeWeight      = 1.0
+	DefaultSchedulingDeadlineWeight = 8.0
+	DefaultSchedulingSizeWeight     = 1.0
+)
+
+// EffectivePolicy returns the configured policy, defaulting to priority.
+func (c *SchedulingConfig) EffectivePolicy() string {
+	if c == nil || c.Policy == "" {
+		return SchedulingPriority
+	}
+	return c.Policy
+}
+
+// Resolved returns the weights with defaults applied.
+func (w *SchedulingWeights) Resolved() (priority, age, deadline, size float64) {
+	pick := func(v *float64, def float64) float64 {
+		if v == nil {
+			return def
+		}
+		return *v
+	}
+	if w == nil {
+		w = &SchedulingWeights{}
+	}
+	return pick(w.Priority, DefaultSchedulingPriorityWeight),
+		pick(w.Age, DefaultSchedulingAgeWeight),
+		pick(w.Deadline, DefaultSchedulingDeadlineWeight),
+		pick(w.Size, DefaultSchedulingSizeWeight)
+}
+
+// Validate checks the policy name and weights.
+func (c *SchedulingConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.EffectivePolicy() {
+	case SchedulingPriority, SchedulingFIFO, SchedulingWeighted:
+	default:
+		return fmt.Errorf("%w: unknown policy %q (valid: priority, fifo, weighted)", ErrInvalidScheduling, c.Policy)
+	}
+	if c.Weights != nil {
+		for _, f := range []struct {
+			name string
+			v    *float64
+		}{
+			{"priority", c.Weights.Priority},
+			{"age", c.Weights.Age},
+			{"deadline", c.Weights.Deadline},
+			{"size", c.Weights.Size},
+		} {
+			if f.v != nil && *f.v < 0 {
+				return fmt.Errorf("%w: weight %s must not be negative", ErrInvalidScheduling, f.name)
+			}
+		}
+	}
+	return nil
+}
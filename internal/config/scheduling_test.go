@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSchedulingConfigValidate(t *testing.T) {
+	neg := -1.0
+	tests := []struct {
+		name    string
+		cfg     *SchedulingConfig
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"default policy", &SchedulingConfig{}, false},
+		{"weighted", &SchedulingConfig{Policy: SchedulingWeighted}, false},
+		{"unknown policy", &SchedulingConfig{Policy: "random"}, true},
+		{"negative weight", &SchedulingConfig{Policy: SchedulingWeighted, Weights: &SchedulingWeights{Age: &neg}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidScheduling) {
+				t.Errorf("error %v is not ErrInvalidScheduling", err)
+			}
+		})
+	}
+}
+
+func TestSchedulingWeightsResolved(t *testing.T) {
+	p, a, d, s := (*SchedulingWeights)(nil).Resolved()
+	if p != DefaultSchedulingPriorityWeight || a != DefaultSchedulingAgeWeight || d != DefaultSchedulingDeadlineWeight || s != DefaultSchedulingSizeWeight {
+		t.Errorf("defaults = %v %v %v %v", p, a, d, s)
+	}
+	three := 3.0
+	if p, _, _, _ := (&SchedulingWeights{Priority: &three}).Resolved(); p != 3 {
+		t.Errorf("priority override = %v", p)
+	}
+}
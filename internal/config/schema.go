@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SchemaBaseURL is where the JSON Schemas for config files are published.
+// The files are generated by gt config schema --dir docs/schemas.
+const SchemaBaseURL = "https://raw.githubusercontent.com/steveyegge/gastown/main/docs/schemas/"
+
+// Schema names, one per config file type (see gt config schema).
+const (
+	SchemaTown         = "town"          // mayor/town.json
+	SchemaRigSettings  = "rig-settings"  // <rig>/settings/config.json
+	SchemaTownSettings = "town-settings" // settings/config.json
+	SchemaMessaging    = "messaging"     // config/messaging.json
+	SchemaDaemon       = "daemon"        // mayor/daemon.json
+	SchemaEscalation   = "escalation"    // settings/escalation.json
+)
+
+// SchemaURL returns the published URL of the named schema.
+func SchemaURL(name string) string {
+	return SchemaBaseURL + name + ".schema.json"
+}
+
+// marshalWithSchema encodes v like MarshalConfig and points editors at the
+// named schema: a leading "$schema" key in JSON, or the comment YAML and
+// TOML language servers look for.
+func marshalWithSchema(v interface{}, format Format, schema string) ([]byte, error) {
+	data, err := MarshalConfig(v, format)
+	if err != nil {
+		return nil, err
+	}
+	url := SchemaURL(schema)
+	switch format {
+	case FormatYAML:
+		return append([]byte("# yaml-language-server: $schema="+url+"\n"), data...), nil
+	case FormatTOML:
+		return append([]byte("#:schema "+url+"\n"), data...), nil
+	}
+
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return data, nil
+	}
+	key, err := json.Marshal(url)
+	if err != nil {
+		return nil, err
+	}
+	rest := bytes.TrimLeft(trimmed[1:], " \t\r\n")
+	if rest[0] == '}' {
+		return []byte("{\n  \"$schema\": " + string(key) + "\n}"), nil
+	}
+	return append([]byte("{\n  \"$schema\": "+string(key)+",\n  "), rest...), nil
+}
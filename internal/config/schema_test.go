@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveConfig_SchemaHint(t *testing.T) {
+	url := SchemaURL(SchemaEscalation)
+	tests := []struct {
+		ext  string
+		want string
+	}{
+		{".json", "{\n  \"$schema\": \"" + url + "\",\n  \"type\""},
+		{".yaml", "# yaml-language-server: $schema=" + url + "\n"},
+		{".toml", "#:schema " + url + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			dir := t.TempDir()
+			active := filepath.Join(dir, "escalation"+tt.ext)
+			if tt.ext != ".json" {
+				writeTestFile(t, active, "")
+			}
+			jsonPath := filepath.Join(dir, "escalation.json")
+
+			cfg := NewEscalationConfig()
+			if err := SaveEscalationConfig(jsonPath, cfg); err != nil {
+				t.Fatalf("SaveEscalationConfig: %v", err)
+			}
+			data, err := os.ReadFile(active)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), tt.want) {
+				t.Errorf("saved file starts %q, want prefix %q", data[:min(len(data), 120)], tt.want)
+			}
+			if _, err := LoadEscalationConfig(jsonPath); err != nil {
+				t.Errorf("LoadEscalationConfig after save: %v", err)
+			}
+		})
+	}
+}
+
+func TestMarshalWithSchema_EmptyObject(t *testing.T) {
+	data, err := marshalWithSchema(struct{}{}, FormatJSON, SchemaTown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if m["$schema"] != SchemaURL(SchemaTown) {
+		t.Errorf("$schema = %q", m["$schema"])
+	}
+}
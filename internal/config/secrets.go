@@ -0,0 +1,376 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SecretRefPrefix marks a RuntimeConfig env value as a reference to a secret
+// rather than a literal, e.g. {"ANTHROPIC_API_KEY": "secret:anthropic"}.
+const SecretRefPrefix = "secret:"
+
+// Secret provider names for SecretsConfig.Provider.
+const (
+	SecretProviderEnvFile  = "env-file"
+	SecretProviderKeychain = "keychain"
+	SecretProviderCommand  = "command"
+)
+
+// secretKeychainService is the service name secrets are stored under in the
+// OS keychain (macOS Keychain, libsecret on Linux).
+const secretKeychainService = "gastown"
+
+var (
+	// ErrSecretNotFound is returned when a provider has no value for a secret.
+	ErrSecretNotFound = errors.New("secret not found")
+
+	// ErrInsecureSecretsFile is returned when the env-file provider's file is
+	// readable or writable by group or others.
+	ErrInsecureSecretsFile = errors.New("secrets file permissions too open")
+
+	// secretNamePattern restricts secret names to characters that are safe to
+	// embed unquoted in a shell command line.
+	secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// SecretsConfig configures where secret references are resolved from.
+// Stored in town settings (settings/config.json) under "secrets".
+type SecretsConfig struct {
+	// Provider selects the backend: "env-file" (default), "keychain", or "command".
+	Provider string `json:"provider,omitempty"`
+
+	// EnvFile is the KEY=VALUE file used by the env-file provider. Relative
+	// paths are resolved against the town root. The file must not be
+	// accessible by group or others (mode 0600 or stricter).
+	// Default: "settings/secrets.env".
+	EnvFile string `json:"env_file,omitempty"`
+
+	// Command is run via "sh -c" by the command provider. The secret name is
+	// passed in GT_SECRET_NAME and the value is read from stdout.
+	// Example: "op read op://gastown/$GT_SECRET_NAME/credential"
+	Command string `json:"command,omitempty"`
+}
+
+// SecretProvider resolves secret values by name.
+type SecretProvider interface {
+	Get(name string) (string, error)
+}
+
+// SecretStore is a SecretProvider that can also save secrets.
+type SecretStore interface {
+	SecretProvider
+	Set(name, value string) error
+}
+
+// ErrSecretsReadOnly is returned when saving a secret with a provider that
+// can only read them.
+var ErrSecretsReadOnly = errors.New("secrets provider is read-only")
+
+// ParseSecretRef reports whether v is a secret reference ("secret:NAME") and
+// returns the referenced name.
+func ParseSecretRef(v string) (string, bool) {
+	name, ok := strings.CutPrefix(v, SecretRefPrefix)
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// ValidateSecretName checks that a secret name is non-empty and contains only
+// letters, digits, '_', '.', and '-'.
+func ValidateSecretName(name string) error {
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.', or '-'", name)
+	}
+	return nil
+}
+
+// Validate checks the provider name and required fields.
+func (c *SecretsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Provider {
+	case "", SecretProviderEnvFile, SecretProviderKeychain:
+	case SecretProviderCommand:
+		if strings.TrimSpace(c.Command) == "" {
+			return fmt.Errorf("secrets: provider %q requires command", SecretProviderCommand)
+		}
+	default:
+		return fmt.Errorf("secrets: unknown provider %q", c.Provider)
+	}
+	return nil
+}
+
+// NewSecretProvider returns the provider described by cfg.
+// A nil cfg selects the env-file provider with its default path.
+func NewSecretProvider(townRoot string, cfg *SecretsConfig) (SecretProvider, error) {
+	if cfg == nil {
+		cfg = &SecretsConfig{}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Provider {
+	case SecretProviderKeychain:
+		return keychainSecretProvider{}, nil
+	case SecretProviderCommand:
+		return commandSecretProvider{command: cfg.Command, dir: townRoot}, nil
+	default:
+		path := cfg.EnvFile
+		if path == "" {
+			path = filepath.Join("settings", "secrets.env")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+		return envFileSecretProvider{path: path}, nil
+	}
+}
+
+// ResolveSecret looks up a secret using the provider configured in the
+// town's settings.
+func ResolveSecret(townRoot, name string) (string, error) {
+	if err := ValidateSecretName(name); err != nil {
+		return "", err
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return "", fmt.Errorf("loading town settings: %w", err)
+	}
+	provider, err := NewSecretProvider(townRoot, settings.Secrets)
+	if err != nil {
+		return "", err
+	}
+	return provider.Get(name)
+}
+
+// StoreSecret saves a secret with the provider configured in the town's
+// settings. The command provider is read-only.
+func StoreSecret(townRoot, name, value string) error {
+	if err := ValidateSecretName(name); err != nil {
+		return err
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	provider, err := NewSecretProvider(townRoot, settings.Secrets)
+	if err != nil {
+		return err
+	}
+	store, ok := provider.(SecretStore)
+	if !ok {
+		return fmt.Errorf("%w: store %s with your secrets manager", ErrSecretsReadOnly, name)
+	}
+	return store.Set(name, value)
+}
+
+// StartupSecrets resolves the secret references in the environment of an
+// agent's startup command — its agent's env settings and its rig's Dolt
+// password — and returns their values by variable name. Startup commands
+// leave these variables out, so launchers pass the returned values through
+// the agent's environment without putting them in any argv (a private env
+// file under tmux, the process environment otherwise). A secret that can't
+// be resolved is an error: the agent must not start without it.
+func StartupSecrets(role, townRoot, rigPath, agentOverride string) (map[string]string, error) {
+	envVars := map[string]string{"GT_ROLE": role}
+	if townRoot != "" {
+		envVars["GT_ROOT"] = townRoot
+	}
+	_, env, err := resolveStartupEnv(envVars, rigPath, agentOverride)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string]string)
+	for key, v := range env {
+		name, ok := ParseSecretRef(v)
+		if !ok {
+			continue
+		}
+		value, err := ResolveSecret(env["GT_ROOT"], name)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s for %s: %w", v, key, err)
+		}
+		secrets[key] = value
+	}
+	return secrets, nil
+}
+
+// envFileSecretProvider reads secrets from a KEY=VALUE file.
+type envFileSecretProvider struct {
+	path string
+}
+
+func (p envFileSecretProvider) Get(name string) (string, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s (no secrets file at %s)", ErrSecretNotFound, name, p.path)
+		}
+		return "", fmt.Errorf("reading secrets file: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%w: %s has mode %04o, want 0600", ErrInsecureSecretsFile, p.path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(p.path) //nolint:gosec // G304: path is from town settings
+	if err != nil {
+		return "", fmt.Errorf("reading secrets file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != name {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+}
+
+// Set replaces the secret's line in the file, or appends one, creating the
+// file with mode 0600 if needed.
+func (p envFileSecretProvider) Set(name, value string) error {
+	var lines []string
+	data, err := os.ReadFile(p.path) //nolint:gosec // G304: path is from town settings
+	switch {
+	case err == nil:
+		if info, statErr := os.Stat(p.path); statErr == nil && info.Mode().Perm()&0o077 != 0 {
+			return fmt.Errorf("%w: %s has mode %04o, want 0600", ErrInsecureSecretsFile, p.path, info.Mode().Perm())
+		}
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	case !os.IsNotExist(err):
+		return fmt.Errorf("reading secrets file: %w", err)
+	}
+
+	entry := name + "=" + value
+	replaced := false
+	for i, line := range lines {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), "="); ok && strings.TrimSpace(key) == name {
+			lines[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, entry)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return fmt.Errorf("creating secrets dir: %w", err)
+	}
+	if err := util.AtomicWriteFile(p.path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("writing secrets file: %w", err)
+	}
+	return nil
+}
+
+// keychainSecretProvider reads secrets from the OS keychain. Secrets are
+// stored with service "gastown" and the secret name as the account.
+type keychainSecretProvider struct{}
+
+func (keychainSecretProvider) Get(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", secretKeychainService, "-a", name, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", secretKeychainService, "name", name)
+	default:
+		return "", fmt.Errorf("keychain secrets are not supported on %s", runtime.GOOS)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s (keychain lookup: %v)", ErrSecretNotFound, name, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// Set adds or updates the secret in the OS keychain.
+func (keychainSecretProvider) Set(name, value string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", secretKeychainService, "-a", name, "-w", value)
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label", "gastown "+name, "service", secretKeychainService, "name", name)
+		cmd.Stdin = strings.NewReader(value)
+	default:
+		return fmt.Errorf("keychain secrets are not supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keychain store for %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// commandSecretProvider runs a user-supplied command to fetch secrets.
+type commandSecretProvider struct {
+	command string
+	dir     string
+}
+
+func (p commandSecretProvider) Get(name string) (string, error) {
+	cmd := exec.Command("sh", "-c", p.command) //nolint:gosec // G204: command is from town settings
+	cmd.Dir = p.dir
+	cmd.Env = append(os.Environ(), "GT_SECRET_NAME="+name)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret command for %s: %w: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("secret command for %s: %w", name, err)
+	}
+	value := strings.TrimRight(string(out), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%w: %s (command returned no output)", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// addDoltPasswordEnv gives a rig agent's session the password for the
+// rig's scoped Dolt user (see gt dolt users) as a secret reference, so bd
+// can connect as that user; StartupSecrets resolves it. The names match beads.DoltPasswordEnv and
+// beads.DoltUserSecret, which this package cannot import.
+func addDoltPasswordEnv(env map[string]string, rigPath string) {
+	if rigPath == "" {
+		return
+	}
+	for _, beadsDir := range []string{filepath.Join(rigPath, "mayor", "rig", ".beads"), filepath.Join(rigPath, ".beads")} {
+		data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json")) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		var meta struct {
+			DoltServerUser string `json:"dolt_server_user"`
+		}
+		if json.Unmarshal(data, &meta) == nil && meta.DoltServerUser != "" {
+			env["BEADS_DOLT_PASSWORD"] = SecretRefPrefix + "dolt-" + meta.DoltServerUser
+		}
+		return
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSecretRef(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"secret:anthropic", "anthropic", true},
+		{"secret:", "", false},
+		{"plain-value", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSecretRef(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseSecretRef(%q) = (%q, %v), want (%q, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestEnvFileSecretProvider(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, "settings", "secrets.env")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "# comment\nanthropic=sk-test-123\nquoted=\"with spaces\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := NewSecretProvider(townRoot, nil)
+	if err != nil {
+		t.Fatalf("NewSecretProvider() error: %v", err)
+	}
+
+	if got, err := provider.Get("anthropic"); err != nil || got != "sk-test-123" {
+		t.Errorf("Get(anthropic) = (%q, %v), want sk-test-123", got, err)
+	}
+	if got, err := provider.Get("quoted"); err != nil || got != "with spaces" {
+		t.Errorf("Get(quoted) = (%q, %v), want \"with spaces\"", got, err)
+	}
+	if _, err := provider.Get("missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrSecretNotFound", err)
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Get("anthropic"); !errors.Is(err, ErrInsecureSecretsFile) {
+		t.Errorf("Get() with 0644 file error = %v, want ErrInsecureSecretsFile", err)
+	}
+}
+
+func TestCommandSecretProvider(t *testing.T) {
+	t.Parallel()
+	provider, err := NewSecretProvider(t.TempDir(), &SecretsConfig{
+		Provider: SecretProviderCommand,
+		Command:  `echo "value-for-$GT_SECRET_NAME"`,
+	})
+	if err != nil {
+		t.Fatalf("NewSecretProvider() error: %v", err)
+	}
+	if got, err := provider.Get("github"); err != nil || got != "value-for-github" {
+		t.Errorf("Get(github) = (%q, %v), want value-for-github", got, err)
+	}
+}
+
+func TestSecretsConfig_Validate(t *testing.T) {
+	t.Parallel()
+	if err := (&SecretsConfig{Provider: SecretProviderCommand}).Validate(); err == nil {
+		t.Error("command provider without command should fail validation")
+	}
+	if err := (&SecretsConfig{Provider: "vault"}).Validate(); err == nil {
+		t.Error("unknown provider should fail validation")
+	}
+	if err := (&SecretsConfig{Provider: SecretProviderKeychain}).Validate(); err != nil {
+		t.Errorf("keychain provider Validate() = %v, want nil", err)
+	}
+}
+
+func TestExportPrefix_SecretRefOmitted(t *testing.T) {
+	t.Parallel()
+	got := ExportPrefix(map[string]string{"API_KEY": "secret:api", "GT_ROLE": "mayor"})
+	want := `export GT_ROLE=mayor && `
+	if got != want {
+		t.Errorf("ExportPrefix() = %q, want %q", got, want)
+	}
+	if got := ExportPrefix(map[string]string{"API_KEY": "secret:api"}); got != "" {
+		t.Errorf("ExportPrefix(only secrets) = %q, want empty", got)
+	}
+}
+
+func TestStartupSecrets(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	beadsDir := filepath.Join(rigPath, "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_server_user":"gt_gastown"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A missing secret fails the start instead of exporting an empty value.
+	if _, err := StartupSecrets("polecat", townRoot, rigPath, ""); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("StartupSecrets() error = %v, want ErrSecretNotFound", err)
+	}
+
+	if err := StoreSecret(townRoot, "dolt-gt_gastown", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	secrets, err := StartupSecrets("polecat", townRoot, rigPath, "")
+	if err != nil {
+		t.Fatalf("StartupSecrets() error: %v", err)
+	}
+	if got := secrets["BEADS_DOLT_PASSWORD"]; got != "hunter2" {
+		t.Errorf("BEADS_DOLT_PASSWORD = %q, want hunter2", got)
+	}
+
+	cmd := BuildPolecatStartupCommand("gastown", "toast", rigPath, "")
+	if strings.Contains(cmd, "BEADS_DOLT_PASSWORD") || strings.Contains(cmd, "hunter2") {
+		t.Errorf("startup command carries the secret: %q", cmd)
+	}
+}
+
+func TestStoreSecret_EnvFile(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	if err := StoreSecret(townRoot, "dolt-gt_gastown", "first"); err != nil {
+		t.Fatalf("StoreSecret() error: %v", err)
+	}
+	if err := StoreSecret(townRoot, "other", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := StoreSecret(townRoot, "dolt-gt_gastown", "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ResolveSecret(townRoot, "dolt-gt_gastown"); err != nil || got != "rotated" {
+		t.Errorf("ResolveSecret() = (%q, %v), want rotated", got, err)
+	}
+	info, err := os.Stat(filepath.Join(townRoot, "settings", "secrets.env"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %v (err %v), want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestAddDoltPasswordEnv(t *testing.T) {
+	t.Parallel()
+	rigPath := t.TempDir()
+	env := map[string]string{}
+	addDoltPasswordEnv(env, rigPath)
+	if _, ok := env["BEADS_DOLT_PASSWORD"]; ok {
+		t.Fatal("password env set for a rig without metadata")
+	}
+
+	beadsDir := filepath.Join(rigPath, "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_server_user":"gt_gastown"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	addDoltPasswordEnv(env, rigPath)
+	if got := env["BEADS_DOLT_PASSWORD"]; got != "secret:dolt-gt_gastown" {
+		t.Errorf("BEADS_DOLT_PASSWORD = %q", got)
+	}
+}
@@ -35,6 +35,8 @@ func TestMain(m *testing.M) {
 	originalPath := os.Getenv("PATH")
 	_ = os.Setenv("PATH", stubDir+string(os.PathListSeparator)+originalPath)
 
+	resetAgentCapabilities(filepath.Join(stubDir, "agent-capabilities.json"))
+
 	code := m.Run()
 
 	_ = os.Setenv("PATH", originalPath)
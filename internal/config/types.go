@@ -23,12 +23,33 @@ type TownConfig struct {
 // MayorConfig represents town-level behavioral configuration (mayor/config.json).
 // This is separate from TownConfig (identity) to keep configuration concerns distinct.
 type MayorConfig struct {
-	Type            string           `json:"type"`                        // "mayor-config"
-	Version         int              `json:"version"`                     // schema version
-	Theme           *TownThemeConfig `json:"theme,omitempty"`             // global theme settings
-	Daemon          *DaemonConfig    `json:"daemon,omitempty"`            // daemon settings
-	Deacon          *DeaconConfig    `json:"deacon,omitempty"`            // deacon settings
-	DefaultCrewName string           `json:"default_crew_name,omitempty"` // default crew name for new rigs
+	Type            string              `json:"type"`                        // "mayor-config"
+	Version         int                 `json:"version"`                     // schema version
+	Theme           *TownThemeConfig    `json:"theme,omitempty"`             // global theme settings
+	Daemon          *DaemonConfig       `json:"daemon,omitempty"`            // daemon settings
+	Deacon          *DeaconConfig       `json:"deacon,omitempty"`            // deacon settings
+	DefaultCrewName string              `json:"default_crew_name,omitempty"` // default crew name for new rigs
+	RigDiscovery    *RigDiscoveryConfig `json:"rig_discovery,omitempty"`     // where to look for unregistered rig databases
+}
+
+// RigDiscoveryConfig controls where gt looks for rig databases to migrate
+// beyond the rigs registered in rigs.json, which are always included.
+type RigDiscoveryConfig struct {
+	// ScanDepth is how many directory levels below the town root are
+	// searched, so nested layouts such as clients/acme/rig are found. The
+	// scan doesn't descend into directories with a .beads directory.
+	// Default: 1 (the town root's own subdirectories).
+	ScanDepth int `json:"scan_depth,omitempty"`
+
+	// Include, if set, limits scanned rigs to paths matching one of these
+	// globs, relative to the town root with "/" separators (e.g.
+	// "clients/*/*"). Matching is case-insensitive. Dot-directories are
+	// only scanned when an Include glob names them.
+	Include []string `json:"include,omitempty"`
+
+	// Exclude skips paths matching any of these globs, and the scan doesn't
+	// descend into them.
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 // CurrentTownSettingsVersion is the current schema version for TownSettings.
@@ -70,6 +91,18 @@ type TownSettings struct {
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
 
+	// SessionPrefix namespaces this town's rig-level tmux sessions so two
+	// towns can run on one machine without colliding. With "alpha", the
+	// gastown witness runs as "alpha-gt-witness" instead of "gt-witness".
+	// Town-level hq- sessions are unchanged.
+	// Default: "" (no prefix)
+	SessionPrefix string `json:"session_prefix,omitempty"`
+
+	// Multiplexer selects the terminal multiplexer that hosts agent sessions.
+	// Values: "tmux" (default), "zellij", "screen", or "headless" to run
+	// agents as daemon-supervised background processes with log files.
+	Multiplexer string `json:"multiplexer,omitempty"`
+
 	// WebTimeouts configures command execution timeouts for the web dashboard.
 	WebTimeouts *WebTimeoutsConfig `json:"web_timeouts,omitempty"`
 
@@ -86,6 +119,38 @@ type TownSettings struct {
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
 	CostTier string `json:"cost_tier,omitempty"`
+
+	// Secrets configures how "secret:NAME" references in agent env vars are
+	// resolved. Nil uses the env-file provider (settings/secrets.env).
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+
+	// Audit configures the log of mutating gt commands (mayor/audit.jsonl).
+	Audit *AuditConfig `json:"audit,omitempty"`
+
+	// Notifications configures which rig events gt rig watch reports and how.
+	Notifications *NotificationsConfig `json:"notifications,omitempty"`
+
+	// Retry overrides how Dolt errors are retried, keyed by error class:
+	// "retryable", "read_only", "capacity", "corruption" (see internal/errclass),
+	// and how long gt waits on slow operations, keyed by operation name
+	// (see doltserver.RetryOperations).
+	// Example: {"capacity": {"max_attempts": 5, "base_backoff": "5s"},
+	//           "dolt_start": {"max_elapsed": "30s"}}
+	Retry map[string]*RetryPolicyConfig `json:"retry,omitempty"`
+
+	// DoltFallback is what bd does while the town's Dolt server is down.
+	// Values: "fail" (default) refuses bd commands instead of letting bd
+	// create an isolated local database; "read-only" has the daemon give each
+	// rig a local snapshot bd can read but not write; "queue" lets bd write to
+	// the snapshot, and gt dolt reconcile merges those writes back once the
+	// server returns; "journal" fails bd reads but queues gt's own bead
+	// updates in .runtime/bead-journal.jsonl for gt dolt journal apply.
+	DoltFallback string `json:"dolt_fallback,omitempty"`
+
+	// Telemetry configures OpenTelemetry tracing of gt commands (see
+	// internal/telemetry). Nil disables it unless the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variable is set.
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -99,6 +164,116 @@ func NewTownSettings() *TownSettings {
 	}
 }
 
+// TownSettings.DoltFallback policies.
+const (
+	DoltFallbackFail     = "fail"      // bd commands fail while the server is down
+	DoltFallbackReadOnly = "read-only" // bd reads a local snapshot; writes fail
+	DoltFallbackQueue    = "queue"     // bd writes to a local snapshot, reconciled later
+	DoltFallbackJournal  = "journal"   // gt journals bead mutations, applied later
+)
+
+// TelemetryConfig configures trace export over OTLP/HTTP.
+type TelemetryConfig struct {
+	// Endpoint is the collector's OTLP/HTTP URL, e.g. "http://localhost:4318".
+	// Traces are posted to its /v1/traces path.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Headers are sent with every export, e.g. an API key for a hosted collector.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// SampleRatio is the fraction of commands traced, from 0 to 1. Nested
+	// gt processes follow their parent's decision. Default: 1.
+	SampleRatio *float64 `json:"sample_ratio,omitempty"`
+}
+
+// DefaultAuditRetentionDays is how long audit log entries are kept when
+// AuditConfig.RetentionDays is unset.
+const DefaultAuditRetentionDays = 90
+
+// AuditConfig configures the append-only audit log of mutating commands.
+type AuditConfig struct {
+	// Disabled turns off audit logging entirely.
+	Disabled bool `json:"disabled,omitempty"`
+	// RetentionDays is how long entries are kept before being pruned.
+	// Negative keeps entries forever. Default: 90.
+	RetentionDays int `json:"retention_days,omitempty"`
+}
+
+// Retention returns the configured retention period, or 0 to keep entries forever.
+func (c *AuditConfig) Retention() time.Duration {
+	days := DefaultAuditRetentionDays
+	if c != nil && c.RetentionDays != 0 {
+		days = c.RetentionDays
+	}
+	if days < 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Rig watch event kinds for NotificationsConfig.Events.
+const (
+	WatchEventMerged        = "merged"         // refinery merged a branch
+	WatchEventMergeFailed   = "merge_failed"   // refinery rejected a branch
+	WatchEventPolecatFailed = "polecat_failed" // polecat session died (not via gt done)
+	WatchEventWitnessDied   = "witness_died"   // witness session died and was restarted
+	WatchEventDoltRestarted = "dolt_restarted" // daemon restarted a crashed or unhealthy Dolt server
+)
+
+// WatchEvents lists the valid rig watch event kinds.
+var WatchEvents = []string{
+	WatchEventMerged,
+	WatchEventMergeFailed,
+	WatchEventPolecatFailed,
+	WatchEventWitnessDied,
+	WatchEventDoltRestarted,
+}
+
+// NotificationsConfig configures gt rig watch.
+type NotificationsConfig struct {
+	// Events opts in to event kinds (see WatchEvents).
+	// Default: merged, polecat_failed, witness_died, dolt_restarted.
+	Events []string `json:"events,omitempty"`
+
+	// Desktop sends OS desktop notifications (notify-send, osascript).
+	// Default: true.
+	Desktop *bool `json:"desktop,omitempty"`
+
+	// Webhook receives a JSON POST for each notification.
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// EnabledEvents returns the opted-in event kinds, applying the default.
+func (c *NotificationsConfig) EnabledEvents() []string {
+	if c == nil || len(c.Events) == 0 {
+		return []string{WatchEventMerged, WatchEventPolecatFailed, WatchEventWitnessDied, WatchEventDoltRestarted}
+	}
+	return c.Events
+}
+
+// DesktopEnabled reports whether desktop notifications are on.
+func (c *NotificationsConfig) DesktopEnabled() bool {
+	return c == nil || c.Desktop == nil || *c.Desktop
+}
+
+// RetryPolicyConfig overrides the retry policy for one error class or
+// operation. Unset fields keep the built-in default.
+type RetryPolicyConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// 1 disables retry.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// BaseBackoff is the delay after the first failure, doubling after each
+	// further failure. Example: "500ms".
+	BaseBackoff string `json:"base_backoff,omitempty"`
+	// MaxBackoff caps the delay between attempts. Example: "15s".
+	MaxBackoff string `json:"max_backoff,omitempty"`
+	// MaxElapsed caps the total time spent retrying an operation. Ignored
+	// for error classes. Example: "30s".
+	MaxElapsed string `json:"max_elapsed,omitempty"`
+	// Jitter randomizes each delay by ±Jitter/2; 0 disables it.
+	Jitter *float64 `json:"jitter,omitempty"`
+}
+
 // WebTimeoutsConfig configures command execution timeouts for the web dashboard.
 type WebTimeoutsConfig struct {
 	// CmdTimeout is the timeout for bd (beads) commands. Default: "15s".
@@ -221,6 +396,8 @@ type PatrolConfig struct {
 	Interval string   `json:"interval,omitempty"` // e.g., "5m"
 	Agent    string   `json:"agent,omitempty"`    // agent that runs this patrol
 	Rigs     []string `json:"rigs,omitempty"`     // rigs this patrol manages (empty = all)
+	Exec     string   `json:"exec,omitempty"`     // shell command for a custom patrol
+	Timeout  string   `json:"timeout,omitempty"`  // e.g., "2m" (custom patrols; default: interval)
 }
 
 // CurrentDaemonPatrolConfigVersion is the current schema version for DaemonPatrolConfig.
@@ -282,6 +459,10 @@ type RigEntry struct {
 	LocalRepo   string       `json:"local_repo,omitempty"`
 	AddedAt     time.Time    `json:"added_at"`
 	BeadsConfig *BeadsConfig `json:"beads,omitempty"`
+
+	// Remote runs this rig's sessions and git operations on another machine
+	// over SSH. Nil means the rig is local.
+	Remote *RemoteConfig `json:"remote,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for a rig.
@@ -333,6 +514,14 @@ type RigSettings struct {
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
+	Schedule   *ScheduleConfig   `json:"schedule,omitempty"`    // maintenance window settings
+	Container  *ContainerConfig  `json:"container,omitempty"`   // polecat container sandbox
+	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`  // next-issue policy for idle polecats
+	WorkCheck  *WorkCheckConfig  `json:"work_check,omitempty"`  // clones checked before stop/shutdown/restart
+	CI         *CIConfig         `json:"ci,omitempty"`          // external CI monitoring of the default branch
+	Git        *GitConfig        `json:"git,omitempty"`         // submodule and LFS checkout of clones
+	Dedup      *DedupConfig      `json:"dedup,omitempty"`       // duplicate detection for automatically filed beads
+	Labels     *LabelsConfig     `json:"labels,omitempty"`      // label taxonomy and enforcement
 
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp", "opencode", "copilot")
@@ -368,6 +557,17 @@ type CrewConfig struct {
 	Startup string `json:"startup,omitempty"`
 }
 
+// WorkCheckConfig selects which clones gt rig shutdown/stop/restart check
+// for uncommitted work, beyond polecats (always checked).
+type WorkCheckConfig struct {
+	// Crew also checks every crew workspace.
+	Crew bool `json:"crew,omitempty"`
+
+	// Refinery also checks refinery/rig, which the refinery hard-resets
+	// when it starts merging.
+	Refinery bool `json:"refinery,omitempty"`
+}
+
 // RuntimeConfig represents LLM runtime configuration for agent sessions.
 // This allows switching between different LLM backends (claude, aider, etc.)
 // without modifying startup code.
@@ -383,8 +583,13 @@ type RuntimeConfig struct {
 	// Args are additional command-line arguments.
 	// Default: ["--dangerously-skip-permissions"] for built-in agents.
 	// Empty array [] means no args (not "use defaults").
+	// Args may use templates such as {{.Rig}} and {{.Env.NAME}}; see ArgContext.
 	Args []string `json:"args"`
 
+	// ArgGroups are args added only when their condition holds, e.g.
+	// --model X only when an env var is set. They follow Args.
+	ArgGroups []RuntimeArgGroup `json:"arg_groups,omitempty"`
+
 	// Env are environment variables to set when starting the agent.
 	// These are merged with the standard GT_* variables.
 	// Used for agent-specific configuration like OPENCODE_PERMISSION.
@@ -392,6 +597,7 @@ type RuntimeConfig struct {
 
 	// InitialPrompt is an optional first message to send after startup.
 	// For claude, this is passed as the prompt argument.
+	// "prompt:NAME" renders NAME from the prompt library (see RenderPrompt).
 	// Empty by default (hooks handle context).
 	InitialPrompt string `json:"initial_prompt,omitempty"`
 
@@ -417,6 +623,11 @@ type RuntimeConfig struct {
 	// BuildStartupCommand can export GT_AGENT for process detection.
 	// Not serialized — this is a runtime-only field.
 	ResolvedAgent string `json:"-"`
+
+	// ArgContext supplies the values templated args expand to. Set by
+	// BuildStartupCommand from the agent's env; when nil, gt's own
+	// environment (GT_ROLE, GT_RIG, ...) is used.
+	ArgContext *ArgContext `json:"-"`
 }
 
 // RuntimeSessionConfig configures how Gas Town discovers runtime session IDs.
@@ -477,7 +688,7 @@ func (rc *RuntimeConfig) BuildCommand() string {
 	resolved := normalizeRuntimeConfig(rc)
 
 	cmd := resolved.Command
-	args := resolved.Args
+	args := expandRuntimeArgs(resolved.Args, resolved.ArgGroups, resolved.ArgContext, true)
 
 	// Combine command and args
 	if len(args) > 0 {
@@ -497,7 +708,7 @@ func (rc *RuntimeConfig) BuildCommandWithPrompt(prompt string) string {
 	// Use provided prompt or fall back to config
 	p := prompt
 	if p == "" {
-		p = resolved.InitialPrompt
+		p = resolvePromptRef(resolved.InitialPrompt, resolved.ArgContext)
 	}
 
 	if p == "" || resolved.PromptMode == "none" {
@@ -517,11 +728,11 @@ func (rc *RuntimeConfig) BuildCommandWithPrompt(prompt string) string {
 // BuildArgsWithPrompt returns the runtime command and args suitable for exec.
 func (rc *RuntimeConfig) BuildArgsWithPrompt(prompt string) []string {
 	resolved := normalizeRuntimeConfig(rc)
-	args := append([]string{resolved.Command}, resolved.Args...)
+	args := append([]string{resolved.Command}, expandRuntimeArgs(resolved.Args, resolved.ArgGroups, resolved.ArgContext, false)...)
 
 	p := prompt
 	if p == "" {
-		p = resolved.InitialPrompt
+		p = resolvePromptRef(resolved.InitialPrompt, resolved.ArgContext)
 	}
 
 	if p != "" && resolved.PromptMode != "none" {
@@ -849,6 +1060,12 @@ type MergeQueueConfig struct {
 	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// OnDoltConflict is what gt done does when merging a polecat's Dolt
+	// branch into main would conflict: "theirs" (default) resolves in the
+	// polecat's favor, "escalate" leaves the data on the branch and
+	// escalates to a human. Schema conflicts are always escalated.
+	OnDoltConflict string `json:"on_dolt_conflict,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	// Nil defaults to true (tests are run).
 	RunTests *bool `json:"run_tests,omitempty"`
@@ -856,6 +1073,11 @@ type MergeQueueConfig struct {
 	// TestCommand is the command to run for tests.
 	TestCommand string `json:"test_command,omitempty"`
 
+	// TestRules scope testing to the files a merge changes: matching
+	// rules' commands run before TestCommand, and a merge whose files all
+	// match skip rules (e.g., docs/**) is not tested.
+	TestRules []TestRuleConfig `json:"test_rules,omitempty"`
+
 	// LintCommand is the command to run for linting (used by formulas).
 	LintCommand string `json:"lint_command,omitempty"`
 
@@ -875,6 +1097,14 @@ type MergeQueueConfig struct {
 	// RetryFlakyTests is the number of times to retry flaky tests.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
+	// CacheTestResults skips tests for a re-queued branch whose commit and
+	// target commit already passed. Nil defaults to true.
+	CacheTestResults *bool `json:"cache_test_results,omitempty"`
+
+	// FlakeQuarantineThreshold quarantines a test once it has flaked on this
+	// many distinct branches, opening a bead to fix it. Zero disables.
+	FlakeQuarantineThreshold int `json:"flake_quarantine_threshold,omitempty"`
+
 	// PollInterval is how often to poll for new merge requests (e.g., "30s").
 	PollInterval string `json:"poll_interval"`
 
@@ -884,6 +1114,72 @@ type MergeQueueConfig struct {
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// ExpediteLabel is the MR bead label that puts an MR in the expedite
+	// lane, merged ahead of the normal lane. Empty means "gt:expedite".
+	ExpediteLabel string `json:"expedite_label,omitempty"`
+
+	// ExpediteMaxConcurrent is max_concurrent for the expedite lane.
+	// Nil defaults to 1; zero means no limit.
+	ExpediteMaxConcurrent *int `json:"expedite_max_concurrent,omitempty"`
+
+	// NormalLaneMaxWait is how long a normal MR can wait before it goes
+	// ahead of expedited MRs (e.g., "2h"). "0" disables the protection.
+	NormalLaneMaxWait string `json:"normal_lane_max_wait,omitempty"`
+
+	// PreMerge lists checks (lint, build, custom scripts) the refinery runs
+	// in order before merging. PostMerge lists actions (deploy trigger,
+	// changelog update) run in order after a successful push.
+	PreMerge  []MergeHookConfig `json:"pre_merge,omitempty"`
+	PostMerge []MergeHookConfig `json:"post_merge,omitempty"`
+
+	// Release tags the default branch after queue milestones.
+	Release *ReleaseConfig `json:"release,omitempty"`
+}
+
+// ReleaseConfig controls release tagging by the refinery. Releases can
+// always be cut on demand with 'gt release cut'.
+type ReleaseConfig struct {
+	// Every cuts a release automatically after this many merges to the
+	// default branch since the last release tag. Zero disables.
+	Every int `json:"every,omitempty"`
+
+	// TagPrefix prefixes release tags (default "v", giving v1.2.3).
+	TagPrefix string `json:"tag_prefix,omitempty"`
+
+	// Branch also creates a release/<tag> branch for each release.
+	Branch bool `json:"branch,omitempty"`
+}
+
+// TestRuleConfig maps changed paths to a focused test command, or marks
+// them as needing no tests.
+type TestRuleConfig struct {
+	// Paths are globs over the changed files; "**" spans directories and
+	// a pattern without "/" matches base names (e.g., "*.md").
+	Paths []string `json:"paths"`
+
+	// Cmd runs before test_command when any changed file matches.
+	Cmd string `json:"cmd,omitempty"`
+
+	// Skip skips testing when every changed file matches a skip rule.
+	Skip bool `json:"skip,omitempty"`
+}
+
+// MergeHookConfig is one step of a pre- or post-merge pipeline.
+type MergeHookConfig struct {
+	// Name identifies the hook; it must be unique within its pipeline.
+	Name string `json:"name"`
+
+	// Cmd is the shell command to run in the refinery worktree. It sees
+	// GT_RIG, GT_MERGE_BRANCH, GT_MERGE_TARGET, GT_SOURCE_ISSUE and, after
+	// the merge, GT_MERGE_COMMIT.
+	Cmd string `json:"cmd"`
+
+	// Timeout bounds the hook's run time (e.g., "5m"). Empty means none.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Optional hooks warn on failure instead of stopping the pipeline.
+	Optional bool `json:"optional,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -892,6 +1188,12 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// OnDoltConflict policy constants.
+const (
+	OnDoltConflictTheirs   = "theirs"
+	OnDoltConflictEscalate = "escalate"
+)
+
 // IsPolecatIntegrationEnabled returns whether polecat integration branch
 // sourcing is enabled. Nil-safe, defaults to true.
 func (c *MergeQueueConfig) IsPolecatIntegrationEnabled() bool {
@@ -973,6 +1275,17 @@ type NamepoolConfig struct {
 	// MaxBeforeNumbering is when to start appending numbers.
 	// Default is 50. After this many polecats, names become name-01, name-02, etc.
 	MaxBeforeNumbering int `json:"max_before_numbering,omitempty"`
+
+	// Prefix and Suffix wrap every allocated name (e.g., Prefix "fe-" gives
+	// "fe-toast"), so polecats from different rigs are told apart in logs.
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+
+	// ReuseCooldown is how many recently released names are held back from
+	// allocation while other names are free. The list survives restarts, so
+	// a name is not handed to a new polecat while logs still mention the
+	// old one. Zero reuses names immediately.
+	ReuseCooldown int `json:"reuse_cooldown,omitempty"`
 }
 
 // DefaultNamepoolConfig returns a NamepoolConfig with sensible defaults.
@@ -986,9 +1299,39 @@ func DefaultNamepoolConfig() *NamepoolConfig {
 // AccountsConfig represents Claude Code account configuration (mayor/accounts.json).
 // This enables Gas Town to manage multiple Claude Code accounts with easy switching.
 type AccountsConfig struct {
-	Version  int                `json:"version"`  // schema version
-	Accounts map[string]Account `json:"accounts"` // handle -> account details
-	Default  string             `json:"default"`  // default account handle
+	Version  int                `json:"version"`          // schema version
+	Accounts map[string]Account `json:"accounts"`         // handle -> account details
+	Default  string             `json:"default"`          // default account handle
+	Policy   *AccountPolicy     `json:"policy,omitempty"` // dynamic assignment policy
+}
+
+// Account assignment policy modes.
+const (
+	// AccountPolicyDefault assigns the default account to every new session.
+	AccountPolicyDefault = "default"
+
+	// AccountPolicyRoundRobin assigns each new polecat the least-recently-used
+	// available account, spreading load across the pool.
+	AccountPolicyRoundRobin = "round-robin"
+)
+
+// AccountPolicy controls how accounts are assigned to new polecat sessions
+// when neither --account nor GT_ACCOUNT selects one explicitly.
+// A nil policy keeps the static behavior (always the default account).
+type AccountPolicy struct {
+	// Mode is the assignment strategy: "default" or "round-robin".
+	// Default: "default".
+	Mode string `json:"mode,omitempty"`
+
+	// RigAccounts dedicates an account to a rig. Polecats in a listed rig
+	// always use that account (subject to Failover). Takes precedence over Mode.
+	// Example: {"gastown": "work", "beads": "personal"}
+	RigAccounts map[string]string `json:"rig_accounts,omitempty"`
+
+	// Failover skips accounts marked rate-limited in mayor/quota.json when
+	// assigning, and lets the daemon rotate sessions that hit a rate limit
+	// onto available accounts (equivalent to running 'gt quota rotate').
+	Failover bool `json:"failover,omitempty"`
 }
 
 // Account represents a single Claude Code account.
@@ -104,6 +104,12 @@ const (
 
 	// FileQuotaJSON is the quota state file in mayor/.
 	FileQuotaJSON = "quota.json"
+
+	// FileAuditJSONL is the append-only log of mutating gt commands in mayor/.
+	FileAuditJSONL = "audit.jsonl"
+
+	// FilePermissionsJSON is the command permission config in mayor/.
+	FilePermissionsJSON = "permissions.json"
 )
 
 // Beads configuration constants.
@@ -288,6 +294,16 @@ func MayorQuotaPath(townRoot string) string {
 	return townRoot + "/" + DirMayor + "/" + FileQuotaJSON
 }
 
+// MayorAuditLogPath returns the path to mayor/audit.jsonl within a town root.
+func MayorAuditLogPath(townRoot string) string {
+	return townRoot + "/" + DirMayor + "/" + FileAuditJSONL
+}
+
+// MayorPermissionsPath returns the path to mayor/permissions.json within a town root.
+func MayorPermissionsPath(townRoot string) string {
+	return townRoot + "/" + DirMayor + "/" + FilePermissionsJSON
+}
+
 // DefaultRateLimitPatterns are the default patterns that indicate a session
 // is rate-limited. These are matched against tmux pane content.
 // Note: patterns are compiled with (?i) for case-insensitive matching.
@@ -0,0 +1,100 @@
+package controlapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Client calls methods on a control API server. Calls on one client are
+// serialized; it is safe for concurrent use.
+type Client struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	nextID int
+}
+
+// Dial connects to the control socket at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to control socket (is the daemon running?): %w", err)
+	}
+	return &Client{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Call invokes method with params (any JSON-encodable value, or nil) and
+// decodes the result into result (nil to discard it). A method failure is
+// returned as *Error.
+func (c *Client) Call(ctx context.Context, method string, params, result any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	req := Request{JSONRPC: Version, ID: json.RawMessage(strconv.Itoa(c.nextID)), Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("encoding params: %w", err)
+		}
+		req.Params = data
+	}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetDeadline(deadline)
+		defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+	}
+	if _, err := c.conn.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("sending %s: %w", method, err)
+	}
+	respLine, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("reading %s response: %w", method, err)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(respLine, &resp); err != nil {
+		return fmt.Errorf("parsing %s response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("decoding %s result: %w", method, err)
+	}
+	return nil
+}
+
+// Caller dials the socket at Path for every call, so a long-lived user
+// such as gt web keeps working across daemon restarts.
+type Caller struct {
+	Path string
+}
+
+// Call is Client.Call on a fresh connection.
+func (c Caller) Call(ctx context.Context, method string, params, result any) error {
+	client, err := Dial(c.Path)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Call(ctx, method, params, result)
+}
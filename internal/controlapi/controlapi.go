@@ -0,0 +1,283 @@
+// Package controlapi is the daemon's local control API: JSON-RPC 2.0 over a
+// unix socket, one request or response per line. IDE extensions, web UIs,
+// and scripts use it to inspect and drive a town without shelling out to gt
+// for every status query.
+package controlapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Version is the JSON-RPC protocol version spoken on the socket.
+const Version = "2.0"
+
+// JSON-RPC 2.0 error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeServerError    = -32000
+)
+
+// maxRequestSize bounds one request line.
+const maxRequestSize = 1 << 20
+
+// SocketPath returns the default control socket for a town.
+func SocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "control.sock")
+}
+
+// Request is a JSON-RPC request. ID is absent for notifications, which get
+// no response.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response. Exactly one of Result and Error is set.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// Method handles one call. params is the raw "params" member (nil when
+// absent). A returned *Error is sent as is; any other error becomes a
+// CodeServerError.
+type Method func(ctx context.Context, params json.RawMessage) (any, error)
+
+// Server dispatches requests read from its listeners to registered methods.
+type Server struct {
+	mu      sync.Mutex
+	methods map[string]Method
+	ln      net.Listener
+	conns   map[net.Conn]struct{}
+	closed  bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewServer creates a server with no methods but "rpc.methods", which lists
+// the registered method names.
+func NewServer() *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		methods: map[string]Method{},
+		conns:   map[net.Conn]struct{}{},
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	s.Handle("rpc.methods", func(context.Context, json.RawMessage) (any, error) {
+		return s.Methods(), nil
+	})
+	return s
+}
+
+// Handle registers m under name, replacing any method already there.
+func (s *Server) Handle(name string, m Method) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[name] = m
+}
+
+// Methods returns the registered method names, sorted.
+func (s *Server) Methods() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Listen opens the unix socket at path, owner-only. A socket left behind by
+// a server that is no longer running is replaced; one that still accepts
+// connections is an error.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating socket dir: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("control socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale control socket: %w", err)
+		}
+	}
+	ln, err := listenUnix(path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("restricting control socket: %w", err)
+	}
+	return ln, nil
+}
+
+// Serve accepts connections on ln until Close is called. Each connection's
+// requests are handled in order; connections are handled concurrently.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = ln.Close()
+		return net.ErrClosed
+	}
+	s.ln = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting connections, closes open ones, and waits for
+// in-flight calls to return. Their contexts are canceled.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.cancel()
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	if errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle runs one request line and returns its response, or nil for a
+// notification.
+func (s *Server) handle(line []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, &Error{Code: CodeParseError, Message: "parse error: " + err.Error()})
+	}
+	if req.JSONRPC != Version || req.Method == "" {
+		return errorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: `invalid request: want "jsonrpc": "2.0" and a method`})
+	}
+
+	s.mu.Lock()
+	m := s.methods[req.Method]
+	s.mu.Unlock()
+
+	var resp *Response
+	if m == nil {
+		resp = errorResponse(req.ID, &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method})
+	} else {
+		resp = s.call(req, m)
+	}
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return resp
+}
+
+func (s *Server) call(req Request, m Method) *Response {
+	result, err := m(s.ctx, req.Params)
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, &Error{Code: CodeServerError, Message: "encoding result: " + err.Error()})
+	}
+	return &Response{JSONRPC: Version, ID: req.ID, Result: data}
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: Version, ID: id, Error: err}
+}
+
+// DecodeParams unmarshals params into v, reporting failure as
+// CodeInvalidParams. Absent params leave v unchanged.
+func DecodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package controlapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/pkg/gastown"
+)
+
+// serve starts s on a socket in a temp dir and returns a connected client.
+func serve(t *testing.T, s *Server) (*Client, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+	path := filepath.Join(t.TempDir(), "control.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	t.Cleanup(func() {
+		_ = s.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c, path
+}
+
+func TestServer_Call(t *testing.T) {
+	s := NewServer()
+	s.Handle("echo", func(_ context.Context, params json.RawMessage) (any, error) {
+		var p map[string]string
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+	s.Handle("fail", func(context.Context, json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+	c, path := serve(t, s)
+	ctx := context.Background()
+
+	var got map[string]string
+	if err := c.Call(ctx, "echo", map[string]string{"a": "b"}, &got); err != nil {
+		t.Fatalf("echo: %v", err)
+	}
+	if got["a"] != "b" {
+		t.Errorf("echo = %v", got)
+	}
+	got = nil
+	if err := (Caller{Path: path}).Call(ctx, "echo", map[string]string{"a": "c"}, &got); err != nil {
+		t.Fatalf("Caller echo: %v", err)
+	}
+	if got["a"] != "c" {
+		t.Errorf("Caller echo = %v", got)
+	}
+
+	var methods []string
+	if err := c.Call(ctx, "rpc.methods", nil, &methods); err != nil {
+		t.Fatalf("rpc.methods: %v", err)
+	}
+	if want := []string{"echo", "fail", "rpc.methods"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("rpc.methods = %v, want %v", methods, want)
+	}
+
+	tests := []struct {
+		method string
+		params any
+		code   int
+	}{
+		{"nope", nil, CodeMethodNotFound},
+		{"echo", []int{1}, CodeInvalidParams},
+		{"fail", nil, CodeServerError},
+	}
+	for _, tt := range tests {
+		err := c.Call(ctx, tt.method, tt.params, nil)
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+			t.Errorf("%s: err = %v, want code %d", tt.method, err, tt.code)
+		}
+	}
+}
+
+func TestServer_RawProtocol(t *testing.T) {
+	s := NewServer()
+	called := make(chan struct{}, 2)
+	s.Handle("ping", func(context.Context, json.RawMessage) (any, error) {
+		called <- struct{}{}
+		return "pong", nil
+	})
+	_, path := serve(t, s)
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// A notification runs but gets no response, so the next line read
+	// answers the parse error that follows it.
+	lines := `{"jsonrpc":"2.0","method":"ping"}` + "\n" + `not json` + "\n" + `{"jsonrpc":"1.0","id":"x","method":"ping"}` + "\n" + `{"jsonrpc":"2.0","id":"y","method":"ping"}` + "\n"
+	if _, err := conn.Write([]byte(lines)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`"code":-32700`,
+		`"id":"x","error":{"code":-32600`,
+		`"id":"y","result":"pong"`,
+	}
+	for _, w := range want {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(line, w) {
+			t.Errorf("response %q, want it to contain %q", line, w)
+		}
+	}
+	if len(called) != 2 {
+		t.Errorf("ping ran %d times, want twice (the notification and y)", len(called))
+	}
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+	path := filepath.Join(t.TempDir(), "control.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(path); err == nil {
+		t.Error("Listen on a live socket succeeded")
+	}
+
+	// Closing a unix listener removes its socket; leave a plain file behind
+	// to stand in for one left by a crashed daemon.
+	_ = ln.Close()
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ln, err = Listen(path)
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	_ = ln.Close()
+}
+
+func newTestTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "demo", "polecats", "nux"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"mayor/town.json": `{"type": "town", "version": 2, "name": "test"}`,
+		"mayor/rigs.json": `{"version": 1, "rigs": {"demo": {"git_url": "https://example.com/demo.git"}}}`,
+	}
+	for rel, content := range files {
+		path := filepath.Join(town, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return town
+}
+
+func TestTownMethods(t *testing.T) {
+	town, err := gastown.Open(newTestTown(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ran [][]string
+	m := &townMethods{town: town, runGT: func(_ context.Context, args ...string) (string, error) {
+		ran = append(ran, args)
+		return "ok", nil
+	}}
+	s := NewServer()
+	m.register(s)
+	c, _ := serve(t, s)
+	ctx := context.Background()
+
+	var rigs []gastown.Rig
+	if err := c.Call(ctx, "rig.list", nil, &rigs); err != nil {
+		t.Fatalf("rig.list: %v", err)
+	}
+	if len(rigs) != 1 || rigs[0].Name != "demo" {
+		t.Errorf("rig.list = %+v", rigs)
+	}
+
+	var res ActionResult
+	if err := c.Call(ctx, "rig.start", RigParams{Rig: "demo"}, &res); err != nil {
+		t.Fatalf("rig.start: %v", err)
+	}
+	if res.Output != "ok" {
+		t.Errorf("rig.start output = %q", res.Output)
+	}
+	if err := c.Call(ctx, "polecat.nudge", NudgeParams{Rig: "demo", Polecat: "nux", Message: "-check mail"}, nil); err != nil {
+		t.Fatalf("polecat.nudge: %v", err)
+	}
+	want := [][]string{{"rig", "start", "demo"}, {"nudge", "demo/nux", "-m", "-check mail"}}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	for method, params := range map[string]any{
+		"rig.stop":      RigParams{Rig: "nope"},
+		"rig.status":    nil,
+		"polecat.nudge": NudgeParams{Rig: "demo", Polecat: "nux"},
+	} {
+		err := c.Call(ctx, method, params, nil)
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+			t.Errorf("%s(%v): err = %v, want invalid params", method, params, err)
+		}
+	}
+	if len(ran) != 2 {
+		t.Errorf("invalid calls ran gt: %v", ran[2:])
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package controlapi
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenUnix listens on the unix socket at path under a umask that leaves
+// the socket owner-only from the moment it is created. The umask is
+// process-wide; Listen runs once, at daemon startup.
+func listenUnix(path string) (net.Listener, error) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
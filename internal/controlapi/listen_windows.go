@@ -0,0 +1,10 @@
+//go:build windows
+
+package controlapi
+
+import "net"
+
+// listenUnix listens on the unix socket at path. Windows has no umask.
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
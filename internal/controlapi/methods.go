@@ -0,0 +1,176 @@
+package controlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/pkg/gastown"
+)
+
+// gtTimeout bounds one gt subprocess run by an action method.
+const gtTimeout = 2 * time.Minute
+
+// escalationLabel marks escalation beads in hq.
+const escalationLabel = "gt:escalation"
+
+// RigParams names the rig a method acts on.
+type RigParams struct {
+	Rig string `json:"rig"`
+}
+
+// NudgeParams are the parameters of polecat.nudge.
+type NudgeParams struct {
+	Rig     string `json:"rig"`
+	Polecat string `json:"polecat"`
+	Message string `json:"message"`
+}
+
+// ActionResult is the result of a method that runs a gt command.
+type ActionResult struct {
+	Output string `json:"output"`
+}
+
+// townMethods implements the town methods. Queries go through pkg/gastown;
+// actions run gt so they behave exactly like the CLI.
+type townMethods struct {
+	town  *gastown.Town
+	runGT func(ctx context.Context, args ...string) (string, error)
+}
+
+// RegisterTownMethods registers the town's methods on s:
+//
+//	rig.list                               all rigs
+//	rig.status    {rig}                    a rig's agents and whether each runs
+//	rig.start     {rig}                    gt rig start <rig>
+//	rig.stop      {rig}                    gt rig stop <rig>
+//	polecat.list  {rig}                    a rig's polecats and whether each runs
+//	polecat.nudge {rig, polecat, message}  gt nudge <rig>/<polecat> -m <message>
+//	queue.status  {rig}                    the rig's merge queue
+//	dolt.health                            the Dolt server's health
+//	escalation.list                        open escalations
+//
+// gtPath is the gt binary the action methods run, in townRoot.
+func RegisterTownMethods(s *Server, townRoot, gtPath string) error {
+	town, err := gastown.Open(townRoot)
+	if err != nil {
+		return err
+	}
+	m := &townMethods{town: town, runGT: gtRunner(townRoot, gtPath)}
+	m.register(s)
+	return nil
+}
+
+func (m *townMethods) register(s *Server) {
+	s.Handle("rig.list", func(context.Context, json.RawMessage) (any, error) {
+		return m.town.Rigs()
+	})
+	s.Handle("rig.status", m.withRig(func(_ context.Context, rig string) (any, error) {
+		return m.town.RigStatus(rig)
+	}))
+	s.Handle("rig.start", m.withRig(func(ctx context.Context, rig string) (any, error) {
+		return m.action(ctx, "rig", "start", rig)
+	}))
+	s.Handle("rig.stop", m.withRig(func(ctx context.Context, rig string) (any, error) {
+		return m.action(ctx, "rig", "stop", rig)
+	}))
+	s.Handle("polecat.list", m.withRig(func(_ context.Context, rig string) (any, error) {
+		status, err := m.town.RigStatus(rig)
+		if err != nil {
+			return nil, err
+		}
+		return status.Polecats, nil
+	}))
+	s.Handle("polecat.nudge", m.nudge)
+	s.Handle("queue.status", m.withRig(func(_ context.Context, rig string) (any, error) {
+		return m.town.MergeQueue(rig)
+	}))
+	s.Handle("dolt.health", func(context.Context, json.RawMessage) (any, error) {
+		return m.town.DoltHealth(), nil
+	})
+	s.Handle("escalation.list", func(context.Context, json.RawMessage) (any, error) {
+		return m.town.Issues(gastown.HQ, gastown.IssueQuery{Label: escalationLabel, Status: "open"})
+	})
+}
+
+// withRig adapts a method that needs a rig, checking the rig exists first
+// so a typo isn't passed on to gt.
+func (m *townMethods) withRig(fn func(ctx context.Context, rig string) (any, error)) Method {
+	return func(ctx context.Context, params json.RawMessage) (any, error) {
+		var p RigParams
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if err := m.checkRig(p.Rig); err != nil {
+			return nil, err
+		}
+		return fn(ctx, p.Rig)
+	}
+}
+
+func (m *townMethods) nudge(ctx context.Context, params json.RawMessage) (any, error) {
+	var p NudgeParams
+	if err := DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Polecat == "" || strings.TrimSpace(p.Message) == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "polecat and message are required"}
+	}
+	if err := m.checkRig(p.Rig); err != nil {
+		return nil, err
+	}
+	return m.action(ctx, "nudge", p.Rig+"/"+p.Polecat, "-m", p.Message)
+}
+
+func (m *townMethods) checkRig(rig string) error {
+	if rig == "" {
+		return &Error{Code: CodeInvalidParams, Message: "rig is required"}
+	}
+	if _, err := m.town.Rig(rig); err != nil {
+		if errors.Is(err, gastown.ErrRigNotFound) {
+			return &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *townMethods) action(ctx context.Context, args ...string) (any, error) {
+	out, err := m.runGT(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gt %s: %w", strings.Join(args, " "), err)
+	}
+	return ActionResult{Output: out}, nil
+}
+
+// gtRunner returns a function that runs gt in townRoot and returns its
+// combined output, with the output in the error on failure.
+func gtRunner(townRoot, gtPath string) func(ctx context.Context, args ...string) (string, error) {
+	return func(ctx context.Context, args ...string) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, gtTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, gtPath, args...)
+		cmd.Dir = townRoot
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		err := cmd.Run()
+		output := strings.TrimSpace(out.String())
+		if ctx.Err() == context.DeadlineExceeded {
+			return output, fmt.Errorf("timed out after %v", gtTimeout)
+		}
+		if err != nil {
+			if output != "" {
+				return output, fmt.Errorf("%w: %s", err, output)
+			}
+			return output, err
+		}
+		return output, nil
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -202,7 +203,10 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		return nil, fmt.Errorf("creating crew dir: %w", err)
 	}
 
-	// Clone the rig repo
+	// Clone the rig repo with the rig's submodule and LFS options
+	checkout := rig.CheckoutOptions(m.rig.Path)
+	m.git.SetCheckoutOptions(&checkout)
+	defer m.git.SetCheckoutOptions(nil)
 	if m.rig.LocalRepo != "" {
 		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
 			style.PrintWarning("could not clone with local repo reference: %v", err)
@@ -227,6 +231,9 @@ func (m *Manager) addLocked(name string, createBranch bool) (*CrewWorker, error)
 		}
 		style.PrintWarning("could not sync remotes from rig: %v", err)
 	}
+	if err := rig.ApplyIdentity(m.rig.Path, "crew", name, crewPath, false); err != nil {
+		style.PrintWarning("could not set git identity: %v", err)
+	}
 
 	crewGit := git.NewGit(crewPath)
 	branchName := m.rig.DefaultBranch()
@@ -704,6 +711,13 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// IMPORTANT: All validation and command building happens BEFORE killing
 	// any existing session, so a validation failure cannot leave the user
 	// without a running session.
+	// Secrets are resolved up front too: a missing one fails the start, and
+	// their values reach the agent's environment, never an argv.
+	secrets, err := config.StartupSecrets("crew", townRoot, m.rig.Path, opts.AgentOverride)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
+
 	var claudeCmd string
 	if opts.ResumeSessionID != "" {
 		// Validate session ID to prevent shell injection. The ID is interpolated
@@ -755,11 +769,13 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	}
 
 	t := tmux.NewTmux()
+	mux := m.sessionMux(t)
+	_, usesTmux := mux.(*tmux.Tmux)
 	sessionID := m.SessionName(name)
 
 	// Check if session already exists — kill AFTER command is fully built
 	// so validation failures don't destroy the user's running session.
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -767,17 +783,18 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		if opts.KillExisting {
 			// Restart/resume mode - kill existing session.
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-			if err := t.KillSessionWithProcesses(sessionID); err != nil {
+			if err := mux.KillSessionWithProcesses(sessionID); err != nil {
 				return fmt.Errorf("killing existing session: %w", err)
 			}
 		} else {
-			// Normal start - session exists, check if agent is actually running
-			if t.IsAgentAlive(sessionID) {
+			// Normal start - session exists, check if agent is actually running.
+			// Only tmux keeps a session whose agent has exited.
+			if !usesTmux || t.IsAgentAlive(sessionID) {
 				return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
 			}
 			// Zombie session - kill and recreate.
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-			if err := t.KillSessionWithProcesses(sessionID); err != nil {
+			if err := mux.KillSessionWithProcesses(sessionID); err != nil {
 				return fmt.Errorf("killing zombie session: %w", err)
 			}
 		}
@@ -793,9 +810,12 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// initial shell inherits the correct GT_ROLE (not the parent's).
 	// See: https://github.com/anthropics/gastown/issues/280 (race condition fix)
 	// See: https://github.com/steveyegge/gastown/issues/1289 (env inheritance fix)
-	if err := t.NewSessionWithCommandAndEnv(sessionID, worker.ClonePath, claudeCmd, envVars); err != nil {
+	if err := multiplexer.NewSessionWithSecrets(mux, sessionID, worker.ClonePath, claudeCmd, envVars, secrets); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
+	if !usesTmux {
+		return nil
+	}
 
 	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
@@ -821,11 +841,11 @@ func (m *Manager) Stop(name string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	mux := m.sessionMux(tmux.NewTmux())
 	sessionID := m.SessionName(name)
 
 	// Check if session exists
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -836,7 +856,7 @@ func (m *Manager) Stop(name string) error {
 	// Kill the session.
 	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 	// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-	if err := t.KillSessionWithProcesses(sessionID); err != nil {
+	if err := mux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -845,7 +865,11 @@ func (m *Manager) Stop(name string) error {
 
 // IsRunning checks if a crew member's session is active.
 func (m *Manager) IsRunning(name string) (bool, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName(name)
-	return t.HasSession(sessionID)
+	return m.sessionMux(tmux.NewTmux()).HasSession(sessionID)
+}
+
+// sessionMux returns the town's session backend, t when it is tmux.
+func (m *Manager) sessionMux(t *tmux.Tmux) multiplexer.Multiplexer {
+	return multiplexer.ForTownWith(filepath.Dir(m.rig.Path), t)
 }
@@ -0,0 +1,141 @@
+package daemon
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// defaultAbandonedBeadsInterval is how often in_progress beads are checked
+// for abandonment. The idle threshold is a day, so hourly is plenty.
+const defaultAbandonedBeadsInterval = time.Hour
+
+// abandonedBeadsInterval returns the configured check interval for abandoned_beads.
+func abandonedBeadsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AbandonedBeads != nil {
+		if config.Patrols.AbandonedBeads.Interval > 0 {
+			return config.Patrols.AbandonedBeads.Interval
+		}
+	}
+	return defaultAbandonedBeadsInterval
+}
+
+// abandonedBeadsConfig returns the scan settings for abandoned_beads.
+func abandonedBeadsConfig(config *DaemonPatrolConfig) *deacon.AbandonedBeadConfig {
+	cfg := deacon.DefaultAbandonedBeadConfig()
+	if config != nil && config.Patrols != nil && config.Patrols.AbandonedBeads != nil {
+		ab := config.Patrols.AbandonedBeads
+		if ab.MaxIdle > 0 {
+			cfg.MaxIdle = ab.MaxIdle
+		}
+		if ab.Status != "" {
+			cfg.Status = ab.Status
+		}
+		cfg.IncludePersistent = ab.IncludePersistent
+	}
+	return cfg
+}
+
+// AbandonedBeadsPatrol reopens in_progress beads whose assignee has had no
+// session and no updates for the configured idle time, in the town's beads
+// and every rig's, like gt deacon abandoned-beads. Each reopened bead is
+// logged to the town events log.
+// It runs as a background goroutine within the daemon.
+type AbandonedBeadsPatrol struct {
+	townRoot string
+	interval time.Duration
+	cfg      *deacon.AbandonedBeadConfig
+	rigs     func() []string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	// Hooks, replaced in tests.
+	scan func(rigName string, cfg *deacon.AbandonedBeadConfig) (*deacon.AbandonedBeadScanResult, error)
+	emit func(eventType string, payload map[string]interface{})
+}
+
+// NewAbandonedBeadsPatrol creates a patrol that scans every interval.
+// rigs is called on each pass so newly added rigs are picked up.
+func NewAbandonedBeadsPatrol(townRoot string, interval time.Duration, cfg *deacon.AbandonedBeadConfig, rigs func() []string, logger func(format string, args ...interface{})) *AbandonedBeadsPatrol {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AbandonedBeadsPatrol{
+		townRoot: townRoot,
+		interval: interval,
+		cfg:      cfg,
+		rigs:     rigs,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		scan: func(rigName string, cfg *deacon.AbandonedBeadConfig) (*deacon.AbandonedBeadScanResult, error) {
+			return deacon.ScanAbandonedBeads(townRoot, rigName, cfg)
+		},
+		emit: func(eventType string, payload map[string]interface{}) {
+			_ = events.LogAt(townRoot, eventType, "daemon", payload, events.VisibilityFeed)
+		},
+	}
+}
+
+// Start begins the patrol goroutine.
+func (p *AbandonedBeadsPatrol) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Stop gracefully stops the patrol.
+func (p *AbandonedBeadsPatrol) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *AbandonedBeadsPatrol) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.check()
+		}
+	}
+}
+
+// check scans the town's beads and then each rig's. A database that can't
+// be listed is logged and retried on the next pass.
+func (p *AbandonedBeadsPatrol) check() {
+	rigs := p.rigs()
+	sort.Strings(rigs)
+	for _, name := range append([]string{""}, rigs...) {
+		if p.ctx.Err() != nil {
+			return
+		}
+		label := name
+		if label == "" {
+			label = "town"
+		}
+		result, err := p.scan(name, p.cfg)
+		if err != nil {
+			p.logger("abandoned_beads: %s: %v", label, err)
+			continue
+		}
+		for _, r := range result.Results {
+			if !r.Reopened {
+				if r.Error != "" {
+					p.logger("abandoned_beads: %s: reopening %s: %s", label, r.BeadID, r.Error)
+				}
+				continue
+			}
+			p.logger("abandoned_beads: %s: %s moved to %s (%s)", label, r.BeadID, p.cfg.Status, r.Reason)
+			p.emit(events.TypeBeadAbandoned, events.BeadAbandonedPayload(name, r.BeadID, r.Assignee, p.cfg.Status, r.Reason))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestAbandonedBeadsPatrol_Check(t *testing.T) {
+	cfg := &deacon.AbandonedBeadConfig{MaxIdle: time.Hour, Status: "stalled"}
+	var logged int
+	p := NewAbandonedBeadsPatrol(t.TempDir(), time.Hour, cfg,
+		func() []string { return []string{"gastown", "broken"} },
+		func(string, ...interface{}) { logged++ })
+
+	var scanned []string
+	p.scan = func(rigName string, got *deacon.AbandonedBeadConfig) (*deacon.AbandonedBeadScanResult, error) {
+		if got != cfg {
+			t.Errorf("scan config = %+v", got)
+		}
+		scanned = append(scanned, rigName)
+		switch rigName {
+		case "broken":
+			return nil, errors.New("bd list failed")
+		case "gastown":
+			return &deacon.AbandonedBeadScanResult{Results: []*deacon.AbandonedBeadResult{
+				{BeadID: "gt-1", Assignee: "gastown/polecats/Toast", Reopened: true, Reason: "idle"},
+				{BeadID: "gt-2", Error: "update failed"},
+			}}, nil
+		}
+		return &deacon.AbandonedBeadScanResult{}, nil
+	}
+	var emitted []map[string]interface{}
+	p.emit = func(eventType string, payload map[string]interface{}) {
+		if eventType != events.TypeBeadAbandoned {
+			t.Errorf("event type = %q", eventType)
+		}
+		emitted = append(emitted, payload)
+	}
+
+	p.check()
+
+	if len(scanned) != 3 || scanned[0] != "" {
+		t.Errorf("scanned %q, want the town first and then both rigs", scanned)
+	}
+	if len(emitted) != 1 || emitted[0]["bead"] != "gt-1" || emitted[0]["status"] != "stalled" {
+		t.Errorf("emitted %v, want one event for gt-1", emitted)
+	}
+	if logged != 3 {
+		t.Errorf("logged %d lines, want 3 (scan error, reopen, update error)", logged)
+	}
+}
+
+func TestAbandonedBeadsConfig(t *testing.T) {
+	cfg := abandonedBeadsConfig(nil)
+	if cfg.MaxIdle != 24*time.Hour || cfg.Status != "open" {
+		t.Errorf("default config = %+v", cfg)
+	}
+	if IsPatrolEnabled(nil, "abandoned_beads") {
+		t.Error("abandoned_beads enabled by default, want opt-in")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{AbandonedBeads: &AbandonedBeadsConfig{
+		Enabled: true, MaxIdle: 2 * time.Hour, Status: "stalled", IncludePersistent: true,
+	}}}
+	cfg = abandonedBeadsConfig(config)
+	if cfg.MaxIdle != 2*time.Hour || cfg.Status != "stalled" || !cfg.IncludePersistent {
+		t.Errorf("configured = %+v", cfg)
+	}
+	if !IsPatrolEnabled(config, "abandoned_beads") {
+		t.Error("abandoned_beads not enabled")
+	}
+	if got := abandonedBeadsInterval(config); got != defaultAbandonedBeadsInterval {
+		t.Errorf("interval = %v", got)
+	}
+}
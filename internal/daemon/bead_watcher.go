@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// defaultBeadChangesInterval is how often the Dolt server is polled for new
+// commits. Each poll reads one dolt_log row per database, so this is cheap.
+const defaultBeadChangesInterval = 15 * time.Second
+
+// beadChangesInterval returns the configured poll interval for bead_changes.
+func beadChangesInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.BeadChanges != nil {
+		if config.Patrols.BeadChanges.Interval > 0 {
+			return config.Patrols.BeadChanges.Interval
+		}
+	}
+	return defaultBeadChangesInterval
+}
+
+// BeadChangeWatcher publishes bead changes from Dolt commits to the town
+// events log so witnesses and dashboards can react without polling bd.
+// It runs as a background goroutine within the daemon.
+type BeadChangeWatcher struct {
+	townRoot string
+	interval time.Duration
+	watcher  *doltserver.ChangeWatcher
+	logger   func(format string, args ...interface{})
+	emit     func(change doltserver.BeadChange) error
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewBeadChangeWatcher creates a watcher that polls every interval.
+func NewBeadChangeWatcher(townRoot string, interval time.Duration, logger func(format string, args ...interface{})) *BeadChangeWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &BeadChangeWatcher{
+		townRoot: townRoot,
+		interval: interval,
+		watcher:  doltserver.NewChangeWatcher(townRoot),
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	w.emit = w.logEvent
+	return w
+}
+
+// Start begins the watcher goroutine. The first poll records each
+// database's current HEAD; events start with the next commit.
+func (w *BeadChangeWatcher) Start() {
+	w.wg.Add(1)
+	go w.run()
+}
+
+// Stop gracefully stops the watcher.
+func (w *BeadChangeWatcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *BeadChangeWatcher) run() {
+	defer w.wg.Done()
+
+	w.poll()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll runs one watcher cycle and emits its changes.
+func (w *BeadChangeWatcher) poll() {
+	ctx, cancel := context.WithTimeout(w.ctx, w.interval)
+	defer cancel()
+
+	changes, err := w.watcher.Poll(ctx)
+	if err != nil && w.ctx.Err() == nil {
+		w.logger("bead_changes: %v", err)
+	}
+	for _, c := range changes {
+		if err := w.emit(c); err != nil {
+			w.logger("bead_changes: emitting %s %s: %v", c.Kind, c.ID, err)
+		}
+	}
+}
+
+// logEvent writes a change to the town events log. Status changes are
+// feed-visible; creations and assignments are audit-only to keep the feed
+// readable.
+func (w *BeadChangeWatcher) logEvent(c doltserver.BeadChange) error {
+	actor := c.Committer
+	if actor == "" {
+		actor = "daemon"
+	}
+	switch c.Kind {
+	case doltserver.ChangeCreated:
+		return events.LogAt(w.townRoot, events.TypeBeadCreated, actor,
+			events.BeadChangePayload(c.Database, c.ID, c.Title, "", c.NewStatus, c.Commit), events.VisibilityAudit)
+	case doltserver.ChangeStatusChanged:
+		return events.LogAt(w.townRoot, events.TypeBeadStatusChanged, actor,
+			events.BeadChangePayload(c.Database, c.ID, c.Title, c.OldStatus, c.NewStatus, c.Commit), events.VisibilityBoth)
+	case doltserver.ChangeAssigned:
+		return events.LogAt(w.townRoot, events.TypeBeadAssigned, actor,
+			events.BeadChangePayload(c.Database, c.ID, c.Title, c.OldAssignee, c.NewAssignee, c.Commit), events.VisibilityAudit)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestBeadChangeWatcher_LogEvent(t *testing.T) {
+	townRoot := t.TempDir()
+	w := NewBeadChangeWatcher(townRoot, defaultBeadChangesInterval, func(string, ...interface{}) {})
+
+	changes := []doltserver.BeadChange{
+		{Database: "gastown", Kind: doltserver.ChangeStatusChanged, ID: "gt-1", OldStatus: "open", NewStatus: "closed", Commit: "abc", Committer: "gastown/polecats/Toast"},
+		{Database: "gastown", Kind: doltserver.ChangeAssigned, ID: "gt-2", NewAssignee: "gastown/polecats/Nux", Commit: "abc"},
+	}
+	for _, c := range changes {
+		if err := w.logEvent(c); err != nil {
+			t.Fatalf("logEvent: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []events.Event
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var e events.Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			t.Fatalf("bad event line %q: %v", line, err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	if got[0].Type != events.TypeBeadStatusChanged || got[0].Actor != "gastown/polecats/Toast" || got[0].Payload["new"] != "closed" {
+		t.Errorf("status event = %+v", got[0])
+	}
+	if got[0].Visibility != events.VisibilityBoth {
+		t.Errorf("status event visibility = %q, want both", got[0].Visibility)
+	}
+	if got[1].Type != events.TypeBeadAssigned || got[1].Actor != "daemon" || got[1].Payload["new"] != "gastown/polecats/Nux" {
+		t.Errorf("assign event = %+v", got[1])
+	}
+}
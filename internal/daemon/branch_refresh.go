@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// defaultBranchRefreshInterval is how often each rig's remote is asked for
+// its default branch. Renames are rare; daily is plenty.
+const defaultBranchRefreshInterval = 24 * time.Hour
+
+// branchRefreshInterval returns the configured check interval for branch_refresh.
+func branchRefreshInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.BranchRefresh != nil {
+		if config.Patrols.BranchRefresh.Interval > 0 {
+			return config.Patrols.BranchRefresh.Interval
+		}
+	}
+	return defaultBranchRefreshInterval
+}
+
+// BranchRefreshPatrol re-detects every rig's default branch from its remote
+// and updates the rig config when upstream renamed it, like
+// gt rig refresh-branch. Changes are logged to the town events log along
+// with the clones still on the old branch.
+// It runs as a background goroutine within the daemon.
+type BranchRefreshPatrol struct {
+	townRoot string
+	interval time.Duration
+	rigs     func() []string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	// Hooks, replaced in tests.
+	refresh func(rigPath string) (*rig.BranchRefresh, error)
+	emit    func(eventType string, payload map[string]interface{})
+}
+
+// NewBranchRefreshPatrol creates a patrol that checks every interval.
+// rigs is called on each pass so newly added rigs are picked up.
+func NewBranchRefreshPatrol(townRoot string, interval time.Duration, rigs func() []string, logger func(format string, args ...interface{})) *BranchRefreshPatrol {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BranchRefreshPatrol{
+		townRoot: townRoot,
+		interval: interval,
+		rigs:     rigs,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		refresh: func(rigPath string) (*rig.BranchRefresh, error) {
+			return rig.RefreshDefaultBranch(rigPath, false)
+		},
+		emit: func(eventType string, payload map[string]interface{}) {
+			_ = events.LogAt(townRoot, eventType, "daemon", payload, events.VisibilityFeed)
+		},
+	}
+}
+
+// Start begins the patrol goroutine.
+func (p *BranchRefreshPatrol) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Stop gracefully stops the patrol.
+func (p *BranchRefreshPatrol) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *BranchRefreshPatrol) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.check()
+		}
+	}
+}
+
+// check refreshes every rig once. A rig whose remote can't be reached is
+// logged and retried on the next pass.
+func (p *BranchRefreshPatrol) check() {
+	rigs := p.rigs()
+	sort.Strings(rigs)
+	for _, name := range rigs {
+		if p.ctx.Err() != nil {
+			return
+		}
+		result, err := p.refresh(filepath.Join(p.townRoot, name))
+		if err != nil {
+			p.logger("branch_refresh: %s: %v", name, err)
+			continue
+		}
+		if !result.Changed {
+			continue
+		}
+
+		var stale []string
+		for _, c := range result.NeedUpdate() {
+			stale = append(stale, c.Path)
+		}
+		p.logger("branch_refresh: %s: default branch %s -> %s; clones still on %s: %v (see gt rig refresh-branch %s)",
+			name, result.Previous, result.Current, result.Previous, stale, name)
+		p.emit(events.TypeRigBranchChanged, events.RigBranchChangedPayload(name, result.Previous, result.Current, stale))
+	}
+}
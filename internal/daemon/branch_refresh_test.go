@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestBranchRefreshPatrol_Check(t *testing.T) {
+	townRoot := t.TempDir()
+	var logged int
+	p := NewBranchRefreshPatrol(townRoot, time.Hour, func() []string { return []string{"unreachable", "renamed", "steady"} },
+		func(string, ...interface{}) { logged++ })
+
+	var refreshed []string
+	p.refresh = func(rigPath string) (*rig.BranchRefresh, error) {
+		name := filepath.Base(rigPath)
+		refreshed = append(refreshed, name)
+		switch name {
+		case "unreachable":
+			return nil, errors.New("could not read from remote")
+		case "renamed":
+			return &rig.BranchRefresh{Rig: name, Previous: "master", Current: "main", Changed: true,
+				Clones: []rig.CloneBranch{{Path: "mayor/rig", Branch: "master", NeedsUpdate: true}, {Path: "crew/joe", Branch: "feature"}}}, nil
+		}
+		return &rig.BranchRefresh{Rig: name, Previous: "main", Current: "main"}, nil
+	}
+	var emitted []map[string]interface{}
+	p.emit = func(eventType string, payload map[string]interface{}) {
+		if eventType != events.TypeRigBranchChanged {
+			t.Errorf("event type = %q", eventType)
+		}
+		emitted = append(emitted, payload)
+	}
+
+	p.check()
+
+	if len(refreshed) != 3 {
+		t.Errorf("refreshed %v, want all three rigs (errors don't stop the pass)", refreshed)
+	}
+	if len(emitted) != 1 || emitted[0]["rig"] != "renamed" {
+		t.Fatalf("emitted = %v, want one event for renamed", emitted)
+	}
+	if stale, _ := emitted[0]["stale_clones"].([]string); len(stale) != 1 || stale[0] != "mayor/rig" {
+		t.Errorf("stale_clones = %v, want [mayor/rig]", emitted[0]["stale_clones"])
+	}
+	if logged != 2 {
+		t.Errorf("logged %d lines, want 2 (one error, one change)", logged)
+	}
+}
+
+func TestBranchRefreshInterval(t *testing.T) {
+	if got := branchRefreshInterval(nil); got != defaultBranchRefreshInterval {
+		t.Errorf("default interval = %v", got)
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{BranchRefresh: &BranchRefreshConfig{Enabled: true, Interval: time.Hour}}}
+	if got := branchRefreshInterval(cfg); got != time.Hour {
+		t.Errorf("configured interval = %v, want 1h", got)
+	}
+}
@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"os"
+
+	"github.com/steveyegge/gastown/internal/controlapi"
+)
+
+// ControlAPISocket returns the control socket path configured in
+// mayor/daemon.json, or the town default.
+func ControlAPISocket(townRoot string, config *DaemonPatrolConfig) string {
+	if config != nil && config.Patrols != nil && config.Patrols.ControlAPI != nil {
+		if config.Patrols.ControlAPI.Socket != "" {
+			return config.Patrols.ControlAPI.Socket
+		}
+	}
+	return controlapi.SocketPath(townRoot)
+}
+
+// ControlAPI serves the town's control methods on a unix socket for the
+// life of the daemon.
+type ControlAPI struct {
+	server *controlapi.Server
+	socket string
+	logger func(format string, args ...interface{})
+}
+
+// StartControlAPI listens on socket and serves the town's methods in the
+// background. gtPath is the gt binary the action methods run.
+func StartControlAPI(townRoot, socket, gtPath string, logger func(format string, args ...interface{})) (*ControlAPI, error) {
+	server := controlapi.NewServer()
+	if err := controlapi.RegisterTownMethods(server, townRoot, gtPath); err != nil {
+		return nil, err
+	}
+	ln, err := controlapi.Listen(socket)
+	if err != nil {
+		return nil, err
+	}
+	a := &ControlAPI{server: server, socket: socket, logger: logger}
+	go func() {
+		if err := server.Serve(ln); err != nil {
+			a.logger("control_api: %v", err)
+		}
+	}()
+	return a, nil
+}
+
+// Stop closes the socket and waits for in-flight calls.
+func (a *ControlAPI) Stop() {
+	if err := a.server.Close(); err != nil {
+		a.logger("control_api: closing: %v", err)
+	}
+	_ = os.Remove(a.socket)
+}
@@ -0,0 +1,329 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// customPatrolTick is how often the runner looks for custom patrols that are
+// due. Patrols are rediscovered on every tick, so installing, disabling, or
+// reconfiguring one takes effect without restarting the daemon.
+const customPatrolTick = 30 * time.Second
+
+// maxPatrolOutput caps the stdout recorded in the events log per run.
+const maxPatrolOutput = 4096
+
+// CustomPatrol is an exec-based patrol registered by a plugin's [patrol]
+// section or defined directly in mayor/daemon.json.
+type CustomPatrol struct {
+	Name     string
+	Source   string // "plugin:<name>" or "daemon.json"
+	Exec     string
+	Dir      string
+	Env      []string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// discoverCustomPatrols returns the enabled custom patrols, sorted by name.
+// Plugin patrols come from the town and the given rigs; a daemon.json entry
+// with the same name can disable one or override its interval and timeout,
+// and an entry with an exec command defines a patrol of its own. Invalid
+// entries are returned as errors and skipped.
+func discoverCustomPatrols(townRoot string, config *DaemonPatrolConfig, rigs []string) ([]CustomPatrol, []error) {
+	var custom map[string]*PatrolConfig
+	if config != nil && config.Patrols != nil {
+		custom = config.Patrols.Custom
+	}
+
+	var errs []error
+	patrols := make(map[string]CustomPatrol)
+
+	plugins, err := plugin.NewScanner(townRoot, rigs).DiscoverAll()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("discovering plugins: %w", err))
+	}
+	for _, p := range plugins {
+		if p.Patrol == nil || !p.Enabled {
+			continue
+		}
+		interval, _ := p.Patrol.IntervalDuration() // validated when the plugin was loaded
+		timeout, _ := p.Patrol.TimeoutDuration()
+		cp := CustomPatrol{
+			Name:     p.PatrolName(),
+			Source:   "plugin:" + p.Name,
+			Exec:     p.Patrol.Exec,
+			Dir:      townRoot,
+			Env:      []string{"GT_PLUGIN_DIR=" + p.Path},
+			Interval: interval,
+			Timeout:  timeout,
+		}
+		if p.RigName != "" {
+			cp.Dir = filepath.Join(townRoot, p.RigName)
+			cp.Env = append(cp.Env, "GT_RIG="+p.RigName)
+		}
+		patrols[cp.Name] = cp
+	}
+
+	for name, pc := range custom {
+		cp, fromPlugin := patrols[name]
+		if !pc.Enabled {
+			delete(patrols, name)
+			continue
+		}
+		if !fromPlugin {
+			if pc.Exec == "" {
+				continue // not a custom patrol, e.g. settings for something else
+			}
+			cp = CustomPatrol{Name: name, Source: "daemon.json", Exec: pc.Exec, Dir: townRoot}
+		}
+		if pc.Interval != "" {
+			d, err := time.ParseDuration(pc.Interval)
+			if err != nil || d <= 0 {
+				errs = append(errs, fmt.Errorf("patrol %s: invalid interval %q", name, pc.Interval))
+				delete(patrols, name)
+				continue
+			}
+			cp.Interval = d
+		}
+		if pc.Timeout != "" {
+			d, err := time.ParseDuration(pc.Timeout)
+			if err != nil || d <= 0 {
+				errs = append(errs, fmt.Errorf("patrol %s: invalid timeout %q", name, pc.Timeout))
+				delete(patrols, name)
+				continue
+			}
+			cp.Timeout = d
+		}
+		if cp.Interval <= 0 {
+			errs = append(errs, fmt.Errorf("patrol %s: interval is required", name))
+			continue
+		}
+		patrols[name] = cp
+	}
+
+	result := make([]CustomPatrol, 0, len(patrols))
+	for _, cp := range patrols {
+		if cp.Timeout <= 0 {
+			cp.Timeout = cp.Interval
+		}
+		cp.Env = append([]string{"GT_TOWN_ROOT=" + townRoot, "GT_PATROL=" + cp.Name}, cp.Env...)
+		result = append(result, cp)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, errs
+}
+
+// CustomPatrolRunner schedules custom patrols alongside the built-in ones.
+// Each patrol runs on its own interval; a run still in progress when the
+// patrol comes due again is not started twice. Stdout goes to the events log
+// and a patrol that starts failing is escalated once until it recovers.
+// It runs as a background goroutine within the daemon.
+type CustomPatrolRunner struct {
+	townRoot string
+	discover func() ([]CustomPatrol, []error)
+	logger   func(format string, args ...interface{})
+	escalate func(p CustomPatrol, errMsg, output string)
+	emit     func(eventType string, payload map[string]interface{}, visibility string) error
+
+	mu      sync.Mutex
+	lastRun map[string]time.Time
+	running map[string]bool
+	failing map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCustomPatrolRunner creates a runner for the town's custom patrols.
+// mayor/daemon.json is reread and rigs is called on every tick to find the
+// rigs whose plugins to consider.
+func NewCustomPatrolRunner(townRoot string, rigs func() []string, gtPath string, logger func(format string, args ...interface{})) *CustomPatrolRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &CustomPatrolRunner{
+		townRoot: townRoot,
+		discover: func() ([]CustomPatrol, []error) {
+			return discoverCustomPatrols(townRoot, LoadPatrolConfig(townRoot), rigs())
+		},
+		logger:  logger,
+		lastRun: make(map[string]time.Time),
+		running: make(map[string]bool),
+		failing: make(map[string]bool),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	r.escalate = func(p CustomPatrol, errMsg, output string) {
+		escalateCustomPatrol(townRoot, gtPath, p, errMsg, output, logger)
+	}
+	r.emit = func(eventType string, payload map[string]interface{}, visibility string) error {
+		return events.LogAt(townRoot, eventType, "daemon", payload, visibility)
+	}
+	return r
+}
+
+// Start begins the runner goroutine. Every patrol runs once at startup and
+// then on its interval.
+func (r *CustomPatrolRunner) Start() {
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Stop cancels in-flight patrol runs and waits for them to exit.
+func (r *CustomPatrolRunner) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *CustomPatrolRunner) run() {
+	defer r.wg.Done()
+
+	r.tick(time.Now())
+	ticker := time.NewTicker(customPatrolTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.tick(now)
+		}
+	}
+}
+
+// tick starts every patrol that is due and not already running.
+func (r *CustomPatrolRunner) tick(now time.Time) {
+	patrols, errs := r.discover()
+	for _, err := range errs {
+		r.logger("custom patrols: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range patrols {
+		if r.running[p.Name] {
+			continue
+		}
+		if last, ok := r.lastRun[p.Name]; ok && now.Sub(last) < p.Interval {
+			continue
+		}
+		r.lastRun[p.Name] = now
+		r.running[p.Name] = true
+		r.wg.Add(1)
+		go func(p CustomPatrol) {
+			defer r.wg.Done()
+			r.runPatrol(p)
+			r.mu.Lock()
+			delete(r.running, p.Name)
+			r.mu.Unlock()
+		}(p)
+	}
+}
+
+// runPatrol executes one patrol run and records its outcome.
+func (r *CustomPatrolRunner) runPatrol(p CustomPatrol) {
+	output, err := execCustomPatrol(r.ctx, p)
+	if r.ctx.Err() != nil {
+		return // daemon shutting down; not the patrol's fault
+	}
+
+	if err == nil {
+		if emitErr := r.emit(events.TypeCustomPatrolRan, events.CustomPatrolPayload(p.Name, p.Source, output, ""), events.VisibilityAudit); emitErr != nil {
+			r.logger("custom patrol %s: logging event: %v", p.Name, emitErr)
+		}
+		r.mu.Lock()
+		recovered := r.failing[p.Name]
+		delete(r.failing, p.Name)
+		r.mu.Unlock()
+		if recovered {
+			r.logger("Custom patrol %s recovered", p.Name)
+		}
+		return
+	}
+
+	errMsg := err.Error()
+	r.logger("Custom patrol %s (%s) failed: %s", p.Name, p.Source, errMsg)
+	if emitErr := r.emit(events.TypeCustomPatrolFailed, events.CustomPatrolPayload(p.Name, p.Source, output, errMsg), events.VisibilityBoth); emitErr != nil {
+		r.logger("custom patrol %s: logging event: %v", p.Name, emitErr)
+	}
+
+	r.mu.Lock()
+	alreadyFailing := r.failing[p.Name]
+	r.failing[p.Name] = true
+	r.mu.Unlock()
+	if !alreadyFailing {
+		r.escalate(p, errMsg, output)
+	}
+}
+
+// execCustomPatrol runs p's command and returns its (truncated) stdout.
+func execCustomPatrol(ctx context.Context, p CustomPatrol) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Exec) //nolint:gosec // G204: patrol commands come from operator-installed plugins and config
+	cmd.Dir = p.Dir
+	cmd.Env = append(os.Environ(), p.Env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on grandchildren still holding the pipes after a timeout kill.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	output := truncatePatrolOutput(stdout.String())
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return output, fmt.Errorf("timed out after %v", p.Timeout)
+		}
+		if msg := truncatePatrolOutput(stderr.String()); msg != "" {
+			return output, fmt.Errorf("%w: %s", err, msg)
+		}
+		return output, err
+	}
+	return output, nil
+}
+
+func truncatePatrolOutput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxPatrolOutput {
+		s = s[:maxPatrolOutput] + "..."
+	}
+	return s
+}
+
+// escalateCustomPatrol files an escalation for a failing patrol via
+// gt escalate. Runs asynchronously so a slow escalation never delays patrols.
+func escalateCustomPatrol(townRoot, gtPath string, p CustomPatrol, errMsg, output string, logger func(format string, args ...interface{})) {
+	description := fmt.Sprintf("Custom patrol %s failing", p.Name)
+	reason := fmt.Sprintf("Patrol %s (%s) failed: %s", p.Name, p.Source, errMsg)
+	if output != "" {
+		reason += "\n\nOutput:\n" + output
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, gtPath, "escalate", description, //nolint:gosec // G204: args are constructed internally
+			"--severity", "high", "--source", "patrol:"+p.Name, "--reason", reason)
+		cmd.Dir = townRoot
+		cmd.Env = os.Environ()
+		if err := cmd.Run(); err != nil {
+			logger("Warning: failed to escalate custom patrol %s: %v", p.Name, err)
+		} else {
+			logger("Escalated failing custom patrol %s", p.Name)
+		}
+	}()
+}
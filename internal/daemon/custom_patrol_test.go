@@ -0,0 +1,198 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func writePatrolPlugin(t *testing.T, dir, name, patrol string) {
+	t.Helper()
+	pluginDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "+++\nname = \"" + name + "\"\n[patrol]\n" + patrol + "\n+++\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPatrolConfig_CustomPatrols(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configJSON := `{
+		"type": "daemon-patrol-config",
+		"version": 1,
+		"patrols": {
+			"witness": {"enabled": true},
+			"disk-check": {"enabled": false},
+			"backup": {"enabled": true, "interval": "1h", "exec": "./backup.sh"},
+			"notes": "not a patrol"
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "mayor", "daemon.json"), []byte(configJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := LoadPatrolConfig(tmpDir)
+	if config == nil || config.Patrols == nil || config.Patrols.Witness == nil {
+		t.Fatal("expected config with built-in patrols to load")
+	}
+	if len(config.Patrols.Custom) != 2 {
+		t.Fatalf("custom patrols = %v, want disk-check and backup", config.Patrols.Custom)
+	}
+	if got := config.Patrols.Custom["backup"]; got.Exec != "./backup.sh" || got.Interval != "1h" {
+		t.Errorf("backup = %+v", got)
+	}
+	if IsPatrolEnabled(config, "disk-check") {
+		t.Error("expected disk-check to be disabled")
+	}
+	if !IsPatrolEnabled(config, "backup") {
+		t.Error("expected backup to be enabled")
+	}
+}
+
+func TestDiscoverCustomPatrols(t *testing.T) {
+	townRoot := t.TempDir()
+	writePatrolPlugin(t, filepath.Join(townRoot, "plugins"), "disk-check", "interval = \"10m\"\nexec = \"./check.sh\"")
+	writePatrolPlugin(t, filepath.Join(townRoot, "plugins"), "stale-branches", "interval = \"1h\"\nexec = \"./prune.sh\"")
+	writePatrolPlugin(t, filepath.Join(townRoot, "gastown", "plugins"), "lint-sweep", "name = \"lint\"\ninterval = \"30m\"\nexec = \"make lint\"\ntimeout = \"5m\"")
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Custom: map[string]*PatrolConfig{
+		"stale-branches": {Enabled: false},
+		"disk-check":     {Enabled: true, Interval: "2m"},
+		"backup":         {Enabled: true, Interval: "1h", Exec: "./backup.sh"},
+		"broken":         {Enabled: true, Exec: "true"},
+	}}}
+
+	patrols, errs := discoverCustomPatrols(townRoot, config, []string{"gastown"})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("errs = %v, want one error for broken", errs)
+	}
+
+	var names []string
+	for _, p := range patrols {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "backup,disk-check,lint" {
+		t.Fatalf("patrols = %s, want backup,disk-check,lint", got)
+	}
+
+	backup, disk, lint := patrols[0], patrols[1], patrols[2]
+	if backup.Source != "daemon.json" || backup.Dir != townRoot || backup.Timeout != time.Hour {
+		t.Errorf("backup = %+v", backup)
+	}
+	if disk.Interval != 2*time.Minute || disk.Timeout != 2*time.Minute {
+		t.Errorf("disk-check interval override not applied: %+v", disk)
+	}
+	if lint.Source != "plugin:lint-sweep" || lint.Dir != filepath.Join(townRoot, "gastown") || lint.Timeout != 5*time.Minute {
+		t.Errorf("lint = %+v", lint)
+	}
+	if !containsEnv(lint.Env, "GT_RIG=gastown") || !containsEnv(lint.Env, "GT_PATROL=lint") {
+		t.Errorf("lint env = %v", lint.Env)
+	}
+}
+
+func containsEnv(env []string, kv string) bool {
+	for _, e := range env {
+		if e == kv {
+			return true
+		}
+	}
+	return false
+}
+
+type recordedEvent struct {
+	eventType string
+	payload   map[string]interface{}
+}
+
+func newTestPatrolRunner(t *testing.T, patrols ...CustomPatrol) (*CustomPatrolRunner, *[]recordedEvent, *[]string) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var mu sync.Mutex
+	var evs []recordedEvent
+	var escalated []string
+	r := &CustomPatrolRunner{
+		discover: func() ([]CustomPatrol, []error) { return patrols, nil },
+		logger:   func(string, ...interface{}) {},
+		escalate: func(p CustomPatrol, errMsg, output string) {
+			mu.Lock()
+			escalated = append(escalated, p.Name)
+			mu.Unlock()
+		},
+		emit: func(eventType string, payload map[string]interface{}, visibility string) error {
+			mu.Lock()
+			evs = append(evs, recordedEvent{eventType, payload})
+			mu.Unlock()
+			return nil
+		},
+		lastRun: make(map[string]time.Time),
+		running: make(map[string]bool),
+		failing: make(map[string]bool),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	return r, &evs, &escalated
+}
+
+func TestCustomPatrolRunner_CapturesOutputAndEscalatesOnce(t *testing.T) {
+	dir := t.TempDir()
+	r, evs, escalated := newTestPatrolRunner(t,
+		CustomPatrol{Name: "ok", Source: "daemon.json", Exec: "echo all good", Dir: dir, Interval: time.Minute, Timeout: 5 * time.Second},
+		CustomPatrol{Name: "bad", Source: "daemon.json", Exec: "echo partial; echo boom >&2; exit 3", Dir: dir, Interval: time.Minute, Timeout: 5 * time.Second},
+	)
+
+	start := time.Now()
+	r.tick(start)
+	r.wg.Wait()
+	r.tick(start.Add(30 * time.Second)) // not due yet
+	r.wg.Wait()
+	r.tick(start.Add(2 * time.Minute)) // due again, still failing
+	r.wg.Wait()
+
+	var ran, failed int
+	for _, e := range *evs {
+		switch e.eventType {
+		case events.TypeCustomPatrolRan:
+			ran++
+			if e.payload["output"] != "all good" {
+				t.Errorf("ok output = %v", e.payload["output"])
+			}
+		case events.TypeCustomPatrolFailed:
+			failed++
+			if e.payload["output"] != "partial" || !strings.Contains(e.payload["error"].(string), "boom") {
+				t.Errorf("bad payload = %v", e.payload)
+			}
+		}
+	}
+	if ran != 2 || failed != 2 {
+		t.Errorf("ran=%d failed=%d, want 2 each", ran, failed)
+	}
+	if len(*escalated) != 1 || (*escalated)[0] != "bad" {
+		t.Errorf("escalated = %v, want [bad] once", *escalated)
+	}
+}
+
+func TestCustomPatrolRunner_Timeout(t *testing.T) {
+	r, evs, _ := newTestPatrolRunner(t,
+		CustomPatrol{Name: "slow", Exec: "sleep 10", Dir: t.TempDir(), Interval: time.Minute, Timeout: 100 * time.Millisecond},
+	)
+	r.tick(time.Now())
+	r.wg.Wait()
+
+	if len(*evs) != 1 || !strings.Contains((*evs)[0].payload["error"].(string), "timed out") {
+		t.Errorf("expected timeout failure, got %v", *evs)
+	}
+}
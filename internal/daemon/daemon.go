@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -27,10 +28,12 @@ import (
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
@@ -53,6 +56,16 @@ type Daemon struct {
 	beadsStores   map[string]beadsdk.Storage
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	beadWatcher   *BeadChangeWatcher
+	rigStats      *RigStatsCollector
+	tableStats    *TableStatsCollector
+	readOnly      *ReadOnlyPatrol
+	branchRefresh *BranchRefreshPatrol
+	upstreamSync  *UpstreamSyncPatrol
+	abandoned     *AbandonedBeadsPatrol
+	headless      *HeadlessSupervisor
+	controlAPI    *ControlAPI
+	customPatrols *CustomPatrolRunner
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -64,11 +77,22 @@ type Daemon struct {
 	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
 	deaconLastStarted time.Time
 
+	// patrolsSettled is set once the first heartbeat completes. Agents the
+	// daemon starts before then are being brought up, not recovered, so no
+	// death events are recorded for them.
+	// Only accessed from heartbeat loop goroutine - no sync needed.
+	patrolsSettled bool
+
 	// syncFailures tracks consecutive git pull failures per workdir.
 	// Used to escalate logging from WARN to ERROR after repeated failures.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	syncFailures map[string]int
 
+	// fallbackReported is set once the daemon has logged that rigs on a
+	// fallback snapshot need gt dolt reconcile, and cleared on failover.
+	// Only accessed from the main loop goroutine - no sync needed.
+	fallbackReported bool
+
 	// PATCH-006: Resolved binary paths to avoid PATH issues in subprocesses.
 	gtPath string
 	bdPath string
@@ -167,6 +191,10 @@ func New(config *Config) (*Daemon, error) {
 func (d *Daemon) Run() error {
 	d.logger.Printf("Daemon starting (PID %d)", os.Getpid())
 
+	// Trace the daemon's bd and SQL calls on their own rather than under
+	// a "gt daemon run" span that lasts as long as the daemon.
+	telemetry.Detach()
+
 	// Acquire exclusive lock to prevent multiple daemons from running.
 	// This prevents the TOCTOU race condition where multiple concurrent starts
 	// can all pass the IsRunning() check before any writes the PID file.
@@ -265,6 +293,82 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start bead change watcher (Dolt commits -> bead change events)
+	if d.doltServer != nil && d.doltServer.IsEnabled() && IsPatrolEnabled(d.patrolConfig, "bead_changes") {
+		d.beadWatcher = NewBeadChangeWatcher(d.config.TownRoot, beadChangesInterval(d.patrolConfig), d.logger.Printf)
+		d.beadWatcher.Start()
+		d.logger.Println("Bead change watcher started")
+	}
+
+	// Start rig stats collector (per-rig activity samples for gt rig stats)
+	if d.doltServer != nil && d.doltServer.IsEnabled() && IsPatrolEnabled(d.patrolConfig, "rig_stats") {
+		d.rigStats = NewRigStatsCollector(d.config.TownRoot, rigStatsInterval(d.patrolConfig), d.getKnownRigs, d.logger.Printf)
+		d.rigStats.Start()
+		d.logger.Println("Rig stats collector started")
+	}
+
+	// Start table stats collector (opt-in processlist sampling for gt dolt table-stats)
+	if d.doltServer != nil && d.doltServer.IsEnabled() && IsPatrolEnabled(d.patrolConfig, "table_stats") {
+		sampleInterval, interval := tableStatsIntervals(d.patrolConfig)
+		d.tableStats = NewTableStatsCollector(d.config.TownRoot, sampleInterval, interval, d.getKnownRigs, d.logger.Printf)
+		d.tableStats.Start()
+		d.logger.Println("Table stats collector started")
+	}
+
+	// Start read-only recovery patrol (write-probes every database)
+	if d.doltServer != nil && d.doltServer.IsEnabled() && IsPatrolEnabled(d.patrolConfig, "read_only_recovery") {
+		escalateAfter, severity := readOnlyEscalation(d.patrolConfig)
+		d.readOnly = NewReadOnlyPatrol(d.config.TownRoot, d.gtPath, readOnlyRecoveryInterval(d.patrolConfig),
+			escalateAfter, severity, d.doltServer.RestartReadOnly, d.logger.Printf)
+		d.readOnly.Start()
+		d.logger.Println("Read-only recovery patrol started")
+	}
+
+	// Start default branch refresh patrol (opt-in; follows upstream branch renames)
+	if IsPatrolEnabled(d.patrolConfig, "branch_refresh") {
+		d.branchRefresh = NewBranchRefreshPatrol(d.config.TownRoot, branchRefreshInterval(d.patrolConfig), d.getKnownRigs, d.logger.Printf)
+		d.branchRefresh.Start()
+		d.logger.Println("Branch refresh patrol started")
+	}
+
+	// Start upstream sync patrol (opt-in; keeps fork rigs current with upstream)
+	if IsPatrolEnabled(d.patrolConfig, "upstream_sync") {
+		d.upstreamSync = NewUpstreamSyncPatrol(d.config.TownRoot, upstreamSyncInterval(d.patrolConfig), upstreamSyncStrategy(d.patrolConfig), d.getKnownRigs, d.logger.Printf)
+		d.upstreamSync.Start()
+		d.logger.Println("Upstream sync patrol started")
+	}
+
+	// Start abandoned beads patrol (opt-in; reopens idle work of dead agents)
+	if IsPatrolEnabled(d.patrolConfig, "abandoned_beads") {
+		d.abandoned = NewAbandonedBeadsPatrol(d.config.TownRoot, abandonedBeadsInterval(d.patrolConfig), abandonedBeadsConfig(d.patrolConfig), d.getKnownRigs, d.logger.Printf)
+		d.abandoned.Start()
+		d.logger.Println("Abandoned beads patrol started")
+	}
+
+	// Start custom patrol runner (plugin [patrol] sections and exec patrols
+	// defined in mayor/daemon.json)
+	d.customPatrols = NewCustomPatrolRunner(d.config.TownRoot, d.getKnownRigs, d.gtPath, d.logger.Printf)
+	d.customPatrols.Start()
+	d.logger.Println("Custom patrol runner started")
+
+	// Supervise headless agent sessions when the town runs without a multiplexer
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot)); err == nil && settings.Multiplexer == multiplexer.NameHeadless {
+		d.headless = NewHeadlessSupervisor(d.config.TownRoot, d.logger.Printf)
+		d.headless.Start()
+		d.logger.Println("Headless session supervisor started")
+	}
+
+	// Serve the control API (JSON-RPC on a unix socket for IDEs and web UIs)
+	if IsPatrolEnabled(d.patrolConfig, "control_api") {
+		socket := ControlAPISocket(d.config.TownRoot, d.patrolConfig)
+		if api, err := StartControlAPI(d.config.TownRoot, socket, d.gtPath, d.logger.Printf); err != nil {
+			d.logger.Printf("Warning: failed to start control API: %v", err)
+		} else {
+			d.controlAPI = api
+			d.logger.Printf("Control API listening on %s", socket)
+		}
+	}
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -391,6 +495,10 @@ func (d *Daemon) heartbeat(state *State) {
 		d.checkDeaconHeartbeat()
 	}
 
+	// 3b. Drain rigs that are inside a scheduled maintenance window.
+	// Must run before the ensure* steps so they see the rig as non-operational.
+	d.enforceMaintenanceWindows()
+
 	// 4. Ensure Witnesses are running for all rigs (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
 	if IsPatrolEnabled(d.patrolConfig, "witness") {
@@ -430,6 +538,9 @@ func (d *Daemon) heartbeat(state *State) {
 	// 11. Check for orphaned work (assigned to dead agents)
 	d.checkOrphanedWork()
 
+	// 11b. Rotate rate-limited sessions to available accounts (accounts policy failover)
+	d.checkQuotaFailover()
+
 	// 12. Check polecat session health (proactive crash detection)
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
@@ -452,6 +563,7 @@ func (d *Daemon) heartbeat(state *State) {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	d.patrolsSettled = true
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
@@ -464,6 +576,58 @@ func (d *Daemon) ensureDoltServerRunning() {
 
 	if err := d.doltServer.EnsureRunning(); err != nil {
 		d.logger.Printf("Error ensuring Dolt server is running: %v", err)
+		if d.doltServer.GaveUp() && !d.doltServer.IsExternal() {
+			d.failoverBeads()
+		}
+	} else {
+		d.reportFallbackRigs()
+	}
+
+	// The daemon restarts the server without going through gt dolt start, so
+	// keep dolt-state.json and the PID/lock files in step with reality.
+	report, err := doltserver.ReconcileState(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Error reconciling Dolt state: %v", err)
+		return
+	}
+	if report.Changed() {
+		d.logger.Printf("Reconciled Dolt state: %s", strings.Join(append(report.Changes, report.RemovedFiles...), "; "))
+	}
+}
+
+// failoverBeads points rigs at local snapshots of their databases once the
+// daemon has given up restarting the Dolt server, if the town's dolt_fallback
+// policy allows it.
+func (d *Daemon) failoverBeads() {
+	switched, err := doltserver.Failover(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Dolt fallback: %v", err)
+	}
+	if len(switched) == 0 {
+		return
+	}
+	rigs := make([]string, len(switched))
+	for i, r := range switched {
+		rigs[i] = r.Rig
+	}
+	d.logger.Printf("Dolt server down: beads for %s now on a %s snapshot (run gt dolt reconcile once it is back)",
+		strings.Join(rigs, ", "), switched[0].Mode)
+	d.fallbackReported = false
+}
+
+// reportFallbackRigs logs, once per recovery, that rigs are still on a
+// fallback snapshot or bead mutations still journaled now that the server
+// is healthy.
+func (d *Daemon) reportFallbackRigs() {
+	if d.fallbackReported {
+		return
+	}
+	d.fallbackReported = true
+	if rigs, err := doltserver.FallbackRigs(d.config.TownRoot); err == nil && len(rigs) > 0 {
+		d.logger.Printf("Dolt server is healthy but %d rig(s) are still on a fallback snapshot: run gt dolt reconcile", len(rigs))
+	}
+	if journal, _ := beads.UnappliedJournal(d.config.TownRoot); len(journal) > 0 {
+		d.logger.Printf("Dolt server is healthy but %d bead mutation(s) are still journaled: run gt dolt journal apply", len(journal))
 	}
 }
 
@@ -538,7 +702,7 @@ func (d *Daemon) ensureBootRunning() {
 
 	// Check for degraded mode
 	degraded := os.Getenv("GT_DEGRADED") == "true"
-	if degraded || !d.tmux.IsAvailable() {
+	if degraded || !d.usesTmux(d.sessionMux()) || !d.tmux.IsAvailable() {
 		// In degraded mode, run mechanical triage directly
 		d.logger.Println("Degraded mode: running mechanical Boot triage")
 		d.runDegradedBootTriage(b)
@@ -558,7 +722,8 @@ func (d *Daemon) ensureBootRunning() {
 }
 
 // runDegradedBootTriage performs mechanical Boot logic without AI reasoning.
-// This is for degraded mode when tmux is unavailable.
+// This is for degraded mode when tmux is unavailable, and for towns on
+// another multiplexer, where Boot does not run.
 func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	startTime := time.Now()
 	status := &boot.Status{
@@ -567,7 +732,7 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	}
 
 	// Simple check: is Deacon session alive?
-	hasDeacon, err := d.tmux.HasSession(d.getDeaconSessionName())
+	hasDeacon, err := d.sessionMux().HasSession(d.getDeaconSessionName())
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		status.LastAction = "error"
@@ -708,7 +873,8 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	d.logger.Printf("Deacon heartbeat is stale (%s old), checking session...", age.Round(time.Minute))
 
 	// Check if session exists
-	hasSession, err := d.tmux.HasSession(sessionName)
+	mux := d.sessionMux()
+	hasSession, err := mux.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		return
@@ -728,7 +894,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
-		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
+		if err := multiplexer.Nudge(mux, sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
 			d.logger.Printf("Error nudging stuck Deacon: %v", err)
 		}
 	}
@@ -738,10 +904,11 @@ func (d *Daemon) checkDeaconHeartbeat() {
 // Extracted for reuse by PATCH-005 grace period logic.
 func (d *Daemon) restartStuckDeacon(sessionName string) {
 	// Check if session exists before trying to kill
-	hasSession, _ := d.tmux.HasSession(sessionName)
+	mux := d.sessionMux()
+	hasSession, _ := mux.HasSession(sessionName)
 	if hasSession {
 		d.logger.Printf("Killing stuck Deacon session %s", sessionName)
-		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		if err := mux.KillSessionWithProcesses(sessionName); err != nil {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
 		}
 	}
@@ -780,10 +947,11 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	// Check for hung session before Start (which only detects process-dead zombies).
 	// A hung session has a live process but no tmux activity for an extended period,
 	// indicating Claude is stuck. Kill it so Start() can recreate a fresh one.
+	deathReason := "session dead"
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Witness for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSession(mgr.SessionName())
+		_ = mgr.Stop()
+		deathReason = fmt.Sprintf("hung (no activity for %v)", hungSessionThreshold)
 	}
 
 	if err := mgr.Start(false, "", nil); err != nil {
@@ -796,6 +964,10 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		return
 	}
 
+	if d.patrolsSettled {
+		_ = events.LogAt(d.config.TownRoot, events.TypeSessionDeath, "daemon",
+			events.SessionDeathPayload(mgr.SessionName(), rigName+"/witness", deathReason, "daemon"), events.VisibilityFeed)
+	}
 	d.logger.Printf("Witness session for %s started successfully", rigName)
 }
 
@@ -832,8 +1004,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	// can recreate a fresh one. See: gt-tr3d
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Refinery for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSession(mgr.SessionName())
+		_ = mgr.Stop()
 	}
 
 	if err := mgr.Start(false, ""); err != nil {
@@ -870,11 +1041,12 @@ func (d *Daemon) ensureMayorRunning() {
 // Called when the deacon patrol is disabled to prevent stale deacons from
 // running their own patrol loops and spawning agents. (hq-2mstj)
 func (d *Daemon) killDeaconSessions() {
+	mux := d.sessionMux()
 	for _, name := range []string{session.DeaconSessionName(), session.BootSessionName()} {
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := mux.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := mux.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -884,12 +1056,13 @@ func (d *Daemon) killDeaconSessions() {
 // killWitnessSessions kills leftover witness tmux sessions for all rigs.
 // Called when the witness patrol is disabled. (hq-2mstj)
 func (d *Daemon) killWitnessSessions() {
+	mux := d.sessionMux()
 	for _, rigName := range d.getKnownRigs() {
 		name := session.WitnessSessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := mux.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := mux.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -899,12 +1072,13 @@ func (d *Daemon) killWitnessSessions() {
 // killRefinerySessions kills leftover refinery tmux sessions for all rigs.
 // Called when the refinery patrol is disabled. (hq-2mstj)
 func (d *Daemon) killRefinerySessions() {
+	mux := d.sessionMux()
 	for _, rigName := range d.getKnownRigs() {
 		name := session.RefinerySessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		exists, _ := mux.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := mux.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -987,7 +1161,8 @@ func (d *Daemon) getPatrolRigs(patrol string) []string {
 
 // isRigOperational checks if a rig is in an operational state.
 // Returns true if the rig can have agents auto-started.
-// Returns false (with reason) if the rig is parked, docked, or has auto_restart blocked/disabled.
+// Returns false (with reason) if the rig is parked, docked, in a maintenance window,
+// or has auto_restart blocked/disabled.
 func (d *Daemon) isRigOperational(rigName string) (bool, string) {
 	cfg := wisp.NewConfig(d.config.TownRoot, rigName)
 
@@ -1024,6 +1199,11 @@ func (d *Daemon) isRigOperational(rigName string) (bool, string) {
 		}
 	}
 
+	// Check scheduled maintenance windows (rig settings schedule block)
+	if d.inMaintenanceWindow(rigName, time.Now()) {
+		return false, "rig is in a scheduled maintenance window"
+	}
+
 	// Check auto_restart config
 	// If explicitly blocked (nil), auto-restart is disabled
 	if cfg.IsBlocked("auto_restart") {
@@ -1100,6 +1280,12 @@ func (d *Daemon) processLifecycleRequests() {
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
 
+	// Stop control API first so no new actions start during shutdown
+	if d.controlAPI != nil {
+		d.controlAPI.Stop()
+		d.logger.Println("Control API stopped")
+	}
+
 	// Stop feed curator
 	if d.curator != nil {
 		d.curator.Stop()
@@ -1119,6 +1305,60 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop bead change watcher
+	if d.beadWatcher != nil {
+		d.beadWatcher.Stop()
+		d.logger.Println("Bead change watcher stopped")
+	}
+
+	// Stop rig stats collector
+	if d.rigStats != nil {
+		d.rigStats.Stop()
+		d.logger.Println("Rig stats collector stopped")
+	}
+
+	// Stop table stats collector
+	if d.tableStats != nil {
+		d.tableStats.Stop()
+		d.logger.Println("Table stats collector stopped")
+	}
+
+	// Stop read-only recovery patrol
+	if d.readOnly != nil {
+		d.readOnly.Stop()
+		d.logger.Println("Read-only recovery patrol stopped")
+	}
+
+	// Stop branch refresh patrol
+	if d.branchRefresh != nil {
+		d.branchRefresh.Stop()
+		d.logger.Println("Branch refresh patrol stopped")
+	}
+
+	// Stop upstream sync patrol
+	if d.upstreamSync != nil {
+		d.upstreamSync.Stop()
+		d.logger.Println("Upstream sync patrol stopped")
+	}
+
+	// Stop abandoned beads patrol
+	if d.abandoned != nil {
+		d.abandoned.Stop()
+		d.logger.Println("Abandoned beads patrol stopped")
+	}
+
+	// Stop custom patrol runner (cancels in-flight runs)
+	if d.customPatrols != nil {
+		d.customPatrols.Stop()
+		d.logger.Println("Custom patrol runner stopped")
+	}
+
+	// Stop headless supervisor (sessions keep running)
+	if d.headless != nil {
+		d.headless.Stop()
+		d.logger.Println("Headless session supervisor stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1425,14 +1665,28 @@ func listPolecatWorktrees(polecatsDir string) ([]string, error) {
 	return polecats, nil
 }
 
+// sessionMux returns the town's multiplexer, which is d.tmux unless the
+// town is configured for another backend.
+func (d *Daemon) sessionMux() multiplexer.Multiplexer {
+	return multiplexer.ForTownWith(d.config.TownRoot, d.tmux)
+}
+
+// usesTmux reports whether mux is the daemon's tmux, so tmux-only steps
+// (themes, pane hooks, respawn) apply.
+func (d *Daemon) usesTmux(mux multiplexer.Multiplexer) bool {
+	_, ok := mux.(*tmux.Tmux)
+	return ok
+}
+
 // checkPolecatHealth checks a single polecat's session health.
 // If the polecat has work-on-hook but the tmux session is dead, it's restarted.
 func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Build the expected tmux session name
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
-	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	// Check if the session exists
+	mux := d.sessionMux()
+	sessionAlive, err := mux.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1491,7 +1745,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// TOCTOU guard: re-verify session is still dead before restarting.
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
-	sessionRevived, err := d.tmux.HasSession(sessionName)
+	sessionRevived, err := mux.HasSession(sessionName)
 	if err == nil && sessionRevived {
 		return // Session came back - no restart needed
 	}
@@ -1502,6 +1756,8 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
+	_ = events.LogAt(d.config.TownRoot, events.TypeSessionDeath, "daemon",
+		events.SessionDeathPayload(sessionName, rigName+"/polecats/"+polecatName, "crashed with work on hook", "daemon"), events.VisibilityFeed)
 
 	// Auto-restart the polecat
 	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
@@ -1590,10 +1846,11 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
-	// Create new tmux session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
-		return fmt.Errorf("creating session: %w", err)
+	// Resolve secrets first so a missing one fails the restart before a
+	// session is created.
+	secrets, err := config.StartupSecrets("polecat", d.config.TownRoot, rigPath, "")
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
 	}
 
 	// Set environment variables using centralized AgentEnv
@@ -1603,6 +1860,29 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		AgentName: polecatName,
 		TownRoot:  d.config.TownRoot,
 	})
+	rc := config.ResolveRoleAgentConfig("polecat", d.config.TownRoot, rigPath)
+	processNames := config.ResolveProcessNames(rc.ResolvedAgent, rc.Command)
+
+	if mux := d.sessionMux(); !d.usesTmux(mux) {
+		// Other backends run the agent as the session itself, with the
+		// whole environment given at creation.
+		env := maps.Clone(envVars)
+		if rc.ResolvedAgent != "" {
+			env["GT_AGENT"] = rc.ResolvedAgent
+		}
+		env["GT_PROCESS_NAMES"] = strings.Join(processNames, ",")
+		startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+		if err := multiplexer.NewSessionWithSecrets(mux, sessionName, workDir, startCmd, env, secrets); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+
+	// Create new tmux session
+	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
+	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
 
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
@@ -1613,13 +1893,11 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// (e.g., witness patrol) can detect non-Claude agents.
 	// BuildStartupCommand sets GT_AGENT in process env via exec env, but that
 	// isn't visible to tmux show-environment.
-	rc := config.ResolveRoleAgentConfig("polecat", d.config.TownRoot, rigPath)
 	if rc.ResolvedAgent != "" {
 		_ = d.tmux.SetEnvironment(sessionName, "GT_AGENT", rc.ResolvedAgent)
 	}
 
 	// Set GT_PROCESS_NAMES for accurate liveness detection of custom agents.
-	processNames := config.ResolveProcessNames(rc.ResolvedAgent, rc.Command)
 	_ = d.tmux.SetEnvironment(sessionName, "GT_PROCESS_NAMES", strings.Join(processNames, ","))
 
 	// Apply theme
@@ -1632,9 +1910,18 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 
 	// Launch Claude with environment exported inline
 	// Pass rigPath so rig agent settings are honored (not town-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
-	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
-		return fmt.Errorf("sending startup command: %w", err)
+	// Secrets stay out of every argv: the pane loads them from a private
+	// env file before the agent starts.
+	paneID, err := d.tmux.GetPaneID(sessionName)
+	if err != nil {
+		return fmt.Errorf("getting pane ID: %w", err)
+	}
+	startCmd, err := d.tmux.WithSecrets(config.BuildStartupCommand(envVars, rigPath, ""), secrets)
+	if err != nil {
+		return err
+	}
+	if err := d.tmux.RespawnPane(paneID, startCmd); err != nil {
+		return fmt.Errorf("starting agent: %w", err)
 	}
 
 	// Wait for Claude to start, then accept bypass permissions warning if it appears.
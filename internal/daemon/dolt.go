@@ -14,6 +14,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/errclass"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/retry"
 )
 
 const doltCmdTimeout = 15 * time.Second
@@ -184,6 +187,14 @@ func (m *DoltServerManager) IsExternal() bool {
 	return m.config != nil && m.config.External
 }
 
+// GaveUp reports whether the restart cap was reached, so the daemon has
+// stopped restarting the server until it is seen healthy again.
+func (m *DoltServerManager) GaveUp() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.escalated
+}
+
 // isRemote returns true when the daemon's Dolt config points to a non-local server.
 func (m *DoltServerManager) isRemote() bool {
 	if m.config == nil {
@@ -367,7 +378,7 @@ func (m *DoltServerManager) EnsureRunning() error {
 			m.sendUnhealthyAlert(err)
 			m.writeUnhealthySignal("health_check_failed", err.Error())
 			m.stopLocked()
-			return m.restartWithBackoff()
+			return m.recoverLocked("health_check_failed")
 		}
 		// Check write capability (read-only detection).
 		// The SELECT 1 health check above only verifies read connectivity.
@@ -378,7 +389,7 @@ func (m *DoltServerManager) EnsureRunning() error {
 			m.sendReadOnlyAlert(err)
 			m.writeUnhealthySignal("read_only", err.Error())
 			m.stopLocked()
-			return m.restartWithBackoff()
+			return m.recoverLocked("read_only")
 		}
 		// Server is healthy — clear any stale unhealthy signal and reset backoff
 		m.clearUnhealthySignal()
@@ -391,6 +402,7 @@ func (m *DoltServerManager) EnsureRunning() error {
 		m.logger("Dolt server PID %d is dead, cleaning up and restarting...", pid)
 		m.sendCrashAlert(pid)
 		m.writeUnhealthySignal("server_dead", fmt.Sprintf("PID %d is dead", pid))
+		return m.recoverLocked("server_dead")
 	}
 	return m.restartWithBackoff()
 }
@@ -451,6 +463,41 @@ func (m *DoltServerManager) restartWithBackoff() error {
 	return m.startLocked()
 }
 
+// recoverLocked restarts a server that crashed or failed a health check and
+// records the restart in the town events log. Must be called with m.mu held.
+func (m *DoltServerManager) recoverLocked(reason string) error {
+	if err := m.restartWithBackoff(); err != nil {
+		return err
+	}
+	if m.townRoot != "" {
+		_ = events.LogAt(m.townRoot, events.TypeDoltRestarted, "daemon",
+			events.DoltRestartPayload(m.config.Port, len(m.restartTimes), reason), events.VisibilityFeed)
+	}
+	return nil
+}
+
+// RestartReadOnly restarts a managed server found read-only outside the
+// regular health check (e.g. by the read_only_recovery patrol, which probes
+// every database rather than just the first). Restarts go through the same
+// backoff and restart cap as EnsureRunning. External servers can't be
+// restarted and return an error.
+func (m *DoltServerManager) RestartReadOnly(detail string) error {
+	if m.IsExternal() {
+		return fmt.Errorf("dolt server is external; restart it manually")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.restarting {
+		return fmt.Errorf("dolt server restart already in progress")
+	}
+	m.logger("Dolt server read-only (%s), restarting...", detail)
+	m.writeUnhealthySignal("read_only", detail)
+	m.stopLocked()
+	return m.recoverLocked("read_only")
+}
+
 // getBackoffDelay returns the current backoff delay.
 func (m *DoltServerManager) getBackoffDelay() time.Duration {
 	if m.currentDelay <= 0 {
@@ -818,21 +865,12 @@ func (m *DoltServerManager) stopLocked() {
 	}
 
 	// Wait for graceful shutdown (up to 5 seconds)
-	done := make(chan struct{})
-	go func() {
-		for i := 0; i < 50; i++ {
-			if !isProcessAlive(process) {
-				close(done)
-				return
-			}
-			time.Sleep(100 * time.Millisecond)
-		}
-	}()
-
-	select {
-	case <-done:
+	exited := retry.Poll(context.Background(), retry.Constant(50, 100*time.Millisecond), func() bool {
+		return !isProcessAlive(process)
+	})
+	if exited {
 		m.logger("Dolt SQL server stopped gracefully")
-	case <-time.After(5 * time.Second):
+	} else {
 		// Force kill
 		m.logger("Dolt SQL server did not stop gracefully, forcing termination")
 		_ = sendKillSignal(process)
@@ -1001,10 +1039,7 @@ func (m *DoltServerManager) getDatabases() ([]string, error) {
 
 // isReadOnlyError checks if an error message indicates a Dolt read-only state.
 func isReadOnlyError(msg string) bool {
-	lower := strings.ToLower(msg)
-	return strings.Contains(lower, "read only") ||
-		strings.Contains(lower, "read-only") ||
-		strings.Contains(lower, "readonly")
+	return errclass.ClassifyMessage(msg) == errclass.ReadOnly
 }
 
 // sendReadOnlyAlert sends an alert when the Dolt server enters read-only mode.
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// headlessReapInterval is how often exited headless sessions are reaped.
+const headlessReapInterval = 30 * time.Second
+
+// HeadlessSupervisor reaps headless agent sessions whose process has exited,
+// so liveness checks report them dead and the usual restart paths (witness,
+// heartbeat) pick them up. It only runs when the town's multiplexer is
+// "headless". It runs as a background goroutine within the daemon.
+type HeadlessSupervisor struct {
+	townRoot string
+	headless *multiplexer.Headless
+	interval time.Duration
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewHeadlessSupervisor creates a supervisor for the town's headless sessions.
+func NewHeadlessSupervisor(townRoot string, logger func(format string, args ...interface{})) *HeadlessSupervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HeadlessSupervisor{
+		townRoot: townRoot,
+		headless: multiplexer.NewHeadless(townRoot),
+		interval: headlessReapInterval,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start begins the supervisor goroutine.
+func (s *HeadlessSupervisor) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop gracefully stops the supervisor. Running sessions are left alone.
+func (s *HeadlessSupervisor) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *HeadlessSupervisor) run() {
+	defer s.wg.Done()
+
+	s.reap()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.reap()
+		}
+	}
+}
+
+// reap clears exited sessions and records each as a session death.
+func (s *HeadlessSupervisor) reap() {
+	reaped, err := s.headless.Reap()
+	if err != nil {
+		s.logger("headless: reaping sessions: %v", err)
+		return
+	}
+	for _, name := range reaped {
+		agent := "unknown"
+		if id, err := session.ParseSessionName(name); err == nil {
+			agent = id.Address()
+		}
+		s.logger("headless: session %s (%s) exited; log: %s", name, agent, s.headless.LogPath(name))
+		_ = events.LogAt(s.townRoot, events.TypeSessionDeath, "daemon",
+			events.SessionDeathPayload(name, agent, "process exited", "daemon"), events.VisibilityFeed)
+	}
+}
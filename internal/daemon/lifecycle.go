@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -171,8 +172,9 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		}
 	}
 
-	// Check if session exists (tmux detection still needed for lifecycle actions)
-	running, err := d.tmux.HasSession(sessionName)
+	// Check if session exists (session detection still needed for lifecycle actions)
+	mux := d.sessionMux()
+	running, err := mux.HasSession(sessionName)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -182,7 +184,7 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		if running {
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 			// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-			if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			if err := mux.KillSessionWithProcesses(sessionName); err != nil {
 				return fmt.Errorf("killing session: %w", err)
 			}
 			d.logger.Printf("Killed session %s", sessionName)
@@ -192,7 +194,7 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 	case ActionCycle, ActionRestart:
 		if running {
 			// Kill the session first - use KillSessionWithProcesses to prevent orphan processes.
-			if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			if err := mux.KillSessionWithProcesses(sessionName); err != nil {
 				return fmt.Errorf("killing session: %w", err)
 			}
 			d.logger.Printf("Killed session %s for restart", sessionName)
@@ -371,6 +373,15 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 		d.syncWorkspace(workDir)
 	}
 
+	if mux := d.sessionMux(); !d.usesTmux(mux) {
+		// Other backends run the startup command as the session itself,
+		// with the environment given at creation.
+		if err := mux.NewSessionWithCommandAndEnv(sessionName, workDir, d.getStartCommand(config, parsed), d.sessionEnvironment(config, parsed)); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+
 	// Create session
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
 	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
@@ -525,8 +536,15 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 }
 
 // setSessionEnvironment sets environment variables for the tmux session.
-// Uses centralized AgentEnv for consistency, plus custom env vars from role config if available.
 func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.RoleConfig, parsed *ParsedIdentity) {
+	for k, v := range d.sessionEnvironment(roleConfig, parsed) {
+		_ = d.tmux.SetEnvironment(sessionName, k, v)
+	}
+}
+
+// sessionEnvironment returns the environment for an agent's session.
+// Uses centralized AgentEnv for consistency, plus custom env vars from role config if available.
+func (d *Daemon) sessionEnvironment(roleConfig *beads.RoleConfig, parsed *ParsedIdentity) map[string]string {
 	// Use centralized AgentEnv for base environment variables
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:      parsed.RoleType,
@@ -534,17 +552,14 @@ func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.Rol
 		AgentName: parsed.AgentName,
 		TownRoot:  d.config.TownRoot,
 	})
-	for k, v := range envVars {
-		_ = d.tmux.SetEnvironment(sessionName, k, v)
-	}
 
-	// Set any custom env vars from role config
+	// Add any custom env vars from role config
 	if roleConfig != nil {
 		for k, v := range roleConfig.EnvVars {
-			expanded := beads.ExpandRolePattern(v, d.config.TownRoot, parsed.RigName, parsed.AgentName, parsed.RoleType)
-			_ = d.tmux.SetEnvironment(sessionName, k, expanded)
+			envVars[k] = beads.ExpandRolePattern(v, d.config.TownRoot, parsed.RigName, parsed.AgentName, parsed.RoleType)
 		}
 	}
+	return envVars
 }
 
 // applySessionTheme applies tmux theming to the session.
@@ -945,6 +960,8 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 		return
 	}
 
+	mux := d.sessionMux()
+
 	// Use the rig's configured prefix (e.g., "gt" for gastown, "bd" for beads)
 	rigPrefix := config.GetRigPrefix(d.config.TownRoot, rigName)
 	// Pattern: <prefix>-<rig>-polecat-<name>
@@ -966,8 +983,8 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 		polecatName := strings.TrimPrefix(agent.ID, prefix)
 		sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
-		// Check if tmux session exists and agent is running
-		if d.tmux.IsAgentAlive(sessionName) {
+		// Check if session exists and agent is running
+		if multiplexer.AgentAlive(mux, sessionName) {
 			// Session is alive - check if it's been stuck too long
 			updatedAt, err := time.Parse(time.RFC3339, agent.UpdatedAt)
 			if err != nil {
@@ -1041,6 +1058,8 @@ func (d *Daemon) checkRigOrphanedWork(rigName string) {
 		return
 	}
 
+	mux := d.sessionMux()
+
 	// Use the rig's configured prefix (e.g., "gt" for gastown, "bd" for beads)
 	rigPrefix := config.GetRigPrefix(d.config.TownRoot, rigName)
 	// Pattern: <prefix>-<rig>-polecat-<name>
@@ -1061,14 +1080,14 @@ func (d *Daemon) checkRigOrphanedWork(rigName string) {
 		sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
 		// Session running = not orphaned (work is being processed)
-		if d.tmux.IsAgentAlive(sessionName) {
+		if multiplexer.AgentAlive(mux, sessionName) {
 			continue
 		}
 
 		// TOCTOU guard: re-verify agent state before taking action.
 		// Between the bd list above and now, the agent may have been
 		// restarted or its hook_bead cleared. Re-check both conditions.
-		if d.tmux.IsAgentAlive(sessionName) {
+		if multiplexer.AgentAlive(mux, sessionName) {
 			continue
 		}
 		currentHookBead := d.getAgentHookBead(agent.ID)
@@ -1,9 +1,11 @@
 package daemon
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -79,6 +81,49 @@ func TestIsPatrolEnabled_DoltRemotes(t *testing.T) {
 	}
 }
 
+func TestIsPatrolEnabled_BranchRefresh(t *testing.T) {
+	// branch_refresh is opt-in: it queries every rig's remote
+	if IsPatrolEnabled(nil, "branch_refresh") {
+		t.Error("expected branch_refresh to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "branch_refresh") {
+		t.Error("expected branch_refresh to be disabled by default")
+	}
+	config.Patrols.BranchRefresh = &BranchRefreshConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "branch_refresh") {
+		t.Error("expected branch_refresh to be enabled when configured")
+	}
+
+	var parsed DaemonPatrolConfig
+	if err := json.Unmarshal([]byte(`{"patrols":{"branch_refresh":{"enabled":true}}}`), &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !IsPatrolEnabled(&parsed, "branch_refresh") {
+		t.Error("expected branch_refresh enabled from daemon.json")
+	}
+	if _, ok := parsed.Patrols.Custom["branch_refresh"]; ok {
+		t.Error("branch_refresh should not be treated as a custom patrol")
+	}
+}
+
+func TestIsPatrolEnabled_UpstreamSync(t *testing.T) {
+	// upstream_sync is opt-in: it pushes to every fork rig
+	if IsPatrolEnabled(nil, "upstream_sync") {
+		t.Error("expected upstream_sync to be disabled with nil config")
+	}
+	var parsed DaemonPatrolConfig
+	if err := json.Unmarshal([]byte(`{"patrols":{"upstream_sync":{"enabled":true,"strategy":"rebase"}}}`), &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !IsPatrolEnabled(&parsed, "upstream_sync") {
+		t.Error("expected upstream_sync enabled from daemon.json")
+	}
+	if parsed.Patrols.UpstreamSync.Strategy != "rebase" {
+		t.Errorf("strategy = %q, want rebase", parsed.Patrols.UpstreamSync.Strategy)
+	}
+}
+
 func TestDoltRemotesInterval(t *testing.T) {
 	// Default interval
 	if got := doltRemotesInterval(nil); got != defaultDoltRemotesInterval {
@@ -98,3 +143,93 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_BeadChanges(t *testing.T) {
+	// bead_changes defaults to enabled
+	if !IsPatrolEnabled(nil, "bead_changes") {
+		t.Error("expected bead_changes to be enabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			BeadChanges: &BeadChangesConfig{Enabled: false},
+		},
+	}
+	if IsPatrolEnabled(config, "bead_changes") {
+		t.Error("expected bead_changes to be disabled when explicitly disabled")
+	}
+	if got := beadChangesInterval(config); got != defaultBeadChangesInterval {
+		t.Errorf("expected default interval %v, got %v", defaultBeadChangesInterval, got)
+	}
+}
+
+func TestIsPatrolEnabled_RigStats(t *testing.T) {
+	// rig_stats defaults to enabled
+	if !IsPatrolEnabled(nil, "rig_stats") {
+		t.Error("expected rig_stats to be enabled with nil config")
+	}
+	if got := rigStatsInterval(nil); got != defaultRigStatsInterval {
+		t.Errorf("expected default interval %v, got %v", defaultRigStatsInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			RigStats: &RigStatsConfig{Enabled: false, Interval: 15 * time.Minute},
+		},
+	}
+	if IsPatrolEnabled(config, "rig_stats") {
+		t.Error("expected rig_stats to be disabled when explicitly disabled")
+	}
+	if got := rigStatsInterval(config); got != 15*time.Minute {
+		t.Errorf("expected 15m interval, got %v", got)
+	}
+}
+
+func TestIsPatrolEnabled_TableStats(t *testing.T) {
+	// table_stats is opt-in: it samples the processlist every few seconds
+	if IsPatrolEnabled(nil, "table_stats") {
+		t.Error("expected table_stats to be disabled with nil config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "table_stats") {
+		t.Error("expected table_stats to be disabled when not configured")
+	}
+	sample, record := tableStatsIntervals(nil)
+	if sample != defaultTableStatsSampleInterval || record != defaultTableStatsInterval {
+		t.Errorf("default intervals = %v, %v; want %v, %v", sample, record, defaultTableStatsSampleInterval, defaultTableStatsInterval)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			TableStats: &TableStatsConfig{Enabled: true, SampleInterval: 5 * time.Second},
+		},
+	}
+	if !IsPatrolEnabled(config, "table_stats") {
+		t.Error("expected table_stats to be enabled when explicitly enabled")
+	}
+	sample, record = tableStatsIntervals(config)
+	if sample != 5*time.Second || record != defaultTableStatsInterval {
+		t.Errorf("intervals = %v, %v; want 5s, %v", sample, record, defaultTableStatsInterval)
+	}
+}
+
+func TestIsPatrolEnabled_ControlAPI(t *testing.T) {
+	// control_api defaults to enabled, on the town's daemon/control.sock
+	if !IsPatrolEnabled(nil, "control_api") {
+		t.Error("expected control_api to be enabled with nil config")
+	}
+	if got, want := ControlAPISocket("/town", nil), filepath.Join("/town", "daemon", "control.sock"); got != want {
+		t.Errorf("expected default socket %s, got %s", want, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			ControlAPI: &ControlAPIConfig{Enabled: false, Socket: "/tmp/gt.sock"},
+		},
+	}
+	if IsPatrolEnabled(config, "control_api") {
+		t.Error("expected control_api to be disabled when explicitly disabled")
+	}
+	if got := ControlAPISocket("/town", config); got != "/tmp/gt.sock" {
+		t.Errorf("expected configured socket, got %s", got)
+	}
+}
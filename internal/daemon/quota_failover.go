@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// quotaFailoverTimeout bounds a single 'gt quota rotate' invocation.
+// Rotation restarts panes and waits for them, so it gets more headroom
+// than the usual subprocess calls.
+const quotaFailoverTimeout = 2 * time.Minute
+
+// checkQuotaFailover rotates rate-limited sessions onto available accounts
+// when the accounts policy has failover enabled (mayor/accounts.json).
+// Delegates to 'gt quota rotate' so the daemon shares the exact rotation
+// logic (session resume, restart commands) used by the CLI.
+func (d *Daemon) checkQuotaFailover() {
+	acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(d.config.TownRoot))
+	if err != nil || acctCfg.Policy == nil || !acctCfg.Policy.Failover {
+		return
+	}
+	if len(acctCfg.Accounts) < 2 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, quotaFailoverTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "quota", "rotate", "--json") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	out, err := cmd.Output()
+	if err != nil {
+		d.logger.Printf("Quota failover: rotate failed: %s", util.FirstLine(err.Error()))
+		return
+	}
+
+	// Nothing to rotate prints a text line instead of JSON; ignore parse errors.
+	var results []struct {
+		Session    string `json:"session"`
+		NewAccount string `json:"new_account"`
+		Rotated    bool   `json:"rotated"`
+		Error      string `json:"error"`
+	}
+	if err := json.Unmarshal(out, &results); err != nil {
+		return
+	}
+	for _, r := range results {
+		switch {
+		case r.Rotated:
+			d.logger.Printf("Quota failover: rotated %s to account %s", r.Session, r.NewAccount)
+		case r.Error != "":
+			d.logger.Printf("Quota failover: %s: %s", r.Session, r.Error)
+		}
+	}
+}
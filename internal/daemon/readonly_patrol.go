@@ -0,0 +1,226 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// defaultReadOnlyRecoveryInterval is how often every database is probed.
+// Each probe is one small write per database.
+const defaultReadOnlyRecoveryInterval = 5 * time.Minute
+
+// defaultReadOnlyEscalateAfter is how many consecutive failed recoveries
+// trigger an escalation.
+const defaultReadOnlyEscalateAfter = 2
+
+// readOnlyRecoveryInterval returns the configured probe interval for read_only_recovery.
+func readOnlyRecoveryInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.ReadOnlyRecovery != nil {
+		if config.Patrols.ReadOnlyRecovery.Interval > 0 {
+			return config.Patrols.ReadOnlyRecovery.Interval
+		}
+	}
+	return defaultReadOnlyRecoveryInterval
+}
+
+// readOnlyEscalation returns the configured escalation threshold and severity.
+func readOnlyEscalation(cfg *DaemonPatrolConfig) (after int, severity string) {
+	after, severity = defaultReadOnlyEscalateAfter, config.SeverityHigh
+	if cfg != nil && cfg.Patrols != nil && cfg.Patrols.ReadOnlyRecovery != nil {
+		if n := cfg.Patrols.ReadOnlyRecovery.EscalateAfter; n > 0 {
+			after = n
+		}
+		if s := cfg.Patrols.ReadOnlyRecovery.Severity; s != "" {
+			severity = s
+		}
+	}
+	return after, severity
+}
+
+// ReadOnlyPatrol probes every Dolt database for writes and restarts the
+// server when any is stuck read-only. The daemon's health check only probes
+// the first database; a wedged manifest elsewhere would otherwise stall
+// writes until someone noticed. Detections and recoveries are logged to the
+// town events log, and repeated failed recoveries are escalated.
+// It runs as a background goroutine within the daemon.
+type ReadOnlyPatrol struct {
+	interval      time.Duration
+	escalateAfter int
+	logger        func(format string, args ...interface{})
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+
+	// Hooks, replaced in tests.
+	listDatabases func() ([]string, error)
+	probe         func(db string) (bool, error)
+	restart       func(detail string) error
+	escalate      func(databases []string, failures int, lastErr error)
+	emit          func(eventType string, payload map[string]interface{})
+
+	failures  int  // consecutive passes where recovery didn't clear read-only
+	escalated bool // an escalation was filed for the current episode
+}
+
+// NewReadOnlyPatrol creates a patrol that probes every interval. restart
+// restarts the server (see DoltServerManager.RestartReadOnly).
+func NewReadOnlyPatrol(townRoot, gtPath string, interval time.Duration, escalateAfter int, severity string, restart func(detail string) error, logger func(format string, args ...interface{})) *ReadOnlyPatrol {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &ReadOnlyPatrol{
+		interval:      interval,
+		escalateAfter: escalateAfter,
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+		listDatabases: func() ([]string, error) { return doltserver.ListDatabases(townRoot) },
+		probe:         func(db string) (bool, error) { return doltserver.CheckReadOnlyDatabase(townRoot, db) },
+		restart:       restart,
+	}
+	p.escalate = func(databases []string, failures int, lastErr error) {
+		escalateReadOnly(townRoot, gtPath, severity, databases, failures, lastErr, logger)
+	}
+	p.emit = func(eventType string, payload map[string]interface{}) {
+		_ = events.LogAt(townRoot, eventType, "daemon", payload, events.VisibilityFeed)
+	}
+	return p
+}
+
+// Start begins the patrol goroutine.
+func (p *ReadOnlyPatrol) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Stop gracefully stops the patrol.
+func (p *ReadOnlyPatrol) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *ReadOnlyPatrol) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.check()
+		}
+	}
+}
+
+// readOnlyDatabases probes every database and returns those that refused
+// the test write. Probe failures that aren't read-only are logged and
+// otherwise ignored, matching the daemon's health check.
+func (p *ReadOnlyPatrol) readOnlyDatabases() []string {
+	databases, err := p.listDatabases()
+	if err != nil {
+		p.logger("read_only_recovery: listing databases: %v", err)
+		return nil
+	}
+	var readOnly []string
+	for _, db := range databases {
+		if p.ctx.Err() != nil {
+			return nil
+		}
+		ro, err := p.probe(db)
+		if err != nil {
+			p.logger("read_only_recovery: probing %s: %v", db, err)
+			continue
+		}
+		if ro {
+			readOnly = append(readOnly, db)
+		}
+	}
+	return readOnly
+}
+
+// check runs one probe pass, restarting the server if any database is
+// read-only and escalating once recovery has failed escalateAfter times in
+// a row.
+func (p *ReadOnlyPatrol) check() {
+	readOnly := p.readOnlyDatabases()
+	if len(readOnly) == 0 {
+		if p.failures > 0 {
+			p.logger("read_only_recovery: databases writable again after %d failed recovery attempt(s)", p.failures)
+		}
+		p.failures = 0
+		p.escalated = false
+		return
+	}
+
+	p.logger("read_only_recovery: read-only database(s): %s", strings.Join(readOnly, ", "))
+
+	err := p.restart("databases: " + strings.Join(readOnly, ", "))
+	if err == nil {
+		if still := p.stillReadOnly(readOnly); len(still) > 0 {
+			err = fmt.Errorf("still read-only after restart: %s", strings.Join(still, ", "))
+		}
+	}
+	if err == nil {
+		p.logger("read_only_recovery: recovered %s", strings.Join(readOnly, ", "))
+		p.emit(events.TypeDoltReadOnlyRecovered, events.DoltReadOnlyPayload(readOnly, p.failures, ""))
+		p.failures = 0
+		p.escalated = false
+		return
+	}
+
+	p.failures++
+	p.logger("read_only_recovery: recovery failed (%d in a row): %v", p.failures, err)
+	p.emit(events.TypeDoltReadOnly, events.DoltReadOnlyPayload(readOnly, p.failures, err.Error()))
+	if p.failures >= p.escalateAfter && !p.escalated {
+		p.escalated = true
+		p.escalate(readOnly, p.failures, err)
+	}
+}
+
+// stillReadOnly re-probes databases after a restart.
+func (p *ReadOnlyPatrol) stillReadOnly(databases []string) []string {
+	var still []string
+	for _, db := range databases {
+		if ro, err := p.probe(db); err == nil && ro {
+			still = append(still, db)
+		}
+	}
+	return still
+}
+
+// escalateReadOnly files an escalation via gt escalate, which routes it per
+// settings/escalation.json. Runs asynchronously so a slow escalation never
+// delays the patrol.
+func escalateReadOnly(townRoot, gtPath, severity string, databases []string, failures int, lastErr error, logger func(format string, args ...interface{})) {
+	description := "Dolt server stuck read-only"
+	reason := fmt.Sprintf(`The read_only_recovery patrol found database(s) refusing writes: %s.
+Restarting the Dolt server has failed %d time(s) in a row: %v
+
+All bead writes (create, update, close) to these databases fail until this is fixed.
+Check the Dolt log and disk, then run: gt dolt status --repair`,
+		strings.Join(databases, ", "), failures, lastErr)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, gtPath, "escalate", description, //nolint:gosec // G204: args are constructed internally
+			"--severity", severity, "--source", "patrol:read_only_recovery", "--reason", reason)
+		cmd.Dir = townRoot
+		cmd.Env = os.Environ()
+		if err := cmd.Run(); err != nil {
+			logger("Warning: failed to escalate read-only Dolt server: %v", err)
+		} else {
+			logger("Escalated read-only Dolt server (%s)", severity)
+		}
+	}()
+}
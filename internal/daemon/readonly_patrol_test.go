@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// fakeReadOnlyPatrol returns a patrol whose probe reports the databases in
+// readOnly as read-only, and which records restarts, events, and escalations.
+type fakeReadOnlyPatrol struct {
+	*ReadOnlyPatrol
+	readOnly    map[string]bool
+	restartErr  error
+	healOnStart bool
+	restarts    int
+	escalations int
+	emitted     []string
+}
+
+func newFakeReadOnlyPatrol(t *testing.T, escalateAfter int) *fakeReadOnlyPatrol {
+	t.Helper()
+	f := &fakeReadOnlyPatrol{readOnly: map[string]bool{}}
+	f.ReadOnlyPatrol = NewReadOnlyPatrol(t.TempDir(), "gt", time.Hour, escalateAfter, "high", func(string) error {
+		f.restarts++
+		if f.restartErr != nil {
+			return f.restartErr
+		}
+		if f.healOnStart {
+			f.readOnly = map[string]bool{}
+		}
+		return nil
+	}, func(string, ...interface{}) {})
+	f.listDatabases = func() ([]string, error) { return []string{"hq", "gastown", "beads"}, nil }
+	f.probe = func(db string) (bool, error) { return f.readOnly[db], nil }
+	f.escalate = func([]string, int, error) { f.escalations++ }
+	f.emit = func(eventType string, _ map[string]interface{}) { f.emitted = append(f.emitted, eventType) }
+	return f
+}
+
+func TestReadOnlyPatrol_AllWritable(t *testing.T) {
+	f := newFakeReadOnlyPatrol(t, 2)
+	f.check()
+	if f.restarts != 0 || len(f.emitted) != 0 {
+		t.Errorf("restarts=%d events=%v, want none", f.restarts, f.emitted)
+	}
+}
+
+func TestReadOnlyPatrol_RecoversNonFirstDatabase(t *testing.T) {
+	f := newFakeReadOnlyPatrol(t, 2)
+	f.readOnly["beads"] = true // not databases[0], which the health check probes
+	f.healOnStart = true
+
+	f.check()
+	if f.restarts != 1 {
+		t.Errorf("restarts = %d, want 1", f.restarts)
+	}
+	if len(f.emitted) != 1 || f.emitted[0] != events.TypeDoltReadOnlyRecovered {
+		t.Errorf("events = %v, want [%s]", f.emitted, events.TypeDoltReadOnlyRecovered)
+	}
+	if f.failures != 0 {
+		t.Errorf("failures = %d, want 0", f.failures)
+	}
+}
+
+func TestReadOnlyPatrol_EscalatesOnRepeatedFailure(t *testing.T) {
+	f := newFakeReadOnlyPatrol(t, 2)
+	f.readOnly["gastown"] = true // restart doesn't clear it
+
+	f.check()
+	if f.escalations != 0 || f.failures != 1 {
+		t.Fatalf("after 1 pass: escalations=%d failures=%d", f.escalations, f.failures)
+	}
+	f.check()
+	if f.escalations != 1 {
+		t.Fatalf("after 2 passes: escalations=%d, want 1", f.escalations)
+	}
+	f.check()
+	if f.escalations != 1 {
+		t.Errorf("escalated again within the same episode: %d", f.escalations)
+	}
+	for _, e := range f.emitted {
+		if e != events.TypeDoltReadOnly {
+			t.Errorf("unexpected event %s", e)
+		}
+	}
+
+	// Once writable, a new episode can escalate again.
+	f.readOnly = map[string]bool{}
+	f.check()
+	if f.failures != 0 || f.escalated {
+		t.Errorf("not reset after recovery: failures=%d escalated=%v", f.failures, f.escalated)
+	}
+}
+
+func TestReadOnlyPatrol_RestartError(t *testing.T) {
+	f := newFakeReadOnlyPatrol(t, 1)
+	f.readOnly["hq"] = true
+	f.restartErr = errors.New("restart cap reached")
+
+	f.check()
+	if f.failures != 1 || f.escalations != 1 {
+		t.Errorf("failures=%d escalations=%d, want 1/1", f.failures, f.escalations)
+	}
+}
+
+func TestReadOnlyRecoveryConfig(t *testing.T) {
+	if !IsPatrolEnabled(nil, "read_only_recovery") {
+		t.Error("expected read_only_recovery to be enabled with nil config")
+	}
+	if got := readOnlyRecoveryInterval(nil); got != defaultReadOnlyRecoveryInterval {
+		t.Errorf("default interval = %v", got)
+	}
+	if after, severity := readOnlyEscalation(nil); after != defaultReadOnlyEscalateAfter || severity != "high" {
+		t.Errorf("default escalation = %d/%s", after, severity)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			ReadOnlyRecovery: &ReadOnlyRecoveryConfig{Enabled: false, Interval: time.Minute, EscalateAfter: 4, Severity: "critical"},
+		},
+	}
+	if IsPatrolEnabled(config, "read_only_recovery") {
+		t.Error("expected read_only_recovery to be disabled when explicitly disabled")
+	}
+	if got := readOnlyRecoveryInterval(config); got != time.Minute {
+		t.Errorf("interval = %v, want 1m", got)
+	}
+	if after, severity := readOnlyEscalation(config); after != 4 || severity != "critical" {
+		t.Errorf("escalation = %d/%s, want 4/critical", after, severity)
+	}
+}
+
+func TestRestartReadOnly(t *testing.T) {
+	m := newTestManager(t)
+	var started int
+	m.startFn = func() error { started++; return nil }
+	m.sleepFn = func(time.Duration) {}
+
+	if err := m.RestartReadOnly("databases: beads"); err != nil {
+		t.Fatalf("RestartReadOnly: %v", err)
+	}
+	if started != 1 {
+		t.Errorf("starts = %d, want 1", started)
+	}
+	if len(m.restartTimes) != 1 {
+		t.Errorf("restart not counted toward the restart cap: %v", m.restartTimes)
+	}
+
+	m.config.External = true
+	if err := m.RestartReadOnly("databases: beads"); err == nil {
+		t.Error("expected error restarting an external server")
+	}
+}
@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/rigstats"
+)
+
+// defaultRigStatsInterval is how often each rig's activity is sampled.
+// Hourly keeps a month of history to ~720 rows per rig.
+const defaultRigStatsInterval = time.Hour
+
+// rigStatsTimeout bounds one collection pass across all rigs.
+const rigStatsTimeout = 2 * time.Minute
+
+// rigStatsInterval returns the configured sampling interval for rig_stats.
+func rigStatsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.RigStats != nil {
+		if config.Patrols.RigStats.Interval > 0 {
+			return config.Patrols.RigStats.Interval
+		}
+	}
+	return defaultRigStatsInterval
+}
+
+// RigStatsCollector samples per-rig activity (merges, closed beads, polecat
+// hours, queue wait) into the gt_rig_stats table in hq.
+// It runs as a background goroutine within the daemon.
+type RigStatsCollector struct {
+	townRoot string
+	interval time.Duration
+	rigs     func() []string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewRigStatsCollector creates a collector that samples every interval.
+// rigs is called on each pass so newly added rigs are picked up.
+func NewRigStatsCollector(townRoot string, interval time.Duration, rigs func() []string, logger func(format string, args ...interface{})) *RigStatsCollector {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RigStatsCollector{
+		townRoot: townRoot,
+		interval: interval,
+		rigs:     rigs,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start begins the collector goroutine. The first sample is taken after one
+// interval, so each sample covers a full window.
+func (c *RigStatsCollector) Start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Stop gracefully stops the collector.
+func (c *RigStatsCollector) Stop() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *RigStatsCollector) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.collect()
+		}
+	}
+}
+
+// collect takes one sample of every rig and records it.
+func (c *RigStatsCollector) collect() {
+	rigs := c.rigs()
+	if len(rigs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, rigStatsTimeout)
+	defer cancel()
+
+	samples, err := rigstats.Collect(ctx, c.townRoot, rigs, c.interval, time.Now())
+	if err != nil && c.ctx.Err() == nil {
+		c.logger("rig_stats: %v", err)
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	db, err := doltserver.DB(c.townRoot, rigstats.Database)
+	if err != nil {
+		c.logger("rig_stats: %v", err)
+		return
+	}
+	if err := rigstats.Record(ctx, db, samples); err != nil && c.ctx.Err() == nil {
+		c.logger("rig_stats: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/wisp"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// inMaintenanceWindow reports whether a rig is inside a scheduled maintenance
+// window (settings/config.json "schedule" block) and has no active override.
+func (d *Daemon) inMaintenanceWindow(rigName string, now time.Time) bool {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.Schedule == nil {
+		return false
+	}
+
+	active, err := settings.Schedule.InMaintenanceWindow(now)
+	if err != nil {
+		d.logger.Printf("Warning: invalid maintenance schedule for %s: %v", rigName, err)
+		return false
+	}
+	if !active {
+		return false
+	}
+
+	cfg := wisp.NewConfig(d.config.TownRoot, rigName)
+	if until := cfg.GetString(config.ScheduleOverrideKey); until != "" {
+		if t, err := time.Parse(time.RFC3339, until); err == nil && now.Before(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// enforceMaintenanceWindows drains rigs that have entered a scheduled
+// maintenance window by stopping their witness and refinery. Polecats are left
+// to finish in-flight work. isRigOperational keeps the agents down for the
+// rest of the window; once it closes, the regular heartbeat restarts them.
+func (d *Daemon) enforceMaintenanceWindows() {
+	now := time.Now()
+	for _, rigName := range d.getKnownRigs() {
+		if !d.inMaintenanceWindow(rigName, now) {
+			continue
+		}
+
+		r := &rig.Rig{
+			Name: rigName,
+			Path: filepath.Join(d.config.TownRoot, rigName),
+		}
+		prefix := session.PrefixFor(rigName)
+
+		mux := d.sessionMux()
+
+		if running, _ := mux.HasSession(session.WitnessSessionName(prefix)); running {
+			d.logger.Printf("Maintenance window active for %s, stopping witness", rigName)
+			if err := witness.NewManager(r).Stop(); err != nil {
+				d.logger.Printf("Error stopping witness for %s: %v", rigName, err)
+			}
+		}
+
+		if running, _ := mux.HasSession(session.RefinerySessionName(prefix)); running {
+			d.logger.Printf("Maintenance window active for %s, stopping refinery", rigName)
+			if err := refinery.NewManager(r).Stop(); err != nil {
+				d.logger.Printf("Error stopping refinery for %s: %v", rigName, err)
+			}
+		}
+	}
+}
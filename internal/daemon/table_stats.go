@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/rigstats"
+)
+
+// defaultTableStatsSampleInterval is how often the processlist is sampled.
+const defaultTableStatsSampleInterval = 2 * time.Second
+
+// defaultTableStatsInterval is how often accumulated table load is recorded.
+const defaultTableStatsInterval = 15 * time.Minute
+
+// tableStatsTimeout bounds one processlist sample or one recording.
+const tableStatsTimeout = 30 * time.Second
+
+// tableStatsIntervals returns the configured sampling and recording
+// intervals for table_stats.
+func tableStatsIntervals(config *DaemonPatrolConfig) (sample, record time.Duration) {
+	sample, record = defaultTableStatsSampleInterval, defaultTableStatsInterval
+	if config != nil && config.Patrols != nil && config.Patrols.TableStats != nil {
+		if c := config.Patrols.TableStats; c.SampleInterval > 0 {
+			sample = c.SampleInterval
+		}
+		if c := config.Patrols.TableStats; c.Interval > 0 {
+			record = c.Interval
+		}
+	}
+	return sample, record
+}
+
+// TableStatsCollector samples the Dolt server's processlist and records
+// per-table query counts and busy time into the gt_table_stats table in hq,
+// showing which rigs and tables drive load on the shared server.
+// It runs as a background goroutine within the daemon.
+type TableStatsCollector struct {
+	townRoot       string
+	sampleInterval time.Duration
+	interval       time.Duration
+	rigs           func() []string
+	logger         func(format string, args ...interface{})
+	ctx            context.Context
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+// NewTableStatsCollector creates a collector that samples every
+// sampleInterval and records every interval. rigs is called on each
+// recording so newly added rigs are attributed.
+func NewTableStatsCollector(townRoot string, sampleInterval, interval time.Duration, rigs func() []string, logger func(format string, args ...interface{})) *TableStatsCollector {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TableStatsCollector{
+		townRoot:       townRoot,
+		sampleInterval: sampleInterval,
+		interval:       interval,
+		rigs:           rigs,
+		logger:         logger,
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// Start begins the collector goroutine.
+func (c *TableStatsCollector) Start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Stop gracefully stops the collector. Load sampled since the last
+// recording is dropped.
+func (c *TableStatsCollector) Stop() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *TableStatsCollector) run() {
+	defer c.wg.Done()
+
+	sampler := rigstats.NewTableSampler(c.sampleInterval, time.Now())
+	sampleTicker := time.NewTicker(c.sampleInterval)
+	defer sampleTicker.Stop()
+	recordTicker := time.NewTicker(c.interval)
+	defer recordTicker.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-sampleTicker.C:
+			err := c.sample(sampler)
+			// Log once per outage rather than every few seconds.
+			if err != nil && !failing && c.ctx.Err() == nil {
+				c.logger("table_stats: %v", err)
+			}
+			failing = err != nil
+		case <-recordTicker.C:
+			c.record(sampler)
+		}
+	}
+}
+
+// sample adds one processlist sample to sampler.
+func (c *TableStatsCollector) sample(sampler *rigstats.TableSampler) error {
+	db, err := doltserver.DB(c.townRoot, "")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, tableStatsTimeout)
+	defer cancel()
+	procs, err := rigstats.ListProcesses(ctx, db)
+	if err != nil {
+		return err
+	}
+	sampler.Observe(procs)
+	return nil
+}
+
+// record writes the load accumulated since the last recording.
+func (c *TableStatsCollector) record(sampler *rigstats.TableSampler) {
+	samples := sampler.Flush(time.Now(), rigstats.DatabaseRigs(c.townRoot, c.rigs()))
+	if len(samples) == 0 {
+		return
+	}
+	db, err := doltserver.DB(c.townRoot, rigstats.Database)
+	if err != nil {
+		c.logger("table_stats: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, tableStatsTimeout)
+	defer cancel()
+	if err := rigstats.RecordTableStats(ctx, db, samples); err != nil && c.ctx.Err() == nil {
+		c.logger("table_stats: %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -101,7 +102,8 @@ type PatrolConfig struct {
 	// Enabled controls whether this patrol runs during heartbeat.
 	Enabled bool `json:"enabled"`
 
-	// Interval is how often to run this patrol (not used yet).
+	// Interval is how often to run this patrol. Only custom patrols use it;
+	// for a plugin patrol it overrides the plugin's interval.
 	Interval string `json:"interval,omitempty"`
 
 	// Agent is the agent type for this patrol (not used yet).
@@ -109,6 +111,12 @@ type PatrolConfig struct {
 
 	// Rigs limits this patrol to specific rigs. If empty, all rigs are patrolled.
 	Rigs []string `json:"rigs,omitempty"`
+
+	// Exec is the shell command run by a custom patrol defined in daemon.json.
+	Exec string `json:"exec,omitempty"`
+
+	// Timeout bounds one run of a custom patrol (default: its interval).
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // PatrolsConfig holds configuration for all patrols.
@@ -118,6 +126,180 @@ type PatrolsConfig struct {
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	BeadChanges *BeadChangesConfig `json:"bead_changes,omitempty"`
+	RigStats    *RigStatsConfig    `json:"rig_stats,omitempty"`
+	TableStats  *TableStatsConfig  `json:"table_stats,omitempty"`
+
+	ReadOnlyRecovery *ReadOnlyRecoveryConfig `json:"read_only_recovery,omitempty"`
+	BranchRefresh    *BranchRefreshConfig    `json:"branch_refresh,omitempty"`
+	UpstreamSync     *UpstreamSyncConfig     `json:"upstream_sync,omitempty"`
+	AbandonedBeads   *AbandonedBeadsConfig   `json:"abandoned_beads,omitempty"`
+	ControlAPI       *ControlAPIConfig       `json:"control_api,omitempty"`
+
+	// Custom holds every other entry under "patrols", keyed by patrol name.
+	// These configure plugin patrols or define exec-based patrols directly.
+	Custom map[string]*PatrolConfig `json:"-"`
+}
+
+// builtinPatrolKeys are the "patrols" keys with a dedicated field.
+var builtinPatrolKeys = map[string]bool{
+	"refinery":     true,
+	"witness":      true,
+	"deacon":       true,
+	"dolt_server":  true,
+	"dolt_remotes": true,
+	"bead_changes": true,
+	"rig_stats":    true,
+	"table_stats":  true,
+
+	"read_only_recovery": true,
+	"branch_refresh":     true,
+	"upstream_sync":      true,
+	"abandoned_beads":    true,
+	"control_api":        true,
+}
+
+// UnmarshalJSON decodes the built-in patrols into their fields and collects
+// the remaining entries into Custom. Entries that are not patrol objects are
+// ignored so an unrelated key can't invalidate the whole file.
+func (p *PatrolsConfig) UnmarshalJSON(data []byte) error {
+	type plain PatrolsConfig
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for name, msg := range raw {
+		if builtinPatrolKeys[name] {
+			continue
+		}
+		var pc PatrolConfig
+		if err := json.Unmarshal(msg, &pc); err != nil {
+			continue
+		}
+		if p.Custom == nil {
+			p.Custom = make(map[string]*PatrolConfig)
+		}
+		p.Custom[name] = &pc
+	}
+	return nil
+}
+
+// BeadChangesConfig holds configuration for the bead_changes watcher, which
+// turns Dolt commits into bead change events in .events.jsonl.
+type BeadChangesConfig struct {
+	// Enabled controls whether the watcher runs (default true).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to poll the Dolt server (default 15s).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// RigStatsConfig holds configuration for the rig_stats collector, which
+// samples per-rig activity into the gt_rig_stats table for gt rig stats.
+type RigStatsConfig struct {
+	// Enabled controls whether the collector runs (default true).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to sample each rig (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// TableStatsConfig holds configuration for the table_stats collector, which
+// samples the Dolt processlist into per-table load in the gt_table_stats
+// table for gt dolt table-stats. Opt-in: it queries the server every few
+// seconds.
+type TableStatsConfig struct {
+	// Enabled controls whether the collector runs (default false).
+	Enabled bool `json:"enabled"`
+
+	// SampleInterval is how often to sample the processlist (default 2s).
+	// Shorter intervals catch more short queries at the cost of more load.
+	SampleInterval time.Duration `json:"sample_interval,omitempty"`
+
+	// Interval is how often to record the accumulated load (default 15m).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// ReadOnlyRecoveryConfig holds configuration for the read_only_recovery
+// patrol, which probes every database for writes and restarts the Dolt
+// server when one is stuck read-only.
+type ReadOnlyRecoveryConfig struct {
+	// Enabled controls whether the patrol runs (default true).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to probe each database (default 5m).
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// EscalateAfter is how many consecutive failed recoveries trigger an
+	// escalation (default 2).
+	EscalateAfter int `json:"escalate_after,omitempty"`
+
+	// Severity is the escalation severity, which selects the route in
+	// settings/escalation.json (default "high").
+	Severity string `json:"severity,omitempty"`
+}
+
+// BranchRefreshConfig holds configuration for the branch_refresh patrol,
+// which re-detects each rig's default branch from its remote (see
+// gt rig refresh-branch). Opt-in: it queries every rig's remote.
+type BranchRefreshConfig struct {
+	// Enabled controls whether the patrol runs (default false).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to check each rig (default 24h).
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// UpstreamSyncConfig holds configuration for the upstream_sync patrol,
+// which keeps fork rigs (rigs with a push_url) in step with their upstream
+// (see gt rig sync-upstream). Opt-in: it pushes to every fork.
+type UpstreamSyncConfig struct {
+	// Enabled controls whether the patrol runs (default false).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to sync each fork rig (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Strategy is "ff-only" (default) or "rebase". With ff-only a fork
+	// holding commits of its own is reported as diverged, not rewritten.
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// AbandonedBeadsConfig holds configuration for the abandoned_beads patrol,
+// which reopens in_progress beads whose assignee has had no session and no
+// updates for MaxIdle (see gt deacon abandoned-beads). Opt-in: it changes
+// bead status and assignees.
+type AbandonedBeadsConfig struct {
+	// Enabled controls whether the patrol runs (default false).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to scan (default 1h).
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// MaxIdle is how long a bead must sit untouched (default 24h).
+	MaxIdle time.Duration `json:"max_idle,omitempty"`
+
+	// Status is the status abandoned beads are moved to (default "open").
+	Status string `json:"status,omitempty"`
+
+	// IncludePersistent also reopens beads of crew and other persistent
+	// agents (default false: only polecat work is reclaimed).
+	IncludePersistent bool `json:"include_persistent,omitempty"`
+}
+
+// ControlAPIConfig holds configuration for the control API, the JSON-RPC
+// server on a unix socket that lets other tools query and drive the town.
+type ControlAPIConfig struct {
+	// Enabled controls whether the API is served (default true).
+	Enabled bool `json:"enabled"`
+
+	// Socket is the socket path (default daemon/control.sock). Unix socket
+	// paths are limited to about 100 bytes, so a town with a long root may
+	// need a shorter one.
+	Socket string `json:"socket,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -157,7 +339,7 @@ func PatrolConfigFile(townRoot string) string {
 // Returns nil if the file doesn't exist or can't be parsed.
 func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 	configFile := PatrolConfigFile(townRoot)
-	data, err := os.ReadFile(configFile)
+	data, err := config.ReadConfigFile(configFile)
 	if err != nil {
 		return nil
 	}
@@ -171,7 +353,8 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, branch_refresh, upstream_sync, table_stats,
+// abandoned_beads) default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -182,6 +365,30 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "branch_refresh" {
+		if config == nil || config.Patrols == nil || config.Patrols.BranchRefresh == nil {
+			return false
+		}
+		return config.Patrols.BranchRefresh.Enabled
+	}
+	if patrol == "upstream_sync" {
+		if config == nil || config.Patrols == nil || config.Patrols.UpstreamSync == nil {
+			return false
+		}
+		return config.Patrols.UpstreamSync.Enabled
+	}
+	if patrol == "abandoned_beads" {
+		if config == nil || config.Patrols == nil || config.Patrols.AbandonedBeads == nil {
+			return false
+		}
+		return config.Patrols.AbandonedBeads.Enabled
+	}
+	if patrol == "table_stats" {
+		if config == nil || config.Patrols == nil || config.Patrols.TableStats == nil {
+			return false
+		}
+		return config.Patrols.TableStats.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -200,6 +407,26 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "bead_changes":
+		if config.Patrols.BeadChanges != nil {
+			return config.Patrols.BeadChanges.Enabled
+		}
+	case "rig_stats":
+		if config.Patrols.RigStats != nil {
+			return config.Patrols.RigStats.Enabled
+		}
+	case "read_only_recovery":
+		if config.Patrols.ReadOnlyRecovery != nil {
+			return config.Patrols.ReadOnlyRecovery.Enabled
+		}
+	case "control_api":
+		if config.Patrols.ControlAPI != nil {
+			return config.Patrols.ControlAPI.Enabled
+		}
+	default:
+		if pc := config.Patrols.Custom[patrol]; pc != nil {
+			return pc.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// defaultUpstreamSyncInterval is how often fork rigs are synced from their
+// upstream.
+const defaultUpstreamSyncInterval = time.Hour
+
+// upstreamSyncInterval returns the configured sync interval for upstream_sync.
+func upstreamSyncInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.UpstreamSync != nil {
+		if config.Patrols.UpstreamSync.Interval > 0 {
+			return config.Patrols.UpstreamSync.Interval
+		}
+	}
+	return defaultUpstreamSyncInterval
+}
+
+// upstreamSyncStrategy returns the configured sync strategy for upstream_sync.
+func upstreamSyncStrategy(config *DaemonPatrolConfig) string {
+	if config != nil && config.Patrols != nil && config.Patrols.UpstreamSync != nil {
+		if config.Patrols.UpstreamSync.Strategy != "" {
+			return config.Patrols.UpstreamSync.Strategy
+		}
+	}
+	return rig.UpstreamSyncFFOnly
+}
+
+// UpstreamSyncPatrol syncs the default branch of every fork rig (one with a
+// push_url) from its upstream, like gt rig sync-upstream. Rigs without a
+// fork are skipped. Syncs are logged to the town events log, and a fork
+// that needs manual attention is reported once when it diverges.
+// It runs as a background goroutine within the daemon.
+type UpstreamSyncPatrol struct {
+	townRoot string
+	interval time.Duration
+	strategy string
+	rigs     func() []string
+	logger   func(format string, args ...interface{})
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	// diverged tracks rigs already reported as diverged.
+	diverged map[string]bool
+
+	// Hooks, replaced in tests.
+	sync func(rigPath, strategy string) (*rig.UpstreamSync, error)
+	emit func(eventType string, payload map[string]interface{})
+}
+
+// NewUpstreamSyncPatrol creates a patrol that syncs every interval using
+// strategy. rigs is called on each pass so newly added rigs are picked up.
+func NewUpstreamSyncPatrol(townRoot string, interval time.Duration, strategy string, rigs func() []string, logger func(format string, args ...interface{})) *UpstreamSyncPatrol {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &UpstreamSyncPatrol{
+		townRoot: townRoot,
+		interval: interval,
+		strategy: strategy,
+		rigs:     rigs,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		diverged: make(map[string]bool),
+		sync: func(rigPath, strategy string) (*rig.UpstreamSync, error) {
+			return rig.SyncFromUpstream(rigPath, strategy, false)
+		},
+		emit: func(eventType string, payload map[string]interface{}) {
+			_ = events.LogAt(townRoot, eventType, "daemon", payload, events.VisibilityFeed)
+		},
+	}
+}
+
+// Start begins the patrol goroutine.
+func (p *UpstreamSyncPatrol) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Stop gracefully stops the patrol.
+func (p *UpstreamSyncPatrol) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *UpstreamSyncPatrol) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.check()
+		}
+	}
+}
+
+// check syncs every fork rig once. A rig whose upstream or fork can't be
+// reached is logged and retried on the next pass.
+func (p *UpstreamSyncPatrol) check() {
+	rigs := p.rigs()
+	sort.Strings(rigs)
+	for _, name := range rigs {
+		if p.ctx.Err() != nil {
+			return
+		}
+		result, err := p.sync(filepath.Join(p.townRoot, name), p.strategy)
+		if errors.Is(err, rig.ErrNoPushURL) {
+			continue
+		}
+		if err != nil {
+			p.logger("upstream_sync: %s: %v", name, err)
+			continue
+		}
+
+		switch result.Status {
+		case rig.UpstreamFastForwarded, rig.UpstreamRebased:
+			delete(p.diverged, name)
+			p.logger("upstream_sync: %s: %s %s (%d upstream commit(s))", name, result.Status, result.Branch, result.Behind)
+			p.emit(events.TypeRigUpstreamSynced, events.RigUpstreamSyncPayload(name, result.Branch, result.Status, result.Ahead, result.Behind, ""))
+		case rig.UpstreamDiverged:
+			if p.diverged[name] {
+				continue
+			}
+			p.diverged[name] = true
+			p.logger("upstream_sync: %s: fork %s diverged from upstream (%d ahead, %d behind); see gt rig sync-upstream %s",
+				name, result.Branch, result.Ahead, result.Behind, name)
+			p.emit(events.TypeRigUpstreamDiverged, events.RigUpstreamSyncPayload(name, result.Branch, result.Status, result.Ahead, result.Behind, result.Error))
+		default:
+			delete(p.diverged, name)
+		}
+	}
+}
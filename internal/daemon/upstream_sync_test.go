@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestUpstreamSyncPatrol_Check(t *testing.T) {
+	townRoot := t.TempDir()
+	var logged int
+	p := NewUpstreamSyncPatrol(townRoot, time.Hour, rig.UpstreamSyncRebase,
+		func() []string { return []string{"plain", "unreachable", "behind", "forked"} },
+		func(string, ...interface{}) { logged++ })
+
+	var synced []string
+	p.sync = func(rigPath, strategy string) (*rig.UpstreamSync, error) {
+		if strategy != rig.UpstreamSyncRebase {
+			t.Errorf("strategy = %q", strategy)
+		}
+		name := filepath.Base(rigPath)
+		synced = append(synced, name)
+		switch name {
+		case "plain":
+			return nil, rig.ErrNoPushURL
+		case "unreachable":
+			return nil, errors.New("could not read from remote")
+		case "behind":
+			return &rig.UpstreamSync{Rig: name, Branch: "main", Behind: 2, Status: rig.UpstreamFastForwarded}, nil
+		}
+		return &rig.UpstreamSync{Rig: name, Branch: "main", Ahead: 1, Behind: 3, Status: rig.UpstreamDiverged, Error: "rebase failed"}, nil
+	}
+	emitted := map[string][]string{}
+	p.emit = func(eventType string, payload map[string]interface{}) {
+		emitted[eventType] = append(emitted[eventType], payload["rig"].(string))
+	}
+
+	p.check()
+
+	if len(synced) != 4 {
+		t.Errorf("synced %v, want all four rigs (errors don't stop the pass)", synced)
+	}
+	if got := emitted[events.TypeRigUpstreamSynced]; len(got) != 1 || got[0] != "behind" {
+		t.Errorf("synced events = %v, want [behind]", got)
+	}
+	if got := emitted[events.TypeRigUpstreamDiverged]; len(got) != 1 || got[0] != "forked" {
+		t.Errorf("diverged events = %v, want [forked]", got)
+	}
+	if logged != 3 {
+		t.Errorf("logged %d lines, want 3 (error, sync, divergence; no-fork rigs are silent)", logged)
+	}
+
+	// A fork that stays diverged is only reported once.
+	p.check()
+	if got := emitted[events.TypeRigUpstreamDiverged]; len(got) != 1 {
+		t.Errorf("diverged events after second pass = %v, want one", got)
+	}
+}
+
+func TestUpstreamSyncConfigDefaults(t *testing.T) {
+	if got := upstreamSyncInterval(nil); got != defaultUpstreamSyncInterval {
+		t.Errorf("default interval = %v", got)
+	}
+	if got := upstreamSyncStrategy(nil); got != rig.UpstreamSyncFFOnly {
+		t.Errorf("default strategy = %q", got)
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{UpstreamSync: &UpstreamSyncConfig{Enabled: true, Interval: 10 * time.Minute, Strategy: rig.UpstreamSyncRebase}}}
+	if got := upstreamSyncInterval(cfg); got != 10*time.Minute {
+		t.Errorf("configured interval = %v, want 10m", got)
+	}
+	if got := upstreamSyncStrategy(cfg); got != rig.UpstreamSyncRebase {
+		t.Errorf("configured strategy = %q", got)
+	}
+}
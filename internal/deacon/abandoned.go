@@ -0,0 +1,172 @@
+package deacon
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// abandonedNudgeTTL is how long the nudge to a former assignee stays
+// queued. The assignee has no session when it is sent, so it must outlive
+// the normal nudge TTL to be seen when the agent comes back.
+const abandonedNudgeTTL = 7 * 24 * time.Hour
+
+// AbandonedBeadConfig holds configurable parameters for abandoned bead detection.
+type AbandonedBeadConfig struct {
+	// MaxIdle is how long an in_progress bead can go without updates, while
+	// its assignee has no session, before it is considered abandoned.
+	MaxIdle time.Duration `json:"max_idle"`
+	// Status is the status abandoned beads are moved to: "open" (default)
+	// returns them to the ready queue, a custom status such as "stalled"
+	// parks them for triage.
+	Status string `json:"status"`
+	// IncludePersistent also reopens beads of crew and other persistent
+	// agents, whose work normally waits for them to restart.
+	IncludePersistent bool `json:"include_persistent"`
+	// DryRun if true, only reports what would be done without making changes.
+	DryRun bool `json:"dry_run"`
+}
+
+// DefaultAbandonedBeadConfig returns the default abandoned bead config.
+func DefaultAbandonedBeadConfig() *AbandonedBeadConfig {
+	return &AbandonedBeadConfig{
+		MaxIdle: 24 * time.Hour,
+		Status:  "open",
+	}
+}
+
+// AbandonedBeadResult represents one abandoned in_progress bead.
+type AbandonedBeadResult struct {
+	BeadID   string `json:"bead_id"`
+	Title    string `json:"title"`
+	Assignee string `json:"assignee"`
+	Idle     string `json:"idle"`
+	Reason   string `json:"reason"`
+	Reopened bool   `json:"reopened"`
+	Nudged   bool   `json:"nudged"`
+	Error    string `json:"error,omitempty"`
+
+	session string // assignee's session, "" when unknown
+}
+
+// AbandonedBeadScanResult contains the results of an abandoned bead scan
+// of one beads database.
+type AbandonedBeadScanResult struct {
+	ScannedAt       time.Time              `json:"scanned_at"`
+	TotalInProgress int                    `json:"total_in_progress"`
+	AbandonedCount  int                    `json:"abandoned_count"`
+	Reopened        int                    `json:"reopened"`
+	Results         []*AbandonedBeadResult `json:"results"`
+}
+
+// ScanAbandonedBeads finds in_progress beads of rigName (the town's own
+// beads when empty) whose assignee has no session and which have not been
+// updated for MaxIdle, and moves them to cfg.Status with the assignee
+// cleared. The reason is recorded as a comment on the bead and the former
+// assignee is sent a queued nudge.
+func ScanAbandonedBeads(townRoot, rigName string, cfg *AbandonedBeadConfig) (*AbandonedBeadScanResult, error) {
+	if cfg == nil {
+		cfg = DefaultAbandonedBeadConfig()
+	}
+	status := cfg.Status
+	if status == "" {
+		status = "open"
+	}
+
+	bd := beads.New(filepath.Join(townRoot, rigName))
+	issues, err := bd.List(beads.ListOptions{Status: "in_progress", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing in_progress beads: %w", err)
+	}
+
+	// Sessions live in the town's multiplexer; asking tmux in a town that
+	// runs another one would report every assignee dead.
+	mux, err := multiplexer.ForTown(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("resolving multiplexer: %w", err)
+	}
+	now := time.Now()
+	abandoned := findAbandoned(issues, now, cfg, func(name string) bool {
+		alive, err := mux.HasSession(name)
+		return alive || err != nil // can't tell: leave it alone
+	})
+
+	result := &AbandonedBeadScanResult{
+		ScannedAt:       now.UTC(),
+		TotalInProgress: len(issues),
+		AbandonedCount:  len(abandoned),
+		Results:         abandoned,
+	}
+	if cfg.DryRun {
+		return result, nil
+	}
+
+	empty := ""
+	for _, r := range abandoned {
+		if err := bd.Update(r.BeadID, beads.UpdateOptions{Status: &status, Assignee: &empty}); err != nil {
+			r.Error = err.Error()
+			continue
+		}
+		r.Reopened = true
+		result.Reopened++
+		_, _ = bd.Run("comment", r.BeadID, fmt.Sprintf("Moved to %s by deacon: %s", status, r.Reason))
+
+		if r.session != "" {
+			msg := fmt.Sprintf("Your bead %s (%q) was moved from in_progress to %s: %s. Re-claim it if you are still working on it.",
+				r.BeadID, r.Title, status, r.Reason)
+			err := nudge.Enqueue(townRoot, r.session, nudge.QueuedNudge{
+				Sender:    "deacon",
+				Message:   msg,
+				ExpiresAt: now.Add(abandonedNudgeTTL),
+			})
+			r.Nudged = err == nil
+		}
+	}
+	return result, nil
+}
+
+// findAbandoned picks the abandoned beads out of issues. hasSession reports
+// whether a tmux session is alive.
+func findAbandoned(issues []*beads.Issue, now time.Time, cfg *AbandonedBeadConfig, hasSession func(string) bool) []*AbandonedBeadResult {
+	var results []*AbandonedBeadResult
+	for _, issue := range issues {
+		updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+		if err != nil {
+			continue // can't age it
+		}
+		idle := now.Sub(updated)
+		if idle < cfg.MaxIdle {
+			continue
+		}
+
+		r := &AbandonedBeadResult{
+			BeadID:   issue.ID,
+			Title:    issue.Title,
+			Assignee: issue.Assignee,
+			Idle:     idle.Round(time.Minute).String(),
+		}
+		switch assignee, err := session.ParseAssignee(issue.Assignee); {
+		case issue.Assignee == "":
+			r.Reason = fmt.Sprintf("no assignee and no updates for %s", r.Idle)
+		case err != nil:
+			// Unknown assignee format: fall back to age alone
+			r.Reason = fmt.Sprintf("unrecognized assignee %s and no updates for %s", issue.Assignee, r.Idle)
+		default:
+			if assignee.IsPersistent() && !cfg.IncludePersistent {
+				continue
+			}
+			if hasSession(assignee.SessionName()) {
+				continue
+			}
+			r.session = assignee.SessionName()
+			r.Reason = fmt.Sprintf("%s has no session and no updates for %s", issue.Assignee, r.Idle)
+		}
+		results = append(results, r)
+	}
+	return results
+}
@@ -0,0 +1,61 @@
+package deacon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestFindAbandoned(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-30 * time.Hour).Format(time.RFC3339)
+	recent := now.Add(-time.Hour).Format(time.RFC3339)
+
+	issues := []*beads.Issue{
+		{ID: "gt-dead", Assignee: "gastown/polecats/Toast", UpdatedAt: old},
+		{ID: "gt-alive", Assignee: "gastown/polecats/Nux", UpdatedAt: old},
+		{ID: "gt-recent", Assignee: "gastown/polecats/Toast", UpdatedAt: recent},
+		{ID: "gt-crew", Assignee: "gastown/crew/max", UpdatedAt: old},
+		{ID: "gt-nobody", UpdatedAt: old},
+		{ID: "gt-weird", Assignee: "a/b/c/d", UpdatedAt: old},
+		{ID: "gt-undated", Assignee: "gastown/polecats/Toast", UpdatedAt: "garbage"},
+	}
+	alive := func(name string) bool { return name == "gt-Nux" }
+
+	cfg := &AbandonedBeadConfig{MaxIdle: 24 * time.Hour}
+	got := map[string]*AbandonedBeadResult{}
+	for _, r := range findAbandoned(issues, now, cfg, alive) {
+		got[r.BeadID] = r
+	}
+	for _, id := range []string{"gt-dead", "gt-nobody", "gt-weird"} {
+		if got[id] == nil {
+			t.Errorf("%s not reported abandoned", id)
+		}
+	}
+	for _, id := range []string{"gt-alive", "gt-recent", "gt-crew", "gt-undated"} {
+		if got[id] != nil {
+			t.Errorf("%s reported abandoned: %+v", id, got[id])
+		}
+	}
+	if r := got["gt-dead"]; r != nil {
+		if r.session != "gt-Toast" {
+			t.Errorf("gt-dead session = %q, want gt-Toast", r.session)
+		}
+		if r.Idle != "30h0m0s" {
+			t.Errorf("gt-dead idle = %q", r.Idle)
+		}
+	}
+	if r := got["gt-nobody"]; r != nil && r.session != "" {
+		t.Errorf("unassigned bead has session %q", r.session)
+	}
+
+	cfg.IncludePersistent = true
+	found := false
+	for _, r := range findAbandoned(issues, now, cfg, alive) {
+		found = found || r.BeadID == "gt-crew"
+	}
+	if !found {
+		t.Error("gt-crew not reported with IncludePersistent")
+	}
+}
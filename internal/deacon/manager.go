@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -26,6 +27,7 @@ type tmuxOps interface {
 	IsAgentAlive(session string) bool
 	KillSessionWithProcesses(name string) error
 	NewSessionWithCommand(name, workDir, command string) error
+	WithSecrets(command string, secrets map[string]string) (string, error)
 	SetRemainOnExit(pane string, on bool) error
 	SetEnvironment(session, key, value string) error
 	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
@@ -40,14 +42,22 @@ type tmuxOps interface {
 type Manager struct {
 	townRoot string
 	tmux     tmuxOps
+
+	// mux is the town's multiplexer when it is not tmux; nil under tmux.
+	mux multiplexer.Multiplexer
 }
 
 // NewManager creates a new deacon manager for a town.
 func NewManager(townRoot string) *Manager {
-	return &Manager{
+	t := tmux.NewTmux()
+	m := &Manager{
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		tmux:     t,
+	}
+	if mux := multiplexer.ForTownWith(townRoot, t); mux != multiplexer.Multiplexer(t) {
+		m.mux = mux
 	}
+	return m
 }
 
 // SessionName returns the tmux session name for the deacon.
@@ -74,10 +84,11 @@ func (m *Manager) Start(agentOverride string) error {
 	sessionID := m.SessionName()
 
 	// Check if session already exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := m.hasSession(sessionID)
 	if running {
 		// Session exists - check if agent is actually running (healthy vs zombie)
-		if t.IsAgentAlive(sessionID) {
+		if m.mux != nil || t.IsAgentAlive(sessionID) {
+			// Outside tmux the session ends with the agent.
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but agent dead. Kill and recreate.
@@ -104,13 +115,39 @@ func (m *Manager) Start(agentOverride string) error {
 		Sender:    "daemon",
 		Topic:     "patrol",
 	}, "I am Deacon. Start patrol: run gt deacon heartbeat, then check gt hook. If no hook, create mol-deacon-patrol wisp and execute it.")
+	secrets, err := config.StartupSecrets("deacon", m.townRoot, "", agentOverride)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
 	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("deacon", "", m.townRoot, "", initialPrompt, agentOverride)
 	if err != nil {
 		return fmt.Errorf("building startup command: %w", err)
 	}
 
+	// Use centralized AgentEnv for consistency across all role startup paths
+	envVars := config.AgentEnv(config.AgentEnvConfig{
+		Role:     "deacon",
+		TownRoot: m.townRoot,
+		Agent:    agentOverride,
+	})
+
+	if m.mux != nil {
+		// Other backends take the whole environment at creation and have
+		// no theme, pane or respawn hook; the daemon's heartbeat restarts
+		// the deacon if it exits.
+		if err := multiplexer.NewSessionWithSecrets(m.mux, sessionID, deaconDir, startupCmd, envVars, secrets); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	// Secrets reach the agent's environment, never an argv.
+	startupCmd, err = t.WithSecrets(startupCmd, secrets)
+	if err != nil {
+		return err
+	}
 	if err := t.NewSessionWithCommand(sessionID, deaconDir, startupCmd); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -121,12 +158,6 @@ func (m *Manager) Start(agentOverride string) error {
 	_ = t.SetRemainOnExit(sessionID, true)
 
 	// Set environment variables (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:     "deacon",
-		TownRoot: m.townRoot,
-		Agent:    agentOverride,
-	})
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
@@ -171,7 +202,7 @@ func (m *Manager) Stop() error {
 	sessionID := m.SessionName()
 
 	// Check if session exists
-	running, err := t.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -179,6 +210,13 @@ func (m *Manager) Stop() error {
 		return ErrNotRunning
 	}
 
+	if m.mux != nil {
+		if err := m.mux.KillSessionWithProcesses(sessionID); err != nil {
+			return fmt.Errorf("killing session: %w", err)
+		}
+		return nil
+	}
+
 	// Try graceful shutdown first (best-effort interrupt)
 	_ = t.SendKeysRaw(sessionID, "C-c")
 	time.Sleep(100 * time.Millisecond)
@@ -195,7 +233,16 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the deacon session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	return m.tmux.HasSession(m.SessionName())
+	return m.hasSession(m.SessionName())
+}
+
+// hasSession reports whether the deacon session exists in the town's
+// multiplexer.
+func (m *Manager) hasSession(name string) (bool, error) {
+	if m.mux != nil {
+		return m.mux.HasSession(name)
+	}
+	return m.tmux.HasSession(name)
 }
 
 // Status returns information about the deacon session.
@@ -203,7 +250,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 	t := m.tmux
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -211,5 +258,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
+	if m.mux != nil {
+		return &tmux.SessionInfo{Name: sessionID}, nil
+	}
 	return t.GetSessionInfo(sessionID)
 }
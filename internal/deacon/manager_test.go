@@ -39,6 +39,10 @@ func (m *mockTmux) KillSessionWithProcesses(name string) error {
 	return m.killErr
 }
 
+func (m *mockTmux) WithSecrets(command string, _ map[string]string) (string, error) {
+	return command, nil
+}
+
 func (m *mockTmux) NewSessionWithCommand(_, _, _ string) error {
 	m.newSessionCalls++
 	return m.newSessionErr
@@ -104,9 +104,8 @@ func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult
 		// Check if assignee agent is still alive (regardless of age)
 		sessionChecked := false
 		if bead.Assignee != "" {
-			sessionName := assigneeToSessionName(bead.Assignee)
-			if sessionName != "" {
-				alive, _ := t.HasSession(sessionName)
+			if assignee, err := session.ParseAssignee(bead.Assignee); err == nil {
+				alive, _ := t.HasSession(assignee.SessionName())
 				hookResult.AgentAlive = alive
 				sessionChecked = true
 			}
@@ -178,16 +177,6 @@ func listHookedBeads(townRoot string) ([]*HookedBead, error) {
 	return beads, nil
 }
 
-// assigneeToSessionName converts an assignee address to a tmux session name.
-// Delegates to session.ParseAddress for consistent parsing across the codebase.
-func assigneeToSessionName(assignee string) string {
-	identity, err := session.ParseAddress(assignee)
-	if err != nil {
-		return ""
-	}
-	return identity.SessionName()
-}
-
 // checkWorktreeState checks an agent's worktree for uncommitted changes or
 // unpushed commits and populates the result fields. This is best-effort;
 // errors are recorded but do not prevent unhooking.
@@ -213,17 +202,22 @@ func checkWorktreeState(townRoot, assignee string, result *StaleHookResult) {
 
 // assigneeToWorktreePath resolves an assignee address to its git worktree path.
 // Returns "" if the assignee format is unrecognized or the worktree doesn't exist.
-// Supports polecat format "rig/polecats/name" and crew format "rig/crew/name".
+// Only polecats and crew have worktrees.
 func assigneeToWorktreePath(townRoot, assignee string) string {
-	parts := strings.Split(assignee, "/")
-	if len(parts) != 3 {
+	a, err := session.ParseAssignee(assignee)
+	if err != nil {
 		return ""
 	}
-
-	rigName, agentType, name := parts[0], parts[1], parts[2]
-	if agentType != "polecats" && agentType != "crew" {
+	var agentType string
+	switch a.Role() {
+	case session.RolePolecat:
+		agentType = "polecats"
+	case session.RoleCrew:
+		agentType = "crew"
+	default:
 		return ""
 	}
+	rigName, name := a.Rig(), a.Name()
 
 	rigPath := filepath.Join(townRoot, rigName)
 
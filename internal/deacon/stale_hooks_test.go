@@ -7,33 +7,6 @@ import (
 	"testing"
 )
 
-func TestAssigneeToSessionName(t *testing.T) {
-	tests := []struct {
-		assignee string
-		want     string
-	}{
-		{"deacon", "hq-deacon"},
-		{"mayor", "hq-mayor"},
-		{"gastown/witness", "gt-witness"},
-		{"gastown/refinery", "gt-refinery"},
-		{"gastown/polecats/max", "gt-max"},
-		{"gastown/crew/joe", "gt-crew-joe"},
-		{"", ""},
-		{"unknown", ""},
-		{"gastown/unknown/agent", ""},
-		{"a/b/c/d", ""},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.assignee, func(t *testing.T) {
-			got := assigneeToSessionName(tt.assignee)
-			if got != tt.want {
-				t.Errorf("assigneeToSessionName(%q) = %q, want %q", tt.assignee, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestAssigneeToWorktreePath_InvalidFormats(t *testing.T) {
 	townRoot := t.TempDir()
 
@@ -53,7 +53,7 @@ func CheckBeads() (BeadsStatus, string) {
 	}
 
 	// Compare versions
-	if compareVersions(version, MinBeadsVersion) < 0 {
+	if CompareVersions(version, MinBeadsVersion) < 0 {
 		return BeadsTooOld, version
 	}
 
@@ -122,9 +122,9 @@ func parseBeadsVersion(output string) string {
 	return ""
 }
 
-// compareVersions compares two semver strings.
+// CompareVersions compares two semver strings.
 // Returns -1 if a < b, 0 if a == b, 1 if a > b.
-func compareVersions(a, b string) int {
+func CompareVersions(a, b string) int {
 	aParts := parseVersion(a)
 	bParts := parseVersion(b)
 
@@ -0,0 +1,88 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// DoltSchemaCheck detects schema drift: rig databases whose beads schema is
+// older than the installed bd expects (usually after a bd upgrade), or newer
+// than it understands. Either makes bd queries fail with confusing errors.
+type DoltSchemaCheck struct {
+	BaseCheck
+}
+
+// NewDoltSchemaCheck creates a check for schema drift between bd and the
+// rig databases.
+func NewDoltSchemaCheck() *DoltSchemaCheck {
+	return &DoltSchemaCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "dolt-schema",
+			CheckDescription: "Check rig database schemas match the installed bd",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run compares each rig database's recorded schema version with bd's.
+func (c *DoltSchemaCheck) Run(ctx *CheckContext) *CheckResult {
+	if running, _, _ := doltserver.IsRunning(ctx.TownRoot); !running {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "Dolt server not running (schema check skipped)",
+			Category: c.CheckCategory,
+		}
+	}
+
+	var bdVersion string
+	if status, version := deps.CheckBeads(); status == deps.BeadsOK || status == deps.BeadsTooOld {
+		bdVersion = version
+	}
+	statuses, err := doltserver.CheckSchemas(ctx.TownRoot, bdVersion)
+	if err != nil {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusWarning,
+			Message:  fmt.Sprintf("Could not check database schemas: %v", err),
+			Category: c.CheckCategory,
+		}
+	}
+
+	var details []string
+	var behind, ahead int
+	for _, s := range statuses {
+		switch s.State {
+		case doltserver.SchemaBehind:
+			behind++
+			details = append(details, fmt.Sprintf("%s: schema %s, expected %s", s.Rig, s.Version, s.Expected))
+		case doltserver.SchemaAhead:
+			ahead++
+			details = append(details, fmt.Sprintf("%s: schema %s is newer than bd %s", s.Rig, s.Version, s.Expected))
+		}
+	}
+
+	if behind == 0 && ahead == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  fmt.Sprintf("%d database schema(s) match bd", len(statuses)),
+			Category: c.CheckCategory,
+		}
+	}
+
+	hint := "Run 'gt dolt upgrade-schema --rig <rig>' for each database behind"
+	if behind == 0 {
+		hint = fmt.Sprintf("Upgrade bd: go install %s", deps.BeadsInstallPath)
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusWarning,
+		Message:  fmt.Sprintf("%d database(s) with schema drift", behind+ahead),
+		Details:  details,
+		FixHint:  hint,
+		Category: c.CheckCategory,
+	}
+}
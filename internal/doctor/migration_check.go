@@ -1,15 +1,16 @@
 package doctor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -228,10 +229,11 @@ func loadRigNames(rigsPath string) map[string]struct{} {
 	return rigs
 }
 
-// DoltServerReachableCheck detects the split-brain risk: metadata.json says
-// dolt_mode=server but the Dolt server is not actually accepting connections.
-// In this state, bd commands may silently create isolated local databases
-// instead of connecting to the centralized server.
+// DoltServerReachableCheck detects split-brain risk: conditions under which
+// bd could read or write a different copy of a rig's beads than other agents.
+// Each rig's storage backend (from metadata.json) reports its own risks; for
+// Dolt server mode that is an unreachable server, in which case bd commands
+// may silently create isolated local databases.
 type DoltServerReachableCheck struct {
 	BaseCheck
 }
@@ -241,51 +243,68 @@ func NewDoltServerReachableCheck() *DoltServerReachableCheck {
 	return &DoltServerReachableCheck{
 		BaseCheck: BaseCheck{
 			CheckName:        "dolt-server-reachable",
-			CheckDescription: "Check that Dolt server is reachable when server mode is configured",
+			CheckDescription: "Check that each rig's beads backend is reachable (split-brain risk)",
 			CheckCategory:    CategoryInfrastructure,
 		},
 	}
 }
 
-// Run checks if any rig has server-mode metadata but the server is unreachable.
+// Run asks every rig's beads backend for split-brain risks.
 func (c *DoltServerReachableCheck) Run(ctx *CheckContext) *CheckResult {
-	// Find rigs configured for server mode, grouped by server address
-	rigsByAddr := c.findServerModeRigsByAddr(ctx.TownRoot)
-	if len(rigsByAddr) == 0 {
+	backends := c.findRigBackends(ctx.TownRoot)
+	if len(backends) == 0 {
 		return &CheckResult{
 			Name:     c.Name(),
 			Status:   StatusOK,
-			Message:  "No rigs configured for Dolt server mode",
+			Message:  "No rigs with a beads backend configured",
 			Category: c.CheckCategory,
 		}
 	}
 
-	// Check connectivity to each unique server address
-	var unreachable []string
+	probeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Group rigs by backend location so a shared server is probed and
+	// reported once.
+	type group struct {
+		backend beads.Backend
+		rigs    []string
+	}
+	groups := map[string]*group{}
+	var locations []string
+	for _, rb := range backends {
+		loc := rb.backend.Name() + " " + rb.backend.Location()
+		g, ok := groups[loc]
+		if !ok {
+			g = &group{backend: rb.backend}
+			groups[loc] = g
+			locations = append(locations, loc)
+		}
+		g.rigs = append(g.rigs, rb.rig)
+	}
+	sort.Strings(locations)
+
 	var details []string
-	totalRigs := 0
-	unreachableRigs := 0
-	for addr, rigs := range rigsByAddr {
-		totalRigs += len(rigs)
-		conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
-		if err != nil {
-			unreachable = append(unreachable, addr)
-			unreachableRigs += len(rigs)
-			details = append(details, fmt.Sprintf("Server %s unreachable (rigs: %s)", addr, strings.Join(rigs, ", ")))
-		} else {
-			_ = conn.Close()
+	atRisk := 0
+	for _, loc := range locations {
+		g := groups[loc]
+		risks := g.backend.SplitBrainRisks(probeCtx)
+		if len(risks) == 0 {
+			continue
+		}
+		atRisk += len(g.rigs)
+		for _, risk := range risks {
+			details = append(details, fmt.Sprintf("%s (rigs: %s)", risk, strings.Join(g.rigs, ", ")))
 		}
 	}
 
-	if len(unreachable) > 0 {
+	if atRisk > 0 {
 		return &CheckResult{
-			Name:   c.Name(),
-			Status: StatusError,
-			Message: fmt.Sprintf("SPLIT-BRAIN RISK: %d rig(s) configured for Dolt server mode but server unreachable at %s",
-				unreachableRigs, strings.Join(unreachable, ", ")),
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("SPLIT-BRAIN RISK: %d rig(s) may read or write an isolated copy of their beads", atRisk),
 			Details: append(details,
-				"bd commands will fail or create isolated local databases",
-				"This is the split-brain scenario — data written now may be invisible to the server later",
+				"This is the split-brain scenario — data written now may be invisible to other agents later",
 			),
 			FixHint:  "Check dolt server connectivity or run 'gt dolt start' for local server",
 			Category: c.CheckCategory,
@@ -295,68 +314,66 @@ func (c *DoltServerReachableCheck) Run(ctx *CheckContext) *CheckResult {
 	return &CheckResult{
 		Name:     c.Name(),
 		Status:   StatusOK,
-		Message:  fmt.Sprintf("Dolt server reachable (%d rig(s) in server mode)", totalRigs),
+		Message:  fmt.Sprintf("Beads backends healthy (%d rig(s), %s)", len(backends), strings.Join(backendSummary(backends), ", ")),
 		Category: c.CheckCategory,
 	}
 }
 
-// findServerModeRigsByAddr returns rig names grouped by their configured server address.
-// Rigs without explicit host/port fall back to the default local server (127.0.0.1:3307).
-func (c *DoltServerReachableCheck) findServerModeRigsByAddr(townRoot string) map[string][]string {
-	result := make(map[string][]string)
+// rigBackend pairs a rig name with its beads storage backend.
+type rigBackend struct {
+	rig     string
+	backend beads.Backend
+}
 
-	// Check town-level beads (hq)
-	townBeadsDir := filepath.Join(townRoot, ".beads")
-	if addr, ok := c.getServerAddr(townBeadsDir); ok {
-		result[addr] = append(result[addr], "hq")
+// findRigBackends opens the backend for the town beads (hq) and every rig
+// that has metadata.json. Rigs without one are skipped; DoltMetadataCheck
+// reports those.
+func (c *DoltServerReachableCheck) findRigBackends(townRoot string) []rigBackend {
+	var result []rigBackend
+	add := func(rig, beadsDir string) {
+		if _, err := beads.ReadBackendMetadata(beadsDir); err != nil {
+			return
+		}
+		if b, err := beads.OpenBackend(beadsDir); err == nil {
+			result = append(result, rigBackend{rig: rig, backend: b})
+		}
 	}
 
-	// Check rig-level beads
-	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
-	rigs := loadRigNames(rigsPath)
-	for rigName := range rigs {
+	add("hq", filepath.Join(townRoot, ".beads"))
+
+	rigNames := loadRigNames(filepath.Join(townRoot, "mayor", "rigs.json"))
+	sorted := make([]string, 0, len(rigNames))
+	for name := range rigNames {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, rigName := range sorted {
 		// Check mayor/rig/.beads first (canonical), then rig/.beads
 		beadsDir := filepath.Join(townRoot, rigName, "mayor", "rig", ".beads")
 		if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
 			beadsDir = filepath.Join(townRoot, rigName, ".beads")
 		}
-		if addr, ok := c.getServerAddr(beadsDir); ok {
-			result[addr] = append(result[addr], rigName)
-		}
+		add(rigName, beadsDir)
 	}
-
 	return result
 }
 
-// getServerAddr reads metadata.json and returns the configured server address if dolt_mode is "server".
-// Returns the address string (host:port) and true if server mode is configured.
-func (c *DoltServerReachableCheck) getServerAddr(beadsDir string) (string, bool) {
-	metadataPath := filepath.Join(beadsDir, "metadata.json")
-	data, err := os.ReadFile(metadataPath)
-	if err != nil {
-		return "", false
+// backendSummary counts rigs per backend, e.g. ["dolt: 3", "sqlite: 1"].
+func backendSummary(backends []rigBackend) []string {
+	counts := map[string]int{}
+	for _, rb := range backends {
+		counts[rb.backend.Name()]++
 	}
-	var metadata struct {
-		DoltMode       string `json:"dolt_mode"`
-		DoltServerHost string `json:"dolt_server_host"`
-		DoltServerPort int    `json:"dolt_server_port"`
-	}
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		return "", false
-	}
-	if metadata.DoltMode != "server" {
-		return "", false
-	}
-
-	host := metadata.DoltServerHost
-	port := metadata.DoltServerPort
-	if host == "" {
-		host = "127.0.0.1"
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
 	}
-	if port == 0 {
-		port = doltserver.DefaultPort
+	sort.Strings(names)
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = fmt.Sprintf("%s: %d", name, counts[name])
 	}
-	return net.JoinHostPort(host, strconv.Itoa(port)), true
+	return out
 }
 
 // DoltOrphanedDatabaseCheck detects databases in .dolt-data/ that are not
@@ -2,8 +2,10 @@ package doctor
 
 import (
 	"encoding/json"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -94,94 +96,6 @@ func setupRigsJSON(t *testing.T, townRoot string, rigNames []string) {
 	}
 }
 
-func TestGetServerAddr(t *testing.T) {
-	check := NewDoltServerReachableCheck()
-
-	tests := []struct {
-		name     string
-		host     string
-		port     int
-		wantAddr string
-		wantOK   bool
-	}{
-		{
-			name:     "defaults to 127.0.0.1:3307",
-			wantAddr: "127.0.0.1:3307",
-			wantOK:   true,
-		},
-		{
-			name:     "explicit IPv4 host and port",
-			host:     "10.0.0.5",
-			port:     3308,
-			wantAddr: "10.0.0.5:3308",
-			wantOK:   true,
-		},
-		{
-			name:     "IPv6 host gets bracketed",
-			host:     "::1",
-			wantAddr: "[::1]:3307",
-			wantOK:   true,
-		},
-		{
-			name:     "IPv6 host with explicit port",
-			host:     "::1",
-			port:     3309,
-			wantAddr: "[::1]:3309",
-			wantOK:   true,
-		},
-		{
-			name:     "explicit host with default port",
-			host:     "dolt.example.com",
-			wantAddr: "dolt.example.com:3307",
-			wantOK:   true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			beadsDir := filepath.Join(t.TempDir(), ".beads")
-			setupServerMetadata(t, beadsDir, tt.host, tt.port)
-
-			addr, ok := check.getServerAddr(beadsDir)
-			if ok != tt.wantOK {
-				t.Fatalf("getServerAddr() ok = %v, want %v", ok, tt.wantOK)
-			}
-			if addr != tt.wantAddr {
-				t.Errorf("getServerAddr() = %q, want %q", addr, tt.wantAddr)
-			}
-		})
-	}
-}
-
-func TestGetServerAddr_NotServerMode(t *testing.T) {
-	check := NewDoltServerReachableCheck()
-	beadsDir := filepath.Join(t.TempDir(), ".beads")
-	if err := os.MkdirAll(beadsDir, 0755); err != nil {
-		t.Fatal(err)
-	}
-	meta := map[string]interface{}{
-		"backend":   "dolt",
-		"dolt_mode": "local",
-	}
-	data, _ := json.Marshal(meta)
-	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), data, 0644); err != nil {
-		t.Fatal(err)
-	}
-
-	_, ok := check.getServerAddr(beadsDir)
-	if ok {
-		t.Error("getServerAddr() returned ok=true for local mode, want false")
-	}
-}
-
-func TestGetServerAddr_NoMetadata(t *testing.T) {
-	check := NewDoltServerReachableCheck()
-	_, ok := check.getServerAddr(filepath.Join(t.TempDir(), "nonexistent"))
-	if ok {
-		t.Error("getServerAddr() returned ok=true for missing metadata, want false")
-	}
-}
-
 func TestDoltOrphanedDatabaseCheck_NoOrphans(t *testing.T) {
 	townRoot := t.TempDir()
 
@@ -297,3 +211,38 @@ func TestDoltOrphanedDatabaseCheck_Name(t *testing.T) {
 		t.Errorf("expected name 'dolt-orphaned-databases', got %q", check.Name())
 	}
 }
+
+func TestDoltServerReachableCheck_UnreachableServer(t *testing.T) {
+	townRoot := t.TempDir()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+
+	setupRigsJSON(t, townRoot, []string{"gastown", "beads"})
+	setupServerMetadata(t, filepath.Join(townRoot, ".beads"), "127.0.0.1", port)
+	setupServerMetadata(t, filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads"), "127.0.0.1", port)
+
+	check := NewDoltServerReachableCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError {
+		t.Fatalf("expected StatusError, got %v: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(result.Message, "2 rig(s)") {
+		t.Errorf("message = %q, want 2 rigs at risk", result.Message)
+	}
+	if len(result.Details) == 0 || !strings.Contains(result.Details[0], "hq, gastown") {
+		t.Errorf("details = %v, want rigs grouped by server", result.Details)
+	}
+}
+
+func TestDoltServerReachableCheck_NoBackends(t *testing.T) {
+	check := NewDoltServerReachableCheck()
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK, got %v: %s", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,79 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/lock"
+)
+
+// StaleLocksCheck finds PID and lock files of the daemon and Dolt server
+// whose holder is gone, as left behind by crashes.
+type StaleLocksCheck struct {
+	FixableCheck
+}
+
+// NewStaleLocksCheck creates a check for stale daemon and Dolt lock files.
+func NewStaleLocksCheck() *StaleLocksCheck {
+	return &StaleLocksCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "stale-locks",
+				CheckDescription: "Detect stale daemon/Dolt PID and lock files",
+				CheckCategory:    CategoryCleanup,
+			},
+		},
+	}
+}
+
+// TownLockArtifacts lists the town's daemon and Dolt lock artifacts.
+func TownLockArtifacts(townRoot string) []lock.Artifact {
+	return lock.TownArtifacts(townRoot, doltserver.DefaultConfig(townRoot).DataDir)
+}
+
+// Run inspects each artifact without removing anything.
+func (c *StaleLocksCheck) Run(ctx *CheckContext) *CheckResult {
+	var details []string
+	for _, r := range lock.Sweep(TownLockArtifacts(ctx.TownRoot), false) {
+		if r.Stale && !r.Keep {
+			rel, err := filepath.Rel(ctx.TownRoot, r.Path)
+			if err != nil {
+				rel = r.Path
+			}
+			details = append(details, fmt.Sprintf("%s: %s", rel, r.Reason))
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No stale PID or lock files",
+			Category: c.Category(),
+		}
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusWarning,
+		Message:  fmt.Sprintf("%d stale PID/lock file(s)", len(details)),
+		Details:  details,
+		FixHint:  "Run 'gt doctor --sweep-locks' or 'gt doctor --fix' to remove them",
+		Category: c.Category(),
+	}
+}
+
+// Fix removes the stale artifacts, re-checking each one first.
+func (c *StaleLocksCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	for _, r := range lock.Sweep(TownLockArtifacts(ctx.TownRoot), true) {
+		if r.Err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", r.Path, r.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("removing stale locks: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
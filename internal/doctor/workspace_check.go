@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // TownConfigExistsCheck verifies mayor/town.json exists.
@@ -27,7 +29,7 @@ func NewTownConfigExistsCheck() *TownConfigExistsCheck {
 func (c *TownConfigExistsCheck) Run(ctx *CheckContext) *CheckResult {
 	configPath := filepath.Join(ctx.TownRoot, "mayor", "town.json")
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	if !config.ConfigExists(configPath) {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -70,7 +72,7 @@ type townConfig struct {
 func (c *TownConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	configPath := filepath.Join(ctx.TownRoot, "mayor", "town.json")
 
-	data, err := os.ReadFile(configPath)
+	data, err := config.ReadConfigFile(configPath)
 	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -80,8 +82,8 @@ func (c *TownConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	var config townConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	var cfg townConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -93,13 +95,13 @@ func (c *TownConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 
 	var issues []string
 
-	if config.Type != "town" {
-		issues = append(issues, fmt.Sprintf("type should be 'town', got '%s'", config.Type))
+	if cfg.Type != "town" {
+		issues = append(issues, fmt.Sprintf("type should be 'town', got '%s'", cfg.Type))
 	}
-	if config.Version == 0 {
+	if cfg.Version == 0 {
 		issues = append(issues, "version field is missing or zero")
 	}
-	if config.Name == "" {
+	if cfg.Name == "" {
 		issues = append(issues, "name field is missing or empty")
 	}
 
@@ -116,7 +118,7 @@ func (c *TownConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	return &CheckResult{
 		Name:    c.Name(),
 		Status:  StatusOK,
-		Message: fmt.Sprintf("mayor/town.json valid (name=%s, version=%d)", config.Name, config.Version),
+		Message: fmt.Sprintf("mayor/town.json valid (name=%s, version=%d)", cfg.Name, cfg.Version),
 	}
 }
 
@@ -142,7 +144,7 @@ func NewRigsRegistryExistsCheck() *RigsRegistryExistsCheck {
 func (c *RigsRegistryExistsCheck) Run(ctx *CheckContext) *CheckResult {
 	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
 
-	if _, err := os.Stat(rigsPath); os.IsNotExist(err) {
+	if !config.ConfigExists(rigsPath) {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusWarning,
@@ -207,7 +209,7 @@ type rigsConfig struct {
 func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
 
-	data, err := os.ReadFile(rigsPath)
+	data, err := config.ReadConfigFile(rigsPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &CheckResult{
@@ -224,8 +226,8 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	var config rigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	var cfg rigsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -235,7 +237,7 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	if len(config.Rigs) == 0 {
+	if len(cfg.Rigs) == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
@@ -247,7 +249,7 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 	var missing []string
 	var found int
 
-	for rigName := range config.Rigs {
+	for rigName := range cfg.Rigs {
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
 		if _, err := os.Stat(rigPath); os.IsNotExist(err) {
 			missing = append(missing, rigName)
@@ -268,7 +270,7 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusWarning,
-			Message: fmt.Sprintf("%d of %d registered rig(s) missing", len(missing), len(config.Rigs)),
+			Message: fmt.Sprintf("%d of %d registered rig(s) missing", len(missing), len(cfg.Rigs)),
 			Details: details,
 			FixHint: "Run 'gt doctor --fix' to remove missing rigs from registry",
 		}
@@ -287,25 +289,25 @@ func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	rigsPath, format := config.ResolveConfigPath(filepath.Join(ctx.TownRoot, "mayor", "rigs.json"))
 
-	data, err := os.ReadFile(rigsPath)
+	data, err := config.ReadConfigFile(rigsPath)
 	if err != nil {
 		return fmt.Errorf("reading rigs.json: %w", err)
 	}
 
-	var config rigsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	var cfg rigsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
 		return fmt.Errorf("parsing rigs.json: %w", err)
 	}
 
 	// Remove missing rigs
 	for _, rig := range c.missingRigs {
-		delete(config.Rigs, rig)
+		delete(cfg.Rigs, rig)
 	}
 
-	// Write back
-	newData, err := json.MarshalIndent(config, "", "  ")
+	// Write back in the format it was read from
+	newData, err := config.MarshalConfig(cfg, format)
 	if err != nil {
 		return fmt.Errorf("marshaling rigs.json: %w", err)
 	}
@@ -357,7 +359,7 @@ func (c *MayorExistsCheck) Run(ctx *CheckContext) *CheckResult {
 
 	for _, f := range expectedFiles {
 		path := filepath.Join(mayorPath, f)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
+		if !config.ConfigExists(path) {
 			missing = append(missing, f)
 		}
 	}
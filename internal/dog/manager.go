@@ -190,6 +190,9 @@ func (m *Manager) createRigWorktree(dogPath, dogName, rigName string) (string, e
 	if err := repoGit.WorktreeAddFromRef(worktreePath, branchName, startPoint); err != nil {
 		return "", fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
+	if err := rig.ApplyIdentity(rigPath, "dog", dogName, worktreePath, true); err != nil {
+		style.PrintWarning("could not set git identity for %s: %v", rigName, err)
+	}
 
 	return worktreePath, nil
 }
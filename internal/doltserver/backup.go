@@ -0,0 +1,100 @@
+package doltserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// backupTimeout bounds a single database backup or restore. Backups copy
+// every chunk, so they get far longer than ordinary queries.
+const backupTimeout = 10 * time.Minute
+
+// BackupDatabase writes a Dolt backup of the named database to destDir,
+// which must not already hold one. It works whether or not the server is
+// running. The backup keeps all branches and history, and RestoreDatabase
+// turns it back into a database.
+func BackupDatabase(townRoot, db, destDir string) error {
+	cfg := DefaultConfig(townRoot)
+	if cfg.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — its databases aren't on this machine", cfg.HostPort())
+	}
+	abs, err := filepath.Abs(destDir)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", destDir, err)
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return fmt.Errorf("creating backup dir: %w", err)
+	}
+	if err := backupDatabase(cfg, db, fileURL(abs)); err != nil {
+		return fmt.Errorf("backing up %s: %w", db, err)
+	}
+	return nil
+}
+
+// RestoreDatabase creates the named database in townRoot's .dolt-data from
+// a backup written by BackupDatabase. The local server must be stopped, so
+// it picks up the new database when it next starts, and the database must
+// not already exist.
+func RestoreDatabase(townRoot, db, srcDir string) error {
+	cfg := DefaultConfig(townRoot)
+	if cfg.IsRemote() {
+		return fmt.Errorf("Dolt server is remote (%s) — its databases aren't on this machine", cfg.HostPort())
+	}
+	if running, pid, _ := IsRunning(townRoot); running {
+		return fmt.Errorf("Dolt server is running (PID %d); stop it with 'gt dolt stop' before restoring %s", pid, db)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.DataDir, db)); err == nil {
+		return fmt.Errorf("database %q already exists in %s", db, cfg.DataDir)
+	}
+	abs, err := filepath.Abs(srcDir)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", srcDir, err)
+	}
+	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
+		return fmt.Errorf("creating data dir: %w", err)
+	}
+	if err := restoreDatabase(cfg.DataDir, db, fileURL(abs)); err != nil {
+		return fmt.Errorf("restoring %s: %w", db, err)
+	}
+	return nil
+}
+
+// backupDatabase syncs db to the backup at url through SQL, so a running
+// server serves it and a stopped one is read from disk.
+// A variable so tests can run without dolt installed.
+var backupDatabase = func(cfg *Config, db, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf("USE %s; CALL DOLT_BACKUP('sync-url', '%s')", db, strings.ReplaceAll(url, "'", "''"))
+	output, err := buildDoltSQLCmd(ctx, cfg, "-q", query).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// restoreDatabase creates db in dataDir from the backup at url.
+// A variable so tests can run without dolt installed.
+var restoreDatabase = func(dataDir, db, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "dolt", "backup", "restore", url, db)
+	cmd.Dir = dataDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// fileURL returns the file:// URL Dolt uses for a local backup directory.
+func fileURL(path string) string {
+	return "file://" + filepath.ToSlash(path)
+}
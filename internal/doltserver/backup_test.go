@@ -0,0 +1,42 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupAndRestoreDatabase(t *testing.T) {
+	origBackup, origRestore := backupDatabase, restoreDatabase
+	t.Cleanup(func() { backupDatabase, restoreDatabase = origBackup, origRestore })
+
+	var backupURL, restoreDir string
+	backupDatabase = func(_ *Config, db, url string) error {
+		backupURL = url
+		return nil
+	}
+	restoreDatabase = func(dataDir, db, url string) error {
+		restoreDir = dataDir
+		return os.MkdirAll(filepath.Join(dataDir, db, ".dolt"), 0755)
+	}
+
+	town := t.TempDir()
+	dest := filepath.Join(t.TempDir(), "myrig")
+	if err := BackupDatabase(town, "myrig", dest); err != nil {
+		t.Fatalf("BackupDatabase: %v", err)
+	}
+	if backupURL != "file://"+filepath.ToSlash(dest) {
+		t.Errorf("backup URL = %q", backupURL)
+	}
+
+	if err := RestoreDatabase(town, "myrig", dest); err != nil {
+		t.Fatalf("RestoreDatabase: %v", err)
+	}
+	if restoreDir != filepath.Join(town, ".dolt-data") {
+		t.Errorf("restored in %q, want the town's .dolt-data", restoreDir)
+	}
+	if err := RestoreDatabase(town, "myrig", dest); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second restore err = %v, want already exists", err)
+	}
+}
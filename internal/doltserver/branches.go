@@ -0,0 +1,254 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MainBranch is the branch polecat branches fork from and merge into.
+const MainBranch = "main"
+
+// polecatBranchPrefix starts every branch made by PolecatBranchName.
+const polecatBranchPrefix = "polecat-"
+
+// Branch is one Dolt branch in a rig database.
+type Branch struct {
+	Name          string    `json:"name"`
+	Hash          string    `json:"hash"`
+	LastCommitter string    `json:"last_committer,omitempty"`
+	LastCommit    time.Time `json:"last_commit"`
+	LastMessage   string    `json:"last_message,omitempty"`
+
+	// Polecat and Created are parsed from polecat branch names
+	// (polecat-<name>-<unix>); both are zero for other branches.
+	Polecat string    `json:"polecat,omitempty"`
+	Created time.Time `json:"created,omitzero"`
+
+	// Unmerged is the number of commits on the branch that main lacks.
+	Unmerged int `json:"unmerged"`
+
+	// Dirty is true if the branch has uncommitted working-set changes,
+	// which polecats leave behind when BD_DOLT_AUTO_COMMIT is off.
+	Dirty bool `json:"dirty"`
+}
+
+// HasUnmergedChanges reports whether deleting the branch would lose data.
+func (b Branch) HasUnmergedChanges() bool {
+	return b.Unmerged > 0 || b.Dirty
+}
+
+// LastActive is the later of the branch's last commit and its creation.
+func (b Branch) LastActive() time.Time {
+	if b.Created.After(b.LastCommit) {
+		return b.Created
+	}
+	return b.LastCommit
+}
+
+// parsePolecatBranch extracts the polecat name and creation time from a
+// branch named by PolecatBranchName.
+func parsePolecatBranch(name string) (polecat string, created time.Time, ok bool) {
+	rest, found := strings.CutPrefix(name, polecatBranchPrefix)
+	if !found {
+		return "", time.Time{}, false
+	}
+	i := strings.LastIndex(rest, "-")
+	if i <= 0 {
+		return "", time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return rest[:i], time.Unix(unix, 0), true
+}
+
+// ListBranches returns every branch in rigDB except main, oldest activity
+// first, with unmerged-commit counts and working-set state filled in.
+func ListBranches(ctx context.Context, townRoot, rigDB string) ([]Branch, error) {
+	db, err := DB(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, `SELECT name, hash, COALESCE(latest_committer, ''),
+		DATE_FORMAT(latest_commit_date, '%Y-%m-%d %H:%i:%s'), COALESCE(latest_commit_message, '')
+		FROM dolt_branches WHERE name <> ? ORDER BY latest_commit_date`, MainBranch)
+	if err != nil {
+		return nil, fmt.Errorf("listing branches in %s: %w", rigDB, err)
+	}
+	var branches []Branch
+	for rows.Next() {
+		var b Branch
+		var date string
+		if err := rows.Scan(&b.Name, &b.Hash, &b.LastCommitter, &date, &b.LastMessage); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning branch: %w", err)
+		}
+		b.LastCommit, _ = time.ParseInLocation("2006-01-02 15:04:05", date, time.UTC)
+		b.Polecat, b.Created, _ = parsePolecatBranch(b.Name)
+		branches = append(branches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing branches in %s: %w", rigDB, err)
+	}
+
+	for i := range branches {
+		if err := branchState(ctx, db, rigDB, &branches[i]); err != nil {
+			return nil, err
+		}
+	}
+	return branches, nil
+}
+
+// GetBranch returns one branch of rigDB, or an error if it doesn't exist.
+func GetBranch(ctx context.Context, townRoot, rigDB, name string) (*Branch, error) {
+	branches, err := ListBranches(ctx, townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range branches {
+		if b.Name == name {
+			return &b, nil
+		}
+	}
+	return nil, fmt.Errorf("branch %q not found in %s", name, rigDB)
+}
+
+// branchState fills in b's unmerged commit count and dirty flag.
+func branchState(ctx context.Context, db *sql.DB, rigDB string, b *Branch) error {
+	if err := validateBranchName(b.Name); err != nil {
+		return fmt.Errorf("branch %q: %w", b.Name, err)
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM dolt_log(?)", MainBranch+".."+b.Name).Scan(&b.Unmerged); err != nil {
+		return fmt.Errorf("counting unmerged commits on %s: %w", b.Name, err)
+	}
+	// A revision database ("rig/branch") reads that branch's working set.
+	if strings.Contains(rigDB, "`") {
+		return fmt.Errorf("invalid database name %q", rigDB)
+	}
+	var changed int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM `%s/%s`.dolt_status", rigDB, b.Name)
+	if err := db.QueryRowContext(ctx, query).Scan(&changed); err != nil {
+		return fmt.Errorf("reading working set of %s: %w", b.Name, err)
+	}
+	b.Dirty = changed > 0
+	return nil
+}
+
+// BranchCommit is a commit on a branch that main lacks.
+type BranchCommit struct {
+	Hash      string    `json:"hash"`
+	Committer string    `json:"committer"`
+	Date      time.Time `json:"date"`
+	Message   string    `json:"message"`
+}
+
+// TableDiffStat summarizes one table's changes on a branch relative to main.
+type TableDiffStat struct {
+	Table    string `json:"table"`
+	Added    int    `json:"rows_added"`
+	Deleted  int    `json:"rows_deleted"`
+	Modified int    `json:"rows_modified"`
+}
+
+// BranchCommits returns the commits on branch that main lacks, newest first.
+func BranchCommits(ctx context.Context, townRoot, rigDB, branch string) ([]BranchCommit, error) {
+	if err := validateBranchName(branch); err != nil {
+		return nil, err
+	}
+	db, err := DB(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, `SELECT commit_hash, committer,
+		DATE_FORMAT(date, '%Y-%m-%d %H:%i:%s'), message
+		FROM dolt_log(?) ORDER BY date DESC`, MainBranch+".."+branch)
+	if err != nil {
+		return nil, fmt.Errorf("reading log of %s: %w", branch, err)
+	}
+	defer rows.Close()
+
+	var commits []BranchCommit
+	for rows.Next() {
+		var c BranchCommit
+		var date string
+		if err := rows.Scan(&c.Hash, &c.Committer, &date, &c.Message); err != nil {
+			return nil, fmt.Errorf("scanning commit: %w", err)
+		}
+		c.Date, _ = time.ParseInLocation("2006-01-02 15:04:05", date, time.UTC)
+		commits = append(commits, c)
+	}
+	return commits, rows.Err()
+}
+
+// BranchDiffStat returns per-table row changes made on branch since it
+// diverged from main (main...branch), committed changes only.
+func BranchDiffStat(ctx context.Context, townRoot, rigDB, branch string) ([]TableDiffStat, error) {
+	if err := validateBranchName(branch); err != nil {
+		return nil, err
+	}
+	db, err := DB(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, `SELECT table_name,
+		COALESCE(rows_added, 0), COALESCE(rows_deleted, 0), COALESCE(rows_modified, 0)
+		FROM dolt_diff_stat(?) ORDER BY table_name`, MainBranch+"..."+branch)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s against %s: %w", branch, MainBranch, err)
+	}
+	defer rows.Close()
+
+	var stats []TableDiffStat
+	for rows.Next() {
+		var s TableDiffStat
+		if err := rows.Scan(&s.Table, &s.Added, &s.Deleted, &s.Modified); err != nil {
+			return nil, fmt.Errorf("scanning diff stat: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// StaleBranches returns the branches with no activity since cutoff.
+func StaleBranches(branches []Branch, cutoff time.Time) []Branch {
+	var stale []Branch
+	for _, b := range branches {
+		if b.LastActive().Before(cutoff) {
+			stale = append(stale, b)
+		}
+	}
+	return stale
+}
+
+// DeleteBranch deletes a branch from rigDB. Branches with unmerged commits
+// or uncommitted changes are refused unless force is set. Main can never
+// be deleted.
+func DeleteBranch(ctx context.Context, townRoot, rigDB string, b Branch, force bool) error {
+	if b.Name == MainBranch {
+		return fmt.Errorf("refusing to delete %s", MainBranch)
+	}
+	if err := validateBranchName(b.Name); err != nil {
+		return err
+	}
+	if b.HasUnmergedChanges() && !force {
+		return fmt.Errorf("branch %s has unmerged changes (%d commit(s), dirty=%v); use --force to delete anyway", b.Name, b.Unmerged, b.Dirty)
+	}
+	db, err := DB(townRoot, rigDB)
+	if err != nil {
+		return err
+	}
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if _, err := db.ExecContext(ctx, "CALL DOLT_BRANCH(?, ?)", flag, b.Name); err != nil {
+		return fmt.Errorf("deleting branch %s in %s: %w", b.Name, rigDB, err)
+	}
+	return nil
+}
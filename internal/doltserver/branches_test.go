@@ -0,0 +1,89 @@
+package doltserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePolecatBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		polecat string
+		created int64
+		ok      bool
+	}{
+		{"polecat-toast-1767225600", "toast", 1767225600, true},
+		{"polecat-dust-devil-1767225600", "dust-devil", 1767225600, true},
+		{"polecat-toast", "", 0, false},
+		{"polecat--1767225600", "", 0, false},
+		{"polecat-toast-abc", "", 0, false},
+		{"feature-x", "", 0, false},
+		{"main", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polecat, created, ok := parsePolecatBranch(tt.name)
+			if ok != tt.ok || polecat != tt.polecat {
+				t.Fatalf("parsePolecatBranch(%q) = %q, %v; want %q, %v", tt.name, polecat, ok, tt.polecat, tt.ok)
+			}
+			if ok && created.Unix() != tt.created {
+				t.Errorf("created = %d, want %d", created.Unix(), tt.created)
+			}
+		})
+	}
+}
+
+func TestParsePolecatBranch_RoundTrip(t *testing.T) {
+	polecat, _, ok := parsePolecatBranch(PolecatBranchName("Toast"))
+	if !ok || polecat != "toast" {
+		t.Errorf("parsePolecatBranch(PolecatBranchName(Toast)) = %q, %v; want toast, true", polecat, ok)
+	}
+}
+
+func TestStaleBranches(t *testing.T) {
+	now := time.Now()
+	branches := []Branch{
+		{Name: "old", LastCommit: now.Add(-10 * 24 * time.Hour)},
+		{Name: "recent-commit", LastCommit: now.Add(-time.Hour)},
+		// Forked from an old main commit but created recently: not stale.
+		{Name: "new-fork", LastCommit: now.Add(-30 * 24 * time.Hour), Created: now.Add(-time.Hour)},
+	}
+	stale := StaleBranches(branches, now.Add(-7*24*time.Hour))
+	if len(stale) != 1 || stale[0].Name != "old" {
+		t.Errorf("StaleBranches = %v, want [old]", stale)
+	}
+}
+
+func TestBranchHasUnmergedChanges(t *testing.T) {
+	if (Branch{}).HasUnmergedChanges() {
+		t.Error("empty branch should have no unmerged changes")
+	}
+	if !(Branch{Unmerged: 1}).HasUnmergedChanges() {
+		t.Error("branch with unmerged commits should report unmerged changes")
+	}
+	if !(Branch{Dirty: true}).HasUnmergedChanges() {
+		t.Error("dirty branch should report unmerged changes")
+	}
+}
+
+func TestDeleteBranch_Refusals(t *testing.T) {
+	ctx := context.Background()
+	townRoot := t.TempDir()
+
+	if err := DeleteBranch(ctx, townRoot, "gastown", Branch{Name: MainBranch}, true); err == nil {
+		t.Error("deleting main should fail even with force")
+	}
+	if err := DeleteBranch(ctx, townRoot, "gastown", Branch{Name: "x'; DROP"}, true); err == nil {
+		t.Error("invalid branch name should be rejected")
+	}
+	err := DeleteBranch(ctx, townRoot, "gastown", Branch{Name: "polecat-toast-1", Unmerged: 2}, false)
+	if err == nil || !strings.Contains(err.Error(), "unmerged") {
+		t.Errorf("unmerged branch without force: err = %v, want unmerged refusal", err)
+	}
+	err = DeleteBranch(ctx, townRoot, "gastown", Branch{Name: "polecat-toast-1", Dirty: true}, false)
+	if err == nil || !strings.Contains(err.Error(), "unmerged") {
+		t.Errorf("dirty branch without force: err = %v, want unmerged refusal", err)
+	}
+}
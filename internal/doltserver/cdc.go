@@ -0,0 +1,232 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Bead change kinds emitted by ChangeWatcher.
+const (
+	ChangeCreated       = "created"
+	ChangeStatusChanged = "status_changed"
+	ChangeAssigned      = "assigned"
+)
+
+// BeadChange is one change to a bead observed between two Dolt commits.
+type BeadChange struct {
+	Database    string `json:"database"`
+	Kind        string `json:"kind"`
+	ID          string `json:"id"`
+	Title       string `json:"title,omitempty"`
+	OldStatus   string `json:"old_status,omitempty"`
+	NewStatus   string `json:"new_status,omitempty"`
+	OldAssignee string `json:"old_assignee,omitempty"`
+	NewAssignee string `json:"new_assignee,omitempty"`
+	Commit      string `json:"commit"`
+	Committer   string `json:"committer,omitempty"`
+}
+
+// issueDiffRow is one row of dolt_diff(from, to, 'issues').
+type issueDiffRow struct {
+	DiffType     string // added, modified, removed
+	ID           string
+	Title        string
+	FromStatus   string
+	ToStatus     string
+	FromAssignee string
+	ToAssignee   string
+}
+
+// classifyIssueDiff turns a diff row into change events. A modified row can
+// produce both a status and an assignment change; deletions and edits to
+// other columns produce none.
+func classifyIssueDiff(row issueDiffRow) []BeadChange {
+	base := BeadChange{ID: row.ID, Title: row.Title}
+	switch row.DiffType {
+	case "added":
+		c := base
+		c.Kind = ChangeCreated
+		c.NewStatus = row.ToStatus
+		c.NewAssignee = row.ToAssignee
+		return []BeadChange{c}
+	case "modified":
+		var changes []BeadChange
+		if row.FromStatus != row.ToStatus {
+			c := base
+			c.Kind = ChangeStatusChanged
+			c.OldStatus, c.NewStatus = row.FromStatus, row.ToStatus
+			changes = append(changes, c)
+		}
+		if row.FromAssignee != row.ToAssignee {
+			c := base
+			c.Kind = ChangeAssigned
+			c.OldAssignee, c.NewAssignee = row.FromAssignee, row.ToAssignee
+			changes = append(changes, c)
+		}
+		return changes
+	default:
+		return nil
+	}
+}
+
+// ChangeWatcher turns new Dolt commits on the town server into bead change
+// events. Each poll compares every database's HEAD with the last commit seen
+// and diffs only the issues table between them, so the cost is independent
+// of table size. Several commits between polls are collapsed into their net
+// effect. Cursors persist across restarts; a database seen for the first time
+// starts at its current HEAD without replaying history.
+type ChangeWatcher struct {
+	townRoot   string
+	cursorPath string
+
+	mu      sync.Mutex
+	cursors map[string]string // database -> last seen commit hash
+	loaded  bool
+}
+
+// NewChangeWatcher creates a watcher for the town's Dolt server. Cursors are
+// stored in daemon/bead-changes.json.
+func NewChangeWatcher(townRoot string) *ChangeWatcher {
+	return &ChangeWatcher{
+		townRoot:   townRoot,
+		cursorPath: filepath.Join(townRoot, "daemon", "bead-changes.json"),
+		cursors:    make(map[string]string),
+	}
+}
+
+// Poll checks every database for new commits and returns the resulting
+// changes, ordered by database. Per-database errors are collected and
+// returned together with the changes from the databases that succeeded.
+func (w *ChangeWatcher) Poll(ctx context.Context) ([]BeadChange, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.loaded {
+		w.loadCursors()
+		w.loaded = true
+	}
+
+	databases, err := ListDatabases(w.townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	sort.Strings(databases)
+
+	var changes []BeadChange
+	var errs []error
+	dirty := false
+	for _, database := range databases {
+		dbChanges, head, err := w.pollDatabase(ctx, database)
+		if head != "" && head != w.cursors[database] {
+			w.cursors[database] = head
+			dirty = true
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", database, err))
+			continue
+		}
+		changes = append(changes, dbChanges...)
+	}
+
+	if dirty {
+		if err := w.saveCursors(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+// pollDatabase returns changes since the database's cursor and its new HEAD.
+func (w *ChangeWatcher) pollDatabase(ctx context.Context, database string) ([]BeadChange, string, error) {
+	db, err := DB(w.townRoot, database)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var head, committer string
+	err = db.QueryRowContext(ctx, "SELECT commit_hash, committer FROM dolt_log ORDER BY date DESC LIMIT 1").Scan(&head, &committer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("reading dolt_log: %w", err)
+	}
+
+	from, ok := w.cursors[database]
+	if !ok || from == head {
+		return nil, head, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT diff_type,
+		COALESCE(to_id, from_id, ''), COALESCE(to_title, from_title, ''),
+		COALESCE(from_status, ''), COALESCE(to_status, ''),
+		COALESCE(from_assignee, ''), COALESCE(to_assignee, '')
+		FROM dolt_diff(?, ?, 'issues')`, from, head)
+	if err != nil {
+		// The cursor commit may have been garbage-collected or the table
+		// may not exist yet; resume from HEAD rather than failing forever.
+		return nil, head, fmt.Errorf("diffing %s..%s (resuming from HEAD): %w", shortHash(from), shortHash(head), err)
+	}
+	defer rows.Close()
+
+	var changes []BeadChange
+	for rows.Next() {
+		var r issueDiffRow
+		if err := rows.Scan(&r.DiffType, &r.ID, &r.Title, &r.FromStatus, &r.ToStatus, &r.FromAssignee, &r.ToAssignee); err != nil {
+			return nil, "", fmt.Errorf("scanning diff: %w", err)
+		}
+		for _, c := range classifyIssueDiff(r) {
+			c.Database = database
+			c.Commit = head
+			c.Committer = committer
+			changes = append(changes, c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("reading diff: %w", err)
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes, head, nil
+}
+
+func (w *ChangeWatcher) loadCursors() {
+	data, err := os.ReadFile(w.cursorPath)
+	if err != nil {
+		return
+	}
+	var cursors map[string]string
+	if json.Unmarshal(data, &cursors) == nil && cursors != nil {
+		w.cursors = cursors
+	}
+}
+
+func (w *ChangeWatcher) saveCursors() error {
+	data, err := json.MarshalIndent(w.cursors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling change cursors: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(w.cursorPath), 0755); err != nil {
+		return fmt.Errorf("creating cursor directory: %w", err)
+	}
+	if err := util.AtomicWriteFile(w.cursorPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing change cursors: %w", err)
+	}
+	return nil
+}
+
+// shortHash abbreviates a commit hash for messages.
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
@@ -0,0 +1,48 @@
+package doltserver
+
+import (
+	"testing"
+)
+
+func TestClassifyIssueDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		row   issueDiffRow
+		kinds []string
+	}{
+		{"added", issueDiffRow{DiffType: "added", ID: "gt-1", ToStatus: "open"}, []string{ChangeCreated}},
+		{"status", issueDiffRow{DiffType: "modified", ID: "gt-1", FromStatus: "open", ToStatus: "closed"}, []string{ChangeStatusChanged}},
+		{"assigned", issueDiffRow{DiffType: "modified", ID: "gt-1", FromStatus: "open", ToStatus: "open", ToAssignee: "gastown/polecats/Toast"}, []string{ChangeAssigned}},
+		{"both", issueDiffRow{DiffType: "modified", ID: "gt-1", FromStatus: "open", ToStatus: "hooked", ToAssignee: "x"}, []string{ChangeStatusChanged, ChangeAssigned}},
+		{"other column", issueDiffRow{DiffType: "modified", ID: "gt-1", FromStatus: "open", ToStatus: "open"}, nil},
+		{"removed", issueDiffRow{DiffType: "removed", ID: "gt-1", FromStatus: "open"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyIssueDiff(tt.row)
+			if len(got) != len(tt.kinds) {
+				t.Fatalf("got %d changes %+v, want %v", len(got), got, tt.kinds)
+			}
+			for i, c := range got {
+				if c.Kind != tt.kinds[i] || c.ID != "gt-1" {
+					t.Errorf("change %d = %+v, want kind %s", i, c, tt.kinds[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChangeWatcherCursors(t *testing.T) {
+	townRoot := t.TempDir()
+	w := NewChangeWatcher(townRoot)
+	w.cursors["gastown"] = "abc123"
+	if err := w.saveCursors(); err != nil {
+		t.Fatal(err)
+	}
+
+	w2 := NewChangeWatcher(townRoot)
+	w2.loadCursors()
+	if w2.cursors["gastown"] != "abc123" {
+		t.Errorf("cursor not persisted: %v", w2.cursors)
+	}
+}
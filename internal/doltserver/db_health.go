@@ -0,0 +1,182 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HealthKeyTables are the beads tables whose row counts deep health reports.
+// Tables a database doesn't have are left out of its counts.
+var HealthKeyTables = []string{"issues", "dependencies", "labels", "comments", "events"}
+
+// databaseProbeTimeout bounds the SQL queries made for one database.
+const databaseProbeTimeout = 10 * time.Second
+
+// DatabaseHealth is the per-database part of deep health metrics.
+type DatabaseHealth struct {
+	Name string `json:"name"`
+
+	// SizeBytes is the database's size on disk (local servers only).
+	SizeBytes int64 `json:"size_bytes,omitempty"`
+
+	// LockFile is true if .dolt/noms/LOCK exists. A running server holds
+	// it; with the server stopped it is stale and blocks the next start.
+	LockFile bool `json:"lock_file"`
+
+	// Probed is true if the server was reachable and the fields below were
+	// queried.
+	Probed bool `json:"probed"`
+
+	// LastCommit is the time of the newest commit on the default branch.
+	LastCommit time.Time `json:"last_commit,omitzero"`
+
+	// Branches is the number of Dolt branches, main included.
+	Branches int `json:"branches"`
+
+	// RowCounts maps each of HealthKeyTables the database has to its row count.
+	RowCounts map[string]int64 `json:"row_counts,omitempty"`
+
+	// ReadOnly is the result of a write probe against this database.
+	ReadOnly bool `json:"read_only"`
+
+	// Errors lists the checks that failed, in the order they ran.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// GetDeepHealthMetrics is GetHealthMetrics plus per-database health for
+// every database. It marks the server unhealthy if any database is read-only.
+func GetDeepHealthMetrics(townRoot string) *HealthMetrics {
+	metrics := GetHealthMetrics(townRoot)
+	metrics.Databases = CollectDatabaseHealth(context.Background(), townRoot, metrics.DatabaseSizes)
+	for _, d := range metrics.Databases {
+		if d.ReadOnly {
+			metrics.Healthy = false
+			metrics.Warnings = append(metrics.Warnings,
+				fmt.Sprintf("database %s is READ-ONLY", d.Name))
+		}
+		for _, e := range d.Errors {
+			metrics.Warnings = append(metrics.Warnings, fmt.Sprintf("database %s: %s", d.Name, e))
+		}
+	}
+	return metrics
+}
+
+// CollectDatabaseHealth reports the health of every database ListDatabases
+// finds. sizes, if given, supplies SizeBytes. Lock files are checked even
+// when the server is down; the SQL checks and write probe need it reachable.
+func CollectDatabaseHealth(ctx context.Context, townRoot string, sizes []DatabaseSize) []DatabaseHealth {
+	databases, err := ListDatabases(townRoot)
+	if err != nil || len(databases) == 0 {
+		return nil
+	}
+	config := DefaultConfig(townRoot)
+	reachable := CheckServerReachable(townRoot) == nil
+
+	sizeOf := make(map[string]int64, len(sizes))
+	for _, s := range sizes {
+		sizeOf[s.Name] = s.Bytes
+	}
+
+	health := make([]DatabaseHealth, 0, len(databases))
+	for _, name := range databases {
+		h := DatabaseHealth{Name: name, SizeBytes: sizeOf[name]}
+		if !config.IsRemote() {
+			h.LockFile = hasDoltLock(filepath.Join(config.DataDir, name))
+		}
+		if reachable {
+			probeDatabase(ctx, townRoot, &h)
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+// hasDoltLock reports whether the database at databaseDir has a noms LOCK file.
+func hasDoltLock(databaseDir string) bool {
+	_, err := os.Stat(filepath.Join(databaseDir, ".dolt", "noms", "LOCK"))
+	return err == nil
+}
+
+// probeDatabase fills in the SQL-derived fields of h. A failed check is
+// recorded in h.Errors and the remaining checks still run.
+func probeDatabase(ctx context.Context, townRoot string, h *DatabaseHealth) {
+	h.Probed = true
+	fail := func(what string, err error) {
+		h.Errors = append(h.Errors, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	db, err := DB(townRoot, h.Name)
+	if err != nil {
+		fail("connecting", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, databaseProbeTimeout)
+	defer cancel()
+
+	var date sql.NullString
+	if err := db.QueryRowContext(ctx,
+		"SELECT DATE_FORMAT(MAX(date), '%Y-%m-%d %H:%i:%s') FROM dolt_log").Scan(&date); err != nil {
+		fail("reading dolt_log", err)
+	} else if date.Valid {
+		h.LastCommit, _ = time.ParseInLocation("2006-01-02 15:04:05", date.String, time.UTC)
+	}
+
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM dolt_branches").Scan(&h.Branches); err != nil {
+		fail("counting branches", err)
+	}
+
+	if counts, err := keyTableCounts(ctx, db); err != nil {
+		fail("counting rows", err)
+	} else {
+		h.RowCounts = counts
+	}
+
+	readOnly, err := CheckReadOnlyDatabase(townRoot, h.Name)
+	if err != nil {
+		fail("read-only probe", err)
+	}
+	h.ReadOnly = readOnly
+}
+
+// keyTableCounts counts the rows of each of HealthKeyTables present in db.
+func keyTableCounts(ctx context.Context, db *sql.DB) (map[string]int64, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(HealthKeyTables)), ",")
+	args := make([]any, len(HealthKeyTables))
+	for i, t := range HealthKeyTables {
+		args[i] = t
+	}
+	rows, err := db.QueryContext(ctx,
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name IN ("+placeholders+")",
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	var present []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		present = append(present, strings.ToLower(name))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(present))
+	for _, table := range present {
+		var n int64
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM `"+table+"`").Scan(&n); err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+		counts[table] = n
+	}
+	return counts, nil
+}
@@ -0,0 +1,70 @@
+package doltserver
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// unusedPort returns a local port nothing is listening on.
+func unusedPort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	return port
+}
+
+func TestCollectDatabaseHealth_ServerDown(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(unusedPort(t)))
+	dataDir := filepath.Join(townRoot, ".dolt-data")
+	for _, db := range []string{"gastown", "hq"} {
+		if err := os.MkdirAll(filepath.Join(dataDir, db, ".dolt", "noms"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "hq", ".dolt", "noms", "LOCK"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	health := CollectDatabaseHealth(context.Background(), townRoot, []DatabaseSize{{Name: "gastown", Bytes: 4096}})
+	if len(health) != 2 {
+		t.Fatalf("got %d databases, want 2", len(health))
+	}
+	byName := map[string]DatabaseHealth{}
+	for _, h := range health {
+		byName[h.Name] = h
+		if h.Probed {
+			t.Errorf("%s: probed with the server down", h.Name)
+		}
+		if len(h.Errors) > 0 {
+			t.Errorf("%s: errors = %v, want none", h.Name, h.Errors)
+		}
+	}
+	if byName["gastown"].LockFile || !byName["hq"].LockFile {
+		t.Errorf("lock files = gastown:%v hq:%v, want only hq", byName["gastown"].LockFile, byName["hq"].LockFile)
+	}
+	if byName["gastown"].SizeBytes != 4096 || byName["hq"].SizeBytes != 0 {
+		t.Errorf("sizes = gastown:%d hq:%d, want 4096 and 0", byName["gastown"].SizeBytes, byName["hq"].SizeBytes)
+	}
+}
+
+func TestGetDeepHealthMetrics_NoDatabases(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(unusedPort(t)))
+
+	metrics := GetDeepHealthMetrics(townRoot)
+	if len(metrics.Databases) != 0 {
+		t.Errorf("Databases = %v, want none", metrics.Databases)
+	}
+	if metrics.ReadOnly {
+		t.Error("ReadOnly with no databases")
+	}
+}
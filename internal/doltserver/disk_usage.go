@@ -0,0 +1,207 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultSizeTTL is how long a measured database size is trusted before
+// it is checked again.
+const DefaultSizeTTL = 5 * time.Minute
+
+// maxSizeWorkers caps the number of databases measured at once.
+const maxSizeWorkers = 8
+
+// DatabaseSize is the on-disk size of one entry in .dolt-data, usually a
+// database directory.
+type DatabaseSize struct {
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+
+	// CheckedAt is when Bytes was last measured, or confirmed current by
+	// an unchanged Fingerprint.
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Fingerprint summarizes the database's .dolt/noms directory (entry
+	// count, total size, newest mtime). Dolt rewrites a file there on every
+	// write, so an unchanged fingerprint means an unchanged database.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// DataUsage is the disk usage of a town's .dolt-data directory.
+type DataUsage struct {
+	TotalBytes int64          `json:"total_bytes"`
+	Databases  []DatabaseSize `json:"databases"`
+
+	// Measured is how many entries were walked this time; the rest came
+	// from the cache.
+	Measured int `json:"measured"`
+}
+
+// SizeCollector measures .dolt-data, reusing earlier results so callers
+// on hot paths (status, health checks) don't walk every database each time.
+// Results persist in daemon/dolt-sizes.json, so separate gt invocations
+// share them.
+type SizeCollector struct {
+	// TTL is how long a cached size is trusted without looking at the
+	// database at all. Zero checks every database on each Collect.
+	TTL time.Duration
+
+	// Incremental reuses a size older than TTL when the database's
+	// fingerprint hasn't changed, so only databases written since the last
+	// check are walked again.
+	Incremental bool
+
+	// Workers is the number of databases measured concurrently.
+	Workers int
+
+	dataDir   string
+	cachePath string
+	now       func() time.Time
+}
+
+// sizeCacheMu serializes collectors in this process so concurrent callers
+// don't race on the cache file.
+var sizeCacheMu sync.Mutex
+
+// NewSizeCollector returns a collector for the town's .dolt-data with the
+// default TTL and incremental mode on.
+func NewSizeCollector(townRoot string) *SizeCollector {
+	workers := runtime.NumCPU()
+	if workers > maxSizeWorkers {
+		workers = maxSizeWorkers
+	}
+	return &SizeCollector{
+		TTL:         DefaultSizeTTL,
+		Incremental: true,
+		Workers:     workers,
+		dataDir:     DefaultConfig(townRoot).DataDir,
+		cachePath:   filepath.Join(townRoot, "daemon", "dolt-sizes.json"),
+		now:         time.Now,
+	}
+}
+
+// Collect returns the size of every entry in .dolt-data, sorted by name.
+// A missing data directory is empty, not an error.
+func (c *SizeCollector) Collect() (*DataUsage, error) {
+	sizeCacheMu.Lock()
+	defer sizeCacheMu.Unlock()
+
+	usage := &DataUsage{Databases: []DatabaseSize{}}
+	entries, err := os.ReadDir(c.dataDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return nil, err
+	}
+
+	cached := c.loadCache()
+	now := c.now()
+	sizes := make([]DatabaseSize, len(entries))
+	var stale []int
+	for i, e := range entries {
+		path := filepath.Join(c.dataDir, e.Name())
+		prev, ok := cached[e.Name()]
+		if ok && c.TTL > 0 && now.Sub(prev.CheckedAt) < c.TTL {
+			sizes[i] = prev
+			continue
+		}
+		fp := fingerprint(path)
+		if ok && c.Incremental && fp != "" && prev.Fingerprint == fp {
+			prev.CheckedAt = now
+			sizes[i] = prev
+			continue
+		}
+		sizes[i] = DatabaseSize{Name: e.Name(), CheckedAt: now, Fingerprint: fp}
+		stale = append(stale, i)
+	}
+
+	workers := c.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				sizes[i].Bytes = dirSize(filepath.Join(c.dataDir, sizes[i].Name))
+			}
+		}()
+	}
+	for _, i := range stale {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].Name < sizes[j].Name })
+	for _, s := range sizes {
+		usage.TotalBytes += s.Bytes
+	}
+	usage.Databases = sizes
+	usage.Measured = len(stale)
+	c.saveCache(sizes)
+	return usage, nil
+}
+
+// loadCache reads the saved sizes, keyed by name. A missing or corrupt
+// cache is empty.
+func (c *SizeCollector) loadCache() map[string]DatabaseSize {
+	cached := make(map[string]DatabaseSize)
+	data, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		return cached
+	}
+	var sizes []DatabaseSize
+	if json.Unmarshal(data, &sizes) != nil {
+		return cached
+	}
+	for _, s := range sizes {
+		cached[s.Name] = s
+	}
+	return cached
+}
+
+// saveCache writes sizes for the next collector. It is only an
+// optimization, so failures are ignored.
+func (c *SizeCollector) saveCache(sizes []DatabaseSize) {
+	if err := os.MkdirAll(filepath.Dir(c.cachePath), 0755); err != nil {
+		return
+	}
+	_ = util.AtomicWriteJSON(c.cachePath, sizes)
+}
+
+// fingerprint summarizes path's .dolt/noms directory without walking the
+// database. It returns "" for entries that aren't Dolt databases, which
+// are then always measured.
+func fingerprint(path string) string {
+	entries, err := os.ReadDir(filepath.Join(path, ".dolt", "noms"))
+	if err != nil {
+		return ""
+	}
+	var total int64
+	var newest time.Time
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		total += info.Size()
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return fmt.Sprintf("%d/%d/%d", len(entries), total, newest.UnixNano())
+}
@@ -0,0 +1,75 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSizeCollector(t *testing.T) {
+	town := t.TempDir()
+	write := func(rel string, size int) {
+		t.Helper()
+		path := filepath.Join(town, ".dolt-data", rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("alpha/.dolt/noms/journal", 100)
+	write("beta/.dolt/noms/journal", 50)
+
+	now := time.Now()
+	collect := func(c *SizeCollector) *DataUsage {
+		t.Helper()
+		c.now = func() time.Time { return now }
+		usage, err := c.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return usage
+	}
+
+	c := NewSizeCollector(town)
+	if u := collect(c); u.TotalBytes != 150 || u.Measured != 2 || len(u.Databases) != 2 {
+		t.Fatalf("first collect = %+v, want 150 bytes from 2 walks", u)
+	}
+
+	// A fresh collector (another gt invocation) shares the cache.
+	if u := collect(NewSizeCollector(town)); u.Measured != 0 || u.TotalBytes != 150 {
+		t.Errorf("within TTL = %+v, want all cached", u)
+	}
+
+	// Past the TTL, unchanged databases are confirmed by fingerprint.
+	now = now.Add(2 * DefaultSizeTTL)
+	if u := collect(NewSizeCollector(town)); u.Measured != 0 {
+		t.Errorf("unchanged past TTL measured %d, want 0", u.Measured)
+	}
+
+	// A write to alpha changes its fingerprint; only alpha is walked again.
+	write("alpha/.dolt/noms/journal", 300)
+	now = now.Add(2 * DefaultSizeTTL)
+	u := collect(NewSizeCollector(town))
+	if u.Measured != 1 || u.TotalBytes != 350 || u.Databases[0].Name != "alpha" || u.Databases[0].Bytes != 300 {
+		t.Errorf("after write = %+v, want alpha remeasured at 300", u)
+	}
+
+	// Without incremental mode, everything past the TTL is walked.
+	now = now.Add(2 * DefaultSizeTTL)
+	full := NewSizeCollector(town)
+	full.Incremental = false
+	if u := collect(full); u.Measured != 2 {
+		t.Errorf("non-incremental measured %d, want 2", u.Measured)
+	}
+}
+
+func TestSizeCollector_NoDataDir(t *testing.T) {
+	u, err := NewSizeCollector(t.TempDir()).Collect()
+	if err != nil || u.TotalBytes != 0 || len(u.Databases) != 0 {
+		t.Errorf("Collect = %+v, %v; want empty", u, err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // dolthubAPIBase is the DoltHub REST API base URL.
@@ -110,7 +112,7 @@ func AddRemote(dbDir, org, repo string) error {
 	url := DoltHubRemoteURL(org, repo)
 	cmd := exec.Command("dolt", "remote", "add", "origin", url)
 	cmd.Dir = dbDir
-	output, err := cmd.CombinedOutput()
+	output, err := util.CombinedOutput(cmd, util.DoltTimeout)
 	if err != nil {
 		msg := strings.TrimSpace(string(output))
 		// "already exists" is fine
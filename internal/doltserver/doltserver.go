@@ -35,9 +35,10 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
-	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -46,7 +47,14 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/errclass"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/retry"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -73,7 +81,7 @@ func EnsureDoltIdentity() error {
 	// We read --global only (not repo-local) to avoid silently persisting
 	// a repo-scoped override into dolt's permanent global config.
 	if needName {
-		gitName, err := exec.Command("git", "config", "--global", "user.name").Output()
+		gitName, err := util.Output(exec.Command("git", "config", "--global", "user.name"), util.GitTimeout)
 		if err != nil || len(bytes.TrimSpace(gitName)) == 0 {
 			return fmt.Errorf("dolt identity not configured and git user.name not available; run: dolt config --global --add user.name \"Your Name\"")
 		}
@@ -83,7 +91,7 @@ func EnsureDoltIdentity() error {
 	}
 
 	if needEmail {
-		gitEmail, err := exec.Command("git", "config", "--global", "user.email").Output()
+		gitEmail, err := util.Output(exec.Command("git", "config", "--global", "user.email"), util.GitTimeout)
 		if err != nil || len(bytes.TrimSpace(gitEmail)) == 0 {
 			return fmt.Errorf("dolt identity not configured and git user.email not available; run: dolt config --global --add user.email \"you@example.com\"")
 		}
@@ -100,7 +108,7 @@ func EnsureDoltIdentity() error {
 // and (false, error) when dolt itself fails unexpectedly.
 func doltConfigMissing(key string) (bool, error) {
 	cmd := exec.Command("dolt", "config", "--global", "--get", key)
-	out, err := cmd.Output()
+	out, err := util.Output(cmd, util.DoltTimeout)
 	if err == nil {
 		// Command succeeded — key exists if output is non-empty
 		return len(bytes.TrimSpace(out)) == 0, nil
@@ -117,8 +125,8 @@ func doltConfigMissing(key string) (bool, error) {
 // Uses --unset then --add to avoid duplicate entries from repeated calls.
 func setDoltGlobalConfig(key, value string) error {
 	// Remove existing value (ignore error — key may not exist yet)
-	_ = exec.Command("dolt", "config", "--global", "--unset", key).Run()
-	return exec.Command("dolt", "config", "--global", "--add", key, value).Run()
+	_ = util.Run(exec.Command("dolt", "config", "--global", "--unset", key), util.DoltTimeout)
+	return util.Run(exec.Command("dolt", "config", "--global", "--add", key, value), util.DoltTimeout)
 }
 
 // Default configuration
@@ -175,7 +183,8 @@ type Config struct {
 }
 
 // DefaultConfig returns the default Dolt server configuration.
-// Environment variables override defaults when set:
+// A port auto-selected by gt dolt start --auto-port (recorded in the state
+// file) replaces DefaultPort. Environment variables override both when set:
 //   - GT_DOLT_HOST → Host
 //   - GT_DOLT_PORT → Port
 //   - GT_DOLT_USER → User
@@ -192,6 +201,10 @@ func DefaultConfig(townRoot string) *Config {
 		MaxConnections: DefaultMaxConnections,
 	}
 
+	if state, err := LoadState(townRoot); err == nil && state.AutoPort && state.Port > 0 {
+		config.Port = state.Port
+	}
+
 	if h := os.Getenv("GT_DOLT_HOST"); h != "" {
 		config.Host = h
 	}
@@ -282,6 +295,9 @@ func RigDatabaseDir(townRoot, rigName string) string {
 
 // State represents the Dolt server's runtime state.
 type State struct {
+	// Version is the state file schema version; see StateVersion.
+	Version int `json:"version"`
+
 	// Running indicates if the server is running.
 	Running bool `json:"running"`
 
@@ -299,6 +315,10 @@ type State struct {
 
 	// Databases is the list of available databases (rig names).
 	Databases []string `json:"databases,omitempty"`
+
+	// AutoPort is set when Port was auto-selected because the default port
+	// was taken. DefaultConfig then uses Port instead of DefaultPort.
+	AutoPort bool `json:"auto_port,omitempty"`
 }
 
 // StateFile returns the path to the state file.
@@ -306,13 +326,28 @@ func StateFile(townRoot string) string {
 	return filepath.Join(townRoot, "daemon", "dolt-state.json")
 }
 
-// LoadState loads Dolt server state from disk.
+// StateVersion is the dolt-state.json schema version this gt writes.
+// Version 0 is a file written before the field existed.
+const StateVersion = 1
+
+// ErrNewerState is returned for a state file written by a newer gt, which
+// is neither read nor overwritten.
+var ErrNewerState = errors.New("dolt state file is from a newer gt")
+
+// stateMu serializes state updates within a process per state file; the
+// flock in lockState serializes them across processes.
+var stateMu sync.Map // map[string]*sync.Mutex
+
+// LoadState loads Dolt server state from disk, migrating files written by
+// older versions. It does not lock: SaveState replaces the file atomically,
+// so a reader sees either the old or the new state. Callers that save a
+// modified copy must use UpdateState instead.
 func LoadState(townRoot string) (*State, error) {
 	stateFile := StateFile(townRoot)
 	data, err := os.ReadFile(stateFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &State{}, nil
+			return &State{Version: StateVersion}, nil
 		}
 		return nil, err
 	}
@@ -321,18 +356,102 @@ func LoadState(townRoot string) (*State, error) {
 	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, err
 	}
+	if err := migrateState(&state); err != nil {
+		return nil, fmt.Errorf("%s: %w", stateFile, err)
+	}
 	return &state, nil
 }
 
-// SaveState saves Dolt server state to disk using atomic write.
+// migrateState upgrades state read from an older file to StateVersion.
+func migrateState(state *State) error {
+	if state.Version > StateVersion {
+		return fmt.Errorf("%w (version %d, this gt supports %d); upgrade gt", ErrNewerState, state.Version, StateVersion)
+	}
+	if state.Version < 1 {
+		// Unversioned files could hold duplicate or empty database names
+		// left by interleaved saves.
+		seen := make(map[string]bool, len(state.Databases))
+		dbs := state.Databases[:0]
+		for _, db := range state.Databases {
+			if db != "" && !seen[db] {
+				seen[db] = true
+				dbs = append(dbs, db)
+			}
+		}
+		state.Databases = dbs
+		state.Version = 1
+	}
+	return nil
+}
+
+// SaveState saves Dolt server state to disk using atomic write, replacing
+// whatever is there. To change some fields, use UpdateState.
 func SaveState(townRoot string, state *State) error {
-	stateFile := StateFile(townRoot)
+	unlock, err := lockState(townRoot)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return saveStateLocked(townRoot, state)
+}
 
-	// Ensure daemon directory exists
-	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+// UpdateState runs fn on the current state and saves the result, holding
+// the state lock throughout so that concurrent updaters (the daemon, gt dolt
+// start/stop) can't interleave and drop each other's changes. Nothing is
+// saved if fn returns an error.
+func UpdateState(townRoot string, fn func(*State) error) error {
+	unlock, err := lockState(townRoot)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := LoadState(townRoot)
+	if errors.Is(err, ErrNewerState) {
+		return err
+	}
+	if err != nil {
+		// An unreadable state file has nothing worth keeping.
+		state = &State{}
+	}
+	if err := fn(state); err != nil {
 		return err
 	}
+	return saveStateLocked(townRoot, state)
+}
+
+// lockState takes the exclusive state lock for townRoot, returning the
+// function that releases it.
+func lockState(townRoot string) (func(), error) {
+	stateFile := StateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return nil, err
+	}
+	mu, _ := stateMu.LoadOrStore(stateFile, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
 
+	fileLock := flock.New(stateFile + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		mu.(*sync.Mutex).Unlock()
+		return nil, fmt.Errorf("locking %s: %w", stateFile, err)
+	}
+	return func() {
+		_ = fileLock.Unlock()
+		mu.(*sync.Mutex).Unlock()
+	}, nil
+}
+
+// saveStateLocked writes state; the caller holds the state lock. A newer
+// gt's state file is left alone.
+func saveStateLocked(townRoot string, state *State) error {
+	stateFile := StateFile(townRoot)
+	var onDisk struct {
+		Version int `json:"version"`
+	}
+	if data, err := os.ReadFile(stateFile); err == nil && json.Unmarshal(data, &onDisk) == nil && onDisk.Version > StateVersion {
+		return fmt.Errorf("%s: %w (version %d); upgrade gt", stateFile, ErrNewerState, onDisk.Version)
+	}
+	state.Version = StateVersion
 	return util.AtomicWriteJSON(stateFile, state)
 }
 
@@ -442,18 +561,11 @@ func HasServerModeMetadata(townRoot string) []string {
 
 // hasServerMode reads metadata.json and returns true if dolt_mode is "server".
 func hasServerMode(beadsDir string) bool {
-	metadataPath := filepath.Join(beadsDir, "metadata.json")
-	data, err := os.ReadFile(metadataPath)
+	meta, err := beads.ReadBackendMetadata(beadsDir)
 	if err != nil {
 		return false
 	}
-	var metadata struct {
-		DoltMode string `json:"dolt_mode"`
-	}
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		return false
-	}
-	return metadata.DoltMode == "server"
+	return meta.DoltMode == beads.DoltModeServer
 }
 
 // findDoltServerOnPort finds a dolt sql-server process listening on the given port.
@@ -461,7 +573,7 @@ func hasServerMode(beadsDir string) bool {
 func findDoltServerOnPort(port int) int {
 	// Use lsof to find process on port
 	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-t")
-	output, err := cmd.Output()
+	output, err := util.Output(cmd, util.ProbeTimeout)
 	if err != nil {
 		return 0
 	}
@@ -488,7 +600,7 @@ func findDoltServerOnPort(port int) int {
 // isDoltProcess checks if a PID is actually a dolt sql-server process.
 func isDoltProcess(pid int) bool {
 	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=")
-	output, err := cmd.Output()
+	output, err := util.Output(cmd, util.ProbeTimeout)
 	if err != nil {
 		return false
 	}
@@ -499,6 +611,13 @@ func isDoltProcess(pid int) bool {
 
 // Start starts the Dolt SQL server.
 func Start(townRoot string) error {
+	return StartWithOptions(townRoot, StartOptions{})
+}
+
+// StartWithOptions starts the Dolt SQL server. A non-dolt process on the
+// port fails with a *PortConflict unless opts.AutoPort is set, in which case
+// the next free port is used.
+func StartWithOptions(townRoot string, opts StartOptions) error {
 	config := DefaultConfig(townRoot)
 
 	// Ensure daemon directory exists
@@ -552,17 +671,34 @@ func Start(townRoot string) error {
 					fmt.Fprintf(os.Stderr, "Warning: could not update PID file: %v\n", err)
 				}
 				// Update state too
-				state, _ := LoadState(townRoot)
-				if state != nil && state.PID != pid {
+				_ = UpdateState(townRoot, func(state *State) error {
 					state.PID = pid
 					state.Running = true
-					_ = SaveState(townRoot, state)
-				}
+					return nil
+				})
 			}
 			return fmt.Errorf("Dolt server already running (PID %d)", pid)
 		}
 	}
 
+	// Something other than dolt on the port would make the server fail to
+	// bind, surfacing only as a timeout below. Catch it up front.
+	prevState, _ := LoadState(townRoot)
+	autoPort := prevState != nil && prevState.AutoPort
+	if err := checkPortConflict(config.Port); err != nil {
+		if !opts.AutoPort {
+			return err
+		}
+		port, findErr := findFreePort(config.Port)
+		if findErr != nil {
+			return fmt.Errorf("%v\n  auto-port: %w", err, findErr)
+		}
+		fmt.Printf("Port %d is in use by another process; using port %d\n", config.Port, port)
+		config.Port = port
+		autoPort = true
+	}
+	portChanged := prevState == nil || prevState.Port != config.Port
+
 	// Ensure data directory exists
 	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
 		return fmt.Errorf("creating data directory: %w", err)
@@ -570,11 +706,10 @@ func Start(townRoot string) error {
 
 	// Clean up stale Dolt LOCK files in all database directories
 	databases, _ := ListDatabases(townRoot)
-	for _, db := range databases {
-		dbDir := filepath.Join(config.DataDir, db)
-		if err := cleanupStaleDoltLock(dbDir); err != nil {
+	for _, r := range lock.Sweep(lock.DoltLockArtifacts(config.DataDir, nil), true) {
+		if r.Err != nil {
 			// Non-fatal warning
-			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Warning: removing stale LOCK file %s: %v\n", r.Path, r.Err)
 		}
 	}
 
@@ -621,75 +756,49 @@ func Start(townRoot string) error {
 	}
 
 	// Save state
-	state := &State{
-		Running:   true,
-		PID:       cmd.Process.Pid,
-		Port:      config.Port,
-		StartedAt: time.Now(),
-		DataDir:   config.DataDir,
-		Databases: databases,
-	}
-	if err := SaveState(townRoot, state); err != nil {
+	err = UpdateState(townRoot, func(state *State) error {
+		state.Running = true
+		state.PID = cmd.Process.Pid
+		state.Port = config.Port
+		state.StartedAt = time.Now()
+		state.DataDir = config.DataDir
+		state.Databases = databases
+		state.AutoPort = autoPort
+		return nil
+	})
+	if err != nil {
 		// Non-fatal - server is still running
 		fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", err)
 	}
 
+	// Point every rig's metadata.json at the new port so bd connects to it.
+	if autoPort && portChanged {
+		if _, errs := EnsureAllMetadata(townRoot); len(errs) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: updating metadata.json for port %d: %v\n", config.Port, errors.Join(errs...))
+		}
+	}
+
 	// Wait for the server to be accepting connections, not just alive.
 	// IsRunning only checks PID — we need CheckServerReachable to confirm
 	// the port is listening. Retry with backoff since startup takes time.
+	startedAt := time.Now()
 	var lastErr error
-	for attempt := 0; attempt < 10; attempt++ {
-		time.Sleep(500 * time.Millisecond)
-
-		running, _, err = IsRunning(townRoot)
+	err = retry.Do(context.Background(), OperationPolicy(townRoot, RetryDoltStart), func() error {
+		running, _, err := IsRunning(townRoot)
 		if err != nil {
-			return fmt.Errorf("verifying server started: %w", err)
+			return retry.Stop(fmt.Errorf("verifying server started: %w", err))
 		}
 		if !running {
-			return fmt.Errorf("Dolt server failed to start (check logs with 'gt dolt logs')")
+			return retry.Stop(fmt.Errorf("Dolt server failed to start (check logs with 'gt dolt logs')"))
 		}
-
-		if err := CheckServerReachable(townRoot); err == nil {
-			return nil // Server is up and accepting connections
-		} else {
-			lastErr = err
-		}
-	}
-
-	return fmt.Errorf("Dolt server process started (PID %d) but not accepting connections after 5s: %w\nCheck logs with: gt dolt logs", cmd.Process.Pid, lastErr)
-}
-
-// cleanupStaleDoltLock removes a stale Dolt LOCK file if no process holds it.
-// Dolt's embedded mode uses a file lock at .dolt/noms/LOCK that can become stale
-// after crashes. This checks if any process holds the lock before removing.
-// Returns nil if lock is held by active processes (this is expected if bd is running).
-func cleanupStaleDoltLock(databaseDir string) error {
-	lockPath := filepath.Join(databaseDir, ".dolt", "noms", "LOCK")
-
-	// Check if lock file exists
-	if _, err := os.Stat(lockPath); os.IsNotExist(err) {
-		return nil // No lock file, nothing to clean
-	}
-
-	// Check if any process holds this file open using lsof
-	cmd := exec.Command("lsof", lockPath)
-	_, err := cmd.Output()
-	if err != nil {
-		// lsof returns exit code 1 when no process has the file open
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			// No process holds the lock - safe to remove stale lock
-			if err := os.Remove(lockPath); err != nil {
-				return fmt.Errorf("failed to remove stale LOCK file: %w", err)
-			}
-			return nil
-		}
-		// Other error - ignore, let dolt handle it
-		return nil
+		lastErr = CheckServerReachable(townRoot)
+		return lastErr
+	})
+	if err == nil || err != lastErr {
+		return err // up, or died while we waited
 	}
-
-	// lsof found processes - lock is legitimately held (likely by bd)
-	// This is not an error condition; dolt server will handle the conflict
-	return nil
+	return fmt.Errorf("Dolt server process started (PID %d) but not accepting connections after %s: %w\nCheck logs with: gt dolt logs",
+		cmd.Process.Pid, time.Since(startedAt).Round(time.Second), lastErr)
 }
 
 // Stop stops the Dolt SQL server.
@@ -716,16 +825,10 @@ func Stop(townRoot string) error {
 	}
 
 	// Wait for graceful shutdown (dolt needs more time)
-	for i := 0; i < 10; i++ {
-		time.Sleep(500 * time.Millisecond)
-		if err := process.Signal(syscall.Signal(0)); err != nil {
-			// Process has exited
-			break
-		}
-	}
-
-	// Check if still running
-	if err := process.Signal(syscall.Signal(0)); err == nil {
+	exited := retry.Poll(context.Background(), OperationPolicy(townRoot, RetryDoltStop), func() bool {
+		return process.Signal(syscall.Signal(0)) != nil
+	})
+	if !exited {
 		// Still running, force kill
 		_ = process.Signal(syscall.SIGKILL)
 		time.Sleep(100 * time.Millisecond)
@@ -735,13 +838,11 @@ func Stop(townRoot string) error {
 	_ = os.Remove(config.PidFile)
 
 	// Update state - preserve historical info
-	state, _ := LoadState(townRoot)
-	if state == nil {
-		state = &State{}
-	}
-	state.Running = false
-	state.PID = 0
-	_ = SaveState(townRoot, state)
+	_ = UpdateState(townRoot, func(state *State) error {
+		state.Running = false
+		state.PID = 0
+		return nil
+	})
 
 	return nil
 }
@@ -862,28 +963,17 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 	// after a recent start (Start() only waits 500ms + process-alive check).
 	// Both reachability and query are inside the loop so transient startup
 	// failures are retried.
-	const baseBackoff = 1 * time.Second
-	const maxBackoff = 8 * time.Second
-	var lastErr error
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
+	policy := OperationPolicy(townRoot, RetryDoltVerify)
+	policy.MaxAttempts = maxAttempts
+	var output []byte
+	err = retry.Do(context.Background(), policy, func() error {
 		// Check if the server is reachable (TCP-level).
 		if reachErr := CheckServerReachable(townRoot); reachErr != nil {
-			lastErr = fmt.Errorf("server not reachable: %w", reachErr)
-			if attempt < maxAttempts {
-				backoff := baseBackoff
-				for i := 1; i < attempt; i++ {
-					backoff *= 2
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-						break
-					}
-				}
-				time.Sleep(backoff)
-			}
-			continue
+			return fmt.Errorf("server not reachable: %w", reachErr)
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 		cmd := buildDoltSQLCmd(ctx, config,
 			"-r", "json",
 			"-q", "SHOW DATABASES",
@@ -895,45 +985,35 @@ func verifyDatabasesWithRetry(townRoot string, maxAttempts int) (served, missing
 		// for the same reason.
 		var stderrBuf bytes.Buffer
 		cmd.Stderr = &stderrBuf
-		output, queryErr := cmd.Output()
-		cancel()
+		var queryErr error
+		output, queryErr = cmd.Output()
 		if queryErr != nil {
 			stderrMsg := strings.TrimSpace(stderrBuf.String())
 			errDetail := strings.TrimSpace(string(output))
 			if stderrMsg != "" {
 				errDetail = errDetail + " (stderr: " + stderrMsg + ")"
 			}
-			lastErr = fmt.Errorf("querying SHOW DATABASES: %w (output: %s)", queryErr, errDetail)
-			if attempt < maxAttempts {
-				backoff := baseBackoff
-				for i := 1; i < attempt; i++ {
-					backoff *= 2
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-						break
-					}
-				}
-				time.Sleep(backoff)
-			}
-			continue
-		}
-
-		var parseErr error
-		served, parseErr = parseShowDatabases(output)
-		if parseErr != nil {
-			return nil, nil, fmt.Errorf("parsing SHOW DATABASES output: %w", parseErr)
+			return fmt.Errorf("querying SHOW DATABASES: %w (output: %s)", queryErr, errDetail)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-		// Compare against filesystem databases.
-		fsDatabases, fsErr := ListDatabases(townRoot)
-		if fsErr != nil {
-			return served, nil, fmt.Errorf("listing filesystem databases: %w", fsErr)
-		}
+	served, err = parseShowDatabases(output)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing SHOW DATABASES output: %w", err)
+	}
 
-		missing = findMissingDatabases(served, fsDatabases)
-		return served, missing, nil
+	// Compare against filesystem databases.
+	fsDatabases, fsErr := ListDatabases(townRoot)
+	if fsErr != nil {
+		return served, nil, fmt.Errorf("listing filesystem databases: %w", fsErr)
 	}
-	return nil, nil, lastErr
+
+	missing = findMissingDatabases(served, fsDatabases)
+	return served, missing, nil
 }
 
 // systemDatabases is the set of Dolt/MySQL internal databases that should be
@@ -1108,7 +1188,7 @@ func InitRig(townRoot, rigName string) (serverWasRunning bool, created bool, err
 
 		cmd := exec.Command("dolt", "init")
 		cmd.Dir = rigDir
-		output, err := cmd.CombinedOutput()
+		output, err := util.CombinedOutput(cmd, util.DoltTimeout)
 		if err != nil {
 			return false, false, fmt.Errorf("initializing Dolt database: %w\n%s", err, output)
 		}
@@ -1128,6 +1208,10 @@ type Migration struct {
 	RigName    string
 	SourcePath string
 	TargetPath string
+
+	// RigPath is the rig directory the database was found in; empty for hq.
+	// It differs from <townRoot>/<RigName> for rigs in nested layouts.
+	RigPath string
 }
 
 // findLocalDoltDB scans beadsDir/dolt/ for a subdirectory containing a .dolt
@@ -1194,7 +1278,7 @@ func FindMigratableDatabases(townRoot string) []Migration {
 	if townSource != "" {
 		// Check target doesn't already have data
 		targetDir := filepath.Join(config.DataDir, "hq")
-		if _, err := os.Stat(filepath.Join(targetDir, ".dolt")); os.IsNotExist(err) {
+		if _, err := os.Stat(filepath.Join(targetDir, ".dolt")); os.IsNotExist(err) || moveInProgress(targetDir) {
 			migrations = append(migrations, Migration{
 				RigName:    "hq",
 				SourcePath: townSource,
@@ -1203,54 +1287,183 @@ func FindMigratableDatabases(townRoot string) []Migration {
 		}
 	}
 
-	// Check rig-level beads databases
-	// Look for directories in townRoot, following .beads/redirect if present
-	entries, err := os.ReadDir(townRoot)
-	if err != nil {
-		return migrations
+	// Check rig-level beads databases, following .beads/redirect if present
+	for _, rig := range discoverRigDatabases(townRoot) {
+		// Dolt database names are case-insensitive: .dolt-data/Gastown
+		// already holds the gastown rig. An interrupted move still needs
+		// finishing.
+		if hasDatabaseFold(config.DataDir, rig.name) && !moveInProgress(filepath.Join(config.DataDir, rig.name)) {
+			continue
+		}
+		migrations = append(migrations, Migration{
+			RigName:    rig.name,
+			SourcePath: rig.source,
+			TargetPath: filepath.Join(config.DataDir, rig.name),
+			RigPath:    rig.path,
+		})
+	}
+
+	return migrations
+}
+
+// discoveredRig is a rig directory holding an embedded Dolt database.
+type discoveredRig struct {
+	name   string // database name: the rig directory's base name
+	path   string // rig directory
+	source string // embedded database directory
+}
+
+// discoverRigDatabases finds rig directories with embedded Dolt databases.
+// Rigs registered in rigs.json come first; the filesystem scan described by
+// the mayor config's rig_discovery adds unregistered ones. Names are
+// compared case-insensitively, and a later directory whose name is taken
+// (including "hq") is skipped with a warning.
+func discoverRigDatabases(townRoot string) []discoveredRig {
+	disc := &config.RigDiscoveryConfig{}
+	if mayorCfg, err := config.LoadMayorConfig(constants.MayorConfigPath(townRoot)); err == nil && mayorCfg.RigDiscovery != nil {
+		disc = mayorCfg.RigDiscovery
 	}
 
+	var rigs []discoveredRig
+	taken := map[string]string{"hq": townRoot} // lower-cased name -> directory
+	add := func(name, dir string) {
+		source := findLocalDoltDB(beads.ResolveBeadsDir(dir))
+		if source == "" {
+			return
+		}
+		key := strings.ToLower(name)
+		if prev, ok := taken[key]; ok {
+			if !sameDir(prev, dir) {
+				fmt.Fprintf(os.Stderr, "[doltserver] Warning: skipping %s: database name %q is already used by %s\n", dir, name, prev)
+			}
+			return
+		}
+		taken[key] = dir
+		rigs = append(rigs, discoveredRig{name: name, path: dir, source: source})
+	}
+
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		names := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			add(name, filepath.Join(townRoot, name))
+		}
+	}
+
+	depth := disc.ScanDepth
+	if depth <= 0 {
+		depth = 1
+	}
+	scanRigDirs(townRoot, "", depth, disc, add)
+	return rigs
+}
+
+// scanRigDirs offers each directory under townRoot/rel to add, descending
+// up to depth levels but not into dot-directories, excluded directories, or
+// directories with their own .beads.
+func scanRigDirs(townRoot, rel string, depth int, disc *config.RigDiscoveryConfig, add func(name, dir string)) {
+	entries, err := os.ReadDir(filepath.Join(townRoot, filepath.FromSlash(rel)))
+	if err != nil {
+		return
+	}
 	for _, entry := range entries {
-		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+		if !entry.IsDir() {
+			continue
+		}
+		relPath := path.Join(rel, entry.Name())
+		if matchGlobFold(disc.Exclude, relPath) {
+			continue
+		}
+		dir := filepath.Join(townRoot, filepath.FromSlash(relPath))
+		included := len(disc.Include) == 0 || matchGlobFold(disc.Include, relPath)
+		if strings.HasPrefix(entry.Name(), ".") {
+			if len(disc.Include) > 0 && included {
+				add(entry.Name(), dir)
+			}
 			continue
 		}
+		if included {
+			add(entry.Name(), dir)
+		}
+		if depth > 1 {
+			if _, err := os.Stat(filepath.Join(dir, ".beads")); os.IsNotExist(err) {
+				scanRigDirs(townRoot, relPath, depth-1, disc, add)
+			}
+		}
+	}
+}
 
-		rigName := entry.Name()
-		resolvedBeadsDir := beads.ResolveBeadsDir(filepath.Join(townRoot, rigName))
-		rigSource := findLocalDoltDB(resolvedBeadsDir)
+// matchGlobFold reports whether name matches any of patterns, ignoring case.
+func matchGlobFold(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
 
-		if rigSource != "" {
-			// Check target doesn't already have data
-			targetDir := filepath.Join(config.DataDir, rigName)
-			if _, err := os.Stat(filepath.Join(targetDir, ".dolt")); os.IsNotExist(err) {
-				migrations = append(migrations, Migration{
-					RigName:    rigName,
-					SourcePath: rigSource,
-					TargetPath: targetDir,
-				})
-			}
+// hasDatabaseFold reports whether dataDir holds a Dolt database named name,
+// ignoring case.
+func hasDatabaseFold(dataDir, name string) bool {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !strings.EqualFold(e.Name(), name) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dataDir, e.Name(), ".dolt")); err == nil {
+			return true
 		}
 	}
+	return false
+}
 
-	return migrations
+// sameDir reports whether a and b are the same directory, which on a
+// case-insensitive filesystem they can be despite differing names.
+func sameDir(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	return err == nil && os.SameFile(ai, bi)
 }
 
 // MigrateRigFromBeads migrates an existing beads Dolt database to the data directory.
 // This is used to migrate from the old per-rig .beads/dolt/<db_name> layout to the new
 // centralized .dolt-data/<rigname> layout.
 func MigrateRigFromBeads(townRoot, rigName, sourcePath string) error {
+	return MigrateRigFromBeadsWithProgress(townRoot, rigName, sourcePath, nil)
+}
+
+// MigrateRigFromBeadsWithProgress is MigrateRigFromBeads reporting the copy
+// to bar, which may be nil, when the move crosses filesystems.
+func MigrateRigFromBeadsWithProgress(townRoot, rigName, sourcePath string, bar *progress.Bar) error {
 	config := DefaultConfig(townRoot)
 
 	targetDir := filepath.Join(config.DataDir, rigName)
 
-	// Check if target already exists
-	if _, err := os.Stat(filepath.Join(targetDir, ".dolt")); err == nil {
-		return fmt.Errorf("rig database %q already exists at %s", rigName, targetDir)
-	}
+	// An interrupted cross-filesystem move is resumed by moveDir, whatever
+	// state it left the target and source in.
+	if !moveInProgress(targetDir) {
+		// Check if target already exists
+		if _, err := os.Stat(filepath.Join(targetDir, ".dolt")); err == nil {
+			return fmt.Errorf("rig database %q already exists at %s", rigName, targetDir)
+		}
 
-	// Check if source exists
-	if _, err := os.Stat(filepath.Join(sourcePath, ".dolt")); os.IsNotExist(err) {
-		return fmt.Errorf("source database not found at %s", sourcePath)
+		// Check if source exists
+		if _, err := os.Stat(filepath.Join(sourcePath, ".dolt")); os.IsNotExist(err) {
+			return fmt.Errorf("source database not found at %s", sourcePath)
+		}
 	}
 
 	// Ensure data directory exists
@@ -1259,7 +1472,7 @@ func MigrateRigFromBeads(townRoot, rigName, sourcePath string) error {
 	}
 
 	// Move the database directory (with cross-filesystem fallback)
-	if err := moveDir(sourcePath, targetDir); err != nil {
+	if err := moveDir(sourcePath, targetDir, bar); err != nil {
 		return fmt.Errorf("moving database: %w", err)
 	}
 
@@ -1554,14 +1767,32 @@ func RepairWorkspace(townRoot string, ws BrokenWorkspace) (string, error) {
 // For other rigs, it writes to mayor/rig/.beads/metadata.json if that path exists,
 // otherwise to <townRoot>/<rigName>/.beads/metadata.json.
 func EnsureMetadata(townRoot, rigName string) error {
+	_, err := ensureMetadata(townRoot, rigName)
+	return err
+}
+
+// ensureMetadata is EnsureMetadata, also reporting whether metadata.json
+// was changed. An up-to-date file is left untouched.
+func ensureMetadata(townRoot, rigName string) (changed bool, err error) {
 	// Use FindOrCreateRigBeadsDir to atomically resolve and create the directory,
 	// avoiding the TOCTOU race where the directory state changes between
 	// FindRigBeadsDir's Stat check and our subsequent file operations.
 	beadsDir, err := FindOrCreateRigBeadsDir(townRoot, rigName)
 	if err != nil {
-		return fmt.Errorf("resolving beads directory for rig %q: %w", rigName, err)
+		return false, fmt.Errorf("resolving beads directory for rig %q: %w", rigName, err)
 	}
+	return ensureMetadataIn(townRoot, rigName, beadsDir)
+}
+
+// EnsureMetadataIn is EnsureMetadata for a rig whose .beads directory isn't
+// at the usual place under the town root, such as a nested rig found by
+// FindMigratableDatabases.
+func EnsureMetadataIn(townRoot, rigName, beadsDir string) error {
+	_, err := ensureMetadataIn(townRoot, rigName, beadsDir)
+	return err
+}
 
+func ensureMetadataIn(townRoot, rigName, beadsDir string) (changed bool, err error) {
 	metadataPath := filepath.Join(beadsDir, "metadata.json")
 
 	// Acquire per-path mutex for goroutine synchronization.
@@ -1573,8 +1804,15 @@ func EnsureMetadata(townRoot, rigName string) error {
 
 	// Load existing metadata if present (preserve any extra fields)
 	existing := make(map[string]interface{})
-	if data, err := os.ReadFile(metadataPath); err == nil {
-		_ = json.Unmarshal(data, &existing) // best effort
+	current, _ := os.ReadFile(metadataPath)
+	if current != nil {
+		_ = json.Unmarshal(current, &existing) // best effort
+	}
+
+	// A rig on a fallback snapshot keeps the mode Failover gave it until
+	// ReconcileFallback moves it back to the server.
+	if existing["gt_fallback"] != nil {
+		return false, nil
 	}
 
 	// Patch dolt server fields. Only set fields that are gastown's responsibility
@@ -1587,42 +1825,117 @@ func EnsureMetadata(townRoot, rigName string) error {
 		existing["dolt_database"] = rigName
 	}
 
+	// Record a non-default server port so bd connects to the right server.
+	if port := DefaultConfig(townRoot).Port; port != DefaultPort {
+		existing["dolt_server_port"] = port
+	} else {
+		delete(existing, "dolt_server_port")
+	}
+
 	// Always set jsonl_export to the canonical filename.
 	// Historical migrations may have left stale values (e.g., "beads.jsonl").
 	existing["jsonl_export"] = "issues.jsonl"
 
 	data, err := json.MarshalIndent(existing, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling metadata: %w", err)
+		return false, fmt.Errorf("marshaling metadata: %w", err)
+	}
+	data = append(data, '\n')
+	if bytes.Equal(data, current) {
+		return false, nil
 	}
 
-	if err := util.AtomicWriteFile(metadataPath, append(data, '\n'), 0600); err != nil {
-		return fmt.Errorf("writing metadata.json: %w", err)
+	if err := util.AtomicWriteFile(metadataPath, data, 0600); err != nil {
+		return false, fmt.Errorf("writing metadata.json: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 // EnsureAllMetadata updates metadata.json for all rig databases known to the
 // Dolt server. This is the fix for the split-brain problem where worktrees
-// each have their own isolated database.
+// each have their own isolated database. updated lists every rig whose
+// metadata.json is now correct, whether or not it had to be rewritten.
 func EnsureAllMetadata(townRoot string) (updated []string, errs []error) {
-	databases, err := ListDatabases(townRoot)
+	results, err := EnsureAllMetadataParallel(townRoot, DefaultMetadataWorkers, nil)
 	if err != nil {
-		return nil, []error{fmt.Errorf("listing databases: %w", err)}
+		return nil, []error{err}
 	}
 
-	for _, dbName := range databases {
-		if err := EnsureMetadata(townRoot, dbName); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", dbName, err))
+	for _, r := range results {
+		if r.Status == MetadataError {
+			errs = append(errs, fmt.Errorf("%s: %s", r.Rig, r.Error))
 		} else {
-			updated = append(updated, dbName)
+			updated = append(updated, r.Rig)
 		}
 	}
 
 	return updated, errs
 }
 
+// DefaultMetadataWorkers bounds how many rigs EnsureAllMetadata updates at once.
+const DefaultMetadataWorkers = 8
+
+// MetadataStatus is the outcome of ensuring one rig's metadata.json.
+type MetadataStatus string
+
+const (
+	MetadataUpdated   MetadataStatus = "updated"
+	MetadataUnchanged MetadataStatus = "unchanged"
+	MetadataError     MetadataStatus = "error"
+)
+
+// MetadataResult is the outcome for one rig from EnsureAllMetadataParallel.
+type MetadataResult struct {
+	Rig    string         `json:"rig"`
+	Status MetadataStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// EnsureAllMetadataParallel is EnsureAllMetadata with up to workers rigs
+// updated at once. progress, if non-nil, is called as each rig finishes
+// (never concurrently). Results are returned in database order; the error
+// is only for failing to list databases.
+func EnsureAllMetadataParallel(townRoot string, workers int, progress func(MetadataResult)) ([]MetadataResult, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]MetadataResult, len(databases))
+	var progressMu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i, dbName := range databases {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r := MetadataResult{Rig: dbName, Status: MetadataUnchanged}
+			if changed, err := ensureMetadata(townRoot, dbName); err != nil {
+				r.Status, r.Error = MetadataError, err.Error()
+			} else if changed {
+				r.Status = MetadataUpdated
+			}
+			results[i] = r
+
+			if progress != nil {
+				progressMu.Lock()
+				progress(r)
+				progressMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
 // FindRigBeadsDir returns the .beads directory path for a rig (read-only lookup).
 // For "hq", returns <townRoot>/.beads.
 // For other rigs, returns <townRoot>/<rigName>/mayor/rig/.beads if it exists,
@@ -1657,6 +1970,15 @@ func FindRigBeadsDir(townRoot, rigName string) string {
 	return rigBeads
 }
 
+// RigDatabase returns the Dolt database backing rigName's beads: the one
+// named in its metadata.json, falling back to the rig name.
+func RigDatabase(townRoot, rigName string) string {
+	if meta, err := beads.ReadBackendMetadata(FindRigBeadsDir(townRoot, rigName)); err == nil && meta.DoltDatabase != "" {
+		return meta.DoltDatabase
+	}
+	return rigName
+}
+
 // FindOrCreateRigBeadsDir atomically resolves and ensures the .beads directory
 // exists for a rig. Unlike FindRigBeadsDir, this combines directory resolution
 // with creation to avoid TOCTOU races where the directory state changes between
@@ -1790,6 +2112,9 @@ type HealthMetrics struct {
 	// DiskUsageHuman is a human-readable disk usage string.
 	DiskUsageHuman string `json:"disk_usage_human"`
 
+	// DatabaseSizes breaks DiskUsageBytes down by database.
+	DatabaseSizes []DatabaseSize `json:"database_sizes,omitempty"`
+
 	// QueryLatency is the time taken for a SELECT 1 round-trip.
 	QueryLatency time.Duration `json:"query_latency_ms"`
 
@@ -1802,6 +2127,9 @@ type HealthMetrics struct {
 
 	// Warnings contains any degradation warnings (non-fatal).
 	Warnings []string `json:"warnings,omitempty"`
+
+	// Databases is per-database health, filled in by GetDeepHealthMetrics.
+	Databases []DatabaseHealth `json:"databases,omitempty"`
 }
 
 // GetHealthMetrics collects resource monitoring metrics from the Dolt server.
@@ -1839,10 +2167,12 @@ func GetHealthMetrics(townRoot string) *HealthMetrics {
 		}
 	}
 
-	// 3. Disk usage
-	diskBytes := dirSize(config.DataDir)
-	metrics.DiskUsageBytes = diskBytes
-	metrics.DiskUsageHuman = formatBytes(diskBytes)
+	// 3. Disk usage (cached; only databases written since the last check are walked)
+	if usage, err := NewSizeCollector(townRoot).Collect(); err == nil {
+		metrics.DiskUsageBytes = usage.TotalBytes
+		metrics.DatabaseSizes = usage.Databases
+	}
+	metrics.DiskUsageHuman = formatBytes(metrics.DiskUsageBytes)
 
 	// 4. Read-only probe: attempt a test write
 	readOnly, _ := CheckReadOnly(townRoot)
@@ -1861,15 +2191,17 @@ func GetHealthMetrics(townRoot string) *HealthMetrics {
 // ("cannot update manifest: database is read only") and will NOT self-recover.
 // Returns (true, nil) if read-only, (false, nil) if writable, (false, err) on probe failure.
 func CheckReadOnly(townRoot string) (bool, error) {
-	config := DefaultConfig(townRoot)
-
 	// Need a database to test writes against
 	databases, err := ListDatabases(townRoot)
 	if err != nil || len(databases) == 0 {
 		return false, nil // Can't probe without a database
 	}
+	return CheckReadOnlyDatabase(townRoot, databases[0])
+}
 
-	db := databases[0]
+// CheckReadOnlyDatabase is CheckReadOnly for one named database.
+func CheckReadOnlyDatabase(townRoot, db string) (bool, error) {
+	config := DefaultConfig(townRoot)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -1896,10 +2228,7 @@ func CheckReadOnly(townRoot string) (bool, error) {
 // IsReadOnlyError checks if an error message indicates a Dolt read-only state.
 // The characteristic error is "cannot update manifest: database is read only".
 func IsReadOnlyError(msg string) bool {
-	lower := strings.ToLower(msg)
-	return strings.Contains(lower, "read only") ||
-		strings.Contains(lower, "read-only") ||
-		strings.Contains(lower, "readonly")
+	return errclass.ClassifyMessage(msg) == errclass.ReadOnly
 }
 
 // RecoverReadOnly detects a read-only Dolt server, restarts it, and verifies
@@ -1933,35 +2262,27 @@ func RecoverReadOnly(townRoot string) error {
 	}
 
 	// Verify recovery with exponential backoff (server may need time to become writable)
-	const maxAttempts = 5
-	const baseBackoff = 500 * time.Millisecond
-	const maxBackoff = 8 * time.Second
-
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		backoff := baseBackoff
-		for i := 1; i < attempt; i++ {
-			backoff *= 2
-			if backoff > maxBackoff {
-				backoff = maxBackoff
-				break
-			}
-		}
-		time.Sleep(backoff)
-
-		readOnly, err = CheckReadOnly(townRoot)
+	attempts := 0
+	errStillReadOnly := errors.New("still read-only")
+	err = retry.Do(context.Background(), OperationPolicy(townRoot, RetryDoltRecover), func() error {
+		attempts++
+		readOnly, err := CheckReadOnly(townRoot)
 		if err != nil {
-			if attempt == maxAttempts {
-				return fmt.Errorf("post-restart probe failed after %d attempts: %w", maxAttempts, err)
-			}
-			continue
+			return fmt.Errorf("post-restart probe failed: %w", err)
 		}
-		if !readOnly {
-			fmt.Printf("Dolt server recovered from read-only state\n")
-			return nil
+		if readOnly {
+			return errStillReadOnly
 		}
+		return nil
+	})
+	if errors.Is(err, errStillReadOnly) {
+		return fmt.Errorf("Dolt server still read-only after restart (%d verification attempts)", attempts)
 	}
-
-	return fmt.Errorf("Dolt server still read-only after restart (%d verification attempts)", maxAttempts)
+	if err != nil {
+		return fmt.Errorf("%w (%d attempts)", err, attempts)
+	}
+	fmt.Printf("Dolt server recovered from read-only state\n")
+	return nil
 }
 
 // doltSQLWithRecovery executes a SQL statement with retry logic and, if retries
@@ -1974,7 +2295,7 @@ func doltSQLWithRecovery(townRoot, rigDB, query string) error {
 	}
 
 	// If the final error is a read-only error, attempt recovery
-	if !IsReadOnlyError(err.Error()) {
+	if !errclass.Is(err, errclass.ReadOnly) {
 		return err
 	}
 
@@ -2042,44 +2363,14 @@ func formatBytes(b int64) string {
 	}
 }
 
-// moveDir moves a directory from src to dest. It first tries os.Rename for
-// efficiency, but falls back to copy+delete if src and dest are on different
-// filesystems (which causes EXDEV error on rename).
-func moveDir(src, dest string) error {
-	if err := os.Rename(src, dest); err == nil {
-		return nil
-	} else if !errors.Is(err, syscall.EXDEV) {
-		return err
-	}
-
-	// Cross-filesystem: copy then delete source
-	if runtime.GOOS == "windows" {
-		cmd := exec.Command("robocopy", src, dest, "/E", "/MOVE", "/R:1", "/W:1")
-		if err := cmd.Run(); err != nil {
-			// robocopy returns 1 for success with copies
-			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() <= 7 {
-				return nil
-			}
-			return fmt.Errorf("robocopy: %w", err)
-		}
-		return nil
-	}
-	cmd := exec.Command("cp", "-a", src, dest)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("copying directory: %w", err)
-	}
-	if err := os.RemoveAll(src); err != nil {
-		return fmt.Errorf("removing source after copy: %w", err)
-	}
-	return nil
-}
-
 // serverExecSQL executes a SQL statement against the Dolt server without targeting
 // a specific database. Used for server-level commands like CREATE DATABASE.
-func serverExecSQL(townRoot, query string) error {
+func serverExecSQL(townRoot, query string) (err error) {
 	config := DefaultConfig(townRoot)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
+	ctx, span := startSQLSpan(ctx, "", query)
+	defer func() { telemetry.End(span, err) }()
 
 	cmd := buildDoltSQLCmd(ctx, config, "-q", query)
 	output, err := cmd.CombinedOutput()
@@ -2096,102 +2387,61 @@ func serverExecSQL(townRoot, query string) error {
 // Only retries on catalog-race errors ("Unknown database"); returns immediately for
 // other failures (e.g., server crash, binary missing).
 func waitForCatalog(townRoot, dbName string) error {
-	const maxAttempts = 5
-	const baseBackoff = 100 * time.Millisecond
-	const maxBackoff = 2 * time.Second
-
 	query := fmt.Sprintf("USE %s", dbName)
-	var lastErr error
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		if err := serverExecSQL(townRoot, query); err != nil {
-			lastErr = err
-			// Only retry catalog-race errors; fail fast on other errors
-			// (connection refused, binary missing, etc.)
-			if !strings.Contains(err.Error(), "Unknown database") {
-				return fmt.Errorf("database %q probe failed (non-retryable): %w", dbName, err)
-			}
-			if attempt < maxAttempts {
-				backoff := baseBackoff
-				for i := 1; i < attempt; i++ {
-					backoff *= 2
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-						break
-					}
-				}
-				time.Sleep(backoff)
-			}
-			continue
+	attempts := 0
+	err := retry.Do(context.Background(), OperationPolicy(townRoot, RetryDoltCatalog), func() error {
+		attempts++
+		err := serverExecSQL(townRoot, query)
+		// Only retry catalog-race errors; fail fast on other errors
+		// (connection refused, binary missing, etc.)
+		if err != nil && !strings.Contains(err.Error(), "Unknown database") {
+			return retry.Stop(fmt.Errorf("database %q probe failed (non-retryable): %w", dbName, err))
 		}
-		return nil
+		return err
+	})
+	if err != nil && strings.Contains(err.Error(), "Unknown database") {
+		return fmt.Errorf("database %q not visible after %d attempts: %w", dbName, attempts, err)
 	}
-	return fmt.Errorf("database %q not visible after %d attempts: %w", dbName, maxAttempts, lastErr)
+	return err
 }
 
 // doltSQL executes a SQL statement against a specific rig database on the Dolt server.
 // Uses the dolt CLI from the data directory (auto-detects running server).
 // The USE prefix selects the database since --use-db is not available on all dolt versions.
-func doltSQL(townRoot, rigDB, query string) error {
+func doltSQL(townRoot, rigDB, query string) (err error) {
 	config := DefaultConfig(townRoot)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
+	ctx, span := startSQLSpan(ctx, rigDB, query)
+	defer func() { telemetry.End(span, err) }()
 
 	// Prepend USE <db> to select the target database.
 	fullQuery := fmt.Sprintf("USE %s; %s", rigDB, query)
 	cmd := buildDoltSQLCmd(ctx, config, "-q", fullQuery)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+		return sqlTimeoutRetryable(ctx, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output))))
 	}
 	return nil
 }
 
-// doltSQLWithRetry executes a SQL statement with exponential backoff on transient errors.
-func doltSQLWithRetry(townRoot, rigDB, query string) error {
-	const maxRetries = 5
-	const baseBackoff = 500 * time.Millisecond
-	const maxBackoff = 15 * time.Second
-
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if err := doltSQL(townRoot, rigDB, query); err != nil {
-			lastErr = err
-			if !isDoltRetryableError(err) {
-				return err
-			}
-			if attempt < maxRetries {
-				backoff := baseBackoff
-				for i := 1; i < attempt; i++ {
-					backoff *= 2
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-						break
-					}
-				}
-				time.Sleep(backoff)
-			}
-			continue
-		}
-		return nil
+// sqlTimeoutRetryable tags err as retryable if ctx timed out. dolt sql is
+// killed when its deadline passes, which reads as an unclassified exit
+// status, but a statement that ran out of time is contention on a busy
+// server rather than a bad query.
+func sqlTimeoutRetryable(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errclass.Wrap(errclass.Retryable, err)
 	}
-	return fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
+	return err
 }
 
-// isDoltRetryableError returns true if the error is a transient Dolt failure worth retrying.
-// Covers manifest lock contention, read-only mode, optimistic lock failures, timeouts,
-// and catalog propagation delays after CREATE DATABASE.
-func isDoltRetryableError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := err.Error()
-	return strings.Contains(msg, "database is read only") ||
-		strings.Contains(msg, "cannot update manifest") ||
-		strings.Contains(msg, "optimistic lock") ||
-		strings.Contains(msg, "serialization failure") ||
-		strings.Contains(msg, "lock wait timeout") ||
-		strings.Contains(msg, "try restarting transaction") ||
-		strings.Contains(msg, "Unknown database")
+// doltSQLWithRetry executes a SQL statement, retrying transient errors per the
+// town's retry policies (see RetryPolicies).
+func doltSQLWithRetry(townRoot, rigDB, query string) error {
+	return errclass.Retry(context.Background(), RetryPolicies(townRoot), func() error {
+		return doltSQL(townRoot, rigDB, query)
+	})
 }
 
 // validBranchNameRe matches only safe branch name characters: alphanumeric, hyphen,
@@ -2258,24 +2508,34 @@ func CommitServerWorkingSet(townRoot, rigDB, message string) error {
 // MergePolecatBranch merges a polecat's Dolt branch into main and deletes it.
 // Called at gt done time to make the polecat's beads changes visible.
 //
-// CRITICAL: The entire operation runs as a single SQL script (one connection).
+// CRITICAL: Each step runs as a single SQL script (one connection).
 // In Dolt server mode, each `dolt sql -q` call opens a new connection, and
 // DOLT_CHECKOUT only affects the current connection. Separate calls would
 // checkout the polecat branch on connection 1, then ADD/COMMIT on connection 2
 // (which defaults back to main), silently losing all polecat working set data.
 //
-// The script handles two scenarios:
-//  1. Fast-forward merge (no conflict): commit polecat working set, merge to main
+// The working sets of main and the polecat branch are committed first, so
+// the conflict preview covers everything the merge applies. Then:
+//  1. Fast-forward merge (no conflict): merge to main
 //  2. Conflict: disable autocommit, merge, resolve with --theirs (polecat wins), commit
 //
 // On conflict, a second script runs with autocommit disabled so conflicts can
 // be resolved rather than triggering an automatic rollback.
 func MergePolecatBranch(townRoot, rigDB, branchName string) error {
+	return MergePolecatBranchWithPolicy(townRoot, rigDB, branchName, config.OnDoltConflictTheirs)
+}
+
+// MergePolecatBranchWithPolicy is MergePolecatBranch with a conflict policy
+// (config.OnDoltConflict*). The merge is previewed first; if it would
+// conflict and policy is "escalate", or the conflicts are in the schema
+// (which --theirs can't resolve), nothing is merged and a
+// *MergeConflictError describing the conflicts is returned.
+func MergePolecatBranchWithPolicy(townRoot, rigDB, branchName, policy string) error {
 	if err := validateBranchName(branchName); err != nil {
 		return fmt.Errorf("merging Dolt branch in %s: %w", rigDB, err)
 	}
-
-	// Phase 1: Commit polecat working set and attempt merge.
+	// Phase 1: Commit both working sets, so the preview below sees exactly
+	// what the merge applies, including the polecat's uncommitted writes.
 	// All in one connection so DOLT_CHECKOUT persists across statements.
 	// NOTE: DOLT_BRANCH('-D') is deliberately NOT in the merge scripts.
 	// If the merge fails (conflict), the branch must still exist for Phase 2.
@@ -2287,22 +2547,34 @@ func MergePolecatBranch(townRoot, rigDB, branchName string) error {
 	// this flush, the merge fails and MR beads get stranded on dead polecat
 	// branches, invisible to the refinery.
 	escaped := strings.ReplaceAll(branchName, "'", "''")
-	script := fmt.Sprintf(`USE %s;
+	flushScript := fmt.Sprintf(`USE %s;
 CALL DOLT_ADD('-A');
 CALL DOLT_COMMIT('--allow-empty', '-m', 'auto-flush main before polecat merge');
 CALL DOLT_CHECKOUT('%s');
 CALL DOLT_ADD('-A');
 CALL DOLT_COMMIT('--allow-empty', '-m', 'polecat %s final state');
 CALL DOLT_CHECKOUT('main');
+`, rigDB, escaped, escaped)
+	if err := doltSQLScriptWithRetry(townRoot, flushScript); err != nil {
+		return fmt.Errorf("committing working sets before merging %s in %s: %w", branchName, rigDB, err)
+	}
+
+	if err := checkMergeConflicts(townRoot, rigDB, branchName, policy); err != nil {
+		return err
+	}
+
+	// Phase 2: Merge. A conflict falls through to Phase 3.
+	script := fmt.Sprintf(`USE %s;
+CALL DOLT_CHECKOUT('main');
 CALL DOLT_MERGE('%s');
-`, rigDB, escaped, escaped, escaped)
+`, rigDB, escaped)
 
 	if err := doltSQLScriptWithRetry(townRoot, script); err != nil {
 		if !strings.Contains(err.Error(), "Merge conflict") {
 			return fmt.Errorf("merging %s to main in %s: %w", branchName, rigDB, err)
 		}
 
-		// Phase 2: Conflict detected. Re-run merge with autocommit disabled
+		// Phase 3: Conflict detected. Re-run merge with autocommit disabled
 		// so conflicts are staged (not rolled back) and can be resolved.
 		// --theirs: polecat state wins (latest mutations, always authoritative).
 		fmt.Printf("Dolt merge conflict on %s, auto-resolving (--theirs)...\n", branchName)
@@ -2320,7 +2592,7 @@ SET @@autocommit = 1;
 		}
 	}
 
-	// Delete branch only after successful merge (either phase).
+	// Delete branch only after successful merge (Phase 2 or 3).
 	// This prevents branch loss if the merge script fails partway through.
 	DeletePolecatBranch(townRoot, rigDB, branchName)
 	return nil
@@ -2329,8 +2601,10 @@ SET @@autocommit = 1;
 // doltSQLScript executes a multi-statement SQL script via a temp file.
 // Uses `dolt sql --file` for reliable multi-statement execution within a
 // single connection, preserving DOLT_CHECKOUT state across statements.
-func doltSQLScript(townRoot, script string) error {
+func doltSQLScript(townRoot, script string) (err error) {
 	config := DefaultConfig(townRoot)
+	_, span := startSQLSpan(context.Background(), "", "SCRIPT")
+	defer func() { telemetry.End(span, err) }()
 
 	tmpFile, err := os.CreateTemp("", "dolt-script-*.sql")
 	if err != nil {
@@ -2350,43 +2624,19 @@ func doltSQLScript(townRoot, script string) error {
 	cmd := buildDoltSQLCmd(ctx, config, "--file", tmpFile.Name())
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+		return sqlTimeoutRetryable(ctx, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output))))
 	}
 	return nil
 }
 
-// doltSQLScriptWithRetry executes a SQL script with exponential backoff on transient errors.
+// doltSQLScriptWithRetry executes a SQL script, retrying transient errors.
 // Callers must ensure scripts are idempotent, as partial execution may have occurred
-// before the retry. Uses the same retry classification as doltSQLWithRetry but with
-// fewer retries and shorter backoff since multi-statement scripts are more expensive.
+// before the retry. Uses the same policies as doltSQLWithRetry but with at most
+// scriptMaxAttempts attempts since multi-statement scripts are more expensive.
 func doltSQLScriptWithRetry(townRoot, script string) error {
-	const maxRetries = 3
-	const baseBackoff = 500 * time.Millisecond
-	const maxBackoff = 8 * time.Second
-
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if err := doltSQLScript(townRoot, script); err != nil {
-			lastErr = err
-			if !isDoltRetryableError(err) {
-				return err
-			}
-			if attempt < maxRetries {
-				backoff := baseBackoff
-				for i := 1; i < attempt; i++ {
-					backoff *= 2
-					if backoff > maxBackoff {
-						backoff = maxBackoff
-						break
-					}
-				}
-				time.Sleep(backoff)
-			}
-			continue
-		}
-		return nil
-	}
-	return fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
+	return errclass.Retry(context.Background(), RetryPolicies(townRoot).Limit(scriptMaxAttempts), func() error {
+		return doltSQLScript(townRoot, script)
+	})
 }
 
 // DeletePolecatBranch deletes a polecat's Dolt branch (cleanup/nuke).
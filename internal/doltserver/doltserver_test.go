@@ -3,6 +3,7 @@ package doltserver
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,6 +12,9 @@ import (
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errclass"
 )
 
 // =============================================================================
@@ -462,6 +466,62 @@ func TestEnsureAllMetadata(t *testing.T) {
 	}
 }
 
+func TestEnsureAllMetadataParallel(t *testing.T) {
+	townRoot := t.TempDir()
+
+	rigs := []string{"hq", "rig-a", "rig-b", "rig-c", "rig-d"}
+	for _, name := range rigs {
+		doltDir := filepath.Join(townRoot, ".dolt-data", name, ".dolt")
+		if err := os.MkdirAll(doltDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var progressed int
+	results, err := EnsureAllMetadataParallel(townRoot, 2, func(MetadataResult) { progressed++ })
+	if err != nil {
+		t.Fatalf("EnsureAllMetadataParallel: %v", err)
+	}
+	if progressed != len(rigs) {
+		t.Errorf("progress called %d times, want %d", progressed, len(rigs))
+	}
+	if len(results) != len(rigs) {
+		t.Fatalf("got %d results, want %d", len(results), len(rigs))
+	}
+	for _, r := range results {
+		if r.Status != MetadataUpdated {
+			t.Errorf("first pass %s: status %q (%s), want updated", r.Rig, r.Status, r.Error)
+		}
+	}
+
+	// Second pass finds everything already correct.
+	results, err = EnsureAllMetadataParallel(townRoot, 2, nil)
+	if err != nil {
+		t.Fatalf("EnsureAllMetadataParallel: %v", err)
+	}
+	for _, r := range results {
+		if r.Status != MetadataUnchanged {
+			t.Errorf("second pass %s: status %q, want unchanged", r.Rig, r.Status)
+		}
+	}
+
+	// A stale field is rewritten, the rest left alone.
+	metadataPath := filepath.Join(FindRigBeadsDir(townRoot, "rig-b"), "metadata.json")
+	if err := os.WriteFile(metadataPath, []byte(`{"jsonl_export":"beads.jsonl"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	results, _ = EnsureAllMetadataParallel(townRoot, 2, nil)
+	for _, r := range results {
+		want := MetadataUnchanged
+		if r.Rig == "rig-b" {
+			want = MetadataUpdated
+		}
+		if r.Status != want {
+			t.Errorf("third pass %s: status %q, want %q", r.Rig, r.Status, want)
+		}
+	}
+}
+
 func TestFindRigBeadsDir(t *testing.T) {
 	townRoot := t.TempDir()
 
@@ -676,7 +736,7 @@ func TestMoveDir_SameFilesystem(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := moveDir(src, dest); err != nil {
+	if err := moveDir(src, dest, nil); err != nil {
 		t.Fatalf("moveDir failed: %v", err)
 	}
 
@@ -1836,7 +1896,7 @@ func TestInitRig_InvalidCharacters(t *testing.T) {
 
 
 // =============================================================================
-// Catalog race condition tests (isDoltRetryableError coverage)
+// Catalog race condition tests (errclass.Classify coverage)
 // =============================================================================
 
 func TestIsDoltRetryableError_CatalogRace(t *testing.T) {
@@ -1851,8 +1911,8 @@ func TestIsDoltRetryableError_CatalogRace(t *testing.T) {
 	}
 	for _, msg := range catalogErrors {
 		err := fmt.Errorf("%s", msg)
-		if !isDoltRetryableError(err) {
-			t.Errorf("isDoltRetryableError(%q) = false, want true (catalog race)", msg)
+		if !errclass.Classify(err).Transient() {
+			t.Errorf("errclass.Classify(%q).Transient() = false, want true (catalog race)", msg)
 		}
 	}
 }
@@ -2028,6 +2088,87 @@ func TestLoadState_CorruptJSON(t *testing.T) {
 	}
 }
 
+func TestUpdateState_Concurrent(t *testing.T) {
+	townRoot := t.TempDir()
+
+	// Each updater appends its own database; a lost update drops one.
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := UpdateState(townRoot, func(s *State) error {
+				s.Databases = append(s.Databases, fmt.Sprintf("db%d", i))
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Databases) != n {
+		t.Errorf("got %d databases after %d concurrent updates: %v", len(state.Databases), n, state.Databases)
+	}
+	if state.Version != StateVersion {
+		t.Errorf("Version = %d, want %d", state.Version, StateVersion)
+	}
+}
+
+func TestLoadState_MigratesUnversioned(t *testing.T) {
+	townRoot := t.TempDir()
+	stateFile := StateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `{"running":true,"pid":42,"port":3307,"databases":["hq","gastown","hq",""]}`
+	if err := os.WriteFile(stateFile, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != StateVersion || state.PID != 42 {
+		t.Errorf("migrated state = %+v", state)
+	}
+	if got := strings.Join(state.Databases, ","); got != "hq,gastown" {
+		t.Errorf("Databases = %q, want hq,gastown", got)
+	}
+}
+
+func TestState_NewerVersionUntouched(t *testing.T) {
+	townRoot := t.TempDir()
+	stateFile := StateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	future := fmt.Sprintf(`{"version":%d,"running":true}`, StateVersion+1)
+	if err := os.WriteFile(stateFile, []byte(future), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadState(townRoot); !errors.Is(err, ErrNewerState) {
+		t.Errorf("LoadState error = %v, want ErrNewerState", err)
+	}
+	if err := SaveState(townRoot, &State{}); !errors.Is(err, ErrNewerState) {
+		t.Errorf("SaveState error = %v, want ErrNewerState", err)
+	}
+	if err := UpdateState(townRoot, func(*State) error { return nil }); !errors.Is(err, ErrNewerState) {
+		t.Errorf("UpdateState error = %v, want ErrNewerState", err)
+	}
+	if data, _ := os.ReadFile(stateFile); string(data) != future {
+		t.Errorf("newer state file was rewritten: %s", data)
+	}
+}
+
 // =============================================================================
 // Rollback round-trip test
 // =============================================================================
@@ -2159,7 +2300,7 @@ func TestFindMigratableDatabases_SkipsDotDirs(t *testing.T) {
 
 func TestMoveDir_SourceNotExists(t *testing.T) {
 	tmpDir := t.TempDir()
-	err := moveDir(filepath.Join(tmpDir, "nonexistent"), filepath.Join(tmpDir, "dest"))
+	err := moveDir(filepath.Join(tmpDir, "nonexistent"), filepath.Join(tmpDir, "dest"), nil)
 	if err == nil {
 		t.Fatal("expected error for nonexistent source")
 	}
@@ -2474,8 +2615,8 @@ func TestIsDoltRetryableError_IncludesReadOnly(t *testing.T) {
 	}
 	for _, tt := range tests {
 		err := fmt.Errorf("%s", tt.msg)
-		if got := isDoltRetryableError(err); got != tt.want {
-			t.Errorf("isDoltRetryableError(%q) = %v, want %v", tt.msg, got, tt.want)
+		if got := errclass.Classify(err).Transient(); got != tt.want {
+			t.Errorf("errclass.Classify(%q).Transient() = %v, want %v", tt.msg, got, tt.want)
 		}
 	}
 }
@@ -2523,7 +2664,7 @@ func TestDoltSQLScriptWithRetry_ImmediateSuccess(t *testing.T) {
 	// doltSQLScriptWithRetry calls doltSQLScript which needs a valid townRoot
 	// with .dolt-data dir and a dolt binary. Since we can't run dolt in CI,
 	// we verify the retry logic by checking that non-retryable errors return
-	// immediately without sleeping (i.e., isDoltRetryableError integration).
+	// immediately without sleeping (i.e., errclass.Classify integration).
 	//
 	// A non-retryable error (e.g., syntax error) should return on first attempt.
 	err := doltSQLScriptWithRetry(t.TempDir(), "INVALID SQL;")
@@ -2540,7 +2681,7 @@ func TestDoltSQLScriptWithRetry_ImmediateSuccess(t *testing.T) {
 }
 
 func TestDoltSQLScriptWithRetry_NonRetryableError(t *testing.T) {
-	// Verify that isDoltRetryableError correctly classifies errors.
+	// Verify that errclass.Classify correctly classifies errors.
 	// Non-retryable errors should fail fast without retry.
 	nonRetryable := []string{
 		"syntax error near 'FOO'",
@@ -2548,8 +2689,8 @@ func TestDoltSQLScriptWithRetry_NonRetryableError(t *testing.T) {
 		"unknown column",
 	}
 	for _, msg := range nonRetryable {
-		if isDoltRetryableError(fmt.Errorf("%s", msg)) {
-			t.Errorf("isDoltRetryableError(%q) = true, want false", msg)
+		if errclass.Classify(fmt.Errorf("%s", msg)).Transient() {
+			t.Errorf("errclass.Classify(%q).Transient() = true, want false", msg)
 		}
 	}
 
@@ -2564,8 +2705,8 @@ func TestDoltSQLScriptWithRetry_NonRetryableError(t *testing.T) {
 		"Unknown database 'myrig'",
 	}
 	for _, msg := range retryable {
-		if !isDoltRetryableError(fmt.Errorf("%s", msg)) {
-			t.Errorf("isDoltRetryableError(%q) = false, want true", msg)
+		if !errclass.Classify(fmt.Errorf("%s", msg)).Transient() {
+			t.Errorf("errclass.Classify(%q).Transient() = false, want true", msg)
 		}
 	}
 }
@@ -3477,3 +3618,118 @@ func TestBuildDoltSQLCmd_RemoteNoPassword(t *testing.T) {
 		}
 	}
 }
+
+// =============================================================================
+// Rig discovery (rigs.json, nested layouts, globs, case)
+// =============================================================================
+
+// writeEmbeddedDB creates an embedded Dolt database under dir/.beads.
+func writeEmbeddedDB(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".beads", "dolt", "beads", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeRigDiscovery(t *testing.T, townRoot string, disc *config.RigDiscoveryConfig) {
+	t.Helper()
+	mayorCfg := config.NewMayorConfig()
+	mayorCfg.RigDiscovery = disc
+	if err := config.SaveMayorConfig(filepath.Join(townRoot, "mayor", "config.json"), mayorCfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func migrationNames(migrations []Migration) []string {
+	var names []string
+	for _, m := range migrations {
+		names = append(names, m.RigName)
+	}
+	return names
+}
+
+func TestFindMigratableDatabases_NestedScanDepth(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEmbeddedDB(t, filepath.Join(townRoot, "clients", "acme", "storefront"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "gastown"))
+	// Inside a rig: never scanned, whatever the depth.
+	writeEmbeddedDB(t, filepath.Join(townRoot, "gastown", "polecats", "nux"))
+
+	if got := migrationNames(FindMigratableDatabases(townRoot)); strings.Join(got, ",") != "gastown" {
+		t.Errorf("default depth: migrations = %v, want [gastown]", got)
+	}
+
+	writeRigDiscovery(t, townRoot, &config.RigDiscoveryConfig{ScanDepth: 3})
+	migrations := FindMigratableDatabases(townRoot)
+	if got := migrationNames(migrations); strings.Join(got, ",") != "storefront,gastown" {
+		t.Fatalf("depth 3: migrations = %v, want [storefront gastown]", got)
+	}
+	want := filepath.Join(townRoot, "clients", "acme", "storefront", ".beads", "dolt", "beads")
+	if migrations[0].SourcePath != want {
+		t.Errorf("SourcePath = %s, want %s", migrations[0].SourcePath, want)
+	}
+}
+
+func TestFindMigratableDatabases_Globs(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEmbeddedDB(t, filepath.Join(townRoot, "Clients", "acme", "storefront"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "Clients", "old", "legacy"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "scratch"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, ".adopted", "vault"))
+
+	writeRigDiscovery(t, townRoot, &config.RigDiscoveryConfig{
+		ScanDepth: 3,
+		Include:   []string{"clients/*/*", ".adopted/*"},
+		Exclude:   []string{"CLIENTS/old"},
+	})
+	// .adopted/vault is two levels down but .adopted itself isn't included,
+	// so the scan doesn't enter it: only an included dot-directory is scanned.
+	if got := migrationNames(FindMigratableDatabases(townRoot)); strings.Join(got, ",") != "storefront" {
+		t.Errorf("migrations = %v, want [storefront]", got)
+	}
+
+	writeEmbeddedDB(t, filepath.Join(townRoot, ".adopted"))
+	writeRigDiscovery(t, townRoot, &config.RigDiscoveryConfig{Include: []string{".adopted"}})
+	if got := migrationNames(FindMigratableDatabases(townRoot)); strings.Join(got, ",") != ".adopted" {
+		t.Errorf("included dot-directory: migrations = %v, want [.adopted]", got)
+	}
+}
+
+func TestFindMigratableDatabases_RigsJSONAuthoritative(t *testing.T) {
+	townRoot := t.TempDir()
+	// A dot-named rig is skipped by the scan but registered in rigs.json.
+	writeEmbeddedDB(t, filepath.Join(townRoot, ".ops"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "beads"))
+	writeRigDiscovery(t, townRoot, &config.RigDiscoveryConfig{Exclude: []string{"*"}})
+
+	rigs := &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: map[string]config.RigEntry{
+		".ops":    {},
+		"missing": {},
+	}}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := migrationNames(FindMigratableDatabases(townRoot)); strings.Join(got, ",") != ".ops" {
+		t.Errorf("migrations = %v, want [.ops] (beads is excluded from the scan)", got)
+	}
+}
+
+func TestFindMigratableDatabases_CaseInsensitiveNames(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEmbeddedDB(t, filepath.Join(townRoot, "gastown"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "nested", "Beads"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "other", "beads"))
+	writeEmbeddedDB(t, filepath.Join(townRoot, "HQ"))
+	writeRigDiscovery(t, townRoot, &config.RigDiscoveryConfig{ScanDepth: 2})
+
+	// The gastown database already exists under another case.
+	if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", "Gastown", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// HQ clashes with the town database; other/beads with nested/Beads.
+	if got := migrationNames(FindMigratableDatabases(townRoot)); strings.Join(got, ",") != "Beads" {
+		t.Errorf("migrations = %v, want [Beads]", got)
+	}
+}
@@ -0,0 +1,406 @@
+package doltserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// fallbackDoltTimeout bounds one dolt CLI call against a fallback snapshot.
+const fallbackDoltTimeout = 2 * time.Minute
+
+// FallbackPolicy returns the town's dolt_fallback setting, "fail" when it
+// is unset. An unknown value is reported and treated as "fail".
+func FallbackPolicy(townRoot string) (string, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return config.DoltFallbackFail, fmt.Errorf("loading town settings: %w", err)
+	}
+	switch p := settings.DoltFallback; p {
+	case "", config.DoltFallbackFail:
+		return config.DoltFallbackFail, nil
+	case config.DoltFallbackReadOnly, config.DoltFallbackQueue, config.DoltFallbackJournal:
+		return p, nil
+	default:
+		return config.DoltFallbackFail, fmt.Errorf("unknown dolt_fallback %q (want %s, %s, %s, or %s)",
+			p, config.DoltFallbackFail, config.DoltFallbackReadOnly, config.DoltFallbackQueue, config.DoltFallbackJournal)
+	}
+}
+
+// FallbackRig is a rig whose beads are on a local snapshot of its server
+// database.
+type FallbackRig struct {
+	Rig        string    `json:"rig"`
+	BeadsDir   string    `json:"beads_dir"`
+	Snapshot   string    `json:"snapshot"`
+	Mode       string    `json:"mode"`
+	ForkCommit string    `json:"fork_commit,omitempty"`
+	Since      time.Time `json:"since"`
+}
+
+// FallbackRigs lists the rigs on a fallback snapshot, including any whose
+// reconcile was interrupted.
+func FallbackRigs(townRoot string) ([]FallbackRig, error) {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	var rigs []FallbackRig
+	for _, db := range databases {
+		beadsDir := FindRigBeadsDir(townRoot, db)
+		meta, err := beads.ReadBackendMetadata(beadsDir)
+		if err != nil || meta.Fallback == nil {
+			continue
+		}
+		rigs = append(rigs, FallbackRig{
+			Rig:        db,
+			BeadsDir:   beadsDir,
+			Snapshot:   snapshotDir(beadsDir, db, meta),
+			Mode:       meta.Fallback.Mode,
+			ForkCommit: meta.Fallback.ForkCommit,
+			Since:      meta.Fallback.Since,
+		})
+	}
+	return rigs, nil
+}
+
+// snapshotDir is where bd opens db in embedded mode.
+func snapshotDir(beadsDir, db string, meta *beads.BackendMetadata) string {
+	if meta.DoltDatabase != "" {
+		db = meta.DoltDatabase
+	}
+	return filepath.Join(beadsDir, "dolt", db)
+}
+
+// Failover points every server-mode rig at a local snapshot of its database
+// when the local Dolt server is down and the town's dolt_fallback policy
+// allows it, so bd keeps working instead of each clone creating its own
+// isolated database. Rigs already on a snapshot are left alone. It returns
+// the rigs it switched; under the "fail" and "journal" policies, which
+// keep rigs on the server, it does nothing.
+func Failover(townRoot string) ([]FallbackRig, error) {
+	cfg := DefaultConfig(townRoot)
+	if cfg.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — there are no local databases to snapshot", cfg.HostPort())
+	}
+	policy, err := FallbackPolicy(townRoot)
+	if err != nil || (policy != config.DoltFallbackReadOnly && policy != config.DoltFallbackQueue) {
+		return nil, err
+	}
+	if running, _, _ := IsRunning(townRoot); running {
+		return nil, fmt.Errorf("Dolt server is running; no fallback needed")
+	}
+
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	var switched []FallbackRig
+	var errs []error
+	for _, db := range databases {
+		rig, err := failoverRig(townRoot, cfg.DataDir, db, policy)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", db, err))
+		} else if rig != nil {
+			switched = append(switched, *rig)
+		}
+	}
+	return switched, errors.Join(errs...)
+}
+
+// failoverRig copies db from the server's data directory into the rig's
+// .beads/dolt/ and switches its metadata to embedded mode. It returns nil
+// for rigs that aren't using the server.
+func failoverRig(townRoot, dataDir, db, policy string) (*FallbackRig, error) {
+	beadsDir := FindRigBeadsDir(townRoot, db)
+	meta, err := beads.ReadBackendMetadata(beadsDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !meta.IsDoltServerMode() || meta.Fallback != nil {
+		return nil, nil
+	}
+
+	snapshot := snapshotDir(beadsDir, db, meta)
+	if _, err := os.Stat(snapshot); err == nil {
+		return nil, fmt.Errorf("%s already exists; move it aside so the snapshot isn't mixed with a stray local database", snapshot)
+	}
+	if err := syncTree(filepath.Join(dataDir, db), snapshot, nil); err != nil {
+		_ = os.RemoveAll(snapshot)
+		return nil, fmt.Errorf("copying snapshot: %w", err)
+	}
+	// A crashed server leaves its lock behind; the copy isn't locked.
+	_ = os.Remove(filepath.Join(snapshot, ".dolt", "noms", "LOCK"))
+
+	fork, err := snapshotHead(snapshot)
+	if err != nil {
+		_ = os.RemoveAll(snapshot)
+		return nil, fmt.Errorf("reading snapshot HEAD: %w", err)
+	}
+	marker := &beads.FallbackMarker{Mode: policy, ForkCommit: fork, Since: time.Now().UTC()}
+	if err := setFallbackMetadata(beadsDir, marker, beads.DoltModeEmbedded); err != nil {
+		_ = os.RemoveAll(snapshot)
+		return nil, err
+	}
+	return &FallbackRig{
+		Rig:        db,
+		BeadsDir:   beadsDir,
+		Snapshot:   snapshot,
+		Mode:       marker.Mode,
+		ForkCommit: marker.ForkCommit,
+		Since:      marker.Since,
+	}, nil
+}
+
+// setFallbackMetadata sets beadsDir's dolt_mode and fallback marker,
+// removing the marker when it is nil. Other fields are preserved.
+func setFallbackMetadata(beadsDir string, marker *beads.FallbackMarker, mode string) error {
+	metadataPath := filepath.Join(beadsDir, "metadata.json")
+	mu := getMetadataMu(metadataPath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, err := os.ReadFile(metadataPath) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("reading metadata.json: %w", err)
+	}
+	existing := make(map[string]interface{})
+	if err := json.Unmarshal(current, &existing); err != nil {
+		return fmt.Errorf("parsing metadata.json: %w", err)
+	}
+	existing["dolt_mode"] = mode
+	if marker != nil {
+		existing["gt_fallback"] = marker
+	} else {
+		delete(existing, "gt_fallback")
+	}
+
+	data, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling metadata: %w", err)
+	}
+	if err := util.AtomicWriteFile(metadataPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing metadata.json: %w", err)
+	}
+	return nil
+}
+
+// ReconcileResult is the outcome for one rig from ReconcileFallback.
+type ReconcileResult struct {
+	FallbackRig
+
+	// Statements is the number of offline changes replayed (or, for a dry
+	// run, that would be).
+	Statements int    `json:"statements"`
+	Reconciled bool   `json:"reconciled"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ReconcileFallback moves every rig on a fallback snapshot back to the Dolt
+// server. For "queue" snapshots the changes made since the snapshot was
+// taken are replayed onto the server first; where a row changed on both
+// sides, the offline write wins. With dryRun it only counts the changes.
+func ReconcileFallback(townRoot string, dryRun bool) ([]ReconcileResult, error) {
+	rigs, err := FallbackRigs(townRoot)
+	if err != nil || len(rigs) == 0 {
+		return nil, err
+	}
+	if err := CheckServerReachable(townRoot); err != nil {
+		return nil, err
+	}
+
+	results := make([]ReconcileResult, 0, len(rigs))
+	for _, rig := range rigs {
+		r := ReconcileResult{FallbackRig: rig}
+		if err := reconcileRig(townRoot, &r, dryRun); err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func reconcileRig(townRoot string, r *ReconcileResult, dryRun bool) error {
+	if r.Mode != config.DoltFallbackQueue {
+		if dryRun {
+			return nil
+		}
+		return restoreServerMode(townRoot, r)
+	}
+	if r.ForkCommit == "" {
+		return fmt.Errorf("no fork commit recorded; reconcile %s by hand", r.Snapshot)
+	}
+
+	if !dryRun {
+		// Send new bd writes to the server before reading the snapshot, so
+		// none land in it after the diff. The marker stays until the
+		// changes are on the server, so an interrupted reconcile resumes.
+		meta, err := beads.ReadBackendMetadata(r.BeadsDir)
+		if err != nil {
+			return err
+		}
+		if err := setFallbackMetadata(r.BeadsDir, meta.Fallback, beads.DoltModeServer); err != nil {
+			return err
+		}
+	}
+
+	patch, err := snapshotChanges(r.Snapshot, r.ForkCommit)
+	if err != nil {
+		return fmt.Errorf("diffing snapshot: %w", err)
+	}
+	statements := queuedStatements(patch)
+	r.Statements = len(statements)
+	if dryRun {
+		return nil
+	}
+	if len(statements) > 0 {
+		message := fmt.Sprintf("gt dolt reconcile: %d change(s) made while the server was down (since %s)",
+			len(statements), r.Since.Format(time.RFC3339))
+		if err := applyQueuedChanges(townRoot, r.Rig, statements, message); err != nil {
+			return fmt.Errorf("applying offline changes: %w", err)
+		}
+	}
+	return restoreServerMode(townRoot, r)
+}
+
+// restoreServerMode clears the rig's fallback marker, puts its metadata
+// back in server mode, and removes the snapshot.
+func restoreServerMode(townRoot string, r *ReconcileResult) error {
+	if err := setFallbackMetadata(r.BeadsDir, nil, beads.DoltModeServer); err != nil {
+		return err
+	}
+	if _, err := ensureMetadataIn(townRoot, r.Rig, r.BeadsDir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(r.Snapshot); err != nil {
+		return fmt.Errorf("removing snapshot: %w", err)
+	}
+	r.Reconciled = true
+	return nil
+}
+
+// queuedStatements splits the SQL from dolt diff -r sql into statements,
+// turning inserts into replaces so rows also written on the server since
+// the snapshot take the offline version.
+func queuedStatements(patch string) []string {
+	var statements []string
+	for _, stmt := range splitSQL(patch) {
+		if len(stmt) >= len("INSERT INTO") && strings.EqualFold(stmt[:len("INSERT INTO")], "INSERT INTO") {
+			stmt = "REPLACE INTO" + stmt[len("INSERT INTO"):]
+		}
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// splitSQL splits script at semicolons outside quotes and backquotes,
+// dropping empty statements and the semicolons themselves.
+func splitSQL(script string) []string {
+	var statements []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			if stmt := strings.TrimSpace(script[start:i]); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			start = i + 1
+		}
+	}
+	if stmt := strings.TrimSpace(script[start:]); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// snapshotHead returns the HEAD commit of the database at dir.
+// A variable so tests can run without dolt installed.
+var snapshotHead = func(dir string) (string, error) {
+	out, err := runDoltIn(dir, "sql", "-r", "csv", "-q", "SELECT HASHOF('HEAD')")
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return "", fmt.Errorf("unexpected output %q", out)
+	}
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// snapshotChanges returns SQL that replays the changes in the database at
+// dir, committed or not, since fork. A variable so tests can run without
+// dolt installed.
+var snapshotChanges = func(dir, fork string) (string, error) {
+	return runDoltIn(dir, "diff", fork, "-r", "sql")
+}
+
+// applyQueuedChanges runs statements against db on the server in one
+// transaction and commits the result. A variable so tests can run without
+// a server.
+var applyQueuedChanges = func(townRoot, db string, statements []string, message string) error {
+	pool, err := DB(townRoot, db)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fallbackDoltTimeout)
+	defer cancel()
+
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s: %w", stmt, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if _, err := pool.ExecContext(ctx, "CALL DOLT_COMMIT('-Am', ?)", message); err != nil &&
+		!strings.Contains(err.Error(), "nothing to commit") {
+		return fmt.Errorf("committing: %w", err)
+	}
+	return nil
+}
+
+// runDoltIn runs a dolt CLI command in dir and returns its stdout.
+func runDoltIn(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fallbackDoltTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "dolt", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("dolt %s: %w (%s)", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,186 @@
+package doltserver
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// writeFallbackSetting sets dolt_fallback in townRoot's settings/config.json.
+func writeFallbackSetting(t *testing.T, townRoot, policy string) {
+	t.Helper()
+	settings := config.NewTownSettings()
+	settings.DoltFallback = policy
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFallbackPolicy(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+		wantErr bool
+	}{
+		{"", config.DoltFallbackFail, false},
+		{"fail", config.DoltFallbackFail, false},
+		{"read-only", config.DoltFallbackReadOnly, false},
+		{"queue", config.DoltFallbackQueue, false},
+		{"journal", config.DoltFallbackJournal, false},
+		{"local", config.DoltFallbackFail, true},
+	}
+	for _, tt := range tests {
+		townRoot := t.TempDir()
+		writeFallbackSetting(t, townRoot, tt.setting)
+		got, err := FallbackPolicy(townRoot)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("FallbackPolicy(%q) = %q, %v; want %q, error %v", tt.setting, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFailoverAndReconcile(t *testing.T) {
+	origHead, origChanges, origApply := snapshotHead, snapshotChanges, applyQueuedChanges
+	t.Cleanup(func() { snapshotHead, snapshotChanges, applyQueuedChanges = origHead, origChanges, origApply })
+
+	townRoot := t.TempDir()
+	port := unusedPort(t)
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(port))
+	writeFallbackSetting(t, townRoot, config.DoltFallbackQueue)
+
+	noms := filepath.Join(townRoot, ".dolt-data", "gastown", ".dolt", "noms")
+	if err := os.MkdirAll(noms, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"LOCK", "manifest"} {
+		if err := os.WriteFile(filepath.Join(noms, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	beadsDir := filepath.Join(townRoot, "gastown", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureMetadata(townRoot, "gastown"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotHead = func(string) (string, error) { return "fork123", nil }
+	switched, err := Failover(townRoot)
+	if err != nil {
+		t.Fatalf("Failover: %v", err)
+	}
+	if len(switched) != 1 || switched[0].Rig != "gastown" || switched[0].Mode != config.DoltFallbackQueue {
+		t.Fatalf("switched = %+v, want gastown in queue mode", switched)
+	}
+	snapshot := filepath.Join(beadsDir, "dolt", "gastown")
+	if _, err := os.Stat(filepath.Join(snapshot, ".dolt", "noms", "manifest")); err != nil {
+		t.Errorf("snapshot not copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(snapshot, ".dolt", "noms", "LOCK")); !os.IsNotExist(err) {
+		t.Errorf("snapshot kept the server's LOCK file")
+	}
+
+	// fix-metadata must not pull the rig back onto the down server.
+	if err := EnsureMetadata(townRoot, "gastown"); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := beads.ReadBackendMetadata(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.DoltMode != beads.DoltModeEmbedded || meta.Fallback == nil || meta.Fallback.ForkCommit != "fork123" {
+		t.Fatalf("metadata after failover = %+v, want embedded with a marker", meta)
+	}
+	if again, err := Failover(townRoot); err != nil || len(again) != 0 {
+		t.Errorf("second Failover = %+v, %v; want nothing switched", again, err)
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	snapshotChanges = func(dir, fork string) (string, error) {
+		if dir != snapshot || fork != "fork123" {
+			t.Errorf("snapshotChanges(%q, %q)", dir, fork)
+		}
+		return "INSERT INTO `issues` (`id`,`title`) VALUES ('gt-1','a; b');\nUPDATE `issues` SET `status`='closed' WHERE `id`='gt-2';\n", nil
+	}
+	var applied []string
+	applyQueuedChanges = func(_, db string, statements []string, _ string) error {
+		if db != "gastown" {
+			t.Errorf("applied to %q, want gastown", db)
+		}
+		applied = statements
+		return nil
+	}
+
+	dry, err := ReconcileFallback(townRoot, true)
+	if err != nil || len(dry) != 1 || dry[0].Statements != 2 || dry[0].Reconciled {
+		t.Fatalf("dry run = %+v, %v; want 2 statements, not reconciled", dry, err)
+	}
+	if applied != nil {
+		t.Fatal("dry run applied changes")
+	}
+
+	results, err := ReconcileFallback(townRoot, false)
+	if err != nil || len(results) != 1 || !results[0].Reconciled {
+		t.Fatalf("ReconcileFallback = %+v, %v", results, err)
+	}
+	want := []string{
+		"REPLACE INTO `issues` (`id`,`title`) VALUES ('gt-1','a; b')",
+		"UPDATE `issues` SET `status`='closed' WHERE `id`='gt-2'",
+	}
+	if !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied = %q, want %q", applied, want)
+	}
+	meta, err = beads.ReadBackendMetadata(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.DoltMode != beads.DoltModeServer || meta.Fallback != nil {
+		t.Errorf("metadata after reconcile = %+v, want server mode without a marker", meta)
+	}
+	if _, err := os.Stat(snapshot); !os.IsNotExist(err) {
+		t.Errorf("snapshot not removed")
+	}
+}
+
+func TestFailover_FailPolicy(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(unusedPort(t)))
+	if err := os.MkdirAll(filepath.Join(townRoot, ".dolt-data", "gastown", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureMetadata(townRoot, "gastown"); err != nil {
+		t.Fatal(err)
+	}
+
+	switched, err := Failover(townRoot)
+	if err != nil || len(switched) != 0 {
+		t.Errorf("Failover = %+v, %v; want nothing under the fail policy", switched, err)
+	}
+}
+
+func TestSplitSQL(t *testing.T) {
+	script := "DELETE FROM `t` WHERE `id`='x;y';\n\nINSERT INTO `t` VALUES ('it\\'s; fine', \"q;\");\nUPDATE `a;b` SET c=1"
+	want := []string{
+		"DELETE FROM `t` WHERE `id`='x;y'",
+		"INSERT INTO `t` VALUES ('it\\'s; fine', \"q;\")",
+		"UPDATE `a;b` SET c=1",
+	}
+	if got := splitSQL(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitSQL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,235 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// maxPreviewRows caps how many conflicting rows are listed per table.
+const maxPreviewRows = 20
+
+// TableConflicts is the number of conflicts a merge would hit in one table.
+type TableConflicts struct {
+	Table  string `json:"table"`
+	Data   int    `json:"data_conflicts"`
+	Schema int    `json:"schema_conflicts"`
+}
+
+// ConflictRow is one row both sides changed. Key is the row's primary key
+// (columns joined with ","); OurChange and TheirChange are the diff types
+// on main and on the branch ("added", "modified", "removed").
+type ConflictRow struct {
+	Table       string `json:"table"`
+	Key         string `json:"key"`
+	OurChange   string `json:"our_change"`
+	TheirChange string `json:"their_change"`
+}
+
+// MergePreview is what merging a branch into main would conflict on.
+type MergePreview struct {
+	Branch string           `json:"branch"`
+	Tables []TableConflicts `json:"tables"`
+
+	// Rows lists up to maxPreviewRows conflicting rows per table.
+	Rows []ConflictRow `json:"rows"`
+}
+
+// HasConflicts reports whether the merge would conflict at all.
+func (p *MergePreview) HasConflicts() bool {
+	return len(p.Tables) > 0
+}
+
+// HasSchemaConflicts reports whether any table's schema would conflict.
+// DOLT_CONFLICTS_RESOLVE only resolves data conflicts, so these always
+// need a human.
+func (p *MergePreview) HasSchemaConflicts() bool {
+	for _, t := range p.Tables {
+		if t.Schema > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary describes the conflicts in one line, e.g. "issues (3 rows), labels (schema)".
+func (p *MergePreview) Summary() string {
+	var parts []string
+	for _, t := range p.Tables {
+		var what []string
+		if t.Data > 0 {
+			what = append(what, fmt.Sprintf("%d rows", t.Data))
+		}
+		if t.Schema > 0 {
+			what = append(what, "schema")
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", t.Table, strings.Join(what, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MergeConflictError is returned by MergePolecatBranchWithPolicy when a
+// merge was not attempted because it would conflict. Main is untouched and
+// the branch keeps all its data.
+type MergeConflictError struct {
+	RigDB   string
+	Preview *MergePreview
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merging %s to main in %s would conflict: %s", e.Preview.Branch, e.RigDB, e.Preview.Summary())
+}
+
+// PreviewMerge reports the conflicts merging branch into main would hit,
+// without changing either. Only committed state is compared: uncommitted
+// working-set changes on either side are not included.
+func PreviewMerge(ctx context.Context, townRoot, rigDB, branch string) (*MergePreview, error) {
+	if err := validateBranchName(branch); err != nil {
+		return nil, err
+	}
+	db, err := DB(townRoot, rigDB)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT `table`, num_data_conflicts, num_schema_conflicts FROM dolt_preview_merge_conflicts_summary(?, ?)", MainBranch, branch)
+	if err != nil {
+		return nil, fmt.Errorf("previewing merge of %s in %s: %w", branch, rigDB, err)
+	}
+	preview := &MergePreview{Branch: branch, Tables: []TableConflicts{}, Rows: []ConflictRow{}}
+	for rows.Next() {
+		var t TableConflicts
+		var data, schema sql.NullInt64
+		if err := rows.Scan(&t.Table, &data, &schema); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning merge preview: %w", err)
+		}
+		t.Data, t.Schema = int(data.Int64), int(schema.Int64)
+		if t.Data > 0 || t.Schema > 0 {
+			preview.Tables = append(preview.Tables, t)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("previewing merge of %s in %s: %w", branch, rigDB, err)
+	}
+
+	for _, t := range preview.Tables {
+		if t.Data == 0 {
+			continue
+		}
+		conflicts, err := previewConflictRows(ctx, db, rigDB, branch, t.Table)
+		if err != nil {
+			return nil, err
+		}
+		preview.Rows = append(preview.Rows, conflicts...)
+	}
+	return preview, nil
+}
+
+// previewConflictRows lists the conflicting rows of one table.
+func previewConflictRows(ctx context.Context, db *sql.DB, rigDB, branch, table string) ([]ConflictRow, error) {
+	keys, err := primaryKeyColumns(ctx, db, rigDB, table)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM dolt_preview_merge_conflicts(?, ?, ?) LIMIT %d", maxPreviewRows), MainBranch, branch, table)
+	if err != nil {
+		return nil, fmt.Errorf("previewing conflicts in %s: %w", table, err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("previewing conflicts in %s: %w", table, err)
+	}
+
+	var conflicts []ConflictRow
+	for rows.Next() {
+		values := make([]sql.NullString, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("scanning conflicts in %s: %w", table, err)
+		}
+		row := make(map[string]string, len(cols))
+		for i, c := range cols {
+			if values[i].Valid {
+				row[c] = values[i].String
+			}
+		}
+		conflicts = append(conflicts, ConflictRow{
+			Table:       table,
+			Key:         conflictRowKey(row, keys),
+			OurChange:   row["our_diff_type"],
+			TheirChange: row["their_diff_type"],
+		})
+	}
+	return conflicts, rows.Err()
+}
+
+// conflictRowKey picks a conflict row's primary key from whichever side
+// still has the row, falling back to Dolt's conflict ID.
+func conflictRowKey(row map[string]string, keys []string) string {
+	for _, side := range []string{"our_", "their_", "base_"} {
+		var parts []string
+		for _, k := range keys {
+			if v, ok := row[side+k]; ok {
+				parts = append(parts, v)
+			}
+		}
+		if len(keys) > 0 && len(parts) == len(keys) {
+			return strings.Join(parts, ",")
+		}
+	}
+	return row["dolt_conflict_id"]
+}
+
+// primaryKeyColumns returns a table's primary key columns in order.
+func primaryKeyColumns(ctx context.Context, db *sql.DB, rigDB, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT column_name FROM information_schema.key_column_usage
+		WHERE table_schema = ? AND table_name = ? AND constraint_name = 'PRIMARY'
+		ORDER BY ordinal_position`, rigDB, table)
+	if err != nil {
+		return nil, fmt.Errorf("reading primary key of %s: %w", table, err)
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, fmt.Errorf("reading primary key of %s: %w", table, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// checkMergeConflicts previews a polecat merge and decides whether to go
+// ahead. It returns a *MergeConflictError if the merge would conflict and
+// policy routes conflicts to a human, or if the conflicts can't be
+// auto-resolved (schema conflicts). A failed preview is reported and the
+// merge proceeds; the merge itself still handles conflicts.
+func checkMergeConflicts(townRoot, rigDB, branchName, policy string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	preview, err := PreviewMerge(ctx, townRoot, rigDB, branchName)
+	if err != nil {
+		fmt.Printf("Warning: could not preview merge of %s: %v\n", branchName, err)
+		return nil
+	}
+	if !preview.HasConflicts() {
+		return nil
+	}
+	if policy == config.OnDoltConflictEscalate || preview.HasSchemaConflicts() {
+		return &MergeConflictError{RigDB: rigDB, Preview: preview}
+	}
+	fmt.Printf("Dolt merge of %s will conflict in %s\n", branchName, preview.Summary())
+	return nil
+}
@@ -0,0 +1,72 @@
+package doltserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMergePreview_Summary(t *testing.T) {
+	p := &MergePreview{Branch: "polecat-toast-1", Tables: []TableConflicts{
+		{Table: "issues", Data: 3},
+		{Table: "labels", Data: 1, Schema: 1},
+		{Table: "metadata", Schema: 2},
+	}}
+	want := "issues (3 rows), labels (1 rows, schema), metadata (schema)"
+	if got := p.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if !p.HasConflicts() || !p.HasSchemaConflicts() {
+		t.Errorf("HasConflicts/HasSchemaConflicts = %v/%v, want true/true", p.HasConflicts(), p.HasSchemaConflicts())
+	}
+}
+
+func TestMergePreview_DataOnly(t *testing.T) {
+	p := &MergePreview{Tables: []TableConflicts{{Table: "issues", Data: 1}}}
+	if p.HasSchemaConflicts() {
+		t.Error("data-only conflicts reported as schema conflicts")
+	}
+	if (&MergePreview{}).HasConflicts() {
+		t.Error("empty preview reported conflicts")
+	}
+}
+
+func TestMergeConflictError(t *testing.T) {
+	err := &MergeConflictError{RigDB: "gastown", Preview: &MergePreview{
+		Branch: "polecat-toast-1",
+		Tables: []TableConflicts{{Table: "issues", Data: 2}},
+	}}
+	want := "merging polecat-toast-1 to main in gastown would conflict: issues (2 rows)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestConflictRowKey(t *testing.T) {
+	tests := []struct {
+		name string
+		row  map[string]string
+		keys []string
+		want string
+	}{
+		{"ours", map[string]string{"our_id": "gt-1", "their_id": "gt-1"}, []string{"id"}, "gt-1"},
+		{"removed on main", map[string]string{"their_id": "gt-2", "base_id": "gt-2"}, []string{"id"}, "gt-2"},
+		{"composite", map[string]string{"our_issue_id": "gt-1", "our_label": "bug"}, []string{"issue_id", "label"}, "gt-1,bug"},
+		{"no primary key", map[string]string{"dolt_conflict_id": "abc"}, nil, "abc"},
+		{"partial key", map[string]string{"our_issue_id": "gt-1", "dolt_conflict_id": "abc"}, []string{"issue_id", "label"}, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conflictRowKey(tt.row, tt.keys); got != tt.want {
+				t.Errorf("conflictRowKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewMerge_InvalidBranch(t *testing.T) {
+	_, err := PreviewMerge(context.Background(), t.TempDir(), "gastown", "x'; DROP TABLE issues; --")
+	if err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("PreviewMerge(invalid branch) err = %v, want invalid branch name", err)
+	}
+}
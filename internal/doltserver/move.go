@@ -0,0 +1,330 @@
+package doltserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// moveStagingDir holds in-progress cross-filesystem moves, next to their
+// destination so the final rename stays on one filesystem. It is a level
+// down so a half-copied database is never a direct child of the data
+// directory, where the Dolt server would load it.
+const moveStagingDir = ".move-tmp"
+
+// movePhase is how far a journaled move has got.
+type movePhase string
+
+const (
+	// movePhaseCopying: the staging copy may be partial; the source is intact.
+	movePhaseCopying movePhase = "copying"
+	// movePhaseVerified: every staged file's checksum matches the source;
+	// the copy only needs renaming into place.
+	movePhaseVerified movePhase = "verified"
+	// movePhaseSwapped: the destination is complete; the source is left to
+	// remove.
+	movePhaseSwapped movePhase = "swapped"
+)
+
+// moveJournal records a cross-filesystem move so one interrupted by a crash
+// resumes where it stopped instead of leaving a partial destination.
+type moveJournal struct {
+	Source    string    `json:"source"`
+	Dest      string    `json:"dest"`
+	Phase     movePhase `json:"phase"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// moveDir moves a directory from src to dest. It first tries os.Rename,
+// falling back to copyVerifySwap when src and dest are on different
+// filesystems (EXDEV). A move interrupted earlier is resumed. A non-nil bar
+// shows the copy's progress.
+func moveDir(src, dest string, bar *progress.Bar) error {
+	if moveInProgress(dest) {
+		return copyVerifySwap(src, dest, bar)
+	}
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	} else if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	return copyVerifySwap(src, dest, bar)
+}
+
+// copyVerifySwap moves src to dest without ever leaving a partial dest:
+//
+//  1. copy src into a staging directory beside dest,
+//  2. compare every file's SHA-256 with the source, re-copying mismatches,
+//  3. rename the staging directory to dest,
+//  4. remove src.
+//
+// Each step is recorded in a journal, so calling it again after a crash
+// finishes the move. src is only removed once dest is verified and in place.
+func copyVerifySwap(src, dest string, bar *progress.Bar) error {
+	staging := moveStagingPath(dest)
+	journalPath := moveJournalPath(dest)
+
+	journal, err := loadMoveJournal(journalPath)
+	if err != nil {
+		return err
+	}
+	if journal == nil {
+		journal = &moveJournal{Source: src, Dest: dest, Phase: movePhaseCopying, StartedAt: time.Now().UTC()}
+		if err := saveMoveJournal(journalPath, journal); err != nil {
+			return err
+		}
+	} else if journal.Source != src {
+		return fmt.Errorf("an interrupted move from %s into %s is pending (journal %s)", journal.Source, dest, journalPath)
+	}
+
+	if journal.Phase == movePhaseCopying {
+		if bar != nil {
+			bar.SetTotal(progress.DirSize(src))
+		}
+		if err := syncTree(src, staging, bar); err != nil {
+			return fmt.Errorf("copying %s: %w", src, err)
+		}
+		if err := verifyTree(src, staging); err != nil {
+			return fmt.Errorf("verifying copy of %s: %w", src, err)
+		}
+		journal.Phase = movePhaseVerified
+		if err := saveMoveJournal(journalPath, journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == movePhaseVerified {
+		// A crash after the rename but before the journal update leaves
+		// dest in place and no staging copy.
+		if _, err := os.Stat(staging); err == nil {
+			if err := os.Rename(staging, dest); err != nil {
+				return fmt.Errorf("renaming verified copy into place: %w", err)
+			}
+		} else if _, err := os.Stat(dest); err != nil {
+			return fmt.Errorf("verified copy %s is missing and %s doesn't exist", staging, dest)
+		}
+		journal.Phase = movePhaseSwapped
+		if err := saveMoveJournal(journalPath, journal); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("removing source after copy: %w", err)
+	}
+	if err := os.Remove(journalPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing move journal: %w", err)
+	}
+	_ = os.Remove(filepath.Dir(staging)) // only succeeds once no other move is pending
+	return nil
+}
+
+// moveInProgress reports whether an interrupted move into dest is pending.
+func moveInProgress(dest string) bool {
+	_, err := os.Stat(moveJournalPath(dest))
+	return err == nil
+}
+
+func moveStagingPath(dest string) string {
+	return filepath.Join(filepath.Dir(dest), moveStagingDir, filepath.Base(dest))
+}
+
+func moveJournalPath(dest string) string {
+	return moveStagingPath(dest) + ".journal.json"
+}
+
+func loadMoveJournal(path string) (*moveJournal, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading move journal: %w", err)
+	}
+	var j moveJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("parsing move journal %s: %w", path, err)
+	}
+	return &j, nil
+}
+
+func saveMoveJournal(path string, j *moveJournal) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating move staging directory: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, j); err != nil {
+		return fmt.Errorf("writing move journal: %w", err)
+	}
+	return nil
+}
+
+// syncTree copies src into dst. Files already in dst with the source's size
+// and modification time are taken as copied by an interrupted earlier run
+// and skipped; verifyTree catches any that aren't.
+func syncTree(src, dst string, bar *progress.Bar) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			return nil // sockets, pipes: nothing to preserve
+		}
+
+		if have, err := os.Lstat(target); err == nil && have.Mode().IsRegular() &&
+			have.Size() == info.Size() && have.ModTime().Equal(info.ModTime()) {
+			bar.Add(info.Size())
+			return nil
+		}
+		if err := copyFileSynced(target, path, info); err != nil {
+			return err
+		}
+		bar.Add(info.Size())
+		return nil
+	})
+}
+
+// copyFileSynced copies src to dst with src's mode and modification time,
+// syncing it to disk before returning.
+func copyFileSynced(dst, src string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	_ = os.Remove(dst) // a stale copy may be read-only
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// verifyTree checks that every regular file under src has an identical copy
+// under dst, re-copying a mismatch once before giving up. Files in dst that
+// aren't in src (left from an earlier copy of a different source state) are
+// removed.
+func verifyTree(src, dst string) error {
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if same, err := sameContents(path, target); err != nil || same {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := copyFileSynced(target, path, info); err != nil {
+			return err
+		}
+		if same, err := sameContents(path, target); err != nil || same {
+			return err
+		}
+		return fmt.Errorf("%s: copy doesn't match the source after retrying", rel)
+	})
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(filepath.Join(src, rel)); os.IsNotExist(err) {
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+}
+
+// sameContents reports whether files a and b have the same SHA-256. A
+// missing b is a mismatch, not an error.
+func sameContents(a, b string) (bool, error) {
+	sumA, err := fileSHA256(a)
+	if err != nil {
+		return false, err
+	}
+	sumB, err := fileSHA256(b)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(sumA, sumB), nil
+}
+
+func fileSHA256(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,182 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// writeTree creates files (relative path -> content) under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func assertTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, want := range files {
+		got, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			t.Errorf("%s: %v", rel, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestCopyVerifySwap(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	dest := filepath.Join(tmp, "data", "gastown")
+	files := map[string]string{
+		".dolt/noms/manifest": "manifest",
+		".dolt/noms/chunk1":   "chunk data",
+		"config.yaml":         "x: 1",
+	}
+	writeTree(t, src, files)
+	mtime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(src, ".dolt", "noms", "chunk1"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(src, "config.yaml"), 0400); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("config.yaml", filepath.Join(src, "link")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := copyVerifySwap(src, dest, nil); err != nil {
+		t.Fatalf("copyVerifySwap: %v", err)
+	}
+
+	assertTree(t, dest, files)
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source still exists after move")
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "data", moveStagingDir)); !os.IsNotExist(err) {
+		t.Error("staging directory left behind")
+	}
+	if moveInProgress(dest) {
+		t.Error("journal left behind")
+	}
+	info, err := os.Stat(filepath.Join(dest, ".dolt", "noms", "chunk1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("mtime = %v, want %v", info.ModTime(), mtime)
+	}
+	if runtime.GOOS != "windows" {
+		if link, err := os.Readlink(filepath.Join(dest, "link")); err != nil || link != "config.yaml" {
+			t.Errorf("symlink = %q, %v", link, err)
+		}
+	}
+}
+
+func TestCopyVerifySwap_ResumesPartialCopy(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	dest := filepath.Join(tmp, "data", "gastown")
+	files := map[string]string{
+		".dolt/noms/manifest": "manifest",
+		".dolt/noms/chunk1":   "good chunk",
+	}
+	writeTree(t, src, files)
+
+	// An earlier run copied a corrupt chunk (same size and mtime, so the
+	// resumed copy skips it) and a file the source no longer has.
+	staging := moveStagingPath(dest)
+	writeTree(t, staging, map[string]string{
+		".dolt/noms/chunk1": "bad! chunk",
+		".dolt/noms/stale":  "old",
+	})
+	info, _ := os.Stat(filepath.Join(src, ".dolt", "noms", "chunk1"))
+	if err := os.Chtimes(filepath.Join(staging, ".dolt", "noms", "chunk1"), info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveMoveJournal(moveJournalPath(dest), &moveJournal{Source: src, Dest: dest, Phase: movePhaseCopying}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveDir(src, dest, nil); err != nil {
+		t.Fatalf("moveDir: %v", err)
+	}
+	assertTree(t, dest, files)
+	if _, err := os.Stat(filepath.Join(dest, ".dolt", "noms", "stale")); !os.IsNotExist(err) {
+		t.Error("stale file from the earlier copy survived")
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source still exists after move")
+	}
+}
+
+func TestCopyVerifySwap_ResumesAfterRename(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	dest := filepath.Join(tmp, "data", "gastown")
+	writeTree(t, src, map[string]string{".dolt/manifest": "m"})
+	writeTree(t, dest, map[string]string{".dolt/manifest": "m"})
+	// Crashed between renaming the verified copy and updating the journal.
+	if err := saveMoveJournal(moveJournalPath(dest), &moveJournal{Source: src, Dest: dest, Phase: movePhaseVerified}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveDir(src, dest, nil); err != nil {
+		t.Fatalf("moveDir: %v", err)
+	}
+	assertTree(t, dest, map[string]string{".dolt/manifest": "m"})
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source still exists after move")
+	}
+	if moveInProgress(dest) {
+		t.Error("journal left behind")
+	}
+}
+
+func TestCopyVerifySwap_OtherMovePending(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	dest := filepath.Join(tmp, "data", "gastown")
+	writeTree(t, src, map[string]string{".dolt/manifest": "m"})
+	if err := saveMoveJournal(moveJournalPath(dest), &moveJournal{Source: filepath.Join(tmp, "other"), Dest: dest, Phase: movePhaseCopying}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyVerifySwap(src, dest, nil); err == nil {
+		t.Fatal("expected an error for a pending move from another source")
+	}
+	assertTree(t, src, map[string]string{".dolt/manifest": "m"})
+}
+
+func TestFindMigratableDatabases_PendingMove(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEmbeddedDB(t, filepath.Join(townRoot, "gastown"))
+	target := filepath.Join(townRoot, ".dolt-data", "gastown")
+	if err := os.MkdirAll(filepath.Join(target, ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindMigratableDatabases(townRoot); len(got) != 0 {
+		t.Fatalf("migrations = %v, want none (target exists)", migrationNames(got))
+	}
+
+	if err := saveMoveJournal(moveJournalPath(target), &moveJournal{Phase: movePhaseSwapped}); err != nil {
+		t.Fatal(err)
+	}
+	if got := migrationNames(FindMigratableDatabases(townRoot)); len(got) != 1 || got[0] != "gastown" {
+		t.Errorf("migrations = %v, want the interrupted gastown move", got)
+	}
+}
@@ -0,0 +1,142 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// poolMaxOpenConns caps connections per pooled handle. Gas Town runs the
+// server with a low max_connections (see DefaultMaxConnections), so CLI
+// callers must not fan out.
+const poolMaxOpenConns = 4
+
+var (
+	poolMu sync.Mutex
+	pools  = map[string]*sql.DB{}
+)
+
+// DB returns a shared connection pool to the Dolt server for database.
+// An empty database connects without selecting one. Handles are cached per
+// server and database for the life of the process; callers must not Close them.
+// Multi-statement queries are enabled so scripts can be executed in one call.
+func DB(townRoot, database string) (*sql.DB, error) {
+	config := DefaultConfig(townRoot)
+
+	cfg := mysql.NewConfig()
+	cfg.User = config.User
+	cfg.Passwd = config.Password
+	cfg.Net = "tcp"
+	cfg.Addr = config.HostPort()
+	cfg.DBName = database
+	cfg.MultiStatements = true
+	cfg.Timeout = 5 * time.Second
+	dsn := cfg.FormatDSN()
+
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	if db, ok := pools[dsn]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening dolt connection: %w", err)
+	}
+	db.SetMaxOpenConns(poolMaxOpenConns)
+	db.SetMaxIdleConns(poolMaxOpenConns)
+	db.SetConnMaxIdleTime(time.Minute)
+	pools[dsn] = db
+	return db, nil
+}
+
+// ResultSet is one result set returned by a query. Values are rendered as
+// strings; SQL NULL is reported as nil.
+type ResultSet struct {
+	Columns []string    `json:"columns"`
+	Rows    [][]*string `json:"rows"`
+}
+
+// QueryAll runs query (which may contain several ';'-separated statements)
+// on a single pooled connection and returns every result set that produced
+// columns. Statements without results (INSERT, USE, ...) are executed but
+// contribute no result set.
+func QueryAll(ctx context.Context, db *sql.DB, query string) (_ []ResultSet, err error) {
+	ctx, span := startSQLSpan(ctx, "", query)
+	defer func() { telemetry.End(span, err) }()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to dolt server: %w", err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []ResultSet
+	for {
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if len(cols) > 0 {
+			rs := ResultSet{Columns: cols}
+			for rows.Next() {
+				raw := make([]sql.RawBytes, len(cols))
+				dest := make([]any, len(cols))
+				for i := range raw {
+					dest[i] = &raw[i]
+				}
+				if err := rows.Scan(dest...); err != nil {
+					return nil, err
+				}
+				row := make([]*string, len(cols))
+				for i, b := range raw {
+					if b != nil {
+						s := string(b)
+						row[i] = &s
+					}
+				}
+				rs.Rows = append(rs.Rows, row)
+			}
+			results = append(results, rs)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// startSQLSpan starts the span for one SQL call. database is the database
+// the statement targets, if known. Only the statement's leading keyword is
+// recorded, since statements can carry bead contents.
+func startSQLSpan(ctx context.Context, database, query string) (context.Context, trace.Span) {
+	op, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system.name", "dolt"),
+		attribute.String("db.operation.name", strings.ToUpper(op)),
+	}
+	if database != "" {
+		attrs = append(attrs, attribute.String("db.namespace", database))
+	}
+	return telemetry.Start(ctx, "dolt.sql", attrs...)
+}
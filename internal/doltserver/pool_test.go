@@ -0,0 +1,27 @@
+package doltserver
+
+import "testing"
+
+func TestDB_SharedPerDatabase(t *testing.T) {
+	townRoot := t.TempDir()
+
+	a, err := DB(townRoot, "gastown")
+	if err != nil {
+		t.Fatalf("DB() error: %v", err)
+	}
+	b, err := DB(townRoot, "gastown")
+	if err != nil {
+		t.Fatalf("DB() error: %v", err)
+	}
+	if a != b {
+		t.Error("DB() should return the same pool for the same database")
+	}
+
+	c, err := DB(townRoot, "beads")
+	if err != nil {
+		t.Fatalf("DB() error: %v", err)
+	}
+	if c == a {
+		t.Error("DB() should return distinct pools for different databases")
+	}
+}
@@ -0,0 +1,106 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ErrPortInUse indicates the Dolt port is held by a process that isn't dolt.
+var ErrPortInUse = errors.New("port in use")
+
+// maxAutoPortAttempts bounds how far past the configured port auto-port mode searches.
+const maxAutoPortAttempts = 100
+
+// StartOptions controls optional Start behavior.
+type StartOptions struct {
+	// AutoPort picks the next free port when the configured one is held by
+	// another process, and records it in the state file and metadata.json so
+	// connection strings follow. Once chosen, the port sticks for this town.
+	AutoPort bool
+}
+
+// PortConflict describes a non-dolt process listening on the Dolt port.
+type PortConflict struct {
+	Port    int
+	PID     int    // 0 if the owner couldn't be identified
+	Command string // owner's command line, if known
+}
+
+// Error describes the conflict and how to resolve it.
+func (c *PortConflict) Error() string {
+	owner := "another process"
+	if c.PID > 0 {
+		owner = fmt.Sprintf("PID %d", c.PID)
+		if c.Command != "" {
+			owner = fmt.Sprintf("PID %d (%s)", c.PID, c.Command)
+		}
+	}
+	return fmt.Sprintf("port %d is held by %s, not a dolt sql-server\n"+
+		"  Free the port, set GT_DOLT_PORT to another port, or run: gt dolt start --auto-port", c.Port, owner)
+}
+
+// Unwrap lets callers match conflicts with errors.Is(err, ErrPortInUse).
+func (c *PortConflict) Unwrap() error {
+	return ErrPortInUse
+}
+
+// CheckPortConflict reports whether the configured local port is held by
+// something other than a dolt sql-server. Returns nil when the port is free or
+// serving dolt, and a *PortConflict otherwise.
+func CheckPortConflict(townRoot string) error {
+	config := DefaultConfig(townRoot)
+	if config.IsRemote() {
+		return nil
+	}
+	return checkPortConflict(config.Port)
+}
+
+func checkPortConflict(port int) error {
+	if !portReachable(net.JoinHostPort("127.0.0.1", strconv.Itoa(port))) {
+		return nil
+	}
+	pid := portOwner(port)
+	if pid > 0 && isDoltProcess(pid) {
+		return nil
+	}
+	conflict := &PortConflict{Port: port, PID: pid}
+	if pid > 0 {
+		if out, err := util.Output(exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command="), util.ProbeTimeout); err == nil {
+			conflict.Command = strings.TrimSpace(string(out))
+		}
+	}
+	return conflict
+}
+
+// portOwner returns the PID listening on port, or 0 if unknown.
+func portOwner(port int) int {
+	out, err := util.Output(exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-t"), util.ProbeTimeout)
+	if err != nil {
+		return 0
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	pid, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+// findFreePort returns the first port after start that can be bound locally.
+func findFreePort(start int) (int, error) {
+	for port := start + 1; port <= start+maxAutoPortAttempts && port <= 65535; port++ {
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err != nil {
+			continue
+		}
+		_ = l.Close()
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port in %d-%d", start+1, start+maxAutoPortAttempts)
+}
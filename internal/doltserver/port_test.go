@@ -0,0 +1,113 @@
+package doltserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckPortConflict(t *testing.T) {
+	if err := checkPortConflict(freePort(t)); err != nil {
+		t.Errorf("free port: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	err = checkPortConflict(port)
+	if !errors.Is(err, ErrPortInUse) {
+		t.Fatalf("err = %v, want ErrPortInUse", err)
+	}
+	var conflict *PortConflict
+	if !errors.As(err, &conflict) || conflict.Port != port {
+		t.Fatalf("err = %#v, want *PortConflict for port %d", err, port)
+	}
+	if conflict.PID != 0 && conflict.PID != os.Getpid() {
+		t.Errorf("owner PID = %d, want %d (or 0 without lsof)", conflict.PID, os.Getpid())
+	}
+	if !strings.Contains(err.Error(), "--auto-port") {
+		t.Errorf("error should suggest --auto-port: %v", err)
+	}
+}
+
+func TestFindFreePort_SkipsTakenPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	taken := l.Addr().(*net.TCPAddr).Port
+
+	port, err := findFreePort(taken - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port == taken || port < taken {
+		t.Errorf("findFreePort(%d) = %d, want a free port after %d", taken-1, port, taken)
+	}
+}
+
+func TestAutoPortState_DrivesConfigAndMetadata(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_DOLT_PORT", "")
+
+	beadsDir := filepath.Join(townRoot, "myrig", "mayor", "rig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A recorded port without AutoPort is history, not configuration.
+	if err := SaveState(townRoot, &State{Port: 3399}); err != nil {
+		t.Fatal(err)
+	}
+	if got := DefaultConfig(townRoot).Port; got != DefaultPort {
+		t.Errorf("port = %d, want default %d", got, DefaultPort)
+	}
+
+	if err := SaveState(townRoot, &State{Port: 3399, AutoPort: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := DefaultConfig(townRoot).Port; got != 3399 {
+		t.Errorf("port = %d, want auto-selected 3399", got)
+	}
+	if got := GetConnectionString(townRoot); !strings.Contains(got, ":3399)") {
+		t.Errorf("connection string %q doesn't use auto-selected port", got)
+	}
+
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatal(err)
+	}
+	readPort := func() interface{} {
+		data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var meta map[string]interface{}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			t.Fatal(err)
+		}
+		return meta["dolt_server_port"]
+	}
+	if got := readPort(); got != float64(3399) {
+		t.Errorf("dolt_server_port = %v, want 3399", got)
+	}
+
+	// Back on the default port, the override is dropped.
+	if err := SaveState(townRoot, &State{Port: DefaultPort}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureMetadata(townRoot, "myrig"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readPort(); got != nil {
+		t.Errorf("dolt_server_port = %v, want unset", got)
+	}
+}
@@ -0,0 +1,230 @@
+package doltserver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ReconcileReport describes what ReconcileState found and changed.
+type ReconcileReport struct {
+	// Previous is the state file contents before reconciling.
+	Previous State
+
+	// Current is the state written by ReconcileState.
+	Current State
+
+	// Reachable reports whether the server port accepted a TCP connection.
+	Reachable bool
+
+	// Changes lists human-readable corrections made to the state file.
+	Changes []string
+
+	// RemovedFiles lists stale PID and lock files that were deleted.
+	RemovedFiles []string
+
+	// Warnings lists problems found that reconciling could not fix.
+	Warnings []string
+}
+
+// Changed reports whether reconciling modified anything on disk.
+func (r *ReconcileReport) Changed() bool {
+	return len(r.Changes) > 0 || len(r.RemovedFiles) > 0
+}
+
+// ReconcileState rewrites dolt-state.json to match the actual server.
+//
+// The state file is only written by gt dolt start/stop, so it drifts when the
+// server is started externally, crashes, or is restarted by the daemon. This
+// probes the port, verifies the PID is a live dolt sql-server, re-lists the
+// databases, fixes or removes the PID file, and removes stale lock files.
+// Safe to call periodically (the daemon does so after each health check).
+func ReconcileState(townRoot string) (*ReconcileReport, error) {
+	config := DefaultConfig(townRoot)
+
+	// Hold the state lock until the rewrite so a concurrent gt dolt start
+	// can't record a server this pass then overwrites as stopped.
+	unlock, err := lockState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	prev, err := LoadState(townRoot)
+	if errors.Is(err, ErrNewerState) {
+		return nil, err
+	}
+	if err != nil {
+		// A corrupt state file is exactly what reconciling is for.
+		prev = &State{}
+	}
+	report := &ReconcileReport{Previous: *prev}
+	next := *prev
+	next.Port = config.Port
+	next.DataDir = config.DataDir
+
+	report.Reachable = portReachable(config.HostPort())
+
+	if config.IsRemote() {
+		next.Running = report.Reachable
+		next.PID = 0
+		if next.Running {
+			dbs, err := listDatabasesRemote(config)
+			if err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("listing remote databases: %v", err))
+			} else {
+				next.Databases = dbs
+			}
+		}
+		return finishReconcile(townRoot, report, next)
+	}
+
+	pid := pidFromFile(config.PidFile)
+	if pid != 0 && !isLiveDoltProcess(pid) {
+		if err := os.Remove(config.PidFile); err == nil {
+			report.RemovedFiles = append(report.RemovedFiles, config.PidFile)
+		}
+		pid = 0
+	}
+	if pid == 0 {
+		// Catches servers started outside gt dolt start.
+		if portPID := findDoltServerOnPort(config.Port); portPID > 0 {
+			pid = portPID
+			if err := os.WriteFile(config.PidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("writing PID file: %v", err))
+			} else {
+				report.Changes = append(report.Changes, fmt.Sprintf("PID file set to %d (server found on port %d)", pid, config.Port))
+			}
+		}
+	}
+
+	next.Running = pid > 0
+	next.PID = pid
+	if next.Running && pid != prev.PID {
+		next.StartedAt = processStartTime(pid)
+	}
+	if next.Running && !report.Reachable {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("dolt sql-server PID %d is alive but port %d is not accepting connections", pid, config.Port))
+	}
+	if !next.Running && report.Reachable {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("port %d is in use by a process that is not a dolt sql-server", config.Port))
+	}
+
+	dbs, err := ListDatabases(townRoot)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("listing databases: %v", err))
+	} else {
+		next.Databases = dbs
+	}
+
+	// A dolt.lock nobody holds is left over from a crashed gt dolt start.
+	artifacts := []lock.Artifact{{Path: filepath.Join(filepath.Dir(config.PidFile), "dolt.lock"), Kind: lock.FlockFile}}
+	// Database LOCK files are held by a running server; only clean them when it's down.
+	if !next.Running {
+		artifacts = append(artifacts, lock.DoltLockArtifacts(config.DataDir, nil)...)
+	}
+	for _, r := range lock.Sweep(artifacts, true) {
+		switch {
+		case r.Removed:
+			report.RemovedFiles = append(report.RemovedFiles, r.Path)
+		case r.Err != nil:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("removing stale %s: %v", r.Path, r.Err))
+		}
+	}
+
+	return finishReconcile(townRoot, report, next)
+}
+
+// finishReconcile records state differences and writes the state file if anything changed.
+func finishReconcile(townRoot string, report *ReconcileReport, next State) (*ReconcileReport, error) {
+	prev := report.Previous
+	if prev.Running != next.Running {
+		report.Changes = append(report.Changes, fmt.Sprintf("running: %t → %t", prev.Running, next.Running))
+	}
+	if prev.PID != next.PID {
+		report.Changes = append(report.Changes, fmt.Sprintf("pid: %d → %d", prev.PID, next.PID))
+	}
+	if prev.Port != next.Port {
+		report.Changes = append(report.Changes, fmt.Sprintf("port: %d → %d", prev.Port, next.Port))
+	}
+	if prev.DataDir != next.DataDir {
+		report.Changes = append(report.Changes, fmt.Sprintf("data dir: %q → %q", prev.DataDir, next.DataDir))
+	}
+	if !slices.Equal(prev.Databases, next.Databases) {
+		report.Changes = append(report.Changes, fmt.Sprintf("databases: [%s] → [%s]",
+			strings.Join(prev.Databases, ", "), strings.Join(next.Databases, ", ")))
+	}
+	report.Current = next
+
+	_, statErr := os.Stat(StateFile(townRoot))
+	if len(report.Changes) == 0 && statErr == nil {
+		return report, nil
+	}
+	if err := saveStateLocked(townRoot, &next); err != nil {
+		return report, fmt.Errorf("saving state: %w", err)
+	}
+	return report, nil
+}
+
+// portReachable reports whether addr accepts a TCP connection.
+func portReachable(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// pidFromFile reads a PID file, returning 0 if it is missing and -1 if malformed.
+func pidFromFile(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return -1 // malformed: treated as stale
+	}
+	return pid
+}
+
+// isLiveDoltProcess reports whether pid is alive and is a dolt sql-server.
+func isLiveDoltProcess(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if err := process.Signal(syscall.Signal(0)); err != nil {
+		return false
+	}
+	return isDoltProcess(pid)
+}
+
+// processStartTime returns when pid started, or now if ps can't tell us.
+func processStartTime(pid int) time.Time {
+	out, err := util.Output(exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "lstart="), util.ProbeTimeout)
+	if err != nil {
+		return time.Now()
+	}
+	// ps pads single-digit days; collapse whitespace so one layout fits both.
+	t, err := time.ParseInLocation("Mon Jan 2 15:04:05 2006", strings.Join(strings.Fields(string(out)), " "), time.Local)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
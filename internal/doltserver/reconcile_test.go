@@ -0,0 +1,119 @@
+package doltserver
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// freePort returns a local port nothing is listening on.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+	return port
+}
+
+func TestReconcileState_StaleStateAndFiles(t *testing.T) {
+	townRoot := t.TempDir()
+	port := freePort(t)
+	t.Setenv("GT_DOLT_HOST", "")
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(port))
+
+	config := DefaultConfig(townRoot)
+	for _, db := range []string{"hq", "gastown"} {
+		if err := os.MkdirAll(filepath.Join(config.DataDir, db, ".dolt", "noms"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := SaveState(townRoot, &State{Running: true, PID: 999999, Port: 3307, StartedAt: startedAt, Databases: []string{"hq", "deleted"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.PidFile, []byte("999999"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lockFile := filepath.Join(townRoot, "daemon", "dolt.lock")
+	if err := os.WriteFile(lockFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ReconcileState(townRoot)
+	if err != nil {
+		t.Fatalf("ReconcileState: %v", err)
+	}
+	if !report.Changed() {
+		t.Fatal("expected changes")
+	}
+	if report.Reachable {
+		t.Error("free port reported reachable")
+	}
+	for _, f := range []string{config.PidFile, lockFile} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("%s not removed", f)
+		}
+		if !slices.Contains(report.RemovedFiles, f) {
+			t.Errorf("RemovedFiles = %v, missing %s", report.RemovedFiles, f)
+		}
+	}
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Running || state.PID != 0 {
+		t.Errorf("state running=%t pid=%d, want stopped", state.Running, state.PID)
+	}
+	if state.Port != port {
+		t.Errorf("port = %d, want %d", state.Port, port)
+	}
+	if !slices.Equal(state.Databases, []string{"gastown", "hq"}) {
+		t.Errorf("databases = %v", state.Databases)
+	}
+	if !state.StartedAt.Equal(startedAt) {
+		t.Errorf("StartedAt = %v, want historical %v preserved", state.StartedAt, startedAt)
+	}
+
+	// A second pass finds nothing to do.
+	again, err := ReconcileState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Changed() {
+		t.Errorf("second reconcile changed: %v %v", again.Changes, again.RemovedFiles)
+	}
+}
+
+func TestReconcileState_CorruptStateFile(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_DOLT_HOST", "")
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(freePort(t)))
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(StateFile(townRoot), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(DefaultConfig(townRoot).PidFile, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReconcileState(townRoot); err != nil {
+		t.Fatalf("ReconcileState: %v", err)
+	}
+	if _, err := LoadState(townRoot); err != nil {
+		t.Errorf("state file still unreadable: %v", err)
+	}
+	if _, err := os.Stat(DefaultConfig(townRoot).PidFile); !os.IsNotExist(err) {
+		t.Error("malformed PID file not removed")
+	}
+}
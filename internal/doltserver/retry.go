@@ -0,0 +1,152 @@
+package doltserver
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errclass"
+	"github.com/steveyegge/gastown/internal/retry"
+)
+
+// scriptMaxAttempts caps retries of multi-statement scripts, which are more
+// expensive to repeat than single statements.
+const scriptMaxAttempts = 3
+
+// RetryPolicies returns the town's retry policies: the errclass defaults with
+// any "retry" overrides from settings/config.json applied. Settings that
+// can't be read or parsed leave the defaults in place.
+func RetryPolicies(townRoot string) errclass.Policies {
+	policies := errclass.DefaultPolicies()
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || len(settings.Retry) == 0 {
+		return policies
+	}
+	if applied, err := ApplyRetryConfig(policies, settings.Retry); err == nil {
+		return applied
+	}
+	return policies
+}
+
+// ApplyRetryConfig returns base with the overrides in cfg applied. Keys are
+// errclass class names; unset fields keep base's value. Operation keys (see
+// RetryOperations) are left to OperationPolicy.
+func ApplyRetryConfig(base errclass.Policies, cfg map[string]*config.RetryPolicyConfig) (errclass.Policies, error) {
+	known := make(map[errclass.Class]bool, len(errclass.Classes))
+	for _, c := range errclass.Classes {
+		known[c] = true
+	}
+
+	policies := make(errclass.Policies, len(base))
+	for c, p := range base {
+		policies[c] = p
+	}
+	for name, override := range cfg {
+		if slices.Contains(RetryOperations, name) {
+			continue
+		}
+		class := errclass.Class(name)
+		if !known[class] {
+			return nil, fmt.Errorf("retry: unknown error class %q", name)
+		}
+		if override == nil {
+			continue
+		}
+		p := policies.For(class)
+		if override.MaxAttempts != 0 {
+			p.MaxAttempts = override.MaxAttempts
+		}
+		if override.BaseBackoff != "" {
+			d, err := time.ParseDuration(override.BaseBackoff)
+			if err != nil {
+				return nil, fmt.Errorf("retry.%s.base_backoff: %w", name, err)
+			}
+			p.BaseBackoff = d
+		}
+		if override.MaxBackoff != "" {
+			d, err := time.ParseDuration(override.MaxBackoff)
+			if err != nil {
+				return nil, fmt.Errorf("retry.%s.max_backoff: %w", name, err)
+			}
+			p.MaxBackoff = d
+		}
+		if override.Jitter != nil {
+			p.Jitter = *override.Jitter
+		}
+		policies[class] = p
+	}
+	return policies, nil
+}
+
+// Retry operation names: the keys of TownSettings.Retry that tune waits
+// rather than error classes.
+const (
+	RetryDoltStart   = "dolt_start"   // a started server accepting connections
+	RetryDoltStop    = "dolt_stop"    // a stopped server exiting before SIGKILL
+	RetryDoltVerify  = "dolt_verify"  // SHOW DATABASES answering after a start
+	RetryDoltRecover = "dolt_recover" // a restarted server leaving read-only mode
+	RetryDoltCatalog = "dolt_catalog" // a created database appearing in the catalog
+	RetryMergeSlot   = "merge_slot"   // the refinery acquiring the merge slot
+)
+
+// RetryOperations lists the operation names.
+var RetryOperations = []string{
+	RetryDoltStart, RetryDoltStop, RetryDoltVerify, RetryDoltRecover, RetryDoltCatalog, RetryMergeSlot,
+}
+
+// defaultOperationPolicies are the built-in waits, by operation name.
+var defaultOperationPolicies = map[string]retry.Policy{
+	RetryDoltStart:   {MaxElapsed: 5 * time.Second, Initial: 100 * time.Millisecond, Max: time.Second},
+	RetryDoltStop:    {MaxElapsed: 5 * time.Second, Initial: 100 * time.Millisecond, Max: time.Second},
+	RetryDoltVerify:  {Initial: time.Second, Max: 8 * time.Second},
+	RetryDoltRecover: {MaxAttempts: 5, Initial: 500 * time.Millisecond, Max: 8 * time.Second},
+	RetryDoltCatalog: {MaxAttempts: 5, Initial: 100 * time.Millisecond, Max: 2 * time.Second},
+	RetryMergeSlot:   {MaxAttempts: 11, Initial: 500 * time.Millisecond, Max: 10 * time.Second, Jitter: 0.2},
+}
+
+// OperationPolicy returns the retry policy for one of RetryOperations:
+// the built-in default with the town's settings override applied. Settings
+// that can't be read or parsed leave the default in place.
+func OperationPolicy(townRoot, op string) retry.Policy {
+	policy := defaultOperationPolicies[op]
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Retry[op] == nil {
+		return policy
+	}
+	if applied, err := ApplyOperationConfig(policy, settings.Retry[op]); err == nil {
+		return applied
+	}
+	return policy
+}
+
+// ApplyOperationConfig returns base with override applied; unset fields
+// keep base's value.
+func ApplyOperationConfig(base retry.Policy, override *config.RetryPolicyConfig) (retry.Policy, error) {
+	p := base
+	if override.MaxAttempts != 0 {
+		p.MaxAttempts = override.MaxAttempts
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"base_backoff", override.BaseBackoff, &p.Initial},
+		{"max_backoff", override.MaxBackoff, &p.Max},
+		{"max_elapsed", override.MaxElapsed, &p.MaxElapsed},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return base, fmt.Errorf("retry %s: %w", d.name, err)
+		}
+		*d.dest = parsed
+	}
+	if override.Jitter != nil {
+		p.Jitter = *override.Jitter
+	}
+	return p, nil
+}
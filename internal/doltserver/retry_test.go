@@ -0,0 +1,118 @@
+package doltserver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errclass"
+)
+
+func TestApplyRetryConfig(t *testing.T) {
+	base := errclass.DefaultPolicies()
+	got, err := ApplyRetryConfig(base, map[string]*config.RetryPolicyConfig{
+		"capacity":   {MaxAttempts: 6, BaseBackoff: "5s"},
+		"corruption": {MaxAttempts: 2},
+		"read_only":  nil,
+	})
+	if err != nil {
+		t.Fatalf("ApplyRetryConfig: %v", err)
+	}
+	capacity := got[errclass.Capacity]
+	if capacity.MaxAttempts != 6 || capacity.BaseBackoff != 5*time.Second || capacity.MaxBackoff != base[errclass.Capacity].MaxBackoff {
+		t.Errorf("capacity = %+v", capacity)
+	}
+	if got[errclass.Corruption].MaxAttempts != 2 {
+		t.Errorf("corruption = %+v", got[errclass.Corruption])
+	}
+	if got[errclass.ReadOnly] != base[errclass.ReadOnly] {
+		t.Errorf("read_only changed: %+v", got[errclass.ReadOnly])
+	}
+	if base[errclass.Capacity].MaxAttempts == 6 {
+		t.Error("base policies were modified")
+	}
+
+	if _, err := ApplyRetryConfig(base, map[string]*config.RetryPolicyConfig{"flaky": {MaxAttempts: 2}}); err == nil {
+		t.Error("expected error for unknown class")
+	}
+	if _, err := ApplyRetryConfig(base, map[string]*config.RetryPolicyConfig{"retryable": {MaxBackoff: "soon"}}); err == nil {
+		t.Error("expected error for bad duration")
+	}
+}
+
+func TestRetryPolicies_FromSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := RetryPolicies(townRoot); got[errclass.Retryable] != errclass.DefaultPolicies()[errclass.Retryable] {
+		t.Errorf("no settings: %+v", got)
+	}
+
+	settings := config.NewTownSettings()
+	settings.Retry = map[string]*config.RetryPolicyConfig{"retryable": {MaxAttempts: 1}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := RetryPolicies(townRoot)[errclass.Retryable].MaxAttempts; got != 1 {
+		t.Errorf("retryable MaxAttempts = %d, want 1 from settings", got)
+	}
+
+	// Malformed overrides fall back to defaults rather than disabling retry.
+	bad := []byte(`{"type":"town-settings","version":1,"retry":{"retryable":{"base_backoff":"x"}}}`)
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), bad, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := RetryPolicies(townRoot); got[errclass.Retryable] != errclass.DefaultPolicies()[errclass.Retryable] {
+		t.Errorf("bad settings: %+v", got[errclass.Retryable])
+	}
+}
+
+func TestOperationPolicy_FromSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := OperationPolicy(townRoot, RetryDoltStart); got.MaxElapsed != 5*time.Second {
+		t.Errorf("default dolt_start MaxElapsed = %v, want 5s", got.MaxElapsed)
+	}
+
+	jitter := 0.0
+	settings := config.NewTownSettings()
+	settings.Retry = map[string]*config.RetryPolicyConfig{
+		RetryDoltStart: {MaxElapsed: "30s", BaseBackoff: "1s"},
+		RetryMergeSlot: {MaxAttempts: 3, Jitter: &jitter},
+		"capacity":     {MaxAttempts: 6},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	start := OperationPolicy(townRoot, RetryDoltStart)
+	if start.MaxElapsed != 30*time.Second || start.Initial != time.Second || start.Max != time.Second {
+		t.Errorf("dolt_start = %+v, want 30s elapsed from settings and default max", start)
+	}
+	if slot := OperationPolicy(townRoot, RetryMergeSlot); slot.MaxAttempts != 3 || slot.Jitter != 0 {
+		t.Errorf("merge_slot = %+v, want 3 attempts without jitter", slot)
+	}
+
+	// Operation keys sit alongside error classes without tripping validation.
+	if got := RetryPolicies(townRoot)[errclass.Capacity].MaxAttempts; got != 6 {
+		t.Errorf("capacity MaxAttempts = %d, want 6", got)
+	}
+
+	if _, err := ApplyOperationConfig(start, &config.RetryPolicyConfig{MaxElapsed: "later"}); err == nil {
+		t.Error("expected error for bad max_elapsed")
+	}
+}
+
+func TestSQLTimeoutRetryable(t *testing.T) {
+	killed := errors.New("signal: killed (output: )")
+	if got := errclass.Classify(sqlTimeoutRetryable(context.Background(), killed)); got != errclass.Unknown {
+		t.Errorf("without timeout: class = %q, want unknown", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+	if got := errclass.Classify(sqlTimeoutRetryable(ctx, killed)); got != errclass.Retryable {
+		t.Errorf("after timeout: class = %q, want retryable", got)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/progress"
 )
 
 // Backup represents a discovered migration backup directory.
@@ -98,6 +100,12 @@ type RollbackResult struct {
 // This resets metadata.json to the pre-migration state since the backup
 // contains the original metadata.json files.
 func RestoreFromBackup(townRoot, backupPath string) (*RollbackResult, error) {
+	return RestoreFromBackupWithProgress(townRoot, backupPath, nil)
+}
+
+// RestoreFromBackupWithProgress is RestoreFromBackup reporting bytes copied
+// to bar, which may be nil.
+func RestoreFromBackupWithProgress(townRoot, backupPath string, bar *progress.Bar) (*RollbackResult, error) {
 	// Verify backup directory exists
 	info, err := os.Stat(backupPath)
 	if err != nil {
@@ -110,12 +118,16 @@ func RestoreFromBackup(townRoot, backupPath string) (*RollbackResult, error) {
 	result := &RollbackResult{
 		BackupPath: backupPath,
 	}
+	if bar != nil {
+		bar.Phase("Restoring", progress.DirSize(backupPath), progress.Bytes)
+		defer bar.Finish()
+	}
 
 	// Restore town-level beads
 	townBackup := filepath.Join(backupPath, "town-beads")
 	if _, err := os.Stat(townBackup); err == nil {
 		townBeads := filepath.Join(townRoot, ".beads")
-		if err := replaceDir(townBeads, townBackup); err != nil {
+		if err := replaceDir(townBeads, townBackup, bar); err != nil {
 			return result, fmt.Errorf("restoring town beads: %w", err)
 		}
 		result.RestoredTown = true
@@ -144,7 +156,7 @@ func RestoreFromBackup(townRoot, backupPath string) (*RollbackResult, error) {
 			rigName := strings.TrimSuffix(name, "-beads")
 			rigBeads := filepath.Join(townRoot, rigName, ".beads")
 			rigBackup := filepath.Join(backupPath, name)
-			if err := replaceDir(rigBeads, rigBackup); err != nil {
+			if err := replaceDir(rigBeads, rigBackup, bar); err != nil {
 				result.SkippedRigs = append(result.SkippedRigs, rigName)
 				continue
 			}
@@ -166,7 +178,7 @@ func RestoreFromBackup(townRoot, backupPath string) (*RollbackResult, error) {
 				continue
 			}
 			rigBeads := filepath.Join(townRoot, rigName, ".beads")
-			if err := replaceDir(rigBeads, rigBackupBeads); err != nil {
+			if err := replaceDir(rigBeads, rigBackupBeads, bar); err != nil {
 				result.SkippedRigs = append(result.SkippedRigs, rigName)
 				continue
 			}
@@ -179,7 +191,7 @@ func RestoreFromBackup(townRoot, backupPath string) (*RollbackResult, error) {
 }
 
 // replaceDir removes dst (if it exists) and copies src to dst.
-func replaceDir(dst, src string) error {
+func replaceDir(dst, src string, bar *progress.Bar) error {
 	// Verify source exists
 	if _, err := os.Stat(src); err != nil {
 		return fmt.Errorf("source not found: %w", err)
@@ -198,15 +210,16 @@ func replaceDir(dst, src string) error {
 	}
 
 	// Copy recursively using cp -a to preserve permissions and timestamps
-	if err := copyDir(dst, src); err != nil {
+	if err := copyDir(dst, src, bar); err != nil {
 		return fmt.Errorf("copying %s to %s: %w", src, dst, err)
 	}
 
 	return nil
 }
 
-// copyDir recursively copies a directory tree.
-func copyDir(dst, src string) error {
+// copyDir recursively copies a directory tree, adding the bytes copied to
+// bar.
+func copyDir(dst, src string, bar *progress.Bar) error {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
 		return err
@@ -226,11 +239,11 @@ func copyDir(dst, src string) error {
 		dstPath := filepath.Join(dst, entry.Name())
 
 		if entry.IsDir() {
-			if err := copyDir(dstPath, srcPath); err != nil {
+			if err := copyDir(dstPath, srcPath, bar); err != nil {
 				return err
 			}
 		} else {
-			if err := copyFile(dstPath, srcPath); err != nil {
+			if err := copyFile(dstPath, srcPath, bar); err != nil {
 				return err
 			}
 		}
@@ -240,7 +253,7 @@ func copyDir(dst, src string) error {
 }
 
 // copyFile copies a single file preserving permissions.
-func copyFile(dst, src string) error {
+func copyFile(dst, src string, bar *progress.Bar) error {
 	data, err := os.ReadFile(src)
 	if err != nil {
 		return err
@@ -251,5 +264,9 @@ func copyFile(dst, src string) error {
 		return err
 	}
 
-	return os.WriteFile(dst, data, srcInfo.Mode())
+	if err := os.WriteFile(dst, data, srcInfo.Mode()); err != nil {
+		return err
+	}
+	bar.Add(int64(len(data)))
+	return nil
 }
@@ -290,7 +290,7 @@ func TestCopyDir(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := copyDir(dst, src); err != nil {
+	if err := copyDir(dst, src, nil); err != nil {
 		t.Fatalf("copyDir failed: %v", err)
 	}
 
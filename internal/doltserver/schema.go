@@ -0,0 +1,254 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// schemaVersionKey is the key in a beads database's metadata table under
+// which bd records the version that created or last migrated its schema.
+const schemaVersionKey = "bd_version"
+
+// schemaBackupPrefix starts the town-root directories UpgradeSchema backs
+// databases up into (schema-backup-<timestamp>/<db>).
+const schemaBackupPrefix = "schema-backup-"
+
+// SchemaState is how a database's schema compares with what is expected.
+type SchemaState string
+
+const (
+	SchemaCurrent SchemaState = "current" // at the expected version
+	SchemaBehind  SchemaState = "behind"  // older than expected: run gt dolt upgrade-schema
+	SchemaAhead   SchemaState = "ahead"   // newer than the installed bd: upgrade bd
+	SchemaUnknown SchemaState = "unknown" // no version recorded, or it couldn't be read
+)
+
+// SchemaStatus is the schema check result for one rig's database.
+type SchemaStatus struct {
+	Rig      string      `json:"rig"`
+	Database string      `json:"database"`
+	Version  string      `json:"version,omitempty"`  // recorded in the database
+	Expected string      `json:"expected,omitempty"` // see CheckSchemas
+	State    SchemaState `json:"state"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// SchemaEntry is what the schema registry knows about one database.
+type SchemaEntry struct {
+	// Expected is the schema version the database was last upgraded to.
+	// A database found older than this has been rolled back.
+	Expected   string    `json:"expected"`
+	UpgradedAt time.Time `json:"upgraded_at"`
+
+	// Backup is the pre-upgrade backup written by the last upgrade.
+	Backup string `json:"backup,omitempty"`
+}
+
+// SchemaRegistry records the expected schema version of each database.
+// It lives in daemon/dolt-schema.json.
+type SchemaRegistry struct {
+	Databases map[string]SchemaEntry `json:"databases"`
+}
+
+func schemaRegistryPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "dolt-schema.json")
+}
+
+// LoadSchemaRegistry reads the town's schema registry. A missing registry
+// is empty.
+func LoadSchemaRegistry(townRoot string) (*SchemaRegistry, error) {
+	reg := &SchemaRegistry{Databases: map[string]SchemaEntry{}}
+	data, err := os.ReadFile(schemaRegistryPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reg, nil
+		}
+		return nil, fmt.Errorf("reading schema registry: %w", err)
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("parsing schema registry: %w", err)
+	}
+	if reg.Databases == nil {
+		reg.Databases = map[string]SchemaEntry{}
+	}
+	return reg, nil
+}
+
+// Save writes the registry back to the town.
+func (r *SchemaRegistry) Save(townRoot string) error {
+	path := schemaRegistryPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, r)
+}
+
+// schemaTarget is a rig whose beads live in a Dolt server database.
+type schemaTarget struct {
+	Rig      string
+	Database string
+	BeadsDir string
+}
+
+// schemaTargets returns hq and every rig in rigs.json that has a beads
+// directory, sorted by rig name.
+func schemaTargets(townRoot string) []schemaTarget {
+	rigs := []string{"hq"}
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+	}
+	sort.Strings(rigs)
+
+	var targets []schemaTarget
+	for _, rig := range rigs {
+		if t, ok := schemaTargetFor(townRoot, rig); ok {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+func schemaTargetFor(townRoot, rig string) (schemaTarget, bool) {
+	beadsDir := FindRigBeadsDir(townRoot, rig)
+	if beadsDir == "" {
+		return schemaTarget{}, false
+	}
+	if _, err := os.Stat(beadsDir); err != nil {
+		return schemaTarget{}, false
+	}
+	db := readExistingDoltDatabase(beadsDir)
+	if db == "" {
+		db = rig
+	}
+	return schemaTarget{Rig: rig, Database: db, BeadsDir: beadsDir}, true
+}
+
+// CheckSchemas compares the schema version recorded in each rig's database
+// with the expected version: the newer of bdVersion (the installed bd's)
+// and the registry's entry. A database newer than bdVersion is ahead, since
+// the installed bd may not understand it. With bdVersion empty only the
+// registry is consulted. The server must be running.
+func CheckSchemas(townRoot, bdVersion string) ([]SchemaStatus, error) {
+	reg, err := LoadSchemaRegistry(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []SchemaStatus
+	for _, t := range schemaTargets(townRoot) {
+		version, err := readSchemaVersion(townRoot, t.Database)
+		statuses = append(statuses, compareSchema(t, version, err, bdVersion, reg.Databases[t.Database].Expected))
+	}
+	return statuses, nil
+}
+
+// compareSchema classifies one database's recorded version.
+func compareSchema(t schemaTarget, version string, readErr error, bdVersion, registered string) SchemaStatus {
+	s := SchemaStatus{Rig: t.Rig, Database: t.Database, Version: version, Expected: bdVersion}
+	if registered != "" && (s.Expected == "" || deps.CompareVersions(registered, s.Expected) > 0) {
+		s.Expected = registered
+	}
+	switch {
+	case readErr != nil:
+		s.State, s.Error = SchemaUnknown, readErr.Error()
+	case version == "":
+		s.State, s.Error = SchemaUnknown, "no schema version recorded"
+	case bdVersion != "" && deps.CompareVersions(version, bdVersion) > 0:
+		s.State, s.Expected = SchemaAhead, bdVersion
+	case s.Expected != "" && deps.CompareVersions(version, s.Expected) < 0:
+		s.State = SchemaBehind
+	case s.Expected == "":
+		s.State, s.Error = SchemaUnknown, "no expected version (bd version unknown and nothing registered)"
+	default:
+		s.State = SchemaCurrent
+	}
+	return s
+}
+
+// readSchemaVersion returns the bd version recorded in db's metadata table,
+// or "" if none is.
+// A variable so tests can run without a Dolt server.
+var readSchemaVersion = func(townRoot, db string) (string, error) {
+	conn, err := DB(townRoot, db)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var version string
+	err = conn.QueryRowContext(ctx, "SELECT value FROM metadata WHERE `key` = ?", schemaVersionKey).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading schema version of %s: %w", db, err)
+	}
+	return version, nil
+}
+
+// SchemaUpgrade describes a finished UpgradeSchema.
+type SchemaUpgrade struct {
+	Database string `json:"database"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Backup   string `json:"backup"`
+}
+
+// UpgradeSchema backs up a rig's database, has bd apply its migrations,
+// and checks the database now records bdVersion, which is then registered
+// as the database's expected version. On failure the backup is kept and
+// named in the error so the database can be restored from it.
+func UpgradeSchema(townRoot, rig, bdVersion string) (*SchemaUpgrade, error) {
+	if bdVersion == "" {
+		return nil, fmt.Errorf("installed bd version unknown; cannot tell which schema to upgrade to")
+	}
+	t, ok := schemaTargetFor(townRoot, rig)
+	if !ok {
+		return nil, fmt.Errorf("rig %q has no beads directory", rig)
+	}
+	from, err := readSchemaVersion(townRoot, t.Database)
+	if err != nil {
+		return nil, err
+	}
+	if from != "" && deps.CompareVersions(from, bdVersion) > 0 {
+		return nil, fmt.Errorf("database %s is at %s, newer than the installed bd %s; upgrade bd instead", t.Database, from, bdVersion)
+	}
+
+	backup := filepath.Join(townRoot, schemaBackupPrefix+time.Now().Format("20060102-150405"), t.Database)
+	if err := BackupDatabase(townRoot, t.Database, backup); err != nil {
+		return nil, fmt.Errorf("pre-upgrade backup: %w", err)
+	}
+	if _, err := runBD(t.BeadsDir, "migrate"); err != nil {
+		return nil, fmt.Errorf("migrating %s (backup at %s): %w", t.Database, backup, err)
+	}
+	to, err := readSchemaVersion(townRoot, t.Database)
+	if err != nil {
+		return nil, fmt.Errorf("verifying %s (backup at %s): %w", t.Database, backup, err)
+	}
+	if to == "" || deps.CompareVersions(to, bdVersion) < 0 {
+		return nil, fmt.Errorf("%s still records schema %q after bd migrate, want %s (backup at %s)", t.Database, to, bdVersion, backup)
+	}
+
+	reg, err := LoadSchemaRegistry(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	reg.Databases[t.Database] = SchemaEntry{Expected: to, UpgradedAt: time.Now().UTC(), Backup: backup}
+	if err := reg.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("recording upgrade: %w", err)
+	}
+	return &SchemaUpgrade{Database: t.Database, From: from, To: to, Backup: backup}, nil
+}
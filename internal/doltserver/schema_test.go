@@ -0,0 +1,119 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareSchema(t *testing.T) {
+	target := schemaTarget{Rig: "myrig", Database: "myrig"}
+	tests := []struct {
+		name, version, bd, registered string
+		want                          SchemaState
+		wantExpected                  string
+	}{
+		{"current", "0.55.0", "0.55.0", "", SchemaCurrent, "0.55.0"},
+		{"behind bd", "0.52.0", "0.55.0", "", SchemaBehind, "0.55.0"},
+		{"ahead of bd", "0.56.0", "0.55.0", "", SchemaAhead, "0.55.0"},
+		{"behind registry", "0.52.0", "", "0.54.0", SchemaBehind, "0.54.0"},
+		{"registry below bd", "0.54.0", "0.55.0", "0.54.0", SchemaBehind, "0.55.0"},
+		{"no version", "", "0.55.0", "", SchemaUnknown, "0.55.0"},
+		{"nothing expected", "0.55.0", "", "", SchemaUnknown, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareSchema(target, tt.version, nil, tt.bd, tt.registered)
+			if got.State != tt.want || got.Expected != tt.wantExpected {
+				t.Errorf("state = %s expected = %q, want %s %q", got.State, got.Expected, tt.want, tt.wantExpected)
+			}
+		})
+	}
+}
+
+// schemaTown makes a town with hq and one rig, each with a beads dir.
+func schemaTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+	for _, dir := range []string{".beads", filepath.Join("myrig", "mayor", "rig", ".beads"), "mayor"} {
+		if err := os.MkdirAll(filepath.Join(town, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigs := `{"version": 1, "rigs": {"myrig": {"git_url": "https://example.com/r.git"}}}`
+	if err := os.WriteFile(filepath.Join(town, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return town
+}
+
+func TestCheckSchemas(t *testing.T) {
+	town := schemaTown(t)
+	orig := readSchemaVersion
+	t.Cleanup(func() { readSchemaVersion = orig })
+	readSchemaVersion = func(_, db string) (string, error) {
+		return map[string]string{"hq": "0.55.0", "myrig": "0.52.0"}[db], nil
+	}
+
+	statuses, err := CheckSchemas(town, "0.55.0")
+	if err != nil {
+		t.Fatalf("CheckSchemas: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want hq and myrig: %+v", len(statuses), statuses)
+	}
+	if statuses[0].Rig != "hq" || statuses[0].State != SchemaCurrent {
+		t.Errorf("hq = %+v, want current", statuses[0])
+	}
+	if statuses[1].Rig != "myrig" || statuses[1].State != SchemaBehind {
+		t.Errorf("myrig = %+v, want behind", statuses[1])
+	}
+}
+
+func TestUpgradeSchema(t *testing.T) {
+	town := schemaTown(t)
+	origRead, origBD, origBackup := readSchemaVersion, runBD, backupDatabase
+	t.Cleanup(func() { readSchemaVersion, runBD, backupDatabase = origRead, origBD, origBackup })
+
+	version := "0.52.0"
+	var backedUp string
+	readSchemaVersion = func(_, _ string) (string, error) { return version, nil }
+	backupDatabase = func(_ *Config, db, url string) error {
+		backedUp = url
+		return nil
+	}
+	runBD = func(_ string, args ...string) (string, error) {
+		if args[0] != "migrate" {
+			t.Errorf("bd %v, want migrate", args)
+		}
+		if backedUp == "" {
+			t.Error("migrated before backing up")
+		}
+		version = "0.55.0"
+		return "", nil
+	}
+
+	up, err := UpgradeSchema(town, "myrig", "0.55.0")
+	if err != nil {
+		t.Fatalf("UpgradeSchema: %v", err)
+	}
+	if up.From != "0.52.0" || up.To != "0.55.0" || !strings.Contains(up.Backup, schemaBackupPrefix) {
+		t.Errorf("upgrade = %+v", up)
+	}
+	reg, err := LoadSchemaRegistry(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reg.Databases["myrig"]; got.Expected != "0.55.0" || got.Backup != up.Backup {
+		t.Errorf("registry entry = %+v", got)
+	}
+
+	// A migration that doesn't move the version fails and names the backup.
+	version = "0.52.0"
+	runBD = func(string, ...string) (string, error) { return "", nil }
+	backedUp = ""
+	if _, err := UpgradeSchema(town, "myrig", "0.55.0"); err == nil || !strings.Contains(err.Error(), "backup at") {
+		t.Errorf("err = %v, want it to name the backup", err)
+	}
+}
@@ -0,0 +1,176 @@
+package doltserver
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SeedOptions is what InitRigSeeded writes into a new rig database.
+type SeedOptions struct {
+	// Prefix is the rig's beads issue prefix (e.g. "gt"). Required.
+	Prefix string
+
+	// Repo is the rig's git URL, recorded on the rig identity bead.
+	Repo string
+
+	// SeedFile is an optional JSONL file of issues, in bd export format,
+	// imported after the schema is in place. Labels on those issues come
+	// with them.
+	SeedFile string
+}
+
+// SeedResult describes a rig database created and seeded by InitRigSeeded.
+type SeedResult struct {
+	BeadsDir string // the rig's .beads directory bd was pointed at
+	RigBead  string // ID of the rig identity bead
+}
+
+// InitRigSeeded creates a rig database like InitRig, then has bd apply the
+// beads schema, the Gas Town custom issue types, the issue prefix, the rig
+// identity bead, and any issues from opts.SeedFile. Each step is checked,
+// and the result is verified through bd before it returns. If anything
+// fails, the new database is dropped, so the rig is never left with a
+// half-initialized database.
+//
+// Seeding needs the Dolt server running (bd connects through it), and only
+// applies to new databases: an existing one is an error, not a no-op.
+func InitRigSeeded(townRoot, rigName string, opts SeedOptions) (*SeedResult, error) {
+	if !beads.ValidPrefix(opts.Prefix) {
+		return nil, fmt.Errorf("invalid prefix %q: must start with a letter and contain only letters, digits, and hyphens (max 20)", opts.Prefix)
+	}
+	if opts.SeedFile != "" {
+		if _, err := os.Stat(opts.SeedFile); err != nil {
+			return nil, fmt.Errorf("seed file: %w", err)
+		}
+	}
+	if DatabaseExists(townRoot, rigName) {
+		return nil, fmt.Errorf("database %q already exists; seeding only applies to new databases", rigName)
+	}
+	if running, _, _ := IsRunning(townRoot); !running {
+		return nil, fmt.Errorf("seeding needs the Dolt server running (start it with: gt dolt start)")
+	}
+
+	serverWasRunning, created, err := InitRig(townRoot, rigName)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Created concurrently between the check above and InitRig: not ours to seed.
+		return nil, fmt.Errorf("database %q already exists; seeding only applies to new databases", rigName)
+	}
+
+	result, err := func() (*SeedResult, error) {
+		if !serverWasRunning {
+			return nil, fmt.Errorf("the Dolt server stopped before the database was registered")
+		}
+		beadsDir, err := FindOrCreateRigBeadsDir(townRoot, rigName)
+		if err != nil {
+			return nil, fmt.Errorf("resolving beads directory: %w", err)
+		}
+		if err := seedDatabase(townRoot, rigName, beadsDir, opts); err != nil {
+			return nil, err
+		}
+		rigBead := beads.RigBeadIDWithPrefix(opts.Prefix, rigName)
+		if err := verifySeed(beadsDir, opts.Prefix, rigBead); err != nil {
+			return nil, fmt.Errorf("verifying seeded database: %w", err)
+		}
+		return &SeedResult{BeadsDir: beadsDir, RigBead: rigBead}, nil
+	}()
+	if err != nil {
+		if rmErr := RemoveDatabase(townRoot, rigName); rmErr != nil {
+			return nil, fmt.Errorf("seeding rig database %q: %w (rollback also failed: %v)", rigName, err, rmErr)
+		}
+		return nil, fmt.Errorf("seeding rig database %q (rolled back): %w", rigName, err)
+	}
+	return result, nil
+}
+
+// seedDatabase applies the schema and seed data to the rig database bd
+// reaches through beadsDir, stopping at the first failed step.
+func seedDatabase(townRoot, rigName, beadsDir string, opts SeedOptions) error {
+	if out, err := runBD(beadsDir, "init", "--prefix", opts.Prefix, "--server"); err != nil && !strings.Contains(out, "already initialized") {
+		return err
+	}
+	// bd init points metadata.json at beads_<prefix>; every later step must
+	// reach the rig's database instead.
+	if err := EnsureMetadata(townRoot, rigName); err != nil {
+		return fmt.Errorf("pointing metadata.json at %q: %w", rigName, err)
+	}
+	for _, args := range [][]string{
+		{"config", "set", "issue_prefix", opts.Prefix},
+		{"config", "set", "types.custom", constants.BeadsCustomTypes},
+		{"config", "set", "sync.mode", "dolt-native"},
+	} {
+		if _, err := runBD(beadsDir, args...); err != nil {
+			return err
+		}
+	}
+	if err := beads.EnsureConfigYAML(beadsDir, opts.Prefix); err != nil {
+		return fmt.Errorf("ensuring config.yaml: %w", err)
+	}
+	if opts.SeedFile != "" {
+		path, err := filepath.Abs(opts.SeedFile)
+		if err != nil {
+			return fmt.Errorf("seed file: %w", err)
+		}
+		if _, err := runBD(beadsDir, "import", "-i", path); err != nil {
+			return err
+		}
+	}
+	return ensureSeedRigBead(beadsDir, rigName, &beads.RigFields{
+		Repo:   opts.Repo,
+		Prefix: opts.Prefix,
+		State:  beads.RigStateActive,
+	})
+}
+
+// verifySeed reads the seeded configuration and rig bead back through bd.
+func verifySeed(beadsDir, prefix, rigBead string) error {
+	got, err := runBD(beadsDir, "config", "get", "issue_prefix")
+	if err != nil {
+		return err
+	}
+	if got != prefix {
+		return fmt.Errorf("issue_prefix is %q, want %q", got, prefix)
+	}
+	types, err := runBD(beadsDir, "config", "get", "types.custom")
+	if err != nil {
+		return err
+	}
+	if types == "" || strings.Contains(types, "not set") {
+		return fmt.Errorf("types.custom is not set")
+	}
+	if _, err := runBD(beadsDir, "show", rigBead, "--json"); err != nil {
+		return fmt.Errorf("rig bead %s not readable: %w", rigBead, err)
+	}
+	return nil
+}
+
+// runBD runs bd against beadsDir and returns its trimmed combined output.
+// A variable so tests can run without bd installed.
+var runBD = func(beadsDir string, args ...string) (string, error) {
+	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
+	// bd init must run from the parent directory, not inside .beads/.
+	cmd.Dir = filepath.Dir(beadsDir)
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	output, err := util.CombinedOutput(cmd, util.BeadsTimeout)
+	out := strings.TrimSpace(string(output))
+	if err != nil {
+		return out, fmt.Errorf("bd %s: %s: %w", strings.Join(args, " "), out, err)
+	}
+	return out, nil
+}
+
+// ensureSeedRigBead creates the rig identity bead.
+// A variable so tests can run without bd installed.
+var ensureSeedRigBead = func(beadsDir, rigName string, fields *beads.RigFields) error {
+	_, err := beads.NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir).EnsureRigBead(rigName, fields)
+	return err
+}
@@ -0,0 +1,117 @@
+package doltserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// stubSeedBD replaces bd with an in-memory config store and returns the
+// commands run. A command whose line contains failOn fails.
+func stubSeedBD(t *testing.T, failOn string) *[]string {
+	t.Helper()
+	origBD, origRigBead := runBD, ensureSeedRigBead
+	t.Cleanup(func() { runBD, ensureSeedRigBead = origBD, origRigBead })
+
+	var calls []string
+	cfg := map[string]string{}
+	rigBeads := map[string]bool{}
+	runBD = func(_ string, args ...string) (string, error) {
+		line := strings.Join(args, " ")
+		calls = append(calls, line)
+		if failOn != "" && strings.Contains(line, failOn) {
+			return "boom", errors.New("exit status 1")
+		}
+		switch {
+		case len(args) == 4 && args[0] == "config" && args[1] == "set":
+			cfg[args[2]] = args[3]
+		case len(args) == 3 && args[0] == "config" && args[1] == "get":
+			return cfg[args[2]], nil
+		case args[0] == "show" && !rigBeads[args[1]]:
+			return "not found", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+	ensureSeedRigBead = func(_ string, rigName string, fields *beads.RigFields) error {
+		calls = append(calls, "rig bead "+rigName)
+		rigBeads[beads.RigBeadIDWithPrefix(fields.Prefix, rigName)] = true
+		return nil
+	}
+	return &calls
+}
+
+func TestSeedDatabase(t *testing.T) {
+	town := t.TempDir()
+	beadsDir := filepath.Join(town, "myrig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	seedFile := filepath.Join(t.TempDir(), "seed.jsonl")
+	calls := stubSeedBD(t, "")
+
+	opts := SeedOptions{Prefix: "mr", SeedFile: seedFile}
+	if err := seedDatabase(town, "myrig", beadsDir, opts); err != nil {
+		t.Fatalf("seedDatabase: %v", err)
+	}
+	if err := verifySeed(beadsDir, "mr", "mr-rig-myrig"); err != nil {
+		t.Fatalf("verifySeed: %v", err)
+	}
+
+	got := strings.Join(*calls, "\n")
+	for _, want := range []string{"init --prefix mr --server", "config set issue_prefix mr", "config set types.custom", "import -i " + seedFile, "rig bead myrig", "show mr-rig-myrig --json"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("bd calls missing %q:\n%s", want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(beadsDir, "config.yaml")); err != nil {
+		t.Errorf("config.yaml not written: %v", err)
+	}
+}
+
+func TestSeedDatabase_StopsAtFailedStep(t *testing.T) {
+	town := t.TempDir()
+	beadsDir := filepath.Join(town, "myrig", ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	calls := stubSeedBD(t, "types.custom")
+
+	err := seedDatabase(town, "myrig", beadsDir, SeedOptions{Prefix: "mr"})
+	if err == nil {
+		t.Fatal("seedDatabase succeeded with a failing step")
+	}
+	for _, c := range *calls {
+		if strings.HasPrefix(c, "rig bead") {
+			t.Errorf("rig bead created after a failed step: %v", *calls)
+		}
+	}
+}
+
+func TestVerifySeed_DetectsMissingData(t *testing.T) {
+	stubSeedBD(t, "")
+	if err := verifySeed("/x/.beads", "mr", "mr-rig-myrig"); err == nil {
+		t.Error("verifySeed passed with nothing seeded")
+	}
+}
+
+func TestInitRigSeeded_Validation(t *testing.T) {
+	town := t.TempDir()
+	stubSeedBD(t, "")
+
+	if _, err := InitRigSeeded(town, "myrig", SeedOptions{Prefix: "-bad"}); err == nil || !strings.Contains(err.Error(), "invalid prefix") {
+		t.Errorf("bad prefix err = %v", err)
+	}
+	if _, err := InitRigSeeded(town, "myrig", SeedOptions{Prefix: "mr", SeedFile: filepath.Join(town, "missing.jsonl")}); err == nil || !strings.Contains(err.Error(), "seed file") {
+		t.Errorf("missing seed file err = %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(town, ".dolt-data", "myrig", ".dolt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InitRigSeeded(town, "myrig", SeedOptions{Prefix: "mr"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("existing database err = %v", err)
+	}
+}
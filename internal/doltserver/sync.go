@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // SyncOptions controls the behavior of SyncDatabases.
@@ -50,7 +52,7 @@ type SyncResult struct {
 func HasRemote(dbDir string) (string, error) {
 	cmd := exec.Command("dolt", "remote", "-v")
 	cmd.Dir = dbDir
-	output, err := cmd.CombinedOutput()
+	output, err := util.CombinedOutput(cmd, util.DoltTimeout)
 	if err != nil {
 		return "", fmt.Errorf("dolt remote -v: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
@@ -78,14 +80,14 @@ func CommitWorkingSet(dbDir string) error {
 	// Stage all changes
 	addCmd := exec.Command("dolt", "add", ".")
 	addCmd.Dir = dbDir
-	if output, err := addCmd.CombinedOutput(); err != nil {
+	if output, err := util.CombinedOutput(addCmd, util.DoltTimeout); err != nil {
 		return fmt.Errorf("dolt add: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
 
 	// Commit (may fail with "nothing to commit" which is fine)
 	commitCmd := exec.Command("dolt", "commit", "-m", "gt dolt sync: auto-commit working changes")
 	commitCmd.Dir = dbDir
-	output, err := commitCmd.CombinedOutput()
+	output, err := util.CombinedOutput(commitCmd, util.DoltTimeout)
 	if err != nil {
 		msg := strings.TrimSpace(string(output))
 		// "nothing to commit" or "no changes added" is success — no changes to push
@@ -109,7 +111,7 @@ func PushDatabase(dbDir string, force bool) error {
 
 	cmd := exec.Command("dolt", args...)
 	cmd.Dir = dbDir
-	output, err := cmd.CombinedOutput()
+	output, err := util.CombinedOutput(cmd, util.TransferTimeout)
 	if err != nil {
 		return fmt.Errorf("dolt push: %w (%s)", err, strings.TrimSpace(string(output)))
 	}
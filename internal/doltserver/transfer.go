@@ -0,0 +1,255 @@
+package doltserver
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// TransferTarget is the destination town of a database transfer: a local
+// path or, with Host set, a path on another machine reached over SSH.
+type TransferTarget struct {
+	// Host is the SSH destination ("user@host" or an ssh_config alias).
+	// Empty means the town is on this machine.
+	Host string
+
+	// TownRoot is the destination town's root directory.
+	TownRoot string
+}
+
+// ParseTransferTarget parses "/path/to/town" or "host:/path/to/town".
+func ParseTransferTarget(s string) (TransferTarget, error) {
+	if s == "" {
+		return TransferTarget{}, fmt.Errorf("destination town is required")
+	}
+	if host, path, ok := strings.Cut(s, ":"); ok && host != "" && !strings.Contains(host, "/") {
+		if strings.HasPrefix(host, "-") {
+			return TransferTarget{}, fmt.Errorf("invalid SSH host %q", host)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return TransferTarget{}, fmt.Errorf("remote town path must be absolute: %q", s)
+		}
+		return TransferTarget{Host: host, TownRoot: filepath.Clean(path)}, nil
+	}
+	abs, err := filepath.Abs(s)
+	if err != nil {
+		return TransferTarget{}, fmt.Errorf("resolving %s: %w", s, err)
+	}
+	return TransferTarget{TownRoot: abs}, nil
+}
+
+// IsRemote reports whether the target is reached over SSH.
+func (t TransferTarget) IsRemote() bool {
+	return t.Host != ""
+}
+
+// String returns the target in the form ParseTransferTarget accepts.
+func (t TransferTarget) String() string {
+	if t.IsRemote() {
+		return t.Host + ":" + t.TownRoot
+	}
+	return t.TownRoot
+}
+
+// DataDir returns the target town's Dolt data directory.
+func (t TransferTarget) DataDir() string {
+	return filepath.Join(t.TownRoot, ".dolt-data")
+}
+
+// Command returns a command that runs argv in dir on the target machine.
+func (t TransferTarget) Command(dir string, argv ...string) *exec.Cmd {
+	if !t.IsRemote() {
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Dir = dir
+		return cmd
+	}
+	args := (&config.RemoteConfig{Host: t.Host}).SSHArgs()
+	args = append(args, config.RemoteShellCommand(dir, argv...))
+	return exec.Command(args[0], args[1:]...)
+}
+
+// run runs argv on the target, returning stdout and folding stderr into errors.
+func (t TransferTarget) run(dir string, argv ...string) (string, error) {
+	cmd := t.Command(dir, argv...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", strings.Join(argv, " "), err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", strings.Join(argv, " "), err)
+	}
+	return string(out), nil
+}
+
+// exists reports whether path exists on the target.
+func (t TransferTarget) exists(path string) (bool, error) {
+	if !t.IsRemote() {
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	err := t.Command("", "test", "-e", path).Run()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("checking %s on %s: %w", path, t.Host, err)
+}
+
+// BranchHash is a Dolt branch and the commit hash at its head.
+type BranchHash struct {
+	Name string
+	Hash string
+}
+
+// branchHashes lists the branch heads of the database at dbDir on target.
+// A variable so tests can run without dolt installed.
+var branchHashes = func(target TransferTarget, dbDir string) ([]BranchHash, error) {
+	out, err := target.run(dbDir, "dolt", "sql", "-r", "csv", "-q", "SELECT name, hash FROM dolt_branches ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing dolt_branches: %w", err)
+	}
+	var hashes []BranchHash
+	for i, rec := range records {
+		if i == 0 || len(rec) < 2 {
+			continue // header
+		}
+		hashes = append(hashes, BranchHash{Name: rec[0], Hash: rec[1]})
+	}
+	return hashes, nil
+}
+
+// TransferResult describes a completed database copy.
+type TransferResult struct {
+	// Source and Dest are the database directories on each side.
+	Source string
+	Dest   string
+
+	// Branches are the verified branch heads, identical on both sides.
+	Branches []BranchHash
+}
+
+// TransferDatabase copies a rig database from townRoot's .dolt-data into the
+// target town and verifies that every branch head matches before returning.
+// The source is left untouched; callers remove it (RemoveDatabase) once the
+// destination town is updated. The local server must be stopped so the
+// database can't change mid-copy, and the target must not already have a
+// database of the same name.
+func TransferDatabase(townRoot, rigName string, target TransferTarget) (*TransferResult, error) {
+	cfg := DefaultConfig(townRoot)
+	if cfg.IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote (%s) — its databases aren't on this machine", cfg.HostPort())
+	}
+	srcDir := filepath.Join(cfg.DataDir, rigName)
+	if _, err := os.Stat(filepath.Join(srcDir, ".dolt")); err != nil {
+		return nil, fmt.Errorf("database %q not found at %s", rigName, srcDir)
+	}
+	if running, pid, _ := IsRunning(townRoot); running {
+		return nil, fmt.Errorf("Dolt server is running (PID %d); stop it with 'gt dolt stop' so %s can't change during the transfer", pid, rigName)
+	}
+	if !target.IsRemote() && filepath.Clean(target.TownRoot) == filepath.Clean(townRoot) {
+		return nil, fmt.Errorf("destination is the source town")
+	}
+
+	destDir := filepath.Join(target.DataDir(), rigName)
+	if exists, err := target.exists(destDir); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, fmt.Errorf("database %q already exists in %s", rigName, target)
+	}
+
+	srcHashes, err := branchHashes(TransferTarget{}, srcDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading source branches: %w", err)
+	}
+	if len(srcHashes) == 0 {
+		return nil, fmt.Errorf("source database %q has no branches", rigName)
+	}
+
+	// Stage next to the final location so the last step is a rename.
+	staging := filepath.Join(target.DataDir(), ".transfer-"+rigName)
+	if _, err := target.run("", "mkdir", "-p", staging); err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	cleanup := func() { _, _ = target.run("", "rm", "-rf", staging) }
+
+	if err := copyDatabase(cfg.DataDir, rigName, target, staging); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	stagedDir := filepath.Join(staging, rigName)
+	destHashes, err := branchHashes(target, stagedDir)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("reading copied branches: %w", err)
+	}
+	if !slices.Equal(srcHashes, destHashes) {
+		cleanup()
+		return nil, fmt.Errorf("verification failed: branch heads differ after copy (source %v, copy %v)", srcHashes, destHashes)
+	}
+
+	if _, err := target.run("", "mv", stagedDir, destDir); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("moving copy into place: %w", err)
+	}
+	cleanup()
+
+	return &TransferResult{Source: srcDir, Dest: destDir, Branches: srcHashes}, nil
+}
+
+// copyDatabase streams dataDir/rigName into staging on the target with tar,
+// which works the same locally and piped through ssh.
+func copyDatabase(dataDir, rigName string, target TransferTarget, staging string) error {
+	send := exec.Command("tar", "-C", dataDir, "-cf", "-", rigName)
+	recv := target.Command(staging, "tar", "-xf", "-")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("creating tar pipe: %w", err)
+	}
+	send.Stdout = w
+	recv.Stdin = r
+	var sendErr, recvErr bytes.Buffer
+	send.Stderr = &sendErr
+	recv.Stderr = &recvErr
+
+	if err := recv.Start(); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return fmt.Errorf("starting receiver: %w", err)
+	}
+	_ = r.Close()
+	if err := send.Start(); err != nil {
+		_ = w.Close()
+		_ = recv.Wait()
+		return fmt.Errorf("starting tar: %w", err)
+	}
+	_ = w.Close()
+	sErr := send.Wait()
+	rErr := recv.Wait()
+	if sErr != nil {
+		return fmt.Errorf("archiving %s: %w: %s", rigName, sErr, strings.TrimSpace(sendErr.String()))
+	}
+	if rErr != nil {
+		return fmt.Errorf("extracting %s on %s: %w: %s", rigName, target.String(), rErr, strings.TrimSpace(recvErr.String()))
+	}
+	return nil
+}
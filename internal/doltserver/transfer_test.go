@@ -0,0 +1,113 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseTransferTarget(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    TransferTarget
+		wantErr bool
+	}{
+		{in: "/srv/gt", want: TransferTarget{TownRoot: "/srv/gt"}},
+		{in: "build-box:/home/me/gt/", want: TransferTarget{Host: "build-box", TownRoot: "/home/me/gt"}},
+		{in: "me@build-box:/home/me/gt", want: TransferTarget{Host: "me@build-box", TownRoot: "/home/me/gt"}},
+		{in: "build-box:gt", wantErr: true},
+		{in: "-oProxyCommand=x:/gt", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTransferTarget(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTransferTarget(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseTransferTarget(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// stubBranchHashes replaces dolt with a reader of a fake HEAD file.
+func stubBranchHashes(t *testing.T) {
+	t.Helper()
+	orig := branchHashes
+	t.Cleanup(func() { branchHashes = orig })
+	branchHashes = func(_ TransferTarget, dbDir string) ([]BranchHash, error) {
+		data, err := os.ReadFile(filepath.Join(dbDir, ".dolt", "HEAD"))
+		if err != nil {
+			return nil, err
+		}
+		return []BranchHash{{Name: "main", Hash: strings.TrimSpace(string(data))}}, nil
+	}
+}
+
+func makeTransferTowns(t *testing.T) (src, dst string) {
+	t.Helper()
+	t.Setenv("GT_DOLT_HOST", "")
+	t.Setenv("GT_DOLT_PORT", strconv.Itoa(freePort(t)))
+	src, dst = t.TempDir(), t.TempDir()
+	dbDir := filepath.Join(src, ".dolt-data", "myrig", ".dolt")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dbDir, "HEAD"), []byte("abc123\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return src, dst
+}
+
+func TestTransferDatabase_Local(t *testing.T) {
+	stubBranchHashes(t)
+	src, dst := makeTransferTowns(t)
+
+	result, err := TransferDatabase(src, "myrig", TransferTarget{TownRoot: dst})
+	if err != nil {
+		t.Fatalf("TransferDatabase: %v", err)
+	}
+	if len(result.Branches) != 1 || result.Branches[0].Hash != "abc123" {
+		t.Errorf("branches = %v", result.Branches)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".dolt-data", "myrig", ".dolt", "HEAD")); err != nil {
+		t.Errorf("destination copy missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".dolt-data", ".transfer-myrig")); !os.IsNotExist(err) {
+		t.Error("staging directory left behind")
+	}
+	if _, err := os.Stat(filepath.Join(src, ".dolt-data", "myrig", ".dolt")); err != nil {
+		t.Error("source must be left for the caller to remove")
+	}
+
+	if _, err := TransferDatabase(src, "myrig", TransferTarget{TownRoot: dst}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second transfer err = %v, want already exists", err)
+	}
+}
+
+func TestTransferDatabase_VerificationFailure(t *testing.T) {
+	stubBranchHashes(t)
+	src, dst := makeTransferTowns(t)
+
+	calls := 0
+	verify := branchHashes
+	branchHashes = func(target TransferTarget, dbDir string) ([]BranchHash, error) {
+		calls++
+		if calls == 2 {
+			return []BranchHash{{Name: "main", Hash: "different"}}, nil
+		}
+		return verify(target, dbDir)
+	}
+
+	_, err := TransferDatabase(src, "myrig", TransferTarget{TownRoot: dst})
+	if err == nil || !strings.Contains(err.Error(), "verification failed") {
+		t.Fatalf("err = %v, want verification failure", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dst, ".dolt-data"))
+	if len(entries) != 0 {
+		t.Errorf("destination not cleaned up: %v", entries)
+	}
+}
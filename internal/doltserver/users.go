@@ -0,0 +1,223 @@
+package doltserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// rigUserPrefix starts the name of every per-rig SQL user gt creates, so
+// ListRigUsers can tell them from users an operator made by hand.
+const rigUserPrefix = "gt_"
+
+// maxSQLUserLen is MySQL's limit on user names, which Dolt enforces too.
+const maxSQLUserLen = 32
+
+// sqlNameRe restricts user and database names embedded in account
+// statements, which cannot take placeholders.
+var sqlNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// RigUser is a per-rig SQL user on the Dolt server.
+type RigUser struct {
+	User   string   `json:"user"`
+	Host   string   `json:"host"`
+	Grants []string `json:"grants"`
+}
+
+// RigUserName returns the SQL user gt creates for a rig database.
+func RigUserName(rigDB string) (string, error) {
+	if !sqlNameRe.MatchString(rigDB) {
+		return "", fmt.Errorf("database name %q contains invalid characters", rigDB)
+	}
+	user := rigUserPrefix + strings.ReplaceAll(rigDB, "-", "_")
+	if len(user) > maxSQLUserLen {
+		return "", fmt.Errorf("user name %q is longer than %d characters", user, maxSQLUserLen)
+	}
+	return user, nil
+}
+
+// GeneratePassword returns a random password safe to quote in SQL.
+func GeneratePassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CreateRigUser creates user (or resets its password if it exists) with
+// every privilege on rigDB and nothing else, connecting from any host.
+func CreateRigUser(ctx context.Context, townRoot, rigDB, user, password string) error {
+	if !sqlNameRe.MatchString(rigDB) {
+		return fmt.Errorf("database name %q contains invalid characters", rigDB)
+	}
+	return createAccount(ctx, townRoot, user, password, []string{
+		fmt.Sprintf("GRANT ALL PRIVILEGES ON `%s`.* TO %%s", rigDB),
+	})
+}
+
+// ViewerUser is the built-in read-only user for dashboards and reporting
+// tools.
+const ViewerUser = "viewer"
+
+// ViewerSecret names the secret holding the viewer's password.
+const ViewerSecret = "dolt-viewer"
+
+// EnsureViewerUser creates the viewer user (or resets its password) and
+// grants it SELECT on every served database, so it can read all beads
+// data and change none of it. System databases, whose tables include
+// password hashes, are left out; rerun after adding rigs.
+func EnsureViewerUser(ctx context.Context, townRoot, password string) error {
+	databases, err := ListDatabases(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing databases: %w", err)
+	}
+	var grants []string
+	for _, name := range databases {
+		if IsSystemDatabase(name) {
+			continue
+		}
+		if !sqlNameRe.MatchString(name) {
+			return fmt.Errorf("database name %q contains invalid characters", name)
+		}
+		grants = append(grants, fmt.Sprintf("GRANT SELECT ON `%s`.* TO %%s", name))
+	}
+	return createAccount(ctx, townRoot, ViewerUser, password, grants)
+}
+
+// createAccount creates user@'%' or resets its password, then runs each
+// grant, a GRANT statement with %s where the account goes.
+func createAccount(ctx context.Context, townRoot, user, password string, grants []string) error {
+	if !sqlNameRe.MatchString(user) {
+		return fmt.Errorf("user name %q contains invalid characters", user)
+	}
+	if strings.ContainsAny(password, `'\`) {
+		return fmt.Errorf("password contains quote characters")
+	}
+	db, err := DB(townRoot, "")
+	if err != nil {
+		return err
+	}
+	account := fmt.Sprintf("'%s'@'%%'", user)
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE USER IF NOT EXISTS %s IDENTIFIED BY '%s'", account, password)); err != nil {
+		return fmt.Errorf("creating user %s: %w", user, err)
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER USER %s IDENTIFIED BY '%s'", account, password)); err != nil {
+		return fmt.Errorf("setting password for %s: %w", user, err)
+	}
+	for _, grant := range grants {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(grant, account)); err != nil {
+			return fmt.Errorf("granting privileges to %s: %w", user, err)
+		}
+	}
+	return nil
+}
+
+// DropRigUser removes user from the server.
+func DropRigUser(ctx context.Context, townRoot, user string) error {
+	if !sqlNameRe.MatchString(user) {
+		return fmt.Errorf("user name %q contains invalid characters", user)
+	}
+	db, err := DB(townRoot, "")
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP USER IF EXISTS '%s'@'%%'", user)); err != nil {
+		return fmt.Errorf("dropping user %s: %w", user, err)
+	}
+	return nil
+}
+
+// ListRigUsers returns the per-rig users on the server with their grants.
+func ListRigUsers(ctx context.Context, townRoot string) ([]RigUser, error) {
+	db, err := DB(townRoot, "")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT User, Host FROM mysql.user WHERE User LIKE ? ORDER BY User", `gt\_%`)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	var users []RigUser
+	for rows.Next() {
+		var u RigUser
+		if err := rows.Scan(&u.User, &u.Host); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		results, err := QueryAll(ctx, db, fmt.Sprintf("SHOW GRANTS FOR '%s'@'%s'", users[i].User, users[i].Host))
+		if err != nil {
+			return nil, fmt.Errorf("showing grants for %s: %w", users[i].User, err)
+		}
+		for _, rs := range results {
+			for _, row := range rs.Rows {
+				if len(row) > 0 && row[0] != nil {
+					users[i].Grants = append(users[i].Grants, *row[0])
+				}
+			}
+		}
+	}
+	return users, nil
+}
+
+// ViewerDSN returns the viewer's connection string in go-sql-driver form,
+// user:password@tcp(host:port)/database; database may be empty.
+func ViewerDSN(townRoot, password, database string) string {
+	cfg := mysql.NewConfig()
+	cfg.User = ViewerUser
+	cfg.Passwd = password
+	cfg.Net = "tcp"
+	cfg.Addr = DefaultConfig(townRoot).HostPort()
+	cfg.DBName = database
+	return cfg.FormatDSN()
+}
+
+// SetMetadataUser sets the dolt_server_user bd connects as in beadsDir's
+// metadata.json, or removes it (back to the server's default user) when
+// user is empty. Other fields are preserved.
+func SetMetadataUser(beadsDir, user string) error {
+	metadataPath := filepath.Join(beadsDir, beads.MetadataFileName)
+	mu := getMetadataMu(metadataPath)
+	mu.Lock()
+	defer mu.Unlock()
+
+	data, err := os.ReadFile(metadataPath) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("reading metadata.json: %w", err)
+	}
+	existing := make(map[string]interface{})
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return fmt.Errorf("parsing metadata.json: %w", err)
+	}
+	if user == "" {
+		delete(existing, "dolt_server_user")
+	} else {
+		existing["dolt_server_user"] = user
+	}
+	out, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling metadata: %w", err)
+	}
+	if err := util.AtomicWriteFile(metadataPath, append(out, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing metadata.json: %w", err)
+	}
+	return nil
+}
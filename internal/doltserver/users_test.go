@@ -0,0 +1,62 @@
+package doltserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestRigUserName(t *testing.T) {
+	if got, err := RigUserName("my-rig"); err != nil || got != "gt_my_rig" {
+		t.Errorf("RigUserName(my-rig) = %q, %v", got, err)
+	}
+	for _, db := range []string{"a'b", "", strings.Repeat("x", 40)} {
+		if _, err := RigUserName(db); err == nil {
+			t.Errorf("RigUserName(%q) accepted", db)
+		}
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	a, err := GeneratePassword()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GeneratePassword()
+	if a == b || len(a) < 24 || strings.ContainsAny(a, `'\`) {
+		t.Errorf("GeneratePassword() = %q, %q", a, b)
+	}
+}
+
+func TestSetMetadataUser(t *testing.T) {
+	beadsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_database":"gastown","custom":1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMetadataUser(beadsDir, "gt_gastown"); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := beads.ReadBackendMetadata(beadsDir)
+	if err != nil || meta.DoltServerUser != "gt_gastown" || meta.DoltDatabase != "gastown" {
+		t.Fatalf("after set: %+v, %v", meta, err)
+	}
+	if err := SetMetadataUser(beadsDir, ""); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(beadsDir, "metadata.json"))
+	if strings.Contains(string(data), "dolt_server_user") || !strings.Contains(string(data), "custom") {
+		t.Errorf("after clear: %s", data)
+	}
+}
+
+func TestViewerDSN(t *testing.T) {
+	t.Setenv("GT_DOLT_HOST", "db.example.com")
+	t.Setenv("GT_DOLT_PORT", "3310")
+	got := ViewerDSN(t.TempDir(), "pw", "gastown")
+	if want := "viewer:pw@tcp(db.example.com:3310)/gastown"; got != want {
+		t.Errorf("ViewerDSN() = %q, want %q", got, want)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // WLCommonsDB is the database name for the wl-commons shared wanted board.
@@ -328,10 +330,12 @@ func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
 }
 
 // doltSQLQuery executes a SQL query and returns the raw CSV output.
-func doltSQLQuery(townRoot, query string) (string, error) {
+func doltSQLQuery(townRoot, query string) (_ string, err error) {
 	config := DefaultConfig(townRoot)
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
+	ctx, span := startSQLSpan(ctx, "", query)
+	defer func() { telemetry.End(span, err) }()
 
 	cmd := buildDoltSQLCmd(ctx, config, "-r", "csv", "-q", query)
 	output, err := cmd.CombinedOutput()
@@ -0,0 +1,150 @@
+// Package errclass sorts Dolt and SQL errors into categories and decides,
+// per category, whether and how an operation is retried.
+//
+// Dolt reports most failures as text (dolt sql CLI output) or as MySQL
+// protocol errors, so classification checks typed errors first and then
+// falls back to matching the message. Callers should use Classify instead
+// of matching error strings themselves.
+package errclass
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Class is an error category.
+type Class string
+
+const (
+	// Unknown is any error not recognized below. It is never retried.
+	Unknown Class = ""
+
+	// Retryable is transient contention: manifest and optimistic lock
+	// conflicts, lock wait timeouts, and catalog races after CREATE DATABASE.
+	Retryable Class = "retryable"
+
+	// ReadOnly means the server refused a write because it is read-only.
+	// Dolt can enter this state under concurrent write load and may not
+	// recover without a restart (see doltserver.RecoverReadOnly).
+	ReadOnly Class = "read_only"
+
+	// Capacity is a resource limit: connections, disk, or memory.
+	Capacity Class = "capacity"
+
+	// Corruption is damaged storage. It is never retried by default;
+	// retrying can make things worse.
+	Corruption Class = "corruption"
+)
+
+// Classes lists the known categories, in the order messages are matched.
+var Classes = []Class{Corruption, ReadOnly, Capacity, Retryable}
+
+// Transient reports whether errors of class c may clear on their own.
+func (c Class) Transient() bool {
+	return c == Retryable || c == ReadOnly || c == Capacity
+}
+
+// Error attaches a class to an error, overriding message-based
+// classification. Use Wrap to create one.
+type Error struct {
+	Class Class
+	Err   error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap returns err tagged with class, or nil if err is nil.
+func Wrap(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Class: class, Err: err}
+}
+
+// Is reports whether err is of class c.
+func Is(err error, c Class) bool {
+	return err != nil && Classify(err) == c
+}
+
+// MySQL server error numbers with a known class.
+// See https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var mysqlClasses = map[uint16]Class{
+	1040: Capacity,  // ER_CON_COUNT_ERROR: too many connections
+	1203: Capacity,  // ER_TOO_MANY_USER_CONNECTIONS
+	1049: Retryable, // ER_BAD_DB_ERROR: unknown database (catalog race)
+	1205: Retryable, // ER_LOCK_WAIT_TIMEOUT
+	1213: Retryable, // ER_LOCK_DEADLOCK
+	1290: ReadOnly,  // ER_OPTION_PREVENTS_STATEMENT (--read-only)
+	1792: ReadOnly,  // ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+}
+
+// Classify returns err's class. Errors wrapped with Wrap keep their class;
+// MySQL protocol errors are classified by number; anything else by message.
+func Classify(err error) Class {
+	if err == nil {
+		return Unknown
+	}
+	var tagged *Error
+	if errors.As(err, &tagged) {
+		return tagged.Class
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		if c, ok := mysqlClasses[myErr.Number]; ok {
+			return c
+		}
+	}
+	return ClassifyMessage(err.Error())
+}
+
+// messagePatterns are lowercase substrings identifying each class.
+var messagePatterns = map[Class][]string{
+	Corruption: {
+		"corrupt",
+		"checksum error",
+		"checksum mismatch",
+		"chunk not found",
+		"invalid journal record",
+	},
+	ReadOnly: {
+		// Characteristic: "cannot update manifest: database is read only".
+		"read only",
+		"read-only",
+		"readonly",
+	},
+	Capacity: {
+		"too many connections",
+		"no space left on device",
+		"disk quota exceeded",
+		"cannot allocate memory",
+	},
+	Retryable: {
+		"cannot update manifest",
+		"optimistic lock",
+		"serialization failure",
+		"lock wait timeout",
+		"try restarting transaction",
+		"deadlock",
+		// Catalog race: CREATE DATABASE returned but the database isn't
+		// visible yet. Distinct from "database not found" (never created).
+		"unknown database",
+	},
+}
+
+// ClassifyMessage classifies an error message, such as dolt sql output.
+func ClassifyMessage(msg string) Class {
+	lower := strings.ToLower(msg)
+	for _, c := range Classes {
+		for _, p := range messagePatterns[c] {
+			if strings.Contains(lower, p) {
+				return c
+			}
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,164 @@
+package errclass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestClassifyMessage(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want Class
+	}{
+		{"cannot update manifest: database is read only", ReadOnly},
+		{"error: read-only mode", ReadOnly},
+		{"cannot update manifest", Retryable},
+		{"optimistic lock failed", Retryable},
+		{"serialization failure", Retryable},
+		{"Lock wait timeout exceeded; try restarting transaction", Retryable},
+		{"exit status 1 (output: Unknown database 'newrig')", Retryable},
+		{"Error 1040: Too many connections", Capacity},
+		{"write /data/x: no space left on device", Capacity},
+		{"chunk not found: corrupt journal", Corruption},
+		{"database not found", Unknown},
+		{"connection refused", Unknown},
+		{"syntax error near 'FOO'", Unknown},
+		{"", Unknown},
+	}
+	for _, tt := range tests {
+		if got := ClassifyMessage(tt.msg); got != tt.want {
+			t.Errorf("ClassifyMessage(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	if got := Classify(nil); got != Unknown {
+		t.Errorf("Classify(nil) = %q", got)
+	}
+	myErr := fmt.Errorf("querying: %w", &mysql.MySQLError{Number: 1040, Message: "busy"})
+	if got := Classify(myErr); got != Capacity {
+		t.Errorf("mysql 1040 = %q, want capacity", got)
+	}
+	// Unmapped numbers fall back to the message.
+	myErr = &mysql.MySQLError{Number: 1105, Message: "database is read only"}
+	if got := Classify(myErr); got != ReadOnly {
+		t.Errorf("mysql 1105 read only = %q, want read_only", got)
+	}
+	// A Wrap tag overrides the message.
+	tagged := fmt.Errorf("outer: %w", Wrap(Corruption, errors.New("lock wait timeout")))
+	if got := Classify(tagged); got != Corruption {
+		t.Errorf("tagged = %q, want corruption", got)
+	}
+	if !Is(tagged, Corruption) || Is(tagged, Retryable) {
+		t.Error("Is disagrees with Classify")
+	}
+	if Wrap(Corruption, nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+}
+
+func TestPolicyBackoff(t *testing.T) {
+	p := Policy{BaseBackoff: 500 * time.Millisecond, MaxBackoff: 3 * time.Second}
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if got := p.Backoff(1); got < 375*time.Millisecond || got > 625*time.Millisecond {
+			t.Fatalf("jittered Backoff(1) = %v, want within ±25%% of 500ms", got)
+		}
+	}
+}
+
+func TestPoliciesForAndLimit(t *testing.T) {
+	p := DefaultPolicies()
+	if got := p.For(Unknown).MaxAttempts; got != 1 {
+		t.Errorf("unknown MaxAttempts = %d, want 1", got)
+	}
+	if got := p.For(Corruption).MaxAttempts; got != 1 {
+		t.Errorf("corruption MaxAttempts = %d, want 1", got)
+	}
+	limited := p.Limit(2)
+	if limited[Retryable].MaxAttempts != 2 || p[Retryable].MaxAttempts != 5 {
+		t.Errorf("Limit: got %d (original %d)", limited[Retryable].MaxAttempts, p[Retryable].MaxAttempts)
+	}
+}
+
+func fastPolicies(attempts int) Policies {
+	return Policies{
+		Retryable: {MaxAttempts: attempts, BaseBackoff: time.Millisecond},
+		ReadOnly:  {MaxAttempts: 2, BaseBackoff: time.Millisecond},
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	err := Retry(ctx, fastPolicies(5), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("optimistic lock failed")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("transient then success: err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	err = Retry(ctx, fastPolicies(5), func() error {
+		calls++
+		return errors.New("syntax error")
+	})
+	if calls != 1 || err == nil || strings.Contains(err.Error(), "attempts") {
+		t.Errorf("unknown error: err=%v calls=%d, want one attempt, unwrapped", err, calls)
+	}
+
+	calls = 0
+	err = Retry(ctx, fastPolicies(3), func() error {
+		calls++
+		return errors.New("lock wait timeout")
+	})
+	if calls != 3 || err == nil || !strings.Contains(err.Error(), "after 3 attempts") || !Is(err, Retryable) {
+		t.Errorf("exhausted: err=%v calls=%d", err, calls)
+	}
+
+	// Attempts are counted across classes: the read-only policy allows 2.
+	calls = 0
+	_ = Retry(ctx, fastPolicies(5), func() error {
+		calls++
+		if calls == 1 {
+			return errors.New("lock wait timeout")
+		}
+		return errors.New("database is read only")
+	})
+	if calls != 2 {
+		t.Errorf("mixed classes: calls=%d, want 2", calls)
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := Policies{Retryable: {MaxAttempts: 5, BaseBackoff: time.Hour}}
+	calls := 0
+	err := Retry(ctx, slow, func() error {
+		calls++
+		return errors.New("lock wait timeout")
+	})
+	if calls != 1 || err == nil || !strings.Contains(err.Error(), "retry canceled") {
+		t.Errorf("canceled: err=%v calls=%d", err, calls)
+	}
+}
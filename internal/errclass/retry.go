@@ -0,0 +1,90 @@
+package errclass
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/retry"
+)
+
+// Policy is how errors of one class are retried.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// 1 or less disables retry.
+	MaxAttempts int
+
+	// BaseBackoff is the delay after the first failure; it doubles after
+	// each further failure up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	// Jitter randomizes each delay by ±Jitter/2 (0.5 gives ±25%).
+	Jitter float64
+}
+
+// Backoff returns the delay after failed attempt n (1-indexed).
+func (p Policy) Backoff(n int) time.Duration {
+	return retry.Policy{Initial: p.BaseBackoff, Max: p.MaxBackoff, Jitter: p.Jitter}.Delay(n)
+}
+
+// Policies maps each class to its retry policy. Classes without an entry
+// are not retried.
+type Policies map[Class]Policy
+
+// DefaultPolicies returns the built-in policies. Read-only errors are
+// retried like transient contention because the caller's recovery path
+// (server restart) only runs once retries are exhausted.
+func DefaultPolicies() Policies {
+	return Policies{
+		Retryable: {MaxAttempts: 5, BaseBackoff: 500 * time.Millisecond, MaxBackoff: 15 * time.Second},
+		ReadOnly:  {MaxAttempts: 5, BaseBackoff: 500 * time.Millisecond, MaxBackoff: 15 * time.Second},
+		Capacity:  {MaxAttempts: 3, BaseBackoff: 2 * time.Second, MaxBackoff: 30 * time.Second},
+	}
+}
+
+// For returns the policy for class c.
+func (p Policies) For(c Class) Policy {
+	if policy, ok := p[c]; ok && c != Unknown {
+		return policy
+	}
+	return Policy{MaxAttempts: 1}
+}
+
+// Limit returns a copy of p with every MaxAttempts capped at n, for callers
+// whose operations are too expensive to repeat as often.
+func (p Policies) Limit(n int) Policies {
+	limited := make(Policies, len(p))
+	for c, policy := range p {
+		policy.MaxAttempts = min(policy.MaxAttempts, n)
+		limited[c] = policy
+	}
+	return limited
+}
+
+// Retry calls fn until it succeeds, fails with an error whose class
+// policy is exhausted, or ctx is done. Attempts are counted across classes,
+// so a failure of one class followed by another doesn't reset the count.
+// An error that was retried is returned as "after N attempts: <err>".
+func Retry(ctx context.Context, policies Policies, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		policy := policies.For(Classify(err))
+		if attempt >= policy.MaxAttempts {
+			if attempt > 1 {
+				return fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry canceled after %d attempts: %v)", err, attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
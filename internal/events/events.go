@@ -68,6 +68,24 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Bead change events (emitted by the daemon from Dolt commits)
+	TypeBeadCreated       = "bead_created"
+	TypeBeadStatusChanged = "bead_status_changed"
+	TypeBeadAssigned      = "bead_assigned"
+	TypeBeadAbandoned     = "bead_abandoned" // idle in_progress bead of a dead agent reopened
+
+	// Infrastructure events (emitted by the daemon)
+	TypeDoltRestarted         = "dolt_restarted"
+	TypeDoltReadOnly          = "dolt_read_only"           // write probe found read-only databases
+	TypeDoltReadOnlyRecovered = "dolt_read_only_recovered" // restart cleared the read-only state
+	TypeRigBranchChanged      = "rig_branch_changed"       // upstream default branch renamed; rig config updated
+	TypeRigUpstreamSynced     = "rig_upstream_synced"      // fork default branch fast-forwarded or rebased onto upstream
+	TypeRigUpstreamDiverged   = "rig_upstream_diverged"    // fork default branch has diverged from upstream
+
+	// Custom patrol events (emitted by the daemon for plugin/configured patrols)
+	TypeCustomPatrolRan    = "custom_patrol_ran"
+	TypeCustomPatrolFailed = "custom_patrol_failed"
 )
 
 // EventsFile is the name of the raw events log.
@@ -88,6 +106,21 @@ func Log(eventType, actor string, payload map[string]interface{}, visibility str
 	return write(event)
 }
 
+// LogAt writes an event to the events log of an explicit town. Use it from
+// long-running processes (e.g. the daemon) whose working directory may not
+// be inside the town.
+func LogAt(townRoot, eventType, actor string, payload map[string]interface{}, visibility string) error {
+	event := Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: visibility,
+	}
+	return writeTo(townRoot, event)
+}
+
 // LogFeed is a convenience wrapper for feed-visible events.
 func LogFeed(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityFeed)
@@ -108,7 +141,11 @@ func write(event Event) error {
 		// Silently ignore - we're not in a Gas Town workspace
 		return nil
 	}
+	return writeTo(townRoot, event)
+}
 
+// writeTo appends an event to the given town's events file.
+func writeTo(townRoot string, event Event) error {
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
 	// Marshal event to JSON
@@ -148,6 +185,26 @@ func SlingPayload(beadID, target string) map[string]interface{} {
 	}
 }
 
+// BeadChangePayload creates a payload for bead change events.
+// Empty old/new values are omitted.
+func BeadChangePayload(database, beadID, title, oldValue, newValue, commit string) map[string]interface{} {
+	p := map[string]interface{}{
+		"database": database,
+		"bead":     beadID,
+		"commit":   commit,
+	}
+	if title != "" {
+		p["title"] = title
+	}
+	if oldValue != "" {
+		p["old"] = oldValue
+	}
+	if newValue != "" {
+		p["new"] = newValue
+	}
+	return p
+}
+
 // HookPayload creates a payload for hook events.
 func HookPayload(beadID string) map[string]interface{} {
 	return map[string]interface{}{
@@ -227,6 +284,22 @@ func PatrolPayload(rig string, polecatCount int, message string) map[string]inte
 	return p
 }
 
+// CustomPatrolPayload creates a payload for custom patrol run events.
+// output is the patrol's stdout; errMsg is empty when the run succeeded.
+func CustomPatrolPayload(patrol, source, output, errMsg string) map[string]interface{} {
+	p := map[string]interface{}{
+		"patrol": patrol,
+		"source": source,
+	}
+	if output != "" {
+		p["output"] = output
+	}
+	if errMsg != "" {
+		p["error"] = errMsg
+	}
+	return p
+}
+
 // PolecatCheckPayload creates a payload for polecat check events.
 func PolecatCheckPayload(rig, polecat, status, issue string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -296,6 +369,71 @@ func SessionDeathPayload(session, agent, reason, caller string) map[string]inter
 	}
 }
 
+// DoltRestartPayload creates a payload for Dolt server restart events.
+// attempt is the restart's position within the current backoff window;
+// reason is why the server was restarted (e.g. "server_dead").
+func DoltRestartPayload(port, attempt int, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"port":    port,
+		"attempt": attempt,
+		"reason":  reason,
+	}
+}
+
+// DoltReadOnlyPayload creates a payload for read-only detection and recovery
+// events. failures is how many consecutive recovery attempts have failed;
+// errMsg is empty unless the latest attempt failed.
+func DoltReadOnlyPayload(databases []string, failures int, errMsg string) map[string]interface{} {
+	p := map[string]interface{}{
+		"databases": databases,
+		"failures":  failures,
+	}
+	if errMsg != "" {
+		p["error"] = errMsg
+	}
+	return p
+}
+
+// RigBranchChangedPayload creates a payload for rig_branch_changed events.
+// staleClones lists clones (relative to the rig) still on the old branch.
+func RigBranchChangedPayload(rig, previous, current string, staleClones []string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":          rig,
+		"previous":     previous,
+		"current":      current,
+		"stale_clones": staleClones,
+	}
+}
+
+// RigUpstreamSyncPayload creates a payload for rig_upstream_synced and
+// rig_upstream_diverged events. ahead and behind count fork and upstream
+// commits as found before syncing.
+func RigUpstreamSyncPayload(rig, branch, status string, ahead, behind int, errMsg string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"branch": branch,
+		"status": status,
+		"ahead":  ahead,
+		"behind": behind,
+	}
+	if errMsg != "" {
+		p["error"] = errMsg
+	}
+	return p
+}
+
+// BeadAbandonedPayload creates a payload for bead_abandoned events. status
+// is the status the bead was moved to.
+func BeadAbandonedPayload(rig, beadID, assignee, status, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":      rig,
+		"bead":     beadID,
+		"assignee": assignee,
+		"status":   status,
+		"reason":   reason,
+	}
+}
+
 // MassDeathPayload creates a payload for mass death events.
 // count: number of sessions that died
 // window: time window in which deaths occurred (e.g., "5s")
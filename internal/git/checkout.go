@@ -0,0 +1,160 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Git config keys recording a rig's checkout options. They are set on the
+// shared .repo.git, whose config every worktree reads, and on standalone
+// clones, so each new checkout initializes submodules and LFS the same way.
+const (
+	configSubmodules = "gastown.submodules"
+	configLFS        = "gastown.lfs"
+)
+
+// LFS modes, matching config.LFSAuto, LFSOn and LFSOff.
+const (
+	LFSAuto = "auto"
+	LFSOn   = "on"
+	LFSOff  = "off"
+)
+
+// ErrLFSMissing is returned when LFS is required but git-lfs is not installed.
+var ErrLFSMissing = errors.New("git-lfs is not installed")
+
+// CheckoutOptions controls how clones and worktrees are checked out.
+type CheckoutOptions struct {
+	Submodules bool   // recursive submodule init/update
+	LFS        string // LFSAuto, LFSOn or LFSOff
+}
+
+// DefaultCheckoutOptions initializes submodules and LFS when the repo uses them.
+func DefaultCheckoutOptions() CheckoutOptions {
+	return CheckoutOptions{Submodules: true, LFS: LFSAuto}
+}
+
+// lfsAvailable reports whether git-lfs is installed. A variable so tests
+// can run either way.
+var lfsAvailable = func() bool {
+	_, err := exec.LookPath("git-lfs")
+	return err == nil
+}
+
+// LFSAvailable reports whether git-lfs is installed.
+func LFSAvailable() bool {
+	return lfsAvailable()
+}
+
+// SetCheckoutOptions sets the options recorded on clones made by g. With
+// nil, clones use the defaults and record nothing.
+func (g *Git) SetCheckoutOptions(opts *CheckoutOptions) {
+	g.checkout = opts
+}
+
+// ApplyCheckoutOptions records opts in the git config of repoPath, a bare
+// repo or a clone, for later worktrees and checkouts.
+func ApplyCheckoutOptions(repoPath string, opts CheckoutOptions) error {
+	if opts.LFS == "" {
+		opts.LFS = LFSAuto
+	}
+	for key, value := range map[string]string{
+		configSubmodules: fmt.Sprintf("%t", opts.Submodules),
+		configLFS:        opts.LFS,
+	} {
+		cmd := exec.Command("git", "-C", repoPath, "config", key, value)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := util.Run(cmd, util.GitTimeout); err != nil {
+			return fmt.Errorf("setting %s: %s", key, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return nil
+}
+
+// ReadCheckoutOptions returns the options recorded for repoPath, or the
+// defaults for those not set.
+func ReadCheckoutOptions(repoPath string) CheckoutOptions {
+	opts := DefaultCheckoutOptions()
+	if v := gitConfigGet(repoPath, configSubmodules); v == "false" {
+		opts.Submodules = false
+	}
+	switch v := gitConfigGet(repoPath, configLFS); v {
+	case LFSOn, LFSOff:
+		opts.LFS = v
+	}
+	return opts
+}
+
+func gitConfigGet(repoPath, key string) string {
+	out, err := util.Output(exec.Command("git", "-C", repoPath, "config", "--get", key), util.GitTimeout)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// UsesLFS reports whether the checkout at path routes any files through
+// the lfs filter in its top-level .gitattributes.
+func UsesLFS(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, ".gitattributes"))
+	return err == nil && bytes.Contains(data, []byte("filter=lfs"))
+}
+
+// InitLFS installs the LFS hooks and filters in the checkout at path and
+// downloads its LFS objects.
+func InitLFS(path string) error {
+	if !lfsAvailable() {
+		return ErrLFSMissing
+	}
+	for _, args := range [][]string{{"lfs", "install", "--local"}, {"lfs", "pull"}} {
+		cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := util.Run(cmd, util.TransferTimeout); err != nil {
+			return fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+		}
+	}
+	return nil
+}
+
+// InitCheckout brings a fresh clone or worktree at path up to its recorded
+// checkout options: submodules, then LFS objects. In auto mode a repo that
+// uses LFS is left with pointer files when git-lfs is missing, and with a
+// warning when the LFS pull fails; on mode treats either as an error.
+func InitCheckout(path string) error {
+	opts := ReadCheckoutOptions(path)
+	if opts.Submodules {
+		if err := InitSubmodules(path); err != nil {
+			return err
+		}
+	}
+	switch {
+	case opts.LFS == LFSOn:
+		if err := InitLFS(path); err != nil {
+			return fmt.Errorf("initializing LFS: %w", err)
+		}
+	case opts.LFS == LFSAuto && UsesLFS(path) && lfsAvailable():
+		if err := InitLFS(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: initializing LFS in %s: %v (leaving pointer files)\n", path, err)
+		}
+	}
+	return nil
+}
+
+// initClone records g's checkout options on a new clone and initializes it.
+func (g *Git) initClone(dest string) error {
+	if g.checkout != nil {
+		if err := ApplyCheckoutOptions(dest, *g.checkout); err != nil {
+			return err
+		}
+	}
+	return InitCheckout(dest)
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckoutOptionsRoundTrip(t *testing.T) {
+	dir := initTestRepo(t)
+	if got := ReadCheckoutOptions(dir); got != DefaultCheckoutOptions() {
+		t.Fatalf("unset options = %+v, want defaults", got)
+	}
+
+	want := CheckoutOptions{Submodules: false, LFS: LFSOff}
+	if err := ApplyCheckoutOptions(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCheckoutOptions(dir); got != want {
+		t.Errorf("ReadCheckoutOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestInitCheckout_SubmodulesDisabled(t *testing.T) {
+	parent, _ := initTestRepoWithSubmodule(t)
+	cloneDest := filepath.Join(t.TempDir(), "clone")
+	cmd := exec.Command("git", "-c", "protocol.file.allow=always", "clone", parent, cloneDest)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("clone: %v\n%s", err, out)
+	}
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	if err := ApplyCheckoutOptions(cloneDest, CheckoutOptions{Submodules: false, LFS: LFSOff}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitCheckout(cloneDest); err != nil {
+		t.Fatalf("InitCheckout: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cloneDest, "libs", "sub", "lib.go")); err == nil {
+		t.Fatal("submodule initialized although disabled")
+	}
+
+	if err := ApplyCheckoutOptions(cloneDest, CheckoutOptions{Submodules: true, LFS: LFSOff}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitCheckout(cloneDest); err != nil {
+		t.Fatalf("InitCheckout: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cloneDest, "libs", "sub", "lib.go")); err != nil {
+		t.Fatalf("expected submodule file after InitCheckout: %v", err)
+	}
+}
+
+func TestInitCheckout_LFS(t *testing.T) {
+	orig := lfsAvailable
+	defer func() { lfsAvailable = orig }()
+	lfsAvailable = func() bool { return false }
+
+	dir := initTestRepo(t)
+	if UsesLFS(dir) {
+		t.Fatal("plain repo reported as using LFS")
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !UsesLFS(dir) {
+		t.Fatal("expected LFS use from .gitattributes")
+	}
+
+	// Auto mode leaves pointer files when git-lfs is missing.
+	if err := InitCheckout(dir); err != nil {
+		t.Errorf("auto without git-lfs: %v", err)
+	}
+
+	// A failed pull in auto mode is a warning, not an error (the test repo
+	// has no remote to pull from, and git-lfs may not be installed).
+	lfsAvailable = func() bool { return true }
+	if err := InitCheckout(dir); err != nil {
+		t.Errorf("auto with failing lfs pull: %v", err)
+	}
+	lfsAvailable = func() bool { return false }
+
+	// On mode requires it.
+	if err := ApplyCheckoutOptions(dir, CheckoutOptions{Submodules: true, LFS: LFSOn}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitCheckout(dir); !errors.Is(err, ErrLFSMissing) {
+		t.Errorf("on without git-lfs: got %v, want ErrLFSMissing", err)
+	}
+}
@@ -9,7 +9,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -25,6 +30,10 @@ type GitError struct {
 }
 
 func (e *GitError) Error() string {
+	var timeout *util.TimeoutError
+	if errors.As(e.Err, &timeout) {
+		return fmt.Sprintf("git %s: %v", e.Command, timeout)
+	}
 	if e.Stderr != "" {
 		return fmt.Sprintf("git %s: %s", e.Command, e.Stderr)
 	}
@@ -38,14 +47,21 @@ func (e *GitError) Unwrap() error {
 // moveDir moves a directory from src to dest. It first tries os.Rename for
 // efficiency, but falls back to copy+delete if src and dest are on different
 // filesystems (which causes EXDEV error on rename).
-func moveDir(src, dest string) error {
+// A non-nil bar shows the copy's progress.
+func moveDir(src, dest string, bar *progress.Bar) error {
 	// Try rename first - works if same filesystem
 	if err := os.Rename(src, dest); err == nil {
 		return nil
 	}
 
 	// Rename failed, use platform-specific copy for cross-filesystem moves
-	if err := copyDirPreserving(src, dest); err != nil {
+	if bar != nil {
+		bar.Phase("Copying into place", progress.DirSize(src), progress.Bytes)
+	}
+	stop := progress.WatchDir(bar, dest)
+	err := copyDirPreserving(src, dest)
+	stop()
+	if err != nil {
 		return fmt.Errorf("copying directory: %w", err)
 	}
 	if err := os.RemoveAll(src); err != nil {
@@ -57,7 +73,11 @@ func moveDir(src, dest string) error {
 // Git wraps git operations for a working directory.
 type Git struct {
 	workDir string
-	gitDir  string // Optional: explicit git directory (for bare repos)
+	gitDir  string   // Optional: explicit git directory (for bare repos)
+	remote  []string // Optional: ssh argv to run commands on another machine; see NewRemoteGit
+
+	progress *progress.Bar    // Optional: clone progress; see SetProgress
+	checkout *CheckoutOptions // Optional: recorded on clones; see SetCheckoutOptions
 }
 
 // NewGit creates a new Git wrapper for the given directory.
@@ -90,16 +110,13 @@ func (g *Git) run(args ...string) (string, error) {
 		args = append([]string{"--git-dir=" + g.gitDir}, args...)
 	}
 
-	cmd := exec.Command("git", args...)
-	if g.workDir != "" {
-		cmd.Dir = g.workDir
-	}
+	cmd := g.command(args, nil)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := util.Run(cmd, util.GitTimeout)
 	if err != nil {
 		return "", g.wrapError(err, stdout.String(), stderr.String(), args)
 	}
@@ -112,17 +129,14 @@ func (g *Git) runWithEnv(args []string, extraEnv []string) (string, error) {
 	if g.gitDir != "" {
 		args = append([]string{"--git-dir=" + g.gitDir}, args...)
 	}
-	cmd := exec.Command("git", args...)
-	if g.workDir != "" {
-		cmd.Dir = g.workDir
-	}
-	if len(extraEnv) > 0 {
+	cmd := g.command(args, extraEnv)
+	if len(extraEnv) > 0 && !g.IsRemote() {
 		cmd.Env = append(os.Environ(), extraEnv...)
 	}
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	err := cmd.Run()
+	err := util.Run(cmd, util.GitTimeout)
 	if err != nil {
 		return "", g.wrapError(err, stdout.String(), stderr.String(), args)
 	}
@@ -173,18 +187,19 @@ func (g *Git) Clone(url, dest string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", url, tmpDest)
+	var stdout, stderr bytes.Buffer
+	args, stderrW := g.withProgress([]string{"clone", url, tmpDest}, &stderr)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
-	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	cmd.Stderr = stderrW
+	if err := util.Run(cmd, util.TransferTimeout); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", url})
 	}
 
 	// Move to final destination (handles cross-filesystem moves)
-	if err := moveDir(tmpDest, dest); err != nil {
+	if err := moveDir(tmpDest, dest, g.progress); err != nil {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
@@ -192,8 +207,8 @@ func (g *Git) Clone(url, dest string) error {
 	if err := configureHooksPath(dest); err != nil {
 		return err
 	}
-	// Initialize submodules if present
-	return InitSubmodules(dest)
+	// Initialize submodules and LFS per the checkout options
+	return g.initClone(dest)
 }
 
 // CloneWithReference clones a repository using a local repo as an object reference.
@@ -217,18 +232,19 @@ func (g *Git) CloneWithReference(url, dest, reference string) error {
 	if runtime.GOOS == "windows" {
 		args = append([]string{"-c", "core.symlinks=true"}, args...)
 	}
-	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	runArgs, stderrW := g.withProgress(args, &stderr)
+	cmd := exec.Command("git", runArgs...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
-	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	cmd.Stderr = stderrW
+	if err := util.Run(cmd, util.TransferTimeout); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), args)
 	}
 
 	// Move to final destination (handles cross-filesystem moves)
-	if err := moveDir(tmpDest, dest); err != nil {
+	if err := moveDir(tmpDest, dest, g.progress); err != nil {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
@@ -236,8 +252,8 @@ func (g *Git) CloneWithReference(url, dest, reference string) error {
 	if err := configureHooksPath(dest); err != nil {
 		return err
 	}
-	// Initialize submodules if present
-	return InitSubmodules(dest)
+	// Initialize submodules and LFS per the checkout options
+	return g.initClone(dest)
 }
 
 // CloneBare clones a repository as a bare repo (no working directory).
@@ -257,23 +273,31 @@ func (g *Git) CloneBare(url, dest string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", "--bare", url, tmpDest)
+	var stdout, stderr bytes.Buffer
+	args, stderrW := g.withProgress([]string{"clone", "--bare", url, tmpDest}, &stderr)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
-	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	cmd.Stderr = stderrW
+	if err := util.Run(cmd, util.TransferTimeout); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--bare", url})
 	}
 
 	// Move to final destination (handles cross-filesystem moves)
-	if err := moveDir(tmpDest, dest); err != nil {
+	if err := moveDir(tmpDest, dest, g.progress); err != nil {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
 	// Configure refspec so worktrees can fetch and see origin/* refs
-	return configureRefspec(dest)
+	if err := configureRefspec(dest); err != nil {
+		return err
+	}
+	// Record checkout options for the worktrees made from it
+	if g.checkout != nil {
+		return ApplyCheckoutOptions(dest, *g.checkout)
+	}
+	return nil
 }
 
 // configureHooksPath sets core.hooksPath to use the repo's .githooks directory
@@ -289,7 +313,7 @@ func configureHooksPath(repoPath string) error {
 	cmd := exec.Command("git", "-C", repoPath, "config", "core.hooksPath", ".githooks")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := util.Run(cmd, util.GitTimeout); err != nil {
 		return fmt.Errorf("configuring hooks path: %s", strings.TrimSpace(stderr.String()))
 	}
 	return nil
@@ -315,13 +339,13 @@ func configureRefspec(repoPath string) error {
 	var stderr bytes.Buffer
 	configCmd := exec.Command("git", "--git-dir", gitDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
 	configCmd.Stderr = &stderr
-	if err := configCmd.Run(); err != nil {
+	if err := util.Run(configCmd, util.GitTimeout); err != nil {
 		return fmt.Errorf("configuring refspec: %s", strings.TrimSpace(stderr.String()))
 	}
 
 	fetchCmd := exec.Command("git", "--git-dir", gitDir, "fetch", "origin")
 	fetchCmd.Stderr = &stderr
-	if err := fetchCmd.Run(); err != nil {
+	if err := util.Run(fetchCmd, util.GitTimeout); err != nil {
 		return fmt.Errorf("fetching origin: %s", strings.TrimSpace(stderr.String()))
 	}
 
@@ -344,23 +368,31 @@ func (g *Git) CloneBareWithReference(url, dest, reference string) error {
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	tmpDest := filepath.Join(tmpDir, filepath.Base(dest))
-	cmd := exec.Command("git", "clone", "--bare", "--reference-if-able", reference, url, tmpDest)
+	var stdout, stderr bytes.Buffer
+	args, stderrW := g.withProgress([]string{"clone", "--bare", "--reference-if-able", reference, url, tmpDest}, &stderr)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = tmpDir
 	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+tmpDir)
-	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	cmd.Stderr = stderrW
+	if err := util.Run(cmd, util.TransferTimeout); err != nil {
 		return g.wrapError(err, stdout.String(), stderr.String(), []string{"clone", "--bare", "--reference-if-able", url})
 	}
 
 	// Move to final destination (handles cross-filesystem moves)
-	if err := moveDir(tmpDest, dest); err != nil {
+	if err := moveDir(tmpDest, dest, g.progress); err != nil {
 		return fmt.Errorf("moving clone to destination: %w", err)
 	}
 
 	// Configure refspec so worktrees can fetch and see origin/* refs
-	return configureRefspec(dest)
+	if err := configureRefspec(dest); err != nil {
+		return err
+	}
+	// Record checkout options for the worktrees made from it
+	if g.checkout != nil {
+		return ApplyCheckoutOptions(dest, *g.checkout)
+	}
+	return nil
 }
 
 // Checkout checks out the given ref.
@@ -455,6 +487,14 @@ func (g *Git) PushWithEnv(remote, branch string, force bool, env []string) error
 	return err
 }
 
+// PushWithLease force-pushes refspec to remote only if the remote branch is
+// still at expect (--force-with-lease), so commits pushed by someone else
+// since it was fetched are never overwritten.
+func (g *Git) PushWithLease(remote, refspec, branch, expect string) error {
+	_, err := g.run("push", "--force-with-lease=refs/heads/"+branch+":"+expect, remote, refspec)
+	return err
+}
+
 // Add stages files for commit.
 func (g *Git) Add(paths ...string) error {
 	args := append([]string{"add"}, paths...)
@@ -566,6 +606,35 @@ func (g *Git) RemoteDefaultBranch() string {
 	return "main" // final fallback
 }
 
+// QueryRemoteDefaultBranch asks the remote which branch its HEAD points to.
+// Unlike RemoteDefaultBranch, which reads the local origin/HEAD (set at clone
+// time and never moved by fetch), this sees upstream renames such as
+// master -> main.
+func (g *Git) QueryRemoteDefaultBranch(remote string) (string, error) {
+	out, err := g.run("ls-remote", "--symref", remote, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	// ls-remote --symref output: "ref: refs/heads/main\tHEAD" then "<sha>\tHEAD"
+	for _, line := range strings.Split(out, "\n") {
+		ref, ok := strings.CutPrefix(strings.TrimSpace(line), "ref: ")
+		if !ok {
+			continue
+		}
+		if target, _, _ := strings.Cut(ref, "\t"); strings.HasPrefix(target, "refs/heads/") {
+			return strings.TrimPrefix(target, "refs/heads/"), nil
+		}
+	}
+	return "", fmt.Errorf("remote %s did not report a HEAD branch", remote)
+}
+
+// SetRemoteHEAD points refs/remotes/<remote>/HEAD at branch, which must
+// already be fetched.
+func (g *Git) SetRemoteHEAD(remote, branch string) error {
+	_, err := g.run("remote", "set-head", remote, branch)
+	return err
+}
+
 // HasUncommittedChanges returns true if there are uncommitted changes.
 func (g *Git) HasUncommittedChanges() (bool, error) {
 	status, err := g.Status()
@@ -619,6 +688,13 @@ func (g *Git) Merge(branch string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to the given ref, failing
+// if the branch has commits the ref lacks.
+func (g *Git) MergeFFOnly(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	return err
+}
+
 // MergeNoFF merges the given branch with --no-ff flag and a custom message.
 func (g *Git) MergeNoFF(branch, message string) error {
 	_, err := g.run("merge", "--no-ff", "-m", message, branch)
@@ -678,6 +754,23 @@ func (g *Git) ListRemoteRefs(remote, prefix string) ([]string, error) {
 	return refs, nil
 }
 
+// ListRemoteHeads is like ListRemoteRefs but also returns each ref's commit,
+// as a map from full ref name to SHA.
+func (g *Git) ListRemoteHeads(remote, prefix string) (map[string]string, error) {
+	out, err := g.run("ls-remote", "--refs", remote, prefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	heads := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) >= 2 {
+			heads[parts[1]] = parts[0]
+		}
+	}
+	return heads, nil
+}
+
 // Rebase rebases the current branch onto the given ref.
 func (g *Git) Rebase(onto string) error {
 	_, err := g.run("rebase", onto)
@@ -730,14 +823,13 @@ func (g *Git) CheckConflicts(source, target string) ([]string, error) {
 // runMergeCheck runs a git merge command and returns error info from both stdout and stderr.
 // ZFC: Returns GitError with raw output for agent observation.
 func (g *Git) runMergeCheck(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = g.workDir
+	cmd := g.command(args, nil)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := util.Run(cmd, util.GitTimeout)
 	if err != nil {
 		// ZFC: Return raw output for observation, don't interpret CONFLICT
 		return "", g.wrapError(err, stdout.String(), stderr.String(), args)
@@ -919,6 +1011,103 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// LatestTag returns the nearest tag reachable from ref whose name matches
+// the glob pattern, or "" if there is none.
+func (g *Git) LatestTag(pattern, ref string) (string, error) {
+	out, err := g.run("describe", "--tags", "--abbrev=0", "--match", pattern, ref)
+	if err != nil {
+		var gitErr *GitError
+		if errors.As(err, &gitErr) && (strings.Contains(gitErr.Stderr, "No names found") ||
+			strings.Contains(gitErr.Stderr, "No tags can describe") ||
+			strings.Contains(gitErr.Stderr, "cannot describe")) {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// CreateAnnotatedTag creates an annotated tag at ref.
+func (g *Git) CreateAnnotatedTag(name, ref, message string) error {
+	_, err := g.run("tag", "-a", name, "-m", message, ref)
+	return err
+}
+
+// LogCommit is one commit in a log listing.
+type LogCommit struct {
+	SHA     string
+	Subject string
+	Body    string // message after the subject, including any trailers
+	Notes   string // notes attached under the ref passed to LogWithNotes
+}
+
+// Log lists the commits in revRange (e.g., "v1.2.0..origin/main"), newest
+// first, following only first parents so each merged MR counts once.
+func (g *Git) Log(revRange string) ([]LogCommit, error) {
+	return g.LogWithNotes(revRange, "")
+}
+
+// LogWithNotes is Log that also reads each commit's notes from notesRef
+// (e.g., "refs/notes/beads"). A missing notes ref yields empty notes.
+func (g *Git) LogWithNotes(revRange, notesRef string) ([]LogCommit, error) {
+	// Fields are separated by US and records by RS so bodies can hold newlines.
+	args := []string{"log", "--first-parent", "--format=%H%x1f%s%x1f%b%x1e"}
+	if notesRef != "" {
+		args = []string{"log", "--first-parent", "--notes=" + notesRef, "--format=%H%x1f%s%x1f%b%x1f%N%x1e"}
+	}
+	out, err := g.run(append(args, revRange)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogCommits(out), nil
+}
+
+// RecentCommits returns up to n commits reachable from HEAD, newest first.
+// With grep set it instead searches every ref for commits whose message
+// mentions grep, so a bead ID also finds work on unmerged branches.
+func (g *Git) RecentCommits(n int, grep string) ([]LogCommit, error) {
+	args := []string{"log", "-n", strconv.Itoa(n), "--format=%H%x1f%s%x1f%b%x1e"}
+	if grep != "" {
+		args = append(args, "--all", "--fixed-strings", "--grep="+grep)
+	}
+	out, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLogCommits(out), nil
+}
+
+// parseLogCommits parses log output in the format written by LogWithNotes.
+func parseLogCommits(out string) []LogCommit {
+	var commits []LogCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 4)
+		c := LogCommit{SHA: fields[0]}
+		if len(fields) > 1 {
+			c.Subject = fields[1]
+		}
+		if len(fields) > 2 {
+			c.Body = strings.TrimSpace(fields[2])
+		}
+		if len(fields) > 3 {
+			c.Notes = strings.TrimSpace(fields[3])
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// AddNote attaches message to commit under notesRef, appending to any note
+// already there.
+func (g *Git) AddNote(notesRef, commit, message string) error {
+	_, err := g.run("notes", "--ref="+notesRef, "append", "-m", message, commit)
+	return err
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -938,7 +1127,7 @@ func (g *Git) WorktreeAdd(path, branch string) error {
 	if _, err := g.run("worktree", "add", "-b", branch, path); err != nil {
 		return err
 	}
-	return InitSubmodules(path)
+	return InitCheckout(path)
 }
 
 // WorktreeAddFromRef creates a new worktree at the given path with a new branch
@@ -947,7 +1136,7 @@ func (g *Git) WorktreeAddFromRef(path, branch, startPoint string) error {
 	if _, err := g.run("worktree", "add", "-b", branch, path, startPoint); err != nil {
 		return err
 	}
-	return InitSubmodules(path)
+	return InitCheckout(path)
 }
 
 // WorktreeAddDetached creates a new worktree at the given path with a detached HEAD.
@@ -955,7 +1144,7 @@ func (g *Git) WorktreeAddDetached(path, ref string) error {
 	if _, err := g.run("worktree", "add", "--detach", path, ref); err != nil {
 		return err
 	}
-	return InitSubmodules(path)
+	return InitCheckout(path)
 }
 
 // WorktreeAddExisting creates a new worktree at the given path for an existing branch.
@@ -963,7 +1152,7 @@ func (g *Git) WorktreeAddExisting(path, branch string) error {
 	if _, err := g.run("worktree", "add", path, branch); err != nil {
 		return err
 	}
-	return InitSubmodules(path)
+	return InitCheckout(path)
 }
 
 // WorktreeAddExistingForce creates a new worktree even if the branch is already checked out elsewhere.
@@ -972,14 +1161,14 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 	if _, err := g.run("worktree", "add", "--force", path, branch); err != nil {
 		return err
 	}
-	return InitSubmodules(path)
+	return InitCheckout(path)
 }
 
 // IsSparseCheckoutConfigured checks if sparse checkout is enabled for a given repo/worktree.
 // This is used by doctor to detect legacy sparse checkout configurations that should be removed.
 func IsSparseCheckoutConfigured(repoPath string) bool {
 	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckout")
-	output, err := cmd.Output()
+	output, err := util.Output(cmd, util.GitTimeout)
 	return err == nil && strings.TrimSpace(string(output)) == "true"
 }
 
@@ -990,7 +1179,7 @@ func RemoveSparseCheckout(repoPath string) error {
 	cmd := exec.Command("git", "-C", repoPath, "sparse-checkout", "disable")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := util.Run(cmd, util.GitTimeout); err != nil {
 		return fmt.Errorf("disabling sparse checkout: %s", strings.TrimSpace(stderr.String()))
 	}
 	return nil
@@ -1006,6 +1195,12 @@ func (g *Git) WorktreeRemove(path string, force bool) error {
 	return err
 }
 
+// WorktreeRepair re-links worktrees that were moved by hand at paths.
+func (g *Git) WorktreeRepair(paths ...string) error {
+	_, err := g.run(append([]string{"worktree", "repair"}, paths...)...)
+	return err
+}
+
 // WorktreePrune removes worktree entries for deleted paths.
 func (g *Git) WorktreePrune() error {
 	_, err := g.run("worktree", "prune")
@@ -1112,6 +1307,22 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// ChangedFiles returns the paths branch changes relative to its merge base
+// with base, i.e. what merging branch into base would touch.
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -1130,6 +1341,28 @@ func (g *Git) CountCommitsBehind(ref string) (int, error) {
 	return count, nil
 }
 
+// AheadBehind returns how many commits HEAD is ahead of and behind ref
+// (e.g., "@{upstream}" or "origin/main").
+func (g *Git) AheadBehind(ref string) (ahead, behind int, err error) {
+	out, err := g.run("rev-list", "--left-right", "--count", ref+"...HEAD")
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(out, "%d %d", &behind, &ahead); err != nil {
+		return 0, 0, fmt.Errorf("parsing commit counts: %w", err)
+	}
+	return ahead, behind, nil
+}
+
+// LastCommitTime returns the committer date of HEAD.
+func (g *Git) LastCommitTime() (time.Time, error) {
+	out, err := g.run("log", "-1", "--format=%cI")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, out)
+}
+
 // StashCount returns the number of stashes belonging to the current branch.
 // Git stashes are stored in the main repo (.git/refs/stash) and shared across
 // all worktrees. Counting all stashes is incorrect for worktree-based polecats:
@@ -1176,6 +1409,11 @@ func (g *Git) StashCount() (int, error) {
 	return count, nil
 }
 
+// Upstream returns the current branch's upstream (e.g., "origin/main").
+func (g *Git) Upstream() (string, error) {
+	return g.run("rev-parse", "--abbrev-ref", "@{u}")
+}
+
 // UnpushedCommits returns the number of commits that are not pushed to the remote.
 // It checks if the current branch has an upstream and counts commits ahead.
 // Returns 0 if there is no upstream configured.
@@ -1479,7 +1717,7 @@ func InitSubmodules(repoPath string) error {
 	cmd := exec.Command("git", "-C", repoPath, "submodule", "update", "--init", "--recursive")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := util.Run(cmd, util.GitTimeout); err != nil {
 		return fmt.Errorf("initializing submodules: %s", strings.TrimSpace(stderr.String()))
 	}
 	return nil
@@ -1568,7 +1806,7 @@ func (g *Git) submoduleURL(ref, submodulePath string) (string, error) {
 	cmd := exec.Command("git", "config", "-f", tmpFile.Name(), "--get-regexp", `^submodule\..*\.path$`)
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
-	if err := cmd.Run(); err != nil {
+	if err := util.Run(cmd, util.GitTimeout); err != nil {
 		return "", fmt.Errorf("reading submodule paths from .gitmodules: %w", err)
 	}
 
@@ -1596,7 +1834,7 @@ func (g *Git) submoduleURL(ref, submodulePath string) (string, error) {
 	urlCmd := exec.Command("git", "config", "-f", tmpFile.Name(), "--get", "submodule."+sectionName+".url")
 	var urlOut bytes.Buffer
 	urlCmd.Stdout = &urlOut
-	if err := urlCmd.Run(); err != nil {
+	if err := util.Run(urlCmd, util.GitTimeout); err != nil {
 		return "", fmt.Errorf("reading URL for submodule %s: %w", sectionName, err)
 	}
 	url := strings.TrimSpace(urlOut.String())
@@ -1619,7 +1857,7 @@ func (g *Git) PushSubmoduleCommit(submodulePath, sha, remote string) error {
 	cmd := exec.Command("git", "-C", absPath, "push", remote, sha+":refs/heads/"+defaultBranch)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := util.Run(cmd, util.GitTimeout); err != nil {
 		return fmt.Errorf("pushing submodule %s commit %s: %s", submodulePath, sha[:8], strings.TrimSpace(stderr.String()))
 	}
 	return nil
@@ -1630,7 +1868,7 @@ func (g *Git) PushSubmoduleCommit(submodulePath, sha, remote string) error {
 func submoduleDefaultBranch(submodulePath, remote string) (string, error) {
 	// Try local symbolic-ref first (no network, fastest)
 	symCmd := exec.Command("git", "-C", submodulePath, "symbolic-ref", "refs/remotes/"+remote+"/HEAD")
-	if symOut, err := symCmd.Output(); err == nil {
+	if symOut, err := util.Output(symCmd, util.GitTimeout); err == nil {
 		ref := strings.TrimSpace(string(symOut))
 		// refs/remotes/origin/HEAD -> refs/remotes/origin/main -> main
 		if parts := strings.Split(ref, "/"); len(parts) > 0 {
@@ -1644,7 +1882,7 @@ func submoduleDefaultBranch(submodulePath, remote string) (string, error) {
 	// Try local tracking refs (no network)
 	for _, candidate := range []string{"main", "master"} {
 		check := exec.Command("git", "-C", submodulePath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+remote+"/"+candidate)
-		if check.Run() == nil {
+		if util.Run(check, util.GitTimeout) == nil {
 			return candidate, nil
 		}
 	}
@@ -1652,7 +1890,7 @@ func submoduleDefaultBranch(submodulePath, remote string) (string, error) {
 	// Fallback: network query via ls-remote
 	for _, candidate := range []string{"main", "master"} {
 		check := exec.Command("git", "-C", submodulePath, "ls-remote", "--exit-code", remote, "refs/heads/"+candidate)
-		if check.Run() == nil {
+		if util.Run(check, util.GitTimeout) == nil {
 			return candidate, nil
 		}
 	}
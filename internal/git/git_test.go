@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 func initTestRepo(t *testing.T) string {
@@ -1554,3 +1557,140 @@ func TestClearPushURL(t *testing.T) {
 		t.Errorf("ClearPushURL (idempotent) should not error, got: %v", err)
 	}
 }
+
+func TestQueryRemoteDefaultBranch_FollowsRename(t *testing.T) {
+	localDir, remoteDir, mainBranch := initTestRepoWithRemote(t)
+	g := NewGit(localDir)
+
+	got, err := g.QueryRemoteDefaultBranch("origin")
+	if err != nil {
+		t.Fatalf("QueryRemoteDefaultBranch: %v", err)
+	}
+	if got != mainBranch {
+		t.Errorf("QueryRemoteDefaultBranch = %q, want %q", got, mainBranch)
+	}
+
+	// Upstream renames its default branch.
+	runGit(t, localDir, "push", "origin", mainBranch+":refs/heads/trunk")
+	runGit(t, remoteDir, "symbolic-ref", "HEAD", "refs/heads/trunk")
+
+	got, err = g.QueryRemoteDefaultBranch("origin")
+	if err != nil {
+		t.Fatalf("QueryRemoteDefaultBranch after rename: %v", err)
+	}
+	if got != "trunk" {
+		t.Errorf("QueryRemoteDefaultBranch after rename = %q, want trunk", got)
+	}
+
+	runGit(t, localDir, "fetch", "origin")
+	if err := g.SetRemoteHEAD("origin", "trunk"); err != nil {
+		t.Fatalf("SetRemoteHEAD: %v", err)
+	}
+	if got := g.RemoteDefaultBranch(); got != "trunk" {
+		t.Errorf("RemoteDefaultBranch after SetRemoteHEAD = %q, want trunk", got)
+	}
+}
+
+func TestTagsAndLog(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	tag, err := g.LatestTag("v*", "HEAD")
+	if err != nil || tag != "" {
+		t.Fatalf("LatestTag with no tags = %q, %v; want empty", tag, err)
+	}
+
+	if err := g.CreateAnnotatedTag("v0.1.0", "HEAD", "Release v0.1.0"); err != nil {
+		t.Fatalf("CreateAnnotatedTag: %v", err)
+	}
+	for _, msg := range []string{"add feature", "fix bug\n\nFixes: gt-abc"} {
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add("f.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Commit(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tag, err = g.LatestTag("v*", "HEAD")
+	if err != nil || tag != "v0.1.0" {
+		t.Fatalf("LatestTag = %q, %v; want v0.1.0", tag, err)
+	}
+	if tag, _ := g.LatestTag("release-*", "HEAD"); tag != "" {
+		t.Errorf("LatestTag with non-matching pattern = %q, want empty", tag)
+	}
+
+	commits, err := g.Log("v0.1.0..HEAD")
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "fix bug" || commits[1].Subject != "add feature" {
+		t.Fatalf("Log = %+v, want [fix bug, add feature]", commits)
+	}
+	if commits[0].Body != "Fixes: gt-abc" || commits[1].Body != "" {
+		t.Errorf("bodies = %q, %q; want trailer on the fix only", commits[0].Body, commits[1].Body)
+	}
+	head, _ := g.Rev("HEAD")
+	if commits[0].SHA != head {
+		t.Errorf("newest commit SHA = %s, want HEAD %s", commits[0].SHA, head)
+	}
+
+	if commits, _ := g.LogWithNotes("v0.1.0..HEAD", "refs/notes/beads"); len(commits) != 2 || commits[1].Notes != "" {
+		t.Fatalf("LogWithNotes with no notes = %+v", commits)
+	}
+	if err := g.AddNote("refs/notes/beads", commits[1].SHA, "Bead: gt-def"); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	noted, err := g.LogWithNotes("v0.1.0..HEAD", "refs/notes/beads")
+	if err != nil {
+		t.Fatalf("LogWithNotes: %v", err)
+	}
+	if noted[1].Notes != "Bead: gt-def" || noted[0].Notes != "" {
+		t.Errorf("notes = %q, %q; want note on the feature only", noted[0].Notes, noted[1].Notes)
+	}
+	if plain, _ := g.Log("v0.1.0..HEAD"); plain[1].Notes != "" {
+		t.Errorf("Log read notes: %q", plain[1].Notes)
+	}
+}
+
+func TestGitErrorReportsTimeout(t *testing.T) {
+	err := &GitError{
+		Command: "fetch",
+		Stderr:  "remote: Counting objects: 12% (1200/10000)",
+		Err:     &util.TimeoutError{Command: "git fetch origin", Timeout: time.Minute},
+	}
+	if got := err.Error(); !strings.Contains(got, "git fetch origin timed out after 1m0s") {
+		t.Errorf("Error() = %q, want the timeout, not partial stderr", got)
+	}
+	if !util.IsTimeout(err) {
+		t.Error("IsTimeout(GitError) = false")
+	}
+}
+
+func TestAheadBehind(t *testing.T) {
+	localDir, _, mainBranch := initTestRepoWithRemote(t)
+	g := NewGit(localDir)
+
+	upstream, err := g.Upstream()
+	if err != nil || upstream != "origin/"+mainBranch {
+		t.Fatalf("Upstream() = %q, %v", upstream, err)
+	}
+	for _, msg := range []string{"one", "two"} {
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", msg)
+		cmd.Dir = localDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+	}
+
+	ahead, behind, err := g.AheadBehind(upstream)
+	if err != nil || ahead != 2 || behind != 0 {
+		t.Errorf("AheadBehind() = %d, %d, %v; want 2, 0", ahead, behind, err)
+	}
+	if last, err := g.LastCommitTime(); err != nil || time.Since(last) > time.Hour {
+		t.Errorf("LastCommitTime() = %v, %v", last, err)
+	}
+}
@@ -0,0 +1,155 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Commit signing formats, matching config.SigningSSH and SigningOpenPGP.
+const (
+	SigningSSH     = "ssh"
+	SigningOpenPGP = "openpgp"
+)
+
+// SigningKeyAgent selects the first key in the SSH agent.
+const SigningKeyAgent = "agent"
+
+// ErrNoAgentKey is returned when signing uses the SSH agent but it holds no key.
+var ErrNoAgentKey = errors.New("no key in the SSH agent (is SSH_AUTH_SOCK forwarded?)")
+
+// SetIdentity sets user.name, user.email and user.signingkey (each only
+// if non-empty) in the repo's config. With worktree set they go to this
+// worktree's own config instead of the config shared by every worktree of
+// the repo, enabling per-worktree config first.
+func (g *Git) SetIdentity(name, email, signingKey string, worktree bool) error {
+	return g.setConfig(worktree, [][2]string{{"user.name", name}, {"user.email", email}, {"user.signingkey", signingKey}})
+}
+
+// ConfigureSigning makes the repo sign every commit with key, a value
+// from ResolveSigningKey. allowedSigners, if set, is the ssh allowed
+// signers file signatures are verified against. worktree is as for
+// SetIdentity.
+func (g *Git) ConfigureSigning(format, key, allowedSigners string, worktree bool) error {
+	return g.setConfig(worktree, [][2]string{
+		{"gpg.format", format},
+		{"user.signingkey", key},
+		{"commit.gpgsign", "true"},
+		{"gpg.ssh.allowedSignersFile", allowedSigners},
+	})
+}
+
+// setConfig sets each non-empty key/value pair, in the worktree's own
+// config when worktree is set.
+func (g *Git) setConfig(worktree bool, pairs [][2]string) error {
+	if worktree {
+		if err := g.EnableWorktreeConfig(); err != nil {
+			return err
+		}
+	}
+	for _, kv := range pairs {
+		if kv[1] == "" {
+			continue
+		}
+		args := []string{"config"}
+		if worktree {
+			args = append(args, "--worktree")
+		}
+		if _, err := g.run(append(args, kv[0], kv[1])...); err != nil {
+			return fmt.Errorf("setting %s: %w", kv[0], err)
+		}
+	}
+	return nil
+}
+
+// ResolveSigningKey turns a configured signing key into a user.signingkey
+// value. For ssh, "agent" becomes the first public key the SSH agent
+// holds and a key file path has "~/" expanded and must exist; openpgp key
+// IDs are used as is.
+func ResolveSigningKey(format, key string) (string, error) {
+	if format == SigningOpenPGP {
+		return key, nil
+	}
+	if key == SigningKeyAgent {
+		out, err := util.Output(exec.Command("ssh-add", "-L"), util.GitTimeout)
+		if err != nil {
+			return "", ErrNoAgentKey
+		}
+		first, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		if first == "" {
+			return "", ErrNoAgentKey
+		}
+		return "key::" + first, nil
+	}
+	if strings.HasPrefix(key, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding %s: %w", key, err)
+		}
+		key = filepath.Join(home, key[2:])
+	}
+	if _, err := os.Stat(key); err != nil {
+		return "", fmt.Errorf("signing key: %w", err)
+	}
+	return key, nil
+}
+
+// UnverifiedCommits returns the commits on branch not on base that lack a
+// good signature, each as "<short sha> (<status>)" with git's %G? status:
+// N unsigned, B bad, E unverifiable (e.g. no allowed signers file), X/Y/R
+// expired or revoked.
+func (g *Git) UnverifiedCommits(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--format=%h %G?", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	var bad []string
+	for _, line := range strings.Split(out, "\n") {
+		sha, status, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || status == "G" || status == "U" {
+			continue
+		}
+		bad = append(bad, fmt.Sprintf("%s (%s)", sha, status))
+	}
+	return bad, nil
+}
+
+// EnableWorktreeConfig turns on extensions.worktreeConfig for the repo so
+// each worktree can have its own config.worktree. For a bare repo,
+// core.bare moves to the bare repo's own config.worktree: left in the
+// shared config, every linked worktree would read it and stop working.
+func (g *Git) EnableWorktreeConfig() error {
+	common, err := g.run("rev-parse", "--git-common-dir")
+	if err != nil {
+		return fmt.Errorf("finding common git dir: %w", err)
+	}
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(g.workDir, common)
+	}
+	shared := NewGitWithDir(common, "")
+	if v, _ := shared.run("config", "--bool", "--get", "extensions.worktreeConfig"); v == "true" {
+		return nil
+	}
+
+	bare, _ := shared.run("config", "--file", filepath.Join(common, "config"), "--bool", "--get", "core.bare")
+	if _, err := shared.run("config", "core.repositoryformatversion", "1"); err != nil {
+		return fmt.Errorf("enabling worktree config: %w", err)
+	}
+	if _, err := shared.run("config", "extensions.worktreeConfig", "true"); err != nil {
+		return fmt.Errorf("enabling worktree config: %w", err)
+	}
+	if strings.TrimSpace(bare) == "true" {
+		if _, err := shared.run("config", "--worktree", "core.bare", "true"); err != nil {
+			return fmt.Errorf("moving core.bare to worktree config: %w", err)
+		}
+		if _, err := shared.run("config", "--file", filepath.Join(common, "config"), "--unset", "core.bare"); err != nil {
+			return fmt.Errorf("moving core.bare to worktree config: %w", err)
+		}
+	}
+	return nil
+}
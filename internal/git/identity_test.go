@@ -0,0 +1,94 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetIdentity_Worktree(t *testing.T) {
+	src := initTestRepo(t)
+	bare := filepath.Join(t.TempDir(), "repo.git")
+	if out, err := exec.Command("git", "clone", "--bare", src, bare).CombinedOutput(); err != nil {
+		t.Fatalf("clone --bare: %v\n%s", err, out)
+	}
+	bareGit := NewGitWithDir(bare, "")
+	wt1 := filepath.Join(t.TempDir(), "wt1")
+	wt2 := filepath.Join(t.TempDir(), "wt2")
+	for i, wt := range []string{wt1, wt2} {
+		if err := bareGit.WorktreeAddFromRef(wt, "b"+string(rune('1'+i)), "HEAD"); err != nil {
+			t.Fatalf("worktree add: %v", err)
+		}
+	}
+
+	if err := NewGit(wt1).SetIdentity("Gastown Polecat", "polecats@rig", "", true); err != nil {
+		t.Fatalf("SetIdentity: %v", err)
+	}
+	if got := gitConfigGet(wt1, "user.email"); got != "polecats@rig" {
+		t.Errorf("wt1 user.email = %q", got)
+	}
+	if got := gitConfigGet(wt2, "user.email"); got == "polecats@rig" {
+		t.Error("identity leaked into another worktree")
+	}
+
+	// Worktrees must still work after core.bare moved out of the shared config.
+	for _, wt := range []string{wt1, wt2} {
+		if _, err := NewGit(wt).Status(); err != nil {
+			t.Errorf("status in %s: %v", wt, err)
+		}
+	}
+	if out, _ := exec.Command("git", "--git-dir="+bare, "rev-parse", "--is-bare-repository").Output(); strings.TrimSpace(string(out)) != "true" {
+		t.Errorf("bare repo no longer bare: %q", out)
+	}
+}
+
+func TestConfigureSigning_UnverifiedCommits(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	dir := initTestRepo(t)
+	keyDir := t.TempDir()
+	key := filepath.Join(keyDir, "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, out)
+	}
+	pub, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := filepath.Join(keyDir, "allowed_signers")
+	if err := os.WriteFile(allowed, []byte("test@test.com "+string(pub)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ResolveSigningKey(SigningSSH, filepath.Join(keyDir, "missing")); err == nil {
+		t.Error("ResolveSigningKey accepted a missing key file")
+	}
+	resolved, err := ResolveSigningKey(SigningSSH, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGit(dir)
+	if err := g.ConfigureSigning(SigningSSH, resolved, allowed, false); err != nil {
+		t.Fatalf("ConfigureSigning: %v", err)
+	}
+	base, _ := g.Rev("HEAD")
+
+	commit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append(append([]string{"-C", dir}, args...), "commit", "--allow-empty", "-q", "-m", "c")...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("commit: %v\n%s", err, out)
+		}
+	}
+	commit()
+	if bad, err := g.UnverifiedCommits(base, "HEAD"); err != nil || len(bad) != 0 {
+		t.Fatalf("signed commit: unverified %v, err %v", bad, err)
+	}
+	commit("-c", "commit.gpgsign=false")
+	if bad, err := g.UnverifiedCommits(base, "HEAD"); err != nil || len(bad) != 1 || !strings.HasSuffix(bad[0], "(N)") {
+		t.Errorf("unsigned commit: unverified %v, err %v", bad, err)
+	}
+}
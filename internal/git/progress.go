@@ -0,0 +1,77 @@
+package git
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/progress"
+)
+
+// SetProgress makes clones run by g report to bar: git's own --progress
+// phases, then the copy into place when the clone has to cross
+// filesystems. Pass nil to turn reporting off.
+func (g *Git) SetProgress(bar *progress.Bar) {
+	g.progress = bar
+}
+
+// withProgress adds --progress to clone args and returns the stderr
+// writer to use when g has a progress bar; otherwise both pass through.
+func (g *Git) withProgress(args []string, stderr *bytes.Buffer) ([]string, io.Writer) {
+	if g.progress == nil {
+		return args, stderr
+	}
+	for i, arg := range args {
+		if arg == "clone" {
+			args = append(args[:i+1:i+1], append([]string{"--progress"}, args[i+1:]...)...)
+			break
+		}
+	}
+	return args, &progressWriter{bar: g.progress, stderr: stderr}
+}
+
+// gitProgressRE matches git progress lines such as
+// "Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s".
+var gitProgressRE = regexp.MustCompile(`^(?:remote: )?([A-Za-z ]+):\s+\d+% \((\d+)/(\d+)\)`)
+
+// progressWriter feeds git's progress output to a Bar. Progress lines are
+// separated by \r; everything else is kept in stderr so errors read the
+// same as without progress.
+type progressWriter struct {
+	bar    *progress.Bar
+	stderr *bytes.Buffer
+	phase  string
+	buf    []byte
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.line(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) line(line []byte) {
+	m := gitProgressRE.FindSubmatch(line)
+	if m == nil {
+		if len(line) > 0 {
+			w.stderr.Write(line)
+			w.stderr.WriteByte('\n')
+		}
+		return
+	}
+	done, _ := strconv.ParseInt(string(m[2]), 10, 64)
+	total, _ := strconv.ParseInt(string(m[3]), 10, 64)
+	if phase := string(m[1]); phase != w.phase {
+		w.phase = phase
+		w.bar.Phase(phase, total, progress.Count)
+	}
+	w.bar.Set(done)
+}
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/progress"
+)
+
+func TestWithProgress_AddsFlagAfterClone(t *testing.T) {
+	g := NewGit("")
+	var stderr bytes.Buffer
+
+	args, w := g.withProgress([]string{"-c", "core.symlinks=true", "clone", "url", "dest"}, &stderr)
+	if strings.Join(args, " ") != "-c core.symlinks=true clone url dest" || w != &stderr {
+		t.Errorf("without a bar: args = %v, want unchanged", args)
+	}
+
+	g.SetProgress(progress.New(&bytes.Buffer{}, "Cloning", 0, progress.Count))
+	args, w = g.withProgress([]string{"-c", "core.symlinks=true", "clone", "url", "dest"}, &stderr)
+	if got := strings.Join(args, " "); got != "-c core.symlinks=true clone --progress url dest" {
+		t.Errorf("args = %q", got)
+	}
+	if _, ok := w.(*progressWriter); !ok {
+		t.Errorf("stderr writer = %T, want *progressWriter", w)
+	}
+}
+
+func TestProgressWriter_KeepsNonProgressOutput(t *testing.T) {
+	var stderr bytes.Buffer
+	w := &progressWriter{bar: progress.New(&bytes.Buffer{}, "Cloning", 0, progress.Count), stderr: &stderr}
+
+	chunks := []string{
+		"Cloning into 'dest'...\n",
+		"remote: Counting objects:  50% (5/10)\rremote: Counting obj",
+		"ects: 100% (10/10), done.\n",
+		"Receiving objects:  30% (3/10)\rReceiving objects: 100% (10/10), 1.2 KiB | 1.2 MiB/s, done.\n",
+		"fatal: something went wrong\n",
+	}
+	for _, c := range chunks {
+		if _, err := w.Write([]byte(c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := "Cloning into 'dest'...\nfatal: something went wrong\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+	if w.phase != "Receiving objects" {
+		t.Errorf("phase = %q, want Receiving objects", w.phase)
+	}
+}
+
+func TestCloneWithProgress(t *testing.T) {
+	src := initTestRepo(t)
+	dest := filepath.Join(t.TempDir(), "clone")
+
+	var out bytes.Buffer
+	bar := progress.New(&out, "Cloning", 0, progress.Count)
+	g := NewGit("")
+	g.SetProgress(bar)
+	if err := g.Clone(src, dest); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	bar.Finish()
+	if !NewGit(dest).IsRepo() {
+		t.Error("clone is not a repo")
+	}
+}
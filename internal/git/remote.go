@@ -0,0 +1,50 @@
+package git
+
+import (
+	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// NewRemoteGit creates a Git wrapper whose commands run in workDir on another
+// machine. sshArgs is the ssh argv up to and including the destination (see
+// config.RemoteConfig.SSHArgs).
+func NewRemoteGit(sshArgs []string, workDir string) *Git {
+	return &Git{workDir: workDir, remote: append([]string(nil), sshArgs...)}
+}
+
+// WithRemote returns a copy of g whose commands run over SSH, keeping its
+// work and git directories. A nil or empty sshArgs returns g unchanged.
+func (g *Git) WithRemote(sshArgs []string) *Git {
+	if len(sshArgs) == 0 {
+		return g
+	}
+	c := *g
+	c.remote = append([]string(nil), sshArgs...)
+	return &c
+}
+
+// IsRemote reports whether commands run over SSH.
+func (g *Git) IsRemote() bool {
+	return len(g.remote) > 0
+}
+
+// command builds a git invocation with extraEnv (KEY=VALUE), running in the
+// work dir locally or on the remote machine. Locally, callers add extraEnv
+// to cmd.Env themselves; remotely it is passed through env(1).
+func (g *Git) command(args []string, extraEnv []string) *exec.Cmd {
+	if !g.IsRemote() {
+		cmd := exec.Command("git", args...)
+		if g.workDir != "" {
+			cmd.Dir = g.workDir
+		}
+		return cmd
+	}
+
+	argv := append([]string{"git"}, args...)
+	if len(extraEnv) > 0 {
+		argv = append(append([]string{"env"}, extraEnv...), argv...)
+	}
+	line := config.RemoteShellCommand(g.workDir, argv...)
+	return exec.Command(g.remote[0], append(append([]string(nil), g.remote[1:]...), line)...) //nolint:gosec // G204: ssh argv comes from rig config
+}
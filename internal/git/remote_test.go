@@ -0,0 +1,33 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemoteGitCommand(t *testing.T) {
+	g := NewRemoteGit([]string{"ssh", "build1"}, "/gt/rig/mayor/rig")
+	cmd := g.command([]string{"status", "--porcelain"}, []string{"GIT_TERMINAL_PROMPT=0"})
+	got := strings.Join(cmd.Args, " ")
+	want := "ssh build1 cd /gt/rig/mayor/rig && env GIT_TERMINAL_PROMPT=0 git status --porcelain"
+	if got != want {
+		t.Errorf("command args = %q, want %q", got, want)
+	}
+	if cmd.Dir != "" {
+		t.Errorf("remote command should not set a local Dir, got %q", cmd.Dir)
+	}
+}
+
+func TestWithRemote(t *testing.T) {
+	local := NewGitWithDir("/gt/rig/.repo.git", "")
+	if local.WithRemote(nil) != local {
+		t.Error("WithRemote(nil) should return the receiver")
+	}
+	remote := local.WithRemote([]string{"ssh", "build1"})
+	if !remote.IsRemote() || local.IsRemote() {
+		t.Error("WithRemote should copy, not mutate")
+	}
+	if remote.gitDir != local.gitDir {
+		t.Errorf("gitDir = %q, want %q", remote.gitDir, local.gitDir)
+	}
+}
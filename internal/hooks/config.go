@@ -173,12 +173,23 @@ func Merge(base, override *HooksConfig) *HooksConfig {
 func DefaultOverrides() map[string]*HooksConfig {
 	pathSetup := `export PATH="$HOME/go/bin:$HOME/.local/bin:$PATH"`
 
+	// Crew and polecat commits carry a "Bead: <id>" trailer naming the work
+	// they implement, so changelogs can trace commits back to beads.
+	beadTrailer := HookEntry{
+		Matcher: "Bash(git commit*)",
+		Hooks: []Hook{{
+			Type:    "command",
+			Command: fmt.Sprintf("%s && gt tap inject bead-trailer", pathSetup),
+		}},
+	}
+
 	return map[string]*HooksConfig{
 		// Crew workers: auto-cycle session on context compaction (gt-op78).
 		// Instead of compacting (lossy), replace with fresh session that
 		// inherits hooked work. The --cycle flag does: collect state →
 		// send handoff mail → respawn pane with fresh Claude instance.
 		"crew": {
+			PreToolUse: []HookEntry{beadTrailer},
 			PreCompact: []HookEntry{
 				{
 					Matcher: "",
@@ -191,6 +202,9 @@ func DefaultOverrides() map[string]*HooksConfig {
 				},
 			},
 		},
+		"polecats": {
+			PreToolUse: []HookEntry{beadTrailer},
+		},
 	}
 }
 
@@ -415,7 +415,8 @@ func TestComputeExpected(t *testing.T) {
 	if len(expected.SessionStart) != 1 || expected.SessionStart[0].Hooks[0].Command != "gastown-crew-session" {
 		t.Errorf("expected gastown/crew SessionStart, got %v", expected.SessionStart)
 	}
-	if len(expected.PreToolUse) != 1 || expected.PreToolUse[0].Hooks[0].Command != "crew-guard" {
+	// The built-in crew bead-trailer hook has a different matcher, so both remain.
+	if len(expected.PreToolUse) != 2 || expected.PreToolUse[1].Hooks[0].Command != "crew-guard" {
 		t.Errorf("expected crew PreToolUse, got %v", expected.PreToolUse)
 	}
 }
@@ -448,6 +449,17 @@ func TestComputeExpectedNoBase(t *testing.T) {
 	if len(crew.SessionStart) != len(defaultBase.SessionStart) {
 		t.Error("expected crew to inherit SessionStart from DefaultBase")
 	}
+
+	// Crew and polecats get the bead trailer injection on top of the base guards
+	for _, target := range []string{"gastown/crew", "gastown/polecats"} {
+		cfg, err := ComputeExpected(target)
+		if err != nil {
+			t.Fatalf("ComputeExpected(%s) failed: %v", target, err)
+		}
+		if len(cfg.PreToolUse) != len(defaultBase.PreToolUse)+1 {
+			t.Errorf("%s: expected bead-trailer PreToolUse hook added to the base guards, got %d entries", target, len(cfg.PreToolUse))
+		}
+	}
 }
 
 // TestComputeExpectedBuiltinPlusOnDisk verifies that on-disk overrides layer
@@ -0,0 +1,123 @@
+package hooks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Drift states reported by TargetDrift.
+const (
+	DriftInSync   = "in sync"  // settings match base + overrides
+	DriftStale    = "stale"    // base or overrides changed since the last sync
+	DriftModified = "modified" // settings were edited since the last sync
+	DriftMissing  = "missing"  // settings.json does not exist
+)
+
+// Version returns a short content hash identifying a hooks configuration.
+// Two configs have the same version exactly when HooksEqual reports them equal.
+func Version(cfg *HooksConfig) string {
+	if cfg == nil {
+		cfg = &HooksConfig{}
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// SyncRecord remembers the hooks version last written to a target.
+type SyncRecord struct {
+	Version  string    `json:"version"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// StatePath returns the path of the sync state file, keyed by settings path.
+func StatePath() string {
+	return filepath.Join(gtDir(), "hooks-state.json")
+}
+
+// LoadSyncState returns the sync records for all targets. A missing state
+// file yields an empty map.
+func LoadSyncState() (map[string]SyncRecord, error) {
+	state := make(map[string]SyncRecord)
+	data, err := os.ReadFile(StatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StatePath(), err)
+	}
+	return state, nil
+}
+
+// RecordSync notes that version was written to the settings file at path.
+func RecordSync(path, version string) error {
+	state, err := LoadSyncState()
+	if err != nil {
+		return err
+	}
+	if state[path].Version == version {
+		return nil
+	}
+	state[path] = SyncRecord{Version: version, SyncedAt: time.Now().UTC()}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling sync state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(StatePath()), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(StatePath(), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", StatePath(), err)
+	}
+	return nil
+}
+
+// TargetDrift classifies a target from its current and expected hooks and
+// its last sync record. exists reports whether settings.json is present.
+// Without a sync record, a mismatch counts as stale: the file predates
+// version tracking and nothing shows it was edited by hand.
+func TargetDrift(exists bool, current, expected *HooksConfig, rec *SyncRecord) string {
+	if !exists {
+		return DriftMissing
+	}
+	currentVersion := Version(current)
+	if currentVersion == Version(expected) {
+		return DriftInSync
+	}
+	if rec != nil && rec.Version != currentVersion {
+		return DriftModified
+	}
+	return DriftStale
+}
+
+// DriftedEvents returns the event types whose entries differ between
+// current and expected, in EventTypes order.
+func DriftedEvents(current, expected *HooksConfig) []string {
+	if current == nil {
+		current = &HooksConfig{}
+	}
+	if expected == nil {
+		expected = &HooksConfig{}
+	}
+	var events []string
+	for _, et := range EventTypes {
+		a, _ := json.Marshal(current.GetEntries(et))
+		b, _ := json.Marshal(expected.GetEntries(et))
+		if string(a) != string(b) {
+			events = append(events, et)
+		}
+	}
+	return events
+}
@@ -0,0 +1,78 @@
+package hooks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionMatchesHooksEqual(t *testing.T) {
+	a := &HooksConfig{SessionStart: []HookEntry{{Matcher: "", Hooks: []Hook{{Type: "command", Command: "gt prime"}}}}}
+	b := &HooksConfig{SessionStart: []HookEntry{{Matcher: "", Hooks: []Hook{{Type: "command", Command: "gt prime"}}}}}
+	c := &HooksConfig{Stop: a.SessionStart}
+
+	if Version(a) != Version(b) {
+		t.Error("equal configs should have the same version")
+	}
+	if Version(a) == Version(c) {
+		t.Error("different configs should have different versions")
+	}
+	if Version(nil) != Version(&HooksConfig{}) {
+		t.Error("nil and empty configs should have the same version")
+	}
+}
+
+func TestTargetDrift(t *testing.T) {
+	expected := &HooksConfig{Stop: []HookEntry{{Hooks: []Hook{{Type: "command", Command: "new"}}}}}
+	old := &HooksConfig{Stop: []HookEntry{{Hooks: []Hook{{Type: "command", Command: "old"}}}}}
+	edited := &HooksConfig{Stop: []HookEntry{{Hooks: []Hook{{Type: "command", Command: "mine"}}}}}
+	synced := &SyncRecord{Version: Version(old)}
+
+	tests := []struct {
+		name    string
+		exists  bool
+		current *HooksConfig
+		rec     *SyncRecord
+		want    string
+	}{
+		{"missing", false, &HooksConfig{}, nil, DriftMissing},
+		{"in sync", true, expected, synced, DriftInSync},
+		{"stale since last sync", true, old, synced, DriftStale},
+		{"edited since last sync", true, edited, synced, DriftModified},
+		{"untracked mismatch", true, edited, nil, DriftStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TargetDrift(tt.exists, tt.current, expected, tt.rec); got != tt.want {
+				t.Errorf("TargetDrift = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriftedEvents(t *testing.T) {
+	entry := []HookEntry{{Hooks: []Hook{{Type: "command", Command: "x"}}}}
+	current := &HooksConfig{SessionStart: entry, Stop: entry}
+	expected := &HooksConfig{SessionStart: entry, PreCompact: entry}
+
+	got := DriftedEvents(current, expected)
+	want := []string{"Stop", "PreCompact"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DriftedEvents = %v, want %v", got, want)
+	}
+}
+
+func TestRecordSync(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	if err := RecordSync("/town/mayor/.claude/settings.json", "abc"); err != nil {
+		t.Fatalf("RecordSync: %v", err)
+	}
+	state, err := LoadSyncState()
+	if err != nil {
+		t.Fatalf("LoadSyncState: %v", err)
+	}
+	rec, ok := state["/town/mayor/.claude/settings.json"]
+	if !ok || rec.Version != "abc" || rec.SyncedAt.IsZero() {
+		t.Errorf("unexpected record: %+v (found=%v)", rec, ok)
+	}
+}
@@ -0,0 +1,224 @@
+// Package issuetemplate renders per-rig issue templates into bead fields.
+//
+// Templates live in <rig>/templates/<name>.md: TOML frontmatter between +++
+// delimiters followed by a markdown body that becomes the bead description.
+// {{var}} placeholders in the title, labels, acceptance criteria, and body are
+// filled from --var values and the defaults declared under [vars].
+//
+//	+++
+//	title = "Fix {{pkg}}: {{summary}}"
+//	type = "bug"
+//	priority = 1
+//	labels = ["bug", "pkg:{{pkg}}"]
+//	acceptance = ["Regression test covers the failure", "go test ./internal/{{pkg}}/... passes"]
+//	formula = "mol-bugfix"
+//
+//	[vars.pkg]
+//	description = "Package containing the bug"
+//
+//	[vars.summary]
+//	default = "see description"
+//	+++
+//	The {{pkg}} package ...
+package issuetemplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Dir is the rig-relative directory holding issue templates.
+const Dir = "templates"
+
+// DefaultPriority is used when a template doesn't set one.
+const DefaultPriority = 2
+
+// varPattern matches {{variable}} placeholders, as in formulas.
+var varPattern = regexp.MustCompile(`\{\{([a-zA-Z_][a-zA-Z0-9_]*)\}\}`)
+
+// Var describes a template variable.
+type Var struct {
+	Description string `toml:"description"`
+	Default     string `toml:"default"`
+}
+
+// Template is a parsed issue template.
+type Template struct {
+	Name       string         `toml:"-"`
+	Path       string         `toml:"-"`
+	Title      string         `toml:"title"`
+	Type       string         `toml:"type"`
+	Priority   *int           `toml:"priority"`
+	Labels     []string       `toml:"labels"`
+	Acceptance []string       `toml:"acceptance"`
+	Formula    string         `toml:"formula"`
+	Vars       map[string]Var `toml:"vars"`
+	Body       string         `toml:"-"`
+}
+
+// Rendered holds the bead fields produced by rendering a template.
+type Rendered struct {
+	Title       string
+	Type        string
+	Priority    int
+	Labels      []string
+	Description string
+	Formula     string
+}
+
+// Path returns the file path of the named template in a rig.
+func Path(rigPath, name string) string {
+	return filepath.Join(rigPath, Dir, name+".md")
+}
+
+// List returns the names of the templates available in a rig, sorted.
+func List(rigPath string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(rigPath, Dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), ".md"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads the named template from a rig.
+func Load(rigPath, name string) (*Template, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	path := Path(rigPath, name)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the rig's templates dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			available, _ := List(rigPath)
+			if len(available) == 0 {
+				return nil, fmt.Errorf("template %q not found (no templates in %s)", name, filepath.Join(rigPath, Dir))
+			}
+			return nil, fmt.Errorf("template %q not found (available: %s)", name, strings.Join(available, ", "))
+		}
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+
+	t, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	t.Name = name
+	t.Path = path
+	return t, nil
+}
+
+// Parse parses template content.
+func Parse(content []byte) (*Template, error) {
+	str := string(content)
+
+	const delimiter = "+++"
+	start := strings.Index(str, delimiter)
+	if start == -1 {
+		return nil, fmt.Errorf("missing TOML frontmatter (no opening +++)")
+	}
+	end := strings.Index(str[start+len(delimiter):], delimiter)
+	if end == -1 {
+		return nil, fmt.Errorf("missing TOML frontmatter (no closing +++)")
+	}
+	end += start + len(delimiter)
+
+	var t Template
+	if _, err := toml.Decode(str[start+len(delimiter):end], &t); err != nil {
+		return nil, fmt.Errorf("parsing TOML frontmatter: %w", err)
+	}
+	t.Body = strings.TrimSpace(str[end+len(delimiter):])
+
+	if t.Title == "" {
+		return nil, fmt.Errorf("missing required field: title")
+	}
+	if t.Priority != nil && (*t.Priority < 0 || *t.Priority > 4) {
+		return nil, fmt.Errorf("priority must be 0-4, got %d", *t.Priority)
+	}
+	return &t, nil
+}
+
+// Variables returns the names of all variables used in the template, sorted.
+func (t *Template) Variables() []string {
+	seen := make(map[string]bool)
+	texts := append([]string{t.Title, t.Body}, t.Labels...)
+	texts = append(texts, t.Acceptance...)
+	for _, s := range texts {
+		for _, m := range varPattern.FindAllStringSubmatch(s, -1) {
+			seen[m[1]] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render fills the template's placeholders from vars, falling back to
+// declared defaults. Every placeholder must resolve to a value.
+func (t *Template) Render(vars map[string]string) (*Rendered, error) {
+	values := make(map[string]string)
+	for name, v := range t.Vars {
+		if v.Default != "" {
+			values[name] = v.Default
+		}
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+
+	var missing []string
+	for _, name := range t.Variables() {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s: missing variable(s) %s (use --var name=value)", t.Name, strings.Join(missing, ", "))
+	}
+
+	expand := func(s string) string {
+		return varPattern.ReplaceAllStringFunc(s, func(m string) string {
+			return values[m[2:len(m)-2]]
+		})
+	}
+
+	r := &Rendered{
+		Title:    expand(t.Title),
+		Type:     t.Type,
+		Priority: DefaultPriority,
+		Formula:  t.Formula,
+	}
+	if t.Priority != nil {
+		r.Priority = *t.Priority
+	}
+	for _, l := range t.Labels {
+		r.Labels = append(r.Labels, expand(l))
+	}
+
+	var desc strings.Builder
+	desc.WriteString(expand(t.Body))
+	if len(t.Acceptance) > 0 {
+		if desc.Len() > 0 {
+			desc.WriteString("\n\n")
+		}
+		desc.WriteString("## Acceptance Criteria\n")
+		for _, a := range t.Acceptance {
+			desc.WriteString("\n- [ ] " + expand(a))
+		}
+	}
+	r.Description = desc.String()
+	return r, nil
+}
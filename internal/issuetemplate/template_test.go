@@ -0,0 +1,111 @@
+package issuetemplate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const bugfixTemplate = `+++
+title = "Fix {{pkg}}: {{summary}}"
+type = "bug"
+priority = 1
+labels = ["bug", "pkg:{{pkg}}"]
+acceptance = ["Regression test covers the failure", "go test ./internal/{{pkg}}/... passes"]
+formula = "mol-bugfix"
+
+[vars.pkg]
+description = "Package containing the bug"
+
+[vars.summary]
+default = "flaky behavior"
++++
+Investigate the {{pkg}} package.
+`
+
+func writeTemplate(t *testing.T, rigPath, name, content string) {
+	t.Helper()
+	dir := filepath.Join(rigPath, Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAndRender(t *testing.T) {
+	rigPath := t.TempDir()
+	writeTemplate(t, rigPath, "bugfix", bugfixTemplate)
+
+	tmpl, err := Load(rigPath, "bugfix")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := strings.Join(tmpl.Variables(), ","); got != "pkg,summary" {
+		t.Errorf("Variables() = %s", got)
+	}
+
+	if _, err := tmpl.Render(nil); err == nil || !strings.Contains(err.Error(), "pkg") {
+		t.Errorf("expected missing pkg error, got %v", err)
+	}
+
+	r, err := tmpl.Render(map[string]string{"pkg": "doltserver"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if r.Title != "Fix doltserver: flaky behavior" {
+		t.Errorf("Title = %q", r.Title)
+	}
+	if r.Priority != 1 || r.Type != "bug" || r.Formula != "mol-bugfix" {
+		t.Errorf("rendered = %+v", r)
+	}
+	if strings.Join(r.Labels, ",") != "bug,pkg:doltserver" {
+		t.Errorf("Labels = %v", r.Labels)
+	}
+	want := "Investigate the doltserver package.\n\n## Acceptance Criteria\n\n" +
+		"- [ ] Regression test covers the failure\n- [ ] go test ./internal/doltserver/... passes"
+	if r.Description != want {
+		t.Errorf("Description = %q\nwant %q", r.Description, want)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	rigPath := t.TempDir()
+	if _, err := Load(rigPath, "bugfix"); err == nil || !strings.Contains(err.Error(), "no templates") {
+		t.Errorf("expected no-templates error, got %v", err)
+	}
+
+	writeTemplate(t, rigPath, "feature", "+++\ntitle = \"x\"\n+++\n")
+	if _, err := Load(rigPath, "bugfix"); err == nil || !strings.Contains(err.Error(), "available: feature") {
+		t.Errorf("expected available list, got %v", err)
+	}
+	if _, err := Load(rigPath, "../feature"); err == nil {
+		t.Error("expected error for path-like name")
+	}
+
+	for name, content := range map[string]string{
+		"no-frontmatter": "just text",
+		"no-title":       "+++\npriority = 1\n+++\n",
+		"bad-priority":   "+++\ntitle = \"x\"\npriority = 7\n+++\n",
+	} {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("%s: expected parse error", name)
+		}
+	}
+}
+
+func TestRender_DefaultPriority(t *testing.T) {
+	tmpl, err := Parse([]byte("+++\ntitle = \"Chore\"\n+++\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := tmpl.Render(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Priority != DefaultPriority || r.Description != "" {
+		t.Errorf("rendered = %+v", r)
+	}
+}
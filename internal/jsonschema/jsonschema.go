@@ -0,0 +1,172 @@
+// Package jsonschema generates JSON Schema documents from Go structs.
+//
+// Property names and optionality come from the structs' json tags, so a
+// schema describes exactly what encoding/json reads and writes. Schemas are
+// deliberately permissive: unknown keys are allowed (older and newer gt
+// versions share config files) and nillable fields accept null.
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+type Schema map[string]interface{}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Generate returns the schema for v's type, identified by id and titled
+// title. Named struct types other than the root are placed in $defs and
+// referenced, which also handles recursive types.
+func Generate(v interface{}, id, title string) Schema {
+	g := &generator{defs: Schema{}, names: map[reflect.Type]string{}}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	root := g.structSchema(t)
+	if props, ok := root["properties"].(Schema); ok {
+		// Lets files point editors at their schema.
+		props["$schema"] = Schema{"type": "string"}
+	}
+	root["$schema"] = Draft
+	root["$id"] = id
+	root["title"] = title
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	defs  Schema
+	names map[reflect.Type]string
+}
+
+// schemaFor returns the schema for t, without null.
+func (g *generator) schemaFor(t reflect.Type) Schema {
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case durationType:
+		return Schema{"type": "integer", "description": "duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": g.fieldSchema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.fieldSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return Schema{"$ref": "#/$defs/" + g.define(t)}
+	default:
+		// interface{}, json.RawMessage-like values: anything goes.
+		return Schema{}
+	}
+}
+
+// fieldSchema is schemaFor, also allowing null for types encoding/json
+// writes as null when nil.
+func (g *generator) fieldSchema(t reflect.Type) Schema {
+	s := g.schemaFor(t)
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return nullable(s)
+	}
+	return s
+}
+
+// nullable widens s to also accept null.
+func nullable(s Schema) Schema {
+	if typ, ok := s["type"].(string); ok {
+		s["type"] = []string{typ, "null"}
+		return s
+	}
+	if len(s) == 0 {
+		return s
+	}
+	return Schema{"anyOf": []Schema{s, {"type": "null"}}}
+}
+
+// define adds named struct type t to $defs and returns its name there.
+func (g *generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for _, taken := range g.names {
+		if taken == name {
+			name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+			break
+		}
+	}
+	g.names[t] = name
+	g.defs[name] = Schema{} // placeholder so recursion terminates
+	g.defs[name] = g.structSchema(t)
+	return name
+}
+
+// structSchema describes a struct's encoding/json fields. Fields without
+// omitempty are still optional: gt fills in defaults for missing ones.
+func (g *generator) structSchema(t reflect.Type) Schema {
+	props := Schema{}
+	g.addFields(t, props)
+	return Schema{"type": "object", "properties": props}
+}
+
+func (g *generator) addFields(t reflect.Type, props Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Untagged embedded structs contribute their fields, as in encoding/json.
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(ft, props)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.fieldSchema(f.Type)
+	}
+}
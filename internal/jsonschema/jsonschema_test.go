@@ -0,0 +1,110 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testInner struct {
+	Name string `json:"name"`
+}
+
+type testNode struct {
+	Children []*testNode `json:"children,omitempty"`
+}
+
+type testEmbedded struct {
+	Shared string `json:"shared"`
+}
+
+type testRoot struct {
+	testEmbedded
+	Count    int               `json:"count"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Enabled  *bool             `json:"enabled,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Inner    testInner         `json:"inner"`
+	Tree     *testNode         `json:"tree,omitempty"`
+	When     time.Time         `json:"when"`
+	Every    time.Duration     `json:"every"`
+	Anything interface{}       `json:"anything"`
+	Skipped  string            `json:"-"`
+	Untagged string
+	hidden   string
+}
+
+func roundTrip(t *testing.T, s Schema) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return m
+}
+
+func TestGenerate(t *testing.T) {
+	s := roundTrip(t, Generate(&testRoot{}, "https://example.com/root.schema.json", "Root"))
+
+	if s["$schema"] != Draft || s["$id"] != "https://example.com/root.schema.json" || s["title"] != "Root" {
+		t.Errorf("header = %v %v %v", s["$schema"], s["$id"], s["title"])
+	}
+	props := s["properties"].(map[string]interface{})
+
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	for _, want := range []string{"$schema", "shared", "count", "ratio", "enabled", "tags", "labels", "inner", "tree", "when", "every", "anything", "Untagged"} {
+		if _, ok := props[want]; !ok {
+			t.Errorf("missing property %q (have %v)", want, names)
+		}
+	}
+	for _, unwanted := range []string{"Skipped", "-", "hidden", "testEmbedded"} {
+		if _, ok := props[unwanted]; ok {
+			t.Errorf("unexpected property %q", unwanted)
+		}
+	}
+
+	tests := []struct {
+		prop string
+		want interface{}
+	}{
+		{"count", map[string]interface{}{"type": "integer"}},
+		{"ratio", map[string]interface{}{"type": "number"}},
+		{"enabled", map[string]interface{}{"type": []interface{}{"boolean", "null"}}},
+		{"tags", map[string]interface{}{"type": []interface{}{"array", "null"}, "items": map[string]interface{}{"type": "string"}}},
+		{"labels", map[string]interface{}{"type": []interface{}{"object", "null"}, "additionalProperties": map[string]interface{}{"type": "string"}}},
+		{"inner", map[string]interface{}{"$ref": "#/$defs/testInner"}},
+		{"tree", map[string]interface{}{"anyOf": []interface{}{map[string]interface{}{"$ref": "#/$defs/testNode"}, map[string]interface{}{"type": "null"}}}},
+		{"when", map[string]interface{}{"type": "string", "format": "date-time"}},
+		{"every", map[string]interface{}{"type": "integer", "description": "duration in nanoseconds"}},
+		{"anything", map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		if got := props[tt.prop]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.prop, got, tt.want)
+		}
+	}
+
+	defs := s["$defs"].(map[string]interface{})
+	node := defs["testNode"].(map[string]interface{})["properties"].(map[string]interface{})
+	children := node["children"].(map[string]interface{})
+	if ref := children["items"].(map[string]interface{})["anyOf"].([]interface{})[0]; !reflect.DeepEqual(ref, map[string]interface{}{"$ref": "#/$defs/testNode"}) {
+		t.Errorf("recursive ref = %v", ref)
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	a, _ := json.Marshal(Generate(testRoot{}, "id", "Root"))
+	b, _ := json.Marshal(Generate(testRoot{}, "id", "Root"))
+	if string(a) != string(b) {
+		t.Error("Generate output differs between runs")
+	}
+}
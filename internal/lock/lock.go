@@ -7,6 +7,8 @@
 // - Session ID (tmux session name)
 //
 // Stale locks (where the PID is dead) are automatically cleaned up.
+//
+// Sweep does the same for the daemon's and Dolt server's PID and lock files.
 package lock
 
 import (
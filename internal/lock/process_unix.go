@@ -4,7 +4,12 @@ package lock
 
 import (
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
 	"syscall"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // processExists checks if a process with the given PID exists and is alive.
@@ -23,3 +28,13 @@ func processExists(pid int) bool {
 	err = process.Signal(syscall.Signal(0))
 	return err == nil
 }
+
+// processCommand returns pid's command line, reporting false if ps can't
+// tell.
+func processCommand(pid int) (string, bool) {
+	out, err := util.Output(exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command="), util.ProbeTimeout)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
@@ -24,3 +24,9 @@ func processExists(pid int) bool {
 	_ = windows.CloseHandle(handle)
 	return true
 }
+
+// processCommand is not available on Windows; PID files are checked for
+// liveness only.
+func processCommand(pid int) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,217 @@
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/flock"
+)
+
+// ArtifactKind says how the holder of a lock artifact is found.
+type ArtifactKind int
+
+const (
+	// PIDFile holds a process ID; it is held while that process lives.
+	PIDFile ArtifactKind = iota
+
+	// FlockFile is an advisory lock file; it is held while any process has
+	// it flocked. The kernel drops the flock when its holder exits, so an
+	// unheld file blocks nothing, but some callers treat its presence as a
+	// sign of a crashed run.
+	FlockFile
+)
+
+func (k ArtifactKind) String() string {
+	if k == PIDFile {
+		return "pid"
+	}
+	return "flock"
+}
+
+// Artifact is a PID or lock file that a crash can leave behind.
+type Artifact struct {
+	Path string
+	Kind ArtifactKind
+
+	// Process, for PID files, is a word the holder's command line must
+	// contain, guarding against the PID having been reused. Empty checks
+	// liveness only; so does a platform without ps.
+	Process string
+
+	// HeldWhile names a PID file whose live process also holds this
+	// artifact, so e.g. Dolt's LOCK files are never touched while the
+	// server that owns them runs.
+	HeldWhile *Artifact
+
+	// Keep marks a flock file meant to persist between holders; an unheld
+	// Keep file is reported but not removed.
+	Keep bool
+}
+
+// SweepResult is the verdict on one artifact.
+type SweepResult struct {
+	Artifact
+	Holder  int    // PID of the holder, when known
+	Stale   bool   // no live holder
+	Removed bool   // deleted by Sweep
+	Reason  string // why it is held or stale
+	Err     error  // removal failure
+}
+
+// Inspect decides whether a is held, without changing anything.
+func Inspect(a Artifact) SweepResult {
+	r := SweepResult{Artifact: a}
+	if a.HeldWhile != nil {
+		if owner := Inspect(*a.HeldWhile); !owner.Stale {
+			r.Holder = owner.Holder
+			r.Reason = fmt.Sprintf("owner %s is running (PID %d)", filepath.Base(a.HeldWhile.Path), owner.Holder)
+			return r
+		}
+	}
+
+	switch a.Kind {
+	case PIDFile:
+		data, err := os.ReadFile(a.Path)
+		if err != nil {
+			r.Reason = "unreadable: " + err.Error()
+			return r
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil || pid <= 0 {
+			r.Stale = true
+			r.Reason = fmt.Sprintf("malformed PID %q", strings.TrimSpace(string(data)))
+			return r
+		}
+		r.Holder = pid
+		if !processExists(pid) {
+			r.Stale = true
+			r.Reason = fmt.Sprintf("PID %d is not running", pid)
+			return r
+		}
+		if a.Process != "" {
+			if cmdline, ok := processCommand(pid); ok && !strings.Contains(cmdline, a.Process) {
+				r.Stale = true
+				r.Reason = fmt.Sprintf("PID %d was reused by %q", pid, truncate(cmdline, 60))
+				return r
+			}
+		}
+		r.Reason = fmt.Sprintf("held by PID %d", pid)
+
+	case FlockFile:
+		fl := flock.New(a.Path)
+		locked, err := fl.TryLock()
+		if err != nil {
+			r.Reason = "can't test lock: " + err.Error()
+			return r
+		}
+		if !locked {
+			r.Reason = "locked by a running process"
+			return r
+		}
+		_ = fl.Unlock()
+		r.Stale = true
+		r.Reason = "not locked by any process"
+	}
+	return r
+}
+
+// Sweep inspects each artifact and, when remove is set, deletes the stale
+// ones. Missing files are skipped. Flock files are deleted while Sweep
+// itself holds the lock, so a process can't take it in between.
+func Sweep(artifacts []Artifact, remove bool) []SweepResult {
+	var results []SweepResult
+	for _, a := range artifacts {
+		if _, err := os.Stat(a.Path); err != nil {
+			continue
+		}
+		r := Inspect(a)
+		if remove && r.Stale && !a.Keep {
+			r.Err = removeArtifact(a)
+			r.Removed = r.Err == nil
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func removeArtifact(a Artifact) error {
+	if a.Kind == FlockFile {
+		fl := flock.New(a.Path)
+		locked, err := fl.TryLock()
+		if err != nil {
+			return err
+		}
+		if !locked {
+			return fmt.Errorf("%s was locked again", a.Path)
+		}
+		defer func() { _ = fl.Unlock() }()
+	}
+	if err := os.Remove(a.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// keptLocks are daemon/ flock files that stay in place between holders:
+// removing one while a process waits on it would split waiters across two
+// inodes and defeat the lock.
+var keptLocks = map[string]bool{
+	"shutdown.lock":        true,
+	"dolt-state.json.lock": true,
+}
+
+// pidProcesses maps daemon/ PID files to the command-line word of their
+// owner.
+var pidProcesses = map[string]string{
+	"daemon.pid": "daemon",
+	"dolt.pid":   "dolt",
+}
+
+// TownArtifacts lists the PID and lock files of a town's daemon and Dolt
+// server: every *.pid and *.lock under <town>/daemon, and each database's
+// .dolt/noms/LOCK under doltDataDir (skipped when empty).
+func TownArtifacts(townRoot, doltDataDir string) []Artifact {
+	daemonDir := filepath.Join(townRoot, "daemon")
+	var artifacts []Artifact
+	var doltPID *Artifact
+
+	pids, _ := filepath.Glob(filepath.Join(daemonDir, "*.pid"))
+	for _, p := range pids {
+		a := Artifact{Path: p, Kind: PIDFile, Process: pidProcesses[filepath.Base(p)]}
+		artifacts = append(artifacts, a)
+		if filepath.Base(p) == "dolt.pid" {
+			doltPID = &a
+		}
+	}
+	locks, _ := filepath.Glob(filepath.Join(daemonDir, "*.lock"))
+	for _, p := range locks {
+		artifacts = append(artifacts, Artifact{Path: p, Kind: FlockFile, Keep: keptLocks[filepath.Base(p)]})
+	}
+	artifacts = append(artifacts, DoltLockArtifacts(doltDataDir, doltPID)...)
+	return artifacts
+}
+
+// DoltLockArtifacts lists the .dolt/noms/LOCK file of each database under
+// dataDir. Dolt flocks these while a database is open; server, if not nil,
+// is the server's PID file, which holds them all while it runs.
+func DoltLockArtifacts(dataDir string, server *Artifact) []Artifact {
+	if dataDir == "" {
+		return nil
+	}
+	locks, _ := filepath.Glob(filepath.Join(dataDir, "*", ".dolt", "noms", "LOCK"))
+	artifacts := make([]Artifact, 0, len(locks))
+	for _, p := range locks {
+		artifacts = append(artifacts, Artifact{Path: p, Kind: FlockFile, HeldWhile: server})
+	}
+	return artifacts
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
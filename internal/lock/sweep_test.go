@@ -0,0 +1,111 @@
+package lock
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gofrs/flock"
+)
+
+// deadPID returns the PID of a process that has exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("can't run true: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func writeFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSweep(t *testing.T) {
+	dir := t.TempDir()
+	live := writeFile(t, filepath.Join(dir, "live.pid"), strconv.Itoa(os.Getpid()))
+	dead := writeFile(t, filepath.Join(dir, "dead.pid"), strconv.Itoa(deadPID(t)))
+	junk := writeFile(t, filepath.Join(dir, "junk.pid"), "not a pid")
+	reused := writeFile(t, filepath.Join(dir, "reused.pid"), strconv.Itoa(os.Getpid()))
+	free := writeFile(t, filepath.Join(dir, "free.lock"), "")
+	kept := writeFile(t, filepath.Join(dir, "kept.lock"), "")
+	held := writeFile(t, filepath.Join(dir, "held.lock"), "")
+	owned := writeFile(t, filepath.Join(dir, "db", "LOCK"), "")
+
+	holder := flock.New(held)
+	if ok, err := holder.TryLock(); err != nil || !ok {
+		t.Fatalf("locking %s: %v", held, err)
+	}
+	defer func() { _ = holder.Unlock() }()
+
+	liveArtifact := Artifact{Path: live, Kind: PIDFile}
+	results := Sweep([]Artifact{
+		liveArtifact,
+		{Path: dead, Kind: PIDFile},
+		{Path: junk, Kind: PIDFile},
+		{Path: reused, Kind: PIDFile, Process: "no-such-program-name"},
+		{Path: free, Kind: FlockFile},
+		{Path: kept, Kind: FlockFile, Keep: true},
+		{Path: held, Kind: FlockFile},
+		{Path: owned, Kind: FlockFile, HeldWhile: &liveArtifact},
+		{Path: filepath.Join(dir, "missing.pid"), Kind: PIDFile},
+	}, true)
+
+	want := map[string]bool{ // path -> removed
+		live: false, dead: true, junk: true, free: true, kept: false, held: false, owned: false,
+	}
+	if _, err := exec.LookPath("ps"); err == nil {
+		want[reused] = true
+	} else {
+		want[reused] = false
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d (missing files skipped): %+v", len(results), len(want), results)
+	}
+	for _, r := range results {
+		if r.Removed != want[r.Path] {
+			t.Errorf("%s: removed=%v stale=%v (%s), want removed=%v", filepath.Base(r.Path), r.Removed, r.Stale, r.Reason, want[r.Path])
+		}
+		if _, err := os.Stat(r.Path); os.IsNotExist(err) != want[r.Path] {
+			t.Errorf("%s: on disk after sweep = %v", filepath.Base(r.Path), !os.IsNotExist(err))
+		}
+	}
+}
+
+func TestTownArtifacts(t *testing.T) {
+	town := t.TempDir()
+	data := filepath.Join(town, ".dolt-data")
+	writeFile(t, filepath.Join(town, "daemon", "dolt.pid"), "1")
+	writeFile(t, filepath.Join(town, "daemon", "daemon.lock"), "")
+	writeFile(t, filepath.Join(town, "daemon", "shutdown.lock"), "")
+	writeFile(t, filepath.Join(town, "daemon", "dolt-state.json"), "{}")
+	writeFile(t, filepath.Join(data, "hq", ".dolt", "noms", "LOCK"), "")
+
+	byPath := make(map[string]Artifact)
+	for _, a := range TownArtifacts(town, data) {
+		rel, _ := filepath.Rel(town, a.Path)
+		byPath[filepath.ToSlash(rel)] = a
+	}
+	if len(byPath) != 4 {
+		t.Fatalf("artifacts = %v", byPath)
+	}
+	if a := byPath["daemon/dolt.pid"]; a.Kind != PIDFile || a.Process != "dolt" {
+		t.Errorf("dolt.pid = %+v", a)
+	}
+	if a := byPath["daemon/shutdown.lock"]; a.Kind != FlockFile || !a.Keep {
+		t.Errorf("shutdown.lock = %+v, want a kept flock file", a)
+	}
+	if a := byPath[".dolt-data/hq/.dolt/noms/LOCK"]; a.HeldWhile == nil || filepath.Base(a.HeldWhile.Path) != "dolt.pid" {
+		t.Errorf("noms LOCK = %+v, want held while dolt.pid lives", a)
+	}
+}
@@ -0,0 +1,305 @@
+package multiplexer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Headless runs each session as a supervised background shell instead of a
+// terminal multiplexer session, for CI and servers without tmux. A session
+// is a process group rooted at `sh`, fed from an append-only input file, with
+// all output captured in a log file:
+//
+//	<town>/daemon/headless/<name>.json  state (pid, work dir, start time)
+//	<town>/daemon/headless/<name>.in    input; SendKeys appends lines here
+//	<town>/daemon/headless/<name>.log   combined stdout/stderr
+//
+// The session is alive while its process group leader is. Attach follows the
+// log rather than connecting a terminal.
+type Headless struct {
+	dir string
+}
+
+// HeadlessState is the on-disk record of a headless session.
+type HeadlessState struct {
+	Name      string    `json:"name"`
+	PID       int       `json:"pid"`
+	WorkDir   string    `json:"work_dir,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// ProcStart is the process's start time as the OS reports it (clock
+	// ticks since boot on Linux), so a recycled PID isn't mistaken for the
+	// session. 0 where the OS doesn't expose it.
+	ProcStart uint64 `json:"proc_start,omitempty"`
+}
+
+// alive reports whether the session's process is still the one that was
+// started: the PID exists and, when recorded, its start time matches.
+func (st *HeadlessState) alive() bool {
+	if !processAlive(st.PID) {
+		return false
+	}
+	return st.ProcStart == 0 || processStartTime(st.PID) == st.ProcStart
+}
+
+// NewHeadless creates a headless backend storing state under townRoot.
+func NewHeadless(townRoot string) *Headless {
+	return &Headless{dir: filepath.Join(townRoot, "daemon", "headless")}
+}
+
+func (h *Headless) statePath(name string) string { return filepath.Join(h.dir, name+".json") }
+func (h *Headless) inputPath(name string) string { return filepath.Join(h.dir, name+".in") }
+
+// LogPath returns the log file for a session.
+func (h *Headless) LogPath(name string) string { return filepath.Join(h.dir, name+".log") }
+
+func (h *Headless) readState(name string) (*HeadlessState, error) {
+	data, err := os.ReadFile(h.statePath(name)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var st HeadlessState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parsing headless state for %s: %w", name, err)
+	}
+	return &st, nil
+}
+
+// ListSessions returns the names of live headless sessions.
+func (h *Headless) ListSessions() ([]string, error) {
+	entries, err := os.ReadDir(h.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sessions []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if st, err := h.readState(name); err == nil && st.alive() {
+			sessions = append(sessions, name)
+		}
+	}
+	sort.Strings(sessions)
+	return sessions, nil
+}
+
+// HasSession reports whether the session's process is running.
+func (h *Headless) HasSession(name string) (bool, error) {
+	st, err := h.readState(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return st.alive(), nil
+}
+
+// NewSession starts a detached shell in its own process group. The shell
+// reads commands from the input file; when it exits, the whole group is
+// killed so no stray readers linger.
+func (h *Headless) NewSession(name, workDir string) error {
+	return h.newSession(name, workDir, nil)
+}
+
+// NewSessionWithCommandAndEnv starts a session whose shell, started with env
+// added to its environment, execs command. The session ends when command
+// exits, and SendKeys input reaches command on its stdin.
+func (h *Headless) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	if err := h.newSession(name, workDir, env); err != nil {
+		return err
+	}
+	return h.SendKeys(name, execCommand(command))
+}
+
+func (h *Headless) newSession(name, workDir string, env map[string]string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if alive, err := h.HasSession(name); err != nil {
+		return err
+	} else if alive {
+		return fmt.Errorf("%w: %s", tmux.ErrSessionExists, name)
+	}
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return fmt.Errorf("creating headless state directory: %w", err)
+	}
+
+	inPath := h.inputPath(name)
+	if err := os.WriteFile(inPath, nil, 0600); err != nil {
+		return fmt.Errorf("creating input file: %w", err)
+	}
+	logFile, err := os.OpenFile(h.LogPath(name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command("sh", "-c", `tail -n +1 -f "$0" | sh; kill 0`, inPath)
+	cmd.Dir = workDir
+	if env != nil {
+		cmd.Env = environWith(env)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	setDetached(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting headless session %s: %w", name, err)
+	}
+	// Reap the child if this process outlives it (e.g. the daemon).
+	go func() { _ = cmd.Wait() }()
+
+	st := HeadlessState{
+		Name:      name,
+		PID:       cmd.Process.Pid,
+		WorkDir:   workDir,
+		StartedAt: time.Now().UTC(),
+		ProcStart: processStartTime(cmd.Process.Pid),
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := util.AtomicWriteFile(h.statePath(name), append(data, '\n'), 0644); err != nil {
+		killGroup(cmd.Process.Pid)
+		return fmt.Errorf("writing headless state: %w", err)
+	}
+	return nil
+}
+
+// SendKeys appends keys and a newline to the session's input.
+func (h *Headless) SendKeys(session, keys string) error {
+	alive, err := h.HasSession(session)
+	if err != nil {
+		return err
+	}
+	if !alive {
+		return fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, session)
+	}
+	f, err := os.OpenFile(h.inputPath(session), os.O_WRONLY|os.O_APPEND, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening input for %s: %w", session, err)
+	}
+	defer f.Close()
+	_, err = io.WriteString(f, keys+"\n")
+	return err
+}
+
+// KillSessionWithProcesses terminates the session's process group and
+// removes its state. The log is kept for post-mortems.
+func (h *Headless) KillSessionWithProcesses(name string) error {
+	st, err := h.readState(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, name)
+	}
+	if err != nil {
+		return err
+	}
+	if st.alive() {
+		killGroup(st.PID)
+	}
+	h.remove(name)
+	return nil
+}
+
+func (h *Headless) remove(name string) {
+	_ = os.Remove(h.statePath(name))
+	_ = os.Remove(h.inputPath(name))
+}
+
+// Reap removes state for sessions whose process has exited and returns
+// their names, so liveness checks stop reporting them and their agents can
+// be restarted by the usual lifecycle. Called periodically by the daemon.
+func (h *Headless) Reap() ([]string, error) {
+	entries, err := os.ReadDir(h.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var reaped []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		st, err := h.readState(name)
+		if err == nil && st.alive() {
+			continue
+		}
+		h.remove(name)
+		reaped = append(reaped, name)
+	}
+	sort.Strings(reaped)
+	return reaped, nil
+}
+
+// Capture returns the last lines of the session's log.
+func (h *Headless) Capture(name string, lines int) (string, error) {
+	data, err := os.ReadFile(h.LogPath(name)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	all := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if lines > 0 && len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.Join(all, "\n"), nil
+}
+
+// SessionActivity returns when the session last wrote to its log.
+func (h *Headless) SessionActivity(name string) (time.Time, error) {
+	info, err := os.Stat(h.LogPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, name)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// Attach streams the session log to stdout until the session exits.
+func (h *Headless) Attach(name string) error {
+	alive, err := h.HasSession(name)
+	if err != nil {
+		return err
+	}
+	if !alive {
+		return fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, name)
+	}
+	f, err := os.Open(h.LogPath(name)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening log for %s: %w", name, err)
+	}
+	defer f.Close()
+	for {
+		if _, err := io.Copy(os.Stdout, f); err != nil {
+			return err
+		}
+		if alive, _ := h.HasSession(name); !alive {
+			_, err := io.Copy(os.Stdout, f)
+			return err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,137 @@
+//go:build !windows
+
+package multiplexer
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestHeadlessLifecycle(t *testing.T) {
+	h := NewHeadless(t.TempDir())
+	workDir := t.TempDir()
+
+	if err := h.NewSession("gt-headless-test", workDir); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	t.Cleanup(func() { _ = h.KillSessionWithProcesses("gt-headless-test") })
+
+	if alive, err := h.HasSession("gt-headless-test"); err != nil || !alive {
+		t.Fatalf("HasSession = %v, %v; want true", alive, err)
+	}
+	if err := h.NewSession("gt-headless-test", workDir); !errors.Is(err, tmux.ErrSessionExists) {
+		t.Errorf("duplicate NewSession error = %v, want ErrSessionExists", err)
+	}
+	sessions, err := h.ListSessions()
+	if err != nil || len(sessions) != 1 || sessions[0] != "gt-headless-test" {
+		t.Errorf("ListSessions = %v, %v", sessions, err)
+	}
+
+	if err := h.SendKeys("gt-headless-test", "echo hello-$((40+2)); pwd"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitFor(t, "command output in log", func() bool {
+		data, _ := os.ReadFile(h.LogPath("gt-headless-test"))
+		return strings.Contains(string(data), "hello-42") && strings.Contains(string(data), workDir)
+	})
+
+	if err := h.KillSessionWithProcesses("gt-headless-test"); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	waitFor(t, "session to die", func() bool {
+		alive, _ := h.HasSession("gt-headless-test")
+		return !alive
+	})
+	if err := h.SendKeys("gt-headless-test", "echo x"); !errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Errorf("SendKeys after kill error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestHeadlessReap(t *testing.T) {
+	h := NewHeadless(t.TempDir())
+	if err := h.NewSession("gt-reap-test", t.TempDir()); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	t.Cleanup(func() { _ = h.KillSessionWithProcesses("gt-reap-test") })
+
+	if reaped, err := h.Reap(); err != nil || len(reaped) != 0 {
+		t.Fatalf("Reap (alive) = %v, %v; want none", reaped, err)
+	}
+
+	// The shell exiting on its own ends the session.
+	if err := h.SendKeys("gt-reap-test", "exit"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitFor(t, "session to exit", func() bool {
+		alive, _ := h.HasSession("gt-reap-test")
+		return !alive
+	})
+
+	reaped, err := h.Reap()
+	if err != nil || len(reaped) != 1 || reaped[0] != "gt-reap-test" {
+		t.Fatalf("Reap = %v, %v; want [gt-reap-test]", reaped, err)
+	}
+	if _, err := os.Stat(h.LogPath("gt-reap-test")); err != nil {
+		t.Errorf("log should survive reap: %v", err)
+	}
+}
+
+func TestHeadlessStateRecycledPID(t *testing.T) {
+	start := processStartTime(os.Getpid())
+	if start == 0 {
+		t.Skip("process start times not available on this platform")
+	}
+	st := HeadlessState{PID: os.Getpid(), ProcStart: start}
+	if !st.alive() {
+		t.Error("alive() = false for this process with its own start time")
+	}
+	st.ProcStart = start + 1
+	if st.alive() {
+		t.Error("alive() = true for a PID whose start time doesn't match")
+	}
+}
+
+func TestHeadlessCommandSession(t *testing.T) {
+	h := NewHeadless(t.TempDir())
+	env := map[string]string{"GT_HEADLESS_TEST": "from-env"}
+	if err := h.NewSessionWithCommandAndEnv("gt-cmd-test", t.TempDir(), `echo "$GT_HEADLESS_TEST" && echo second && read line`, env); err != nil {
+		t.Fatalf("NewSessionWithCommandAndEnv: %v", err)
+	}
+	t.Cleanup(func() { _ = h.KillSessionWithProcesses("gt-cmd-test") })
+
+	waitFor(t, "command output", func() bool {
+		out, _ := Capture(h, "gt-cmd-test", 1)
+		return out == "second"
+	})
+	if out, err := Capture(h, "gt-cmd-test", 0); err != nil || out != "from-env\nsecond" {
+		t.Errorf("Capture = %q, %v; want %q", out, err, "from-env\nsecond")
+	}
+	if last, err := Activity(h, "gt-cmd-test"); err != nil || time.Since(last) > time.Minute {
+		t.Errorf("Activity = %v, %v; want recent", last, err)
+	}
+
+	// The session ends with its command.
+	if err := h.SendKeys("gt-cmd-test", "done"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitFor(t, "session to end with its command", func() bool {
+		return !AgentAlive(h, "gt-cmd-test")
+	})
+}
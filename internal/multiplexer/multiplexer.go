@@ -0,0 +1,258 @@
+// Package multiplexer abstracts the terminal multiplexer that hosts agent
+// sessions. tmux is the default; zellij and GNU screen are available for
+// machines that don't run tmux, and headless mode runs agents as supervised
+// background processes with no multiplexer at all. The backend is chosen per
+// town with TownSettings.Multiplexer.
+package multiplexer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Backend names accepted in TownSettings.Multiplexer.
+const (
+	NameTmux     = "tmux"
+	NameZellij   = "zellij"
+	NameScreen   = "screen"
+	NameHeadless = "headless"
+)
+
+// ErrUnknown is returned by New for an unrecognized backend name.
+var ErrUnknown = errors.New("unknown multiplexer")
+
+// Multiplexer is the session-level surface gastown needs from a terminal
+// multiplexer. *tmux.Tmux implements it directly.
+type Multiplexer interface {
+	HasSession(name string) (bool, error)
+	NewSession(name, workDir string) error
+	// NewSessionWithCommandAndEnv creates a session running command, with
+	// env added to the environment it starts with. The session ends when
+	// command does.
+	NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error
+	// SendKeys types keys into the session followed by Enter.
+	SendKeys(session, keys string) error
+	// KillSessionWithProcesses ends the session and everything running in it.
+	KillSessionWithProcesses(name string) error
+	ListSessions() ([]string, error)
+}
+
+var _ Multiplexer = (*tmux.Tmux)(nil)
+
+// Attacher is implemented by backends that can connect the user to a
+// session. tmux does this via AttachSession; see Attach.
+type Attacher interface {
+	Attach(name string) error
+}
+
+// Attach connects the user to a session: a terminal attach for real
+// multiplexers, a log follow for headless sessions.
+func Attach(m Multiplexer, name string) error {
+	switch b := m.(type) {
+	case *tmux.Tmux:
+		return b.AttachSession(name)
+	case Attacher:
+		return b.Attach(name)
+	default:
+		return fmt.Errorf("%T does not support attach", m)
+	}
+}
+
+// Capturer is implemented by backends that can return a session's recent
+// output. *tmux.Tmux does this via CapturePane.
+type Capturer interface {
+	Capture(name string, lines int) (string, error)
+}
+
+// Capture returns the last lines of a session's output.
+func Capture(m Multiplexer, name string, lines int) (string, error) {
+	switch b := m.(type) {
+	case *tmux.Tmux:
+		return b.CapturePane(name, lines)
+	case Capturer:
+		return b.Capture(name, lines)
+	default:
+		return "", fmt.Errorf("%T does not support capturing output", m)
+	}
+}
+
+// AgentAlive reports whether the agent a session was started with is still
+// running. A tmux session can outlive its agent, leaving the pane's shell,
+// so tmux inspects the pane's processes; other backends end the session
+// with the agent.
+func AgentAlive(m Multiplexer, name string) bool {
+	if t, ok := m.(*tmux.Tmux); ok {
+		return t.IsAgentAlive(name)
+	}
+	alive, err := m.HasSession(name)
+	return err == nil && alive
+}
+
+// Nudge delivers a message to the agent in a session, through tmux's
+// NudgeSession (which waits out typing and paste detection) when the
+// session is in tmux.
+func Nudge(m Multiplexer, name, message string) error {
+	if t, ok := m.(*tmux.Tmux); ok {
+		return t.NudgeSession(name, message)
+	}
+	return m.SendKeys(name, message)
+}
+
+// ActivityReporter is implemented by backends that know when a session
+// last produced output. *tmux.Tmux does this via GetSessionActivity.
+type ActivityReporter interface {
+	SessionActivity(name string) (time.Time, error)
+}
+
+// Activity returns when a session last produced output.
+func Activity(m Multiplexer, name string) (time.Time, error) {
+	switch b := m.(type) {
+	case *tmux.Tmux:
+		return b.GetSessionActivity(name)
+	case ActivityReporter:
+		return b.SessionActivity(name)
+	default:
+		return time.Time{}, fmt.Errorf("%T does not report session activity", m)
+	}
+}
+
+// NewSessionWithSecrets starts a session running command, with env in the
+// session's environment and secrets in command's. tmux would carry env
+// values in its argv, where any local user can read them, so under tmux the
+// secrets reach command through Tmux.WithSecrets instead; other backends
+// pass both through the process environment.
+func NewSessionWithSecrets(m Multiplexer, name, workDir, command string, env, secrets map[string]string) error {
+	if t, ok := m.(*tmux.Tmux); ok {
+		wrapped, err := t.WithSecrets(command, secrets)
+		if err != nil {
+			return err
+		}
+		return t.NewSessionWithCommandAndEnv(name, workDir, wrapped, env)
+	}
+	all := make(map[string]string, len(env)+len(secrets))
+	maps.Copy(all, env)
+	maps.Copy(all, secrets)
+	return m.NewSessionWithCommandAndEnv(name, workDir, command, all)
+}
+
+// Health classifies a session like tmux's CheckSessionHealth. Outside tmux
+// the agent is the session, so AgentDead never occurs; AgentHung is
+// reported when maxInactivity is positive and the backend reports no
+// output for that long.
+func Health(m Multiplexer, name string, maxInactivity time.Duration) tmux.ZombieStatus {
+	if t, ok := m.(*tmux.Tmux); ok {
+		return t.CheckSessionHealth(name, maxInactivity)
+	}
+	if alive, err := m.HasSession(name); err != nil || !alive {
+		return tmux.SessionDead
+	}
+	if maxInactivity > 0 {
+		if last, err := Activity(m, name); err == nil && !last.IsZero() && time.Since(last) > maxInactivity {
+			return tmux.AgentHung
+		}
+	}
+	return tmux.SessionHealthy
+}
+
+// New returns the named backend. An empty name selects tmux. townRoot is
+// where headless sessions keep their state; other backends ignore it.
+func New(name, townRoot string) (Multiplexer, error) {
+	switch name {
+	case "", NameTmux:
+		return tmux.NewTmux(), nil
+	case NameZellij:
+		return NewZellij(), nil
+	case NameScreen:
+		return NewScreen(), nil
+	case NameHeadless:
+		return NewHeadless(townRoot), nil
+	default:
+		return nil, fmt.Errorf("%w %q (want %s, %s, %s, or %s)", ErrUnknown, name, NameTmux, NameZellij, NameScreen, NameHeadless)
+	}
+}
+
+// ForTown returns the backend configured in the town's settings/config.json.
+// Missing settings select tmux.
+func ForTown(townRoot string) (Multiplexer, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return New(settings.Multiplexer, townRoot)
+}
+
+// ForTownWith is ForTown for callers that already hold a *tmux.Tmux (a
+// remote rig's, or a test's): t is returned when the town uses tmux, and
+// also when its settings can't be read, since tmux is the default.
+func ForTownWith(townRoot string, t *tmux.Tmux) Multiplexer {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Multiplexer == "" || settings.Multiplexer == NameTmux {
+		return t
+	}
+	m, err := New(settings.Multiplexer, townRoot)
+	if err != nil {
+		return t
+	}
+	return m
+}
+
+// validSessionNameRe matches tmux's rule so names stay portable between backends.
+var validSessionNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func validateSessionName(name string) error {
+	if name == "" || !validSessionNameRe.MatchString(name) {
+		return fmt.Errorf("%w %q: must match %s", tmux.ErrInvalidSessionName, name, validSessionNameRe.String())
+	}
+	return nil
+}
+
+// hasSession reports whether name is among the sessions list returns.
+func hasSession(list func() ([]string, error), name string) (bool, error) {
+	sessions, err := list()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sessions {
+		if s == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// environWith returns this process's environment with env added, for
+// backends whose sessions inherit the environment of the command that
+// creates them.
+func environWith(env map[string]string) []string {
+	environ := os.Environ()
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		environ = append(environ, k+"="+env[k])
+	}
+	return environ
+}
+
+// execCommand returns a line that, typed into a session's shell, replaces
+// the shell with command. command may be a compound shell command, such as
+// a startup command prefixed with "export ... &&", so it runs under its own
+// sh rather than being passed to exec directly.
+func execCommand(command string) string {
+	return "exec sh -c " + config.ShellQuote(command)
+}
+
+// runInteractive runs a command attached to the user's terminal.
+func runInteractive(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
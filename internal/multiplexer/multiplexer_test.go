@@ -0,0 +1,114 @@
+package multiplexer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		want any
+	}{
+		{"", &tmux.Tmux{}},
+		{NameTmux, &tmux.Tmux{}},
+		{NameZellij, &Zellij{}},
+		{NameScreen, &Screen{}},
+		{NameHeadless, &Headless{}},
+	}
+	for _, tt := range tests {
+		got, err := New(tt.name, t.TempDir())
+		if err != nil {
+			t.Fatalf("New(%q): %v", tt.name, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+			t.Errorf("New(%q) = %T, want %T", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := New("byobu", ""); !errors.Is(err, ErrUnknown) {
+		t.Errorf("New(byobu) error = %v, want ErrUnknown", err)
+	}
+}
+
+func TestForTown(t *testing.T) {
+	townRoot := t.TempDir()
+	m, err := ForTown(townRoot)
+	if err != nil {
+		t.Fatalf("ForTown (no settings): %v", err)
+	}
+	if _, ok := m.(*tmux.Tmux); !ok {
+		t.Errorf("ForTown (no settings) = %T, want *tmux.Tmux", m)
+	}
+
+	path := filepath.Join(townRoot, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"type":"town-settings","version":1,"multiplexer":"zellij"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err = ForTown(townRoot)
+	if err != nil {
+		t.Fatalf("ForTown: %v", err)
+	}
+	if _, ok := m.(*Zellij); !ok {
+		t.Errorf("ForTown = %T, want *Zellij", m)
+	}
+}
+
+func TestForTownWith(t *testing.T) {
+	townRoot := t.TempDir()
+	own := tmux.NewTmux()
+	if m := ForTownWith(townRoot, own); m != Multiplexer(own) {
+		t.Errorf("ForTownWith (no settings) = %v, want the given tmux", m)
+	}
+
+	path := filepath.Join(townRoot, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"type":"town-settings","version":1,"multiplexer":"screen"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if m := ForTownWith(townRoot, own); reflect.TypeOf(m) != reflect.TypeOf(&Screen{}) {
+		t.Errorf("ForTownWith = %T, want *Screen", m)
+	}
+}
+
+func TestParseZellijSessions(t *testing.T) {
+	out := "gt-witness [Created 2h ago]\ngt-furiosa [Created 5m ago] (current)\nold [Created 1d ago] (EXITED - attach to resurrect)\n"
+	got := parseZellijSessions(out)
+	want := []string{"gt-witness", "gt-furiosa"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseZellijSessions = %v, want %v", got, want)
+	}
+}
+
+func TestParseScreenSessions(t *testing.T) {
+	out := "There are screens on:\n\t4242.gt-witness\t(10/14/2026 09:00:00 AM)\t(Detached)\n\t4343.gt-crew-max\t(Attached)\n2 Sockets in /run/screen/S-me.\n"
+	got := parseScreenSessions(out)
+	want := []string{"gt-witness", "gt-crew-max"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseScreenSessions = %v, want %v", got, want)
+	}
+}
+
+func TestScreenEscape(t *testing.T) {
+	if got := screenEscape(`echo $HOME ^C \n`); got != `echo \$HOME \^C \\n` {
+		t.Errorf("screenEscape = %q", got)
+	}
+}
+
+func TestNewSessionValidatesName(t *testing.T) {
+	for _, m := range []Multiplexer{NewZellij(), NewScreen()} {
+		if err := m.NewSession("bad.name", ""); !errors.Is(err, tmux.ErrInvalidSessionName) {
+			t.Errorf("%T.NewSession(bad.name) error = %v, want ErrInvalidSessionName", m, err)
+		}
+	}
+}
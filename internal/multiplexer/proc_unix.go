@@ -0,0 +1,55 @@
+//go:build !windows
+
+package multiplexer
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// setDetached puts cmd in its own process group so it survives the caller
+// and can be killed as a unit.
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// processAlive reports whether pid exists, via signal 0.
+func processAlive(pid int) bool {
+	return pid > 0 && syscall.Kill(pid, 0) == nil
+}
+
+// processStartTime returns pid's start time in clock ticks since boot, from
+// field 22 of /proc/<pid>/stat. It returns 0 where /proc is unavailable
+// (e.g. macOS) or the process is gone.
+func processStartTime(pid int) uint64 {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0
+	}
+	// comm (field 2) is parenthesised and may contain spaces; fields after
+	// it start at field 3.
+	i := strings.LastIndexByte(string(data), ')')
+	if i < 0 {
+		return 0
+	}
+	fields := strings.Fields(string(data[i+1:]))
+	if len(fields) < 20 {
+		return 0
+	}
+	start, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return start
+}
+
+// killGroup sends SIGTERM, then SIGKILL, to the process group led by pid.
+func killGroup(pid int) {
+	_ = syscall.Kill(-pid, syscall.SIGTERM)
+	time.Sleep(100 * time.Millisecond)
+	_ = syscall.Kill(-pid, syscall.SIGKILL)
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package multiplexer
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setDetached is a no-op on Windows; the process already runs independently.
+func setDetached(cmd *exec.Cmd) {}
+
+// processAlive reports whether pid exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(os.Signal(nil)) == nil
+}
+
+// processStartTime is not tracked on Windows; 0 means unknown.
+func processStartTime(pid int) uint64 { return 0 }
+
+// killGroup kills the process led by pid. Windows has no process groups in
+// the Unix sense, so children may survive.
+func killGroup(pid int) {
+	if p, err := os.FindProcess(pid); err == nil {
+		_ = p.Kill()
+	}
+}
@@ -0,0 +1,129 @@
+package multiplexer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Screen drives GNU screen. Sessions are addressed by their -S name.
+type Screen struct{}
+
+// NewScreen creates a GNU screen backend.
+func NewScreen() *Screen {
+	return &Screen{}
+}
+
+// run executes screen with dir as the working directory and returns stdout.
+func (s *Screen) run(dir string, args ...string) (string, error) {
+	return s.runEnv(dir, nil, args...)
+}
+
+// runEnv is run with env added to screen's environment, which new
+// sessions inherit.
+func (s *Screen) runEnv(dir string, env map[string]string, args ...string) (string, error) {
+	cmd := exec.Command("screen", args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = environWith(env)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg != "" {
+			return stdout.String(), fmt.Errorf("screen %s: %s", args[0], msg)
+		}
+		return stdout.String(), fmt.Errorf("screen %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// ListSessions returns the names of running screen sessions.
+func (s *Screen) ListSessions() ([]string, error) {
+	// screen -ls exits non-zero both when sessions exist and when there are
+	// none, so parse whatever it printed.
+	out, err := s.run("", "-ls")
+	if strings.Contains(out, "No Sockets found") {
+		return nil, nil
+	}
+	sessions := parseScreenSessions(out)
+	if len(sessions) == 0 && err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// parseScreenSessions extracts names from `screen -ls`, whose session lines
+// look like "\t12345.name\t(Detached)".
+func parseScreenSessions(out string) []string {
+	var sessions []string
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		_, name, ok := strings.Cut(fields[0], ".")
+		if !ok || name == "" {
+			continue
+		}
+		sessions = append(sessions, name)
+	}
+	return sessions
+}
+
+// HasSession checks if a session exists.
+func (s *Screen) HasSession(name string) (bool, error) {
+	return hasSession(s.ListSessions, name)
+}
+
+// NewSession creates a detached session rooted at workDir.
+func (s *Screen) NewSession(name, workDir string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	_, err := s.run(workDir, "-dmS", name)
+	return err
+}
+
+// NewSessionWithCommandAndEnv creates a detached session whose only window
+// runs command; screen ends the session when it exits.
+func (s *Screen) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	_, err := s.runEnv(workDir, env, "-dmS", name, "sh", "-c", command)
+	return err
+}
+
+// SendKeys stuffs keys into the session's input followed by Enter.
+func (s *Screen) SendKeys(session, keys string) error {
+	_, err := s.run("", "-S", session, "-p", "0", "-X", "stuff", screenEscape(keys)+"\r")
+	return err
+}
+
+// screenEscape protects characters that screen's stuff command would
+// otherwise interpret (backslash escapes, ^X control notation, $VAR).
+func screenEscape(keys string) string {
+	return strings.NewReplacer(`\`, `\\`, `^`, `\^`, `$`, `\$`).Replace(keys)
+}
+
+// KillSessionWithProcesses quits the session; screen sends SIGHUP to every
+// window's process group.
+func (s *Screen) KillSessionWithProcesses(name string) error {
+	_, err := s.run("", "-S", name, "-X", "quit")
+	return err
+}
+
+// Attach reattaches the current terminal to the session.
+func (s *Screen) Attach(name string) error {
+	return runInteractive("screen", "-r", name)
+}
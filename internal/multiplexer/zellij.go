@@ -0,0 +1,119 @@
+package multiplexer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Zellij drives zellij (0.39+) through its CLI.
+type Zellij struct{}
+
+// NewZellij creates a zellij backend.
+func NewZellij() *Zellij {
+	return &Zellij{}
+}
+
+// run executes zellij with dir as the working directory and returns stdout.
+func (z *Zellij) run(dir string, args ...string) (string, error) {
+	return z.runEnv(dir, nil, args...)
+}
+
+// runEnv is run with env added to zellij's environment, which a session
+// created by the command inherits.
+func (z *Zellij) runEnv(dir string, env map[string]string, args ...string) (string, error) {
+	cmd := exec.Command("zellij", args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = environWith(env)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("zellij %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("zellij %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// ListSessions returns the names of running zellij sessions. Exited
+// sessions that zellij keeps for resurrection are skipped.
+func (z *Zellij) ListSessions() ([]string, error) {
+	out, err := z.run("", "list-sessions", "--no-formatting")
+	if err != nil {
+		if strings.Contains(err.Error(), "No active zellij sessions") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseZellijSessions(out), nil
+}
+
+// parseZellijSessions extracts session names from `zellij list-sessions
+// --no-formatting`, whose lines look like "name [Created 1h ago]" with an
+// "(EXITED ...)" suffix for dead sessions.
+func parseZellijSessions(out string) []string {
+	var sessions []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.Contains(line, "EXITED") {
+			continue
+		}
+		sessions = append(sessions, fields[0])
+	}
+	return sessions
+}
+
+// HasSession checks if a session exists.
+func (z *Zellij) HasSession(name string) (bool, error) {
+	return hasSession(z.ListSessions, name)
+}
+
+// NewSession creates a detached session rooted at workDir.
+func (z *Zellij) NewSession(name, workDir string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	_, err := z.run(workDir, "attach", "--create-background", name)
+	return err
+}
+
+// NewSessionWithCommandAndEnv creates a detached session and replaces its
+// shell with command, so the session ends when command exits.
+func (z *Zellij) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if _, err := z.runEnv(workDir, env, "attach", "--create-background", name); err != nil {
+		return err
+	}
+	return z.SendKeys(name, execCommand(command))
+}
+
+// SendKeys writes keys literally into the session's focused pane, then Enter.
+func (z *Zellij) SendKeys(session, keys string) error {
+	if _, err := z.run("", "--session", session, "action", "write-chars", keys); err != nil {
+		return err
+	}
+	_, err := z.run("", "--session", session, "action", "write", "13")
+	return err
+}
+
+// KillSessionWithProcesses kills the session, which terminates its panes'
+// processes, and removes it from zellij's resurrection list.
+func (z *Zellij) KillSessionWithProcesses(name string) error {
+	if _, err := z.run("", "kill-session", name); err != nil {
+		return err
+	}
+	_, _ = z.run("", "delete-session", name)
+	return nil
+}
+
+// Attach attaches the current terminal to the session.
+func (z *Zellij) Attach(name string) error {
+	return runInteractive("zellij", "attach", name)
+}
@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BoundHook is a hook together with the plugin that declares it.
+type BoundHook struct {
+	Plugin *Plugin
+	Hook   Hook
+}
+
+// Name identifies the hook in output, e.g. "plugin:lint-check".
+func (b BoundHook) Name() string {
+	return "plugin:" + b.Plugin.Name
+}
+
+// ShellCommand returns the hook's command with GT_PLUGIN_DIR exported, for
+// callers that run it through their own executor.
+func (b BoundHook) ShellCommand() string {
+	return "export GT_PLUGIN_DIR=" + shellQuote(b.Plugin.Path) + "; " + b.Hook.Run
+}
+
+// HooksFor returns the hooks of enabled plugins registered at point, ordered
+// by plugin name and then declaration order.
+func HooksFor(plugins []*Plugin, point HookPoint) []BoundHook {
+	sorted := append([]*Plugin(nil), plugins...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var hooks []BoundHook
+	for _, p := range sorted {
+		if !p.Enabled {
+			continue
+		}
+		for _, h := range p.Hooks {
+			if h.On == point {
+				hooks = append(hooks, BoundHook{Plugin: p, Hook: h})
+			}
+		}
+	}
+	return hooks
+}
+
+// DiscoverHooks returns the hooks at point for rigName: those of town-level
+// plugins plus the rig's own (a rig plugin replaces a town plugin of the same
+// name). With an empty rigName only town-level plugins are considered.
+func DiscoverHooks(townRoot, rigName string, point HookPoint) ([]BoundHook, error) {
+	var rigNames []string
+	if rigName != "" {
+		rigNames = []string{rigName}
+	}
+	plugins, err := NewScanner(townRoot, rigNames).DiscoverAll()
+	if err != nil {
+		return nil, err
+	}
+	return HooksFor(plugins, point), nil
+}
+
+// RunHook runs one hook in dir with env added to the environment.
+func RunHook(ctx context.Context, b BoundHook, dir string, env []string) error {
+	timeout, err := b.Hook.TimeoutDuration()
+	if err != nil {
+		return err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", b.Hook.Run) //nolint:gosec // G204: hook commands come from operator-installed plugins
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GT_PLUGIN_DIR="+b.Plugin.Path), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Don't wait on grandchildren still holding stderr after a timeout kill.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if len(msg) > 500 {
+				msg = msg[:500] + "..."
+			}
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// RunHooks runs every hook at point for rigName, in order, reporting each to
+// out. Failures do not stop later hooks; the failures of required hooks are
+// returned joined, optional ones are only reported.
+func RunHooks(ctx context.Context, townRoot, rigName string, point HookPoint, dir string, env []string, out io.Writer) error {
+	hooks, err := DiscoverHooks(townRoot, rigName, point)
+	if err != nil {
+		return fmt.Errorf("discovering plugin hooks: %w", err)
+	}
+
+	var errs []error
+	for _, b := range hooks {
+		if err := RunHook(ctx, b, dir, env); err != nil {
+			if b.Hook.Optional {
+				_, _ = fmt.Fprintf(out, "  Warning: optional %s hook %s failed: %v\n", point, b.Name(), err)
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s hook %s: %w", point, b.Name(), err))
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "  Ran %s hook %s\n", point, b.Name())
+	}
+	return errors.Join(errs...)
+}
+
+// shellQuote single-quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,175 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// HookPoint names a lifecycle event at which plugin hooks run.
+type HookPoint string
+
+const (
+	// HookRigAdd runs in the new rig's directory after gt rig add.
+	HookRigAdd HookPoint = "rig-add"
+
+	// HookPolecatSpawn runs in a polecat's worktree after it is created.
+	HookPolecatSpawn HookPoint = "polecat-spawn"
+
+	// HookPolecatDone runs in a polecat's worktree when it finishes (gt done).
+	HookPolecatDone HookPoint = "polecat-done"
+
+	// HookPreMerge runs in the refinery worktree before a merge. A failing
+	// required hook rejects the merge.
+	HookPreMerge HookPoint = "pre-merge"
+
+	// HookPostMerge runs in the refinery worktree after a merge is pushed.
+	HookPostMerge HookPoint = "post-merge"
+)
+
+// HookPoints lists the valid hook points.
+var HookPoints = []HookPoint{HookRigAdd, HookPolecatSpawn, HookPolecatDone, HookPreMerge, HookPostMerge}
+
+// Hook is a [[hooks]] entry: a shell command run at a hook point.
+type Hook struct {
+	// On is the hook point.
+	On HookPoint `json:"on" toml:"on"`
+
+	// Run is the shell command. GT_PLUGIN_DIR is set to the plugin directory.
+	Run string `json:"run" toml:"run"`
+
+	// Timeout bounds the run (e.g., "2m"). Empty means no timeout.
+	Timeout string `json:"timeout,omitempty" toml:"timeout,omitempty"`
+
+	// Optional hooks only warn on failure. Only pre-merge hooks can block.
+	Optional bool `json:"optional,omitempty" toml:"optional,omitempty"`
+}
+
+// Command is a [[commands]] entry: a command the plugin contributes,
+// invoked with gt plugin exec <plugin> <name> [args...].
+type Command struct {
+	Name        string `json:"name" toml:"name"`
+	Description string `json:"description,omitempty" toml:"description,omitempty"`
+	Run         string `json:"run" toml:"run"`
+}
+
+// commandNamePattern restricts command names to something safe to type.
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Validate checks the plugin's commands and hooks. Gates are not validated
+// here; the Deacon interprets them.
+func (p *Plugin) Validate() error {
+	seen := make(map[string]bool, len(p.Commands))
+	for i, c := range p.Commands {
+		if !commandNamePattern.MatchString(c.Name) {
+			return fmt.Errorf("command %d: invalid name %q", i, c.Name)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("command %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Run == "" {
+			return fmt.Errorf("command %q: run is required", c.Name)
+		}
+	}
+	for i, h := range p.Hooks {
+		if !validHookPoint(h.On) {
+			return fmt.Errorf("hook %d: unknown hook point %q (valid: %v)", i, h.On, HookPoints)
+		}
+		if h.Run == "" {
+			return fmt.Errorf("hook %d (%s): run is required", i, h.On)
+		}
+		if _, err := h.TimeoutDuration(); err != nil {
+			return fmt.Errorf("hook %d (%s): %w", i, h.On, err)
+		}
+	}
+	if p.Patrol != nil {
+		if err := p.Patrol.validate(); err != nil {
+			return fmt.Errorf("patrol: %w", err)
+		}
+	}
+	return nil
+}
+
+func validHookPoint(point HookPoint) bool {
+	for _, p := range HookPoints {
+		if p == point {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeoutDuration parses the hook's timeout. Zero means none.
+func (h Hook) TimeoutDuration() (time.Duration, error) {
+	if h.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", h.Timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %v", d)
+	}
+	return d, nil
+}
+
+// FindCommand returns the contributed command with the given name.
+func (p *Plugin) FindCommand(name string) (*Command, bool) {
+	for i := range p.Commands {
+		if p.Commands[i].Name == name {
+			return &p.Commands[i], true
+		}
+	}
+	return nil, false
+}
+
+func (p *Plugin) commandNames() []string {
+	var names []string
+	for _, c := range p.Commands {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func (p *Plugin) hookPoints() []HookPoint {
+	var points []HookPoint
+	seen := make(map[HookPoint]bool)
+	for _, h := range p.Hooks {
+		if !seen[h.On] {
+			seen[h.On] = true
+			points = append(points, h.On)
+		}
+	}
+	return points
+}
+
+// disabledMarker is the file whose presence in a plugin directory disables
+// the plugin. Keeping the switch on disk next to the plugin means it is
+// discovered along with the plugin rather than tracked elsewhere.
+const disabledMarker = ".disabled"
+
+func isDisabled(pluginDir string) bool {
+	_, err := os.Stat(filepath.Join(pluginDir, disabledMarker))
+	return err == nil
+}
+
+// SetEnabled enables or disables the plugin. Disabled plugins are still
+// listed but their hooks and commands do not run and the Deacon skips them.
+func SetEnabled(p *Plugin, enabled bool) error {
+	marker := filepath.Join(p.Path, disabledMarker)
+	if enabled {
+		if err := os.Remove(marker); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("enabling plugin %s: %w", p.Name, err)
+		}
+	} else {
+		if err := os.WriteFile(marker, []byte("disabled by gt plugin disable\n"), 0644); err != nil {
+			return fmt.Errorf("disabling plugin %s: %w", p.Name, err)
+		}
+	}
+	p.Enabled = enabled
+	return nil
+}
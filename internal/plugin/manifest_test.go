@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePlugin(t *testing.T, dir, name, extra string) string {
+	t.Helper()
+	pluginDir := filepath.Join(dir, name)
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "+++\nname = \"" + name + "\"\n" + extra + "\n+++\n# " + name + "\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return pluginDir
+}
+
+func TestParsePluginMD_CommandsAndHooks(t *testing.T) {
+	content := []byte(`+++
+name = "tools"
+
+[[commands]]
+name = "status"
+description = "Show status"
+run = "echo ok"
+
+[[hooks]]
+on = "pre-merge"
+run = "make lint"
+timeout = "2m"
+
+[[hooks]]
+on = "rig-add"
+run = "./setup.sh"
+optional = true
++++
+`)
+	p, err := parsePluginMD(content, "/plugins/tools", LocationTown, "")
+	if err != nil {
+		t.Fatalf("parsePluginMD: %v", err)
+	}
+	if c, ok := p.FindCommand("status"); !ok || c.Run != "echo ok" {
+		t.Errorf("command not parsed: %+v", p.Commands)
+	}
+	if len(p.Hooks) != 2 || p.Hooks[0].On != HookPreMerge || !p.Hooks[1].Optional {
+		t.Errorf("hooks not parsed: %+v", p.Hooks)
+	}
+	s := p.Summary()
+	if len(s.HookPoints) != 2 || len(s.Commands) != 1 || !s.Enabled {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
+
+func TestParsePluginMD_InvalidManifest(t *testing.T) {
+	tests := map[string]string{
+		"unknown hook point": "[[hooks]]\non = \"pre-lunch\"\nrun = \"true\"",
+		"hook without run":   "[[hooks]]\non = \"rig-add\"",
+		"bad timeout":        "[[hooks]]\non = \"rig-add\"\nrun = \"true\"\ntimeout = \"soon\"",
+		"bad command name":   "[[commands]]\nname = \"Do It\"\nrun = \"true\"",
+		"duplicate command":  "[[commands]]\nname = \"a\"\nrun = \"true\"\n[[commands]]\nname = \"a\"\nrun = \"true\"",
+		"patrol no interval": "[patrol]\nexec = \"true\"",
+		"patrol no exec":     "[patrol]\ninterval = \"5m\"",
+		"patrol bad timeout": "[patrol]\ninterval = \"5m\"\nexec = \"true\"\ntimeout = \"-1s\"",
+	}
+	for name, extra := range tests {
+		t.Run(name, func(t *testing.T) {
+			content := []byte("+++\nname = \"bad\"\n" + extra + "\n+++\n")
+			if _, err := parsePluginMD(content, "/p", LocationTown, ""); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestParsePluginMD_Patrol(t *testing.T) {
+	content := []byte("+++\nname = \"disk-check\"\n[patrol]\ninterval = \"10m\"\nexec = \"./check.sh\"\n+++\n")
+	p, err := parsePluginMD(content, "/p", LocationTown, "")
+	if err != nil {
+		t.Fatalf("parsePluginMD: %v", err)
+	}
+	if p.PatrolName() != "disk-check" {
+		t.Errorf("PatrolName = %q, want plugin name", p.PatrolName())
+	}
+	if d, _ := p.Patrol.IntervalDuration(); d.Minutes() != 10 {
+		t.Errorf("interval = %v, want 10m", d)
+	}
+	if p.Summary().Patrol != "disk-check" {
+		t.Errorf("summary patrol = %q", p.Summary().Patrol)
+	}
+}
+
+func TestSetEnabled(t *testing.T) {
+	townRoot := t.TempDir()
+	writePlugin(t, filepath.Join(townRoot, "plugins"), "tools", "")
+	scanner := NewScanner(townRoot, nil)
+
+	p, err := scanner.GetPlugin("tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Enabled {
+		t.Fatal("plugin should start enabled")
+	}
+	if err := SetEnabled(p, false); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ = scanner.GetPlugin("tools"); p.Enabled {
+		t.Error("plugin should be disabled after SetEnabled(false)")
+	}
+	if err := SetEnabled(p, true); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ = scanner.GetPlugin("tools"); !p.Enabled {
+		t.Error("plugin should be enabled after SetEnabled(true)")
+	}
+}
+
+func TestDiscoverHooks(t *testing.T) {
+	townRoot := t.TempDir()
+	hook := "[[hooks]]\non = \"polecat-spawn\"\nrun = \"true\""
+	writePlugin(t, filepath.Join(townRoot, "plugins"), "b-town", hook)
+	writePlugin(t, filepath.Join(townRoot, "gastown", "plugins"), "a-rig", hook)
+	writePlugin(t, filepath.Join(townRoot, "other", "plugins"), "c-other", hook)
+	off := writePlugin(t, filepath.Join(townRoot, "plugins"), "d-off", hook)
+	if err := os.WriteFile(filepath.Join(off, disabledMarker), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := DiscoverHooks(townRoot, "gastown", HookPolecatSpawn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, h := range hooks {
+		names = append(names, h.Name())
+	}
+	if got := strings.Join(names, ","); got != "plugin:a-rig,plugin:b-town" {
+		t.Errorf("hooks = %s, want plugin:a-rig,plugin:b-town", got)
+	}
+}
+
+func TestRunHook(t *testing.T) {
+	dir := t.TempDir()
+	p := &Plugin{Name: "tools", Path: "/plugins/tools", Enabled: true}
+	out := filepath.Join(dir, "out")
+
+	b := BoundHook{Plugin: p, Hook: Hook{On: HookRigAdd, Run: `echo "$GT_PLUGIN_DIR $GT_RIG $(pwd)" > ` + out}}
+	if err := RunHook(context.Background(), b, dir, []string{"GT_RIG=gastown"}); err != nil {
+		t.Fatalf("RunHook: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if !strings.HasPrefix(string(data), "/plugins/tools gastown ") {
+		t.Errorf("hook output = %q", data)
+	}
+
+	b.Hook = Hook{On: HookRigAdd, Run: "sleep 10", Timeout: "50ms"}
+	if err := RunHook(context.Background(), b, dir, nil); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"fmt"
+	"time"
+)
+
+// Patrol is a plugin's [patrol] section: a command the daemon runs on a fixed
+// interval alongside the built-in deacon, witness, and refinery patrols.
+//
+// The daemon only runs executables. A WASM module is run by pointing Exec at
+// a runtime, e.g. exec = "wasmtime run $GT_PLUGIN_DIR/patrol.wasm".
+type Patrol struct {
+	// Name identifies the patrol in mayor/daemon.json and the events log.
+	// Defaults to the plugin name.
+	Name string `json:"name,omitempty" toml:"name,omitempty"`
+
+	// Interval is how often the patrol runs (e.g., "10m").
+	Interval string `json:"interval" toml:"interval"`
+
+	// Exec is the shell command to run. GT_PLUGIN_DIR is set to the plugin
+	// directory.
+	Exec string `json:"exec" toml:"exec"`
+
+	// Timeout bounds one run (e.g., "2m"). Defaults to the interval.
+	Timeout string `json:"timeout,omitempty" toml:"timeout,omitempty"`
+}
+
+func (p *Patrol) validate() error {
+	if p.Name != "" && !commandNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid name %q", p.Name)
+	}
+	if p.Exec == "" {
+		return fmt.Errorf("exec is required")
+	}
+	if _, err := p.IntervalDuration(); err != nil {
+		return err
+	}
+	if _, err := p.TimeoutDuration(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IntervalDuration parses the patrol interval.
+func (p *Patrol) IntervalDuration() (time.Duration, error) {
+	if p.Interval == "" {
+		return 0, fmt.Errorf("interval is required")
+	}
+	d, err := time.ParseDuration(p.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", p.Interval, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("interval must be positive, got %v", d)
+	}
+	return d, nil
+}
+
+// TimeoutDuration parses the patrol timeout. Zero means the interval applies.
+func (p *Patrol) TimeoutDuration() (time.Duration, error) {
+	if p.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", p.Timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %v", d)
+	}
+	return d, nil
+}
+
+// PatrolName returns the name of the plugin's patrol, or "" if it has none.
+func (p *Plugin) PatrolName() string {
+	if p.Patrol == nil {
+		return ""
+	}
+	if p.Patrol.Name != "" {
+		return p.Patrol.Name
+	}
+	return p.Name
+}
@@ -184,9 +184,17 @@ func parsePluginMD(content []byte, pluginDir string, location Location, rigName
 		Gate:         fm.Gate,
 		Tracking:     fm.Tracking,
 		Execution:    fm.Execution,
+		Commands:     fm.Commands,
+		Hooks:        fm.Hooks,
+		Patrol:       fm.Patrol,
+		Enabled:      !isDisabled(pluginDir),
 		Instructions: body,
 	}
 
+	if err := plugin.Validate(); err != nil {
+		return nil, err
+	}
+
 	return plugin, nil
 }
 
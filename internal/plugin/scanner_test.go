@@ -462,3 +462,25 @@ version = 1
 		t.Errorf("expected location 'rig', got %q", plugins[0].Location)
 	}
 }
+
+func TestParsePluginMD_CIStatus(t *testing.T) {
+	// The shipped ci-status plugin is a patrol-only plugin.
+	content, err := os.ReadFile(filepath.Join("..", "..", "plugins", "ci-status", "plugin.md"))
+	if err != nil {
+		t.Skipf("ci-status plugin not found (expected in plugins/): %v", err)
+	}
+
+	plugin, err := parsePluginMD(content, "/test/ci-status", LocationTown, "")
+	if err != nil {
+		t.Fatalf("parsePluginMD failed: %v", err)
+	}
+	if plugin.PatrolName() != "ci-status" {
+		t.Errorf("patrol name = %q, want ci-status", plugin.PatrolName())
+	}
+	if plugin.Patrol.Exec != "gt witness ci-check --all" {
+		t.Errorf("patrol exec = %q", plugin.Patrol.Exec)
+	}
+	if plugin.Gate == nil || plugin.Gate.Type != GateManual {
+		t.Errorf("gate = %+v, want manual so dogs never run it", plugin.Gate)
+	}
+}
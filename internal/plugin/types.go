@@ -39,6 +39,18 @@ type Plugin struct {
 	// Execution defines timeout and notification settings.
 	Execution *Execution `json:"execution,omitempty"`
 
+	// Commands are shell commands the plugin contributes (gt plugin exec).
+	Commands []Command `json:"commands,omitempty"`
+
+	// Hooks run the plugin's scripts at lifecycle hook points.
+	Hooks []Hook `json:"hooks,omitempty"`
+
+	// Patrol registers a custom patrol the daemon runs on an interval.
+	Patrol *Patrol `json:"patrol,omitempty"`
+
+	// Enabled is false when the plugin was disabled with gt plugin disable.
+	Enabled bool `json:"enabled"`
+
 	// Instructions is the markdown body (after frontmatter).
 	Instructions string `json:"instructions,omitempty"`
 }
@@ -121,16 +133,23 @@ type PluginFrontmatter struct {
 	Gate        *Gate      `toml:"gate,omitempty"`
 	Tracking    *Tracking  `toml:"tracking,omitempty"`
 	Execution   *Execution `toml:"execution,omitempty"`
+	Commands    []Command  `toml:"commands,omitempty"`
+	Hooks       []Hook     `toml:"hooks,omitempty"`
+	Patrol      *Patrol    `toml:"patrol,omitempty"`
 }
 
 // PluginSummary provides a concise overview of a plugin.
 type PluginSummary struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Location    Location `json:"location"`
-	RigName     string   `json:"rig_name,omitempty"`
-	GateType    GateType `json:"gate_type,omitempty"`
-	Path        string   `json:"path"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Location    Location    `json:"location"`
+	RigName     string      `json:"rig_name,omitempty"`
+	GateType    GateType    `json:"gate_type,omitempty"`
+	Path        string      `json:"path"`
+	Enabled     bool        `json:"enabled"`
+	Commands    []string    `json:"commands,omitempty"`
+	HookPoints  []HookPoint `json:"hook_points,omitempty"`
+	Patrol      string      `json:"patrol,omitempty"`
 }
 
 // Summary returns a PluginSummary for this plugin.
@@ -149,6 +168,10 @@ func (p *Plugin) Summary() PluginSummary {
 		RigName:     p.RigName,
 		GateType:    gateType,
 		Path:        p.Path,
+		Enabled:     p.Enabled,
+		Commands:    p.commandNames(),
+		HookPoints:  p.hookPoints(),
+		Patrol:      p.PatrolName(),
 	}
 }
 
@@ -0,0 +1,29 @@
+package polecat
+
+import (
+	"os/exec"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// containerConfig returns the rig's polecat container settings, or nil when
+// the rig has no settings file or the sandbox is not configured.
+func (m *SessionManager) containerConfig() *config.ContainerConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || !settings.Container.IsEnabled() {
+		return nil
+	}
+	return settings.Container
+}
+
+// removeContainer force-removes the polecat's container after its session is
+// killed. Killing the pane only kills the runtime client; the container
+// itself would otherwise keep running. Best-effort: the container is usually
+// already gone via --rm.
+func (m *SessionManager) removeContainer(sessionID string) {
+	c := m.containerConfig()
+	if c == nil {
+		return
+	}
+	_ = exec.Command(c.RuntimeName(), "rm", "-f", sessionID).Run() //nolint:gosec // G204: runtime is validated, name is a session ID
+}
@@ -3,6 +3,7 @@
 package polecat
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand/v2"
@@ -19,13 +20,17 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/errclass"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Retry constants for Dolt operations (matching hook update pattern in sling.go).
@@ -55,19 +60,12 @@ func doltBackoff(attempt int) time.Duration {
 	return result
 }
 
-// isDoltOptimisticLockError returns true if the error is an optimistic lock / serialization failure.
-// These indicate transient write conflicts from concurrent Dolt operations — worth retrying.
+// isDoltOptimisticLockError returns true if the error is a write conflict or
+// read-only refusal from concurrent Dolt operations (see errclass): Dolt is
+// up but busy, so it is worth retrying.
 func isDoltOptimisticLockError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := err.Error()
-	return strings.Contains(msg, "optimistic lock") ||
-		strings.Contains(msg, "serialization failure") ||
-		strings.Contains(msg, "lock wait timeout") ||
-		strings.Contains(msg, "try restarting transaction") ||
-		strings.Contains(msg, "database is read only") ||
-		strings.Contains(msg, "cannot update manifest")
+	c := errclass.Classify(err)
+	return c == errclass.Retryable || c == errclass.ReadOnly
 }
 
 // isDoltConfigError returns true if the error indicates a configuration or initialization
@@ -121,6 +119,7 @@ type Manager struct {
 	beads    *beads.Beads
 	namePool *NamePool
 	tmux     *tmux.Tmux
+	mux      multiplexer.Multiplexer // hosts sessions; nil when t is
 }
 
 // NewManager creates a new polecat manager.
@@ -133,30 +132,30 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 
 	// Try to load rig settings for namepool config
 	settingsPath := filepath.Join(r.Path, "settings", "config.json")
-	var pool *NamePool
-
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err == nil && settings.Namepool != nil {
-		// Use configured namepool settings
-		pool = NewNamePoolWithConfig(
-			r.Path,
-			r.Name,
-			settings.Namepool.Style,
-			settings.Namepool.Names,
-			settings.Namepool.MaxBeforeNumbering,
-		)
-	} else {
-		// Use defaults
-		pool = NewNamePool(r.Path, r.Name)
+	var namepoolConfig *config.NamepoolConfig
+	if settings, err := config.LoadRigSettings(settingsPath); err == nil {
+		namepoolConfig = settings.Namepool
 	}
+	pool := NewNamePoolFromSettings(r.Path, r.Name, namepoolConfig)
 	_ = pool.Load() // non-fatal: state file may not exist for new rigs
 
+	// Remote rigs keep their clones and sessions on the rig's host.
+	var mux multiplexer.Multiplexer
+	if r.IsRemote() {
+		g = r.HostGit(g)
+		t = r.Tmux()
+		mux = t
+	} else if t != nil {
+		mux = multiplexer.ForTownWith(filepath.Dir(r.Path), t)
+	}
+
 	return &Manager{
 		rig:      r,
 		git:      g,
 		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
 		namePool: pool,
 		tmux:     t,
+		mux:      mux,
 	}
 }
 
@@ -415,7 +414,7 @@ func (m *Manager) repoBase() (*git.Git, error) {
 	bareRepoPath := filepath.Join(m.rig.Path, ".repo.git")
 	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
 		// Bare repo exists - use it
-		return git.NewGitWithDir(bareRepoPath, ""), nil
+		return m.rig.HostGit(git.NewGitWithDir(bareRepoPath, "")), nil
 	}
 
 	// Fall back to mayor/rig (legacy architecture)
@@ -423,7 +422,7 @@ func (m *Manager) repoBase() (*git.Git, error) {
 	if _, err := os.Stat(mayorPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("no repo base found (neither .repo.git nor mayor/rig exists)")
 	}
-	return git.NewGit(mayorPath), nil
+	return m.rig.Git(mayorPath), nil
 }
 
 // polecatDir returns the parent directory for a polecat.
@@ -601,7 +600,11 @@ func (m *Manager) Add(name string) (*Polecat, error) {
 // AddWithOptions creates a new polecat with the specified options.
 // This allows setting hook_bead atomically at creation time, avoiding
 // cross-beads routing issues when slinging work to new polecats.
-func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error) {
+func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, err error) {
+	_, span := telemetry.Start(context.Background(), "polecat.add",
+		attribute.String("polecat.rig", m.rig.Name), attribute.String("polecat.name", name))
+	defer func() { telemetry.End(span, err) }()
+
 	// Acquire per-polecat file lock to prevent concurrent Add/Remove/Repair races
 	fl, err := m.lockPolecat(name)
 	if err != nil {
@@ -700,6 +703,11 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 			startPoint, m.rig.Path, filepath.Join(m.rig.Path, ".repo.git"))
 	}
 
+	// Pick up submodule/LFS settings edited since the rig was added
+	if err := rig.SyncCheckoutOptions(m.rig.Path); err != nil {
+		style.PrintWarning("could not apply rig checkout options: %v", err)
+	}
+
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
@@ -708,6 +716,9 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
 	worktreeCreated = true
+	if err := rig.ApplyIdentity(m.rig.Path, "polecat", name, clonePath, true); err != nil {
+		style.PrintWarning("could not set git identity: %v", err)
+	}
 
 	// NOTE: No per-directory CLAUDE.md or AGENTS.md is created here.
 	// Only ~/gt/CLAUDE.md (town-root identity anchor) exists on disk.
@@ -838,7 +849,7 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 			}
 		} else {
 			// Fallback path: Check git directly (for polecats that haven't reported yet)
-			polecatGit := git.NewGit(clonePath)
+			polecatGit := m.rig.Git(clonePath)
 			status, err := polecatGit.CheckUncommittedWork()
 			if err == nil && !status.Clean() {
 				// For backward compatibility: force only bypasses uncommitted changes, not stashes/unpushed
@@ -920,7 +931,7 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear, selfNuke bool)
 		}
 		mayorRigPath := filepath.Join(m.rig.Path, "mayor", "rig")
 		if info, statErr := os.Stat(mayorRigPath); statErr == nil && info.IsDir() {
-			mayorGit := git.NewGit(mayorRigPath)
+			mayorGit := m.rig.Git(mayorRigPath)
 			_ = mayorGit.WorktreePrune()
 		}
 		// Fall back to direct removal if repo base not found
@@ -1068,10 +1079,10 @@ func (m *Manager) AllocateName() (string, error) {
 	// can be allocated after its directory was cleaned up while the tmux session
 	// lingers (race between cleanup and allocation). This extra check ensures
 	// no stale session blocks the new polecat's session creation.
-	if m.tmux != nil {
+	if m.mux != nil {
 		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
-		if alive, _ := m.tmux.HasSession(sessionName); alive {
-			_ = m.tmux.KillSessionWithProcesses(sessionName)
+		if alive, _ := m.mux.HasSession(sessionName); alive {
+			_ = m.mux.KillSessionWithProcesses(sessionName)
 		}
 	}
 
@@ -1085,6 +1096,92 @@ func (m *Manager) ReleaseName(name string) {
 	_ = m.namePool.Save() // non-fatal: state file update
 }
 
+// Rename gives an idle polecat a new name: its directory and worktree move
+// to the new name, its agent bead is replaced, and the old name returns to
+// the pool. Polecats with a running session or assigned work are refused,
+// since their session, branch and assignee all carry the old name.
+func (m *Manager) Rename(oldName, newName string) error {
+	if err := ValidateName(newName); err != nil {
+		return err
+	}
+	if oldName == newName {
+		return fmt.Errorf("polecat %s already has that name", oldName)
+	}
+	if m.rig.IsRemote() {
+		return fmt.Errorf("renaming polecats of remote rig %s is not supported", m.rig.Name)
+	}
+
+	// Lock both names in a fixed order so two renames can't deadlock.
+	first, second := oldName, newName
+	if second < first {
+		first, second = second, first
+	}
+	for _, name := range []string{first, second} {
+		fl, err := m.lockPolecat(name)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = fl.Unlock() }()
+	}
+
+	if !m.exists(oldName) {
+		return ErrPolecatNotFound
+	}
+	if m.exists(newName) {
+		return fmt.Errorf("%w: %s", ErrPolecatExists, newName)
+	}
+	if _, err := os.Stat(m.pendingPath(newName)); err == nil {
+		return fmt.Errorf("name %s is reserved by a polecat being spawned", newName)
+	}
+	if m.mux != nil {
+		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), oldName)
+		if running, _ := m.mux.HasSession(sessionName); running {
+			return fmt.Errorf("polecat %s has a running session (%s); stop it first", oldName, sessionName)
+		}
+	}
+	p, err := m.loadFromBeads(oldName)
+	if err != nil {
+		return err
+	}
+	if p.Issue != "" {
+		return fmt.Errorf("polecat %s has work assigned (%s); rename only idle polecats", oldName, p.Issue)
+	}
+
+	if err := os.Rename(m.polecatDir(oldName), m.polecatDir(newName)); err != nil {
+		return fmt.Errorf("moving polecat directory: %w", err)
+	}
+	if repoGit, err := m.repoBase(); err == nil {
+		if err := repoGit.WorktreeRepair(m.clonePath(newName)); err != nil {
+			style.PrintWarning("could not re-link worktree for %s: %v", newName, err)
+		}
+	}
+
+	// Carry the agent bead's self-reported state over to the new identity.
+	oldID := m.agentBeadID(oldName)
+	fields := &beads.AgentFields{RoleType: "polecat", Rig: m.rig.Name, AgentState: "idle"}
+	if _, old, err := m.beads.GetAgentBead(oldID); err == nil && old != nil {
+		fields.AgentState = old.AgentState
+		fields.CleanupStatus = old.CleanupStatus
+		fields.NotificationLevel = old.NotificationLevel
+	}
+	if err := m.createAgentBeadWithRetry(m.agentBeadID(newName), fields); err != nil {
+		style.PrintWarning("could not create agent bead for %s: %v", newName, err)
+	}
+	if err := m.beads.ResetAgentBeadForReuse(oldID, "polecat renamed to "+newName); err != nil && !errors.Is(err, beads.ErrNotFound) {
+		style.PrintWarning("could not reset agent bead %s: %v", oldID, err)
+	}
+
+	fl, err := m.lockPool()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+	m.namePool.Release(oldName)
+	m.namePool.MarkInUse(newName)
+	_ = m.namePool.Save() // non-fatal: state file update
+	return nil
+}
+
 // RepairWorktree repairs a stale polecat by removing it and creating a fresh worktree.
 // This is NOT for normal operation - it handles reconciliation when AllocateName
 // returns a name that unexpectedly already exists (stale state recovery).
@@ -1117,7 +1214,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 
 	// Get the old clone path (may be old or new structure)
 	oldClonePath := m.clonePath(name)
-	polecatGit := git.NewGit(oldClonePath)
+	polecatGit := m.rig.Git(oldClonePath)
 
 	// New clone path uses new structure
 	polecatDir := m.polecatDir(name)
@@ -1178,6 +1275,9 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	if err := repoGit.WorktreeAddFromRef(tmpClonePath, branchName, startPoint); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
+	if err := rig.ApplyIdentity(m.rig.Path, "polecat", name, tmpClonePath, true); err != nil {
+		style.PrintWarning("could not set git identity: %v", err)
+	}
 
 	// New worktree created successfully — now safe to remove old worktree and reset bead.
 	// Remove old worktree BEFORE resetting bead to prevent name collision if a new
@@ -1308,11 +1408,11 @@ func (m *Manager) reconcilePoolInternal() {
 
 	// Get names with tmux sessions
 	var namesWithSessions []string
-	if m.tmux != nil {
+	if m.mux != nil {
 		poolNames := m.namePool.getNames()
 		for _, name := range poolNames {
 			sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
-			hasSession, _ := m.tmux.HasSession(sessionName)
+			hasSession, _ := m.mux.HasSession(sessionName)
 			if hasSession {
 				namesWithSessions = append(namesWithSessions, name)
 			}
@@ -1345,15 +1445,15 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 	// - No directory: orphan session, always kill (worktree was removed but tmux lingered)
 	// - Has directory but dead process: stale session from crashed startup (gt-jn40ft)
 	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-	if m.tmux != nil {
+	if m.mux != nil {
 		for _, name := range namesWithSessions {
 			sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
 			if !dirSet[name] {
 				// Orphan: session exists but no directory
-				_ = m.tmux.KillSessionWithProcesses(sessionName)
-			} else if isSessionProcessDead(m.tmux, sessionName) {
+				_ = m.mux.KillSessionWithProcesses(sessionName)
+			} else if m.sessionProcessDead(sessionName) {
 				// Stale: directory exists but session's process has died
-				_ = m.tmux.KillSessionWithProcesses(sessionName)
+				_ = m.mux.KillSessionWithProcesses(sessionName)
 			}
 		}
 	}
@@ -1365,6 +1465,16 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 	m.cleanupOrphanPolecatState()
 }
 
+// sessionProcessDead reports whether a session outlived its agent. Only
+// tmux keeps a session after its process exits; other backends end the
+// session with it.
+func (m *Manager) sessionProcessDead(sessionName string) bool {
+	if _, ok := m.mux.(*tmux.Tmux); !ok {
+		return false
+	}
+	return isSessionProcessDead(m.tmux, sessionName)
+}
+
 // isSessionProcessDead checks if a tmux session's pane process has exited.
 // Returns true only when we can confirm the process is dead, not on transient
 // tmux query failures (gt-kncti: permission denied false positives).
@@ -1628,7 +1738,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	clonePath := m.clonePath(name)
 
 	// Get actual branch from worktree (branches are now timestamped)
-	polecatGit := git.NewGit(clonePath)
+	polecatGit := m.rig.Git(clonePath)
 	branchName, err := polecatGit.CurrentBranch()
 	if err != nil {
 		// Fall back to old format if we can't read the branch
@@ -1679,9 +1789,9 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	if issue != nil {
 		issueID = issue.ID
 		state = StateWorking
-	} else if m.tmux != nil {
+	} else if m.mux != nil {
 		sessionName := session.PolecatSessionName(session.PrefixFor(m.rig.Name), name)
-		if running, _ := m.tmux.HasSession(sessionName); running {
+		if running, _ := m.mux.HasSession(sessionName); running {
 			state = StateWorking
 		}
 	}
@@ -1800,7 +1910,7 @@ func (m *Manager) DetectStalePolecats(threshold int) ([]*StalenessInfo, error) {
 		info.HasActiveSession = checkTmuxSession(sessionName)
 
 		// Check how far behind main
-		polecatGit := git.NewGit(p.ClonePath)
+		polecatGit := m.rig.Git(p.ClonePath)
 		info.CommitsBehind = countCommitsBehind(polecatGit, defaultBranch)
 
 		// Check for uncommitted work (excluding .beads/ files which are synced across worktrees)
@@ -1,6 +1,7 @@
 package polecat
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -200,6 +201,35 @@ func TestRemoveNotFound(t *testing.T) {
 	}
 }
 
+func TestRenameRefusals(t *testing.T) {
+	root := t.TempDir()
+	r := &rig.Rig{
+		Name: "test-rig",
+		Path: root,
+	}
+	m := NewManager(r, git.NewGit(root), nil)
+	for _, name := range []string{"Toast", "Nux"} {
+		if err := os.MkdirAll(m.polecatDir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.Rename("Ghost", "Furiosa"); !errors.Is(err, ErrPolecatNotFound) {
+		t.Errorf("Rename(missing) = %v, want ErrPolecatNotFound", err)
+	}
+	if err := m.Rename("Toast", "Nux"); !errors.Is(err, ErrPolecatExists) {
+		t.Errorf("Rename(onto existing) = %v, want ErrPolecatExists", err)
+	}
+	for _, bad := range []string{"witness", "crew-max", "bad/name", ""} {
+		if err := m.Rename("Toast", bad); err == nil {
+			t.Errorf("Rename(Toast, %q) succeeded, want invalid name error", bad)
+		}
+	}
+	if !m.exists("Toast") {
+		t.Error("refused renames must leave the polecat in place")
+	}
+}
+
 func TestPolecatDir(t *testing.T) {
 	r := &rig.Rig{
 		Name: "test-rig",
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strings"
 	"sync"
 
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -107,6 +111,19 @@ type NamePool struct {
 	// MaxSize is the maximum number of themed names before overflow.
 	MaxSize int `json:"max_size"`
 
+	// Prefix and Suffix wrap every allocated name. Names without them (from
+	// before the pattern was set) are still recognized as pool names.
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+
+	// ReuseCooldown is how many released names Recent holds back from
+	// allocation while other names are free.
+	ReuseCooldown int `json:"reuse_cooldown,omitempty"`
+
+	// Recent lists released names, oldest first. Unlike InUse it is
+	// persisted, so the cooldown survives restarts.
+	Recent []string `json:"recent,omitempty"`
+
 	// stateFile is the path to persist pool state.
 	stateFile string
 }
@@ -143,6 +160,65 @@ func NewNamePoolWithConfig(rigPath, rigName, theme string, customNames []string,
 	}
 }
 
+// NewNamePoolFromSettings creates a name pool from a rig's namepool
+// settings, or with defaults when cfg is nil. An invalid prefix or suffix
+// is ignored with a warning.
+func NewNamePoolFromSettings(rigPath, rigName string, cfg *config.NamepoolConfig) *NamePool {
+	if cfg == nil {
+		return NewNamePool(rigPath, rigName)
+	}
+	pool := NewNamePoolWithConfig(rigPath, rigName, cfg.Style, cfg.Names, cfg.MaxBeforeNumbering)
+	if err := ValidateNamePattern(cfg.Prefix, cfg.Suffix); err != nil {
+		style.PrintWarning("ignoring namepool pattern for %s: %v", rigName, err)
+	} else {
+		pool.Prefix, pool.Suffix = cfg.Prefix, cfg.Suffix
+	}
+	pool.ReuseCooldown = cfg.ReuseCooldown
+	return pool
+}
+
+// validName matches names usable as polecat directory, branch and session
+// name components.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateName checks that name can be used for a polecat.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid polecat name %q: use letters, digits, '-' and '_', starting with a letter or digit", name)
+	}
+	if ReservedInfraAgentNames[name] {
+		return fmt.Errorf("polecat name %q is reserved for an infrastructure agent", name)
+	}
+	// Session names are <prefix>-<name>; <prefix>-crew-* parses as crew.
+	if strings.HasPrefix(name, "crew-") {
+		return fmt.Errorf("polecat name %q would be mistaken for a crew session", name)
+	}
+	return nil
+}
+
+// ValidateNamePattern checks that prefix and suffix yield valid names.
+func ValidateNamePattern(prefix, suffix string) error {
+	if prefix == "" && suffix == "" {
+		return nil
+	}
+	return ValidateName(prefix + "name" + suffix)
+}
+
+// decorate applies the pool's prefix and suffix to a base name.
+func (p *NamePool) decorate(base string) string {
+	return p.Prefix + base + p.Suffix
+}
+
+// baseName strips the pool's prefix and suffix from name, if present.
+func (p *NamePool) baseName(name string) string {
+	if (p.Prefix != "" || p.Suffix != "") &&
+		len(name) > len(p.Prefix)+len(p.Suffix) &&
+		strings.HasPrefix(name, p.Prefix) && strings.HasSuffix(name, p.Suffix) {
+		return name[len(p.Prefix) : len(name)-len(p.Suffix)]
+	}
+	return name
+}
+
 // getNames returns the list of names to use for the pool.
 // Reserved infrastructure agent names are filtered out.
 func (p *NamePool) getNames() []string {
@@ -199,6 +275,7 @@ func (p *NamePool) Load() error {
 	}
 
 	p.InUse = make(map[string]bool)
+	p.Recent = loaded.RecentNames
 
 	p.OverflowNext = loaded.OverflowNext
 	if p.OverflowNext < p.MaxSize+1 {
@@ -214,13 +291,14 @@ func (p *NamePool) Load() error {
 // namePoolState is the subset of NamePool that is persisted to the state file.
 // Only runtime state is saved, not configuration (Theme, CustomNames come from settings).
 type namePoolState struct {
-	RigName      string `json:"rig_name"`
-	OverflowNext int    `json:"overflow_next"`
-	MaxSize      int    `json:"max_size"`
+	RigName      string   `json:"rig_name"`
+	OverflowNext int      `json:"overflow_next"`
+	MaxSize      int      `json:"max_size"`
+	RecentNames  []string `json:"recent_names,omitempty"`
 }
 
 // Save persists the pool state to disk using atomic write.
-// Only runtime state (OverflowNext, MaxSize) is saved - configuration like
+// Only runtime state (OverflowNext, MaxSize, Recent) is saved - configuration like
 // Theme and CustomNames come from settings/config.json and are not persisted here.
 func (p *NamePool) Save() error {
 	p.mu.RLock()
@@ -236,35 +314,54 @@ func (p *NamePool) Save() error {
 		RigName:      p.RigName,
 		OverflowNext: p.OverflowNext,
 		MaxSize:      p.MaxSize,
+		RecentNames:  p.Recent,
 	}
 
 	return util.AtomicWriteJSON(p.stateFile, state)
 }
 
 // Allocate returns a name from the pool.
-// It prefers names in order from the theme list, and falls back to overflow names
-// when the pool is exhausted.
+// It prefers names in order from the theme list, skipping recently released
+// names while others are free, and falls back to overflow names when the
+// pool is exhausted.
 func (p *NamePool) Allocate() (string, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	names := p.getNames()
+	recent := make(map[string]bool, len(p.Recent))
+	for _, name := range p.Recent {
+		recent[name] = true
+	}
 
-	// Try to find first available name from the theme
-	for i := 0; i < len(names) && i < p.MaxSize; i++ {
-		name := names[i]
-		if !p.InUse[name] {
-			p.InUse[name] = true
-			return name, nil
+	// Try to find first available name from the theme, cooling names first
+	for _, skipRecent := range []bool{true, false} {
+		for i := 0; i < len(names) && i < p.MaxSize; i++ {
+			name := p.decorate(names[i])
+			if !p.InUse[name] && !(skipRecent && recent[name]) {
+				p.InUse[name] = true
+				p.forget(name)
+				return name, nil
+			}
 		}
 	}
 
 	// Pool exhausted, use overflow naming
-	name := p.formatOverflowName(p.OverflowNext)
+	name := p.decorate(p.formatOverflowName(p.OverflowNext))
 	p.OverflowNext++
 	return name, nil
 }
 
+// forget drops name from the recently released list.
+func (p *NamePool) forget(name string) {
+	for i, n := range p.Recent {
+		if n == name {
+			p.Recent = append(p.Recent[:i:i], p.Recent[i+1:]...)
+			return
+		}
+	}
+}
+
 // Release returns a name slot to the available pool.
 // Called when a polecat is nuked - the name becomes available for new polecats.
 // NOTE: This releases the NAME, not the polecat. The polecat is gone (nuked).
@@ -276,15 +373,24 @@ func (p *NamePool) Release(name string) {
 	// Check if it's a themed name
 	if p.isThemedName(name) {
 		delete(p.InUse, name)
+		if p.ReuseCooldown > 0 {
+			p.forget(name)
+			p.Recent = append(p.Recent, name)
+			if len(p.Recent) > p.ReuseCooldown {
+				p.Recent = p.Recent[len(p.Recent)-p.ReuseCooldown:]
+			}
+		}
 	}
 	// Overflow names are not reusable, so we don't track them
 }
 
-// isThemedName checks if a name is in the theme pool.
+// isThemedName checks if a name is in the theme pool, with or without the
+// pool's prefix and suffix.
 func (p *NamePool) isThemedName(name string) bool {
+	base := p.baseName(name)
 	names := p.getNames()
 	for _, n := range names {
-		if n == name {
+		if n == name || n == base {
 			return true
 		}
 	}
@@ -438,5 +544,6 @@ func (p *NamePool) Reset() {
 	defer p.mu.Unlock()
 
 	p.InUse = make(map[string]bool)
+	p.Recent = nil
 	p.OverflowNext = p.MaxSize + 1
 }
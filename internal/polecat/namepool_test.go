@@ -525,3 +525,70 @@ func TestNamePool_ReservedNamesInCustomNames(t *testing.T) {
 		t.Errorf("expected alpha, beta, gamma to be allocated, got %v", allocated)
 	}
 }
+
+func TestNamePool_Pattern(t *testing.T) {
+	pool := NewNamePoolWithConfig(t.TempDir(), "testrig", "mad-max", nil, 2)
+	pool.Prefix, pool.Suffix = "fe-", "-x"
+
+	if name, _ := pool.Allocate(); name != "fe-furiosa-x" {
+		t.Errorf("first allocation = %q, want fe-furiosa-x", name)
+	}
+	pool.Allocate()
+	if name, _ := pool.Allocate(); name != "fe-3-x" {
+		t.Errorf("overflow allocation = %q, want fe-3-x", name)
+	}
+
+	// Names from before the pattern was set still count as pool names.
+	if !pool.IsPoolName("fe-nux-x") || !pool.IsPoolName("nux") {
+		t.Error("decorated and plain themed names should both be pool names")
+	}
+	pool.Release("fe-furiosa-x")
+	if name, _ := pool.Allocate(); name != "fe-furiosa-x" {
+		t.Errorf("after release = %q, want fe-furiosa-x", name)
+	}
+}
+
+func TestNamePool_ReuseCooldown(t *testing.T) {
+	dir := t.TempDir()
+	pool := NewNamePoolWithConfig(dir, "testrig", "mad-max", nil, 3)
+	pool.ReuseCooldown = 1
+
+	pool.Allocate() // furiosa
+	pool.Allocate() // nux
+	pool.Release("furiosa")
+	if err := pool.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cooldown survives a restart: furiosa is skipped while slit is free.
+	pool2 := NewNamePoolWithConfig(dir, "testrig", "mad-max", nil, 3)
+	pool2.ReuseCooldown = 1
+	if err := pool2.Load(); err != nil {
+		t.Fatal(err)
+	}
+	pool2.Reconcile([]string{"nux"})
+	if name, _ := pool2.Allocate(); name != "slit" {
+		t.Errorf("allocation during cooldown = %q, want slit", name)
+	}
+	// With every other name taken, a cooling name is still better than overflow.
+	if name, _ := pool2.Allocate(); name != "furiosa" {
+		t.Errorf("allocation with only cooling names free = %q, want furiosa", name)
+	}
+	if len(pool2.Recent) != 0 {
+		t.Errorf("Recent = %v, want allocated name removed", pool2.Recent)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for name, ok := range map[string]bool{
+		"Toast": true, "bullet-farmer": true, "fe_01": true,
+		"": false, "-x": false, "a/b": false, "refinery": false, "crew-joe": false,
+	} {
+		if err := ValidateName(name); (err == nil) != ok {
+			t.Errorf("ValidateName(%q) = %v, want ok=%v", name, err, ok)
+		}
+	}
+	if err := ValidateNamePattern("crew-", ""); err == nil {
+		t.Error("a crew- prefix should be rejected")
+	}
+}
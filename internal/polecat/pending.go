@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -117,13 +118,15 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 	}
 
 	t := tmux.NewTmux()
+	mux := multiplexer.ForTownWith(townRoot, t)
+	_, usesTmux := mux.(*tmux.Tmux)
 	var results []TriggerResult
 
 	for _, ps := range pending {
 		result := TriggerResult{Spawn: ps}
 
-		// Check if session still exists (ZFC: query tmux directly)
-		running, err := t.HasSession(ps.Session)
+		// Check if session still exists (ZFC: query the multiplexer directly)
+		running, err := mux.HasSession(ps.Session)
 		if err != nil {
 			result.Error = fmt.Errorf("checking session: %w", err)
 			results = append(results, result)
@@ -140,20 +143,27 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 			continue
 		}
 
-		// Check if runtime is ready (non-blocking poll)
-		rigPath := filepath.Join(townRoot, ps.Rig)
-		runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, rigPath)
-		err = t.WaitForRuntimeReady(ps.Session, runtimeConfig, timeout)
-		if err != nil {
-			// Not ready yet - leave mail in inbox for next poll
-			result.Skipped = true
-			results = append(results, result)
-			continue
+		// Check if runtime is ready (non-blocking poll). Only a tmux pane
+		// can be inspected; other backends' sessions are ready once up.
+		if usesTmux {
+			rigPath := filepath.Join(townRoot, ps.Rig)
+			runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, rigPath)
+			if err := t.WaitForRuntimeReady(ps.Session, runtimeConfig, timeout); err != nil {
+				// Not ready yet - leave mail in inbox for next poll
+				result.Skipped = true
+				results = append(results, result)
+				continue
+			}
 		}
 
 		// Runtime is ready - send trigger
 		triggerMsg := "Begin."
-		if err := t.NudgeSession(ps.Session, triggerMsg); err != nil {
+		if usesTmux {
+			err = t.NudgeSession(ps.Session, triggerMsg)
+		} else {
+			err = mux.SendKeys(ps.Session, triggerMsg)
+		}
+		if err != nil {
 			result.Error = fmt.Errorf("nudging session: %w", err)
 			results = append(results, result)
 			continue
@@ -6,21 +6,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
-	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -42,16 +46,33 @@ var (
 type SessionManager struct {
 	tmux *tmux.Tmux
 	rig  *rig.Rig
+
+	// mux hosts the sessions: tmux itself, or the town's configured
+	// multiplexer. tmux-only steps (session environment table, theming,
+	// pane hooks) are skipped for other backends.
+	mux multiplexer.Multiplexer
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
 func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
+	mux := multiplexer.ForTownWith(filepath.Dir(r.Path), t)
+	if r.IsRemote() {
+		t = r.Tmux()
+		mux = t
+	}
 	return &SessionManager{
 		tmux: t,
 		rig:  r,
+		mux:  mux,
 	}
 }
 
+// usesTmux reports whether sessions are hosted by tmux.
+func (m *SessionManager) usesTmux() bool {
+	_, ok := m.mux.(*tmux.Tmux)
+	return ok
+}
+
 // SessionStartOptions configures polecat session startup.
 type SessionStartOptions struct {
 	// WorkDir overrides the default working directory (polecat clone dir).
@@ -129,7 +150,7 @@ func (m *SessionManager) SessionName(polecat string) string {
 func validateSessionName(sessionName, rigName string) error {
 	// Expected format: gt-<rig>-<name>
 	// Check if the name part starts with the rig prefix (indicates double-prefix bug)
-	prefix := session.PrefixFor(rigName) + "-"
+	prefix := session.RigSessionPrefix(session.PrefixFor(rigName))
 	if !strings.HasPrefix(sessionName, prefix) {
 		return nil // Not our rig, can't validate
 	}
@@ -187,7 +208,11 @@ func (m *SessionManager) hasPolecat(polecat string) bool {
 }
 
 // Start creates and starts a new session for a polecat.
-func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
+func (m *SessionManager) Start(polecat string, opts SessionStartOptions) (err error) {
+	_, span := telemetry.Start(context.Background(), "polecat.session.start",
+		attribute.String("polecat.rig", m.rig.Name), attribute.String("polecat.name", polecat))
+	defer func() { telemetry.End(span, err) }()
+
 	if !m.hasPolecat(polecat) {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
@@ -197,15 +222,16 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Check if session already exists.
 	// If an existing session's pane process has died, kill the stale session
 	// and proceed rather than returning ErrSessionRunning (gt-jn40ft).
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if running {
 		if m.isSessionStale(sessionID) {
-			if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+			if err := m.mux.KillSessionWithProcesses(sessionID); err != nil {
 				return fmt.Errorf("killing stale session %s: %w", sessionID, err)
 			}
+			m.removeContainer(sessionID)
 		} else {
 			return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
 		}
@@ -255,6 +281,14 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	}
 	beacon := session.FormatStartupBeacon(beaconConfig)
 
+	// Resolve secrets before building the command so a missing secret fails
+	// the start. Their values reach the agent through the session
+	// environment, never the command line.
+	secrets, err := config.StartupSecrets("polecat", townRoot, m.rig.Path, opts.Agent)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
+
 	command := opts.Command
 	if command == "" {
 		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
@@ -281,7 +315,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// when the polecat's cwd is deleted before gt done finishes, these env vars allow
 	// branch detection and path resolution without a working directory.
 	polecatGitBranch := ""
-	if g := git.NewGit(workDir); g != nil {
+	if g := m.rig.Git(workDir); g != nil {
 		if b, err := g.CurrentBranch(); err == nil {
 			polecatGitBranch = b
 		}
@@ -297,13 +331,38 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		envVarsToInject["GT_BRANCH"] = polecatGitBranch
 	}
 	command = config.PrependEnv(command, envVarsToInject)
+	command = m.rig.AgentCommand(townRoot, command)
+
+	// Container sandbox: run the whole startup command inside the rig's image.
+	container := m.containerConfig()
+	if container != nil {
+		wrapped, err := container.WrapCommand(sessionID, workDir, townRoot, command, slices.Sorted(maps.Keys(secrets)))
+		if err != nil {
+			return fmt.Errorf("building container command: %w", err)
+		}
+		command = wrapped
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+	if err := multiplexer.NewSessionWithSecrets(m.mux, sessionID, workDir, command, nil, secrets); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Hook the issue to the polecat if provided via --issue flag
+	if opts.Issue != "" {
+		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+		if err := m.hookIssue(opts.Issue, agentID, workDir); err != nil {
+			style.PrintWarning("could not hook issue %s: %v", opts.Issue, err)
+		}
+	}
+
+	if !m.usesTmux() {
+		// The startup command already carries the agent's environment and
+		// the beacon; there is no session table, theme, or pane to set up.
+		return m.verifyStarted(sessionID)
+	}
+
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	// Note: townRoot already defined above for ResolveRoleAgentConfig
@@ -352,14 +411,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// shadow built-in preset names (e.g., custom "codex" running "opencode"),
 	// so we resolve process names from both agent name and actual command.
 	processNames := config.ResolveProcessNames(runtimeConfig.ResolvedAgent, runtimeConfig.Command)
-	debugSession("SetEnvironment GT_PROCESS_NAMES", m.tmux.SetEnvironment(sessionID, "GT_PROCESS_NAMES", strings.Join(processNames, ",")))
-	// Hook the issue to the polecat if provided via --issue flag
-	if opts.Issue != "" {
-		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
-		if err := m.hookIssue(opts.Issue, agentID, workDir); err != nil {
-			style.PrintWarning("could not hook issue %s: %v", opts.Issue, err)
-		}
+	if container != nil {
+		// The pane runs the container client; the agent lives inside it.
+		processNames = append(processNames, container.RuntimeName())
 	}
+	debugSession("SetEnvironment GT_PROCESS_NAMES", m.tmux.SetEnvironment(sessionID, "GT_PROCESS_NAMES", strings.Join(processNames, ",")))
 
 	// Apply theme (non-fatal)
 	theme := tmux.AssignTheme(m.rig.Name)
@@ -404,14 +460,8 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Legacy fallback for other startup paths (non-fatal)
 	_ = runtime.RunStartupFallback(m.tmux, sessionID, "polecat", runtimeConfig)
 
-	// Verify session survived startup - if the command crashed, the session may have died.
-	// Without this check, Start() would return success even if the pane died during initialization.
-	running, err = m.tmux.HasSession(sessionID)
-	if err != nil {
-		return fmt.Errorf("verifying session: %w", err)
-	}
-	if !running {
-		return fmt.Errorf("session %s died during startup (agent command may have failed)", sessionID)
+	if err := m.verifyStarted(sessionID); err != nil {
+		return err
 	}
 
 	// Validate GT_AGENT is set. Without GT_AGENT, IsAgentAlive falls back to
@@ -426,9 +476,26 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 			sessionID, runtimeConfig.Command)
 	}
 
-	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	_ = session.TrackSessionPID(townRoot, sessionID, m.tmux)
+	// Track PID for defense-in-depth orphan cleanup (non-fatal).
+	// Remote PIDs mean nothing on this machine, so they are not tracked.
+	if !m.rig.IsRemote() {
+		_ = session.TrackSessionPID(townRoot, sessionID, m.tmux)
+	}
+
+	return nil
+}
 
+// verifyStarted checks the session survived startup. If the command
+// crashed, the session may have died; without this check, Start would
+// return success even if the pane died during initialization.
+func (m *SessionManager) verifyStarted(sessionID string) error {
+	running, err := m.mux.HasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("verifying session: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("session %s died during startup (agent command may have failed)", sessionID)
+	}
 	return nil
 }
 
@@ -436,7 +503,12 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 // A stale session exists in tmux but its main process (the agent) is no longer running.
 // This happens when the agent crashes during startup but tmux keeps the dead pane.
 // Delegates to isSessionProcessDead to avoid duplicating process-check logic (gt-qgzj1h).
+// Other backends end the session with its command, so a live one is never
+// stale.
 func (m *SessionManager) isSessionStale(sessionID string) bool {
+	if !m.usesTmux() {
+		return false
+	}
 	return isSessionProcessDead(m.tmux, sessionID)
 }
 
@@ -444,7 +516,7 @@ func (m *SessionManager) isSessionStale(sessionID string) bool {
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -453,16 +525,17 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	}
 
 	// Try graceful shutdown first
-	if !force {
+	if !force && m.usesTmux() {
 		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
 		session.WaitForSessionExit(m.tmux, sessionID, constants.GracefulShutdownTimeout)
 	}
 
 	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 	// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+	if err := m.mux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	m.removeContainer(sessionID)
 
 	return nil
 }
@@ -472,6 +545,10 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
+	if !m.usesTmux() {
+		// Other backends end the session when the agent exits.
+		return m.mux.HasSession(sessionID)
+	}
 	status := m.tmux.CheckSessionHealth(sessionID, 0)
 	return status == tmux.SessionHealthy, nil
 }
@@ -480,7 +557,7 @@ func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -492,7 +569,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		RigName:   m.rig.Name,
 	}
 
-	if !running {
+	if !running || !m.usesTmux() {
 		return info, nil
 	}
 
@@ -534,12 +611,12 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 // This includes polecats, witness, refinery, and crew sessions.
 // Use ListPolecats() to get only polecat sessions.
 func (m *SessionManager) List() ([]SessionInfo, error) {
-	sessions, err := m.tmux.ListSessions()
+	sessions, err := m.mux.ListSessions()
 	if err != nil {
 		return nil, err
 	}
 
-	prefix := session.PrefixFor(m.rig.Name) + "-"
+	prefix := session.RigSessionPrefix(session.PrefixFor(m.rig.Name))
 	var infos []SessionInfo
 
 	for _, sessionID := range sessions {
@@ -583,7 +660,7 @@ func (m *SessionManager) ListPolecats() ([]SessionInfo, error) {
 func (m *SessionManager) Attach(polecat string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -591,14 +668,14 @@ func (m *SessionManager) Attach(polecat string) error {
 		return ErrSessionNotFound
 	}
 
-	return m.tmux.AttachSession(sessionID)
+	return multiplexer.Attach(m.mux, sessionID)
 }
 
 // Capture returns the recent output from a polecat session.
 func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -606,12 +683,12 @@ func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return multiplexer.Capture(m.mux, sessionID, lines)
 }
 
 // CaptureSession returns the recent output from a session by raw session ID.
 func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, error) {
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -619,20 +696,23 @@ func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, er
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return multiplexer.Capture(m.mux, sessionID, lines)
 }
 
 // Inject sends a message to a polecat session.
 func (m *SessionManager) Inject(polecat, message string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if !running {
 		return ErrSessionNotFound
 	}
+	if !m.usesTmux() {
+		return m.mux.SendKeys(sessionID, message)
+	}
 
 	debounceMs := 200 + (len(message)/1024)*100
 	if debounceMs > 1500 {
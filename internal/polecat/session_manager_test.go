@@ -7,8 +7,10 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -483,3 +485,61 @@ func TestValidateSessionName(t *testing.T) {
 		})
 	}
 }
+
+// TestStartPolecatHeadless verifies that a town configured with
+// multiplexer: headless really runs the polecat's agent, outside tmux.
+func TestStartPolecatHeadless(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("headless sessions need a POSIX shell")
+	}
+	setupTestRegistryForSession(t)
+
+	townRoot := t.TempDir()
+	settingsDir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type":"town-settings","version":1,"multiplexer":"headless"}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rigPath := filepath.Join(townRoot, "gastown")
+	workDir := filepath.Join(rigPath, "polecats", "Toast")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &rig.Rig{Name: "gastown", Path: rigPath, Polecats: []string{"Toast"}}
+	m := NewSessionManager(tmux.NewTmux(), r)
+	t.Cleanup(func() { _ = m.Stop("Toast", true) })
+
+	if err := m.Start("Toast", SessionStartOptions{WorkDir: workDir, Command: `echo "agent for $GT_POLECAT" && sleep 30`}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	h := multiplexer.NewHeadless(townRoot)
+	if alive, err := h.HasSession("gt-Toast"); err != nil || !alive {
+		t.Fatalf("headless HasSession = %v, %v; want true", alive, err)
+	}
+	if running, err := m.IsRunning("Toast"); err != nil || !running {
+		t.Fatalf("IsRunning = %v, %v; want true", running, err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		out, _ := m.Capture("Toast", 10)
+		if strings.Contains(out, "agent for Toast") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("agent output not captured; got %q", out)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if err := m.Stop("Toast", true); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if alive, _ := h.HasSession("gt-Toast"); alive {
+		t.Error("session still running after Stop")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package preflight
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// diskFree returns the bytes available to an unprivileged user on the
+// filesystem holding path.
+func diskFree(path string) (int64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), nil
+}
+
+// onSameFilesystem compares the device IDs of a and b.
+func onSameFilesystem(a, b string) bool {
+	ai, errA := os.Stat(a)
+	bi, errB := os.Stat(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	as, okA := ai.Sys().(*syscall.Stat_t)
+	bs, okB := bi.Sys().(*syscall.Stat_t)
+	return okA && okB && as.Dev == bs.Dev
+}
@@ -0,0 +1,257 @@
+// Package preflight checks free disk space and external tools before
+// long-running operations such as gt rig add, gt dolt migrate, and
+// gt town backup, so they abort up front with a report instead of failing
+// halfway.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/progress"
+)
+
+// Check statuses.
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// Headroom is the free space a disk check asks for on top of the estimate,
+// so the Dolt server and logs can keep writing once the operation is done.
+const Headroom int64 = 256 << 20
+
+// MinGitVersion is the oldest git gt relies on: core.hooksPath, set on
+// the refinery worktree, arrived in 2.9.
+const MinGitVersion = "2.9.0"
+
+// toolTimeout bounds a single version probe or ls-remote.
+const toolTimeout = 30 * time.Second
+
+// ErrFailed is wrapped by Report.Err when a check failed.
+var ErrFailed = errors.New("preflight failed")
+
+// Check is the outcome of one preflight check.
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Report collects the checks run before an operation.
+type Report struct {
+	Operation string  `json:"operation"`
+	Checks    []Check `json:"checks"`
+}
+
+// New returns an empty report for operation, e.g. "rig add".
+func New(operation string) *Report {
+	return &Report{Operation: operation}
+}
+
+func (r *Report) add(name, status, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Failed returns the checks that failed.
+func (r *Report) Failed() []Check {
+	var failed []Check
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Err returns an error wrapping ErrFailed that lists the failed checks, or
+// nil if none failed.
+func (r *Report) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, c := range failed {
+		fmt.Fprintf(&b, "\n  %s: %s", c.Name, c.Detail)
+	}
+	return fmt.Errorf("%w for %s:%s", ErrFailed, r.Operation, b.String())
+}
+
+// Tool is an external command an operation runs.
+type Tool struct {
+	Name        string   // binary looked up in PATH
+	VersionArgs []string // arguments that print its version
+	MinVersion  string   // oldest usable version; "" accepts any
+	Install     string   // how to get it, shown when it's missing or too old
+}
+
+// The tools gt shells out to.
+var (
+	Git = Tool{
+		Name:        "git",
+		VersionArgs: []string{"--version"},
+		MinVersion:  MinGitVersion,
+		Install:     "https://git-scm.com/downloads",
+	}
+	Beads = Tool{
+		Name:        "bd",
+		VersionArgs: []string{"version"},
+		MinVersion:  deps.MinBeadsVersion,
+		Install:     "go install " + deps.BeadsInstallPath,
+	}
+	GitLFS = Tool{
+		Name:        "git-lfs",
+		VersionArgs: []string{"version"},
+		Install:     "https://git-lfs.com",
+	}
+	Dolt = Tool{
+		Name:        "dolt",
+		VersionArgs: []string{"version"},
+		Install:     "https://github.com/dolthub/dolt#installation",
+	}
+)
+
+// Variables so tests can run without the tools, a network, or a full disk.
+var (
+	lookPath    = exec.LookPath
+	toolVersion = func(path string, args []string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+		return string(out), err
+	}
+	lsRemote = func(url string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", url)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	freeSpace      = diskFree
+	sameFilesystem = onSameFilesystem
+)
+
+var versionRe = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// RequireTool checks that t is in PATH and, if it has a minimum version,
+// new enough. With optional set, a missing tool is a warning and why says
+// what will be left out.
+func (r *Report) RequireTool(t Tool, optional bool, why string) {
+	path, err := lookPath(t.Name)
+	if err != nil {
+		if optional {
+			r.add(t.Name, StatusWarn, "not found in PATH; %s", why)
+			return
+		}
+		r.add(t.Name, StatusFail, "not found in PATH (install: %s)", t.Install)
+		return
+	}
+	out, err := toolVersion(path, t.VersionArgs)
+	version := versionRe.FindString(out)
+	if err != nil || version == "" {
+		r.add(t.Name, StatusWarn, "found at %s but its version could not be read", path)
+		return
+	}
+	if t.MinVersion != "" && deps.CompareVersions(version, t.MinVersion) < 0 {
+		r.add(t.Name, StatusFail, "version %s is older than the minimum %s (upgrade: %s)", version, t.MinVersion, t.Install)
+		return
+	}
+	r.add(t.Name, StatusOK, "version %s", version)
+}
+
+// RequireRemote checks that git can list the heads of url, catching typos
+// and missing credentials before anything is created.
+func (r *Report) RequireRemote(url string) {
+	if err := lsRemote(url); err != nil {
+		r.add("remote", StatusFail, "cannot reach %s: %v", url, err)
+		return
+	}
+	r.add("remote", StatusOK, "%s is reachable", url)
+}
+
+// RequireSpace checks that the filesystem holding dir, or its nearest
+// existing parent, has need bytes free plus Headroom. A negative need means
+// the size couldn't be estimated, so only Headroom is asked for.
+func (r *Report) RequireSpace(name, dir string, need int64) {
+	existing := existingParent(dir)
+	free, err := freeSpace(existing)
+	if err != nil {
+		r.add(name, StatusWarn, "could not read free space on %s: %v", existing, err)
+		return
+	}
+	estimate := "size unknown"
+	want := Headroom
+	if need >= 0 {
+		estimate = "need ~" + formatBytes(need)
+		want += need
+	}
+	if free < want {
+		r.add(name, StatusFail, "%s free on %s; %s plus %s headroom", formatBytes(free), existing, estimate, formatBytes(Headroom))
+		return
+	}
+	r.add(name, StatusOK, "%s free on %s; %s", formatBytes(free), existing, estimate)
+}
+
+// SameFilesystem reports whether a and b, or their nearest existing
+// parents, are on the same filesystem, where a move is a rename rather
+// than a copy.
+func SameFilesystem(a, b string) bool {
+	return sameFilesystem(existingParent(a), existingParent(b))
+}
+
+// CloneSize estimates the disk a rig's clones of gitURL take: the shared
+// bare repo and the mayor clone each hold the history, and the refinery
+// worktree a checkout, so three times the repository. Only a local source
+// (a path or file:// URL, or a --local-repo) can be measured; for a remote
+// URL it returns -1.
+func CloneSize(gitURL, localRepo string) int64 {
+	src := strings.TrimPrefix(gitURL, "file://")
+	if localRepo != "" {
+		src = localRepo
+	}
+	if info, err := os.Stat(src); err == nil && info.IsDir() {
+		return 3 * progress.DirSize(src)
+	}
+	return -1
+}
+
+// existingParent returns dir, or its nearest ancestor that exists.
+func existingParent(dir string) string {
+	dir = filepath.Clean(dir)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,124 @@
+package preflight
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRequireTool(t *testing.T) {
+	origLook, origVersion := lookPath, toolVersion
+	t.Cleanup(func() { lookPath, toolVersion = origLook, origVersion })
+
+	versions := map[string]string{
+		"git":  "git version 2.43.0\n",
+		"bd":   "bd version 0.40.1 (dev)\n",
+		"dolt": "garbled",
+	}
+	lookPath = func(name string) (string, error) {
+		if name == "missing" {
+			return "", errors.New("not found")
+		}
+		return "/usr/bin/" + name, nil
+	}
+	toolVersion = func(path string, _ []string) (string, error) {
+		return versions[filepath.Base(path)], nil
+	}
+
+	r := New("test")
+	r.RequireTool(Git, false, "")
+	r.RequireTool(Beads, false, "")
+	r.RequireTool(Dolt, false, "")
+	r.RequireTool(Tool{Name: "missing"}, true, "nothing else breaks")
+	r.RequireTool(Tool{Name: "missing", Install: "ask around"}, false, "")
+
+	want := []string{StatusOK, StatusFail, StatusWarn, StatusWarn, StatusFail}
+	for i, c := range r.Checks {
+		if c.Status != want[i] {
+			t.Errorf("check %d (%s) = %s %q, want %s", i, c.Name, c.Status, c.Detail, want[i])
+		}
+	}
+	if !strings.Contains(r.Checks[1].Detail, "0.40.1 is older than the minimum") {
+		t.Errorf("bd detail = %q", r.Checks[1].Detail)
+	}
+
+	err := r.Err()
+	if !errors.Is(err, ErrFailed) || !strings.Contains(err.Error(), "ask around") || strings.Contains(err.Error(), "git:") {
+		t.Errorf("Err() = %v, want the two failures only", err)
+	}
+}
+
+func TestRequireSpace(t *testing.T) {
+	orig := freeSpace
+	t.Cleanup(func() { freeSpace = orig })
+	freeSpace = func(string) (int64, error) { return Headroom + 100, nil }
+
+	dir := t.TempDir()
+	r := New("test")
+	r.RequireSpace("fits", filepath.Join(dir, "not", "yet", "created"), 100)
+	r.RequireSpace("unknown", dir, -1)
+	r.RequireSpace("too big", dir, 101)
+
+	want := []string{StatusOK, StatusOK, StatusFail}
+	for i, c := range r.Checks {
+		if c.Status != want[i] {
+			t.Errorf("check %s = %s %q, want %s", c.Name, c.Status, c.Detail, want[i])
+		}
+	}
+	if !strings.Contains(r.Checks[0].Detail, dir) {
+		t.Errorf("detail %q doesn't name the existing parent %s", r.Checks[0].Detail, dir)
+	}
+
+	freeSpace = func(string) (int64, error) { return 0, errors.New("statfs failed") }
+	r = New("test")
+	r.RequireSpace("disk", dir, 1)
+	if r.Checks[0].Status != StatusWarn || r.Err() != nil {
+		t.Errorf("unreadable free space = %+v, want a warning", r.Checks[0])
+	}
+}
+
+func TestRequireRemote(t *testing.T) {
+	orig := lsRemote
+	t.Cleanup(func() { lsRemote = orig })
+	lsRemote = func(url string) error {
+		if strings.Contains(url, "typo") {
+			return errors.New("repository not found")
+		}
+		return nil
+	}
+
+	r := New("test")
+	r.RequireRemote("git@github.com:org/repo.git")
+	r.RequireRemote("git@github.com:org/typo.git")
+	if r.Checks[0].Status != StatusOK || r.Checks[1].Status != StatusFail {
+		t.Errorf("checks = %+v", r.Checks)
+	}
+}
+
+func TestCloneSize(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "pack"), make([]byte, 1000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := CloneSize(repo, ""); got != 3000 {
+		t.Errorf("CloneSize(path) = %d, want 3000", got)
+	}
+	if got := CloneSize("file://"+repo, ""); got != 3000 {
+		t.Errorf("CloneSize(file URL) = %d, want 3000", got)
+	}
+	if got := CloneSize("git@github.com:org/repo.git", repo); got != 3000 {
+		t.Errorf("CloneSize(remote, local repo) = %d, want 3000", got)
+	}
+	if got := CloneSize("git@github.com:org/repo.git", ""); got != -1 {
+		t.Errorf("CloneSize(remote) = %d, want -1", got)
+	}
+}
+
+func TestSameFilesystem(t *testing.T) {
+	dir := t.TempDir()
+	if !SameFilesystem(dir, filepath.Join(dir, "missing", "child")) {
+		t.Error("a directory and its missing child are on different filesystems")
+	}
+}
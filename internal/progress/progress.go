@@ -0,0 +1,286 @@
+// Package progress reports the progress of long-running operations such as
+// clones, database migrations, and backup restores.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Unit is what a Bar counts.
+type Unit int
+
+const (
+	Count Unit = iota // objects, files, databases
+	Bytes
+)
+
+const (
+	barWidth    = 30
+	redrawEvery = 100 * time.Millisecond
+	// quietPeriod keeps log mode silent for phases that finish quickly.
+	quietPeriod = 2 * time.Second
+	// logEvery is how often log mode reports a phase with no known total.
+	logEvery = 10 * time.Second
+)
+
+// Bar reports progress of one operation, possibly in several phases.
+//
+// On a terminal it redraws a single line in place with a bar and ETA, and
+// erases it on Finish so the caller's own output follows cleanly. Elsewhere
+// (logs, pipes, agent sessions) it prints a line at each quarter of a phase
+// that has run longer than a couple of seconds.
+//
+// A nil *Bar is valid and does nothing, so code can take an optional one.
+type Bar struct {
+	mu  sync.Mutex
+	w   io.Writer
+	tty bool
+	now func() time.Time
+
+	label string
+	unit  Unit
+	total int64
+	done  int64
+	start time.Time
+
+	lastDraw    time.Time
+	drawn       bool  // tty: a bar line is on screen
+	logged      bool  // log mode: this phase printed something
+	nextQuarter int64 // log mode: next quarter of the phase to report
+	lastLog     time.Time
+}
+
+// New returns a Bar writing to w, drawn in place if w is a terminal.
+// total may be 0 if unknown.
+func New(w io.Writer, label string, total int64, unit Unit) *Bar {
+	return newBar(w, isTerminal(w), time.Now, label, total, unit)
+}
+
+func newBar(w io.Writer, tty bool, now func() time.Time, label string, total int64, unit Unit) *Bar {
+	b := &Bar{w: w, tty: tty, now: now}
+	b.startPhase(label, total, unit)
+	return b
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Phase starts a new phase of the operation, e.g. moving from receiving
+// objects to resolving deltas.
+func (b *Bar) Phase(label string, total int64, unit Unit) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.endPhase()
+	b.startPhase(label, total, unit)
+	b.render(true)
+}
+
+// SetTotal updates the current phase's total.
+func (b *Bar) SetTotal(total int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total = total
+}
+
+// Set records how much of the current phase is done.
+func (b *Bar) Set(done int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = done
+	b.render(false)
+}
+
+// Add records n more units done.
+func (b *Bar) Add(n int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done += n
+	b.render(false)
+}
+
+// Finish ends the operation: the terminal line is erased, and in log mode a
+// phase that reported progress gets a closing line. The Bar can be reused
+// by calling Phase.
+func (b *Bar) Finish() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.endPhase()
+	if b.drawn {
+		fmt.Fprint(b.w, "\r\033[K")
+		b.drawn = false
+	}
+}
+
+func (b *Bar) startPhase(label string, total int64, unit Unit) {
+	b.label, b.total, b.unit = label, total, unit
+	b.done = 0
+	b.start = b.now()
+	b.logged = false
+	b.nextQuarter = 1
+	b.lastLog = b.start
+}
+
+func (b *Bar) endPhase() {
+	if !b.tty && b.logged {
+		fmt.Fprintf(b.w, "  %s: done (%s) in %s\n", b.label, b.amount(b.done), formatDuration(b.now().Sub(b.start)))
+	}
+	b.logged = false
+}
+
+func (b *Bar) render(force bool) {
+	now := b.now()
+	if b.tty {
+		if !force && now.Sub(b.lastDraw) < redrawEvery {
+			return
+		}
+		b.lastDraw = now
+		b.drawn = true
+		fmt.Fprintf(b.w, "\r\033[K  %s", b.line(true))
+		return
+	}
+
+	if now.Sub(b.start) < quietPeriod {
+		return
+	}
+	if b.total > 0 {
+		quarter := b.done * 4 / b.total
+		if quarter < b.nextQuarter || quarter >= 4 {
+			return // 100% is reported by the closing line
+		}
+		b.nextQuarter = quarter + 1
+	} else if b.logged && now.Sub(b.lastLog) < logEvery {
+		return
+	}
+	b.lastLog = now
+	b.logged = true
+	fmt.Fprintf(b.w, "  %s\n", b.line(false))
+}
+
+// line renders the current state: with a bar on a terminal, plain in logs.
+func (b *Bar) line(bar bool) string {
+	if b.total <= 0 {
+		return fmt.Sprintf("%s: %s", b.label, b.amount(b.done))
+	}
+	done := min(b.done, b.total)
+	pct := done * 100 / b.total
+
+	var sb strings.Builder
+	sb.WriteString(b.label)
+	if bar {
+		filled := int(done * barWidth / b.total)
+		fmt.Fprintf(&sb, " [%s%s] %3d%% %s/%s", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled), pct, b.amount(done), b.amount(b.total))
+	} else {
+		fmt.Fprintf(&sb, ": %d%% (%s/%s)", pct, b.amount(done), b.amount(b.total))
+	}
+	if eta, ok := b.eta(); ok {
+		fmt.Fprintf(&sb, ", ETA %s", formatDuration(eta))
+	}
+	return sb.String()
+}
+
+// eta extrapolates the current phase's rate so far. It needs a second of
+// history to be worth showing.
+func (b *Bar) eta() (time.Duration, bool) {
+	elapsed := b.now().Sub(b.start)
+	if b.done <= 0 || b.done >= b.total || elapsed < time.Second {
+		return 0, false
+	}
+	return time.Duration(float64(elapsed) * float64(b.total-b.done) / float64(b.done)), true
+}
+
+func (b *Bar) amount(n int64) string {
+	if b.unit == Bytes {
+		return formatBytes(n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// WatchDir reports the growing size of dir to b until stop is called, for
+// copies done by an external tool such as cp. b's current phase should be in
+// Bytes with the source size as total.
+func WatchDir(b *Bar, dir string) (stop func()) {
+	if b == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				b.Set(DirSize(dir))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+		b.Set(DirSize(dir))
+	}
+}
+
+// DirSize returns the total size of the files under dir.
+func DirSize(dir string) int64 {
+	var total int64
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,153 @@
+package progress
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a controllable time source.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestBar_NilIsNoop(t *testing.T) {
+	var b *Bar
+	b.Phase("x", 10, Count)
+	b.Set(5)
+	b.Add(1)
+	b.SetTotal(20)
+	b.Finish()
+	WatchDir(b, t.TempDir())()
+}
+
+func TestBar_LogModeQuietForFastPhases(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := newBar(&out, false, clock.now, "Receiving objects", 100, Count)
+
+	for i := int64(1); i <= 100; i++ {
+		clock.advance(10 * time.Millisecond)
+		b.Set(i)
+	}
+	b.Finish()
+	if out.Len() != 0 {
+		t.Errorf("fast phase logged:\n%s", out.String())
+	}
+}
+
+func TestBar_LogModeQuarters(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := newBar(&out, false, clock.now, "Receiving objects", 100, Count)
+
+	for i := int64(1); i <= 100; i++ {
+		clock.advance(100 * time.Millisecond)
+		b.Set(i)
+	}
+	b.Finish()
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	// Quiet for the first 2s (20%), then 25/50/75%, then the closing line.
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), out.String())
+	}
+	if want := "  Receiving objects: 25% (25/100), ETA 8s"; lines[0] != want {
+		t.Errorf("first line = %q, want %q", lines[0], want)
+	}
+	if want := "  Receiving objects: done (100) in 10s"; lines[3] != want {
+		t.Errorf("last line = %q, want %q", lines[3], want)
+	}
+}
+
+func TestBar_LogModeUnknownTotal(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := newBar(&out, false, clock.now, "Copying", 0, Bytes)
+
+	for i := 0; i < 25; i++ {
+		clock.advance(time.Second)
+		b.Add(1024)
+	}
+	// Logged once past the quiet period, then every 10s.
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Errorf("got %d lines, want 3:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), "Copying: 2.0 KB") {
+		t.Errorf("missing byte amount:\n%s", out.String())
+	}
+}
+
+func TestBar_TerminalRedrawsAndClears(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := newBar(&out, true, clock.now, "Migrating", 4, Count)
+
+	clock.advance(2 * time.Second)
+	b.Set(2)
+	if !strings.Contains(out.String(), "\r\033[K  Migrating ["+strings.Repeat("=", 15)+strings.Repeat(" ", 15)+"]  50% 2/4, ETA 2s") {
+		t.Errorf("bar not drawn:\n%q", out.String())
+	}
+
+	// Redraws are throttled.
+	n := out.Len()
+	b.Set(3)
+	if out.Len() != n {
+		t.Error("redrew within throttle interval")
+	}
+
+	b.Finish()
+	if !strings.HasSuffix(out.String(), "\r\033[K") {
+		t.Errorf("line not cleared on Finish:\n%q", out.String())
+	}
+}
+
+func TestBar_PhaseResets(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := newBar(&out, false, clock.now, "Receiving objects", 10, Count)
+	clock.advance(3 * time.Second)
+	b.Set(5)
+
+	b.Phase("Resolving deltas", 20, Count)
+	if !strings.Contains(out.String(), "Receiving objects: done (5) in 3s") {
+		t.Errorf("previous phase not closed:\n%s", out.String())
+	}
+	clock.advance(3 * time.Second)
+	b.Set(10)
+	if !strings.Contains(out.String(), "Resolving deltas: 50% (10/20)") {
+		t.Errorf("new phase not reported:\n%s", out.String())
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"a": 10, filepath.Join("sub", "b"): 32} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := DirSize(dir); got != 42 {
+		t.Errorf("DirSize = %d, want 42", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		1536:            "1.5 KB",
+		5 * 1024 * 1024: "5.0 MB",
+	}
+	for in, want := range tests {
+		if got := formatBytes(in); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package quota
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// SelectAccount picks an account handle for a new session in rigName
+// according to the accounts policy. Returns "" when no policy is configured,
+// meaning the caller should fall back to the default account resolution.
+//
+// Selection order:
+//  1. Dedicated rig account (policy.rig_accounts)
+//  2. Least-recently-used available account (round-robin mode)
+//  3. Default account
+//
+// With failover enabled, a rate-limited choice is replaced by the
+// least-recently-used available account. If every account is limited the
+// original choice is kept so the session still starts (and can be rotated later).
+func (m *Manager) SelectAccount(acctCfg *config.AccountsConfig, state *config.QuotaState, rigName string) string {
+	if acctCfg == nil || acctCfg.Policy == nil || len(acctCfg.Accounts) == 0 {
+		return ""
+	}
+	policy := acctCfg.Policy
+
+	choice := acctCfg.Default
+	if dedicated, ok := policy.RigAccounts[rigName]; ok && dedicated != "" {
+		choice = dedicated
+	} else if policy.Mode == config.AccountPolicyRoundRobin {
+		if available := m.AvailableAccounts(state); len(available) > 0 {
+			choice = available[0]
+		}
+	}
+
+	if policy.Failover && choice != "" && state.Accounts[choice].Status == config.QuotaStatusLimited {
+		for _, candidate := range m.AvailableAccounts(state) {
+			if candidate != choice {
+				return candidate
+			}
+		}
+	}
+
+	return choice
+}
+
+// AssignAccount selects an account for a new session in rigName and records
+// it as used, so round-robin assignment advances across concurrent spawns.
+// Holds the quota lock for the whole read-select-write cycle.
+// Returns "" (and no error) when no policy is configured.
+func (m *Manager) AssignAccount(acctCfg *config.AccountsConfig, rigName string) (string, error) {
+	if acctCfg == nil || acctCfg.Policy == nil {
+		return "", nil
+	}
+
+	var handle string
+	err := m.WithLock(func() error {
+		state, err := m.Load()
+		if err != nil {
+			return err
+		}
+		m.EnsureAccountsTracked(state, acctCfg.Accounts)
+
+		handle = m.SelectAccount(acctCfg, state, rigName)
+		if handle == "" {
+			return nil
+		}
+
+		acctState := state.Accounts[handle]
+		acctState.LastUsed = time.Now().UTC().Format(time.RFC3339)
+		state.Accounts[handle] = acctState
+		return m.SaveUnlocked(state)
+	})
+	if err != nil {
+		return "", err
+	}
+	return handle, nil
+}
+
+// AccountUsage summarizes recent use of a single account.
+type AccountUsage struct {
+	Handle         string   `json:"handle"`
+	Status         string   `json:"status"`
+	LastUsed       string   `json:"last_used,omitempty"`
+	ResetsAt       string   `json:"resets_at,omitempty"`
+	ActiveSessions []string `json:"active_sessions,omitempty"`
+	DedicatedRigs  []string `json:"dedicated_rigs,omitempty"`
+	IsDefault      bool     `json:"is_default"`
+}
+
+// Usage builds a per-account usage summary from quota state and the latest
+// session scan. scanResults may be nil when sessions could not be listed.
+func Usage(acctCfg *config.AccountsConfig, state *config.QuotaState, scanResults []ScanResult) []AccountUsage {
+	sessionsByAccount := make(map[string][]string)
+	for _, r := range scanResults {
+		if r.AccountHandle != "" {
+			sessionsByAccount[r.AccountHandle] = append(sessionsByAccount[r.AccountHandle], r.Session)
+		}
+	}
+
+	rigsByAccount := make(map[string][]string)
+	if acctCfg.Policy != nil {
+		for rigName, handle := range acctCfg.Policy.RigAccounts {
+			rigsByAccount[handle] = append(rigsByAccount[handle], rigName)
+		}
+	}
+
+	var usage []AccountUsage
+	for _, handle := range slices.Sorted(maps.Keys(acctCfg.Accounts)) {
+		qs := state.Accounts[handle]
+		status := string(qs.Status)
+		if status == "" {
+			status = string(config.QuotaStatusAvailable)
+		}
+		sessions := sessionsByAccount[handle]
+		slices.Sort(sessions)
+		rigs := rigsByAccount[handle]
+		slices.Sort(rigs)
+		usage = append(usage, AccountUsage{
+			Handle:         handle,
+			Status:         status,
+			LastUsed:       qs.LastUsed,
+			ResetsAt:       qs.ResetsAt,
+			ActiveSessions: sessions,
+			DedicatedRigs:  rigs,
+			IsDefault:      handle == acctCfg.Default,
+		})
+	}
+	return usage
+}
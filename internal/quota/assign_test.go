@@ -0,0 +1,135 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testAccountsConfig(policy *config.AccountPolicy) *config.AccountsConfig {
+	return &config.AccountsConfig{
+		Version: config.CurrentAccountsVersion,
+		Accounts: map[string]config.Account{
+			"work":     {ConfigDir: "/tmp/work"},
+			"personal": {ConfigDir: "/tmp/personal"},
+			"spare":    {ConfigDir: "/tmp/spare"},
+		},
+		Default: "work",
+		Policy:  policy,
+	}
+}
+
+func TestSelectAccount_NoPolicy(t *testing.T) {
+	mgr := NewManager(t.TempDir())
+	state := &config.QuotaState{Accounts: map[string]config.AccountQuotaState{}}
+	if got := mgr.SelectAccount(testAccountsConfig(nil), state, "gastown"); got != "" {
+		t.Errorf("SelectAccount() with no policy = %q, want empty", got)
+	}
+}
+
+func TestSelectAccount_Policies(t *testing.T) {
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"work":     {Status: config.QuotaStatusAvailable, LastUsed: "2025-01-03T00:00:00Z"},
+			"personal": {Status: config.QuotaStatusAvailable, LastUsed: "2025-01-01T00:00:00Z"},
+			"spare":    {Status: config.QuotaStatusLimited, LastUsed: "2024-12-01T00:00:00Z"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy *config.AccountPolicy
+		rig    string
+		want   string
+	}{
+		{"default mode uses default", &config.AccountPolicy{}, "gastown", "work"},
+		{"round-robin picks LRU available", &config.AccountPolicy{Mode: config.AccountPolicyRoundRobin}, "gastown", "personal"},
+		{"dedicated rig account wins", &config.AccountPolicy{
+			Mode:        config.AccountPolicyRoundRobin,
+			RigAccounts: map[string]string{"gastown": "work"},
+		}, "gastown", "work"},
+		{"dedicated account only for its rig", &config.AccountPolicy{
+			RigAccounts: map[string]string{"beads": "personal"},
+		}, "gastown", "work"},
+		{"limited dedicated kept without failover", &config.AccountPolicy{
+			RigAccounts: map[string]string{"gastown": "spare"},
+		}, "gastown", "spare"},
+		{"limited dedicated fails over", &config.AccountPolicy{
+			RigAccounts: map[string]string{"gastown": "spare"},
+			Failover:    true,
+		}, "gastown", "personal"},
+	}
+
+	mgr := NewManager(t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mgr.SelectAccount(testAccountsConfig(tt.policy), state, tt.rig); got != tt.want {
+				t.Errorf("SelectAccount() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignAccount_RoundRobinAdvances(t *testing.T) {
+	townRoot := setupTestTown(t)
+	mgr := NewManager(townRoot)
+	acctCfg := testAccountsConfig(&config.AccountPolicy{Mode: config.AccountPolicyRoundRobin})
+
+	if err := mgr.Save(&config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"work":     {Status: config.QuotaStatusAvailable, LastUsed: "2025-01-01T00:00:00Z"},
+			"personal": {Status: config.QuotaStatusAvailable, LastUsed: "2025-01-02T00:00:00Z"},
+			"spare":    {Status: config.QuotaStatusAvailable, LastUsed: "2025-01-03T00:00:00Z"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		handle, err := mgr.AssignAccount(acctCfg, "gastown")
+		if err != nil {
+			t.Fatalf("AssignAccount() error: %v", err)
+		}
+		got = append(got, handle)
+	}
+
+	want := []string{"work", "personal", "spare"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("AssignAccount() sequence = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	acctCfg := testAccountsConfig(&config.AccountPolicy{
+		RigAccounts: map[string]string{"gastown": "work"},
+	})
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"spare": {Status: config.QuotaStatusLimited, ResetsAt: "7pm"},
+		},
+	}
+	scans := []ScanResult{
+		{Session: "gt-gastown-p-toast", AccountHandle: "work"},
+		{Session: "gt-gastown-witness", AccountHandle: "work"},
+		{Session: "hq-mayor"},
+	}
+
+	usage := Usage(acctCfg, state, scans)
+	if len(usage) != 3 {
+		t.Fatalf("Usage() returned %d entries, want 3", len(usage))
+	}
+	// Sorted by handle: personal, spare, work
+	if usage[0].Handle != "personal" || usage[0].Status != string(config.QuotaStatusAvailable) {
+		t.Errorf("usage[0] = %+v, want personal/available", usage[0])
+	}
+	if usage[1].Handle != "spare" || usage[1].Status != string(config.QuotaStatusLimited) || usage[1].ResetsAt != "7pm" {
+		t.Errorf("usage[1] = %+v, want spare/limited resets 7pm", usage[1])
+	}
+	work := usage[2]
+	if !work.IsDefault || len(work.ActiveSessions) != 2 || len(work.DedicatedRigs) != 1 {
+		t.Errorf("usage[2] = %+v, want default with 2 sessions and 1 dedicated rig", work)
+	}
+}
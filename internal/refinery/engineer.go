@@ -18,12 +18,18 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/retry"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -84,12 +90,27 @@ type MergeQueueConfig struct {
 	// TestCommand is the command to run for testing.
 	TestCommand string `json:"test_command"`
 
+	// TestRules scope testing to the paths a merge changes: focused
+	// commands run before TestCommand, and a merge whose files all match
+	// skip rules is not tested. They can't be combined with Gates.
+	TestRules []*TestRule `json:"test_rules"`
+
 	// DeleteMergedBranches controls whether to delete branches after merge.
 	DeleteMergedBranches bool `json:"delete_merged_branches"`
 
 	// RetryFlakyTests is the number of times to retry flaky tests.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
+	// CacheTestResults skips tests for a re-queued branch whose commit and
+	// target commit already passed.
+	CacheTestResults bool `json:"cache_test_results"`
+
+	// FlakeQuarantineThreshold is the number of distinct branches a test must
+	// flake on (fail, then pass on retry) before it is quarantined: its
+	// failures stop blocking merges and a bead is opened to fix it.
+	// Zero disables quarantine; flakes are still recorded.
+	FlakeQuarantineThreshold int `json:"flake_quarantine_threshold"`
+
 	// PollInterval is how often to check for new MRs.
 	PollInterval time.Duration `json:"poll_interval"`
 
@@ -104,6 +125,19 @@ type MergeQueueConfig struct {
 	// in manager.go), so concurrent re-claim is not a concern in practice.
 	StaleClaimTimeout time.Duration `json:"stale_claim_timeout"`
 
+	// ExpediteLabel is the MR bead label that puts an MR in the expedite
+	// lane, ahead of the normal lane.
+	ExpediteLabel string `json:"expedite_label"`
+
+	// ExpediteMaxConcurrent is MaxConcurrent for the expedite lane; the
+	// normal lane uses MaxConcurrent. Zero means no limit.
+	ExpediteMaxConcurrent int `json:"expedite_max_concurrent"`
+
+	// NormalLaneMaxWait is how long a normal MR can wait before it goes
+	// ahead of expedited MRs, so a stream of hotfixes can't starve the
+	// normal lane. Zero disables the protection.
+	NormalLaneMaxWait time.Duration `json:"normal_lane_max_wait"`
+
 	// Gates defines named quality gate commands to run before merging.
 	// When non-empty, gates replace the legacy RunTests/TestCommand path.
 	// Each gate runs as a shell command with an optional per-gate timeout.
@@ -112,20 +146,35 @@ type MergeQueueConfig struct {
 	// GatesParallel controls whether gates run concurrently.
 	// When true, all gates start simultaneously; any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// PreMerge lists checks run in order after gates/tests and before the
+	// merge commit is made. A failing required check fails the MR.
+	PreMerge []*MergeHook `json:"pre_merge"`
+
+	// PostMerge lists actions run in order after a successful push, such as
+	// a deploy trigger. Failures are reported but never undo the merge.
+	PostMerge []*MergeHook `json:"post_merge"`
+
+	// Release controls release tagging of the default branch.
+	Release ReleaseConfig `json:"release"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
-		Enabled:              true,
-		OnConflict:           "assign_back",
-		RunTests:             true,
-		TestCommand:          "",
-		DeleteMergedBranches: true,
-		RetryFlakyTests:      1,
-		PollInterval:         30 * time.Second,
-		MaxConcurrent:        1,
-		StaleClaimTimeout:    DefaultStaleClaimTimeout,
+		Enabled:               true,
+		OnConflict:            "assign_back",
+		RunTests:              true,
+		TestCommand:           "",
+		DeleteMergedBranches:  true,
+		RetryFlakyTests:       1,
+		CacheTestResults:      true,
+		PollInterval:          30 * time.Second,
+		MaxConcurrent:         1,
+		StaleClaimTimeout:     DefaultStaleClaimTimeout,
+		ExpediteLabel:         DefaultExpediteLabel,
+		ExpediteMaxConcurrent: 1,
+		NormalLaneMaxWait:     DefaultNormalLaneMaxWait,
 	}
 }
 
@@ -146,6 +195,7 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	Lane            Lane       // Merge queue lane (normal or expedite)
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -194,8 +244,8 @@ type Engineer struct {
 	mergeSlotEnsureExists func() (string, error)
 	mergeSlotAcquire      func(holder string, addWaiter bool) (*beads.MergeSlotStatus, error)
 	mergeSlotRelease      func(holder string) error
-	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
-	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	mergeSlotRetry        retry.Policy // Retries while another rig holds the slot
+	testResults           *TestResultStore
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -228,8 +278,8 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		mergeSlotRelease: func(holder string) error {
 			return beadsClient.MergeSlotRelease(holder)
 		},
-		mergeSlotMaxRetries:   10,
-		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		mergeSlotRetry: doltserver.OperationPolicy(filepath.Dir(r.Path), doltserver.RetryMergeSlot),
+		testResults:    NewTestResultStore(r.Path),
 	}
 }
 
@@ -267,17 +317,26 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool                      `json:"enabled"`
-		OnConflict           *string                    `json:"on_conflict"`
-		RunTests             *bool                      `json:"run_tests"`
-		TestCommand          *string                    `json:"test_command"`
-		DeleteMergedBranches *bool                      `json:"delete_merged_branches"`
-		RetryFlakyTests      *int                       `json:"retry_flaky_tests"`
-		PollInterval         *string                    `json:"poll_interval"`
-		MaxConcurrent        *int                       `json:"max_concurrent"`
-		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
-		Gates                map[string]*gateConfigRaw  `json:"gates"`
-		GatesParallel        *bool                      `json:"gates_parallel"`
+		Enabled               *bool                     `json:"enabled"`
+		OnConflict            *string                   `json:"on_conflict"`
+		RunTests              *bool                     `json:"run_tests"`
+		TestCommand           *string                   `json:"test_command"`
+		TestRules             []*TestRule               `json:"test_rules"`
+		DeleteMergedBranches  *bool                     `json:"delete_merged_branches"`
+		RetryFlakyTests       *int                      `json:"retry_flaky_tests"`
+		CacheTestResults      *bool                     `json:"cache_test_results"`
+		FlakeQuarantine       *int                      `json:"flake_quarantine_threshold"`
+		PollInterval          *string                   `json:"poll_interval"`
+		MaxConcurrent         *int                      `json:"max_concurrent"`
+		StaleClaimTimeout     *string                   `json:"stale_claim_timeout"`
+		ExpediteLabel         *string                   `json:"expedite_label"`
+		ExpediteMaxConcurrent *int                      `json:"expedite_max_concurrent"`
+		NormalLaneMaxWait     *string                   `json:"normal_lane_max_wait"`
+		Gates                 map[string]*gateConfigRaw `json:"gates"`
+		GatesParallel         *bool                     `json:"gates_parallel"`
+		PreMerge              []*mergeHookRaw           `json:"pre_merge"`
+		PostMerge             []*mergeHookRaw           `json:"post_merge"`
+		Release               *ReleaseConfig            `json:"release"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -297,12 +356,28 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.TestCommand != nil {
 		e.config.TestCommand = *mqRaw.TestCommand
 	}
+	if mqRaw.TestRules != nil {
+		rules, err := parseTestRules(mqRaw.TestRules)
+		if err != nil {
+			return err
+		}
+		e.config.TestRules = rules
+	}
 	if mqRaw.DeleteMergedBranches != nil {
 		e.config.DeleteMergedBranches = *mqRaw.DeleteMergedBranches
 	}
 	if mqRaw.RetryFlakyTests != nil {
 		e.config.RetryFlakyTests = *mqRaw.RetryFlakyTests
 	}
+	if mqRaw.CacheTestResults != nil {
+		e.config.CacheTestResults = *mqRaw.CacheTestResults
+	}
+	if mqRaw.FlakeQuarantine != nil {
+		if *mqRaw.FlakeQuarantine < 0 {
+			return fmt.Errorf("flake_quarantine_threshold must be non-negative, got %d", *mqRaw.FlakeQuarantine)
+		}
+		e.config.FlakeQuarantineThreshold = *mqRaw.FlakeQuarantine
+	}
 	if mqRaw.MaxConcurrent != nil {
 		e.config.MaxConcurrent = *mqRaw.MaxConcurrent
 	}
@@ -323,6 +398,25 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.StaleClaimTimeout = dur
 	}
+	if mqRaw.ExpediteLabel != nil && strings.TrimSpace(*mqRaw.ExpediteLabel) != "" {
+		e.config.ExpediteLabel = strings.TrimSpace(*mqRaw.ExpediteLabel)
+	}
+	if mqRaw.ExpediteMaxConcurrent != nil {
+		if *mqRaw.ExpediteMaxConcurrent < 0 {
+			return fmt.Errorf("expedite_max_concurrent must be non-negative, got %d", *mqRaw.ExpediteMaxConcurrent)
+		}
+		e.config.ExpediteMaxConcurrent = *mqRaw.ExpediteMaxConcurrent
+	}
+	if mqRaw.NormalLaneMaxWait != nil {
+		dur, err := time.ParseDuration(*mqRaw.NormalLaneMaxWait)
+		if err != nil {
+			return fmt.Errorf("invalid normal_lane_max_wait %q: %w", *mqRaw.NormalLaneMaxWait, err)
+		}
+		if dur < 0 {
+			return fmt.Errorf("normal_lane_max_wait must be non-negative, got %v", dur)
+		}
+		e.config.NormalLaneMaxWait = dur
+	}
 
 	// Parse gates configuration
 	if mqRaw.Gates != nil {
@@ -345,6 +439,34 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	// Test rules scope test_command, which gates replace; accepting both
+	// would silently ignore the rules.
+	if len(e.config.Gates) > 0 && len(e.config.TestRules) > 0 {
+		return fmt.Errorf("test_rules cannot be combined with gates: scope the gate commands instead")
+	}
+
+	// Parse merge hook pipelines
+	if mqRaw.PreMerge != nil {
+		hooks, err := parseMergeHooks(HookPhasePreMerge, mqRaw.PreMerge)
+		if err != nil {
+			return err
+		}
+		e.config.PreMerge = hooks
+	}
+	if mqRaw.PostMerge != nil {
+		hooks, err := parseMergeHooks(HookPhasePostMerge, mqRaw.PostMerge)
+		if err != nil {
+			return err
+		}
+		e.config.PostMerge = hooks
+	}
+
+	if mqRaw.Release != nil {
+		if mqRaw.Release.Every < 0 {
+			return fmt.Errorf("release.every must be non-negative, got %d", mqRaw.Release.Every)
+		}
+		e.config.Release = *mqRaw.Release
+	}
 
 	return nil
 }
@@ -369,6 +491,9 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	HookResults []HookResult // Pre/post-merge hooks that ran, in order
+	TestRuns    []TestRun    // Test commands that ran, in order
+	TestSkipped string       // Why testing was skipped by test rules, if it was
 }
 
 // doMerge performs the actual git merge operation.
@@ -404,6 +529,14 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
 	}
 
+	// Step 2.5: Check commit signatures and set up signing for the merge commit
+	if err := e.signingPreflight(target, branch); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
 	conflicts, err := e.git.CheckConflicts(branch, target)
@@ -452,25 +585,64 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
 	}
 
-	// Step 4: Run quality gates (or legacy tests) if configured
-	if len(e.config.Gates) > 0 {
+	// Step 4: Run quality gates (or legacy tests) if configured.
+	// A re-queued branch whose commit and target are unchanged already passed.
+	var testResult ProcessResult
+	testsConfigured := len(e.config.Gates) > 0 || (e.config.RunTests && (e.config.TestCommand != "" || len(e.config.TestRules) > 0))
+	var branchSHA, targetSHA string
+	if testsConfigured && e.config.CacheTestResults {
+		branchSHA, _ = e.git.Rev(branch)
+		targetSHA, _ = e.git.Rev("HEAD")
+	}
+	if testsConfigured && e.config.CacheTestResults && e.testResults.HasPassed(branch, branchSHA, targetSHA) {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Tests already passed for %s at %s on %s, skipping\n", branch, shortSHA(branchSHA), shortSHA(targetSHA))
+	} else if len(e.config.Gates) > 0 {
 		// New gates system: run configured quality gates
 		gateResult := e.runGates(ctx)
 		if !gateResult.Success {
 			return gateResult
 		}
-	} else if e.config.RunTests && e.config.TestCommand != "" {
-		// Legacy test command path (backward compatible)
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
+		e.recordTestPass(branch, branchSHA, targetSHA)
+	} else if testsConfigured {
+		// Legacy test command path, scoped by test rules to the changed files
+		var files []string
+		if len(e.config.TestRules) > 0 {
+			if files, err = e.git.ChangedFiles(target, branch); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not list changed files, running full suite: %v\n", err)
+				files = nil
+			}
+		}
+		plan := planTests(e.config.TestRules, e.config.TestCommand, files)
+		if plan.SkipReason != "" {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Skipping tests: %s\n", plan.SkipReason)
+			testResult.TestSkipped = plan.SkipReason
+		} else {
+			result := e.runTestPlan(ctx, branch, plan)
+			if !result.Success {
+				return ProcessResult{
+					Success:     false,
+					TestsFailed: true,
+					Error:       result.Error,
+					TestRuns:    result.TestRuns,
+				}
 			}
+			testResult.TestRuns = result.TestRuns
+			_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+		}
+		e.recordTestPass(branch, branchSHA, targetSHA)
+	}
+
+	// Step 4.5: Run pre-merge hooks in order.
+	hookEnv := mergeHookEnv(e.rig.Name, branch, target, sourceIssue, "")
+	hookResults, hookFailed := e.runMergeHooks(ctx, HookPhasePreMerge, e.pipelineHooks(HookPhasePreMerge), hookEnv)
+	if hookFailed != nil {
+		return ProcessResult{
+			Success:     false,
+			Error:       fmt.Sprintf("pre-merge hook %q failed: %s", hookFailed.Name, hookFailed.Error),
+			HookResults: hookResults,
+			TestRuns:    testResult.TestRuns,
+			TestSkipped: testResult.TestSkipped,
 		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
 	// Step 5: Perform the actual merge using squash merge
@@ -485,6 +657,8 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
 	}
+	// Tag the squash commit with its bead so changelogs can trace it.
+	originalMsg = changelog.WithTrailer(originalMsg, sourceIssue)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
 	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
@@ -561,9 +735,17 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
+
+	// Step 9: Run post-merge hooks. The merge has landed, so a failure here
+	// is recorded but does not fail the MR.
+	postResults, _ := e.runMergeHooks(ctx, HookPhasePostMerge, e.pipelineHooks(HookPhasePostMerge),
+		mergeHookEnv(e.rig.Name, branch, target, sourceIssue, mergeCommit))
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		HookResults: append(hookResults, postResults...),
+		TestRuns:    testResult.TestRuns,
+		TestSkipped: testResult.TestSkipped,
 	}
 }
 
@@ -582,40 +764,42 @@ func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	// safely proceed without re-acquiring — no concurrent push is possible.
 	selfConflictHolder := e.rig.Name + "/refinery"
 
-	backoff := e.mergeSlotRetryBackoff
-	if backoff == 0 {
-		backoff = 500 * time.Millisecond
+	policy := e.mergeSlotRetry
+	maxRetries := policy.MaxAttempts - 1
+	policy.OnRetry = func(a retry.Attempt) {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merge slot held, retrying in %v (attempt %d/%d)...\n", a.Delay.Round(time.Millisecond), a.Number, maxRetries)
 	}
 
-	for attempt := 0; attempt <= e.mergeSlotMaxRetries; attempt++ {
-		if attempt > 0 {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Merge slot held, retrying in %v (attempt %d/%d)...\n", backoff, attempt, e.mergeSlotMaxRetries)
-			select {
-			case <-time.After(backoff):
-			case <-ctx.Done():
-				return "", ctx.Err()
-			}
-			backoff = min(backoff*2, 10*time.Second)
-		}
-
+	var acquired string
+	err = retry.Do(ctx, policy, func() error {
 		status, err := e.mergeSlotAcquire(holder, false)
 		if err != nil {
-			return "", fmt.Errorf("acquire merge slot %s (%s): %w", slotID, holder, err)
+			return retry.Stop(fmt.Errorf("acquire merge slot %s (%s): %w", slotID, holder, err))
 		}
 		if status == nil {
-			return "", fmt.Errorf("acquire merge slot %s (%s): empty status", slotID, holder)
+			return retry.Stop(fmt.Errorf("acquire merge slot %s (%s): empty status", slotID, holder))
 		}
 		if status.Available || status.Holder == holder {
-			return holder, nil
+			acquired = holder
+			return nil
 		}
 		// Slot held by our own conflict-resolution path — safe to proceed.
 		if status.Holder == selfConflictHolder {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Merge slot held by conflict-resolution path, proceeding\n")
-			return "", nil // No holder to release — conflict-resolution owns the slot
+			return nil // No holder to release — conflict-resolution owns the slot
+		}
+		return errMergeSlotTimeout
+	})
+	if errors.Is(err, errMergeSlotTimeout) {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
 		}
+		return "", fmt.Errorf("merge slot %s: %w after %d retries", slotID, errMergeSlotTimeout, maxRetries)
 	}
-
-	return "", fmt.Errorf("merge slot %s: %w after %d retries", slotID, errMergeSlotTimeout, e.mergeSlotMaxRetries)
+	if err != nil {
+		return "", err
+	}
+	return acquired, nil
 }
 
 // ValidateTestCommand validates that a test command is safe to execute.
@@ -629,15 +813,20 @@ func ValidateTestCommand(cmd string) error {
 	return nil
 }
 
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
-	if err := ValidateTestCommand(e.config.TestCommand); err != nil {
+// runTestCommand runs a test command and returns the result.
+// A test that fails and then passes on retry is recorded as a flake on branch;
+// a failing run whose failed tests are all quarantined counts as a pass.
+func (e *Engineer) runTestCommand(ctx context.Context, branch, testCmd string) (res ProcessResult) {
+	if err := ValidateTestCommand(testCmd); err != nil {
 		return ProcessResult{
 			Success: false,
 			Error:   fmt.Sprintf("invalid test command: %v", err),
 		}
 	}
 
+	ctx, span := telemetry.Start(ctx, "refinery.tests", attribute.String("refinery.branch", branch))
+	defer func() { endResultSpan(span, res) }()
+
 	// Run the test command with retries for flaky tests
 	maxRetries := e.config.RetryFlakyTests
 	if maxRetries < 1 {
@@ -645,16 +834,17 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var prevFailed []string
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
 		}
 
-		// Trust boundary: TestCommand comes from rig's config.json (operator-controlled
+		// Trust boundary: test commands come from rig's config.json (operator-controlled
 		// infrastructure config), not from PR branches or user input. Shell execution
 		// is intentional for flexibility (pipes, env vars, etc).
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Executing test command: %s\n", e.config.TestCommand)
-		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Executing test command: %s\n", testCmd)
+		cmd := exec.CommandContext(ctx, "sh", "-c", testCmd) //nolint:gosec // G204: test commands are from trusted rig config
 		cmd.Dir = e.workDir
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
@@ -662,10 +852,20 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 
 		err := cmd.Run()
 		if err == nil {
+			if len(prevFailed) > 0 {
+				e.recordFlakes(prevFailed, branch)
+			}
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
 
+		failed := failedTests(stdout.String() + "\n" + stderr.String())
+		if e.allQuarantined(failed) {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Only quarantined tests failed (%s), treating as passed\n", strings.Join(failed, ", "))
+			return ProcessResult{Success: true}
+		}
+		prevFailed = failed
+
 		// Check if context was canceled
 		if ctx.Err() != nil {
 			return ProcessResult{
@@ -684,6 +884,11 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 
 // runGate executes a single quality gate command and returns the result.
 func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+	return e.runGateWithEnv(ctx, name, gate, nil)
+}
+
+// runGateWithEnv is runGate with extra environment variables for the command.
+func (e *Engineer) runGateWithEnv(ctx context.Context, name string, gate *GateConfig, env []string) GateResult {
 	start := time.Now()
 
 	if strings.TrimSpace(gate.Cmd) == "" {
@@ -705,6 +910,9 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 
 	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
 	cmd.Dir = e.workDir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -743,7 +951,10 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 // runGates executes all configured quality gates and returns a ProcessResult.
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
 // Any single gate failure means overall failure.
-func (e *Engineer) runGates(ctx context.Context) ProcessResult {
+func (e *Engineer) runGates(ctx context.Context) (res ProcessResult) {
+	ctx, span := telemetry.Start(ctx, "refinery.gates", attribute.Int("refinery.gates", len(e.config.Gates)))
+	defer func() { endResultSpan(span, res) }()
+
 	gates := e.config.Gates
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
@@ -848,8 +1059,25 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	ctx, span := telemetry.Start(ctx, "refinery.merge",
+		attribute.String("refinery.branch", mr.Branch),
+		attribute.String("refinery.target", mr.Target),
+		attribute.String("refinery.worker", mr.Worker))
+
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	result := e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	endResultSpan(span, result)
+	return result
+}
+
+// endResultSpan ends a span for a step reporting its outcome as a
+// ProcessResult, marking the span failed when the step failed.
+func endResultSpan(span trace.Span, result ProcessResult) {
+	var err error
+	if !result.Success {
+		err = errors.New(result.Error)
+	}
+	telemetry.End(span, err)
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -879,11 +1107,18 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			}
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
+			if len(result.HookResults) > 0 {
+				mrFields.HookResults = FormatHookResults(result.HookResults)
+			}
+			if runs := testRunsSummary(result); runs != "" {
+				mrFields.TestRuns = runs
+			}
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
 			}
 		}
+		e.logMergeEvent(events.TypeMerged, mr, "")
 
 		// Close MR bead with reason 'merged'
 		if err := e.beads.CloseWithReason("merged", mr.ID); err != nil {
@@ -929,6 +1164,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// Run convoy check to auto-close and notify subscribers.
 	e.postMergeConvoyCheck(mr)
 
+	// 3.5. Cut a release if enough MRs have merged since the last one
+	e.maybeCutRelease(mr)
+
 	// 4. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
@@ -947,6 +1185,9 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	e.recordRunResults(mr.ID, result)
+	e.logMergeEvent(events.TypeMergeFailed, mr, result.Error)
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -988,6 +1229,17 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// logMergeEvent records a merge outcome in the town events log so the feed
+// and gt rig watch can report it. Best-effort.
+func (e *Engineer) logMergeEvent(eventType string, mr *MRInfo, reason string) {
+	if e.rig == nil || e.rig.Path == "" {
+		return
+	}
+	townRoot := filepath.Dir(e.rig.Path)
+	_ = events.LogAt(townRoot, eventType, e.rig.Name+"/refinery",
+		events.MergePayload(mr.ID, mr.Worker, mr.Branch, reason), events.VisibilityFeed)
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1172,9 +1424,11 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 }
 
 // firstOpenBlocker returns the ID of the first open blocker for an issue,
-// or empty string if none are open.
+// or empty string if none are open. Cross-rig blockers arrive wrapped as
+// "external:prefix:id" and are unwrapped so Show can route them.
 func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 	for _, blockerID := range issue.BlockedBy {
+		blockerID = beads.ExtractIssueID(blockerID)
 		isOpen, err := e.IsBeadOpen(blockerID)
 		if err == nil && isOpen {
 			return blockerID
@@ -1186,12 +1440,22 @@ func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
 // - Not blocked by an open task (checked via firstOpenBlocker)
-// Sorted by priority (highest first).
+// Sorted by lane, then priority (highest first): expedited MRs go ahead of
+// normal ones unless a normal MR has waited longer than NormalLaneMaxWait.
+// MRs in a lane that already has its limit of claimed MRs in flight are
+// left out until one finishes. Nothing is ready while the queue is paused
+// (see PauseQueue).
 //
 // Uses bd list instead of bd ready because MRs are ephemeral beads and
 // bd ready filters out ephemeral issues (see gt-t5t6y). This matches the
 // pattern used by ListBlockedMRs and ListAllOpenMRs.
 func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
+	if pause := e.Paused(); pause != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merge queue paused since %s: holding all MRs\n",
+			pause.Since.Format(time.RFC3339))
+		return nil, nil
+	}
+
 	// Query beads for all open merge-request issues.
 	// Cannot use ReadyWithType here because bd ready excludes ephemeral beads,
 	// and MRs are ephemeral by design. Use List + manual blocker check instead.
@@ -1206,11 +1470,13 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 
 	// Convert beads issues to MRInfo
 	var mrs []*MRInfo
+	inFlight := make(map[Lane]int)
 	for _, issue := range issues {
 		// Skip closed MRs (workaround for bd list not respecting --status filter)
 		if issue.Status != "open" {
 			continue
 		}
+		lane := e.config.LaneOf(issue.Labels)
 
 		// Skip blocked MRs (replaces bd ready's blocker filtering)
 		if blockedBy := e.firstOpenBlocker(issue); blockedBy != "" {
@@ -1240,16 +1506,32 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 					issue.ID, parseErr)
 			}
 			if !stale {
+				inFlight[lane]++
 				continue
 			}
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Stale claim detected: %s (assignee: %s, updated: %s) — eligible for re-claim\n",
 				issue.ID, issue.Assignee, issue.UpdatedAt)
 		}
 
-		mrs = append(mrs, issueToMRInfo(issue, fields))
+		mr := issueToMRInfo(issue, fields)
+		mr.Lane = lane
+		mrs = append(mrs, mr)
 	}
 
-	return mrs, nil
+	ready, deferred := orderByLane(mrs, inFlight, e.config, time.Now())
+	if len(deferred) > 0 {
+		counts := make(map[Lane]int)
+		for _, mr := range deferred {
+			counts[mr.Lane]++
+		}
+		for _, lane := range []Lane{LaneExpedite, LaneNormal} {
+			if counts[lane] > 0 {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] %s lane at limit (%d in flight): holding %d MR(s)\n",
+					lane, inFlight[lane], counts[lane])
+			}
+		}
+	}
+	return ready, nil
 }
 
 // ListBlockedMRs returns MRs that are blocked by open tasks.
@@ -1417,6 +1699,9 @@ func detectQueueAnomalies(
 // This replaces mrqueue.Claim() for beads-based MRs.
 // The workerID is typically the refinery's identifier (e.g., "gastown/refinery").
 func (e *Engineer) ClaimMR(mrID, workerID string) error {
+	if e.Paused() != nil {
+		return fmt.Errorf("%w: not claiming %s (gt mq resume %s)", ErrQueuePaused, mrID, e.rig.Name)
+	}
 	return e.beads.Update(mrID, beads.UpdateOptions{
 		Assignee: &workerID,
 	})
@@ -1492,7 +1777,8 @@ func (e *Engineer) notifyDeaconConvoyFeeding(mr *MRInfo) {
 
 	// Emit event to wake deacon from await-signal (router.Send doesn't write
 	// to .events.jsonl, but await-signal watches the events file).
-	_ = events.LogFeed(events.TypeMail, e.rig.Name+"/refinery", events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID))
+	_ = events.LogAt(filepath.Dir(e.rig.Path), events.TypeMail, e.rig.Name+"/refinery",
+		events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID), events.VisibilityFeed)
 }
 
 // convoyInfo holds minimal info about a closed convoy for post-merge processing.
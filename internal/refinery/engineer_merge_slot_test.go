@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/retry"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -43,10 +44,9 @@ func TestAcquireMainPushSlot_RetrySuccess(t *testing.T) {
 	var attempts int
 
 	e := &Engineer{
-		rig:                   &rig.Rig{Name: "testrig"},
-		output:                io.Discard,
-		mergeSlotMaxRetries:   3,
-		mergeSlotRetryBackoff: time.Millisecond, // Fast for tests
+		rig:            &rig.Rig{Name: "testrig"},
+		output:         io.Discard,
+		mergeSlotRetry: retry.Policy{MaxAttempts: 4, Initial: time.Millisecond}, // Fast for tests
 		mergeSlotEnsureExists: func() (string, error) {
 			return "merge-slot", nil
 		},
@@ -74,10 +74,9 @@ func TestAcquireMainPushSlot_RetrySuccess(t *testing.T) {
 
 func TestAcquireMainPushSlot_MaxRetriesExceeded(t *testing.T) {
 	e := &Engineer{
-		rig:                   &rig.Rig{Name: "testrig"},
-		output:                io.Discard,
-		mergeSlotMaxRetries:   2,
-		mergeSlotRetryBackoff: time.Millisecond,
+		rig:            &rig.Rig{Name: "testrig"},
+		output:         io.Discard,
+		mergeSlotRetry: retry.Policy{MaxAttempts: 3, Initial: time.Millisecond},
 		mergeSlotEnsureExists: func() (string, error) {
 			return "merge-slot", nil
 		},
@@ -125,10 +124,9 @@ func TestAcquireMainPushSlot_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	e := &Engineer{
-		rig:                   &rig.Rig{Name: "testrig"},
-		output:                io.Discard,
-		mergeSlotMaxRetries:   10,
-		mergeSlotRetryBackoff: time.Second, // Slow enough to allow cancellation
+		rig:            &rig.Rig{Name: "testrig"},
+		output:         io.Discard,
+		mergeSlotRetry: retry.Policy{MaxAttempts: 11, Initial: time.Second}, // Slow enough to allow cancellation
 		mergeSlotEnsureExists: func() (string, error) {
 			return "merge-slot", nil
 		},
@@ -165,10 +163,9 @@ func TestAcquireMainPushSlot_ConcurrentSingleWriter(t *testing.T) {
 	currentHolder := ""
 
 	e := &Engineer{
-		rig:                   &rig.Rig{Name: "testrig"},
-		output:                io.Discard,
-		mergeSlotMaxRetries:   0, // No retry — fail immediately if held
-		mergeSlotRetryBackoff: time.Millisecond,
+		rig:            &rig.Rig{Name: "testrig"},
+		output:         io.Discard,
+		mergeSlotRetry: retry.Policy{MaxAttempts: 1, Initial: time.Millisecond}, // No retry — fail immediately if held
 		mergeSlotEnsureExists: func() (string, error) {
 			return "merge-slot", nil
 		},
@@ -247,10 +244,9 @@ func TestAcquireMainPushSlot_BackoffConverges(t *testing.T) {
 	var attempts int
 
 	e := &Engineer{
-		rig:                   &rig.Rig{Name: "testrig"},
-		output:                io.Discard,
-		mergeSlotMaxRetries:   6,
-		mergeSlotRetryBackoff: time.Millisecond, // Use millisecond to keep test fast
+		rig:            &rig.Rig{Name: "testrig"},
+		output:         io.Discard,
+		mergeSlotRetry: retry.Policy{MaxAttempts: 7, Initial: time.Millisecond}, // Use millisecond to keep test fast
 		mergeSlotEnsureExists: func() (string, error) {
 			return "merge-slot", nil
 		},
@@ -52,8 +52,8 @@ func TestValidateTestCommand(t *testing.T) {
 	}
 }
 
-func TestRunTests_EmptyCommand(t *testing.T) {
-	// Verify that runTests returns a failure when TestCommand is empty,
+func TestRunTestCommand_EmptyCommand(t *testing.T) {
+	// Verify that runTestCommand returns a failure when TestCommand is empty,
 	// rather than silently succeeding or executing a blank shell command.
 	e := &Engineer{
 		config: &MergeQueueConfig{
@@ -61,7 +61,7 @@ func TestRunTests_EmptyCommand(t *testing.T) {
 		},
 	}
 
-	result := e.runTests(nil)
+	result := e.runTestCommand(nil, "", e.config.TestCommand)
 	if result.Success {
 		t.Error("expected failure for empty test command, got success")
 	}
@@ -70,14 +70,14 @@ func TestRunTests_EmptyCommand(t *testing.T) {
 	}
 }
 
-func TestRunTests_WhitespaceCommand(t *testing.T) {
+func TestRunTestCommand_WhitespaceCommand(t *testing.T) {
 	e := &Engineer{
 		config: &MergeQueueConfig{
 			TestCommand: "   ",
 		},
 	}
 
-	result := e.runTests(nil)
+	result := e.runTestCommand(nil, "", e.config.TestCommand)
 	if result.Success {
 		t.Error("expected failure for whitespace-only test command, got success")
 	}
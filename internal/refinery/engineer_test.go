@@ -360,6 +360,29 @@ func TestEngineer_LoadConfig_GateInvalidTimeout(t *testing.T) {
 	}
 }
 
+func TestEngineer_LoadConfig_GatesWithTestRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"gates": map[string]interface{}{
+				"test": map[string]interface{}{"cmd": "go test ./..."},
+			},
+			"test_rules": []interface{}{
+				map[string]interface{}{"paths": []string{"docs/**"}, "skip": true},
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err == nil || !strings.Contains(err.Error(), "test_rules") {
+		t.Errorf("LoadConfig() error = %v, want test_rules/gates conflict", err)
+	}
+}
+
 func TestRunGate_Success(t *testing.T) {
 	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
 	e := NewEngineer(r)
@@ -0,0 +1,91 @@
+package refinery
+
+import (
+	"sort"
+	"time"
+)
+
+// Lane is a merge queue lane. Expedited MRs (hotfixes) are processed ahead
+// of the normal lane, each lane with its own concurrency limit.
+type Lane string
+
+const (
+	LaneNormal   Lane = "normal"
+	LaneExpedite Lane = "expedite"
+)
+
+// DefaultExpediteLabel is the MR bead label that puts an MR in the expedite
+// lane. 'gt mq promote' adds it; rigs can choose another via
+// merge_queue.expedite_label.
+const DefaultExpediteLabel = "gt:expedite"
+
+// DefaultNormalLaneMaxWait is how long a normal MR can wait before it is
+// processed ahead of expedited MRs.
+const DefaultNormalLaneMaxWait = 2 * time.Hour
+
+// LaneOf returns the lane for an MR bead with the given labels.
+func (c *MergeQueueConfig) LaneOf(labels []string) Lane {
+	label := c.ExpediteLabel
+	if label == "" {
+		label = DefaultExpediteLabel
+	}
+	for _, l := range labels {
+		if l == label {
+			return LaneExpedite
+		}
+	}
+	return LaneNormal
+}
+
+// LaneLimit returns how many MRs in lane may be in flight at once.
+// Zero means no limit.
+func (c *MergeQueueConfig) LaneLimit(lane Lane) int {
+	if lane == LaneExpedite {
+		return c.ExpediteMaxConcurrent
+	}
+	return c.MaxConcurrent
+}
+
+// Starving reports whether a normal-lane MR created at createdAt has waited
+// long enough to go ahead of the expedite lane.
+func (c *MergeQueueConfig) Starving(lane Lane, createdAt, now time.Time) bool {
+	return lane == LaneNormal && c.NormalLaneMaxWait > 0 &&
+		!createdAt.IsZero() && now.Sub(createdAt) >= c.NormalLaneMaxWait
+}
+
+// LaneRank orders MRs across lanes: starving normal MRs first, then the
+// expedite lane, then the rest of the normal lane. Lower ranks go first.
+func (c *MergeQueueConfig) LaneRank(lane Lane, createdAt, now time.Time) int {
+	switch {
+	case c.Starving(lane, createdAt, now):
+		return 0
+	case lane == LaneExpedite:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// orderByLane sorts ready MRs into processing order and drops those whose
+// lane is already at its limit. inFlight counts claimed MRs per lane.
+// Within a rank, MRs keep score order (highest first). The dropped MRs are
+// returned as deferred.
+func orderByLane(ready []*MRInfo, inFlight map[Lane]int, cfg *MergeQueueConfig, now time.Time) (ordered, deferred []*MRInfo) {
+	for _, mr := range ready {
+		if limit := cfg.LaneLimit(mr.Lane); limit > 0 && inFlight[mr.Lane] >= limit {
+			deferred = append(deferred, mr)
+			continue
+		}
+		ordered = append(ordered, mr)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri := cfg.LaneRank(ordered[i].Lane, ordered[i].CreatedAt, now)
+		rj := cfg.LaneRank(ordered[j].Lane, ordered[j].CreatedAt, now)
+		if ri != rj {
+			return ri < rj
+		}
+		return ordered[i].ScoreAt(now) > ordered[j].ScoreAt(now)
+	})
+	return ordered, deferred
+}
@@ -0,0 +1,174 @@
+package refinery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestLaneOf(t *testing.T) {
+	cfg := DefaultMergeQueueConfig()
+	if got := cfg.LaneOf([]string{"gt:merge-request", DefaultExpediteLabel}); got != LaneExpedite {
+		t.Errorf("LaneOf(default label) = %s, want expedite", got)
+	}
+	if got := cfg.LaneOf([]string{"gt:merge-request"}); got != LaneNormal {
+		t.Errorf("LaneOf(no label) = %s, want normal", got)
+	}
+
+	cfg.ExpediteLabel = "hotfix"
+	if got := cfg.LaneOf([]string{DefaultExpediteLabel}); got != LaneNormal {
+		t.Errorf("LaneOf(default label with custom config) = %s, want normal", got)
+	}
+	if got := cfg.LaneOf([]string{"hotfix"}); got != LaneExpedite {
+		t.Errorf("LaneOf(custom label) = %s, want expedite", got)
+	}
+}
+
+func laneIDs(mrs []*MRInfo) []string {
+	ids := make([]string, len(mrs))
+	for i, mr := range mrs {
+		ids[i] = mr.ID
+	}
+	return ids
+}
+
+func TestOrderByLane(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	mr := func(id string, lane Lane, age time.Duration) *MRInfo {
+		return &MRInfo{ID: id, Lane: lane, Priority: 2, CreatedAt: now.Add(-age)}
+	}
+
+	tests := []struct {
+		name         string
+		ready        []*MRInfo
+		inFlight     map[Lane]int
+		maxWait      time.Duration
+		wantOrder    []string
+		wantDeferred []string
+	}{
+		{
+			name: "expedited jump the normal lane",
+			ready: []*MRInfo{
+				mr("old-normal", LaneNormal, 30*time.Minute),
+				mr("hotfix", LaneExpedite, time.Minute),
+				mr("new-normal", LaneNormal, 10*time.Minute),
+			},
+			maxWait:   time.Hour,
+			wantOrder: []string{"hotfix", "old-normal", "new-normal"},
+		},
+		{
+			name: "starving normal MR goes first",
+			ready: []*MRInfo{
+				mr("hotfix", LaneExpedite, time.Minute),
+				mr("starved", LaneNormal, 3*time.Hour),
+				mr("normal", LaneNormal, 10*time.Minute),
+			},
+			maxWait:   2 * time.Hour,
+			wantOrder: []string{"starved", "hotfix", "normal"},
+		},
+		{
+			name: "zero max wait disables starvation protection",
+			ready: []*MRInfo{
+				mr("starved", LaneNormal, 30*time.Hour),
+				mr("hotfix", LaneExpedite, time.Minute),
+			},
+			wantOrder: []string{"hotfix", "starved"},
+		},
+		{
+			name: "full normal lane holds normal MRs only",
+			ready: []*MRInfo{
+				mr("normal", LaneNormal, 10*time.Minute),
+				mr("hotfix", LaneExpedite, time.Minute),
+			},
+			inFlight:     map[Lane]int{LaneNormal: 1},
+			maxWait:      time.Hour,
+			wantOrder:    []string{"hotfix"},
+			wantDeferred: []string{"normal"},
+		},
+		{
+			name: "full expedite lane holds expedited MRs only",
+			ready: []*MRInfo{
+				mr("normal", LaneNormal, 10*time.Minute),
+				mr("hotfix", LaneExpedite, time.Minute),
+			},
+			inFlight:     map[Lane]int{LaneExpedite: 1},
+			maxWait:      time.Hour,
+			wantOrder:    []string{"normal"},
+			wantDeferred: []string{"hotfix"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultMergeQueueConfig()
+			cfg.NormalLaneMaxWait = tt.maxWait
+			ordered, deferred := orderByLane(tt.ready, tt.inFlight, cfg, now)
+			if got := laneIDs(ordered); !slices.Equal(got, tt.wantOrder) {
+				t.Errorf("order = %v, want %v", got, tt.wantOrder)
+			}
+			if got := laneIDs(deferred); !slices.Equal(got, tt.wantDeferred) {
+				t.Errorf("deferred = %v, want %v", got, tt.wantDeferred)
+			}
+		})
+	}
+}
+
+func TestOrderByLane_ZeroLimitIsUnlimited(t *testing.T) {
+	now := time.Now()
+	cfg := DefaultMergeQueueConfig()
+	cfg.ExpediteMaxConcurrent = 0
+	ready := []*MRInfo{{ID: "hotfix", Lane: LaneExpedite, CreatedAt: now}}
+
+	ordered, deferred := orderByLane(ready, map[Lane]int{LaneExpedite: 5}, cfg, now)
+	if len(ordered) != 1 || len(deferred) != 0 {
+		t.Errorf("got ordered=%v deferred=%v, want hotfix ready", laneIDs(ordered), laneIDs(deferred))
+	}
+}
+
+func TestEngineer_LoadConfig_Lanes(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"expedite_label":          "hotfix",
+			"expedite_max_concurrent": 2,
+			"normal_lane_max_wait":    "45m",
+		},
+	}
+	data, _ := json.Marshal(config)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.config.ExpediteLabel != "hotfix" {
+		t.Errorf("ExpediteLabel = %q, want hotfix", e.config.ExpediteLabel)
+	}
+	if e.config.ExpediteMaxConcurrent != 2 {
+		t.Errorf("ExpediteMaxConcurrent = %d, want 2", e.config.ExpediteMaxConcurrent)
+	}
+	if e.config.NormalLaneMaxWait != 45*time.Minute {
+		t.Errorf("NormalLaneMaxWait = %v, want 45m", e.config.NormalLaneMaxWait)
+	}
+}
+
+func TestEngineer_LoadConfig_InvalidNormalLaneMaxWait(t *testing.T) {
+	for _, wait := range []string{"soon", "-1h"} {
+		tmpDir := t.TempDir()
+		data := []byte(`{"merge_queue": {"normal_lane_max_wait": "` + wait + `"}}`)
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+		if err := e.LoadConfig(); err == nil {
+			t.Errorf("LoadConfig with normal_lane_max_wait %q: expected error", wait)
+		}
+	}
+}
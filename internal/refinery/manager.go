@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
@@ -51,6 +52,16 @@ func (m *Manager) SetOutput(w io.Writer) {
 	m.output = w
 }
 
+// sessionMux returns the multiplexer hosting the refinery session: the
+// rig's tmux for a remote rig, otherwise the town's configured backend.
+func (m *Manager) sessionMux() multiplexer.Multiplexer {
+	t := m.rig.Tmux()
+	if m.rig.IsRemote() {
+		return t
+	}
+	return multiplexer.ForTownWith(filepath.Dir(m.rig.Path), t)
+}
+
 // SessionName returns the tmux session name for this refinery.
 func (m *Manager) SessionName() string {
 	return session.RefinerySessionName(session.PrefixFor(m.rig.Name))
@@ -62,9 +73,7 @@ func (m *Manager) SessionName() string {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	sessionName := m.SessionName()
-	status := t.CheckSessionHealth(sessionName, 0)
+	status := multiplexer.Health(m.sessionMux(), m.SessionName(), 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -74,17 +83,16 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
-	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
+	return multiplexer.Health(m.sessionMux(), m.SessionName(), maxInactivity)
 }
 
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	mux := m.sessionMux()
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -92,7 +100,10 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		return t.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
 
 // Start starts the refinery.
@@ -101,7 +112,9 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string) error {
-	t := tmux.NewTmux()
+	t := m.rig.Tmux()
+	mux := m.sessionMux()
+	_, usesTmux := mux.(*tmux.Tmux)
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -110,10 +123,10 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	}
 
 	// Check if session already exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := mux.HasSession(sessionID)
 	if running {
 		// Session exists - check if agent is actually running (healthy vs zombie)
-		if t.IsAgentAlive(sessionID) {
+		if multiplexer.AgentAlive(mux, sessionID) {
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but agent dead. Kill and recreate.
@@ -156,9 +169,13 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		Topic:     "patrol",
 	}, "Run `gt prime --hook` and begin patrol.")
 
+	secrets, err := config.StartupSecrets("refinery", townRoot, m.rig.Path, agentOverride)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
+
 	var command string
 	if agentOverride != "" {
-		var err error
 		command, err = config.BuildAgentStartupCommandWithAgentOverride("refinery", m.rig.Name, townRoot, m.rig.Path, initialPrompt, agentOverride)
 		if err != nil {
 			return fmt.Errorf("building startup command with agent override: %w", err)
@@ -167,10 +184,19 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		command = config.BuildAgentStartupCommand("refinery", m.rig.Name, townRoot, m.rig.Path, initialPrompt)
 	}
 
+	// Remote rigs reach beads through the town's Dolt server.
+	command = m.rig.AgentCommand(townRoot, command)
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, refineryRigDir, command); err != nil {
-		return fmt.Errorf("creating tmux session: %w", err)
+	// Secrets reach the agent's environment, never an argv.
+	if err := multiplexer.NewSessionWithSecrets(mux, sessionID, refineryRigDir, command, nil, secrets); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	if !usesTmux {
+		// The startup command carries the agent's environment; there is
+		// no session table, theme, or pane prompt to wait for.
+		return nil
 	}
 
 	// Set environment variables (non-fatal: session works without these)
@@ -216,17 +242,20 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	mux := m.sessionMux()
 	sessionID := m.SessionName()
 
-	// Check if tmux session exists
-	running, _ := t.HasSession(sessionID)
+	// Check if the session exists
+	running, _ := mux.HasSession(sessionID)
 	if !running {
 		return ErrNotRunning
 	}
 
-	// Kill the tmux session
-	return t.KillSession(sessionID)
+	// Kill the session
+	if t, ok := mux.(*tmux.Tmux); ok {
+		return t.KillSession(sessionID)
+	}
+	return mux.KillSessionWithProcesses(sessionID)
 }
 
 // Queue returns the current merge queue.
@@ -351,6 +380,8 @@ func (m *Manager) issueToMR(issue *beads.Issue) *MergeRequest {
 		TargetBranch: target,
 		Status:       MROpen,
 		CreatedAt:    parseTime(issue.CreatedAt),
+		HookResults:  fields.HookResults,
+		TestRuns:     fields.TestRuns,
 	}
 }
 
@@ -472,6 +503,26 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 	return mr, nil
 }
 
+// SetExpedite moves an open MR into the expedite lane, or back to the
+// normal lane when expedite is false, by adding or removing label on its
+// bead. label is the rig's merge_queue.expedite_label.
+func (m *Manager) SetExpedite(idOrBranch, label string, expedite bool) (*MergeRequest, error) {
+	mr, err := m.FindMR(idOrBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := beads.UpdateOptions{RemoveLabels: []string{label}}
+	if expedite {
+		opts = beads.UpdateOptions{AddLabels: []string{label}}
+	}
+	b := beads.New(m.rig.BeadsPath())
+	if err := b.Update(mr.ID, opts); err != nil {
+		return nil, fmt.Errorf("updating MR bead labels: %w", err)
+	}
+	return mr, nil
+}
+
 // notifyWorkerRejected sends a rejection notification to a polecat.
 func (m *Manager) notifyWorkerRejected(mr *MergeRequest, reason string) {
 	router := mail.NewRouter(m.workDir)
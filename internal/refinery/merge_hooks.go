@@ -0,0 +1,217 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// Merge hook phases.
+const (
+	HookPhasePreMerge  = "pre_merge"
+	HookPhasePostMerge = "post_merge"
+)
+
+// MergeHook is one step of a rig's pre- or post-merge pipeline.
+type MergeHook struct {
+	// Name identifies the hook in output and on the MR bead.
+	Name string `json:"name"`
+
+	// Cmd is the shell command to execute in the refinery worktree.
+	Cmd string `json:"cmd"`
+
+	// Timeout is the maximum time the hook may run. Zero means no timeout.
+	Timeout time.Duration `json:"timeout"`
+
+	// Optional hooks are reported when they fail but never stop the pipeline.
+	Optional bool `json:"optional"`
+}
+
+// mergeHookRaw is the JSON-friendly representation of a merge hook with
+// timeout as a string duration.
+type mergeHookRaw struct {
+	Name     string `json:"name"`
+	Cmd      string `json:"cmd"`
+	Timeout  string `json:"timeout"`
+	Optional bool   `json:"optional"`
+}
+
+// parseMergeHooks converts the raw hooks of one phase, validating that each
+// has a unique name, a command, and a positive timeout if one is set.
+func parseMergeHooks(phase string, raws []*mergeHookRaw) ([]*MergeHook, error) {
+	hooks := make([]*MergeHook, 0, len(raws))
+	seen := make(map[string]bool, len(raws))
+	for i, raw := range raws {
+		if raw == nil || strings.TrimSpace(raw.Name) == "" {
+			return nil, fmt.Errorf("%s hook %d: name is required", phase, i)
+		}
+		if seen[raw.Name] {
+			return nil, fmt.Errorf("%s hook %q: duplicate name", phase, raw.Name)
+		}
+		seen[raw.Name] = true
+		if strings.TrimSpace(raw.Cmd) == "" {
+			return nil, fmt.Errorf("%s hook %q: cmd is required", phase, raw.Name)
+		}
+		hook := &MergeHook{Name: raw.Name, Cmd: raw.Cmd, Optional: raw.Optional}
+		if raw.Timeout != "" {
+			dur, err := time.ParseDuration(raw.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for %s hook %q: %w", phase, raw.Name, err)
+			}
+			if dur <= 0 {
+				return nil, fmt.Errorf("%s hook %q timeout must be positive, got %v", phase, raw.Name, dur)
+			}
+			hook.Timeout = dur
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+// pipelineHooks returns the rig's configured hooks for phase followed by the
+// hooks of enabled plugins at the matching hook point. Plugins are discovered
+// on every merge so installing or disabling one takes effect immediately.
+func (e *Engineer) pipelineHooks(phase string) []*MergeHook {
+	hooks := e.config.PreMerge
+	point := plugin.HookPreMerge
+	if phase == HookPhasePostMerge {
+		hooks = e.config.PostMerge
+		point = plugin.HookPostMerge
+	}
+	if e.rig == nil || e.rig.Path == "" {
+		return hooks
+	}
+
+	bound, err := plugin.DiscoverHooks(filepath.Dir(e.rig.Path), e.rig.Name, point)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: discovering plugin hooks: %v\n", err)
+		return hooks
+	}
+	if len(bound) == 0 {
+		return hooks
+	}
+	all := append([]*MergeHook(nil), hooks...)
+	for _, b := range bound {
+		timeout, _ := b.Hook.TimeoutDuration() // validated when the plugin was loaded
+		all = append(all, &MergeHook{
+			Name:     b.Name(),
+			Cmd:      b.ShellCommand(),
+			Timeout:  timeout,
+			Optional: b.Hook.Optional,
+		})
+	}
+	return all
+}
+
+// HookResult holds the outcome of a single merge hook execution.
+type HookResult struct {
+	Phase    string
+	Name     string
+	Success  bool
+	Optional bool
+	Error    string
+	Elapsed  time.Duration
+}
+
+// mergeHookEnv returns the environment describing the merge to its hooks.
+// commit is empty for pre-merge hooks.
+func mergeHookEnv(rigName, branch, target, sourceIssue, commit string) []string {
+	env := []string{
+		"GT_RIG=" + rigName,
+		"GT_MERGE_BRANCH=" + branch,
+		"GT_MERGE_TARGET=" + target,
+		"GT_SOURCE_ISSUE=" + sourceIssue,
+	}
+	if commit != "" {
+		env = append(env, "GT_MERGE_COMMIT="+commit)
+	}
+	return env
+}
+
+// runMergeHooks runs hooks in order and returns the results of those that
+// ran. The first failing required hook stops the pipeline and is returned as
+// failed; optional failures are reported and the pipeline continues.
+func (e *Engineer) runMergeHooks(ctx context.Context, phase string, hooks []*MergeHook, env []string) ([]HookResult, *HookResult) {
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d %s hook(s)\n", len(hooks), phase)
+
+	results := make([]HookResult, 0, len(hooks))
+	for _, hook := range hooks {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Hook %s/%s: starting (%s)\n", phase, hook.Name, hook.Cmd)
+		gr := e.runGateWithEnv(ctx, hook.Name, &GateConfig{Cmd: hook.Cmd, Timeout: hook.Timeout}, env)
+		result := HookResult{
+			Phase:    phase,
+			Name:     hook.Name,
+			Success:  gr.Success,
+			Optional: hook.Optional,
+			Error:    gr.Error,
+			Elapsed:  gr.Elapsed,
+		}
+		results = append(results, result)
+
+		elapsed := result.Elapsed.Truncate(time.Millisecond)
+		switch {
+		case result.Success:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Hook %s/%s: passed (%v)\n", phase, hook.Name, elapsed)
+		case hook.Optional:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: optional hook %s/%s failed (%v) - %s\n", phase, hook.Name, elapsed, result.Error)
+		default:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Hook %s/%s: FAILED (%v) - %s\n", phase, hook.Name, elapsed, result.Error)
+			return results, &results[len(results)-1]
+		}
+	}
+	return results, nil
+}
+
+// FormatHookResults renders results as a one-line summary for the MR bead,
+// e.g. "pre_merge:lint=pass pre_merge:changelog=fail(optional)".
+func FormatHookResults(results []HookResult) string {
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		status := "pass"
+		if !r.Success {
+			status = "fail"
+			if r.Optional {
+				status += "(optional)"
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s:%s=%s", r.Phase, r.Name, status))
+	}
+	return strings.Join(parts, " ")
+}
+
+// recordRunResults stores the hook and test summaries of a failed merge on
+// the MR bead so they show in queue status. Best-effort: failures are only
+// reported.
+func (e *Engineer) recordRunResults(mrID string, result ProcessResult) {
+	runs := testRunsSummary(result)
+	if mrID == "" || (len(result.HookResults) == 0 && runs == "") || e.beads == nil {
+		return
+	}
+	mrBead, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mrID, err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	if len(result.HookResults) > 0 {
+		mrFields.HookResults = FormatHookResults(result.HookResults)
+	}
+	if runs != "" {
+		mrFields.TestRuns = runs
+	}
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record run results on MR %s: %v\n", mrID, err)
+	}
+}
@@ -0,0 +1,151 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestEngineer_LoadConfig_WithMergeHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"pre_merge": []map[string]interface{}{
+				{"name": "lint", "cmd": "make lint", "timeout": "2m"},
+				{"name": "build", "cmd": "make build"},
+			},
+			"post_merge": []map[string]interface{}{
+				{"name": "deploy", "cmd": "./deploy.sh", "optional": true},
+			},
+		},
+	}
+	data, _ := json.Marshal(config)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if len(e.config.PreMerge) != 2 || e.config.PreMerge[0].Name != "lint" || e.config.PreMerge[1].Name != "build" {
+		t.Fatalf("pre_merge not parsed in order: %+v", e.config.PreMerge)
+	}
+	if e.config.PreMerge[0].Timeout != 2*time.Minute {
+		t.Errorf("lint timeout = %v, want 2m", e.config.PreMerge[0].Timeout)
+	}
+	if len(e.config.PostMerge) != 1 || !e.config.PostMerge[0].Optional {
+		t.Errorf("post_merge not parsed: %+v", e.config.PostMerge)
+	}
+}
+
+func TestParseMergeHooks_Invalid(t *testing.T) {
+	tests := map[string][]*mergeHookRaw{
+		"missing name":     {{Cmd: "true"}},
+		"missing cmd":      {{Name: "a"}},
+		"duplicate":        {{Name: "a", Cmd: "true"}, {Name: "a", Cmd: "true"}},
+		"bad timeout":      {{Name: "a", Cmd: "true", Timeout: "soon"}},
+		"negative timeout": {{Name: "a", Cmd: "true", Timeout: "-1s"}},
+	}
+	for name, raws := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseMergeHooks(HookPhasePreMerge, raws); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestRunMergeHooks_StopsOnRequiredFailure(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	hooks := []*MergeHook{
+		{Name: "lint", Cmd: "true"},
+		{Name: "changelog", Cmd: "exit 1", Optional: true},
+		{Name: "build", Cmd: "exit 2"},
+		{Name: "never", Cmd: "true"},
+	}
+	results, failed := e.runMergeHooks(context.Background(), HookPhasePreMerge, hooks, nil)
+
+	if failed == nil || failed.Name != "build" {
+		t.Fatalf("failed = %+v, want build", failed)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3 (stopped before %q)", len(results), "never")
+	}
+	want := "pre_merge:lint=pass pre_merge:changelog=fail(optional) pre_merge:build=fail"
+	if got := FormatHookResults(results); got != want {
+		t.Errorf("FormatHookResults = %q, want %q", got, want)
+	}
+}
+
+func TestRunMergeHooks_Env(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	out := filepath.Join(t.TempDir(), "env")
+	hooks := []*MergeHook{{Name: "env", Cmd: `echo "$GT_RIG $GT_MERGE_BRANCH $GT_MERGE_TARGET $GT_SOURCE_ISSUE $GT_MERGE_COMMIT" > ` + out}}
+	env := mergeHookEnv("test-rig", "polecat/nux", "main", "gt-1", "abc123")
+	if _, failed := e.runMergeHooks(context.Background(), HookPhasePostMerge, hooks, env); failed != nil {
+		t.Fatalf("hook failed: %s", failed.Error)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "test-rig polecat/nux main gt-1 abc123" {
+		t.Errorf("hook env = %q", got)
+	}
+}
+
+func TestRunMergeHooks_Timeout(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+
+	hooks := []*MergeHook{{Name: "slow", Cmd: "sleep 10", Timeout: 100 * time.Millisecond}}
+	_, failed := e.runMergeHooks(context.Background(), HookPhasePreMerge, hooks, nil)
+	if failed == nil || !strings.Contains(failed.Error, "timed out") {
+		t.Errorf("expected timeout failure, got %+v", failed)
+	}
+}
+
+func TestPipelineHooks_IncludesPluginHooks(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	pluginDir := filepath.Join(rigPath, "plugins", "changelog")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "+++\nname = \"changelog\"\n[[hooks]]\non = \"post-merge\"\nrun = \"./update.sh\"\noptional = true\n+++\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.md"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "gastown", Path: rigPath})
+	e.output = io.Discard
+	e.config.PostMerge = []*MergeHook{{Name: "deploy", Cmd: "true"}}
+
+	hooks := e.pipelineHooks(HookPhasePostMerge)
+	if len(hooks) != 2 || hooks[0].Name != "deploy" || hooks[1].Name != "plugin:changelog" {
+		t.Fatalf("unexpected hooks: %+v", hooks)
+	}
+	if !hooks[1].Optional || !strings.Contains(hooks[1].Cmd, "GT_PLUGIN_DIR=") {
+		t.Errorf("plugin hook not converted: %+v", hooks[1])
+	}
+	if len(e.pipelineHooks(HookPhasePreMerge)) != 0 {
+		t.Error("post-merge plugin hook leaked into pre-merge")
+	}
+}
@@ -0,0 +1,86 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Wisp config keys holding a rig's merge queue pause. The queue is paused
+// while pauseAtKey is set; the state lives in the town's wisp layer, so it
+// survives refinery restarts.
+const (
+	pauseAtKey     = "merge_queue_paused_at"
+	pauseByKey     = "merge_queue_paused_by"
+	pauseReasonKey = "merge_queue_paused_reason"
+)
+
+// ErrQueuePaused is returned when claiming an MR while the rig's merge
+// queue is paused.
+var ErrQueuePaused = errors.New("merge queue is paused")
+
+// QueuePause describes a paused merge queue.
+type QueuePause struct {
+	Since  time.Time `json:"since"`
+	By     string    `json:"by,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// PauseQueue pauses merge processing for a rig. The MR in flight finishes;
+// no new MR is ready until ResumeQueue. Pausing an already paused queue
+// updates its reason and keeps the original start time.
+func PauseQueue(townRoot, rigName, by, reason string) (*QueuePause, error) {
+	cfg := wisp.NewConfig(townRoot, rigName)
+	pause := &QueuePause{Since: time.Now().UTC().Truncate(time.Second), By: by, Reason: reason}
+	if existing := QueuePaused(townRoot, rigName); existing != nil {
+		pause.Since = existing.Since
+	}
+
+	if err := cfg.Set(pauseAtKey, pause.Since.Format(time.RFC3339)); err != nil {
+		return nil, fmt.Errorf("saving merge queue pause: %w", err)
+	}
+	if err := cfg.Set(pauseByKey, by); err != nil {
+		return nil, fmt.Errorf("saving merge queue pause: %w", err)
+	}
+	if err := cfg.Set(pauseReasonKey, reason); err != nil {
+		return nil, fmt.Errorf("saving merge queue pause: %w", err)
+	}
+	return pause, nil
+}
+
+// ResumeQueue resumes merge processing for a rig. It reports whether the
+// queue was paused.
+func ResumeQueue(townRoot, rigName string) (bool, error) {
+	wasPaused := QueuePaused(townRoot, rigName) != nil
+	cfg := wisp.NewConfig(townRoot, rigName)
+	for _, key := range []string{pauseAtKey, pauseByKey, pauseReasonKey} {
+		if err := cfg.Unset(key); err != nil {
+			return wasPaused, fmt.Errorf("clearing merge queue pause: %w", err)
+		}
+	}
+	return wasPaused, nil
+}
+
+// QueuePaused returns the rig's merge queue pause, or nil if the queue is
+// running.
+func QueuePaused(townRoot, rigName string) *QueuePause {
+	cfg := wisp.NewConfig(townRoot, rigName)
+	at := cfg.GetString(pauseAtKey)
+	if at == "" {
+		return nil
+	}
+	since, _ := time.Parse(time.RFC3339, at)
+	return &QueuePause{
+		Since:  since,
+		By:     cfg.GetString(pauseByKey),
+		Reason: cfg.GetString(pauseReasonKey),
+	}
+}
+
+// Paused returns the merge queue pause for the engineer's rig, or nil.
+func (e *Engineer) Paused() *QueuePause {
+	return QueuePaused(filepath.Dir(e.rig.Path), e.rig.Name)
+}
@@ -0,0 +1,84 @@
+package refinery
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestPauseQueue_RoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if p := QueuePaused(townRoot, "gastown"); p != nil {
+		t.Fatalf("new rig reports paused: %+v", p)
+	}
+
+	first, err := PauseQueue(townRoot, "gastown", "overseer", "release freeze")
+	if err != nil {
+		t.Fatalf("PauseQueue: %v", err)
+	}
+	got := QueuePaused(townRoot, "gastown")
+	if got == nil {
+		t.Fatal("QueuePaused = nil after pause")
+	}
+	if got.By != "overseer" || got.Reason != "release freeze" {
+		t.Errorf("QueuePaused = %+v, want by overseer, reason release freeze", got)
+	}
+	if !got.Since.Equal(first.Since) {
+		t.Errorf("Since = %v, want %v", got.Since, first.Since)
+	}
+
+	// Pausing again updates the reason but keeps the start time.
+	second, err := PauseQueue(townRoot, "gastown", "mayor", "extended freeze")
+	if err != nil {
+		t.Fatalf("PauseQueue again: %v", err)
+	}
+	if !second.Since.Equal(got.Since) || second.Reason != "extended freeze" {
+		t.Errorf("re-pause = %+v, want since %v with new reason", second, got.Since)
+	}
+
+	// Other rigs are unaffected.
+	if p := QueuePaused(townRoot, "beads"); p != nil {
+		t.Errorf("other rig reports paused: %+v", p)
+	}
+
+	wasPaused, err := ResumeQueue(townRoot, "gastown")
+	if err != nil || !wasPaused {
+		t.Fatalf("ResumeQueue = %v, %v; want true, nil", wasPaused, err)
+	}
+	if p := QueuePaused(townRoot, "gastown"); p != nil {
+		t.Errorf("still paused after resume: %+v", p)
+	}
+	if wasPaused, _ := ResumeQueue(townRoot, "gastown"); wasPaused {
+		t.Error("second resume reports the queue was paused")
+	}
+}
+
+func TestEngineer_PausedQueueHoldsMRs(t *testing.T) {
+	townRoot := t.TempDir()
+	r := &rig.Rig{Name: "gastown", Path: filepath.Join(townRoot, "gastown")}
+	if _, err := PauseQueue(townRoot, "gastown", "overseer", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(r)
+	var out bytes.Buffer
+	e.SetOutput(&out)
+
+	// No beads are queried while paused, so this works without a database.
+	ready, err := e.ListReadyMRs()
+	if err != nil || len(ready) != 0 {
+		t.Errorf("ListReadyMRs = %v, %v; want nothing ready", ready, err)
+	}
+	if !strings.Contains(out.String(), "paused") {
+		t.Errorf("output %q does not mention the pause", out.String())
+	}
+
+	if err := e.ClaimMR("gt-mr-1", "gastown/refinery"); !errors.Is(err, ErrQueuePaused) {
+		t.Errorf("ClaimMR error = %v, want ErrQueuePaused", err)
+	}
+}
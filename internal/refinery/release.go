@@ -0,0 +1,283 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// ReleaseLabel labels the bead recording each release.
+const ReleaseLabel = "gt:release"
+
+// DefaultReleaseTagPrefix prefixes release tags (v1.2.3).
+const DefaultReleaseTagPrefix = "v"
+
+// ErrNothingToRelease is returned when the default branch has no commits
+// since the last release tag.
+var ErrNothingToRelease = errors.New("nothing to release")
+
+// ReleaseConfig controls release tagging for a rig's merge queue.
+type ReleaseConfig struct {
+	// Every cuts a release automatically once this many MRs have merged to
+	// the default branch since the last release. Zero means releases are
+	// only cut on demand with 'gt release cut'.
+	Every int `json:"every"`
+
+	// TagPrefix prefixes release tags; the rest is a semantic version.
+	TagPrefix string `json:"tag_prefix"`
+
+	// Branch also creates and pushes a release/<tag> branch.
+	Branch bool `json:"branch"`
+}
+
+// ReleaseOptions tunes a single release cut.
+type ReleaseOptions struct {
+	Version string // explicit tag (without prefix); empty bumps the last one
+	Bump    string // "major", "minor", or "patch" (default)
+	Branch  bool   // create a release branch even if the config doesn't
+	DryRun  bool   // compute the release but change nothing
+	Actor   string // who cut the release, recorded on the bead
+}
+
+// ChangelogEntry is one merged change in a release.
+type ChangelogEntry struct {
+	Commit string `json:"commit"`
+	Title  string `json:"title"`
+	Issue  string `json:"issue,omitempty"` // source bead, when the MR bead is known
+	MR     string `json:"mr,omitempty"`
+}
+
+// Release describes a cut (or, for a dry run, proposed) release.
+type Release struct {
+	Tag       string           `json:"tag"`
+	Previous  string           `json:"previous,omitempty"` // last release tag
+	Commit    string           `json:"commit"`
+	Target    string           `json:"target"`
+	Branch    string           `json:"branch,omitempty"`
+	BeadID    string           `json:"bead_id,omitempty"`
+	Changelog []ChangelogEntry `json:"changelog"`
+}
+
+// releaseTagPrefix returns the configured tag prefix or the default.
+func (c ReleaseConfig) releaseTagPrefix() string {
+	if c.TagPrefix == "" {
+		return DefaultReleaseTagPrefix
+	}
+	return c.TagPrefix
+}
+
+// nextReleaseVersion bumps the semantic version in previous (a tag with
+// prefix). With no previous release the first version is 0.1.0.
+func nextReleaseVersion(prefix, previous, bump string) (string, error) {
+	if previous == "" {
+		return "0.1.0", nil
+	}
+	version := strings.TrimPrefix(previous, prefix)
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("last release tag %q is not %sMAJOR.MINOR.PATCH; pass an explicit version", previous, prefix)
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("last release tag %q is not %sMAJOR.MINOR.PATCH; pass an explicit version", previous, prefix)
+		}
+		nums[i] = n
+	}
+
+	switch bump {
+	case "major":
+		nums = [3]int{nums[0] + 1, 0, 0}
+	case "minor":
+		nums = [3]int{nums[0], nums[1] + 1, 0}
+	case "", "patch":
+		nums[2]++
+	default:
+		return "", fmt.Errorf("invalid bump %q: must be major, minor, or patch", bump)
+	}
+	return fmt.Sprintf("%d.%d.%d", nums[0], nums[1], nums[2]), nil
+}
+
+// FormatChangelog renders a release's changelog as the tag message and
+// release bead body.
+func FormatChangelog(rel *Release) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Release %s\n\n", rel.Tag)
+	if rel.Previous != "" {
+		fmt.Fprintf(&sb, "Changes since %s:\n\n", rel.Previous)
+	} else {
+		sb.WriteString("Changes:\n\n")
+	}
+	for _, entry := range rel.Changelog {
+		if entry.Issue != "" {
+			fmt.Fprintf(&sb, "- %s (%s)\n", entry.Title, entry.Issue)
+		} else {
+			fmt.Fprintf(&sb, "- %s\n", entry.Title)
+		}
+	}
+	return sb.String()
+}
+
+// releaseRange is the revision range holding unreleased commits on ref.
+func releaseRange(previous, ref string) string {
+	if previous == "" {
+		return ref
+	}
+	return previous + ".." + ref
+}
+
+// unreleased returns the last release tag on the default branch and the
+// first-parent commits merged to it since.
+func (e *Engineer) unreleased() (target, ref, previous string, commits []git.LogCommit, err error) {
+	target = e.rig.DefaultBranch()
+	if err := e.git.Fetch("origin"); err != nil {
+		return "", "", "", nil, fmt.Errorf("fetching origin: %w", err)
+	}
+	ref = "origin/" + target
+	previous, err = e.git.LatestTag(e.config.Release.releaseTagPrefix()+"*", ref)
+	if err != nil {
+		return "", "", "", nil, fmt.Errorf("finding last release tag: %w", err)
+	}
+	commits, err = e.git.Log(releaseRange(previous, ref))
+	if err != nil {
+		return "", "", "", nil, fmt.Errorf("listing commits in %s: %w", releaseRange(previous, ref), err)
+	}
+	return target, ref, previous, commits, nil
+}
+
+// releaseChangelog builds changelog entries for commits, newest first. A
+// commit made by a merged MR takes the title of the MR's source bead;
+// other commits use their subject.
+func (e *Engineer) releaseChangelog(commits []git.LogCommit) []ChangelogEntry {
+	byCommit, err := changelog.MergedMRs(e.beads)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: listing merged MRs for changelog: %v\n", err)
+	}
+
+	entries := make([]ChangelogEntry, 0, len(commits))
+	for _, c := range commits {
+		entry := ChangelogEntry{Commit: c.SHA, Title: c.Subject}
+		if mr, ok := byCommit[c.SHA]; ok {
+			entry.MR, entry.Issue = mr.ID, mr.SourceIssue
+			entry.Title = mr.Title
+			if mr.SourceIssue != "" {
+				if src, err := e.beads.Show(mr.SourceIssue); err == nil && src.Title != "" {
+					entry.Title = src.Title
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// CutRelease tags the default branch with the next release version,
+// optionally creates a release branch, pushes both, and records the
+// release as a closed gt:release bead whose body is the changelog.
+func (e *Engineer) CutRelease(opts ReleaseOptions) (*Release, error) {
+	target, ref, previous, commits, err := e.unreleased()
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		if previous == "" {
+			return nil, fmt.Errorf("%w: %s has no commits", ErrNothingToRelease, ref)
+		}
+		return nil, fmt.Errorf("%w: no commits on %s since %s", ErrNothingToRelease, target, previous)
+	}
+
+	prefix := e.config.Release.releaseTagPrefix()
+	version := strings.TrimPrefix(opts.Version, prefix)
+	if version == "" {
+		if version, err = nextReleaseVersion(prefix, previous, opts.Bump); err != nil {
+			return nil, err
+		}
+	}
+	rel := &Release{
+		Tag:       prefix + version,
+		Previous:  previous,
+		Commit:    commits[0].SHA,
+		Target:    target,
+		Changelog: e.releaseChangelog(commits),
+	}
+	if opts.Branch || e.config.Release.Branch {
+		rel.Branch = "release/" + rel.Tag
+	}
+	if opts.DryRun {
+		return rel, nil
+	}
+
+	message := FormatChangelog(rel)
+	if err := e.git.CreateAnnotatedTag(rel.Tag, rel.Commit, message); err != nil {
+		return nil, fmt.Errorf("creating tag %s: %w", rel.Tag, err)
+	}
+	if err := e.git.Push("origin", "refs/tags/"+rel.Tag, false); err != nil {
+		return nil, fmt.Errorf("pushing tag %s: %w", rel.Tag, err)
+	}
+	if rel.Branch != "" {
+		if err := e.git.CreateBranchFrom(rel.Branch, rel.Commit); err != nil {
+			return nil, fmt.Errorf("creating release branch %s: %w", rel.Branch, err)
+		}
+		if err := e.git.Push("origin", rel.Branch, false); err != nil {
+			return nil, fmt.Errorf("pushing release branch %s: %w", rel.Branch, err)
+		}
+	}
+
+	// The tag is the release; the bead is its record in beads history.
+	var body strings.Builder
+	fmt.Fprintf(&body, "tag: %s\ncommit: %s\ntarget: %s\nrig: %s\n", rel.Tag, rel.Commit, rel.Target, e.rig.Name)
+	if rel.Previous != "" {
+		fmt.Fprintf(&body, "previous: %s\n", rel.Previous)
+	}
+	if rel.Branch != "" {
+		fmt.Fprintf(&body, "branch: %s\n", rel.Branch)
+	}
+	fmt.Fprintf(&body, "changes: %d\n\n%s", len(rel.Changelog), message)
+	bead, err := e.beads.Create(beads.CreateOptions{
+		Title:       "Release " + rel.Tag,
+		Type:        "release",
+		Priority:    3,
+		Description: body.String(),
+		Actor:       opts.Actor,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: release %s tagged but not recorded: %v\n", rel.Tag, err)
+		return rel, nil
+	}
+	rel.BeadID = bead.ID
+	if err := e.beads.CloseWithReason("released", bead.ID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close release bead %s: %v\n", bead.ID, err)
+	}
+	return rel, nil
+}
+
+// maybeCutRelease cuts a release after a merge to the default branch once
+// Release.Every MRs have merged since the last release.
+func (e *Engineer) maybeCutRelease(mr *MRInfo) {
+	every := e.config.Release.Every
+	if every <= 0 || (mr.Target != "" && mr.Target != e.rig.DefaultBranch()) {
+		return
+	}
+	_, _, previous, commits, err := e.unreleased()
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: release check: %v\n", err)
+		return
+	}
+	if len(commits) < every {
+		return
+	}
+
+	rel, err := e.CutRelease(ReleaseOptions{Actor: e.rig.Name + "/refinery"})
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: automatic release after %d merges since %s failed: %v\n",
+			len(commits), previous, err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Released %s (%d changes)\n", rel.Tag, len(rel.Changelog))
+}
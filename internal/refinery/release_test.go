@@ -0,0 +1,174 @@
+package refinery
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestNextReleaseVersion(t *testing.T) {
+	tests := []struct {
+		previous, bump, want string
+		wantErr              bool
+	}{
+		{previous: "", want: "0.1.0"},
+		{previous: "v1.2.3", want: "1.2.4"},
+		{previous: "v1.2.3", bump: "patch", want: "1.2.4"},
+		{previous: "v1.2.3", bump: "minor", want: "1.3.0"},
+		{previous: "v1.2.3", bump: "major", want: "2.0.0"},
+		{previous: "v1.2.3", bump: "huge", wantErr: true},
+		{previous: "v1.2", wantErr: true},
+		{previous: "vnext", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := nextReleaseVersion("v", tt.previous, tt.bump)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("nextReleaseVersion(%q, %q) = %q, want error", tt.previous, tt.bump, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("nextReleaseVersion(%q, %q) = %q, %v; want %q", tt.previous, tt.bump, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatChangelog(t *testing.T) {
+	got := FormatChangelog(&Release{
+		Tag:      "v1.3.0",
+		Previous: "v1.2.0",
+		Changelog: []ChangelogEntry{
+			{Title: "Add priority lanes", Issue: "gt-abc", MR: "gt-mr1"},
+			{Title: "Fix typo in README"},
+		},
+	})
+	want := "Release v1.3.0\n\nChanges since v1.2.0:\n\n- Add priority lanes (gt-abc)\n- Fix typo in README\n"
+	if got != want {
+		t.Errorf("FormatChangelog =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// releaseTestRig builds an origin with a v1.0.0 tag followed by two
+// commits, and a rig whose refinery clone tracks it. bd always fails, so
+// changelogs fall back to commit subjects and no release bead is created.
+func releaseTestRig(t *testing.T) (e *Engineer, origin string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	origin = filepath.Join(root, "origin.git")
+	work := filepath.Join(root, "work")
+	rigDir := filepath.Join(root, "gastown")
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(work, "f.txt"), []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(work, "add", ".")
+		run(work, "commit", "-m", msg)
+	}
+
+	run(root, "init", "--bare", "-b", "main", origin)
+	run(root, "init", "-b", "main", work)
+	commit("initial")
+	run(work, "tag", "-a", "v1.0.0", "-m", "v1.0.0")
+	commit("Add feature")
+	commit("Fix bug")
+	run(work, "remote", "add", "origin", origin)
+	run(work, "push", "origin", "main", "--tags")
+
+	if err := os.MkdirAll(filepath.Join(rigDir, "refinery"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(root, "clone", origin, filepath.Join(rigDir, "refinery", "rig"))
+	run(filepath.Join(rigDir, "refinery", "rig"), "config", "user.email", "t@t")
+	run(filepath.Join(rigDir, "refinery", "rig"), "config", "user.name", "t")
+
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	e = NewEngineer(&rig.Rig{Name: "gastown", Path: rigDir})
+	e.SetOutput(io.Discard)
+	return e, origin
+}
+
+func TestCutRelease(t *testing.T) {
+	e, origin := releaseTestRig(t)
+
+	dry, err := e.CutRelease(ReleaseOptions{DryRun: true, Branch: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Tag != "v1.0.1" || dry.Previous != "v1.0.0" || dry.Branch != "release/v1.0.1" {
+		t.Errorf("dry run = %+v, want v1.0.1 after v1.0.0 with release branch", dry)
+	}
+	if len(dry.Changelog) != 2 || dry.Changelog[0].Title != "Fix bug" || dry.Changelog[1].Title != "Add feature" {
+		t.Errorf("changelog = %+v, want [Fix bug, Add feature]", dry.Changelog)
+	}
+	if out, _ := exec.Command("git", "--git-dir", origin, "tag").Output(); strings.Contains(string(out), "v1.0.1") {
+		t.Fatal("dry run pushed a tag")
+	}
+
+	rel, err := e.CutRelease(ReleaseOptions{Bump: "minor"})
+	if err != nil {
+		t.Fatalf("CutRelease: %v", err)
+	}
+	if rel.Tag != "v1.1.0" {
+		t.Errorf("tag = %s, want v1.1.0", rel.Tag)
+	}
+	out, err := exec.Command("git", "--git-dir", origin, "cat-file", "-p", "v1.1.0").Output()
+	if err != nil {
+		t.Fatalf("tag not pushed to origin: %v", err)
+	}
+	if !strings.Contains(string(out), "- Fix bug") || !strings.Contains(string(out), "Changes since v1.0.0") {
+		t.Errorf("tag message missing changelog:\n%s", out)
+	}
+
+	if _, err := e.CutRelease(ReleaseOptions{}); !errors.Is(err, ErrNothingToRelease) {
+		t.Errorf("second cut error = %v, want ErrNothingToRelease", err)
+	}
+}
+
+func TestMaybeCutRelease_WaitsForMilestone(t *testing.T) {
+	e, origin := releaseTestRig(t)
+	mr := &MRInfo{ID: "gt-mr1", Target: "main"}
+
+	e.config.Release.Every = 3
+	e.maybeCutRelease(mr)
+	if out, _ := exec.Command("git", "--git-dir", origin, "tag").Output(); strings.TrimSpace(string(out)) != "v1.0.0" {
+		t.Fatalf("released before the milestone: tags %q", out)
+	}
+
+	e.config.Release.Every = 2
+	e.maybeCutRelease(&MRInfo{ID: "gt-mr2", Target: "integration/epic"})
+	if out, _ := exec.Command("git", "--git-dir", origin, "tag").Output(); strings.Contains(string(out), "v1.0.1") {
+		t.Fatal("released after a merge to a non-default branch")
+	}
+
+	e.maybeCutRelease(mr)
+	if out, _ := exec.Command("git", "--git-dir", origin, "tag").Output(); !strings.Contains(string(out), "v1.0.1") {
+		t.Fatalf("no release at the milestone: tags %q", out)
+	}
+}
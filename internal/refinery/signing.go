@@ -0,0 +1,45 @@
+package refinery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// signingPreflight prepares and checks commit signing before a merge.
+// When the rig signs refinery commits, the signing config is reapplied to
+// the refinery's clone so the squash commit is signed with the current
+// key (settings may have changed, or the forwarded SSH agent moved). When
+// the rig verifies signatures, every commit on branch must carry a good
+// one.
+func (e *Engineer) signingPreflight(target, branch string) error {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err != nil || settings.Git == nil || settings.Git.Signing == nil {
+		return nil
+	}
+	signing := settings.Git.Signing
+
+	if signing.SignsRole("refinery") {
+		info, statErr := os.Stat(filepath.Join(e.workDir, ".git"))
+		worktree := statErr == nil && !info.IsDir()
+		if err := rig.ApplyIdentity(e.rig.Path, "refinery", "", e.workDir, worktree); err != nil {
+			return fmt.Errorf("configuring refinery commit signing: %w", err)
+		}
+	}
+
+	if !signing.Verify {
+		return nil
+	}
+	unverified, err := e.git.UnverifiedCommits(target, branch)
+	if err != nil {
+		return fmt.Errorf("verifying commit signatures: %w", err)
+	}
+	if len(unverified) > 0 {
+		return fmt.Errorf("commits without a good signature: %s", strings.Join(unverified, ", "))
+	}
+	return nil
+}
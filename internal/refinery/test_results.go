@@ -0,0 +1,330 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxPassedRuns bounds the passed-run cache; the oldest entries are dropped.
+const maxPassedRuns = 500
+
+// PassedRun records a test run that passed for a branch commit merged onto a
+// target commit.
+type PassedRun struct {
+	Branch       string    `json:"branch"`
+	Commit       string    `json:"commit"`
+	TargetCommit string    `json:"target_commit"`
+	At           time.Time `json:"at"`
+}
+
+// FlakeRecord tracks a test that failed and then passed on retry of the same
+// tree. Branches lists the distinct branches it flaked on.
+type FlakeRecord struct {
+	Test          string     `json:"test"`
+	Branches      []string   `json:"branches"`
+	Count         int        `json:"count"`
+	LastSeen      time.Time  `json:"last_seen"`
+	QuarantinedAt *time.Time `json:"quarantined_at,omitempty"`
+	Bead          string     `json:"bead,omitempty"`
+}
+
+// Quarantined reports whether failures of this test are ignored.
+func (f *FlakeRecord) Quarantined() bool {
+	return f != nil && f.QuarantinedAt != nil
+}
+
+type testResultsFile struct {
+	Passed map[string]*PassedRun   `json:"passed"`
+	Flakes map[string]*FlakeRecord `json:"flakes"`
+}
+
+// TestResultStore persists the refinery's test history for a rig in
+// <rig>/.runtime/refinery/test-results.json: trees that already passed, so a
+// re-queued branch whose commit and target are unchanged skips its tests, and
+// tests that flaked, so they can be quarantined. A nil store records nothing.
+type TestResultStore struct {
+	path string
+
+	mu     sync.Mutex
+	data   testResultsFile
+	loaded bool
+}
+
+// NewTestResultStore returns the store for the rig at rigPath.
+func NewTestResultStore(rigPath string) *TestResultStore {
+	return &TestResultStore{path: filepath.Join(rigPath, ".runtime", "refinery", "test-results.json")}
+}
+
+func passedKey(branch, commit, targetCommit string) string {
+	return branch + "@" + commit + ".." + targetCommit
+}
+
+// HasPassed reports whether tests already passed for branch at commit on top
+// of targetCommit.
+func (s *TestResultStore) HasPassed(branch, commit, targetCommit string) bool {
+	if s == nil || commit == "" || targetCommit == "" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	_, ok := s.data.Passed[passedKey(branch, commit, targetCommit)]
+	return ok
+}
+
+// RecordPass remembers a passing run.
+func (s *TestResultStore) RecordPass(branch, commit, targetCommit string) error {
+	if s == nil || commit == "" || targetCommit == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	s.data.Passed[passedKey(branch, commit, targetCommit)] = &PassedRun{
+		Branch:       branch,
+		Commit:       commit,
+		TargetCommit: targetCommit,
+		At:           time.Now().UTC(),
+	}
+	s.prunePassedLocked()
+	return s.saveLocked()
+}
+
+// RecordFlake notes that test flaked on branch and returns its updated record.
+func (s *TestResultStore) RecordFlake(test, branch string) (*FlakeRecord, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	rec := s.data.Flakes[test]
+	if rec == nil {
+		rec = &FlakeRecord{Test: test}
+		s.data.Flakes[test] = rec
+	}
+	rec.Count++
+	rec.LastSeen = time.Now().UTC()
+	if branch != "" && !containsString(rec.Branches, branch) {
+		rec.Branches = append(rec.Branches, branch)
+	}
+	copied := *rec
+	return &copied, s.saveLocked()
+}
+
+// Quarantine marks test as quarantined, linking the bead that tracks it.
+func (s *TestResultStore) Quarantine(test, beadID string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	rec := s.data.Flakes[test]
+	if rec == nil {
+		rec = &FlakeRecord{Test: test}
+		s.data.Flakes[test] = rec
+	}
+	now := time.Now().UTC()
+	rec.QuarantinedAt = &now
+	rec.Bead = beadID
+	return s.saveLocked()
+}
+
+// Flakes returns all flake records sorted by test name.
+func (s *TestResultStore) Flakes() []*FlakeRecord {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	out := make([]*FlakeRecord, 0, len(s.data.Flakes))
+	for _, rec := range s.data.Flakes {
+		copied := *rec
+		out = append(out, &copied)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Test < out[j].Test })
+	return out
+}
+
+// IsQuarantined reports whether test is quarantined.
+func (s *TestResultStore) IsQuarantined(test string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadLocked()
+	return s.data.Flakes[test].Quarantined()
+}
+
+func (s *TestResultStore) loadLocked() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	if data, err := os.ReadFile(s.path); err == nil {
+		_ = json.Unmarshal(data, &s.data)
+	}
+	if s.data.Passed == nil {
+		s.data.Passed = make(map[string]*PassedRun)
+	}
+	if s.data.Flakes == nil {
+		s.data.Flakes = make(map[string]*FlakeRecord)
+	}
+}
+
+func (s *TestResultStore) prunePassedLocked() {
+	if len(s.data.Passed) <= maxPassedRuns {
+		return
+	}
+	keys := make([]string, 0, len(s.data.Passed))
+	for k := range s.data.Passed {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return s.data.Passed[keys[i]].At.Before(s.data.Passed[keys[j]].At) })
+	for _, k := range keys[:len(keys)-maxPassedRuns] {
+		delete(s.data.Passed, k)
+	}
+}
+
+func (s *TestResultStore) saveLocked() error {
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling test results: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating test results dir: %w", err)
+	}
+	if err := util.AtomicWriteFile(s.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing test results: %w", err)
+	}
+	return nil
+}
+
+// recordTestPass caches a passing run so a re-queue of the same tree skips
+// its tests. Errors are reported but never fail the merge.
+func (e *Engineer) recordTestPass(branch, commit, targetCommit string) {
+	if !e.config.CacheTestResults {
+		return
+	}
+	if err := e.testResults.RecordPass(branch, commit, targetCommit); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not cache test result: %v\n", err)
+	}
+}
+
+// recordFlakes records tests that failed and then passed on retry, and
+// quarantines those that have now flaked on enough distinct branches.
+func (e *Engineer) recordFlakes(tests []string, branch string) {
+	for _, test := range tests {
+		rec, err := e.testResults.RecordFlake(test, branch)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not record flake %s: %v\n", test, err)
+			continue
+		}
+		if rec == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Flaky test %s (flaked on %d branch(es))\n", test, len(rec.Branches))
+		threshold := e.config.FlakeQuarantineThreshold
+		if threshold > 0 && len(rec.Branches) >= threshold && !rec.Quarantined() {
+			e.quarantineTest(rec)
+		}
+	}
+}
+
+// quarantineTest stops test from blocking merges and opens a bead to fix it.
+func (e *Engineer) quarantineTest(rec *FlakeRecord) {
+	var beadID string
+	if e.beads != nil {
+		issue, err := e.beads.Create(beads.CreateOptions{
+			Title:    fmt.Sprintf("Fix flaky test: %s", rec.Test),
+			Type:     "bug",
+			Priority: 2,
+			Description: fmt.Sprintf(`The refinery quarantined %s after it flaked on %d branches (%d flakes total).
+Its failures no longer block merges. Fix the test, then delete its "flakes"
+entry in %s to lift the quarantine.
+
+Branches: %s`, rec.Test, len(rec.Branches), rec.Count, e.testResults.path, strings.Join(rec.Branches, ", ")),
+			Actor: e.rig.Name + "/refinery",
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not open bead for flaky test %s: %v\n", rec.Test, err)
+		} else {
+			beadID = issue.ID
+		}
+	}
+	if err := e.testResults.Quarantine(rec.Test, beadID); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not quarantine %s: %v\n", rec.Test, err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Quarantined flaky test %s (bead %s)\n", rec.Test, beadID)
+}
+
+// allQuarantined reports whether failed is non-empty and every test in it is
+// quarantined.
+func (e *Engineer) allQuarantined(failed []string) bool {
+	if len(failed) == 0 || e.config.FlakeQuarantineThreshold == 0 {
+		return false
+	}
+	for _, test := range failed {
+		if !e.testResults.IsQuarantined(test) {
+			return false
+		}
+	}
+	return true
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// failedTestPatterns recognize failing test names in common runner output:
+// `go test` ("--- FAIL: TestName") and pytest ("FAILED path::test").
+var failedTestPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^\s*--- FAIL: (\S+)`),
+	regexp.MustCompile(`(?m)^FAILED (\S+)`),
+}
+
+// failedTests extracts the distinct failing test names from output, sorted.
+// Returns nil when the output names no tests (e.g. a build failure).
+func failedTests(output string) []string {
+	seen := make(map[string]bool)
+	for _, re := range failedTestPatterns {
+		for _, m := range re.FindAllStringSubmatch(output, -1) {
+			seen[m[1]] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
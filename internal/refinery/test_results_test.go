@@ -0,0 +1,110 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFailedTests(t *testing.T) {
+	output := `=== RUN   TestA
+--- FAIL: TestA (0.01s)
+    --- FAIL: TestA/sub (0.00s)
+--- PASS: TestB (0.00s)
+FAILED tests/test_api.py::test_login - AssertionError
+--- FAIL: TestA (0.01s)
+`
+	want := []string{"TestA", "TestA/sub", "tests/test_api.py::test_login"}
+	if got := failedTests(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("failedTests() = %v, want %v", got, want)
+	}
+	if got := failedTests("build failed: syntax error"); got != nil {
+		t.Errorf("failedTests(build error) = %v, want nil", got)
+	}
+}
+
+func TestTestResultStorePassedRuns(t *testing.T) {
+	rigPath := t.TempDir()
+	s := NewTestResultStore(rigPath)
+	if s.HasPassed("polecat/nux", "abc", "def") {
+		t.Fatal("empty store reported a pass")
+	}
+	if err := s.RecordPass("polecat/nux", "abc", "def"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewTestResultStore(rigPath)
+	if !reloaded.HasPassed("polecat/nux", "abc", "def") {
+		t.Error("pass not persisted")
+	}
+	if reloaded.HasPassed("polecat/nux", "abc", "moved") {
+		t.Error("pass should not carry over to a new target commit")
+	}
+
+	var nilStore *TestResultStore
+	if nilStore.HasPassed("b", "c", "t") || nilStore.RecordPass("b", "c", "t") != nil {
+		t.Error("nil store should be inert")
+	}
+}
+
+func TestTestResultStoreFlakes(t *testing.T) {
+	s := NewTestResultStore(t.TempDir())
+	for _, branch := range []string{"polecat/a", "polecat/a", "polecat/b"} {
+		if _, err := s.RecordFlake("TestRace", branch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	flakes := s.Flakes()
+	if len(flakes) != 1 || flakes[0].Count != 3 || len(flakes[0].Branches) != 2 {
+		t.Fatalf("Flakes() = %+v", flakes)
+	}
+	if s.IsQuarantined("TestRace") {
+		t.Error("not quarantined yet")
+	}
+	if err := s.Quarantine("TestRace", "gt-123"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsQuarantined("TestRace") {
+		t.Error("expected quarantine")
+	}
+}
+
+func TestRunTestsRecordsFlakeAndQuarantines(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran-once")
+	// Fails the first time, passes the second: a flake.
+	cmd := `if [ -f ` + marker + ` ]; then exit 0; fi; touch ` + marker + `; echo "--- FAIL: TestRace (0.01s)"; exit 1`
+
+	e := &Engineer{
+		config: &MergeQueueConfig{
+			TestCommand:              cmd,
+			RetryFlakyTests:          2,
+			FlakeQuarantineThreshold: 1,
+		},
+		workDir:     dir,
+		output:      io.Discard,
+		testResults: NewTestResultStore(dir),
+	}
+
+	if result := e.runTestCommand(context.Background(), "polecat/nux", e.config.TestCommand); !result.Success {
+		t.Fatalf("runTestCommand() = %+v, want success after retry", result)
+	}
+	if !e.testResults.IsQuarantined("TestRace") {
+		t.Fatal("TestRace should be quarantined after flaking on one branch")
+	}
+
+	// Once quarantined, its failures alone no longer block the merge.
+	e.config.TestCommand = `echo "--- FAIL: TestRace (0.01s)"; exit 1`
+	e.config.RetryFlakyTests = 1
+	if result := e.runTestCommand(context.Background(), "polecat/other", e.config.TestCommand); !result.Success {
+		t.Errorf("runTestCommand() = %+v, want success with only quarantined failures", result)
+	}
+
+	// Any other failure still blocks.
+	e.config.TestCommand = `echo "--- FAIL: TestRace"; echo "--- FAIL: TestReal"; exit 1`
+	if result := e.runTestCommand(context.Background(), "polecat/other", e.config.TestCommand); result.Success {
+		t.Error("non-quarantined failure should block")
+	}
+}
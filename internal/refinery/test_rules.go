@@ -0,0 +1,189 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// TestRule scopes testing to the paths a merge touches, so a monorepo can
+// skip its full suite for docs-only changes or run a package's focused
+// tests before the full suite.
+type TestRule struct {
+	// Paths are globs matched against the changed files. "**" matches any
+	// number of directories; a pattern without "/" matches base names.
+	Paths []string `json:"paths"`
+
+	// Cmd runs before the full test command when any changed file matches.
+	Cmd string `json:"cmd,omitempty"`
+
+	// Skip skips testing entirely when every changed file matches a skip rule.
+	Skip bool `json:"skip,omitempty"`
+}
+
+// TestRun is one test command the refinery executed for a merge.
+type TestRun struct {
+	Cmd     string
+	Success bool
+}
+
+// testPlan is the ordered test commands for a merge. A plan with no
+// commands and a SkipReason skips testing.
+type testPlan struct {
+	Cmds       []string
+	SkipReason string
+}
+
+// parseTestRules validates the raw test rules: each needs paths and either
+// a command or skip, but not both.
+func parseTestRules(raws []*TestRule) ([]*TestRule, error) {
+	rules := make([]*TestRule, 0, len(raws))
+	for i, r := range raws {
+		if r == nil || len(r.Paths) == 0 {
+			return nil, fmt.Errorf("test rule %d: paths are required", i)
+		}
+		for _, p := range r.Paths {
+			if _, err := path.Match(strings.ReplaceAll(p, "**", "*"), ""); err != nil {
+				return nil, fmt.Errorf("test rule %d: invalid path %q: %w", i, p, err)
+			}
+		}
+		hasCmd := strings.TrimSpace(r.Cmd) != ""
+		if hasCmd == r.Skip {
+			return nil, fmt.Errorf("test rule %d: set exactly one of cmd or skip", i)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// planTests decides which commands test a merge changing files. Without
+// rules, or when the changed files are unknown (nil), only the full test
+// command runs.
+func planTests(rules []*TestRule, fullCmd string, files []string) testPlan {
+	if len(rules) == 0 || files == nil {
+		return testPlan{Cmds: nonEmpty(fullCmd)}
+	}
+
+	skipped := 0
+	for _, f := range files {
+		for _, r := range rules {
+			if r.Skip && r.matches(f) {
+				skipped++
+				break
+			}
+		}
+	}
+	if len(files) > 0 && skipped == len(files) {
+		return testPlan{SkipReason: fmt.Sprintf("all %d changed file(s) match skip rules", len(files))}
+	}
+
+	var plan testPlan
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if r.Skip || seen[r.Cmd] {
+			continue
+		}
+		for _, f := range files {
+			if r.matches(f) {
+				seen[r.Cmd] = true
+				plan.Cmds = append(plan.Cmds, r.Cmd)
+				break
+			}
+		}
+	}
+	if !seen[fullCmd] {
+		plan.Cmds = append(plan.Cmds, nonEmpty(fullCmd)...)
+	}
+	return plan
+}
+
+func nonEmpty(cmd string) []string {
+	if strings.TrimSpace(cmd) == "" {
+		return nil
+	}
+	return []string{cmd}
+}
+
+// matches reports whether file matches any of the rule's paths.
+func (r *TestRule) matches(file string) bool {
+	for _, p := range r.Paths {
+		if matchPathGlob(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches a slash-separated path against a glob in which "**"
+// spans any number of directories. A pattern without "/" matches base names.
+func matchPathGlob(pattern, file string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pat, parts []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pat[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], parts[0]); !ok {
+			return false
+		}
+		pat, parts = pat[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// runTestPlan runs the plan's commands in order, stopping at the first
+// failure. Each command gets the flaky-test retries and quarantine of
+// runTestCommand. The commands that ran are returned in TestRuns.
+func (e *Engineer) runTestPlan(ctx context.Context, branch string, plan testPlan) ProcessResult {
+	var runs []TestRun
+	for _, cmd := range plan.Cmds {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", cmd)
+		result := e.runTestCommand(ctx, branch, cmd)
+		runs = append(runs, TestRun{Cmd: cmd, Success: result.Success})
+		if !result.Success {
+			result.TestRuns = runs
+			return result
+		}
+	}
+	return ProcessResult{Success: true, TestRuns: runs}
+}
+
+// FormatTestRuns renders the test commands a merge ran as a one-line
+// summary for the MR bead, e.g. "[go test ./internal/doltserver/...]=pass
+// [go test ./...]=fail".
+func FormatTestRuns(runs []TestRun) string {
+	parts := make([]string, 0, len(runs))
+	for _, r := range runs {
+		status := "pass"
+		if !r.Success {
+			status = "fail"
+		}
+		parts = append(parts, fmt.Sprintf("[%s]=%s", r.Cmd, status))
+	}
+	return strings.Join(parts, " ")
+}
+
+// testRunsSummary is the MR bead summary of a merge's testing: the commands
+// that ran, or why test rules skipped them. Empty when nothing was tested.
+func testRunsSummary(result ProcessResult) string {
+	if result.TestSkipped != "" {
+		return "skipped (" + result.TestSkipped + ")"
+	}
+	return FormatTestRuns(result.TestRuns)
+}
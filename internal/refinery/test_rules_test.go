@@ -0,0 +1,94 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"docs/**", "docs/reference.md", true},
+		{"docs/**", "docs/design/plan.md", true},
+		{"docs/**", "internal/docs/x.go", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/design/plan.md", true},
+		{"*.md", "main.go", false},
+		{"internal/doltserver/**", "internal/doltserver/doltserver.go", true},
+		{"internal/doltserver/**", "internal/doltserverx/a.go", false},
+		{"internal/*/testdata/**", "internal/cmd/testdata/a/b.json", true},
+		{"**/testdata/*.json", "a/b/testdata/c.json", true},
+		{"**", "anything/at/all", true},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestPlanTests(t *testing.T) {
+	rules := []*TestRule{
+		{Paths: []string{"docs/**", "*.md"}, Skip: true},
+		{Paths: []string{"internal/doltserver/**"}, Cmd: "go test ./internal/doltserver/..."},
+	}
+	const full = "go test ./..."
+	tests := []struct {
+		name     string
+		files    []string
+		wantCmds []string
+		wantSkip bool
+	}{
+		{"docs only", []string{"docs/reference.md", "README.md"}, nil, true},
+		{"doltserver first", []string{"internal/doltserver/doltserver.go", "docs/x.md"}, []string{"go test ./internal/doltserver/...", full}, false},
+		{"other code", []string{"internal/cmd/root.go"}, []string{full}, false},
+		{"files unknown", nil, []string{full}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planTests(rules, full, tt.files)
+			if !reflect.DeepEqual(plan.Cmds, tt.wantCmds) || (plan.SkipReason != "") != tt.wantSkip {
+				t.Errorf("plan = %+v, want cmds %v skip %v", plan, tt.wantCmds, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestParseTestRules(t *testing.T) {
+	if _, err := parseTestRules([]*TestRule{{Paths: []string{"docs/**"}, Skip: true}, {Paths: []string{"a/**"}, Cmd: "make a"}}); err != nil {
+		t.Fatalf("valid rules: %v", err)
+	}
+	for name, r := range map[string]*TestRule{
+		"no paths":    {Cmd: "make"},
+		"neither":     {Paths: []string{"a"}},
+		"both":        {Paths: []string{"a"}, Cmd: "make", Skip: true},
+		"bad pattern": {Paths: []string{"a/["}, Skip: true},
+		"nil rule":    nil,
+	} {
+		if _, err := parseTestRules([]*TestRule{r}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRunTestPlanStopsAtFailure(t *testing.T) {
+	e := &Engineer{
+		config:  &MergeQueueConfig{RetryFlakyTests: 1},
+		workDir: t.TempDir(),
+		output:  io.Discard,
+	}
+	result := e.runTestPlan(context.Background(), "polecat/nux", testPlan{Cmds: []string{"true", "false", "true"}})
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	if got := FormatTestRuns(result.TestRuns); got != "[true]=pass [false]=fail" {
+		t.Errorf("FormatTestRuns() = %q", got)
+	}
+	if got := testRunsSummary(ProcessResult{TestSkipped: "docs only"}); got != "skipped (docs only)" {
+		t.Errorf("testRunsSummary() = %q", got)
+	}
+}
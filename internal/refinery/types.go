@@ -41,6 +41,12 @@ type MergeRequest struct {
 
 	// Error contains error details if the MR failed.
 	Error string `json:"error,omitempty"`
+
+	// HookResults summarizes the last pre/post-merge hook run, if any.
+	HookResults string `json:"hook_results,omitempty"`
+
+	// TestRuns summarizes the test commands the last merge attempt ran.
+	TestRuns string `json:"test_runs,omitempty"`
 }
 
 // MRStatus represents the status of a merge request.
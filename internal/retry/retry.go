@@ -0,0 +1,141 @@
+// Package retry runs operations with exponential backoff and jitter.
+//
+// For Dolt errors that should be retried according to their class (lock
+// contention, read-only, capacity), use errclass.Retry, which builds on the
+// backoff here. This package is for the rest: waiting for a server to come
+// up, for a process to exit, or for a contended resource to free up.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Policy is how an operation is retried.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// 1 disables retry; 0 leaves only MaxElapsed as a limit.
+	MaxAttempts int
+
+	// MaxElapsed stops retrying when the next attempt would start this long
+	// after the first. Zero means no limit.
+	MaxElapsed time.Duration
+
+	// Initial is the delay after the first failure. Each further failure
+	// multiplies it by Multiplier (2 when zero), up to Max.
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+
+	// Jitter randomizes each delay by ±Jitter/2 (0.5 gives ±25%), so
+	// callers that failed together don't retry together.
+	Jitter float64
+
+	// OnRetry, if set, is called after each failed attempt that will be
+	// retried, before the delay. Use it to log progress.
+	OnRetry func(Attempt)
+}
+
+// Attempt describes a failed attempt that is about to be retried.
+type Attempt struct {
+	Number  int           // 1-indexed
+	Err     error         // why it failed
+	Delay   time.Duration // wait before the next attempt
+	Elapsed time.Duration // since the first attempt started
+}
+
+// Delay returns the wait after failed attempt n (1-indexed).
+func (p Policy) Delay(n int) time.Duration {
+	mult := p.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	delay := p.Initial
+	for i := 1; i < n; i++ {
+		delay = time.Duration(float64(delay) * mult)
+		if p.Max > 0 && delay > p.Max {
+			delay = p.Max
+			break
+		}
+	}
+	if p.Jitter > 0 {
+		delay = time.Duration(float64(delay) * (1.0 + (rand.Float64()-0.5)*p.Jitter)) //nolint:gosec // G404: jitter doesn't need crypto rand
+	}
+	if p.Max > 0 && delay > p.Max {
+		delay = p.Max
+	}
+	return delay
+}
+
+// Constant returns a policy that makes up to attempts attempts, interval
+// apart: the shape of a plain polling loop.
+func Constant(attempts int, interval time.Duration) Policy {
+	return Policy{MaxAttempts: attempts, Initial: interval, Max: interval, Multiplier: 1}
+}
+
+// permanentError marks an error that must not be retried.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Stop wraps err so Do returns it at once instead of retrying. Do returns
+// err itself, not the wrapper.
+func Stop(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls fn until it succeeds, returns an error wrapped by Stop, or the
+// policy's limits are reached, and returns fn's last error. If ctx is done
+// while waiting, the last error is returned annotated with the cancellation.
+func Do(ctx context.Context, p Policy, fn func() error) error {
+	start := time.Now()
+	for n := 1; ; n++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if p.MaxAttempts > 0 && n >= p.MaxAttempts {
+			return err
+		}
+		delay := p.Delay(n)
+		elapsed := time.Since(start)
+		if p.MaxElapsed > 0 && elapsed+delay > p.MaxElapsed {
+			return err
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(Attempt{Number: n, Err: err, Delay: delay, Elapsed: elapsed})
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry canceled after %d attempts: %w)", err, n, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// errNotYet is the failure Poll reports while its condition is false.
+var errNotYet = errors.New("condition not met")
+
+// Poll checks cond under p until it reports true and returns whether it
+// ever did.
+func Poll(ctx context.Context, p Policy, cond func() bool) bool {
+	return Do(ctx, p, func() error {
+		if cond() {
+			return nil
+		}
+		return errNotYet
+	}) == nil
+}
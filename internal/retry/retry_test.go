@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	p := Policy{Initial: 100 * time.Millisecond, Max: time.Second}
+	for i, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if got := p.Delay(i + 1); got != want*time.Millisecond {
+			t.Errorf("Delay(%d) = %v, want %vms", i+1, got, want)
+		}
+	}
+
+	p.Multiplier = 3
+	if got := p.Delay(2); got != 300*time.Millisecond {
+		t.Errorf("Delay(2) with multiplier 3 = %v, want 300ms", got)
+	}
+
+	p = Policy{Initial: time.Second, Jitter: 0.5}
+	for i := 0; i < 50; i++ {
+		if got := p.Delay(1); got < 750*time.Millisecond || got > 1250*time.Millisecond {
+			t.Fatalf("jittered Delay(1) = %v, want within ±25%% of 1s", got)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	errBusy := errors.New("busy")
+	var attempts []Attempt
+	calls := 0
+	err := Do(context.Background(), Policy{
+		MaxAttempts: 5,
+		Initial:     time.Millisecond,
+		OnRetry:     func(a Attempt) { attempts = append(attempts, a) },
+	}, func() error {
+		calls++
+		if calls < 3 {
+			return errBusy
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("Do = %v after %d calls, want success on call 3", err, calls)
+	}
+	if len(attempts) != 2 || attempts[0].Number != 1 || attempts[1].Number != 2 || attempts[1].Err != errBusy {
+		t.Errorf("OnRetry attempts = %+v", attempts)
+	}
+
+	calls = 0
+	err = Do(context.Background(), Policy{MaxAttempts: 3, Initial: time.Millisecond}, func() error {
+		calls++
+		return errBusy
+	})
+	if err != errBusy || calls != 3 {
+		t.Errorf("exhausted Do = %v after %d calls, want busy after 3", err, calls)
+	}
+
+	calls = 0
+	errFatal := errors.New("fatal")
+	err = Do(context.Background(), Policy{MaxAttempts: 3, Initial: time.Millisecond}, func() error {
+		calls++
+		return Stop(errFatal)
+	})
+	if err != errFatal || calls != 1 {
+		t.Errorf("stopped Do = %v after %d calls, want fatal after 1", err, calls)
+	}
+}
+
+func TestDoMaxElapsed(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := Do(context.Background(), Policy{MaxElapsed: 50 * time.Millisecond, Initial: 20 * time.Millisecond, Max: 20 * time.Millisecond}, func() error {
+		calls++
+		return errors.New("down")
+	})
+	if err == nil {
+		t.Fatal("Do succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond+20*time.Millisecond {
+		t.Errorf("Do ran %v, past MaxElapsed", elapsed)
+	}
+	if calls < 2 || calls > 3 {
+		t.Errorf("calls = %d, want 2-3 within 50ms at 20ms intervals", calls)
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errBusy := errors.New("busy")
+	err := Do(ctx, Policy{Initial: time.Hour}, func() error { return errBusy })
+	if !errors.Is(err, errBusy) || !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled Do = %v, want busy and context.Canceled", err)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	n := 0
+	if !Poll(context.Background(), Constant(5, time.Millisecond), func() bool { n++; return n == 3 }) {
+		t.Error("Poll = false, want true on the third check")
+	}
+	if Poll(context.Background(), Constant(2, time.Millisecond), func() bool { return false }) {
+		t.Error("Poll = true for a condition that never holds")
+	}
+}
@@ -0,0 +1,73 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// CheckoutOptions returns the submodule and LFS options for the rig's
+// clones from its settings/config.json, or the defaults.
+func CheckoutOptions(rigPath string) git.CheckoutOptions {
+	var gc *config.GitConfig
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+		gc = settings.Git
+	}
+	return git.CheckoutOptions{Submodules: gc.SubmodulesEnabled(), LFS: gc.LFSMode()}
+}
+
+// lfsWarning explains what goes wrong when the checkout at path uses LFS
+// that won't be fetched, or returns "".
+func lfsWarning(path string, opts git.CheckoutOptions) string {
+	if !git.UsesLFS(path) {
+		return ""
+	}
+	switch {
+	case opts.LFS == git.LFSOff:
+		return "repository uses git-lfs but lfs is off for this rig; LFS files are pointer files"
+	case !git.LFSAvailable():
+		return "repository uses git-lfs but git-lfs is not installed; LFS files are pointer files until it is (then run: git lfs pull)"
+	}
+	return ""
+}
+
+// SyncCheckoutOptions records the rig's current checkout options on its
+// shared .repo.git, so worktrees made from then on follow settings edited
+// after the rig was added.
+func SyncCheckoutOptions(rigPath string) error {
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); err != nil {
+		return nil
+	}
+	return git.ApplyCheckoutOptions(bareRepoPath, CheckoutOptions(rigPath))
+}
+
+// ApplyIdentity sets the rig's git identity and commit signing for an
+// agent of role named name on the clone or worktree at repoPath. It does
+// nothing when the rig configures neither.
+func ApplyIdentity(rigPath, role, name, repoPath string, worktree bool) error {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.Git == nil {
+		return nil
+	}
+	g := git.NewGit(repoPath)
+	rigName := filepath.Base(rigPath)
+	if id := settings.Git.IdentityFor(rigName, role, name); id != nil {
+		if err := g.SetIdentity(id.Name, id.Email, id.SigningKey, worktree); err != nil {
+			return err
+		}
+	}
+	key := settings.Git.SigningKeyFor(rigName, role, name)
+	if key == "" {
+		return nil
+	}
+	signing := settings.Git.Signing
+	resolved, err := git.ResolveSigningKey(signing.SigningFormat(), key)
+	if err != nil {
+		return fmt.Errorf("commit signing: %w", err)
+	}
+	return g.ConfigureSigning(signing.SigningFormat(), resolved, signing.AllowedSigners, worktree)
+}
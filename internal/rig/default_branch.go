@@ -0,0 +1,146 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// BranchRefresh is the result of re-detecting a rig's default branch.
+type BranchRefresh struct {
+	Rig      string `json:"rig"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+	Changed  bool   `json:"changed"`
+
+	// Clones lists the rig's clones and whether each still has the previous
+	// default branch checked out.
+	Clones []CloneBranch `json:"clones"`
+}
+
+// CloneBranch is the checked-out branch of one clone in a rig.
+type CloneBranch struct {
+	Path        string `json:"path"` // relative to the rig, e.g. "mayor/rig"
+	Branch      string `json:"branch"`
+	NeedsUpdate bool   `json:"needs_update"`
+}
+
+// NeedUpdate returns the clones still on the previous default branch.
+func (r *BranchRefresh) NeedUpdate() []CloneBranch {
+	var out []CloneBranch
+	for _, c := range r.Clones {
+		if c.NeedsUpdate {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// RefreshDefaultBranch re-detects the rig's default branch from the remote
+// HEAD. default_branch is captured when the rig is added, so an upstream
+// rename (master -> main) otherwise leaves the rig targeting a stale branch.
+//
+// When the branch changed and dryRun is false, the shared bare repo is
+// fetched, its origin/HEAD is moved, and config.json is updated; the merge
+// queue and new polecats follow default_branch. Clones are only inspected,
+// never switched, since they may hold work in progress.
+func RefreshDefaultBranch(rigPath string, dryRun bool) (*BranchRefresh, error) {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading rig config: %w", err)
+	}
+
+	repo := rigRepoGit(rigPath)
+	if repo == nil {
+		return nil, fmt.Errorf("no git repository found in %s", rigPath)
+	}
+	detected, err := repo.QueryRemoteDefaultBranch("origin")
+	if err != nil {
+		return nil, fmt.Errorf("querying remote default branch: %w", err)
+	}
+
+	previous := cfg.DefaultBranch
+	if previous == "" {
+		previous = "main"
+	}
+	result := &BranchRefresh{
+		Rig:      filepath.Base(rigPath),
+		Previous: previous,
+		Current:  detected,
+		Changed:  detected != previous,
+	}
+
+	if result.Changed && !dryRun {
+		if err := repo.Fetch("origin"); err != nil {
+			return nil, fmt.Errorf("fetching origin: %w", err)
+		}
+		if err := repo.SetRemoteHEAD("origin", detected); err != nil {
+			return nil, fmt.Errorf("updating origin/HEAD: %w", err)
+		}
+		cfg.DefaultBranch = detected
+		if err := SaveRigConfig(rigPath, cfg); err != nil {
+			return nil, fmt.Errorf("saving rig config: %w", err)
+		}
+	}
+
+	for _, rel := range rigClonePaths(rigPath) {
+		branch, err := git.NewGit(filepath.Join(rigPath, rel)).CurrentBranch()
+		if err != nil {
+			continue
+		}
+		result.Clones = append(result.Clones, CloneBranch{
+			Path:        rel,
+			Branch:      branch,
+			NeedsUpdate: result.Changed && branch == previous,
+		})
+	}
+	return result, nil
+}
+
+// rigRepoGit returns the rig's shared bare repo, falling back to the mayor
+// clone for rigs without one.
+func rigRepoGit(rigPath string) *git.Git {
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); err == nil {
+		return git.NewGitWithDir(bareRepoPath, "")
+	}
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(mayorRigPath); err == nil {
+		return git.NewGit(mayorRigPath)
+	}
+	return nil
+}
+
+// rigClonePaths lists the rig's long-lived clones, relative to rigPath:
+// mayor/rig, refinery/rig, and every crew workspace. Polecat worktrees are
+// left out; they are recreated from the default branch.
+func rigClonePaths(rigPath string) []string {
+	var paths []string
+	for _, rel := range []string{filepath.Join("mayor", "rig"), filepath.Join("refinery", "rig")} {
+		if isClone(filepath.Join(rigPath, rel)) {
+			paths = append(paths, rel)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(rigPath, "crew"))
+	if err != nil {
+		return paths
+	}
+	var crew []string
+	for _, e := range entries {
+		if rel := filepath.Join("crew", e.Name()); e.IsDir() && isClone(filepath.Join(rigPath, rel)) {
+			crew = append(crew, rel)
+		}
+	}
+	sort.Strings(crew)
+	return append(paths, crew...)
+}
+
+// isClone reports whether dir is a git clone or worktree of its own, so a
+// plain directory isn't mistaken for the repo containing it.
+func isClone(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
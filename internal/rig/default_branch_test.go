@@ -0,0 +1,120 @@
+package rig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.email=test@test.com", "-c", "user.name=Test User", "-c", "init.defaultBranch=master"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v in %s: %v\n%s", args, dir, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupRenamedUpstreamRig creates a rig whose config says master while the
+// upstream has renamed its default branch to main.
+func setupRenamedUpstreamRig(t *testing.T) (rigPath, upstream string) {
+	t.Helper()
+	tmp := t.TempDir()
+	upstream = filepath.Join(tmp, "upstream.git")
+	gitIn(t, tmp, "init", "--bare", upstream)
+	gitIn(t, upstream, "symbolic-ref", "HEAD", "refs/heads/master")
+
+	seed := filepath.Join(tmp, "seed")
+	gitIn(t, tmp, "clone", upstream, seed)
+	if err := os.WriteFile(filepath.Join(seed, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, seed, "add", ".")
+	gitIn(t, seed, "commit", "-m", "initial")
+	gitIn(t, seed, "push", "origin", "HEAD:master")
+
+	rigPath = filepath.Join(tmp, "myrig")
+	bare := filepath.Join(rigPath, ".repo.git")
+	gitIn(t, tmp, "clone", "--bare", upstream, bare)
+	gitIn(t, bare, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	gitIn(t, bare, "fetch", "origin")
+	gitIn(t, tmp, "clone", upstream, filepath.Join(rigPath, "mayor", "rig"))
+	gitIn(t, tmp, "clone", upstream, filepath.Join(rigPath, "crew", "joe"))
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew", "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveRigConfig(rigPath, &RigConfig{Type: "rig", Version: 1, Name: "myrig", DefaultBranch: "master"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Upstream renames master -> main.
+	gitIn(t, seed, "push", "origin", "master:main")
+	gitIn(t, upstream, "symbolic-ref", "HEAD", "refs/heads/main")
+	gitIn(t, seed, "push", "origin", "--delete", "master")
+	return rigPath, upstream
+}
+
+func TestRefreshDefaultBranch_DryRun(t *testing.T) {
+	rigPath, _ := setupRenamedUpstreamRig(t)
+
+	result, err := RefreshDefaultBranch(rigPath, true)
+	if err != nil {
+		t.Fatalf("RefreshDefaultBranch: %v", err)
+	}
+	if !result.Changed || result.Previous != "master" || result.Current != "main" {
+		t.Errorf("result = %+v, want master -> main", result)
+	}
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultBranch != "master" {
+		t.Errorf("dry run changed default_branch to %q", cfg.DefaultBranch)
+	}
+}
+
+func TestRefreshDefaultBranch_Apply(t *testing.T) {
+	rigPath, _ := setupRenamedUpstreamRig(t)
+
+	result, err := RefreshDefaultBranch(rigPath, false)
+	if err != nil {
+		t.Fatalf("RefreshDefaultBranch: %v", err)
+	}
+	if !result.Changed || result.Current != "main" {
+		t.Fatalf("result = %+v, want change to main", result)
+	}
+
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultBranch != "main" {
+		t.Errorf("default_branch = %q, want main", cfg.DefaultBranch)
+	}
+	if head := gitIn(t, rigPath, "--git-dir", filepath.Join(rigPath, ".repo.git"), "symbolic-ref", "refs/remotes/origin/HEAD"); head != "refs/remotes/origin/main" {
+		t.Errorf("bare origin/HEAD = %q, want refs/remotes/origin/main", head)
+	}
+
+	var stale []string
+	for _, c := range result.NeedUpdate() {
+		stale = append(stale, c.Path)
+	}
+	want := []string{filepath.Join("mayor", "rig"), filepath.Join("crew", "joe")}
+	if strings.Join(stale, ",") != strings.Join(want, ",") {
+		t.Errorf("clones needing update = %v, want %v", stale, want)
+	}
+
+	// A second refresh is a no-op.
+	again, err := RefreshDefaultBranch(rigPath, false)
+	if err != nil {
+		t.Fatalf("second RefreshDefaultBranch: %v", err)
+	}
+	if again.Changed || len(again.NeedUpdate()) != 0 {
+		t.Errorf("second refresh = %+v, want no change", again)
+	}
+}
@@ -0,0 +1,217 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/progress"
+)
+
+// DiskUsage is the disk usage of a rig (or the town itself) by category, in
+// bytes.
+type DiskUsage struct {
+	Name string `json:"name"`
+
+	// Clones are full clones: .repo.git, mayor/rig, and crew clones.
+	Clones int64 `json:"clones"`
+
+	// Worktrees are git worktrees: refinery/rig and polecat worktrees.
+	Worktrees int64 `json:"worktrees"`
+
+	// Dolt is the rig's database in .dolt-data. Zero when the Dolt server
+	// is remote.
+	Dolt int64 `json:"dolt"`
+
+	// Logs are headless session logs and agent transcripts.
+	Logs int64 `json:"logs"`
+
+	// Backups are migration backups and town backup archives.
+	Backups int64 `json:"backups"`
+
+	// Other is everything else: config, agent state, overlays.
+	Other int64 `json:"other"`
+
+	Total int64 `json:"total"`
+}
+
+// Add adds o's sizes to u.
+func (u *DiskUsage) Add(o DiskUsage) {
+	u.Clones += o.Clones
+	u.Worktrees += o.Worktrees
+	u.Dolt += o.Dolt
+	u.Logs += o.Logs
+	u.Backups += o.Backups
+	u.Other += o.Other
+	u.Total += o.Total
+}
+
+func (u *DiskUsage) total() {
+	u.Total = u.Clones + u.Worktrees + u.Dolt + u.Logs + u.Backups + u.Other
+}
+
+// DiskReport is the disk usage of every measured rig, the town-level files
+// that belong to no rig, and their sum.
+type DiskReport struct {
+	Rigs  []DiskUsage `json:"rigs"`
+	Town  DiskUsage   `json:"town"`
+	Total DiskUsage   `json:"total"`
+}
+
+// DiskUsageOptions controls MeasureDiskUsage.
+type DiskUsageOptions struct {
+	// LogPatterns returns glob patterns for a rig's log and transcript
+	// files, as for GCOptions.LogPatterns. Optional.
+	LogPatterns func(rigName string) []string
+}
+
+// Town-root backup locations: migration backups hold <rig>-beads and
+// town-beads directories, schema backups hold one directory per database,
+// the test backup holds rigs/<rig>, and 'gt town backup' writes
+// gt-town-*.tar.gz by default.
+const (
+	migrationBackupGlob = "migration-backup-*"
+	schemaBackupGlob    = "schema-backup-*"
+	migrationTestBackup = ".migration-test-backup"
+	townBackupGlob      = "gt-town-*.tar.gz"
+)
+
+// MeasureDiskUsage reports disk usage for the named rigs of the town at
+// townRoot. The town row covers what lies outside every rig directory, so
+// it is only complete when all rigs are measured. Sizes are apparent file
+// sizes; unreadable entries are skipped.
+func MeasureDiskUsage(townRoot string, rigNames []string, opts DiskUsageOptions) *DiskReport {
+	report := &DiskReport{Town: DiskUsage{Name: "(town)"}}
+	remoteDolt := doltserver.DefaultConfig(townRoot).IsRemote()
+
+	var rigLogsInTown, rigBackups, rigDolt int64
+	for _, name := range rigNames {
+		u := DiskUsage{Name: name}
+		measureRigDir(filepath.Join(townRoot, name), &u)
+		if !remoteDolt {
+			u.Dolt = progress.DirSize(doltserver.RigDatabaseDir(townRoot, name))
+			rigDolt += u.Dolt
+		}
+		if opts.LogPatterns != nil {
+			for _, path := range globFiles(opts.LogPatterns(name)) {
+				size := fileSize(path)
+				u.Logs += size
+				if isWithin(townRoot, path) {
+					rigLogsInTown += size
+				}
+			}
+		}
+		backups, _ := filepath.Glob(filepath.Join(townRoot, migrationBackupGlob, name+"-beads"))
+		schemaBackups, _ := filepath.Glob(filepath.Join(townRoot, schemaBackupGlob, name))
+		backups = append(backups, schemaBackups...)
+		backups = append(backups, filepath.Join(townRoot, migrationTestBackup, "rigs", name))
+		for _, path := range backups {
+			u.Backups += progress.DirSize(path)
+		}
+		rigBackups += u.Backups
+
+		u.total()
+		report.Rigs = append(report.Rigs, u)
+	}
+
+	// Town level: whatever the rigs didn't claim.
+	skip := map[string]bool{".dolt-data": true, "daemon": true, "logs": true, migrationTestBackup: true}
+	for _, name := range rigNames {
+		skip[name] = true
+	}
+	town := &report.Town
+	if !remoteDolt {
+		town.Dolt = progress.DirSize(doltserver.DefaultConfig(townRoot).DataDir) - rigDolt
+	}
+	town.Logs = progress.DirSize(filepath.Join(townRoot, "daemon")) + progress.DirSize(filepath.Join(townRoot, "logs")) - rigLogsInTown
+	backups := []string{filepath.Join(townRoot, migrationTestBackup)}
+	for _, glob := range []string{migrationBackupGlob, schemaBackupGlob, townBackupGlob} {
+		matches, _ := filepath.Glob(filepath.Join(townRoot, glob))
+		backups = append(backups, matches...)
+	}
+	for _, path := range backups {
+		town.Backups += progress.DirSize(path)
+		skip[filepath.Base(path)] = true
+	}
+	town.Backups -= rigBackups
+	entries, _ := os.ReadDir(townRoot)
+	for _, e := range entries {
+		if !skip[e.Name()] {
+			town.Other += progress.DirSize(filepath.Join(townRoot, e.Name()))
+		}
+	}
+	town.total()
+
+	report.Total = DiskUsage{Name: "total"}
+	for _, u := range report.Rigs {
+		report.Total.Add(u)
+	}
+	report.Total.Add(report.Town)
+	return report
+}
+
+// measureRigDir splits a rig directory into clones, worktrees, and other.
+// Any directory below the rig with a .git directory is a clone, one with a
+// .git file is a worktree, and the shared bare repo is a clone.
+func measureRigDir(rigPath string, u *DiskUsage) {
+	_ = filepath.Walk(rigPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if !info.IsDir() {
+			u.Other += info.Size()
+			return nil
+		}
+		if path == rigPath {
+			return nil
+		}
+		if info.Name() == ".repo.git" {
+			u.Clones += progress.DirSize(path)
+			return filepath.SkipDir
+		}
+		if gitInfo, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			if gitInfo.IsDir() {
+				u.Clones += progress.DirSize(path)
+			} else {
+				u.Worktrees += progress.DirSize(path)
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// globFiles returns the regular files matching any of patterns, each once.
+func globFiles(patterns []string) []string {
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+				seen[m] = true
+			}
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// isWithin reports whether path is inside dir.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,58 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMeasureDiskUsage(t *testing.T) {
+	town := t.TempDir()
+	write := func(rel string, size int) {
+		t.Helper()
+		path := filepath.Join(town, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("demo/.repo.git/objects/pack", 1000)
+	write("demo/mayor/rig/.git/index", 200)
+	write("demo/mayor/rig/main.go", 50)
+	write("demo/crew/dave/.git/index", 100)
+	write("demo/refinery/rig/.git", 10)
+	write("demo/refinery/rig/main.go", 40)
+	write("demo/polecats/nux/demo/.git", 10)
+	write("demo/polecats/nux/demo/main.go", 30)
+	write("demo/config.json", 5)
+	write("demo/witness/state.json", 3)
+	write(".dolt-data/demo/.dolt/noms", 400)
+	write(".dolt-data/hq/.dolt/noms", 70)
+	write("migration-backup-20260101-000000/demo-beads/issues.jsonl", 20)
+	write("migration-backup-20260101-000000/town-beads/issues.jsonl", 8)
+	write("gt-town-20260101-000000.tar.gz", 9)
+	write("daemon/headless/gt-demo-nux.log", 60)
+	write("daemon/daemon.log", 6)
+	write("mayor/rigs.json", 2)
+
+	report := MeasureDiskUsage(town, []string{"demo"}, DiskUsageOptions{
+		LogPatterns: func(name string) []string {
+			return []string{filepath.Join(town, "daemon", "headless", "gt-"+name+"-*.log")}
+		},
+	})
+
+	want := DiskUsage{Name: "demo", Clones: 1350, Worktrees: 90, Dolt: 400, Logs: 60, Backups: 20, Other: 8, Total: 1928}
+	if len(report.Rigs) != 1 || report.Rigs[0] != want {
+		t.Errorf("rig = %+v\nwant  %+v", report.Rigs, want)
+	}
+	wantTown := DiskUsage{Name: "(town)", Dolt: 70, Logs: 6, Backups: 17, Other: 2, Total: 95}
+	if report.Town != wantTown {
+		t.Errorf("town = %+v\nwant   %+v", report.Town, wantTown)
+	}
+	if report.Total.Total != want.Total+wantTown.Total || report.Total.Dolt != 470 {
+		t.Errorf("total = %+v", report.Total)
+	}
+}
@@ -0,0 +1,291 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/progress"
+)
+
+// gcBranchPattern limits branch cleanup to branches gastown creates itself;
+// crew and human branches are never touched.
+const gcBranchPattern = "polecat/*"
+
+// GCOptions controls GarbageCollect.
+type GCOptions struct {
+	// Retention is how long matching log and transcript files are kept.
+	// Zero skips log cleanup.
+	Retention time.Duration
+
+	// LogPatterns are glob patterns for the rig's log and transcript files.
+	// They usually live outside the rig (daemon/headless, ~/.claude), so the
+	// caller supplies them.
+	LogPatterns []string
+
+	// KeepLog reports whether a matched file is still in use, e.g. the log of
+	// a live session. Optional.
+	KeepLog func(path string) bool
+
+	// PolecatActive reports whether a polecat still has a session, in which
+	// case its worktree is left alone even if git has lost track of it.
+	// Optional.
+	PolecatActive func(name string) bool
+
+	// NoRemote skips deleting merged polecat branches on the remote.
+	NoRemote bool
+
+	DryRun bool
+}
+
+// GCResult lists what GarbageCollect removed (or, in a dry run, would
+// remove).
+type GCResult struct {
+	Rig    string `json:"rig"`
+	DryRun bool   `json:"dry_run"`
+
+	Branches  []GCBranch `json:"branches"`
+	Worktrees []GCPath   `json:"worktrees"`
+	Logs      []GCPath   `json:"logs"`
+
+	// ReclaimedBytes is the size of the removed worktrees and logs. Objects
+	// freed by branch deletion are left to git's own gc.
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+
+	// Warnings are non-fatal failures; the rest of the run continues.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GCBranch is a deleted branch.
+type GCBranch struct {
+	Name   string `json:"name"`
+	Remote bool   `json:"remote"`           // deleted on the remote rather than locally
+	Reason string `json:"reason,omitempty"` // see git.PrunedBranch
+}
+
+// GCPath is a removed file or directory.
+type GCPath struct {
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// GarbageCollect cleans up what a long-running rig accumulates: polecat
+// branches already merged to the default branch, worktrees left behind by
+// crashed polecats, and logs and transcripts older than the retention window.
+//
+// Worktrees are handled first so branches held by dead worktrees become
+// deletable. Branch deletion only uses git branch -d and only touches
+// remote branches that are ancestors of the default branch, so no
+// unmerged work is lost.
+func GarbageCollect(rigPath string, opts GCOptions) (*GCResult, error) {
+	repo := rigRepoGit(rigPath)
+	if repo == nil {
+		return nil, fmt.Errorf("no git repository found in %s", rigPath)
+	}
+	result := &GCResult{Rig: filepath.Base(rigPath), DryRun: opts.DryRun}
+
+	if err := gcWorktrees(repo, rigPath, opts, result); err != nil {
+		return nil, err
+	}
+	if err := gcBranches(repo, rigPath, opts, result); err != nil {
+		return nil, err
+	}
+	if opts.Retention > 0 {
+		gcLogs(opts, result)
+	}
+
+	for _, p := range result.Worktrees {
+		result.ReclaimedBytes += p.Bytes
+	}
+	for _, p := range result.Logs {
+		result.ReclaimedBytes += p.Bytes
+	}
+	return result, nil
+}
+
+// gcWorktrees prunes registrations whose directory is gone and removes
+// polecat worktree directories whose git metadata is gone. The latter is
+// what a polecat that crashed mid-nuke leaves behind: a directory git can no
+// longer operate in, so it can't hold commits.
+func gcWorktrees(repo *git.Git, rigPath string, opts GCOptions, result *GCResult) error {
+	worktrees, err := repo.WorktreeList()
+	if err != nil {
+		return fmt.Errorf("listing worktrees: %w", err)
+	}
+	stale := false
+	for _, wt := range worktrees {
+		if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+			result.Worktrees = append(result.Worktrees, GCPath{Path: wt.Path, Reason: "directory missing"})
+			stale = true
+		}
+	}
+	if stale && !opts.DryRun {
+		if err := repo.WorktreePrune(); err != nil {
+			return fmt.Errorf("pruning worktrees: %w", err)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(rigPath, "polecats"))
+	if err != nil {
+		return nil
+	}
+	rigName := filepath.Base(rigPath)
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if opts.PolecatActive != nil && opts.PolecatActive(e.Name()) {
+			continue
+		}
+		// polecats/<name>/<rig>, or polecats/<name> in the old layout.
+		for _, dir := range []string{
+			filepath.Join(rigPath, "polecats", e.Name(), rigName),
+			filepath.Join(rigPath, "polecats", e.Name()),
+		} {
+			if !brokenWorktree(dir) {
+				continue
+			}
+			size := progress.DirSize(dir)
+			if !opts.DryRun {
+				if err := os.RemoveAll(dir); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("removing %s: %v", dir, err))
+					break
+				}
+			}
+			result.Worktrees = append(result.Worktrees, GCPath{Path: dir, Bytes: size, Reason: "git metadata missing"})
+			break
+		}
+	}
+	return nil
+}
+
+// brokenWorktree reports whether dir is a linked worktree (a .git file
+// pointing at the repo's metadata) whose metadata no longer exists.
+func brokenWorktree(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, ".git")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return false // no .git, or a full clone with a .git directory
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return false
+	}
+	gitdir = strings.TrimSpace(gitdir)
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(dir, gitdir)
+	}
+	_, err = os.Stat(gitdir)
+	return os.IsNotExist(err)
+}
+
+// gcBranches deletes local polecat branches that are merged or whose remote
+// branch is gone, then (unless opts.NoRemote) polecat branches on the remote
+// that are merged to the default branch.
+func gcBranches(repo *git.Git, rigPath string, opts GCOptions, result *GCResult) error {
+	if err := repo.FetchPrune("origin"); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("fetch --prune: %v", err))
+	}
+
+	pruned, err := repo.PruneStaleBranches(gcBranchPattern, opts.DryRun)
+	if err != nil {
+		return fmt.Errorf("pruning local branches: %w", err)
+	}
+	// A real run can't delete branches checked out in a worktree; leave them
+	// out of dry runs too.
+	checkedOut := make(map[string]bool)
+	if worktrees, err := repo.WorktreeList(); err == nil {
+		for _, wt := range worktrees {
+			if _, err := os.Stat(wt.Path); err == nil {
+				checkedOut[wt.Branch] = true
+			}
+		}
+	}
+	for _, b := range pruned {
+		if opts.DryRun && checkedOut[b.Name] {
+			continue
+		}
+		result.Branches = append(result.Branches, GCBranch{Name: b.Name, Reason: b.Reason})
+	}
+
+	if opts.NoRemote {
+		return nil
+	}
+	defaultBranch := "main"
+	cfg, err := LoadRigConfig(rigPath)
+	if err == nil && cfg.DefaultBranch != "" {
+		defaultBranch = cfg.DefaultBranch
+	}
+	// Fork rigs push polecat branches to the fork, not the upstream.
+	remote := "origin"
+	if err == nil && cfg.PushURL != "" {
+		remote = cfg.PushURL
+	}
+	heads, err := repo.ListRemoteHeads(remote, "refs/heads/"+strings.TrimSuffix(gcBranchPattern, "*"))
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("listing remote branches: %v", err))
+		return nil
+	}
+	refs := make([]string, 0, len(heads))
+	for ref := range heads {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		branch := strings.TrimPrefix(ref, "refs/heads/")
+		// Check the tip the remote reported, not a possibly stale tracking
+		// ref. A tip we don't have locally can't be proven merged and is kept.
+		sha := heads[ref]
+		merged, err := repo.IsAncestor(sha, "origin/"+defaultBranch)
+		if err != nil || !merged {
+			continue
+		}
+		if !opts.DryRun {
+			// The lease keeps a branch that gained commits since ls-remote.
+			if err := repo.PushWithLease("origin", ":"+ref, branch, sha); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("deleting remote %s: %v", branch, err))
+				continue
+			}
+		}
+		result.Branches = append(result.Branches, GCBranch{Name: branch, Remote: true, Reason: "merged"})
+	}
+	return nil
+}
+
+// gcLogs removes files matching opts.LogPatterns last modified before the
+// retention window.
+func gcLogs(opts GCOptions, result *GCResult) {
+	cutoff := time.Now().Add(-opts.Retention)
+	seen := make(map[string]bool)
+	for _, pattern := range opts.LogPatterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("log pattern %s: %v", pattern, err))
+			continue
+		}
+		for _, path := range matches {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if opts.KeepLog != nil && opts.KeepLog(path) {
+				continue
+			}
+			if !opts.DryRun {
+				if err := os.Remove(path); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("removing %s: %v", path, err))
+					continue
+				}
+			}
+			result.Logs = append(result.Logs, GCPath{Path: path, Bytes: info.Size()})
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupGCRig creates a rig with polecat worktrees from its bare repo:
+// alpha has pushed work that upstream merged, beta has pushed unmerged work,
+// gone's directory was deleted, and crashed's git metadata was deleted.
+func setupGCRig(t *testing.T) (rigPath, upstream string) {
+	t.Helper()
+	tmp := t.TempDir()
+	upstream = filepath.Join(tmp, "upstream.git")
+	gitIn(t, tmp, "init", "--bare", upstream)
+	gitIn(t, upstream, "symbolic-ref", "HEAD", "refs/heads/master")
+
+	seed := filepath.Join(tmp, "seed")
+	gitIn(t, tmp, "clone", upstream, seed)
+	commitFile(t, seed, "README.md", "# Test\n")
+	gitIn(t, seed, "push", "origin", "HEAD:master")
+
+	rigPath = filepath.Join(tmp, "myrig")
+	bare := filepath.Join(rigPath, ".repo.git")
+	gitIn(t, tmp, "clone", "--bare", upstream, bare)
+	gitIn(t, bare, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	gitIn(t, bare, "fetch", "origin")
+	if err := SaveRigConfig(rigPath, &RigConfig{Type: "rig", Version: 1, Name: "myrig", DefaultBranch: "master"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"alpha", "beta", "gone", "crashed"} {
+		wt := filepath.Join(rigPath, "polecats", name, "myrig")
+		gitIn(t, bare, "worktree", "add", "-b", "polecat/"+name, wt, "origin/master")
+		commitFile(t, wt, name+".txt", name+"\n")
+		gitIn(t, wt, "push", "origin", "polecat/"+name)
+	}
+	// Upstream merges alpha's work; beta stays open.
+	gitIn(t, seed, "fetch", "origin")
+	gitIn(t, seed, "merge", "--ff-only", "origin/polecat/alpha")
+	gitIn(t, seed, "push", "origin", "HEAD:master")
+	gitIn(t, bare, "fetch", "origin")
+
+	// alpha finished and was removed cleanly; gone's directory vanished
+	// without git noticing; crashed lost its worktree metadata.
+	gitIn(t, bare, "worktree", "remove", "--force", filepath.Join(rigPath, "polecats", "alpha", "myrig"))
+	if err := os.RemoveAll(filepath.Join(rigPath, "polecats", "gone")); err != nil {
+		t.Fatal(err)
+	}
+	crashed := filepath.Join(rigPath, "polecats", "crashed", "myrig")
+	if err := os.RemoveAll(gitIn(t, crashed, "rev-parse", "--absolute-git-dir")); err != nil {
+		t.Fatal(err)
+	}
+	return rigPath, upstream
+}
+
+func gcBranchNames(r *GCResult, remote bool) []string {
+	var names []string
+	for _, b := range r.Branches {
+		if b.Remote == remote {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
+func TestGarbageCollect_DryRun(t *testing.T) {
+	rigPath, upstream := setupGCRig(t)
+
+	result, err := GarbageCollect(rigPath, GCOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("GarbageCollect: %v", err)
+	}
+	if got := strings.Join(gcBranchNames(result, true), ","); got != "polecat/alpha" {
+		t.Errorf("remote branches = %q, want polecat/alpha", got)
+	}
+	if len(result.Worktrees) != 2 {
+		t.Errorf("worktrees = %+v, want gone and crashed", result.Worktrees)
+	}
+
+	// Nothing changed.
+	if _, err := os.Stat(filepath.Join(rigPath, "polecats", "crashed", "myrig")); err != nil {
+		t.Errorf("dry run removed crashed worktree: %v", err)
+	}
+	if refs := gitIn(t, upstream, "branch", "--list", "polecat/alpha"); refs == "" {
+		t.Error("dry run deleted polecat/alpha on the remote")
+	}
+}
+
+func TestGarbageCollect_Branches(t *testing.T) {
+	rigPath, upstream := setupGCRig(t)
+
+	result, err := GarbageCollect(rigPath, GCOptions{})
+	if err != nil {
+		t.Fatalf("GarbageCollect: %v", err)
+	}
+	if got := strings.Join(gcBranchNames(result, true), ","); got != "polecat/alpha" {
+		t.Errorf("remote branches = %q, want polecat/alpha", got)
+	}
+	remote := gitIn(t, upstream, "branch", "--list", "polecat/*")
+	if strings.Contains(remote, "polecat/alpha") || !strings.Contains(remote, "polecat/beta") {
+		t.Errorf("remote branches after gc:\n%s\nwant only alpha deleted", remote)
+	}
+
+	local := gitIn(t, filepath.Join(rigPath, ".repo.git"), "branch", "--list", "polecat/*")
+	if strings.Contains(local, "polecat/alpha") {
+		t.Errorf("merged polecat/alpha kept locally:\n%s", local)
+	}
+	if !strings.Contains(local, "polecat/beta") {
+		t.Errorf("unmerged polecat/beta deleted locally:\n%s", local)
+	}
+}
+
+func TestGarbageCollect_Worktrees(t *testing.T) {
+	rigPath, _ := setupGCRig(t)
+	bare := filepath.Join(rigPath, ".repo.git")
+
+	result, err := GarbageCollect(rigPath, GCOptions{NoRemote: true})
+	if err != nil {
+		t.Fatalf("GarbageCollect: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, "polecats", "crashed", "myrig")); !os.IsNotExist(err) {
+		t.Errorf("crashed worktree not removed (err=%v)", err)
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, "polecats", "beta", "myrig")); err != nil {
+		t.Errorf("live worktree removed: %v", err)
+	}
+	if wts := gitIn(t, bare, "worktree", "list"); strings.Contains(wts, "gone") {
+		t.Errorf("stale worktree still registered:\n%s", wts)
+	}
+	if result.ReclaimedBytes == 0 {
+		t.Error("ReclaimedBytes = 0, want size of crashed worktree")
+	}
+}
+
+func TestGarbageCollect_PolecatActive(t *testing.T) {
+	rigPath, _ := setupGCRig(t)
+
+	_, err := GarbageCollect(rigPath, GCOptions{
+		NoRemote:      true,
+		PolecatActive: func(name string) bool { return name == "crashed" },
+	})
+	if err != nil {
+		t.Fatalf("GarbageCollect: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, "polecats", "crashed", "myrig")); err != nil {
+		t.Errorf("worktree of active polecat removed: %v", err)
+	}
+}
+
+func TestGarbageCollect_Logs(t *testing.T) {
+	rigPath, _ := setupGCRig(t)
+	logDir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"old.log", "fresh.log", "live.log"} {
+		path := filepath.Join(logDir, name)
+		if err := os.WriteFile(path, []byte("some output\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if name != "fresh.log" {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	result, err := GarbageCollect(rigPath, GCOptions{
+		NoRemote:    true,
+		Retention:   24 * time.Hour,
+		LogPatterns: []string{filepath.Join(logDir, "*.log")},
+		KeepLog:     func(path string) bool { return filepath.Base(path) == "live.log" },
+	})
+	if err != nil {
+		t.Fatalf("GarbageCollect: %v", err)
+	}
+	if len(result.Logs) != 1 || filepath.Base(result.Logs[0].Path) != "old.log" {
+		t.Fatalf("logs = %+v, want only old.log", result.Logs)
+	}
+	if result.Logs[0].Bytes != int64(len("some output\n")) {
+		t.Errorf("old.log bytes = %d", result.Logs[0].Bytes)
+	}
+	for name, want := range map[string]bool{"old.log": false, "fresh.log": true, "live.log": true} {
+		_, err := os.Stat(filepath.Join(logDir, name))
+		if got := err == nil; got != want {
+			t.Errorf("%s exists = %v, want %v", name, got, want)
+		}
+	}
+}
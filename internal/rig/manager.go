@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/progress"
 	"github.com/steveyegge/gastown/internal/templates/commands"
 	"github.com/steveyegge/gastown/internal/util"
 )
@@ -168,6 +170,7 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 		PushURL:   strings.TrimSpace(entry.PushURL),
 		LocalRepo: entry.LocalRepo,
 		Config:    entry.BeadsConfig,
+		Remote:    entry.Remote,
 	}
 
 	// Scan for polecats
@@ -224,6 +227,17 @@ type AddRigOptions struct {
 	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
 	LocalRepo     string // Optional local repo for reference clones
 	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+
+	// Git, if set, is saved to the rig settings before cloning and selects
+	// submodule and LFS handling for every clone of the rig.
+	Git *config.GitConfig
+
+	// Stages selects the provisioning stages to run, in any order; nil runs
+	// them all. Unselected stages are recorded as skipped.
+	Stages []string
+	// AfterStage, if set, runs after each stage succeeds. An error from it
+	// fails the stage.
+	AfterStage func(stage string) error
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -268,6 +282,10 @@ func resolveLocalRepo(path, gitURL string) (string, string) {
 //	├── witness/               # Witness agent (no clone)
 //	├── polecats/              # Worker directories (empty)
 //	└── crew/<crew>/           # Default human workspace
+//
+// The work runs as the stages in ProvisionStages, recorded in the rig's
+// provisioning checklist. If a stage after config fails the rig directory
+// is kept so Provision can resume from the failed stage.
 func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if m.RigExists(opts.Name) {
 		return nil, ErrRigExists
@@ -289,10 +307,21 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		}
 	}
 
+	stages := opts.Stages
+	if stages == nil {
+		stages = ProvisionStages
+	}
+	if !slices.Contains(stages, StageConfig) {
+		return nil, fmt.Errorf("the %s stage cannot be skipped when adding a rig", StageConfig)
+	}
+
 	rigPath := filepath.Join(m.townRoot, opts.Name)
 
 	// Check if directory already exists
 	if _, err := os.Stat(rigPath); err == nil {
+		if _, err := LoadProvisionChecklist(rigPath); err == nil {
+			return nil, fmt.Errorf("directory already exists: %s\n\nA previous gt rig add stopped part way; resume it with:\n  gt rig provision %s", rigPath, opts.Name)
+		}
 		return nil, fmt.Errorf("directory already exists: %s\n\nTo adopt an existing directory, use --adopt:\n  gt rig add %s --adopt", rigPath, opts.Name)
 	}
 
@@ -309,56 +338,111 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if warn != "" {
 		fmt.Printf("  Warning: %s\n", warn)
 	}
+	opts.LocalRepo = localRepo
 
 	// Create container directory
 	if err := os.MkdirAll(rigPath, 0755); err != nil {
 		return nil, fmt.Errorf("creating rig directory: %w", err)
 	}
 
-	// Track cleanup on failure (best-effort cleanup)
-	cleanup := func() { _ = os.RemoveAll(rigPath) }
-	success := false
-	defer func() {
-		if !success {
-			cleanup()
-		}
-	}()
+	run := &provisionRun{
+		opts:    opts,
+		rigPath: rigPath,
+		checklist: newProvisionChecklist(stages, ProvisionRequest{
+			PrefixExplicit: userProvidedPrefix,
+			DefaultBranch:  opts.DefaultBranch,
+		}),
+	}
+	err := m.runStages(run, stages)
+	if err != nil && !run.checklist.Done(StageConfig) {
+		// Nothing worth resuming yet (best-effort cleanup)
+		_ = os.RemoveAll(rigPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.registerProvisioned(run)
+}
 
-	// Create rig config
+// provisionConfig writes the rig's config.json and settings directory.
+func (m *Manager) provisionConfig(run *provisionRun) error {
+	opts := run.opts
 	rigConfig := &RigConfig{
 		Type:      "rig",
 		Version:   CurrentRigConfigVersion,
 		Name:      opts.Name,
 		GitURL:    opts.GitURL,
 		PushURL:   opts.PushURL,
-		LocalRepo: localRepo,
+		LocalRepo: opts.LocalRepo,
 		CreatedAt: time.Now(),
 		Beads: &BeadsConfig{
 			Prefix: opts.BeadsPrefix,
 		},
 	}
-	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-		return nil, fmt.Errorf("saving rig config: %w", err)
+	if err := m.saveRigConfig(run.rigPath, rigConfig); err != nil {
+		return fmt.Errorf("saving rig config: %w", err)
+	}
+	run.cfg = rigConfig
+
+	// Create rig-level settings directory (used by gt config for rig overrides)
+	rigSettingsPath := filepath.Join(run.rigPath, constants.DirSettings)
+	if err := os.MkdirAll(rigSettingsPath, 0755); err != nil {
+		return fmt.Errorf("creating settings dir: %w", err)
+	}
+	if opts.Git != nil {
+		settingsPath := config.RigSettingsPath(run.rigPath)
+		settings, err := config.LoadRigSettings(settingsPath)
+		if err != nil {
+			settings = config.NewRigSettings()
+		}
+		settings.Git = opts.Git
+		if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+			return fmt.Errorf("saving rig settings: %w", err)
+		}
+	}
+	return nil
+}
+
+// provisionClone creates the shared bare repo, the mayor clone, the
+// refinery worktree and the agent directories.
+func (m *Manager) provisionClone(run *provisionRun) error {
+	rigPath := run.rigPath
+	rigConfig := run.cfg
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+	refineryRigPath := filepath.Join(rigPath, "refinery", "rig")
+
+	// A failed earlier attempt may have left partial clones behind.
+	for _, path := range []string{refineryRigPath, mayorRigPath, bareRepoPath} {
+		_ = os.RemoveAll(path)
 	}
 
 	// Create shared bare repo as source of truth for refinery and polecats.
 	// This allows refinery to see polecat branches without pushing to remote.
 	// Mayor remains a separate clone (doesn't need branch visibility).
 	fmt.Printf("  Cloning repository (this may take a moment)...\n")
-	bareRepoPath := filepath.Join(rigPath, ".repo.git")
-	if localRepo != "" {
-		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
+	bar := progress.New(os.Stdout, "Cloning", 0, progress.Count)
+	m.git.SetProgress(bar)
+	defer m.git.SetProgress(nil)
+	checkout := CheckoutOptions(rigPath)
+	m.git.SetCheckoutOptions(&checkout)
+	defer m.git.SetCheckoutOptions(nil)
+	defer bar.Finish()
+	if rigConfig.LocalRepo != "" {
+		if err := m.git.CloneBareWithReference(rigConfig.GitURL, bareRepoPath, rigConfig.LocalRepo); err != nil {
+			bar.Finish()
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(bareRepoPath)
-			if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
-				return nil, wrapCloneError(err, opts.GitURL)
+			if err := m.git.CloneBare(rigConfig.GitURL, bareRepoPath); err != nil {
+				return wrapCloneError(err, rigConfig.GitURL)
 			}
 		}
 	} else {
-		if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
-			return nil, wrapCloneError(err, opts.GitURL)
+		if err := m.git.CloneBare(rigConfig.GitURL, bareRepoPath); err != nil {
+			return wrapCloneError(err, rigConfig.GitURL)
 		}
 	}
+	bar.Finish()
 	fmt.Printf("   ✓ Created shared bare repo\n")
 	bareGit := git.NewGitWithDir(bareRepoPath, "")
 
@@ -366,24 +450,25 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// An empty repo has no refs, so RemoteDefaultBranch/DefaultBranch would
 	// return "main" as a fallback, but checkout would fail with an opaque error.
 	if empty, err := bareGit.IsEmpty(); err != nil {
-		return nil, fmt.Errorf("checking if repository is empty: %w", err)
+		return fmt.Errorf("checking if repository is empty: %w", err)
 	} else if empty {
-		return nil, fmt.Errorf("repository %s is empty (no commits). Push at least one commit before adding it as a rig", opts.GitURL)
+		return fmt.Errorf("repository %s is empty (no commits). Push at least one commit before adding it as a rig", rigConfig.GitURL)
 	}
 
 	// Configure push URL if provided (for read-only upstream repos)
 	// This sets origin's push URL to the fork while keeping fetch URL as upstream
-	if opts.PushURL != "" {
-		if err := bareGit.ConfigurePushURL("origin", opts.PushURL); err != nil {
-			return nil, fmt.Errorf("configuring push URL: %w", err)
+	if rigConfig.PushURL != "" {
+		if err := bareGit.ConfigurePushURL("origin", rigConfig.PushURL); err != nil {
+			return fmt.Errorf("configuring push URL: %w", err)
 		}
-		fmt.Printf("   ✓ Configured push URL (fork: %s)\n", util.RedactURL(opts.PushURL)) // fmt.Printf matches AddRig's established success output pattern
+		fmt.Printf("   ✓ Configured push URL (fork: %s)\n", util.RedactURL(rigConfig.PushURL)) // fmt.Printf matches AddRig's established success output pattern
 	}
 
 	// Determine default branch: use provided value or auto-detect from remote
+	requestedBranch := run.checklist.Request.DefaultBranch
 	var defaultBranch string
-	if opts.DefaultBranch != "" {
-		defaultBranch = opts.DefaultBranch
+	if requestedBranch != "" {
+		defaultBranch = requestedBranch
 	} else {
 		// Try to get default branch from remote first, fall back to local detection
 		defaultBranch = bareGit.RemoteDefaultBranch()
@@ -392,56 +477,148 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		}
 	}
 	// Validate user-provided branch exists on remote (auto-detected branches are inherently valid)
-	if opts.DefaultBranch != "" {
+	if requestedBranch != "" {
 		ref := fmt.Sprintf("origin/%s", defaultBranch)
 		if exists, err := bareGit.RefExists(ref); err != nil {
-			return nil, fmt.Errorf("checking ref %s: %w", ref, err)
+			return fmt.Errorf("checking ref %s: %w", ref, err)
 		} else if !exists {
-			return nil, fmt.Errorf("branch %q does not exist on remote (ref %s not found in bare repo)", defaultBranch, ref)
+			return fmt.Errorf("branch %q does not exist on remote (ref %s not found in bare repo)", defaultBranch, ref)
 		}
 	}
 
 	rigConfig.DefaultBranch = defaultBranch
 	// Re-save config with default branch
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-		return nil, fmt.Errorf("updating rig config with default branch: %w", err)
+		return fmt.Errorf("updating rig config with default branch: %w", err)
 	}
 
 	// Create mayor as regular clone (separate from bare repo).
 	// Mayor doesn't need to see polecat branches - that's refinery's job.
 	// This also allows mayor to stay on the default branch without conflicting with refinery.
 	fmt.Printf("  Creating mayor clone...\n")
-	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
 	if err := os.MkdirAll(filepath.Dir(mayorRigPath), 0755); err != nil {
-		return nil, fmt.Errorf("creating mayor dir: %w", err)
+		return fmt.Errorf("creating mayor dir: %w", err)
 	}
-	if localRepo != "" {
-		if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
+	if rigConfig.LocalRepo != "" {
+		if err := m.git.CloneWithReference(rigConfig.GitURL, mayorRigPath, rigConfig.LocalRepo); err != nil {
+			bar.Finish()
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(mayorRigPath)
-			if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
-				return nil, fmt.Errorf("cloning for mayor: %w", err)
+			if err := m.git.Clone(rigConfig.GitURL, mayorRigPath); err != nil {
+				return fmt.Errorf("cloning for mayor: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
-			return nil, fmt.Errorf("cloning for mayor: %w", err)
+		if err := m.git.Clone(rigConfig.GitURL, mayorRigPath); err != nil {
+			return fmt.Errorf("cloning for mayor: %w", err)
 		}
 	}
+	bar.Finish()
 
 	// Checkout the default branch for mayor (clone defaults to remote's HEAD, not our configured branch)
 	mayorGit := git.NewGitWithDir("", mayorRigPath)
 	if err := mayorGit.Checkout(defaultBranch); err != nil {
-		return nil, fmt.Errorf("checking out default branch for mayor: %w", err)
+		return fmt.Errorf("checking out default branch for mayor: %w", err)
 	}
 	// Configure push URL on mayor clone (separate clone, doesn't inherit from bare repo)
-	if opts.PushURL != "" {
-		if err := mayorGit.ConfigurePushURL("origin", opts.PushURL); err != nil {
-			return nil, fmt.Errorf("configuring mayor push URL: %w", err)
+	if rigConfig.PushURL != "" {
+		if err := mayorGit.ConfigurePushURL("origin", rigConfig.PushURL); err != nil {
+			return fmt.Errorf("configuring mayor push URL: %w", err)
 		}
 	}
+	if err := ApplyIdentity(rigPath, "mayor", "", mayorRigPath, false); err != nil {
+		return fmt.Errorf("setting mayor git identity: %w", err)
+	}
 	fmt.Printf("   ✓ Created mayor clone\n")
 
+	// NOTE: No per-directory CLAUDE.md/AGENTS.md is created for any agent.
+	// Only ~/gt/CLAUDE.md (town-root identity anchor) exists on disk.
+	// Full context is injected ephemerally by `gt prime` at session start.
+
+	// Create refinery as worktree from bare repo on default branch.
+	// Refinery needs to see polecat branches (shared .repo.git) and merges them.
+	// Being on the default branch allows direct merge workflow.
+	fmt.Printf("  Creating refinery worktree...\n")
+	if err := os.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
+		return fmt.Errorf("creating refinery dir: %w", err)
+	}
+	if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
+		return fmt.Errorf("creating refinery worktree: %w", err)
+	}
+	refineryGit := git.NewGit(refineryRigPath)
+	if err := refineryGit.ConfigureHooksPath(); err != nil {
+		return fmt.Errorf("configuring hooks for refinery: %w", err)
+	}
+	if err := ApplyIdentity(rigPath, "refinery", "", refineryRigPath, true); err != nil {
+		return fmt.Errorf("setting refinery git identity: %w", err)
+	}
+	fmt.Printf("   ✓ Created refinery worktree\n")
+	if warn := lfsWarning(refineryRigPath, checkout); warn != "" {
+		fmt.Printf("  Warning: %s\n", warn)
+	}
+	// Copy overlay files from .runtime/overlay/ to refinery root.
+	// This allows services to have .env and other config files at their root.
+	if err := CopyOverlay(rigPath, refineryRigPath); err != nil {
+		// Non-fatal - log warning but continue
+		fmt.Printf("  Warning: Could not copy overlay files to refinery: %v\n", err)
+	}
+
+	// NOTE: Claude settings are installed by the agent at startup, not here.
+	// Claude Code does NOT traverse parent directories for settings.json.
+	// See: https://github.com/anthropics/claude-code/issues/12962
+
+	// Create empty crew directory with README (crew members added via gt crew add)
+	crewPath := filepath.Join(rigPath, "crew")
+	if err := os.MkdirAll(crewPath, 0755); err != nil {
+		return fmt.Errorf("creating crew dir: %w", err)
+	}
+	// Create README with instructions
+	readmePath := filepath.Join(crewPath, "README.md")
+	readmeContent := `# Crew Directory
+
+This directory contains crew worker workspaces.
+
+## Adding a Crew Member
+
+` + "```bash" + `
+gt crew add <name>    # Creates crew/<name>/ with a git clone
+` + "```" + `
+
+## Crew vs Polecats
+
+- **Crew**: Persistent, user-managed workspaces (never auto-garbage-collected)
+- **Polecats**: Transient, witness-managed workers (cleaned up after work completes)
+
+Use crew for your own workspace. Polecats are for batch work dispatch.
+`
+	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
+		return fmt.Errorf("creating crew README: %w", err)
+	}
+	// Create witness directory (no clone needed)
+	witnessPath := filepath.Join(rigPath, "witness")
+	if err := os.MkdirAll(witnessPath, 0755); err != nil {
+		return fmt.Errorf("creating witness dir: %w", err)
+	}
+	// NOTE: Witness hooks are installed by witness/manager.go:Start() via EnsureSettingsForRole.
+	// No need to create patrol hooks here — agents self-install at startup.
+
+	// Create polecats directory (settings are scaffolded by the hooks stage)
+	polecatsPath := filepath.Join(rigPath, "polecats")
+	if err := os.MkdirAll(polecatsPath, 0755); err != nil {
+		return fmt.Errorf("creating polecats dir: %w", err)
+	}
+	return nil
+}
+
+// provisionBeads creates the rig's beads database, points bd at the Dolt
+// server, and registers the rig's route.
+func (m *Manager) provisionBeads(run *provisionRun) error {
+	rigPath := run.rigPath
+	rigConfig := run.cfg
+	name := rigConfig.Name
+	prefix := rigConfig.Beads.Prefix
+	mayorRigPath := filepath.Join(rigPath, "mayor", "rig")
+
 	// Check if source repo has tracked .beads/ directory.
 	// If so, we need to initialize the database (it doesn't exist after clone since DB files are gitignored).
 	sourceBeadsDir := filepath.Join(mayorRigPath, ".beads")
@@ -457,19 +634,19 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		if sourcePrefix := detectBeadsPrefixFromConfig(sourceBeadsConfig); sourcePrefix != "" {
 			fmt.Printf("  Detected existing beads prefix '%s' from source repo\n", sourcePrefix)
 			// Only error on mismatch if user explicitly provided --prefix
-			if userProvidedPrefix && strings.TrimSuffix(opts.BeadsPrefix, "-") != strings.TrimSuffix(sourcePrefix, "-") {
-				return nil, fmt.Errorf("prefix mismatch: source repo uses '%s' but --prefix '%s' was provided; use --prefix %s to match existing issues", sourcePrefix, opts.BeadsPrefix, sourcePrefix)
+			if run.checklist.Request.PrefixExplicit && strings.TrimSuffix(prefix, "-") != strings.TrimSuffix(sourcePrefix, "-") {
+				return fmt.Errorf("prefix mismatch: source repo uses '%s' but --prefix '%s' was provided; use --prefix %s to match existing issues", sourcePrefix, prefix, sourcePrefix)
 			}
 			// Use detected prefix (overrides derived prefix)
-			opts.BeadsPrefix = sourcePrefix
+			prefix = sourcePrefix
 			rigConfig.Beads.Prefix = sourcePrefix
 			// Re-save rig config with detected prefix
 			if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
-				return nil, fmt.Errorf("updating rig config with detected prefix: %w", err)
+				return fmt.Errorf("updating rig config with detected prefix: %w", err)
 			}
 		} else {
 			// Detection failed (no issues yet) - use derived/provided prefix
-			fmt.Printf("  Using prefix '%s' for tracked beads (no existing issues to detect from)\n", opts.BeadsPrefix)
+			fmt.Printf("  Using prefix '%s' for tracked beads (no existing issues to detect from)\n", prefix)
 		}
 
 		// Initialize bd database if runtime files are missing.
@@ -481,7 +658,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		// to a new workspace), we still need to run bd init to create the server-side
 		// database and set issue_prefix. Always ensure issue_prefix is set afterward.
 		if !bdDatabaseExists(sourceBeadsDir) {
-			cmd := exec.Command("bd", "init", "--prefix", opts.BeadsPrefix, "--server") // opts.BeadsPrefix validated earlier
+			cmd := exec.Command("bd", "init", "--prefix", prefix, "--server") // prefix validated earlier
 			cmd.Dir = mayorRigPath
 			if output, err := cmd.CombinedOutput(); err != nil {
 				fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
@@ -496,40 +673,36 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		configCmd.Dir = mayorRigPath
 		_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
 
-		prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", opts.BeadsPrefix)
+		prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix)
 		prefixSetCmd.Dir = mayorRigPath
 		if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
 			fmt.Printf("  Warning: Could not set issue_prefix: %v (%s)\n", prefixErr, strings.TrimSpace(string(prefixOutput)))
 		}
 	}
 
-	// NOTE: No per-directory CLAUDE.md/AGENTS.md is created for any agent.
-	// Only ~/gt/CLAUDE.md (town-root identity anchor) exists on disk.
-	// Full context is injected ephemerally by `gt prime` at session start.
-
 	// Create server-side database for this rig BEFORE initializing beads.
 	// InitBeads runs bd init --server which writes metadata.json, but the actual
 	// database in .dolt-data/ must exist first for bd config commands to work.
 	if _, err := exec.LookPath("dolt"); err == nil {
-		if _, _, err := doltserver.InitRig(m.townRoot, opts.Name); err != nil {
+		if _, _, err := doltserver.InitRig(m.townRoot, name); err != nil {
 			fmt.Printf("  Warning: Could not create rig database: %v\n", err)
 		}
 	}
 
-	// Initialize beads at rig level BEFORE creating worktrees.
+	// Initialize beads at rig level BEFORE setting up worktree redirects.
 	// This ensures rig/.beads exists so worktree redirects can point to it.
 	fmt.Printf("  Initializing beads database...\n")
-	if err := m.InitBeads(rigPath, opts.BeadsPrefix); err != nil {
-		return nil, fmt.Errorf("initializing beads: %w", err)
+	if err := m.InitBeads(rigPath, prefix); err != nil {
+		return fmt.Errorf("initializing beads: %w", err)
 	}
-	fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", opts.BeadsPrefix)
+	fmt.Printf("   ✓ Initialized beads (prefix: %s)\n", prefix)
 
 	// Ensure metadata.json has dolt_mode=server and dolt_database=<rigName>.
 	// bd init --server sets dolt_mode but not dolt_database. EnsureMetadata
 	// writes both fields so bd connects to the correct centralized database.
 	// This must happen BEFORE setting issue_prefix below, so bd connects to
 	// the correct server-side database (rigName, not beads_<prefix>).
-	if err := doltserver.EnsureMetadata(m.townRoot, opts.Name); err != nil {
+	if err := doltserver.EnsureMetadata(m.townRoot, name); err != nil {
 		// Non-fatal: daemon's EnsureAllMetadata self-heals on next startup,
 		// or user can run gt doctor --fix to repair manually.
 		fmt.Printf("  Warning: Could not set Dolt server metadata: %v\n", err)
@@ -542,7 +715,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Now that EnsureMetadata has corrected dolt_database, re-set it.
 	{
 		resolvedBeadsDir := beads.ResolveBeadsDir(rigPath)
-		prefixCmd := exec.Command("bd", "config", "set", "issue_prefix", opts.BeadsPrefix)
+		prefixCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix)
 		prefixCmd.Dir = rigPath
 		prefixCmd.Env = append(os.Environ(), "BEADS_DIR="+resolvedBeadsDir)
 		if out, err := prefixCmd.CombinedOutput(); err != nil {
@@ -559,7 +732,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Non-fatal: sync will work without a remote; user can add one manually later.
 	if token := doltserver.DoltHubToken(); token != "" {
 		if org := doltserver.DoltHubOrg(); org != "" {
-			dbName := "beads_" + opts.Name
+			dbName := "beads_" + name
 			dbDir := doltserver.RigDatabaseDir(m.townRoot, dbName)
 			fmt.Printf("  Setting up DoltHub remote for %s/%s...\n", org, doltserver.DoltHubRepoName(dbName))
 			if err := doltserver.SetupDoltHubRemote(dbDir, org, dbName, token); err != nil {
@@ -581,79 +754,42 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		fmt.Printf("  Warning: Could not provision PRIME.md: %v\n", err)
 	}
 
-	// Create refinery as worktree from bare repo on default branch.
-	// Refinery needs to see polecat branches (shared .repo.git) and merges them.
-	// Being on the default branch allows direct merge workflow.
-	fmt.Printf("  Creating refinery worktree...\n")
-	refineryRigPath := filepath.Join(rigPath, "refinery", "rig")
-	if err := os.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
-		return nil, fmt.Errorf("creating refinery dir: %w", err)
-	}
-	if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
-		return nil, fmt.Errorf("creating refinery worktree: %w", err)
-	}
-	refineryGit := git.NewGit(refineryRigPath)
-	if err := refineryGit.ConfigureHooksPath(); err != nil {
-		return nil, fmt.Errorf("configuring hooks for refinery: %w", err)
-	}
-	fmt.Printf("   ✓ Created refinery worktree\n")
 	// Set up beads redirect for refinery (points to rig-level .beads)
-	if err := beads.SetupRedirect(m.townRoot, refineryRigPath); err != nil {
-		fmt.Printf("  Warning: Could not set up refinery beads redirect: %v\n", err)
-	}
-	// Copy overlay files from .runtime/overlay/ to refinery root.
-	// This allows services to have .env and other config files at their root.
-	if err := CopyOverlay(rigPath, refineryRigPath); err != nil {
-		// Non-fatal - log warning but continue
-		fmt.Printf("  Warning: Could not copy overlay files to refinery: %v\n", err)
-	}
-
-	// NOTE: Claude settings are installed by the agent at startup, not here.
-	// Claude Code does NOT traverse parent directories for settings.json.
-	// See: https://github.com/anthropics/claude-code/issues/12962
-
-	// Create empty crew directory with README (crew members added via gt crew add)
-	crewPath := filepath.Join(rigPath, "crew")
-	if err := os.MkdirAll(crewPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating crew dir: %w", err)
+	refineryRigPath := filepath.Join(rigPath, "refinery", "rig")
+	if _, err := os.Stat(refineryRigPath); err == nil {
+		if err := beads.SetupRedirect(m.townRoot, refineryRigPath); err != nil {
+			fmt.Printf("  Warning: Could not set up refinery beads redirect: %v\n", err)
+		}
 	}
-	// Create README with instructions
-	readmePath := filepath.Join(crewPath, "README.md")
-	readmeContent := `# Crew Directory
-
-This directory contains crew worker workspaces.
-
-## Adding a Crew Member
 
-` + "```bash" + `
-gt crew add <name>    # Creates crew/<name>/ with a git clone
-` + "```" + `
-
-## Crew vs Polecats
-
-- **Crew**: Persistent, user-managed workspaces (never auto-garbage-collected)
-- **Polecats**: Transient, witness-managed workers (cleaned up after work completes)
-
-Use crew for your own workspace. Polecats are for batch work dispatch.
-`
-	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
-		return nil, fmt.Errorf("creating crew README: %w", err)
-	}
-	// Create witness directory (no clone needed)
-	witnessPath := filepath.Join(rigPath, "witness")
-	if err := os.MkdirAll(witnessPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating witness dir: %w", err)
+	// Register route in town-level routes.jsonl BEFORE creating agent beads.
+	// initAgentBeads calls ResolveRoutingTarget which needs the route to exist.
+	// Without this, agent bead creation logs "no route found" warnings (#1424).
+	if prefix != "" {
+		routePath := name
+		mayorRigBeads := filepath.Join(rigPath, "mayor", "rig", ".beads")
+		if _, err := os.Stat(mayorRigBeads); err == nil {
+			routePath = name + "/mayor/rig"
+		}
+		route := beads.Route{
+			Prefix: prefix + "-",
+			Path:   routePath,
+		}
+		if err := beads.AppendRoute(m.townRoot, route); err != nil {
+			fmt.Printf("  Warning: Could not update routes.jsonl: %v\n", err)
+		}
 	}
-	// NOTE: Witness hooks are installed by witness/manager.go:Start() via EnsureSettingsForRole.
-	// No need to create patrol hooks here — agents self-install at startup.
+	return nil
+}
 
-	// Create polecats directory with .claude/ settings scaffold.
+// provisionHooks scaffolds the polecat settings and commands.
+func (m *Manager) provisionHooks(run *provisionRun) error {
 	// Settings are passed to Claude Code via --settings flag at session start.
 	// Scaffolding them here ensures the settings file exists before the first
 	// polecat session starts, preventing startup failures from missing hooks.
-	polecatsPath := filepath.Join(rigPath, "polecats")
+	polecatsPath := filepath.Join(run.rigPath, "polecats")
 	if err := os.MkdirAll(polecatsPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating polecats dir: %w", err)
+		return fmt.Errorf("creating polecats dir: %w", err)
 	}
 	if err := claude.EnsureSettingsForRole(polecatsPath, "polecat"); err != nil {
 		// Non-fatal: session startup will retry via EnsureSettingsForRole
@@ -663,34 +799,17 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 		// Non-fatal: commands are convenience, not critical
 		fmt.Printf("  %s Could not scaffold polecat commands: %v\n", "!", err)
 	}
+	return nil
+}
 
-	// Register route in town-level routes.jsonl BEFORE creating agent beads.
-	// initAgentBeads calls ResolveRoutingTarget which needs the route to exist.
-	// Without this, agent bead creation logs "no route found" warnings (#1424).
-	if opts.BeadsPrefix != "" {
-		routePath := opts.Name
-		mayorRigBeads := filepath.Join(rigPath, "mayor", "rig", ".beads")
-		if _, err := os.Stat(mayorRigBeads); err == nil {
-			routePath = opts.Name + "/mayor/rig"
-		}
-		route := beads.Route{
-			Prefix: opts.BeadsPrefix + "-",
-			Path:   routePath,
-		}
-		if err := beads.AppendRoute(m.townRoot, route); err != nil {
-			fmt.Printf("  Warning: Could not update routes.jsonl: %v\n", err)
-		}
-	}
-
-	// Create rig-level settings directory (used by gt config for rig overrides)
-	rigSettingsPath := filepath.Join(rigPath, constants.DirSettings)
-	if err := os.MkdirAll(rigSettingsPath, 0755); err != nil {
-		return nil, fmt.Errorf("creating settings dir: %w", err)
-	}
+// provisionPatrols creates the rig's agent beads, seeds its patrol
+// molecules, and creates its plugin directories.
+func (m *Manager) provisionPatrols(run *provisionRun) error {
+	rigPath := run.rigPath
 
 	// Create rig-level agent beads (witness, refinery) in rig beads.
 	// Town-level agents (mayor, deacon) are created by gt install in town beads.
-	if err := m.initAgentBeads(rigPath, opts.Name, opts.BeadsPrefix); err != nil {
+	if err := m.initAgentBeads(rigPath, run.cfg.Name, run.cfg.Beads.Prefix); err != nil {
 		// Non-fatal: log warning but continue
 		fmt.Fprintf(os.Stderr, "  Warning: Could not create agent beads: %v\n", err)
 	}
@@ -706,24 +825,16 @@ Use crew for your own workspace. Polecats are for batch work dispatch.
 		// Non-fatal: log warning but continue
 		fmt.Fprintf(os.Stderr, "  Warning: Could not create plugin directories: %v\n", err)
 	}
-
-	// Register in town config
-	m.config.Rigs[opts.Name] = config.RigEntry{
-		GitURL:    opts.GitURL,
-		PushURL:   opts.PushURL,
-		LocalRepo: localRepo,
-		AddedAt:   time.Now(),
-		BeadsConfig: &config.BeadsConfig{
-			Prefix: opts.BeadsPrefix,
-		},
-	}
-
-	success = true
-	return m.loadRig(opts.Name, m.config.Rigs[opts.Name])
+	return nil
 }
 
 // saveRigConfig writes the rig configuration to config.json.
 func (m *Manager) saveRigConfig(rigPath string, cfg *RigConfig) error {
+	return SaveRigConfig(rigPath, cfg)
+}
+
+// SaveRigConfig writes the rig configuration to config.json.
+func SaveRigConfig(rigPath string, cfg *RigConfig) error {
 	configPath := filepath.Join(rigPath, "config.json")
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
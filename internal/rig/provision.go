@@ -0,0 +1,278 @@
+package rig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Provisioning stages, in the order gt rig add runs them.
+const (
+	StageConfig  = "config"  // rig container, config.json, settings dir
+	StageClone   = "clone"   // bare repo, mayor clone, refinery worktree, agent dirs
+	StageBeads   = "beads"   // beads database, Dolt metadata, route, PRIME.md
+	StageHooks   = "hooks"   // polecat settings and commands
+	StagePatrols = "patrols" // agent beads, patrol molecules, plugin dirs
+)
+
+// ProvisionStages lists every provisioning stage in run order.
+var ProvisionStages = []string{StageConfig, StageClone, StageBeads, StageHooks, StagePatrols}
+
+// AgentStages are the stages gt rig add --no-agents skips.
+var AgentStages = []string{StageHooks, StagePatrols}
+
+// ProvisionFile is the rig-relative path of the provisioning checklist.
+const ProvisionFile = ".runtime/provision.json"
+
+// Stage states recorded in the checklist.
+const (
+	StagePending = "pending"
+	StageDone    = "done"
+	StageSkipped = "skipped"
+	StageFailed  = "failed"
+)
+
+// StageRecord is one stage's entry in the provisioning checklist.
+type StageRecord struct {
+	Name   string    `json:"name"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ProvisionRequest holds the gt rig add options that aren't in config.json
+// but are needed to resume provisioning.
+type ProvisionRequest struct {
+	PrefixExplicit bool   `json:"prefix_explicit,omitempty"` // --prefix was given
+	DefaultBranch  string `json:"default_branch,omitempty"`  // --branch, before the clone stage
+}
+
+// ProvisionChecklist records which provisioning stages a rig has completed.
+type ProvisionChecklist struct {
+	Stages  []StageRecord    `json:"stages"`
+	Request ProvisionRequest `json:"request"`
+}
+
+func newProvisionChecklist(stages []string, req ProvisionRequest) *ProvisionChecklist {
+	c := &ProvisionChecklist{Request: req}
+	for _, name := range ProvisionStages {
+		status := StageSkipped
+		if slices.Contains(stages, name) {
+			status = StagePending
+		}
+		c.Stages = append(c.Stages, StageRecord{Name: name, Status: status})
+	}
+	return c
+}
+
+// Status returns the recorded status of stage, or "" if it isn't listed.
+func (c *ProvisionChecklist) Status(stage string) string {
+	for _, s := range c.Stages {
+		if s.Name == stage {
+			return s.Status
+		}
+	}
+	return ""
+}
+
+// Done reports whether stage completed.
+func (c *ProvisionChecklist) Done(stage string) bool {
+	return c.Status(stage) == StageDone
+}
+
+// Remaining returns the stages that are pending or failed, in run order.
+func (c *ProvisionChecklist) Remaining() []string {
+	var names []string
+	for _, s := range c.Stages {
+		if s.Status == StagePending || s.Status == StageFailed {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+func (c *ProvisionChecklist) record(stage string, err error) {
+	for i := range c.Stages {
+		if c.Stages[i].Name != stage {
+			continue
+		}
+		c.Stages[i].At = time.Now().UTC()
+		c.Stages[i].Status, c.Stages[i].Error = StageDone, ""
+		if err != nil {
+			c.Stages[i].Status, c.Stages[i].Error = StageFailed, err.Error()
+		}
+		return
+	}
+}
+
+// LoadProvisionChecklist reads a rig's provisioning checklist.
+func LoadProvisionChecklist(rigPath string) (*ProvisionChecklist, error) {
+	data, err := os.ReadFile(filepath.Join(rigPath, ProvisionFile))
+	if err != nil {
+		return nil, err
+	}
+	var c ProvisionChecklist
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ProvisionFile, err)
+	}
+	return &c, nil
+}
+
+func saveProvisionChecklist(rigPath string, c *ProvisionChecklist) error {
+	path := filepath.Join(rigPath, ProvisionFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, c)
+}
+
+// SelectStages turns --only and --skip lists into the stages to run, in
+// run order. With neither, every stage is selected.
+func SelectStages(only, skip []string) ([]string, error) {
+	if len(only) > 0 && len(skip) > 0 {
+		return nil, fmt.Errorf("--only and --skip cannot be combined")
+	}
+	for _, name := range append(slices.Clone(only), skip...) {
+		if !slices.Contains(ProvisionStages, name) {
+			return nil, fmt.Errorf("unknown stage %q (stages: %s)", name, strings.Join(ProvisionStages, ", "))
+		}
+	}
+	var stages []string
+	for _, name := range ProvisionStages {
+		if (len(only) > 0 && !slices.Contains(only, name)) || slices.Contains(skip, name) {
+			continue
+		}
+		stages = append(stages, name)
+	}
+	return stages, nil
+}
+
+// provisionRun is the state threaded through one run of provisioning stages.
+type provisionRun struct {
+	opts      AddRigOptions
+	rigPath   string
+	cfg       *RigConfig // nil until the config stage has run
+	checklist *ProvisionChecklist
+}
+
+func (m *Manager) runStage(run *provisionRun, stage string) error {
+	switch stage {
+	case StageConfig:
+		return m.provisionConfig(run)
+	case StageClone:
+		return m.provisionClone(run)
+	case StageBeads:
+		return m.provisionBeads(run)
+	case StageHooks:
+		return m.provisionHooks(run)
+	case StagePatrols:
+		return m.provisionPatrols(run)
+	}
+	return fmt.Errorf("unknown stage %q", stage)
+}
+
+// runStages runs the selected stages in order, saving the checklist after
+// each one, and stops at the first failure.
+func (m *Manager) runStages(run *provisionRun, stages []string) error {
+	for _, stage := range ProvisionStages {
+		if !slices.Contains(stages, stage) {
+			continue
+		}
+		err := m.runStage(run, stage)
+		if err == nil && run.opts.AfterStage != nil {
+			err = run.opts.AfterStage(stage)
+		}
+		run.checklist.record(stage, err)
+		if saveErr := saveProvisionChecklist(run.rigPath, run.checklist); saveErr != nil && err == nil {
+			err = fmt.Errorf("saving provisioning checklist: %w", saveErr)
+		}
+		if err != nil {
+			return fmt.Errorf("%s stage: %w", stage, err)
+		}
+	}
+	return nil
+}
+
+// registerProvisioned adds the rig to the town config once no stage is
+// left pending or failed, and returns nil until then. Rigs that are already
+// registered keep their entry.
+func (m *Manager) registerProvisioned(run *provisionRun) (*Rig, error) {
+	name := run.cfg.Name
+	if len(run.checklist.Remaining()) > 0 {
+		return nil, nil
+	}
+	entry, ok := m.config.Rigs[name]
+	if !ok {
+		entry = config.RigEntry{
+			GitURL:    run.cfg.GitURL,
+			PushURL:   run.cfg.PushURL,
+			LocalRepo: run.cfg.LocalRepo,
+			AddedAt:   time.Now(),
+			BeadsConfig: &config.BeadsConfig{
+				Prefix: run.cfg.Beads.Prefix,
+			},
+		}
+		m.config.Rigs[name] = entry
+	}
+	return m.loadRig(name, entry)
+}
+
+// Provision resumes provisioning a rig that gt rig add left part way. With
+// stages nil it runs every stage still pending or failed; otherwise it runs
+// exactly the given stages, which may include ones skipped or already done.
+// The config stage can't be rerun. The rig is registered in the town config
+// and returned once nothing is left pending or failed; until then Provision
+// returns a nil rig.
+func (m *Manager) Provision(name string, stages []string, afterStage func(stage string) error) (*Rig, error) {
+	rigPath := filepath.Join(m.townRoot, name)
+	checklist, err := LoadProvisionChecklist(rigPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if !m.RigExists(name) {
+			return nil, ErrRigNotFound
+		}
+		// Rigs added before staged provisioning completed every stage.
+		checklist = newProvisionChecklist(ProvisionStages, ProvisionRequest{})
+		for _, stage := range ProvisionStages {
+			checklist.record(stage, nil)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("reading provisioning checklist: %w", err)
+	}
+
+	if stages == nil {
+		stages = checklist.Remaining()
+	}
+	if slices.Contains(stages, StageConfig) {
+		if !checklist.Done(StageConfig) {
+			return nil, fmt.Errorf("rig %s has no config.json; remove %s and run gt rig add again", name, rigPath)
+		}
+		return nil, fmt.Errorf("the %s stage can't be rerun; edit %s/config.json instead", StageConfig, rigPath)
+	}
+
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading rig config: %w", err)
+	}
+	if cfg.Beads == nil {
+		cfg.Beads = &BeadsConfig{Prefix: deriveBeadsPrefix(name)}
+	}
+
+	run := &provisionRun{
+		opts:      AddRigOptions{Name: name, AfterStage: afterStage},
+		rigPath:   rigPath,
+		cfg:       cfg,
+		checklist: checklist,
+	}
+	if err := m.runStages(run, stages); err != nil {
+		return nil, err
+	}
+	return m.registerProvisioned(run)
+}
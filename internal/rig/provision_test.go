@@ -0,0 +1,128 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestSelectStages(t *testing.T) {
+	tests := []struct {
+		only, skip []string
+		want       []string
+		wantErr    bool
+	}{
+		{nil, nil, ProvisionStages, false},
+		{[]string{StagePatrols, StageConfig}, nil, []string{StageConfig, StagePatrols}, false},
+		{nil, AgentStages, []string{StageConfig, StageClone, StageBeads}, false},
+		{[]string{StageClone}, []string{StageBeads}, nil, true},
+		{[]string{"agents"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := SelectStages(tt.only, tt.skip)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SelectStages(%q, %q) = %q, %v; want %q, error %v", tt.only, tt.skip, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAddRig_KeepsFailedRigForResume(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+
+	_, err := manager.AddRig(AddRigOptions{
+		Name:   "broken",
+		GitURL: filepath.Join(root, "no-such-repo"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "clone stage") {
+		t.Fatalf("AddRig error = %v, want a clone stage failure", err)
+	}
+	if manager.RigExists("broken") {
+		t.Error("failed rig was registered")
+	}
+
+	rigPath := filepath.Join(root, "broken")
+	checklist, err := LoadProvisionChecklist(rigPath)
+	if err != nil {
+		t.Fatalf("checklist not kept: %v", err)
+	}
+	if !checklist.Done(StageConfig) || checklist.Status(StageClone) != StageFailed {
+		t.Errorf("checklist = %+v, want config done and clone failed", checklist.Stages)
+	}
+	if got := checklist.Remaining(); !reflect.DeepEqual(got, []string{StageClone, StageBeads, StageHooks, StagePatrols}) {
+		t.Errorf("Remaining = %q", got)
+	}
+
+	_, err = manager.AddRig(AddRigOptions{Name: "broken", GitURL: "git@github.com:test/test.git"})
+	if err == nil || !strings.Contains(err.Error(), "gt rig provision broken") {
+		t.Errorf("second AddRig error = %v, want a hint to resume", err)
+	}
+}
+
+func TestAddRig_ConfigStageRequired(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+
+	if _, err := manager.AddRig(AddRigOptions{Name: "norig", GitURL: "git@github.com:test/test.git", Stages: []string{StageClone}}); err == nil {
+		t.Fatal("AddRig without the config stage succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(root, "norig")); !os.IsNotExist(err) {
+		t.Error("rig directory created without the config stage")
+	}
+}
+
+func TestProvision_ResumesRemainingStages(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+
+	rigPath := filepath.Join(root, "resume")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveRigConfig(rigPath, &RigConfig{Type: "rig", Name: "resume", GitURL: "git@github.com:test/test.git", Beads: &BeadsConfig{Prefix: "rs"}}); err != nil {
+		t.Fatal(err)
+	}
+	checklist := newProvisionChecklist([]string{StageConfig, StageClone, StageBeads, StageHooks}, ProvisionRequest{})
+	for _, stage := range []string{StageConfig, StageClone, StageBeads} {
+		checklist.record(stage, nil)
+	}
+	checklist.record(StageHooks, os.ErrPermission)
+	if err := saveProvisionChecklist(rigPath, checklist); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.Provision("resume", []string{StageConfig}, nil); err == nil {
+		t.Error("Provision reran the config stage")
+	}
+
+	var ran []string
+	r, err := manager.Provision("resume", nil, func(stage string) error {
+		ran = append(ran, stage)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if !reflect.DeepEqual(ran, []string{StageHooks}) {
+		t.Errorf("ran = %q, want only hooks", ran)
+	}
+	if r == nil || !manager.RigExists("resume") || rigsConfig.Rigs["resume"].BeadsConfig.Prefix != "rs" {
+		t.Fatalf("rig not registered once provisioned: %+v", rigsConfig.Rigs)
+	}
+
+	checklist, err = LoadProvisionChecklist(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !checklist.Done(StageHooks) || checklist.Status(StagePatrols) != StageSkipped {
+		t.Errorf("checklist = %+v, want hooks done and patrols still skipped", checklist.Stages)
+	}
+
+	if _, err := manager.Provision("missing", nil, nil); err != ErrRigNotFound {
+		t.Errorf("Provision(missing) = %v, want ErrRigNotFound", err)
+	}
+}
@@ -0,0 +1,80 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// RecreateClones rebuilds a registered rig's repository clones from its git
+// URL: the shared .repo.git, the mayor clone, and the refinery worktree. The
+// rig directory itself (config.json, .beads, agent directories) must already
+// exist, e.g. restored from a town backup. Clones that already exist are
+// left alone. Crew clones are recreated separately with crew.Manager.Add.
+func (m *Manager) RecreateClones(r *Rig) error {
+	if r.GitURL == "" {
+		return fmt.Errorf("rig %s has no git URL", r.Name)
+	}
+	defaultBranch := r.DefaultBranch()
+	checkout := CheckoutOptions(r.Path)
+	m.git.SetCheckoutOptions(&checkout)
+	defer m.git.SetCheckoutOptions(nil)
+
+	bareRepoPath := filepath.Join(r.Path, ".repo.git")
+	if _, err := os.Stat(bareRepoPath); os.IsNotExist(err) {
+		if err := m.git.CloneBare(r.GitURL, bareRepoPath); err != nil {
+			return wrapCloneError(err, r.GitURL)
+		}
+	}
+	bareGit := git.NewGitWithDir(bareRepoPath, "")
+	if r.PushURL != "" {
+		if err := bareGit.ConfigurePushURL("origin", r.PushURL); err != nil {
+			return fmt.Errorf("configuring push URL: %w", err)
+		}
+	}
+
+	mayorRigPath := filepath.Join(r.Path, "mayor", "rig")
+	if _, err := os.Stat(mayorRigPath); os.IsNotExist(err) {
+		if err := m.git.Clone(r.GitURL, mayorRigPath); err != nil {
+			return fmt.Errorf("cloning for mayor: %w", err)
+		}
+		mayorGit := git.NewGitWithDir("", mayorRigPath)
+		if err := mayorGit.Checkout(defaultBranch); err != nil {
+			return fmt.Errorf("checking out default branch for mayor: %w", err)
+		}
+		if r.PushURL != "" {
+			if err := mayorGit.ConfigurePushURL("origin", r.PushURL); err != nil {
+				return fmt.Errorf("configuring mayor push URL: %w", err)
+			}
+		}
+		if err := ApplyIdentity(r.Path, "mayor", "", mayorRigPath, false); err != nil {
+			return fmt.Errorf("setting mayor git identity: %w", err)
+		}
+	}
+
+	refineryRigPath := filepath.Join(r.Path, "refinery", "rig")
+	if _, err := os.Stat(refineryRigPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(refineryRigPath), 0755); err != nil {
+			return fmt.Errorf("creating refinery dir: %w", err)
+		}
+		if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
+			return fmt.Errorf("creating refinery worktree: %w", err)
+		}
+		if err := git.NewGit(refineryRigPath).ConfigureHooksPath(); err != nil {
+			return fmt.Errorf("configuring hooks for refinery: %w", err)
+		}
+		if err := ApplyIdentity(r.Path, "refinery", "", refineryRigPath, true); err != nil {
+			return fmt.Errorf("setting refinery git identity: %w", err)
+		}
+		if err := beads.SetupRedirect(m.townRoot, refineryRigPath); err != nil {
+			fmt.Printf("  Warning: Could not set up refinery beads redirect: %v\n", err)
+		}
+		if err := CopyOverlay(r.Path, refineryRigPath); err != nil {
+			fmt.Printf("  Warning: Could not copy overlay files to refinery: %v\n", err)
+		}
+	}
+	return nil
+}
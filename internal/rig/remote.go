@@ -0,0 +1,45 @@
+package rig
+
+import (
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// IsRemote reports whether the rig runs on another machine.
+func (r *Rig) IsRemote() bool {
+	return r != nil && r.Remote != nil
+}
+
+// Tmux returns a tmux wrapper for the machine that hosts the rig's sessions.
+func (r *Rig) Tmux() *tmux.Tmux {
+	if r.IsRemote() {
+		return tmux.NewRemoteTmux(r.Remote.SSHArgs())
+	}
+	return tmux.NewTmux()
+}
+
+// Git returns a git wrapper for dir on the machine that holds the rig's
+// clones. Remote rigs use the same absolute paths as the town.
+func (r *Rig) Git(dir string) *git.Git {
+	return r.HostGit(git.NewGit(dir))
+}
+
+// HostGit retargets g at the machine that holds the rig's clones.
+func (r *Rig) HostGit(g *git.Git) *git.Git {
+	if r.IsRemote() {
+		return g.WithRemote(r.Remote.SSHArgs())
+	}
+	return g
+}
+
+// AgentCommand prepends the environment a remote agent needs to reach the
+// town's Dolt server to an agent startup command. Local rigs get command
+// unchanged.
+func (r *Rig) AgentCommand(townRoot, command string) string {
+	if !r.IsRemote() {
+		return command
+	}
+	return config.PrependEnv(command, r.Remote.AgentEnv(doltserver.DefaultConfig(townRoot).Port))
+}
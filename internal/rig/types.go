@@ -41,6 +41,10 @@ type Rig struct {
 
 	// HasMayor indicates if the rig has a mayor clone.
 	HasMayor bool `json:"has_mayor"`
+
+	// Remote is set when the rig's sessions and git operations run on
+	// another machine over SSH. Nil for local rigs.
+	Remote *config.RemoteConfig `json:"remote,omitempty"`
 }
 
 // AgentDirs are the standard agent directories in a rig.
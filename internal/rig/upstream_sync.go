@@ -0,0 +1,212 @@
+package rig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Upstream sync strategies for fork rigs.
+const (
+	// UpstreamSyncFFOnly only fast-forwards the fork; a fork with commits of
+	// its own on the default branch is reported as diverged.
+	UpstreamSyncFFOnly = "ff-only"
+
+	// UpstreamSyncRebase rebases the fork's own commits onto upstream and
+	// force-pushes (with lease) when the fork has diverged.
+	UpstreamSyncRebase = "rebase"
+)
+
+// Upstream sync statuses.
+const (
+	UpstreamUpToDate      = "up-to-date"
+	UpstreamAhead         = "ahead"  // fork has commits upstream lacks; nothing to pull
+	UpstreamBehind        = "behind" // dry run: would fast-forward
+	UpstreamFastForwarded = "fast-forwarded"
+	UpstreamRebased       = "rebased"
+	UpstreamDiverged      = "diverged"
+)
+
+// ErrNoPushURL is returned by SyncFromUpstream for rigs without a fork.
+var ErrNoPushURL = errors.New("rig has no push_url (not a fork rig)")
+
+// UpstreamSync is the result of syncing a fork rig's default branch from
+// its upstream. The last result is kept in <rig>/.runtime/upstream-sync.json
+// for gt rig status.
+type UpstreamSync struct {
+	Rig    string `json:"rig"`
+	Branch string `json:"branch"`
+
+	// Upstream and Fork are the branch heads found before syncing.
+	Upstream string `json:"upstream"`
+	Fork     string `json:"fork"`
+
+	// Ahead counts fork commits missing upstream, Behind upstream commits
+	// missing from the fork, both before syncing.
+	Ahead  int `json:"ahead"`
+	Behind int `json:"behind"`
+
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"` // why a diverged fork was left alone
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// upstreamSyncStatePath returns where the last sync result is stored.
+func upstreamSyncStatePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "upstream-sync.json")
+}
+
+// LoadUpstreamSync returns the last recorded sync result for the rig, or
+// nil if it was never synced.
+func LoadUpstreamSync(rigPath string) (*UpstreamSync, error) {
+	data, err := os.ReadFile(upstreamSyncStatePath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s UpstreamSync
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", upstreamSyncStatePath(rigPath), err)
+	}
+	return &s, nil
+}
+
+func saveUpstreamSync(rigPath string, s *UpstreamSync) error {
+	if err := os.MkdirAll(filepath.Join(rigPath, ".runtime"), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(upstreamSyncStatePath(rigPath), data, 0644)
+}
+
+// SyncFromUpstream brings a fork rig's default branch up to date with its
+// upstream. Fork rigs fetch from a read-only upstream (origin) and push to
+// push_url, so without this the fork's default branch only moves when
+// someone syncs it by hand.
+//
+// A fork that is only behind is fast-forwarded. A fork with commits of its
+// own is rebased onto upstream when strategy is UpstreamSyncRebase, and
+// otherwise (or when the rebase conflicts) left alone and reported as
+// diverged. Pushes never discard commits: fast-forwards are plain pushes
+// and rebases are pushed with a lease on the fetched fork head.
+//
+// With dryRun nothing is pushed or recorded.
+func SyncFromUpstream(rigPath, strategy string, dryRun bool) (*UpstreamSync, error) {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading rig config: %w", err)
+	}
+	if cfg.PushURL == "" {
+		return nil, ErrNoPushURL
+	}
+	switch strategy {
+	case "":
+		strategy = UpstreamSyncFFOnly
+	case UpstreamSyncFFOnly, UpstreamSyncRebase:
+	default:
+		return nil, fmt.Errorf("unknown sync strategy %q (want %s or %s)", strategy, UpstreamSyncFFOnly, UpstreamSyncRebase)
+	}
+
+	repo := rigRepoGit(rigPath)
+	if repo == nil {
+		return nil, fmt.Errorf("no git repository found in %s", rigPath)
+	}
+	branch := cfg.DefaultBranch
+	if branch == "" {
+		branch = "main"
+	}
+	upstreamRef := "refs/remotes/origin/" + branch
+	forkRef := "refs/fork/" + branch
+
+	if err := repo.Fetch("origin"); err != nil {
+		return nil, fmt.Errorf("fetching upstream: %w", err)
+	}
+	if err := repo.FetchBranch(cfg.PushURL, "+refs/heads/"+branch+":"+forkRef); err != nil {
+		return nil, fmt.Errorf("fetching %s from fork: %w", branch, err)
+	}
+
+	result := &UpstreamSync{Rig: filepath.Base(rigPath), Branch: branch, CheckedAt: time.Now().UTC()}
+	if result.Upstream, err = repo.Rev(upstreamRef); err != nil {
+		return nil, fmt.Errorf("resolving upstream %s: %w", branch, err)
+	}
+	if result.Fork, err = repo.Rev(forkRef); err != nil {
+		return nil, fmt.Errorf("resolving fork %s: %w", branch, err)
+	}
+	if result.Ahead, err = repo.CommitsAhead(upstreamRef, forkRef); err != nil {
+		return nil, fmt.Errorf("comparing fork with upstream: %w", err)
+	}
+	if result.Behind, err = repo.CommitsAhead(forkRef, upstreamRef); err != nil {
+		return nil, fmt.Errorf("comparing fork with upstream: %w", err)
+	}
+
+	switch {
+	case result.Behind == 0 && result.Ahead == 0:
+		result.Status = UpstreamUpToDate
+	case result.Behind == 0:
+		result.Status = UpstreamAhead
+	case result.Ahead == 0 && dryRun:
+		result.Status = UpstreamBehind
+	case result.Ahead == 0:
+		if err := repo.Push(cfg.PushURL, upstreamRef+":refs/heads/"+branch, false); err != nil {
+			return nil, fmt.Errorf("fast-forwarding fork %s: %w", branch, err)
+		}
+		result.Status = UpstreamFastForwarded
+	case strategy != UpstreamSyncRebase || dryRun:
+		result.Status = UpstreamDiverged
+	default:
+		if err := rebaseFork(repo, cfg.PushURL, result, upstreamRef, forkRef); err != nil {
+			result.Status = UpstreamDiverged
+			result.Error = err.Error()
+		} else {
+			result.Status = UpstreamRebased
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := saveUpstreamSync(rigPath, result); err != nil {
+		return nil, fmt.Errorf("recording sync state: %w", err)
+	}
+	return result, nil
+}
+
+// rebaseFork replays the fork's own commits onto upstream in a scratch
+// worktree and pushes the result, provided the fork hasn't moved since it
+// was fetched.
+func rebaseFork(repo *git.Git, pushURL string, s *UpstreamSync, upstreamRef, forkRef string) error {
+	tmp, err := os.MkdirTemp("", "gt-upstream-sync-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	wtPath := filepath.Join(tmp, s.Rig)
+	if err := repo.WorktreeAddDetached(wtPath, forkRef); err != nil {
+		return fmt.Errorf("creating rebase worktree: %w", err)
+	}
+	defer func() {
+		_ = repo.WorktreeRemove(wtPath, true)
+		_ = repo.WorktreePrune()
+	}()
+
+	wt := git.NewGit(wtPath)
+	if err := wt.Rebase(upstreamRef); err != nil {
+		_ = wt.AbortRebase()
+		return fmt.Errorf("rebase onto upstream %s failed; sync the fork by hand", s.Branch)
+	}
+	if err := wt.PushWithLease(pushURL, "HEAD:refs/heads/"+s.Branch, s.Branch, s.Fork); err != nil {
+		return fmt.Errorf("pushing rebased %s: %w", s.Branch, err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package rig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupForkRig creates a rig fetching from a read-only upstream and pushing
+// to a fork, both on master, with the upstream one commit ahead of the fork.
+func setupForkRig(t *testing.T) (rigPath, upstreamWork, fork string) {
+	t.Helper()
+	// Rebases in SyncFromUpstream commit without the test's -c identity.
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	tmp := t.TempDir()
+	upstream := filepath.Join(tmp, "upstream.git")
+	gitIn(t, tmp, "init", "--bare", upstream)
+	gitIn(t, upstream, "symbolic-ref", "HEAD", "refs/heads/master")
+
+	upstreamWork = filepath.Join(tmp, "upstream-work")
+	gitIn(t, tmp, "clone", upstream, upstreamWork)
+	commitFile(t, upstreamWork, "README.md", "# Test\n")
+	gitIn(t, upstreamWork, "push", "origin", "HEAD:master")
+
+	fork = filepath.Join(tmp, "fork.git")
+	gitIn(t, tmp, "clone", "--bare", upstream, fork)
+
+	rigPath = filepath.Join(tmp, "myrig")
+	bare := filepath.Join(rigPath, ".repo.git")
+	gitIn(t, tmp, "clone", "--bare", upstream, bare)
+	gitIn(t, bare, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	gitIn(t, bare, "remote", "set-url", "--push", "origin", fork)
+	if err := SaveRigConfig(rigPath, &RigConfig{Type: "rig", Version: 1, Name: "myrig", DefaultBranch: "master", PushURL: fork}); err != nil {
+		t.Fatal(err)
+	}
+
+	commitFile(t, upstreamWork, "upstream.txt", "new upstream work\n")
+	gitIn(t, upstreamWork, "push", "origin", "HEAD:master")
+	return rigPath, upstreamWork, fork
+}
+
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "add", name)
+	gitIn(t, dir, "commit", "-m", "update "+name)
+}
+
+// commitToFork pushes a commit of the fork's own to its master.
+func commitToFork(t *testing.T, fork, name, content string) {
+	t.Helper()
+	work := filepath.Join(t.TempDir(), "fork-work")
+	gitIn(t, filepath.Dir(work), "clone", fork, work)
+	commitFile(t, work, name, content)
+	gitIn(t, work, "push", "origin", "HEAD:master")
+}
+
+func TestSyncFromUpstream_FastForward(t *testing.T) {
+	rigPath, upstreamWork, fork := setupForkRig(t)
+	want := gitIn(t, upstreamWork, "rev-parse", "HEAD")
+
+	dry, err := SyncFromUpstream(rigPath, UpstreamSyncFFOnly, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Status != UpstreamBehind || dry.Behind != 1 || dry.Ahead != 0 {
+		t.Errorf("dry run = %+v, want behind by 1", dry)
+	}
+	if got := gitIn(t, fork, "rev-parse", "master"); got == want {
+		t.Error("dry run pushed to the fork")
+	}
+
+	result, err := SyncFromUpstream(rigPath, UpstreamSyncFFOnly, false)
+	if err != nil {
+		t.Fatalf("SyncFromUpstream: %v", err)
+	}
+	if result.Status != UpstreamFastForwarded {
+		t.Errorf("status = %q, want %q", result.Status, UpstreamFastForwarded)
+	}
+	if got := gitIn(t, fork, "rev-parse", "master"); got != want {
+		t.Errorf("fork master = %s, want upstream %s", got, want)
+	}
+
+	saved, err := LoadUpstreamSync(rigPath)
+	if err != nil || saved == nil || saved.Status != UpstreamFastForwarded {
+		t.Errorf("LoadUpstreamSync = %+v, %v", saved, err)
+	}
+
+	again, err := SyncFromUpstream(rigPath, UpstreamSyncFFOnly, false)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if again.Status != UpstreamUpToDate {
+		t.Errorf("second sync status = %q, want %q", again.Status, UpstreamUpToDate)
+	}
+}
+
+func TestSyncFromUpstream_DivergedFFOnly(t *testing.T) {
+	rigPath, _, fork := setupForkRig(t)
+	commitToFork(t, fork, "fork.txt", "fork-only work\n")
+	before := gitIn(t, fork, "rev-parse", "master")
+
+	result, err := SyncFromUpstream(rigPath, UpstreamSyncFFOnly, false)
+	if err != nil {
+		t.Fatalf("SyncFromUpstream: %v", err)
+	}
+	if result.Status != UpstreamDiverged || result.Ahead != 1 || result.Behind != 1 {
+		t.Errorf("result = %+v, want diverged 1 ahead, 1 behind", result)
+	}
+	if got := gitIn(t, fork, "rev-parse", "master"); got != before {
+		t.Error("ff-only sync changed a diverged fork")
+	}
+}
+
+func TestSyncFromUpstream_Rebase(t *testing.T) {
+	rigPath, upstreamWork, fork := setupForkRig(t)
+	commitToFork(t, fork, "fork.txt", "fork-only work\n")
+
+	result, err := SyncFromUpstream(rigPath, UpstreamSyncRebase, false)
+	if err != nil {
+		t.Fatalf("SyncFromUpstream: %v", err)
+	}
+	if result.Status != UpstreamRebased {
+		t.Fatalf("result = %+v, want rebased", result)
+	}
+	upstreamHead := gitIn(t, upstreamWork, "rev-parse", "HEAD")
+	if parent := gitIn(t, fork, "rev-parse", "master~1"); parent != upstreamHead {
+		t.Errorf("fork master~1 = %s, want upstream head %s", parent, upstreamHead)
+	}
+	if files := gitIn(t, fork, "ls-tree", "--name-only", "master"); files != "README.md\nfork.txt\nupstream.txt" {
+		t.Errorf("fork master files = %q", files)
+	}
+}
+
+func TestSyncFromUpstream_RebaseConflict(t *testing.T) {
+	rigPath, _, fork := setupForkRig(t)
+	commitToFork(t, fork, "upstream.txt", "conflicting fork work\n")
+	before := gitIn(t, fork, "rev-parse", "master")
+
+	result, err := SyncFromUpstream(rigPath, UpstreamSyncRebase, false)
+	if err != nil {
+		t.Fatalf("SyncFromUpstream: %v", err)
+	}
+	if result.Status != UpstreamDiverged || result.Error == "" {
+		t.Errorf("result = %+v, want diverged with an error", result)
+	}
+	if got := gitIn(t, fork, "rev-parse", "master"); got != before {
+		t.Error("conflicting rebase changed the fork")
+	}
+	if wts := gitIn(t, filepath.Join(rigPath, ".repo.git"), "worktree", "list"); strings.Contains(wts, "\n") {
+		t.Errorf("worktrees left behind:\n%s", wts)
+	}
+}
+
+func TestSyncFromUpstream_NoPushURL(t *testing.T) {
+	rigPath := t.TempDir()
+	if err := SaveRigConfig(rigPath, &RigConfig{Type: "rig", Version: 1, Name: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncFromUpstream(rigPath, "", false); !errors.Is(err, ErrNoPushURL) {
+		t.Errorf("err = %v, want ErrNoPushURL", err)
+	}
+}
+
+func TestLoadUpstreamSync_NeverSynced(t *testing.T) {
+	s, err := LoadUpstreamSync(t.TempDir())
+	if s != nil || err != nil {
+		t.Errorf("LoadUpstreamSync = %+v, %v; want nil, nil", s, err)
+	}
+}
@@ -0,0 +1,303 @@
+// Package rigstats samples per-rig activity into a Dolt table so history
+// survives beyond what the beads and events logs can answer cheaply.
+//
+// The daemon's rig_stats patrol calls Collect and Record on an interval;
+// gt rig stats reads the samples back with Query.
+//
+// The opt-in table_stats patrol feeds processlist samples to a
+// TableSampler and records per-table load with RecordTableStats, which
+// gt dolt table-stats reads back with QueryTableStats.
+package rigstats
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Database is the Dolt database holding the stats table. Town beads (hq)
+// always exist and aren't tied to any one rig.
+const Database = "hq"
+
+// Table is the stats table name.
+const Table = "gt_rig_stats"
+
+// schema creates the stats table. One row per rig per sample.
+const schema = `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+	rig VARCHAR(128) NOT NULL,
+	sampled_at DATETIME NOT NULL,
+	window_seconds INT NOT NULL,
+	commits_landed INT NOT NULL,
+	beads_closed INT NOT NULL,
+	polecat_hours DOUBLE NOT NULL,
+	queue_depth INT NOT NULL,
+	queue_wait_hours DOUBLE NOT NULL,
+	PRIMARY KEY (rig, sampled_at)
+)`
+
+// sqlTimeLayout is how sample times are stored (UTC).
+const sqlTimeLayout = "2006-01-02 15:04:05"
+
+// Sample is one rig's activity over a sampling window ending at SampledAt.
+type Sample struct {
+	Rig       string    `json:"rig"`
+	SampledAt time.Time `json:"sampled_at"`
+	Window    int       `json:"window_seconds"`
+
+	// CommitsLanded is the number of merges the refinery landed.
+	CommitsLanded int `json:"commits_landed"`
+
+	// BeadsClosed is the number of work items closed.
+	BeadsClosed int `json:"beads_closed"`
+
+	// PolecatHours is in-progress polecats at sample time × window length.
+	PolecatHours float64 `json:"polecat_hours"`
+
+	// QueueDepth is the number of open, unassigned work items at sample time.
+	QueueDepth int `json:"queue_depth"`
+
+	// QueueWaitHours is the mean age of those queued items.
+	QueueWaitHours float64 `json:"queue_wait_hours"`
+}
+
+// Collect samples each rig's activity over the window ending now. A rig
+// whose database can't be read is reported in the returned error; the
+// other rigs' samples are still returned.
+func Collect(ctx context.Context, townRoot string, rigs []string, window time.Duration, now time.Time) ([]Sample, error) {
+	merges, err := countMerges(townRoot, now.Add(-window), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []Sample
+	var errs []error
+	for _, rig := range rigs {
+		s := Sample{
+			Rig:           rig,
+			SampledAt:     now.UTC().Truncate(time.Second),
+			Window:        int(window / time.Second),
+			CommitsLanded: merges[rig],
+		}
+		if err := collectBeads(ctx, townRoot, rig, window, &s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rig, err))
+			continue
+		}
+		samples = append(samples, s)
+	}
+	return samples, errors.Join(errs...)
+}
+
+// collectBeads fills the bead-derived fields of s from the rig's database.
+func collectBeads(ctx context.Context, townRoot, rig string, window time.Duration, s *Sample) error {
+	db, err := doltserver.DB(townRoot, doltserver.RigDatabase(townRoot, rig))
+	if err != nil {
+		return err
+	}
+	seconds := int(window / time.Second)
+
+	query, args := beads.From("issues").
+		Select("COUNT(*)").
+		Where("status = 'closed'").
+		Where("closed_at > DATE_SUB(NOW(), INTERVAL ? SECOND)", seconds).
+		WorkItems().
+		Build()
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&s.BeadsClosed); err != nil {
+		return fmt.Errorf("counting closed beads: %w", err)
+	}
+
+	var polecats int
+	query, args = beads.From("issues").
+		Select("COUNT(DISTINCT assignee)").
+		Where("status = 'in_progress'").
+		Where("assignee LIKE ?", rig+"/polecats/%").
+		Build()
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&polecats); err != nil {
+		return fmt.Errorf("counting working polecats: %w", err)
+	}
+	s.PolecatHours = float64(polecats) * window.Hours()
+
+	query, args = beads.From("issues").
+		Select("COUNT(*)", "COALESCE(AVG(TIMESTAMPDIFF(SECOND, created_at, NOW())), 0) / 3600.0").
+		Where("status = 'open'").
+		Where("COALESCE(assignee, '') = ''").
+		WorkItems().
+		Build()
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&s.QueueDepth, &s.QueueWaitHours); err != nil {
+		return fmt.Errorf("measuring queue: %w", err)
+	}
+	return nil
+}
+
+// countMerges counts refinery merges per rig in (from, to] from the town
+// events log. The refinery logs merges as "<rig>/refinery".
+func countMerges(townRoot string, from, to time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return counts, nil
+		}
+		return nil, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !strings.Contains(string(line), `"`+events.TypeMerged+`"`) {
+			continue // cheap pre-filter; most lines aren't merges
+		}
+		var ev events.Event
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Type != events.TypeMerged {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+		if err != nil || !ts.After(from) || ts.After(to) {
+			continue
+		}
+		if rig, role, ok := strings.Cut(ev.Actor, "/"); ok && role == "refinery" {
+			counts[rig]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events log: %w", err)
+	}
+	return counts, nil
+}
+
+// Record writes samples to the stats table, creating it if needed, and
+// commits them so the history is versioned with the rest of hq.
+func Record(ctx context.Context, db *sql.DB, samples []Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("creating %s: %w", Table, err)
+	}
+	for _, s := range samples {
+		_, err := db.ExecContext(ctx, `REPLACE INTO `+Table+`
+			(rig, sampled_at, window_seconds, commits_landed, beads_closed, polecat_hours, queue_depth, queue_wait_hours)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			s.Rig, s.SampledAt.UTC().Format(sqlTimeLayout), s.Window,
+			s.CommitsLanded, s.BeadsClosed, s.PolecatHours, s.QueueDepth, s.QueueWaitHours)
+		if err != nil {
+			return fmt.Errorf("recording %s sample: %w", s.Rig, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "CALL DOLT_ADD(?)", Table); err != nil {
+		return fmt.Errorf("staging %s: %w", Table, err)
+	}
+	if _, err := db.ExecContext(ctx, "CALL DOLT_COMMIT('-m', ?)", fmt.Sprintf("rig stats: %d sample(s)", len(samples))); err != nil &&
+		!strings.Contains(err.Error(), "nothing to commit") {
+		return fmt.Errorf("committing %s: %w", Table, err)
+	}
+	return nil
+}
+
+// Query returns rig's samples taken at or after since, oldest first. A
+// missing table (no samples recorded yet) returns no samples.
+func Query(ctx context.Context, db *sql.DB, rig string, since time.Time) ([]Sample, error) {
+	rows, err := db.QueryContext(ctx, `SELECT rig, DATE_FORMAT(sampled_at, '%Y-%m-%d %H:%i:%s'), window_seconds,
+		commits_landed, beads_closed, polecat_hours, queue_depth, queue_wait_hours
+		FROM `+Table+` WHERE rig = ? AND sampled_at >= ? ORDER BY sampled_at`,
+		rig, since.UTC().Format(sqlTimeLayout))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying %s: %w", Table, err)
+	}
+	defer rows.Close()
+
+	var samples []Sample
+	for rows.Next() {
+		var s Sample
+		var at string
+		if err := rows.Scan(&s.Rig, &at, &s.Window, &s.CommitsLanded, &s.BeadsClosed, &s.PolecatHours, &s.QueueDepth, &s.QueueWaitHours); err != nil {
+			return nil, fmt.Errorf("scanning sample: %w", err)
+		}
+		if s.SampledAt, err = time.ParseInLocation(sqlTimeLayout, at, time.UTC); err != nil {
+			return nil, fmt.Errorf("parsing sample time %q: %w", at, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// Bucket is the aggregate of the samples in one time range.
+type Bucket struct {
+	Start          time.Time `json:"start"`
+	Samples        int       `json:"samples"`
+	CommitsLanded  int       `json:"commits_landed"`
+	BeadsClosed    int       `json:"beads_closed"`
+	PolecatHours   float64   `json:"polecat_hours"`
+	QueueDepth     float64   `json:"queue_depth"`      // mean
+	QueueWaitHours float64   `json:"queue_wait_hours"` // mean
+}
+
+// Aggregate groups samples into n equal buckets spanning [from, to].
+// Counts and hours are summed; queue depth and wait are averaged.
+func Aggregate(samples []Sample, from, to time.Time, n int) []Bucket {
+	if n <= 0 || !to.After(from) {
+		return nil
+	}
+	width := to.Sub(from) / time.Duration(n)
+	buckets := make([]Bucket, n)
+	for i := range buckets {
+		buckets[i].Start = from.Add(time.Duration(i) * width)
+	}
+	for _, s := range samples {
+		if s.SampledAt.Before(from) || s.SampledAt.After(to) {
+			continue
+		}
+		i := min(int(s.SampledAt.Sub(from)/width), n-1)
+		b := &buckets[i]
+		b.Samples++
+		b.CommitsLanded += s.CommitsLanded
+		b.BeadsClosed += s.BeadsClosed
+		b.PolecatHours += s.PolecatHours
+		b.QueueDepth += float64(s.QueueDepth)
+		b.QueueWaitHours += s.QueueWaitHours
+	}
+	for i := range buckets {
+		if c := buckets[i].Samples; c > 0 {
+			buckets[i].QueueDepth /= float64(c)
+			buckets[i].QueueWaitHours /= float64(c)
+		}
+	}
+	return buckets
+}
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a one-line bar chart scaled to the maximum.
+func Sparkline(values []float64) string {
+	var maxV float64
+	for _, v := range values {
+		maxV = math.Max(maxV, v)
+	}
+	var sb strings.Builder
+	for _, v := range values {
+		if maxV <= 0 || v <= 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		i := int(math.Round(v / maxV * float64(len(sparkBlocks)-1)))
+		sb.WriteRune(sparkBlocks[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package rigstats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestCountMerges(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lines := []string{
+		`{"ts":"2026-03-01T11:30:00Z","type":"merged","actor":"gastown/refinery"}`,
+		`{"ts":"2026-03-01T11:45:00Z","type":"merged","actor":"gastown/refinery"}`,
+		`{"ts":"2026-03-01T11:50:00Z","type":"merged","actor":"beads/refinery"}`,
+		`{"ts":"2026-03-01T10:30:00Z","type":"merged","actor":"gastown/refinery"}`, // before window
+		`{"ts":"2026-03-01T11:40:00Z","type":"merge_failed","actor":"gastown/refinery"}`,
+		`{"ts":"2026-03-01T11:40:00Z","type":"merged","actor":"gastown/polecats/toast"}`,
+		`not json "merged"`,
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := countMerges(townRoot, now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("countMerges: %v", err)
+	}
+	if counts["gastown"] != 2 || counts["beads"] != 1 || len(counts) != 2 {
+		t.Errorf("counts = %v, want gastown:2 beads:1", counts)
+	}
+
+	// No events file yet is not an error.
+	counts, err = countMerges(t.TempDir(), now.Add(-time.Hour), now)
+	if err != nil || len(counts) != 0 {
+		t.Errorf("missing log: counts=%v err=%v", counts, err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(4 * time.Hour)
+	samples := []Sample{
+		{SampledAt: from.Add(30 * time.Minute), CommitsLanded: 2, QueueDepth: 4, QueueWaitHours: 1},
+		{SampledAt: from.Add(50 * time.Minute), CommitsLanded: 1, QueueDepth: 2, QueueWaitHours: 3},
+		{SampledAt: from.Add(3 * time.Hour), BeadsClosed: 5, PolecatHours: 2},
+		{SampledAt: to, CommitsLanded: 7},                       // end is inclusive, lands in last bucket
+		{SampledAt: from.Add(-time.Minute), CommitsLanded: 100}, // out of range
+	}
+
+	buckets := Aggregate(samples, from, to, 4)
+	if len(buckets) != 4 {
+		t.Fatalf("len = %d, want 4", len(buckets))
+	}
+	if b := buckets[0]; b.Samples != 2 || b.CommitsLanded != 3 || b.QueueDepth != 3 || b.QueueWaitHours != 2 {
+		t.Errorf("bucket 0 = %+v", b)
+	}
+	if b := buckets[1]; b.Samples != 0 || !b.Start.Equal(from.Add(time.Hour)) {
+		t.Errorf("bucket 1 = %+v", b)
+	}
+	if b := buckets[3]; b.Samples != 2 || b.BeadsClosed != 5 || b.PolecatHours != 2 || b.CommitsLanded != 7 {
+		t.Errorf("bucket 3 = %+v", b)
+	}
+
+	if got := Aggregate(samples, to, from, 4); got != nil {
+		t.Errorf("reversed range = %v, want nil", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		in   []float64
+		want string
+	}{
+		{in: []float64{0, 1, 2, 4, 8}, want: " ▂▃▅█"},
+		{in: []float64{3, 3}, want: "██"},
+		{in: []float64{0, 0}, want: "  "},
+		{in: nil, want: ""},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.in); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package rigstats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// TableStatsTable holds per-table load samples, next to Table in hq.
+const TableStatsTable = "gt_table_stats"
+
+// tableStatsSchema creates the table stats table. One row per database
+// table per flush.
+const tableStatsSchema = `CREATE TABLE IF NOT EXISTS ` + TableStatsTable + ` (
+	db_name VARCHAR(128) NOT NULL,
+	table_name VARCHAR(128) NOT NULL,
+	sampled_at DATETIME NOT NULL,
+	rig VARCHAR(128) NOT NULL,
+	window_seconds INT NOT NULL,
+	queries INT NOT NULL,
+	observations INT NOT NULL,
+	busy_seconds DOUBLE NOT NULL,
+	max_query_seconds DOUBLE NOT NULL,
+	PRIMARY KEY (db_name, table_name, sampled_at)
+)`
+
+// TableSample is the load one table saw over a window ending at SampledAt,
+// estimated by sampling the server's processlist. Queries that start and
+// finish between two samples aren't seen, so the counts are a profile of
+// where server time goes, not an exact query log.
+type TableSample struct {
+	Database  string    `json:"database"`
+	Table     string    `json:"table"`
+	Rig       string    `json:"rig,omitempty"`
+	SampledAt time.Time `json:"sampled_at"`
+	Window    int       `json:"window_seconds"`
+
+	// Queries is the number of distinct queries seen running on the table.
+	Queries int `json:"queries"`
+
+	// Observations is the number of times a query on the table was seen
+	// running, summed over all processlist samples.
+	Observations int `json:"observations"`
+
+	// BusySeconds estimates the time queries spent on the table:
+	// Observations × the sampling interval.
+	BusySeconds float64 `json:"busy_seconds"`
+
+	// MaxQuerySeconds is the longest a query on the table was seen running
+	// (the processlist reports whole seconds).
+	MaxQuerySeconds float64 `json:"max_query_seconds"`
+}
+
+// MeanQuerySeconds estimates the mean latency of the queries seen.
+func (s TableSample) MeanQuerySeconds() float64 {
+	if s.Queries == 0 {
+		return 0
+	}
+	return s.BusySeconds / float64(s.Queries)
+}
+
+// Process is one running statement from the server's processlist.
+type Process struct {
+	ID       int64
+	Database string
+	Seconds  int
+	Query    string
+}
+
+// ListProcesses returns the statements running on the server, leaving out
+// the connection asking.
+func ListProcesses(ctx context.Context, db *sql.DB) ([]Process, error) {
+	rows, err := db.QueryContext(ctx, `SELECT ID, COALESCE(DB, ''), COALESCE(TIME, 0), COALESCE(INFO, '')
+		FROM information_schema.PROCESSLIST WHERE COMMAND = 'Query' AND ID <> CONNECTION_ID()`)
+	if err != nil {
+		return nil, fmt.Errorf("reading processlist: %w", err)
+	}
+	defer rows.Close()
+
+	var procs []Process
+	for rows.Next() {
+		var p Process
+		if err := rows.Scan(&p.ID, &p.Database, &p.Seconds, &p.Query); err != nil {
+			return nil, fmt.Errorf("scanning processlist: %w", err)
+		}
+		procs = append(procs, p)
+	}
+	return procs, rows.Err()
+}
+
+// tableRef is a table qualified by its database.
+type tableRef struct {
+	db, table string
+}
+
+// tableRefPattern matches the table named after FROM, JOIN, INTO, or UPDATE,
+// optionally database-qualified and backquoted.
+var tableRefPattern = regexp.MustCompile("(?i)\\b(?:FROM|JOIN|INTO|UPDATE)\\s+(`[^`]+`|\\w+)(?:\\s*\\.\\s*(`[^`]+`|\\w+))?")
+
+// tablesInQuery returns the tables query reads or writes, resolving
+// unqualified names against defaultDB. Catalog tables aren't load on a rig
+// database and are left out. It is a heuristic: aliases, comma joins, and
+// table functions beyond their name aren't understood.
+func tablesInQuery(query, defaultDB string) []tableRef {
+	var refs []tableRef
+	seen := map[tableRef]bool{}
+	for _, m := range tableRefPattern.FindAllStringSubmatch(query, -1) {
+		ref := tableRef{db: defaultDB, table: unquoteIdent(m[1])}
+		if m[2] != "" {
+			ref = tableRef{db: unquoteIdent(m[1]), table: unquoteIdent(m[2])}
+		}
+		ref.db, ref.table = strings.ToLower(ref.db), strings.ToLower(ref.table)
+		switch {
+		case ref.db == "" || ref.table == "dual":
+			continue
+		case ref.db == "information_schema" || ref.db == "mysql" || ref.db == "performance_schema":
+			continue
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func unquoteIdent(s string) string {
+	return strings.Trim(s, "`")
+}
+
+// TableSampler accumulates processlist samples into per-table load until
+// it is flushed.
+type TableSampler struct {
+	interval time.Duration
+	since    time.Time
+	stats    map[tableRef]*TableSample
+
+	// running is what each connection was running at the last sample, so
+	// a long query seen several times counts once.
+	running map[int64]Process
+}
+
+// NewTableSampler returns a sampler for processlist samples taken every
+// interval, with its first window starting at now.
+func NewTableSampler(interval time.Duration, now time.Time) *TableSampler {
+	return &TableSampler{
+		interval: interval,
+		since:    now,
+		stats:    make(map[tableRef]*TableSample),
+		running:  make(map[int64]Process),
+	}
+}
+
+// Observe adds one processlist sample.
+func (s *TableSampler) Observe(procs []Process) {
+	running := make(map[int64]Process, len(procs))
+	for _, p := range procs {
+		running[p.ID] = p
+		prev, ok := s.running[p.ID]
+		continuing := ok && prev.Query == p.Query && p.Seconds >= prev.Seconds
+		for _, ref := range tablesInQuery(p.Query, p.Database) {
+			st := s.stats[ref]
+			if st == nil {
+				st = &TableSample{Database: ref.db, Table: ref.table}
+				s.stats[ref] = st
+			}
+			if !continuing {
+				st.Queries++
+			}
+			st.Observations++
+			st.BusySeconds += s.interval.Seconds()
+			st.MaxQuerySeconds = max(st.MaxQuerySeconds, float64(p.Seconds))
+		}
+	}
+	s.running = running
+}
+
+// Flush returns the load accumulated since the last flush, busiest table
+// first, and starts a new window. rigs maps database names to the rig they
+// belong to; databases it doesn't name (such as hq) get no rig.
+func (s *TableSampler) Flush(now time.Time, rigs map[string]string) []TableSample {
+	window := int(now.Sub(s.since) / time.Second)
+	samples := make([]TableSample, 0, len(s.stats))
+	for _, st := range s.stats {
+		st.Rig = rigs[st.Database]
+		st.SampledAt = now.UTC().Truncate(time.Second)
+		st.Window = window
+		samples = append(samples, *st)
+	}
+	sortByLoad(samples)
+	s.stats = make(map[tableRef]*TableSample)
+	s.since = now
+	return samples
+}
+
+// DatabaseRigs maps each rig's Dolt database name to the rig.
+func DatabaseRigs(townRoot string, rigs []string) map[string]string {
+	m := make(map[string]string, len(rigs))
+	for _, rig := range rigs {
+		m[strings.ToLower(doltserver.RigDatabase(townRoot, rig))] = rig
+	}
+	return m
+}
+
+// RecordTableStats writes samples to the table stats table, creating it if
+// needed, and commits them.
+func RecordTableStats(ctx context.Context, db *sql.DB, samples []TableSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, tableStatsSchema); err != nil {
+		return fmt.Errorf("creating %s: %w", TableStatsTable, err)
+	}
+	for _, s := range samples {
+		_, err := db.ExecContext(ctx, `REPLACE INTO `+TableStatsTable+`
+			(db_name, table_name, sampled_at, rig, window_seconds, queries, observations, busy_seconds, max_query_seconds)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			s.Database, s.Table, s.SampledAt.UTC().Format(sqlTimeLayout), s.Rig, s.Window,
+			s.Queries, s.Observations, s.BusySeconds, s.MaxQuerySeconds)
+		if err != nil {
+			return fmt.Errorf("recording %s.%s sample: %w", s.Database, s.Table, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "CALL DOLT_ADD(?)", TableStatsTable); err != nil {
+		return fmt.Errorf("staging %s: %w", TableStatsTable, err)
+	}
+	if _, err := db.ExecContext(ctx, "CALL DOLT_COMMIT('-m', ?)", fmt.Sprintf("table stats: %d sample(s)", len(samples))); err != nil &&
+		!strings.Contains(err.Error(), "nothing to commit") {
+		return fmt.Errorf("committing %s: %w", TableStatsTable, err)
+	}
+	return nil
+}
+
+// QueryTableStats returns table samples taken at or after since, oldest
+// first, optionally only for one rig. A missing table (nothing recorded
+// yet) returns no samples.
+func QueryTableStats(ctx context.Context, db *sql.DB, rig string, since time.Time) ([]TableSample, error) {
+	query := `SELECT db_name, table_name, rig, DATE_FORMAT(sampled_at, '%Y-%m-%d %H:%i:%s'), window_seconds,
+		queries, observations, busy_seconds, max_query_seconds
+		FROM ` + TableStatsTable + ` WHERE sampled_at >= ?`
+	args := []any{since.UTC().Format(sqlTimeLayout)}
+	if rig != "" {
+		query += " AND rig = ?"
+		args = append(args, rig)
+	}
+	rows, err := db.QueryContext(ctx, query+" ORDER BY sampled_at", args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying %s: %w", TableStatsTable, err)
+	}
+	defer rows.Close()
+
+	var samples []TableSample
+	for rows.Next() {
+		var s TableSample
+		var at string
+		if err := rows.Scan(&s.Database, &s.Table, &s.Rig, &at, &s.Window,
+			&s.Queries, &s.Observations, &s.BusySeconds, &s.MaxQuerySeconds); err != nil {
+			return nil, fmt.Errorf("scanning table sample: %w", err)
+		}
+		if s.SampledAt, err = time.ParseInLocation(sqlTimeLayout, at, time.UTC); err != nil {
+			return nil, fmt.Errorf("parsing sample time %q: %w", at, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// SummarizeTables totals samples per table, busiest first. Each total's
+// SampledAt is the table's latest sample and Window the sum of its windows.
+func SummarizeTables(samples []TableSample) []TableSample {
+	totals := make(map[tableRef]*TableSample)
+	for _, s := range samples {
+		ref := tableRef{db: s.Database, table: s.Table}
+		t := totals[ref]
+		if t == nil {
+			t = &TableSample{Database: s.Database, Table: s.Table}
+			totals[ref] = t
+		}
+		if s.Rig != "" {
+			t.Rig = s.Rig
+		}
+		if s.SampledAt.After(t.SampledAt) {
+			t.SampledAt = s.SampledAt
+		}
+		t.Window += s.Window
+		t.Queries += s.Queries
+		t.Observations += s.Observations
+		t.BusySeconds += s.BusySeconds
+		t.MaxQuerySeconds = max(t.MaxQuerySeconds, s.MaxQuerySeconds)
+	}
+	out := make([]TableSample, 0, len(totals))
+	for _, t := range totals {
+		out = append(out, *t)
+	}
+	sortByLoad(out)
+	return out
+}
+
+// sortByLoad orders samples by busy time, then queries, then name.
+func sortByLoad(samples []TableSample) {
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i], samples[j]
+		if a.BusySeconds != b.BusySeconds {
+			return a.BusySeconds > b.BusySeconds
+		}
+		if a.Queries != b.Queries {
+			return a.Queries > b.Queries
+		}
+		if a.Database != b.Database {
+			return a.Database < b.Database
+		}
+		return a.Table < b.Table
+	})
+}
@@ -0,0 +1,93 @@
+package rigstats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTablesInQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		db    string
+		want  []tableRef
+	}{
+		{"SELECT * FROM issues WHERE status = 'open'", "gastown", []tableRef{{"gastown", "issues"}}},
+		{"SELECT i.id FROM `issues` i JOIN dependencies d ON d.issue_id = i.id", "gastown",
+			[]tableRef{{"gastown", "issues"}, {"gastown", "dependencies"}}},
+		{"INSERT INTO labels (issue_id, label) VALUES (?, ?)", "beads", []tableRef{{"beads", "labels"}}},
+		{"update `hq`.`Issues` set status = 'closed'", "", []tableRef{{"hq", "issues"}}},
+		{"DELETE FROM events WHERE id IN (SELECT id FROM events LIMIT 10)", "gastown", []tableRef{{"gastown", "events"}}},
+		{"SELECT COUNT(*) FROM dolt_log(?)", "gastown", []tableRef{{"gastown", "dolt_log"}}},
+		{"SELECT * FROM information_schema.PROCESSLIST", "gastown", nil},
+		{"SELECT 1 FROM DUAL", "gastown", nil},
+		{"SELECT * FROM issues", "", nil}, // no database to attribute it to
+	}
+	for _, tt := range tests {
+		if got := tablesInQuery(tt.query, tt.db); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tablesInQuery(%q, %q) = %v, want %v", tt.query, tt.db, got, tt.want)
+		}
+	}
+}
+
+func TestTableSampler(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewTableSampler(2*time.Second, start)
+
+	// A long query on issues seen three times is one query; connection 2
+	// runs the same statement again after it finished, which is a new one.
+	long := "SELECT * FROM issues JOIN labels ON labels.issue_id = issues.id"
+	s.Observe([]Process{{ID: 1, Database: "gastown", Seconds: 0, Query: long}, {ID: 2, Database: "beads", Query: "SELECT * FROM issues"}})
+	s.Observe([]Process{{ID: 1, Database: "gastown", Seconds: 2, Query: long}})
+	s.Observe([]Process{{ID: 1, Database: "gastown", Seconds: 4, Query: long}, {ID: 2, Database: "beads", Query: "SELECT * FROM issues"}})
+
+	samples := s.Flush(start.Add(10*time.Minute), map[string]string{"gastown": "gastown"})
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, want 3: %+v", len(samples), samples)
+	}
+	if got := samples[2]; got.Database != "beads" || got.Queries != 2 || got.Observations != 2 || got.Rig != "" {
+		t.Errorf("beads.issues = %+v, want 2 queries, 2 observations, no rig", got)
+	}
+	for _, got := range samples[:2] {
+		if got.Database != "gastown" || got.Rig != "gastown" {
+			t.Errorf("sample = %+v, want a gastown table", got)
+			continue
+		}
+		if got.Queries != 1 || got.Observations != 3 || got.BusySeconds != 6 || got.MaxQuerySeconds != 4 {
+			t.Errorf("gastown.%s = %+v, want 1 query, 3 observations, 6s busy, 4s max", got.Table, got)
+		}
+		if got.Window != 600 || !got.SampledAt.Equal(start.Add(10*time.Minute)) {
+			t.Errorf("gastown.%s window = %d at %v", got.Table, got.Window, got.SampledAt)
+		}
+	}
+
+	if again := s.Flush(start.Add(20*time.Minute), nil); len(again) != 0 {
+		t.Errorf("second flush = %+v, want nothing", again)
+	}
+}
+
+func TestSummarizeTables(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	samples := []TableSample{
+		{Database: "gastown", Table: "issues", Rig: "gastown", SampledAt: at, Window: 900, Queries: 4, BusySeconds: 8, MaxQuerySeconds: 3},
+		{Database: "hq", Table: "issues", SampledAt: at, Window: 900, Queries: 10, BusySeconds: 12},
+		{Database: "gastown", Table: "issues", Rig: "gastown", SampledAt: at.Add(15 * time.Minute), Window: 900, Queries: 2, BusySeconds: 6, MaxQuerySeconds: 1},
+	}
+	got := SummarizeTables(samples)
+	if len(got) != 2 {
+		t.Fatalf("got %d tables, want 2", len(got))
+	}
+	first := got[0]
+	if first.Database != "gastown" || first.Queries != 6 || first.BusySeconds != 14 || first.MaxQuerySeconds != 3 || first.Window != 1800 {
+		t.Errorf("gastown.issues = %+v", first)
+	}
+	if !first.SampledAt.Equal(at.Add(15 * time.Minute)) {
+		t.Errorf("SampledAt = %v, want the latest sample", first.SampledAt)
+	}
+	if mean := first.MeanQuerySeconds(); mean < 2.33 || mean > 2.34 {
+		t.Errorf("MeanQuerySeconds = %v, want ~2.33", mean)
+	}
+	if got[1].Database != "hq" {
+		t.Errorf("second = %+v, want hq.issues", got[1])
+	}
+}
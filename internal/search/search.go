@@ -0,0 +1,380 @@
+// Package search finds a term across a town's beads, agent transcripts and
+// session logs, and config files, so gt search can answer "where did we
+// discuss X" from one place.
+//
+// Beads are searched in the Dolt server with SQL LIKE on titles and
+// descriptions; transcripts, logs and configs are scanned line by line.
+// Matching is case-insensitive everywhere.
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kind is the type of thing a result was found in.
+type Kind string
+
+// Result kinds.
+const (
+	KindBead       Kind = "bead"
+	KindTranscript Kind = "transcript" // agent transcripts and headless session logs
+	KindConfig     Kind = "config"
+)
+
+// Kinds lists every result kind, in the order results are reported.
+var Kinds = []Kind{KindBead, KindTranscript, KindConfig}
+
+// DefaultLimit is the most results returned per kind.
+const DefaultLimit = 50
+
+// snippetWidth is the most characters of context shown around a match.
+const snippetWidth = 120
+
+// maxConfigSize skips files too large to be hand-written config.
+const maxConfigSize = 1 << 20
+
+// Result is one match.
+type Result struct {
+	Kind Kind   `json:"kind"`
+	Rig  string `json:"rig,omitempty"` // empty for town-level results
+
+	// Location is the bead ID, or path:line for files.
+	Location string `json:"location"`
+
+	// Title is the bead's title, or the role and time of a transcript
+	// message.
+	Title string `json:"title,omitempty"`
+
+	// Status is the bead's status.
+	Status string `json:"status,omitempty"`
+
+	// Snippet is the matching text with some context.
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Scope is one rig, or the town itself, to search.
+type Scope struct {
+	Rig string // empty for the town
+
+	// DB is the scope's beads database; nil skips beads.
+	DB *sql.DB
+
+	// TranscriptGlobs match agent transcripts (*.jsonl) and session logs.
+	TranscriptGlobs []string
+
+	// ConfigGlobs match config files.
+	ConfigGlobs []string
+}
+
+// Options controls Search.
+type Options struct {
+	Term string
+
+	// Kinds to search; empty searches all.
+	Kinds []Kind
+
+	// Limit is the most results per kind. Default: DefaultLimit.
+	Limit int
+}
+
+func (o Options) wants(k Kind) bool {
+	if len(o.Kinds) == 0 {
+		return true
+	}
+	for _, w := range o.Kinds {
+		if w == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Search looks for opts.Term in every scope. A source that fails (e.g. a
+// database that is down) doesn't stop the others: its error is joined
+// into the returned error alongside whatever was found.
+func Search(ctx context.Context, scopes []Scope, opts Options) ([]Result, error) {
+	if strings.TrimSpace(opts.Term) == "" {
+		return nil, errors.New("empty search term")
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+
+	var results []Result
+	var errs []error
+	for _, kind := range Kinds {
+		if !opts.wants(kind) {
+			continue
+		}
+		remaining := opts.Limit
+		for _, sc := range scopes {
+			if remaining <= 0 {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			var found []Result
+			var err error
+			switch kind {
+			case KindBead:
+				if sc.DB == nil {
+					continue
+				}
+				found, err = Beads(ctx, sc.DB, opts.Term, remaining)
+			case KindTranscript:
+				found, err = Transcripts(globFiles(sc.TranscriptGlobs), opts.Term, remaining)
+			case KindConfig:
+				found, err = Configs(globFiles(sc.ConfigGlobs), opts.Term, remaining)
+			}
+			for i := range found {
+				found[i].Rig = sc.Rig
+			}
+			results = append(results, found...)
+			remaining -= len(found)
+			if err != nil {
+				name := sc.Rig
+				if name == "" {
+					name = "town"
+				}
+				errs = append(errs, fmt.Errorf("%s %ss: %w", name, kind, err))
+			}
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Beads returns up to limit beads whose title or description contains
+// term, most recently updated first.
+func Beads(ctx context.Context, db *sql.DB, term string, limit int) ([]Result, error) {
+	pattern := "%" + escapeLike(strings.ToLower(term)) + "%"
+	rows, err := db.QueryContext(ctx,
+		"SELECT id, title, status, description FROM issues"+
+			" WHERE LOWER(title) LIKE ? OR LOWER(description) LIKE ?"+
+			" ORDER BY updated_at DESC LIMIT ?",
+		pattern, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		var id, title, status string
+		var desc sql.NullString
+		if err := rows.Scan(&id, &title, &status, &desc); err != nil {
+			return results, err
+		}
+		r := Result{Kind: KindBead, Location: id, Title: title, Status: status}
+		if !containsFold(title, term) {
+			r.Snippet = Snippet(desc.String, term)
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// escapeLike escapes LIKE wildcards so term matches literally.
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+// Transcripts returns up to limit matches in files: for *.jsonl agent
+// transcripts, messages whose text contains term; for other files, such
+// as headless session logs, matching lines.
+func Transcripts(files []string, term string, limit int) ([]Result, error) {
+	var results []Result
+	err := scanFiles(files, term, limit, &results, func(path string, lineNo int, line []byte) (Result, bool) {
+		if filepath.Ext(path) != ".jsonl" {
+			text := string(line)
+			return Result{Kind: KindTranscript, Location: fmt.Sprintf("%s:%d", path, lineNo), Snippet: Snippet(text, term)}, true
+		}
+		var msg struct {
+			Type      string `json:"type"`
+			Timestamp string `json:"timestamp"`
+			Message   struct {
+				Role    string          `json:"role"`
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(line, &msg) != nil {
+			return Result{}, false
+		}
+		text := messageText(msg.Message.Content)
+		if !containsFold(text, term) {
+			return Result{}, false
+		}
+		title := msg.Message.Role
+		if title == "" {
+			title = msg.Type
+		}
+		if msg.Timestamp != "" {
+			title += " " + msg.Timestamp
+		}
+		return Result{
+			Kind:     KindTranscript,
+			Location: fmt.Sprintf("%s:%d", path, lineNo),
+			Title:    strings.TrimSpace(title),
+			Snippet:  Snippet(text, term),
+		}, true
+	})
+	return results, err
+}
+
+// messageText returns the text in a transcript message's content, a
+// string or a list of blocks: text blocks and tool results count,
+// tool inputs and images don't.
+func messageText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var blocks []struct {
+		Type    string          `json:"type"`
+		Text    string          `json:"text"`
+		Content json.RawMessage `json:"content"`
+	}
+	if json.Unmarshal(raw, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		switch {
+		case b.Text != "":
+			parts = append(parts, b.Text)
+		case b.Type == "tool_result" && len(b.Content) > 0:
+			parts = append(parts, messageText(b.Content))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Configs returns up to limit matching lines in files, skipping any over
+// 1 MiB.
+func Configs(files []string, term string, limit int) ([]Result, error) {
+	var small []string
+	for _, path := range files {
+		if info, err := os.Stat(path); err == nil && info.Size() <= maxConfigSize {
+			small = append(small, path)
+		}
+	}
+	var results []Result
+	err := scanFiles(small, term, limit, &results, func(path string, lineNo int, line []byte) (Result, bool) {
+		return Result{Kind: KindConfig, Location: fmt.Sprintf("%s:%d", path, lineNo), Snippet: Snippet(string(line), term)}, true
+	})
+	return results, err
+}
+
+// scanFiles calls match for each line of files that contains term,
+// ignoring case, until limit results are collected. Unreadable files are
+// skipped.
+func scanFiles(files []string, term string, limit int, results *[]Result, match func(path string, lineNo int, line []byte) (Result, bool)) error {
+	needle := bytes.ToLower([]byte(term))
+	for _, path := range files {
+		if len(*results) >= limit {
+			return nil
+		}
+		f, err := os.Open(path) //nolint:gosec // G304: paths come from the town's own globs
+		if err != nil {
+			continue
+		}
+		br := bufio.NewReader(f)
+		lineNo := 0
+		for len(*results) < limit {
+			line, readErr := br.ReadBytes('\n')
+			if len(line) > 0 {
+				lineNo++
+				line = bytes.TrimRight(line, "\r\n")
+				if bytes.Contains(bytes.ToLower(line), needle) {
+					if r, ok := match(path, lineNo, line); ok {
+						*results = append(*results, r)
+					}
+				}
+			}
+			if readErr != nil {
+				if readErr != io.EOF {
+					f.Close()
+					return fmt.Errorf("reading %s: %w", path, readErr)
+				}
+				break
+			}
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// globFiles expands patterns into the regular files they match, sorted
+// and deduplicated.
+func globFiles(patterns []string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, p := range patterns {
+		matches, _ := filepath.Glob(p)
+		for _, m := range matches {
+			if seen[m] {
+				continue
+			}
+			if info, err := os.Stat(m); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			seen[m] = true
+			files = append(files, m)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// containsFold reports whether s contains term, ignoring case.
+func containsFold(s, term string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(term))
+}
+
+// Snippet returns the part of text around the first match of term, with
+// whitespace collapsed and "…" marking cuts, or "" if term isn't in text.
+func Snippet(text, term string) string {
+	text = strings.Join(strings.FieldsFunc(text, unicode.IsSpace), " ")
+	i := strings.Index(strings.ToLower(text), strings.ToLower(term))
+	if i < 0 {
+		return ""
+	}
+	start := i - (snippetWidth-len(term))/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWidth
+	if end > len(text) {
+		end = len(text)
+		start = max(0, end-snippetWidth)
+	}
+	// Don't cut through a UTF-8 sequence.
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+	out := text[start:end]
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(text) {
+		out += "…"
+	}
+	return out
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	transcript := filepath.Join(dir, "session.jsonl")
+	writeFile(t, transcript, strings.Join([]string{
+		`{"type":"user","timestamp":"2026-03-01T10:00:00Z","message":{"role":"user","content":"why is the TLS test flaky?"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","input":{"cmd":"grep flaky"}}]}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":[{"type":"text","text":"--- FAIL: TestTLSHandshake (flaky)"}]}]}}`,
+		`not json mentioning flaky`,
+	}, "\n"))
+	log := filepath.Join(dir, "gt-gastown-toast.log")
+	writeFile(t, log, "starting\nretrying Flaky test\n")
+
+	got, err := Transcripts([]string{log, transcript}, "flaky", 10)
+	if err != nil {
+		t.Fatalf("Transcripts: %v", err)
+	}
+	var locs []string
+	for _, r := range got {
+		locs = append(locs, filepath.Base(r.Location))
+	}
+	want := "gt-gastown-toast.log:2 session.jsonl:1 session.jsonl:3"
+	if strings.Join(locs, " ") != want {
+		t.Errorf("locations = %v, want %s", locs, want)
+	}
+	if got[1].Title != "user 2026-03-01T10:00:00Z" {
+		t.Errorf("title = %q", got[1].Title)
+	}
+	if !strings.Contains(got[2].Snippet, "TestTLSHandshake") {
+		t.Errorf("tool result snippet = %q", got[2].Snippet)
+	}
+}
+
+func TestConfigs_Limit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeFile(t, path, "{\n  \"tls\": true,\n  \"tls_cert\": \"x\",\n  \"tls_key\": \"y\"\n}\n")
+
+	got, err := Configs([]string{path}, "TLS", 2)
+	if err != nil {
+		t.Fatalf("Configs: %v", err)
+	}
+	if len(got) != 2 || got[0].Location != path+":2" || got[0].Snippet != `"tls": true,` {
+		t.Errorf("Configs = %+v", got)
+	}
+}
+
+func TestSearch_FilesAcrossScopes(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, "settings", "config.json"), `{"flaky_retries": 2}`)
+	writeFile(t, filepath.Join(town, "gastown", "settings", "config.json"), `{"note": "flaky"}`)
+
+	scopes := []Scope{
+		{ConfigGlobs: []string{filepath.Join(town, "settings", "*.json")}},
+		{Rig: "gastown", ConfigGlobs: []string{filepath.Join(town, "gastown", "settings", "*.json")}},
+	}
+	got, err := Search(context.Background(), scopes, Options{Term: "flaky", Kinds: []Kind{KindConfig}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(got) != 2 || got[0].Rig != "" || got[1].Rig != "gastown" {
+		t.Errorf("Search = %+v", got)
+	}
+
+	if _, err := Search(context.Background(), scopes, Options{Term: "  "}); err == nil {
+		t.Error("Search with blank term succeeded")
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("a ", 100) + "needle" + strings.Repeat(" b", 100)
+	got := Snippet(long, "NEEDLE")
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "needle") {
+		t.Errorf("Snippet = %q", got)
+	}
+	if got := Snippet("short\n  text  here", "text"); got != "short text here" {
+		t.Errorf("Snippet = %q, want whitespace collapsed", got)
+	}
+	if got := Snippet("nothing", "needle"); got != "" {
+		t.Errorf("Snippet = %q, want empty", got)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got := escapeLike(`100%_done\`); got != `100\%\_done\\` {
+		t.Errorf("escapeLike = %q", got)
+	}
+}
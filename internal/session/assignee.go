@@ -0,0 +1,40 @@
+package session
+
+// Assignee is the agent a bead is assigned to. Beads record assignees as
+// mail-style addresses ("mayor", "gastown/witness", "gastown/crew/max",
+// "gastown/polecats/Toast", or the legacy polecat form "gastown/Toast");
+// Assignee is the one place that format is interpreted.
+type Assignee struct {
+	id AgentIdentity
+}
+
+// ParseAssignee parses a bead assignee. Unrecognized formats are an error.
+func ParseAssignee(assignee string) (*Assignee, error) {
+	id, err := ParseAddress(assignee)
+	if err != nil {
+		return nil, err
+	}
+	return &Assignee{id: *id}, nil
+}
+
+// Role returns the assignee's role.
+func (a *Assignee) Role() Role { return a.id.Role }
+
+// Rig returns the assignee's rig, or "" for town-level agents.
+func (a *Assignee) Rig() string { return a.id.Rig }
+
+// Name returns the crew or polecat name, or "" for singleton roles.
+func (a *Assignee) Name() string { return a.id.Name }
+
+// SessionName returns the assignee's tmux session name.
+func (a *Assignee) SessionName() string { return a.id.SessionName() }
+
+// IsPersistent reports whether the assignee is a long-lived identity that
+// resumes its work after a restart. Only polecats are ephemeral: when a
+// polecat's session is gone its in-progress work is abandoned, while a
+// missing crew, witness, refinery, mayor or deacon session just means the
+// agent is down.
+func (a *Assignee) IsPersistent() bool { return a.id.Role != RolePolecat }
+
+// String returns the canonical address, e.g. "gastown/polecats/Toast".
+func (a *Assignee) String() string { return a.id.Address() }
@@ -0,0 +1,55 @@
+package session
+
+import "testing"
+
+func TestParseAssignee(t *testing.T) {
+	old := defaultRegistry
+	defaultRegistry = testRegistry()
+	defer func() { defaultRegistry = old }()
+
+	tests := []struct {
+		assignee       string
+		wantSession    string
+		wantRole       Role
+		wantRig        string
+		wantPersistent bool
+	}{
+		{"mayor", "hq-mayor", RoleMayor, "", true},
+		{"deacon", "hq-deacon", RoleDeacon, "", true},
+		{"gastown/witness", "gt-witness", RoleWitness, "gastown", true},
+		{"gastown/refinery", "gt-refinery", RoleRefinery, "gastown", true},
+		{"gastown/crew/joe", "gt-crew-joe", RoleCrew, "gastown", true},
+		{"gastown/polecats/max", "gt-max", RolePolecat, "gastown", false},
+		{"beads/nux", "bd-nux", RolePolecat, "beads", false}, // legacy polecat form
+		{"", "", "", "", false},
+		{"unknown", "", "", "", false},
+		{"overseer", "", "", "", false},
+		{"gastown/unknown/agent", "", "", "", false},
+		{"gastown/refinery/rig", "", "", "", false},
+		{"a/b/c/d", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.assignee, func(t *testing.T) {
+			a, err := ParseAssignee(tt.assignee)
+			if tt.wantSession == "" {
+				if err == nil {
+					t.Fatalf("ParseAssignee(%q) = %+v, want error", tt.assignee, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAssignee(%q): %v", tt.assignee, err)
+			}
+			if got := a.SessionName(); got != tt.wantSession {
+				t.Errorf("SessionName() = %q, want %q", got, tt.wantSession)
+			}
+			if a.Role() != tt.wantRole || a.Rig() != tt.wantRig {
+				t.Errorf("Role(), Rig() = %q, %q, want %q, %q", a.Role(), a.Rig(), tt.wantRole, tt.wantRig)
+			}
+			if a.IsPersistent() != tt.wantPersistent {
+				t.Errorf("IsPersistent() = %v, want %v", a.IsPersistent(), tt.wantPersistent)
+			}
+		})
+	}
+}
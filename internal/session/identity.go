@@ -124,9 +124,14 @@ func ParseSessionNameWithRegistry(session string, registry *PrefixRegistry) (*Ag
 		}
 	}
 
-	// Rig-level roles: <prefix>-<rest>
+	// Rig-level roles: [<town>-]<prefix>-<rest>
+	rigSession, ok := stripTownPrefix(session)
+	if !ok {
+		return nil, fmt.Errorf("invalid session name %q: not in town session prefix %q", session, townPrefix)
+	}
+
 	// Use registry to identify the prefix boundary
-	prefix, rest, _ := registry.matchPrefix(session)
+	prefix, rest, _ := registry.matchPrefix(rigSession)
 	if prefix == "" || rest == "" {
 		return nil, fmt.Errorf("invalid session name %q: cannot determine prefix", session)
 	}
@@ -152,11 +152,16 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 		return nil, fmt.Errorf("ensuring runtime settings: %w", err)
 	}
 
-	// 3. Build startup command if not provided.
+	// 3. Resolve secrets, then build the startup command if not provided.
+	// A missing secret fails the start; the values reach the agent's
+	// environment, never an argv.
+	secrets, err := config.StartupSecrets(cfg.Role, cfg.TownRoot, cfg.RigPath, cfg.AgentOverride)
+	if err != nil {
+		return nil, fmt.Errorf("resolving secrets: %w", err)
+	}
 	command := cfg.Command
 	if command == "" {
 		prompt := buildPrompt(cfg)
-		var err error
 		command, err = buildCommand(cfg, prompt)
 		if err != nil {
 			return nil, fmt.Errorf("building startup command: %w", err)
@@ -176,6 +181,10 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 	}
 
 	// 4. Create tmux session with command.
+	command, err = t.WithSecrets(command, secrets)
+	if err != nil {
+		return nil, err
+	}
 	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
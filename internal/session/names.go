@@ -3,6 +3,7 @@ package session
 
 import (
 	"fmt"
+	"strings"
 )
 
 // DefaultPrefix is the default beads prefix used when no rig-specific prefix is known.
@@ -11,6 +12,42 @@ const DefaultPrefix = "gt"
 // HQPrefix is the prefix for town-level services (Mayor, Deacon).
 const HQPrefix = "hq-"
 
+// townPrefix namespaces rig-level session names so several towns can share
+// one tmux server. Empty (the default) leaves names unchanged.
+var townPrefix string
+
+// SetTownPrefix sets the town-wide session prefix (TownSettings.SessionPrefix).
+// Rig-level names become "<town>-<rigPrefix>-..."; town-level hq- sessions
+// are not affected. A trailing dash is optional.
+func SetTownPrefix(prefix string) {
+	townPrefix = strings.TrimSuffix(strings.TrimSpace(prefix), "-")
+}
+
+// RigSessionPrefix returns the prefix shared by every session of a rig,
+// including the trailing dash (e.g., "gt-", or "alpha-gt-" with a town prefix).
+// Use it instead of rigPrefix+"-" when scanning tmux sessions.
+func RigSessionPrefix(rigPrefix string) string {
+	return withTownPrefix(rigPrefix + "-")
+}
+
+func withTownPrefix(name string) string {
+	if townPrefix == "" {
+		return name
+	}
+	return townPrefix + "-" + name
+}
+
+// stripTownPrefix removes the town prefix from a rig-level session name.
+// Returns false if a town prefix is set and name does not carry it, i.e. the
+// session belongs to another town.
+func stripTownPrefix(name string) (string, bool) {
+	if townPrefix == "" {
+		return name, true
+	}
+	rest, ok := strings.CutPrefix(name, townPrefix+"-")
+	return rest, ok
+}
+
 // MayorSessionName returns the session name for the Mayor agent.
 // One mayor per machine - multi-town requires containers/VMs for isolation.
 func MayorSessionName() string {
@@ -26,25 +63,25 @@ func DeaconSessionName() string {
 // WitnessSessionName returns the session name for a rig's Witness agent.
 // rigPrefix is the rig's beads prefix (e.g., "gt" for gastown, "bd" for beads).
 func WitnessSessionName(rigPrefix string) string {
-	return fmt.Sprintf("%s-witness", rigPrefix)
+	return withTownPrefix(fmt.Sprintf("%s-witness", rigPrefix))
 }
 
 // RefinerySessionName returns the session name for a rig's Refinery agent.
 // rigPrefix is the rig's beads prefix (e.g., "gt" for gastown, "bd" for beads).
 func RefinerySessionName(rigPrefix string) string {
-	return fmt.Sprintf("%s-refinery", rigPrefix)
+	return withTownPrefix(fmt.Sprintf("%s-refinery", rigPrefix))
 }
 
 // CrewSessionName returns the session name for a crew worker in a rig.
 // rigPrefix is the rig's beads prefix (e.g., "gt" for gastown, "bd" for beads).
 func CrewSessionName(rigPrefix, name string) string {
-	return fmt.Sprintf("%s-crew-%s", rigPrefix, name)
+	return withTownPrefix(fmt.Sprintf("%s-crew-%s", rigPrefix, name))
 }
 
 // PolecatSessionName returns the session name for a polecat in a rig.
 // rigPrefix is the rig's beads prefix (e.g., "gt" for gastown, "bd" for beads).
 func PolecatSessionName(rigPrefix, name string) string {
-	return fmt.Sprintf("%s-%s", rigPrefix, name)
+	return withTownPrefix(fmt.Sprintf("%s-%s", rigPrefix, name))
 }
 
 // OverseerSessionName returns the session name for the human operator.
@@ -116,3 +116,49 @@ func TestDefaultPrefix(t *testing.T) {
 		t.Errorf("DefaultPrefix = %q, want %q", DefaultPrefix, want)
 	}
 }
+
+func TestTownPrefix(t *testing.T) {
+	SetTownPrefix("alpha-")
+	defer SetTownPrefix("")
+
+	reg := NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	old := defaultRegistry
+	defaultRegistry = reg
+	defer func() { defaultRegistry = old }()
+
+	if got := WitnessSessionName("gt"); got != "alpha-gt-witness" {
+		t.Errorf("WitnessSessionName = %q, want alpha-gt-witness", got)
+	}
+	if got := CrewSessionName("gt", "max"); got != "alpha-gt-crew-max" {
+		t.Errorf("CrewSessionName = %q, want alpha-gt-crew-max", got)
+	}
+	if got := RigSessionPrefix("gt"); got != "alpha-gt-" {
+		t.Errorf("RigSessionPrefix = %q, want alpha-gt-", got)
+	}
+	if got := MayorSessionName(); got != "hq-mayor" {
+		t.Errorf("MayorSessionName = %q, want hq-mayor (unprefixed)", got)
+	}
+
+	id, err := ParseSessionName("alpha-gt-crew-max")
+	if err != nil {
+		t.Fatalf("ParseSessionName: %v", err)
+	}
+	if id.Role != RoleCrew || id.Rig != "gastown" || id.Name != "max" {
+		t.Errorf("ParseSessionName = %+v, want crew gastown/max", id)
+	}
+	if id.SessionName() != "alpha-gt-crew-max" {
+		t.Errorf("round trip = %q", id.SessionName())
+	}
+
+	// Another town's (unprefixed) sessions are not ours.
+	if _, err := ParseSessionName("gt-witness"); err == nil {
+		t.Error("ParseSessionName(gt-witness) should fail with a town prefix set")
+	}
+	if IsKnownSession("gt-witness") {
+		t.Error("IsKnownSession(gt-witness) = true with a town prefix set")
+	}
+	if !IsKnownSession("alpha-gt-witness") {
+		t.Error("IsKnownSession(alpha-gt-witness) = false")
+	}
+}
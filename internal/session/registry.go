@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strings"
 	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // PrefixRegistry maps beads prefixes to rig names and vice versa.
@@ -95,10 +97,15 @@ func SetDefaultRegistry(r *PrefixRegistry) {
 	defaultRegistry = r
 }
 
-// InitRegistry populates the default registry from the town's rigs.json.
+// InitRegistry populates the default registry from the town's rigs.json and
+// applies the town session prefix from settings/config.json.
 // Should be called early in the process lifecycle.
 // Safe to call multiple times; later calls replace earlier data.
 func InitRegistry(townRoot string) error {
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		SetTownPrefix(settings.SessionPrefix)
+	}
+
 	r, err := BuildPrefixRegistryFromTown(townRoot)
 	if err != nil {
 		return err
@@ -177,7 +184,11 @@ func IsKnownSession(sess string) bool {
 	if strings.HasPrefix(sess, HQPrefix) {
 		return true
 	}
-	return defaultRegistry.HasPrefix(sess)
+	rigSess, ok := stripTownPrefix(sess)
+	if !ok {
+		return false
+	}
+	return defaultRegistry.HasPrefix(rigSess)
 }
 
 // matchPrefix finds the prefix in a session name suffix using the registry.
@@ -84,11 +84,17 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 	beacon := fmt.Sprintf("[GAS TOWN] %s <- %s • %s • %s",
 		cfg.Recipient, cfg.Sender, timestamp, topic)
 
+	// An assignment names its bead so gt prime can brief on it.
+	issueFlag := ""
+	if cfg.Topic == "assigned" && cfg.MolID != "" {
+		issueFlag = " --issue " + cfg.MolID
+	}
+
 	// For non-hook agents, add "Run gt prime" instruction since there's no
 	// SessionStart hook to do it automatically. Work instructions will
 	// come as a separate nudge after gt prime completes.
 	if cfg.IncludePrimeInstruction {
-		beacon += "\n\nRun `" + cli.Name() + " prime` to initialize your context."
+		beacon += "\n\nRun `" + cli.Name() + " prime" + issueFlag + "` to initialize your context."
 		// Don't add work instructions here - they come as a delayed nudge after gt prime
 		return beacon
 	}
@@ -108,7 +114,7 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 	// Matches refinery pattern: short instruction with prime before action.
 	// Exclude work instructions only if explicitly set (non-hook agents get them via delayed nudge)
 	if cfg.Topic == "assigned" && !cfg.ExcludeWorkInstructions {
-		beacon += "\n\nRun `" + cli.Name() + " prime --hook` and begin work on your hook."
+		beacon += "\n\nRun `" + cli.Name() + " prime --hook" + issueFlag + "` and begin work on your hook."
 	}
 
 	return beacon
@@ -236,7 +236,7 @@ func TestFormatStartupBeacon(t *testing.T) {
 				"crew gus (rig: gastown)",
 				"<- deacon",
 				"assigned:gt-abc12",
-				"gt prime --hook",
+				"gt prime --hook --issue gt-abc12",
 				"begin work",
 			},
 			wantNot: []string{
@@ -179,6 +179,13 @@ func commonChars(a, b string) int {
 	return common
 }
 
+// Distance returns the edit distance between a and b: the fewest single
+// character insertions, deletions and substitutions turning one into the
+// other.
+func Distance(a, b string) int {
+	return levenshteinDistance(a, b)
+}
+
 // levenshteinDistance calculates the edit distance between two strings.
 func levenshteinDistance(a, b string) int {
 	if len(a) == 0 {
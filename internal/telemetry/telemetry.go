@@ -0,0 +1,210 @@
+// Package telemetry traces gt with OpenTelemetry. Each gt command is a
+// span; bd calls, Dolt SQL, merges, and polecat spawns inside it are child
+// spans, so a slow command can be broken down by where its time went.
+//
+// Tracing is off unless the town's settings name an OTLP endpoint (or the
+// standard OTEL_EXPORTER_OTLP_ENDPOINT variable is set); until then every
+// span is a no-op. A command's trace context is passed to the gt commands
+// it runs as TRACEPARENT (see WithTraceparent), so they join the same trace.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies gt's spans to the tracing backend.
+const instrumentationName = "github.com/steveyegge/gastown"
+
+// traceparentEnv carries the W3C trace context to child processes.
+const traceparentEnv = "TRACEPARENT"
+
+// shutdownTimeout bounds the final export when a command exits.
+const shutdownTimeout = 5 * time.Second
+
+var (
+	mu sync.Mutex
+
+	// root is the parent of spans started from a context without one: the
+	// span of the gt command being run. Most of gt doesn't pass contexts.
+	root trace.Span
+
+	// traceparent is root's W3C trace context, for child gt processes.
+	traceparent string
+)
+
+// Setup installs the OTLP exporter if tracing is configured for townRoot
+// (which may be empty outside a town) and returns a function that flushes
+// pending spans and shuts the exporter down. When tracing isn't configured
+// it installs nothing and the returned function does nothing.
+func Setup(townRoot, service, version string) (shutdown func()) {
+	cfg := loadConfig(townRoot)
+	if !enabled(cfg) {
+		return func() {}
+	}
+
+	opts := []otlptracehttp.Option{}
+	if cfg != nil && cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(tracesURL(cfg.Endpoint)))
+	}
+	if cfg != nil && len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: tracing disabled: %v\n", err)
+		return func() {}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio(cfg)))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(service),
+			semconv.ServiceVersion(version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	// Export failures are the collector's problem, not the command's.
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = provider.Shutdown(ctx)
+	}
+}
+
+// tracesURL adds the standard /v1/traces path to a collector URL given
+// without one; the exporter otherwise posts to the URL as is.
+func tracesURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || strings.Trim(u.Path, "/") != "" {
+		return endpoint
+	}
+	u.Path = "/v1/traces"
+	return u.String()
+}
+
+func loadConfig(townRoot string) *config.TelemetryConfig {
+	if townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.Telemetry
+}
+
+// enabled reports whether spans should be exported: an endpoint is
+// configured in town settings or in the standard environment variables.
+func enabled(cfg *config.TelemetryConfig) bool {
+	if cfg != nil && cfg.Endpoint != "" {
+		return true
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+func sampleRatio(cfg *config.TelemetryConfig) float64 {
+	if cfg == nil || cfg.SampleRatio == nil {
+		return 1
+	}
+	return min(max(*cfg.SampleRatio, 0), 1)
+}
+
+// StartCommand starts the span for a gt command. It continues the trace of
+// a parent gt process (from TRACEPARENT) and becomes the parent of spans
+// started without one and, via WithTraceparent, of child gt processes.
+func StartCommand(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if tp := os.Getenv(traceparentEnv); tp != "" {
+		ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": tp})
+	}
+	ctx, span := tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	if !span.SpanContext().IsValid() {
+		return ctx, span
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	mu.Lock()
+	root = span
+	traceparent = carrier.Get("traceparent")
+	mu.Unlock()
+	return ctx, span
+}
+
+// Detach stops the command span from parenting spans started without one.
+// Long-running commands such as the daemon call it so each unit of their
+// work is traced on its own rather than under a command span lasting days.
+func Detach() {
+	mu.Lock()
+	root = nil
+	traceparent = ""
+	mu.Unlock()
+	_ = os.Unsetenv(traceparentEnv)
+}
+
+// WithTraceparent returns env (the process environment when nil) with
+// TRACEPARENT set to the command span's context, for a child gt process
+// that should join the trace. Without a command span env is returned
+// unchanged. Only gt children get it: agent sessions and other tools
+// started by gt must not inherit a trace that ends when this command does.
+func WithTraceparent(env []string) []string {
+	mu.Lock()
+	tp := traceparent
+	mu.Unlock()
+	if tp == "" {
+		return env
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	return append(env, traceparentEnv+"="+tp)
+}
+
+// Start starts a span as a child of the span in ctx or, when ctx has
+// none, of the command span. Callers without a context pass
+// context.Background().
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		mu.Lock()
+		parent := root
+		mu.Unlock()
+		if parent != nil {
+			ctx = trace.ContextWithSpan(ctx, parent)
+		}
+	}
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, as the span's status and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,161 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useRecorder installs a tracer provider that records spans in memory.
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		Detach()
+	})
+	return rec
+}
+
+func TestStart_ParentsToCommandSpan(t *testing.T) {
+	rec := useRecorder(t)
+	t.Setenv(traceparentEnv, "")
+
+	_, cmd := StartCommand(context.Background(), "gt sling")
+	_, child := Start(context.Background(), "bd show")
+	End(child, nil)
+	End(cmd, nil)
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	bd, gt := spans[0], spans[1]
+	if bd.Parent().SpanID() != gt.SpanContext().SpanID() {
+		t.Errorf("bd span parent = %s, want the command span %s", bd.Parent().SpanID(), gt.SpanContext().SpanID())
+	}
+
+	// The process environment is left alone; child gt processes get the
+	// command's trace context through WithTraceparent.
+	if got := os.Getenv(traceparentEnv); got != "" {
+		t.Errorf("TRACEPARENT set in the process environment: %q", got)
+	}
+	env := WithTraceparent([]string{"HOME=/tmp"})
+	tp, ok := strings.CutPrefix(env[len(env)-1], traceparentEnv+"=")
+	if !ok || len(env) != 2 {
+		t.Fatalf("WithTraceparent env = %v, want HOME plus TRACEPARENT", env)
+	}
+	var carrier propagation.MapCarrier = map[string]string{"traceparent": tp}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	_, next := StartCommand(ctx, "gt hook")
+	End(next, nil)
+	if got := rec.Ended()[2].Parent().SpanID(); got != gt.SpanContext().SpanID() {
+		t.Errorf("child process span parent = %s, want %s", got, gt.SpanContext().SpanID())
+	}
+}
+
+func TestStartCommand_ContinuesTraceparent(t *testing.T) {
+	rec := useRecorder(t)
+	t.Setenv(traceparentEnv, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	_, span := StartCommand(context.Background(), "gt prime")
+	End(span, nil)
+
+	got := rec.Ended()[0]
+	if got.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the parent's", got.SpanContext().TraceID())
+	}
+	if got.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s", got.Parent().SpanID())
+	}
+}
+
+func TestDetach(t *testing.T) {
+	rec := useRecorder(t)
+	t.Setenv(traceparentEnv, "")
+
+	_, cmd := StartCommand(context.Background(), "gt daemon run")
+	Detach()
+	if os.Getenv(traceparentEnv) != "" {
+		t.Error("TRACEPARENT still set after Detach")
+	}
+	if env := WithTraceparent([]string{}); len(env) != 0 {
+		t.Errorf("WithTraceparent after Detach = %v, want no TRACEPARENT", env)
+	}
+	_, child := Start(context.Background(), "dolt.sql")
+	End(child, nil)
+	End(cmd, nil)
+
+	if rec.Ended()[0].Parent().IsValid() {
+		t.Error("span started after Detach has a parent")
+	}
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	rec := useRecorder(t)
+
+	_, span := Start(context.Background(), "refinery.merge")
+	End(span, errors.New("conflict"))
+
+	got := rec.Ended()[0]
+	if got.Status().Code != codes.Error || got.Status().Description != "conflict" {
+		t.Errorf("status = %+v, want error %q", got.Status(), "conflict")
+	}
+	if len(got.Events()) != 1 {
+		t.Errorf("events = %v, want the recorded error", got.Events())
+	}
+}
+
+func TestEnabledAndSampleRatio(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	if enabled(nil) || enabled(&config.TelemetryConfig{}) {
+		t.Error("enabled without an endpoint")
+	}
+	if !enabled(&config.TelemetryConfig{Endpoint: "http://localhost:4318"}) {
+		t.Error("not enabled with a configured endpoint")
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	if !enabled(nil) {
+		t.Error("not enabled with OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+
+	ratio := func(v float64) *config.TelemetryConfig { return &config.TelemetryConfig{SampleRatio: &v} }
+	for _, tt := range []struct {
+		cfg  *config.TelemetryConfig
+		want float64
+	}{
+		{nil, 1},
+		{&config.TelemetryConfig{}, 1},
+		{ratio(0.25), 0.25},
+		{ratio(-1), 0},
+		{ratio(3), 1},
+	} {
+		if got := sampleRatio(tt.cfg); got != tt.want {
+			t.Errorf("sampleRatio(%+v) = %v, want %v", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestTracesURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"http://localhost:4318":                  "http://localhost:4318/v1/traces",
+		"http://localhost:4318/":                 "http://localhost:4318/v1/traces",
+		"https://otlp.example.com/api/v1/traces": "https://otlp.example.com/api/v1/traces",
+	} {
+		if got := tracesURL(in); got != want {
+			t.Errorf("tracesURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 func killProcessGroup(pgid int) {
@@ -18,7 +20,7 @@ func killProcessGroup(pgid int) {
 // getParentPID returns the parent process ID (PPID) for a given PID.
 // Returns empty string if the process doesn't exist or PPID can't be determined.
 func getParentPID(pid string) string {
-	out, err := exec.Command("ps", "-o", "ppid=", "-p", pid).Output()
+	out, err := util.Output(exec.Command("ps", "-o", "ppid=", "-p", pid), util.ProbeTimeout)
 	if err != nil {
 		return ""
 	}
@@ -28,7 +30,7 @@ func getParentPID(pid string) string {
 // getProcessGroupID returns the process group ID (PGID) for a given PID.
 // Returns empty string if the process doesn't exist or PGID can't be determined.
 func getProcessGroupID(pid string) string {
-	out, err := exec.Command("ps", "-o", "pgid=", "-p", pid).Output()
+	out, err := util.Output(exec.Command("ps", "-o", "pgid=", "-p", pid), util.ProbeTimeout)
 	if err != nil {
 		return ""
 	}
@@ -41,7 +43,7 @@ func getProcessGroupMembers(pgid string) []string {
 	// Use ps to find all processes with this PGID
 	// On macOS: ps -axo pid,pgid
 	// On Linux: ps -eo pid,pgid
-	out, err := exec.Command("ps", "-axo", "pid,pgid").Output()
+	out, err := util.Output(exec.Command("ps", "-axo", "pid,pgid"), util.ProbeTimeout)
 	if err != nil {
 		return nil
 	}
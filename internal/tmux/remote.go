@@ -0,0 +1,83 @@
+package tmux
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// NewRemoteTmux creates a Tmux wrapper that drives the tmux server on another
+// machine. sshArgs is the ssh argv up to and including the destination (see
+// config.RemoteConfig.SSHArgs); each tmux invocation runs as one remote
+// command. Process inspection and cleanup run remotely too.
+func NewRemoteTmux(sshArgs []string) *Tmux {
+	return &Tmux{remote: append([]string(nil), sshArgs...)}
+}
+
+// IsRemote reports whether this wrapper drives a tmux server over SSH.
+func (t *Tmux) IsRemote() bool {
+	return len(t.remote) > 0
+}
+
+// command builds an invocation of name with args, on the remote machine for
+// remote wrappers.
+func (t *Tmux) command(name string, args ...string) *exec.Cmd {
+	if !t.IsRemote() {
+		return exec.Command(name, args...)
+	}
+	sshArgs := append(append([]string(nil), t.remote[1:]...), config.RemoteShellCommand("", append([]string{name}, args...)...))
+	return exec.Command(t.remote[0], sshArgs...) //nolint:gosec // G204: ssh argv comes from rig config
+}
+
+// remoteProcessTreeHasNames reports whether pid or any of its descendants on
+// the remote machine has a command name in names. One ps call covers the tree.
+func (t *Tmux) remoteProcessTreeHasNames(pid string, names []string) bool {
+	out, err := util.Output(t.command("ps", "-axo", "pid=,ppid=,comm="), util.ProbeTimeout)
+	if err != nil {
+		return false
+	}
+	return processTreeHasNames(string(out), pid, names)
+}
+
+// processTreeHasNames walks `ps -axo pid=,ppid=,comm=` output from root.
+func processTreeHasNames(psOutput, root string, names []string) bool {
+	nameSet := make(map[string]bool, len(names))
+	for _, n := range names {
+		nameSet[n] = true
+	}
+	children := make(map[string][]string)
+	comm := make(map[string]string)
+	for _, line := range strings.Split(psOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, ppid := fields[0], fields[1]
+		comm[pid] = filepath.Base(strings.Join(fields[2:], " "))
+		children[ppid] = append(children[ppid], pid)
+	}
+	seen := map[string]bool{}
+	queue := []string{root}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		if nameSet[comm[pid]] {
+			return true
+		}
+		queue = append(queue, children[pid]...)
+	}
+	return false
+}
+
+// remoteKillTree terminates pid and its children on the remote machine.
+func (t *Tmux) remoteKillTree(pid string) {
+	_ = t.command("pkill", "-TERM", "-P", pid).Run()
+	_ = t.command("kill", "-TERM", pid).Run()
+}
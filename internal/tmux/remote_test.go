@@ -0,0 +1,37 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemoteTmuxCommand(t *testing.T) {
+	tm := NewRemoteTmux([]string{"ssh", "-o", "BatchMode=yes", "build1"})
+	if !tm.IsRemote() || NewTmux().IsRemote() {
+		t.Fatal("IsRemote mismatch")
+	}
+	cmd := tm.command("tmux", "has-session", "-t", "gt-witness")
+	got := strings.Join(cmd.Args, " ")
+	want := "ssh -o BatchMode=yes build1 tmux has-session -t gt-witness"
+	if got != want {
+		t.Errorf("command args = %q, want %q", got, want)
+	}
+}
+
+func TestProcessTreeHasNames(t *testing.T) {
+	ps := `    1     0 init
+  100     1 tmux
+  200   100 zsh
+  300   200 /usr/local/bin/claude
+  400     1 node
+`
+	if !processTreeHasNames(ps, "200", []string{"claude"}) {
+		t.Error("expected claude under 200")
+	}
+	if processTreeHasNames(ps, "200", []string{"node"}) {
+		t.Error("node is not a descendant of 200")
+	}
+	if processTreeHasNames(ps, "999", []string{"claude"}) {
+		t.Error("unknown root should not match")
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // sessionNudgeLocks serializes nudges to the same session.
@@ -55,7 +56,9 @@ func validateSessionName(name string) error {
 }
 
 // Tmux wraps tmux operations.
-type Tmux struct{}
+type Tmux struct {
+	remote []string // ssh argv for a tmux server on another machine; see NewRemoteTmux
+}
 
 // NewTmux creates a new Tmux wrapper.
 func NewTmux() *Tmux {
@@ -68,12 +71,16 @@ func NewTmux() *Tmux {
 func (t *Tmux) run(args ...string) (string, error) {
 	// Prepend -u flag for UTF-8 mode (PATCH-004)
 	allArgs := append([]string{"-u"}, args...)
-	cmd := exec.Command("tmux", allArgs...)
+	cmd := t.command("tmux", allArgs...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	timeout := util.TmuxTimeout
+	if len(args) > 0 && args[0] == "attach-session" {
+		timeout = 0 // interactive: runs until the user detaches
+	}
+	err := util.Run(cmd, timeout)
 	if err != nil {
 		return "", t.wrapError(err, stderr.String(), args)
 	}
@@ -85,6 +92,10 @@ func (t *Tmux) run(args ...string) (string, error) {
 func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 	stderr = strings.TrimSpace(stderr)
 
+	if util.IsTimeout(err) {
+		return err
+	}
+
 	// Detect specific error types
 	if strings.Contains(stderr, "no server running") ||
 		strings.Contains(stderr, "error connecting to") ||
@@ -247,6 +258,11 @@ const processKillGracePeriod = 2 * time.Second
 //
 // This ensures Claude processes and all their children are properly terminated.
 func (t *Tmux) KillSessionWithProcesses(name string) error {
+	if t.IsRemote() {
+		// The remote tmux server hangs up the pane's process tree.
+		return t.KillSession(name)
+	}
+
 	// Get the pane PID
 	pid, err := t.GetPanePID(name)
 	if err != nil {
@@ -315,6 +331,10 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 // the calling process (e.g., gt done) is running inside the session it's terminating.
 // Without exclusion, the caller would be killed before completing the cleanup.
 func (t *Tmux) KillSessionWithProcessesExcluding(name string, excludePIDs []string) error {
+	if t.IsRemote() {
+		return t.KillSession(name)
+	}
+
 	// Build exclusion set for O(1) lookup
 	exclude := make(map[string]bool)
 	for _, pid := range excludePIDs {
@@ -434,7 +454,7 @@ func getAllDescendants(pid string) []string {
 	var result []string
 
 	// Get direct children using pgrep
-	out, err := exec.Command("pgrep", "-P", pid).Output()
+	out, err := util.Output(exec.Command("pgrep", "-P", pid), util.ProbeTimeout)
 	if err != nil {
 		return result
 	}
@@ -473,6 +493,11 @@ func (t *Tmux) KillPaneProcesses(pane string) error {
 		return fmt.Errorf("pane PID is empty")
 	}
 
+	if t.IsRemote() {
+		t.remoteKillTree(pid)
+		return nil
+	}
+
 	// Walk the process tree for all descendants (catches processes that
 	// called setsid() and created their own process groups)
 	descendants := getAllDescendants(pid)
@@ -604,7 +629,7 @@ func (t *Tmux) SetExitEmpty(on bool) error {
 // IsAvailable checks if tmux is installed and can be invoked.
 func (t *Tmux) IsAvailable() bool {
 	cmd := exec.Command("tmux", "-V")
-	return cmd.Run() == nil
+	return util.Run(cmd, util.TmuxTimeout) == nil
 }
 
 // HasSession checks if a session exists (exact match).
@@ -1177,6 +1202,20 @@ func (t *Tmux) GetPaneID(session string) (string, error) {
 	return result, nil
 }
 
+// GetPaneSessionName returns the name of the session pane (e.g. "%3")
+// belongs to, as tmux reports it.
+func (t *Tmux) GetPaneSessionName(pane string) (string, error) {
+	out, err := t.run("display-message", "-t", pane, "-p", "#{session_name}")
+	if err != nil {
+		return "", err
+	}
+	result := strings.TrimSpace(out)
+	if result == "" {
+		return "", fmt.Errorf("no session found for pane %s", pane)
+	}
+	return result, nil
+}
+
 // GetPaneWorkDir returns the current working directory of a pane.
 // Targets pane 0 explicitly to avoid returning the active pane's
 // working directory in multi-pane sessions.
@@ -1310,7 +1349,7 @@ func processMatchesNames(pid string, names []string) bool {
 	}
 	// Use ps to get the command name (COMM column gives the executable name)
 	cmd := exec.Command("ps", "-p", pid, "-o", "comm=")
-	out, err := cmd.Output()
+	out, err := util.Output(cmd, util.ProbeTimeout)
 	if err != nil {
 		return false
 	}
@@ -1337,7 +1376,7 @@ func hasDescendantWithNames(pid string, names []string, depth int) bool {
 	}
 	// Use pgrep to find child processes
 	cmd := exec.Command("pgrep", "-P", pid, "-l")
-	out, err := cmd.Output()
+	out, err := util.Output(cmd, util.ProbeTimeout)
 	if err != nil {
 		return false
 	}
@@ -1448,6 +1487,40 @@ func (t *Tmux) SetEnvironment(session, key, value string) error {
 	return err
 }
 
+// secretsFileScript creates a private temp file on the tmux host, fills it
+// from stdin and prints its path.
+const secretsFileScript = `umask 077 && f=$(mktemp "${TMPDIR:-/tmp}/gt-secrets.XXXXXX") && cat > "$f" && echo "$f"`
+
+// WithSecrets returns command prefixed to export secrets from a 0600 env
+// file on the tmux host and delete it before command runs. The values are
+// written to the file over stdin, so they never appear in an argv: not in
+// tmux's, not in ps output, not on the ssh command line of a remote rig.
+// Each call writes a fresh file, which the command consumes once; if the
+// command is never run, the file is left behind, readable only by its owner.
+func (t *Tmux) WithSecrets(command string, secrets map[string]string) (string, error) {
+	if len(secrets) == 0 {
+		return command, nil
+	}
+	keys := make([]string, 0, len(secrets))
+	for k := range secrets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var env strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&env, "export %s=%s\n", k, config.ShellQuote(secrets[k]))
+	}
+
+	cmd := t.command("sh", "-c", secretsFileScript)
+	cmd.Stdin = strings.NewReader(env.String())
+	out, err := util.Output(cmd, util.TmuxTimeout)
+	if err != nil {
+		return "", fmt.Errorf("writing secrets file: %w", err)
+	}
+	path := config.ShellQuote(strings.TrimSpace(string(out)))
+	return ". " + path + " && rm -f " + path + " && " + command, nil
+}
+
 // GetEnvironment gets an environment variable from the session.
 func (t *Tmux) GetEnvironment(session, key string) (string, error) {
 	out, err := t.run("show-environment", "-t", session, key)
@@ -1593,6 +1666,9 @@ func (t *Tmux) IsRuntimeRunning(session string, processNames []string) bool {
 	if err != nil || pid == "" {
 		return false
 	}
+	if t.IsRemote() {
+		return t.remoteProcessTreeHasNames(pid, processNames)
+	}
 	// If pane command is a shell, check descendants
 	for _, shell := range constants.SupportedShells {
 		if cmd == shell {
@@ -2296,7 +2372,7 @@ func CurrentSessionName() string {
 	}
 	// TMUX format: /path/to/socket,server_pid,session_index
 	// We can use display-message to get the session name directly
-	out, err := exec.Command("tmux", "display-message", "-p", "#{session_name}").Output()
+	out, err := util.Output(exec.Command("tmux", "display-message", "-p", "#{session_name}"), util.TmuxTimeout)
 	if err != nil {
 		return ""
 	}
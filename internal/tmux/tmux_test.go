@@ -1617,6 +1617,45 @@ func TestNewSessionWithCommandAndEnv(t *testing.T) {
 	}
 }
 
+func TestWithSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+	tm := NewTmux()
+
+	secrets := map[string]string{"GT_TEST_TOKEN": "s3cr'et value"}
+	cmd, err := tm.WithSecrets(`printf %s "$GT_TEST_TOKEN"`, secrets)
+	if err != nil {
+		t.Fatalf("WithSecrets: %v", err)
+	}
+	if strings.Contains(cmd, "s3cr") {
+		t.Fatalf("secret value in command: %q", cmd)
+	}
+	files, _ := os.ReadDir(os.Getenv("TMPDIR"))
+	if len(files) != 1 {
+		t.Fatalf("want one secrets file, got %d", len(files))
+	}
+	if info, _ := files[0].Info(); info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	out, err := exec.Command("sh", "-c", cmd).Output()
+	if err != nil {
+		t.Fatalf("running command: %v", err)
+	}
+	if string(out) != "s3cr'et value" {
+		t.Errorf("command saw %q, want the secret", out)
+	}
+	if files, _ := os.ReadDir(os.Getenv("TMPDIR")); len(files) != 0 {
+		t.Errorf("secrets file not removed after use")
+	}
+
+	if cmd, err := tm.WithSecrets("true", nil); err != nil || cmd != "true" {
+		t.Errorf("WithSecrets(nil) = %q, %v; want the command unchanged", cmd, err)
+	}
+}
+
 func TestNewSessionWithCommandAndEnvEmpty(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
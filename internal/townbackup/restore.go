@@ -0,0 +1,212 @@
+package townbackup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// RestoreOptions configures Restore.
+type RestoreOptions struct {
+	// SkipClones restores files and databases only, leaving every rig
+	// without its clones.
+	SkipClones bool
+}
+
+// RestoreResult describes a completed restore.
+type RestoreResult struct {
+	Manifest *Manifest
+
+	// HomeSkipped lists ~/.gt files that already existed and were kept.
+	HomeSkipped []string
+
+	// Warnings are per-rig failures. The rest of the town was restored.
+	Warnings []string
+}
+
+// Restore rebuilds a town at townRoot, which must be empty or absent, from
+// an archive written by Backup. Files and databases are restored first; a
+// rig whose clones can't be recreated is reported in Warnings rather than
+// failing the restore.
+func Restore(r io.Reader, townRoot string, opts RestoreOptions) (*RestoreResult, error) {
+	if entries, err := os.ReadDir(townRoot); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", townRoot)
+	} else if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", townRoot, err)
+	}
+	if err := os.MkdirAll(townRoot, 0755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", townRoot, err)
+	}
+
+	staging, err := os.MkdirTemp("", "gt-town-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	result, err := extract(r, townRoot, staging)
+	if err != nil {
+		return nil, err
+	}
+	manifest := result.Manifest
+
+	for _, db := range manifest.Databases {
+		if err := restoreDatabase(townRoot, db, filepath.Join(staging, db)); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.SkipClones {
+		return result, nil
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading restored rigs config: %w", err)
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	for _, rc := range manifest.Rigs {
+		if rc.Remote {
+			continue
+		}
+		if err := recreateRig(mgr, townRoot, rc); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", rc.Name, err))
+		}
+	}
+	return result, nil
+}
+
+// recreateRig clones a restored rig's repositories and crew workers again.
+func recreateRig(mgr *rig.Manager, townRoot string, rc RigClones) error {
+	r, err := mgr.GetRig(rc.Name)
+	if err != nil {
+		return fmt.Errorf("loading rig: %w", err)
+	}
+	if err := mgr.RecreateClones(r); err != nil {
+		return err
+	}
+	crewMgr := crew.NewManager(r, git.NewGit(r.Path))
+	for _, c := range rc.Crew {
+		if _, err := crewMgr.Add(c.Name, c.Branch == "crew/"+c.Name); err != nil {
+			return fmt.Errorf("recreating crew %s: %w", c.Name, err)
+		}
+	}
+	if err := doltserver.EnsureMetadata(townRoot, rc.Name); err != nil {
+		return fmt.Errorf("updating beads metadata: %w", err)
+	}
+	return nil
+}
+
+// extract unpacks the archive into townRoot, the database backups into
+// staging, and any ~/.gt files that don't already exist.
+func extract(r io.Reader, townRoot, staging string) (*RestoreResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, fmt.Errorf("not a town backup: %s is missing", manifestName)
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than this gt supports (%d)", manifest.Version, ManifestVersion)
+	}
+	result := &RestoreResult{Manifest: &manifest}
+
+	home := gtHome()
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+
+		var root, rel string
+		switch {
+		case strings.HasPrefix(hdr.Name, townPrefix):
+			root, rel = townRoot, strings.TrimPrefix(hdr.Name, townPrefix)
+		case strings.HasPrefix(hdr.Name, doltPrefix):
+			root, rel = staging, strings.TrimPrefix(hdr.Name, doltPrefix)
+		case strings.HasPrefix(hdr.Name, homePrefix):
+			root, rel = home, strings.TrimPrefix(hdr.Name, homePrefix)
+			if _, err := os.Lstat(filepath.Join(home, filepath.FromSlash(rel))); err == nil {
+				result.HomeSkipped = append(result.HomeSkipped, rel)
+				continue
+			}
+		default:
+			return nil, fmt.Errorf("unexpected archive entry %q", hdr.Name)
+		}
+		if err := writeEntry(tr, hdr, root, rel); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// writeEntry writes a file or symlink from the archive to root/rel, refusing
+// paths that would land outside root.
+func writeEntry(tr *tar.Reader, hdr *tar.Header, root, rel string) error {
+	rel = filepath.FromSlash(rel)
+	if !filepath.IsLocal(rel) {
+		return fmt.Errorf("unsafe archive entry %q", hdr.Name)
+	}
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("restoring %s: %w", rel, err)
+	}
+	// A symlinked parent directory would let a later entry escape root.
+	parent, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", rel, err)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", rel, err)
+	}
+	if inside, err := filepath.Rel(realRoot, parent); err != nil || !filepath.IsLocal(inside) {
+		return fmt.Errorf("unsafe archive entry %q", hdr.Name)
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeSymlink:
+		if err := os.Symlink(hdr.Linkname, path); err != nil {
+			return fmt.Errorf("restoring %s: %w", rel, err)
+		}
+	case tar.TypeReg:
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, hdr.FileInfo().Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("restoring %s: %w", rel, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("restoring %s: %w", rel, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("restoring %s: %w", rel, err)
+		}
+		_ = os.Chtimes(path, hdr.ModTime, hdr.ModTime)
+	default:
+		return fmt.Errorf("unsupported archive entry %q", hdr.Name)
+	}
+	return nil
+}
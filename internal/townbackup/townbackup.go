@@ -0,0 +1,396 @@
+// Package townbackup snapshots everything needed to rebuild a town, except
+// its repository clones, into a single archive, and restores a town from one.
+//
+// The archive is a gzipped tar. manifest.json comes first, followed by the
+// town's files under town/, one Dolt backup per database under dolt/<db>/,
+// and the user's ~/.gt hooks config under home/.gt/. Clones are not stored:
+// the manifest records each rig's git URL and crew workers, and Restore
+// clones them again.
+package townbackup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/progress"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// ManifestVersion is the current archive format version.
+const ManifestVersion = 1
+
+// Archive member names and prefixes.
+const (
+	manifestName = "manifest.json"
+	townPrefix   = "town/"
+	doltPrefix   = "dolt/"
+	homePrefix   = "home/.gt/"
+)
+
+// Manifest describes a town backup.
+type Manifest struct {
+	// Version is the archive format version (ManifestVersion).
+	Version int `json:"version"`
+
+	// CreatedAt is when the backup was taken.
+	CreatedAt time.Time `json:"created_at"`
+
+	// GTVersion is the version of gt that wrote the backup.
+	GTVersion string `json:"gt_version,omitempty"`
+
+	// TownRoot is the town's path when it was backed up.
+	TownRoot string `json:"town_root"`
+
+	// Files is the number of town files in the archive.
+	Files int `json:"files"`
+
+	// Databases are the Dolt databases in the archive.
+	Databases []string `json:"databases,omitempty"`
+
+	// HomeFiles are the ~/.gt files in the archive, relative to ~/.gt.
+	HomeFiles []string `json:"home_files,omitempty"`
+
+	// Rigs are the clones to recreate, one entry per registered rig.
+	Rigs []RigClones `json:"rigs,omitempty"`
+}
+
+// RigClones records what's needed to clone a rig's repositories again.
+type RigClones struct {
+	Name          string      `json:"name"`
+	GitURL        string      `json:"git_url"`
+	PushURL       string      `json:"push_url,omitempty"`
+	DefaultBranch string      `json:"default_branch"`
+	Crew          []CrewClone `json:"crew,omitempty"`
+
+	// Remote rigs keep their clones on another machine, so restore leaves
+	// them alone.
+	Remote bool `json:"remote,omitempty"`
+}
+
+// CrewClone is a crew worker's clone and the branch it had checked out.
+type CrewClone struct {
+	Name   string `json:"name"`
+	Branch string `json:"branch,omitempty"`
+}
+
+// BackupOptions configures Backup.
+type BackupOptions struct {
+	// GTVersion is recorded in the manifest.
+	GTVersion string
+
+	// Exclude lists absolute paths inside the town to leave out, such as
+	// the archive being written.
+	Exclude []string
+}
+
+// backupDatabase and restoreDatabase are variables so tests can run without
+// dolt installed.
+var (
+	backupDatabase  = doltserver.BackupDatabase
+	restoreDatabase = doltserver.RestoreDatabase
+)
+
+// Backup writes an archive of townRoot to w and returns its manifest.
+// Repository clones, polecat worktrees, and .dolt-data are left out; the
+// databases are captured with Dolt backups instead, so the server may be
+// running.
+func Backup(townRoot string, w io.Writer, opts BackupOptions) (*Manifest, error) {
+	if doltserver.DefaultConfig(townRoot).IsRemote() {
+		return nil, fmt.Errorf("Dolt server is remote — back up its databases on the server's machine")
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	manifest := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now().UTC(),
+		GTVersion: opts.GTVersion,
+		TownRoot:  townRoot,
+	}
+	rigs, err := rigClones(townRoot, rigsConfig)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Rigs = rigs
+
+	files, err := townFiles(townRoot, rigsConfig, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Files = len(files)
+	homeFiles := gtHomeFiles()
+	manifest.HomeFiles = homeFiles
+
+	staging, err := os.MkdirTemp("", "gt-town-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	databases, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	sort.Strings(databases)
+	for _, db := range databases {
+		if err := backupDatabase(townRoot, db, filepath.Join(staging, db)); err != nil {
+			return nil, err
+		}
+	}
+	manifest.Databases = databases
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+
+	for _, rel := range files {
+		if err := addFile(tw, filepath.Join(townRoot, rel), townPrefix+filepath.ToSlash(rel)); err != nil {
+			return nil, err
+		}
+	}
+	for _, db := range databases {
+		dbDir := filepath.Join(staging, db)
+		rels, err := walkFiles(dbDir, nil)
+		if err != nil {
+			return nil, fmt.Errorf("reading backup of %s: %w", db, err)
+		}
+		for _, rel := range rels {
+			if err := addFile(tw, filepath.Join(dbDir, rel), doltPrefix+db+"/"+filepath.ToSlash(rel)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	home := gtHome()
+	for _, rel := range homeFiles {
+		if err := addFile(tw, filepath.Join(home, rel), homePrefix+filepath.ToSlash(rel)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("finishing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("finishing archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// Estimate returns the bytes of town files Backup would archive and the
+// bytes of Dolt data it would back up into its staging directory, for
+// checking free space before a backup. The archive is compressed, so it
+// needs at most their sum.
+func Estimate(townRoot string, exclude []string) (files, databases int64, err error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return 0, 0, fmt.Errorf("loading rigs config: %w", err)
+	}
+	rels, err := townFiles(townRoot, rigsConfig, exclude)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, rel := range rels {
+		if info, err := os.Lstat(filepath.Join(townRoot, rel)); err == nil {
+			files += info.Size()
+		}
+	}
+	return files, progress.DirSize(doltserver.DefaultConfig(townRoot).DataDir), nil
+}
+
+// rigClones lists each registered rig's clone sources, sorted by name.
+func rigClones(townRoot string, rigsConfig *config.RigsConfig) ([]RigClones, error) {
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	names := mgr.ListRigNames()
+	sort.Strings(names)
+
+	var rigs []RigClones
+	for _, name := range names {
+		entry := rigsConfig.Rigs[name]
+		rc := RigClones{
+			Name:    name,
+			GitURL:  entry.GitURL,
+			PushURL: strings.TrimSpace(entry.PushURL),
+			Remote:  entry.Remote != nil,
+		}
+		r, err := mgr.GetRig(name)
+		if err != nil {
+			// Registered but missing on disk: nothing to back up beyond
+			// the rigs.json entry.
+			rigs = append(rigs, rc)
+			continue
+		}
+		rc.DefaultBranch = r.DefaultBranch()
+
+		workers, err := crew.NewManager(r, git.NewGit(r.Path)).List()
+		if err != nil {
+			return nil, fmt.Errorf("listing crew for %s: %w", name, err)
+		}
+		for _, w := range workers {
+			if !isClone(w.ClonePath) {
+				continue
+			}
+			rc.Crew = append(rc.Crew, CrewClone{Name: w.Name, Branch: w.Branch})
+		}
+		rigs = append(rigs, rc)
+	}
+	return rigs, nil
+}
+
+// townFiles lists the files to archive, relative to townRoot.
+func townFiles(townRoot string, rigsConfig *config.RigsConfig, exclude []string) ([]string, error) {
+	skip := map[string]bool{".dolt-data": true}
+	for name := range rigsConfig.Rigs {
+		// Polecat worktrees are rebuilt from scratch on the next sling.
+		entries, _ := os.ReadDir(filepath.Join(townRoot, name, "polecats"))
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				skip[filepath.Join(name, "polecats", e.Name())] = true
+			}
+		}
+	}
+	for _, path := range exclude {
+		if rel, err := filepath.Rel(townRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+			skip[rel] = true
+		}
+	}
+	files, err := walkFiles(townRoot, func(rel string, d fs.DirEntry) bool {
+		if skip[rel] {
+			return true
+		}
+		if !d.IsDir() {
+			return false
+		}
+		// The town root may itself be a git repo; only nested clones and
+		// worktrees are left out.
+		return d.Name() == ".repo.git" || isClone(filepath.Join(townRoot, rel))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading town: %w", err)
+	}
+	return files, nil
+}
+
+// walkFiles returns the regular files and symlinks under root, relative to
+// it, skipping .git and anything skip reports true for.
+func walkFiles(root string, skip func(rel string, d fs.DirEntry) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" || (skip != nil && skip(rel, d)) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() || d.Type()&fs.ModeSymlink != 0 {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// isClone reports whether dir is a git clone or worktree.
+func isClone(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// gtHome returns the user's ~/.gt directory.
+func gtHome() string {
+	return filepath.Dir(hooks.BasePath())
+}
+
+// gtHomeFiles lists the hooks config files in ~/.gt, relative to it.
+func gtHomeFiles() []string {
+	home := gtHome()
+	var files []string
+	if _, err := os.Stat(hooks.BasePath()); err == nil {
+		files = append(files, filepath.Base(hooks.BasePath()))
+	}
+	entries, _ := os.ReadDir(hooks.OverridesDir())
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			rel, err := filepath.Rel(home, filepath.Join(hooks.OverridesDir(), e.Name()))
+			if err == nil {
+				files = append(files, rel)
+			}
+		}
+	}
+	return files
+}
+
+// addFile writes the file or symlink at path to tw as name.
+func addFile(tw *tar.Writer, path, name string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	var link string
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return fmt.Errorf("archiving %s: %w", path, err)
+		}
+	}
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	defer f.Close()
+	// CopyN so a file that grows mid-backup (a log) can't overrun its header.
+	if _, err := io.CopyN(tw, f, hdr.Size); err != nil {
+		return fmt.Errorf("archiving %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package townbackup
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t",
+		"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stubDolt replaces the Dolt backup and restore with plain file copies of
+// a marker file.
+func stubDolt(t *testing.T) (restored *[]string) {
+	t.Helper()
+	origBackup, origRestore := backupDatabase, restoreDatabase
+	t.Cleanup(func() { backupDatabase, restoreDatabase = origBackup, origRestore })
+
+	restored = new([]string)
+	backupDatabase = func(townRoot, db, destDir string) error {
+		data, err := os.ReadFile(filepath.Join(townRoot, ".dolt-data", db, "marker"))
+		if err != nil {
+			return err
+		}
+		writeFile(t, filepath.Join(destDir, "backup"), string(data))
+		return nil
+	}
+	restoreDatabase = func(townRoot, db, srcDir string) error {
+		data, err := os.ReadFile(filepath.Join(srcDir, "backup"))
+		if err != nil {
+			return err
+		}
+		writeFile(t, filepath.Join(doltserver.RigDatabaseDir(townRoot, db), "marker"), string(data))
+		*restored = append(*restored, db)
+		return nil
+	}
+	return restored
+}
+
+func TestBackupRestore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	restored := stubDolt(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeFile(t, filepath.Join(home, ".gt", "hooks-base.json"), `{"base":true}`)
+	writeFile(t, filepath.Join(home, ".gt", "hooks-overrides", "crew.json"), `{}`)
+
+	origin := filepath.Join(t.TempDir(), "origin")
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, origin, "init", "-b", "main")
+	writeFile(t, filepath.Join(origin, "README.md"), "hello")
+	runGit(t, origin, "add", ".")
+	runGit(t, origin, "commit", "-m", "initial")
+
+	town := t.TempDir()
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(town), &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs: map[string]config.RigEntry{
+			"demo": {GitURL: origin, AddedAt: time.Now()},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(town, "settings", "config.json"), `{"theme":"dark"}`)
+	writeFile(t, filepath.Join(town, ".beads", "routes.jsonl"), `{"prefix":"gt-"}`)
+	writeFile(t, filepath.Join(town, ".dolt-data", "demo", ".dolt", "noms"), "chunks")
+	writeFile(t, filepath.Join(town, ".dolt-data", "demo", "marker"), "demo-db")
+	writeFile(t, filepath.Join(town, "demo", "config.json"), `{"type":"rig","name":"demo","default_branch":"main"}`)
+	writeFile(t, filepath.Join(town, "demo", "witness", "state.json"), `{}`)
+	writeFile(t, filepath.Join(town, "demo", "polecats", "nux", "demo", "work.txt"), "wip")
+	runGit(t, town, "clone", origin, filepath.Join(town, "demo", "mayor", "rig"))
+	runGit(t, town, "clone", origin, filepath.Join(town, "demo", "crew", "dave"))
+	writeFile(t, filepath.Join(town, "demo", "crew", "dave", "state.json"), `{"name":"dave","branch":"crew/dave"}`)
+
+	var archive bytes.Buffer
+	manifest, err := Backup(town, &archive, BackupOptions{GTVersion: "test"})
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if len(manifest.Databases) != 1 || manifest.Databases[0] != "demo" {
+		t.Errorf("Databases = %v, want [demo]", manifest.Databases)
+	}
+	if len(manifest.Rigs) != 1 || manifest.Rigs[0].GitURL != origin || manifest.Rigs[0].DefaultBranch != "main" {
+		t.Fatalf("Rigs = %+v", manifest.Rigs)
+	}
+	if crew := manifest.Rigs[0].Crew; len(crew) != 1 || crew[0] != (CrewClone{Name: "dave", Branch: "crew/dave"}) {
+		t.Errorf("Crew = %+v, want dave on crew/dave", crew)
+	}
+	if len(manifest.HomeFiles) != 2 {
+		t.Errorf("HomeFiles = %v, want hooks base and one override", manifest.HomeFiles)
+	}
+
+	// The base hooks file already exists on the restoring machine.
+	if err := os.WriteFile(filepath.Join(home, ".gt", "hooks-base.json"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(home, ".gt", "hooks-overrides", "crew.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "town")
+	result, err := Restore(bytes.NewReader(archive.Bytes()), dest, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings = %v", result.Warnings)
+	}
+
+	for rel, want := range map[string]string{
+		"settings/config.json":        `{"theme":"dark"}`,
+		".beads/routes.jsonl":         `{"prefix":"gt-"}`,
+		".dolt-data/demo/marker":      "demo-db",
+		"demo/witness/state.json":     `{}`,
+		"demo/mayor/rig/README.md":    "hello",
+		"demo/refinery/rig/README.md": "hello",
+		"demo/crew/dave/README.md":    "hello",
+		"demo/.repo.git/HEAD":         "",
+	} {
+		data, err := os.ReadFile(filepath.Join(dest, rel))
+		if err != nil {
+			t.Errorf("%s: %v", rel, err)
+			continue
+		}
+		if want != "" && string(data) != want {
+			t.Errorf("%s = %q, want %q", rel, data, want)
+		}
+	}
+	if len(*restored) != 1 {
+		t.Errorf("restored databases = %v", *restored)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "demo", "polecats", "nux")); !os.IsNotExist(err) {
+		t.Errorf("polecat worktree was restored")
+	}
+	if data, _ := os.ReadFile(filepath.Join(home, ".gt", "hooks-base.json")); string(data) != "mine" {
+		t.Errorf("existing hooks-base.json overwritten: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".gt", "hooks-overrides", "crew.json")); err != nil {
+		t.Errorf("missing override not restored: %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", filepath.Join(dest, "demo", "crew", "dave"), "branch", "--show-current").Output()
+	if err != nil || string(bytes.TrimSpace(out)) != "crew/dave" {
+		t.Errorf("crew dave on %q (%v), want crew/dave", out, err)
+	}
+
+	if _, err := Restore(bytes.NewReader(archive.Bytes()), dest, RestoreOptions{}); err == nil {
+		t.Error("Restore into a non-empty town succeeded")
+	}
+}
+
+func TestTownFilesSkipsClones(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, ".git", "HEAD"), "ref: refs/heads/main")
+	writeFile(t, filepath.Join(town, "mayor", "town.json"), `{}`)
+	writeFile(t, filepath.Join(town, "demo", "mayor", "rig", ".git"), "gitdir: elsewhere")
+	writeFile(t, filepath.Join(town, "demo", "mayor", "rig", "src.go"), "package x")
+	writeFile(t, filepath.Join(town, "demo", ".repo.git", "HEAD"), "ref: refs/heads/main")
+	writeFile(t, filepath.Join(town, ".dolt-data", "demo", "marker"), "x")
+	writeFile(t, filepath.Join(town, "out.tar.gz"), "x")
+
+	files, err := townFiles(town, &config.RigsConfig{Rigs: map[string]config.RigEntry{"demo": {}}},
+		[]string{filepath.Join(town, "out.tar.gz")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != filepath.Join("mayor", "town.json") {
+		t.Errorf("townFiles = %v, want only mayor/town.json", files)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, "mayor", "rigs.json"), `{"version":1,"rigs":{"demo":{}}}`)
+	writeFile(t, filepath.Join(town, "demo", "mayor", "rig", ".git"), "gitdir: elsewhere")
+	writeFile(t, filepath.Join(town, "demo", "mayor", "rig", "src.go"), "package x")
+	writeFile(t, filepath.Join(town, ".dolt-data", "demo", "chunk"), "0123456789")
+
+	files, databases, err := Estimate(town, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(len(`{"version":1,"rigs":{"demo":{}}}`)); files != want {
+		t.Errorf("files = %d, want %d (rigs.json only)", files, want)
+	}
+	if databases != 10 {
+		t.Errorf("databases = %d, want 10", databases)
+	}
+}
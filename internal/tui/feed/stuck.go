@@ -304,9 +304,9 @@ func deriveSessionName(rig, role, name string) string {
 			return session.HQPrefix + role
 		}
 		if name == "" {
-			return rigPrefix + "-" + role
+			return session.RigSessionPrefix(rigPrefix) + role
 		}
-		return rigPrefix + "-" + role + "-" + name
+		return session.RigSessionPrefix(rigPrefix) + role + "-" + name
 	}
 }
 
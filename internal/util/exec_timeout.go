@@ -0,0 +1,200 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default subprocess timeouts. A hung subprocess fails the operation after
+// this long instead of hanging gt; tools that touch the network get longer.
+const (
+	GitTimeout      = 10 * time.Minute // fetch, push, and local git commands
+	TransferTimeout = 30 * time.Minute // git clones and dolt push/pull of whole repositories
+	BeadsTimeout    = 2 * time.Minute  // bd
+	DoltTimeout     = time.Minute      // dolt CLI commands other than sql-server
+	TmuxTimeout     = 30 * time.Second // tmux (except attach)
+	ProbeTimeout    = 10 * time.Second // ps, pgrep, lsof
+)
+
+// waitDelay bounds how long Wait keeps reading output after a killed
+// process exits, in case a grandchild still holds its stdout open.
+const waitDelay = 5 * time.Second
+
+var (
+	overrideMu  sync.RWMutex
+	overrideSet bool
+	overrideVal time.Duration
+	commandCtx  context.Context // nil means context.Background()
+)
+
+// SetTimeoutOverride replaces every subprocess's default timeout with d,
+// as gt's global --timeout flag does. Zero disables timeouts. Interactive
+// commands (those run with a zero default) are never limited.
+func SetTimeoutOverride(d time.Duration) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	overrideSet, overrideVal = true, d
+}
+
+// ClearTimeoutOverride restores the default timeouts.
+func ClearTimeoutOverride() {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	overrideSet, overrideVal = false, 0
+}
+
+// SetCommandContext makes Run, Output, and CombinedOutput kill their
+// subprocess when ctx is done. gt installs the running command's context
+// here so cancelling the command stops whatever git, bd, dolt, or tmux
+// process it is waiting on. A nil ctx restores context.Background().
+func SetCommandContext(ctx context.Context) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	commandCtx = ctx
+}
+
+// CommandContext returns the context installed by SetCommandContext, or
+// context.Background() if there is none.
+func CommandContext() context.Context {
+	overrideMu.RLock()
+	defer overrideMu.RUnlock()
+	if commandCtx == nil {
+		return context.Background()
+	}
+	return commandCtx
+}
+
+// EffectiveTimeout returns the limit applied to a subprocess whose default
+// is def: the override if one is set, otherwise def. Zero means no limit.
+func EffectiveTimeout(def time.Duration) time.Duration {
+	if def <= 0 {
+		return 0
+	}
+	overrideMu.RLock()
+	defer overrideMu.RUnlock()
+	if overrideSet {
+		return overrideVal
+	}
+	return def
+}
+
+// TimeoutError reports a subprocess that was killed for running too long.
+type TimeoutError struct {
+	Command string        // the command line, abbreviated
+	Timeout time.Duration // the limit it exceeded
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s (raise with --timeout)", e.Command, e.Timeout)
+}
+
+// Unwrap makes errors.Is(err, context.DeadlineExceeded) true.
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// IsTimeout reports whether err is or wraps a *TimeoutError.
+func IsTimeout(err error) bool {
+	var te *TimeoutError
+	return errors.As(err, &te)
+}
+
+// Run runs cmd, killing it if it outlives the effective timeout for def
+// (see EffectiveTimeout) or the command context is done (see
+// SetCommandContext). It returns a *TimeoutError on timeout.
+func Run(cmd *exec.Cmd, def time.Duration) error {
+	return RunContext(CommandContext(), cmd, def)
+}
+
+// RunContext is Run, also killing cmd when ctx is done. Cancellation
+// returns ctx's error, annotated with the command line.
+func RunContext(ctx context.Context, cmd *exec.Cmd, def time.Duration) error {
+	timeout := EffectiveTimeout(def)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if cmd.WaitDelay == 0 {
+		cmd.WaitDelay = waitDelay
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		<-done
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && timeout > 0 {
+			return &TimeoutError{Command: commandLine(cmd), Timeout: timeout}
+		}
+		return fmt.Errorf("%s: %w", commandLine(cmd), ctx.Err())
+	}
+}
+
+// Output is Run returning stdout, like cmd.Output. When cmd.Stderr is
+// unset, stderr is captured into the returned *exec.ExitError.
+func Output(cmd *exec.Cmd, def time.Duration) ([]byte, error) {
+	if cmd.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	captureStderr := cmd.Stderr == nil
+	if captureStderr {
+		cmd.Stderr = &stderr
+	}
+	err := Run(cmd, def)
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput is Run returning stdout and stderr together, like
+// cmd.CombinedOutput.
+func CombinedOutput(cmd *exec.Cmd, def time.Duration) ([]byte, error) {
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		return nil, errors.New("exec: Stdout or Stderr already set")
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := Run(cmd, def)
+	return out.Bytes(), err
+}
+
+// commandLine names cmd for error messages: the program and its first few
+// arguments.
+func commandLine(cmd *exec.Cmd) string {
+	const maxArgs = 4
+	parts := []string{filepath.Base(cmd.Path)}
+	if len(cmd.Args) > 0 {
+		parts[0] = filepath.Base(cmd.Args[0])
+	}
+	args := cmd.Args
+	if len(args) > 0 {
+		args = args[1:]
+	}
+	for i, a := range args {
+		if i == maxArgs {
+			parts = append(parts, "...")
+			break
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
+}
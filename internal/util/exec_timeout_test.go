@@ -0,0 +1,90 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	start := time.Now()
+	err := Run(exec.Command("sleep", "10"), 100*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %s, want it killed after 100ms", elapsed)
+	}
+	var te *TimeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("Run error = %v, want *TimeoutError", err)
+	}
+	if te.Command != "sleep 10" || te.Timeout != 100*time.Millisecond {
+		t.Errorf("TimeoutError = %+v", te)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("TimeoutError does not unwrap to context.DeadlineExceeded")
+	}
+	if !strings.Contains(err.Error(), "sleep 10 timed out after 100ms") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestTimeoutOverride(t *testing.T) {
+	t.Cleanup(ClearTimeoutOverride)
+
+	if got := EffectiveTimeout(time.Minute); got != time.Minute {
+		t.Errorf("default = %s, want 1m", got)
+	}
+	SetTimeoutOverride(time.Second)
+	if got := EffectiveTimeout(time.Minute); got != time.Second {
+		t.Errorf("override = %s, want 1s", got)
+	}
+	if got := EffectiveTimeout(0); got != 0 {
+		t.Errorf("interactive command limited to %s", got)
+	}
+	SetTimeoutOverride(0)
+	if got := EffectiveTimeout(time.Minute); got != 0 {
+		t.Errorf("--timeout 0 = %s, want no limit", got)
+	}
+}
+
+func TestRunCommandContextCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	SetCommandContext(ctx)
+	t.Cleanup(func() { SetCommandContext(nil) })
+	cancel()
+
+	start := time.Now()
+	err := Run(exec.Command("sleep", "10"), time.Minute)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %s, want it killed by the cancelled context", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run error = %v, want context.Canceled", err)
+	}
+	if IsTimeout(err) {
+		t.Error("cancellation reported as a timeout")
+	}
+}
+
+func TestOutputCapturesStderr(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out, err := Output(exec.Command("sh", "-c", "echo out; echo oops >&2; exit 3"), time.Minute)
+	if string(out) != "out\n" {
+		t.Errorf("stdout = %q", out)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || strings.TrimSpace(string(exitErr.Stderr)) != "oops" {
+		t.Errorf("err = %v, want ExitError with stderr", err)
+	}
+}
@@ -256,8 +256,13 @@ func getProcessCwd(pid int) string {
 	return ""
 }
 
+// townMarkers are the files under mayor/ that identify a Gas Town root:
+// town.json and its YAML/TOML variants. util sits below config, so this
+// mirrors config.ConfigExists rather than calling it.
+var townMarkers = []string{"town.json", "town.yaml", "town.yml", "town.toml"}
+
 // isInGasTownWorkspace checks whether a process's working directory is inside
-// a Gas Town workspace (identified by the mayor/town.json marker).
+// a Gas Town workspace (identified by a mayor/town.json marker or variant).
 // Returns true if the process cwd is at or under a Gas Town workspace root.
 // Returns false if the cwd cannot be determined or is not under any workspace.
 func isInGasTownWorkspace(pid int) bool {
@@ -269,8 +274,10 @@ func isInGasTownWorkspace(pid int) bool {
 	// Walk up from cwd looking for a Gas Town workspace marker
 	current := cwd
 	for {
-		if _, err := os.Stat(filepath.Join(current, "mayor", "town.json")); err == nil {
-			return true
+		for _, marker := range townMarkers {
+			if _, err := os.Stat(filepath.Join(current, "mayor", marker)); err == nil {
+				return true
+			}
 		}
 		parent := filepath.Dir(current)
 		if parent == current {
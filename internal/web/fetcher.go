@@ -392,16 +392,11 @@ func (f *LiveConvoyFetcher) getWorkersFromAssignees(details map[string]*issueDet
 // getSessionActivityForAssignee looks up tmux session activity for an assignee.
 // Assignee format: "rigname/polecats/polecatname" -> session "gt-rigname-polecatname"
 func (f *LiveConvoyFetcher) getSessionActivityForAssignee(assignee string) *time.Time {
-	// Parse assignee: "roxas/polecats/dag" -> rig="roxas", polecat="dag"
-	parts := strings.Split(assignee, "/")
-	if len(parts) != 3 || parts[1] != "polecats" {
+	a, err := session.ParseAssignee(assignee)
+	if err != nil || a.Role() != session.RolePolecat {
 		return nil
 	}
-	rig := parts[0]
-	polecat := parts[2]
-
-	// Construct session name
-	sessionName := session.PolecatSessionName(session.PrefixFor(rig), polecat)
+	sessionName := a.SessionName()
 
 	// Query tmux for session activity
 	// Format: session_activity returns unix timestamp
@@ -0,0 +1,227 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/pkg/gastown"
+)
+
+// townFetchTimeout bounds the control API calls behind one snapshot.
+const townFetchTimeout = 15 * time.Second
+
+// townActions are the control API methods the town dashboard may call on a
+// user's behalf. Everything else it does is read-only.
+var townActions = map[string]bool{
+	"rig.start":     true,
+	"rig.stop":      true,
+	"polecat.nudge": true,
+}
+
+// TownCaller calls a method on the daemon's control API.
+// controlapi.Caller implements it.
+type TownCaller interface {
+	Call(ctx context.Context, method string, params, result any) error
+}
+
+// TownSnapshot is what the town dashboard shows.
+type TownSnapshot struct {
+	Rigs        []TownRig           `json:"rigs"`
+	Dolt        *gastown.DoltHealth `json:"dolt,omitempty"`
+	Escalations []TownEscalation    `json:"escalations"`
+	Errors      []string            `json:"errors,omitempty"`
+
+	// ReadOnly is true when no token is configured, so actions are refused.
+	ReadOnly  bool      `json:"read_only"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TownRig is one rig card: its agents and its merge queue.
+type TownRig struct {
+	Name   string                 `json:"name"`
+	Status *gastown.RigStatus     `json:"status,omitempty"`
+	Queue  []gastown.MergeRequest `json:"queue"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// TownEscalation is an open escalation.
+type TownEscalation struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Severity  string    `json:"severity"`
+	Acked     bool      `json:"acked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TownActionRequest asks the dashboard to run an action method.
+type TownActionRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// TownActionResponse is the result of an action.
+type TownActionResponse struct {
+	Success bool   `json:"success"`
+	Output  string `json:"output,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// TownHandler serves the town dashboard from the daemon's control API.
+type TownHandler struct {
+	caller TownCaller
+	token  string
+	tmpl   *template.Template
+}
+
+// NewTownHandler creates the handler for gt web. With token empty the
+// dashboard is read-only; otherwise actions need it as a bearer token.
+func NewTownHandler(caller TownCaller, token string) (*TownHandler, error) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("loading templates: %w", err)
+	}
+	return &TownHandler{caller: caller, token: token, tmpl: tmpl}, nil
+}
+
+func (h *TownHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/" && r.Method == http.MethodGet:
+		h.handlePage(w, r)
+	case r.URL.Path == "/api/town" && r.Method == http.MethodGet:
+		h.handleSnapshot(w, r)
+	case r.URL.Path == "/api/action" && r.Method == http.MethodPost:
+		h.handleAction(w, r)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func (h *TownHandler) handlePage(w http.ResponseWriter, r *http.Request) {
+	snap := h.snapshot(r.Context())
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.tmpl.ExecuteTemplate(w, "town.html", snap); err != nil {
+		http.Error(w, "Failed to render template", http.StatusInternalServerError)
+	}
+}
+
+func (h *TownHandler) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.snapshot(r.Context()))
+}
+
+func (h *TownHandler) handleAction(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" {
+		h.sendAction(w, http.StatusForbidden, TownActionResponse{Error: "dashboard is read-only (start gt web with a token to enable actions)"})
+		return
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) != 1 {
+		h.sendAction(w, http.StatusUnauthorized, TownActionResponse{Error: "missing or wrong token"})
+		return
+	}
+
+	var req TownActionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+		h.sendAction(w, http.StatusBadRequest, TownActionResponse{Error: "invalid request: " + err.Error()})
+		return
+	}
+	if !townActions[req.Method] {
+		h.sendAction(w, http.StatusBadRequest, TownActionResponse{Error: "not an action: " + req.Method})
+		return
+	}
+
+	var result struct {
+		Output string `json:"output"`
+	}
+	var params any
+	if len(req.Params) > 0 {
+		params = req.Params
+	}
+	if err := h.caller.Call(r.Context(), req.Method, params, &result); err != nil {
+		h.sendAction(w, http.StatusBadGateway, TownActionResponse{Error: err.Error()})
+		return
+	}
+	h.sendAction(w, http.StatusOK, TownActionResponse{Success: true, Output: result.Output})
+}
+
+func (h *TownHandler) sendAction(w http.ResponseWriter, status int, resp TownActionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// snapshot gathers the dashboard's data. A failed call is recorded in the
+// snapshot and the rest is still shown.
+func (h *TownHandler) snapshot(ctx context.Context) *TownSnapshot {
+	ctx, cancel := context.WithTimeout(ctx, townFetchTimeout)
+	defer cancel()
+
+	snap := &TownSnapshot{
+		Rigs:        []TownRig{},
+		Escalations: []TownEscalation{},
+		ReadOnly:    h.token == "",
+		UpdatedAt:   time.Now(),
+	}
+	fail := func(what string, err error) {
+		snap.Errors = append(snap.Errors, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	var rigs []gastown.Rig
+	if err := h.caller.Call(ctx, "rig.list", nil, &rigs); err != nil {
+		fail("rigs", err)
+	}
+	for _, rig := range rigs {
+		tr := TownRig{Name: rig.Name, Queue: []gastown.MergeRequest{}}
+		params := map[string]string{"rig": rig.Name}
+		var status gastown.RigStatus
+		if err := h.caller.Call(ctx, "rig.status", params, &status); err != nil {
+			tr.Error = err.Error()
+		} else {
+			tr.Status = &status
+		}
+		if rig.HasRefinery {
+			if err := h.caller.Call(ctx, "queue.status", params, &tr.Queue); err != nil && tr.Error == "" {
+				tr.Error = "merge queue: " + err.Error()
+			}
+		}
+		snap.Rigs = append(snap.Rigs, tr)
+	}
+
+	var dolt gastown.DoltHealth
+	if err := h.caller.Call(ctx, "dolt.health", nil, &dolt); err != nil {
+		fail("dolt", err)
+	} else {
+		snap.Dolt = &dolt
+	}
+
+	var escalations []gastown.Issue
+	if err := h.caller.Call(ctx, "escalation.list", nil, &escalations); err != nil {
+		fail("escalations", err)
+	}
+	for _, e := range escalations {
+		snap.Escalations = append(snap.Escalations, townEscalation(e))
+	}
+
+	return snap
+}
+
+// townEscalation reads severity and acknowledgement from the bead's labels,
+// as FetchEscalations does.
+func townEscalation(issue gastown.Issue) TownEscalation {
+	e := TownEscalation{ID: issue.ID, Title: issue.Title, Severity: "medium", CreatedAt: issue.CreatedAt}
+	for _, label := range issue.Labels {
+		if sev, ok := strings.CutPrefix(label, "severity:"); ok {
+			e.Severity = sev
+		}
+		if label == "acked" {
+			e.Acked = true
+		}
+	}
+	return e
+}
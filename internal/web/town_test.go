@@ -0,0 +1,159 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeTownCaller answers control API calls from canned JSON results.
+type fakeTownCaller struct {
+	results map[string]string
+	calls   []string
+}
+
+func (f *fakeTownCaller) Call(_ context.Context, method string, params, result any) error {
+	call := method
+	if params != nil {
+		data, _ := json.Marshal(params)
+		call += " " + string(data)
+	}
+	f.calls = append(f.calls, call)
+	res, ok := f.results[method]
+	if !ok {
+		return errors.New("method not found: " + method)
+	}
+	return json.Unmarshal([]byte(res), result)
+}
+
+func newFakeTown() *fakeTownCaller {
+	return &fakeTownCaller{results: map[string]string{
+		"rig.list":   `[{"name": "gastown", "has_refinery": true}, {"name": "beads"}]`,
+		"rig.status": `{"rig": {"name": "gastown"}, "witness_running": true, "polecats": [{"name": "nux", "running": true}], "crew": []}`,
+		"queue.status": `[{"position": 1, "id": "gt-mr1", "branch": "polecat/nux/gt-abc", "worker": "nux",
+			"created_at": "2026-01-02T03:04:05Z"}]`,
+		"dolt.health":     `{"addr": "127.0.0.1:3307", "running": true, "pid": 42, "latency": 1500000, "databases": ["hq", "gastown"]}`,
+		"escalation.list": `[{"id": "hq-e1", "title": "Build broken", "labels": ["gt:escalation", "severity:critical", "acked"]}]`,
+		"rig.stop":        `{"output": "stopped gastown"}`,
+	}}
+}
+
+func TestTownHandler_Snapshot(t *testing.T) {
+	caller := newFakeTown()
+	delete(caller.results, "escalation.list")
+	h, err := NewTownHandler(caller, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/town", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var snap TownSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snap.Rigs) != 2 || snap.Rigs[0].Status == nil || !snap.Rigs[0].Status.WitnessRunning {
+		t.Fatalf("rigs = %+v", snap.Rigs)
+	}
+	if len(snap.Rigs[0].Queue) != 1 || len(snap.Rigs[1].Queue) != 0 {
+		t.Errorf("queues: gastown %+v, beads %+v (beads has no refinery)", snap.Rigs[0].Queue, snap.Rigs[1].Queue)
+	}
+	if snap.Dolt == nil || !snap.Dolt.Running || len(snap.Dolt.Databases) != 2 {
+		t.Errorf("dolt = %+v", snap.Dolt)
+	}
+	if !snap.ReadOnly {
+		t.Error("ReadOnly = false with no token")
+	}
+	if len(snap.Errors) != 1 || !strings.HasPrefix(snap.Errors[0], "escalations:") {
+		t.Errorf("errors = %v, want the failed escalation.list only", snap.Errors)
+	}
+	for _, call := range caller.calls {
+		if strings.HasPrefix(call, "queue.status") && strings.Contains(call, "beads") {
+			t.Errorf("queried merge queue of rig without refinery: %s", call)
+		}
+	}
+}
+
+func TestTownHandler_Page(t *testing.T) {
+	h, err := NewTownHandler(newFakeTown(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"gastown", "polecat/nux/gt-abc", "127.0.0.1:3307", "Build broken", "severity-critical", `class="readonly"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /nope: status %d, want 404", rec.Code)
+	}
+}
+
+func TestTownHandler_Action(t *testing.T) {
+	action := `{"method": "rig.stop", "params": {"rig": "gastown"}}`
+	tests := []struct {
+		name   string
+		token  string
+		auth   string
+		body   string
+		status int
+	}{
+		{"read-only", "", "Bearer s3cret", action, http.StatusForbidden},
+		{"no token", "s3cret", "", action, http.StatusUnauthorized},
+		{"wrong token", "s3cret", "Bearer guess", action, http.StatusUnauthorized},
+		{"not an action", "s3cret", "Bearer s3cret", `{"method": "rig.list"}`, http.StatusBadRequest},
+		{"ok", "s3cret", "Bearer s3cret", action, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := newFakeTown()
+			h, err := NewTownHandler(caller, tt.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/action", strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			var resp TownActionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if tt.status != http.StatusOK {
+				if len(caller.calls) != 0 {
+					t.Errorf("refused action still called %v", caller.calls)
+				}
+				return
+			}
+			if !resp.Success || resp.Output != "stopped gastown" {
+				t.Errorf("response = %+v", resp)
+			}
+			if len(caller.calls) != 1 || caller.calls[0] != `rig.stop {"rig":"gastown"}` {
+				t.Errorf("calls = %v", caller.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package witness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// CIStatus is the outcome of the latest finished CI run on a branch.
+type CIStatus string
+
+const (
+	CIPassed  CIStatus = "passed"
+	CIFailed  CIStatus = "failed"
+	CIUnknown CIStatus = "unknown" // no finished run to judge by
+)
+
+// CIRedLabel marks beads the witness opened for a red default branch.
+const CIRedLabel = "ci:red"
+
+// ciRequestTimeout bounds one query to a CI provider.
+const ciRequestTimeout = 30 * time.Second
+
+// CIRun is the finished CI run that decides a branch's status.
+type CIRun struct {
+	Status     CIStatus  `json:"status"`
+	Name       string    `json:"name,omitempty"` // workflow or pipeline
+	Commit     string    `json:"commit,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// ShortCommit returns the first 8 characters of the run's commit.
+func (r *CIRun) ShortCommit() string {
+	if len(r.Commit) > 8 {
+		return r.Commit[:8]
+	}
+	return r.Commit
+}
+
+// FetchCIStatus asks the configured provider for the latest finished run
+// on branch. repo is the GitHub owner/name, used by the github provider.
+func FetchCIStatus(ctx context.Context, cfg *config.CIConfig, repo, branch string) (*CIRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, ciRequestTimeout)
+	defer cancel()
+	switch cfg.Provider {
+	case config.CIProviderGitHub:
+		if repo == "" {
+			return nil, fmt.Errorf("no GitHub repo: set ci.repo in the rig settings")
+		}
+		return fetchGitHubStatus(ctx, repo, cfg.Workflow, branch)
+	case config.CIProviderBuildkite:
+		return fetchBuildkiteStatus(ctx, cfg.Org, cfg.Pipeline, branch)
+	}
+	return nil, fmt.Errorf("unknown CI provider %q", cfg.Provider)
+}
+
+// ghRun is one entry of gh run list --json.
+type ghRun struct {
+	WorkflowName string    `json:"workflowName"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	HeadSha      string    `json:"headSha"`
+	URL          string    `json:"url"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// runGH runs gh and returns its stdout. A variable so tests can run
+// without gh installed.
+var runGH = func(ctx context.Context, args ...string) ([]byte, error) {
+	out, err := util.Output(exec.CommandContext(ctx, "gh", args...), ciRequestTimeout)
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("gh %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
+
+func fetchGitHubStatus(ctx context.Context, repo, workflow, branch string) (*CIRun, error) {
+	args := []string{"run", "list", "--repo", repo, "--branch", branch, "--limit", "20",
+		"--json", "workflowName,status,conclusion,headSha,url,updatedAt"}
+	if workflow != "" {
+		args = append(args, "--workflow", workflow)
+	}
+	out, err := runGH(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var runs []ghRun
+	if err := json.Unmarshal(out, &runs); err != nil {
+		return nil, fmt.Errorf("parsing gh run list output: %w", err)
+	}
+	return judgeGitHubRuns(runs), nil
+}
+
+// judgeGitHubRuns decides a branch's status from its runs, newest first:
+// the branch is red if the latest finished run of any workflow failed.
+// Cancelled and skipped runs don't count either way.
+func judgeGitHubRuns(runs []ghRun) *CIRun {
+	seen := make(map[string]bool)
+	var passed *CIRun
+	for _, r := range runs {
+		if r.Status != "completed" || seen[r.WorkflowName] {
+			continue
+		}
+		run := &CIRun{Name: r.WorkflowName, Commit: r.HeadSha, URL: r.URL, FinishedAt: r.UpdatedAt}
+		switch r.Conclusion {
+		case "failure", "timed_out", "startup_failure":
+			run.Status = CIFailed
+			return run
+		case "success":
+			seen[r.WorkflowName] = true
+			if passed == nil {
+				run.Status = CIPassed
+				passed = run
+			}
+		}
+	}
+	if passed != nil {
+		return passed
+	}
+	return &CIRun{Status: CIUnknown}
+}
+
+// buildkiteAPI is the Buildkite REST API base URL, replaced in tests.
+var buildkiteAPI = "https://api.buildkite.com/v2"
+
+func fetchBuildkiteStatus(ctx context.Context, org, pipeline, branch string) (*CIRun, error) {
+	token := os.Getenv("BUILDKITE_API_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("BUILDKITE_API_TOKEN is not set")
+	}
+	q := url.Values{"branch": {branch}, "per_page": {"1"}, "state[]": {"passed", "failed"}}
+	u := fmt.Sprintf("%s/organizations/%s/pipelines/%s/builds?%s",
+		buildkiteAPI, url.PathEscape(org), url.PathEscape(pipeline), q.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying Buildkite: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("querying Buildkite: %s", resp.Status)
+	}
+
+	var builds []struct {
+		State      string    `json:"state"`
+		Commit     string    `json:"commit"`
+		WebURL     string    `json:"web_url"`
+		FinishedAt time.Time `json:"finished_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&builds); err != nil {
+		return nil, fmt.Errorf("parsing Buildkite builds: %w", err)
+	}
+	if len(builds) == 0 {
+		return &CIRun{Status: CIUnknown}, nil
+	}
+	b := builds[0]
+	run := &CIRun{Status: CIPassed, Name: pipeline, Commit: b.Commit, URL: b.WebURL, FinishedAt: b.FinishedAt}
+	if b.State == "failed" {
+		run.Status = CIFailed
+	}
+	return run, nil
+}
+
+// GitHubRepoFromURL extracts owner/name from a GitHub remote URL, or
+// returns "" for other hosts.
+func GitHubRepoFromURL(gitURL string) string {
+	s := strings.TrimSuffix(strings.TrimSpace(gitURL), "/")
+	s = strings.TrimSuffix(s, ".git")
+	for _, prefix := range []string{"git@github.com:", "ssh://git@github.com/", "https://github.com/", "http://github.com/"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			if parts := strings.Split(rest, "/"); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+				return rest
+			}
+		}
+	}
+	return ""
+}
+
+// Wisp config keys recording that a rig's default branch is red. Like the
+// merge queue pause they live in the town's wisp layer, so a witness
+// restart doesn't file the breakage twice.
+const (
+	ciRedSinceKey  = "ci_red_since"
+	ciRedCommitKey = "ci_red_commit"
+	ciRedBeadKey   = "ci_red_bead"
+	ciRedURLKey    = "ci_red_url"
+)
+
+// CIRedState is a rig's recorded red default branch.
+type CIRedState struct {
+	Since  time.Time `json:"since"`
+	Commit string    `json:"commit,omitempty"`
+	Bead   string    `json:"bead,omitempty"`
+	URL    string    `json:"url,omitempty"`
+}
+
+// LoadCIRed returns the rig's red branch state, or nil if CI is green.
+func LoadCIRed(townRoot, rigName string) *CIRedState {
+	cfg := wisp.NewConfig(townRoot, rigName)
+	at := cfg.GetString(ciRedSinceKey)
+	if at == "" {
+		return nil
+	}
+	since, _ := time.Parse(time.RFC3339, at)
+	return &CIRedState{
+		Since:  since,
+		Commit: cfg.GetString(ciRedCommitKey),
+		Bead:   cfg.GetString(ciRedBeadKey),
+		URL:    cfg.GetString(ciRedURLKey),
+	}
+}
+
+// SaveCIRed records that the rig's default branch is red.
+func SaveCIRed(townRoot, rigName string, s *CIRedState) error {
+	cfg := wisp.NewConfig(townRoot, rigName)
+	for key, value := range map[string]string{
+		ciRedSinceKey:  s.Since.UTC().Format(time.RFC3339),
+		ciRedCommitKey: s.Commit,
+		ciRedBeadKey:   s.Bead,
+		ciRedURLKey:    s.URL,
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			return fmt.Errorf("saving CI state: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClearCIRed records that the rig's default branch is green again.
+func ClearCIRed(townRoot, rigName string) error {
+	cfg := wisp.NewConfig(townRoot, rigName)
+	for _, key := range []string{ciRedSinceKey, ciRedCommitKey, ciRedBeadKey, ciRedURLKey} {
+		if err := cfg.Unset(key); err != nil {
+			return fmt.Errorf("clearing CI state: %w", err)
+		}
+	}
+	return nil
+}
+
+// CITransition is what a CI check found compared to the recorded state.
+type CITransition string
+
+const (
+	CIStillGreen CITransition = "green"
+	CIWentRed    CITransition = "went-red"
+	CIStillRed   CITransition = "still-red"
+	CIWentGreen  CITransition = "went-green"
+)
+
+// NextCITransition compares run with the recorded red state (nil when
+// green). A run with no verdict keeps the current state.
+func NextCITransition(red *CIRedState, run *CIRun) CITransition {
+	switch {
+	case run.Status == CIFailed && red == nil:
+		return CIWentRed
+	case run.Status == CIPassed && red != nil:
+		return CIWentGreen
+	case red != nil:
+		return CIStillRed
+	}
+	return CIStillGreen
+}
+
+// CreateCIRedBead opens a bug bead in the rig for its red default branch
+// and returns its ID. Its priority follows severity like incidents. With
+// the rig's dedup settings, a bead closed as a duplicate of an open one
+// returns that bead's ID instead, so the red state tracks it.
+func CreateCIRedBead(rigPath, rigName, branch, severity string, run *CIRun) (string, error) {
+	bd := beads.New(rigPath)
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:    fmt.Sprintf("CI red on %s: %s (%s)", branch, orNull(run.Name), orNull(run.ShortCommit())),
+		Type:     "bug",
+		Priority: incidentPriority(severity),
+		Description: strings.Join([]string{
+			fmt.Sprintf("CI on %s's default branch %s is failing. The merge queue is held until it passes again.", rigName, branch),
+			"",
+			"run: " + orNull(run.Name),
+			"commit: " + orNull(run.Commit),
+			"url: " + orNull(run.URL),
+			"severity: " + severity,
+		}, "\n"),
+		Actor: rigName + "/witness",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating CI bead: %w", err)
+	}
+	if err := bd.Update(issue.ID, beads.UpdateOptions{AddLabels: []string{CIRedLabel}}); err != nil {
+		return issue.ID, fmt.Errorf("labeling CI bead %s: %w", issue.ID, err)
+	}
+	if cfg := config.LoadDedupConfig(rigPath); cfg != nil {
+		res, err := bd.CheckDuplicates(issue, cfg)
+		if err != nil {
+			return issue.ID, fmt.Errorf("checking CI bead %s for duplicates: %w", issue.ID, err)
+		}
+		if res.DuplicateOf != "" {
+			return res.DuplicateOf, nil
+		}
+	}
+	return issue.ID, nil
+}
+
+// CloseCIRedBead closes the rig's CI bead once the branch passes again.
+func CloseCIRedBead(rigPath, beadID string, run *CIRun) error {
+	return beads.New(rigPath).CloseWithReason(
+		fmt.Sprintf("CI green again at %s (%s)", orNull(run.ShortCommit()), orNull(run.URL)), beadID)
+}
@@ -0,0 +1,164 @@
+package witness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestJudgeGitHubRuns(t *testing.T) {
+	run := func(workflow, status, conclusion, sha string) ghRun {
+		return ghRun{WorkflowName: workflow, Status: status, Conclusion: conclusion, HeadSha: sha}
+	}
+	tests := []struct {
+		name string
+		runs []ghRun
+		want CIStatus
+		sha  string
+	}{
+		{"no runs", nil, CIUnknown, ""},
+		{"only in progress", []ghRun{run("ci", "in_progress", "", "c")}, CIUnknown, ""},
+		{"latest passed", []ghRun{run("ci", "completed", "success", "b"), run("ci", "completed", "failure", "a")}, CIPassed, "b"},
+		{"latest failed", []ghRun{run("ci", "in_progress", "", "c"), run("ci", "completed", "failure", "b")}, CIFailed, "b"},
+		{"other workflow failed", []ghRun{run("ci", "completed", "success", "b"), run("lint", "completed", "timed_out", "b")}, CIFailed, "b"},
+		{"cancelled ignored", []ghRun{run("ci", "completed", "cancelled", "b"), run("ci", "completed", "success", "a")}, CIPassed, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := judgeGitHubRuns(tt.runs)
+			if got.Status != tt.want || got.Commit != tt.sha {
+				t.Errorf("got %s at %q, want %s at %q", got.Status, got.Commit, tt.want, tt.sha)
+			}
+		})
+	}
+}
+
+func TestFetchCIStatus_GitHub(t *testing.T) {
+	orig := runGH
+	defer func() { runGH = orig }()
+	var gotArgs []string
+	runGH = func(_ context.Context, args ...string) ([]byte, error) {
+		gotArgs = args
+		return []byte(`[{"workflowName":"ci","status":"completed","conclusion":"failure","headSha":"abc123def456","url":"https://github.com/o/r/actions/runs/1"}]`), nil
+	}
+
+	cfg := &config.CIConfig{Provider: config.CIProviderGitHub, Workflow: "ci.yml"}
+	run, err := FetchCIStatus(context.Background(), cfg, "o/r", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != CIFailed || run.ShortCommit() != "abc123de" {
+		t.Errorf("run = %+v", run)
+	}
+	joined := strings.Join(gotArgs, " ")
+	for _, want := range []string{"--repo o/r", "--branch main", "--workflow ci.yml"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("gh args %q missing %q", joined, want)
+		}
+	}
+
+	if _, err := FetchCIStatus(context.Background(), cfg, "", "main"); err == nil {
+		t.Error("expected an error without a repo")
+	}
+}
+
+func TestFetchCIStatus_Buildkite(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/organizations/acme/pipelines/web/builds" || r.URL.Query().Get("branch") != "main" {
+			t.Errorf("request = %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`[{"state":"failed","commit":"deadbeef","web_url":"https://buildkite.com/acme/web/builds/7"}]`))
+	}))
+	defer srv.Close()
+	orig := buildkiteAPI
+	buildkiteAPI = srv.URL
+	defer func() { buildkiteAPI = orig }()
+
+	cfg := &config.CIConfig{Provider: config.CIProviderBuildkite, Org: "acme", Pipeline: "web"}
+	t.Setenv("BUILDKITE_API_TOKEN", "")
+	if _, err := FetchCIStatus(context.Background(), cfg, "", "main"); err == nil {
+		t.Error("expected an error without BUILDKITE_API_TOKEN")
+	}
+
+	t.Setenv("BUILDKITE_API_TOKEN", "tok")
+	run, err := FetchCIStatus(context.Background(), cfg, "", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != CIFailed || run.Name != "web" || run.Commit != "deadbeef" {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestGitHubRepoFromURL(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:steveyegge/gastown.git":       "steveyegge/gastown",
+		"https://github.com/steveyegge/gastown":       "steveyegge/gastown",
+		"https://github.com/steveyegge/gastown.git/":  "steveyegge/gastown",
+		"ssh://git@github.com/steveyegge/gastown.git": "steveyegge/gastown",
+		"https://gitlab.com/steveyegge/gastown.git":   "",
+		"https://github.com/steveyegge":               "",
+		"/srv/git/gastown.git":                        "",
+	}
+	for in, want := range tests {
+		if got := GitHubRepoFromURL(in); got != want {
+			t.Errorf("GitHubRepoFromURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCIRedState(t *testing.T) {
+	townRoot := t.TempDir()
+	if LoadCIRed(townRoot, "gastown") != nil {
+		t.Fatal("fresh rig reported red")
+	}
+
+	since := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	if err := SaveCIRed(townRoot, "gastown", &CIRedState{Since: since, Commit: "abc", Bead: "gt-1", URL: "u"}); err != nil {
+		t.Fatal(err)
+	}
+	got := LoadCIRed(townRoot, "gastown")
+	if got == nil || !got.Since.Equal(since) || got.Commit != "abc" || got.Bead != "gt-1" || got.URL != "u" {
+		t.Errorf("loaded %+v", got)
+	}
+	if LoadCIRed(townRoot, "other") != nil {
+		t.Error("state leaked to another rig")
+	}
+
+	if err := ClearCIRed(townRoot, "gastown"); err != nil {
+		t.Fatal(err)
+	}
+	if LoadCIRed(townRoot, "gastown") != nil {
+		t.Error("still red after clear")
+	}
+}
+
+func TestNextCITransition(t *testing.T) {
+	red := &CIRedState{Bead: "gt-1"}
+	tests := []struct {
+		red  *CIRedState
+		run  CIStatus
+		want CITransition
+	}{
+		{nil, CIPassed, CIStillGreen},
+		{nil, CIUnknown, CIStillGreen},
+		{nil, CIFailed, CIWentRed},
+		{red, CIFailed, CIStillRed},
+		{red, CIUnknown, CIStillRed},
+		{red, CIPassed, CIWentGreen},
+	}
+	for _, tt := range tests {
+		if got := NextCITransition(tt.red, &CIRun{Status: tt.run}); got != tt.want {
+			t.Errorf("red=%v run=%s: got %s, want %s", tt.red != nil, tt.run, got, tt.want)
+		}
+	}
+}
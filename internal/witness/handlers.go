@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -36,6 +37,11 @@ func initRegistryFromWorkDir(workDir string) {
 	}
 }
 
+// townMux returns the multiplexer hosting the town's agent sessions.
+func townMux(townRoot string) multiplexer.Multiplexer {
+	return multiplexer.ForTownWith(townRoot, tmux.NewTmux())
+}
+
 // HandlerResult tracks the result of handling a protocol message.
 type HandlerResult struct {
 	MessageID    string
@@ -391,6 +397,9 @@ Please fix the issue and resubmit with 'gt done'.`,
 	result.Handled = true
 	result.MailSent = notification.ID
 	result.Action = fmt.Sprintf("notified %s of merge failure: %s - %s", payload.PolecatName, payload.FailureType, payload.Error)
+	if id := recordIncident(workDir, IncidentFromMergeFailed(rigName, payload)); id != "" {
+		result.Action += fmt.Sprintf(" (incident %s)", id)
+	}
 
 	return result
 }
@@ -435,33 +444,20 @@ func createCleanupWisp(workDir, polecatName, issueID, branch string) (string, er
 		description += fmt.Sprintf("\nBranch: %s", branch)
 	}
 
-	labels := strings.Join(CleanupWispLabels(polecatName, "pending"), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--json",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	created, err := beads.New(workDir).Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Priority:    -1,
+		Ephemeral:   true,
+		Labels:      CleanupWispLabels(polecatName, "pending"),
+	})
 	if err != nil {
 		return "", err
 	}
-
-	// Parse JSON output from bd create --json
-	var created struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal([]byte(output), &created); err == nil && created.ID != "" {
-		return created.ID, nil
-	}
-
-	// Fallback: extract from "Created: <id>" format
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
+	if created.ID == "" {
+		return "", fmt.Errorf("bd create returned no cleanup wisp ID")
 	}
-
-	return "", fmt.Errorf("could not parse bead ID from bd create output: %q", output)
+	return created.ID, nil
 }
 
 // createSwarmWisp creates a wisp to track swarm (batch) work.
@@ -469,23 +465,20 @@ func createSwarmWisp(workDir string, payload *SwarmStartPayload) (string, error)
 	title := fmt.Sprintf("swarm:%s", payload.SwarmID)
 	description := fmt.Sprintf("Tracking batch: %s\nTotal: %d polecats", payload.SwarmID, payload.Total)
 
-	labels := strings.Join(SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	created, err := beads.New(workDir).Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Priority:    -1,
+		Ephemeral:   true,
+		Labels:      SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt),
+	})
 	if err != nil {
 		return "", err
 	}
-
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
+	if created.ID == "" {
+		return "", fmt.Errorf("bd create returned no swarm wisp ID")
 	}
-
-	return output, nil
+	return created.ID, nil
 }
 
 // findCleanupWisp finds an existing cleanup wisp for a polecat.
@@ -613,8 +606,8 @@ func nudgeRefinery(townRoot, rigName string) error {
 	sessionName := session.RefinerySessionName(session.PrefixFor(rigName))
 
 	// Check if refinery is running
-	t := tmux.NewTmux()
-	running, err := t.HasSession(sessionName)
+	mux := townMux(townRoot)
+	running, err := mux.HasSession(sessionName)
 	if err != nil {
 		return fmt.Errorf("checking refinery session: %w", err)
 	}
@@ -629,7 +622,7 @@ func nudgeRefinery(townRoot, rigName string) error {
 	// No cooperative queue — idle agents never call Drain(), so queued
 	// nudges would be stuck forever. Direct delivery is safe: if the
 	// agent is busy, text buffers in tmux and is processed at next prompt.
-	return t.NudgeSession(sessionName, "MERGE_READY received - check inbox for pending work")
+	return multiplexer.Nudge(mux, sessionName, "MERGE_READY received - check inbox for pending work")
 }
 
 // escalateToDeacon sends an escalation mail to the Deacon for routine operational issues.
@@ -767,18 +760,23 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	initRegistryFromWorkDir(workDir)
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
-	t := tmux.NewTmux()
+	townRoot, _ := workspace.Find(workDir)
+	mux := townMux(townRoot)
 
 	// Check if session exists and kill it
-	if running, _ := t.HasSession(sessionName); running {
-		// Try graceful shutdown first (Ctrl-C), then force kill
-		_ = t.SendKeysRaw(sessionName, "C-c")
-		// Brief delay for graceful handling
-		time.Sleep(100 * time.Millisecond)
-		// Force kill the session
-		if err := t.KillSession(sessionName); err != nil {
-			// Log but continue - session might already be dead
-			// The important thing is we tried
+	if running, _ := mux.HasSession(sessionName); running {
+		if t, ok := mux.(*tmux.Tmux); ok {
+			// Try graceful shutdown first (Ctrl-C), then force kill
+			_ = t.SendKeysRaw(sessionName, "C-c")
+			// Brief delay for graceful handling
+			time.Sleep(100 * time.Millisecond)
+			// Force kill the session
+			if err := t.KillSession(sessionName); err != nil {
+				// Log but continue - session might already be dead
+				// The important thing is we tried
+			}
+		} else {
+			_ = mux.KillSessionWithProcesses(sessionName)
 		}
 	}
 
@@ -980,7 +978,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		return result
 	}
 
-	t := tmux.NewTmux()
+	t := townMux(townRoot)
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1014,7 +1012,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 			// This catches the "tmux-alive-but-agent-dead" zombie class that
 			// status.go detects but DetectZombiePolecats previously missed.
 			// See: gt-kj6r6
-			if !t.IsAgentAlive(sessionName) {
+			if !multiplexer.AgentAlive(t, sessionName) {
 				// Read hook bead before nuke (nuke may clean up agent bead)
 				_, deadAgentHookBead := getAgentBeadState(workDir, agentBeadID)
 				zombie := ZombieResult{
@@ -1052,7 +1050,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 					// A session where Claude is alive but has produced no tmux output
 					// for a long time is likely hung (infinite loop, crashed mid-call,
 					// or waiting for something that will never arrive). See: gt-tr3d
-					lastActivity, actErr := t.GetSessionActivity(sessionName)
+					lastActivity, actErr := multiplexer.Activity(t, sessionName)
 					if actErr == nil && !lastActivity.IsZero() {
 						inactiveMinutes := int(time.Since(lastActivity).Minutes())
 						if inactiveMinutes >= HungSessionThresholdMinutes {
@@ -1086,7 +1084,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 
 // detectZombieLiveSession checks a polecat with a live tmux session for zombie indicators:
 // stuck done-intent, dead agent process, or closed bead while still running.
-func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, router *mail.Router) (ZombieResult, bool) {
+func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t multiplexer.Multiplexer, doneIntent *DoneIntent, router *mail.Router) (ZombieResult, bool) {
 	// Check for done-intent stuck too long (polecat hung in gt done).
 	if doneIntent != nil && time.Since(doneIntent.Timestamp) > 60*time.Second {
 		_, stuckHookBead := getAgentBeadState(workDir, agentBeadID)
@@ -1105,7 +1103,7 @@ func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, session
 	}
 
 	// Tmux alive but agent process dead (gt-kj6r6).
-	if !t.IsAgentAlive(sessionName) {
+	if !multiplexer.AgentAlive(t, sessionName) {
 		_, deadAgentHookBead := getAgentBeadState(workDir, agentBeadID)
 		zombie := ZombieResult{
 			PolecatName: polecatName,
@@ -1142,7 +1140,7 @@ func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, session
 
 // detectZombieDeadSession checks a polecat with a dead tmux session for zombie indicators:
 // stale done-intent, or active agent state / hooked bead with no session.
-func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
+func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t multiplexer.Multiplexer, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
 	// Done-intent: polecat was trying to exit.
 	if doneIntent != nil {
 		age := time.Since(doneIntent.Timestamp)
@@ -1241,6 +1239,7 @@ func handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus
 			zombie.Error = wispErr
 		}
 		zombie.Action = fmt.Sprintf("escalated (cleanup_status=%s, wisp=%s)", cleanupStatus, wispID)
+		recordIncident(workDir, IncidentFromZombie(rigName, *zombie))
 	}
 }
 
@@ -1288,7 +1287,12 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		return result // No polecats directory
 	}
 
-	t := tmux.NewTmux()
+	// Only tmux panes show the agent's interactive prompts; other backends
+	// have nothing to dismiss.
+	t, ok := townMux(townRoot).(*tmux.Tmux)
+	if !ok {
+		return result
+	}
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1330,6 +1334,9 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 			if err := t.AcceptBypassPermissionsWarning(sessionName); err != nil {
 				stalled.Action = "escalated"
 				stalled.Error = fmt.Errorf("auto-dismiss failed: %w", err)
+				inc := IncidentFromStalled(rigName, stalled)
+				inc.Evidence += "\npane:\n" + content
+				recordIncident(workDir, inc)
 			} else {
 				stalled.Action = "auto-dismissed"
 			}
@@ -1478,20 +1485,19 @@ func DetectOrphanedBeads(workDir, rigName string, router *mail.Router) *DetectOr
 		beadList = append(beadList, batch...)
 	}
 
-	t := tmux.NewTmux()
+	t := townMux(townRoot)
 
 	for _, bead := range beadList {
 		if bead.Assignee == "" {
 			continue // No assignee — not a dead-polecat orphan
 		}
 
-		// Parse assignee: "rigname/polecats/polecatname"
-		parts := strings.Split(bead.Assignee, "/")
-		if len(parts) != 3 || parts[1] != "polecats" {
+		assignee, err := session.ParseAssignee(bead.Assignee)
+		if err != nil || assignee.Role() != session.RolePolecat {
 			continue // Not a polecat assignee (crew, refinery, etc.)
 		}
-		assigneeRig := parts[0]
-		polecatName := parts[2]
+		assigneeRig := assignee.Rig()
+		polecatName := assignee.Name()
 
 		// Only check beads assigned to polecats in this rig
 		if assigneeRig != rigName {
@@ -1620,7 +1626,7 @@ func DetectOrphanedMolecules(workDir, rigName string, router *mail.Router) *Dete
 
 	// Step 2: Check each polecat-assigned bead
 	polecatPrefix := rigName + "/polecats/"
-	t := tmux.NewTmux()
+	t := townMux(townRoot)
 	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
 
 	for _, b := range allBeads {
@@ -1854,7 +1860,7 @@ func getAgentBeadLabels(workDir, agentBeadID string) []string {
 // sessionRecreated checks whether a tmux session was (re)created after the
 // given timestamp. Returns true if the session exists and was created after
 // detectedAt, indicating a new session replaced the dead one (TOCTOU guard).
-func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bool {
+func sessionRecreated(t multiplexer.Multiplexer, sessionName string, detectedAt time.Time) bool {
 	alive, err := t.HasSession(sessionName)
 	if err != nil || !alive {
 		return false // Still dead — not recreated
@@ -0,0 +1,443 @@
+package witness
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// IncidentType classifies an anomaly the witness detected.
+type IncidentType string
+
+const (
+	IncidentAgentDead    IncidentType = "agent-dead"    // session or agent process gone while work was in flight
+	IncidentStuckPolecat IncidentType = "stuck-polecat" // live session not making progress (e.g. blocked on a prompt)
+	IncidentTestBreak    IncidentType = "test-break"    // merge rejected because tests failed
+	IncidentMergeFailure IncidentType = "merge-failure" // merge rejected for another reason (build, lint, conflict)
+)
+
+// IncidentLabel marks incident beads.
+const IncidentLabel = "gt:incident"
+
+// Evidence excerpts are trimmed to their last maxEvidenceLines lines and
+// maxEvidenceBytes bytes, where errors and test failures usually are.
+const (
+	maxEvidenceLines = 40
+	maxEvidenceBytes = 4000
+)
+
+// Incident is a structured witness incident report, stored as a bead.
+// Fields are kept as "key: value" lines in the description, followed by the
+// evidence excerpt verbatim.
+type Incident struct {
+	Type       IncidentType `json:"type"`
+	Severity   string       `json:"severity"` // critical, high, medium, low
+	Rig        string       `json:"rig"`
+	Summary    string       `json:"summary"`          // one line, used as the bead title
+	Agents     []string     `json:"agents,omitempty"` // affected agent addresses, e.g. "gastown/Toast"
+	Evidence   string       `json:"evidence,omitempty"`
+	DetectedBy string       `json:"detected_by,omitempty"` // e.g. "gastown/witness"
+	DetectedAt time.Time    `json:"detected_at"`
+	// RelatedBead is the work involved, e.g. the polecat's hooked bead.
+	RelatedBead string `json:"related_bead,omitempty"`
+}
+
+// IncidentRecord is an incident as read back from beads.
+type IncidentRecord struct {
+	Incident
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// IsValidIncidentType reports whether t is a known incident type.
+func IsValidIncidentType(t IncidentType) bool {
+	switch t {
+	case IncidentAgentDead, IncidentStuckPolecat, IncidentTestBreak, IncidentMergeFailure:
+		return true
+	}
+	return false
+}
+
+// Validate checks that inc has the fields every incident needs.
+func (inc *Incident) Validate() error {
+	if !IsValidIncidentType(inc.Type) {
+		return fmt.Errorf("unknown incident type %q", inc.Type)
+	}
+	if !config.IsValidSeverity(inc.Severity) {
+		return fmt.Errorf("invalid severity %q: must be critical, high, medium, or low", inc.Severity)
+	}
+	if inc.Rig == "" {
+		return fmt.Errorf("incident has no rig")
+	}
+	if strings.TrimSpace(inc.Summary) == "" {
+		return fmt.Errorf("incident has no summary")
+	}
+	return nil
+}
+
+// FormatIncidentDescription renders inc as an incident bead description.
+func FormatIncidentDescription(inc *Incident) string {
+	lines := []string{
+		inc.Summary,
+		"",
+		"incident_type: " + string(inc.Type),
+		"severity: " + inc.Severity,
+		"rig: " + inc.Rig,
+		"agents: " + orNull(strings.Join(inc.Agents, ", ")),
+		"detected_by: " + orNull(inc.DetectedBy),
+		"detected_at: " + inc.DetectedAt.UTC().Format(time.RFC3339),
+		"related_bead: " + orNull(inc.RelatedBead),
+	}
+	if inc.Evidence != "" {
+		lines = append(lines, "evidence:", EvidenceExcerpt(inc.Evidence))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func orNull(s string) string {
+	if s == "" {
+		return "null"
+	}
+	return s
+}
+
+// ParseIncidentDescription extracts an incident from a bead description
+// written by FormatIncidentDescription.
+func ParseIncidentDescription(description string) *Incident {
+	inc := &Incident{}
+	lines := strings.Split(description, "\n")
+	if len(lines) > 0 {
+		inc.Summary = strings.TrimSpace(lines[0])
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "evidence:" {
+			inc.Evidence = strings.Join(lines[i+1:], "\n")
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "null" {
+			value = ""
+		}
+		switch strings.TrimSpace(key) {
+		case "incident_type":
+			inc.Type = IncidentType(value)
+		case "severity":
+			inc.Severity = value
+		case "rig":
+			inc.Rig = value
+		case "agents":
+			for _, a := range strings.Split(value, ",") {
+				if a = strings.TrimSpace(a); a != "" {
+					inc.Agents = append(inc.Agents, a)
+				}
+			}
+		case "detected_by":
+			inc.DetectedBy = value
+		case "detected_at":
+			inc.DetectedAt, _ = time.Parse(time.RFC3339, value)
+		case "related_bead":
+			inc.RelatedBead = value
+		}
+	}
+	return inc
+}
+
+// EvidenceExcerpt trims evidence to its last lines, within the size limits
+// for incident beads.
+func EvidenceExcerpt(evidence string) string {
+	evidence = strings.TrimRight(evidence, "\n")
+	lines := strings.Split(evidence, "\n")
+	trimmed := false
+	if len(lines) > maxEvidenceLines {
+		lines = lines[len(lines)-maxEvidenceLines:]
+		trimmed = true
+	}
+	out := strings.Join(lines, "\n")
+	if len(out) > maxEvidenceBytes {
+		out = out[len(out)-maxEvidenceBytes:]
+		if i := strings.IndexByte(out, '\n'); i >= 0 {
+			out = out[i+1:] // don't start mid-line
+		}
+		trimmed = true
+	}
+	if trimmed {
+		out = "[...]\n" + out
+	}
+	return out
+}
+
+// incidentPriority maps severity to bead priority, matching escalations.
+func incidentPriority(severity string) int {
+	switch severity {
+	case config.SeverityCritical:
+		return 0
+	case config.SeverityHigh:
+		return 1
+	case config.SeverityMedium:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// CreateIncident records inc as an incident bead in the town's beads and
+// returns its ID. High and critical incidents are also filed as escalation
+// beads related to the incident, so they enter the escalation lifecycle
+// (ack, close, re-escalation when stale). With the rig's dedup settings, an
+// incident closed as a duplicate of an open one is not escalated again and
+// that incident's ID is returned.
+func CreateIncident(townRoot string, inc *Incident) (string, error) {
+	if inc.DetectedAt.IsZero() {
+		inc.DetectedAt = time.Now()
+	}
+	if inc.DetectedBy == "" {
+		inc.DetectedBy = inc.Rig + "/witness"
+	}
+	if err := inc.Validate(); err != nil {
+		return "", err
+	}
+
+	labels := []string{
+		IncidentLabel,
+		"incident:" + string(inc.Type),
+		"severity:" + inc.Severity,
+		"rig:" + inc.Rig,
+	}
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	created, err := bd.Create(beads.CreateOptions{
+		Title:       inc.Summary,
+		Description: FormatIncidentDescription(inc),
+		Labels:      labels,
+		Priority:    incidentPriority(inc.Severity),
+		Actor:       inc.DetectedBy,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating incident bead: %w", err)
+	}
+	if created.ID == "" {
+		return "", fmt.Errorf("bd create returned no incident ID")
+	}
+	dup, err := dedupIncident(bd, townRoot, inc, created)
+	if err != nil {
+		return created.ID, err
+	}
+	if dup != "" {
+		// The open incident was escalated when it was filed.
+		return dup, nil
+	}
+
+	if inc.Severity == config.SeverityHigh || inc.Severity == config.SeverityCritical {
+		if _, err := bd.CreateEscalationBead(inc.Summary, &beads.EscalationFields{
+			Severity:    inc.Severity,
+			Reason:      fmt.Sprintf("%s incident in %s (see %s)", inc.Type, inc.Rig, created.ID),
+			Source:      "incident:" + string(inc.Type),
+			EscalatedBy: inc.DetectedBy,
+			EscalatedAt: inc.DetectedAt.Format(time.RFC3339),
+			RelatedBead: created.ID,
+		}); err != nil {
+			return created.ID, fmt.Errorf("incident %s created but escalation failed: %w", created.ID, err)
+		}
+	}
+	return created.ID, nil
+}
+
+// dedupIncident checks a new incident bead against the rig's other open
+// incidents when the rig has dedup settings, returning the incident it was
+// closed as a duplicate of, if any.
+func dedupIncident(bd *beads.Beads, townRoot string, inc *Incident, created *beads.Issue) (string, error) {
+	cfg := config.LoadDedupConfig(filepath.Join(townRoot, inc.Rig))
+	if cfg == nil {
+		return "", nil
+	}
+	open, err := bd.List(beads.ListOptions{Label: IncidentLabel, Status: "open", Priority: -1})
+	if err != nil {
+		return "", fmt.Errorf("listing incidents: %w", err)
+	}
+	var candidates []*beads.Issue
+	for _, issue := range open {
+		if ParseIncidentDescription(issue.Description).Rig == inc.Rig {
+			candidates = append(candidates, issue)
+		}
+	}
+	if created.Title == "" {
+		created.Title, created.Description = inc.Summary, FormatIncidentDescription(inc)
+	}
+	res, err := bd.LinkDuplicates(created, candidates, cfg)
+	if err != nil {
+		return "", fmt.Errorf("checking incident %s for duplicates: %w", created.ID, err)
+	}
+	return res.DuplicateOf, nil
+}
+
+// recordIncident files inc from a witness handler, best-effort: incident
+// reports must never block the handler's own recovery work. Patrols see the
+// same condition every cycle, so while an open incident with the same rig,
+// type and subject exists no new one is filed and that incident's ID is
+// returned. It returns "" if no incident was found or created.
+func recordIncident(workDir string, inc *Incident) string {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return ""
+	}
+	open, err := ListIncidents(townRoot, IncidentFilter{Rig: inc.Rig, Type: inc.Type})
+	if err == nil {
+		if id := findOpenIncident(open, inc); id != "" {
+			return id
+		}
+	}
+	id, _ := CreateIncident(townRoot, inc)
+	return id
+}
+
+// incidentSubject identifies what an incident is about: the affected agents
+// and the work involved.
+func incidentSubject(inc *Incident) string {
+	return strings.Join(inc.Agents, ",") + "|" + inc.RelatedBead
+}
+
+// findOpenIncident returns the ID of an open record reporting the same
+// rig, type and subject as inc, or "".
+func findOpenIncident(records []IncidentRecord, inc *Incident) string {
+	subject := incidentSubject(inc)
+	for _, r := range records {
+		if r.Status == "closed" {
+			continue
+		}
+		if r.Rig == inc.Rig && r.Type == inc.Type && incidentSubject(&r.Incident) == subject {
+			return r.ID
+		}
+	}
+	return ""
+}
+
+// IncidentFilter selects incidents for ListIncidents. Empty fields match
+// everything.
+type IncidentFilter struct {
+	Rig      string
+	Type     IncidentType
+	Severity string
+	Since    time.Time // detected at or after
+	All      bool      // include closed incidents
+}
+
+// ListIncidents returns the town's incidents matching f, newest first.
+func ListIncidents(townRoot string, f IncidentFilter) ([]IncidentRecord, error) {
+	status := "open"
+	if f.All {
+		status = "all"
+	}
+	issues, err := beads.New(beads.ResolveBeadsDir(townRoot)).List(beads.ListOptions{
+		Label:    IncidentLabel,
+		Status:   status,
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing incidents: %w", err)
+	}
+
+	var records []IncidentRecord
+	for _, issue := range issues {
+		inc := ParseIncidentDescription(issue.Description)
+		if inc.Summary == "" {
+			inc.Summary = issue.Title
+		}
+		if (f.Rig != "" && inc.Rig != f.Rig) ||
+			(f.Type != "" && inc.Type != f.Type) ||
+			(f.Severity != "" && inc.Severity != f.Severity) ||
+			(!f.Since.IsZero() && inc.DetectedAt.Before(f.Since)) {
+			continue
+		}
+		records = append(records, IncidentRecord{Incident: *inc, ID: issue.ID, Status: issue.Status})
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].DetectedAt.After(records[j].DetectedAt)
+	})
+	return records, nil
+}
+
+// IncidentFromZombie builds an agent-dead incident from a zombie patrol
+// result. Zombies whose work couldn't be recovered or cleaned up
+// automatically are high severity.
+func IncidentFromZombie(rigName string, z ZombieResult) *Incident {
+	severity := config.SeverityMedium
+	if z.Error != nil || strings.HasPrefix(z.Action, "escalated") || (z.HookBead != "" && !z.BeadRecovered) {
+		severity = config.SeverityHigh
+	}
+
+	evidence := []string{"agent_state: " + orNull(z.AgentState)}
+	if z.HookBead != "" {
+		evidence = append(evidence, fmt.Sprintf("hook_bead: %s (recovered: %t)", z.HookBead, z.BeadRecovered))
+	}
+	if z.Action != "" {
+		evidence = append(evidence, "action: "+z.Action)
+	}
+	if z.Error != nil {
+		evidence = append(evidence, "error: "+z.Error.Error())
+	}
+
+	return &Incident{
+		Type:        IncidentAgentDead,
+		Severity:    severity,
+		Rig:         rigName,
+		Summary:     fmt.Sprintf("Polecat %s/%s agent dead (%s)", rigName, z.PolecatName, orNull(z.AgentState)),
+		Agents:      []string{rigName + "/" + z.PolecatName},
+		Evidence:    strings.Join(evidence, "\n"),
+		RelatedBead: z.HookBead,
+	}
+}
+
+// IncidentFromStalled builds a stuck-polecat incident from a stall
+// detection result. Stalls that were auto-dismissed are low severity.
+func IncidentFromStalled(rigName string, s StalledResult) *Incident {
+	severity := config.SeverityMedium
+	if s.Action == "auto-dismissed" && s.Error == nil {
+		severity = config.SeverityLow
+	}
+
+	evidence := []string{"stall_type: " + orNull(s.StallType)}
+	if s.Action != "" {
+		evidence = append(evidence, "action: "+s.Action)
+	}
+	if s.Error != nil {
+		evidence = append(evidence, "error: "+s.Error.Error())
+	}
+
+	return &Incident{
+		Type:     IncidentStuckPolecat,
+		Severity: severity,
+		Rig:      rigName,
+		Summary:  fmt.Sprintf("Polecat %s/%s stuck (%s)", rigName, s.PolecatName, orNull(s.StallType)),
+		Agents:   []string{rigName + "/" + s.PolecatName},
+		Evidence: strings.Join(evidence, "\n"),
+	}
+}
+
+// IncidentFromMergeFailed builds an incident from a MERGE_FAILED message:
+// test-break when tests failed, merge-failure otherwise. The refinery's
+// error output is the evidence.
+func IncidentFromMergeFailed(rigName string, p *MergeFailedPayload) *Incident {
+	typ := IncidentMergeFailure
+	if strings.EqualFold(p.FailureType, "test") || strings.EqualFold(p.FailureType, "tests") {
+		typ = IncidentTestBreak
+	}
+	return &Incident{
+		Type:        typ,
+		Severity:    config.SeverityMedium,
+		Rig:         rigName,
+		Summary:     fmt.Sprintf("Merge of %s failed: %s", p.Branch, orNull(p.FailureType)),
+		Agents:      []string{rigName + "/" + p.PolecatName, rigName + "/refinery"},
+		Evidence:    p.Error,
+		DetectedAt:  p.FailedAt,
+		RelatedBead: p.IssueID,
+	}
+}
@@ -0,0 +1,279 @@
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIncidentDescription_RoundTrip(t *testing.T) {
+	inc := &Incident{
+		Type:        IncidentTestBreak,
+		Severity:    "high",
+		Rig:         "gastown",
+		Summary:     "Merge of polecat/Toast failed: test",
+		Agents:      []string{"gastown/Toast", "gastown/refinery"},
+		Evidence:    "--- FAIL: TestFoo\nseverity: not a field\n    foo_test.go:12: boom",
+		DetectedBy:  "gastown/witness",
+		DetectedAt:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		RelatedBead: "gt-abc",
+	}
+
+	got := ParseIncidentDescription(FormatIncidentDescription(inc))
+	if got.Type != inc.Type || got.Severity != inc.Severity || got.Rig != inc.Rig ||
+		got.Summary != inc.Summary || got.DetectedBy != inc.DetectedBy ||
+		!got.DetectedAt.Equal(inc.DetectedAt) || got.RelatedBead != inc.RelatedBead {
+		t.Errorf("round trip = %+v, want %+v", got, inc)
+	}
+	if strings.Join(got.Agents, ",") != "gastown/Toast,gastown/refinery" {
+		t.Errorf("agents = %v", got.Agents)
+	}
+	if got.Evidence != inc.Evidence {
+		t.Errorf("evidence = %q, want %q", got.Evidence, inc.Evidence)
+	}
+}
+
+func TestIncidentDescription_NullFields(t *testing.T) {
+	inc := &Incident{Type: IncidentAgentDead, Severity: "medium", Rig: "gastown", Summary: "dead"}
+	got := ParseIncidentDescription(FormatIncidentDescription(inc))
+	if got.Agents != nil || got.RelatedBead != "" || got.Evidence != "" {
+		t.Errorf("expected empty optional fields, got %+v", got)
+	}
+}
+
+func TestEvidenceExcerpt_KeepsTail(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	got := EvidenceExcerpt(strings.Join(lines, "\n"))
+	if !strings.HasPrefix(got, "[...]\nline 61\n") || !strings.HasSuffix(got, "line 100") {
+		t.Errorf("excerpt = %q", got)
+	}
+
+	long := strings.Repeat("x", 300) + "\n"
+	got = EvidenceExcerpt(strings.Repeat(long, 30))
+	if len(got) > maxEvidenceBytes+len("[...]\n") {
+		t.Errorf("excerpt is %d bytes", len(got))
+	}
+	if !strings.HasPrefix(got, "[...]\n"+strings.Repeat("x", 300)) {
+		t.Errorf("excerpt starts mid-line: %q", got[:20])
+	}
+
+	if got := EvidenceExcerpt("short\n"); got != "short" {
+		t.Errorf("short excerpt = %q", got)
+	}
+}
+
+func TestIncidentBuilders(t *testing.T) {
+	z := IncidentFromZombie("gastown", ZombieResult{
+		PolecatName: "Toast",
+		AgentState:  "agent-dead-in-session",
+		HookBead:    "gt-work",
+		Action:      "escalated (cleanup_status=has_unpushed, wisp=gt-w1)",
+	})
+	if z.Type != IncidentAgentDead || z.Severity != "high" || z.RelatedBead != "gt-work" || z.Agents[0] != "gastown/Toast" {
+		t.Errorf("zombie incident = %+v", z)
+	}
+	if err := z.Validate(); err != nil {
+		t.Errorf("zombie incident invalid: %v", err)
+	}
+
+	recovered := IncidentFromZombie("gastown", ZombieResult{PolecatName: "Nux", AgentState: "idle", Action: "auto-nuked"})
+	if recovered.Severity != "medium" {
+		t.Errorf("auto-nuked zombie severity = %s, want medium", recovered.Severity)
+	}
+
+	s := IncidentFromStalled("gastown", StalledResult{
+		PolecatName: "Toast",
+		StallType:   "bypass-permissions",
+		Action:      "escalated",
+		Error:       errors.New("auto-dismiss failed: no pane"),
+	})
+	if s.Type != IncidentStuckPolecat || s.Severity != "medium" || !strings.Contains(s.Evidence, "no pane") {
+		t.Errorf("stalled incident = %+v", s)
+	}
+
+	m := IncidentFromMergeFailed("gastown", &MergeFailedPayload{PolecatName: "Toast", Branch: "polecat/Toast", FailureType: "test", Error: "FAIL"})
+	if m.Type != IncidentTestBreak || m.Evidence != "FAIL" {
+		t.Errorf("merge failed incident = %+v", m)
+	}
+	if b := IncidentFromMergeFailed("gastown", &MergeFailedPayload{FailureType: "build"}); b.Type != IncidentMergeFailure {
+		t.Errorf("build failure type = %s, want merge-failure", b.Type)
+	}
+}
+
+func TestIncidentValidate(t *testing.T) {
+	for _, inc := range []*Incident{
+		{Type: "bogus", Severity: "high", Rig: "r", Summary: "s"},
+		{Type: IncidentAgentDead, Severity: "urgent", Rig: "r", Summary: "s"},
+		{Type: IncidentAgentDead, Severity: "high", Summary: "s"},
+		{Type: IncidentAgentDead, Severity: "high", Rig: "r"},
+	} {
+		if err := inc.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", inc)
+		}
+	}
+}
+
+// installIncidentBd installs a fake bd that logs its arguments, answers
+// create with a fixed ID, and answers list with listJSON.
+func installIncidentBd(t *testing.T, listJSON string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a shell script as bd")
+	}
+	binDir := t.TempDir()
+	argsLog := filepath.Join(binDir, "bd_args.log")
+	listFile := filepath.Join(binDir, "list.json")
+	if err := os.WriteFile(listFile, []byte(listJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf(`#!/bin/sh
+for a in "$@"; do printf '%%s\n' "$a" >> %q; done
+echo "--" >> %q
+[ "$1" = "--allow-stale" ] && shift
+case "$1" in
+  create) echo '{"id":"hq-inc1"}' ;;
+  list) cat %q ;;
+  *) echo '{}' ;;
+esac
+`, argsLog, argsLog, listFile)
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsLog
+}
+
+// incidentTown returns a town root with a beads directory.
+func incidentTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestCreateIncident_HighSeverityEscalates(t *testing.T) {
+	argsLog := installIncidentBd(t, "[]")
+	townRoot := incidentTown(t)
+
+	id, err := CreateIncident(townRoot, &Incident{
+		Type:     IncidentAgentDead,
+		Severity: "high",
+		Rig:      "gastown",
+		Summary:  "Polecat gastown/Toast agent dead",
+	})
+	if err != nil {
+		t.Fatalf("CreateIncident: %v", err)
+	}
+	if id != "hq-inc1" {
+		t.Errorf("id = %q, want hq-inc1", id)
+	}
+
+	data, err := os.ReadFile(argsLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Split(strings.TrimSuffix(string(data), "--\n"), "--\n")
+	if len(calls) != 2 {
+		t.Fatalf("got %d bd calls, want incident + escalation:\n%s", len(calls), data)
+	}
+	for _, want := range []string{"--labels=gt:incident,incident:agent-dead,severity:high,rig:gastown", "--priority=1", "--actor=gastown/witness"} {
+		if !strings.Contains(calls[0], want+"\n") {
+			t.Errorf("incident create missing %q:\n%s", want, calls[0])
+		}
+	}
+	if !strings.Contains(calls[1], "--labels=gt:escalation") || !strings.Contains(calls[1], "related_bead: hq-inc1") {
+		t.Errorf("escalation not linked to incident:\n%s", calls[1])
+	}
+}
+
+func TestCreateIncident_LowSeverityDoesNotEscalate(t *testing.T) {
+	argsLog := installIncidentBd(t, "[]")
+
+	if _, err := CreateIncident(incidentTown(t), &Incident{
+		Type: IncidentStuckPolecat, Severity: "low", Rig: "gastown", Summary: "stuck",
+	}); err != nil {
+		t.Fatalf("CreateIncident: %v", err)
+	}
+	data, _ := os.ReadFile(argsLog)
+	if n := strings.Count(string(data), "--\n"); n != 1 {
+		t.Errorf("got %d bd calls, want 1:\n%s", n, data)
+	}
+}
+
+func TestListIncidents_Filters(t *testing.T) {
+	mk := func(id string, inc *Incident) map[string]any {
+		return map[string]any{"id": id, "title": inc.Summary, "status": "open", "description": FormatIncidentDescription(inc)}
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	issues := []map[string]any{
+		mk("hq-1", &Incident{Type: IncidentAgentDead, Severity: "high", Rig: "gastown", Summary: "old", DetectedAt: now.Add(-48 * time.Hour)}),
+		mk("hq-2", &Incident{Type: IncidentTestBreak, Severity: "medium", Rig: "gastown", Summary: "new", DetectedAt: now}),
+		mk("hq-3", &Incident{Type: IncidentAgentDead, Severity: "high", Rig: "beads", Summary: "other rig", DetectedAt: now.Add(-time.Hour)}),
+	}
+	data, _ := json.Marshal(issues)
+	installIncidentBd(t, string(data))
+	townRoot := incidentTown(t)
+
+	all, err := ListIncidents(townRoot, IncidentFilter{})
+	if err != nil {
+		t.Fatalf("ListIncidents: %v", err)
+	}
+	var ids []string
+	for _, r := range all {
+		ids = append(ids, r.ID)
+	}
+	if strings.Join(ids, ",") != "hq-2,hq-3,hq-1" {
+		t.Errorf("order = %v, want newest first", ids)
+	}
+
+	got, _ := ListIncidents(townRoot, IncidentFilter{Rig: "gastown", Severity: "high"})
+	if len(got) != 1 || got[0].ID != "hq-1" {
+		t.Errorf("rig+severity filter = %+v", got)
+	}
+	got, _ = ListIncidents(townRoot, IncidentFilter{Type: IncidentAgentDead, Since: now.Add(-2 * time.Hour)})
+	if len(got) != 1 || got[0].ID != "hq-3" {
+		t.Errorf("type+since filter = %+v", got)
+	}
+}
+
+func TestRecordIncident_SkipsWhileOpen(t *testing.T) {
+	stalled := IncidentFromStalled("gastown", StalledResult{PolecatName: "Toast", StallType: "unknown-prompt"})
+	open := []map[string]any{{
+		"id": "hq-open", "title": stalled.Summary, "status": "open",
+		"description": FormatIncidentDescription(stalled),
+	}}
+	data, _ := json.Marshal(open)
+	argsLog := installIncidentBd(t, string(data))
+	townRoot := incidentTown(t)
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	again := IncidentFromStalled("gastown", StalledResult{PolecatName: "Toast", StallType: "unknown-prompt"})
+	if id := recordIncident(townRoot, again); id != "hq-open" {
+		t.Errorf("recordIncident = %q, want the open incident hq-open", id)
+	}
+	logged, _ := os.ReadFile(argsLog)
+	if strings.Contains(string(logged), "create") {
+		t.Errorf("created a new incident while one was open:\n%s", logged)
+	}
+
+	other := IncidentFromStalled("gastown", StalledResult{PolecatName: "Nux", StallType: "unknown-prompt"})
+	if id := recordIncident(townRoot, other); id != "hq-inc1" {
+		t.Errorf("recordIncident for another polecat = %q, want hq-inc1", id)
+	}
+}
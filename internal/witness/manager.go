@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/multiplexer"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -45,8 +47,7 @@ func NewManager(r *rig.Rig) *Manager {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	status := t.CheckSessionHealth(m.SessionName(), 0)
+	status := multiplexer.Health(m.sessionMux(), m.SessionName(), 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -56,8 +57,17 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
-	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
+	return multiplexer.Health(m.sessionMux(), m.SessionName(), maxInactivity)
+}
+
+// sessionMux returns the multiplexer hosting the witness session: the
+// rig's tmux for a remote rig, otherwise the town's configured backend.
+func (m *Manager) sessionMux() multiplexer.Multiplexer {
+	t := m.rig.Tmux()
+	if m.rig.IsRemote() {
+		return t
+	}
+	return multiplexer.ForTownWith(m.townRoot(), t)
 }
 
 // SessionName returns the tmux session name for this witness.
@@ -68,10 +78,10 @@ func (m *Manager) SessionName() string {
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	mux := m.sessionMux()
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -79,7 +89,10 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		return t.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
 
 // witnessDir returns the working directory for the witness.
@@ -105,7 +118,7 @@ func (m *Manager) witnessDir() string {
 // envOverrides are KEY=VALUE pairs that override all other env var sources.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []string) error {
-	t := tmux.NewTmux()
+	mux := m.sessionMux()
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -114,15 +127,15 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	}
 
 	// Check if session already exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := mux.HasSession(sessionID)
 	if running {
 		// Session exists - check if Claude is actually running (healthy vs zombie)
-		if t.IsAgentAlive(sessionID) {
+		if multiplexer.AgentAlive(mux, sessionID) {
 			// Healthy - Claude is running
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
-		if err := t.KillSession(sessionID); err != nil {
+		if err := mux.KillSessionWithProcesses(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -158,39 +171,56 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	// NOTE: No gt prime injection needed - SessionStart hook handles it automatically
 	// Export GT_ROLE and BD_ACTOR in the command since tmux SetEnvironment only affects new panes
 	// Pass m.rig.Path so rig agent settings are honored (not town-level defaults)
+	secrets, err := config.StartupSecrets("witness", townRoot, m.rig.Path, agentOverride)
+	if err != nil {
+		return fmt.Errorf("resolving secrets: %w", err)
+	}
 	command, err := buildWitnessStartCommand(m.rig.Path, m.rig.Name, townRoot, agentOverride, roleConfig)
 	if err != nil {
 		return err
 	}
 
-	// Create session with command directly to avoid send-keys race condition.
-	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, witnessDir, command); err != nil {
-		return fmt.Errorf("creating tmux session: %w", err)
-	}
+	// Remote rigs reach beads through the town's Dolt server.
+	command = m.rig.AgentCommand(townRoot, command)
 
-	// Set environment variables (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
+	// Session environment: centralized AgentEnv for consistency across all
+	// role startup paths, then role config env vars, then CLI env overrides
+	// (highest priority).
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:     "witness",
 		Rig:      m.rig.Name,
 		TownRoot: townRoot,
 		Agent:    agentOverride,
 	})
-	for k, v := range envVars {
-		_ = t.SetEnvironment(sessionID, k, v)
-	}
-	// Apply role config env vars if present (non-fatal).
-	for key, value := range roleConfigEnvVars(roleConfig, townRoot, m.rig.Name) {
-		_ = t.SetEnvironment(sessionID, key, value)
-	}
-	// Apply CLI env overrides (highest priority, non-fatal).
+	maps.Copy(envVars, roleConfigEnvVars(roleConfig, townRoot, m.rig.Name))
 	for _, override := range envOverrides {
 		if key, value, ok := strings.Cut(override, "="); ok {
-			_ = t.SetEnvironment(sessionID, key, value)
+			envVars[key] = value
 		}
 	}
 
+	t, usesTmux := mux.(*tmux.Tmux)
+	if !usesTmux {
+		// Other backends take the whole environment at creation; there is
+		// no session table, theme, or pane to wait on.
+		if err := multiplexer.NewSessionWithSecrets(mux, sessionID, witnessDir, command, envVars, secrets); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		return nil
+	}
+
+	// Create session with command directly to avoid send-keys race condition.
+	// See: https://github.com/anthropics/gastown/issues/280
+	// Secrets reach the agent's environment, never an argv.
+	if err := multiplexer.NewSessionWithSecrets(t, sessionID, witnessDir, command, nil, secrets); err != nil {
+		return fmt.Errorf("creating tmux session: %w", err)
+	}
+
+	// Set environment variables (non-fatal: session works without these)
+	for k, v := range envVars {
+		_ = t.SetEnvironment(sessionID, k, v)
+	}
+
 	// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
 	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
@@ -207,9 +237,12 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		log.Printf("warning: accepting bypass permissions for %s: %v", sessionID, err)
 	}
 
-	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	if err := session.TrackSessionPID(townRoot, sessionID, t); err != nil {
-		log.Printf("warning: tracking session PID for %s: %v", sessionID, err)
+	// Track PID for defense-in-depth orphan cleanup (non-fatal).
+	// Remote PIDs mean nothing on this machine, so they are not tracked.
+	if !m.rig.IsRemote() {
+		if err := session.TrackSessionPID(townRoot, sessionID, t); err != nil {
+			log.Printf("warning: tracking session PID for %s: %v", sessionID, err)
+		}
 	}
 
 	time.Sleep(constants.ShutdownNotifyDelay)
@@ -269,15 +302,18 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string,
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	mux := m.sessionMux()
 	sessionID := m.SessionName()
 
-	// Check if tmux session exists
-	running, _ := t.HasSession(sessionID)
+	// Check if the session exists
+	running, _ := mux.HasSession(sessionID)
 	if !running {
 		return ErrNotRunning
 	}
 
-	// Kill the tmux session
-	return t.KillSession(sessionID)
+	// Kill the session
+	if t, ok := mux.(*tmux.Tmux); ok {
+		return t.KillSession(sessionID)
+	}
+	return mux.KillSessionWithProcesses(sessionID)
 }
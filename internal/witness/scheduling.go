@@ -0,0 +1,130 @@
+package witness
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// maxAgeWeeks caps the age factor so very old issues don't drown out priority.
+const maxAgeWeeks = 4.0
+
+// deadlineHorizon is how far ahead of a deadline the deadline factor starts rising.
+const deadlineHorizon = 14 * 24 * time.Hour
+
+// ScoredIssue is a ready issue ranked by a scheduling policy.
+type ScoredIssue struct {
+	Issue   *beads.Issue `json:"issue"`
+	Score   float64      `json:"score"`
+	Reasons []string     `json:"reasons,omitempty"`
+}
+
+// RankReadyWork orders ready issues by the rig's scheduling policy, best
+// first. The witness uses the head of this list when an idle polecat
+// needs its next issue.
+func RankReadyWork(issues []*beads.Issue, cfg *config.SchedulingConfig, now time.Time) []ScoredIssue {
+	ranked := make([]ScoredIssue, 0, len(issues))
+	policy := cfg.EffectivePolicy()
+	var weights *config.SchedulingWeights
+	if cfg != nil {
+		weights = cfg.Weights
+	}
+	for _, issue := range issues {
+		s := ScoredIssue{Issue: issue}
+		if policy == config.SchedulingWeighted {
+			s.Score, s.Reasons = weightedScore(issue, weights, now)
+		}
+		ranked = append(ranked, s)
+	}
+
+	byPriority := func(a, b *beads.Issue) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return olderFirst(a, b)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].Issue, ranked[j].Issue
+		switch policy {
+		case config.SchedulingFIFO:
+			return olderFirst(a, b)
+		case config.SchedulingWeighted:
+			if ranked[i].Score != ranked[j].Score {
+				return ranked[i].Score > ranked[j].Score
+			}
+			return byPriority(a, b)
+		default:
+			return byPriority(a, b)
+		}
+	})
+	return ranked
+}
+
+// weightedScore computes an issue's score under the weighted policy and
+// the per-factor contributions that produced it.
+func weightedScore(issue *beads.Issue, weights *config.SchedulingWeights, now time.Time) (float64, []string) {
+	wp, wa, wd, ws := weights.Resolved()
+	hints := beads.ParseSchedulingHints(issue)
+
+	priority := clamp01(float64(4-issue.Priority) / 4)
+
+	var age float64
+	if created, ok := parseIssueTime(issue.CreatedAt); ok && now.After(created) {
+		age = math.Min(now.Sub(created).Hours()/(24*7), maxAgeWeeks)
+	}
+
+	var deadline float64
+	if !hints.Deadline.IsZero() {
+		remaining := hints.Deadline.Sub(now)
+		if remaining <= 24*time.Hour {
+			deadline = 1
+		} else {
+			deadline = clamp01(1 - float64(remaining-24*time.Hour)/float64(deadlineHorizon-24*time.Hour))
+		}
+	}
+
+	size := 0.5 // unknown size is neutral
+	if hints.Size > 0 {
+		size = clamp01(1 - float64(hints.Size-1)/float64(beads.MaxSizePoints-1))
+	}
+
+	score := hints.Weight * (wp*priority + wa*age + wd*deadline + ws*size)
+
+	reasons := []string{
+		fmt.Sprintf("priority P%d: +%.2f", issue.Priority, wp*priority),
+		fmt.Sprintf("age %.1fw: +%.2f", age, wa*age),
+	}
+	if !hints.Deadline.IsZero() {
+		reasons = append(reasons, fmt.Sprintf("deadline %s: +%.2f", hints.Deadline.Format("2006-01-02"), wd*deadline))
+	}
+	if hints.Size > 0 {
+		reasons = append(reasons, fmt.Sprintf("size %d: +%.2f", hints.Size, ws*size))
+	}
+	if hints.Weight != 1 {
+		reasons = append(reasons, fmt.Sprintf("weight ×%g", hints.Weight))
+	}
+	return score, reasons
+}
+
+func olderFirst(a, b *beads.Issue) bool {
+	ta, okA := parseIssueTime(a.CreatedAt)
+	tb, okB := parseIssueTime(b.CreatedAt)
+	if okA && okB && !ta.Equal(tb) {
+		return ta.Before(tb)
+	}
+	return a.ID < b.ID
+}
+
+func parseIssueTime(s string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, err == nil
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,71 @@
+package witness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func rankedIDs(ranked []ScoredIssue) string {
+	ids := make([]string, len(ranked))
+	for i, s := range ranked {
+		ids[i] = s.Issue.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRankReadyWork_Policies(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+
+	issues := []*beads.Issue{
+		{ID: "gt-new-p1", Priority: 1, CreatedAt: ago(time.Hour)},
+		{ID: "gt-old-p3", Priority: 3, CreatedAt: ago(21 * 24 * time.Hour)},
+		{ID: "gt-old-p1", Priority: 1, CreatedAt: ago(48 * time.Hour)},
+		{ID: "gt-due-p3", Priority: 3, CreatedAt: ago(2 * time.Hour), Labels: []string{"deadline:" + now.Add(6*time.Hour).Format(time.RFC3339), "size:S"}},
+	}
+
+	tests := []struct {
+		cfg  *config.SchedulingConfig
+		want string
+	}{
+		{nil, "gt-old-p1,gt-new-p1,gt-old-p3,gt-due-p3"},
+		{&config.SchedulingConfig{Policy: config.SchedulingFIFO}, "gt-old-p3,gt-old-p1,gt-due-p3,gt-new-p1"},
+		// Due today outranks a fresh P1 under default weights; a three-week-old P3 doesn't.
+		{&config.SchedulingConfig{Policy: config.SchedulingWeighted}, "gt-due-p3,gt-old-p1,gt-new-p1,gt-old-p3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cfg.EffectivePolicy(), func(t *testing.T) {
+			if got := rankedIDs(RankReadyWork(issues, tt.cfg, now)); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankReadyWork_WeightsAndMultiplier(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	issues := []*beads.Issue{
+		{ID: "gt-a", Priority: 1, CreatedAt: now.Format(time.RFC3339)},
+		{ID: "gt-b", Priority: 2, CreatedAt: now.Format(time.RFC3339), Labels: []string{"weight:3"}},
+	}
+
+	ranked := RankReadyWork(issues, &config.SchedulingConfig{Policy: config.SchedulingWeighted}, now)
+	if got := rankedIDs(ranked); got != "gt-b,gt-a" {
+		t.Errorf("weight multiplier ignored: %s", got)
+	}
+	if !strings.Contains(strings.Join(ranked[0].Reasons, ","), "weight ×3") {
+		t.Errorf("reasons = %v", ranked[0].Reasons)
+	}
+
+	zero := 0.0
+	cfg := &config.SchedulingConfig{Policy: config.SchedulingWeighted, Weights: &config.SchedulingWeights{Priority: &zero, Size: &zero}}
+	for _, s := range RankReadyWork(issues, cfg, now) {
+		if s.Score != 0 {
+			t.Errorf("%s score = %v, want 0 with priority and size weights zeroed", s.Issue.ID, s.Score)
+		}
+	}
+}
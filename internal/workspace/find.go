@@ -18,6 +18,7 @@ var ErrNotFound = errors.New("not in a Gas Town workspace")
 const (
 	// PrimaryMarker is the main config file that identifies a workspace.
 	// The town.json file lives in mayor/ along with other mayor config.
+	// Its YAML and TOML variants (town.yaml, town.toml) count too.
 	PrimaryMarker = "mayor/town.json"
 
 	// SecondaryMarker is an alternative indicator at the town level.
@@ -41,7 +42,7 @@ func Find(startDir string) (string, error) {
 
 	current := absDir
 	for {
-		if _, err := os.Stat(filepath.Join(current, PrimaryMarker)); err == nil {
+		if config.ConfigExists(filepath.Join(current, PrimaryMarker)) {
 			if !inWorktree {
 				return current, nil
 			}
@@ -145,7 +146,7 @@ func IsWorkspace(dir string) (bool, error) {
 
 	// Check for primary marker (mayor/town.json)
 	primaryPath := filepath.Join(absDir, PrimaryMarker)
-	if _, err := os.Stat(primaryPath); err == nil {
+	if config.ConfigExists(primaryPath) {
 		return true, nil
 	}
 
@@ -278,3 +278,25 @@ func TestFindSkipsNestedWorkspaceInCrew(t *testing.T) {
 		t.Errorf("Find = %q, want %q (should skip nested workspace in crew/)", found, root)
 	}
 }
+
+func TestGetTownName_YAMLTownConfig(t *testing.T) {
+	root := realPath(t, t.TempDir())
+	if err := os.MkdirAll(filepath.Join(root, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	townYAML := "type: town\nversion: 2\nname: yamltown  # set by gt install\n"
+	if err := os.WriteFile(filepath.Join(root, "mayor", "town.yaml"), []byte(townYAML), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if ok, _ := IsWorkspace(root); !ok {
+		t.Error("IsWorkspace = false for mayor/town.yaml")
+	}
+	name, err := GetTownName(root)
+	if err != nil {
+		t.Fatalf("GetTownName: %v", err)
+	}
+	if name != "yamltown" {
+		t.Errorf("GetTownName = %q, want yamltown", name)
+	}
+}
@@ -0,0 +1,28 @@
+// Package gastown is the public, read-only Go API for inspecting a Gas Town
+// workspace: its rigs, whether their agents are running, the Dolt server's
+// health, and the beads in each rig. Dashboards, bots, and other external
+// tools can use it instead of shelling out to gt and parsing its output.
+//
+// Open a town by its root directory, or by any directory inside it:
+//
+//	town, err := gastown.Open("~/gt")
+//	rigs, err := town.Rigs()
+//	status, err := town.RigStatus("gastown")
+//	health := town.DoltHealth()
+//	queue, err := town.MergeQueue("gastown")
+//	issues, err := town.Issues("gastown", gastown.IssueQuery{Status: "open"})
+//
+// # Stability
+//
+// This package follows semantic versioning with the gastown module. Within
+// a major version, exported identifiers are not removed or renamed and their
+// behavior does not change incompatibly. New functions, methods, and struct
+// fields may be added in minor versions, so construct structs with field
+// names. The types here are this package's own: they do not change when gt's
+// internals do. Nothing here modifies the town.
+//
+// Agent liveness is read from the town's session multiplexer (tmux unless
+// configured otherwise) and beads through bd, so those tools must be
+// installed for RigStatus, MergeQueue, and Issues; the rest reads files and
+// the Dolt server directly.
+package gastown
@@ -0,0 +1,398 @@
+package gastown
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/multiplexer"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// HQ is the rig name that refers to the town's own beads (the town root's
+// .beads) in Issues and Issue.
+const HQ = "hq"
+
+// ErrNotTown is returned by Open when the directory is not inside a Gas
+// Town workspace.
+var ErrNotTown = errors.New("not in a Gas Town workspace")
+
+// ErrRigNotFound is returned for a rig name the town doesn't have.
+var ErrRigNotFound = errors.New("rig not found")
+
+// Town is a Gas Town workspace opened for inspection.
+type Town struct {
+	root string
+}
+
+// Open returns the town containing dir, which may be the town root or any
+// directory below it. A leading ~/ is expanded.
+//
+// Open also points gt's session naming at this town, which is process-wide:
+// a process should inspect one town at a time.
+func Open(dir string) (*Town, error) {
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("expanding ~: %w", err)
+		}
+		dir = filepath.Join(home, rest)
+	}
+	root, err := workspace.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotTown, dir)
+	}
+	_ = session.InitRegistry(root)
+	return &Town{root: root}, nil
+}
+
+// Root returns the town's root directory.
+func (t *Town) Root() string {
+	return t.root
+}
+
+// Rig describes one rig registered in the town.
+type Rig struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	GitURL string `json:"git_url"`
+
+	// Prefix is the rig's beads issue prefix (e.g. "gt").
+	Prefix string `json:"prefix,omitempty"`
+
+	Polecats    []string `json:"polecats,omitempty"`
+	Crew        []string `json:"crew,omitempty"`
+	HasWitness  bool     `json:"has_witness"`
+	HasRefinery bool     `json:"has_refinery"`
+
+	// Remote is true when the rig's agents run on another machine.
+	Remote bool `json:"remote,omitempty"`
+}
+
+// Rigs returns every rig registered in the town, sorted by name. Rigs
+// whose directory is missing are skipped.
+func (t *Town) Rigs() ([]Rig, error) {
+	mgr, names, err := t.rigManager()
+	if err != nil {
+		return nil, err
+	}
+	rigs := make([]Rig, 0, len(names))
+	for _, name := range names {
+		r, err := mgr.GetRig(name)
+		if err != nil {
+			continue
+		}
+		rigs = append(rigs, publicRig(r))
+	}
+	return rigs, nil
+}
+
+// Rig returns the named rig.
+func (t *Town) Rig(name string) (*Rig, error) {
+	r, err := t.loadRig(name)
+	if err != nil {
+		return nil, err
+	}
+	pr := publicRig(r)
+	return &pr, nil
+}
+
+// Agent is an agent's name and whether its session is running.
+type Agent struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+}
+
+// RigStatus is a rig's agents and whether each is running.
+type RigStatus struct {
+	Rig             Rig     `json:"rig"`
+	WitnessRunning  bool    `json:"witness_running"`
+	RefineryRunning bool    `json:"refinery_running"`
+	Polecats        []Agent `json:"polecats"`
+	Crew            []Agent `json:"crew"`
+}
+
+// RigStatus reports which of the rig's agents have live sessions.
+func (t *Town) RigStatus(name string) (*RigStatus, error) {
+	r, err := t.loadRig(name)
+	if err != nil {
+		return nil, err
+	}
+	status := &RigStatus{Rig: publicRig(r), Polecats: []Agent{}, Crew: []Agent{}}
+	status.WitnessRunning, _ = witness.NewManager(r).IsRunning()
+	status.RefineryRunning, _ = refinery.NewManager(r).IsRunning()
+
+	mux, err := multiplexer.ForTown(t.root)
+	if err != nil {
+		return nil, err
+	}
+	prefix := session.PrefixFor(name)
+	for _, p := range r.Polecats {
+		running, _ := mux.HasSession(session.PolecatSessionName(prefix, p))
+		status.Polecats = append(status.Polecats, Agent{Name: p, Running: running})
+	}
+	for _, c := range r.Crew {
+		running, _ := mux.HasSession(session.CrewSessionName(prefix, c))
+		status.Crew = append(status.Crew, Agent{Name: c, Running: running})
+	}
+	return status, nil
+}
+
+// MergeRequest is a branch waiting in a rig's merge queue.
+type MergeRequest struct {
+	// Position is 1 for the next branch the refinery will merge.
+	Position     int       `json:"position"`
+	ID           string    `json:"id"`
+	Branch       string    `json:"branch"`
+	Worker       string    `json:"worker,omitempty"`
+	IssueID      string    `json:"issue_id,omitempty"`
+	TargetBranch string    `json:"target_branch,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Error is why the last merge attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// MergeQueue returns the rig's open merge requests in the order the
+// refinery will process them.
+func (t *Town) MergeQueue(rigName string) ([]MergeRequest, error) {
+	r, err := t.loadRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	items, err := refinery.NewManager(r).Queue()
+	if err != nil {
+		return nil, err
+	}
+	queue := make([]MergeRequest, 0, len(items))
+	for _, item := range items {
+		mr := item.MR
+		queue = append(queue, MergeRequest{
+			Position:     item.Position,
+			ID:           mr.ID,
+			Branch:       mr.Branch,
+			Worker:       mr.Worker,
+			IssueID:      mr.IssueID,
+			TargetBranch: mr.TargetBranch,
+			CreatedAt:    mr.CreatedAt,
+			Error:        mr.Error,
+		})
+	}
+	return queue, nil
+}
+
+// DoltHealth describes the town's Dolt server.
+type DoltHealth struct {
+	// Remote is true when the server runs elsewhere and gt doesn't manage it.
+	Remote bool   `json:"remote"`
+	Addr   string `json:"addr"`
+
+	// Running is whether the server answers. PID is zero for remote servers.
+	Running bool `json:"running"`
+	PID     int  `json:"pid,omitempty"`
+
+	// Latency is how long a trivial query took; zero when not running.
+	Latency time.Duration `json:"latency,omitempty"`
+
+	Databases []string `json:"databases"`
+
+	// Error describes why the server is considered unhealthy, if it is.
+	Error string `json:"error,omitempty"`
+}
+
+// DoltHealth checks the town's Dolt server. Problems are reported in the
+// result rather than as an error.
+func (t *Town) DoltHealth() DoltHealth {
+	cfg := doltserver.DefaultConfig(t.root)
+	h := DoltHealth{Remote: cfg.IsRemote(), Addr: cfg.HostPort(), Databases: []string{}}
+	if !h.Remote {
+		running, pid, err := doltserver.IsRunning(t.root)
+		if err != nil {
+			h.Error = err.Error()
+		}
+		if !running {
+			if h.Error == "" {
+				h.Error = "server not running"
+			}
+			if dbs, err := doltserver.ListDatabases(t.root); err == nil && dbs != nil {
+				h.Databases = dbs
+			}
+			return h
+		}
+		h.PID = pid
+	}
+	latency, err := doltserver.MeasureQueryLatency(t.root)
+	if err != nil {
+		h.Error = err.Error()
+		return h
+	}
+	h.Running, h.Latency = true, latency
+	if dbs, err := doltserver.ListDatabases(t.root); err == nil && dbs != nil {
+		h.Databases = dbs
+	}
+	return h
+}
+
+// Issue is a bead.
+type Issue struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	Priority    int       `json:"priority"`
+	Type        string    `json:"type"`
+	Assignee    string    `json:"assignee,omitempty"`
+	Parent      string    `json:"parent,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ClosedAt    time.Time `json:"closed_at,omitzero"`
+}
+
+// IssueQuery filters Issues. Zero fields don't filter.
+type IssueQuery struct {
+	Status   string // "open", "in_progress", "closed", or "all"; bd's default when empty
+	Label    string // e.g. "gt:merge-request"
+	Assignee string // e.g. "gastown/polecats/toast"
+	Parent   string // children of this issue
+	Priority *int   // 0 (highest) to 4
+	Limit    int    // at most this many; zero for all
+}
+
+// Issues lists beads in the named rig (or HQ) matching q.
+func (t *Town) Issues(rigName string, q IssueQuery) ([]Issue, error) {
+	bd, err := t.beadsFor(rigName)
+	if err != nil {
+		return nil, err
+	}
+	opts := beads.ListOptions{
+		Status:   q.Status,
+		Label:    q.Label,
+		Assignee: q.Assignee,
+		Parent:   q.Parent,
+		Priority: -1,
+		Limit:    q.Limit,
+	}
+	if q.Priority != nil {
+		opts.Priority = *q.Priority
+	}
+	list, err := bd.List(opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s beads: %w", rigName, err)
+	}
+	issues := make([]Issue, 0, len(list))
+	for _, i := range list {
+		issues = append(issues, publicIssue(i))
+	}
+	return issues, nil
+}
+
+// Issue returns one bead from the named rig (or HQ) by ID.
+func (t *Town) Issue(rigName, id string) (*Issue, error) {
+	bd, err := t.beadsFor(rigName)
+	if err != nil {
+		return nil, err
+	}
+	i, err := bd.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("showing %s: %w", id, err)
+	}
+	issue := publicIssue(i)
+	return &issue, nil
+}
+
+func (t *Town) rigManager() (*rig.Manager, []string, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(t.root))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return rig.NewManager(t.root, rigsConfig, git.NewGit(t.root)), names, nil
+}
+
+func (t *Town) loadRig(name string) (*rig.Rig, error) {
+	mgr, _, err := t.rigManager()
+	if err != nil {
+		return nil, err
+	}
+	r, err := mgr.GetRig(name)
+	if errors.Is(err, rig.ErrRigNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrRigNotFound, name)
+	}
+	return r, err
+}
+
+func (t *Town) beadsFor(rigName string) (*beads.Beads, error) {
+	path := t.root
+	if rigName != HQ {
+		r, err := t.loadRig(rigName)
+		if err != nil {
+			return nil, err
+		}
+		path = r.Path
+	}
+	return beads.NewWithBeadsDir(path, beads.ResolveBeadsDir(path)), nil
+}
+
+func publicRig(r *rig.Rig) Rig {
+	pr := Rig{
+		Name:        r.Name,
+		Path:        r.Path,
+		GitURL:      r.GitURL,
+		Polecats:    r.Polecats,
+		Crew:        r.Crew,
+		HasWitness:  r.HasWitness,
+		HasRefinery: r.HasRefinery,
+		Remote:      r.Remote != nil,
+	}
+	if r.Config != nil {
+		pr.Prefix = r.Config.Prefix
+	}
+	return pr
+}
+
+func publicIssue(i *beads.Issue) Issue {
+	return Issue{
+		ID:          i.ID,
+		Title:       i.Title,
+		Description: i.Description,
+		Status:      i.Status,
+		Priority:    i.Priority,
+		Type:        i.Type,
+		Assignee:    i.Assignee,
+		Parent:      i.Parent,
+		Labels:      i.Labels,
+		CreatedAt:   parseTime(i.CreatedAt),
+		UpdatedAt:   parseTime(i.UpdatedAt),
+		ClosedAt:    parseTime(i.ClosedAt),
+	}
+}
+
+// parseTime reads bd's RFC 3339 timestamps; anything else is zero.
+func parseTime(s string) time.Time {
+	ts, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return ts
+}
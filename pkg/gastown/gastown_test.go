@@ -0,0 +1,87 @@
+package gastown
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+	for _, dir := range []string{"demo/polecats/nux", "demo/polecats/.hidden", "demo/crew/dave", "demo/witness", "demo/refinery/rig"} {
+		if err := os.MkdirAll(filepath.Join(town, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"mayor/town.json": `{"type": "town", "version": 2, "name": "test"}`,
+		"mayor/rigs.json": `{"version": 1, "rigs": {
+			"demo": {"git_url": "https://example.com/demo.git", "beads": {"prefix": "dm"}},
+			"gone": {"git_url": "https://example.com/gone.git"}
+		}}`,
+	}
+	for rel, content := range files {
+		path := filepath.Join(town, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return town
+}
+
+func TestOpen(t *testing.T) {
+	root := newTestTown(t)
+
+	town, err := Open(filepath.Join(root, "demo", "crew", "dave"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if town.Root() != root {
+		t.Errorf("Root() = %q, want %q", town.Root(), root)
+	}
+
+	if _, err := Open(t.TempDir()); !errors.Is(err, ErrNotTown) {
+		t.Errorf("Open outside a town: err = %v, want ErrNotTown", err)
+	}
+}
+
+func TestRigs(t *testing.T) {
+	town, err := Open(newTestTown(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rigs, err := town.Rigs()
+	if err != nil {
+		t.Fatalf("Rigs: %v", err)
+	}
+	if len(rigs) != 1 {
+		t.Fatalf("got %d rigs, want demo only (gone has no directory): %+v", len(rigs), rigs)
+	}
+	demo := rigs[0]
+	if demo.Name != "demo" || demo.Prefix != "dm" || demo.GitURL != "https://example.com/demo.git" {
+		t.Errorf("demo = %+v", demo)
+	}
+	if len(demo.Polecats) != 1 || demo.Polecats[0] != "nux" || len(demo.Crew) != 1 || !demo.HasWitness || !demo.HasRefinery {
+		t.Errorf("demo agents = %+v", demo)
+	}
+
+	if _, err := town.Rig("nope"); !errors.Is(err, ErrRigNotFound) {
+		t.Errorf("Rig(nope): err = %v, want ErrRigNotFound", err)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	if got := parseTime("2026-01-02T03:04:05Z"); !got.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("parseTime = %v", got)
+	}
+	if got := parseTime(""); !got.IsZero() {
+		t.Errorf("parseTime(\"\") = %v, want zero", got)
+	}
+}